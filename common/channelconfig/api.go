@@ -80,6 +80,12 @@ type Orderer interface {
 	// ConsensusType returns the configured consensus type
 	ConsensusType() string
 
+	// ConsensusMetadata returns the metadata associated with the consensus type.
+	ConsensusMetadata() []byte
+
+	// ConsensusState returns the consensus-type migration state.
+	ConsensusState() ab.ConsensusType_State
+
 	// BatchSize returns the maximum number of messages to include in a block
 	BatchSize() *ab.BatchSize
 
@@ -89,6 +95,9 @@ type Orderer interface {
 	// MaxChannelsCount returns the maximum count of channels to allow for an ordering network
 	MaxChannelsCount() uint64
 
+	// BlockScheduling returns the order in which messages are included in blocks
+	BlockScheduling() *ab.BlockScheduling
+
 	// KafkaBrokers returns the addresses (IP:port notation) of a set of "bootstrap"
 	// Kafka brokers, i.e. this is not necessarily the entire set of Kafka brokers
 	// used for ordering
@@ -13,6 +13,7 @@ import (
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/msp"
 	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/utils"
 
 	"github.com/pkg/errors"
@@ -87,7 +88,14 @@ func (b *Bundle) ValidateNew(nb Resources) error {
 		}
 
 		if oc.ConsensusType() != noc.ConsensusType() {
-			return errors.Errorf("Attempted to change consensus type from %s to %s", oc.ConsensusType(), noc.ConsensusType())
+			// The consensus type may only be changed in maintenance mode, in an
+			// update which leaves the channel in maintenance mode
+			if oc.ConsensusState() != ab.ConsensusType_STATE_MAINTENANCE {
+				return errors.Errorf("Attempted to change consensus type from %s to %s, but the channel is not in maintenance mode", oc.ConsensusType(), noc.ConsensusType())
+			}
+			if noc.ConsensusState() != ab.ConsensusType_STATE_MAINTENANCE {
+				return errors.Errorf("Attempted to change consensus type from %s to %s and to exit maintenance mode in the same update", oc.ConsensusType(), noc.ConsensusType())
+			}
 		}
 
 		for orgName, org := range oc.Organizations() {
@@ -94,6 +94,35 @@ func TestValidateNew(t *testing.T) {
 		assert.Regexp(t, "Attempted to change consensus type from", err.Error())
 	})
 
+	t.Run("ConsensusTypeChangeInMaintenance", func(t *testing.T) {
+		bundle := func(consensusType string, state ab.ConsensusType_State) *Bundle {
+			return &Bundle{
+				channelConfig: &ChannelConfig{
+					ordererConfig: &OrdererConfig{
+						protos: &OrdererProtos{
+							ConsensusType: &ab.ConsensusType{
+								Type:  consensusType,
+								State: state,
+							},
+						},
+					},
+				},
+			}
+		}
+
+		cb := bundle("type1", ab.ConsensusType_STATE_MAINTENANCE)
+		assert.NoError(t, cb.ValidateNew(bundle("type2", ab.ConsensusType_STATE_MAINTENANCE)))
+
+		err := cb.ValidateNew(bundle("type2", ab.ConsensusType_STATE_NORMAL))
+		assert.EqualError(t, err, "Attempted to change consensus type from type1 to type2 and to exit maintenance mode in the same update")
+
+		err = bundle("type1", ab.ConsensusType_STATE_NORMAL).ValidateNew(bundle("type2", ab.ConsensusType_STATE_MAINTENANCE))
+		assert.EqualError(t, err, "Attempted to change consensus type from type1 to type2, but the channel is not in maintenance mode")
+
+		assert.NoError(t, bundle("type1", ab.ConsensusType_STATE_NORMAL).ValidateNew(bundle("type1", ab.ConsensusType_STATE_MAINTENANCE)))
+		assert.NoError(t, cb.ValidateNew(bundle("type1", ab.ConsensusType_STATE_NORMAL)))
+	})
+
 	t.Run("OrdererOrgMSPIDChange", func(t *testing.T) {
 		cb := &Bundle{
 			channelConfig: &ChannelConfig{
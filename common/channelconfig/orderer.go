@@ -40,6 +40,9 @@ const (
 
 	// KafkaBrokersKey is the cb.ConfigItem type key name for the KafkaBrokers message
 	KafkaBrokersKey = "KafkaBrokers"
+
+	// BlockSchedulingKey is the cb.ConfigItem type key name for the BlockScheduling message
+	BlockSchedulingKey = "BlockScheduling"
 )
 
 // OrdererProtos is used as the source of the OrdererConfig
@@ -49,6 +52,7 @@ type OrdererProtos struct {
 	BatchTimeout        *ab.BatchTimeout
 	KafkaBrokers        *ab.KafkaBrokers
 	ChannelRestrictions *ab.ChannelRestrictions
+	BlockScheduling     *ab.BlockScheduling
 	Capabilities        *cb.Capabilities
 }
 
@@ -89,6 +93,16 @@ func (oc *OrdererConfig) ConsensusType() string {
 	return oc.protos.ConsensusType.Type
 }
 
+// ConsensusMetadata returns the metadata associated with the consensus type.
+func (oc *OrdererConfig) ConsensusMetadata() []byte {
+	return oc.protos.ConsensusType.Metadata
+}
+
+// ConsensusState returns the consensus-type migration state.
+func (oc *OrdererConfig) ConsensusState() ab.ConsensusType_State {
+	return oc.protos.ConsensusType.State
+}
+
 // BatchSize returns the maximum number of messages to include in a block
 func (oc *OrdererConfig) BatchSize() *ab.BatchSize {
 	return oc.protos.BatchSize
@@ -111,6 +125,11 @@ func (oc *OrdererConfig) MaxChannelsCount() uint64 {
 	return oc.protos.ChannelRestrictions.MaxCount
 }
 
+// BlockScheduling returns the order in which messages are included in blocks
+func (oc *OrdererConfig) BlockScheduling() *ab.BlockScheduling {
+	return oc.protos.BlockScheduling
+}
+
 // Organizations returns a map of the orgs in the channel
 func (oc *OrdererConfig) Organizations() map[string]Org {
 	return oc.orgs
@@ -126,6 +145,7 @@ func (oc *OrdererConfig) Validate() error {
 		oc.validateBatchSize,
 		oc.validateBatchTimeout,
 		oc.validateKafkaBrokers,
+		oc.validateBlockScheduling,
 	} {
 		if err := validator(); err != nil {
 			return err
@@ -172,6 +192,32 @@ func (oc *OrdererConfig) validateKafkaBrokers() error {
 	return nil
 }
 
+func (oc *OrdererConfig) validateBlockScheduling() error {
+	classes := make(map[string]struct{})
+	headerTypes := make(map[int32]string)
+	for _, class := range oc.protos.BlockScheduling.Classes {
+		if class.Name == "" {
+			return fmt.Errorf("Attempted to define a block scheduling class without a name")
+		}
+		if _, ok := classes[class.Name]; ok {
+			return fmt.Errorf("Attempted to define the block scheduling class %s twice", class.Name)
+		}
+		classes[class.Name] = struct{}{}
+		for _, headerType := range class.HeaderTypes {
+			if other, ok := headerTypes[headerType]; ok {
+				return fmt.Errorf("Attempted to assign header type %s to both block scheduling classes %s and %s", cb.HeaderType(headerType), other, class.Name)
+			}
+			headerTypes[headerType] = class.Name
+		}
+	}
+	for mspID, weight := range oc.protos.BlockScheduling.OrganizationWeights {
+		if weight == 0 {
+			return fmt.Errorf("Attempted to set the block scheduling weight of organization %s to an invalid value: 0", mspID)
+		}
+	}
+	return nil
+}
+
 // This does just a barebones sanity check.
 func brokerEntrySeemsValid(broker string) bool {
 	if !strings.Contains(broker, ":") {
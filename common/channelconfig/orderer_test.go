@@ -55,3 +55,37 @@ func TestKafkaBrokers(t *testing.T) {
 	oc = &OrdererConfig{protos: &OrdererProtos{KafkaBrokers: &ab.KafkaBrokers{Brokers: []string{"127.0.0.1", "foo.bar", "127.0.0.1:-1", "localhost:65536", "foo.bar.:9092", ".127.0.0.1:9092", "-foo.bar:9092"}}}}
 	assert.Error(t, oc.validateKafkaBrokers(), "Invalid kafka brokers")
 }
+
+func TestBlockScheduling(t *testing.T) {
+	oc := &OrdererConfig{protos: &OrdererProtos{BlockScheduling: &ab.BlockScheduling{}}}
+	assert.NoError(t, oc.validateBlockScheduling(), "Empty block scheduling")
+
+	oc = &OrdererConfig{protos: &OrdererProtos{BlockScheduling: &ab.BlockScheduling{
+		Classes: []*ab.BlockScheduling_Class{
+			{Name: "priority", Policy: "/Channel/Orderer/Writers", Expedite: true},
+			{Name: "config", HeaderTypes: []int32{1, 2}},
+		},
+		OrganizationWeights: map[string]uint32{"Org1MSP": 2},
+	}}}
+	assert.NoError(t, oc.validateBlockScheduling(), "Valid block scheduling")
+
+	oc = &OrdererConfig{protos: &OrdererProtos{BlockScheduling: &ab.BlockScheduling{
+		Classes: []*ab.BlockScheduling_Class{{HeaderTypes: []int32{3}}},
+	}}}
+	assert.EqualError(t, oc.validateBlockScheduling(), "Attempted to define a block scheduling class without a name")
+
+	oc = &OrdererConfig{protos: &OrdererProtos{BlockScheduling: &ab.BlockScheduling{
+		Classes: []*ab.BlockScheduling_Class{{Name: "a"}, {Name: "a"}},
+	}}}
+	assert.EqualError(t, oc.validateBlockScheduling(), "Attempted to define the block scheduling class a twice")
+
+	oc = &OrdererConfig{protos: &OrdererProtos{BlockScheduling: &ab.BlockScheduling{
+		Classes: []*ab.BlockScheduling_Class{{Name: "a", HeaderTypes: []int32{3}}, {Name: "b", HeaderTypes: []int32{3}}},
+	}}}
+	assert.EqualError(t, oc.validateBlockScheduling(), "Attempted to assign header type ENDORSER_TRANSACTION to both block scheduling classes a and b")
+
+	oc = &OrdererConfig{protos: &OrdererProtos{BlockScheduling: &ab.BlockScheduling{
+		OrganizationWeights: map[string]uint32{"Org1MSP": 0},
+	}}}
+	assert.EqualError(t, oc.validateBlockScheduling(), "Attempted to set the block scheduling weight of organization Org1MSP to an invalid value: 0")
+}
@@ -104,11 +104,12 @@ func OrdererAddressesValue(addresses []string) *StandardConfigValue {
 
 // ConsensusTypeValue returns the config definition for the orderer consensus type.
 // It is a value for the /Channel/Orderer group.
-func ConsensusTypeValue(consensusType string) *StandardConfigValue {
+func ConsensusTypeValue(consensusType string, consensusMetadata []byte) *StandardConfigValue {
 	return &StandardConfigValue{
 		key: ConsensusTypeKey,
 		value: &ab.ConsensusType{
-			Type: consensusType,
+			Type:     consensusType,
+			Metadata: consensusMetadata,
 		},
 	}
 }
@@ -148,6 +149,15 @@ func ChannelRestrictionsValue(maxChannelCount uint64) *StandardConfigValue {
 	}
 }
 
+// BlockSchedulingValue returns the config definition for the order in which messages are included in blocks.
+// It is a value for the /Channel/Orderer group.
+func BlockSchedulingValue(blockScheduling *ab.BlockScheduling) *StandardConfigValue {
+	return &StandardConfigValue{
+		key:   BlockSchedulingKey,
+		value: blockScheduling,
+	}
+}
+
 // KafkaBrokersValue returns the config definition for the addresses of the ordering service's Kafka brokers.
 // It is a value for the /Channel/Orderer group.
 func KafkaBrokersValue(brokers []string) *StandardConfigValue {
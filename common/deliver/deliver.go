@@ -25,6 +25,7 @@ import (
 	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/comm"
@@ -142,6 +143,7 @@ func (ds *deliverHandler) Handle(srv *DeliverServer) error {
 			return err
 		}
 
+		deliverMetrics().Counter("requests_received").Inc(1)
 		if err := ds.deliverBlocks(srv, envelope); err != nil {
 			return err
 		}
@@ -318,10 +320,16 @@ func (ds *deliverHandler) validateChannelHeader(srv *DeliverServer, chdr *cb.Cha
 }
 
 func sendStatusReply(srv *DeliverServer, status cb.Status) error {
+	deliverMetrics().Tagged(map[string]string{"status": status.String()}).Counter("requests_completed").Inc(1)
 	return srv.Send(srv.CreateStatusReply(status))
 
 }
 
 func sendBlockReply(srv *DeliverServer, block *cb.Block) error {
+	deliverMetrics().Counter("blocks_sent").Inc(1)
 	return srv.Send(srv.CreateBlockReply(block))
 }
+
+func deliverMetrics() metrics.Scope {
+	return metrics.RootScope.SubScope("deliver")
+}
@@ -39,6 +39,7 @@ var (
 
 	modules          map[string]string // Holds the map of all modules and their respective log level
 	peerStartModules map[string]string
+	activeSpec       string // The logging specification that was last applied
 
 	lock sync.RWMutex
 	once sync.Once
@@ -179,6 +180,13 @@ func InitFromSpec(spec string) string {
 
 	logging.SetLevel(levelAll, "") // set the logging level for all modules
 
+	lock.Lock()
+	if spec == "" {
+		spec = strings.ToLower(levelAll.String())
+	}
+	activeSpec = spec
+	lock.Unlock()
+
 	// iterate through modules to reload their level in the modules map based on
 	// the new default level
 	for k := range modules {
@@ -190,6 +198,14 @@ func InitFromSpec(spec string) string {
 	return levelAll.String()
 }
 
+// Spec returns the logging specification that was last applied
+// with InitFromSpec.
+func Spec() string {
+	lock.RLock()
+	defer lock.RUnlock()
+	return activeSpec
+}
+
 // SetPeerStartupModulesMap saves the modules and their log levels.
 // this function should only be called at the end of peer startup.
 func SetPeerStartupModulesMap() {
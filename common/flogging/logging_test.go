@@ -161,6 +161,18 @@ func TestInitFromSpec(t *testing.T) {
 
 }
 
+func TestSpec(t *testing.T) {
+	defer flogging.Reset()
+
+	assert.Equal(t, "info", flogging.Spec())
+
+	flogging.InitFromSpec("warning:a,b=debug")
+	assert.Equal(t, "warning:a,b=debug", flogging.Spec())
+
+	flogging.InitFromSpec("")
+	assert.Equal(t, "info", flogging.Spec())
+}
+
 func ExampleInitBackend() {
 	level, _ := logging.LogLevel(flogging.DefaultLevel())
 	// initializes logging backend for testing and sets time to 1970-01-01 00:00:00.000 UTC
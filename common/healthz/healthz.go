@@ -0,0 +1,160 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package healthz
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// StatusOK is the status reported when all the health checks passed
+	StatusOK = "OK"
+	// StatusUnavailable is the status reported when a health check failed
+	StatusUnavailable = "Service Unavailable"
+
+	defaultTimeout = 30 * time.Second
+)
+
+// HealthChecker defines the interface components must implement
+// in order to register with the HealthHandler.
+// HealthCheck returns an error if the component is not healthy.
+type HealthChecker interface {
+	HealthCheck(context.Context) error
+}
+
+// FailedCheck represents a failed health check of a component
+type FailedCheck struct {
+	Component string `json:"component"`
+	Reason    string `json:"reason"`
+}
+
+// HealthStatus is the JSON body returned by the HealthHandler
+type HealthStatus struct {
+	Status       string        `json:"status"`
+	Time         time.Time     `json:"time"`
+	FailedChecks []FailedCheck `json:"failed_checks,omitempty"`
+}
+
+// HealthHandler runs the registered health checks when
+// it serves an HTTP request
+type HealthHandler struct {
+	sync.RWMutex
+	healthCheckers map[string]HealthChecker
+	now            func() time.Time
+	timeout        time.Duration
+}
+
+// NewHealthHandler returns a new HealthHandler with no registered checkers
+func NewHealthHandler() *HealthHandler {
+	return &HealthHandler{
+		healthCheckers: make(map[string]HealthChecker),
+		now:            time.Now,
+		timeout:        defaultTimeout,
+	}
+}
+
+// RegisterChecker registers a HealthChecker for the given component.
+// It returns an error if the component is already registered.
+func (h *HealthHandler) RegisterChecker(component string, checker HealthChecker) error {
+	h.Lock()
+	defer h.Unlock()
+
+	if _, exists := h.healthCheckers[component]; exists {
+		return errors.Errorf("health checker for component %s is already registered", component)
+	}
+	h.healthCheckers[component] = checker
+	return nil
+}
+
+// DeregisterChecker removes the HealthChecker of the given component
+func (h *HealthHandler) DeregisterChecker(component string) {
+	h.Lock()
+	defer h.Unlock()
+	delete(h.healthCheckers, component)
+}
+
+// SetTimeout sets the time all the health checks of a request need to complete in.
+// It is not safe to call it while the handler is serving requests.
+func (h *HealthHandler) SetTimeout(timeout time.Duration) {
+	h.timeout = timeout
+}
+
+// ServeHTTP runs the health checks and reports their outcome.
+// The status code is 200 if all checks passed and 503 otherwise.
+func (h *HealthHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		rw.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), h.timeout)
+	defer cancel()
+
+	failedChecks := h.RunChecks(ctx)
+	status := HealthStatus{Status: StatusOK, Time: h.now()}
+	code := http.StatusOK
+	if len(failedChecks) > 0 {
+		status.Status = StatusUnavailable
+		status.FailedChecks = failedChecks
+		code = http.StatusServiceUnavailable
+	}
+
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(code)
+	json.NewEncoder(rw).Encode(status)
+}
+
+// RunChecks runs all the registered health checks concurrently and
+// returns the checks that failed, sorted by component
+func (h *HealthHandler) RunChecks(ctx context.Context) []FailedCheck {
+	h.RLock()
+	defer h.RUnlock()
+
+	var mutex sync.Mutex
+	var failedChecks []FailedCheck
+	var wg sync.WaitGroup
+	for component, checker := range h.healthCheckers {
+		wg.Add(1)
+		go func(component string, checker HealthChecker) {
+			defer wg.Done()
+			if err := runCheck(ctx, checker); err != nil {
+				mutex.Lock()
+				failedChecks = append(failedChecks, FailedCheck{Component: component, Reason: err.Error()})
+				mutex.Unlock()
+			}
+		}(component, checker)
+	}
+	wg.Wait()
+
+	sort.Slice(failedChecks, func(i, j int) bool {
+		return failedChecks[i].Component < failedChecks[j].Component
+	})
+	return failedChecks
+}
+
+// runCheck runs the health check, failing it if it
+// doesn't complete before the context is done
+func runCheck(ctx context.Context, checker HealthChecker) error {
+	errC := make(chan error, 1)
+	go func() {
+		errC <- checker.HealthCheck(ctx)
+	}()
+
+	select {
+	case err := <-errC:
+		return err
+	case <-ctx.Done():
+		return errors.New("health check timed out")
+	}
+}
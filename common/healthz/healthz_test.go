@@ -0,0 +1,127 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package healthz
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockChecker struct {
+	err   error
+	block bool
+}
+
+func (c *mockChecker) HealthCheck(ctx context.Context) error {
+	if c.block {
+		<-ctx.Done()
+	}
+	return c.err
+}
+
+func TestRegisterChecker(t *testing.T) {
+	h := NewHealthHandler()
+	assert.NoError(t, h.RegisterChecker("foo", &mockChecker{}))
+	err := h.RegisterChecker("foo", &mockChecker{})
+	assert.EqualError(t, err, "health checker for component foo is already registered")
+
+	h.DeregisterChecker("foo")
+	assert.NoError(t, h.RegisterChecker("foo", &mockChecker{}))
+}
+
+func TestHealthHandler(t *testing.T) {
+	now := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, testCase := range []struct {
+		name           string
+		checkers       map[string]HealthChecker
+		method         string
+		expectedCode   int
+		expectedStatus HealthStatus
+	}{
+		{
+			name:           "no checkers",
+			method:         http.MethodGet,
+			expectedCode:   http.StatusOK,
+			expectedStatus: HealthStatus{Status: StatusOK, Time: now},
+		},
+		{
+			name:   "all checks pass",
+			method: http.MethodGet,
+			checkers: map[string]HealthChecker{
+				"couchdb": &mockChecker{},
+				"docker":  &mockChecker{},
+			},
+			expectedCode:   http.StatusOK,
+			expectedStatus: HealthStatus{Status: StatusOK, Time: now},
+		},
+		{
+			name:   "checks fail",
+			method: http.MethodGet,
+			checkers: map[string]HealthChecker{
+				"docker":  &mockChecker{err: errors.New("cannot ping docker")},
+				"couchdb": &mockChecker{err: errors.New("connection refused")},
+				"kafka":   &mockChecker{},
+			},
+			expectedCode: http.StatusServiceUnavailable,
+			expectedStatus: HealthStatus{
+				Status: StatusUnavailable,
+				Time:   now,
+				FailedChecks: []FailedCheck{
+					{Component: "couchdb", Reason: "connection refused"},
+					{Component: "docker", Reason: "cannot ping docker"},
+				},
+			},
+		},
+		{
+			name:   "check times out",
+			method: http.MethodGet,
+			checkers: map[string]HealthChecker{
+				"kafka": &mockChecker{block: true},
+			},
+			expectedCode: http.StatusServiceUnavailable,
+			expectedStatus: HealthStatus{
+				Status:       StatusUnavailable,
+				Time:         now,
+				FailedChecks: []FailedCheck{{Component: "kafka", Reason: "health check timed out"}},
+			},
+		},
+		{
+			name:         "bad method",
+			method:       http.MethodPost,
+			expectedCode: http.StatusMethodNotAllowed,
+		},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			h := NewHealthHandler()
+			h.now = func() time.Time { return now }
+			h.SetTimeout(100 * time.Millisecond)
+			for component, checker := range testCase.checkers {
+				assert.NoError(t, h.RegisterChecker(component, checker))
+			}
+
+			resp := httptest.NewRecorder()
+			h.ServeHTTP(resp, httptest.NewRequest(testCase.method, "/healthz", nil))
+			assert.Equal(t, testCase.expectedCode, resp.Code)
+			if testCase.expectedCode == http.StatusMethodNotAllowed {
+				return
+			}
+
+			assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+			var status HealthStatus
+			assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &status))
+			assert.Equal(t, testCase.expectedStatus, status)
+		})
+	}
+}
@@ -18,6 +18,7 @@ package blkstorage
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/protos/common"
@@ -49,12 +50,25 @@ var (
 	ErrAttrNotIndexed = errors.New("Attribute not indexed")
 )
 
+// ErrBlockPruned is used to indicate that a requested block lies in the range
+// of blocks that has been pruned from the block store
+type ErrBlockPruned struct {
+	FirstAvailableBlockNum uint64
+}
+
+func (err *ErrBlockPruned) Error() string {
+	return fmt.Sprintf("the requested block has been pruned, first available block is [%d]", err.FirstAvailableBlockNum)
+}
+
 // BlockStoreProvider provides an handle to a BlockStore
 type BlockStoreProvider interface {
 	CreateBlockStore(ledgerid string) (BlockStore, error)
 	OpenBlockStore(ledgerid string) (BlockStore, error)
 	Exists(ledgerid string) (bool, error)
 	List() ([]string, error)
+	// Remove deletes the blocks and the index of the given ledger.
+	// The BlockStore of the ledger must have been shut down beforehand.
+	Remove(ledgerid string) error
 	Close()
 }
 
@@ -71,5 +85,14 @@ type BlockStore interface {
 	RetrieveTxByBlockNumTranNum(blockNum uint64, tranNum uint64) (*common.Envelope, error)
 	RetrieveBlockByTxID(txID string) (*common.Block, error)
 	RetrieveTxValidationCodeByTxID(txID string) (peer.TxValidationCode, error)
+	// GetFirstAvailableBlockNum returns the number of the oldest block that has not been pruned
+	GetFirstAvailableBlockNum() (uint64, error)
+	// PruneBlocksBefore removes the blocks with number lower than `blockNum`. The pruning is performed
+	// at the granularity of the underlying storage units and hence some of these blocks may be retained.
+	// If `archiveDir` is not empty, the pruned data is moved to this directory instead of being deleted
+	PruneBlocksBefore(blockNum uint64, archiveDir string) error
+	// BootstrapFromSnapshot adds the last block of a ledger snapshot as the first block of an empty store.
+	// The blocks with number lower than the given block are treated as pruned
+	BootstrapFromSnapshot(lastBlock *common.Block) error
 	Shutdown()
 }
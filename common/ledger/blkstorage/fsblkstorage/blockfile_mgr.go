@@ -53,6 +53,8 @@ type blockfileMgr struct {
 	cpInfoCond        *sync.Cond
 	currentFileWriter *blockfileWriter
 	bcInfo            atomic.Value
+	prunedInfo        atomic.Value
+	pruneLock         sync.Mutex
 }
 
 /*
@@ -121,6 +123,9 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 		}
 		logger.Debugf("Info constructed by scanning the blocks dir = %s", spew.Sdump(cpInfo))
 	} else {
+		if err = completePendingRollback(rootDir, indexStore, cpInfo); err != nil {
+			panic(fmt.Sprintf("Could not complete the pending rollback of block files: %s", err))
+		}
 		logger.Debug(`Synching block information from block storage (if needed)`)
 		syncCPInfoFromFS(rootDir, cpInfo)
 	}
@@ -144,6 +149,13 @@ func newBlockfileMgr(id string, conf *Conf, indexConfig *blkstorage.IndexConfig,
 	// Create a new KeyValue store database handler for the blocks index in the keyvalue database
 	mgr.index = newBlockIndex(indexConfig, indexStore)
 
+	// Load the information about the blocks that have been pruned (if any)
+	prunedInfo, err := mgr.loadPruneInfo()
+	if err != nil {
+		panic(fmt.Sprintf("Could not get prune info from db: %s", err))
+	}
+	mgr.prunedInfo.Store(prunedInfo)
+
 	// Update the manager with the checkpoint info and the file writer
 	mgr.cpInfo = cpInfo
 	mgr.currentFileWriter = currentFileWriter
@@ -354,6 +366,10 @@ func (mgr *blockfileMgr) syncIndex() error {
 		startingBlockNum = lastBlockIndexed + 1
 	} else {
 		logger.Debugf("No block indexed, Last block present in block files=[%d]", mgr.cpInfo.lastBlockNumber)
+		// the block files before the first available one may have been pruned
+		prunedInfo := mgr.getPruneInfo()
+		startFileNum = prunedInfo.firstAvailableFileSuffixNum
+		startingBlockNum = prunedInfo.firstAvailableBlockNum
 	}
 
 	logger.Infof("Start building index from block [%d] to last block [%d]", startingBlockNum, mgr.cpInfo.lastBlockNumber)
@@ -447,6 +463,9 @@ func (mgr *blockfileMgr) retrieveBlockByHash(blockHash []byte) (*common.Block, e
 	if err != nil {
 		return nil, err
 	}
+	if err = mgr.checkLocNotPruned(loc); err != nil {
+		return nil, err
+	}
 	return mgr.fetchBlock(loc)
 }
 
@@ -457,6 +476,9 @@ func (mgr *blockfileMgr) retrieveBlockByNumber(blockNum uint64) (*common.Block,
 	if blockNum == math.MaxUint64 {
 		blockNum = mgr.getBlockchainInfo().Height - 1
 	}
+	if err := mgr.checkBlockNumNotPruned(blockNum); err != nil {
+		return nil, err
+	}
 
 	loc, err := mgr.index.getBlockLocByBlockNum(blockNum)
 	if err != nil {
@@ -473,6 +495,9 @@ func (mgr *blockfileMgr) retrieveBlockByTxID(txID string) (*common.Block, error)
 	if err != nil {
 		return nil, err
 	}
+	if err = mgr.checkLocNotPruned(loc); err != nil {
+		return nil, err
+	}
 	return mgr.fetchBlock(loc)
 }
 
@@ -483,6 +508,9 @@ func (mgr *blockfileMgr) retrieveTxValidationCodeByTxID(txID string) (peer.TxVal
 
 func (mgr *blockfileMgr) retrieveBlockHeaderByNumber(blockNum uint64) (*common.BlockHeader, error) {
 	logger.Debugf("retrieveBlockHeaderByNumber() - blockNum = [%d]", blockNum)
+	if err := mgr.checkBlockNumNotPruned(blockNum); err != nil {
+		return nil, err
+	}
 	loc, err := mgr.index.getBlockLocByBlockNum(blockNum)
 	if err != nil {
 		return nil, err
@@ -499,6 +527,9 @@ func (mgr *blockfileMgr) retrieveBlockHeaderByNumber(blockNum uint64) (*common.B
 }
 
 func (mgr *blockfileMgr) retrieveBlocks(startNum uint64) (*blocksItr, error) {
+	if err := mgr.checkBlockNumNotPruned(startNum); err != nil {
+		return nil, err
+	}
 	return newBlockItr(mgr, startNum), nil
 }
 
@@ -508,11 +539,17 @@ func (mgr *blockfileMgr) retrieveTransactionByID(txID string) (*common.Envelope,
 	if err != nil {
 		return nil, err
 	}
+	if err = mgr.checkLocNotPruned(loc); err != nil {
+		return nil, err
+	}
 	return mgr.fetchTransactionEnvelope(loc)
 }
 
 func (mgr *blockfileMgr) retrieveTransactionByBlockNumTranNum(blockNum uint64, tranNum uint64) (*common.Envelope, error) {
 	logger.Debugf("retrieveTransactionByBlockNumTranNum() - blockNum = [%d], tranNum = [%d]", blockNum, tranNum)
+	if err := mgr.checkBlockNumNotPruned(blockNum); err != nil {
+		return nil, err
+	}
 	loc, err := mgr.index.getTXLocByBlockNumTranNum(blockNum, tranNum)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,275 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+)
+
+var (
+	blkMgrPruneInfoKey = []byte("blkMgrPruneInfo")
+)
+
+// pruneInfo tracks the oldest block file (and the oldest block in it) that has not been pruned.
+// The block index is not updated on pruning; entries for the pruned blocks continue to be present
+// so that the duplicate txid check and the lookup of validation codes are not affected. Any lookup that
+// resolves to a pruned block file results in an `ErrBlockPruned`
+type pruneInfo struct {
+	firstAvailableFileSuffixNum int
+	firstAvailableBlockNum      uint64
+}
+
+func (i *pruneInfo) marshal() ([]byte, error) {
+	buffer := proto.NewBuffer([]byte{})
+	var err error
+	if err = buffer.EncodeVarint(uint64(i.firstAvailableFileSuffixNum)); err != nil {
+		return nil, err
+	}
+	if err = buffer.EncodeVarint(i.firstAvailableBlockNum); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func (i *pruneInfo) unmarshal(b []byte) error {
+	buffer := proto.NewBuffer(b)
+	var val uint64
+	var err error
+
+	if val, err = buffer.DecodeVarint(); err != nil {
+		return err
+	}
+	i.firstAvailableFileSuffixNum = int(val)
+
+	if val, err = buffer.DecodeVarint(); err != nil {
+		return err
+	}
+	i.firstAvailableBlockNum = val
+	return nil
+}
+
+func (i *pruneInfo) String() string {
+	return fmt.Sprintf("firstAvailableFileSuffixNum=[%d], firstAvailableBlockNum=[%d]",
+		i.firstAvailableFileSuffixNum, i.firstAvailableBlockNum)
+}
+
+// loadPruneInfo loads the prune info from the db. If the block files were never pruned,
+// prune info that marks all the blocks as available is returned
+func (mgr *blockfileMgr) loadPruneInfo() (*pruneInfo, error) {
+	var b []byte
+	var err error
+	if b, err = mgr.db.Get(blkMgrPruneInfoKey); err != nil {
+		return nil, err
+	}
+	i := &pruneInfo{}
+	if b == nil {
+		return i, nil
+	}
+	if err = i.unmarshal(b); err != nil {
+		return nil, err
+	}
+	logger.Debugf("loaded pruneInfo:%s", i)
+	return i, nil
+}
+
+func (mgr *blockfileMgr) savePruneInfo(i *pruneInfo) error {
+	b, err := i.marshal()
+	if err != nil {
+		return err
+	}
+	return mgr.db.Put(blkMgrPruneInfoKey, b, true)
+}
+
+func (mgr *blockfileMgr) getPruneInfo() *pruneInfo {
+	return mgr.prunedInfo.Load().(*pruneInfo)
+}
+
+func (mgr *blockfileMgr) getFirstAvailableBlockNum() uint64 {
+	return mgr.getPruneInfo().firstAvailableBlockNum
+}
+
+// checkBlockNumNotPruned returns an `ErrBlockPruned` if the given block has been pruned
+func (mgr *blockfileMgr) checkBlockNumNotPruned(blockNum uint64) error {
+	if info := mgr.getPruneInfo(); blockNum < info.firstAvailableBlockNum {
+		return &blkstorage.ErrBlockPruned{FirstAvailableBlockNum: info.firstAvailableBlockNum}
+	}
+	return nil
+}
+
+// checkLocNotPruned returns an `ErrBlockPruned` if the given location lies in a pruned block file
+func (mgr *blockfileMgr) checkLocNotPruned(lp *fileLocPointer) error {
+	if info := mgr.getPruneInfo(); lp.fileSuffixNum < info.firstAvailableFileSuffixNum {
+		return &blkstorage.ErrBlockPruned{FirstAvailableBlockNum: info.firstAvailableBlockNum}
+	}
+	return nil
+}
+
+// pruneBlocksBefore removes all the block files that contain only the blocks with number lower than
+// the given block number. The block file that contains the last block is never removed. If `archiveDir`
+// is not empty, the block files are moved to a sub-directory (named after the ledger) of the `archiveDir`
+func (mgr *blockfileMgr) pruneBlocksBefore(blockNum uint64, archiveDir string) error {
+	mgr.pruneLock.Lock()
+	defer mgr.pruneLock.Unlock()
+
+	mgr.cpInfoCond.L.Lock()
+	cpInfo := mgr.cpInfo
+	mgr.cpInfoCond.L.Unlock()
+
+	currentInfo := mgr.getPruneInfo()
+	if cpInfo.isChainEmpty || blockNum <= currentInfo.firstAvailableBlockNum {
+		logger.Debugf("Nothing to prune before block [%d]. Current prune info: %s", blockNum, currentInfo)
+		return nil
+	}
+	if blockNum > cpInfo.lastBlockNumber {
+		blockNum = cpInfo.lastBlockNumber
+	}
+	lp, err := mgr.index.getBlockLocByBlockNum(blockNum)
+	if err != nil {
+		return fmt.Errorf("Error while locating block [%d] for pruning: %s", blockNum, err)
+	}
+	if lp.fileSuffixNum <= currentInfo.firstAvailableFileSuffixNum {
+		logger.Debugf("Block [%d] lies in the oldest available block file, nothing to prune", blockNum)
+		return nil
+	}
+	firstBlockNum, err := mgr.firstBlockNumInFile(lp.fileSuffixNum)
+	if err != nil {
+		return err
+	}
+	newInfo := &pruneInfo{firstAvailableFileSuffixNum: lp.fileSuffixNum, firstAvailableBlockNum: firstBlockNum}
+	// the prune info is saved before touching the block files so that a crash in between
+	// leaves behind only the stale files, which are cleaned up by the next pruning
+	if err = mgr.savePruneInfo(newInfo); err != nil {
+		return fmt.Errorf("Error while saving prune info to db: %s", err)
+	}
+	mgr.prunedInfo.Store(newInfo)
+	logger.Infof("Pruning block files [%d] to [%d], first available block is now [%d]",
+		currentInfo.firstAvailableFileSuffixNum, newInfo.firstAvailableFileSuffixNum-1, newInfo.firstAvailableBlockNum)
+	return mgr.removeBlockfilesBefore(newInfo.firstAvailableFileSuffixNum, archiveDir)
+}
+
+// firstBlockNumInFile returns the number of the first block stored in the given block file
+func (mgr *blockfileMgr) firstBlockNumInFile(fileNum int) (uint64, error) {
+	stream, err := newBlockfileStream(mgr.rootDir, fileNum, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer stream.close()
+	blockBytes, err := stream.nextBlockBytes()
+	if err != nil {
+		return 0, err
+	}
+	if blockBytes == nil {
+		return 0, fmt.Errorf("no block found in block file [%d]", fileNum)
+	}
+	info, err := extractSerializedBlockInfo(blockBytes)
+	if err != nil {
+		return 0, err
+	}
+	return info.blockHeader.Number, nil
+}
+
+// bootstrapFromSnapshot adds the last block of a ledger snapshot as the first block of an empty block store.
+// The blocks before this block are never going to be present and hence they are marked as pruned. The prune
+// info is saved before adding the block so that a crash in between leaves behind an empty block store that
+// can be bootstrapped again
+func (mgr *blockfileMgr) bootstrapFromSnapshot(lastBlock *common.Block) error {
+	mgr.pruneLock.Lock()
+	defer mgr.pruneLock.Unlock()
+
+	mgr.cpInfoCond.L.Lock()
+	cpInfo := mgr.cpInfo
+	mgr.cpInfoCond.L.Unlock()
+
+	if !cpInfo.isChainEmpty {
+		return fmt.Errorf("block store is not empty, last block is [%d]", cpInfo.lastBlockNumber)
+	}
+	info := &pruneInfo{
+		firstAvailableFileSuffixNum: cpInfo.latestFileChunkSuffixNum,
+		firstAvailableBlockNum:      lastBlock.Header.Number,
+	}
+	if err := mgr.savePruneInfo(info); err != nil {
+		return fmt.Errorf("Error while saving prune info to db: %s", err)
+	}
+	mgr.prunedInfo.Store(info)
+	// the blockchain info is moved to the height of the snapshot so that the block is accepted as the next block
+	mgr.bcInfo.Store(&common.BlockchainInfo{Height: lastBlock.Header.Number})
+	if err := mgr.addBlock(lastBlock); err != nil {
+		mgr.bcInfo.Store(&common.BlockchainInfo{Height: 0})
+		return err
+	}
+	logger.Infof("Block store bootstrapped from snapshot, first available block is [%d]", lastBlock.Header.Number)
+	return nil
+}
+
+// removeBlockfilesBefore deletes (or archives) all the existing block files with a suffix lower than the given suffix
+func (mgr *blockfileMgr) removeBlockfilesBefore(fileSuffixNum int, archiveDir string) error {
+	var ledgerArchiveDir string
+	if archiveDir != "" {
+		ledgerArchiveDir = filepath.Join(archiveDir, filepath.Base(mgr.rootDir))
+		if _, err := util.CreateDirIfMissing(ledgerArchiveDir); err != nil {
+			return err
+		}
+	}
+	for i := 0; i < fileSuffixNum; i++ {
+		filePath := deriveBlockfilePath(mgr.rootDir, i)
+		exists, _, err := util.FileExists(filePath)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			continue
+		}
+		if ledgerArchiveDir == "" {
+			logger.Debugf("Deleting block file [%s]", filePath)
+			err = os.Remove(filePath)
+		} else {
+			logger.Debugf("Archiving block file [%s] to [%s]", filePath, ledgerArchiveDir)
+			err = moveFile(filePath, filepath.Join(ledgerArchiveDir, filepath.Base(filePath)))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveFile moves the file by renaming it and falls back to copying
+// if the destination lies on a different file system
+func moveFile(src, dst string) error {
+	if err := os.Rename(src, dst); err == nil {
+		return nil
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err = out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	if err = out.Close(); err != nil {
+		return err
+	}
+	return os.Remove(src)
+}
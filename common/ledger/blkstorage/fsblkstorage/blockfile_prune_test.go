@@ -0,0 +1,184 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/protos/common"
+	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockfileMgrPrune(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 100)
+	env := newTestEnv(t, NewConf(testPath(), maxFileSizeForBlocks(t, blocks[:10])))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	mgr := blkfileMgrWrapper.blockfileMgr
+	blkfileMgrWrapper.addBlocks(blocks)
+	assert.True(t, mgr.cpInfo.latestFileChunkSuffixNum > 5)
+	assert.Equal(t, uint64(0), mgr.getFirstAvailableBlockNum())
+
+	assert.NoError(t, mgr.pruneBlocksBefore(50, ""))
+	firstAvailable := mgr.getFirstAvailableBlockNum()
+	assert.True(t, firstAvailable > 0 && firstAvailable <= 50)
+	assert.False(t, blockfileExists(mgr.rootDir, 0))
+	assert.True(t, blockfileExists(mgr.rootDir, mgr.getPruneInfo().firstAvailableFileSuffixNum))
+
+	// pruned blocks
+	_, err := mgr.retrieveBlockByNumber(0)
+	assert.IsType(t, &blkstorage.ErrBlockPruned{}, err)
+	_, err = mgr.retrieveBlockByHash(blocks[0].Header.Hash())
+	assert.IsType(t, &blkstorage.ErrBlockPruned{}, err)
+	_, err = mgr.retrieveBlockByNumber(firstAvailable - 1)
+	assert.Equal(t, &blkstorage.ErrBlockPruned{FirstAvailableBlockNum: firstAvailable}, err)
+	prunedTxID := txIDOfFirstTx(t, blocks[0])
+	_, err = mgr.retrieveTransactionByID(prunedTxID)
+	assert.IsType(t, &blkstorage.ErrBlockPruned{}, err)
+	_, err = mgr.retrieveBlocks(0)
+	assert.IsType(t, &blkstorage.ErrBlockPruned{}, err)
+	// the index continues to serve the validation codes of the pruned transactions
+	_, err = mgr.retrieveTxValidationCodeByTxID(prunedTxID)
+	assert.NoError(t, err)
+
+	// retained blocks
+	blkfileMgrWrapper.testGetBlockByNumber(blocks[firstAvailable:], firstAvailable)
+	blkfileMgrWrapper.testGetBlockByHash(blocks[firstAvailable:])
+	itr, err := mgr.retrieveBlocks(firstAvailable)
+	assert.NoError(t, err)
+	blk, err := itr.Next()
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(blocks[firstAvailable], blk.(*common.Block)))
+	itr.Close()
+
+	// pruning to an older block is a no-op
+	assert.NoError(t, mgr.pruneBlocksBefore(10, ""))
+	assert.Equal(t, firstAvailable, mgr.getFirstAvailableBlockNum())
+
+	// the prune info survives a restart
+	blkfileMgrWrapper.close()
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, "testLedger")
+	defer blkfileMgrWrapper.close()
+	assert.Equal(t, firstAvailable, blkfileMgrWrapper.blockfileMgr.getFirstAvailableBlockNum())
+	blkfileMgrWrapper.testGetBlockByNumber(blocks[firstAvailable:], firstAvailable)
+}
+
+func TestBlockfileMgrPruneNeverRemovesLastBlock(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 30)
+	env := newTestEnv(t, NewConf(testPath(), maxFileSizeForBlocks(t, blocks[:5])))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	defer blkfileMgrWrapper.close()
+	mgr := blkfileMgrWrapper.blockfileMgr
+	blkfileMgrWrapper.addBlocks(blocks)
+
+	assert.NoError(t, mgr.pruneBlocksBefore(1000, ""))
+	assert.True(t, mgr.getFirstAvailableBlockNum() <= 29)
+	b, err := mgr.retrieveBlockByNumber(29)
+	assert.NoError(t, err)
+	assert.Equal(t, blocks[29].Header, b.Header)
+	assert.True(t, blockfileExists(mgr.rootDir, mgr.cpInfo.latestFileChunkSuffixNum))
+}
+
+func TestBlockfileMgrPruneWithArchive(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 50)
+	env := newTestEnv(t, NewConf(testPath(), maxFileSizeForBlocks(t, blocks[:10])))
+	defer env.Cleanup()
+	archiveDir := filepath.Join(env.provider.conf.blockStorageDir, "archive")
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	defer blkfileMgrWrapper.close()
+	mgr := blkfileMgrWrapper.blockfileMgr
+	blkfileMgrWrapper.addBlocks(blocks)
+
+	assert.NoError(t, mgr.pruneBlocksBefore(40, archiveDir))
+	prunedUpto := mgr.getPruneInfo().firstAvailableFileSuffixNum
+	assert.True(t, prunedUpto > 0)
+	for i := 0; i < prunedUpto; i++ {
+		assert.False(t, blockfileExists(mgr.rootDir, i))
+		assert.True(t, blockfileExists(filepath.Join(archiveDir, "testLedger"), i))
+	}
+	// the archived files are readable block files
+	lastBlockBytes, _, numBlocks, err := scanForLastCompleteBlock(filepath.Join(archiveDir, "testLedger"), 0, 0)
+	assert.NoError(t, err)
+	assert.NotNil(t, lastBlockBytes)
+	assert.True(t, numBlocks > 0)
+}
+
+func TestBlockfileMgrBootstrapFromSnapshot(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 20)
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	mgr := blkfileMgrWrapper.blockfileMgr
+
+	assert.NoError(t, mgr.bootstrapFromSnapshot(blocks[10]))
+	assert.Equal(t, uint64(10), mgr.getFirstAvailableBlockNum())
+	assert.Equal(t, uint64(11), mgr.getBlockchainInfo().Height)
+	assert.Equal(t, blocks[10].Header.Hash(), mgr.getBlockchainInfo().CurrentBlockHash)
+	// a non-empty block store cannot be bootstrapped again
+	assert.Error(t, mgr.bootstrapFromSnapshot(blocks[10]))
+
+	blkfileMgrWrapper.addBlocks(blocks[11:])
+	_, err := mgr.retrieveBlockByNumber(9)
+	assert.Equal(t, &blkstorage.ErrBlockPruned{FirstAvailableBlockNum: 10}, err)
+	_, err = mgr.retrieveBlocks(5)
+	assert.IsType(t, &blkstorage.ErrBlockPruned{}, err)
+	blkfileMgrWrapper.testGetBlockByNumber(blocks[10:], 10)
+	blkfileMgrWrapper.testGetBlockByHash(blocks[10:])
+	itr, err := mgr.retrieveBlocks(10)
+	assert.NoError(t, err)
+	blk, err := itr.Next()
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(blocks[10], blk.(*common.Block)))
+	itr.Close()
+
+	// the bootstrapped block store survives a restart
+	blkfileMgrWrapper.close()
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, "testLedger")
+	defer blkfileMgrWrapper.close()
+	assert.Equal(t, uint64(10), blkfileMgrWrapper.blockfileMgr.getFirstAvailableBlockNum())
+	assert.Equal(t, uint64(20), blkfileMgrWrapper.blockfileMgr.getBlockchainInfo().Height)
+	blkfileMgrWrapper.testGetBlockByNumber(blocks[10:], 10)
+}
+
+func TestPruneInfoSerialization(t *testing.T) {
+	info := &pruneInfo{firstAvailableFileSuffixNum: 12, firstAvailableBlockNum: 2000}
+	b, err := info.marshal()
+	assert.NoError(t, err)
+	infoFromBytes := &pruneInfo{}
+	assert.NoError(t, infoFromBytes.unmarshal(b))
+	assert.Equal(t, info, infoFromBytes)
+}
+
+func maxFileSizeForBlocks(t *testing.T, blocks []*common.Block) int {
+	size := 0
+	for _, block := range blocks {
+		by, _, err := serializeBlock(block)
+		assert.NoError(t, err)
+		size += len(by) + len(proto.EncodeVarint(uint64(len(by))))
+	}
+	return size
+}
+
+func blockfileExists(rootDir string, fileNum int) bool {
+	_, err := os.Stat(deriveBlockfilePath(rootDir, fileNum))
+	return err == nil
+}
+
+func txIDOfFirstTx(t *testing.T, block *common.Block) string {
+	env, err := putils.ExtractEnvelope(block, 0)
+	assert.NoError(t, err)
+	chdr, err := putils.ChannelHeader(env)
+	assert.NoError(t, err)
+	return chdr.TxId
+}
@@ -0,0 +1,161 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/peer"
+)
+
+var (
+	blkMgrRollbackInfoKey = []byte("blkMgrRollbackInfo")
+)
+
+// Rollback truncates the block store of the given ledger such that the block `targetBlockNum` becomes the
+// last block. The block index and the checkpoint info are updated atomically before the block files are
+// truncated, along with a marker that makes the next open of the block store complete the truncation if
+// a crash happens in between. The block store must not be open while it is being rolled back
+func Rollback(conf *Conf, indexConfig *blkstorage.IndexConfig, ledgerID string, targetBlockNum uint64) error {
+	exists, _, err := util.FileExists(conf.getLedgerBlockDir(ledgerID))
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("block store for ledger [%s] does not exist", ledgerID)
+	}
+	indexProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: conf.getIndexDir()})
+	defer indexProvider.Close()
+	indexStore := indexProvider.GetDBHandle(ledgerID)
+
+	mgr := newBlockfileMgr(ledgerID, conf, indexConfig, indexStore)
+	cpInfo, err := mgr.prepareRollback(targetBlockNum)
+	mgr.close()
+	if err != nil {
+		return err
+	}
+	if err = completePendingRollback(mgr.rootDir, indexStore, cpInfo); err != nil {
+		return err
+	}
+	logger.Infof("Rolled back block store of ledger [%s] to block [%d]", ledgerID, targetBlockNum)
+	return nil
+}
+
+// prepareRollback removes the index entries of the blocks after the target block and saves the checkpoint info
+// that points to the end of the target block, along with the rollback marker, in a single batch
+func (mgr *blockfileMgr) prepareRollback(targetBlockNum uint64) (*checkpointInfo, error) {
+	cpInfo := mgr.cpInfo
+	if cpInfo.isChainEmpty || targetBlockNum >= cpInfo.lastBlockNumber {
+		return nil, fmt.Errorf("target block number [%d] should be lower than the last block number [%d]",
+			targetBlockNum, mgr.getBlockchainInfo().Height-1)
+	}
+	if firstAvailableBlockNum := mgr.getFirstAvailableBlockNum(); targetBlockNum < firstAvailableBlockNum {
+		return nil, fmt.Errorf("target block number [%d] has been pruned, first available block is [%d]",
+			targetBlockNum, firstAvailableBlockNum)
+	}
+	lp, err := mgr.index.getBlockLocByBlockNum(targetBlockNum + 1)
+	if err != nil {
+		return nil, fmt.Errorf("Error while locating block [%d] for rollback: %s", targetBlockNum+1, err)
+	}
+
+	batch := leveldbhelper.NewUpdateBatch()
+	if err = mgr.addIndexEntriesToBeDeleted(batch, lp); err != nil {
+		return nil, err
+	}
+	batch.Put(indexCheckpointKey, encodeBlockNum(targetBlockNum))
+	newCPInfo := &checkpointInfo{
+		latestFileChunkSuffixNum: lp.fileSuffixNum,
+		latestFileChunksize:      lp.offset,
+		isChainEmpty:             false,
+		lastBlockNumber:          targetBlockNum,
+	}
+	cpInfoBytes, err := newCPInfo.marshal()
+	if err != nil {
+		return nil, err
+	}
+	batch.Put(blkMgrInfoKey, cpInfoBytes)
+	batch.Put(blkMgrRollbackInfoKey, encodeBlockNum(targetBlockNum))
+	if err = mgr.db.WriteBatch(batch, true); err != nil {
+		return nil, err
+	}
+	return newCPInfo, nil
+}
+
+// addIndexEntriesToBeDeleted adds to the batch the deletion of the index entries of all the blocks
+// starting from the given location. The entries keyed by the txid of a transaction that was marked as
+// a duplicate are retained, as these entries are shared with the original transaction
+func (mgr *blockfileMgr) addIndexEntriesToBeDeleted(batch *leveldbhelper.UpdateBatch, lp *fileLocPointer) error {
+	stream, err := newBlockStream(mgr.rootDir, lp.fileSuffixNum, int64(lp.offset), mgr.cpInfo.latestFileChunkSuffixNum)
+	if err != nil {
+		return err
+	}
+	defer stream.close()
+	for {
+		blockBytes, err := stream.nextBlockBytes()
+		if err != nil {
+			return err
+		}
+		if blockBytes == nil {
+			return nil
+		}
+		info, err := extractSerializedBlockInfo(blockBytes)
+		if err != nil {
+			return err
+		}
+		blockNum := info.blockHeader.Number
+		logger.Debugf("Deleting index entries of block [%d]", blockNum)
+		batch.Delete(constructBlockHashKey(info.blockHeader.Hash()))
+		batch.Delete(constructBlockNumKey(blockNum))
+		txsfltr := ledgerUtil.TxValidationFlags(info.metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+		for txNum, txOffset := range info.txOffsets {
+			batch.Delete(constructBlockNumTranNumKey(blockNum, uint64(txNum)))
+			if len(txsfltr) > txNum && txsfltr.Flag(txNum) == peer.TxValidationCode_DUPLICATE_TXID {
+				continue
+			}
+			batch.Delete(constructTxIDKey(txOffset.txID))
+			batch.Delete(constructBlockTxIDKey(txOffset.txID))
+			batch.Delete(constructTxValidationCodeIDKey(txOffset.txID))
+		}
+	}
+}
+
+// completePendingRollback truncates the block files as per the given checkpoint info if the rollback marker
+// is present, i.e., the checkpoint info was saved by a rollback that may not have truncated the block files yet.
+// The block files after the latest one are deleted before truncating the latest one so that the operation can
+// be repeated safely
+func completePendingRollback(rootDir string, db *leveldbhelper.DBHandle, cpInfo *checkpointInfo) error {
+	marker, err := db.Get(blkMgrRollbackInfoKey)
+	if err != nil || marker == nil {
+		return err
+	}
+	logger.Infof("Truncating block files to the end of block [%d] for completing the rollback", decodeBlockNum(marker))
+	for fileNum := cpInfo.latestFileChunkSuffixNum + 1; ; fileNum++ {
+		filePath := deriveBlockfilePath(rootDir, fileNum)
+		exists, _, err := util.FileExists(filePath)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			break
+		}
+		logger.Debugf("Deleting block file [%s]", filePath)
+		if err = os.Remove(filePath); err != nil {
+			return err
+		}
+	}
+	filePath := deriveBlockfilePath(rootDir, cpInfo.latestFileChunkSuffixNum)
+	if err = os.Truncate(filePath, int64(cpInfo.latestFileChunksize)); err != nil {
+		return err
+	}
+	return db.Delete(blkMgrRollbackInfoKey, true)
+}
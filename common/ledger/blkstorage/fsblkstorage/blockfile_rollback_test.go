@@ -0,0 +1,115 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fsblkstorage
+
+import (
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollback(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 50)
+	conf := NewConf(testPath(), maxFileSizeForBlocks(t, blocks[:10]))
+	defer os.RemoveAll(conf.blockStorageDir)
+	env := newTestEnv(t, conf)
+	indexConfig := env.provider.indexConfig
+
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	blkfileMgrWrapper.addBlocks(blocks)
+	assert.True(t, blkfileMgrWrapper.blockfileMgr.cpInfo.latestFileChunkSuffixNum > 2)
+	blkfileMgrWrapper.close()
+	env.provider.Close()
+
+	// the rollback target should be lower than the last block
+	assert.EqualError(t, Rollback(conf, indexConfig, "testLedger", 49),
+		"target block number [49] should be lower than the last block number [49]")
+	assert.EqualError(t, Rollback(conf, indexConfig, "nonExistentLedger", 10),
+		"block store for ledger [nonExistentLedger] does not exist")
+	assert.NoError(t, Rollback(conf, indexConfig, "testLedger", 14))
+
+	env = newTestEnv(t, conf)
+	defer env.provider.Close()
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, "testLedger")
+	mgr := blkfileMgrWrapper.blockfileMgr
+	assert.Equal(t, uint64(15), mgr.getBlockchainInfo().Height)
+	assert.Equal(t, blocks[14].Header.Hash(), mgr.getBlockchainInfo().CurrentBlockHash)
+	blkfileMgrWrapper.testGetBlockByNumber(blocks[:15], 0)
+	assert.False(t, blockfileExists(mgr.rootDir, mgr.cpInfo.latestFileChunkSuffixNum+1))
+
+	// the index entries of the removed blocks are gone
+	_, err := mgr.retrieveBlockByHash(blocks[20].Header.Hash())
+	assert.Equal(t, blkstorage.ErrNotFoundInIndex, err)
+	_, err = mgr.retrieveTransactionByID(txIDOfFirstTx(t, blocks[20]))
+	assert.Equal(t, blkstorage.ErrNotFoundInIndex, err)
+	_, err = mgr.retrieveTxValidationCodeByTxID(txIDOfFirstTx(t, blocks[20]))
+	assert.Equal(t, blkstorage.ErrNotFoundInIndex, err)
+
+	// the blocks after the rollback target can be committed again
+	blkfileMgrWrapper.addBlocks(blocks[15:])
+	blkfileMgrWrapper.testGetBlockByNumber(blocks, 0)
+	blkfileMgrWrapper.close()
+}
+
+func TestRollbackCompletedOnRestart(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 30)
+	conf := NewConf(testPath(), maxFileSizeForBlocks(t, blocks[:10]))
+	defer os.RemoveAll(conf.blockStorageDir)
+	env := newTestEnv(t, conf)
+
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	blkfileMgrWrapper.addBlocks(blocks)
+	mgr := blkfileMgrWrapper.blockfileMgr
+
+	// simulate a crash after the index is updated but before the block files are truncated
+	_, err := mgr.prepareRollback(4)
+	assert.NoError(t, err)
+	blkfileMgrWrapper.close()
+	env.provider.Close()
+
+	env = newTestEnv(t, conf)
+	defer env.provider.Close()
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, "testLedger")
+	defer blkfileMgrWrapper.close()
+	mgr = blkfileMgrWrapper.blockfileMgr
+	assert.Equal(t, uint64(5), mgr.getBlockchainInfo().Height)
+	blkfileMgrWrapper.testGetBlockByNumber(blocks[:5], 0)
+	assert.False(t, blockfileExists(mgr.rootDir, mgr.cpInfo.latestFileChunkSuffixNum+1))
+	marker, err := mgr.db.Get(blkMgrRollbackInfoKey)
+	assert.NoError(t, err)
+	assert.Nil(t, marker)
+}
+
+func TestRollbackPrunedTarget(t *testing.T) {
+	blocks := testutil.ConstructTestBlocks(t, 30)
+	conf := NewConf(testPath(), maxFileSizeForBlocks(t, blocks[:5]))
+	defer os.RemoveAll(conf.blockStorageDir)
+	env := newTestEnv(t, conf)
+
+	blkfileMgrWrapper := newTestBlockfileWrapper(env, "testLedger")
+	blkfileMgrWrapper.addBlocks(blocks)
+	assert.NoError(t, blkfileMgrWrapper.blockfileMgr.pruneBlocksBefore(20, ""))
+	firstAvailable := blkfileMgrWrapper.blockfileMgr.getFirstAvailableBlockNum()
+	assert.True(t, firstAvailable > 0)
+	blkfileMgrWrapper.close()
+	indexConfig := env.provider.indexConfig
+	env.provider.Close()
+
+	err := Rollback(conf, indexConfig, "testLedger", 0)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "has been pruned")
+
+	// the failed rollback leaves the block store untouched
+	env = newTestEnv(t, conf)
+	defer env.provider.Close()
+	blkfileMgrWrapper = newTestBlockfileWrapper(env, "testLedger")
+	defer blkfileMgrWrapper.close()
+	assert.Equal(t, uint64(30), blkfileMgrWrapper.blockfileMgr.getBlockchainInfo().Height)
+}
@@ -52,6 +52,9 @@ func (itr *blocksItr) waitForBlock(blockNum uint64) uint64 {
 func (itr *blocksItr) initStream() error {
 	var lp *fileLocPointer
 	var err error
+	if err = itr.mgr.checkBlockNumNotPruned(itr.blockNumToRetrieve); err != nil {
+		return err
+	}
 	if lp, err = itr.mgr.index.getBlockLocByBlockNum(itr.blockNumToRetrieve); err != nil {
 		return err
 	}
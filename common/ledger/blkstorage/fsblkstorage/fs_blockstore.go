@@ -86,6 +86,22 @@ func (store *fsBlockStore) RetrieveTxValidationCodeByTxID(txID string) (peer.TxV
 	return store.fileMgr.retrieveTxValidationCodeByTxID(txID)
 }
 
+// GetFirstAvailableBlockNum returns the number of the oldest block that has not been pruned
+func (store *fsBlockStore) GetFirstAvailableBlockNum() (uint64, error) {
+	return store.fileMgr.getFirstAvailableBlockNum(), nil
+}
+
+// PruneBlocksBefore removes the block files that contain only the blocks with number lower than `blockNum`.
+// If `archiveDir` is not empty, the block files are moved to a sub-directory (named after the ledger) of `archiveDir`
+func (store *fsBlockStore) PruneBlocksBefore(blockNum uint64, archiveDir string) error {
+	return store.fileMgr.pruneBlocksBefore(blockNum, archiveDir)
+}
+
+// BootstrapFromSnapshot adds the given block as the first block of an empty block store
+func (store *fsBlockStore) BootstrapFromSnapshot(lastBlock *common.Block) error {
+	return store.fileMgr.bootstrapFromSnapshot(lastBlock)
+}
+
 // Shutdown shuts down the block store
 func (store *fsBlockStore) Shutdown() {
 	logger.Debugf("closing fs blockStore:%s", store.id)
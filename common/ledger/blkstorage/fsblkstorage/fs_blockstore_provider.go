@@ -17,6 +17,8 @@ limitations under the License.
 package fsblkstorage
 
 import (
+	"os"
+
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/util"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
@@ -60,6 +62,27 @@ func (p *FsBlockstoreProvider) List() ([]string, error) {
 	return util.ListSubdirs(p.conf.getChainsDir())
 }
 
+// Remove deletes the index entries and the block files of the given ledger.
+// The index is removed first, so that an interrupted removal leaves block
+// files which are indexed again when the block store is next opened.
+func (p *FsBlockstoreProvider) Remove(ledgerid string) error {
+	indexStoreHandle := p.leveldbProvider.GetDBHandle(ledgerid)
+	itr := indexStoreHandle.GetIterator(nil, nil)
+	batch := leveldbhelper.NewUpdateBatch()
+	for itr.Next() {
+		batch.Delete(itr.Key())
+	}
+	err := itr.Error()
+	itr.Release()
+	if err != nil {
+		return err
+	}
+	if err := indexStoreHandle.WriteBatch(batch, true); err != nil {
+		return err
+	}
+	return os.RemoveAll(p.conf.getLedgerBlockDir(ledgerid))
+}
+
 // Close closes the FsBlockstoreProvider
 func (p *FsBlockstoreProvider) Close() {
 	p.leveldbProvider.Close()
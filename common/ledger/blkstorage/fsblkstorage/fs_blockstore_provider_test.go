@@ -147,6 +147,42 @@ func TestBlockStoreProvider(t *testing.T) {
 
 }
 
+func TestRemoveBlockStore(t *testing.T) {
+	env := newTestEnv(t, NewConf(testPath(), 0))
+	defer env.Cleanup()
+
+	provider := env.provider
+	blocks := testutil.ConstructTestBlocks(t, 5)
+	for _, ledgerid := range []string{"ledger1", "ledger2"} {
+		store, _ := provider.OpenBlockStore(ledgerid)
+		for _, b := range blocks {
+			testutil.AssertNoError(t, store.AddBlock(b), "")
+		}
+		store.Shutdown()
+	}
+
+	testutil.AssertNoError(t, provider.Remove("ledger1"), "")
+	exists, err := provider.Exists("ledger1")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, exists, false)
+	storeNames, _ := provider.List()
+	testutil.AssertEquals(t, storeNames, []string{"ledger2"})
+
+	// a ledger created again with the same id starts empty
+	store1, _ := provider.OpenBlockStore("ledger1")
+	defer store1.Shutdown()
+	bcInfo, err := store1.GetBlockchainInfo()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, bcInfo.Height, uint64(0))
+	_, err = store1.RetrieveBlockByHash(blocks[0].Header.Hash())
+	testutil.AssertError(t, err, "Expected the index of the removed ledger to be empty")
+
+	// the other ledger is untouched
+	store2, _ := provider.OpenBlockStore("ledger2")
+	defer store2.Shutdown()
+	checkBlocks(t, blocks, store2)
+}
+
 func constructLedgerid(id int) string {
 	return fmt.Sprintf("ledger_%d", id)
 }
@@ -0,0 +1,128 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// BlockPrunePolicy is a `ledger.PrunePolicy` that can be evaluated against a `BlockStore`
+// for deciding which blocks are no longer required to be retained
+type BlockPrunePolicy interface {
+	// RetainFrom returns the number of the oldest block that the policy requires to be retained in the store
+	RetainFrom(store BlockStore) (uint64, error)
+}
+
+// KeepLastNBlocksPolicy is a `BlockPrunePolicy` that retains only the latest N blocks
+type KeepLastNBlocksPolicy struct {
+	N uint64
+}
+
+// RetainFrom implements method in interface `BlockPrunePolicy`
+func (p *KeepLastNBlocksPolicy) RetainFrom(store BlockStore) (uint64, error) {
+	if p.N == 0 {
+		return 0, fmt.Errorf("number of blocks to retain should be greater than zero")
+	}
+	bcInfo, err := store.GetBlockchainInfo()
+	if err != nil {
+		return 0, err
+	}
+	if bcInfo.Height <= p.N {
+		return 0, nil
+	}
+	return bcInfo.Height - p.N, nil
+}
+
+// KeepBlocksNewerThanPolicy is a `BlockPrunePolicy` that retains only the blocks whose
+// transactions were created at or after the given time. The latest block is always retained
+type KeepBlocksNewerThanPolicy struct {
+	Time time.Time
+}
+
+// RetainFrom implements method in interface `BlockPrunePolicy`
+func (p *KeepBlocksNewerThanPolicy) RetainFrom(store BlockStore) (uint64, error) {
+	bcInfo, err := store.GetBlockchainInfo()
+	if err != nil {
+		return 0, err
+	}
+	if bcInfo.Height == 0 {
+		return 0, nil
+	}
+	firstBlockNum, err := store.GetFirstAvailableBlockNum()
+	if err != nil {
+		return 0, err
+	}
+	lastBlockNum := bcInfo.Height - 1
+	// blocks are ordered by the time of their creation, hence find the first block
+	// in the available range that is not older than the policy time
+	var searchErr error
+	n := sort.Search(int(lastBlockNum-firstBlockNum), func(i int) bool {
+		if searchErr != nil {
+			return true
+		}
+		block, err := store.RetrieveBlockByNumber(firstBlockNum + uint64(i))
+		if err != nil {
+			searchErr = err
+			return true
+		}
+		blockTime, err := blockTimestamp(block)
+		if err != nil {
+			searchErr = err
+			return true
+		}
+		return !blockTime.Before(p.Time)
+	})
+	if searchErr != nil {
+		return 0, searchErr
+	}
+	return firstBlockNum + uint64(n), nil
+}
+
+// ArchiveThenDeletePolicy is a `BlockPrunePolicy` that prunes the blocks as decided by the wrapped
+// policy but, instead of deleting the pruned block files, moves them to the archive directory
+type ArchiveThenDeletePolicy struct {
+	Policy     BlockPrunePolicy
+	ArchiveDir string
+}
+
+// RetainFrom implements method in interface `BlockPrunePolicy`
+func (p *ArchiveThenDeletePolicy) RetainFrom(store BlockStore) (uint64, error) {
+	if p.ArchiveDir == "" {
+		return 0, fmt.Errorf("archive directory is not specified")
+	}
+	return p.Policy.RetainFrom(store)
+}
+
+// ArchiveDirForPolicy returns the directory to which the block files pruned under the given
+// policy are to be moved. An empty string is returned if the pruned block files are to be deleted
+func ArchiveDirForPolicy(policy BlockPrunePolicy) string {
+	if archivePolicy, ok := policy.(*ArchiveThenDeletePolicy); ok {
+		return archivePolicy.ArchiveDir
+	}
+	return ""
+}
+
+// blockTimestamp returns the time of creation of the first transaction in the block
+func blockTimestamp(block *common.Block) (time.Time, error) {
+	env, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return time.Time{}, err
+	}
+	chdr, err := utils.ChannelHeader(env)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if chdr.Timestamp == nil {
+		return time.Time{}, fmt.Errorf("timestamp missing in the channel header of block [%d]", block.Header.Number)
+	}
+	return time.Unix(chdr.Timestamp.Seconds, int64(chdr.Timestamp.Nanos)), nil
+}
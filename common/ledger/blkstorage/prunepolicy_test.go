@@ -0,0 +1,111 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blkstorage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeepLastNBlocksPolicy(t *testing.T) {
+	store := newMockBlockStore(t, time.Now(), 10)
+	retainFrom, err := (&KeepLastNBlocksPolicy{N: 3}).RetainFrom(store)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), retainFrom)
+
+	retainFrom, err = (&KeepLastNBlocksPolicy{N: 20}).RetainFrom(store)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), retainFrom)
+
+	_, err = (&KeepLastNBlocksPolicy{}).RetainFrom(store)
+	assert.EqualError(t, err, "number of blocks to retain should be greater than zero")
+}
+
+func TestKeepBlocksNewerThanPolicy(t *testing.T) {
+	start := time.Unix(1500000000, 0)
+	// block i is created at start + i hours
+	store := newMockBlockStore(t, start, 10)
+
+	retainFrom, err := (&KeepBlocksNewerThanPolicy{Time: start.Add(4 * time.Hour)}).RetainFrom(store)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(4), retainFrom)
+
+	retainFrom, err = (&KeepBlocksNewerThanPolicy{Time: start.Add(90 * time.Minute)}).RetainFrom(store)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), retainFrom)
+
+	retainFrom, err = (&KeepBlocksNewerThanPolicy{Time: start.Add(-time.Hour)}).RetainFrom(store)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), retainFrom)
+
+	// the latest block is always retained
+	retainFrom, err = (&KeepBlocksNewerThanPolicy{Time: start.Add(100 * time.Hour)}).RetainFrom(store)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(9), retainFrom)
+
+	// the search starts from the first available block
+	store.firstAvailableBlockNum = 6
+	retainFrom, err = (&KeepBlocksNewerThanPolicy{Time: start.Add(4 * time.Hour)}).RetainFrom(store)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(6), retainFrom)
+}
+
+func TestArchiveThenDeletePolicy(t *testing.T) {
+	store := newMockBlockStore(t, time.Now(), 10)
+	policy := &ArchiveThenDeletePolicy{Policy: &KeepLastNBlocksPolicy{N: 4}, ArchiveDir: "/tmp/archive"}
+	retainFrom, err := policy.RetainFrom(store)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(6), retainFrom)
+	assert.Equal(t, "/tmp/archive", ArchiveDirForPolicy(policy))
+	assert.Equal(t, "", ArchiveDirForPolicy(policy.Policy))
+
+	_, err = (&ArchiveThenDeletePolicy{Policy: &KeepLastNBlocksPolicy{N: 4}}).RetainFrom(store)
+	assert.EqualError(t, err, "archive directory is not specified")
+}
+
+type mockBlockStore struct {
+	BlockStore
+	blocks                 []*common.Block
+	firstAvailableBlockNum uint64
+}
+
+func newMockBlockStore(t *testing.T, start time.Time, numBlocks int) *mockBlockStore {
+	store := &mockBlockStore{}
+	for i := 0; i < numBlocks; i++ {
+		blockTime := start.Add(time.Duration(i) * time.Hour)
+		chdr := &common.ChannelHeader{
+			Type:      int32(common.HeaderType_ENDORSER_TRANSACTION),
+			Timestamp: &timestamp.Timestamp{Seconds: blockTime.Unix(), Nanos: int32(blockTime.Nanosecond())},
+		}
+		env := &common.Envelope{
+			Payload: utils.MarshalOrPanic(&common.Payload{
+				Header: &common.Header{ChannelHeader: utils.MarshalOrPanic(chdr)},
+			}),
+		}
+		block := common.NewBlock(uint64(i), nil)
+		block.Data.Data = [][]byte{utils.MarshalOrPanic(env)}
+		store.blocks = append(store.blocks, block)
+	}
+	return store
+}
+
+func (s *mockBlockStore) GetBlockchainInfo() (*common.BlockchainInfo, error) {
+	return &common.BlockchainInfo{Height: uint64(len(s.blocks))}, nil
+}
+
+func (s *mockBlockStore) GetFirstAvailableBlockNum() (uint64, error) {
+	return s.firstAvailableBlockNum, nil
+}
+
+func (s *mockBlockStore) RetrieveBlockByNumber(blockNum uint64) (*common.Block, error) {
+	return s.blocks[blockNum], nil
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package fileledger
 
 import (
+	"path/filepath"
 	"sync"
 
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
@@ -28,6 +29,9 @@ import (
 type fileLedgerFactory struct {
 	blkstorageProvider blkstorage.BlockStoreProvider // TODO: READ BlockStoreProvider
 	ledgers            map[string]blockledger.ReadWriter	// TODO: READ blockledger.ReadWriter
+	blockStores        map[string]blkstorage.BlockStore
+	directory          string
+	retention          RetentionPolicy
 	mutex              sync.Mutex
 }
 
@@ -49,11 +53,32 @@ func (flf *fileLedgerFactory) GetOrCreate(chainID string) (blockledger.ReadWrite
 		return nil, err
 	}
 	// new file ledger by blockStore
-	ledger = NewFileLedger(blockStore)
+	fileLedger := NewFileLedger(blockStore)
+	fileLedger.retention = flf.retention
+	configBlockPath := filepath.Join(flf.directory, fsblkstorage.ChainsDir, key, configBlockFileName)
+	if err := fileLedger.loadConfigBlock(configBlockPath); err != nil {
+		blockStore.Shutdown()
+		return nil, err
+	}
+	ledger = fileLedger
 	flf.ledgers[key] = ledger // 保存 ledger
+	flf.blockStores[key] = blockStore
 	return ledger, nil
 }
 
+// Remove closes the ledger of the given chain, if it is open, and deletes it
+func (flf *fileLedgerFactory) Remove(chainID string) error {
+	flf.mutex.Lock()
+	defer flf.mutex.Unlock()
+
+	if blockStore, ok := flf.blockStores[chainID]; ok {
+		blockStore.Shutdown()
+		delete(flf.blockStores, chainID)
+		delete(flf.ledgers, chainID)
+	}
+	return flf.blkstorageProvider.Remove(chainID)
+}
+
 // ChainIDs returns the chain IDs the factory is aware of
 func (flf *fileLedgerFactory) ChainIDs() []string {
 	chainIDs, err := flf.blkstorageProvider.List()
@@ -70,12 +95,21 @@ func (flf *fileLedgerFactory) Close() {
 
 // New creates a new ledger factory
 func New(directory string) blockledger.Factory {
+	return NewWithRetention(directory, RetentionPolicy{})
+}
+
+// NewWithRetention creates a new ledger factory whose ledgers prune the
+// blocks out of the given retention policy
+func NewWithRetention(directory string, retention RetentionPolicy) blockledger.Factory {
 	return &fileLedgerFactory{
 		blkstorageProvider: fsblkstorage.NewProvider(
 			fsblkstorage.NewConf(directory, -1),
 			&blkstorage.IndexConfig{
 				AttrsToIndex: []blkstorage.IndexableAttr{blkstorage.IndexableAttrBlockNum}},
 		),
-		ledgers: make(map[string]blockledger.ReadWriter),
+		ledgers:     make(map[string]blockledger.ReadWriter),
+		blockStores: make(map[string]blkstorage.BlockStore),
+		directory:   directory,
+		retention:   retention,
 	}
 }
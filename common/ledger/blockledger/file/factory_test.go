@@ -19,11 +19,13 @@ package fileledger
 import (
 	"fmt"
 	"io/ioutil"
+	"os"
 	"testing"
 
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	cb "github.com/hyperledger/fabric/protos/common"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -50,6 +52,10 @@ func (mbsp *mockBlockStoreProvider) List() ([]string, error) {
 	return mbsp.list, mbsp.error
 }
 
+func (mbsp *mockBlockStoreProvider) Remove(ledgerid string) error {
+	return mbsp.error
+}
+
 func (mbsp *mockBlockStoreProvider) Close() {
 }
 
@@ -57,6 +63,7 @@ func TestBlockstoreProviderError(t *testing.T) {
 	flf := &fileLedgerFactory{
 		blkstorageProvider: &mockBlockStoreProvider{error: fmt.Errorf("blockstorage provider error")},
 		ledgers:            make(map[string]blockledger.ReadWriter),
+		blockStores:        make(map[string]blkstorage.BlockStore),
 	}
 	assert.Panics(
 		t,
@@ -90,3 +97,24 @@ func TestMultiReinitialization(t *testing.T) {
 	assert.Equal(t, 3, len(flf.ChainIDs()), "Expected chain to be recovered")
 	flf.Close()
 }
+
+func TestRemove(t *testing.T) {
+	dir, err := ioutil.TempDir("", "hyperledger_fabric")
+	assert.NoError(t, err, "Error creating temp dir: %s", err)
+	defer os.RemoveAll(dir)
+
+	flf := New(dir)
+	defer flf.Close()
+	chain, err := flf.GetOrCreate("foo")
+	assert.NoError(t, err, "Error GetOrCreate chain")
+	assert.NoError(t, chain.Append(blockledger.CreateNextBlock(chain, []*cb.Envelope{{Payload: []byte("My Data")}})))
+	_, err = flf.GetOrCreate("bar")
+	assert.NoError(t, err, "Error GetOrCreate chain")
+
+	assert.NoError(t, flf.Remove("foo"), "Error removing chain")
+	assert.Equal(t, []string{"bar"}, flf.ChainIDs())
+
+	chain, err = flf.GetOrCreate("foo")
+	assert.NoError(t, err, "Error GetOrCreate chain")
+	assert.Zero(t, chain.Height(), "Expected chain to be empty")
+}
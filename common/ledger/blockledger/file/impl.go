@@ -17,8 +17,12 @@ limitations under the License.
 package fileledger
 
 import (
+	"sync/atomic"
+	"time"
+
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
@@ -42,6 +46,15 @@ func init() {
 type FileLedger struct {
 	blockStore FileLedgerBlockStore
 	signal     chan struct{}
+	retention  RetentionPolicy
+	pruning    int32     // set while the retention policy is enforced
+	suspended  int32     // set while the retention policy is suspended
+	lastPrune  time.Time // when the retention policy was last enforced
+
+	// configBlockPath is the file the copy of the latest config block is
+	// stored in before it is pruned, and configBlock holds the copy
+	configBlockPath string
+	configBlock     atomic.Value
 }
 
 // FileLedgerBlockStore defines the interface to interact with deliver when using a
@@ -68,6 +81,10 @@ type fileLedgerIterator struct {
 func (i *fileLedgerIterator) Next() (*cb.Block, cb.Status) {
 	result, err := i.commonIterator.Next()
 	if err != nil {
+		if _, ok := err.(*blkstorage.ErrBlockPruned); ok {
+			logger.Warning(err)
+			return nil, cb.Status_NOT_FOUND
+		}
 		logger.Error(err)
 		return nil, cb.Status_SERVICE_UNAVAILABLE
 	}
@@ -97,8 +114,8 @@ func (i *fileLedgerIterator) Close() {
 func (fl *FileLedger) Iterator(startPosition *ab.SeekPosition) (blockledger.Iterator, uint64) {
 	var startingBlockNumber uint64
 	switch start := startPosition.Type.(type) {	/*分析起始位置类型*/
-	case *ab.SeekPosition_Oldest: /*搜索最旧区块，区块号为 0 */
-		startingBlockNumber = 0
+	case *ab.SeekPosition_Oldest: /*搜索最旧区块，即最旧的未被裁剪的区块*/
+		startingBlockNumber = fl.firstAvailableBlockNum()
 	case *ab.SeekPosition_Newest:	/*搜索最新区块*/
 		info, err := fl.blockStore.GetBlockchainInfo() /*获取区块链信息*/
 		if err != nil {
@@ -112,6 +129,12 @@ func (fl *FileLedger) Iterator(startPosition *ab.SeekPosition) (blockledger.Iter
 		if startingBlockNumber > height {	/*若超过高度，则报错*/
 			return &blockledger.NotFoundErrorIterator{}, 0
 		}
+		if startingBlockNumber < fl.firstAvailableBlockNum() {	/*区块已被裁剪*/
+			if block := fl.storedConfigBlock(); block != nil && block.Header.Number == startingBlockNumber {
+				return &configBlockIterator{ledger: fl, block: block, number: startingBlockNumber}, startingBlockNumber
+			}
+			return &blockledger.NotFoundErrorIterator{}, 0
+		}
 	default:
 		return &blockledger.NotFoundErrorIterator{}, 0
 	}
@@ -141,6 +164,7 @@ func (fl *FileLedger) Append(block *cb.Block) error {
 	if err == nil {
 		close(fl.signal)
 		fl.signal = make(chan struct{})
+		fl.maybePrune()
 	}
 	return err
 }
@@ -117,6 +117,18 @@ func (mbs *mockBlockStore) RetrieveTxValidationCodeByTxID(txID string) (peer.TxV
 	return mbs.txValidationCode, mbs.defaultError
 }
 
+func (mbs *mockBlockStore) GetFirstAvailableBlockNum() (uint64, error) {
+	return 0, mbs.defaultError
+}
+
+func (mbs *mockBlockStore) PruneBlocksBefore(blockNum uint64, archiveDir string) error {
+	return mbs.defaultError
+}
+
+func (mbs *mockBlockStore) BootstrapFromSnapshot(lastBlock *cb.Block) error {
+	return mbs.defaultError
+}
+
 func (*mockBlockStore) Shutdown() {
 }
 
@@ -0,0 +1,298 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fileledger
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync/atomic"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// configBlockFileName is the name of the file, in the directory of the block
+// files of a channel, holding the copy of its latest config block
+const configBlockFileName = "lastconfigblock"
+
+// retentionCheckInterval is the minimum time between two enforcements of the
+// retention policy triggered by appended blocks
+var retentionCheckInterval = time.Minute
+
+// RetentionPolicy bounds the blocks kept by a FileLedger. A block is pruned
+// once it is neither among the MaxBlocks most recent blocks nor younger than
+// MaxAge, a zero value meaning no bound. Blocks are pruned by whole block
+// files, so some of the blocks beyond the bounds are retained until their
+// block file is complete. The latest config block remains readable once it
+// is pruned, from a copy stored alongside the block files.
+type RetentionPolicy struct {
+	MaxBlocks uint64
+	MaxAge    time.Duration
+}
+
+// enabled returns whether the policy prunes any block
+func (p RetentionPolicy) enabled() bool {
+	return p.MaxBlocks > 0 || p.MaxAge > 0
+}
+
+// PrunableBlockStore is a FileLedgerBlockStore which can prune its oldest
+// blocks. When the block store of a FileLedger implements it, seeking the
+// oldest block resolves to the oldest retained block, and seeking a pruned
+// block is answered with NOT_FOUND.
+type PrunableBlockStore interface {
+	FileLedgerBlockStore
+	RetrieveBlockByNumber(blockNum uint64) (*cb.Block, error)
+	GetFirstAvailableBlockNum() (uint64, error)
+	PruneBlocksBefore(blockNum uint64, archiveDir string) error
+}
+
+// firstAvailableBlockNum returns the number of the oldest retained block
+func (fl *FileLedger) firstAvailableBlockNum() uint64 {
+	store, ok := fl.blockStore.(PrunableBlockStore)
+	if !ok {
+		return 0
+	}
+	blockNum, err := store.GetFirstAvailableBlockNum()
+	if err != nil {
+		logger.Panic(err)
+	}
+	return blockNum
+}
+
+// SuspendRetention suspends the enforcement of the retention policy, or
+// resumes it. It is suspended while the consenter of the channel may read any
+// past block.
+func (fl *FileLedger) SuspendRetention(suspend bool) {
+	var suspended int32
+	if suspend {
+		suspended = 1
+	}
+	atomic.StoreInt32(&fl.suspended, suspended)
+}
+
+// maybePrune enforces the retention policy in the background, unless it has
+// been enforced recently or is being enforced
+func (fl *FileLedger) maybePrune() {
+	if !fl.retention.enabled() || atomic.LoadInt32(&fl.suspended) != 0 || time.Since(fl.lastPrune) < retentionCheckInterval {
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&fl.pruning, 0, 1) {
+		return
+	}
+	fl.lastPrune = time.Now()
+	go func() {
+		defer atomic.StoreInt32(&fl.pruning, 0)
+		if err := fl.Prune(time.Now()); err != nil {
+			logger.Errorf("Failed enforcing the retention policy of the ledger: %s", err)
+		}
+	}()
+}
+
+// Prune removes the blocks which are out of the retention policy at the
+// given time, unless the retention policy is suspended
+func (fl *FileLedger) Prune(now time.Time) error {
+	store, ok := fl.blockStore.(PrunableBlockStore)
+	if !ok || !fl.retention.enabled() || atomic.LoadInt32(&fl.suspended) != 0 {
+		return nil
+	}
+
+	height := fl.Height()
+	if height == 0 {
+		return nil
+	}
+	first, err := store.GetFirstAvailableBlockNum()
+	if err != nil {
+		return err
+	}
+
+	// the newest block is always retained
+	retainFrom := height - 1
+	if maxBlocks := fl.retention.MaxBlocks; maxBlocks > 0 {
+		retainFrom = 0
+		if maxBlocks < height {
+			retainFrom = height - maxBlocks
+		}
+	}
+	if fl.retention.MaxAge > 0 {
+		sinceBlock, err := firstBlockSince(store, first, height, now.Add(-fl.retention.MaxAge))
+		if err != nil {
+			return err
+		}
+		if fl.retention.MaxBlocks == 0 || sinceBlock < retainFrom {
+			retainFrom = sinceBlock
+		}
+	}
+
+	newest, err := store.RetrieveBlockByNumber(height - 1)
+	if err != nil {
+		return errors.WithMessage(err, "could not retrieve the newest block")
+	}
+	lastConfig, err := utils.GetLastConfigIndexFromBlock(newest)
+	if err != nil {
+		return errors.WithMessage(err, "could not find the last config block")
+	}
+	if lastConfig < retainFrom {
+		if fl.configBlockPath == "" {
+			// there is nowhere to store a copy of the last config block
+			retainFrom = lastConfig
+		} else if err := fl.storeConfigBlock(store, lastConfig); err != nil {
+			return err
+		}
+	}
+
+	if retainFrom <= first {
+		return nil
+	}
+	logger.Debugf("Pruning the blocks before block [%d], last config block is [%d]", retainFrom, lastConfig)
+	return store.PruneBlocksBefore(retainFrom, "")
+}
+
+// firstBlockSince returns the number of the first block in [first, height)
+// which was created at or after the given time, or height - 1 if there is
+// none, as the newest block is always retained. Blocks are assumed to be
+// ordered by creation time.
+func firstBlockSince(store PrunableBlockStore, first, height uint64, since time.Time) (uint64, error) {
+	var searchErr error
+	offset := sort.Search(int(height-1-first), func(i int) bool {
+		if searchErr != nil {
+			return true
+		}
+		block, err := store.RetrieveBlockByNumber(first + uint64(i))
+		if err != nil {
+			searchErr = errors.WithMessage(err, "could not retrieve block")
+			return true
+		}
+		created, err := blockTimestamp(block)
+		if err != nil {
+			searchErr = err
+			return true
+		}
+		return !created.Before(since)
+	})
+	if searchErr != nil {
+		return 0, searchErr
+	}
+	return first + uint64(offset), nil
+}
+
+// blockTimestamp returns the timestamp of the first transaction of the block
+func blockTimestamp(block *cb.Block) (time.Time, error) {
+	env, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return time.Time{}, errors.WithMessage(err, "could not extract the first transaction of block")
+	}
+	chdr, err := utils.ChannelHeader(env)
+	if err != nil {
+		return time.Time{}, errors.WithMessage(err, "could not read the channel header of block")
+	}
+	if chdr.Timestamp == nil {
+		return time.Time{}, errors.Errorf("block [%d] has no timestamp", block.Header.Number)
+	}
+	return time.Unix(chdr.Timestamp.Seconds, int64(chdr.Timestamp.Nanos)), nil
+}
+
+// loadConfigBlock sets the file the copy of the latest config block is stored
+// in, and reads the copy if it was stored
+func (fl *FileLedger) loadConfigBlock(path string) error {
+	fl.configBlockPath = path
+	blockBytes, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "could not read the stored config block")
+	}
+	block := &cb.Block{}
+	if err := proto.Unmarshal(blockBytes, block); err != nil {
+		return errors.Wrap(err, "could not unmarshal the stored config block")
+	}
+	fl.configBlock.Store(block)
+	return nil
+}
+
+// storeConfigBlock stores a copy of the config block with the given number,
+// before it is pruned, unless it is already stored
+func (fl *FileLedger) storeConfigBlock(store PrunableBlockStore, number uint64) error {
+	if stored := fl.storedConfigBlock(); stored != nil && stored.Header.Number == number {
+		return nil
+	}
+	block, err := store.RetrieveBlockByNumber(number)
+	if err != nil {
+		return errors.WithMessage(err, "could not retrieve the last config block")
+	}
+	blockBytes, err := proto.Marshal(block)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal the last config block")
+	}
+	tmpPath := fl.configBlockPath + ".tmp"
+	if err := ioutil.WriteFile(tmpPath, blockBytes, 0640); err != nil {
+		return errors.Wrap(err, "could not store the last config block")
+	}
+	if err := os.Rename(tmpPath, fl.configBlockPath); err != nil {
+		return errors.Wrap(err, "could not store the last config block")
+	}
+	fl.configBlock.Store(block)
+	logger.Debugf("Stored a copy of the last config block [%d]", number)
+	return nil
+}
+
+// storedConfigBlock returns the stored copy of the latest config block, or
+// nil if none was stored
+func (fl *FileLedger) storedConfigBlock() *cb.Block {
+	block, _ := fl.configBlock.Load().(*cb.Block)
+	return block
+}
+
+// configBlockIterator returns the stored copy of the latest config block,
+// then the blocks after it which are retained
+type configBlockIterator struct {
+	ledger *FileLedger
+	block  *cb.Block
+	number uint64
+	next   blockledger.Iterator
+}
+
+// Next returns the config block first, then blocks until there is a new block
+// available, or until Close is called
+func (i *configBlockIterator) Next() (*cb.Block, cb.Status) {
+	if block := i.block; block != nil {
+		i.block = nil
+		return block, cb.Status_SUCCESS
+	}
+	return i.following().Next()
+}
+
+// ReadyChan supplies a channel which will block until Next will not block
+func (i *configBlockIterator) ReadyChan() <-chan struct{} {
+	if i.block != nil {
+		return closedChan
+	}
+	return i.following().ReadyChan()
+}
+
+// Close releases resources acquired by the Iterator
+func (i *configBlockIterator) Close() {
+	if i.next != nil {
+		i.next.Close()
+	}
+}
+
+// following returns the iterator over the blocks after the config block
+func (i *configBlockIterator) following() blockledger.Iterator {
+	if i.next == nil {
+		i.next, _ = i.ledger.Iterator(&ab.SeekPosition{
+			Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: i.number + 1}},
+		})
+	}
+	return i.next
+}
@@ -0,0 +1,247 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package fileledger
+
+import (
+	"io/ioutil"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
+	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var retentionEpoch = time.Unix(1500000000, 0)
+
+// newRetentionLedger returns a ledger whose block files hold a few blocks each
+func newRetentionLedger(t *testing.T, retention RetentionPolicy) (*FileLedger, func()) {
+	dir, err := ioutil.TempDir("", "retention")
+	require.NoError(t, err)
+	flf := newRetentionLedgerFactory(dir, retention)
+	rw, err := flf.GetOrCreate("testchannel")
+	require.NoError(t, err)
+	return rw.(*FileLedger), func() {
+		flf.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func newRetentionLedgerFactory(dir string, retention RetentionPolicy) *fileLedgerFactory {
+	return &fileLedgerFactory{
+		blkstorageProvider: fsblkstorage.NewProvider(
+			fsblkstorage.NewConf(dir, 1024),
+			&blkstorage.IndexConfig{AttrsToIndex: []blkstorage.IndexableAttr{blkstorage.IndexableAttrBlockNum}},
+		),
+		ledgers:     make(map[string]blockledger.ReadWriter),
+		blockStores: make(map[string]blkstorage.BlockStore),
+		directory:   dir,
+		retention:   retention,
+	}
+}
+
+// appendBlocks appends blocks created an hour apart, each pointing to the
+// given last config block, or to itself if lastConfig is nil
+func appendBlocks(t *testing.T, fl *FileLedger, count int, lastConfig *uint64) {
+	for i := 0; i < count; i++ {
+		number := fl.Height()
+		env := &cb.Envelope{
+			Payload: utils.MarshalOrPanic(&cb.Payload{
+				Header: &cb.Header{
+					ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{
+						ChannelId: "testchannel",
+						Timestamp: &timestamp.Timestamp{Seconds: retentionEpoch.Add(time.Duration(number) * time.Hour).Unix()},
+					}),
+				},
+				Data: make([]byte, 200),
+			}),
+		}
+		block := blockledger.CreateNextBlock(fl, []*cb.Envelope{env})
+		configIndex := number
+		if lastConfig != nil {
+			configIndex = *lastConfig
+		}
+		block.Metadata.Metadata[cb.BlockMetadataIndex_LAST_CONFIG] = utils.MarshalOrPanic(&cb.Metadata{
+			Value: utils.MarshalOrPanic(&cb.LastConfig{Index: configIndex}),
+		})
+		require.NoError(t, fl.Append(block))
+	}
+}
+
+func TestRetentionMaxBlocks(t *testing.T) {
+	fl, cleanup := newRetentionLedger(t, RetentionPolicy{MaxBlocks: 5})
+	defer cleanup()
+	appendBlocks(t, fl, 20, nil)
+
+	require.NoError(t, fl.Prune(time.Now()))
+	first := fl.firstAvailableBlockNum()
+	assert.True(t, first > 0 && first <= 15, "Expected blocks before block 15 to be pruned, first available block is %d", first)
+	assert.Equal(t, uint64(20), fl.Height())
+
+	// seeking the oldest block starts at the first retained block
+	it, num := fl.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Oldest{}})
+	assert.Equal(t, first, num)
+	block, status := it.Next()
+	assert.Equal(t, cb.Status_SUCCESS, status)
+	assert.Equal(t, first, block.Header.Number)
+	it.Close()
+
+	// seeking a pruned block is not found
+	it, _ = fl.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: first - 1}}})
+	_, status = it.Next()
+	assert.Equal(t, cb.Status_NOT_FOUND, status)
+	it.Close()
+
+	// blocks keep being appended after pruning
+	appendBlocks(t, fl, 1, nil)
+	assert.Equal(t, uint64(21), fl.Height())
+}
+
+func TestRetentionOldLastConfigBlock(t *testing.T) {
+	dir, err := ioutil.TempDir("", "retention")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	flf := newRetentionLedgerFactory(dir, RetentionPolicy{MaxBlocks: 2})
+	rw, err := flf.GetOrCreate("testchannel")
+	require.NoError(t, err)
+	fl := rw.(*FileLedger)
+
+	lastConfig := uint64(3)
+	appendBlocks(t, fl, 4, nil)
+	appendBlocks(t, fl, 26, &lastConfig)
+
+	require.NoError(t, fl.Prune(time.Now()))
+	first := fl.firstAvailableBlockNum()
+	assert.True(t, first > lastConfig && first <= 28, "Expected blocks up to the bound to be pruned, first available block is %d", first)
+
+	// the last config block is read from its stored copy, followed by the
+	// blocks after it if they are retained
+	block := blockledger.GetBlock(fl, lastConfig)
+	require.NotNil(t, block, "Expected the last config block to be retrievable")
+	assert.Equal(t, lastConfig, block.Header.Number)
+	it, num := fl.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Specified{Specified: &ab.SeekSpecified{Number: lastConfig}}})
+	assert.Equal(t, lastConfig, num)
+	_, status := it.Next()
+	assert.Equal(t, cb.Status_SUCCESS, status)
+	_, status = it.Next()
+	assert.Equal(t, cb.Status_NOT_FOUND, status)
+	it.Close()
+
+	// the other pruned blocks are not found
+	assert.Nil(t, blockledger.GetBlock(fl, lastConfig-1))
+
+	// the copy survives a restart
+	flf.Close()
+	flf = newRetentionLedgerFactory(dir, RetentionPolicy{MaxBlocks: 2})
+	defer flf.Close()
+	rw, err = flf.GetOrCreate("testchannel")
+	require.NoError(t, err)
+	block = blockledger.GetBlock(rw, lastConfig)
+	require.NotNil(t, block, "Expected the last config block to be retrievable after a restart")
+	assert.Equal(t, lastConfig, block.Header.Number)
+}
+
+func TestRetentionLastConfigBlockWithoutCopy(t *testing.T) {
+	fl, cleanup := newRetentionLedger(t, RetentionPolicy{MaxBlocks: 2})
+	defer cleanup()
+	fl.configBlockPath = ""
+	lastConfig := uint64(8)
+	appendBlocks(t, fl, 20, &lastConfig)
+
+	require.NoError(t, fl.Prune(time.Now()))
+	first := fl.firstAvailableBlockNum()
+	assert.True(t, first > 0 && first <= lastConfig, "Expected the last config block to be retained, first available block is %d", first)
+	assert.NotNil(t, blockledger.GetBlock(fl, lastConfig))
+}
+
+func TestRetentionMaxAge(t *testing.T) {
+	fl, cleanup := newRetentionLedger(t, RetentionPolicy{MaxAge: 5 * time.Hour})
+	defer cleanup()
+	appendBlocks(t, fl, 20, nil)
+
+	// block 14 was created 5 hours before block 19
+	require.NoError(t, fl.Prune(retentionEpoch.Add(19*time.Hour)))
+	first := fl.firstAvailableBlockNum()
+	assert.True(t, first > 0 && first <= 14, "Expected blocks before block 14 to be pruned, first available block is %d", first)
+
+	// blocks within either bound are retained
+	fl.retention.MaxBlocks = 20
+	appendBlocks(t, fl, 10, nil)
+	require.NoError(t, fl.Prune(retentionEpoch.Add(29*time.Hour)))
+	assert.Equal(t, first, fl.firstAvailableBlockNum(), "Expected the last 20 blocks to be retained")
+
+	// no block younger than the bound
+	fl.retention.MaxBlocks = 0
+	require.NoError(t, fl.Prune(retentionEpoch.Add(1000*time.Hour)))
+	assert.True(t, fl.firstAvailableBlockNum() <= 29, "Expected the newest block to be retained")
+	assert.NotNil(t, blockledger.GetBlock(fl, 29))
+}
+
+func TestRetentionPrunedWhileIterating(t *testing.T) {
+	fl, cleanup := newRetentionLedger(t, RetentionPolicy{MaxBlocks: 5})
+	defer cleanup()
+	appendBlocks(t, fl, 20, nil)
+
+	it, _ := fl.Iterator(&ab.SeekPosition{Type: &ab.SeekPosition_Oldest{}})
+	defer it.Close()
+	require.NoError(t, fl.Prune(time.Now()))
+	_, status := it.Next()
+	assert.Equal(t, cb.Status_NOT_FOUND, status)
+}
+
+func TestRetentionOnAppend(t *testing.T) {
+	defer func(interval time.Duration) { retentionCheckInterval = interval }(retentionCheckInterval)
+	retentionCheckInterval = 0
+
+	fl, cleanup := newRetentionLedger(t, RetentionPolicy{MaxBlocks: 5})
+	defer cleanup()
+	appendBlocks(t, fl, 20, nil)
+
+	deadline := time.Now().Add(5 * time.Second)
+	for fl.firstAvailableBlockNum() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+		appendBlocks(t, fl, 1, nil)
+	}
+	assert.NotZero(t, fl.firstAvailableBlockNum(), "Expected appended blocks to trigger pruning")
+	for atomic.LoadInt32(&fl.pruning) != 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestRetentionDisabled(t *testing.T) {
+	fl, cleanup := newRetentionLedger(t, RetentionPolicy{})
+	defer cleanup()
+	appendBlocks(t, fl, 20, nil)
+
+	require.NoError(t, fl.Prune(retentionEpoch.Add(1000*time.Hour)))
+	assert.Zero(t, fl.firstAvailableBlockNum())
+}
+
+func TestRetentionSuspended(t *testing.T) {
+	defer func(interval time.Duration) { retentionCheckInterval = interval }(retentionCheckInterval)
+	retentionCheckInterval = 0
+
+	fl, cleanup := newRetentionLedger(t, RetentionPolicy{MaxBlocks: 5})
+	defer cleanup()
+	fl.SuspendRetention(true)
+	appendBlocks(t, fl, 20, nil)
+
+	require.NoError(t, fl.Prune(time.Now()))
+	assert.Zero(t, fl.firstAvailableBlockNum(), "Expected no block to be pruned while the retention is suspended")
+
+	fl.SuspendRetention(false)
+	require.NoError(t, fl.Prune(time.Now()))
+	assert.NotZero(t, fl.firstAvailableBlockNum(), "Expected blocks to be pruned once the retention is resumed")
+}
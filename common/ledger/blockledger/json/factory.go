@@ -122,6 +122,14 @@ func (jlf *jsonLedgerFactory) ChainIDs() []string {
 	return ids
 }
 
+// Remove deletes the directory holding the ledger of the given chain
+func (jlf *jsonLedgerFactory) Remove(chainID string) error {
+	jlf.mutex.Lock()
+	defer jlf.mutex.Unlock()
+	delete(jlf.ledgers, chainID)
+	return os.RemoveAll(filepath.Join(jlf.directory, fmt.Sprintf(chainDirectoryFormatString, chainID)))
+}
+
 // Close is a no-op for the JSON ledger
 func (jlf *jsonLedgerFactory) Close() {
 	return // nothing to do
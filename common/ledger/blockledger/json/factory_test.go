@@ -23,6 +23,8 @@ import (
 	"path"
 	"testing"
 
+	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	cb "github.com/hyperledger/fabric/protos/common"
 	logging "github.com/op/go-logging"
 	"github.com/stretchr/testify/assert"
 )
@@ -119,3 +121,25 @@ func TestClose(t *testing.T) {
 	jlf := New(name)
 	assert.NotPanics(t, func() { jlf.Close() }, "Noop should not pannic")
 }
+
+func TestRemove(t *testing.T) {
+	name, err := ioutil.TempDir("", "hyperledger_fabric")
+	assert.Nil(t, err, "Error creating temp dir: %s", err)
+	defer os.RemoveAll(name)
+
+	jlf := New(name)
+	chain, err := jlf.GetOrCreate("foo")
+	assert.NoError(t, err)
+	assert.NoError(t, chain.Append(blockledger.CreateNextBlock(chain, []*cb.Envelope{{Payload: []byte("My Data")}})))
+	_, err = jlf.GetOrCreate("bar")
+	assert.NoError(t, err)
+
+	assert.NoError(t, jlf.Remove("foo"))
+	assert.Equal(t, []string{"bar"}, jlf.ChainIDs())
+
+	jlf = New(name)
+	assert.Equal(t, []string{"bar"}, jlf.ChainIDs(), "Expected the removed chain not to be recovered")
+	chain, err = jlf.GetOrCreate("foo")
+	assert.NoError(t, err)
+	assert.Zero(t, chain.Height(), "Expected chain to be empty")
+}
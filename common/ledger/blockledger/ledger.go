@@ -27,6 +27,10 @@ type Factory interface {
 	// ChainIDs returns the chain IDs the Factory is aware of
 	ChainIDs() []string
 
+	// Remove closes the ledger of the given chain, if it is open,
+	// and deletes it
+	Remove(chainID string) error
+
 	// Close releases all resources acquired by the factory
 	Close()
 }
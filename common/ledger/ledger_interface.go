@@ -44,6 +44,14 @@ type ResultsIterator interface {
 	Close()
 }
 
+// QueryResultsIterator - an iterator for query result set that supports pagination
+type QueryResultsIterator interface {
+	ResultsIterator
+	// GetBookmarkAndClose returns the bookmark for fetching the next page of the results and releases
+	// the resources occupied by the iterator. The bookmark is empty if there are no more results
+	GetBookmarkAndClose() string
+}
+
 // QueryResult - a general interface for supporting different types of query results. Actual types differ for different queries
 type QueryResult interface{}
 
@@ -0,0 +1,58 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package leveldbhelper
+
+import (
+	"fmt"
+	"syscall"
+
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/opt"
+)
+
+// FileLock is an exclusive lock on a directory that is held across the processes. A leveldb acquires
+// an exclusive lock on its directory while it is open and releases the lock when it is closed or when
+// the process that opened it exits. The FileLock exploits this behavior by opening an empty db
+type FileLock struct {
+	db       *leveldb.DB
+	filePath string
+}
+
+// NewFileLock returns a lock on the given directory
+func NewFileLock(filePath string) *FileLock {
+	return &FileLock{filePath: filePath}
+}
+
+// Lock acquires the lock. An error is returned if the lock is held by another process or by another
+// FileLock in the same process
+func (f *FileLock) Lock() error {
+	dirEmpty, err := util.CreateDirIfMissing(f.filePath)
+	if err != nil {
+		return fmt.Errorf("Error while trying to create dir [%s] for the file lock: %s", f.filePath, err)
+	}
+	f.db, err = leveldb.OpenFile(f.filePath, &opt.Options{ErrorIfMissing: !dirEmpty})
+	if err == syscall.EAGAIN {
+		return fmt.Errorf("lock is already acquired on file %s", f.filePath)
+	}
+	if err != nil {
+		return fmt.Errorf("Error while trying to acquire lock on file %s: %s", f.filePath, err)
+	}
+	return nil
+}
+
+// Unlock releases the lock. Unlock can be invoked even if the lock is not held
+func (f *FileLock) Unlock() {
+	if f.db == nil {
+		return
+	}
+	if err := f.db.Close(); err != nil {
+		logger.Warningf("Error while releasing lock on file %s: %s", f.filePath, err)
+		return
+	}
+	f.db = nil
+}
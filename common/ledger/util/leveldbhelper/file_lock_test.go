@@ -0,0 +1,38 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package leveldbhelper
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+)
+
+func TestFileLock(t *testing.T) {
+	lockPath := filepath.Join(testDBPath, "fileLock")
+	defer os.RemoveAll(testDBPath)
+
+	fileLock := NewFileLock(lockPath)
+	testutil.AssertNoError(t, fileLock.Lock(), "")
+
+	// a second lock on the same directory cannot be acquired while the first one is held
+	anotherFileLock := NewFileLock(lockPath)
+	err := anotherFileLock.Lock()
+	testutil.AssertError(t, err, "")
+	testutil.AssertEquals(t, err.Error(), "lock is already acquired on file "+lockPath)
+
+	// after the first lock is released, the second lock can be acquired
+	fileLock.Unlock()
+	testutil.AssertNoError(t, anotherFileLock.Lock(), "")
+	anotherFileLock.Unlock()
+
+	// unlock is a no-op for a lock that is not held
+	fileLock.Unlock()
+	anotherFileLock.Unlock()
+}
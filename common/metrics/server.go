@@ -8,12 +8,14 @@ package metrics
 
 import (
 	"fmt"
+	"net/http"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/spf13/viper"
 	"github.com/uber-go/tally"
+	promreporter "github.com/uber-go/tally/prometheus"
 )
 
 const (
@@ -29,7 +31,9 @@ const (
 	defaultStatsdReporterFlushBytes    = 1432
 )
 
-var RootScope Scope
+// RootScope is the root metrics scope of the process. Until Init is
+// called it is a no-op scope, so instrumented code can always use it.
+var RootScope = newNoOpScope()
 var once sync.Once
 var started uint32
 
@@ -90,11 +94,30 @@ func Start() error {
 	return nil
 }
 
+// PrometheusHandler returns the http.Handler that exposes the metrics of the
+// root scope in the Prometheus text format, or nil if the root scope doesn't
+// report to Prometheus
+func PrometheusHandler() http.Handler {
+	return prometheusHandler(RootScope)
+}
+
+func prometheusHandler(rootScope Scope) http.Handler {
+	s, ok := rootScope.(*scope)
+	if !ok {
+		return nil
+	}
+	r, ok := s.baseReporter.(*promReporter)
+	if !ok {
+		return nil
+	}
+	return r.HTTPHandler()
+}
+
 //Shutdown closes underlying resources used by metrics server
 func Shutdown() error {
 	if atomic.CompareAndSwapUint32(&started, 1, 0) {
 		err := RootScope.Close()
-		RootScope = nil
+		RootScope = newNoOpScope()
 		return err
 	}
 
@@ -133,9 +156,17 @@ func (g *noOpGauge) Update(v float64) {
 
 }
 
+type noOpTimer struct {
+}
+
+func (t *noOpTimer) Record(d time.Duration) {
+
+}
+
 type noOpScope struct {
 	counter *noOpCounter
 	gauge   *noOpGauge
+	timer   *noOpTimer
 }
 
 func (s *noOpScope) Counter(name string) Counter {
@@ -146,6 +177,10 @@ func (s *noOpScope) Gauge(name string) Gauge {
 	return s.gauge
 }
 
+func (s *noOpScope) Timer(name string) Timer {
+	return s.timer
+}
+
 func (s *noOpScope) Tagged(tags map[string]string) Scope {
 	return s
 }
@@ -166,6 +201,7 @@ func newNoOpScope() Scope {
 	return &noOpScope{
 		counter: &noOpCounter{},
 		gauge:   &noOpGauge{},
+		timer:   &noOpTimer{},
 	}
 }
 
@@ -186,12 +222,15 @@ func create(opts Opts) (rootScope Scope, e error) {
 
 		var reporter tally.StatsReporter
 		var cachedReporter tally.CachedStatsReporter
+		separator := tally.DefaultSeparator
 		if opts.Reporter == statsdReporterType {
 			reporter, e = newStatsdReporter(opts.StatsdReporterOpts)
 		}
 
 		if opts.Reporter == promReporterType {
 			cachedReporter, e = newPromReporter(opts.PromReporterOpts)
+			// prometheus doesn't allow dots in metric names
+			separator = promreporter.DefaultSeparator
 		}
 
 		if e != nil {
@@ -201,6 +240,7 @@ func create(opts Opts) (rootScope Scope, e error) {
 		rootScope = newRootScope(
 			tally.ScopeOptions{
 				Prefix:         namespace,
+				Separator:      separator,
 				Reporter:       reporter,
 				CachedReporter: cachedReporter,
 			}, opts.Interval)
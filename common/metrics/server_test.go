@@ -8,6 +8,7 @@ package metrics
 
 import (
 	"fmt"
+	"net/http/httptest"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -187,11 +188,56 @@ func TestNoOpScopeMetrics(t *testing.T) {
 	subScope := s.SubScope("test")
 	subScope.Counter("foo").Inc(2)
 	subScope.Gauge("bar").Update(1.33)
+	subScope.Timer("baz").Record(time.Second)
 	tagSubScope := subScope.Tagged(map[string]string{"env": "test"})
 	tagSubScope.Counter("foo").Inc(2)
 	tagSubScope.Gauge("bar").Update(1.33)
 }
 
+func TestPrometheusHandler(t *testing.T) {
+	t.Parallel()
+	s, err := create(Opts{Enabled: false})
+	assert.NoError(t, err)
+	assert.Nil(t, prometheusHandler(s))
+
+	s, err = create(Opts{
+		Enabled:  true,
+		Reporter: statsdReporterType,
+		Interval: 1 * time.Second,
+		StatsdReporterOpts: StatsdReporterOpts{
+			Address:       "127.0.0.1:8125",
+			FlushInterval: 2 * time.Second,
+			FlushBytes:    512,
+		}})
+	assert.NoError(t, err)
+	defer s.Close()
+	assert.Nil(t, prometheusHandler(s))
+
+	// no listen address, the metrics are only served by the handler
+	s, err = create(Opts{
+		Enabled:  true,
+		Reporter: promReporterType,
+		Interval: 100 * time.Millisecond,
+	})
+	assert.NoError(t, err)
+	defer s.Close()
+	assert.NoError(t, s.Start())
+	handler := prometheusHandler(s)
+	assert.NotNil(t, handler)
+
+	s.SubScope("endorser").Counter("proposals_received").Inc(3)
+	scrape := func() string {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest("GET", "/metrics", nil))
+		return resp.Body.String()
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for !strings.Contains(scrape(), "hyperledger_fabric_endorser_proposals_received 3") && time.Now().Before(deadline) {
+		time.Sleep(100 * time.Millisecond)
+	}
+	assert.Contains(t, scrape(), "hyperledger_fabric_endorser_proposals_received 3")
+}
+
 func TestNewOpts(t *testing.T) {
 	t.Parallel()
 	defer viper.Reset()
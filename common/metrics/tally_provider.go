@@ -53,6 +53,18 @@ func (g *gauge) Update(v float64) {
 	g.tallyGauge.Update(v)
 }
 
+type timer struct {
+	tallyTimer tally.Timer
+}
+
+func newTimer(tallyTimer tally.Timer) *timer {
+	return &timer{tallyTimer: tallyTimer}
+}
+
+func (t *timer) Record(d time.Duration) {
+	t.tallyTimer.Record(d)
+}
+
 type scopeRegistry struct {
 	sync.RWMutex
 	subScopes map[string]*scope
@@ -68,9 +80,11 @@ type scope struct {
 
 	cm sync.RWMutex
 	gm sync.RWMutex
+	tm sync.RWMutex
 
 	counters map[string]*counter
 	gauges   map[string]*gauge
+	timers   map[string]*timer
 }
 
 func newRootScope(opts tally.ScopeOptions, interval time.Duration) Scope {
@@ -92,7 +106,8 @@ func newRootScope(opts tally.ScopeOptions, interval time.Duration) Scope {
 		},
 		baseReporter: baseReporter,
 		counters:     make(map[string]*counter),
-		gauges:       make(map[string]*gauge)}
+		gauges:       make(map[string]*gauge),
+		timers:       make(map[string]*timer)}
 }
 
 func newStatsdReporter(statsdReporterOpts StatsdReporterOpts) (tally.StatsReporter, error) {
@@ -120,20 +135,18 @@ func newStatsdReporter(statsdReporterOpts StatsdReporterOpts) (tally.StatsReport
 }
 
 func newPromReporter(promReporterOpts PromReporterOpts) (promreporter.Reporter, error) {
-	if promReporterOpts.ListenAddress == "" {
-		return nil, errors.New("missing prometheus listenAddress option")
-	}
-
 	opts := promreporter.Options{Registerer: prometheus.NewRegistry()}
 	reporter := promreporter.NewReporter(opts)
-	mux := http.NewServeMux()
-	handler := promReporterHttpHandler(opts.Registerer.(*prometheus.Registry))
-	mux.Handle("/metrics", handler)
-	server := &http.Server{Addr: promReporterOpts.ListenAddress, Handler: mux}
 	promReporter := &promReporter{
 		reporter: reporter,
-		server:   server,
 		registry: opts.Registerer.(*prometheus.Registry)}
+	// Without a listen address the metrics are only exposed
+	// through the handler returned by PrometheusHandler
+	if promReporterOpts.ListenAddress != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/metrics", promReporter.HTTPHandler())
+		promReporter.server = &http.Server{Addr: promReporterOpts.ListenAddress, Handler: mux}
+	}
 	return promReporter, nil
 }
 
@@ -171,6 +184,23 @@ func (s *scope) Gauge(name string) Gauge {
 	return val
 }
 
+func (s *scope) Timer(name string) Timer {
+	s.tm.RLock()
+	val, ok := s.timers[name]
+	s.tm.RUnlock()
+	if !ok {
+		s.tm.Lock()
+		val, ok = s.timers[name]
+		if !ok {
+			timer := s.tallyScope.Timer(name)
+			val = newTimer(timer)
+			s.timers[name] = val
+		}
+		s.tm.Unlock()
+	}
+	return val
+}
+
 func (s *scope) Tagged(tags map[string]string) Scope {
 	originTags := tags
 	tags = mergeRightTags(s.tags, tags)
@@ -203,6 +233,7 @@ func (s *scope) Tagged(tags map[string]string) Scope {
 
 		counters: make(map[string]*counter),
 		gauges:   make(map[string]*gauge),
+		timers:   make(map[string]*timer),
 	}
 
 	s.registry.subScopes[key] = subScope
@@ -239,6 +270,7 @@ func (s *scope) SubScope(prefix string) Scope {
 
 		counters: make(map[string]*counter),
 		gauges:   make(map[string]*gauge),
+		timers:   make(map[string]*timer),
 	}
 
 	s.registry.subScopes[key] = subScope
@@ -390,11 +422,17 @@ func (r *promReporter) Flush() {
 }
 
 func (r *promReporter) Close() error {
+	if r.server == nil {
+		return nil
+	}
 	//TODO: Timeout here?
 	return r.server.Shutdown(context.Background())
 }
 
 func (r *promReporter) Start() error {
+	if r.server == nil {
+		return nil
+	}
 	return r.server.ListenAndServe()
 }
 
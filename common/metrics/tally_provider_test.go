@@ -53,11 +53,13 @@ func (m *testFloatValue) ReportGauge(value float64) {
 type testStatsReporter struct {
 	cg sync.WaitGroup
 	gg sync.WaitGroup
+	tg sync.WaitGroup
 
 	scope Scope
 
 	counters map[string]*testIntValue
 	gauges   map[string]*testFloatValue
+	timers   map[string]time.Duration
 
 	flushes int32
 }
@@ -66,7 +68,8 @@ type testStatsReporter struct {
 func newTestStatsReporter() *testStatsReporter {
 	return &testStatsReporter{
 		counters: make(map[string]*testIntValue),
-		gauges:   make(map[string]*testFloatValue)}
+		gauges:   make(map[string]*testFloatValue),
+		timers:   make(map[string]time.Duration)}
 }
 
 func (r *testStatsReporter) WaitAll() {
@@ -121,7 +124,8 @@ func (r *testStatsReporter) AllocateTimer(
 }
 
 func (r *testStatsReporter) ReportTimer(name string, tags map[string]string, interval time.Duration) {
-
+	r.timers[name] = interval
+	r.tg.Done()
 }
 
 func (r *testStatsReporter) AllocateHistogram(
@@ -245,6 +249,24 @@ func TestMultiGaugeReport(t *testing.T) {
 	assert.Equal(t, float64(3.33), r.gauges[namespace+".foo"].val)
 }
 
+func TestTimer(t *testing.T) {
+	t.Parallel()
+	r := newTestStatsReporter()
+	opts := tally.ScopeOptions{
+		Prefix:    namespace,
+		Separator: tally.DefaultSeparator,
+		Reporter:  r}
+
+	s := newRootScope(opts, 1*time.Second)
+	go s.Start()
+	defer s.Close()
+	r.tg.Add(1)
+	s.SubScope("peer").Timer("foo").Record(2 * time.Second)
+	r.tg.Wait()
+
+	assert.Equal(t, 2*time.Second, r.timers[namespace+".peer.foo"])
+}
+
 func TestSubScope(t *testing.T) {
 	t.Parallel()
 	r := newTestStatsReporter()
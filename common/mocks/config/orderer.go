@@ -17,6 +17,10 @@ import (
 type Orderer struct {
 	// ConsensusTypeVal is returned as the result of ConsensusType()
 	ConsensusTypeVal string
+	// ConsensusMetadataVal is returned as the result of ConsensusMetadata()
+	ConsensusMetadataVal []byte
+	// ConsensusStateVal is returned as the result of ConsensusState()
+	ConsensusStateVal ab.ConsensusType_State
 	// BatchSizeVal is returned as the result of BatchSize()
 	BatchSizeVal *ab.BatchSize
 	// BatchTimeoutVal is returned as the result of BatchTimeout()
@@ -25,6 +29,8 @@ type Orderer struct {
 	KafkaBrokersVal []string
 	// MaxChannelsCountVal is returns as the result of MaxChannelsCount()
 	MaxChannelsCountVal uint64
+	// BlockSchedulingVal is returned as the result of BlockScheduling()
+	BlockSchedulingVal *ab.BlockScheduling
 	// OrganizationsVal is returned as the result of Organizations()
 	OrganizationsVal map[string]channelconfig.Org
 	// CapabilitiesVal is returned as the result of Capabilities()
@@ -36,6 +42,16 @@ func (scm *Orderer) ConsensusType() string {
 	return scm.ConsensusTypeVal
 }
 
+// ConsensusMetadata returns the ConsensusMetadataVal
+func (scm *Orderer) ConsensusMetadata() []byte {
+	return scm.ConsensusMetadataVal
+}
+
+// ConsensusState returns the ConsensusStateVal
+func (scm *Orderer) ConsensusState() ab.ConsensusType_State {
+	return scm.ConsensusStateVal
+}
+
 // BatchSize returns the BatchSizeVal
 func (scm *Orderer) BatchSize() *ab.BatchSize {
 	return scm.BatchSizeVal
@@ -56,6 +72,11 @@ func (scm *Orderer) MaxChannelsCount() uint64 {
 	return scm.MaxChannelsCountVal
 }
 
+// BlockScheduling returns the BlockSchedulingVal
+func (scm *Orderer) BlockScheduling() *ab.BlockScheduling {
+	return scm.BlockSchedulingVal
+}
+
 // Organizations returns OrganizationsVal
 func (scm *Orderer) Organizations() map[string]channelconfig.Org {
 	return scm.OrganizationsVal
@@ -52,10 +52,18 @@ func (m *MockQueryExecutor) GetStateRangeScanIterator(namespace string, startKey
 
 }
 
+func (m *MockQueryExecutor) GetStateRangeScanIteratorWithMetadata(namespace, startKey, endKey string, metadata map[string]interface{}) (ledger.QueryResultsIterator, error) {
+	return nil, nil
+}
+
 func (m *MockQueryExecutor) ExecuteQuery(namespace, query string) (ledger.ResultsIterator, error) {
 	return nil, nil
 }
 
+func (m *MockQueryExecutor) ExecuteQueryWithMetadata(namespace, query string, metadata map[string]interface{}) (ledger.QueryResultsIterator, error) {
+	return nil, nil
+}
+
 func (m *MockQueryExecutor) GetPrivateData(namespace, collection, key string) ([]byte, error) {
 	return nil, nil
 }
@@ -72,5 +80,17 @@ func (m *MockQueryExecutor) ExecuteQueryOnPrivateData(namespace, collection, que
 	return nil, nil
 }
 
+func (m *MockQueryExecutor) GetStateMetadata(namespace, key string) (map[string][]byte, error) {
+	return nil, nil
+}
+
+func (m *MockQueryExecutor) GetPrivateDataMetadata(namespace, collection, key string) (map[string][]byte, error) {
+	return nil, nil
+}
+
+func (m *MockQueryExecutor) GetPrivateDataMetadataByHash(namespace, collection string, keyhash []byte) (map[string][]byte, error) {
+	return nil, nil
+}
+
 func (m *MockQueryExecutor) Done() {
 }
@@ -59,11 +59,11 @@ func (c *MocksccProviderImpl) IsSysCC(name string) bool {
 		return c.SysCCMap[name]
 	}
 
-	return (name == "lscc") || (name == "escc") || (name == "vscc") || (name == "notext")
+	return (name == "lscc") || (name == "escc") || (name == "vscc") || (name == "notext") || (name == "_lifecycle")
 }
 
 func (c *MocksccProviderImpl) IsSysCCAndNotInvokableCC2CC(name string) bool {
-	return (name == "escc") || (name == "vscc")
+	return (name == "escc") || (name == "vscc") || (name == "_lifecycle")
 }
 
 func (c *MocksccProviderImpl) IsSysCCAndNotInvokableExternal(name string) bool {
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package encoder
 
 import (
+	"io/ioutil"
+
 	"github.com/hyperledger/fabric/common/cauthdsl"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/crypto"
@@ -19,6 +21,7 @@ import (
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/msp"
 	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 
@@ -45,6 +48,8 @@ const (
 	ConsensusTypeSolo = "solo"
 	// ConsensusTypeKafka identifies the Kafka-based consensus implementation.
 	ConsensusTypeKafka = "kafka"
+	// ConsensusTypeRaft identifies the Raft-based consensus implementation.
+	ConsensusTypeRaft = "raft"
 
 	// BlockValidationPolicyKey TODO
 	BlockValidationPolicyKey = "BlockValidation"
@@ -145,7 +150,14 @@ func NewOrdererGroup(conf *genesisconfig.Orderer) (*cb.ConfigGroup, error) {
 		Policy:    policies.ImplicitMetaAnyPolicy(channelconfig.WritersPolicyKey).Value(),
 		ModPolicy: channelconfig.AdminsPolicyKey,
 	}
-	addValue(ordererGroup, channelconfig.ConsensusTypeValue(conf.OrdererType), channelconfig.AdminsPolicyKey)
+	var consensusMetadata []byte
+	if conf.OrdererType == ConsensusTypeRaft {
+		var err error
+		if consensusMetadata, err = raftConfigMetadata(&conf.Raft); err != nil {
+			return nil, errors.Wrap(err, "cannot marshal raft metadata")
+		}
+	}
+	addValue(ordererGroup, channelconfig.ConsensusTypeValue(conf.OrdererType, consensusMetadata), channelconfig.AdminsPolicyKey)
 	addValue(ordererGroup, channelconfig.BatchSizeValue(
 		conf.BatchSize.MaxMessageCount,
 		conf.BatchSize.AbsoluteMaxBytes,
@@ -162,6 +174,7 @@ func NewOrdererGroup(conf *genesisconfig.Orderer) (*cb.ConfigGroup, error) {
 	case ConsensusTypeSolo:
 	case ConsensusTypeKafka:
 		addValue(ordererGroup, channelconfig.KafkaBrokersValue(conf.Kafka.Brokers), channelconfig.AdminsPolicyKey)
+	case ConsensusTypeRaft:
 	default:
 		return nil, errors.Errorf("unknown orderer type: %s", conf.OrdererType)
 	}
@@ -178,6 +191,40 @@ func NewOrdererGroup(conf *genesisconfig.Orderer) (*cb.ConfigGroup, error) {
 	return ordererGroup, nil
 }
 
+// raftConfigMetadata encodes the consenters and options of a Raft-based ordering service,
+// reading the TLS certificates of the consenters from the referenced files.
+func raftConfigMetadata(conf *genesisconfig.Raft) ([]byte, error) {
+	if len(conf.Consenters) == 0 {
+		return nil, errors.New("no consenters are defined")
+	}
+	metadata := &ab.RaftConfigMetadata{
+		Options: &ab.RaftOptions{
+			TickInterval:           conf.Options.TickInterval,
+			ElectionTick:           conf.Options.ElectionTick,
+			HeartbeatTick:          conf.Options.HeartbeatTick,
+			MaxEntriesPerMsg:       conf.Options.MaxEntriesPerMsg,
+			SnapshotIntervalBlocks: conf.Options.SnapshotIntervalBlocks,
+		},
+	}
+	for _, c := range conf.Consenters {
+		clientCert, err := ioutil.ReadFile(c.ClientTLSCert)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot load client cert for consenter %s:%d", c.Host, c.Port)
+		}
+		serverCert, err := ioutil.ReadFile(c.ServerTLSCert)
+		if err != nil {
+			return nil, errors.Wrapf(err, "cannot load server cert for consenter %s:%d", c.Host, c.Port)
+		}
+		metadata.Consenters = append(metadata.Consenters, &ab.RaftConsenter{
+			Host:          c.Host,
+			Port:          c.Port,
+			ClientTlsCert: clientCert,
+			ServerTlsCert: serverCert,
+		})
+	}
+	return proto.Marshal(metadata)
+}
+
 // NewOrdererOrgGroup returns an orderer org component of the channel configuration.  It defines the crypto material for the
 // organization (its MSP).  It sets the mod_policy of all elements to "Admins".
 func NewOrdererOrgGroup(conf *genesisconfig.Organization) (*cb.ConfigGroup, error) {
@@ -7,6 +7,9 @@ SPDX-License-Identifier: Apache-2.0
 package encoder
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/hyperledger/fabric/common/capabilities"
@@ -17,6 +20,7 @@ import (
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
 	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 
@@ -264,6 +268,44 @@ func TestNewOrdererGroup(t *testing.T) {
 		assert.Error(t, err)
 		assert.Nil(t, group)
 	})
+
+	t.Run("Raft without consenters", func(t *testing.T) {
+		config := genesisconfig.Load(genesisconfig.SampleDevModeSoloProfile)
+		config.Orderer.OrdererType = ConsensusTypeRaft
+		config.Orderer.Raft.Consenters = nil
+		group, err := NewOrdererGroup(config.Orderer)
+		assert.EqualError(t, err, "cannot marshal raft metadata: no consenters are defined")
+		assert.Nil(t, group)
+	})
+
+	t.Run("Raft", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "encoder")
+		assert.NoError(t, err)
+		defer os.RemoveAll(dir)
+		certFile := filepath.Join(dir, "cert.pem")
+		assert.NoError(t, ioutil.WriteFile(certFile, []byte("cert"), 0644))
+
+		config := genesisconfig.Load(genesisconfig.SampleDevModeSoloProfile)
+		config.Orderer.OrdererType = ConsensusTypeRaft
+		config.Orderer.Raft.Consenters = []*genesisconfig.RaftConsenter{
+			{Host: "raft0.example.com", Port: 7050, ClientTLSCert: certFile, ServerTLSCert: certFile},
+		}
+		group, err := NewOrdererGroup(config.Orderer)
+		assert.NoError(t, err)
+
+		consensusType := &ab.ConsensusType{}
+		assert.NoError(t, proto.Unmarshal(group.Values[channelconfig.ConsensusTypeKey].Value, consensusType))
+		assert.Equal(t, ConsensusTypeRaft, consensusType.Type)
+		metadata := &ab.RaftConfigMetadata{}
+		assert.NoError(t, proto.Unmarshal(consensusType.Metadata, metadata))
+		assert.Len(t, metadata.Consenters, 1)
+		assert.Equal(t, []byte("cert"), metadata.Consenters[0].ServerTlsCert)
+		assert.Equal(t, "500ms", metadata.Options.TickInterval)
+
+		config.Orderer.Raft.Consenters[0].ClientTLSCert = filepath.Join(dir, "missing.pem")
+		_, err = NewOrdererGroup(config.Orderer)
+		assert.Error(t, err)
+	})
 }
 
 func TestBootstrapper(t *testing.T) {
@@ -151,6 +151,7 @@ type Orderer struct {
 	BatchTimeout  time.Duration   `yaml:"BatchTimeout"`
 	BatchSize     BatchSize       `yaml:"BatchSize"`
 	Kafka         Kafka           `yaml:"Kafka"`
+	Raft          Raft            `yaml:"Raft"`
 	Organizations []*Organization `yaml:"Organizations"`
 	MaxChannels   uint64          `yaml:"MaxChannels"`
 	Capabilities  map[string]bool `yaml:"Capabilities"`
@@ -168,6 +169,29 @@ type Kafka struct {
 	Brokers []string `yaml:"Brokers"`
 }
 
+// Raft contains configuration for the Raft-based orderer.
+type Raft struct {
+	Consenters []*RaftConsenter `yaml:"Consenters"`
+	Options    RaftOptions      `yaml:"Options"`
+}
+
+// RaftConsenter identifies a member of the Raft cluster of a channel.
+type RaftConsenter struct {
+	Host          string `yaml:"Host"`
+	Port          uint32 `yaml:"Port"`
+	ClientTLSCert string `yaml:"ClientTLSCert"`
+	ServerTLSCert string `yaml:"ServerTLSCert"`
+}
+
+// RaftOptions contains the tunable parameters of the Raft-based orderer.
+type RaftOptions struct {
+	TickInterval           string `yaml:"TickInterval"`
+	ElectionTick           uint32 `yaml:"ElectionTick"`
+	HeartbeatTick          uint32 `yaml:"HeartbeatTick"`
+	MaxEntriesPerMsg       uint32 `yaml:"MaxEntriesPerMsg"`
+	SnapshotIntervalBlocks uint32 `yaml:"SnapshotIntervalBlocks"`
+}
+
 var genesisDefaults = TopLevel{
 	Orderer: &Orderer{
 		OrdererType:  "solo",
@@ -181,6 +205,15 @@ var genesisDefaults = TopLevel{
 		Kafka: Kafka{
 			Brokers: []string{"127.0.0.1:9092"},
 		},
+		Raft: Raft{
+			Options: RaftOptions{
+				TickInterval:           "500ms",
+				ElectionTick:           10,
+				HeartbeatTick:          1,
+				MaxEntriesPerMsg:       10,
+				SnapshotIntervalBlocks: 100,
+			},
+		},
 	},
 }
 
@@ -261,7 +294,7 @@ func (t *TopLevel) completeInitialization(configDir string) {
 	}
 
 	if t.Orderer != nil {
-		t.Orderer.completeInitialization()
+		t.Orderer.completeInitialization(configDir)
 	}
 }
 
@@ -291,7 +324,7 @@ func (p *Profile) completeInitialization(configDir string) {
 
 	// Some profiles will not define orderer parameters
 	if p.Orderer != nil {
-		p.Orderer.completeInitialization()
+		p.Orderer.completeInitialization(configDir)
 	}
 }
 
@@ -318,7 +351,14 @@ func (org *Organization) completeInitialization(configDir string) {
 	translatePaths(configDir, org)
 }
 
-func (oc *Orderer) completeInitialization() {
+func (oc *Orderer) completeInitialization(configDir string) {
+	defer func() {
+		for _, c := range oc.Raft.Consenters {
+			cf.TranslatePathInPlace(configDir, &c.ClientTLSCert)
+			cf.TranslatePathInPlace(configDir, &c.ServerTLSCert)
+		}
+	}()
+
 	for {
 		switch {
 		case oc.OrdererType == "":
@@ -342,6 +382,18 @@ func (oc *Orderer) completeInitialization() {
 		case oc.Kafka.Brokers == nil:
 			logger.Infof("Orderer.Kafka.Brokers unset, setting to %v", genesisDefaults.Orderer.Kafka.Brokers)
 			oc.Kafka.Brokers = genesisDefaults.Orderer.Kafka.Brokers
+		case oc.Raft.Options.TickInterval == "":
+			logger.Infof("Orderer.Raft.Options.TickInterval unset, setting to %s", genesisDefaults.Orderer.Raft.Options.TickInterval)
+			oc.Raft.Options.TickInterval = genesisDefaults.Orderer.Raft.Options.TickInterval
+		case oc.Raft.Options.ElectionTick == 0:
+			logger.Infof("Orderer.Raft.Options.ElectionTick unset, setting to %d", genesisDefaults.Orderer.Raft.Options.ElectionTick)
+			oc.Raft.Options.ElectionTick = genesisDefaults.Orderer.Raft.Options.ElectionTick
+		case oc.Raft.Options.HeartbeatTick == 0:
+			logger.Infof("Orderer.Raft.Options.HeartbeatTick unset, setting to %d", genesisDefaults.Orderer.Raft.Options.HeartbeatTick)
+			oc.Raft.Options.HeartbeatTick = genesisDefaults.Orderer.Raft.Options.HeartbeatTick
+		case oc.Raft.Options.MaxEntriesPerMsg == 0:
+			logger.Infof("Orderer.Raft.Options.MaxEntriesPerMsg unset, setting to %d", genesisDefaults.Orderer.Raft.Options.MaxEntriesPerMsg)
+			oc.Raft.Options.MaxEntriesPerMsg = genesisDefaults.Orderer.Raft.Options.MaxEntriesPerMsg
 		default:
 			return
 		}
@@ -0,0 +1,366 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package edit
+
+import (
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/resourcesconfig"
+	"github.com/hyperledger/fabric/common/tools/configtxlator/update"
+	"github.com/hyperledger/fabric/common/tools/protolator"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+const (
+	// ChannelCapabilities identifies the capabilities of the /Channel group
+	ChannelCapabilities = "channel"
+	// OrdererCapabilities identifies the capabilities of the /Channel/Orderer group
+	OrdererCapabilities = "orderer"
+	// ApplicationCapabilities identifies the capabilities of the /Channel/Application group
+	ApplicationCapabilities = "application"
+
+	// ordererAdminsPolicy is the mod policy of a newly set OrdererAddresses value
+	ordererAdminsPolicy = "/" + channelconfig.ChannelGroupKey + "/" + channelconfig.OrdererGroupKey + "/" + channelconfig.AdminsPolicyKey
+)
+
+// Edit modifies a config in place
+type Edit func(config *cb.Config) error
+
+// Envelope applies the edits to a copy of the original config and returns an
+// unsigned envelope carrying the config update which transitions the channel
+// from the original config to the edited one.  Edits of a resources config
+// produce a PEER_RESOURCE_UPDATE envelope, all others a CONFIG_UPDATE one.
+func Envelope(channelID string, original *cb.Config, edits ...Edit) (*cb.Envelope, error) {
+	if channelID == "" {
+		return nil, errors.New("missing channel ID")
+	}
+
+	updated := proto.Clone(original).(*cb.Config)
+	for _, edit := range edits {
+		if err := edit(updated); err != nil {
+			return nil, err
+		}
+	}
+
+	configUpdate, err := update.Compute(original, updated)
+	if err != nil {
+		return nil, errors.Wrap(err, "error computing config update")
+	}
+	configUpdate.ChannelId = channelID
+
+	headerType := cb.HeaderType_CONFIG_UPDATE
+	if original.Type == int32(cb.ConfigType_RESOURCE) {
+		headerType = cb.HeaderType_PEER_RESOURCE_UPDATE
+	}
+
+	return utils.CreateSignedEnvelope(headerType, channelID, nil, &cb.ConfigUpdateEnvelope{
+		ConfigUpdate: utils.MarshalOrPanic(configUpdate),
+	}, 0, 0)
+}
+
+// ConfigFromBlock returns the channel ID and the config carried by a config block
+func ConfigFromBlock(block *cb.Block) (string, *cb.Config, error) {
+	envelope, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "error extracting envelope from block")
+	}
+
+	payload, err := utils.ExtractPayload(envelope)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "error extracting payload from envelope")
+	}
+	if payload.Header == nil {
+		return "", nil, errors.New("missing payload header")
+	}
+
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "error unmarshaling channel header")
+	}
+	if chdr.Type != int32(cb.HeaderType_CONFIG) {
+		return "", nil, errors.Errorf("block is not a config block, it holds a transaction of type %s", cb.HeaderType(chdr.Type))
+	}
+
+	configEnvelope, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+	if err != nil {
+		return "", nil, errors.Wrap(err, "error unmarshaling config envelope")
+	}
+	if configEnvelope.Config == nil {
+		return "", nil, errors.New("config envelope carries no config")
+	}
+
+	return chdr.ChannelId, configEnvelope.Config, nil
+}
+
+// OrgGroupFromJSON reads the definition of an org encoded as JSON, as printed
+// by 'configtxgen -printOrg'
+func OrgGroupFromJSON(r io.Reader) (*cb.ConfigGroup, error) {
+	org := &pb.DynamicApplicationOrgGroup{ConfigGroup: &cb.ConfigGroup{}}
+	if err := protolator.DeepUnmarshalJSON(r, org); err != nil {
+		return nil, errors.Wrap(err, "error decoding org definition")
+	}
+	return org.ConfigGroup, nil
+}
+
+// AddApplicationOrg adds the definition of an org to the application group
+func AddApplicationOrg(orgName string, org *cb.ConfigGroup) Edit {
+	return func(config *cb.Config) error {
+		application, err := subGroup(config, channelconfig.ApplicationGroupKey)
+		if err != nil {
+			return err
+		}
+		if _, ok := application.Groups[orgName]; ok {
+			return errors.Errorf("application org %s already exists", orgName)
+		}
+		if application.Groups == nil {
+			application.Groups = map[string]*cb.ConfigGroup{}
+		}
+		application.Groups[orgName] = org
+		return nil
+	}
+}
+
+// RemoveApplicationOrg removes the definition of an org from the application group
+func RemoveApplicationOrg(orgName string) Edit {
+	return func(config *cb.Config) error {
+		application, err := subGroup(config, channelconfig.ApplicationGroupKey)
+		if err != nil {
+			return err
+		}
+		if _, ok := application.Groups[orgName]; !ok {
+			return errors.Errorf("application org %s does not exist", orgName)
+		}
+		delete(application.Groups, orgName)
+		return nil
+	}
+}
+
+// SetAnchorPeers replaces the anchor peers of an application org
+func SetAnchorPeers(orgName string, anchorPeers []*pb.AnchorPeer) Edit {
+	return func(config *cb.Config) error {
+		org, err := subGroup(config, channelconfig.ApplicationGroupKey, orgName)
+		if err != nil {
+			return err
+		}
+		setValue(org, channelconfig.AnchorPeersValue(anchorPeers), channelconfig.AdminsPolicyKey)
+		return nil
+	}
+}
+
+// SetOrdererAddresses replaces the addresses of the ordering service nodes
+func SetOrdererAddresses(addresses []string) Edit {
+	return func(config *cb.Config) error {
+		if len(addresses) == 0 {
+			return errors.New("at least one orderer address is required")
+		}
+		for _, address := range addresses {
+			if _, _, err := net.SplitHostPort(address); err != nil {
+				return errors.Wrapf(err, "invalid orderer address %s", address)
+			}
+		}
+		channel, err := subGroup(config)
+		if err != nil {
+			return err
+		}
+		setValue(channel, channelconfig.OrdererAddressesValue(addresses), ordererAdminsPolicy)
+		return nil
+	}
+}
+
+// SetBatchSize changes the batch size of the ordering service.  Zero
+// arguments leave the corresponding current setting unchanged.
+func SetBatchSize(maxMessageCount, absoluteMaxBytes, preferredMaxBytes uint32) Edit {
+	return func(config *cb.Config) error {
+		orderer, err := subGroup(config, channelconfig.OrdererGroupKey)
+		if err != nil {
+			return err
+		}
+
+		batchSize := &ab.BatchSize{}
+		if value, ok := orderer.Values[channelconfig.BatchSizeKey]; ok {
+			if err := proto.Unmarshal(value.Value, batchSize); err != nil {
+				return errors.Wrap(err, "error unmarshaling current batch size")
+			}
+		}
+		if maxMessageCount != 0 {
+			batchSize.MaxMessageCount = maxMessageCount
+		}
+		if absoluteMaxBytes != 0 {
+			batchSize.AbsoluteMaxBytes = absoluteMaxBytes
+		}
+		if preferredMaxBytes != 0 {
+			batchSize.PreferredMaxBytes = preferredMaxBytes
+		}
+
+		if batchSize.MaxMessageCount == 0 {
+			return errors.New("max message count must be greater than zero")
+		}
+		if batchSize.AbsoluteMaxBytes == 0 {
+			return errors.New("absolute max bytes must be greater than zero")
+		}
+		if batchSize.PreferredMaxBytes > batchSize.AbsoluteMaxBytes {
+			return errors.Errorf("preferred max bytes (%d) must not exceed absolute max bytes (%d)",
+				batchSize.PreferredMaxBytes, batchSize.AbsoluteMaxBytes)
+		}
+
+		setValue(orderer, channelconfig.BatchSizeValue(batchSize.MaxMessageCount,
+			batchSize.AbsoluteMaxBytes, batchSize.PreferredMaxBytes), channelconfig.AdminsPolicyKey)
+		return nil
+	}
+}
+
+// SetBatchTimeout changes the batch timeout of the ordering service
+func SetBatchTimeout(timeout string) Edit {
+	return func(config *cb.Config) error {
+		duration, err := time.ParseDuration(timeout)
+		if err != nil {
+			return errors.Wrapf(err, "invalid batch timeout %s", timeout)
+		}
+		if duration <= 0 {
+			return errors.Errorf("batch timeout must be greater than zero, got %s", timeout)
+		}
+		orderer, err := subGroup(config, channelconfig.OrdererGroupKey)
+		if err != nil {
+			return err
+		}
+		setValue(orderer, channelconfig.BatchTimeoutValue(timeout), channelconfig.AdminsPolicyKey)
+		return nil
+	}
+}
+
+// SetCapabilities replaces the capabilities of the channel, orderer or
+// application group, as identified by ChannelCapabilities,
+// OrdererCapabilities or ApplicationCapabilities
+func SetCapabilities(groupName string, capabilities []string) Edit {
+	return func(config *cb.Config) error {
+		var path []string
+		switch groupName {
+		case ChannelCapabilities:
+		case OrdererCapabilities:
+			path = []string{channelconfig.OrdererGroupKey}
+		case ApplicationCapabilities:
+			path = []string{channelconfig.ApplicationGroupKey}
+		default:
+			return errors.Errorf("unknown capabilities group %s, expected one of %s, %s or %s",
+				groupName, ChannelCapabilities, OrdererCapabilities, ApplicationCapabilities)
+		}
+
+		group, err := subGroup(config, path...)
+		if err != nil {
+			return err
+		}
+
+		required := make(map[string]bool)
+		for _, capability := range capabilities {
+			required[capability] = true
+		}
+		setValue(group, channelconfig.CapabilitiesValue(required), channelconfig.AdminsPolicyKey)
+		return nil
+	}
+}
+
+// SetACLs sets the policies referenced by the ACLs of the given peer
+// resources (for example "qscc/GetChainInfo" mapped to
+// "/Channel/Application/Readers").  It applies to the resources config of a
+// channel rather than to its channel config.
+func SetACLs(acls map[string]string) Edit {
+	return func(config *cb.Config) error {
+		if config.Type != int32(cb.ConfigType_RESOURCE) {
+			return errors.New("ACLs can only be set in a resources config")
+		}
+		apis, err := subGroup(config, resourcesconfig.APIsGroupKey)
+		if err != nil {
+			return err
+		}
+		if apis.Values == nil {
+			apis.Values = map[string]*cb.ConfigValue{}
+		}
+
+		for resource, policyRef := range acls {
+			if resource == "" || policyRef == "" {
+				return errors.Errorf("invalid ACL %q: %q", resource, policyRef)
+			}
+			value := &cb.ConfigValue{
+				Value:     utils.MarshalOrPanic(&pb.APIResource{PolicyRef: policyRef}),
+				ModPolicy: apis.ModPolicy,
+			}
+			if existing, ok := apis.Values[resource]; ok {
+				value.Version = existing.Version
+				value.ModPolicy = existing.ModPolicy
+			}
+			apis.Values[resource] = value
+		}
+		return nil
+	}
+}
+
+// ParseAnchorPeer parses an anchor peer given as host:port
+func ParseAnchorPeer(anchorPeer string) (*pb.AnchorPeer, error) {
+	host, portStr, err := net.SplitHostPort(anchorPeer)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid anchor peer %s", anchorPeer)
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid port in anchor peer %s", anchorPeer)
+	}
+	return &pb.AnchorPeer{Host: host, Port: int32(port)}, nil
+}
+
+// ParseACL parses an ACL given as resource=policy
+func ParseACL(acl string) (string, string, error) {
+	parts := strings.SplitN(acl, "=", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.Errorf("invalid ACL %s, expected resource=policy", acl)
+	}
+	return parts[0], parts[1], nil
+}
+
+// subGroup returns the group at the given path below the root group of config
+func subGroup(config *cb.Config, path ...string) (*cb.ConfigGroup, error) {
+	group := config.ChannelGroup
+	if group == nil {
+		return nil, errors.New("config has no root group")
+	}
+	for i, key := range path {
+		next, ok := group.Groups[key]
+		if !ok {
+			return nil, errors.Errorf("config has no group %s", strings.Join(path[:i+1], "/"))
+		}
+		group = next
+	}
+	if group.Values == nil {
+		group.Values = map[string]*cb.ConfigValue{}
+	}
+	return group, nil
+}
+
+// setValue sets a value of group, keeping the mod policy and version of the
+// value it replaces.  A new value gets the default mod policy.
+func setValue(group *cb.ConfigGroup, value channelconfig.ConfigValue, defaultModPolicy string) {
+	configValue := &cb.ConfigValue{
+		Value:     utils.MarshalOrPanic(value.Value()),
+		ModPolicy: defaultModPolicy,
+	}
+	if existing, ok := group.Values[value.Key()]; ok {
+		configValue.Version = existing.Version
+		configValue.ModPolicy = existing.ModPolicy
+	}
+	group.Values[value.Key()] = configValue
+}
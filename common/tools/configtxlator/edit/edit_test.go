@@ -0,0 +1,321 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package edit
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/resourcesconfig"
+	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
+	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	"github.com/hyperledger/fabric/common/tools/protolator"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testChannelID = "testchannel"
+
+var testBlock *cb.Block
+
+func init() {
+	factory.InitFactories(nil)
+
+	testBlock = encoder.New(genesisconfig.Load(genesisconfig.SampleDevModeSoloProfile)).GenesisBlockForChannel(testChannelID)
+}
+
+func testConfig(t *testing.T) *cb.Config {
+	_, config, err := ConfigFromBlock(testBlock)
+	require.NoError(t, err)
+	return config
+}
+
+// configUpdate applies the edit and returns the resulting config update
+func configUpdate(t *testing.T, config *cb.Config, e Edit) *cb.ConfigUpdate {
+	env, err := Envelope(testChannelID, config, e)
+	require.NoError(t, err)
+
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	require.NoError(t, err)
+	configUpdateEnv, err := configtx.UnmarshalConfigUpdateEnvelope(payload.Data)
+	require.NoError(t, err)
+	assert.Empty(t, configUpdateEnv.Signatures)
+	configUpdate, err := configtx.UnmarshalConfigUpdate(configUpdateEnv.ConfigUpdate)
+	require.NoError(t, err)
+	assert.Equal(t, testChannelID, configUpdate.ChannelId)
+	return configUpdate
+}
+
+func TestConfigFromBlock(t *testing.T) {
+	channelID, config, err := ConfigFromBlock(testBlock)
+	assert.NoError(t, err)
+	assert.Equal(t, testChannelID, channelID)
+	assert.Contains(t, config.ChannelGroup.Groups, channelconfig.OrdererGroupKey)
+	assert.Contains(t, config.ChannelGroup.Groups, channelconfig.ApplicationGroupKey)
+
+	_, _, err = ConfigFromBlock(&cb.Block{Data: &cb.BlockData{}})
+	assert.Error(t, err)
+
+	env, err := utils.CreateSignedEnvelope(cb.HeaderType_ENDORSER_TRANSACTION, testChannelID, nil, &cb.ConfigEnvelope{}, 0, 0)
+	require.NoError(t, err)
+	block := cb.NewBlock(1, nil)
+	block.Data.Data = [][]byte{utils.MarshalOrPanic(env)}
+	_, _, err = ConfigFromBlock(block)
+	assert.EqualError(t, err, "block is not a config block, it holds a transaction of type ENDORSER_TRANSACTION")
+}
+
+func TestEnvelope(t *testing.T) {
+	config := testConfig(t)
+	original := proto.Clone(config)
+
+	env, err := Envelope(testChannelID, config, SetBatchTimeout("5s"))
+	assert.NoError(t, err)
+	chdr, err := utils.ChannelHeader(env)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(cb.HeaderType_CONFIG_UPDATE), chdr.Type)
+	assert.Equal(t, testChannelID, chdr.ChannelId)
+	assert.True(t, proto.Equal(original, config), "the original config must not be modified")
+
+	_, err = Envelope("", config, SetBatchTimeout("5s"))
+	assert.EqualError(t, err, "missing channel ID")
+
+	_, err = Envelope(testChannelID, config, SetBatchTimeout("-5s"))
+	assert.EqualError(t, err, "batch timeout must be greater than zero, got -5s")
+
+	_, err = Envelope(testChannelID, config, SetBatchTimeout("2s"))
+	assert.EqualError(t, err, "error computing config update: no differences detected between original and updated config")
+}
+
+func TestSetBatchTimeout(t *testing.T) {
+	config := testConfig(t)
+	updt := configUpdate(t, config, SetBatchTimeout("5s"))
+
+	value := updt.WriteSet.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.BatchTimeoutKey]
+	require.NotNil(t, value)
+	original := config.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.BatchTimeoutKey]
+	assert.Equal(t, original.Version+1, value.Version)
+	assert.Equal(t, original.ModPolicy, value.ModPolicy)
+	batchTimeout := &ab.BatchTimeout{}
+	assert.NoError(t, proto.Unmarshal(value.Value, batchTimeout))
+	assert.Equal(t, "5s", batchTimeout.Timeout)
+
+	_, err := Envelope(testChannelID, config, SetBatchTimeout("forever"))
+	assert.Error(t, err)
+}
+
+func TestSetBatchSize(t *testing.T) {
+	config := testConfig(t)
+	originalBatchSize := &ab.BatchSize{}
+	require.NoError(t, proto.Unmarshal(config.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.BatchSizeKey].Value, originalBatchSize))
+
+	updt := configUpdate(t, config, SetBatchSize(originalBatchSize.MaxMessageCount+1, 0, 0))
+	batchSize := &ab.BatchSize{}
+	assert.NoError(t, proto.Unmarshal(updt.WriteSet.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.BatchSizeKey].Value, batchSize))
+	assert.Equal(t, originalBatchSize.MaxMessageCount+1, batchSize.MaxMessageCount)
+	assert.Equal(t, originalBatchSize.AbsoluteMaxBytes, batchSize.AbsoluteMaxBytes)
+	assert.Equal(t, originalBatchSize.PreferredMaxBytes, batchSize.PreferredMaxBytes)
+
+	_, err := Envelope(testChannelID, config, SetBatchSize(0, 1024, 2048))
+	assert.EqualError(t, err, "preferred max bytes (2048) must not exceed absolute max bytes (1024)")
+
+	noBatchSize := testConfig(t)
+	delete(noBatchSize.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Values, channelconfig.BatchSizeKey)
+	_, err = Envelope(testChannelID, noBatchSize, SetBatchSize(0, 1024, 512))
+	assert.EqualError(t, err, "max message count must be greater than zero")
+	_, err = Envelope(testChannelID, noBatchSize, SetBatchSize(10, 0, 0))
+	assert.EqualError(t, err, "absolute max bytes must be greater than zero")
+}
+
+func TestSetOrdererAddresses(t *testing.T) {
+	config := testConfig(t)
+	updt := configUpdate(t, config, SetOrdererAddresses([]string{"orderer0:7050", "orderer1:7050"}))
+
+	addresses := &cb.OrdererAddresses{}
+	assert.NoError(t, proto.Unmarshal(updt.WriteSet.Values[channelconfig.OrdererAddressesKey].Value, addresses))
+	assert.Equal(t, []string{"orderer0:7050", "orderer1:7050"}, addresses.Addresses)
+
+	_, err := Envelope(testChannelID, config, SetOrdererAddresses(nil))
+	assert.EqualError(t, err, "at least one orderer address is required")
+	_, err = Envelope(testChannelID, config, SetOrdererAddresses([]string{"orderer0"}))
+	assert.Error(t, err)
+
+	noAddresses := testConfig(t)
+	delete(noAddresses.ChannelGroup.Values, channelconfig.OrdererAddressesKey)
+	updt = configUpdate(t, noAddresses, SetOrdererAddresses([]string{"orderer0:7050"}))
+	assert.Equal(t, ordererAdminsPolicy, updt.WriteSet.Values[channelconfig.OrdererAddressesKey].ModPolicy)
+}
+
+func TestAddRemoveApplicationOrg(t *testing.T) {
+	config := testConfig(t)
+	application := config.ChannelGroup.Groups[channelconfig.ApplicationGroupKey]
+	require.Len(t, application.Groups, 1)
+	var existingOrg string
+	var org *cb.ConfigGroup
+	for name, group := range application.Groups {
+		existingOrg, org = name, proto.Clone(group).(*cb.ConfigGroup)
+	}
+
+	updt := configUpdate(t, config, AddApplicationOrg("NewOrg", org))
+	assert.Contains(t, updt.WriteSet.Groups[channelconfig.ApplicationGroupKey].Groups, "NewOrg")
+	assert.Equal(t, application.Version+1, updt.WriteSet.Groups[channelconfig.ApplicationGroupKey].Version)
+
+	_, err := Envelope(testChannelID, config, AddApplicationOrg(existingOrg, org))
+	assert.EqualError(t, err, "application org "+existingOrg+" already exists")
+
+	updt = configUpdate(t, config, RemoveApplicationOrg(existingOrg))
+	assert.NotContains(t, updt.WriteSet.Groups[channelconfig.ApplicationGroupKey].Groups, existingOrg)
+	assert.Equal(t, application.Version+1, updt.WriteSet.Groups[channelconfig.ApplicationGroupKey].Version)
+
+	_, err = Envelope(testChannelID, config, RemoveApplicationOrg("NewOrg"))
+	assert.EqualError(t, err, "application org NewOrg does not exist")
+
+	noApplication := testConfig(t)
+	delete(noApplication.ChannelGroup.Groups, channelconfig.ApplicationGroupKey)
+	_, err = Envelope(testChannelID, noApplication, AddApplicationOrg("NewOrg", org))
+	assert.EqualError(t, err, "config has no group Application")
+}
+
+func TestSetAnchorPeers(t *testing.T) {
+	config := testConfig(t)
+	var orgName string
+	for name := range config.ChannelGroup.Groups[channelconfig.ApplicationGroupKey].Groups {
+		orgName = name
+	}
+
+	anchorPeers := []*pb.AnchorPeer{{Host: "peer0", Port: 7051}}
+	updt := configUpdate(t, config, SetAnchorPeers(orgName, anchorPeers))
+	value := updt.WriteSet.Groups[channelconfig.ApplicationGroupKey].Groups[orgName].Values[channelconfig.AnchorPeersKey]
+	require.NotNil(t, value)
+	written := &pb.AnchorPeers{}
+	assert.NoError(t, proto.Unmarshal(value.Value, written))
+	assert.True(t, proto.Equal(&pb.AnchorPeers{AnchorPeers: anchorPeers}, written))
+
+	_, err := Envelope(testChannelID, config, SetAnchorPeers("Unknown", anchorPeers))
+	assert.EqualError(t, err, "config has no group Application/Unknown")
+}
+
+func TestSetCapabilities(t *testing.T) {
+	for group, path := range map[string][]string{
+		ChannelCapabilities:     nil,
+		OrdererCapabilities:     {channelconfig.OrdererGroupKey},
+		ApplicationCapabilities: {channelconfig.ApplicationGroupKey},
+	} {
+		config := testConfig(t)
+		updt := configUpdate(t, config, SetCapabilities(group, []string{"V1_1"}))
+
+		writeGroup := updt.WriteSet
+		for _, key := range path {
+			writeGroup = writeGroup.Groups[key]
+		}
+		value := writeGroup.Values[channelconfig.CapabilitiesKey]
+		require.NotNil(t, value, "capabilities of group %s", group)
+		capabilities := &cb.Capabilities{}
+		assert.NoError(t, proto.Unmarshal(value.Value, capabilities))
+		assert.Equal(t, map[string]*cb.Capability{"V1_1": {}}, capabilities.Capabilities)
+	}
+
+	_, err := Envelope(testChannelID, testConfig(t), SetCapabilities("consortiums", nil))
+	assert.EqualError(t, err, "unknown capabilities group consortiums, expected one of channel, orderer or application")
+}
+
+func TestSetACLs(t *testing.T) {
+	resourcesConfig := &cb.Config{
+		Type: int32(cb.ConfigType_RESOURCE),
+		ChannelGroup: &cb.ConfigGroup{
+			Groups: map[string]*cb.ConfigGroup{
+				resourcesconfig.APIsGroupKey: {
+					Values: map[string]*cb.ConfigValue{
+						"qscc/GetChainInfo": {
+							Value:     utils.MarshalOrPanic(&pb.APIResource{PolicyRef: "/Channel/Application/Readers"}),
+							ModPolicy: "/Channel/Application/Admins",
+							Version:   2,
+						},
+					},
+					ModPolicy: "Admins",
+				},
+			},
+		},
+	}
+
+	env, err := Envelope(testChannelID, resourcesConfig, SetACLs(map[string]string{
+		"qscc/GetChainInfo":   "/Channel/Application/Writers",
+		"qscc/GetBlockByHash": "/Channel/Application/Readers",
+	}))
+	require.NoError(t, err)
+	chdr, err := utils.ChannelHeader(env)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(cb.HeaderType_PEER_RESOURCE_UPDATE), chdr.Type)
+
+	updt := configUpdate(t, resourcesConfig, SetACLs(map[string]string{
+		"qscc/GetChainInfo":   "/Channel/Application/Writers",
+		"qscc/GetBlockByHash": "/Channel/Application/Readers",
+	}))
+	values := updt.WriteSet.Groups[resourcesconfig.APIsGroupKey].Values
+	assert.Equal(t, uint64(3), values["qscc/GetChainInfo"].Version)
+	assert.Equal(t, "/Channel/Application/Admins", values["qscc/GetChainInfo"].ModPolicy)
+	assert.Equal(t, uint64(0), values["qscc/GetBlockByHash"].Version)
+	assert.Equal(t, "Admins", values["qscc/GetBlockByHash"].ModPolicy)
+	api := &pb.APIResource{}
+	assert.NoError(t, proto.Unmarshal(values["qscc/GetChainInfo"].Value, api))
+	assert.Equal(t, "/Channel/Application/Writers", api.PolicyRef)
+
+	_, err = Envelope(testChannelID, testConfig(t), SetACLs(map[string]string{"qscc/GetChainInfo": "/Channel/Application/Writers"}))
+	assert.EqualError(t, err, "ACLs can only be set in a resources config")
+
+	_, err = Envelope(testChannelID, resourcesConfig, SetACLs(map[string]string{"qscc/GetChainInfo": ""}))
+	assert.Error(t, err)
+}
+
+func TestOrgGroupFromJSON(t *testing.T) {
+	config := testConfig(t)
+	var org *cb.ConfigGroup
+	for _, group := range config.ChannelGroup.Groups[channelconfig.ApplicationGroupKey].Groups {
+		org = group
+	}
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, protolator.DeepMarshalJSON(buf, &pb.DynamicApplicationOrgGroup{ConfigGroup: org}))
+
+	parsed, err := OrgGroupFromJSON(buf)
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(org, parsed))
+
+	_, err = OrgGroupFromJSON(bytes.NewBufferString("{"))
+	assert.Error(t, err)
+}
+
+func TestParseAnchorPeer(t *testing.T) {
+	anchorPeer, err := ParseAnchorPeer("peer0.org1.example.com:7051")
+	assert.NoError(t, err)
+	assert.Equal(t, &pb.AnchorPeer{Host: "peer0.org1.example.com", Port: 7051}, anchorPeer)
+
+	for _, bad := range []string{"peer0", "peer0:port", "peer0:70510"} {
+		_, err = ParseAnchorPeer(bad)
+		assert.Error(t, err, bad)
+	}
+}
+
+func TestParseACL(t *testing.T) {
+	resource, policyRef, err := ParseACL("qscc/GetChainInfo=/Channel/Application/Readers")
+	assert.NoError(t, err)
+	assert.Equal(t, "qscc/GetChainInfo", resource)
+	assert.Equal(t, "/Channel/Application/Readers", policyRef)
+
+	for _, bad := range []string{"qscc/GetChainInfo", "=Readers", "qscc/GetChainInfo="} {
+		_, _, err = ParseACL(bad)
+		assert.Error(t, err, bad)
+	}
+}
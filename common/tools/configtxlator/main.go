@@ -23,11 +23,15 @@ import (
 	"os"
 	"reflect"
 
+	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
+	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	"github.com/hyperledger/fabric/common/tools/configtxlator/edit"
 	"github.com/hyperledger/fabric/common/tools/configtxlator/metadata"
 	"github.com/hyperledger/fabric/common/tools/configtxlator/rest"
 	"github.com/hyperledger/fabric/common/tools/configtxlator/update"
 	"github.com/hyperledger/fabric/common/tools/protolator"
 	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/op/go-logging"
@@ -61,12 +65,52 @@ var (
 	computeUpdateChannelID = computeUpdate.Flag("channel_id", "The name of the channel for this update.").Required().String()
 	computeUpdateDest      = computeUpdate.Flag("output", "A file to write the JSON document to.").Default(os.Stdout.Name()).OpenFile(os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
 
+	editConfig    = app.Command("edit", "Edits a channel config and computes the config update envelope, ready to be signed, which applies the edit.")
+	editBlock     = editConfig.Flag("block", "A config block holding the config to edit.").File()
+	editOriginal  = editConfig.Flag("original", "The config message to edit, as an alternative to --block.").File()
+	editChannelID = editConfig.Flag("channel_id", "The name of the channel for this update, required with --original.").String()
+	editDest      = editConfig.Flag("output", "A file to write the config update envelope to.").Default(os.Stdout.Name()).OpenFile(os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600)
+
+	addOrg            = editConfig.Command("add_org", "Adds an application org to the channel.")
+	addOrgName        = addOrg.Flag("org", "The name of the org.").Required().String()
+	addOrgMSPDir      = addOrg.Flag("msp_dir", "The MSP directory of the org.").String()
+	addOrgMSPID       = addOrg.Flag("msp_id", "The MSP ID of the org, required with --msp_dir.").String()
+	addOrgMSPType     = addOrg.Flag("msp_type", "The type of the MSP of the org.").Default("bccsp").String()
+	addOrgAnchorPeers = addOrg.Flag("anchor_peer", "An anchor peer of the org as host:port, may be repeated.").Strings()
+	addOrgGroup       = addOrg.Flag("org_group", "A JSON file defining the org, as printed by 'configtxgen -printOrg', as an alternative to --msp_dir.").File()
+
+	removeOrg     = editConfig.Command("remove_org", "Removes an application org from the channel.")
+	removeOrgName = removeOrg.Flag("org", "The name of the org.").Required().String()
+
+	setAnchorPeers      = editConfig.Command("set_anchor_peers", "Replaces the anchor peers of an application org.")
+	setAnchorPeersOrg   = setAnchorPeers.Flag("org", "The name of the org.").Required().String()
+	setAnchorPeersPeers = setAnchorPeers.Flag("anchor_peer", "An anchor peer of the org as host:port, may be repeated.").Strings()
+
+	setOrdererAddresses          = editConfig.Command("set_orderer_addresses", "Replaces the addresses of the ordering service nodes.")
+	setOrdererAddressesAddresses = setOrdererAddresses.Flag("address", "An orderer address as host:port, may be repeated.").Required().Strings()
+
+	setBatchSize                  = editConfig.Command("set_batch_size", "Changes the batch size of the ordering service.")
+	setBatchSizeMaxMessageCount   = setBatchSize.Flag("max_message_count", "The maximum number of messages in a batch, unchanged if not set.").Uint32()
+	setBatchSizeAbsoluteMaxBytes  = setBatchSize.Flag("absolute_max_bytes", "The absolute maximum number of bytes in a batch, unchanged if not set.").Uint32()
+	setBatchSizePreferredMaxBytes = setBatchSize.Flag("preferred_max_bytes", "The preferred maximum number of bytes in a batch, unchanged if not set.").Uint32()
+
+	setBatchTimeout        = editConfig.Command("set_batch_timeout", "Changes the batch timeout of the ordering service.")
+	setBatchTimeoutTimeout = setBatchTimeout.Flag("timeout", "The batch timeout, for example '2s'.").Required().String()
+
+	setCapabilities             = editConfig.Command("set_capabilities", "Replaces the capabilities of the channel, orderer or application group.")
+	setCapabilitiesGroup        = setCapabilities.Flag("group", "The group whose capabilities to set.").Required().Enum(edit.ChannelCapabilities, edit.OrdererCapabilities, edit.ApplicationCapabilities)
+	setCapabilitiesCapabilities = setCapabilities.Flag("capability", "A required capability, for example 'V1_1', may be repeated.").Strings()
+
+	setACLs     = editConfig.Command("set_acls", "Sets the policies of peer resource ACLs in a resources config.")
+	setACLsACLs = setACLs.Flag("acl", "An ACL as resource=policy, for example 'qscc/GetChainInfo=/Channel/Application/Readers', may be repeated.").Required().Strings()
+
 	version = app.Command("version", "Show version information")
 )
 
 func main() {
 	kingpin.Version("0.0.1")
-	switch kingpin.MustParse(app.Parse(os.Args[1:])) {
+	command := kingpin.MustParse(app.Parse(os.Args[1:]))
+	switch command {
 	// "start" command
 	case start.FullCommand():
 		startServer(fmt.Sprintf("%s:%d", *hostname, *port))
@@ -93,6 +137,13 @@ func main() {
 		if err != nil {
 			app.Fatalf("Error computing update: %s", err)
 		}
+	case addOrg.FullCommand(), removeOrg.FullCommand(), setAnchorPeers.FullCommand(), setOrdererAddresses.FullCommand(),
+		setBatchSize.FullCommand(), setBatchTimeout.FullCommand(), setCapabilities.FullCommand(), setACLs.FullCommand():
+		defer (*editDest).Close()
+		err := editCfg(command, *editDest)
+		if err != nil {
+			app.Fatalf("Error editing config: %s", err)
+		}
 	// "version" command
 	case version.FullCommand():
 		printVersion()
@@ -203,3 +254,149 @@ func computeUpdt(original, updated, output *os.File, channelID string) error {
 
 	return nil
 }
+
+func editCfg(command string, output *os.File) error {
+	channelID, config, err := editInput()
+	if err != nil {
+		return err
+	}
+
+	e, err := configEdit(command)
+	if err != nil {
+		return err
+	}
+
+	env, err := edit.Envelope(channelID, config, e)
+	if err != nil {
+		return err
+	}
+
+	outBytes, err := proto.Marshal(env)
+	if err != nil {
+		return errors.Wrapf(err, "error marshaling config update envelope")
+	}
+
+	_, err = output.Write(outBytes)
+	if err != nil {
+		return errors.Wrapf(err, "error writing config update envelope to output")
+	}
+
+	return nil
+}
+
+// editInput returns the channel ID and the config to edit, read either from a
+// config block or from a config message
+func editInput() (string, *cb.Config, error) {
+	switch {
+	case *editBlock != nil:
+		defer (*editBlock).Close()
+		in, err := ioutil.ReadAll(*editBlock)
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "error reading config block")
+		}
+		block := &cb.Block{}
+		err = proto.Unmarshal(in, block)
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "error unmarshaling config block")
+		}
+		return edit.ConfigFromBlock(block)
+	case *editOriginal != nil:
+		defer (*editOriginal).Close()
+		in, err := ioutil.ReadAll(*editOriginal)
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "error reading original config")
+		}
+		config := &cb.Config{}
+		err = proto.Unmarshal(in, config)
+		if err != nil {
+			return "", nil, errors.Wrapf(err, "error unmarshaling original config")
+		}
+		return *editChannelID, config, nil
+	default:
+		return "", nil, errors.New("either --block or --original must be specified")
+	}
+}
+
+func configEdit(command string) (edit.Edit, error) {
+	switch command {
+	case addOrg.FullCommand():
+		org, err := orgGroup()
+		if err != nil {
+			return nil, err
+		}
+		return edit.AddApplicationOrg(*addOrgName, org), nil
+	case removeOrg.FullCommand():
+		return edit.RemoveApplicationOrg(*removeOrgName), nil
+	case setAnchorPeers.FullCommand():
+		anchorPeers, err := parseAnchorPeers(*setAnchorPeersPeers)
+		if err != nil {
+			return nil, err
+		}
+		return edit.SetAnchorPeers(*setAnchorPeersOrg, anchorPeers), nil
+	case setOrdererAddresses.FullCommand():
+		return edit.SetOrdererAddresses(*setOrdererAddressesAddresses), nil
+	case setBatchSize.FullCommand():
+		return edit.SetBatchSize(*setBatchSizeMaxMessageCount, *setBatchSizeAbsoluteMaxBytes, *setBatchSizePreferredMaxBytes), nil
+	case setBatchTimeout.FullCommand():
+		return edit.SetBatchTimeout(*setBatchTimeoutTimeout), nil
+	case setCapabilities.FullCommand():
+		return edit.SetCapabilities(*setCapabilitiesGroup, *setCapabilitiesCapabilities), nil
+	case setACLs.FullCommand():
+		acls := make(map[string]string)
+		for _, acl := range *setACLsACLs {
+			resource, policyRef, err := edit.ParseACL(acl)
+			if err != nil {
+				return nil, err
+			}
+			acls[resource] = policyRef
+		}
+		return edit.SetACLs(acls), nil
+	default:
+		return nil, errors.Errorf("unknown edit %s", command)
+	}
+}
+
+// orgGroup returns the definition of the org to add, either read from a JSON
+// file or generated from its MSP directory
+func orgGroup() (*cb.ConfigGroup, error) {
+	if *addOrgGroup != nil {
+		defer (*addOrgGroup).Close()
+		return edit.OrgGroupFromJSON(*addOrgGroup)
+	}
+
+	if *addOrgMSPDir == "" || *addOrgMSPID == "" {
+		return nil, errors.New("either --org_group or both --msp_dir and --msp_id must be specified")
+	}
+
+	anchorPeers, err := parseAnchorPeers(*addOrgAnchorPeers)
+	if err != nil {
+		return nil, err
+	}
+	org := &genesisconfig.Organization{
+		Name:           *addOrgName,
+		ID:             *addOrgMSPID,
+		MSPDir:         *addOrgMSPDir,
+		MSPType:        *addOrgMSPType,
+		AdminPrincipal: genesisconfig.AdminRoleAdminPrincipal,
+	}
+	for _, anchorPeer := range anchorPeers {
+		org.AnchorPeers = append(org.AnchorPeers, &genesisconfig.AnchorPeer{
+			Host: anchorPeer.Host,
+			Port: int(anchorPeer.Port),
+		})
+	}
+
+	return encoder.NewApplicationOrgGroup(org)
+}
+
+func parseAnchorPeers(anchorPeers []string) ([]*pb.AnchorPeer, error) {
+	var result []*pb.AnchorPeer
+	for _, anchorPeer := range anchorPeers {
+		ap, err := edit.ParseAnchorPeer(anchorPeer)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, ap)
+	}
+	return result, nil
+}
@@ -0,0 +1,197 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/hyperledger/fabric/common/tools/configtxlator/edit"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/gorilla/mux"
+	"github.com/pkg/errors"
+)
+
+// maxEditFormMemory is the amount of memory used to parse the multipart form
+// of an edit request, the remainder is stored in temporary files
+const maxEditFormMemory = 32 << 20
+
+// editParsers construct, for each supported edit operation, the edit from the
+// form values of the request
+var editParsers = map[string]func(r *http.Request) (edit.Edit, error){
+	"add_org": func(r *http.Request) (edit.Edit, error) {
+		orgName, err := requiredFormValue("org", r)
+		if err != nil {
+			return nil, err
+		}
+		orgFile, _, err := r.FormFile("org_group")
+		if err != nil {
+			return nil, errors.Wrap(err, "error with field 'org_group'")
+		}
+		defer orgFile.Close()
+		org, err := edit.OrgGroupFromJSON(orgFile)
+		if err != nil {
+			return nil, err
+		}
+		return edit.AddApplicationOrg(orgName, org), nil
+	},
+	"remove_org": func(r *http.Request) (edit.Edit, error) {
+		orgName, err := requiredFormValue("org", r)
+		if err != nil {
+			return nil, err
+		}
+		return edit.RemoveApplicationOrg(orgName), nil
+	},
+	"set_anchor_peers": func(r *http.Request) (edit.Edit, error) {
+		orgName, err := requiredFormValue("org", r)
+		if err != nil {
+			return nil, err
+		}
+		var anchorPeers []*pb.AnchorPeer
+		for _, value := range r.Form["anchor_peer"] {
+			anchorPeer, err := edit.ParseAnchorPeer(value)
+			if err != nil {
+				return nil, err
+			}
+			anchorPeers = append(anchorPeers, anchorPeer)
+		}
+		return edit.SetAnchorPeers(orgName, anchorPeers), nil
+	},
+	"set_orderer_addresses": func(r *http.Request) (edit.Edit, error) {
+		return edit.SetOrdererAddresses(r.Form["address"]), nil
+	},
+	"set_batch_size": func(r *http.Request) (edit.Edit, error) {
+		var sizes [3]uint32
+		for i, field := range []string{"max_message_count", "absolute_max_bytes", "preferred_max_bytes"} {
+			value := r.FormValue(field)
+			if value == "" {
+				continue
+			}
+			size, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				return nil, errors.Wrapf(err, "error with field '%s'", field)
+			}
+			sizes[i] = uint32(size)
+		}
+		return edit.SetBatchSize(sizes[0], sizes[1], sizes[2]), nil
+	},
+	"set_batch_timeout": func(r *http.Request) (edit.Edit, error) {
+		timeout, err := requiredFormValue("timeout", r)
+		if err != nil {
+			return nil, err
+		}
+		return edit.SetBatchTimeout(timeout), nil
+	},
+	"set_capabilities": func(r *http.Request) (edit.Edit, error) {
+		group, err := requiredFormValue("group", r)
+		if err != nil {
+			return nil, err
+		}
+		return edit.SetCapabilities(group, r.Form["capability"]), nil
+	},
+	"set_acls": func(r *http.Request) (edit.Edit, error) {
+		acls := make(map[string]string)
+		for _, value := range r.Form["acl"] {
+			resource, policyRef, err := edit.ParseACL(value)
+			if err != nil {
+				return nil, err
+			}
+			acls[resource] = policyRef
+		}
+		if len(acls) == 0 {
+			return nil, errors.New("missing field 'acl'")
+		}
+		return edit.SetACLs(acls), nil
+	},
+}
+
+func requiredFormValue(field string, r *http.Request) (string, error) {
+	value := r.FormValue(field)
+	if value == "" {
+		return "", errors.Errorf("missing field '%s'", field)
+	}
+	return value, nil
+}
+
+// editInput returns the channel ID and the config to edit, taken either from
+// the config block in field 'block' or from the config in field 'original'
+// and the channel ID in field 'channel'
+func editInput(r *http.Request) (string, *cb.Config, error) {
+	if r.MultipartForm != nil && len(r.MultipartForm.File["block"]) > 0 {
+		blockBytes, err := fieldBytes("block", r)
+		if err != nil {
+			return "", nil, fmt.Errorf("error with field 'block': %s", err)
+		}
+		block := &cb.Block{}
+		err = proto.Unmarshal(blockBytes, block)
+		if err != nil {
+			return "", nil, fmt.Errorf("error with field 'block': %s", err)
+		}
+		return edit.ConfigFromBlock(block)
+	}
+
+	config, err := fieldConfigProto("original", r)
+	if err != nil {
+		return "", nil, fmt.Errorf("error with field 'original': %s", err)
+	}
+	return r.FormValue("channel"), config, nil
+}
+
+func EditConfig(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	operation := vars["operation"]
+
+	parser, ok := editParsers[operation]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, "Unknown edit operation: %s\n", operation)
+		return
+	}
+
+	err := r.ParseMultipartForm(maxEditFormMemory)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, "Error parsing form: %s\n", err)
+		return
+	}
+
+	channelID, config, err := editInput(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, "Error reading config: %s\n", err)
+		return
+	}
+
+	e, err := parser(r)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, "Error with edit parameters: %s\n", err)
+		return
+	}
+
+	env, err := edit.Envelope(channelID, config, e)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, "Error editing config: %s\n", err)
+		return
+	}
+
+	encoded, err := proto.Marshal(env)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprintf(w, "Error marshaling config update envelope: %s\n", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.WriteHeader(http.StatusOK)
+	w.Write(encoded)
+}
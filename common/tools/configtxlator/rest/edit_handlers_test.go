@@ -0,0 +1,122 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package rest
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hyperledger/fabric/bccsp/factory"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
+	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
+	"github.com/hyperledger/fabric/common/tools/configtxlator/edit"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func editRequest(t *testing.T, operation string, files map[string][]byte, values map[string][]string) *httptest.ResponseRecorder {
+	buffer := &bytes.Buffer{}
+	mpw := multipart.NewWriter(buffer)
+
+	for field, content := range files {
+		ffw, err := mpw.CreateFormFile(field, field)
+		require.NoError(t, err)
+		_, err = bytes.NewReader(content).WriteTo(ffw)
+		require.NoError(t, err)
+	}
+	for field, fieldValues := range values {
+		for _, value := range fieldValues {
+			require.NoError(t, mpw.WriteField(field, value))
+		}
+	}
+	require.NoError(t, mpw.Close())
+
+	req, err := http.NewRequest("POST", "/configtxlator/edit/"+operation, buffer)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", mpw.FormDataContentType())
+
+	rec := httptest.NewRecorder()
+	NewRouter().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestEditConfig(t *testing.T) {
+	factory.InitFactories(nil)
+	block := encoder.New(genesisconfig.Load(genesisconfig.SampleDevModeSoloProfile)).GenesisBlockForChannel("foo")
+	blockBytes := utils.MarshalOrPanic(block)
+	_, config, err := edit.ConfigFromBlock(block)
+	require.NoError(t, err)
+
+	t.Run("FromBlock", func(t *testing.T) {
+		rec := editRequest(t, "set_orderer_addresses", map[string][]byte{"block": blockBytes},
+			map[string][]string{"address": {"orderer0:7050", "orderer1:7050"}})
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		env := &cb.Envelope{}
+		require.NoError(t, proto.Unmarshal(rec.Body.Bytes(), env))
+		chdr, err := utils.ChannelHeader(env)
+		require.NoError(t, err)
+		assert.Equal(t, int32(cb.HeaderType_CONFIG_UPDATE), chdr.Type)
+		assert.Equal(t, "foo", chdr.ChannelId)
+	})
+
+	t.Run("FromConfig", func(t *testing.T) {
+		rec := editRequest(t, "set_batch_size", map[string][]byte{"original": utils.MarshalOrPanic(config)},
+			map[string][]string{"channel": {"bar"}, "max_message_count": {"50"}})
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+		env := &cb.Envelope{}
+		require.NoError(t, proto.Unmarshal(rec.Body.Bytes(), env))
+		chdr, err := utils.ChannelHeader(env)
+		require.NoError(t, err)
+		assert.Equal(t, "bar", chdr.ChannelId)
+	})
+
+	t.Run("RemoveOrg", func(t *testing.T) {
+		var orgName string
+		for name := range config.ChannelGroup.Groups[channelconfig.ApplicationGroupKey].Groups {
+			orgName = name
+		}
+		rec := editRequest(t, "remove_org", map[string][]byte{"block": blockBytes},
+			map[string][]string{"org": {orgName}})
+		assert.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	})
+
+	t.Run("UnknownOperation", func(t *testing.T) {
+		rec := editRequest(t, "set_everything", map[string][]byte{"block": blockBytes}, nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code, rec.Body.String())
+	})
+
+	t.Run("MissingConfig", func(t *testing.T) {
+		rec := editRequest(t, "set_batch_timeout", nil, map[string][]string{"timeout": {"5s"}})
+		assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	})
+
+	t.Run("BadParameters", func(t *testing.T) {
+		rec := editRequest(t, "set_batch_size", map[string][]byte{"block": blockBytes},
+			map[string][]string{"max_message_count": {"many"}})
+		assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+
+		rec = editRequest(t, "add_org", map[string][]byte{"block": blockBytes},
+			map[string][]string{"org": {"NewOrg"}})
+		assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	})
+
+	t.Run("BadEdit", func(t *testing.T) {
+		rec := editRequest(t, "set_batch_timeout", map[string][]byte{"block": blockBytes},
+			map[string][]string{"timeout": {"forever"}})
+		assert.Equal(t, http.StatusBadRequest, rec.Code, rec.Body.String())
+	})
+}
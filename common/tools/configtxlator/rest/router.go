@@ -35,6 +35,9 @@ func NewRouter() *mux.Router {
 	router.
 		HandleFunc("/configtxlator/config/verify", SanityCheckConfig).
 		Methods("POST")
+	router.
+		HandleFunc("/configtxlator/edit/{operation}", EditConfig).
+		Methods("POST")
 
 	return router
 }
@@ -7,6 +7,7 @@ package main
 
 import (
 	"bytes"
+	"crypto/x509"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -210,6 +211,12 @@ var (
 	ext           = app.Command("extend", "Extend existing network")
 	inputDir      = ext.Flag("input", "The input directory in which existing network place").Default("crypto-config").String()
 	extConfigFile = ext.Flag("config", "The configuration template to use").File()
+
+	ren             = app.Command("renew", "Renew the certificates of an existing network")
+	renewInputDir   = ren.Flag("input", "The input directory in which existing network place").Default("crypto-config").String()
+	renewConfigFile = ren.Flag("config", "The configuration template to use").File()
+	rekey           = ren.Flag("rekey", "Generate new keys for the renewed certificates").Bool()
+	rotateCA        = ren.Flag("rotate-ca", "Issue the renewed certificates by new CAs, keeping the previous CA certificates in the MSPs").Bool()
 )
 
 func main() {
@@ -223,6 +230,9 @@ func main() {
 	case ext.FullCommand():
 		extend()
 
+	case ren.FullCommand():
+		renew()
+
 		// "showtemplate" command
 	case showtemplate.FullCommand():
 		fmt.Print(defaultConfig)
@@ -251,6 +261,13 @@ func getConfig() (*Config, error) {
 			return nil, fmt.Errorf("Error reading configuration: %s", err)
 		}
 
+		configData = string(data)
+	} else if *renewConfigFile != nil {
+		data, err := ioutil.ReadAll(*renewConfigFile)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading configuration: %s", err)
+		}
+
 		configData = string(data)
 	} else {
 		configData = defaultConfig
@@ -370,6 +387,176 @@ func extendOrdererOrg(orgSpec OrgSpec) {
 	}
 }
 
+func renew() {
+	config, err := getConfig()
+	if err != nil {
+		fmt.Printf("Error reading config: %s", err)
+		os.Exit(-1)
+	}
+
+	for _, orgSpec := range config.PeerOrgs {
+		err = renderOrgSpec(&orgSpec, "peer")
+		if err != nil {
+			fmt.Printf("Error processing peer configuration: %s", err)
+			os.Exit(-1)
+		}
+		renewPeerOrg(orgSpec)
+	}
+
+	for _, orgSpec := range config.OrdererOrgs {
+		err = renderOrgSpec(&orgSpec, "orderer")
+		if err != nil {
+			fmt.Printf("Error processing orderer configuration: %s", err)
+			os.Exit(-1)
+		}
+		renewOrdererOrg(orgSpec)
+	}
+}
+
+func renewPeerOrg(orgSpec OrgSpec) {
+	orgName := orgSpec.Domain
+	orgDir := filepath.Join(*renewInputDir, "peerOrganizations", orgName)
+
+	// TODO: add ability to specify usernames
+	users := []NodeSpec{}
+	for j := 1; j <= orgSpec.Users.Count; j++ {
+		user := NodeSpec{
+			CommonName: fmt.Sprintf("%s%d@%s", userBaseName, j, orgName),
+		}
+
+		users = append(users, user)
+	}
+	// add the admin user
+	adminUser := NodeSpec{
+		CommonName: fmt.Sprintf("%s@%s", adminBaseName, orgName),
+	}
+	users = append(users, adminUser)
+
+	renewOrg(orgDir, orgSpec, "peers", msp.PEER, orgSpec.EnableNodeOUs, users)
+}
+
+func renewOrdererOrg(orgSpec OrgSpec) {
+	orgName := orgSpec.Domain
+	orgDir := filepath.Join(*renewInputDir, "ordererOrganizations", orgName)
+
+	adminUser := NodeSpec{
+		CommonName: fmt.Sprintf("%s@%s", adminBaseName, orgName),
+	}
+
+	renewOrg(orgDir, orgSpec, "orderers", msp.ORDERER, false, []NodeSpec{adminUser})
+}
+
+// renewOrg reissues the certificates of the nodes and users of the org in
+// orgDir. They are issued by the existing CAs of the org, or by new ones if
+// CA rotation was requested.
+func renewOrg(orgDir string, orgSpec OrgSpec, nodesDirName string, nodeType int, nodeOUs bool, users []NodeSpec) {
+	orgName := orgSpec.Domain
+	if _, err := os.Stat(orgDir); os.IsNotExist(err) {
+		fmt.Printf("Skipping org %s: %s does not exist\n", orgName, orgDir)
+		return
+	}
+
+	fmt.Println(orgName)
+	caDir := filepath.Join(orgDir, "ca")
+	tlsCADir := filepath.Join(orgDir, "tlsca")
+	mspDir := filepath.Join(orgDir, "msp")
+	nodesDir := filepath.Join(orgDir, nodesDirName)
+	usersDir := filepath.Join(orgDir, "users")
+
+	signCA := getCA(caDir, orgSpec, orgSpec.CA.CommonName)
+	tlsCA := getCA(tlsCADir, orgSpec, "tls"+orgSpec.CA.CommonName)
+	if signCA.Signer == nil || signCA.SignCert == nil || tlsCA.Signer == nil || tlsCA.SignCert == nil {
+		fmt.Printf("Error loading CAs for org %s from %s\n", orgName, orgDir)
+		os.Exit(1)
+	}
+
+	var prevSignCert, prevTLSCert *x509.Certificate
+	if *rotateCA {
+		var err error
+		prevSignCert, prevTLSCert = signCA.SignCert, tlsCA.SignCert
+		signCA, err = replaceCA(caDir, orgSpec, signCA.Name)
+		if err != nil {
+			fmt.Printf("Error rotating signCA for org %s:\n%v\n", orgName, err)
+			os.Exit(1)
+		}
+		tlsCA, err = replaceCA(tlsCADir, orgSpec, tlsCA.Name)
+		if err != nil {
+			fmt.Printf("Error rotating tlsCA for org %s:\n%v\n", orgName, err)
+			os.Exit(1)
+		}
+	}
+
+	err := msp.RenewVerifyingMSP(mspDir, signCA, tlsCA, prevSignCert, prevTLSCert)
+	if err != nil {
+		fmt.Printf("Error renewing MSP for org %s:\n%v\n", orgName, err)
+		os.Exit(1)
+	}
+
+	renewNodes(nodesDir, orgSpec.Specs, signCA, tlsCA, prevSignCert, prevTLSCert, nodeType, nodeOUs)
+	renewNodes(usersDir, users, signCA, tlsCA, prevSignCert, prevTLSCert, msp.CLIENT, nodeOUs)
+
+	// the admin cert was reissued, so replace its copies in the org's MSP
+	// and in the MSPs of each of the org's nodes
+	adminUserName := fmt.Sprintf("%s@%s", adminBaseName, orgName)
+	adminCertsDirs := []string{filepath.Join(mspDir, "admincerts")}
+	for _, spec := range orgSpec.Specs {
+		adminCertsDirs = append(adminCertsDirs, filepath.Join(nodesDir, spec.CommonName, "msp", "admincerts"))
+	}
+	for _, adminCertsDir := range adminCertsDirs {
+		err = updateAdminCert(usersDir, adminCertsDir, adminUserName)
+		if err != nil {
+			fmt.Printf("Error updating admin cert in %s:\n%v\n", adminCertsDir, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// replaceCA moves the CA in caDir aside to caDir-previous and generates a new
+// CA with the same name in its place
+func replaceCA(caDir string, orgSpec OrgSpec, name string) (*ca.CA, error) {
+	prevDir := caDir + "-previous"
+	err := os.RemoveAll(prevDir)
+	if err != nil {
+		return nil, err
+	}
+	err = os.Rename(caDir, prevDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return ca.NewCA(caDir, orgSpec.Domain, name, orgSpec.CA.Country, orgSpec.CA.Province, orgSpec.CA.Locality, orgSpec.CA.OrganizationalUnit, orgSpec.CA.StreetAddress, orgSpec.CA.PostalCode)
+}
+
+func renewNodes(baseDir string, nodes []NodeSpec, signCA *ca.CA, tlsCA *ca.CA,
+	prevSignCert, prevTLSCert *x509.Certificate, nodeType int, nodeOUs bool) {
+
+	for _, node := range nodes {
+		nodeDir := filepath.Join(baseDir, node.CommonName)
+		if _, err := os.Stat(nodeDir); os.IsNotExist(err) {
+			fmt.Printf("Skipping %s: %s does not exist\n", node.CommonName, nodeDir)
+			continue
+		}
+		err := msp.RenewLocalMSP(nodeDir, node.CommonName, node.SANS, signCA, tlsCA,
+			prevSignCert, prevTLSCert, nodeType, nodeOUs, *rekey)
+		if err != nil {
+			fmt.Printf("Error renewing local MSP for %s:\n%v\n", node, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// updateAdminCert replaces the copy of the admin cert in adminCertsDir, if
+// there is one, with the current admin cert
+func updateAdminCert(usersDir, adminCertsDir, adminUserName string) error {
+	adminCert := filepath.Join(adminCertsDir, adminUserName+"-cert.pem")
+	if _, err := os.Stat(adminCert); os.IsNotExist(err) {
+		return nil
+	}
+
+	return copyFile(filepath.Join(usersDir, adminUserName, "msp", "signcerts",
+		adminUserName+"-cert.pem"), adminCert)
+}
+
 func generate() {
 
 	config, err := getConfig()
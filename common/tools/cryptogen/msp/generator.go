@@ -6,9 +6,12 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"crypto/ecdsa"
 	"crypto/x509"
 	"encoding/hex"
 	"encoding/pem"
+	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 
@@ -200,6 +203,204 @@ func GenerateVerifyingMSP(baseDir string, signCA *ca.CA, tlsCA *ca.CA, nodeOUs b
 	return nil
 }
 
+// RenewLocalMSP reissues the signing and TLS certificates of the local MSP
+// previously generated in baseDir by GenerateLocalMSP, using the given CAs.
+// The existing keys are kept unless rekey is set. If the certificates of the
+// CAs which issued the previous certificates are given, they are kept in the
+// MSP next to the certificates of the current CAs.
+func RenewLocalMSP(baseDir, name string, sans []string, signCA *ca.CA, tlsCA *ca.CA,
+	prevSignCert, prevTLSCert *x509.Certificate, nodeType int, nodeOUs, rekey bool) error {
+
+	mspDir := filepath.Join(baseDir, "msp")
+	tlsDir := filepath.Join(baseDir, "tls")
+
+	/*
+		Reissue the MSP identity
+	*/
+	keystore := filepath.Join(mspDir, "keystore")
+	signCertPath := filepath.Join(mspDir, "signcerts", x509Filename(name))
+
+	var ecPubKey *ecdsa.PublicKey
+	if rekey {
+		// clear the keystore so that it only holds the new key
+		err := os.RemoveAll(keystore)
+		if err != nil {
+			return err
+		}
+		err = os.MkdirAll(keystore, 0755)
+		if err != nil {
+			return err
+		}
+		priv, _, err := csp.GeneratePrivateKey(keystore)
+		if err != nil {
+			return err
+		}
+		ecPubKey, err = csp.GetECPublicKey(priv)
+		if err != nil {
+			return err
+		}
+	} else {
+		var err error
+		ecPubKey, err = loadECPublicKey(signCertPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	var ous []string
+	if nodeOUs {
+		ous = []string{nodeOUMap[nodeType]}
+	}
+	cert, err := signCA.SignCertificate(filepath.Join(mspDir, "signcerts"),
+		name, ous, nil, ecPubKey, x509.KeyUsageDigitalSignature, []x509.ExtKeyUsage{})
+	if err != nil {
+		return err
+	}
+
+	err = exportCACerts(mspDir, signCA, tlsCA, prevSignCert, prevTLSCert)
+	if err != nil {
+		return err
+	}
+
+	// keep the signing identity an admin of this MSP if it was one
+	adminCert := filepath.Join(mspDir, "admincerts", x509Filename(name))
+	if _, err := os.Stat(adminCert); err == nil {
+		err = x509Export(adminCert, cert)
+		if err != nil {
+			return err
+		}
+	}
+
+	/*
+		Reissue the TLS certificate
+	*/
+	tlsFilePrefix := "server"
+	if nodeType == CLIENT {
+		tlsFilePrefix = "client"
+	}
+	tlsCertPath := filepath.Join(tlsDir, tlsFilePrefix+".crt")
+
+	var tlsPubKey *ecdsa.PublicKey
+	if rekey {
+		tlsPrivKey, _, err := csp.GeneratePrivateKey(tlsDir)
+		if err != nil {
+			return err
+		}
+		tlsPubKey, err = csp.GetECPublicKey(tlsPrivKey)
+		if err != nil {
+			return err
+		}
+		err = keyExport(tlsDir, filepath.Join(tlsDir, tlsFilePrefix+".key"), tlsPrivKey)
+		if err != nil {
+			return err
+		}
+	} else {
+		tlsPubKey, err = loadECPublicKey(tlsCertPath)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tlsCA.SignCertificate(tlsDir,
+		name, nil, sans, tlsPubKey, x509.KeyUsageDigitalSignature|x509.KeyUsageKeyEncipherment,
+		[]x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth})
+	if err != nil {
+		return err
+	}
+	err = os.Rename(filepath.Join(tlsDir, x509Filename(name)), tlsCertPath)
+	if err != nil {
+		return err
+	}
+
+	return exportTLSRootCerts(mspDir, tlsDir, tlsCA)
+}
+
+// RenewVerifyingMSP updates the CA certificates of the verifying MSP
+// previously generated in baseDir by GenerateVerifyingMSP. If the
+// certificates of previous CAs are given, they are kept in the MSP next to
+// the certificates of the current CAs.
+func RenewVerifyingMSP(baseDir string, signCA *ca.CA, tlsCA *ca.CA, prevSignCert, prevTLSCert *x509.Certificate) error {
+	return exportCACerts(baseDir, signCA, tlsCA, prevSignCert, prevTLSCert)
+}
+
+// exportCACerts writes the certificates of the signing and TLS CAs into the
+// cacerts and tlscacerts folders of mspDir. The certificates of the previous
+// CAs, if given and different from the current ones, are written next to them.
+func exportCACerts(mspDir string, signCA, tlsCA *ca.CA, prevSignCert, prevTLSCert *x509.Certificate) error {
+	for _, c := range []struct {
+		folder string
+		ca     *ca.CA
+		prev   *x509.Certificate
+	}{
+		{"cacerts", signCA, prevSignCert},
+		{"tlscacerts", tlsCA, prevTLSCert},
+	} {
+		if c.ca.SignCert == nil {
+			return fmt.Errorf("missing certificate for CA %s", c.ca.Name)
+		}
+		err := x509Export(filepath.Join(mspDir, c.folder, x509Filename(c.ca.Name)), c.ca.SignCert)
+		if err != nil {
+			return err
+		}
+		if c.prev == nil || c.prev.Equal(c.ca.SignCert) {
+			continue
+		}
+		err = x509Export(filepath.Join(mspDir, c.folder, previousX509Filename(c.ca.Name)), c.prev)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// exportTLSRootCerts writes the certificate of the TLS CA into ca.crt of
+// tlsDir, followed by the certificate of the previous TLS CA if the MSP
+// still holds one, so that TLS peers of both CAs are trusted.
+func exportTLSRootCerts(mspDir, tlsDir string, tlsCA *ca.CA) error {
+	prev, err := ioutil.ReadFile(filepath.Join(mspDir, "tlscacerts", previousX509Filename(tlsCA.Name)))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	file, err := os.Create(filepath.Join(tlsDir, "ca.crt"))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = pem.Encode(file, &pem.Block{Type: "CERTIFICATE", Bytes: tlsCA.SignCert.Raw})
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(prev)
+
+	return err
+}
+
+// loadECPublicKey returns the ECDSA public key of the PEM encoded
+// certificate at path
+func loadECPublicKey(path string) (*ecdsa.PublicKey, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(raw)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", path)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	pubKey, ok := cert.PublicKey.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("certificate %s does not hold an ECDSA public key", path)
+	}
+
+	return pubKey, nil
+}
+
 func createFolderStructure(rootDir string, local bool) error {
 
 	var folders []string
@@ -228,6 +429,12 @@ func x509Filename(name string) string {
 	return name + "-cert.pem"
 }
 
+// previousX509Filename returns the name of the file holding the certificate
+// of the CA which preceded the CA with the given name
+func previousX509Filename(name string) string {
+	return x509Filename(name + "-previous")
+}
+
 func x509Export(path string, cert *x509.Certificate) error {
 	return pemExport(path, "CERTIFICATE", cert.Raw)
 }
@@ -6,17 +6,21 @@ SPDX-License-Identifier: Apache-2.0
 package msp_test
 
 import (
+	"crypto/x509"
+	"encoding/pem"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/stretchr/testify/assert"
 	"gopkg.in/yaml.v2"
 
 	"github.com/hyperledger/fabric/common/tools/cryptogen/ca"
 	"github.com/hyperledger/fabric/common/tools/cryptogen/msp"
 	fabricmsp "github.com/hyperledger/fabric/msp"
+	mspprotos "github.com/hyperledger/fabric/protos/msp"
 )
 
 const (
@@ -172,6 +176,126 @@ func TestGenerateVerifyingMSP(t *testing.T) {
 	cleanup(testDir)
 }
 
+func TestRenewLocalMSP(t *testing.T) {
+	cleanup(testDir)
+	defer cleanup(testDir)
+
+	mspDir := filepath.Join(testDir, "msp")
+	tlsDir := filepath.Join(testDir, "tls")
+
+	signCA, err := ca.NewCA(filepath.Join(testDir, "ca"), testCAOrg, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	assert.NoError(t, err, "Error generating CA")
+	tlsCA, err := ca.NewCA(filepath.Join(testDir, "tlsca"), testCAOrg, "tls"+testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	assert.NoError(t, err, "Error generating CA")
+
+	nodeDir := testDir
+	err = msp.GenerateLocalMSP(nodeDir, testName, []string{"localhost"}, signCA, tlsCA, msp.PEER, true)
+	assert.NoError(t, err, "Failed to generate local MSP")
+
+	signCertFile := filepath.Join(mspDir, "signcerts", testName+"-cert.pem")
+	tlsCertFile := filepath.Join(tlsDir, "server.crt")
+	oldSignCert := loadCert(t, signCertFile)
+	oldTLSCert := loadCert(t, tlsCertFile)
+
+	// renew keeping the keys
+	err = msp.RenewLocalMSP(nodeDir, testName, []string{"localhost"}, signCA, tlsCA, nil, nil, msp.PEER, true, false)
+	assert.NoError(t, err, "Failed to renew local MSP")
+
+	signCert := loadCert(t, signCertFile)
+	tlsCert := loadCert(t, tlsCertFile)
+	assert.NotEqual(t, oldSignCert.SerialNumber, signCert.SerialNumber)
+	assert.Equal(t, oldSignCert.PublicKey, signCert.PublicKey)
+	assert.NoError(t, signCert.CheckSignatureFrom(signCA.SignCert))
+	assert.NotEqual(t, oldTLSCert.SerialNumber, tlsCert.SerialNumber)
+	assert.Equal(t, oldTLSCert.PublicKey, tlsCert.PublicKey)
+	assert.Equal(t, []string{"localhost"}, tlsCert.DNSNames)
+	assert.Equal(t, signCert, loadCert(t, filepath.Join(mspDir, "admincerts", testName+"-cert.pem")))
+	assertValidLocalMSP(t, mspDir)
+
+	// renew with new keys
+	err = msp.RenewLocalMSP(nodeDir, testName, []string{"localhost"}, signCA, tlsCA, nil, nil, msp.PEER, true, true)
+	assert.NoError(t, err, "Failed to renew local MSP")
+
+	rekeyedSignCert := loadCert(t, signCertFile)
+	assert.NotEqual(t, signCert.PublicKey, rekeyedSignCert.PublicKey)
+	assert.NotEqual(t, tlsCert.PublicKey, loadCert(t, tlsCertFile).PublicKey)
+	keys, err := ioutil.ReadDir(filepath.Join(mspDir, "keystore"))
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+	assertValidLocalMSP(t, mspDir)
+
+	// rotate the CAs
+	newSignCA, err := ca.NewCA(filepath.Join(testDir, "newca"), testCAOrg, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	assert.NoError(t, err, "Error generating CA")
+	newTLSCA, err := ca.NewCA(filepath.Join(testDir, "newtlsca"), testCAOrg, "tls"+testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	assert.NoError(t, err, "Error generating CA")
+
+	err = msp.RenewLocalMSP(nodeDir, testName, []string{"localhost"}, newSignCA, newTLSCA,
+		signCA.SignCert, tlsCA.SignCert, msp.PEER, true, false)
+	assert.NoError(t, err, "Failed to renew local MSP")
+
+	assert.NoError(t, loadCert(t, signCertFile).CheckSignatureFrom(newSignCA.SignCert))
+	assert.NoError(t, loadCert(t, tlsCertFile).CheckSignatureFrom(newTLSCA.SignCert))
+	assert.Equal(t, newSignCA.SignCert, loadCert(t, filepath.Join(mspDir, "cacerts", testCAName+"-cert.pem")))
+	assert.Equal(t, signCA.SignCert, loadCert(t, filepath.Join(mspDir, "cacerts", testCAName+"-previous-cert.pem")))
+	assert.Equal(t, newTLSCA.SignCert, loadCert(t, filepath.Join(mspDir, "tlscacerts", "tls"+testCAName+"-cert.pem")))
+	assert.Equal(t, tlsCA.SignCert, loadCert(t, filepath.Join(mspDir, "tlscacerts", "tls"+testCAName+"-previous-cert.pem")))
+	assertValidLocalMSP(t, mspDir)
+
+	// both TLS CAs are trusted
+	caPEM, err := ioutil.ReadFile(filepath.Join(tlsDir, "ca.crt"))
+	assert.NoError(t, err)
+	block, rest := pem.Decode(caPEM)
+	assert.Equal(t, newTLSCA.SignCert.Raw, block.Bytes)
+	block, _ = pem.Decode(rest)
+	assert.Equal(t, tlsCA.SignCert.Raw, block.Bytes)
+
+	// a missing certificate cannot be renewed
+	err = msp.RenewLocalMSP(filepath.Join(testDir, "missing"), testName, nil, signCA, tlsCA, nil, nil, msp.PEER, true, false)
+	assert.Error(t, err)
+}
+
+func TestRenewVerifyingMSP(t *testing.T) {
+	cleanup(testDir)
+	defer cleanup(testDir)
+
+	mspDir := filepath.Join(testDir, "msp")
+	signCA, err := ca.NewCA(filepath.Join(testDir, "ca"), testCAOrg, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	assert.NoError(t, err, "Error generating CA")
+	tlsCA, err := ca.NewCA(filepath.Join(testDir, "tlsca"), testCAOrg, "tls"+testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	assert.NoError(t, err, "Error generating CA")
+	err = msp.GenerateVerifyingMSP(mspDir, signCA, tlsCA, true)
+	assert.NoError(t, err, "Failed to generate verifying MSP")
+
+	// renewing under the same CAs does not add previous CA certificates
+	err = msp.RenewVerifyingMSP(mspDir, signCA, tlsCA, signCA.SignCert, tlsCA.SignCert)
+	assert.NoError(t, err, "Failed to renew verifying MSP")
+	assert.False(t, checkForFile(filepath.Join(mspDir, "cacerts", testCAName+"-previous-cert.pem")))
+
+	newSignCA, err := ca.NewCA(filepath.Join(testDir, "newca"), testCAOrg, testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	assert.NoError(t, err, "Error generating CA")
+	newTLSCA, err := ca.NewCA(filepath.Join(testDir, "newtlsca"), testCAOrg, "tls"+testCAName, testCountry, testProvince, testLocality, testOrganizationalUnit, testStreetAddress, testPostalCode)
+	assert.NoError(t, err, "Error generating CA")
+
+	err = msp.RenewVerifyingMSP(mspDir, newSignCA, newTLSCA, signCA.SignCert, tlsCA.SignCert)
+	assert.NoError(t, err, "Failed to renew verifying MSP")
+	assert.Equal(t, newSignCA.SignCert, loadCert(t, filepath.Join(mspDir, "cacerts", testCAName+"-cert.pem")))
+	assert.Equal(t, signCA.SignCert, loadCert(t, filepath.Join(mspDir, "cacerts", testCAName+"-previous-cert.pem")))
+	assert.Equal(t, newTLSCA.SignCert, loadCert(t, filepath.Join(mspDir, "tlscacerts", "tls"+testCAName+"-cert.pem")))
+	assert.Equal(t, tlsCA.SignCert, loadCert(t, filepath.Join(mspDir, "tlscacerts", "tls"+testCAName+"-previous-cert.pem")))
+
+	testMSPConfig, err := fabricmsp.GetVerifyingMspConfig(mspDir, testName, fabricmsp.ProviderTypeToString(fabricmsp.FABRIC))
+	assert.NoError(t, err, "Error parsing verifying MSP config")
+	fabricMSPConfig := &mspprotos.FabricMSPConfig{}
+	err = proto.Unmarshal(testMSPConfig.Config, fabricMSPConfig)
+	assert.NoError(t, err)
+	assert.Len(t, fabricMSPConfig.RootCerts, 2)
+	assert.Len(t, fabricMSPConfig.TlsRootCerts, 2)
+
+	err = msp.RenewVerifyingMSP(mspDir, &ca.CA{}, tlsCA, nil, nil)
+	assert.Error(t, err, "Empty CA should have failed")
+}
+
 func TestExportConfig(t *testing.T) {
 	path := filepath.Join(testDir, "export-test")
 	configFile := filepath.Join(path, "config.yaml")
@@ -202,6 +326,28 @@ func TestExportConfig(t *testing.T) {
 	assert.Equal(t, msp.PEEROU, config.NodeOUs.PeerOUIdentifier.OrganizationalUnitIdentifier)
 }
 
+func loadCert(t *testing.T, file string) *x509.Certificate {
+	raw, err := ioutil.ReadFile(file)
+	if err != nil {
+		t.Fatalf("failed to read certificate: [%s]", err)
+	}
+	block, _ := pem.Decode(raw)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatalf("failed to parse certificate: [%s]", err)
+	}
+	return cert
+}
+
+func assertValidLocalMSP(t *testing.T, mspDir string) {
+	testMSPConfig, err := fabricmsp.GetLocalMspConfig(mspDir, nil, testName)
+	assert.NoError(t, err, "Error parsing local MSP config")
+	testMSP, err := fabricmsp.New(&fabricmsp.BCCSPNewOpts{NewBaseOpts: fabricmsp.NewBaseOpts{Version: fabricmsp.MSPv1_0}})
+	assert.NoError(t, err, "Error creating new BCCSP MSP")
+	err = testMSP.Setup(testMSPConfig)
+	assert.NoError(t, err, "Error setting up local MSP")
+}
+
 func cleanup(dir string) {
 	os.RemoveAll(dir)
 }
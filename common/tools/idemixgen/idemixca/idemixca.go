@@ -7,6 +7,10 @@ SPDX-License-Identifier: Apache-2.0
 package idemixca
 
 import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
+
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/idemix"
 	"github.com/hyperledger/fabric/msp"
@@ -16,16 +20,17 @@ import (
 )
 
 // GenerateIssuerKey invokes Idemix library to generate an issuer (CA) signing key pair
-// currently two attributes are supported by the issuer:
+// currently three attributes are supported by the issuer:
 // AttributeNameOU is the organization unit name
 // AttributeNameRole is the role (member or admin) name
+// AttributeNameRevocationHandle is the revocation handle of the credential
 // Generated keys are serialized to bytes
 func GenerateIssuerKey() ([]byte, []byte, error) {
 	rng, err := idemix.GetRand()
 	if err != nil {
 		return nil, nil, err
 	}
-	AttributeNames := []string{msp.AttributeNameOU, msp.AttributeNameRole}
+	AttributeNames := []string{msp.AttributeNameOU, msp.AttributeNameRole, msp.AttributeNameRevocationHandle}
 	key, err := idemix.NewIssuerKey(AttributeNames, rng)
 	if err != nil {
 		return nil, nil, errors.WithMessage(err, "cannot generate CA key")
@@ -35,16 +40,87 @@ func GenerateIssuerKey() ([]byte, []byte, error) {
 	return key.ISk, ipkSerialized, err
 }
 
+// GenerateRevocationKey generates the long term key pair of the revocation authority
+// The private key is PEM encoded, the public key is PEM encoded in PKIX format
+func GenerateRevocationKey() ([]byte, []byte, error) {
+	key, err := idemix.GenerateLongTermRevocationKey()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "cannot generate revocation key")
+	}
+	skBytes, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to marshal revocation secret key")
+	}
+	pkBytes, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to marshal revocation public key")
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: skBytes}),
+		pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkBytes}), nil
+}
+
+// ParseRevocationKey parses the PEM encoded revocation secret key
+func ParseRevocationKey(pemBytes []byte) (*ecdsa.PrivateKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("failed to decode PEM block of the revocation secret key")
+	}
+	key, err := x509.ParseECPrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse revocation secret key")
+	}
+	return key, nil
+}
+
+// GenerateCRI creates the serialized credential revocation information with which
+// the credential with the given revocation handle proves that it is not revoked in epoch
+func GenerateCRI(revocationHandle *FP256BN.BIG, revKey *ecdsa.PrivateKey, epoch int) ([]byte, error) {
+	rng, err := idemix.GetRand()
+	if err != nil {
+		return nil, errors.WithMessage(err, "Error getting PRNG")
+	}
+	cri, err := idemix.CreateCRI(revKey, []*FP256BN.BIG{revocationHandle}, epoch, idemix.ALG_WBB_SIGNATURE, rng)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create credential revocation information")
+	}
+	return proto.Marshal(cri)
+}
+
+// UpdateSignerCRI replaces the credential revocation information of a serialized
+// signer config with the one for the given epoch
+func UpdateSignerCRI(signerBytes []byte, revKey *ecdsa.PrivateKey, epoch int) ([]byte, error) {
+	signer := &m.IdemixMSPSignerConfig{}
+	err := proto.Unmarshal(signerBytes, signer)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal signer config")
+	}
+	cred := &idemix.Credential{}
+	err = proto.Unmarshal(signer.Cred, cred)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal credential")
+	}
+	if len(cred.Attrs) != 3 {
+		return nil, errors.Errorf("the credential has no revocation handle")
+	}
+
+	signer.CredentialRevocationInformation, err = GenerateCRI(FP256BN.FromBytes(cred.Attrs[2]), revKey, epoch)
+	if err != nil {
+		return nil, err
+	}
+	return proto.Marshal(signer)
+}
+
 // GenerateMSPConfig creates a new MSP config
 // If the new MSP config contains a signer then
 // it generates a fresh user secret and issues a credential
-// with two attributes (described above)
+// with three attributes (described above)
 // using the CA's key pair from the file
+// and the credential revocation information for the given epoch
 // If the new MSP config does not contain a signer
 // (meaning it is used only for verification)
 // then only a public key of the CA (issuer) is added to the MSP config (besides the name)
-func GenerateSignerConfig(isAdmin bool, ouString string, key *idemix.IssuerKey) ([]byte, error) {
-	attrs := make([]*FP256BN.BIG, 2)
+func GenerateSignerConfig(isAdmin bool, ouString string, revocationHandle int, key *idemix.IssuerKey, revKey *ecdsa.PrivateKey, epoch int) ([]byte, error) {
+	attrs := make([]*FP256BN.BIG, 3)
 
 	if ouString == "" {
 		return nil, errors.Errorf("the OU attribute value is empty")
@@ -58,6 +134,7 @@ func GenerateSignerConfig(isAdmin bool, ouString string, key *idemix.IssuerKey)
 
 	attrs[0] = idemix.HashModOrder([]byte(ouString))
 	attrs[1] = FP256BN.NewBIGint(int(role))
+	attrs[2] = FP256BN.NewBIGint(revocationHandle)
 
 	rng, err := idemix.GetRand()
 	if err != nil {
@@ -78,11 +155,17 @@ func GenerateSignerConfig(isAdmin bool, ouString string, key *idemix.IssuerKey)
 		return nil, errors.WithMessage(err, "failed to marshal credential")
 	}
 
+	criBytes, err := GenerateCRI(attrs[2], revKey, epoch)
+	if err != nil {
+		return nil, err
+	}
+
 	signer := &m.IdemixMSPSignerConfig{
-		credBytes,
-		idemix.BigToBytes(sk),
-		ouString,
-		isAdmin,
+		Cred:                            credBytes,
+		Sk:                              idemix.BigToBytes(sk),
+		OrganizationalUnitIdentifier:    ouString,
+		IsAdmin:                         isAdmin,
+		CredentialRevocationInformation: criBytes,
 	}
 	return proto.Marshal(signer)
 }
@@ -32,27 +32,46 @@ func TestIdemixCa(t *testing.T) {
 	err = proto.Unmarshal(ipkBytes, ipk)
 	assert.NoError(t, err)
 
-	writeVerifierToFile(ipkBytes)
+	revocationKeyBytes, revocationPkBytes, err := GenerateRevocationKey()
+	assert.NoError(t, err)
+	revocationKey, err := ParseRevocationKey(revocationKeyBytes)
+	assert.NoError(t, err)
+	_, err = ParseRevocationKey(revocationPkBytes)
+	assert.Error(t, err)
+
+	writeVerifierToFile(ipkBytes, revocationPkBytes, "0")
 
 	key := &idemix.IssuerKey{isk, ipk}
 
-	conf, err := GenerateSignerConfig(false, "OU1", key)
+	conf, err := GenerateSignerConfig(false, "OU1", 1, key, revocationKey, 0)
+	assert.NoError(t, err)
+	cleanupSigner()
+	assert.NoError(t, writeSignerToFile(conf))
+	assert.NoError(t, setupMSP())
+
+	conf, err = GenerateSignerConfig(true, "OU1", 2, key, revocationKey, 0)
 	assert.NoError(t, err)
 	cleanupSigner()
 	assert.NoError(t, writeSignerToFile(conf))
 	assert.NoError(t, setupMSP())
 
-	conf, err = GenerateSignerConfig(true, "OU1", key)
+	// The verifiers moved on to a new epoch, the signer needs a new CRI
+	assert.NoError(t, ioutil.WriteFile(filepath.Join(testDir, m.IdemixConfigDirMsp, m.IdemixConfigFileRevocationEpoch), []byte("1"), 0644))
+	assert.Error(t, setupMSP())
+	conf, err = UpdateSignerCRI(conf, revocationKey, 1)
 	assert.NoError(t, err)
 	cleanupSigner()
 	assert.NoError(t, writeSignerToFile(conf))
 	assert.NoError(t, setupMSP())
 
+	_, err = UpdateSignerCRI([]byte("barf"), revocationKey, 1)
+	assert.Error(t, err)
+
 	// Without the verifier dir present, setup should give an error
 	cleanupVerifier()
 	assert.Error(t, setupMSP())
 
-	_, err = GenerateSignerConfig(true, "", key)
+	_, err = GenerateSignerConfig(true, "", 1, key, revocationKey, 0)
 	assert.EqualError(t, err, "the OU attribute value is empty")
 }
 
@@ -73,11 +92,19 @@ func cleanupVerifier() {
 	os.RemoveAll(filepath.Join(testDir, m.IdemixConfigDirMsp))
 }
 
-func writeVerifierToFile(ipkBytes []byte) error {
+func writeVerifierToFile(ipkBytes []byte, revocationPkBytes []byte, epoch string) error {
 	err := os.Mkdir(filepath.Join(testDir, m.IdemixConfigDirMsp), os.ModePerm)
 	if err != nil {
 		return err
 	}
+	err = ioutil.WriteFile(filepath.Join(testDir, m.IdemixConfigDirMsp, m.IdemixConfigFileRevocationPublicKey), revocationPkBytes, 0644)
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(filepath.Join(testDir, m.IdemixConfigDirMsp, m.IdemixConfigFileRevocationEpoch), []byte(epoch), 0644)
+	if err != nil {
+		return err
+	}
 	return ioutil.WriteFile(filepath.Join(testDir, m.IdemixConfigDirMsp, m.IdemixConfigFileIssuerPublicKey), ipkBytes, 0644)
 }
 
@@ -90,7 +117,7 @@ func writeSignerToFile(signerBytes []byte) error {
 }
 
 // setupMSP tests whether we can successfully setup an idemix msp
-// with the generated config bytes
+// with the generated config bytes, and whether its default signer is valid
 func setupMSP() error {
 	// setup an idemix msp from the test directory
 	msp, err := m.New(&m.IdemixNewOpts{NewBaseOpts: m.NewBaseOpts{Version: m.MSPv1_1}})
@@ -98,6 +125,17 @@ func setupMSP() error {
 		return errors.Wrap(err, "Getting MSP failed")
 	}
 	mspConfig, err := m.GetIdemixMspConfig(testDir, "TestName")
+	if err != nil {
+		return errors.Wrap(err, "Getting MSP config failed")
+	}
 
-	return msp.Setup(mspConfig)
+	err = msp.Setup(mspConfig)
+	if err != nil {
+		return err
+	}
+	signer, err := msp.GetDefaultSigningIdentity()
+	if err != nil {
+		return err
+	}
+	return signer.Validate()
 }
@@ -12,10 +12,12 @@ package main
 // the Identity Mixer MSP
 
 import (
+	"crypto/ecdsa"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/tools/idemixgen/idemixca"
@@ -29,6 +31,7 @@ import (
 const (
 	IdemixDirIssuer             = "ca"
 	IdemixConfigIssuerSecretKey = "IssuerSecretKey"
+	IdemixConfigRevocationKey   = "RevocationKey"
 )
 
 // command line flags
@@ -39,6 +42,10 @@ var (
 	genSignerConfig = app.Command("signerconfig", "Generate a default signer for this Idemix MSP")
 	genCredOU       = genSignerConfig.Flag("org-unit", "The Organizational Unit of the default signer").Short('u').String()
 	genCredIsAdmin  = genSignerConfig.Flag("admin", "Make the default signer admin").Short('a').Bool()
+	genCredRH       = genSignerConfig.Flag("revocation-handle", "The revocation handle of the default signer").Short('r').Default("1").Int()
+	genCredEpoch    = genSignerConfig.Flag("epoch", "The revocation epoch of the credential revocation information of the default signer").Short('e').Default("0").Int()
+	genCRI          = app.Command("cri", "Generate the credential revocation information of the default signer for a new revocation epoch")
+	genCRIEpoch     = genCRI.Flag("epoch", "The new revocation epoch").Short('e').Required().Int()
 
 	version = app.Command("version", "Show version information")
 )
@@ -51,6 +58,8 @@ func main() {
 	case genIssuerKey.FullCommand():
 		isk, ipk, err := idemixca.GenerateIssuerKey()
 		handleError(err)
+		revocationKey, revocationPk, err := idemixca.GenerateRevocationKey()
+		handleError(err)
 
 		// Prevent overwriting the existing key
 		path := filepath.Join(IdemixDirIssuer)
@@ -65,9 +74,13 @@ func main() {
 		writeFile(filepath.Join(IdemixDirIssuer, IdemixConfigIssuerSecretKey), isk)
 		writeFile(filepath.Join(IdemixDirIssuer, msp.IdemixConfigFileIssuerPublicKey), ipk)
 		writeFile(filepath.Join(msp.IdemixConfigDirMsp, msp.IdemixConfigFileIssuerPublicKey), ipk)
+		writeFile(filepath.Join(IdemixDirIssuer, IdemixConfigRevocationKey), revocationKey)
+		writeFile(filepath.Join(IdemixDirIssuer, msp.IdemixConfigFileRevocationPublicKey), revocationPk)
+		writeFile(filepath.Join(msp.IdemixConfigDirMsp, msp.IdemixConfigFileRevocationPublicKey), revocationPk)
+		writeFile(filepath.Join(msp.IdemixConfigDirMsp, msp.IdemixConfigFileRevocationEpoch), []byte("0"))
 
 	case genSignerConfig.FullCommand():
-		config, err := idemixca.GenerateSignerConfig(*genCredIsAdmin, *genCredOU, readIssuerKey())
+		config, err := idemixca.GenerateSignerConfig(*genCredIsAdmin, *genCredOU, *genCredRH, readIssuerKey(), readRevocationKey(), *genCredEpoch)
 		handleError(err)
 
 		path := msp.IdemixConfigDirUser
@@ -77,6 +90,19 @@ func main() {
 		handleError(os.Mkdir(msp.IdemixConfigDirUser, 0770))
 		writeFile(filepath.Join(msp.IdemixConfigDirUser, msp.IdemixConfigFileSigner), config)
 
+	case genCRI.FullCommand():
+		path := filepath.Join(msp.IdemixConfigDirUser, msp.IdemixConfigFileSigner)
+		signerBytes, err := ioutil.ReadFile(path)
+		if err != nil {
+			handleError(errors.Wrapf(err, "failed to open signer config file: %s", path))
+		}
+		config, err := idemixca.UpdateSignerCRI(signerBytes, readRevocationKey(), *genCRIEpoch)
+		handleError(err)
+
+		// Write the updated config and the new epoch the verifiers expect
+		writeFile(path, config)
+		writeFile(filepath.Join(msp.IdemixConfigDirMsp, msp.IdemixConfigFileRevocationEpoch), []byte(strconv.Itoa(*genCRIEpoch)))
+
 	case version.FullCommand():
 		printVersion()
 	}
@@ -110,6 +136,19 @@ func readIssuerKey() *idemix.IssuerKey {
 	return key
 }
 
+// readRevocationKey reads the revocation secret key from the current directory
+func readRevocationKey() *ecdsa.PrivateKey {
+	path := filepath.Join(IdemixDirIssuer, IdemixConfigRevocationKey)
+	keyBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+		handleError(errors.Wrapf(err, "failed to open revocation secret key file: %s", path))
+	}
+	key, err := idemixca.ParseRevocationKey(keyBytes)
+	handleError(err)
+
+	return key
+}
+
 // checkDirectoryNotExists checks whether a directory with the given path already exists and exits if this is the case
 func checkDirectoryNotExists(path string, errorMessage string) {
 	_, err := os.Stat(path)
@@ -74,6 +74,22 @@ func (d *defaultACLProvider) initialize() {
 	d.cResourcePolicyMap[resources.QSCC_GetBlockByHash] = CHANNELREADERS
 	d.cResourcePolicyMap[resources.QSCC_GetTransactionByID] = CHANNELREADERS
 	d.cResourcePolicyMap[resources.QSCC_GetBlockByTxID] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.QSCC_GetMissingPvtDataInfo] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.QSCC_GetBlocksByRange] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.QSCC_GetBlockHeadersByRange] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.QSCC_GetTransactionsInfoByBlockNumber] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.QSCC_GetTransactionsByChaincode] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.QSCC_GetTransactionsByCreator] = CHANNELREADERS
+
+	//------------ _lifecycle ------------
+	//p resources (none)
+
+	//c resources
+	d.cResourcePolicyMap[resources.Lifecycle_ApproveChaincodeDefinitionForMyOrg] = CHANNELWRITERS
+	d.cResourcePolicyMap[resources.Lifecycle_CheckCommitReadiness] = CHANNELWRITERS
+	d.cResourcePolicyMap[resources.Lifecycle_CommitChaincodeDefinition] = CHANNELWRITERS
+	d.cResourcePolicyMap[resources.Lifecycle_QueryChaincodeDefinition] = CHANNELREADERS
+	d.cResourcePolicyMap[resources.Lifecycle_QueryChaincodeDefinitions] = CHANNELREADERS
 
 	//--------------- CSCC resources -----------
 	//p resources (implemented by the chaincode currently)
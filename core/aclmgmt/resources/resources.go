@@ -24,11 +24,24 @@ const (
 	LSCC_GETINSTALLEDCHAINCODES = "LSCC.GETINSTALLEDCHAINCODES"
 
 	//QSCC resources
-	QSCC_GetChainInfo       = "QSCC.GetChainInfo"
-	QSCC_GetBlockByNumber   = "QSCC.GetBlockByNumber"
-	QSCC_GetBlockByHash     = "QSCC.GetBlockByHash"
-	QSCC_GetTransactionByID = "QSCC.GetTransactionByID"
-	QSCC_GetBlockByTxID     = "QSCC.GetBlockByTxID"
+	QSCC_GetChainInfo                     = "QSCC.GetChainInfo"
+	QSCC_GetBlockByNumber                 = "QSCC.GetBlockByNumber"
+	QSCC_GetBlockByHash                   = "QSCC.GetBlockByHash"
+	QSCC_GetTransactionByID               = "QSCC.GetTransactionByID"
+	QSCC_GetBlockByTxID                   = "QSCC.GetBlockByTxID"
+	QSCC_GetMissingPvtDataInfo            = "QSCC.GetMissingPvtDataInfo"
+	QSCC_GetBlocksByRange                 = "QSCC.GetBlocksByRange"
+	QSCC_GetBlockHeadersByRange           = "QSCC.GetBlockHeadersByRange"
+	QSCC_GetTransactionsInfoByBlockNumber = "QSCC.GetTransactionsInfoByBlockNumber"
+	QSCC_GetTransactionsByChaincode       = "QSCC.GetTransactionsByChaincode"
+	QSCC_GetTransactionsByCreator         = "QSCC.GetTransactionsByCreator"
+
+	//_lifecycle resources
+	Lifecycle_ApproveChaincodeDefinitionForMyOrg = "_lifecycle.ApproveChaincodeDefinitionForMyOrg"
+	Lifecycle_CheckCommitReadiness               = "_lifecycle.CheckCommitReadiness"
+	Lifecycle_CommitChaincodeDefinition          = "_lifecycle.CommitChaincodeDefinition"
+	Lifecycle_QueryChaincodeDefinition           = "_lifecycle.QueryChaincodeDefinition"
+	Lifecycle_QueryChaincodeDefinitions          = "_lifecycle.QueryChaincodeDefinitions"
 
 	//CSCC resources
 	CSCC_JoinChain                = "CSCC.JoinChain"
@@ -17,6 +17,7 @@ limitations under the License.
 package chaincode
 
 import (
+	"bytes"
 	"fmt"
 	"io"
 	"path/filepath"
@@ -615,6 +616,44 @@ func (chaincodeSupport *ChaincodeSupport) Stop(context context.Context, cccid *c
 	return err
 }
 
+// launchDeploymentSpec returns the deployment spec from which the chaincode is
+// launched. A chaincode defined through the lifecycle system chaincode is
+// launched from the package installed for the version being invoked, while
+// lscc returns the deployment spec of a chaincode it instantiated.
+func launchDeploymentSpec(context context.Context, cccid *ccprovider.CCContext, name string) (*pb.ChaincodeDeploymentSpec, error) {
+	canName := cccid.GetCanonicalName()
+	def, err := getLifecycleDefinition(context, name)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("could not get definition of %s", canName))
+	}
+	if def != nil {
+		ccpack, err := ccprovider.GetChaincodeFromFS(name, cccid.Version)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("could not get installed package of %s", canName))
+		}
+		return ccpack.GetDepSpec(), nil
+	}
+
+	//hopefully we are restarting from existing image and the deployed transaction exists
+	//(this will also validate the ID from the LSCC if we're not using the config-tree approach)
+	depPayload, err := GetCDS(context, cccid.TxID, cccid.SignedProposal, cccid.Proposal, cccid.ChainID, name)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("could not get ChaincodeDeploymentSpec for %s", canName))
+	}
+	if depPayload == nil {
+		return nil, errors.Errorf("nil ChaincodeDeploymentSpec for %s", canName)
+	}
+
+	cds := &pb.ChaincodeDeploymentSpec{}
+
+	//Get lang from original deployment
+	err = proto.Unmarshal(depPayload, cds)
+	if err != nil {
+		return nil, errors.Wrap(err, fmt.Sprintf("failed to unmarshal deployment transactions for %s", canName))
+	}
+	return cds, nil
+}
+
 // Launch will launch the chaincode if not running (if running return nil) and will wait for handler of the chaincode to get into FSM ready state.
 func (chaincodeSupport *ChaincodeSupport) Launch(context context.Context, cccid *ccprovider.CCContext, spec interface{}) (*pb.ChaincodeID, *pb.ChaincodeInput, error) {
 	//build the chaincode
@@ -679,24 +718,9 @@ func (chaincodeSupport *ChaincodeSupport) Launch(context context.Context, cccid
 			chaincodeLogger.Error("You are attempting to perform an action other than Deploy on Chaincode that is not ready and you are in developer mode. Did you forget to Deploy your chaincode?")
 		}
 
-		var depPayload []byte
-
-		//hopefully we are restarting from existing image and the deployed transaction exists
-		//(this will also validate the ID from the LSCC if we're not using the config-tree approach)
-		depPayload, err = GetCDS(context, cccid.TxID, cccid.SignedProposal, cccid.Proposal, cccid.ChainID, cID.Name)
-		if err != nil {
-			return cID, cMsg, errors.WithMessage(err, fmt.Sprintf("could not get ChaincodeDeploymentSpec for %s", canName))
-		}
-		if depPayload == nil {
-			return cID, cMsg, errors.WithMessage(err, fmt.Sprintf("nil ChaincodeDeploymentSpec for %s", canName))
-		}
-
-		cds = &pb.ChaincodeDeploymentSpec{}
-
-		//Get lang from original deployment
-		err = proto.Unmarshal(depPayload, cds)
+		cds, err = launchDeploymentSpec(context, cccid, cID.Name)
 		if err != nil {
-			return cID, cMsg, errors.Wrap(err, fmt.Sprintf("failed to unmarshal deployment transactions for %s", canName))
+			return cID, cMsg, err
 		}
 	}
 
@@ -727,6 +751,11 @@ func (chaincodeSupport *ChaincodeSupport) Launch(context context.Context, cccid
 		}
 
 		builder := func() (io.Reader, error) { return platforms.GenerateDockerBuild(cds) }
+		if cds.ExecEnv == pb.ChaincodeDeploymentSpec_EXTERNAL {
+			//external chaincode is not built, the VM reads the connection
+			//information from the code package instead
+			builder = func() (io.Reader, error) { return bytes.NewReader(cds.CodePackage), nil }
+		}
 
 		err = chaincodeSupport.launchAndWaitForRegister(context, cccid, cds, &ccLauncherImpl{context, chaincodeSupport, cccid, cds, builder})
 		if err != nil {
@@ -757,8 +786,11 @@ func (chaincodeSupport *ChaincodeSupport) Launch(context context.Context, cccid
 //getVMType - just returns a string for now. Another possibility is to use a factory method to
 //return a VM executor
 func (chaincodeSupport *ChaincodeSupport) getVMType(cds *pb.ChaincodeDeploymentSpec) (string, error) {
-	if cds.ExecEnv == pb.ChaincodeDeploymentSpec_SYSTEM {
+	switch cds.ExecEnv {
+	case pb.ChaincodeDeploymentSpec_SYSTEM:
 		return container.SYSTEM, nil
+	case pb.ChaincodeDeploymentSpec_EXTERNAL:
+		return container.EXTERNAL, nil
 	}
 	return container.DOCKER, nil
 }
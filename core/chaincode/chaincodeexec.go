@@ -24,6 +24,7 @@ import (
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/lifecycle"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/pkg/errors"
 	"golang.org/x/net/context"
@@ -54,8 +55,29 @@ func GetCDS(ctxt context.Context, txid string, signedProp *pb.SignedProposal, pr
 	return res.Payload, nil
 }
 
-// GetChaincodeDefinition returns resourcesconfig.ChaincodeDefinition for the chaincode with the supplied name
+// getLifecycleDefinition returns the definition of the chaincode committed
+// to the namespace of the lifecycle system chaincode, or nil if it has none.
+// The definition is read through the transaction simulator of the context,
+// so that the transaction depends on it.
+func getLifecycleDefinition(ctxt context.Context, chaincodeID string) (*lifecycle.Definition, error) {
+	txsim := getTxSimulator(ctxt)
+	if txsim == nil {
+		return nil, nil
+	}
+	return lifecycle.QueryDefinition(txsim, chaincodeID)
+}
+
+// GetChaincodeDefinition returns resourcesconfig.ChaincodeDefinition for the chaincode with the supplied name.
+// The definition committed through the lifecycle system chaincode takes precedence over the chaincode data of lscc.
 func GetChaincodeDefinition(ctxt context.Context, txid string, signedProp *pb.SignedProposal, prop *pb.Proposal, chainID string, chaincodeID string) (resourcesconfig.ChaincodeDefinition, error) {
+	def, err := getLifecycleDefinition(ctxt, chaincodeID)
+	if err != nil {
+		return nil, err
+	}
+	if def != nil {
+		return def, nil
+	}
+
 	version := util.GetSysCCVersion()
 	cccid := ccprovider.NewCCContext(chainID, "lscc", version, txid, true, signedProp, prop)
 	res, _, err := ExecuteChaincode(ctxt, cccid, [][]byte{[]byte("getccdata"), []byte(chainID), []byte(chaincodeID)})
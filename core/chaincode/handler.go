@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"sort"
 	"sync"
 	"time"
 
@@ -57,6 +58,9 @@ type transactionContext struct {
 type pendingQueryResult struct {
 	batch []*pb.QueryResultBytes
 	count int
+	// paginated queries return their whole page in a single response,
+	// along with the metadata needed to request the next page
+	paginated bool
 }
 
 type nextStateInfo struct {
@@ -215,11 +219,11 @@ func (handler *Handler) deleteTxContext(chainID, txid string) {
 }
 
 func (handler *Handler) initializeQueryContext(txContext *transactionContext, queryID string,
-	queryIterator commonledger.ResultsIterator) {
+	queryIterator commonledger.ResultsIterator, paginated bool) {
 	handler.Lock()
 	defer handler.Unlock()
 	txContext.queryIteratorMap[queryID] = queryIterator
-	txContext.pendingQueryResults[queryID] = &pendingQueryResult{batch: make([]*pb.QueryResultBytes, 0)}
+	txContext.pendingQueryResults[queryID] = &pendingQueryResult{batch: make([]*pb.QueryResultBytes, 0), paginated: paginated}
 }
 
 func (handler *Handler) getQueryIterator(txContext *transactionContext, queryID string) commonledger.ResultsIterator {
@@ -420,9 +424,11 @@ func newChaincodeSupportHandler(chaincodeSupport *ChaincodeSupport, peerChatStre
 			{Name: pb.ChaincodeMessage_READY.String(), Src: []string{establishedstate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_PUT_STATE.String(), Src: []string{readystate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_DEL_STATE.String(), Src: []string{readystate}, Dst: readystate},
+			{Name: pb.ChaincodeMessage_PUT_STATE_METADATA.String(), Src: []string{readystate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_INVOKE_CHAINCODE.String(), Src: []string{readystate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_COMPLETED.String(), Src: []string{readystate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_GET_STATE.String(), Src: []string{readystate}, Dst: readystate},
+			{Name: pb.ChaincodeMessage_GET_STATE_METADATA.String(), Src: []string{readystate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_GET_STATE_BY_RANGE.String(), Src: []string{readystate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_GET_QUERY_RESULT.String(), Src: []string{readystate}, Dst: readystate},
 			{Name: pb.ChaincodeMessage_GET_HISTORY_FOR_KEY.String(), Src: []string{readystate}, Dst: readystate},
@@ -437,6 +443,7 @@ func newChaincodeSupportHandler(chaincodeSupport *ChaincodeSupport, peerChatStre
 			"before_" + pb.ChaincodeMessage_REGISTER.String():           func(e *fsm.Event) { v.beforeRegisterEvent(e, v.FSM.Current()) },
 			"before_" + pb.ChaincodeMessage_COMPLETED.String():          func(e *fsm.Event) { v.beforeCompletedEvent(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_GET_STATE.String():           func(e *fsm.Event) { v.afterGetState(e, v.FSM.Current()) },
+			"after_" + pb.ChaincodeMessage_GET_STATE_METADATA.String():  func(e *fsm.Event) { v.afterGetStateMetadata(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_GET_STATE_BY_RANGE.String():  func(e *fsm.Event) { v.afterGetStateByRange(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_GET_QUERY_RESULT.String():    func(e *fsm.Event) { v.afterGetQueryResult(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_GET_HISTORY_FOR_KEY.String(): func(e *fsm.Event) { v.afterGetHistoryForKey(e, v.FSM.Current()) },
@@ -444,6 +451,7 @@ func newChaincodeSupportHandler(chaincodeSupport *ChaincodeSupport, peerChatStre
 			"after_" + pb.ChaincodeMessage_QUERY_STATE_CLOSE.String():   func(e *fsm.Event) { v.afterQueryStateClose(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_PUT_STATE.String():           func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_DEL_STATE.String():           func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
+			"after_" + pb.ChaincodeMessage_PUT_STATE_METADATA.String():  func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
 			"after_" + pb.ChaincodeMessage_INVOKE_CHAINCODE.String():    func(e *fsm.Event) { v.enterBusyState(e, v.FSM.Current()) },
 			"enter_" + establishedstate:                                 func(e *fsm.Event) { v.enterEstablishedState(e, v.FSM.Current()) },
 			"enter_" + readystate:                                       func(e *fsm.Event) { v.enterReadyState(e, v.FSM.Current()) },
@@ -665,6 +673,92 @@ func (handler *Handler) handleGetState(msg *pb.ChaincodeMessage) {
 	}()
 }
 
+// afterGetStateMetadata handles a GET_STATE_METADATA request from the chaincode.
+func (handler *Handler) afterGetStateMetadata(e *fsm.Event, state string) {
+	msg, ok := e.Args[0].(*pb.ChaincodeMessage)
+	if !ok {
+		e.Cancel(errors.New("received unexpected message type"))
+		return
+	}
+	chaincodeLogger.Debugf("[%s]Received %s, invoking get state metadata from ledger", shorttxid(msg.Txid), pb.ChaincodeMessage_GET_STATE_METADATA)
+
+	// Query ledger for the metadata of the key
+	handler.handleGetStateMetadata(msg)
+}
+
+// Handles query to ledger to get the metadata of a key
+func (handler *Handler) handleGetStateMetadata(msg *pb.ChaincodeMessage) {
+	go func() {
+		// Check if this is the unique state request from this chaincode txid
+		uniqueReq := handler.createTXIDEntry(msg.ChannelId, msg.Txid)
+		if !uniqueReq {
+			// Drop this request
+			chaincodeLogger.Error("Another state request pending for this Txid. Cannot process.")
+			return
+		}
+
+		var serialSendMsg *pb.ChaincodeMessage
+		var txContext *transactionContext
+		txContext, serialSendMsg = handler.isValidTxSim(msg.ChannelId, msg.Txid,
+			"[%s]No ledger context for GetStateMetadata. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+
+		defer func() {
+			handler.deleteTXIDEntry(msg.ChannelId, msg.Txid)
+			chaincodeLogger.Debugf("[%s]handleGetStateMetadata serial send %s",
+				shorttxid(serialSendMsg.Txid), serialSendMsg.Type)
+			handler.serialSendAsync(serialSendMsg, nil)
+		}()
+
+		if txContext == nil {
+			return
+		}
+
+		errHandler := func(err error, errFmt string, errArgs ...interface{}) {
+			chaincodeLogger.Errorf(errFmt, errArgs...)
+			serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_ERROR, Payload: []byte(err.Error()), Txid: msg.Txid, ChannelId: msg.ChannelId}
+		}
+
+		getStateMetadata := &pb.GetStateMetadata{}
+		if err := proto.Unmarshal(msg.Payload, getStateMetadata); err != nil {
+			errHandler(err, "[%s]Unable to decipher payload. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+			return
+		}
+		chaincodeID := handler.getCCRootName()
+		chaincodeLogger.Debugf("[%s] getting state metadata for chaincode %s, key %s, channel %s",
+			shorttxid(msg.Txid), chaincodeID, getStateMetadata.Key, txContext.chainID)
+
+		var metadata map[string][]byte
+		var err error
+		if isCollectionSet(getStateMetadata.Collection) {
+			metadata, err = txContext.txsimulator.GetPrivateDataMetadata(chaincodeID, getStateMetadata.Collection, getStateMetadata.Key)
+		} else {
+			metadata, err = txContext.txsimulator.GetStateMetadata(chaincodeID, getStateMetadata.Key)
+		}
+		if err != nil {
+			errHandler(err, "[%s]Failed to get chaincode state metadata(%s). Sending %s", shorttxid(msg.Txid), err, pb.ChaincodeMessage_ERROR)
+			return
+		}
+
+		var metakeys []string
+		for metakey := range metadata {
+			metakeys = append(metakeys, metakey)
+		}
+		sort.Strings(metakeys)
+		metadataResult := &pb.StateMetadataResult{}
+		for _, metakey := range metakeys {
+			metadataResult.Entries = append(metadataResult.Entries, &pb.StateMetadata{Metakey: metakey, Value: metadata[metakey]})
+		}
+		res, err := proto.Marshal(metadataResult)
+		if err != nil {
+			errHandler(err, "[%s]Failed to marshal state metadata(%s). Sending %s", shorttxid(msg.Txid), err, pb.ChaincodeMessage_ERROR)
+			return
+		}
+
+		chaincodeLogger.Debugf("[%s]Got state metadata. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_RESPONSE)
+		serialSendMsg = &pb.ChaincodeMessage{Type: pb.ChaincodeMessage_RESPONSE, Payload: res, Txid: msg.Txid, ChannelId: msg.ChannelId}
+	}()
+}
+
 // afterGetStateByRange handles a GET_STATE_BY_RANGE request from the chaincode.
 func (handler *Handler) afterGetStateByRange(e *fsm.Event, state string) {
 	msg, ok := e.Args[0].(*pb.ChaincodeMessage)
@@ -731,9 +825,27 @@ func (handler *Handler) handleGetStateByRange(msg *pb.ChaincodeMessage) {
 		var rangeIter commonledger.ResultsIterator
 		var err error
 
-		if isCollectionSet(getStateByRange.Collection) {
+		metadata, err := getQueryMetadataFromBytes(getStateByRange.Metadata)
+		if err != nil {
+			errHandler(err, nil, "Failed to get query metadata. Sending %s", pb.ChaincodeMessage_ERROR)
+			return
+		}
+		paginated := metadata != nil
+
+		switch {
+		case isCollectionSet(getStateByRange.Collection) && paginated:
+			err = errors.New("pagination is not supported for private data queries")
+		case isCollectionSet(getStateByRange.Collection):
 			rangeIter, err = txContext.txsimulator.GetPrivateDataRangeScanIterator(chaincodeID, getStateByRange.Collection, getStateByRange.StartKey, getStateByRange.EndKey)
-		} else {
+		case paginated:
+			// the bookmark of a range query is the key to resume the scan from
+			startKey := getStateByRange.StartKey
+			if metadata.Bookmark != "" {
+				startKey = metadata.Bookmark
+			}
+			rangeIter, err = txContext.txsimulator.GetStateRangeScanIteratorWithMetadata(chaincodeID, startKey, getStateByRange.EndKey,
+				map[string]interface{}{"limit": metadata.PageSize})
+		default:
 			rangeIter, err = txContext.txsimulator.GetStateRangeScanIterator(chaincodeID, getStateByRange.StartKey, getStateByRange.EndKey)
 		}
 		if err != nil {
@@ -741,7 +853,7 @@ func (handler *Handler) handleGetStateByRange(msg *pb.ChaincodeMessage) {
 			return
 		}
 
-		handler.initializeQueryContext(txContext, iterID, rangeIter)
+		handler.initializeQueryContext(txContext, iterID, rangeIter, paginated)
 
 		var payload *pb.QueryResponse
 		payload, err = getQueryResponse(handler, txContext, rangeIter, iterID)
@@ -764,6 +876,22 @@ func (handler *Handler) handleGetStateByRange(msg *pb.ChaincodeMessage) {
 
 const maxResultLimit = 100
 
+// getQueryMetadataFromBytes unmarshals the pagination options sent along with
+// a query. A nil result means the query is not paginated.
+func getQueryMetadataFromBytes(metadataBytes []byte) (*pb.QueryMetadata, error) {
+	if len(metadataBytes) == 0 {
+		return nil, nil
+	}
+	metadata := &pb.QueryMetadata{}
+	if err := proto.Unmarshal(metadataBytes, metadata); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal query metadata")
+	}
+	if metadata.PageSize <= 0 {
+		return nil, errors.Errorf("invalid page size %d, must be greater than zero", metadata.PageSize)
+	}
+	return metadata, nil
+}
+
 //getQueryResponse takes an iterator and fetch state to construct QueryResponse
 func getQueryResponse(handler *Handler, txContext *transactionContext, iter commonledger.ResultsIterator,
 	iterID string) (*pb.QueryResponse, error) {
@@ -778,9 +906,17 @@ func getQueryResponse(handler *Handler, txContext *transactionContext, iter comm
 		case queryResult == nil:
 			// nil response from iterator indicates end of query results
 			batch := pendingQueryResults.cut()
+			var metadataBytes []byte
+			if pendingQueryResults.paginated {
+				metadataBytes, err = getQueryResponseMetadata(iter, len(batch))
+				if err != nil {
+					handler.cleanupQueryContext(txContext, iterID)
+					return nil, err
+				}
+			}
 			handler.cleanupQueryContext(txContext, iterID)
-			return &pb.QueryResponse{Results: batch, HasMore: false, Id: iterID}, nil
-		case pendingQueryResults.count == maxResultLimit:
+			return &pb.QueryResponse{Results: batch, HasMore: false, Id: iterID, Metadata: metadataBytes}, nil
+		case !pendingQueryResults.paginated && pendingQueryResults.count == maxResultLimit:
 			// max number of results queued up, cut batch, then add current result to pending batch
 			batch := pendingQueryResults.cut()
 			if err := pendingQueryResults.add(queryResult); err != nil {
@@ -797,6 +933,19 @@ func getQueryResponse(handler *Handler, txContext *transactionContext, iter comm
 	}
 }
 
+// getQueryResponseMetadata builds the metadata returned with the page of a
+// paginated query, including the bookmark to fetch the next page with
+func getQueryResponseMetadata(iter commonledger.ResultsIterator, fetchedRecordsCount int) ([]byte, error) {
+	queryResultsIter, ok := iter.(commonledger.QueryResultsIterator)
+	if !ok {
+		return nil, errors.New("iterator does not support pagination")
+	}
+	return proto.Marshal(&pb.QueryResponseMetadata{
+		FetchedRecordsCount: int32(fetchedRecordsCount),
+		Bookmark:            queryResultsIter.GetBookmarkAndClose(),
+	})
+}
+
 func (p *pendingQueryResult) cut() []*pb.QueryResultBytes {
 	batch := p.batch
 	p.batch = nil
@@ -1034,11 +1183,26 @@ func (handler *Handler) handleGetQueryResult(msg *pb.ChaincodeMessage) {
 
 		chaincodeID := handler.getCCRootName()
 
-		var err error
+		metadata, err := getQueryMetadataFromBytes(getQueryResult.Metadata)
+		if err != nil {
+			errHandler([]byte(err.Error()), nil, "Failed to get query metadata. Sending %s", pb.ChaincodeMessage_ERROR)
+			return
+		}
+		paginated := metadata != nil
+
 		var executeIter commonledger.ResultsIterator
-		if isCollectionSet(getQueryResult.Collection) {
+		switch {
+		case isCollectionSet(getQueryResult.Collection) && paginated:
+			err = errors.New("pagination is not supported for private data queries")
+		case isCollectionSet(getQueryResult.Collection):
 			executeIter, err = txContext.txsimulator.ExecuteQueryOnPrivateData(chaincodeID, getQueryResult.Collection, getQueryResult.Query)
-		} else {
+		case paginated:
+			executeIter, err = txContext.txsimulator.ExecuteQueryWithMetadata(chaincodeID, getQueryResult.Query,
+				map[string]interface{}{
+					"limit":    metadata.PageSize,
+					"bookmark": metadata.Bookmark,
+				})
+		default:
 			executeIter, err = txContext.txsimulator.ExecuteQuery(chaincodeID, getQueryResult.Query)
 		}
 
@@ -1047,7 +1211,7 @@ func (handler *Handler) handleGetQueryResult(msg *pb.ChaincodeMessage) {
 			return
 		}
 
-		handler.initializeQueryContext(txContext, iterID, executeIter)
+		handler.initializeQueryContext(txContext, iterID, executeIter, paginated)
 
 		var payload *pb.QueryResponse
 		payload, err = getQueryResponse(handler, txContext, executeIter, iterID)
@@ -1137,7 +1301,7 @@ func (handler *Handler) handleGetHistoryForKey(msg *pb.ChaincodeMessage) {
 			return
 		}
 
-		handler.initializeQueryContext(txContext, iterID, historyIter)
+		handler.initializeQueryContext(txContext, iterID, historyIter, false)
 
 		var payload *pb.QueryResponse
 		payload, err = getQueryResponse(handler, txContext, historyIter, iterID)
@@ -1280,6 +1444,32 @@ func (handler *Handler) enterBusyState(e *fsm.Event, state string) {
 			} else {
 				err = txContext.txsimulator.DeleteState(chaincodeID, delState.Key)
 			}
+		} else if msg.Type.String() == pb.ChaincodeMessage_PUT_STATE_METADATA.String() {
+			putStateMetadata := &pb.PutStateMetadata{}
+			unmarshalErr := proto.Unmarshal(msg.Payload, putStateMetadata)
+			if unmarshalErr != nil || putStateMetadata.Metadata == nil {
+				errHandler([]byte("invalid PutStateMetadata payload"), "[%s]Unable to decipher payload. Sending %s", shorttxid(msg.Txid), pb.ChaincodeMessage_ERROR)
+				return
+			}
+
+			// the metadata of the key is read first, so that only the given entry is changed
+			var metadata map[string][]byte
+			if isCollectionSet(putStateMetadata.Collection) {
+				metadata, err = txContext.txsimulator.GetPrivateDataMetadata(chaincodeID, putStateMetadata.Collection, putStateMetadata.Key)
+			} else {
+				metadata, err = txContext.txsimulator.GetStateMetadata(chaincodeID, putStateMetadata.Key)
+			}
+			if err == nil {
+				if metadata == nil {
+					metadata = make(map[string][]byte)
+				}
+				metadata[putStateMetadata.Metadata.Metakey] = putStateMetadata.Metadata.Value
+				if isCollectionSet(putStateMetadata.Collection) {
+					err = txContext.txsimulator.SetPrivateDataMetadata(chaincodeID, putStateMetadata.Collection, putStateMetadata.Key, metadata)
+				} else {
+					err = txContext.txsimulator.SetStateMetadata(chaincodeID, putStateMetadata.Key, metadata)
+				}
+			}
 		} else if msg.Type.String() == pb.ChaincodeMessage_INVOKE_CHAINCODE.String() {
 			chaincodeLogger.Debugf("[%s] C-call-C", shorttxid(msg.Txid))
 			chaincodeSpec := &pb.ChaincodeSpec{}
@@ -1349,10 +1539,13 @@ func (handler *Handler) enterBusyState(e *fsm.Event, state string) {
 
 				version = cd.CCVersion()
 
-				err = ccprovider.CheckInstantiationPolicy(calledCcIns.ChaincodeName, version, cd.(*ccprovider.ChaincodeData))
-				if err != nil {
-					errHandler([]byte(err.Error()), "[%s]CheckInstantiationPolicy, error %s. Sending %s", shorttxid(msg.Txid), err, pb.ChaincodeMessage_ERROR)
-					return
+				// only chaincodes instantiated through lscc have an instantiation policy
+				if lsccData, ok := cd.(*ccprovider.ChaincodeData); ok {
+					err = ccprovider.CheckInstantiationPolicy(calledCcIns.ChaincodeName, version, lsccData)
+					if err != nil {
+						errHandler([]byte(err.Error()), "[%s]CheckInstantiationPolicy, error %s. Sending %s", shorttxid(msg.Txid), err, pb.ChaincodeMessage_ERROR)
+						return
+					}
 				}
 			} else {
 				//this is a system cc, just call it directly
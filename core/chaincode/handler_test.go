@@ -11,8 +11,10 @@ import (
 	"math"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -56,7 +58,7 @@ func TestGetQueryResponse(t *testing.T) {
 		queryID := "test"
 		t.Run(fmt.Sprintf("%d", tc.expectedResultCount), func(t *testing.T) {
 			resultsIterator := &MockResultsIterator{}
-			handler.initializeQueryContext(transactionContext, queryID, resultsIterator)
+			handler.initializeQueryContext(transactionContext, queryID, resultsIterator, false)
 			if tc.expectedResultCount > 0 {
 				resultsIterator.On("Next").Return(queryResult, nil).Times(tc.expectedResultCount)
 			}
@@ -107,3 +109,74 @@ func (m *MockResultsIterator) Next() (ledger.QueryResult, error) {
 func (m *MockResultsIterator) Close() {
 	m.Called()
 }
+
+func TestGetQueryResponsePaginated(t *testing.T) {
+	queryResult := &queryresult.KV{
+		Key:       "key",
+		Namespace: "namespace",
+		Value:     []byte("value"),
+	}
+
+	handler := &Handler{}
+	transactionContext := &transactionContext{
+		queryIteratorMap:    make(map[string]ledger.ResultsIterator),
+		pendingQueryResults: make(map[string]*pendingQueryResult),
+	}
+	queryID := "test"
+
+	// a paginated query returns the whole page in one response, even beyond maxResultLimit
+	resultsIterator := &MockQueryResultsIterator{}
+	handler.initializeQueryContext(transactionContext, queryID, resultsIterator, true)
+	resultsIterator.On("Next").Return(queryResult, nil).Times(maxResultLimit + 1)
+	resultsIterator.On("Next").Return(nil, nil).Once()
+	resultsIterator.On("GetBookmarkAndClose").Return("nextKey").Once()
+	resultsIterator.On("Close").Return()
+
+	queryResponse, err := getQueryResponse(handler, transactionContext, resultsIterator, queryID)
+	assert.NoError(t, err)
+	assert.False(t, queryResponse.GetHasMore())
+	assert.Len(t, queryResponse.GetResults(), maxResultLimit+1)
+
+	metadata := &pb.QueryResponseMetadata{}
+	assert.NoError(t, proto.Unmarshal(queryResponse.GetMetadata(), metadata))
+	assert.Equal(t, int32(maxResultLimit+1), metadata.FetchedRecordsCount)
+	assert.Equal(t, "nextKey", metadata.Bookmark)
+	resultsIterator.AssertExpectations(t)
+
+	// an iterator that cannot produce a bookmark fails the query
+	plainIterator := &MockResultsIterator{}
+	handler.initializeQueryContext(transactionContext, queryID, plainIterator, true)
+	plainIterator.On("Next").Return(nil, nil).Once()
+	plainIterator.On("Close").Return().Once()
+	_, err = getQueryResponse(handler, transactionContext, plainIterator, queryID)
+	assert.EqualError(t, err, "iterator does not support pagination")
+	plainIterator.AssertExpectations(t)
+}
+
+func TestGetQueryMetadataFromBytes(t *testing.T) {
+	metadata, err := getQueryMetadataFromBytes(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, metadata)
+
+	metadataBytes, _ := proto.Marshal(&pb.QueryMetadata{PageSize: 10, Bookmark: "key1"})
+	metadata, err = getQueryMetadataFromBytes(metadataBytes)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(10), metadata.PageSize)
+	assert.Equal(t, "key1", metadata.Bookmark)
+
+	metadataBytes, _ = proto.Marshal(&pb.QueryMetadata{PageSize: -1})
+	_, err = getQueryMetadataFromBytes(metadataBytes)
+	assert.EqualError(t, err, "invalid page size -1, must be greater than zero")
+
+	_, err = getQueryMetadataFromBytes([]byte("garbage"))
+	assert.Error(t, err)
+}
+
+type MockQueryResultsIterator struct {
+	MockResultsIterator
+}
+
+func (m *MockQueryResultsIterator) GetBookmarkAndClose() string {
+	args := m.Called()
+	return args.String(0)
+}
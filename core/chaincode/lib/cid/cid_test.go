@@ -20,6 +20,7 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/core/chaincode/lib/cid"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/protos/msp"
 	"github.com/stretchr/testify/assert"
 )
@@ -106,6 +107,19 @@ func TestClient(t *testing.T) {
 	assert.Error(t, err, "NewSubmitterInfo should have returned an error when submitter with fake creator is passed")
 }
 
+func TestClientWithShimMockStub(t *testing.T) {
+	stub := shim.NewMockStub("cidtest", nil)
+	stub.MockCreator("Org1MSP", []byte(certWithAttrs))
+
+	mspid, err := cid.GetMSPID(stub)
+	assert.NoError(t, err, "Error getting MSP ID of the submitter of the transaction")
+	assert.Equal(t, "Org1MSP", mspid)
+	attrVal, found, err := cid.GetAttributeValue(stub, "attr1")
+	assert.NoError(t, err, "Error getting attribute of the submitter of the transaction")
+	assert.True(t, found, "Attribute 'attr1' should be found in the submitter cert")
+	assert.Equal(t, "val1", attrVal)
+}
+
 func getMockStub() (cid.ChaincodeStubInterface, error) {
 	stub := &mockStub{}
 	sid := &msp.SerializedIdentity{Mspid: "DEFAULT",
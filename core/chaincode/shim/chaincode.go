@@ -437,11 +437,20 @@ func (stub *ChaincodeStub) PutState(key string, value []byte) error {
 func (stub *ChaincodeStub) GetQueryResult(query string) (StateQueryIteratorInterface, error) {
 	// Access public data by setting the collection to empty string
 	collection := ""
-	response, err := stub.handler.handleGetQueryResult(collection, query, stub.ChannelId, stub.TxID)
+	iterator, _, err := stub.handleGetQueryResult(collection, query, nil)
+	return iterator, err
+}
+
+// GetQueryResultWithPagination documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetQueryResultWithPagination(query string, pageSize int32,
+	bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	// Access public data by setting the collection to empty string
+	collection := ""
+	metadata, err := createQueryMetadata(pageSize, bookmark)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return &StateQueryIterator{CommonIterator: &CommonIterator{stub.handler, stub.ChannelId, stub.TxID, response, 0}}, nil
+	return stub.handleGetQueryResult(collection, query, metadata)
 }
 
 // DelState documentation can be found in interfaces.go
@@ -451,6 +460,24 @@ func (stub *ChaincodeStub) DelState(key string) error {
 	return stub.handler.handleDelState(collection, key, stub.ChannelId, stub.TxID)
 }
 
+// SetStateValidationParameter documentation can be found in interfaces.go
+func (stub *ChaincodeStub) SetStateValidationParameter(key string, ep []byte) error {
+	// Access public data by setting the collection to empty string
+	collection := ""
+	return stub.handler.handlePutStateMetadataEntry(collection, key, pb.MetaDataKeys_VALIDATION_PARAMETER.String(), ep, stub.ChannelId, stub.TxID)
+}
+
+// GetStateValidationParameter documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetStateValidationParameter(key string) ([]byte, error) {
+	// Access public data by setting the collection to empty string
+	collection := ""
+	md, err := stub.handler.handleGetStateMetadata(collection, key, stub.ChannelId, stub.TxID)
+	if err != nil {
+		return nil, err
+	}
+	return md[pb.MetaDataKeys_VALIDATION_PARAMETER.String()], nil
+}
+
 // CommonIterator documentation can be found in interfaces.go
 type CommonIterator struct {
 	handler    *Handler
@@ -477,12 +504,51 @@ const (
 	HISTORY_QUERY_RESULT
 )
 
-func (stub *ChaincodeStub) handleGetStateByRange(collection, startKey, endKey string) (StateQueryIteratorInterface, error) {
-	response, err := stub.handler.handleGetStateByRange(collection, startKey, endKey, stub.ChannelId, stub.TxID)
+func (stub *ChaincodeStub) handleGetStateByRange(collection, startKey, endKey string,
+	metadata []byte) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	response, err := stub.handler.handleGetStateByRange(collection, startKey, endKey, metadata, stub.ChannelId, stub.TxID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return stub.createStateQueryIterator(response)
+}
+
+func (stub *ChaincodeStub) handleGetQueryResult(collection, query string,
+	metadata []byte) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	response, err := stub.handler.handleGetQueryResult(collection, query, metadata, stub.ChannelId, stub.TxID)
 	if err != nil {
+		return nil, nil, err
+	}
+	return stub.createStateQueryIterator(response)
+}
+
+// createStateQueryIterator wraps the first response of a query in an iterator,
+// along with the response metadata returned for paginated queries
+func (stub *ChaincodeStub) createStateQueryIterator(response *pb.QueryResponse) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	var responseMetadata *pb.QueryResponseMetadata
+	if len(response.Metadata) > 0 {
+		responseMetadata = &pb.QueryResponseMetadata{}
+		if err := proto.Unmarshal(response.Metadata, responseMetadata); err != nil {
+			return nil, nil, errors.Wrap(err, "failed to unmarshal query response metadata")
+		}
+	}
+	iterator := &StateQueryIterator{CommonIterator: &CommonIterator{stub.handler, stub.ChannelId, stub.TxID, response, 0}}
+	return iterator, responseMetadata, nil
+}
+
+// createQueryMetadata marshals the pagination options sent to the peer with a query
+func createQueryMetadata(pageSize int32, bookmark string) ([]byte, error) {
+	if err := validatePageSize(pageSize); err != nil {
 		return nil, err
 	}
-	return &StateQueryIterator{CommonIterator: &CommonIterator{stub.handler, stub.ChannelId, stub.TxID, response, 0}}, nil
+	return proto.Marshal(&pb.QueryMetadata{PageSize: pageSize, Bookmark: bookmark})
+}
+
+func validatePageSize(pageSize int32) error {
+	if pageSize <= 0 {
+		return errors.Errorf("invalid page size %d, must be greater than zero", pageSize)
+	}
+	return nil
 }
 
 // GetStateByRange documentation can be found in interfaces.go
@@ -494,7 +560,25 @@ func (stub *ChaincodeStub) GetStateByRange(startKey, endKey string) (StateQueryI
 		return nil, err
 	}
 	collection := ""
-	return stub.handleGetStateByRange(collection, startKey, endKey)
+	iterator, _, err := stub.handleGetStateByRange(collection, startKey, endKey, nil)
+	return iterator, err
+}
+
+// GetStateByRangeWithPagination documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetStateByRangeWithPagination(startKey, endKey string, pageSize int32,
+	bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	if startKey == "" {
+		startKey = emptyKeySubstitute
+	}
+	if err := validateSimpleKeys(startKey, endKey); err != nil {
+		return nil, nil, err
+	}
+	metadata, err := createQueryMetadata(pageSize, bookmark)
+	if err != nil {
+		return nil, nil, err
+	}
+	collection := ""
+	return stub.handleGetStateByRange(collection, startKey, endKey, metadata)
 }
 
 // GetHistoryForKey documentation can be found in interfaces.go
@@ -577,12 +661,28 @@ func validateSimpleKeys(simpleKeys ...string) error {
 func (stub *ChaincodeStub) GetStateByPartialCompositeKey(objectType string, attributes []string) (StateQueryIteratorInterface, error) {
 	collection := ""
 	if partialCompositeKey, err := stub.CreateCompositeKey(objectType, attributes); err == nil {
-		return stub.handleGetStateByRange(collection, partialCompositeKey, partialCompositeKey+string(maxUnicodeRuneValue))
+		iterator, _, err := stub.handleGetStateByRange(collection, partialCompositeKey, partialCompositeKey+string(maxUnicodeRuneValue), nil)
+		return iterator, err
 	} else {
 		return nil, err
 	}
 }
 
+// GetStateByPartialCompositeKeyWithPagination documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetStateByPartialCompositeKeyWithPagination(objectType string, keys []string,
+	pageSize int32, bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	partialCompositeKey, err := stub.CreateCompositeKey(objectType, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+	metadata, err := createQueryMetadata(pageSize, bookmark)
+	if err != nil {
+		return nil, nil, err
+	}
+	collection := ""
+	return stub.handleGetStateByRange(collection, partialCompositeKey, partialCompositeKey+string(maxUnicodeRuneValue), metadata)
+}
+
 func (iter *StateQueryIterator) Next() (*queryresult.KV, error) {
 	if result, err := iter.nextResult(STATE_QUERY_RESULT); err == nil {
 		return result.(*queryresult.KV), err
@@ -10,6 +10,8 @@ package shim
 
 import (
 	"fmt"
+
+	pb "github.com/hyperledger/fabric/protos/peer"
 )
 
 // private state functions
@@ -41,6 +43,26 @@ func (stub *ChaincodeStub) DelPrivateData(collection string, key string) error {
 	return stub.handler.handleDelState(collection, key, stub.ChannelId, stub.TxID)
 }
 
+// SetPrivateDataValidationParameter documentation can be found in interfaces.go
+func (stub *ChaincodeStub) SetPrivateDataValidationParameter(collection, key string, ep []byte) error {
+	if collection == "" {
+		return fmt.Errorf("collection must not be an empty string")
+	}
+	return stub.handler.handlePutStateMetadataEntry(collection, key, pb.MetaDataKeys_VALIDATION_PARAMETER.String(), ep, stub.ChannelId, stub.TxID)
+}
+
+// GetPrivateDataValidationParameter documentation can be found in interfaces.go
+func (stub *ChaincodeStub) GetPrivateDataValidationParameter(collection, key string) ([]byte, error) {
+	if collection == "" {
+		return nil, fmt.Errorf("collection must not be an empty string")
+	}
+	md, err := stub.handler.handleGetStateMetadata(collection, key, stub.ChannelId, stub.TxID)
+	if err != nil {
+		return nil, err
+	}
+	return md[pb.MetaDataKeys_VALIDATION_PARAMETER.String()], nil
+}
+
 // GetPrivateDataByRange documentation can be found in interfaces.go
 func (stub *ChaincodeStub) GetPrivateDataByRange(collection, startKey, endKey string) (StateQueryIteratorInterface, error) {
 	if collection == "" {
@@ -52,7 +74,8 @@ func (stub *ChaincodeStub) GetPrivateDataByRange(collection, startKey, endKey st
 	if err := validateSimpleKeys(startKey, endKey); err != nil {
 		return nil, err
 	}
-	return stub.handleGetStateByRange(collection, startKey, endKey)
+	iterator, _, err := stub.handleGetStateByRange(collection, startKey, endKey, nil)
+	return iterator, err
 }
 
 // GetPrivateDataByPartialCompositeKey documentation can be found in interfaces.go
@@ -61,7 +84,8 @@ func (stub *ChaincodeStub) GetPrivateDataByPartialCompositeKey(collection, objec
 		return nil, fmt.Errorf("collection must not be an empty string")
 	}
 	if partialCompositeKey, err := stub.CreateCompositeKey(objectType, attributes); err == nil {
-		return stub.handleGetStateByRange(collection, partialCompositeKey, partialCompositeKey+string(maxUnicodeRuneValue))
+		iterator, _, err := stub.handleGetStateByRange(collection, partialCompositeKey, partialCompositeKey+string(maxUnicodeRuneValue), nil)
+		return iterator, err
 	} else {
 		return nil, err
 	}
@@ -72,7 +96,7 @@ func (stub *ChaincodeStub) GetPrivateDataQueryResult(collection, query string) (
 	if collection == "" {
 		return nil, fmt.Errorf("collection must not be an empty string")
 	}
-	response, err := stub.handler.handleGetQueryResult(collection, query, stub.ChannelId, stub.TxID)
+	response, err := stub.handler.handleGetQueryResult(collection, query, nil, stub.ChannelId, stub.TxID)
 	if err != nil {
 		return nil, err
 	}
@@ -99,6 +99,18 @@ type ChaincodeStubInterface interface {
 	// the ledger when the transaction is validated and successfully committed.
 	DelState(key string) error
 
+	// SetStateValidationParameter sets the key-level endorsement policy for `key`.
+	// The policy is stored in the metadata of `key` and is evaluated, in place of
+	// the chaincode-level endorsement policy, for the transactions that write
+	// `key` after this transaction is committed.
+	SetStateValidationParameter(key string, ep []byte) error
+
+	// GetStateValidationParameter retrieves the key-level endorsement policy
+	// for `key`. Note that this will introduce a read dependency on `key` in
+	// the transaction's readset. If `key` has no key-level endorsement policy,
+	// (nil, nil) is returned.
+	GetStateValidationParameter(key string) ([]byte, error)
+
 	// GetStateByRange returns a range iterator over a set of keys in the
 	// ledger. The iterator can be used to iterate over all keys
 	// between the startKey (inclusive) and endKey (exclusive).
@@ -110,6 +122,21 @@ type ChaincodeStubInterface interface {
 	// has not changed since transaction endorsement (phantom reads detected).
 	GetStateByRange(startKey, endKey string) (StateQueryIteratorInterface, error)
 
+	// GetStateByRangeWithPagination returns a range iterator over a set of keys
+	// in the ledger, returning at most `pageSize` keys per page. The iterator
+	// can be used to fetch all keys between the startKey (inclusive) and endKey
+	// (exclusive). When an empty string is passed as a value to the bookmark
+	// argument, the returned iterator can be used to fetch the first `pageSize`
+	// keys between the startKey and endKey. When the bookmark is a non-empty
+	// string, the iterator fetches the next `pageSize` keys after the bookmark.
+	// The bookmark to fetch the next page is returned in QueryResponseMetadata,
+	// along with the number of records fetched. Note that startKey and endKey
+	// can be empty string, which implies unbounded range query on start or end.
+	// Call Close() on the returned StateQueryIteratorInterface object when done.
+	// This call is only supported in a read only transaction.
+	GetStateByRangeWithPagination(startKey, endKey string, pageSize int32,
+		bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error)
+
 	// GetStateByPartialCompositeKey queries the state in the ledger based on
 	// a given partial composite key. This function returns an iterator
 	// which can be used to iterate over all composite keys whose prefix matches
@@ -122,6 +149,17 @@ type ChaincodeStubInterface interface {
 	// has not changed since transaction endorsement (phantom reads detected).
 	GetStateByPartialCompositeKey(objectType string, keys []string) (StateQueryIteratorInterface, error)
 
+	// GetStateByPartialCompositeKeyWithPagination queries the state in the
+	// ledger based on a given partial composite key, returning at most
+	// `pageSize` composite keys per page whose prefix matches the given partial
+	// composite key. The bookmark works as for GetStateByRangeWithPagination:
+	// an empty bookmark fetches the first page, and the bookmark returned in
+	// QueryResponseMetadata fetches the next one.
+	// Call Close() on the returned StateQueryIteratorInterface object when done.
+	// This call is only supported in a read only transaction.
+	GetStateByPartialCompositeKeyWithPagination(objectType string, keys []string,
+		pageSize int32, bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error)
+
 	// CreateCompositeKey combines the given `attributes` to form a composite
 	// key. The objectType and attributes are expected to have only valid utf8
 	// strings and should not contain U+0000 (nil byte) and U+10FFFF
@@ -148,6 +186,20 @@ type ChaincodeStubInterface interface {
 	// ledger, and should limit use to read-only chaincode operations.
 	GetQueryResult(query string) (StateQueryIteratorInterface, error)
 
+	// GetQueryResultWithPagination performs a "rich" query against a state
+	// database, returning at most `pageSize` records per page. It is only
+	// supported for state databases that support rich query, e.g. CouchDB.
+	// When an empty string is passed as a value to the bookmark argument, the
+	// returned iterator can be used to fetch the first `pageSize` query
+	// results. When the bookmark is a non-empty string, the iterator fetches
+	// the next `pageSize` results after the bookmark. The bookmark to fetch the
+	// next page is returned in QueryResponseMetadata, along with the number of
+	// records fetched. The bookmark is only valid for the same query.
+	// Call Close() on the returned StateQueryIteratorInterface object when done.
+	// This call is only supported in a read only transaction.
+	GetQueryResultWithPagination(query string, pageSize int32,
+		bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error)
+
 	// GetHistoryForKey returns a history of key values across time.
 	// For each historic key update, the historic value and associated
 	// transaction id and timestamp are returned. The timestamp is the
@@ -0,0 +1,530 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shim
+
+import (
+	"encoding/json"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+	"github.com/pkg/errors"
+)
+
+// mockQuery is a CouchDB Mango query, evaluated by MockStub against its
+// in-memory state so that chaincode rich queries can be unit tested.
+// Only JSON object values take part in a query, as with CouchDB.
+type mockQuery struct {
+	selector map[string]interface{}
+	sort     []mockSortField
+	fields   []string
+	limit    int
+	skip     int
+}
+
+type mockSortField struct {
+	field string
+	desc  bool
+}
+
+// mockQueryDoc is a state entry matched by a query
+type mockQueryDoc struct {
+	key   string
+	value []byte
+	doc   map[string]interface{}
+}
+
+// parseMockQuery parses a Mango query string. Options that only affect how
+// CouchDB executes the query, such as use_index, are accepted and ignored.
+func parseMockQuery(query string) (*mockQuery, error) {
+	raw := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(query), &raw); err != nil {
+		return nil, errors.Wrap(err, "invalid query, must be a JSON object")
+	}
+
+	q := &mockQuery{}
+	selector, ok := raw["selector"].(map[string]interface{})
+	if !ok {
+		return nil, errors.New("invalid query, selector must be a JSON object")
+	}
+	q.selector = selector
+
+	if rawSort, ok := raw["sort"]; ok {
+		sortFields, ok := rawSort.([]interface{})
+		if !ok {
+			return nil, errors.New("invalid query, sort must be an array")
+		}
+		for _, sortField := range sortFields {
+			field, err := parseMockSortField(sortField)
+			if err != nil {
+				return nil, err
+			}
+			q.sort = append(q.sort, field)
+		}
+	}
+
+	if rawFields, ok := raw["fields"]; ok {
+		fields, ok := rawFields.([]interface{})
+		if !ok {
+			return nil, errors.New("invalid query, fields must be an array")
+		}
+		for _, field := range fields {
+			name, ok := field.(string)
+			if !ok {
+				return nil, errors.New("invalid query, fields must be an array of strings")
+			}
+			q.fields = append(q.fields, name)
+		}
+	}
+
+	var err error
+	if q.limit, err = parseMockQueryCount(raw, "limit"); err != nil {
+		return nil, err
+	}
+	if q.skip, err = parseMockQueryCount(raw, "skip"); err != nil {
+		return nil, err
+	}
+	return q, nil
+}
+
+func parseMockSortField(sortField interface{}) (mockSortField, error) {
+	switch s := sortField.(type) {
+	case string:
+		return mockSortField{field: s}, nil
+	case map[string]interface{}:
+		if len(s) == 1 {
+			for field, direction := range s {
+				switch direction {
+				case "asc":
+					return mockSortField{field: field}, nil
+				case "desc":
+					return mockSortField{field: field, desc: true}, nil
+				}
+			}
+		}
+	}
+	return mockSortField{}, errors.Errorf("invalid query, unsupported sort field %v", sortField)
+}
+
+func parseMockQueryCount(raw map[string]interface{}, option string) (int, error) {
+	value, ok := raw[option]
+	if !ok {
+		return 0, nil
+	}
+	count, ok := value.(float64)
+	if !ok || count < 0 || count != math.Trunc(count) {
+		return 0, errors.Errorf("invalid query, %s must be a non-negative integer", option)
+	}
+	return int(count), nil
+}
+
+// execute runs the query over the given keys, in key order, fetching values
+// with getValue. Matches are sorted, skipped and limited as requested.
+func (q *mockQuery) execute(keys []string, getValue func(key string) []byte) ([]*queryresult.KV, error) {
+	var matches []*mockQueryDoc
+	for _, key := range keys {
+		value := getValue(key)
+		doc := map[string]interface{}{}
+		if err := json.Unmarshal(value, &doc); err != nil {
+			// non-JSON values are not visible to rich queries
+			continue
+		}
+		doc["_id"] = key
+		matched, err := matchMockSelector(doc, true, q.selector)
+		if err != nil {
+			return nil, err
+		}
+		if matched {
+			matches = append(matches, &mockQueryDoc{key: key, value: value, doc: doc})
+		}
+	}
+
+	if len(q.sort) > 0 {
+		sort.SliceStable(matches, func(i, j int) bool {
+			for _, s := range q.sort {
+				vi, _ := lookupMockField(matches[i].doc, s.field)
+				vj, _ := lookupMockField(matches[j].doc, s.field)
+				c := collateMockValues(vi, vj)
+				if c == 0 {
+					continue
+				}
+				if s.desc {
+					return c > 0
+				}
+				return c < 0
+			}
+			return false
+		})
+	}
+
+	if q.skip >= len(matches) {
+		matches = nil
+	} else {
+		matches = matches[q.skip:]
+	}
+	if q.limit > 0 && q.limit < len(matches) {
+		matches = matches[:q.limit]
+	}
+
+	results := make([]*queryresult.KV, 0, len(matches))
+	for _, match := range matches {
+		value := match.value
+		if len(q.fields) > 0 {
+			projected, err := json.Marshal(projectMockFields(match.doc, q.fields))
+			if err != nil {
+				return nil, err
+			}
+			value = projected
+		}
+		results = append(results, &queryresult.KV{Key: match.key, Value: value})
+	}
+	return results, nil
+}
+
+// matchMockSelector reports whether value satisfies selector. Field names
+// select (possibly nested) fields of value, while operators apply to value itself.
+func matchMockSelector(value interface{}, exists bool, selector map[string]interface{}) (bool, error) {
+	for key, arg := range selector {
+		var matched bool
+		var err error
+		switch {
+		case key == "$and" || key == "$or" || key == "$nor":
+			matched, err = matchMockCombination(value, exists, key, arg)
+		case key == "$not":
+			subSelector, ok := arg.(map[string]interface{})
+			if !ok {
+				return false, errors.New("invalid query, $not requires a selector")
+			}
+			matched, err = matchMockSelector(value, exists, subSelector)
+			matched = !matched
+		case strings.HasPrefix(key, "$"):
+			matched, err = matchMockOperator(value, exists, key, arg)
+		default:
+			fieldValue, fieldExists := lookupMockField(value, key)
+			matched, err = matchMockCondition(fieldValue, fieldExists, arg)
+		}
+		if err != nil || !matched {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func matchMockCombination(value interface{}, exists bool, operator string, arg interface{}) (bool, error) {
+	selectors, ok := arg.([]interface{})
+	if !ok {
+		return false, errors.Errorf("invalid query, %s requires an array of selectors", operator)
+	}
+	matchCount := 0
+	for _, s := range selectors {
+		subSelector, ok := s.(map[string]interface{})
+		if !ok {
+			return false, errors.Errorf("invalid query, %s requires an array of selectors", operator)
+		}
+		matched, err := matchMockSelector(value, exists, subSelector)
+		if err != nil {
+			return false, err
+		}
+		if matched {
+			matchCount++
+		}
+	}
+	switch operator {
+	case "$and":
+		return matchCount == len(selectors), nil
+	case "$or":
+		return matchCount > 0, nil
+	default:
+		return matchCount == 0, nil
+	}
+}
+
+// matchMockCondition matches a field against its condition, which is either
+// a value to compare for equality or an object of operators and sub-fields.
+// As with CouchDB, a missing field only matches {"$exists": false}.
+func matchMockCondition(value interface{}, exists bool, condition interface{}) (bool, error) {
+	if conditionMap, ok := condition.(map[string]interface{}); ok {
+		if !exists {
+			existsArg, ok := conditionMap["$exists"]
+			return ok && len(conditionMap) == 1 && existsArg == false, nil
+		}
+		return matchMockSelector(value, exists, conditionMap)
+	}
+	return exists && collateMockValues(value, condition) == 0, nil
+}
+
+func matchMockOperator(value interface{}, exists bool, operator string, arg interface{}) (bool, error) {
+	if operator == "$exists" {
+		want, ok := arg.(bool)
+		if !ok {
+			return false, errors.New("invalid query, $exists requires a boolean")
+		}
+		return exists == want, nil
+	}
+	if !exists {
+		return false, nil
+	}
+
+	switch operator {
+	case "$eq":
+		return collateMockValues(value, arg) == 0, nil
+	case "$ne":
+		return collateMockValues(value, arg) != 0, nil
+	case "$lt":
+		return collateMockValues(value, arg) < 0, nil
+	case "$lte":
+		return collateMockValues(value, arg) <= 0, nil
+	case "$gt":
+		return collateMockValues(value, arg) > 0, nil
+	case "$gte":
+		return collateMockValues(value, arg) >= 0, nil
+	case "$type":
+		return mockValueType(value) == arg, nil
+	case "$in", "$nin":
+		candidates, ok := arg.([]interface{})
+		if !ok {
+			return false, errors.Errorf("invalid query, %s requires an array", operator)
+		}
+		found := mockValueIn(value, candidates)
+		return found == (operator == "$in"), nil
+	case "$size":
+		array, ok := value.([]interface{})
+		size, isNumber := arg.(float64)
+		if !isNumber {
+			return false, errors.New("invalid query, $size requires a number")
+		}
+		return ok && float64(len(array)) == size, nil
+	case "$mod":
+		return matchMockMod(value, arg)
+	case "$regex":
+		pattern, ok := arg.(string)
+		if !ok {
+			return false, errors.New("invalid query, $regex requires a string")
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return false, errors.Wrap(err, "invalid query, bad $regex")
+		}
+		s, ok := value.(string)
+		return ok && re.MatchString(s), nil
+	case "$all":
+		required, ok := arg.([]interface{})
+		if !ok {
+			return false, errors.New("invalid query, $all requires an array")
+		}
+		array, ok := value.([]interface{})
+		if !ok {
+			return false, nil
+		}
+		for _, r := range required {
+			if !mockValueIn(r, array) {
+				return false, nil
+			}
+		}
+		return true, nil
+	case "$elemMatch", "$allMatch":
+		subSelector, ok := arg.(map[string]interface{})
+		if !ok {
+			return false, errors.Errorf("invalid query, %s requires a selector", operator)
+		}
+		array, ok := value.([]interface{})
+		if !ok || len(array) == 0 {
+			return false, nil
+		}
+		for _, elem := range array {
+			matched, err := matchMockSelector(elem, true, subSelector)
+			if err != nil {
+				return false, err
+			}
+			if matched && operator == "$elemMatch" {
+				return true, nil
+			}
+			if !matched && operator == "$allMatch" {
+				return false, nil
+			}
+		}
+		return operator == "$allMatch", nil
+	}
+	return false, errors.Errorf("invalid query, unsupported operator %s", operator)
+}
+
+func matchMockMod(value interface{}, arg interface{}) (bool, error) {
+	args, ok := arg.([]interface{})
+	if !ok || len(args) != 2 {
+		return false, errors.New("invalid query, $mod requires [divisor, remainder]")
+	}
+	divisor, ok1 := args[0].(float64)
+	remainder, ok2 := args[1].(float64)
+	if !ok1 || !ok2 || divisor == 0 || divisor != math.Trunc(divisor) || remainder != math.Trunc(remainder) {
+		return false, errors.New("invalid query, $mod requires integer [divisor, remainder]")
+	}
+	n, ok := value.(float64)
+	if !ok || n != math.Trunc(n) {
+		return false, nil
+	}
+	return int64(n)%int64(divisor) == int64(remainder), nil
+}
+
+// mockValueIn reports whether value, or any element of value if it is an
+// array, equals one of the candidates
+func mockValueIn(value interface{}, candidates []interface{}) bool {
+	values := []interface{}{value}
+	if array, ok := value.([]interface{}); ok {
+		values = array
+	}
+	for _, v := range values {
+		for _, c := range candidates {
+			if collateMockValues(v, c) == 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lookupMockField resolves a dotted field path, indexing arrays by position
+func lookupMockField(value interface{}, field string) (interface{}, bool) {
+	for _, name := range strings.Split(field, ".") {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			next, ok := v[name]
+			if !ok {
+				return nil, false
+			}
+			value = next
+		case []interface{}:
+			i, err := strconv.Atoi(name)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// projectMockFields builds a document holding only the requested fields
+func projectMockFields(doc map[string]interface{}, fields []string) map[string]interface{} {
+	projected := map[string]interface{}{}
+	for _, field := range fields {
+		value, ok := lookupMockField(doc, field)
+		if !ok {
+			continue
+		}
+		path := strings.Split(field, ".")
+		target := projected
+		for _, name := range path[:len(path)-1] {
+			next, ok := target[name].(map[string]interface{})
+			if !ok {
+				next = map[string]interface{}{}
+				target[name] = next
+			}
+			target = next
+		}
+		target[path[len(path)-1]] = value
+	}
+	return projected
+}
+
+func mockValueType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// mockCollationRank orders JSON types as CouchDB collates them:
+// null < false < true < numbers < strings < arrays < objects
+func mockCollationRank(value interface{}) int {
+	switch v := value.(type) {
+	case nil:
+		return 0
+	case bool:
+		if v {
+			return 2
+		}
+		return 1
+	case float64:
+		return 3
+	case string:
+		return 4
+	case []interface{}:
+		return 5
+	default:
+		return 6
+	}
+}
+
+// collateMockValues compares two JSON values following CouchDB collation.
+// Strings are compared by code point rather than with ICU rules.
+func collateMockValues(a, b interface{}) int {
+	ra, rb := mockCollationRank(a), mockCollationRank(b)
+	if ra != rb {
+		if ra < rb {
+			return -1
+		}
+		return 1
+	}
+	switch va := a.(type) {
+	case float64:
+		vb := b.(float64)
+		switch {
+		case va < vb:
+			return -1
+		case va > vb:
+			return 1
+		}
+		return 0
+	case string:
+		return strings.Compare(va, b.(string))
+	case []interface{}:
+		vb := b.([]interface{})
+		for i := 0; i < len(va) && i < len(vb); i++ {
+			if c := collateMockValues(va[i], vb[i]); c != 0 {
+				return c
+			}
+		}
+		return len(va) - len(vb)
+	case map[string]interface{}:
+		vb := b.(map[string]interface{})
+		keysA, keysB := sortedMockKeys(va), sortedMockKeys(vb)
+		for i := 0; i < len(keysA) && i < len(keysB); i++ {
+			if c := strings.Compare(keysA[i], keysB[i]); c != 0 {
+				return c
+			}
+			if c := collateMockValues(va[keysA[i]], vb[keysB[i]]); c != 0 {
+				return c
+			}
+		}
+		return len(keysA) - len(keysB)
+	}
+	return 0
+}
+
+func sortedMockKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
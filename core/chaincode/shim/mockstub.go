@@ -11,12 +11,16 @@ package shim
 import (
 	"container/list"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+	"github.com/hyperledger/fabric/protos/msp"
 	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/op/go-logging"
 	"github.com/pkg/errors"
 )
@@ -43,6 +47,25 @@ type MockStub struct {
 	// Keys stores the list of mapped values in lexical order
 	Keys *list.List
 
+	// EndorsementPolicies keeps the key-level endorsement policies of the keys in State
+	EndorsementPolicies map[string][]byte
+
+	// PvtState keeps the name value pairs of each private data collection
+	PvtState map[string]map[string][]byte
+
+	// PvtEndorsementPolicies keeps the key-level endorsement policies of the
+	// keys in each private data collection
+	PvtEndorsementPolicies map[string]map[string][]byte
+
+	// Creator is the serialized identity returned by GetCreator, see MockCreator
+	Creator []byte
+
+	// TransientMap is the transient data returned by GetTransient
+	TransientMap map[string][]byte
+
+	// history keeps the modifications of each key in State, oldest first
+	history map[string][]*queryresult.KeyModification
+
 	// registered list of other MockStub chaincodes that can be called from this MockStub
 	Invokables map[string]*MockStub
 
@@ -55,6 +78,9 @@ type MockStub struct {
 	// mocked signedProposal
 	signedProposal *pb.SignedProposal
 
+	// binding of the mocked signedProposal
+	binding []byte
+
 	// stores a channel ID of the proposal
 	ChannelID string
 }
@@ -103,9 +129,17 @@ func (stub *MockStub) MockTransactionStart(txid string) {
 // End a mocked transaction, clearing the UUID.
 func (stub *MockStub) MockTransactionEnd(uuid string) {
 	stub.signedProposal = nil
+	stub.binding = nil
 	stub.TxID = ""
 }
 
+// MockCreator sets the identity returned by GetCreator to a serialized
+// identity of the given MSP, e.g. a PEM encoded certificate for an X.509 MSP.
+// This lets chaincode using the client identity library run against the stub.
+func (stub *MockStub) MockCreator(mspID string, idBytes []byte) {
+	stub.Creator = utils.MarshalOrPanic(&msp.SerializedIdentity{Mspid: mspID, IdBytes: idBytes})
+}
+
 // Register a peer chaincode with this MockStub
 // invokableChaincodeName is the name or hash of the peer
 // otherStub is a MockStub of the peer, already intialised
@@ -136,40 +170,152 @@ func (stub *MockStub) GetDecorations() map[string][]byte {
 }
 
 // Invoke this chaincode, also starts and ends a transaction.
+// The creator, transient map and binding are taken from the signed proposal,
+// as the peer does for a real transaction.
 func (stub *MockStub) MockInvokeWithSignedProposal(uuid string, args [][]byte, sp *pb.SignedProposal) pb.Response {
 	stub.args = args
 	stub.MockTransactionStart(uuid)
 	stub.signedProposal = sp
+	if sp != nil && len(sp.ProposalBytes) > 0 {
+		if err := stub.setProposalContext(sp); err != nil {
+			stub.MockTransactionEnd(uuid)
+			return Error(err.Error())
+		}
+	}
 	res := stub.cc.Invoke(stub)
 	stub.MockTransactionEnd(uuid)
 	return res
 }
 
+func (stub *MockStub) setProposalContext(sp *pb.SignedProposal) error {
+	proposal, err := utils.GetProposal(sp.ProposalBytes)
+	if err != nil {
+		return errors.WithMessage(err, "failed extracting proposal from signed proposal")
+	}
+	creator, transient, err := utils.GetChaincodeProposalContext(proposal)
+	if err != nil {
+		return errors.WithMessage(err, "failed extracting signed proposal fields")
+	}
+	binding, err := utils.ComputeProposalBinding(proposal)
+	if err != nil {
+		return errors.WithMessage(err, "failed computing binding from signed proposal")
+	}
+	stub.Creator = creator
+	stub.TransientMap = transient
+	stub.binding = binding
+	return nil
+}
+
+// GetPrivateData retrieves the value for a given key from a private data collection.
+// Unlike the peer, the stub sees private writes of the current transaction.
 func (stub *MockStub) GetPrivateData(collection string, key string) ([]byte, error) {
-	return nil, errors.New("Not Implemented")
+	if collection == "" {
+		return nil, errors.New("collection must not be an empty string")
+	}
+	return stub.PvtState[collection][key], nil
 }
 
+// PutPrivateData writes the specified `value` and `key` into a private data collection.
 func (stub *MockStub) PutPrivateData(collection string, key string, value []byte) error {
-	return errors.New("Not Implemented")
+	if collection == "" {
+		return errors.New("collection must not be an empty string")
+	}
+	if stub.TxID == "" {
+		err := errors.New("cannot PutPrivateData without a transactions - call stub.MockTransactionStart()?")
+		mockLogger.Errorf("%+v", err)
+		return err
+	}
+
+	mockLogger.Debug("MockStub", stub.Name, "Putting", key, value, "in collection", collection)
+	if _, ok := stub.PvtState[collection]; !ok {
+		stub.PvtState[collection] = make(map[string][]byte)
+	}
+	stub.PvtState[collection][key] = value
+	return nil
 }
 
+// DelPrivateData removes the specified `key` and its value from a private data collection.
 func (stub *MockStub) DelPrivateData(collection string, key string) error {
-	return errors.New("Not Implemented")
+	if collection == "" {
+		return errors.New("collection must not be an empty string")
+	}
+	mockLogger.Debug("MockStub", stub.Name, "Deleting", key, "from collection", collection)
+	delete(stub.PvtState[collection], key)
+	return nil
+}
+
+// SetPrivateDataValidationParameter sets the key-level endorsement policy of
+// the specified `key` in a private data collection.
+func (stub *MockStub) SetPrivateDataValidationParameter(collection, key string, ep []byte) error {
+	if collection == "" {
+		return errors.New("collection must not be an empty string")
+	}
+	if _, ok := stub.PvtEndorsementPolicies[collection]; !ok {
+		stub.PvtEndorsementPolicies[collection] = make(map[string][]byte)
+	}
+	stub.PvtEndorsementPolicies[collection][key] = ep
+	return nil
 }
 
+// GetPrivateDataValidationParameter retrieves the key-level endorsement policy
+// of the specified `key` in a private data collection.
+func (stub *MockStub) GetPrivateDataValidationParameter(collection, key string) ([]byte, error) {
+	if collection == "" {
+		return nil, errors.New("collection must not be an empty string")
+	}
+	return stub.PvtEndorsementPolicies[collection][key], nil
+}
+
+// GetPrivateDataByRange returns a range iterator over the keys of a private data
+// collection between the startKey (inclusive) and endKey (exclusive).
 func (stub *MockStub) GetPrivateDataByRange(collection, startKey, endKey string) (StateQueryIteratorInterface, error) {
-	return nil, errors.New("Not Implemented")
+	if collection == "" {
+		return nil, errors.New("collection must not be an empty string")
+	}
+	if err := validateSimpleKeys(startKey, endKey); err != nil {
+		return nil, err
+	}
+	return stub.newPvtDataRangeIterator(collection, startKey, endKey), nil
 }
 
+// GetPrivateDataByPartialCompositeKey queries a private data collection based
+// on a given partial composite key.
 func (stub *MockStub) GetPrivateDataByPartialCompositeKey(collection, objectType string, attributes []string) (StateQueryIteratorInterface, error) {
-	return nil, errors.New("Not Implemented")
+	if collection == "" {
+		return nil, errors.New("collection must not be an empty string")
+	}
+	partialCompositeKey, err := stub.CreateCompositeKey(objectType, attributes)
+	if err != nil {
+		return nil, err
+	}
+	return stub.newPvtDataRangeIterator(collection, partialCompositeKey, partialCompositeKey+string(maxUnicodeRuneValue)), nil
 }
 
+// GetPrivateDataQueryResult evaluates a CouchDB Mango query against a private
+// data collection. See GetQueryResult for the supported query syntax.
 func (stub *MockStub) GetPrivateDataQueryResult(collection, query string) (StateQueryIteratorInterface, error) {
-	// Not implemented since the mock engine does not have a query engine.
-	// However, a very simple query engine that supports string matching
-	// could be implemented to test that the framework supports queries
-	return nil, errors.New("Not Implemented")
+	if collection == "" {
+		return nil, errors.New("collection must not be an empty string")
+	}
+	q, err := parseMockQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	pvtState := stub.PvtState[collection]
+	results, err := q.execute(sortedKeys(pvtState), func(key string) []byte { return pvtState[key] })
+	if err != nil {
+		return nil, err
+	}
+	return &mockStateQueryIterator{results: results}, nil
+}
+
+func (stub *MockStub) newPvtDataRangeIterator(collection, startKey, endKey string) *mockStateQueryIterator {
+	pvtState := stub.PvtState[collection]
+	var results []*queryresult.KV
+	for _, key := range keysInRange(sortedKeys(pvtState), startKey, endKey) {
+		results = append(results, &queryresult.KV{Key: key, Value: pvtState[key]})
+	}
+	return &mockStateQueryIterator{results: results}
 }
 
 // GetState retrieves the value for a given key from the ledger
@@ -189,6 +335,7 @@ func (stub *MockStub) PutState(key string, value []byte) error {
 
 	mockLogger.Debug("MockStub", stub.Name, "Putting", key, value)
 	stub.State[key] = value
+	stub.recordHistory(key, value, false)
 
 	// insert key into ordered list of keys
 	for elem := stub.Keys.Front(); elem != nil; elem = elem.Next() {
@@ -227,6 +374,7 @@ func (stub *MockStub) PutState(key string, value []byte) error {
 func (stub *MockStub) DelState(key string) error {
 	mockLogger.Debug("MockStub", stub.Name, "Deleting", key, stub.State[key])
 	delete(stub.State, key)
+	stub.recordHistory(key, nil, true)
 
 	for elem := stub.Keys.Front(); elem != nil; elem = elem.Next() {
 		if strings.Compare(key, elem.Value.(string)) == 0 {
@@ -237,6 +385,18 @@ func (stub *MockStub) DelState(key string) error {
 	return nil
 }
 
+// SetStateValidationParameter sets the key-level endorsement policy of the specified `key`.
+func (stub *MockStub) SetStateValidationParameter(key string, ep []byte) error {
+	mockLogger.Debug("MockStub", stub.Name, "Setting validation parameter of", key)
+	stub.EndorsementPolicies[key] = ep
+	return nil
+}
+
+// GetStateValidationParameter retrieves the key-level endorsement policy of the specified `key`.
+func (stub *MockStub) GetStateValidationParameter(key string) ([]byte, error) {
+	return stub.EndorsementPolicies[key], nil
+}
+
 func (stub *MockStub) GetStateByRange(startKey, endKey string) (StateQueryIteratorInterface, error) {
 	if err := validateSimpleKeys(startKey, endKey); err != nil {
 		return nil, err
@@ -244,22 +404,130 @@ func (stub *MockStub) GetStateByRange(startKey, endKey string) (StateQueryIterat
 	return NewMockStateRangeQueryIterator(stub, startKey, endKey), nil
 }
 
-// GetQueryResult function can be invoked by a chaincode to perform a
-// rich query against state database.  Only supported by state database implementations
-// that support rich query.  The query string is in the syntax of the underlying
-// state database. An iterator is returned which can be used to iterate (next) over
-// the query result set
+// GetQueryResult evaluates a CouchDB Mango query against the state of the
+// stub, as the peer does with a CouchDB state database. The selector
+// supports the combination operators ($and, $or, $nor, $not), the condition
+// operators ($eq, $ne, $lt, $lte, $gt, $gte, $exists, $type, $in, $nin,
+// $size, $mod, $regex, $all, $elemMatch, $allMatch) and the `_id` field,
+// which holds the key. The sort, fields, limit and skip options are honored,
+// while options only relevant to CouchDB, such as use_index, are ignored.
+// Values that are not JSON objects never match a query.
 func (stub *MockStub) GetQueryResult(query string) (StateQueryIteratorInterface, error) {
-	// Not implemented since the mock engine does not have a query engine.
-	// However, a very simple query engine that supports string matching
-	// could be implemented to test that the framework supports queries
-	return nil, errors.New("not implemented")
+	results, err := stub.executeQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return &mockStateQueryIterator{results: results}, nil
+}
+
+// GetStateByRangeWithPagination returns at most pageSize keys between the
+// startKey (inclusive) and endKey (exclusive), starting at the bookmark if
+// one is given. The returned bookmark is the key the next page starts at.
+func (stub *MockStub) GetStateByRangeWithPagination(startKey, endKey string, pageSize int32,
+	bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	if err := validateSimpleKeys(startKey, endKey); err != nil {
+		return nil, nil, err
+	}
+	return stub.getStateByRangeWithPagination(startKey, endKey, pageSize, bookmark)
 }
 
-// GetHistoryForKey function can be invoked by a chaincode to return a history of
-// key values across time. GetHistoryForKey is intended to be used for read-only queries.
+// GetStateByPartialCompositeKeyWithPagination queries the state based on a
+// given partial composite key, returning at most pageSize keys per page.
+func (stub *MockStub) GetStateByPartialCompositeKeyWithPagination(objectType string, keys []string,
+	pageSize int32, bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	partialCompositeKey, err := stub.CreateCompositeKey(objectType, keys)
+	if err != nil {
+		return nil, nil, err
+	}
+	return stub.getStateByRangeWithPagination(partialCompositeKey, partialCompositeKey+string(maxUnicodeRuneValue), pageSize, bookmark)
+}
+
+// GetQueryResultWithPagination evaluates a CouchDB Mango query against the
+// state of the stub, returning at most pageSize results per page. The
+// bookmark is opaque and only valid for the query that returned it.
+func (stub *MockStub) GetQueryResultWithPagination(query string, pageSize int32,
+	bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	if err := validatePageSize(pageSize); err != nil {
+		return nil, nil, err
+	}
+	offset := 0
+	if bookmark != "" {
+		var err error
+		if offset, err = strconv.Atoi(bookmark); err != nil || offset < 0 {
+			return nil, nil, errors.Errorf("invalid bookmark %s", bookmark)
+		}
+	}
+	results, err := stub.executeQuery(query)
+	if err != nil {
+		return nil, nil, err
+	}
+	if offset > len(results) {
+		offset = len(results)
+	}
+	page, next := results[offset:], ""
+	if len(page) > int(pageSize) {
+		page = page[:pageSize]
+		next = strconv.Itoa(offset + int(pageSize))
+	}
+	metadata := &pb.QueryResponseMetadata{FetchedRecordsCount: int32(len(page)), Bookmark: next}
+	return &mockStateQueryIterator{results: page}, metadata, nil
+}
+
+func (stub *MockStub) executeQuery(query string) ([]*queryresult.KV, error) {
+	q, err := parseMockQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for elem := stub.Keys.Front(); elem != nil; elem = elem.Next() {
+		keys = append(keys, elem.Value.(string))
+	}
+	return q.execute(keys, func(key string) []byte { return stub.State[key] })
+}
+
+func (stub *MockStub) getStateByRangeWithPagination(startKey, endKey string, pageSize int32,
+	bookmark string) (StateQueryIteratorInterface, *pb.QueryResponseMetadata, error) {
+	if err := validatePageSize(pageSize); err != nil {
+		return nil, nil, err
+	}
+	// the bookmark of a range query is the key to resume the scan from
+	if bookmark != "" {
+		startKey = bookmark
+	}
+	var keys []string
+	for elem := stub.Keys.Front(); elem != nil; elem = elem.Next() {
+		keys = append(keys, elem.Value.(string))
+	}
+	keys = keysInRange(keys, startKey, endKey)
+
+	next := ""
+	if len(keys) > int(pageSize) {
+		next = keys[pageSize]
+		keys = keys[:pageSize]
+	}
+	results := make([]*queryresult.KV, 0, len(keys))
+	for _, key := range keys {
+		results = append(results, &queryresult.KV{Key: key, Value: stub.State[key]})
+	}
+	metadata := &pb.QueryResponseMetadata{FetchedRecordsCount: int32(len(results)), Bookmark: next}
+	return &mockStateQueryIterator{results: results}, metadata, nil
+}
+
+// GetHistoryForKey returns the modifications of a key made through the stub,
+// oldest first. Deletions are reported with IsDelete set.
 func (stub *MockStub) GetHistoryForKey(key string) (HistoryQueryIteratorInterface, error) {
-	return nil, errors.New("not implemented")
+	modifications := make([]*queryresult.KeyModification, len(stub.history[key]))
+	copy(modifications, stub.history[key])
+	return &mockHistoryQueryIterator{results: modifications}, nil
+}
+
+func (stub *MockStub) recordHistory(key string, value []byte, isDelete bool) {
+	stub.history[key] = append(stub.history[key], &queryresult.KeyModification{
+		TxId:      stub.TxID,
+		Value:     value,
+		Timestamp: stub.TxTimestamp,
+		IsDelete:  isDelete,
+	})
 }
 
 //GetStateByPartialCompositeKey function can be invoked by a chaincode to query the
@@ -306,19 +574,22 @@ func (stub *MockStub) InvokeChaincode(chaincodeName string, args [][]byte, chann
 	return res
 }
 
-// Not implemented
+// GetCreator returns the identity set with MockCreator or taken from the
+// signed proposal of MockInvokeWithSignedProposal.
 func (stub *MockStub) GetCreator() ([]byte, error) {
-	return nil, nil
+	return stub.Creator, nil
 }
 
-// Not implemented
+// GetTransient returns the TransientMap of the stub, which is also taken
+// from the signed proposal of MockInvokeWithSignedProposal.
 func (stub *MockStub) GetTransient() (map[string][]byte, error) {
-	return nil, nil
+	return stub.TransientMap, nil
 }
 
-// Not implemented
+// GetBinding returns the binding of the signed proposal passed to
+// MockInvokeWithSignedProposal, or nil outside of such a transaction.
 func (stub *MockStub) GetBinding() ([]byte, error) {
-	return nil, nil
+	return stub.binding, nil
 }
 
 // Not implemented
@@ -358,6 +629,11 @@ func NewMockStub(name string, cc Chaincode) *MockStub {
 	s.Name = name
 	s.cc = cc
 	s.State = make(map[string][]byte)
+	s.EndorsementPolicies = make(map[string][]byte)
+	s.PvtState = make(map[string]map[string][]byte)
+	s.PvtEndorsementPolicies = make(map[string]map[string][]byte)
+	s.TransientMap = make(map[string][]byte)
+	s.history = make(map[string][]*queryresult.KeyModification)
 	s.Invokables = make(map[string]*MockStub)
 	s.Keys = list.New()
 
@@ -486,6 +762,85 @@ func NewMockStateRangeQueryIterator(stub *MockStub, startKey string, endKey stri
 	return iter
 }
 
+/*****************************
+ Query Iterators
+*****************************/
+
+// mockStateQueryIterator iterates over precomputed query results
+type mockStateQueryIterator struct {
+	results []*queryresult.KV
+	current int
+	closed  bool
+}
+
+func (iter *mockStateQueryIterator) HasNext() bool {
+	return !iter.closed && iter.current < len(iter.results)
+}
+
+func (iter *mockStateQueryIterator) Next() (*queryresult.KV, error) {
+	if !iter.HasNext() {
+		return nil, errors.New("no more query results")
+	}
+	result := iter.results[iter.current]
+	iter.current++
+	return result, nil
+}
+
+func (iter *mockStateQueryIterator) Close() error {
+	iter.closed = true
+	return nil
+}
+
+// mockHistoryQueryIterator iterates over the modifications of a key
+type mockHistoryQueryIterator struct {
+	results []*queryresult.KeyModification
+	current int
+	closed  bool
+}
+
+func (iter *mockHistoryQueryIterator) HasNext() bool {
+	return !iter.closed && iter.current < len(iter.results)
+}
+
+func (iter *mockHistoryQueryIterator) Next() (*queryresult.KeyModification, error) {
+	if !iter.HasNext() {
+		return nil, errors.New("no more history results")
+	}
+	result := iter.results[iter.current]
+	iter.current++
+	return result, nil
+}
+
+func (iter *mockHistoryQueryIterator) Close() error {
+	iter.closed = true
+	return nil
+}
+
+func sortedKeys(state map[string][]byte) []string {
+	keys := make([]string, 0, len(state))
+	for key := range state {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// keysInRange filters sorted keys to those between startKey (inclusive) and
+// endKey (exclusive). Empty keys leave the range unbounded.
+func keysInRange(keys []string, startKey, endKey string) []string {
+	var inRange []string
+	for _, key := range keys {
+		if key < startKey {
+			continue
+		}
+		if endKey != "" && key >= endKey {
+			break
+		}
+		inRange = append(inRange, key)
+	}
+	return inRange
+}
+
 func getBytes(function string, args []string) [][]byte {
 	bytes := make([][]byte, 0, len(args)+1)
 	bytes = append(bytes, []byte(function))
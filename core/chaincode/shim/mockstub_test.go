@@ -22,8 +22,14 @@ import (
 	"reflect"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestMockStateRangeQueryIterator(t *testing.T) {
@@ -271,3 +277,324 @@ func TestMockMock(t *testing.T) {
 	getBytes("f", []string{"a", "b"})
 	getFuncArgs([][]byte{[]byte("a")})
 }
+
+func putMarbles(t *testing.T, stub *MockStub, marbles ...*Marble) {
+	stub.MockTransactionStart("init")
+	for _, marble := range marbles {
+		marbleJSONBytes, _ := json.Marshal(marble)
+		assert.NoError(t, stub.PutState(marble.Name, marbleJSONBytes))
+	}
+	stub.MockTransactionEnd("init")
+}
+
+func queryKeys(t *testing.T, iter StateQueryIteratorInterface) []string {
+	var keys []string
+	for iter.HasNext() {
+		kv, err := iter.Next()
+		assert.NoError(t, err)
+		keys = append(keys, kv.Key)
+	}
+	assert.NoError(t, iter.Close())
+	return keys
+}
+
+func TestMockStubGetQueryResult(t *testing.T) {
+	stub := NewMockStub("GetQueryResultTest", nil)
+	putMarbles(t, stub,
+		&Marble{"marble", "marble1", "red", 35, "tom"},
+		&Marble{"marble", "marble2", "blue", 50, "jerry"},
+		&Marble{"marble", "marble3", "red", 10, "jerry"},
+		&Marble{"marble", "marble4", "green", 20, "tom"},
+	)
+	stub.MockTransactionStart("init")
+	stub.PutState("notjson", []byte("not a JSON document"))
+	stub.MockTransactionEnd("init")
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{`{"selector":{}}`, []string{"marble1", "marble2", "marble3", "marble4"}},
+		{`{"selector":{"owner":"tom"}}`, []string{"marble1", "marble4"}},
+		{`{"selector":{"owner":"tom","color":"red"}}`, []string{"marble1"}},
+		{`{"selector":{"size":{"$gt":10,"$lte":35}}}`, []string{"marble1", "marble4"}},
+		{`{"selector":{"$or":[{"color":"blue"},{"size":{"$lt":15}}]}}`, []string{"marble2", "marble3"}},
+		{`{"selector":{"$not":{"owner":"tom"}}}`, []string{"marble2", "marble3"}},
+		{`{"selector":{"$nor":[{"owner":"tom"},{"color":"blue"}]}}`, []string{"marble3"}},
+		{`{"selector":{"color":{"$in":["green","blue"]}}}`, []string{"marble2", "marble4"}},
+		{`{"selector":{"color":{"$nin":["green","blue"]}}}`, []string{"marble1", "marble3"}},
+		{`{"selector":{"name":{"$regex":"^marble[12]$"}}}`, []string{"marble1", "marble2"}},
+		{`{"selector":{"size":{"$mod":[10,0]}}}`, []string{"marble2", "marble3", "marble4"}},
+		{`{"selector":{"owner":{"$exists":false}}}`, nil},
+		{`{"selector":{"size":{"$type":"number"}}}`, []string{"marble1", "marble2", "marble3", "marble4"}},
+		{`{"selector":{"_id":{"$gte":"marble3"}}}`, []string{"marble3", "marble4"}},
+		{`{"selector":{"docType":"marble"},"sort":[{"size":"desc"}]}`, []string{"marble2", "marble1", "marble4", "marble3"}},
+		{`{"selector":{"docType":"marble"},"sort":["owner",{"size":"asc"}]}`, []string{"marble3", "marble2", "marble4", "marble1"}},
+		{`{"selector":{"docType":"marble"},"sort":["size"],"limit":2,"skip":1}`, []string{"marble4", "marble1"}},
+		{`{"selector":{"docType":"marble"},"use_index":["_design/indexOwnerDoc","indexOwner"]}`, []string{"marble1", "marble2", "marble3", "marble4"}},
+	}
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			iter, err := stub.GetQueryResult(test.query)
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, queryKeys(t, iter))
+		})
+	}
+
+	// fields projects the returned documents
+	iter, err := stub.GetQueryResult(`{"selector":{"name":"marble1"},"fields":["name","owner"]}`)
+	assert.NoError(t, err)
+	kv, err := iter.Next()
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"name":"marble1","owner":"tom"}`, string(kv.Value))
+
+	// without fields the stored value is returned as is
+	iter, err = stub.GetQueryResult(`{"selector":{"name":"marble2"}}`)
+	assert.NoError(t, err)
+	kv, err = iter.Next()
+	assert.NoError(t, err)
+	assert.Equal(t, stub.State["marble2"], kv.Value)
+
+	for _, query := range []string{
+		`not json`,
+		`{"fields":["name"]}`,
+		`{"selector":{"size":{"$bogus":1}}}`,
+		`{"selector":{"$or":{"owner":"tom"}}}`,
+		`{"selector":{},"limit":-1}`,
+		`{"selector":{},"sort":[{"size":"up"}]}`,
+	} {
+		_, err := stub.GetQueryResult(query)
+		assert.Error(t, err, query)
+	}
+}
+
+func TestMockStubQueryNestedFields(t *testing.T) {
+	stub := NewMockStub("NestedQueryTest", nil)
+	stub.MockTransactionStart("init")
+	stub.PutState("asset1", []byte(`{"owner":{"name":"tom","org":"org1"},"tags":["a","b"],"readings":[{"v":1},{"v":5}]}`))
+	stub.PutState("asset2", []byte(`{"owner":{"name":"jerry","org":"org2"},"tags":["b"],"readings":[{"v":7}]}`))
+	stub.MockTransactionEnd("init")
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{`{"selector":{"owner.org":"org1"}}`, []string{"asset1"}},
+		{`{"selector":{"owner":{"name":"jerry"}}}`, []string{"asset2"}},
+		{`{"selector":{"tags":{"$all":["a","b"]}}}`, []string{"asset1"}},
+		{`{"selector":{"tags":{"$size":1}}}`, []string{"asset2"}},
+		{`{"selector":{"tags.0":"b"}}`, []string{"asset2"}},
+		{`{"selector":{"tags":{"$in":["a"]}}}`, []string{"asset1"}},
+		{`{"selector":{"readings":{"$elemMatch":{"v":{"$gt":4}}}}}`, []string{"asset1", "asset2"}},
+		{`{"selector":{"readings":{"$allMatch":{"v":{"$gt":4}}}}}`, []string{"asset2"}},
+	}
+	for _, test := range tests {
+		iter, err := stub.GetQueryResult(test.query)
+		assert.NoError(t, err, test.query)
+		assert.Equal(t, test.expected, queryKeys(t, iter), test.query)
+	}
+
+	iter, err := stub.GetQueryResult(`{"selector":{"owner.name":"tom"},"fields":["owner.org"]}`)
+	assert.NoError(t, err)
+	kv, _ := iter.Next()
+	assert.JSONEq(t, `{"owner":{"org":"org1"}}`, string(kv.Value))
+}
+
+func TestMockStubPagination(t *testing.T) {
+	stub := NewMockStub("PaginationTest", nil)
+	putMarbles(t, stub,
+		&Marble{"marble", "marble1", "red", 35, "tom"},
+		&Marble{"marble", "marble2", "blue", 50, "jerry"},
+		&Marble{"marble", "marble3", "red", 10, "jerry"},
+		&Marble{"marble", "marble4", "green", 20, "tom"},
+		&Marble{"marble", "marble5", "red", 5, "tom"},
+	)
+
+	var keys []string
+	bookmark := ""
+	for pages := 1; ; pages++ {
+		iter, metadata, err := stub.GetStateByRangeWithPagination("marble1", "marble5", 2, bookmark)
+		assert.NoError(t, err)
+		keys = append(keys, queryKeys(t, iter)...)
+		if metadata.Bookmark == "" {
+			assert.Equal(t, 2, pages)
+			assert.Equal(t, int32(2), metadata.FetchedRecordsCount)
+			break
+		}
+		bookmark = metadata.Bookmark
+	}
+	assert.Equal(t, []string{"marble1", "marble2", "marble3", "marble4"}, keys)
+
+	keys, bookmark = nil, ""
+	for pages := 1; ; pages++ {
+		iter, metadata, err := stub.GetQueryResultWithPagination(`{"selector":{"color":"red"}}`, 2, bookmark)
+		assert.NoError(t, err)
+		keys = append(keys, queryKeys(t, iter)...)
+		if metadata.Bookmark == "" {
+			assert.Equal(t, 2, pages)
+			assert.Equal(t, int32(1), metadata.FetchedRecordsCount)
+			break
+		}
+		bookmark = metadata.Bookmark
+	}
+	assert.Equal(t, []string{"marble1", "marble3", "marble5"}, keys)
+
+	_, _, err := stub.GetQueryResultWithPagination(`{"selector":{}}`, 2, "not a bookmark")
+	assert.EqualError(t, err, "invalid bookmark not a bookmark")
+	_, _, err = stub.GetStateByRangeWithPagination("", "", 0, "")
+	assert.EqualError(t, err, "invalid page size 0, must be greater than zero")
+
+	stub.MockTransactionStart("init")
+	for _, color := range []string{"blue", "green", "red"} {
+		key, _ := stub.CreateCompositeKey("color~name", []string{color, "marble"})
+		stub.PutState(key, []byte{0})
+	}
+	stub.MockTransactionEnd("init")
+	iter, metadata, err := stub.GetStateByPartialCompositeKeyWithPagination("color~name", []string{}, 2, "")
+	assert.NoError(t, err)
+	assert.Len(t, queryKeys(t, iter), 2)
+	iter, _, err = stub.GetStateByPartialCompositeKeyWithPagination("color~name", []string{}, 2, metadata.Bookmark)
+	assert.NoError(t, err)
+	assert.Len(t, queryKeys(t, iter), 1)
+}
+
+func TestMockStubPrivateData(t *testing.T) {
+	stub := NewMockStub("PrivateDataTest", nil)
+
+	assert.Error(t, stub.PutPrivateData("coll1", "key1", []byte("value")), "writes require a transaction")
+
+	stub.MockTransactionStart("init")
+	assert.NoError(t, stub.PutPrivateData("coll1", "key1", []byte(`{"owner":"tom"}`)))
+	assert.NoError(t, stub.PutPrivateData("coll1", "key2", []byte(`{"owner":"jerry"}`)))
+	assert.NoError(t, stub.PutPrivateData("coll1", "key3", []byte(`{"owner":"tom"}`)))
+	assert.NoError(t, stub.PutPrivateData("coll2", "key1", []byte("other")))
+	assert.NoError(t, stub.SetPrivateDataValidationParameter("coll1", "key1", []byte("policy")))
+	_, err := stub.GetPrivateData("", "key1")
+	assert.Error(t, err)
+	stub.MockTransactionEnd("init")
+
+	value, err := stub.GetPrivateData("coll1", "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(`{"owner":"tom"}`), value)
+	value, err = stub.GetPrivateData("coll2", "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("other"), value)
+	value, err = stub.GetState("key1")
+	assert.NoError(t, err)
+	assert.Nil(t, value, "private data must not leak into the public state")
+
+	ep, err := stub.GetPrivateDataValidationParameter("coll1", "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("policy"), ep)
+
+	iter, err := stub.GetPrivateDataByRange("coll1", "key2", "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"key2", "key3"}, queryKeys(t, iter))
+
+	iter, err = stub.GetPrivateDataQueryResult("coll1", `{"selector":{"owner":"tom"}}`)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"key1", "key3"}, queryKeys(t, iter))
+
+	stub.MockTransactionStart("del")
+	key, _ := stub.CreateCompositeKey("owner~key", []string{"tom", "key1"})
+	assert.NoError(t, stub.PutPrivateData("coll1", key, []byte{0}))
+	assert.NoError(t, stub.DelPrivateData("coll1", "key2"))
+	stub.MockTransactionEnd("del")
+
+	iter, err = stub.GetPrivateDataByPartialCompositeKey("coll1", "owner~key", []string{"tom"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{key}, queryKeys(t, iter))
+	value, _ = stub.GetPrivateData("coll1", "key2")
+	assert.Nil(t, value)
+}
+
+func TestMockStubGetHistoryForKey(t *testing.T) {
+	stub := NewMockStub("HistoryTest", nil)
+	stub.MockTransactionStart("tx1")
+	stub.PutState("key", []byte("v1"))
+	stub.MockTransactionEnd("tx1")
+	stub.MockTransactionStart("tx2")
+	stub.PutState("key", []byte("v2"))
+	stub.MockTransactionEnd("tx2")
+	stub.MockTransactionStart("tx3")
+	stub.DelState("key")
+	stub.MockTransactionEnd("tx3")
+
+	iter, err := stub.GetHistoryForKey("key")
+	assert.NoError(t, err)
+	var txIDs []string
+	for iter.HasNext() {
+		modification, err := iter.Next()
+		assert.NoError(t, err)
+		assert.NotNil(t, modification.Timestamp)
+		txIDs = append(txIDs, modification.TxId)
+		if modification.TxId == "tx3" {
+			assert.True(t, modification.IsDelete)
+		} else {
+			assert.False(t, modification.IsDelete)
+		}
+	}
+	assert.Equal(t, []string{"tx1", "tx2", "tx3"}, txIDs)
+	_, err = iter.Next()
+	assert.Error(t, err)
+
+	iter, err = stub.GetHistoryForKey("missing")
+	assert.NoError(t, err)
+	assert.False(t, iter.HasNext())
+}
+
+func TestMockStubProposalContext(t *testing.T) {
+	stub := NewMockStub("ProposalContextTest", nil)
+
+	stub.MockCreator("Org1MSP", []byte("certificate"))
+	creator, err := stub.GetCreator()
+	assert.NoError(t, err)
+	sid := &msp.SerializedIdentity{}
+	assert.NoError(t, proto.Unmarshal(creator, sid))
+	assert.Equal(t, "Org1MSP", sid.Mspid)
+	assert.Equal(t, []byte("certificate"), sid.IdBytes)
+
+	stub.TransientMap["secret"] = []byte("value")
+	transient, err := stub.GetTransient()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value"), transient["secret"])
+
+	// the creator, transient map and binding come from the signed proposal
+	stub = NewMockStub("ProposalContextTest", &shimTestCC{})
+	cis := &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "cc"}}}
+	proposal, _, err := utils.CreateChaincodeProposalWithTransient(common.HeaderType_ENDORSER_TRANSACTION, "testchannel", cis,
+		[]byte("creator"), map[string][]byte{"key": []byte("transient")})
+	assert.NoError(t, err)
+	sp := &pb.SignedProposal{ProposalBytes: utils.MarshalOrPanic(proposal)}
+	expectedBinding, err := utils.ComputeProposalBinding(proposal)
+	assert.NoError(t, err)
+
+	stub.cc = &contextCheckCC{t: t, creator: []byte("creator"), transient: []byte("transient"), binding: expectedBinding}
+	res := stub.MockInvokeWithSignedProposal("tx1", [][]byte{[]byte("check")}, sp)
+	assert.Equal(t, int32(OK), res.Status, res.Message)
+
+	res = stub.MockInvokeWithSignedProposal("tx2", nil, &pb.SignedProposal{ProposalBytes: []byte("garbage")})
+	assert.Equal(t, int32(ERROR), res.Status)
+}
+
+// contextCheckCC verifies the proposal context seen by the chaincode
+type contextCheckCC struct {
+	t         *testing.T
+	creator   []byte
+	transient []byte
+	binding   []byte
+}
+
+func (cc *contextCheckCC) Init(stub ChaincodeStubInterface) pb.Response {
+	return Success(nil)
+}
+
+func (cc *contextCheckCC) Invoke(stub ChaincodeStubInterface) pb.Response {
+	creator, _ := stub.GetCreator()
+	transient, _ := stub.GetTransient()
+	binding, _ := stub.GetBinding()
+	assert.Equal(cc.t, cc.creator, creator)
+	assert.Equal(cc.t, cc.transient, transient["key"])
+	assert.Equal(cc.t, cc.binding, binding)
+	return Success(nil)
+}
@@ -0,0 +1,102 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shim
+
+import (
+	"github.com/hyperledger/fabric/core/comm"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+)
+
+// TLSProperties passed to ChaincodeServer
+type TLSProperties struct {
+	// Disabled disables TLS for the chaincode server
+	Disabled bool
+	// Key is the PEM encoded private key of the server
+	Key []byte
+	// Cert is the PEM encoded certificate of the server
+	Cert []byte
+	// ClientCACerts are the PEM encoded certificates of the CAs used to
+	// verify the peer. Client authentication is required when set.
+	ClientCACerts []byte
+}
+
+// ChaincodeServer runs chaincode as a service which the peer connects to,
+// as opposed to chaincode started through Start which connects to the peer
+type ChaincodeServer struct {
+	// CCID is the name the chaincode registers with. It must match the
+	// name and version of the chaincode installed on the peer, in the
+	// form name:version
+	CCID string
+	// Address is the address the server listens on
+	Address string
+	// CC is the chaincode the requests are dispatched to
+	CC Chaincode
+	// TLSProps is the TLS configuration of the server
+	TLSProps TLSProperties
+	// KaOpts are the keepalive options, the defaults are used when nil
+	KaOpts *comm.KeepaliveOptions
+}
+
+// serverStream adapts the server side of the Connect stream to
+// PeerChaincodeStream
+type serverStream struct {
+	pb.Chaincode_ConnectServer
+}
+
+// CloseSend is a no-op as the stream is closed when Connect returns
+func (s *serverStream) CloseSend() error {
+	return nil
+}
+
+// Connect handles a connection from the peer. The exchange is the same
+// as the one driven by Start, only the direction of the dial differs.
+func (cs *ChaincodeServer) Connect(stream pb.Chaincode_ConnectServer) error {
+	return chatWithPeer(cs.CCID, &serverStream{stream}, cs.CC)
+}
+
+// Start listens on the configured address and serves the chaincode. It
+// blocks until the server stops.
+func (cs *ChaincodeServer) Start() error {
+	if cs.CCID == "" {
+		return errors.New("ccid must be specified")
+	}
+	if cs.Address == "" {
+		return errors.New("address must be specified")
+	}
+	if cs.CC == nil {
+		return errors.New("chaincode must be specified")
+	}
+
+	secOpts := &comm.SecureOptions{}
+	if !cs.TLSProps.Disabled {
+		if cs.TLSProps.Key == nil || cs.TLSProps.Cert == nil {
+			return errors.New("key and certificate must be specified when TLS is enabled")
+		}
+		secOpts.UseTLS = true
+		secOpts.Key = cs.TLSProps.Key
+		secOpts.Certificate = cs.TLSProps.Cert
+		if cs.TLSProps.ClientCACerts != nil {
+			secOpts.RequireClientCert = true
+			secOpts.ClientRootCAs = [][]byte{cs.TLSProps.ClientCACerts}
+		}
+	}
+
+	kaOpts := cs.KaOpts
+	if kaOpts == nil {
+		kaOpts = comm.DefaultKeepaliveOptions()
+	}
+
+	server, err := comm.NewGRPCServer(cs.Address, comm.ServerConfig{SecOpts: secOpts, KaOpts: kaOpts})
+	if err != nil {
+		return errors.WithMessage(err, "error creating chaincode server")
+	}
+	pb.RegisterChaincodeServer(server.Server(), cs)
+
+	chaincodeLogger.Infof("Chaincode %s listening on %s", cs.CCID, cs.Address)
+	return server.Start()
+}
@@ -0,0 +1,40 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package shim
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChaincodeServerStartErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		server *ChaincodeServer
+		err    string
+	}{
+		{"no ccid", &ChaincodeServer{Address: "127.0.0.1:0", CC: &shimTestCC{}}, "ccid must be specified"},
+		{"no address", &ChaincodeServer{CCID: "cc:1.0", CC: &shimTestCC{}}, "address must be specified"},
+		{"no chaincode", &ChaincodeServer{CCID: "cc:1.0", Address: "127.0.0.1:0"}, "chaincode must be specified"},
+		{"no tls key", &ChaincodeServer{CCID: "cc:1.0", Address: "127.0.0.1:0", CC: &shimTestCC{}}, "key and certificate must be specified when TLS is enabled"},
+		{"bad address", &ChaincodeServer{CCID: "cc:1.0", Address: "bad address", CC: &shimTestCC{}, TLSProps: TLSProperties{Disabled: true}}, "error creating chaincode server"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.server.Start()
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func TestServerStreamCloseSend(t *testing.T) {
+	s := &serverStream{}
+	assert.NoError(t, s.CloseSend())
+}
@@ -24,6 +24,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
 	mockpeer "github.com/hyperledger/fabric/common/mocks/peer"
 	"github.com/hyperledger/fabric/common/util"
@@ -979,3 +980,43 @@ func TestSend(t *testing.T) {
 	err := stream.Send(msg)
 	assert.NotNil(t, err, "should have errored on panic")
 }
+
+func TestQueryPagination(t *testing.T) {
+	stub := &ChaincodeStub{}
+
+	// invalid page sizes are rejected before reaching the peer
+	_, _, err := stub.GetStateByRangeWithPagination("A", "B", 0, "")
+	assert.EqualError(t, err, "invalid page size 0, must be greater than zero")
+	_, _, err = stub.GetStateByPartialCompositeKeyWithPagination("color~name", []string{"blue"}, -1, "")
+	assert.EqualError(t, err, "invalid page size -1, must be greater than zero")
+	_, _, err = stub.GetQueryResultWithPagination(`{"selector":{}}`, 0, "")
+	assert.EqualError(t, err, "invalid page size 0, must be greater than zero")
+
+	metadata, err := createQueryMetadata(10, "B")
+	assert.NoError(t, err)
+	queryMetadata := &pb.QueryMetadata{}
+	assert.NoError(t, proto.Unmarshal(metadata, queryMetadata))
+	assert.Equal(t, int32(10), queryMetadata.PageSize)
+	assert.Equal(t, "B", queryMetadata.Bookmark)
+
+	// the response metadata is only returned for paginated queries
+	iterator, responseMetadata, err := stub.createStateQueryIterator(&pb.QueryResponse{})
+	assert.NoError(t, err)
+	assert.NotNil(t, iterator)
+	assert.Nil(t, responseMetadata)
+
+	response := &pb.QueryResponse{
+		Results: []*pb.QueryResultBytes{
+			{ResultBytes: utils.MarshalOrPanic(&lproto.KV{Namespace: "getputcc", Key: "A", Value: []byte("100")})},
+		},
+		Metadata: utils.MarshalOrPanic(&pb.QueryResponseMetadata{FetchedRecordsCount: 1, Bookmark: "B"}),
+	}
+	iterator, responseMetadata, err = stub.createStateQueryIterator(response)
+	assert.NoError(t, err)
+	assert.True(t, iterator.HasNext())
+	assert.Equal(t, int32(1), responseMetadata.FetchedRecordsCount)
+	assert.Equal(t, "B", responseMetadata.Bookmark)
+
+	_, _, err = stub.createStateQueryIterator(&pb.QueryResponse{Metadata: []byte("garbage")})
+	assert.Error(t, err)
+}
@@ -42,6 +42,14 @@ type Committer interface {
 	// collections and namespaces of private data to retrieve
 	GetPvtDataByNum(blockNum uint64, filter ledger.PvtNsCollFilter) ([]*ledger.TxPvtData, error)
 
+	// CommitPvtDataOfOldBlocks commits the private data that was missing
+	// at the time of the commit of the corresponding blocks
+	CommitPvtDataOfOldBlocks(blocksPvtData []*ledger.BlockPvtData) error
+
+	// GetMissingPvtDataInfoForMostRecentBlocks returns the private data that is
+	// still missing for at most maxBlocks most recent blocks
+	GetMissingPvtDataInfoForMostRecentBlocks(maxBlocks int) (ledger.MissingPvtDataInfo, error)
+
 	// Get recent block sequence number
 	LedgerHeight() (uint64, error)
 
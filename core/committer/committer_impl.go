@@ -17,7 +17,10 @@ limitations under the License.
 package committer
 
 import (
+	"time"
+
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/events/producer"
 	"github.com/hyperledger/fabric/protos/common"
@@ -84,9 +87,11 @@ func (lc *LedgerCommitter) CommitWithPvtData(blockAndPvtData *ledger.BlockAndPvt
 	}
 
 	// Committing new block
+	startTime := time.Now()
 	if err := lc.PeerLedger.CommitWithPvtData(blockAndPvtData); err != nil {
 		return err
 	}
+	lc.reportCommitMetrics(blockAndPvtData.Block, time.Since(startTime))
 
 	// post commit actions, such as event publishing
 	lc.postCommit(blockAndPvtData.Block)
@@ -99,6 +104,19 @@ func (lc *LedgerCommitter) GetPvtDataAndBlockByNum(seqNum uint64) (*ledger.Block
 	return lc.PeerLedger.GetPvtDataAndBlockByNum(seqNum, nil)
 }
 
+// reportCommitMetrics reports the duration of the commit of the block
+// and the height of the blockchain of the block's channel
+func (lc *LedgerCommitter) reportCommitMetrics(block *common.Block, commitDuration time.Duration) {
+	channelID, err := utils.GetChainIDFromBlock(block)
+	if err != nil {
+		logger.Warningf("Failed extracting channel from block [%d]: %s", block.Header.Number, err)
+		return
+	}
+	scope := metrics.RootScope.SubScope("committer").Tagged(map[string]string{"channel": channelID})
+	scope.Timer("block_commit_duration").Record(commitDuration)
+	scope.Gauge("blockchain_height").Update(float64(block.Header.Number + 1))
+}
+
 // postCommit publish event or handle other tasks once block committed to the ledger
 func (lc *LedgerCommitter) postCommit(block *common.Block) {
 	// create/send block events *after* the block has been committed
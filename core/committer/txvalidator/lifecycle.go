@@ -0,0 +1,148 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txvalidator
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/core/common/lifecycle"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// validateLifecycleTx validates an invocation of the lifecycle system
+// chaincode. An approval must be submitted by an admin of the approving
+// org, while the endorsements of the commit of a definition must satisfy
+// the lifecycle endorsement policy of the channel, or come from the peers
+// of a majority of its orgs if there is none.
+func (v *vsccValidatorImpl) validateLifecycleTx(chdr *common.ChannelHeader, envBytes []byte, wrNsRWSets map[string]*rwsetutil.NsRwSet) (error, peer.TxValidationCode) {
+	for ns := range wrNsRWSets {
+		if ns != lifecycle.Namespace {
+			return errors.Errorf("chaincode %s attempted to write to the namespace of %s", lifecycle.Namespace, ns),
+				peer.TxValidationCode_ILLEGAL_WRITESET
+		}
+	}
+
+	nsRWSet, ok := wrNsRWSets[lifecycle.Namespace]
+	if !ok {
+		// nothing was written, there is nothing to authorize
+		return nil, peer.TxValidationCode_VALID
+	}
+	if len(nsRWSet.CollHashedRwSets) > 0 || len(nsRWSet.KvRwSet.MetadataWrites) > 0 || len(nsRWSet.KvRwSet.Writes) != 1 {
+		return errors.Errorf("invocation of %s must write exactly one key", lifecycle.Namespace),
+			peer.TxValidationCode_ILLEGAL_WRITESET
+	}
+
+	write := nsRWSet.KvRwSet.Writes[0]
+	pol, signedDataOf, err := v.lifecyclePolicy(chdr.ChannelId, write.Key)
+	if err != nil {
+		return err, peer.TxValidationCode_ILLEGAL_WRITESET
+	}
+
+	signedData, err := signedDataOf(envBytes)
+	if err != nil {
+		return err, peer.TxValidationCode_INVALID_OTHER_REASON
+	}
+	if err = pol.Evaluate(signedData); err != nil {
+		return errors.WithMessage(err, "lifecycle endorsement policy failure"), peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE
+	}
+	return nil, peer.TxValidationCode_VALID
+}
+
+// lifecyclePolicy returns the policy a write of the given key in the
+// lifecycle namespace has to satisfy, along with the function extracting
+// the signatures the policy is evaluated against
+func (v *vsccValidatorImpl) lifecyclePolicy(channel, key string) (policies.Policy, func([]byte) ([]*common.SignedData, error), error) {
+	var envelope *common.SignaturePolicyEnvelope
+	signedDataOf := endorsementSignedData
+	if _, err := lifecycle.ParseDefinitionKey(key); err == nil {
+		if pm := v.support.PolicyManager(); pm != nil {
+			if pol, ok := pm.GetPolicy(lifecycle.EndorsementPolicyPath); ok {
+				return pol, signedDataOf, nil
+			}
+		}
+		envelope = lifecycle.MajorityPolicy(v.support.GetMSPIDs(channel))
+	} else if _, _, org, err := lifecycle.ParseApprovalKey(key); err == nil {
+		// an org approves a definition through its admins, which sign the
+		// transaction as its creator
+		envelope = cauthdsl.SignedByMspAdmin(org)
+		signedDataOf = creatorSignedData
+	} else {
+		return nil, nil, errors.Errorf("invalid write of key %s to the namespace of %s", key, lifecycle.Namespace)
+	}
+
+	policyBytes, err := proto.Marshal(envelope)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not marshal lifecycle policy")
+	}
+	pol, _, err := cauthdsl.NewPolicyProvider(v.support.MSPManager()).NewPolicy(policyBytes)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "could not create lifecycle policy")
+	}
+	return pol, signedDataOf, nil
+}
+
+// creatorSignedData returns the signature of the creator of a transaction
+func creatorSignedData(envBytes []byte) ([]*common.SignedData, error) {
+	env, err := utils.GetEnvelopeFromBlock(envBytes)
+	if err != nil {
+		return nil, err
+	}
+	return env.AsSignedData()
+}
+
+// endorsementSignedData returns the signatures of the endorsers
+// of a transaction, one per endorsing identity
+func endorsementSignedData(envBytes []byte) ([]*common.SignedData, error) {
+	env, err := utils.GetEnvelopeFromBlock(envBytes)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := utils.GetPayload(env)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := utils.GetTransaction(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+	if len(tx.Actions) != 1 {
+		return nil, errors.Errorf("expected exactly one action, got %d", len(tx.Actions))
+	}
+	cap, err := utils.GetChaincodeActionPayload(tx.Actions[0].Payload)
+	if err != nil {
+		return nil, err
+	}
+	if cap.Action == nil {
+		return nil, errors.New("nil chaincode endorsed action")
+	}
+
+	var signedData []*common.SignedData
+	seen := make(map[string]struct{})
+	for _, endorsement := range cap.Action.Endorsements {
+		sID := &msp.SerializedIdentity{}
+		if err := proto.Unmarshal(endorsement.Endorser, sID); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal endorser")
+		}
+		id := sID.Mspid + string(sID.IdBytes)
+		if _, in := seen[id]; in {
+			continue
+		}
+		seen[id] = struct{}{}
+		signedData = append(signedData, &common.SignedData{
+			Data:      append(cap.Action.ProposalResponsePayload, endorsement.Endorser...),
+			Identity:  endorsement.Endorser,
+			Signature: endorsement.Signature,
+		})
+	}
+	return signedData, nil
+}
@@ -0,0 +1,242 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txvalidator
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
+	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/common/lifecycle"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	mocktxvalidator "github.com/hyperledger/fabric/core/mocks/txvalidator"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/msp/mgmt"
+	"github.com/hyperledger/fabric/protos/common"
+	pmsp "github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/peer"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/sync/semaphore"
+)
+
+type lifecycleWrite struct {
+	ns  string
+	key string
+}
+
+func createLifecycleRWset(t *testing.T, writes ...lifecycleWrite) []byte {
+	rwsetBuilder := rwsetutil.NewRWSetBuilder()
+	for _, w := range writes {
+		rwsetBuilder.AddToWriteSet(w.ns, w.key, []byte("value"))
+	}
+	rwset, err := rwsetBuilder.GetTxSimulationResults()
+	assert.NoError(t, err)
+	rwsetBytes, err := rwset.GetPubSimulationBytes()
+	assert.NoError(t, err)
+	return rwsetBytes
+}
+
+// roleIdentity is an identity holding exactly the given roles of its org,
+// as the sample MSP cannot tell peers apart. Without roles, its MSP decides.
+type roleIdentity struct {
+	msp.Identity
+	roles []pmsp.MSPRole_MSPRoleType
+}
+
+func (id *roleIdentity) SatisfiesPrincipal(principal *pmsp.MSPPrincipal) error {
+	if id.roles != nil && principal.PrincipalClassification == pmsp.MSPPrincipal_ROLE {
+		role := &pmsp.MSPRole{}
+		if err := proto.Unmarshal(principal.Principal, role); err != nil {
+			return err
+		}
+		if role.Role != pmsp.MSPRole_MEMBER && role.MspIdentifier == id.GetMSPIdentifier() {
+			for _, r := range id.roles {
+				if r == role.Role {
+					return nil
+				}
+			}
+			return errors.New("the identity does not hold the role")
+		}
+	}
+	return id.Identity.SatisfiesPrincipal(principal)
+}
+
+// roleMSPManager deserializes the identities of the underlying manager
+// as roleIdentities holding the given roles
+type roleMSPManager struct {
+	msp.MSPManager
+	roles []pmsp.MSPRole_MSPRoleType
+}
+
+func (m *roleMSPManager) DeserializeIdentity(serializedID []byte) (msp.Identity, error) {
+	id, err := m.MSPManager.DeserializeIdentity(serializedID)
+	if err != nil {
+		return nil, err
+	}
+	return &roleIdentity{Identity: id, roles: m.roles}, nil
+}
+
+func validateLifecycleBlock(t *testing.T, pm policies.Manager, writes ...lifecycleWrite) *common.Block {
+	return validateLifecycleBlockWithRoles(t, pm, nil, writes...)
+}
+
+// validateLifecycleBlockWithRoles validates a block whose only transaction,
+// created and endorsed by the signer holding the given roles, performs the
+// given writes
+func validateLifecycleBlockWithRoles(t *testing.T, pm policies.Manager, roles []pmsp.MSPRole_MSPRoleType, writes ...lifecycleWrite) *common.Block {
+	theLedger := new(mockLedger)
+	theLedger.On("GetTransactionByID", mock.Anything).Return(&peer.ProcessedTransaction{}, errors.New("Cannot find the transaction"))
+	vcs := struct {
+		*mocktxvalidator.Support
+		*semaphore.Weighted
+	}{&mocktxvalidator.Support{
+		LedgerVal:     theLedger,
+		MSPManagerVal: &roleMSPManager{MSPManager: mgmt.GetManagerForChain(util.GetTestChainID()), roles: roles},
+		ACVal:         &mockconfig.MockApplicationCapabilities{},
+		PolicyMgrVal:  pm,
+	}, semaphore.NewWeighted(10)}
+	validator := NewTxValidator("", vcs, &mockPluginMapper{})
+
+	tx := getEnv(lifecycle.Namespace, createLifecycleRWset(t, writes...), t)
+	b := &common.Block{
+		Data:   &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}},
+		Header: &common.BlockHeader{},
+	}
+	assert.NoError(t, validator.Validate(b))
+	return b
+}
+
+func TestLifecycleApproval(t *testing.T) {
+	// the signer is an admin of the DEFAULT org
+	b := validateLifecycleBlock(t, nil, lifecycleWrite{lifecycle.Namespace, lifecycle.ApprovalKey("mycc", 1, "DEFAULT")})
+	assertValid(b, t)
+
+	b = validateLifecycleBlock(t, nil, lifecycleWrite{lifecycle.Namespace, lifecycle.ApprovalKey("mycc", 1, "OtherOrg")})
+	assertInvalid(b, t, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
+
+	// members of the org which are not its admins cannot approve
+	for _, role := range []pmsp.MSPRole_MSPRoleType{pmsp.MSPRole_PEER, pmsp.MSPRole_CLIENT} {
+		roles := []pmsp.MSPRole_MSPRoleType{role}
+		b = validateLifecycleBlockWithRoles(t, nil, roles, lifecycleWrite{lifecycle.Namespace, lifecycle.ApprovalKey("mycc", 1, "DEFAULT")})
+		assertInvalid(b, t, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
+	}
+}
+
+func TestLifecycleCommit(t *testing.T) {
+	// without a channel policy, the peers of a majority of the orgs
+	// (here the only one, DEFAULT) must endorse the commit
+	peerRole := []pmsp.MSPRole_MSPRoleType{pmsp.MSPRole_PEER}
+	b := validateLifecycleBlockWithRoles(t, nil, peerRole, lifecycleWrite{lifecycle.Namespace, lifecycle.DefinitionKey("mycc")})
+	assertValid(b, t)
+
+	// neither clients nor admins may endorse the commit, and neither may
+	// the signer of the sample MSP, which does not enable NodeOUs
+	for _, roles := range [][]pmsp.MSPRole_MSPRoleType{
+		{pmsp.MSPRole_CLIENT},
+		{pmsp.MSPRole_ADMIN},
+		nil,
+	} {
+		b = validateLifecycleBlockWithRoles(t, nil, roles, lifecycleWrite{lifecycle.Namespace, lifecycle.DefinitionKey("mycc")})
+		assertInvalid(b, t, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
+	}
+
+	pm := &mockpolicies.Manager{PolicyMap: map[string]policies.Policy{
+		lifecycle.EndorsementPolicyPath: &mockpolicies.Policy{Err: errors.New("not enough orgs")},
+	}}
+	b = validateLifecycleBlockWithRoles(t, pm, peerRole, lifecycleWrite{lifecycle.Namespace, lifecycle.DefinitionKey("mycc")})
+	assertInvalid(b, t, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
+
+	pm.PolicyMap[lifecycle.EndorsementPolicyPath] = &mockpolicies.Policy{}
+	b = validateLifecycleBlock(t, pm, lifecycleWrite{lifecycle.Namespace, lifecycle.DefinitionKey("mycc")})
+	assertValid(b, t)
+}
+
+func TestLifecycleIllegalWrites(t *testing.T) {
+	tests := []struct {
+		name   string
+		writes []lifecycleWrite
+	}{
+		{"unknown key", []lifecycleWrite{{lifecycle.Namespace, "foo"}}},
+		{"two keys", []lifecycleWrite{
+			{lifecycle.Namespace, lifecycle.ApprovalKey("mycc", 1, "DEFAULT")},
+			{lifecycle.Namespace, lifecycle.DefinitionKey("mycc")},
+		}},
+		{"other namespace", []lifecycleWrite{
+			{lifecycle.Namespace, lifecycle.ApprovalKey("mycc", 1, "DEFAULT")},
+			{"mycc", "key"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := validateLifecycleBlock(t, nil, tt.writes...)
+			assertInvalid(b, t, peer.TxValidationCode_ILLEGAL_WRITESET)
+		})
+	}
+}
+
+func TestLifecycleDefinedChaincode(t *testing.T) {
+	ccID := "mycc"
+	definition := func(version string) []byte {
+		return utils.MarshalOrPanic(&lb.ChaincodeDefinition{
+			Sequence:            1,
+			Version:             version,
+			ValidationPlugin:    "vscc",
+			ValidationParameter: signedByAnyMember([]string{"DEFAULT"}),
+		})
+	}
+
+	validate := func(definition []byte, writes ...lifecycleWrite) *common.Block {
+		theLedger := new(mockLedger)
+		theLedger.On("GetTransactionByID", mock.Anything).Return(&peer.ProcessedTransaction{}, errors.New("Cannot find the transaction"))
+		// the chaincode is not known to lscc, its definition must be used
+		queryExecutor := new(mockQueryExecutor)
+		queryExecutor.On("GetState", lifecycle.Namespace, lifecycle.DefinitionKey(ccID)).Return(definition, nil)
+		queryExecutor.On("GetStateMetadata", ccID, mock.Anything).Return(map[string][]byte(nil), nil)
+		theLedger.On("NewQueryExecutor", mock.Anything).Return(queryExecutor, nil)
+		vcs := struct {
+			*mocktxvalidator.Support
+			*semaphore.Weighted
+		}{&mocktxvalidator.Support{LedgerVal: theLedger, ACVal: &mockconfig.MockApplicationCapabilities{}}, semaphore.NewWeighted(10)}
+		validator := NewTxValidator("", vcs, &mockPluginMapper{})
+
+		tx := getEnv(ccID, createLifecycleRWset(t, writes...), t)
+		b := &common.Block{
+			Data:   &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}},
+			Header: &common.BlockHeader{},
+		}
+		assert.NoError(t, validator.Validate(b))
+		return b
+	}
+
+	b := validate(definition(ccVersion), lifecycleWrite{ccID, "key"})
+	assertValid(b, t)
+
+	// the validation plugin of the definition decides
+	c := validationPlugin.getCallback()
+	validationPlugin.setCallback(func() error {
+		return errors.New("endorsement policy not satisfied")
+	})
+	b = validate(definition(ccVersion), lifecycleWrite{ccID, "key"})
+	validationPlugin.setCallback(c)
+	assertInvalid(b, t, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
+
+	// the transaction must invoke the version of the definition
+	b = validate(definition("2.0"), lifecycleWrite{ccID, "key"})
+	assertInvalid(b, t, peer.TxValidationCode_EXPIRED_CHAINCODE)
+
+	// an application chaincode cannot write definitions
+	b = validate(definition(ccVersion), lifecycleWrite{ccID, "key"}, lifecycleWrite{lifecycle.Namespace, lifecycle.DefinitionKey("othercc")})
+	assertInvalid(b, t, peer.TxValidationCode_ILLEGAL_WRITESET)
+}
@@ -0,0 +1,223 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txvalidator
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
+	vs "github.com/hyperledger/fabric/core/handlers/validation/api/state"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+// PluginName defines the name of the plugin as it appears in the configuration
+type PluginName string
+
+// PluginMapper maps plugin names to their corresponding factory instance.
+// Returns nil if the name isn't associated to any plugin.
+type PluginMapper interface {
+	PluginFactoryByName(name PluginName) validation.PluginFactory
+}
+
+// MapBasedPluginMapper maps plugin names to their corresponding factories
+type MapBasedPluginMapper map[string]validation.PluginFactory
+
+// PluginFactoryByName returns a plugin factory for the given plugin name, or nil if not found
+func (m MapBasedPluginMapper) PluginFactoryByName(name PluginName) validation.PluginFactory {
+	return m[string(name)]
+}
+
+// Context defines information about a transaction
+// that is being validated
+type Context struct {
+	Seq        int
+	Envelope   []byte
+	TxID       string
+	Channel    string
+	PluginName string
+	Policy     []byte
+	Namespace  string
+	Block      *common.Block
+}
+
+// String returns a string representation of this Context
+func (c Context) String() string {
+	return fmt.Sprintf("Tx %s, seq %d out of %d in block %d for channel %s with validation plugin %s", c.TxID, c.Seq, len(c.Block.Data.Data), c.Block.Header.Number, c.Channel, c.PluginName)
+}
+
+// PluginValidator validates transactions with validation plugins
+type PluginValidator struct {
+	sync.Mutex
+	pluginChannelMapping map[PluginName]*pluginsByChannel
+	PluginMapper
+	Support
+}
+
+// NewPluginValidator creates a new PluginValidator
+func NewPluginValidator(pm PluginMapper, support Support) *PluginValidator {
+	return &PluginValidator{
+		PluginMapper:         pm,
+		Support:              support,
+		pluginChannelMapping: make(map[PluginName]*pluginsByChannel),
+	}
+}
+
+// ValidateWithPlugin validates the transaction in the given context
+// with the validation plugin the context refers to
+func (pv *PluginValidator) ValidateWithPlugin(ctx *Context) error {
+	plugin, err := pv.getOrCreatePlugin(ctx)
+	if err != nil {
+		return &validation.ExecutionFailureError{
+			Reason: fmt.Sprintf("plugin with name %s couldn't be used: %v", ctx.PluginName, err),
+		}
+	}
+	err = plugin.Validate(ctx.Block, ctx.Namespace, ctx.Seq, 0, SerializedPolicy(ctx.Policy))
+	validityStatus := "valid"
+	if err != nil {
+		validityStatus = fmt.Sprintf("invalid: %v", err)
+	}
+	logger.Debug("Transaction", ctx.TxID, "appears to be", validityStatus)
+	return err
+}
+
+func (pv *PluginValidator) getOrCreatePlugin(ctx *Context) (validation.Plugin, error) {
+	pluginFactory := pv.PluginFactoryByName(PluginName(ctx.PluginName))
+	if pluginFactory == nil {
+		return nil, errors.Errorf("plugin with name %s wasn't found", ctx.PluginName)
+	}
+
+	pluginsByChannel := pv.getOrCreatePluginChannelMapping(PluginName(ctx.PluginName), pluginFactory)
+	return pluginsByChannel.createPluginIfAbsent(ctx.Channel)
+}
+
+func (pv *PluginValidator) getOrCreatePluginChannelMapping(plugin PluginName, pf validation.PluginFactory) *pluginsByChannel {
+	pv.Lock()
+	defer pv.Unlock()
+	channelMapping, exists := pv.pluginChannelMapping[plugin]
+	if !exists {
+		channelMapping = &pluginsByChannel{
+			pluginFactory:    pf,
+			channels2Plugins: make(map[string]validation.Plugin),
+			pv:               pv,
+		}
+		pv.pluginChannelMapping[plugin] = channelMapping
+	}
+	return channelMapping
+}
+
+// pluginsByChannel holds the instances of a validation
+// plugin, one for every channel it's used in
+type pluginsByChannel struct {
+	sync.RWMutex
+	pluginFactory    validation.PluginFactory
+	channels2Plugins map[string]validation.Plugin
+	pv               *PluginValidator
+}
+
+func (pbc *pluginsByChannel) createPluginIfAbsent(channel string) (validation.Plugin, error) {
+	pbc.RLock()
+	plugin, exists := pbc.channels2Plugins[channel]
+	pbc.RUnlock()
+	if exists {
+		return plugin, nil
+	}
+
+	pbc.Lock()
+	defer pbc.Unlock()
+	plugin, exists = pbc.channels2Plugins[channel]
+	if exists {
+		return plugin, nil
+	}
+
+	pluginInstance := pbc.pluginFactory.New()
+	plugin, err := pbc.initPlugin(pluginInstance, channel)
+	if err != nil {
+		return nil, err
+	}
+	pbc.channels2Plugins[channel] = plugin
+	return plugin, nil
+}
+
+func (pbc *pluginsByChannel) initPlugin(plugin validation.Plugin, channel string) (validation.Plugin, error) {
+	pe := &PolicyEvaluator{Support: pbc.pv.Support}
+	sf := &StateFetcherImpl{Support: pbc.pv.Support}
+	caps := &channelCapabilities{Support: pbc.pv.Support}
+	if err := plugin.Init(pe, sf, caps); err != nil {
+		return nil, errors.Wrap(err, "failed initializing plugin")
+	}
+	return plugin, nil
+}
+
+// PolicyEvaluator evaluates policies with the
+// MSP manager of the channel
+type PolicyEvaluator struct {
+	Support
+}
+
+// Evaluate takes a set of SignedData and evaluates whether this set of signatures satisfies the policy
+func (id *PolicyEvaluator) Evaluate(policyBytes []byte, signatureSet []*common.SignedData) error {
+	pp := cauthdsl.NewPolicyProvider(id.MSPManager())
+	policy, _, err := pp.NewPolicy(policyBytes)
+	if err != nil {
+		return err
+	}
+	return policy.Evaluate(signatureSet)
+}
+
+// SerializedPolicy defines a marshaled policy
+type SerializedPolicy []byte
+
+// Bytes returns the bytes of the SerializedPolicy
+func (sp SerializedPolicy) Bytes() []byte {
+	return sp
+}
+
+// StateFetcherImpl fetches the world state of the channel
+type StateFetcherImpl struct {
+	Support
+}
+
+// FetchState fetches state
+func (sf *StateFetcherImpl) FetchState() (vs.State, error) {
+	l := sf.Ledger()
+	if l == nil {
+		return nil, errors.New("nil ledger instance")
+	}
+	qe, err := l.NewQueryExecutor()
+	if err != nil {
+		return nil, err
+	}
+	return &StateImpl{qe}, nil
+}
+
+// StateImpl exposes read access to the world state
+// through a ledger query executor
+type StateImpl struct {
+	ledger.QueryExecutor
+}
+
+// channelCapabilities exposes the current capabilities of the channel,
+// as these may change with a config update after the plugin is
+// initialized
+type channelCapabilities struct {
+	Support
+}
+
+// PrivateChannelData returns true if support for private channel data (a.k.a. collections) is enabled.
+func (c *channelCapabilities) PrivateChannelData() bool {
+	return c.Support.Capabilities().PrivateChannelData()
+}
+
+// V1_1Validation returns true if this channel is configured to perform stricter validation
+// of transactions (as introduced in v1.1).
+func (c *channelCapabilities) V1_1Validation() bool {
+	return c.Support.Capabilities().V1_1Validation()
+}
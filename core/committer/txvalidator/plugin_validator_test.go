@@ -0,0 +1,146 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txvalidator
+
+import (
+	"errors"
+	"testing"
+
+	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
+	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
+	"github.com/hyperledger/fabric/core/handlers/validation/api/capabilities"
+	"github.com/hyperledger/fabric/core/handlers/validation/api/policies"
+	"github.com/hyperledger/fabric/core/handlers/validation/api/state"
+	mocktxvalidator "github.com/hyperledger/fabric/core/mocks/txvalidator"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sync/semaphore"
+)
+
+type testPlugin struct {
+	initErr      error
+	validateErr  error
+	dependencies []validation.Dependency
+	contextData  []validation.ContextDatum
+}
+
+func (p *testPlugin) Validate(block *common.Block, namespace string, txPosition int, actionPosition int, contextData ...validation.ContextDatum) error {
+	p.contextData = contextData
+	return p.validateErr
+}
+
+func (p *testPlugin) Init(dependencies ...validation.Dependency) error {
+	p.dependencies = dependencies
+	return p.initErr
+}
+
+type testPluginFactory struct {
+	plugins []*testPlugin
+	initErr error
+}
+
+func (f *testPluginFactory) New() validation.Plugin {
+	p := &testPlugin{initErr: f.initErr}
+	f.plugins = append(f.plugins, p)
+	return p
+}
+
+func newTestSupport(sup *mocktxvalidator.Support) Support {
+	return struct {
+		*mocktxvalidator.Support
+		*semaphore.Weighted
+	}{sup, semaphore.NewWeighted(10)}
+}
+
+func testContext(channel string) *Context {
+	return &Context{
+		Channel:    channel,
+		PluginName: "vscc",
+		Policy:     []byte("policy"),
+		Namespace:  "mycc",
+		Block:      &common.Block{Data: &common.BlockData{Data: [][]byte{{}}}, Header: &common.BlockHeader{}},
+	}
+}
+
+func TestValidateWithPlugin(t *testing.T) {
+	factory := &testPluginFactory{}
+	pv := NewPluginValidator(MapBasedPluginMapper{"vscc": factory}, newTestSupport(&mocktxvalidator.Support{}))
+
+	// Scenario I: the plugin isn't found
+	ctx := testContext("mychannel")
+	ctx.PluginName = "unknown"
+	err := pv.ValidateWithPlugin(ctx)
+	assert.IsType(t, &validation.ExecutionFailureError{}, err)
+	assert.Contains(t, err.Error(), "plugin with name unknown wasn't found")
+
+	// Scenario II: the plugin validates the transaction with the policy of the context
+	err = pv.ValidateWithPlugin(testContext("mychannel"))
+	assert.NoError(t, err)
+	assert.Len(t, factory.plugins, 1)
+	assert.Len(t, factory.plugins[0].contextData, 1)
+	serializedPolicy, isSerializedPolicy := factory.plugins[0].contextData[0].(policies.SerializedPolicy)
+	assert.True(t, isSerializedPolicy)
+	assert.Equal(t, []byte("policy"), serializedPolicy.Bytes())
+
+	// Scenario III: the plugin instance is reused for the same channel
+	factory.plugins[0].validateErr = errors.New("invalid tx")
+	err = pv.ValidateWithPlugin(testContext("mychannel"))
+	assert.EqualError(t, err, "invalid tx")
+	assert.Len(t, factory.plugins, 1)
+
+	// Scenario IV: a new plugin instance is created for another channel
+	err = pv.ValidateWithPlugin(testContext("otherchannel"))
+	assert.NoError(t, err)
+	assert.Len(t, factory.plugins, 2)
+}
+
+func TestValidateWithPluginInitFailure(t *testing.T) {
+	factory := &testPluginFactory{initErr: errors.New("missing dependency")}
+	pv := NewPluginValidator(MapBasedPluginMapper{"vscc": factory}, newTestSupport(&mocktxvalidator.Support{}))
+
+	err := pv.ValidateWithPlugin(testContext("mychannel"))
+	assert.IsType(t, &validation.ExecutionFailureError{}, err)
+	assert.Contains(t, err.Error(), "missing dependency")
+}
+
+func TestPluginDependencies(t *testing.T) {
+	factory := &testPluginFactory{}
+	support := &mocktxvalidator.Support{ACVal: &mockconfig.MockApplicationCapabilities{PrivateChannelDataRv: true}}
+	pv := NewPluginValidator(MapBasedPluginMapper{"vscc": factory}, newTestSupport(support))
+
+	err := pv.ValidateWithPlugin(testContext("mychannel"))
+	assert.NoError(t, err)
+
+	var (
+		sf   state.StateFetcher
+		pe   policies.PolicyEvaluator
+		caps capabilities.Capabilities
+	)
+	for _, dep := range factory.plugins[0].dependencies {
+		if d, isStateFetcher := dep.(state.StateFetcher); isStateFetcher {
+			sf = d
+		}
+		if d, isPolicyEvaluator := dep.(policies.PolicyEvaluator); isPolicyEvaluator {
+			pe = d
+		}
+		if d, isCapabilities := dep.(capabilities.Capabilities); isCapabilities {
+			caps = d
+		}
+	}
+	assert.NotNil(t, sf)
+	assert.NotNil(t, pe)
+	assert.NotNil(t, caps)
+
+	// the capabilities reflect the current config of the channel
+	assert.True(t, caps.PrivateChannelData())
+	support.ACVal = &mockconfig.MockApplicationCapabilities{}
+	assert.False(t, caps.PrivateChannelData())
+
+	// there is no ledger to fetch the state from
+	_, err = sf.FetchState()
+	assert.Error(t, err)
+}
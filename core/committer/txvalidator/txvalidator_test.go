@@ -52,7 +52,7 @@ func testValidationWithNTXes(t *testing.T, ledger ledger2.PeerLedger, gbHash []b
 		*mocktxvalidator.Support
 		*semaphore.Weighted
 	}{&mocktxvalidator.Support{LedgerVal: ledger, ACVal: &config.MockApplicationCapabilities{}}, semaphore.NewWeighted(10)}
-	tValidator := &txValidator{"", vcs, mockVsccValidator}
+	tValidator := &txValidator{"", vcs, mockVsccValidator, newBlockDependencies()}
 
 	bcInfo, _ := ledger.GetBlockchainInfo()
 	testutil.AssertEquals(t, bcInfo, &common.BlockchainInfo{
@@ -148,7 +148,7 @@ func TestBlockValidationDuplicateTXId(t *testing.T) {
 		*mocktxvalidator.Support
 		*semaphore.Weighted
 	}{&mocktxvalidator.Support{LedgerVal: ledger, ACVal: acv}, semaphore.NewWeighted(10)}
-	tValidator := &txValidator{"", vcs, mockVsccValidator}
+	tValidator := &txValidator{"", vcs, mockVsccValidator, newBlockDependencies()}
 
 	bcInfo, _ := ledger.GetBlockchainInfo()
 	testutil.AssertEquals(t, bcInfo, &common.BlockchainInfo{
@@ -235,7 +235,7 @@ func TestNewTxValidator_DuplicateTransactions(t *testing.T) {
 		*mocktxvalidator.Support
 		*semaphore.Weighted
 	}{&mocktxvalidator.Support{LedgerVal: ledger, ACVal: &config.MockApplicationCapabilities{}}, semaphore.NewWeighted(10)}
-	tValidator := &txValidator{"", vcs, &validator.MockVsccValidator{}}
+	tValidator := &txValidator{"", vcs, &validator.MockVsccValidator{}, newBlockDependencies()}
 
 	// Create simple endorsement transaction
 	payload := &common.Payload{
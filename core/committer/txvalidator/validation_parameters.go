@@ -0,0 +1,155 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txvalidator
+
+import (
+	"sync"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+)
+
+// validationParameterKey identifies a key whose validation parameter (that is,
+// its key-level endorsement policy) may be updated by a transaction. Private keys
+// are identified by their hash, as this is all the validating peer is guaranteed to have
+type validationParameterKey struct {
+	ns   string
+	coll string
+	key  string
+}
+
+// validationParameterUpdate is the kind of update a transaction performs
+// on the validation parameter of a key
+type validationParameterUpdate int
+
+const (
+	// noUpdate means that no preceding valid transaction updated the validation parameter
+	noUpdate validationParameterUpdate = iota
+	// metadataUpdate means that a preceding valid transaction wrote the metadata of the key
+	metadataUpdate
+	// keyDeletion means that a preceding valid transaction deleted the key, along with its metadata
+	keyDeletion
+)
+
+// txValidationParameterUpdates records the keys whose validation parameter
+// is updated by a transaction, and the outcome of the validation of the transaction
+type txValidationParameterUpdates struct {
+	extracted bool
+	validated bool
+	valid     bool
+	updates   map[validationParameterKey]validationParameterUpdate
+}
+
+// blockDependencies tracks the updates to the validation parameters of keys performed by
+// the transactions of the block being validated. Transactions are validated in parallel;
+// a transaction that writes a key whose validation parameter is updated by a preceding
+// transaction of the same block has to wait for the outcome of the validation of the
+// latter, as the validation parameter it has to be validated against depends on it
+type blockDependencies struct {
+	lock sync.Mutex
+	cond *sync.Cond
+	txs  []*txValidationParameterUpdates
+}
+
+func newBlockDependencies() *blockDependencies {
+	deps := &blockDependencies{}
+	deps.cond = sync.NewCond(&deps.lock)
+	return deps
+}
+
+// reset prepares the tracking of the dependencies for a new block with the given number of transactions
+func (d *blockDependencies) reset(txCount int) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.txs = make([]*txValidationParameterUpdates, txCount)
+	for i := range d.txs {
+		d.txs[i] = &txValidationParameterUpdates{}
+	}
+}
+
+// extract records the keys whose validation parameter is updated by the transaction at position seq
+// in the block: the keys whose metadata is written and the keys that are deleted. Metadata writes to
+// collections are only considered if the private data capability is enabled
+func (d *blockDependencies) extract(seq int, txRWSet *rwsetutil.TxRwSet, privateData bool) {
+	updates := make(map[validationParameterKey]validationParameterUpdate)
+	for _, ns := range txRWSet.NsRwSets {
+		if ns.KvRwSet != nil {
+			for _, write := range ns.KvRwSet.Writes {
+				if write.IsDelete {
+					updates[validationParameterKey{ns: ns.NameSpace, key: write.Key}] = keyDeletion
+				}
+			}
+			for _, metadataWrite := range ns.KvRwSet.MetadataWrites {
+				updates[validationParameterKey{ns: ns.NameSpace, key: metadataWrite.Key}] = metadataUpdate
+			}
+		}
+		if !privateData {
+			continue
+		}
+		for _, coll := range ns.CollHashedRwSets {
+			if coll.HashedRwSet == nil {
+				continue
+			}
+			for _, write := range coll.HashedRwSet.HashedWrites {
+				if write.IsDelete {
+					updates[validationParameterKey{ns.NameSpace, coll.CollectionName, string(write.KeyHash)}] = keyDeletion
+				}
+			}
+			for _, metadataWrite := range coll.HashedRwSet.MetadataWrites {
+				updates[validationParameterKey{ns.NameSpace, coll.CollectionName, string(metadataWrite.KeyHash)}] = metadataUpdate
+			}
+		}
+	}
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if seq >= len(d.txs) {
+		return
+	}
+	d.txs[seq].updates = updates
+	d.txs[seq].extracted = true
+	d.cond.Broadcast()
+}
+
+// setResult records the outcome of the validation of the transaction at position seq in the block
+func (d *blockDependencies) setResult(seq int, valid bool) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if seq >= len(d.txs) {
+		return
+	}
+	d.txs[seq].validated = true
+	d.txs[seq].valid = valid
+	d.cond.Broadcast()
+}
+
+// precedingUpdate returns the update performed on the validation parameter of the given key
+// by the closest preceding valid transaction of the block which updates it, waiting for the
+// validation of the preceding transactions to complete as needed
+func (d *blockDependencies) precedingUpdate(seq int, key validationParameterKey) validationParameterUpdate {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	if seq > len(d.txs) {
+		return noUpdate
+	}
+	for i := seq - 1; i >= 0; i-- {
+		tx := d.txs[i]
+		for !tx.extracted && !tx.validated {
+			d.cond.Wait()
+		}
+		update, updated := tx.updates[key]
+		if !updated {
+			continue
+		}
+		for !tx.validated {
+			d.cond.Wait()
+		}
+		if tx.valid {
+			return update
+		}
+	}
+	return noUpdate
+}
@@ -0,0 +1,96 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package txvalidator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func buildTxRWSet(t *testing.T, rwsetBuilder *rwsetutil.RWSetBuilder) *rwsetutil.TxRwSet {
+	simRes, err := rwsetBuilder.GetTxSimulationResults()
+	assert.NoError(t, err)
+	txRWSet, err := rwsetutil.TxRwSetFromProtoMsg(simRes.PubSimulationResults)
+	assert.NoError(t, err)
+	return txRWSet
+}
+
+func txRWSetWithMetadataWrite(t *testing.T, ns, key string) *rwsetutil.TxRwSet {
+	rwsetBuilder := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder.AddToMetadataWriteSet(ns, key, map[string][]byte{"VALIDATION_PARAMETER": []byte("policy")})
+	return buildTxRWSet(t, rwsetBuilder)
+}
+
+func TestBlockDependenciesNoUpdates(t *testing.T) {
+	deps := newBlockDependencies()
+	deps.reset(2)
+
+	deps.extract(0, &rwsetutil.TxRwSet{}, true)
+	assert.Equal(t, noUpdate, deps.precedingUpdate(1, validationParameterKey{ns: "mycc", key: "key"}))
+	assert.Equal(t, noUpdate, deps.precedingUpdate(0, validationParameterKey{ns: "mycc", key: "key"}))
+}
+
+func TestBlockDependenciesMetadataUpdate(t *testing.T) {
+	deps := newBlockDependencies()
+	deps.reset(3)
+
+	deps.extract(0, txRWSetWithMetadataWrite(t, "mycc", "key"), true)
+	// the tx that doesn't update the key is not waited for once validated
+	deps.setResult(1, true)
+
+	done := make(chan validationParameterUpdate)
+	go func() {
+		done <- deps.precedingUpdate(2, validationParameterKey{ns: "mycc", key: "key"})
+	}()
+
+	// the outcome depends on the validation of the first tx
+	select {
+	case <-done:
+		t.Fatal("precedingUpdate should wait for the validation of the tx updating the key")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	deps.setResult(0, true)
+	assert.Equal(t, metadataUpdate, <-done)
+
+	// keys in other namespaces are not affected
+	assert.Equal(t, noUpdate, deps.precedingUpdate(2, validationParameterKey{ns: "othercc", key: "key"}))
+}
+
+func TestBlockDependenciesInvalidUpdate(t *testing.T) {
+	deps := newBlockDependencies()
+	deps.reset(2)
+
+	deps.extract(0, txRWSetWithMetadataWrite(t, "mycc", "key"), true)
+	deps.setResult(0, false)
+	assert.Equal(t, noUpdate, deps.precedingUpdate(1, validationParameterKey{ns: "mycc", key: "key"}))
+}
+
+func TestBlockDependenciesKeyDeletion(t *testing.T) {
+	rwsetBuilder := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder.AddToWriteSet("mycc", "key", nil)
+	rwsetBuilder.AddToPvtAndHashedWriteSet("mycc", "coll", "pvtkey", nil)
+	txRWSet := buildTxRWSet(t, rwsetBuilder)
+
+	deps := newBlockDependencies()
+	deps.reset(2)
+	deps.extract(0, txRWSet, false)
+	deps.setResult(0, true)
+	assert.Equal(t, keyDeletion, deps.precedingUpdate(1, validationParameterKey{ns: "mycc", key: "key"}))
+
+	// private writes are ignored without the private data capability
+	pvtKey := validationParameterKey{"mycc", "coll", string(txRWSet.NsRwSets[0].CollHashedRwSets[0].HashedRwSet.HashedWrites[0].KeyHash)}
+	assert.Equal(t, noUpdate, deps.precedingUpdate(1, pvtKey))
+
+	deps.reset(2)
+	deps.extract(0, txRWSet, true)
+	deps.setResult(0, true)
+	assert.Equal(t, keyDeletion, deps.precedingUpdate(1, pvtKey))
+}
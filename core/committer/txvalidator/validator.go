@@ -16,12 +16,15 @@ import (
 	"github.com/hyperledger/fabric/common/configtx"
 	commonerrors "github.com/hyperledger/fabric/common/errors"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/common/resourcesconfig"
 	coreUtil "github.com/hyperledger/fabric/common/util"
-	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/lifecycle"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
 	"github.com/hyperledger/fabric/core/common/validation"
+	validationapi "github.com/hyperledger/fabric/core/handlers/validation/api"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	ledgerUtil "github.com/hyperledger/fabric/core/ledger/util"
@@ -61,6 +64,9 @@ type Support interface {
 	// ChaincodeByName returns the definition (and whether they exist)
 	// for a chaincode in a specific channel
 	ChaincodeByName(chainname, ccname string) (resourcesconfig.ChaincodeDefinition, bool)
+
+	// PolicyManager returns the policy manager of the channel
+	PolicyManager() policies.Manager
 }
 
 //Validator interface which defines API to validate block transactions
@@ -74,15 +80,16 @@ type Validator interface {
 // and vscc execution, in order to increase
 // testability of txValidator
 type vsccValidator interface {
-	VSCCValidateTx(payload *common.Payload, envBytes []byte, env *common.Envelope) (error, peer.TxValidationCode)
+	VSCCValidateTx(seq int, payload *common.Payload, envBytes []byte, block *common.Block) (error, peer.TxValidationCode)
 }
 
-// vsccValidator implementation which used to call
-// vscc chaincode and validate block transactions
+// vsccValidator implementation which uses the validation
+// plugins of the chaincodes to validate block transactions
 type vsccValidatorImpl struct {
-	support     Support
-	ccprovider  ccprovider.ChaincodeProvider
-	sccprovider sysccprovider.SystemChaincodeProvider
+	support         Support
+	sccprovider     sysccprovider.SystemChaincodeProvider
+	pluginValidator *PluginValidator
+	deps            *blockDependencies
 }
 
 // implementation of Validator interface, keeps
@@ -92,6 +99,7 @@ type txValidator struct {
 	ChainID string
 	support Support
 	vscc    vsccValidator
+	deps    *blockDependencies
 }
 
 var logger *logging.Logger // package-level logger
@@ -117,14 +125,20 @@ type blockValidationResult struct {
 	txid                 string
 }
 
-// NewTxValidator creates new transactions validator
-func NewTxValidator(chainID string, support Support) Validator {
+// NewTxValidator creates new transactions validator; the transactions are
+// validated with the validation plugins that the given PluginMapper resolves
+func NewTxValidator(chainID string, support Support, pluginMapper PluginMapper) Validator {
+	// the dependencies on the validation parameters of keys are shared
+	// between the block validation and the vscc validation of the txs
+	deps := newBlockDependencies()
 	// Encapsulates interface implementation
 	return &txValidator{chainID, support,
 		&vsccValidatorImpl{
-			support:     support,
-			ccprovider:  ccprovider.GetChaincodeProvider(),
-			sccprovider: sysccprovider.GetSystemChaincodeProvider()}}
+			support:         support,
+			sccprovider:     sysccprovider.GetSystemChaincodeProvider(),
+			pluginValidator: NewPluginValidator(pluginMapper, support),
+			deps:            deps},
+		deps}
 }
 
 func (v *txValidator) chainExists(chain string) bool {
@@ -152,6 +166,9 @@ func (v *txValidator) chainExists(chain string) bool {
 //    state is when a config transaction is received, but they are
 //    guaranteed to be alone in the block. If/when this assumption
 //    is violated, this code must be changed.
+// 3) a transaction that writes a key whose validation parameter is
+//    updated by a preceding transaction in the block waits for the
+//    validation of the latter to complete (see blockDependencies).
 func (v *txValidator) Validate(block *common.Block) error {
 	var err error
 	var errPos int
@@ -168,6 +185,8 @@ func (v *txValidator) Validate(block *common.Block) error {
 	// array of txids
 	txidArray := make([]string, len(block.Data.Data))
 
+	v.deps.reset(len(block.Data.Data))
+
 	results := make(chan *blockValidationResult)
 	go func() {
 		for tIdx, d := range block.Data.Data {
@@ -196,6 +215,9 @@ func (v *txValidator) Validate(block *common.Block) error {
 	for i := 0; i < len(block.Data.Data); i++ {
 		res := <-results
 
+		// unblock the validation of the txs that depend on this one
+		v.deps.setResult(res.tIdx, res.err == nil && res.validationCode == peer.TxValidationCode_VALID)
+
 		if res.err != nil {
 			// if there is an error, we buffer its value, wait for
 			// all workers to complete validation and then return
@@ -342,7 +364,9 @@ func validateTx(req *blockValidationRequest, results chan<- *blockValidationResu
 		if common.HeaderType(chdr.Type) == common.HeaderType_ENDORSER_TRANSACTION {
 			// Check duplicate transactions
 			txID = chdr.TxId
-			if _, err := v.support.Ledger().GetTransactionByID(txID); err == nil {
+			// a transaction in a pruned block is still a duplicate
+			_, err := v.support.Ledger().GetTransactionByID(txID)
+			if _, pruned := err.(*blkstorage.ErrBlockPruned); err == nil || pruned {
 				logger.Error("Duplicate transaction found, ", txID, ", skipping")
 				results <- &blockValidationResult{
 					tIdx:           tIdx,
@@ -353,7 +377,7 @@ func validateTx(req *blockValidationRequest, results chan<- *blockValidationResu
 
 			// Validate tx with vscc and policy
 			logger.Debug("Validating transaction vscc tx validate")
-			err, cde := v.vscc.VSCCValidateTx(payload, d, env)
+			err, cde := v.vscc.VSCCValidateTx(tIdx, payload, d, block)
 			if err != nil {
 				logger.Errorf("VSCCValidateTx for transaction txId = %s returned error: %s", txID, err)
 				switch err.(type) {
@@ -632,7 +656,7 @@ func (v *vsccValidatorImpl) GetInfoForValidate(txid, chID, ccID string) (*sysccp
 // performs a ledger write
 func (v *vsccValidatorImpl) txWritesToNamespace(ns *rwsetutil.NsRwSet) bool {
 	// check for public writes first
-	if ns.KvRwSet != nil && len(ns.KvRwSet.Writes) > 0 {
+	if ns.KvRwSet != nil && (len(ns.KvRwSet.Writes) > 0 || len(ns.KvRwSet.MetadataWrites) > 0) {
 		return true
 	}
 
@@ -643,7 +667,7 @@ func (v *vsccValidatorImpl) txWritesToNamespace(ns *rwsetutil.NsRwSet) bool {
 
 	// check for private writes for all collections
 	for _, c := range ns.CollHashedRwSets {
-		if c.HashedRwSet != nil && len(c.HashedRwSet.HashedWrites) > 0 {
+		if c.HashedRwSet != nil && (len(c.HashedRwSet.HashedWrites) > 0 || len(c.HashedRwSet.MetadataWrites) > 0) {
 			return true
 		}
 	}
@@ -651,9 +675,9 @@ func (v *vsccValidatorImpl) txWritesToNamespace(ns *rwsetutil.NsRwSet) bool {
 	return false
 }
 
-func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []byte, env *common.Envelope) (error, peer.TxValidationCode) {
-	logger.Debugf("VSCCValidateTx starts for env %p envbytes %p", env, envBytes)
-	defer logger.Debugf("VSCCValidateTx completes for env %p envbytes %p", env, envBytes)
+func (v *vsccValidatorImpl) VSCCValidateTx(seq int, payload *common.Payload, envBytes []byte, block *common.Block) (error, peer.TxValidationCode) {
+	logger.Debugf("VSCCValidateTx starts for bytes %p", envBytes)
+	defer logger.Debugf("VSCCValidateTx completes for bytes %p", envBytes)
 
 	// get header extensions so we have the chaincode ID
 	hdrExt, err := utils.GetChaincodeHeaderExtension(payload.Header)
@@ -673,7 +697,9 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 	   2) does it write to LSCC's namespace?
 	   3) does it write to any cc that cannot be invoked? */
 	wrNamespace := []string{}
+	wrNsRWSets := make(map[string]*rwsetutil.NsRwSet)
 	writesToLSCC := false
+	writesToLifecycle := false
 	writesToNonInvokableSCC := false
 	respPayload, err := utils.GetActionFromEnvelope(envBytes)
 	if err != nil {
@@ -683,14 +709,22 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 	if err = txRWSet.FromProtoBytes(respPayload.Results); err != nil {
 		return errors.WithMessage(err, "txRWSet.FromProtoBytes failed"), peer.TxValidationCode_BAD_RWSET
 	}
+	// record the validation parameters updated by this tx, for
+	// the validation of the txs that follow it in the block
+	v.deps.extract(seq, txRWSet, v.support.Capabilities().PrivateChannelData())
 	for _, ns := range txRWSet.NsRwSets {
 		if v.txWritesToNamespace(ns) {
 			wrNamespace = append(wrNamespace, ns.NameSpace)
+			wrNsRWSets[ns.NameSpace] = ns
 
 			if !writesToLSCC && ns.NameSpace == "lscc" {
 				writesToLSCC = true
 			}
 
+			if !writesToLifecycle && ns.NameSpace == lifecycle.Namespace {
+				writesToLifecycle = true
+			}
+
 			if !writesToNonInvokableSCC && v.sccprovider.IsSysCCAndNotInvokableCC2CC(ns.NameSpace) {
 				writesToNonInvokableSCC = true
 			}
@@ -748,6 +782,12 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 			return errors.Errorf("chaincode %s attempted to write to the namespace of LSCC", ccID),
 				peer.TxValidationCode_ILLEGAL_WRITESET
 		}
+		// the same goes for the namespace of the lifecycle system chaincode,
+		// which holds the chaincode definitions
+		if writesToLifecycle {
+			return errors.Errorf("chaincode %s attempted to write to the namespace of %s", ccID, lifecycle.Namespace),
+				peer.TxValidationCode_ILLEGAL_WRITESET
+		}
 		// 2) we don't write to the namespace of a chaincode that we cannot invoke - if
 		//    the chaincode cannot be invoked in the first place, there's no legitimate
 		//    way in which a transaction has a write set that writes to it; additionally
@@ -778,8 +818,12 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 				return err, peer.TxValidationCode_EXPIRED_CHAINCODE
 			}
 
-			// do VSCC validation
-			if err = v.VSCCValidateTxForCC(envBytes, chdr.TxId, chdr.ChannelId, vscc.ChaincodeName, vscc.ChaincodeVersion, policy); err != nil {
+			// the writes are validated against the key-level endorsement
+			// policies of the written keys, or against the chaincode-level
+			// endorsement policy for the keys that have none
+			policies, err := v.validationPolicies(seq, wrNsRWSets[ns], policy)
+			if err != nil {
+				logger.Errorf("Retrieving the validation parameters for txId = %s returned error: %+v", chdr.TxId, err)
 				switch err.(type) {
 				case *commonerrors.VSCCEndorsementPolicyError:
 					return err, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE
@@ -787,6 +831,28 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 					return err, peer.TxValidationCode_INVALID_OTHER_REASON
 				}
 			}
+
+			// do VSCC validation
+			for _, p := range policies {
+				ctx := &Context{
+					Seq:        seq,
+					Envelope:   envBytes,
+					Block:      block,
+					TxID:       chdr.TxId,
+					Channel:    chdr.ChannelId,
+					Namespace:  ns,
+					Policy:     p,
+					PluginName: vscc.ChaincodeName,
+				}
+				if err = v.VSCCValidateTxForCC(ctx); err != nil {
+					switch err.(type) {
+					case *commonerrors.VSCCEndorsementPolicyError:
+						return err, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE
+					default:
+						return err, peer.TxValidationCode_INVALID_OTHER_REASON
+					}
+				}
+			}
 		}
 	} else {
 		// make sure that we can invoke this system chaincode - if the chaincode
@@ -798,6 +864,12 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 				peer.TxValidationCode_ILLEGAL_WRITESET
 		}
 
+		// the lifecycle system chaincode is validated against the
+		// policies governing chaincode definitions rather than by VSCC
+		if ccID == lifecycle.Namespace {
+			return v.validateLifecycleTx(chdr, envBytes, wrNsRWSets)
+		}
+
 		// Get latest chaincode version, vscc and validate policy
 		_, vscc, policy, err := v.GetInfoForValidate(chdr.TxId, chdr.ChannelId, ccID)
 		if err != nil {
@@ -810,7 +882,17 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 		// currently, VSCC does custom validation for LSCC only; if an hlf
 		// user creates a new system chaincode which is invokable from the outside
 		// they have to modify VSCC to provide appropriate validation
-		if err = v.VSCCValidateTxForCC(envBytes, chdr.TxId, vscc.ChainID, vscc.ChaincodeName, vscc.ChaincodeVersion, policy); err != nil {
+		ctx := &Context{
+			Seq:        seq,
+			Envelope:   envBytes,
+			Block:      block,
+			TxID:       chdr.TxId,
+			Channel:    chdr.ChannelId,
+			Namespace:  ccID,
+			Policy:     policy,
+			PluginName: vscc.ChaincodeName,
+		}
+		if err = v.VSCCValidateTxForCC(ctx); err != nil {
 			switch err.(type) {
 			case *commonerrors.VSCCEndorsementPolicyError:
 				return err, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE
@@ -823,39 +905,111 @@ func (v *vsccValidatorImpl) VSCCValidateTx(payload *common.Payload, envBytes []b
 	return nil, peer.TxValidationCode_VALID
 }
 
-func (v *vsccValidatorImpl) VSCCValidateTxForCC(envBytes []byte, txid, chid, vsccName, vsccVer string, policy []byte) error {
-	logger.Debugf("VSCCValidateTxForCC starts for envbytes %p", envBytes)
-	defer logger.Debugf("VSCCValidateTxForCC completes for envbytes %p", envBytes)
-	ctxt, txsim, err := v.ccprovider.GetContext(v.support.Ledger(), txid)
+// VSCCValidateTxForCC validates the transaction in the given context with
+// the validation plugin of the chaincode; a failure to run the plugin makes the
+// block unprocessable, while any other error marks the transaction as invalid
+func (v *vsccValidatorImpl) VSCCValidateTxForCC(ctx *Context) error {
+	logger.Debug("Validating", ctx, "with plugin")
+	err := v.pluginValidator.ValidateWithPlugin(ctx)
+	if err == nil {
+		return nil
+	}
+	// If the error is a pluggable validation execution error, cast it to the common errors ExecutionFailureError.
+	if e, isExecutionError := err.(*validationapi.ExecutionFailureError); isExecutionError {
+		return &commonerrors.VSCCExecutionFailureError{Reason: e.Error()}
+	}
+	// Else, treat it as an endorsement error.
+	return &commonerrors.VSCCEndorsementPolicyError{Reason: err.Error()}
+}
+
+// validationPolicies returns the endorsement policies that the tx at position seq in the block has to
+// satisfy for its writes to the given namespace: the key-level endorsement policy (that is, the validation
+// parameter) of each written key and, if any written key has none, the chaincode-level endorsement policy.
+// A tx that writes a key whose validation parameter is updated by a preceding valid tx in the same block
+// is rejected, as it was endorsed against a validation parameter that is no longer current
+func (v *vsccValidatorImpl) validationPolicies(seq int, nsRWSet *rwsetutil.NsRwSet, ccPolicy []byte) ([][]byte, error) {
+	l := v.support.Ledger()
+	if l == nil {
+		return nil, errors.New("nil ledger instance")
+	}
+
+	qe, err := l.NewQueryExecutor()
 	if err != nil {
-		msg := fmt.Sprintf("Cannot obtain context for txid=%s, err: %s", txid, err)
-		logger.Errorf(msg)
-		return &commonerrors.VSCCExecutionFailureError{Reason: msg}
+		return nil, errors.WithMessage(err, "could not retrieve QueryExecutor")
+	}
+	defer qe.Done()
+
+	var keys []validationParameterKey
+	if nsRWSet.KvRwSet != nil {
+		for _, write := range nsRWSet.KvRwSet.Writes {
+			keys = append(keys, validationParameterKey{ns: nsRWSet.NameSpace, key: write.Key})
+		}
+		for _, metadataWrite := range nsRWSet.KvRwSet.MetadataWrites {
+			keys = append(keys, validationParameterKey{ns: nsRWSet.NameSpace, key: metadataWrite.Key})
+		}
+	}
+	if v.support.Capabilities().PrivateChannelData() {
+		for _, coll := range nsRWSet.CollHashedRwSets {
+			if coll.HashedRwSet == nil {
+				continue
+			}
+			for _, write := range coll.HashedRwSet.HashedWrites {
+				keys = append(keys, validationParameterKey{nsRWSet.NameSpace, coll.CollectionName, string(write.KeyHash)})
+			}
+			for _, metadataWrite := range coll.HashedRwSet.MetadataWrites {
+				keys = append(keys, validationParameterKey{nsRWSet.NameSpace, coll.CollectionName, string(metadataWrite.KeyHash)})
+			}
+		}
+	}
+
+	var policies [][]byte
+	added := make(map[string]struct{})
+	for _, key := range keys {
+		vp, err := v.validationParameter(qe, seq, key)
+		if err != nil {
+			return nil, err
+		}
+		if vp == nil {
+			vp = ccPolicy
+		}
+		if _, in := added[string(vp)]; in {
+			continue
+		}
+		added[string(vp)] = struct{}{}
+		policies = append(policies, vp)
 	}
-	defer txsim.Done()
 
-	// build arguments for VSCC invocation
-	// args[0] - function name (not used now)
-	// args[1] - serialized Envelope
-	// args[2] - serialized policy
-	args := [][]byte{[]byte(""), envBytes, policy}
+	return policies, nil
+}
 
-	// get context to invoke VSCC
-	vscctxid := coreUtil.GenerateUUID()
-	cccid := v.ccprovider.GetCCContext(chid, vsccName, vsccVer, vscctxid, true, nil, nil)
+// validationParameter returns the validation parameter of the given key,
+// or nil if the key is subject to the chaincode-level endorsement policy
+func (v *vsccValidatorImpl) validationParameter(qe ledger.QueryExecutor, seq int, key validationParameterKey) ([]byte, error) {
+	update := v.deps.precedingUpdate(seq, key)
+	if update == metadataUpdate {
+		return nil, &commonerrors.VSCCEndorsementPolicyError{
+			Reason: fmt.Sprintf("validation parameter for key [%x] in namespace [%s] updated by a preceding transaction in the block", key.key, key.ns)}
+	}
 
-	// invoke VSCC
-	logger.Debug("Invoking VSCC txid", txid, "chaindID", chid)
-	res, _, err := v.ccprovider.ExecuteChaincode(ctxt, cccid, args)
+	var md map[string][]byte
+	var err error
+	if key.coll == "" {
+		md, err = qe.GetStateMetadata(key.ns, key.key)
+	} else {
+		md, err = qe.GetPrivateDataMetadataByHash(key.ns, key.coll, []byte(key.key))
+	}
 	if err != nil {
-		msg := fmt.Sprintf("Invoke VSCC failed for transaction txid=%s, error: %s", txid, err)
-		return &commonerrors.VSCCExecutionFailureError{Reason: msg}
+		return nil, &commonerrors.VSCCInfoLookupFailureError{Reason: fmt.Sprintf("Could not retrieve metadata for key [%x] in namespace [%s], error %s", key.key, key.ns, err)}
 	}
-	if res.Status != shim.OK {
-		return &commonerrors.VSCCEndorsementPolicyError{Reason: fmt.Sprintf("%s", res.Message)}
+	vp := md[peer.MetaDataKeys_VALIDATION_PARAMETER.String()]
+
+	// the deletion of the key in the block drops its validation parameter
+	if update == keyDeletion && vp != nil {
+		return nil, &commonerrors.VSCCEndorsementPolicyError{
+			Reason: fmt.Sprintf("key [%x] in namespace [%s] with a validation parameter deleted by a preceding transaction in the block", key.key, key.ns)}
 	}
 
-	return nil
+	return vp, nil
 }
 
 func (v *vsccValidatorImpl) getCDataForCC(chid, ccid string) (resourcesconfig.ChaincodeDefinition, error) {
@@ -870,6 +1024,16 @@ func (v *vsccValidatorImpl) getCDataForCC(chid, ccid string) (resourcesconfig.Ch
 	}
 	defer qe.Done()
 
+	// a chaincode defined through the lifecycle system chaincode is
+	// validated according to its definition, otherwise lscc decides
+	def, err := lifecycle.QueryDefinition(qe, ccid)
+	if err != nil {
+		return nil, &commonerrors.VSCCInfoLookupFailureError{Reason: fmt.Sprintf("Could not retrieve definition of chaincode %s, error %s", ccid, err)}
+	}
+	if def != nil {
+		return def, nil
+	}
+
 	bytes, err := qe.GetState("lscc", ccid)
 	if err != nil {
 		return nil, &commonerrors.VSCCInfoLookupFailureError{Reason: fmt.Sprintf("Could not retrieve state for chaincode %s, error %s", ccid, err)}
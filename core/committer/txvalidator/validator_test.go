@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sync"
 	"testing"
 
 	"github.com/hyperledger/fabric/common/cauthdsl"
@@ -20,19 +21,20 @@ import (
 	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
 	"github.com/hyperledger/fabric/common/mocks/scc"
 	"github.com/hyperledger/fabric/common/util"
-	"github.com/hyperledger/fabric/core/chaincode/shim"
 	ccp "github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/lifecycle"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
 	lutils "github.com/hyperledger/fabric/core/ledger/util"
-	"github.com/hyperledger/fabric/core/mocks/ccprovider"
 	mocktxvalidator "github.com/hyperledger/fabric/core/mocks/txvalidator"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/msp/mgmt/testtools"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
 	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/spf13/viper"
@@ -57,7 +59,7 @@ func setupLedgerAndValidator(t *testing.T) (ledger.PeerLedger, Validator) {
 		*mocktxvalidator.Support
 		*semaphore.Weighted
 	}{&mocktxvalidator.Support{LedgerVal: theLedger, ACVal: &mockconfig.MockApplicationCapabilities{}}, semaphore.NewWeighted(10)}
-	theValidator := NewTxValidator("", vcs)
+	theValidator := NewTxValidator("", vcs, &mockPluginMapper{})
 
 	return theLedger, theValidator
 }
@@ -223,14 +225,105 @@ func TestInvokeOKPvtDataOnly(t *testing.T) {
 	tx := getEnv(ccID, rwsetBytes, t)
 	b := &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}}, Header: &common.BlockHeader{Number: 2}}
 
-	v.(*txValidator).vscc.(*vsccValidatorImpl).ccprovider.(*ccprovider.MockCcProviderImpl).ExecuteResultProvider = nil
-	v.(*txValidator).vscc.(*vsccValidatorImpl).ccprovider.(*ccprovider.MockCcProviderImpl).ExecuteChaincodeResponse = &peer.Response{Status: shim.ERROR}
-
+	// Keep default callback
+	c := validationPlugin.getCallback()
+	validationPlugin.setCallback(func() error {
+		return errors.New("endorsement policy not satisfied")
+	})
 	err = v.Validate(b)
+	// Restore default callback
+	validationPlugin.setCallback(c)
 	assert.NoError(t, err)
 	assertInvalid(b, t, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
 }
 
+func putKeyWithValidationParameter(theLedger ledger.PeerLedger, ccname, key string, vp []byte, t *testing.T) {
+	simulator, err := theLedger.NewTxSimulator(util.GenerateUUID())
+	assert.NoError(t, err)
+	simulator.SetState(ccname, key, []byte("value"))
+	simulator.SetStateMetadata(ccname, key, map[string][]byte{peer.MetaDataKeys_VALIDATION_PARAMETER.String(): vp})
+	simulator.Done()
+
+	simRes, err := simulator.GetTxSimulationResults()
+	assert.NoError(t, err)
+	pubSimulationBytes, err := simRes.GetPubSimulationBytes()
+	assert.NoError(t, err)
+	bcInfo, err := theLedger.GetBlockchainInfo()
+	assert.NoError(t, err)
+	block := testutil.ConstructBlock(t, bcInfo.Height, []byte("hash"), [][]byte{pubSimulationBytes}, true)
+	err = theLedger.CommitWithPvtData(&ledger.BlockAndPvtData{
+		Block: block,
+	})
+	assert.NoError(t, err)
+}
+
+func TestValidationPolicies(t *testing.T) {
+	l, v := setupLedgerAndValidator(t)
+	defer ledgermgmt.CleanupTestEnv()
+	defer l.Close()
+
+	ccID := "mycc"
+	ccPolicy := signedByAnyMember([]string{"DEFAULT"})
+	keyPolicy := signedByAnyMember([]string{"SampleOrg"})
+	putCCInfo(l, ccID, ccPolicy, t)
+	putKeyWithValidationParameter(l, ccID, "key", keyPolicy, t)
+
+	vscc := v.(*txValidator).vscc.(*vsccValidatorImpl)
+	v.(*txValidator).deps.reset(1)
+
+	// a key without validation parameter is subject to the chaincode-level policy
+	nsRWSet := &rwsetutil.NsRwSet{NameSpace: ccID, KvRwSet: &kvrwset.KVRWSet{
+		Writes: []*kvrwset.KVWrite{{Key: "otherkey", Value: []byte("value")}},
+	}}
+	policies, err := vscc.validationPolicies(0, nsRWSet, ccPolicy)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{ccPolicy}, policies)
+
+	// a key with a validation parameter is subject to it only
+	nsRWSet = &rwsetutil.NsRwSet{NameSpace: ccID, KvRwSet: &kvrwset.KVRWSet{
+		Writes: []*kvrwset.KVWrite{{Key: "key", Value: []byte("value")}},
+	}}
+	policies, err = vscc.validationPolicies(0, nsRWSet, ccPolicy)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{keyPolicy}, policies)
+
+	// an update of the metadata is subject to the current validation parameter
+	nsRWSet = &rwsetutil.NsRwSet{NameSpace: ccID, KvRwSet: &kvrwset.KVRWSet{
+		Writes:         []*kvrwset.KVWrite{{Key: "otherkey", Value: []byte("value")}},
+		MetadataWrites: []*kvrwset.KVMetadataWrite{{Key: "key"}},
+	}}
+	policies, err = vscc.validationPolicies(0, nsRWSet, ccPolicy)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{ccPolicy, keyPolicy}, policies)
+}
+
+func TestInvokeNOKValidationParameterUpdatedInBlock(t *testing.T) {
+	l, v := setupLedgerAndValidator(t)
+	defer ledgermgmt.CleanupTestEnv()
+	defer l.Close()
+
+	ccID := "mycc"
+	putCCInfo(l, ccID, signedByAnyMember([]string{"DEFAULT"}), t)
+
+	// the first tx sets the validation parameter of the key
+	rwsetBuilder := rwsetutil.NewRWSetBuilder()
+	rwsetBuilder.AddToMetadataWriteSet(ccID, "key", map[string][]byte{peer.MetaDataKeys_VALIDATION_PARAMETER.String(): signedByAnyMember([]string{"SampleOrg"})})
+	simRes, err := rwsetBuilder.GetTxSimulationResults()
+	assert.NoError(t, err)
+	rwsetBytes, err := simRes.GetPubSimulationBytes()
+	assert.NoError(t, err)
+	tx0 := getEnv(ccID, rwsetBytes, t)
+	// the second tx writes the key
+	tx1 := getEnv(ccID, createRWset(t, ccID), t)
+	b := &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx0), utils.MarshalOrPanic(tx1)}}, Header: &common.BlockHeader{Number: 2}}
+
+	err = v.Validate(b)
+	assert.NoError(t, err)
+	txsFilter := lutils.TxValidationFlags(b.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	assert.True(t, txsFilter.IsValid(0))
+	assert.True(t, txsFilter.IsSetTo(1, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE))
+}
+
 func TestInvokeOKSCC(t *testing.T) {
 	l, v := setupLedgerAndValidator(t)
 	defer ledgermgmt.CleanupTestEnv()
@@ -493,6 +586,16 @@ func (m *mockLedger) CommitWithPvtData(pvtDataAndBlock *ledger.BlockAndPvtData)
 	return nil
 }
 
+// CommitPvtDataOfOldBlocks commits the pvt data of the already committed blocks
+func (m *mockLedger) CommitPvtDataOfOldBlocks(blocksPvtData []*ledger.BlockPvtData) error {
+	return nil
+}
+
+// GetMissingPvtDataInfoForMostRecentBlocks returns the missing pvt data of the most recent blocks
+func (m *mockLedger) GetMissingPvtDataInfoForMostRecentBlocks(maxBlocks int) (ledger.MissingPvtDataInfo, error) {
+	return nil, nil
+}
+
 // PurgePrivateData purges the private data
 func (m *mockLedger) PurgePrivateData(maxBlockNumToRetain uint64) error {
 	return nil
@@ -508,6 +611,11 @@ func (m *mockLedger) Prune(policy ledger2.PrunePolicy) error {
 	return nil
 }
 
+// ExportSnapshot exports a snapshot of the ledger
+func (m *mockLedger) ExportSnapshot(snapshotDir string) ([]byte, error) {
+	return nil, nil
+}
+
 func (m *mockLedger) GetBlockchainInfo() (*common.BlockchainInfo, error) {
 	args := m.Called()
 	return args.Get(0).(*common.BlockchainInfo), nil
@@ -557,11 +665,21 @@ func (exec *mockQueryExecutor) GetStateRangeScanIterator(namespace string, start
 	return args.Get(0).(ledger2.ResultsIterator), args.Error(1)
 }
 
+func (exec *mockQueryExecutor) GetStateRangeScanIteratorWithMetadata(namespace, startKey, endKey string, metadata map[string]interface{}) (ledger2.QueryResultsIterator, error) {
+	args := exec.Called(namespace, startKey, endKey, metadata)
+	return args.Get(0).(ledger2.QueryResultsIterator), args.Error(1)
+}
+
 func (exec *mockQueryExecutor) ExecuteQuery(namespace, query string) (ledger2.ResultsIterator, error) {
 	args := exec.Called(namespace)
 	return args.Get(0).(ledger2.ResultsIterator), args.Error(1)
 }
 
+func (exec *mockQueryExecutor) ExecuteQueryWithMetadata(namespace, query string, metadata map[string]interface{}) (ledger2.QueryResultsIterator, error) {
+	args := exec.Called(namespace, query, metadata)
+	return args.Get(0).(ledger2.QueryResultsIterator), args.Error(1)
+}
+
 func (exec *mockQueryExecutor) GetPrivateData(namespace, collection, key string) ([]byte, error) {
 	args := exec.Called(namespace, collection, key)
 	return args.Get(0).([]byte), args.Error(1)
@@ -582,6 +700,21 @@ func (exec *mockQueryExecutor) ExecuteQueryOnPrivateData(namespace, collection,
 	return args.Get(0).(ledger2.ResultsIterator), args.Error(1)
 }
 
+func (exec *mockQueryExecutor) GetStateMetadata(namespace, key string) (map[string][]byte, error) {
+	args := exec.Called(namespace, key)
+	return args.Get(0).(map[string][]byte), args.Error(1)
+}
+
+func (exec *mockQueryExecutor) GetPrivateDataMetadata(namespace, collection, key string) (map[string][]byte, error) {
+	args := exec.Called(namespace, collection, key)
+	return args.Get(0).(map[string][]byte), args.Error(1)
+}
+
+func (exec *mockQueryExecutor) GetPrivateDataMetadataByHash(namespace, collection string, keyhash []byte) (map[string][]byte, error) {
+	args := exec.Called(namespace, collection, keyhash)
+	return args.Get(0).(map[string][]byte), args.Error(1)
+}
+
 func (exec *mockQueryExecutor) Done() {
 }
 
@@ -598,7 +731,7 @@ func TestLedgerIsNoAvailable(t *testing.T) {
 		*mocktxvalidator.Support
 		*semaphore.Weighted
 	}{&mocktxvalidator.Support{LedgerVal: theLedger, ACVal: &mockconfig.MockApplicationCapabilities{}}, semaphore.NewWeighted(10)}
-	validator := NewTxValidator("", vcs)
+	validator := NewTxValidator("", vcs, &mockPluginMapper{})
 
 	ccID := "mycc"
 	tx := getEnv(ccID, createRWset(t, ccID), t)
@@ -629,7 +762,7 @@ func TestValidationInvalidEndorsing(t *testing.T) {
 		*mocktxvalidator.Support
 		*semaphore.Weighted
 	}{&mocktxvalidator.Support{LedgerVal: theLedger, ACVal: &mockconfig.MockApplicationCapabilities{}}, semaphore.NewWeighted(10)}
-	validator := NewTxValidator("", vcs)
+	validator := NewTxValidator("", vcs, &mockPluginMapper{})
 
 	ccID := "mycc"
 	tx := getEnv(ccID, createRWset(t, ccID), t)
@@ -646,7 +779,9 @@ func TestValidationInvalidEndorsing(t *testing.T) {
 	cdbytes := utils.MarshalOrPanic(cd)
 
 	queryExecutor := new(mockQueryExecutor)
+	queryExecutor.On("GetState", lifecycle.Namespace, lifecycle.DefinitionKey(ccID)).Return([]byte(nil), nil)
 	queryExecutor.On("GetState", "lscc", ccID).Return(cdbytes, nil)
+	queryExecutor.On("GetStateMetadata", ccID, mock.Anything).Return(map[string][]byte(nil), nil)
 	theLedger.On("NewQueryExecutor", mock.Anything).Return(queryExecutor, nil)
 
 	b := &common.Block{
@@ -655,13 +790,13 @@ func TestValidationInvalidEndorsing(t *testing.T) {
 	}
 
 	// Keep default callback
-	c := executeChaincodeProvider.getCallback()
-	executeChaincodeProvider.setCallback(func() (*peer.Response, *peer.ChaincodeEvent, error) {
-		return &peer.Response{Status: shim.ERROR}, nil, nil
+	c := validationPlugin.getCallback()
+	validationPlugin.setCallback(func() error {
+		return errors.New("endorsement policy not satisfied")
 	})
 	err := validator.Validate(b)
 	// Restore default callback
-	executeChaincodeProvider.setCallback(c)
+	validationPlugin.setCallback(c)
 	assert.NoError(t, err)
 	assertInvalid(b, t, peer.TxValidationCode_ENDORSEMENT_POLICY_FAILURE)
 }
@@ -673,7 +808,7 @@ func TestValidationResourceUpdate(t *testing.T) {
 		*mocktxvalidator.Support
 		*semaphore.Weighted
 	}{sup, semaphore.NewWeighted(10)}
-	validator := NewTxValidator("", vcs)
+	validator := NewTxValidator("", vcs, &mockPluginMapper{})
 
 	ccID := "mycc"
 	tx := getEnvWithType(ccID, createRWset(t, ccID), common.HeaderType_PEER_RESOURCE_UPDATE, t)
@@ -690,7 +825,9 @@ func TestValidationResourceUpdate(t *testing.T) {
 	cdbytes := utils.MarshalOrPanic(cd)
 
 	queryExecutor := new(mockQueryExecutor)
+	queryExecutor.On("GetState", lifecycle.Namespace, lifecycle.DefinitionKey(ccID)).Return([]byte(nil), nil)
 	queryExecutor.On("GetState", "lscc", ccID).Return(cdbytes, nil)
+	queryExecutor.On("GetStateMetadata", ccID, mock.Anything).Return(map[string][]byte(nil), nil)
 	theLedger.On("NewQueryExecutor", mock.Anything).Return(queryExecutor, nil)
 
 	b1 := &common.Block{Data: &common.BlockData{Data: [][]byte{utils.MarshalOrPanic(tx)}},
@@ -701,9 +838,9 @@ func TestValidationResourceUpdate(t *testing.T) {
 	}
 
 	// Keep default callback
-	c := executeChaincodeProvider.getCallback()
-	executeChaincodeProvider.setCallback(func() (*peer.Response, *peer.ChaincodeEvent, error) {
-		return &peer.Response{Status: shim.ERROR}, nil, nil
+	c := validationPlugin.getCallback()
+	validationPlugin.setCallback(func() error {
+		return errors.New("endorsement policy not satisfied")
 	})
 	err := validator.Validate(b1)
 	assert.NoError(t, err)
@@ -711,42 +848,66 @@ func TestValidationResourceUpdate(t *testing.T) {
 	err = validator.Validate(b2)
 	assert.NoError(t, err)
 	// Restore default callback
-	executeChaincodeProvider.setCallback(c)
+	validationPlugin.setCallback(c)
 	assertInvalid(b1, t, peer.TxValidationCode_UNSUPPORTED_TX_PAYLOAD)
 	assertValid(b2, t)
 }
 
-type ccResultCallback func() (*peer.Response, *peer.ChaincodeEvent, error)
+type validationResultCallback func() error
 
-type ccExecuteChaincode struct {
-	executeChaincodeCalback ccResultCallback
+// mockValidationPlugin is a validation plugin whose
+// validation result is determined by a callback
+type mockValidationPlugin struct {
+	sync.Mutex
+	callback validationResultCallback
 }
 
-func (cc *ccExecuteChaincode) ExecuteChaincodeResult() (*peer.Response, *peer.ChaincodeEvent, error) {
-	return cc.executeChaincodeCalback()
+func (p *mockValidationPlugin) Validate(block *common.Block, namespace string, txPosition int, actionPosition int, contextData ...validation.ContextDatum) error {
+	return p.getCallback()()
 }
 
-func (cc *ccExecuteChaincode) getCallback() ccResultCallback {
-	return cc.executeChaincodeCalback
+func (p *mockValidationPlugin) Init(dependencies ...validation.Dependency) error {
+	return nil
 }
 
-func (cc *ccExecuteChaincode) setCallback(calback ccResultCallback) {
-	cc.executeChaincodeCalback = calback
+func (p *mockValidationPlugin) getCallback() validationResultCallback {
+	p.Lock()
+	defer p.Unlock()
+	return p.callback
 }
 
-var signer msp.SigningIdentity
+func (p *mockValidationPlugin) setCallback(callback validationResultCallback) {
+	p.Lock()
+	defer p.Unlock()
+	p.callback = callback
+}
 
-var signerSerialized []byte
+func (p *mockValidationPlugin) New() validation.Plugin {
+	return p
+}
 
-var executeChaincodeProvider = &ccExecuteChaincode{
-	executeChaincodeCalback: func() (*peer.Response, *peer.ChaincodeEvent, error) {
-		return &peer.Response{Status: shim.OK}, nil, nil
+var validationPlugin = &mockValidationPlugin{
+	callback: func() error {
+		return nil
 	},
 }
 
+// mockPluginMapper maps the vscc plugin name to the mock validation plugin
+type mockPluginMapper struct{}
+
+func (*mockPluginMapper) PluginFactoryByName(name PluginName) validation.PluginFactory {
+	if name == "vscc" {
+		return validationPlugin
+	}
+	return nil
+}
+
+var signer msp.SigningIdentity
+
+var signerSerialized []byte
+
 func TestMain(m *testing.M) {
 	sysccprovider.RegisterSystemChaincodeProviderFactory(&scc.MocksccProviderFactory{})
-	ccp.RegisterChaincodeProviderFactory(&ccprovider.MockCcProviderFactory{ExecuteResultProvider: executeChaincodeProvider})
 
 	msptesttools.LoadMSPSetupForTesting()
 
@@ -0,0 +1,170 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+const (
+	// Namespace is the name of the lifecycle system chaincode, and the
+	// namespace in which it keeps the chaincode definitions and approvals
+	Namespace = "_lifecycle"
+
+	// EndorsementPolicyPath is the channel policy which must be satisfied
+	// to commit a chaincode definition. When the channel does not define
+	// it, a majority of the application organizations is required.
+	EndorsementPolicyPath = "/Channel/Application/LifecycleEndorsement"
+
+	// InitFunction is the function an invocation must name to run the
+	// Init of a chaincode whose definition requires it
+	InitFunction = "init"
+
+	// InitializedKey is the key of the namespace of a chaincode recording
+	// the version of the definition for which its Init was run. It starts
+	// with 0x00 so that it does not clash with the keys of the chaincode.
+	InitializedKey = "\x00initialized"
+
+	definitionPrefix = "namespaces/"
+	approvalPrefix   = "approvals/"
+)
+
+// DefinitionKey returns the key under which the committed definition
+// of the given chaincode is stored
+func DefinitionKey(name string) string {
+	return definitionPrefix + name
+}
+
+// DefinitionKeyRange returns the start and end keys of the range
+// holding all committed definitions
+func DefinitionKeyRange() (string, string) {
+	return definitionPrefix, definitionPrefix[:len(definitionPrefix)-1] + "0"
+}
+
+// ApprovalKey returns the key under which the approval of an organization
+// for a given sequence of a chaincode definition is stored
+func ApprovalKey(name string, sequence int64, org string) string {
+	return approvalPrefix + name + "/" + strconv.FormatInt(sequence, 10) + "/" + org
+}
+
+// ParseDefinitionKey returns the chaincode name held in the supplied
+// definition key
+func ParseDefinitionKey(key string) (string, error) {
+	if !strings.HasPrefix(key, definitionPrefix) || len(key) == len(definitionPrefix) {
+		return "", errors.Errorf("invalid definition key %s", key)
+	}
+	return key[len(definitionPrefix):], nil
+}
+
+// ParseApprovalKey returns the chaincode name, sequence and organization
+// held in the supplied approval key
+func ParseApprovalKey(key string) (string, int64, string, error) {
+	if !strings.HasPrefix(key, approvalPrefix) {
+		return "", 0, "", errors.Errorf("invalid approval key %s", key)
+	}
+	parts := strings.SplitN(key[len(approvalPrefix):], "/", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+		return "", 0, "", errors.Errorf("invalid approval key %s", key)
+	}
+	sequence, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", 0, "", errors.Errorf("invalid sequence in approval key %s", key)
+	}
+	return parts[0], sequence, parts[2], nil
+}
+
+// MajorityPolicy returns a policy requiring the signatures of peers of
+// more than half of the supplied organizations
+func MajorityPolicy(orgs []string) *common.SignaturePolicyEnvelope {
+	sorted := make([]string, len(orgs))
+	copy(sorted, orgs)
+	sort.Strings(sorted)
+
+	principals := make([]*msp.MSPPrincipal, len(sorted))
+	policies := make([]*common.SignaturePolicy, len(sorted))
+	for i, org := range sorted {
+		principals[i] = &msp.MSPPrincipal{
+			PrincipalClassification: msp.MSPPrincipal_ROLE,
+			Principal:               utils.MarshalOrPanic(&msp.MSPRole{Role: msp.MSPRole_PEER, MspIdentifier: org}),
+		}
+		policies[i] = cauthdsl.SignedBy(int32(i))
+	}
+
+	return &common.SignaturePolicyEnvelope{
+		Version:    0,
+		Rule:       cauthdsl.NOutOf(int32(len(sorted)/2+1), policies),
+		Identities: principals,
+	}
+}
+
+// Definition is the committed definition of a chaincode. It implements
+// resourcesconfig.ChaincodeDefinition, so that the peer can use it in
+// place of the chaincode data of lscc.
+type Definition struct {
+	Name string
+	*lb.ChaincodeDefinition
+}
+
+// CCName returns the name of the chaincode
+func (d *Definition) CCName() string {
+	return d.Name
+}
+
+// Hash returns nil, as a definition does not bind a chaincode package
+func (d *Definition) Hash() []byte {
+	return nil
+}
+
+// CCVersion returns the version of the chaincode
+func (d *Definition) CCVersion() string {
+	return d.Version
+}
+
+// Validation returns the validation plugin of the chaincode and its
+// endorsement policy
+func (d *Definition) Validation() (string, []byte) {
+	return d.ValidationPlugin, d.ValidationParameter
+}
+
+// Endorsement returns the endorsement plugin of the chaincode
+func (d *Definition) Endorsement() string {
+	return d.EndorsementPlugin
+}
+
+// StateGetter reads the state of the ledger of a channel, such as a
+// query executor or a transaction simulator
+type StateGetter interface {
+	GetState(namespace, key string) ([]byte, error)
+}
+
+// QueryDefinition returns the definition of the given chaincode committed
+// to the lifecycle namespace, or nil if the chaincode has none
+func QueryDefinition(state StateGetter, name string) (*Definition, error) {
+	cdBytes, err := state.GetState(Namespace, DefinitionKey(name))
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("could not get definition of %s", name))
+	}
+	if cdBytes == nil {
+		return nil, nil
+	}
+	cd := &lb.ChaincodeDefinition{}
+	if err = proto.Unmarshal(cdBytes, cd); err != nil {
+		return nil, errors.Wrapf(err, "could not unmarshal definition of %s", name)
+	}
+	return &Definition{Name: name, ChaincodeDefinition: cd}, nil
+}
@@ -0,0 +1,67 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefinitionKey(t *testing.T) {
+	key := DefinitionKey("mycc")
+	name, err := ParseDefinitionKey(key)
+	assert.NoError(t, err)
+	assert.Equal(t, "mycc", name)
+
+	startKey, endKey := DefinitionKeyRange()
+	assert.True(t, startKey < key && key < endKey)
+	assert.False(t, startKey < ApprovalKey("mycc", 1, "org1") && ApprovalKey("mycc", 1, "org1") < endKey)
+
+	_, err = ParseDefinitionKey("namespaces/")
+	assert.EqualError(t, err, "invalid definition key namespaces/")
+	_, err = ParseDefinitionKey(ApprovalKey("mycc", 1, "org1"))
+	assert.Error(t, err)
+}
+
+func TestApprovalKey(t *testing.T) {
+	name, sequence, org, err := ParseApprovalKey(ApprovalKey("mycc", 3, "org1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "mycc", name)
+	assert.Equal(t, int64(3), sequence)
+	assert.Equal(t, "org1", org)
+
+	for _, key := range []string{DefinitionKey("mycc"), "approvals/mycc/1", "approvals//1/org1", "approvals/mycc/1/"} {
+		_, _, _, err = ParseApprovalKey(key)
+		assert.EqualError(t, err, "invalid approval key "+key)
+	}
+	_, _, _, err = ParseApprovalKey("approvals/mycc/one/org1")
+	assert.EqualError(t, err, "invalid sequence in approval key approvals/mycc/one/org1")
+}
+
+func TestMajorityPolicy(t *testing.T) {
+	for orgs, required := range map[int]int32{1: 1, 2: 2, 3: 2, 4: 3, 5: 3} {
+		var ids []string
+		for i := 0; i < orgs; i++ {
+			ids = append(ids, fmt.Sprintf("org%d", orgs-i))
+		}
+		p := MajorityPolicy(ids)
+		assert.Equal(t, required, p.Rule.GetNOutOf().N)
+		assert.Len(t, p.Rule.GetNOutOf().Rules, orgs)
+		assert.Len(t, p.Identities, orgs)
+
+		// the principals are sorted by org
+		role := &msp.MSPRole{}
+		assert.NoError(t, proto.Unmarshal(p.Identities[0].Principal, role))
+		assert.Equal(t, "org1", role.MspIdentifier)
+		assert.Equal(t, msp.MSPRole_PEER, role.Role)
+		assert.Equal(t, msp.MSPPrincipal_ROLE, p.Identities[0].PrincipalClassification)
+	}
+}
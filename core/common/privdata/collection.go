@@ -44,6 +44,10 @@ type CollectionAccessPolicy interface {
 	// upon endorsement. This number has to be bigger than RequiredPeerCount().
 	MaximumPeerCount() int
 
+	// BlockToLive returns the number of blocks after which the private data
+	// of the collection expires. Zero means that the private data never expires.
+	BlockToLive() uint64
+
 	// MemberOrgs returns the collection's members as MSP IDs. This serves as
 	// a human-readable way of quickly identifying who is part of a collection.
 	MemberOrgs() []string
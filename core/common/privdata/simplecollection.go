@@ -47,6 +47,12 @@ func (sc *SimpleCollection) MaximumPeerCount() int {
 	return int(sc.conf.MaximumPeerCount)
 }
 
+// BlockToLive returns the number of blocks after which
+// the private data of this collection expires
+func (sc *SimpleCollection) BlockToLive() uint64 {
+	return sc.conf.BlockToLive
+}
+
 // AccessFilter returns the member filter function that evaluates signed data
 // against the member access policy of this collection
 func (sc *SimpleCollection) AccessFilter() Filter {
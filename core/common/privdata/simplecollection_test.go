@@ -104,6 +104,7 @@ func TestSetupGoodConfigCollection(t *testing.T) {
 		Name:              "test collection",
 		RequiredPeerCount: 1,
 		MemberOrgsPolicy:  accessPolicy,
+		BlockToLive:       100,
 	}
 
 	// set up simple collection with valid data
@@ -121,6 +122,9 @@ func TestSetupGoodConfigCollection(t *testing.T) {
 
 	// check required peer count
 	assert.True(t, sc.RequiredPeerCount() == 1)
+
+	// check block to live
+	assert.Equal(t, uint64(100), sc.BlockToLive())
 }
 
 func TestSimpleCollectionFilter(t *testing.T) {
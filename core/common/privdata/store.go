@@ -10,6 +10,7 @@ import (
 	"fmt"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/common/lifecycle"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/protos/common"
@@ -55,6 +56,19 @@ func (c *simpleCollectionStore) retrieveCollectionConfigPackage(cc common.Collec
 	}
 	defer qe.Done()
 
+	// the collections of a chaincode defined through the lifecycle
+	// system chaincode are part of its definition
+	def, err := lifecycle.QueryDefinition(qe, cc.Namespace)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("error while retrieving collection for collection criteria %#v", cc))
+	}
+	if def != nil {
+		if def.Collections == nil {
+			return nil, NoSuchCollectionError(cc)
+		}
+		return def.Collections, nil
+	}
+
 	cb, err := qe.GetState("lscc", c.s.GetCollectionKVSKey(cc))
 	if err != nil {
 		return nil, errors.WithMessage(err, fmt.Sprintf("error while retrieving collection for collection criteria %#v", cc))
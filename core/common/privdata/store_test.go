@@ -12,9 +12,12 @@ import (
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/cauthdsl"
 	lm "github.com/hyperledger/fabric/common/mocks/ledger"
+	"github.com/hyperledger/fabric/core/common/lifecycle"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/protos/common"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/stretchr/testify/assert"
 	"github.com/syndtr/goleveldb/leveldb/errors"
 )
@@ -48,6 +51,7 @@ func TestCollectionStore(t *testing.T) {
 
 	support.QErr = nil
 	wState["lscc"] = make(map[string][]byte)
+	wState[lifecycle.Namespace] = make(map[string][]byte)
 
 	_, err = cs.RetrieveCollection(common.CollectionCriteria{})
 	assert.Error(t, err)
@@ -97,4 +101,23 @@ func TestCollectionStore(t *testing.T) {
 	ccc, err := cs.RetrieveCollectionConfigPackage(ccr)
 	assert.NoError(t, err)
 	assert.NotNil(t, ccc)
+
+	// the collections of a committed definition take precedence over lscc
+	wState[lifecycle.Namespace][lifecycle.DefinitionKey("cc")] = []byte("barf")
+	_, err = cs.RetrieveCollection(ccr)
+	assert.Error(t, err)
+
+	definition := &lb.ChaincodeDefinition{Sequence: 1, Version: "1.0"}
+	wState[lifecycle.Namespace][lifecycle.DefinitionKey("cc")] = utils.MarshalOrPanic(definition)
+	_, err = cs.RetrieveCollection(ccr)
+	assert.Equal(t, NoSuchCollectionError(ccr), err)
+
+	cc = &common.CollectionConfig{Payload: &common.CollectionConfig_StaticCollectionConfig{&common.StaticCollectionConfig{Name: "definedcollection", MemberOrgsPolicy: accessPolicy}}}
+	definition.Collections = &common.CollectionConfigPackage{[]*common.CollectionConfig{cc}}
+	wState[lifecycle.Namespace][lifecycle.DefinitionKey("cc")] = utils.MarshalOrPanic(definition)
+	_, err = cs.RetrieveCollection(ccr)
+	assert.Error(t, err)
+	c, err = cs.RetrieveCollection(common.CollectionCriteria{Channel: "ch", Namespace: "cc", Collection: "definedcollection"})
+	assert.NoError(t, err)
+	assert.NotNil(t, c)
 }
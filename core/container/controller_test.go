@@ -28,6 +28,7 @@ import (
 
 	"github.com/hyperledger/fabric/core/container/ccintf"
 	"github.com/hyperledger/fabric/core/container/dockercontroller"
+	"github.com/hyperledger/fabric/core/container/externalcontroller"
 	"github.com/hyperledger/fabric/core/container/inproccontroller"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/stretchr/testify/assert"
@@ -282,6 +283,10 @@ func TestNewVM(t *testing.T) {
 	ivm := vm.(*inproccontroller.InprocVM)
 	assert.NotNil(t, ivm, "Requested System VM but newVM did not return inproccontroller.InprocVM")
 
+	vm = vmcontroller.newVM("External")
+	evm := vm.(*externalcontroller.ExternalVM)
+	assert.NotNil(t, evm, "Requested External VM but newVM did not return externalcontroller.ExternalVM")
+
 	vm = vmcontroller.newVM("")
 	dvm = vm.(*dockercontroller.DockerVM)
 	assert.NotNil(t, dvm, "Requested default VM but newVM did not return dockercontroller.DockerVM")
@@ -20,6 +20,7 @@ import (
 	"archive/tar"
 	"bytes"
 	"compress/gzip"
+	stdcontext "context"
 	"encoding/hex"
 	"fmt"
 	"io"
@@ -83,6 +84,8 @@ type dockerClient interface {
 	KillContainer(opts docker.KillContainerOptions) error
 	// RemoveContainer removes a docker container, returns an error in case of failure
 	RemoveContainer(opts docker.RemoveContainerOptions) error
+	// Ping pings the docker daemon, returns an error in case of failure
+	Ping() error
 }
 
 // NewDockerVM returns a new DockerVM instance
@@ -92,6 +95,19 @@ func NewDockerVM() *DockerVM {
 	return &vm
 }
 
+// HealthCheck checks if the DockerVM is able to communicate with the Docker
+// daemon. It is registered with the operations endpoint of the peer.
+func (vm *DockerVM) HealthCheck(ctx stdcontext.Context) error {
+	client, err := vm.getClientFnc()
+	if err != nil {
+		return fmt.Errorf("failed to connect to Docker daemon: %s", err)
+	}
+	if err := client.Ping(); err != nil {
+		return fmt.Errorf("failed to ping Docker daemon: %s", err)
+	}
+	return nil
+}
+
 func getDockerClient() (dockerClient, error) {
 	return cutil.NewDockerClient()
 }
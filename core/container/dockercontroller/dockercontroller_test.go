@@ -212,6 +212,27 @@ func Test_Stop(t *testing.T) {
 	testerr(t, err, true)
 }
 
+func TestHealthCheck(t *testing.T) {
+	dvm := DockerVM{getClientFnc: getMockClient}
+	ctx := context.Background()
+
+	// Failure case: getMockClient returns error
+	getClientErr = true
+	err := dvm.HealthCheck(ctx)
+	assert.EqualError(t, err, "failed to connect to Docker daemon: Failed to get client")
+	getClientErr = false
+
+	// Failure case: the docker daemon can't be pinged
+	pingErr = true
+	err = dvm.HealthCheck(ctx)
+	assert.EqualError(t, err, "failed to ping Docker daemon: Error pinging docker daemon")
+	pingErr = false
+
+	// Success case
+	err = dvm.HealthCheck(ctx)
+	assert.NoError(t, err)
+}
+
 func Test_Destroy(t *testing.T) {
 	dvm := DockerVM{}
 	ccid := ccintf.CCID{ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "simple"}}}
@@ -306,7 +327,7 @@ type mockClient struct {
 }
 
 var getClientErr, createErr, uploadErr, noSuchImgErr, buildErr, removeImgErr,
-	startErr, stopErr, killErr, removeErr bool
+	startErr, stopErr, killErr, removeErr, pingErr bool
 
 func (c *mockClient) CreateContainer(options docker.CreateContainerOptions) (*docker.Container, error) {
 	if createErr {
@@ -374,6 +395,13 @@ func (c *mockClient) RemoveContainer(opts docker.RemoveContainerOptions) error {
 	return nil
 }
 
+func (c *mockClient) Ping() error {
+	if pingErr {
+		return errors.New("Error pinging docker daemon")
+	}
+	return nil
+}
+
 func formatInvalidChars(name string) (string, error) {
 	return "inv@lid*character$/", nil
 }
@@ -0,0 +1,166 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package externalcontroller
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/pkg/errors"
+)
+
+// ConnectionFile is the name of the file in the code package of an
+// external chaincode which holds the information needed to reach it
+const ConnectionFile = "connection.json"
+
+// DefaultDialTimeout is used when the connection information does not
+// specify how long to wait for the chaincode server
+const DefaultDialTimeout = 3 * time.Second
+
+// Connection describes how the peer connects to a chaincode server.
+// Certificates and keys are PEM encoded.
+type Connection struct {
+	Address            string `json:"address"`
+	DialTimeout        string `json:"dial_timeout,omitempty"`
+	TLSRequired        bool   `json:"tls_required"`
+	ClientAuthRequired bool   `json:"client_auth_required,omitempty"`
+	ClientKey          string `json:"client_key,omitempty"`
+	ClientCert         string `json:"client_cert,omitempty"`
+	RootCert           string `json:"root_cert,omitempty"`
+}
+
+// Validate checks that the connection information is complete
+func (c *Connection) Validate() error {
+	if c.Address == "" {
+		return errors.New("chaincode address must be specified")
+	}
+	if c.DialTimeout != "" {
+		if _, err := time.ParseDuration(c.DialTimeout); err != nil {
+			return errors.Wrapf(err, "invalid dial timeout %s", c.DialTimeout)
+		}
+	}
+	if !c.TLSRequired {
+		if c.ClientAuthRequired {
+			return errors.New("client authentication requires TLS")
+		}
+		return nil
+	}
+	if c.RootCert == "" {
+		return errors.New("root certificate must be specified when TLS is required")
+	}
+	if c.ClientAuthRequired && (c.ClientKey == "" || c.ClientCert == "") {
+		return errors.New("client key and certificate must be specified when client authentication is required")
+	}
+	return nil
+}
+
+// ClientConfig returns the gRPC client configuration used to dial the
+// chaincode server
+func (c *Connection) ClientConfig() (comm.ClientConfig, error) {
+	if err := c.Validate(); err != nil {
+		return comm.ClientConfig{}, err
+	}
+
+	timeout := DefaultDialTimeout
+	if c.DialTimeout != "" {
+		timeout, _ = time.ParseDuration(c.DialTimeout)
+	}
+
+	secOpts := &comm.SecureOptions{UseTLS: c.TLSRequired}
+	if c.TLSRequired {
+		secOpts.ServerRootCAs = [][]byte{[]byte(c.RootCert)}
+		if c.ClientAuthRequired {
+			secOpts.RequireClientCert = true
+			secOpts.Key = []byte(c.ClientKey)
+			secOpts.Certificate = []byte(c.ClientCert)
+		}
+	}
+
+	return comm.ClientConfig{
+		SecOpts: secOpts,
+		KaOpts:  comm.DefaultKeepaliveOptions(),
+		Timeout: timeout,
+	}, nil
+}
+
+// ParseConnection reads the connection information from the code package
+// of an external chaincode
+func ParseConnection(codePackage io.Reader) (*Connection, error) {
+	gr, err := gzip.NewReader(codePackage)
+	if err != nil {
+		return nil, errors.Wrap(err, "error reading code package")
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil, errors.Errorf("%s not found in code package", ConnectionFile)
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "error reading code package")
+		}
+		if header.Name != ConnectionFile {
+			continue
+		}
+
+		raw, err := ioutil.ReadAll(tr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "error reading %s", ConnectionFile)
+		}
+		conn := &Connection{}
+		if err := json.Unmarshal(raw, conn); err != nil {
+			return nil, errors.Wrapf(err, "error unmarshaling %s", ConnectionFile)
+		}
+		if err := conn.Validate(); err != nil {
+			return nil, errors.WithMessage(err, "invalid connection information")
+		}
+		return conn, nil
+	}
+}
+
+// CreateCodePackage returns the code package of an external chaincode
+// reachable through the supplied connection
+func CreateCodePackage(conn *Connection) ([]byte, error) {
+	if err := conn.Validate(); err != nil {
+		return nil, errors.WithMessage(err, "invalid connection information")
+	}
+	raw, err := json.Marshal(conn)
+	if err != nil {
+		return nil, errors.Wrap(err, "error marshaling connection information")
+	}
+
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	header := &tar.Header{
+		Name:    ConnectionFile,
+		Mode:    0644,
+		Size:    int64(len(raw)),
+		ModTime: time.Unix(0, 0),
+	}
+	if err := tw.WriteHeader(header); err != nil {
+		return nil, errors.Wrap(err, "error writing code package")
+	}
+	if _, err := tw.Write(raw); err != nil {
+		return nil, errors.Wrap(err, "error writing code package")
+	}
+	if err := tw.Close(); err != nil {
+		return nil, errors.Wrap(err, "error writing code package")
+	}
+	if err := gw.Close(); err != nil {
+		return nil, errors.Wrap(err, "error writing code package")
+	}
+	return buf.Bytes(), nil
+}
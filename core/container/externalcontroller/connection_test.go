@@ -0,0 +1,122 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package externalcontroller
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name string
+		conn Connection
+		err  string
+	}{
+		{"plaintext", Connection{Address: "cc:9999"}, ""},
+		{"tls", Connection{Address: "cc:9999", TLSRequired: true, RootCert: "root"}, ""},
+		{"mutual tls", Connection{Address: "cc:9999", TLSRequired: true, ClientAuthRequired: true, RootCert: "root", ClientKey: "key", ClientCert: "cert"}, ""},
+		{"no address", Connection{}, "chaincode address must be specified"},
+		{"bad timeout", Connection{Address: "cc:9999", DialTimeout: "soon"}, "invalid dial timeout soon"},
+		{"client auth without tls", Connection{Address: "cc:9999", ClientAuthRequired: true}, "client authentication requires TLS"},
+		{"no root cert", Connection{Address: "cc:9999", TLSRequired: true}, "root certificate must be specified when TLS is required"},
+		{"no client cert", Connection{Address: "cc:9999", TLSRequired: true, ClientAuthRequired: true, RootCert: "root", ClientKey: "key"}, "client key and certificate must be specified"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.conn.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.err)
+		})
+	}
+}
+
+func TestClientConfig(t *testing.T) {
+	conn := &Connection{Address: "cc:9999"}
+	config, err := conn.ClientConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultDialTimeout, config.Timeout)
+	assert.False(t, config.SecOpts.UseTLS)
+
+	conn = &Connection{
+		Address:            "cc:9999",
+		DialTimeout:        "10s",
+		TLSRequired:        true,
+		ClientAuthRequired: true,
+		RootCert:           "root",
+		ClientKey:          "key",
+		ClientCert:         "cert",
+	}
+	config, err = conn.ClientConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, 10*time.Second, config.Timeout)
+	assert.True(t, config.SecOpts.UseTLS)
+	assert.True(t, config.SecOpts.RequireClientCert)
+	assert.Equal(t, [][]byte{[]byte("root")}, config.SecOpts.ServerRootCAs)
+	assert.Equal(t, []byte("key"), config.SecOpts.Key)
+	assert.Equal(t, []byte("cert"), config.SecOpts.Certificate)
+
+	conn = &Connection{Address: "cc:9999", TLSRequired: true}
+	_, err = conn.ClientConfig()
+	assert.Error(t, err)
+}
+
+func TestCodePackage(t *testing.T) {
+	conn := &Connection{Address: "cc:9999", DialTimeout: "5s", TLSRequired: true, RootCert: "root"}
+	codePackage, err := CreateCodePackage(conn)
+	assert.NoError(t, err)
+
+	parsed, err := ParseConnection(bytes.NewReader(codePackage))
+	assert.NoError(t, err)
+	assert.Equal(t, conn, parsed)
+
+	// the package is deterministic so that it hashes the same on every peer
+	again, err := CreateCodePackage(conn)
+	assert.NoError(t, err)
+	assert.Equal(t, codePackage, again)
+
+	_, err = CreateCodePackage(&Connection{})
+	assert.EqualError(t, err, "invalid connection information: chaincode address must be specified")
+}
+
+func TestParseConnectionErrors(t *testing.T) {
+	_, err := ParseConnection(bytes.NewReader([]byte("not a package")))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "error reading code package")
+
+	_, err = ParseConnection(bytes.NewReader(writeCodePackage(t, "other.json", "{}")))
+	assert.EqualError(t, err, "connection.json not found in code package")
+
+	_, err = ParseConnection(bytes.NewReader(writeCodePackage(t, ConnectionFile, "{")))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "error unmarshaling connection.json")
+
+	_, err = ParseConnection(bytes.NewReader(writeCodePackage(t, ConnectionFile, `{"tls_required":true}`)))
+	assert.EqualError(t, err, "invalid connection information: chaincode address must be specified")
+}
+
+func writeCodePackage(t *testing.T, name, contents string) []byte {
+	buf := &bytes.Buffer{}
+	gw := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gw)
+	assert.NoError(t, tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(contents))}))
+	_, err := tw.Write([]byte(contents))
+	assert.NoError(t, err)
+	assert.NoError(t, tw.Close())
+	assert.NoError(t, gw.Close())
+	return buf.Bytes()
+}
@@ -0,0 +1,175 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package externalcontroller
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/comm"
+	container "github.com/hyperledger/fabric/core/container/api"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+var externalLogger = flogging.MustGetLogger("externalcontroller")
+
+// externalInstance tracks the connection to a running chaincode server
+type externalInstance struct {
+	conn   *grpc.ClientConn
+	cancel context.CancelFunc
+}
+
+var (
+	instLock     sync.Mutex
+	instRegistry = make(map[string]*externalInstance)
+)
+
+// ExternalVM is a vm for chaincode which is deployed and run outside of the
+// peer's control. Rather than launching the chaincode, the peer dials the
+// chaincode server described by the code package and drives the chaincode
+// over the resulting stream.
+type ExternalVM struct{}
+
+// Deploy is a no-op as there is nothing for the peer to build
+func (vm *ExternalVM) Deploy(ctxt context.Context, ccid ccintf.CCID, args []string, env []string, reader io.Reader) error {
+	return nil
+}
+
+// Start connects to the chaincode server. The builder is expected to
+// supply the chaincode's code package, from which the connection
+// information is read.
+func (vm *ExternalVM) Start(ctxt context.Context, ccid ccintf.CCID, args []string, env []string, filesToUpload map[string][]byte, builder container.BuildSpecFactory, prelaunchFunc container.PrelaunchFunc) error {
+	instName, _ := vm.GetVMName(ccid, nil)
+
+	instLock.Lock()
+	_, running := instRegistry[instName]
+	instLock.Unlock()
+	if running {
+		return fmt.Errorf("chaincode running %s", instName)
+	}
+
+	ccSupport, ok := ctxt.Value(ccintf.GetCCHandlerKey()).(ccintf.CCSupport)
+	if !ok || ccSupport == nil {
+		return fmt.Errorf("chaincode stream handler not supplied")
+	}
+
+	if builder == nil {
+		return fmt.Errorf("code package not supplied for %s", instName)
+	}
+	codePackage, err := builder()
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("error getting code package for %s", instName))
+	}
+	connInfo, err := ParseConnection(codePackage)
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("error getting connection information for %s", instName))
+	}
+
+	clientConfig, err := connInfo.ClientConfig()
+	if err != nil {
+		return err
+	}
+	client, err := comm.NewGRPCClient(clientConfig)
+	if err != nil {
+		return errors.WithMessage(err, "error creating chaincode client")
+	}
+
+	externalLogger.Debugf("connecting to chaincode %s at %s", instName, connInfo.Address)
+	conn, err := client.NewConnection(connInfo.Address, "")
+	if err != nil {
+		return errors.WithMessage(err, fmt.Sprintf("error connecting to chaincode %s at %s", instName, connInfo.Address))
+	}
+
+	if prelaunchFunc != nil {
+		if err = prelaunchFunc(); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+
+	// the stream outlives the request that launched the chaincode
+	streamCtx, cancel := context.WithCancel(context.Background())
+	stream, err := pb.NewChaincodeClient(conn).Connect(streamCtx)
+	if err != nil {
+		cancel()
+		conn.Close()
+		return errors.WithMessage(err, fmt.Sprintf("error opening stream to chaincode %s", instName))
+	}
+
+	inst := &externalInstance{conn: conn, cancel: cancel}
+	instLock.Lock()
+	instRegistry[instName] = inst
+	instLock.Unlock()
+
+	go func() {
+		defer vm.release(instName, inst)
+		externalLogger.Debugf("chaincode-support started for %s", instName)
+		err := ccSupport.HandleChaincodeStream(ctxt, stream)
+		if err != nil {
+			externalLogger.Errorf("chaincode %s ended with err: %s", instName, err)
+			return
+		}
+		externalLogger.Debugf("chaincode-support ended for %s", instName)
+	}()
+
+	return nil
+}
+
+// release closes the connection to the chaincode server and removes the
+// instance from the registry if it has not been replaced
+func (vm *ExternalVM) release(instName string, inst *externalInstance) {
+	inst.cancel()
+	inst.conn.Close()
+
+	instLock.Lock()
+	if instRegistry[instName] == inst {
+		delete(instRegistry, instName)
+	}
+	instLock.Unlock()
+}
+
+// Stop disconnects from the chaincode server. The server itself is not
+// managed by the peer and keeps running.
+func (vm *ExternalVM) Stop(ctxt context.Context, ccid ccintf.CCID, timeout uint, dontkill bool, dontremove bool) error {
+	instName, _ := vm.GetVMName(ccid, nil)
+
+	instLock.Lock()
+	inst := instRegistry[instName]
+	instLock.Unlock()
+	if inst == nil {
+		return fmt.Errorf("%s not running", instName)
+	}
+
+	vm.release(instName, inst)
+	return nil
+}
+
+// Destroy is a no-op as there is no image to remove
+func (vm *ExternalVM) Destroy(ctxt context.Context, ccid ccintf.CCID, force bool, noprune bool) error {
+	return nil
+}
+
+// GetVMName returns the canonical name of the chaincode. It accepts a format
+// function parameter to allow different formatting based on the desired use
+// of the name.
+func (vm *ExternalVM) GetVMName(ccid ccintf.CCID, format func(string) (string, error)) (string, error) {
+	name := ccid.GetName()
+	if format != nil {
+		formattedName, err := format(name)
+		if err != nil {
+			return formattedName, err
+		}
+		name = formattedName
+	}
+	return name, nil
+}
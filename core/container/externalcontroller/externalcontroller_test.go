@@ -0,0 +1,175 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package externalcontroller
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/container/ccintf"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+type mockChaincode struct{}
+
+func (cc *mockChaincode) Init(stub shim.ChaincodeStubInterface) pb.Response {
+	return shim.Success(nil)
+}
+
+func (cc *mockChaincode) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
+	return shim.Success(nil)
+}
+
+// mockCCSupport records the chaincode registering over the stream and
+// then drains the stream until it is closed
+type mockCCSupport struct {
+	registered chan *pb.ChaincodeID
+	done       chan struct{}
+}
+
+func newMockCCSupport() *mockCCSupport {
+	return &mockCCSupport{registered: make(chan *pb.ChaincodeID, 1), done: make(chan struct{})}
+}
+
+func (s *mockCCSupport) HandleChaincodeStream(ctxt context.Context, stream ccintf.ChaincodeStream) error {
+	defer close(s.done)
+	msg, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	if msg.Type != pb.ChaincodeMessage_REGISTER {
+		return errors.New("expected REGISTER")
+	}
+	chaincodeID := &pb.ChaincodeID{}
+	if err := proto.Unmarshal(msg.Payload, chaincodeID); err != nil {
+		return err
+	}
+	s.registered <- chaincodeID
+	for {
+		if _, err := stream.Recv(); err != nil {
+			return nil
+		}
+	}
+}
+
+func testCCID() ccintf.CCID {
+	return ccintf.CCID{
+		ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "extcc"}},
+		Version:       "1.0",
+	}
+}
+
+func builderFor(t *testing.T, conn *Connection) func() (io.Reader, error) {
+	codePackage, err := CreateCodePackage(conn)
+	assert.NoError(t, err)
+	return func() (io.Reader, error) { return bytes.NewReader(codePackage), nil }
+}
+
+func freeAddress(t *testing.T) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	defer lis.Close()
+	return lis.Addr().String()
+}
+
+func TestStartAndStop(t *testing.T) {
+	address := freeAddress(t)
+	server := &shim.ChaincodeServer{
+		CCID:     "extcc:1.0",
+		Address:  address,
+		CC:       &mockChaincode{},
+		TLSProps: shim.TLSProperties{Disabled: true},
+	}
+	go server.Start()
+
+	ccSupport := newMockCCSupport()
+	ctxt := context.WithValue(context.Background(), ccintf.GetCCHandlerKey(), ccSupport)
+
+	prelaunched := false
+	prelaunch := func() error {
+		prelaunched = true
+		return nil
+	}
+
+	vm := &ExternalVM{}
+	builder := builderFor(t, &Connection{Address: address})
+	err := vm.Start(ctxt, testCCID(), nil, nil, nil, builder, prelaunch)
+	assert.NoError(t, err)
+	assert.True(t, prelaunched)
+
+	select {
+	case chaincodeID := <-ccSupport.registered:
+		assert.Equal(t, "extcc:1.0", chaincodeID.Name)
+	case <-time.After(5 * time.Second):
+		t.Fatal("chaincode did not register")
+	}
+
+	err = vm.Start(ctxt, testCCID(), nil, nil, nil, builder, prelaunch)
+	assert.EqualError(t, err, "chaincode running extcc-1.0")
+
+	err = vm.Stop(ctxt, testCCID(), 0, false, false)
+	assert.NoError(t, err)
+
+	select {
+	case <-ccSupport.done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream was not closed")
+	}
+
+	err = vm.Stop(ctxt, testCCID(), 0, false, false)
+	assert.EqualError(t, err, "extcc-1.0 not running")
+}
+
+func TestStartErrors(t *testing.T) {
+	vm := &ExternalVM{}
+	ctxt := context.WithValue(context.Background(), ccintf.GetCCHandlerKey(), newMockCCSupport())
+
+	err := vm.Start(context.Background(), testCCID(), nil, nil, nil, builderFor(t, &Connection{Address: "127.0.0.1:1"}), nil)
+	assert.EqualError(t, err, "chaincode stream handler not supplied")
+
+	err = vm.Start(ctxt, testCCID(), nil, nil, nil, nil, nil)
+	assert.EqualError(t, err, "code package not supplied for extcc-1.0")
+
+	badBuilder := func() (io.Reader, error) { return nil, errors.New("no package") }
+	err = vm.Start(ctxt, testCCID(), nil, nil, nil, badBuilder, nil)
+	assert.EqualError(t, err, "error getting code package for extcc-1.0: no package")
+
+	notAPackage := func() (io.Reader, error) { return bytes.NewReader([]byte("garbage")), nil }
+	err = vm.Start(ctxt, testCCID(), nil, nil, nil, notAPackage, nil)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "error getting connection information for extcc-1.0")
+
+	prelaunch := func() error {
+		t.Fatal("prelaunch must not run when the chaincode cannot be reached")
+		return nil
+	}
+	unreachable := builderFor(t, &Connection{Address: freeAddress(t), DialTimeout: "100ms"})
+	err = vm.Start(ctxt, testCCID(), nil, nil, nil, unreachable, prelaunch)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "error connecting to chaincode extcc-1.0")
+}
+
+func TestGetVMName(t *testing.T) {
+	vm := &ExternalVM{}
+	name, err := vm.GetVMName(testCCID(), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "extcc-1.0", name)
+
+	name, err = vm.GetVMName(testCCID(), func(name string) (string, error) {
+		return "formatted-" + name, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "formatted-extcc-1.0", name)
+}
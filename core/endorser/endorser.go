@@ -8,14 +8,17 @@ package endorser
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/common/resourcesconfig"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/chaincode"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/lifecycle"
 	"github.com/hyperledger/fabric/core/common/validation"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/protos/common"
@@ -92,6 +95,9 @@ type Support interface {
 	// GetApplicationConfig returns the configtxapplication.SharedConfig for the channel
 	// and whether the Application config exists
 	GetApplicationConfig(cid string) (channelconfig.Application, bool)
+
+	// EndorseWithPlugin endorses the response with a plugin
+	EndorseWithPlugin(ctx Context) (*pb.ProposalResponse, error)
 }
 
 // Endorser provides the Endorser service ProcessProposal
@@ -118,8 +124,8 @@ func NewEndorserServer(privDist privateDataDistributor, s Support) pb.EndorserSe
 	return e
 }
 
-//call specified chaincode (system or user)
-func (e *Endorser) callChaincode(ctxt context.Context, chainID string, version string, txid string, signedProp *pb.SignedProposal, prop *pb.Proposal, cis *pb.ChaincodeInvocationSpec, cid *pb.ChaincodeID, txsim ledger.TxSimulator) (*pb.Response, *pb.ChaincodeEvent, error) {
+//call specified chaincode (system or user), running its Init rather than its Invoke if isInit is set
+func (e *Endorser) callChaincode(ctxt context.Context, chainID string, version string, txid string, signedProp *pb.SignedProposal, prop *pb.Proposal, cis *pb.ChaincodeInvocationSpec, cid *pb.ChaincodeID, txsim ledger.TxSimulator, isInit bool) (*pb.Response, *pb.ChaincodeEvent, error) {
 	endorserLogger.Debugf("[%s][%s] Entry chaincode: %s version: %s", chainID, shorttxid(txid), cid, version)
 	defer endorserLogger.Debugf("[%s][%s] Exit", chainID, shorttxid(txid))
 	var err error
@@ -133,7 +139,19 @@ func (e *Endorser) callChaincode(ctxt context.Context, chainID string, version s
 	//is this a system chaincode
 	scc := e.s.IsSysCC(cid.Name)
 
-	res, ccevent, err = e.s.Execute(ctxt, chainID, cid.Name, version, txid, scc, signedProp, prop, cis)
+	var spec interface{} = cis
+	if isInit {
+		// the chaincode runs its Init when it is sent a deployment spec
+		spec, err = e.SanitizeUserCDS(&pb.ChaincodeDeploymentSpec{ChaincodeSpec: &pb.ChaincodeSpec{
+			ChaincodeId: &pb.ChaincodeID{Name: cid.Name, Version: version},
+			Input:       cis.ChaincodeSpec.Input,
+		}})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	res, ccevent, err = e.s.Execute(ctxt, chainID, cid.Name, version, txid, scc, signedProp, prop, spec)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -193,6 +211,37 @@ func (e *Endorser) SanitizeUserCDS(userCDS *pb.ChaincodeDeploymentSpec) (*pb.Cha
 	return sanitizedCDS, nil
 }
 
+// checkInit returns whether an invocation of a chaincode whose definition
+// requires Init runs its Init, which is the case when the invocation names
+// the init function. Init must run once for each version of the definition,
+// before any other invocation, and the version it ran for is recorded in
+// the namespace of the chaincode.
+func checkInit(def *lifecycle.Definition, cis *pb.ChaincodeInvocationSpec, txsim ledger.TxSimulator) (bool, error) {
+	if txsim == nil {
+		return false, errors.Errorf("chaincode %s requires Init, which cannot be checked without a transaction simulator", def.Name)
+	}
+	initialized, err := txsim.GetState(def.Name, lifecycle.InitializedKey)
+	if err != nil {
+		return false, errors.WithMessage(err, fmt.Sprintf("could not check whether chaincode %s is initialized", def.Name))
+	}
+
+	args := cis.GetChaincodeSpec().GetInput().GetArgs()
+	isInit := len(args) > 0 && string(args[0]) == lifecycle.InitFunction
+	switch done := string(initialized) == def.Version; {
+	case isInit && done:
+		return false, errors.Errorf("chaincode %s is already initialized for version %s", def.Name, def.Version)
+	case !isInit && !done:
+		return false, errors.Errorf("chaincode %s must be initialized by invoking %s first", def.Name, lifecycle.InitFunction)
+	case !isInit:
+		return false, nil
+	}
+
+	if err = txsim.SetState(def.Name, lifecycle.InitializedKey, []byte(def.Version)); err != nil {
+		return false, errors.WithMessage(err, fmt.Sprintf("could not record the initialization of chaincode %s", def.Name))
+	}
+	return true, nil
+}
+
 // TO BE REMOVED WHEN JAVA CC IS ENABLED
 // disableJavaCCInst if trying to install, instantiate or upgrade Java CC
 func (e *Endorser) disableJavaCCInst(cid *pb.ChaincodeID, cis *pb.ChaincodeInvocationSpec) error {
@@ -263,6 +312,7 @@ func (e *Endorser) simulateProposal(ctx context.Context, chainID string, txid st
 
 	var cdLedger resourcesconfig.ChaincodeDefinition
 	var version string
+	var isInit bool
 
 	if !e.s.IsSysCC(cid.Name) {
 		cdLedger, err = e.s.GetChaincodeDefinition(ctx, chainID, txid, signedProp, prop, cid.Name, txsim)
@@ -275,6 +325,12 @@ func (e *Endorser) simulateProposal(ctx context.Context, chainID string, txid st
 		if err != nil {
 			return nil, nil, nil, nil, err
 		}
+
+		if def, ok := cdLedger.(*lifecycle.Definition); ok && def.InitRequired {
+			if isInit, err = checkInit(def, cis, txsim); err != nil {
+				return nil, nil, nil, nil, err
+			}
+		}
 	} else {
 		version = util.GetSysCCVersion()
 	}
@@ -284,7 +340,7 @@ func (e *Endorser) simulateProposal(ctx context.Context, chainID string, txid st
 	var pubSimResBytes []byte
 	var res *pb.Response
 	var ccevent *pb.ChaincodeEvent
-	res, ccevent, err = e.callChaincode(ctx, chainID, version, txid, signedProp, prop, cis, cid, txsim)
+	res, ccevent, err = e.callChaincode(ctx, chainID, version, txid, signedProp, prop, cis, cid, txsim, isInit)
 	if err != nil {
 		endorserLogger.Errorf("[%s][%s] failed to invoke chaincode %s, error: %+v", chainID, shorttxid(txid), cid, err)
 		return nil, nil, nil, nil, err
@@ -311,13 +367,13 @@ func (e *Endorser) simulateProposal(ctx context.Context, chainID string, txid st
 	return cdLedger, res, pubSimResBytes, ccevent, nil
 }
 
-//endorse the proposal by calling the ESCC
+//endorse the proposal with the endorsement plugin of the chaincode
 func (e *Endorser) endorseProposal(ctx context.Context, chainID string, txid string, signedProp *pb.SignedProposal, proposal *pb.Proposal, response *pb.Response, simRes []byte, event *pb.ChaincodeEvent, visibility []byte, ccid *pb.ChaincodeID, txsim ledger.TxSimulator, cd resourcesconfig.ChaincodeDefinition) (*pb.ProposalResponse, error) {
 	endorserLogger.Debugf("[%s][%s] Entry chaincode: %s", chainID, shorttxid(txid), ccid)
 	defer endorserLogger.Debugf("[%s][%s] Exit", chainID, shorttxid(txid))
 
 	isSysCC := cd == nil
-	// 1) extract the name of the escc that is requested to endorse this chaincode
+	// 1) extract the name of the endorsement plugin that is requested to endorse this chaincode
 	var escc string
 	//ie, "lscc" or system chaincodes
 	if isSysCC {
@@ -338,11 +394,6 @@ func (e *Endorser) endorseProposal(ctx context.Context, chainID string, txid str
 		}
 	}
 
-	resBytes, err := putils.GetBytesResponse(response)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to marshal response bytes")
-	}
-
 	// set version of executing chaincode
 	if isSysCC {
 		// if we want to allow mixed fabric levels we should
@@ -352,50 +403,20 @@ func (e *Endorser) endorseProposal(ctx context.Context, chainID string, txid str
 		ccid.Version = cd.CCVersion()
 	}
 
-	ccidBytes, err := putils.Marshal(ccid)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to marshal ChaincodeID")
-	}
-
-	// 3) call the ESCC we've identified
-	// arguments:
-	// args[0] - function name (not used now)
-	// args[1] - serialized Header object
-	// args[2] - serialized ChaincodeProposalPayload object
-	// args[3] - ChaincodeID of executing chaincode
-	// args[4] - result of executing chaincode
-	// args[5] - binary blob of simulation results
-	// args[6] - serialized events
-	// args[7] - payloadVisibility
-	args := [][]byte{[]byte(""), proposal.Header, proposal.Payload, ccidBytes, resBytes, simRes, eventBytes, visibility}
-	version := util.GetSysCCVersion()
-	ecccis := &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{Type: pb.ChaincodeSpec_GOLANG, ChaincodeId: &pb.ChaincodeID{Name: escc}, Input: &pb.ChaincodeInput{Args: args}}}
-	res, _, err := e.callChaincode(ctx, chainID, version, txid, signedProp, proposal, ecccis, &pb.ChaincodeID{Name: escc}, txsim)
-	if err != nil {
-		return nil, err
-	}
-
-	if res.Status >= shim.ERRORTHRESHOLD {
-		return &pb.ProposalResponse{Response: res}, nil
-	}
-
-	prBytes := res.Payload
-	// Note that we do not extract any simulation results from
-	// the call to ESCC. This is intentional becuse ESCC is meant
-	// to endorse (i.e. sign) the simulation results of a chaincode,
-	// but it can't obviously sign its own. Furthermore, ESCC runs
-	// on private input (its own signing key) and so if it were to
-	// produce simulationr results, they are likely to be different
-	// from other ESCCs, which would stand in the way of the
-	// endorsement process.
-
-	//3 -- respond
-	pResp, err := putils.GetProposalResponse(prBytes)
-	if err != nil {
-		return nil, err
-	}
-
-	return pResp, nil
+	// 2) endorse with the plugin we've identified
+	pluginCtx := Context{
+		Proposal:       proposal,
+		SignedProposal: signedProp,
+		Visibility:     visibility,
+		Response:       response,
+		Event:          eventBytes,
+		SimRes:         simRes,
+		ChaincodeID:    ccid,
+		Channel:        chainID,
+		TxID:           txid,
+		PluginName:     escc,
+	}
+	return e.s.EndorseWithPlugin(pluginCtx)
 }
 
 //preProcess checks the tx proposal headers, uniqueness and ACL
@@ -470,6 +491,13 @@ func (e *Endorser) preProcess(signedProp *pb.SignedProposal) (*validateResult, e
 
 // ProcessProposal process the Proposal
 func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedProposal) (*pb.ProposalResponse, error) {
+	startTime := time.Now()
+	endorserMetrics := metrics.RootScope.SubScope("endorser")
+	endorserMetrics.Counter("proposals_received").Inc(1)
+	defer func() {
+		endorserMetrics.Timer("proposal_duration").Record(time.Since(startTime))
+	}()
+
 	addr := util.ExtractRemoteAddress(ctx)
 	endorserLogger.Debug("Entering: Got request from", addr)
 	defer endorserLogger.Debugf("Exit: request from", addr)
@@ -557,6 +585,7 @@ func (e *Endorser) ProcessProposal(ctx context.Context, signedProp *pb.SignedPro
 	// contains the "return value" from the
 	// chaincode invocation
 	pResp.Response.Payload = res.Payload
+	endorserMetrics.Counter("successful_proposals").Inc(1)
 
 	return pResp, nil
 }
@@ -4,7 +4,7 @@ Copyright IBM Corp. All Rights Reserved.
 SPDX-License-Identifier: Apache-2.0
 */
 
-package endorser
+package endorser_test
 
 import (
 	"context"
@@ -16,6 +16,8 @@ import (
 	mc "github.com/hyperledger/fabric/common/mocks/config"
 	"github.com/hyperledger/fabric/common/mocks/resourcesconfig"
 	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/common/lifecycle"
+	"github.com/hyperledger/fabric/core/endorser"
 	"github.com/hyperledger/fabric/core/endorser/mocks"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/mocks/ccprovider"
@@ -26,6 +28,7 @@ import (
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	pb "github.com/hyperledger/fabric/protos/peer"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -59,7 +62,7 @@ func getSignedPropWithCHIdAndArgs(chid, ccid, ccver string, ccargs [][]byte, t *
 }
 
 func TestEndorserNilProp(t *testing.T) {
-	es := NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
+	es := endorser.NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
 		return nil
 	}, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
@@ -75,7 +78,7 @@ func TestEndorserNilProp(t *testing.T) {
 }
 
 func TestEndorserUninvokableSysCC(t *testing.T) {
-	es := NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
+	es := endorser.NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
 		return nil
 	}, &em.MockSupport{
 		GetApplicationConfigBoolRv:       true,
@@ -91,7 +94,7 @@ func TestEndorserUninvokableSysCC(t *testing.T) {
 }
 
 func TestEndorserCCInvocationFailed(t *testing.T) {
-	es := NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
+	es := endorser.NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
 		return nil
 	}, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
@@ -109,7 +112,7 @@ func TestEndorserCCInvocationFailed(t *testing.T) {
 }
 
 func TestEndorserNoCCDef(t *testing.T) {
-	es := NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
+	es := endorser.NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
 		return nil
 	}, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
@@ -127,7 +130,7 @@ func TestEndorserNoCCDef(t *testing.T) {
 }
 
 func TestEndorserBadInstPolicy(t *testing.T) {
-	es := NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
+	es := endorser.NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
 		return nil
 	}, &em.MockSupport{
 		GetApplicationConfigBoolRv:    true,
@@ -146,7 +149,7 @@ func TestEndorserBadInstPolicy(t *testing.T) {
 }
 
 func TestEndorserSysCC(t *testing.T) {
-	es := NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
+	es := endorser.NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
 		return nil
 	}, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
@@ -165,7 +168,7 @@ func TestEndorserSysCC(t *testing.T) {
 }
 
 func TestEndorserCCInvocationError(t *testing.T) {
-	es := NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
+	es := endorser.NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
 		return nil
 	}, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
@@ -183,7 +186,7 @@ func TestEndorserCCInvocationError(t *testing.T) {
 }
 
 func TestEndorserLSCCBadType(t *testing.T) {
-	es := NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
+	es := endorser.NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
 		return nil
 	}, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
@@ -209,7 +212,7 @@ func TestEndorserLSCCBadType(t *testing.T) {
 }
 
 func TestEndorserDupTXId(t *testing.T) {
-	es := NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
+	es := endorser.NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
 		return nil
 	}, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
@@ -226,7 +229,7 @@ func TestEndorserDupTXId(t *testing.T) {
 }
 
 func TestEndorserBadACL(t *testing.T) {
-	es := NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
+	es := endorser.NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
 		return nil
 	}, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
@@ -245,7 +248,7 @@ func TestEndorserBadACL(t *testing.T) {
 }
 
 func TestEndorserGoodPathEmptyChannel(t *testing.T) {
-	es := NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
+	es := endorser.NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
 		return nil
 	}, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
@@ -263,7 +266,7 @@ func TestEndorserGoodPathEmptyChannel(t *testing.T) {
 }
 
 func TestEndorserLSCCInitFails(t *testing.T) {
-	es := NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
+	es := endorser.NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
 		return nil
 	}, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
@@ -293,7 +296,7 @@ func TestEndorserLSCCDeploySysCC(t *testing.T) {
 	SysCCMap := make(map[string]struct{})
 	deployedCCName := "barf"
 	SysCCMap[deployedCCName] = struct{}{}
-	es := NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
+	es := endorser.NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
 		return nil
 	}, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
@@ -320,11 +323,11 @@ func TestEndorserLSCCDeploySysCC(t *testing.T) {
 }
 
 func TestEndorserLSCCJava1(t *testing.T) {
-	if javaEnabled() {
+	if endorser.JavaEnabled() {
 		t.Skip("Java chaincode is supported")
 	}
 
-	es := NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
+	es := endorser.NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
 		return nil
 	}, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
@@ -351,11 +354,11 @@ func TestEndorserLSCCJava1(t *testing.T) {
 }
 
 func TestEndorserLSCCJava2(t *testing.T) {
-	if javaEnabled() {
+	if endorser.JavaEnabled() {
 		t.Skip("Java chaincode is supported")
 	}
 
-	es := NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
+	es := endorser.NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
 		return nil
 	}, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
@@ -382,7 +385,7 @@ func TestEndorserLSCCJava2(t *testing.T) {
 }
 
 func TestEndorserGoodPathWEvents(t *testing.T) {
-	es := NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
+	es := endorser.NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
 		return nil
 	}, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
@@ -401,7 +404,7 @@ func TestEndorserGoodPathWEvents(t *testing.T) {
 }
 
 func TestEndorserBadChannel(t *testing.T) {
-	es := NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
+	es := endorser.NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
 		return nil
 	}, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
@@ -419,7 +422,7 @@ func TestEndorserBadChannel(t *testing.T) {
 }
 
 func TestEndorserGoodPath(t *testing.T) {
-	es := NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
+	es := endorser.NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
 		return nil
 	}, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
@@ -437,7 +440,7 @@ func TestEndorserGoodPath(t *testing.T) {
 }
 
 func TestEndorserLSCC(t *testing.T) {
-	es := NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
+	es := endorser.NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
 		return nil
 	}, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
@@ -463,7 +466,7 @@ func TestEndorserLSCC(t *testing.T) {
 }
 
 func TestSimulateProposal(t *testing.T) {
-	es := NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
+	es := endorser.NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
 		return nil
 	}, &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
@@ -474,39 +477,80 @@ func TestSimulateProposal(t *testing.T) {
 		GetTxSimulatorRv:           &ccprovider.MockTxSim{&ledger.TxSimulationResults{PubSimulationResults: &rwset.TxReadWriteSet{}}},
 	})
 
-	_, _, _, _, err := es.(*Endorser).simulateProposal(nil, "", "", nil, nil, nil, nil)
+	_, _, _, _, err := es.(*endorser.Endorser).SimulateProposal(nil, "", "", nil, nil, nil, nil)
 	assert.Error(t, err)
 }
 
-func TestEndorserJavaChecks(t *testing.T) {
-	if javaEnabled() {
-		t.Skip("Java chaincode is supported")
-	}
+// stateTxSim is a transaction simulator keeping the state in memory
+type stateTxSim struct {
+	*ccprovider.MockTxSim
+	state map[string][]byte
+}
 
-	es := NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
-		return nil
-	}, &em.MockSupport{
+func (s *stateTxSim) GetState(namespace string, key string) ([]byte, error) {
+	return s.state[namespace+"/"+key], nil
+}
+
+func (s *stateTxSim) SetState(namespace string, key string, value []byte) error {
+	s.state[namespace+"/"+key] = value
+	return nil
+}
+
+func TestSimulateProposalInitRequired(t *testing.T) {
+	support := &em.MockSupport{
 		GetApplicationConfigBoolRv: true,
 		GetApplicationConfigRv:     &mc.MockApplication{&mc.MockApplicationCapabilities{}},
 		GetTransactionByIDErr:      errors.New(""),
-		ChaincodeDefinitionRv:      &resourceconfig.MockChaincodeDefinition{EndorsementStr: "ESCC"},
-		ExecuteResp:                &pb.Response{Status: 200, Payload: utils.MarshalOrPanic(&pb.ProposalResponse{Response: &pb.Response{}})},
-		GetTxSimulatorRv:           &ccprovider.MockTxSim{&ledger.TxSimulationResults{PubSimulationResults: &rwset.TxReadWriteSet{}}},
-	})
+		ChaincodeDefinitionRv: &lifecycle.Definition{Name: "mycc", ChaincodeDefinition: &lb.ChaincodeDefinition{
+			Sequence:          1,
+			Version:           "1.0",
+			EndorsementPlugin: "ESCC",
+			InitRequired:      true,
+		}},
+		ExecuteResp:    &pb.Response{Status: 200, Message: "invoked"},
+		ExecuteCDSResp: &pb.Response{Status: 200, Message: "initialized"},
+	}
+	es := endorser.NewEndorserServer(func(channel string, txID string, privateData *rwset.TxPvtReadWriteSet) error {
+		return nil
+	}, support).(*endorser.Endorser)
+	txsim := &stateTxSim{
+		MockTxSim: &ccprovider.MockTxSim{GetTxSimulationResultsRv: &ledger.TxSimulationResults{PubSimulationResults: &rwset.TxReadWriteSet{}}},
+		state:     map[string][]byte{},
+	}
 
-	err := es.(*Endorser).disableJavaCCInst(&pb.ChaincodeID{Name: "lscc"}, &pb.ChaincodeInvocationSpec{})
-	assert.NoError(t, err)
-	err = es.(*Endorser).disableJavaCCInst(&pb.ChaincodeID{Name: "lscc"}, &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{Input: &pb.ChaincodeInput{}}})
+	simulate := func(function string) (*pb.Response, error) {
+		signedProp := getSignedPropWithCHIdAndArgs(util.GetTestChainID(), "mycc", "", [][]byte{[]byte(function)}, t)
+		prop, err := utils.GetProposal(signedProp.ProposalBytes)
+		assert.NoError(t, err)
+		_, res, _, _, err := es.SimulateProposal(context.Background(), util.GetTestChainID(), "txid", signedProp, prop, &pb.ChaincodeID{Name: "mycc"}, txsim)
+		return res, err
+	}
+
+	_, err := simulate("invoke")
+	assert.EqualError(t, err, "chaincode mycc must be initialized by invoking init first")
+
+	res, err := simulate(lifecycle.InitFunction)
 	assert.NoError(t, err)
-	err = es.(*Endorser).disableJavaCCInst(&pb.ChaincodeID{Name: "lscc"}, &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{Input: &pb.ChaincodeInput{Args: [][]byte{[]byte("foo")}}}})
+	assert.Equal(t, "initialized", res.Message)
+	assert.Equal(t, []byte("1.0"), txsim.state["mycc/"+lifecycle.InitializedKey])
+
+	_, err = simulate(lifecycle.InitFunction)
+	assert.EqualError(t, err, "chaincode mycc is already initialized for version 1.0")
+
+	res, err = simulate("invoke")
 	assert.NoError(t, err)
-	err = es.(*Endorser).disableJavaCCInst(&pb.ChaincodeID{Name: "lscc"}, &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{Input: &pb.ChaincodeInput{Args: [][]byte{[]byte("install")}}}})
-	assert.Error(t, err)
-}
+	assert.Equal(t, "invoked", res.Message)
+
+	// a new version of the definition must be initialized again
+	support.ChaincodeDefinitionRv.(*lifecycle.Definition).Version = "2.0"
+	_, err = simulate("invoke")
+	assert.EqualError(t, err, "chaincode mycc must be initialized by invoking init first")
 
-func TestChaincodeError_Error(t *testing.T) {
-	ce := &chaincodeError{status: 1, msg: "foo"}
-	assert.Equal(t, ce.Error(), "chaincode error (status: 1, message: foo)")
+	// Init is not run when the definition does not require it
+	support.ChaincodeDefinitionRv.(*lifecycle.Definition).InitRequired = false
+	res, err = simulate(lifecycle.InitFunction)
+	assert.NoError(t, err)
+	assert.Equal(t, "invoked", res.Message)
 }
 
 func TestEndorserAcquireTxSimulator(t *testing.T) {
@@ -539,7 +583,7 @@ func TestEndorserAcquireTxSimulator(t *testing.T) {
 				ChaincodeDefinitionRv:      &resourceconfig.MockChaincodeDefinition{EndorsementStr: "ESCC"},
 				ExecuteResp:                expectedResponse,
 			}
-			es := NewEndorserServer(
+			es := endorser.NewEndorserServer(
 				func(string, string, *rwset.TxPvtReadWriteSet) error { return nil },
 				support,
 			)
@@ -583,12 +627,12 @@ func TestMain(m *testing.M) {
 
 //go:generate counterfeiter -o mocks/support.go --fake-name Support . support
 type support interface {
-	Support
+	endorser.Support
 }
 
 func TestUserCDSSanitization(t *testing.T) {
 	fakeSupport := &mocks.Support{}
-	e := NewEndorserServer(nil, fakeSupport)
+	e := endorser.NewEndorserServer(nil, fakeSupport)
 
 	userCDS := &pb.ChaincodeDeploymentSpec{
 		ChaincodeSpec: &pb.ChaincodeSpec{
@@ -619,7 +663,7 @@ func TestUserCDSSanitization(t *testing.T) {
 
 	fakeSupport.GetChaincodeDeploymentSpecFSReturns(fsCDS, nil)
 
-	sanitizedCDS, err := e.(*Endorser).SanitizeUserCDS(userCDS)
+	sanitizedCDS, err := e.(*endorser.Endorser).SanitizeUserCDS(userCDS)
 	assert.NoError(t, err)
 	assert.Nil(t, sanitizedCDS.CodePackage)
 	assert.True(t, proto.Equal(userCDS.ChaincodeSpec.Input, sanitizedCDS.ChaincodeSpec.Input))
@@ -627,7 +671,7 @@ func TestUserCDSSanitization(t *testing.T) {
 
 	t.Run("BadPath", func(t *testing.T) {
 		fakeSupport.GetChaincodeDeploymentSpecFSReturns(nil, fmt.Errorf("fake-error"))
-		_, err := e.(*Endorser).SanitizeUserCDS(userCDS)
+		_, err := e.(*endorser.Endorser).SanitizeUserCDS(userCDS)
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "fake-error")
 	})
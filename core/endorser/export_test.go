@@ -0,0 +1,47 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/resourcesconfig"
+	"github.com/hyperledger/fabric/core/ledger"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+// JavaEnabled exposes javaEnabled to the tests of package endorser_test
+var JavaEnabled = javaEnabled
+
+// SimulateProposal exposes simulateProposal to the tests of package endorser_test
+func (e *Endorser) SimulateProposal(ctx context.Context, chainID string, txid string, signedProp *pb.SignedProposal, prop *pb.Proposal, cid *pb.ChaincodeID, txsim ledger.TxSimulator) (resourcesconfig.ChaincodeDefinition, *pb.Response, []byte, *pb.ChaincodeEvent, error) {
+	return e.simulateProposal(ctx, chainID, txid, signedProp, prop, cid, txsim)
+}
+
+func TestEndorserJavaChecks(t *testing.T) {
+	if javaEnabled() {
+		t.Skip("Java chaincode is supported")
+	}
+
+	e := &Endorser{}
+
+	err := e.disableJavaCCInst(&pb.ChaincodeID{Name: "lscc"}, &pb.ChaincodeInvocationSpec{})
+	assert.NoError(t, err)
+	err = e.disableJavaCCInst(&pb.ChaincodeID{Name: "lscc"}, &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{Input: &pb.ChaincodeInput{}}})
+	assert.NoError(t, err)
+	err = e.disableJavaCCInst(&pb.ChaincodeID{Name: "lscc"}, &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{Input: &pb.ChaincodeInput{Args: [][]byte{[]byte("foo")}}}})
+	assert.NoError(t, err)
+	err = e.disableJavaCCInst(&pb.ChaincodeID{Name: "lscc"}, &pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{Input: &pb.ChaincodeInput{Args: [][]byte{[]byte("install")}}}})
+	assert.Error(t, err)
+}
+
+func TestChaincodeError_Error(t *testing.T) {
+	ce := &chaincodeError{status: 1, msg: "foo"}
+	assert.Equal(t, ce.Error(), "chaincode error (status: 1, message: foo)")
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/resourcesconfig"
+	"github.com/hyperledger/fabric/core/endorser"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
@@ -184,6 +185,19 @@ type Support struct {
 		result1 channelconfig.Application
 		result2 bool
 	}
+	EndorseWithPluginStub        func(ctx endorser.Context) (*pb.ProposalResponse, error)
+	endorseWithPluginMutex       sync.RWMutex
+	endorseWithPluginArgsForCall []struct {
+		ctx endorser.Context
+	}
+	endorseWithPluginReturns struct {
+		result1 *pb.ProposalResponse
+		result2 error
+	}
+	endorseWithPluginReturnsOnCall map[int]struct {
+		result1 *pb.ProposalResponse
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -817,6 +831,57 @@ func (fake *Support) GetApplicationConfigReturnsOnCall(i int, result1 channelcon
 	}{result1, result2}
 }
 
+func (fake *Support) EndorseWithPlugin(ctx endorser.Context) (*pb.ProposalResponse, error) {
+	fake.endorseWithPluginMutex.Lock()
+	ret, specificReturn := fake.endorseWithPluginReturnsOnCall[len(fake.endorseWithPluginArgsForCall)]
+	fake.endorseWithPluginArgsForCall = append(fake.endorseWithPluginArgsForCall, struct {
+		ctx endorser.Context
+	}{ctx})
+	fake.recordInvocation("EndorseWithPlugin", []interface{}{ctx})
+	fake.endorseWithPluginMutex.Unlock()
+	if fake.EndorseWithPluginStub != nil {
+		return fake.EndorseWithPluginStub(ctx)
+	}
+	if specificReturn {
+		return ret.result1, ret.result2
+	}
+	return fake.endorseWithPluginReturns.result1, fake.endorseWithPluginReturns.result2
+}
+
+func (fake *Support) EndorseWithPluginCallCount() int {
+	fake.endorseWithPluginMutex.RLock()
+	defer fake.endorseWithPluginMutex.RUnlock()
+	return len(fake.endorseWithPluginArgsForCall)
+}
+
+func (fake *Support) EndorseWithPluginArgsForCall(i int) endorser.Context {
+	fake.endorseWithPluginMutex.RLock()
+	defer fake.endorseWithPluginMutex.RUnlock()
+	return fake.endorseWithPluginArgsForCall[i].ctx
+}
+
+func (fake *Support) EndorseWithPluginReturns(result1 *pb.ProposalResponse, result2 error) {
+	fake.EndorseWithPluginStub = nil
+	fake.endorseWithPluginReturns = struct {
+		result1 *pb.ProposalResponse
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *Support) EndorseWithPluginReturnsOnCall(i int, result1 *pb.ProposalResponse, result2 error) {
+	fake.EndorseWithPluginStub = nil
+	if fake.endorseWithPluginReturnsOnCall == nil {
+		fake.endorseWithPluginReturnsOnCall = make(map[int]struct {
+			result1 *pb.ProposalResponse
+			result2 error
+		})
+	}
+	fake.endorseWithPluginReturnsOnCall[i] = struct {
+		result1 *pb.ProposalResponse
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *Support) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -844,6 +909,8 @@ func (fake *Support) Invocations() map[string][][]interface{} {
 	defer fake.getChaincodeDeploymentSpecFSMutex.RUnlock()
 	fake.getApplicationConfigMutex.RLock()
 	defer fake.getApplicationConfigMutex.RUnlock()
+	fake.endorseWithPluginMutex.RLock()
+	defer fake.endorseWithPluginMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -0,0 +1,257 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	endorsement "github.com/hyperledger/fabric/core/handlers/endorsement/api"
+	"github.com/hyperledger/fabric/core/handlers/endorsement/api/identities"
+	"github.com/hyperledger/fabric/core/handlers/endorsement/api/state"
+	"github.com/hyperledger/fabric/core/ledger"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// PluginName defines the name of the plugin as it appears in the configuration
+type PluginName string
+
+// PluginMapper maps plugin names to their corresponding factory instance.
+// Returns nil if the name isn't associated to any plugin.
+type PluginMapper interface {
+	PluginFactoryByName(name PluginName) endorsement.PluginFactory
+}
+
+// MapBasedPluginMapper maps plugin names to their corresponding factories
+type MapBasedPluginMapper map[string]endorsement.PluginFactory
+
+// PluginFactoryByName returns a plugin factory for the given plugin name, or nil if not found
+func (m MapBasedPluginMapper) PluginFactoryByName(name PluginName) endorsement.PluginFactory {
+	return m[string(name)]
+}
+
+// Context defines the data that is related to an in-flight endorsement
+type Context struct {
+	PluginName     string
+	ChaincodeID    *pb.ChaincodeID
+	Channel        string
+	TxID           string
+	Proposal       *pb.Proposal
+	SignedProposal *pb.SignedProposal
+	Visibility     []byte
+	Response       *pb.Response
+	Event          []byte
+	SimRes         []byte
+}
+
+// String returns a text representation of this context
+func (c Context) String() string {
+	return fmt.Sprintf("{plugin: %s, channel: %s, tx: %s, chaincode: %s}", c.PluginName, c.Channel, c.TxID, c.ChaincodeID.Name)
+}
+
+// QueryCreator creates new QueryExecutors
+type QueryCreator interface {
+	NewQueryExecutor() (ledger.QueryExecutor, error)
+}
+
+// ChannelStateRetriever retrieves Channel state
+type ChannelStateRetriever interface {
+	// NewQueryCreator returns a QueryCreator for the given Channel
+	NewQueryCreator(channel string) (QueryCreator, error)
+}
+
+// PluginSupport aggregates the support interfaces
+// needed for the operation of the plugin endorser
+type PluginSupport struct {
+	ChannelStateRetriever
+	identities.SigningIdentityFetcher
+	PluginMapper
+}
+
+// NewPluginEndorser creates a new PluginEndorser
+func NewPluginEndorser(ps *PluginSupport) *PluginEndorser {
+	return &PluginEndorser{
+		SigningIdentityFetcher: ps.SigningIdentityFetcher,
+		PluginMapper:           ps.PluginMapper,
+		pluginChannelMapping:   make(map[PluginName]*pluginsByChannel),
+		ChannelStateRetriever:  ps.ChannelStateRetriever,
+	}
+}
+
+// PluginEndorser endorses proposal responses using plugins
+type PluginEndorser struct {
+	sync.Mutex
+	PluginMapper
+	pluginChannelMapping map[PluginName]*pluginsByChannel
+	ChannelStateRetriever
+	identities.SigningIdentityFetcher
+}
+
+// EndorseWithPlugin endorses the response with a plugin
+func (pe *PluginEndorser) EndorseWithPlugin(ctx Context) (*pb.ProposalResponse, error) {
+	endorserLogger.Debug("Entering endorsement for", ctx)
+
+	if ctx.Response == nil {
+		return nil, errors.New("response is nil")
+	}
+
+	if ctx.Response.Status >= shim.ERRORTHRESHOLD {
+		return &pb.ProposalResponse{Response: ctx.Response}, nil
+	}
+
+	plugin, err := pe.getOrCreatePlugin(PluginName(ctx.PluginName), ctx.Channel)
+	if err != nil {
+		endorserLogger.Warning("Endorsement with plugin for", ctx, " failed:", err)
+		return nil, errors.Errorf("plugin with name %s could not be used: %v", ctx.PluginName, err)
+	}
+
+	prpBytes, err := proposalResponsePayloadFromContext(ctx)
+	if err != nil {
+		endorserLogger.Warning("Failed marshaling proposal response payload to bytes", err)
+		return nil, errors.WithMessage(err, "failed marshaling proposal response payload to bytes")
+	}
+
+	peerEndorsement, prpBytes, err := plugin.Endorse(prpBytes, ctx.SignedProposal)
+	if err != nil {
+		endorserLogger.Warning("Endorsement with plugin for", ctx, " failed:", err)
+		return nil, errors.WithStack(err)
+	}
+
+	resp := &pb.ProposalResponse{
+		Version:     1,
+		Endorsement: peerEndorsement,
+		Payload:     prpBytes,
+		Response:    &pb.Response{Status: 200, Message: "OK"},
+	}
+	endorserLogger.Debug("Exiting", ctx)
+	return resp, nil
+}
+
+// getOrCreatePlugin returns a plugin instance for the given plugin name and channel
+func (pe *PluginEndorser) getOrCreatePlugin(plugin PluginName, channel string) (endorsement.Plugin, error) {
+	pluginFactory := pe.PluginFactoryByName(plugin)
+	if pluginFactory == nil {
+		return nil, errors.Errorf("plugin with name %s wasn't found", plugin)
+	}
+
+	pluginsByChannel := pe.getOrCreatePluginChannelMapping(plugin, pluginFactory)
+	return pluginsByChannel.createPluginIfAbsent(channel)
+}
+
+func (pe *PluginEndorser) getOrCreatePluginChannelMapping(plugin PluginName, pf endorsement.PluginFactory) *pluginsByChannel {
+	pe.Lock()
+	defer pe.Unlock()
+	channelMapping, exists := pe.pluginChannelMapping[plugin]
+	if !exists {
+		channelMapping = &pluginsByChannel{
+			pluginFactory:    pf,
+			channels2Plugins: make(map[string]endorsement.Plugin),
+			pe:               pe,
+		}
+		pe.pluginChannelMapping[plugin] = channelMapping
+	}
+	return channelMapping
+}
+
+// pluginsByChannel holds the instances of an endorsement
+// plugin, one for every channel it's used in
+type pluginsByChannel struct {
+	sync.RWMutex
+	pluginFactory    endorsement.PluginFactory
+	channels2Plugins map[string]endorsement.Plugin
+	pe               *PluginEndorser
+}
+
+func (pbc *pluginsByChannel) createPluginIfAbsent(channel string) (endorsement.Plugin, error) {
+	pbc.RLock()
+	plugin, exists := pbc.channels2Plugins[channel]
+	pbc.RUnlock()
+	if exists {
+		return plugin, nil
+	}
+
+	pbc.Lock()
+	defer pbc.Unlock()
+	plugin, exists = pbc.channels2Plugins[channel]
+	if exists {
+		return plugin, nil
+	}
+
+	pluginInstance := pbc.pluginFactory.New()
+	plugin, err := pbc.initPlugin(pluginInstance, channel)
+	if err != nil {
+		return nil, err
+	}
+	pbc.channels2Plugins[channel] = plugin
+	return plugin, nil
+}
+
+func (pbc *pluginsByChannel) initPlugin(plugin endorsement.Plugin, channel string) (endorsement.Plugin, error) {
+	var dependencies []endorsement.Dependency
+	// If this is a channel endorsement, add the channel state as a dependency
+	if channel != "" {
+		query, err := pbc.pe.NewQueryCreator(channel)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed obtaining channel state")
+		}
+		dependencies = append(dependencies, &ChannelState{QueryCreator: query})
+	}
+	// Add the SigningIdentityFetcher as a dependency
+	dependencies = append(dependencies, pbc.pe.SigningIdentityFetcher)
+	if err := plugin.Init(dependencies...); err != nil {
+		return nil, err
+	}
+	return plugin, nil
+}
+
+// proposalResponsePayloadFromContext returns the bytes of the
+// proposal response payload that is to be endorsed
+func proposalResponsePayloadFromContext(ctx Context) ([]byte, error) {
+	hdr, err := putils.GetHeader(ctx.Proposal.Header)
+	if err != nil {
+		endorserLogger.Warning("Failed parsing header", err)
+		return nil, errors.Wrap(err, "failed parsing header")
+	}
+
+	pHashBytes, err := putils.GetProposalHash1(hdr, ctx.Proposal.Payload, ctx.Visibility)
+	if err != nil {
+		endorserLogger.Warning("Failed computing proposal hash", err)
+		return nil, errors.Wrap(err, "could not compute proposal hash")
+	}
+
+	prpBytes, err := putils.GetBytesProposalResponsePayload(pHashBytes, ctx.Response, ctx.SimRes, ctx.Event, ctx.ChaincodeID)
+	if err != nil {
+		endorserLogger.Warning("Failed marshaling the proposal response payload to bytes", err)
+		return nil, errors.New("failure while marshaling the ProposalResponsePayload")
+	}
+	return prpBytes, nil
+}
+
+// ChannelState defines state operations
+type ChannelState struct {
+	QueryCreator
+}
+
+// FetchState fetches state
+func (cs *ChannelState) FetchState() (state.State, error) {
+	qe, err := cs.NewQueryExecutor()
+	if err != nil {
+		return nil, err
+	}
+
+	return &StateContext{
+		QueryExecutor: qe,
+	}, nil
+}
+
+// StateContext defines an execution context that interacts with the state
+type StateContext struct {
+	ledger.QueryExecutor
+}
@@ -0,0 +1,217 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorser_test
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/endorser"
+	endorsement "github.com/hyperledger/fabric/core/handlers/endorsement/api"
+	"github.com/hyperledger/fabric/core/handlers/endorsement/api/identities"
+	"github.com/hyperledger/fabric/core/handlers/endorsement/api/state"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockPlugin struct {
+	mock.Mock
+}
+
+func (p *mockPlugin) Endorse(payload []byte, sp *pb.SignedProposal) (*pb.Endorsement, []byte, error) {
+	args := p.Called(payload, sp)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*pb.Endorsement), args.Get(1).([]byte), args.Error(2)
+}
+
+func (p *mockPlugin) Init(dependencies ...endorsement.Dependency) error {
+	args := p.Called(dependencies)
+	return args.Error(0)
+}
+
+type mockPluginFactory struct {
+	mock.Mock
+}
+
+func (f *mockPluginFactory) New() endorsement.Plugin {
+	return f.Called().Get(0).(endorsement.Plugin)
+}
+
+type mockChannelStateRetriever struct {
+	mock.Mock
+}
+
+func (r *mockChannelStateRetriever) NewQueryCreator(channel string) (endorser.QueryCreator, error) {
+	args := r.Called(channel)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(endorser.QueryCreator), args.Error(1)
+}
+
+type mockQueryCreator struct {
+	mock.Mock
+}
+
+func (qc *mockQueryCreator) NewQueryExecutor() (ledger.QueryExecutor, error) {
+	args := qc.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(ledger.QueryExecutor), args.Error(1)
+}
+
+type mockSigningIdentityFetcher struct{}
+
+func (*mockSigningIdentityFetcher) SigningIdentityForRequest(*pb.SignedProposal) (identities.SigningIdentity, error) {
+	return nil, errors.New("not implemented")
+}
+
+func testEndorsementContext(t *testing.T) endorser.Context {
+	prop, _, err := utils.CreateChaincodeProposal(common.HeaderType_ENDORSER_TRANSACTION, "mychannel",
+		&pb.ChaincodeInvocationSpec{ChaincodeSpec: &pb.ChaincodeSpec{ChaincodeId: &pb.ChaincodeID{Name: "mycc"}}}, []byte("creator"))
+	assert.NoError(t, err)
+	return endorser.Context{
+		PluginName:     "plugin",
+		Channel:        "mychannel",
+		TxID:           "txid",
+		ChaincodeID:    &pb.ChaincodeID{Name: "mycc", Version: "1.0"},
+		Proposal:       prop,
+		SignedProposal: &pb.SignedProposal{},
+		Response:       &pb.Response{Status: 200, Payload: []byte("payload")},
+		SimRes:         []byte("simulation results"),
+	}
+}
+
+func TestPluginEndorserNotFound(t *testing.T) {
+	pe := endorser.NewPluginEndorser(&endorser.PluginSupport{
+		PluginMapper: endorser.MapBasedPluginMapper{},
+	})
+	resp, err := pe.EndorseWithPlugin(testEndorsementContext(t))
+	assert.Nil(t, resp)
+	assert.Contains(t, err.Error(), "plugin with name plugin wasn't found")
+}
+
+func TestPluginEndorserChaincodeError(t *testing.T) {
+	pe := endorser.NewPluginEndorser(&endorser.PluginSupport{
+		PluginMapper: endorser.MapBasedPluginMapper{},
+	})
+	ctx := testEndorsementContext(t)
+	ctx.Response = &pb.Response{Status: 500, Message: "chaincode failed"}
+	resp, err := pe.EndorseWithPlugin(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, ctx.Response, resp.Response)
+	assert.Nil(t, resp.Endorsement)
+}
+
+func TestPluginEndorserGreenPath(t *testing.T) {
+	plugin := &mockPlugin{}
+	factory := &mockPluginFactory{}
+	factory.On("New").Return(plugin).Once()
+	qc := &mockQueryCreator{}
+	csr := &mockChannelStateRetriever{}
+	csr.On("NewQueryCreator", "mychannel").Return(qc, nil)
+	sif := &mockSigningIdentityFetcher{}
+
+	pe := endorser.NewPluginEndorser(&endorser.PluginSupport{
+		ChannelStateRetriever:  csr,
+		SigningIdentityFetcher: sif,
+		PluginMapper:           endorser.MapBasedPluginMapper{"plugin": factory},
+	})
+
+	ctx := testEndorsementContext(t)
+	plugin.On("Init", mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		deps := args.Get(0).([]endorsement.Dependency)
+		assert.Len(t, deps, 2)
+		_, isStateFetcher := deps[0].(state.StateFetcher)
+		assert.True(t, isStateFetcher)
+		assert.Equal(t, sif, deps[1])
+	})
+	endorsementResult := &pb.Endorsement{Signature: []byte("signature"), Endorser: []byte("endorser")}
+	plugin.On("Endorse", mock.Anything, ctx.SignedProposal).Return(endorsementResult, []byte("endorsed payload"), nil)
+
+	resp, err := pe.EndorseWithPlugin(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, endorsementResult, resp.Endorsement)
+	assert.Equal(t, []byte("endorsed payload"), resp.Payload)
+	assert.Equal(t, int32(200), resp.Response.Status)
+
+	// the payload passed to the plugin is the proposal response payload of the context
+	prp, err := utils.GetProposalResponsePayload(plugin.Calls[1].Arguments.Get(0).([]byte))
+	assert.NoError(t, err)
+	action, err := utils.GetChaincodeAction(prp.Extension)
+	assert.NoError(t, err)
+	assert.Equal(t, ctx.SimRes, action.Results)
+	assert.Equal(t, ctx.Response, action.Response)
+
+	// the plugin instance is reused for subsequent endorsements in the channel
+	_, err = pe.EndorseWithPlugin(ctx)
+	assert.NoError(t, err)
+	factory.AssertNumberOfCalls(t, "New", 1)
+	plugin.AssertNumberOfCalls(t, "Init", 1)
+	plugin.AssertNumberOfCalls(t, "Endorse", 2)
+}
+
+func TestPluginEndorserFailures(t *testing.T) {
+	t.Run("channel state unavailable", func(t *testing.T) {
+		factory := &mockPluginFactory{}
+		factory.On("New").Return(&mockPlugin{})
+		csr := &mockChannelStateRetriever{}
+		csr.On("NewQueryCreator", "mychannel").Return(nil, errors.New("channel does not exist"))
+		pe := endorser.NewPluginEndorser(&endorser.PluginSupport{
+			ChannelStateRetriever: csr,
+			PluginMapper:          endorser.MapBasedPluginMapper{"plugin": factory},
+		})
+		_, err := pe.EndorseWithPlugin(testEndorsementContext(t))
+		assert.Contains(t, err.Error(), "channel does not exist")
+	})
+
+	t.Run("plugin initialization fails", func(t *testing.T) {
+		plugin := &mockPlugin{}
+		plugin.On("Init", mock.Anything).Return(errors.New("missing dependency"))
+		factory := &mockPluginFactory{}
+		factory.On("New").Return(plugin)
+		csr := &mockChannelStateRetriever{}
+		csr.On("NewQueryCreator", "mychannel").Return(&mockQueryCreator{}, nil)
+		pe := endorser.NewPluginEndorser(&endorser.PluginSupport{
+			ChannelStateRetriever: csr,
+			PluginMapper:          endorser.MapBasedPluginMapper{"plugin": factory},
+		})
+		_, err := pe.EndorseWithPlugin(testEndorsementContext(t))
+		assert.Contains(t, err.Error(), "missing dependency")
+	})
+
+	t.Run("endorsement fails", func(t *testing.T) {
+		plugin := &mockPlugin{}
+		plugin.On("Init", mock.Anything).Return(nil)
+		plugin.On("Endorse", mock.Anything, mock.Anything).Return(nil, nil, errors.New("signing failed"))
+		factory := &mockPluginFactory{}
+		factory.On("New").Return(plugin)
+		csr := &mockChannelStateRetriever{}
+		csr.On("NewQueryCreator", "mychannel").Return(&mockQueryCreator{}, nil)
+		pe := endorser.NewPluginEndorser(&endorser.PluginSupport{
+			ChannelStateRetriever: csr,
+			PluginMapper:          endorser.MapBasedPluginMapper{"plugin": factory},
+		})
+		_, err := pe.EndorseWithPlugin(testEndorsementContext(t))
+		assert.Contains(t, err.Error(), "signing failed")
+	})
+
+	t.Run("nil response", func(t *testing.T) {
+		pe := endorser.NewPluginEndorser(&endorser.PluginSupport{})
+		ctx := testEndorsementContext(t)
+		ctx.Response = nil
+		_, err := pe.EndorseWithPlugin(ctx)
+		assert.EqualError(t, err, "response is nil")
+	})
+}
@@ -14,10 +14,12 @@ import (
 	"github.com/hyperledger/fabric/core/chaincode"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/handlers/decoration"
+	"github.com/hyperledger/fabric/core/handlers/endorsement/api/identities"
 	"github.com/hyperledger/fabric/core/handlers/library"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/peer"
 	"github.com/hyperledger/fabric/core/scc"
+	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/protos/common"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/pkg/errors"
@@ -26,7 +28,28 @@ import (
 
 // SupportImpl provides an implementation of the endorser.Support interface
 // issuing calls to various static methods of the peer
-type SupportImpl struct{}
+type SupportImpl struct {
+	*PluginEndorser
+}
+
+// NewQueryCreator returns a QueryCreator for the ledger of the given channel
+func (s *SupportImpl) NewQueryCreator(channel string) (QueryCreator, error) {
+	lgr := peer.GetLedger(channel)
+	if lgr == nil {
+		return nil, errors.Errorf("channel does not exist: %s", channel)
+	}
+	return lgr, nil
+}
+
+// SigningIdentityForRequest returns the signing identity of the peer,
+// which is used to endorse all the proposals
+func (s *SupportImpl) SigningIdentityForRequest(*pb.SignedProposal) (identities.SigningIdentity, error) {
+	localMSP := mspmgmt.GetLocalMSP()
+	if localMSP == nil {
+		return nil, errors.New("nil local MSP manager")
+	}
+	return localMSP.GetDefaultSigningIdentity()
+}
 
 // IsSysCCAndNotInvokableExternal returns true if the supplied chaincode is
 // ia system chaincode and it NOT invokable
@@ -134,9 +157,14 @@ func (s *SupportImpl) IsJavaCC(buf []byte) (bool, error) {
 }
 
 // CheckInstantiationPolicy returns an error if the instantiation in the supplied
-// ChaincodeDefinition differs from the instantiation policy stored on the ledger
+// ChaincodeDefinition differs from the instantiation policy stored on the ledger.
+// Chaincodes defined through the lifecycle system chaincode have no instantiation policy.
 func (s *SupportImpl) CheckInstantiationPolicy(name, version string, cd resourcesconfig.ChaincodeDefinition) error {
-	return ccprovider.CheckInstantiationPolicy(name, version, cd.(*ccprovider.ChaincodeData))
+	lsccData, ok := cd.(*ccprovider.ChaincodeData)
+	if !ok {
+		return nil
+	}
+	return ccprovider.CheckInstantiationPolicy(name, version, lsccData)
 }
 
 // GetApplicationConfig returns the configtxapplication.SharedConfig for the channel
@@ -0,0 +1,77 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package builtin
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric/core/handlers/endorsement/api/identities"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSigningIdentity struct {
+	serializeErr error
+	signErr      error
+}
+
+func (id *mockSigningIdentity) Serialize() ([]byte, error) {
+	return []byte("identity"), id.serializeErr
+}
+
+func (id *mockSigningIdentity) Sign(msg []byte) ([]byte, error) {
+	if id.signErr != nil {
+		return nil, id.signErr
+	}
+	return append([]byte("signature over "), msg...), nil
+}
+
+type mockSigningIdentityFetcher struct {
+	identity *mockSigningIdentity
+	err      error
+}
+
+func (f *mockSigningIdentityFetcher) SigningIdentityForRequest(*peer.SignedProposal) (identities.SigningIdentity, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return f.identity, nil
+}
+
+func TestDefaultEndorsementInit(t *testing.T) {
+	plugin := (&DefaultEndorsementFactory{}).New()
+	err := plugin.Init()
+	assert.EqualError(t, err, "could not find SigningIdentityFetcher in dependencies")
+
+	err = plugin.Init(&struct{}{}, &mockSigningIdentityFetcher{})
+	assert.NoError(t, err)
+}
+
+func TestDefaultEndorsement(t *testing.T) {
+	sif := &mockSigningIdentityFetcher{identity: &mockSigningIdentity{}}
+	plugin := (&DefaultEndorsementFactory{}).New()
+	assert.NoError(t, plugin.Init(sif))
+
+	endorsement, payload, err := plugin.Endorse([]byte("payload"), &peer.SignedProposal{})
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("payload"), payload)
+	assert.Equal(t, []byte("identity"), endorsement.Endorser)
+	assert.Equal(t, []byte("signature over payloadidentity"), endorsement.Signature)
+
+	sif.identity.signErr = errors.New("no key")
+	_, _, err = plugin.Endorse([]byte("payload"), &peer.SignedProposal{})
+	assert.Contains(t, err.Error(), "could not sign the proposal response payload")
+
+	sif.identity.serializeErr = errors.New("bad identity")
+	_, _, err = plugin.Endorse([]byte("payload"), &peer.SignedProposal{})
+	assert.Contains(t, err.Error(), "could not serialize the signing identity")
+
+	sif.err = errors.New("no identity")
+	_, _, err = plugin.Endorse([]byte("payload"), &peer.SignedProposal{})
+	assert.Contains(t, err.Error(), "failed fetching signing identity")
+}
@@ -0,0 +1,20 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	endorsement "github.com/hyperledger/fabric/core/handlers/endorsement/api"
+	"github.com/hyperledger/fabric/core/handlers/endorsement/builtin"
+)
+
+// NewPluginFactory is the function ran by the plugin infrastructure to create an endorsement plugin factory.
+func NewPluginFactory() endorsement.PluginFactory {
+	return &builtin.DefaultEndorsementFactory{}
+}
+
+func main() {
+}
@@ -11,6 +11,10 @@ import (
 	"github.com/hyperledger/fabric/core/handlers/auth/filter"
 	"github.com/hyperledger/fabric/core/handlers/decoration"
 	"github.com/hyperledger/fabric/core/handlers/decoration/decorator"
+	endorsement "github.com/hyperledger/fabric/core/handlers/endorsement/api"
+	endorsementbuiltin "github.com/hyperledger/fabric/core/handlers/endorsement/builtin"
+	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
+	validationbuiltin "github.com/hyperledger/fabric/core/handlers/validation/builtin"
 )
 
 // HandlerLibrary is used to assert
@@ -39,3 +43,17 @@ func (r *HandlerLibrary) ExpirationCheck() auth.Filter {
 func (r *HandlerLibrary) DefaultDecorator() decoration.Decorator {
 	return decorator.NewDecorator()
 }
+
+// DefaultEndorsement creates a factory of endorsement plugins
+// that endorse proposal responses the way the default
+// endorsement system chaincode does
+func (r *HandlerLibrary) DefaultEndorsement() endorsement.PluginFactory {
+	return &endorsementbuiltin.DefaultEndorsementFactory{}
+}
+
+// DefaultValidation creates a factory of validation plugins
+// that validate transactions the way the default validation
+// system chaincode does
+func (r *HandlerLibrary) DefaultValidation() validation.PluginFactory {
+	return &validationbuiltin.DefaultValidationFactory{}
+}
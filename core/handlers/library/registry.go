@@ -15,6 +15,8 @@ import (
 
 	"github.com/hyperledger/fabric/core/handlers/auth"
 	"github.com/hyperledger/fabric/core/handlers/decoration"
+	endorsement "github.com/hyperledger/fabric/core/handlers/endorsement/api"
+	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
 )
 
 // Registry defines an object that looks up
@@ -35,14 +37,29 @@ const (
 	// Decoration handler - append or mutate the chaincode input
 	// passed to the chaincode
 	Decoration
+	// Endorsement handler - endorse the proposal responses
+	// of the chaincodes that select it
+	Endorsement
+	// Validation handler - validate the transactions of
+	// the chaincodes that select it
+	Validation
+
+	// DefaultEndorsementPlugin and DefaultValidationPlugin are the names
+	// under which the built-in endorsement and validation plugins can
+	// always be selected, in addition to the names they are mapped to
+	DefaultEndorsementPlugin = "DefaultEndorsement"
+	DefaultValidationPlugin  = "DefaultValidation"
 
 	authPluginFactory      = "NewFilter"
 	decoratorPluginFactory = "NewDecorator"
+	pluginFactory          = "NewPluginFactory"
 )
 
 type registry struct {
 	filters    []auth.Filter
 	decorators []decoration.Decorator
+	endorsers  map[string]endorsement.PluginFactory
+	validators map[string]validation.PluginFactory
 }
 
 var once sync.Once
@@ -53,8 +70,14 @@ var reg registry
 type Config struct {
 	AuthFilters []*HandlerConfig `mapstructure:"authFilters" yaml:"authFilters"`
 	Decorators  []*HandlerConfig `mapstructure:"decorators" yaml:"decorators"`
+	Endorsers   PluginMapping    `mapstructure:"endorsers" yaml:"endorsers"`
+	Validators  PluginMapping    `mapstructure:"validators" yaml:"validators"`
 }
 
+// PluginMapping maps the names under which the chaincodes
+// select endorsement or validation handlers to their configuration
+type PluginMapping map[string]*HandlerConfig
+
 // HandlerConfig defines configuration for a plugin or compiled handler
 type HandlerConfig struct {
 	Name    string `mapstructure:"name" yaml:"name"`
@@ -65,7 +88,10 @@ type HandlerConfig struct {
 // of the registry
 func InitRegistry(c Config) Registry {
 	once.Do(func() {
-		reg = registry{}
+		reg = registry{
+			endorsers:  make(map[string]endorsement.PluginFactory),
+			validators: make(map[string]validation.PluginFactory),
+		}
 		reg.loadHandlers(c)
 	})
 	return &reg
@@ -79,19 +105,33 @@ func (r *registry) loadHandlers(c Config) {
 	for _, config := range c.Decorators {
 		r.evaluateModeAndLoad(config, Decoration)
 	}
+	for chaincodeID, config := range c.Endorsers {
+		r.evaluateModeAndLoad(config, Endorsement, chaincodeID)
+	}
+	for chaincodeID, config := range c.Validators {
+		r.evaluateModeAndLoad(config, Validation, chaincodeID)
+	}
+	if _, exists := r.endorsers[DefaultEndorsementPlugin]; !exists {
+		r.loadCompiled(DefaultEndorsementPlugin, Endorsement, DefaultEndorsementPlugin)
+	}
+	if _, exists := r.validators[DefaultValidationPlugin]; !exists {
+		r.loadCompiled(DefaultValidationPlugin, Validation, DefaultValidationPlugin)
+	}
 }
 
-// evaluateModeAndLoad if a library path is provided, load the shared object
-func (r *registry) evaluateModeAndLoad(c *HandlerConfig, handlerType HandlerType) {
+// evaluateModeAndLoad if a library path is provided, load the shared object.
+// Endorsement and validation handlers are registered under the name given
+// in extraArgs, which is the one the chaincodes select them with
+func (r *registry) evaluateModeAndLoad(c *HandlerConfig, handlerType HandlerType, extraArgs ...string) {
 	if c.Library != "" {
-		r.loadPlugin(c.Library, handlerType)
+		r.loadPlugin(c.Library, handlerType, extraArgs...)
 	} else {
-		r.loadCompiled(c.Name, handlerType)
+		r.loadCompiled(c.Name, handlerType, extraArgs...)
 	}
 }
 
 // loadCompiled loads a statically compiled handler
-func (r *registry) loadCompiled(handlerFactory string, handlerType HandlerType) {
+func (r *registry) loadCompiled(handlerFactory string, handlerType HandlerType, extraArgs ...string) {
 	registryMD := reflect.ValueOf(&HandlerLibrary{})
 
 	o := registryMD.MethodByName(handlerFactory)
@@ -105,11 +145,21 @@ func (r *registry) loadCompiled(handlerFactory string, handlerType HandlerType)
 		r.filters = append(r.filters, inst.(auth.Filter))
 	} else if handlerType == Decoration {
 		r.decorators = append(r.decorators, inst.(decoration.Decorator))
+	} else if handlerType == Endorsement {
+		if len(extraArgs) != 1 {
+			panic(fmt.Errorf("expected 1 argument in extraArgs"))
+		}
+		r.endorsers[extraArgs[0]] = inst.(endorsement.PluginFactory)
+	} else if handlerType == Validation {
+		if len(extraArgs) != 1 {
+			panic(fmt.Errorf("expected 1 argument in extraArgs"))
+		}
+		r.validators[extraArgs[0]] = inst.(validation.PluginFactory)
 	}
 }
 
 // loadPlugin loads a pluggagle handler
-func (r *registry) loadPlugin(pluginPath string, handlerType HandlerType) {
+func (r *registry) loadPlugin(pluginPath string, handlerType HandlerType, extraArgs ...string) {
 	if _, err := os.Stat(pluginPath); err != nil {
 		panic(fmt.Errorf("Could not find plugin at path %s: %s", pluginPath, err))
 	}
@@ -123,6 +173,10 @@ func (r *registry) loadPlugin(pluginPath string, handlerType HandlerType) {
 		r.initAuthPlugin(p)
 	} else if handlerType == Decoration {
 		r.initDecoratorPlugin(p)
+	} else if handlerType == Endorsement {
+		r.initEndorsementPlugin(p, extraArgs...)
+	} else if handlerType == Validation {
+		r.initValidationPlugin(p, extraArgs...)
 	}
 }
 
@@ -159,6 +213,48 @@ func (r *registry) initDecoratorPlugin(p *plugin.Plugin) {
 	}
 }
 
+// initEndorsementPlugin constructs an endorsement plugin factory from the given plugin
+func (r *registry) initEndorsementPlugin(p *plugin.Plugin, extraArgs ...string) {
+	if len(extraArgs) != 1 {
+		panic(fmt.Errorf("expected 1 argument in extraArgs"))
+	}
+	factorySymbol, err := p.Lookup(pluginFactory)
+	if err != nil {
+		panicWithLookupError(pluginFactory, err)
+	}
+
+	constructor, ok := factorySymbol.(func() endorsement.PluginFactory)
+	if !ok {
+		panicWithDefinitionError(pluginFactory)
+	}
+	factory := constructor()
+	if factory == nil {
+		panic(fmt.Errorf("factory instance returned nil"))
+	}
+	r.endorsers[extraArgs[0]] = factory
+}
+
+// initValidationPlugin constructs a validation plugin factory from the given plugin
+func (r *registry) initValidationPlugin(p *plugin.Plugin, extraArgs ...string) {
+	if len(extraArgs) != 1 {
+		panic(fmt.Errorf("expected 1 argument in extraArgs"))
+	}
+	factorySymbol, err := p.Lookup(pluginFactory)
+	if err != nil {
+		panicWithLookupError(pluginFactory, err)
+	}
+
+	constructor, ok := factorySymbol.(func() validation.PluginFactory)
+	if !ok {
+		panicWithDefinitionError(pluginFactory)
+	}
+	factory := constructor()
+	if factory == nil {
+		panic(fmt.Errorf("factory instance returned nil"))
+	}
+	r.validators[extraArgs[0]] = factory
+}
+
 // panicWithLookupError panics when a handler constructor lookup fails
 func panicWithLookupError(factory string, err error) {
 	panic(fmt.Errorf("Filter must contain constructor with name %s. Error from lookup: %s",
@@ -169,7 +265,7 @@ func panicWithLookupError(factory string, err error) {
 // the expected function definition
 func panicWithDefinitionError(factory string) {
 	panic(fmt.Errorf("Constructor method %s does not match expected definition",
-		authPluginFactory))
+		factory))
 }
 
 // Lookup returns a list of handlers with the given
@@ -179,6 +275,10 @@ func (r *registry) Lookup(handlerType HandlerType) interface{} {
 		return r.filters
 	} else if handlerType == Decoration {
 		return r.decorators
+	} else if handlerType == Endorsement {
+		return r.endorsers
+	} else if handlerType == Validation {
+		return r.validators
 	}
 
 	return nil
@@ -19,6 +19,8 @@ import (
 	"golang.org/x/net/context"
 
 	"github.com/golang/protobuf/proto"
+	endorsement "github.com/hyperledger/fabric/core/handlers/endorsement/api"
+	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
 	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/stretchr/testify/assert"
 )
@@ -26,6 +28,8 @@ import (
 const (
 	authPluginPackage      = "github.com/hyperledger/fabric/core/handlers/auth/plugin"
 	decoratorPluginPackage = "github.com/hyperledger/fabric/core/handlers/decoration/plugin"
+	endorsementTestPlugin  = "github.com/hyperledger/fabric/core/handlers/endorsement/plugin"
+	validationTestPlugin   = "github.com/hyperledger/fabric/core/handlers/validation/plugin"
 )
 
 func TestLoadAuthPlugin(t *testing.T) {
@@ -72,6 +76,48 @@ func TestLoadDecoratorPlugin(t *testing.T) {
 	assert.True(t, proto.Equal(decoratedInput, testInput), "Expected chaincode input to remain unchanged")
 }
 
+func TestEndorsementPlugin(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "")
+	assert.NoError(t, err, "Could not create temp directory for plugins")
+	defer os.Remove(testDir)
+	pluginPath := strings.Join([]string{testDir, "/", "endorsementplugin.so"}, "")
+
+	cmd := exec.Command("go", "build", "-o", pluginPath, "-buildmode=plugin",
+		endorsementTestPlugin)
+	output, err := cmd.CombinedOutput()
+	assert.NoError(t, err, "Could not build plugin: "+string(output))
+
+	testReg := registry{endorsers: make(map[string]endorsement.PluginFactory)}
+	testReg.loadPlugin(pluginPath, Endorsement, "escc")
+	mapping := testReg.Lookup(Endorsement).(map[string]endorsement.PluginFactory)
+	factory := mapping["escc"]
+	assert.NotNil(t, factory)
+	instance := factory.New()
+	assert.NotNil(t, instance)
+	assert.Error(t, instance.Init())
+}
+
+func TestValidationPlugin(t *testing.T) {
+	testDir, err := ioutil.TempDir("", "")
+	assert.NoError(t, err, "Could not create temp directory for plugins")
+	defer os.Remove(testDir)
+	pluginPath := strings.Join([]string{testDir, "/", "validationplugin.so"}, "")
+
+	cmd := exec.Command("go", "build", "-o", pluginPath, "-buildmode=plugin",
+		validationTestPlugin)
+	output, err := cmd.CombinedOutput()
+	assert.NoError(t, err, "Could not build plugin: "+string(output))
+
+	testReg := registry{validators: make(map[string]validation.PluginFactory)}
+	testReg.loadPlugin(pluginPath, Validation, "vscc")
+	mapping := testReg.Lookup(Validation).(map[string]validation.PluginFactory)
+	factory := mapping["vscc"]
+	assert.NotNil(t, factory)
+	instance := factory.New()
+	assert.NotNil(t, instance)
+	assert.Error(t, instance.Init())
+}
+
 func TestLoadPluginInvalidPath(t *testing.T) {
 	defer func() {
 		if r := recover(); r == nil {
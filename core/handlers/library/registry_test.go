@@ -11,6 +11,8 @@ import (
 
 	"github.com/hyperledger/fabric/core/handlers/auth"
 	"github.com/hyperledger/fabric/core/handlers/decoration"
+	endorsement "github.com/hyperledger/fabric/core/handlers/endorsement/api"
+	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -18,6 +20,8 @@ func TestInitRegistry(t *testing.T) {
 	r := InitRegistry(Config{
 		AuthFilters: []*HandlerConfig{{Name: "DefaultAuth"}},
 		Decorators:  []*HandlerConfig{{Name: "DefaultDecorator"}},
+		Endorsers:   PluginMapping{"escc": &HandlerConfig{Name: "DefaultEndorsement"}},
+		Validators:  PluginMapping{"vscc": &HandlerConfig{Name: "DefaultValidation"}},
 	})
 	assert.NotNil(t, r)
 	authHandlers := r.Lookup(Auth)
@@ -31,6 +35,22 @@ func TestInitRegistry(t *testing.T) {
 	decorators, isDecorators := decorationHandlers.([]decoration.Decorator)
 	assert.True(t, isDecorators)
 	assert.Len(t, decorators, 1)
+
+	endorsementHandlers := r.Lookup(Endorsement)
+	assert.NotNil(t, endorsementHandlers)
+	endorsers, isEndorsers := endorsementHandlers.(map[string]endorsement.PluginFactory)
+	assert.True(t, isEndorsers)
+	assert.Len(t, endorsers, 2)
+	assert.NotNil(t, endorsers["escc"])
+	assert.NotNil(t, endorsers[DefaultEndorsementPlugin], "The built-in plugin should be selectable by its name")
+
+	validationHandlers := r.Lookup(Validation)
+	assert.NotNil(t, validationHandlers)
+	validators, isValidators := validationHandlers.(map[string]validation.PluginFactory)
+	assert.True(t, isValidators)
+	assert.Len(t, validators, 2)
+	assert.NotNil(t, validators["vscc"])
+	assert.NotNil(t, validators[DefaultValidationPlugin], "The built-in plugin should be selectable by its name")
 }
 
 func TestLoadCompiledInvalid(t *testing.T) {
@@ -43,3 +63,13 @@ func TestLoadCompiledInvalid(t *testing.T) {
 	testReg := registry{}
 	testReg.loadCompiled("InvalidFactory", Auth)
 }
+
+func TestLoadCompiledMissingPluginName(t *testing.T) {
+	testReg := registry{endorsers: make(map[string]endorsement.PluginFactory)}
+	assert.Panics(t, func() {
+		testReg.loadCompiled("DefaultEndorsement", Endorsement)
+	})
+
+	testReg.loadCompiled("DefaultEndorsement", Endorsement, "escc")
+	assert.NotNil(t, testReg.endorsers["escc"])
+}
@@ -0,0 +1,24 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package capabilities
+
+import (
+	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
+)
+
+// Capabilities defines the capabilities of the channel
+// that are relevant for the validation of transactions
+type Capabilities interface {
+	validation.Dependency
+
+	// PrivateChannelData returns true if support for private channel data (a.k.a. collections) is enabled.
+	PrivateChannelData() bool
+
+	// V1_1Validation returns true if this channel is configured to perform stricter validation
+	// of transactions (as introduced in v1.1).
+	V1_1Validation() bool
+}
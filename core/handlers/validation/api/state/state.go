@@ -0,0 +1,31 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package state
+
+import (
+	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
+)
+
+// State defines interaction with the world state
+type State interface {
+	// GetStateMultipleKeys gets the values for multiple keys in a single call
+	GetStateMultipleKeys(namespace string, keys []string) ([][]byte, error)
+
+	// GetStateMetadata returns the metadata of the given key in the given namespace
+	GetStateMetadata(namespace, key string) (map[string][]byte, error)
+
+	// Done releases resources occupied by the State
+	Done()
+}
+
+// StateFetcher retrieves an instance of a state
+type StateFetcher interface {
+	validation.Dependency
+
+	// FetchState fetches state
+	FetchState() (State, error)
+}
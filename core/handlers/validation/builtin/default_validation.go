@@ -0,0 +1,137 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package builtin
+
+import (
+	"github.com/hyperledger/fabric/common/flogging"
+	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
+	"github.com/hyperledger/fabric/core/handlers/validation/api/capabilities"
+	"github.com/hyperledger/fabric/core/handlers/validation/api/policies"
+	"github.com/hyperledger/fabric/core/handlers/validation/api/state"
+	"github.com/hyperledger/fabric/core/scc/vscc"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("defaultValidation")
+
+// DefaultValidationFactory creates DefaultValidation plugins
+type DefaultValidationFactory struct {
+}
+
+// New returns a new DefaultValidation plugin
+func (*DefaultValidationFactory) New() validation.Plugin {
+	return &DefaultValidation{}
+}
+
+// DefaultValidation validates transactions the way the
+// default validation system chaincode does
+type DefaultValidation struct {
+	validator *vscc.ValidatorOneValidSignature
+}
+
+// Validate validates the transaction at the given position in the block
+// against the serialized policy passed in the context data
+func (v *DefaultValidation) Validate(block *common.Block, namespace string, txPosition int, actionPosition int, contextData ...validation.ContextDatum) error {
+	if len(contextData) == 0 {
+		return errors.New("expected to receive policy bytes in context data")
+	}
+
+	serializedPolicy, isSerializedPolicy := contextData[0].(policies.SerializedPolicy)
+	if !isSerializedPolicy {
+		return errors.New("expected to receive a serialized policy in the first context data")
+	}
+	if block == nil || block.Data == nil {
+		return errors.New("empty block")
+	}
+	if txPosition >= len(block.Data.Data) {
+		return errors.Errorf("block has only %d transactions, but requested tx at position %d", len(block.Data.Data), txPosition)
+	}
+	if block.Header == nil {
+		return errors.Errorf("no block header")
+	}
+
+	err := v.validator.Validate(block.Data.Data[txPosition], serializedPolicy.Bytes())
+	if err != nil {
+		logger.Debugf("block %d, namespace: %s, tx %d validation results is: %v", block.Header.Number, namespace, txPosition, err)
+		return err
+	}
+
+	logger.Debugf("block %d, namespace: %s, tx %d validation results is: valid", block.Header.Number, namespace, txPosition)
+	return nil
+}
+
+// Init injects the state fetcher, the policy evaluator and
+// the capabilities of the channel into the plugin
+func (v *DefaultValidation) Init(dependencies ...validation.Dependency) error {
+	var (
+		sf   state.StateFetcher
+		pe   policies.PolicyEvaluator
+		caps capabilities.Capabilities
+	)
+	for _, dep := range dependencies {
+		if stateFetcher, isStateFetcher := dep.(state.StateFetcher); isStateFetcher {
+			sf = stateFetcher
+		}
+		if policyEvaluator, isPolicyFetcher := dep.(policies.PolicyEvaluator); isPolicyFetcher {
+			pe = policyEvaluator
+		}
+		if c, isCapabilities := dep.(capabilities.Capabilities); isCapabilities {
+			caps = c
+		}
+	}
+	if sf == nil {
+		return errors.New("stateFetcher not passed in init")
+	}
+	if pe == nil {
+		return errors.New("policy fetcher not passed in init")
+	}
+	if caps == nil {
+		return errors.New("capabilities not passed in init")
+	}
+
+	v.validator = vscc.New(&pluginSupport{
+		StateFetcher:    sf,
+		PolicyEvaluator: pe,
+		capabilities:    caps,
+	})
+	return nil
+}
+
+// pluginSupport implements vscc.Support on top of the dependencies
+// of the plugin; as a plugin instance is bound to a single channel,
+// the channel passed by the validation logic is not needed
+type pluginSupport struct {
+	state.StateFetcher
+	policies.PolicyEvaluator
+	capabilities capabilities.Capabilities
+}
+
+func (s *pluginSupport) GetState(chainID, namespace, key string) ([]byte, error) {
+	st, err := s.FetchState()
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not fetch state")
+	}
+	defer st.Done()
+
+	values, err := st.GetStateMultipleKeys(namespace, []string{key})
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, nil
+	}
+	return values[0], nil
+}
+
+func (s *pluginSupport) EvaluatePolicy(chainID string, policyBytes []byte, signatureSet []*common.SignedData) error {
+	return s.Evaluate(policyBytes, signatureSet)
+}
+
+func (s *pluginSupport) Capabilities(chainID string) (capabilities.Capabilities, bool) {
+	return s.capabilities, true
+}
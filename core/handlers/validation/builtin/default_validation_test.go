@@ -0,0 +1,154 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package builtin
+
+import (
+	"errors"
+	"testing"
+
+	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
+	"github.com/hyperledger/fabric/core/handlers/validation/api/state"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockState struct {
+	values map[string][]byte
+	done   bool
+}
+
+func (s *mockState) GetStateMultipleKeys(namespace string, keys []string) ([][]byte, error) {
+	var values [][]byte
+	for _, key := range keys {
+		values = append(values, s.values[namespace+"/"+key])
+	}
+	return values, nil
+}
+
+func (s *mockState) GetStateMetadata(namespace, key string) (map[string][]byte, error) {
+	return nil, nil
+}
+
+func (s *mockState) Done() {
+	s.done = true
+}
+
+type mockStateFetcher struct {
+	state *mockState
+	err   error
+}
+
+func (sf *mockStateFetcher) FetchState() (state.State, error) {
+	if sf.err != nil {
+		return nil, sf.err
+	}
+	return sf.state, nil
+}
+
+type mockPolicyEvaluator struct {
+	err error
+}
+
+func (pe *mockPolicyEvaluator) Evaluate(policyBytes []byte, signatureSet []*common.SignedData) error {
+	return pe.err
+}
+
+type mockCapabilities struct{}
+
+func (*mockCapabilities) PrivateChannelData() bool {
+	return true
+}
+
+func (*mockCapabilities) V1_1Validation() bool {
+	return true
+}
+
+type serializedPolicy []byte
+
+func (sp serializedPolicy) Bytes() []byte {
+	return sp
+}
+
+func TestDefaultValidationInit(t *testing.T) {
+	sf := &mockStateFetcher{}
+	pe := &mockPolicyEvaluator{}
+	caps := &mockCapabilities{}
+
+	for _, testCase := range []struct {
+		name         string
+		dependencies []validation.Dependency
+		expectedErr  string
+	}{
+		{name: "no state fetcher", dependencies: []validation.Dependency{pe, caps}, expectedErr: "stateFetcher not passed in init"},
+		{name: "no policy evaluator", dependencies: []validation.Dependency{sf, caps}, expectedErr: "policy fetcher not passed in init"},
+		{name: "no capabilities", dependencies: []validation.Dependency{sf, pe}, expectedErr: "capabilities not passed in init"},
+		{name: "all dependencies", dependencies: []validation.Dependency{sf, pe, caps}},
+	} {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := (&DefaultValidationFactory{}).New().Init(testCase.dependencies...)
+			if testCase.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, testCase.expectedErr)
+		})
+	}
+}
+
+func TestDefaultValidationBadInput(t *testing.T) {
+	plugin := (&DefaultValidationFactory{}).New()
+	assert.NoError(t, plugin.Init(&mockStateFetcher{}, &mockPolicyEvaluator{}, &mockCapabilities{}))
+
+	block := &common.Block{Data: &common.BlockData{Data: [][]byte{[]byte("tx")}}, Header: &common.BlockHeader{}}
+
+	err := plugin.Validate(block, "mycc", 0, 0)
+	assert.EqualError(t, err, "expected to receive policy bytes in context data")
+
+	err = plugin.Validate(block, "mycc", 0, 0, &struct{}{})
+	assert.EqualError(t, err, "expected to receive a serialized policy in the first context data")
+
+	err = plugin.Validate(&common.Block{}, "mycc", 0, 0, serializedPolicy("policy"))
+	assert.EqualError(t, err, "empty block")
+
+	err = plugin.Validate(block, "mycc", 1, 0, serializedPolicy("policy"))
+	assert.EqualError(t, err, "block has only 1 transactions, but requested tx at position 1")
+
+	err = plugin.Validate(&common.Block{Data: block.Data}, "mycc", 0, 0, serializedPolicy("policy"))
+	assert.EqualError(t, err, "no block header")
+
+	// the transaction itself is malformed
+	err = plugin.Validate(block, "mycc", 0, 0, serializedPolicy("policy"))
+	assert.Error(t, err)
+}
+
+func TestPluginSupport(t *testing.T) {
+	st := &mockState{values: map[string][]byte{"lscc/mycc": []byte("definition")}}
+	support := &pluginSupport{
+		StateFetcher:    &mockStateFetcher{state: st},
+		PolicyEvaluator: &mockPolicyEvaluator{err: errors.New("policy not satisfied")},
+		capabilities:    &mockCapabilities{},
+	}
+
+	value, err := support.GetState("mychannel", "lscc", "mycc")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("definition"), value)
+	assert.True(t, st.done)
+
+	value, err = support.GetState("mychannel", "lscc", "othercc")
+	assert.NoError(t, err)
+	assert.Nil(t, value)
+
+	support.StateFetcher = &mockStateFetcher{err: errors.New("ledger unavailable")}
+	_, err = support.GetState("mychannel", "lscc", "mycc")
+	assert.Contains(t, err.Error(), "ledger unavailable")
+
+	assert.EqualError(t, support.EvaluatePolicy("mychannel", nil, nil), "policy not satisfied")
+
+	caps, exists := support.Capabilities("mychannel")
+	assert.True(t, exists)
+	assert.True(t, caps.PrivateChannelData())
+}
@@ -0,0 +1,20 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package main
+
+import (
+	validation "github.com/hyperledger/fabric/core/handlers/validation/api"
+	"github.com/hyperledger/fabric/core/handlers/validation/builtin"
+)
+
+// NewPluginFactory is the function ran by the plugin infrastructure to create a validation plugin factory.
+func NewPluginFactory() validation.PluginFactory {
+	return &builtin.DefaultValidationFactory{}
+}
+
+func main() {
+}
@@ -0,0 +1,71 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/common/lifecycle"
+	"github.com/hyperledger/fabric/core/common/privdata"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/protos/common"
+)
+
+const lsccNamespace = "lscc"
+
+// collectionInfoRetriever implements interface `pvtdatapolicy.CollectionInfoProvider`.
+// The collection configurations are retrieved from the definitions of the chaincodes committed
+// to the lifecycle namespace of the state db, or from the lscc namespace for the chaincodes
+// instantiated by lscc. The state db is read directly (and not via a query executor) because the
+// configurations are retrieved while a block is being committed, i.e., while the commit lock is
+// held by the txmgr
+type collectionInfoRetriever struct {
+	db privacyenabledstate.DB
+}
+
+// GetState implements lifecycle.StateGetter on top of the state db
+func (r *collectionInfoRetriever) GetState(namespace, key string) ([]byte, error) {
+	vv, err := r.db.GetState(namespace, key)
+	if err != nil || vv == nil {
+		return nil, err
+	}
+	return vv.Value, nil
+}
+
+// CollectionInfo implements function from interface `pvtdatapolicy.CollectionInfoProvider`
+func (r *collectionInfoRetriever) CollectionInfo(chaincodeName, collectionName string) (*common.StaticCollectionConfig, error) {
+	collConfigPkg, err := r.collectionConfigPackage(chaincodeName)
+	if err != nil || collConfigPkg == nil {
+		return nil, err
+	}
+	for _, collConfig := range collConfigPkg.Config {
+		staticCollConfig := collConfig.GetStaticCollectionConfig()
+		if staticCollConfig != nil && staticCollConfig.Name == collectionName {
+			return staticCollConfig, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *collectionInfoRetriever) collectionConfigPackage(chaincodeName string) (*common.CollectionConfigPackage, error) {
+	def, err := lifecycle.QueryDefinition(r, chaincodeName)
+	if err != nil {
+		return nil, err
+	}
+	if def != nil {
+		return def.Collections, nil
+	}
+
+	collConfigPkgBytes, err := r.GetState(lsccNamespace, privdata.BuildCollectionKVSKey(chaincodeName))
+	if err != nil || collConfigPkgBytes == nil {
+		return nil, err
+	}
+	collConfigPkg := &common.CollectionConfigPackage{}
+	if err := proto.Unmarshal(collConfigPkgBytes, collConfigPkg); err != nil {
+		return nil, err
+	}
+	return collConfigPkg, nil
+}
@@ -104,6 +104,11 @@ func (scanner *historyScanner) Next() (commonledger.QueryResult, error) {
 
 		// Get the transaction from block storage that is associated with this history record
 		tranEnvelope, err := scanner.blockStore.RetrieveTxByBlockNumTranNum(blockNum, tranNum)
+		if _, ok := err.(*blkstorage.ErrBlockPruned); ok {
+			logger.Debugf("Block [%d] containing the history record for namespace:%s key:%s has been pruned. Skipping...",
+				blockNum, scanner.namespace, scanner.key)
+			continue
+		}
 		if err != nil {
 			return nil, err
 		}
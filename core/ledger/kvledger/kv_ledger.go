@@ -7,13 +7,14 @@ SPDX-License-Identifier: Apache-2.0
 package kvledger
 
 import (
-	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/hyperledger/fabric/common/flogging"
 	commonledger "github.com/hyperledger/fabric/common/ledger"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
@@ -23,8 +24,10 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/txmgr/lockbasedtxmgr"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/ledgerstorage"
+	"github.com/hyperledger/fabric/core/ledger/pvtdatapolicy"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
 )
 
 var logger = flogging.MustGetLogger("kvledger")
@@ -35,8 +38,13 @@ type kvLedger struct {
 	ledgerID        string
 	blockStore      *ledgerstorage.Store
 	txtmgmt         txmgr.TxMgr
+	versionedDB     privacyenabledstate.DB
 	historyDB       historydb.HistoryDB
 	blockAPIsRWLock *sync.RWMutex
+	pruneInProgress int32
+	pruneWG         sync.WaitGroup
+	// snapshotConfigBlock is the config block of the snapshot from which the ledger was bootstrapped (if any)
+	snapshotConfigBlock *common.Block
 }
 
 // NewKVLedger constructs new `KVLedger`
@@ -46,13 +54,20 @@ func newKVLedger(ledgerID string, blockStore *ledgerstorage.Store,
 
 	logger.Debugf("Creating KVLedger ledgerID=%s: ", ledgerID)
 
+	// The pvt data expires as per the BlockToLive of the collections, which is
+	// read from the collection configurations in the state database
+	btlPolicy := pvtdatapolicy.NewBTLPolicy(&collectionInfoRetriever{versionedDB})
+	blockStore.Init(btlPolicy)
+	versionedDB.Init(btlPolicy)
+
 	//Initialize transaction manager using state database
 	var txmgmt txmgr.TxMgr
 	txmgmt = lockbasedtxmgr.NewLockBasedTxMgr(ledgerID, versionedDB, stateListeners)
 
 	// Create a kvLedger for this chain/ledger, which encasulates the underlying
 	// id store, blockstore, txmgr (state database), history database
-	l := &kvLedger{ledgerID, blockStore, txmgmt, historyDB, &sync.RWMutex{}}
+	l := &kvLedger{ledgerID: ledgerID, blockStore: blockStore, txtmgmt: txmgmt, versionedDB: versionedDB,
+		historyDB: historyDB, blockAPIsRWLock: &sync.RWMutex{}}
 
 	// TODO Move the function `GetChaincodeEventListener` to ledger interface and
 	// this functionality of regiserting for events to ledgermgmt package so that this
@@ -161,12 +176,23 @@ func (l *kvLedger) GetBlockchainInfo() (*common.BlockchainInfo, error) {
 // GetBlockByNumber returns block at a given height
 // blockNumber of  math.MaxUint64 will return last block
 func (l *kvLedger) GetBlockByNumber(blockNumber uint64) (*common.Block, error) {
-	block, err := l.blockStore.RetrieveBlockByNumber(blockNumber)
+	block, err := l.retrieveBlockByNumber(blockNumber)
 	l.blockAPIsRWLock.RLock()
 	l.blockAPIsRWLock.RUnlock()
 	return block, err
 }
 
+// retrieveBlockByNumber retrieves the block from the block store. For a ledger bootstrapped from a snapshot,
+// the config block of the snapshot is returned even though it precedes the first available block
+func (l *kvLedger) retrieveBlockByNumber(blockNumber uint64) (*common.Block, error) {
+	block, err := l.blockStore.RetrieveBlockByNumber(blockNumber)
+	if _, ok := err.(*blkstorage.ErrBlockPruned); ok && l.snapshotConfigBlock != nil &&
+		l.snapshotConfigBlock.Header.Number == blockNumber {
+		return l.snapshotConfigBlock, nil
+	}
+	return block, err
+}
+
 // GetBlocksIterator returns an iterator that starts from `startBlockNumber`(inclusive).
 // The iterator is a blocking iterator i.e., it blocks till the next block gets available in the ledger
 // ResultsIterator contains type BlockHolder
@@ -201,9 +227,94 @@ func (l *kvLedger) GetTxValidationCodeByTxID(txID string) (peer.TxValidationCode
 	return txValidationCode, err
 }
 
-//Prune prunes the blocks/transactions that satisfy the given policy
+// Prune prunes the blocks/transactions that satisfy the given policy. The policy is expected to be
+// a `blkstorage.BlockPrunePolicy`. Irrespective of the policy, the latest config block and the blocks
+// that may be required for recovering the state DB and history DB are never pruned
 func (l *kvLedger) Prune(policy commonledger.PrunePolicy) error {
-	return errors.New("Not yet implemented")
+	blockPrunePolicy, ok := policy.(blkstorage.BlockPrunePolicy)
+	if !ok {
+		return fmt.Errorf("unsupported prune policy type [%T]", policy)
+	}
+	l.blockAPIsRWLock.Lock()
+	defer l.blockAPIsRWLock.Unlock()
+
+	info, err := l.blockStore.GetBlockchainInfo()
+	if err != nil {
+		return err
+	}
+	if info.Height == 0 {
+		logger.Debugf("[%s] Block storage is empty, nothing to prune", l.ledgerID)
+		return nil
+	}
+	retainFrom, err := blockPrunePolicy.RetainFrom(l.blockStore)
+	if err != nil {
+		return err
+	}
+	lastConfigBlockNum, err := l.lastConfigBlockNum(info.Height - 1)
+	if err != nil {
+		return err
+	}
+	if retainFrom > lastConfigBlockNum {
+		retainFrom = lastConfigBlockNum
+	}
+	for _, r := range []recoverable{l.txtmgmt, l.historyDB} {
+		recoverFlag, firstBlockNum, err := r.ShouldRecover(info.Height - 1)
+		if err != nil {
+			return err
+		}
+		if recoverFlag && retainFrom > firstBlockNum {
+			retainFrom = firstBlockNum
+		}
+	}
+	logger.Infof("[%s] Pruning blocks before block [%d]", l.ledgerID, retainFrom)
+	return l.blockStore.PruneBlocksBefore(retainFrom, blkstorage.ArchiveDirForPolicy(blockPrunePolicy))
+}
+
+// schedulePrune applies the prune policy configured for the periodic pruning in the background.
+// This is a no-op if a previously scheduled pruning is still in progress
+func (l *kvLedger) schedulePrune() {
+	if !atomic.CompareAndSwapInt32(&l.pruneInProgress, 0, 1) {
+		logger.Debugf("[%s] Pruning is already in progress", l.ledgerID)
+		return
+	}
+	l.pruneWG.Add(1)
+	go func() {
+		defer l.pruneWG.Done()
+		defer atomic.StoreInt32(&l.pruneInProgress, 0)
+		policy, err := newBlockPrunePolicyFromConfig()
+		if err == nil {
+			err = l.Prune(policy)
+		}
+		if err != nil {
+			logger.Errorf("[%s] Error while pruning blocks: %s", l.ledgerID, err)
+		}
+	}()
+}
+
+// newBlockPrunePolicyFromConfig constructs the prune policy configured for the periodic pruning
+func newBlockPrunePolicyFromConfig() (blkstorage.BlockPrunePolicy, error) {
+	var policy blkstorage.BlockPrunePolicy
+	switch name := ledgerconfig.GetPrunePolicy(); name {
+	case "keepLastNBlocks":
+		policy = &blkstorage.KeepLastNBlocksPolicy{N: ledgerconfig.GetPruneKeepLastNBlocks()}
+	case "keepBlocksNewerThan":
+		policy = &blkstorage.KeepBlocksNewerThanPolicy{Time: time.Now().Add(-ledgerconfig.GetPruneKeepBlocksNewerThan())}
+	default:
+		return nil, fmt.Errorf("unknown prune policy [%s]", name)
+	}
+	if archiveDir := ledgerconfig.GetPruneArchiveDir(); archiveDir != "" {
+		policy = &blkstorage.ArchiveThenDeletePolicy{Policy: policy, ArchiveDir: archiveDir}
+	}
+	return policy, nil
+}
+
+// lastConfigBlockNum returns the number of the latest config block as recorded in the given block
+func (l *kvLedger) lastConfigBlockNum(blockNum uint64) (uint64, error) {
+	block, err := l.retrieveBlockByNumber(blockNum)
+	if err != nil {
+		return 0, err
+	}
+	return utils.GetLastConfigIndexFromBlock(block)
 }
 
 // NewTxSimulator returns new `ledger.TxSimulator`
@@ -265,6 +376,10 @@ func (l *kvLedger) CommitWithPvtData(pvtdataAndBlock *ledger.BlockAndPvtData) er
 		}
 	}
 
+	if ledgerconfig.GetPrunePolicy() != "" && blockNo%ledgerconfig.GetPruneIntervalBlocks() == 0 {
+		l.schedulePrune()
+	}
+
 	elapsedCommitWithPvtData := time.Since(startStateValidation) / time.Millisecond // total duration in ms
 
 	logger.Infof("[%s] Committed block [%d] with %d transaction(s) in %dms (state_validation=%dms block_commit=%dms state_commit=%dms)",
@@ -292,6 +407,26 @@ func (l *kvLedger) GetPvtDataByNum(blockNum uint64, filter ledger.PvtNsCollFilte
 	return pvtdata, err
 }
 
+// CommitPvtDataOfOldBlocks commits the pvt data of the already committed blocks.
+// The pvt data is first added to the pvt data store, which retains only the data that is
+// recorded as missing, and then the stale writes are removed before the commit to the state db
+func (l *kvLedger) CommitPvtDataOfOldBlocks(blocksPvtData []*ledger.BlockPvtData) error {
+	l.blockAPIsRWLock.Lock()
+	defer l.blockAPIsRWLock.Unlock()
+	logger.Debugf("[%s] Committing pvt data of [%d] old blocks to the pvt data store", l.ledgerID, len(blocksPvtData))
+	if err := l.blockStore.CommitPvtDataOfOldBlocks(blocksPvtData); err != nil {
+		return err
+	}
+	logger.Debugf("[%s] Committing pvt data of [%d] old blocks to the state database", l.ledgerID, len(blocksPvtData))
+	return l.txtmgmt.RemoveStaleAndCommitPvtDataOfOldBlocks(blocksPvtData)
+}
+
+// GetMissingPvtDataInfoForMostRecentBlocks returns the information about the pvt data that is still
+// missing for at most `maxBlocks` most recent blocks
+func (l *kvLedger) GetMissingPvtDataInfoForMostRecentBlocks(maxBlocks int) (ledger.MissingPvtDataInfo, error) {
+	return l.blockStore.GetMissingPvtDataInfoForMostRecentBlocks(maxBlocks)
+}
+
 // Purge removes private read-writes set generated by endorsers at block height lesser than
 // a given maxBlockNumToRetain. In other words, Purge only retains private read-write sets
 // that were generated at block height of maxBlockNumToRetain or higher.
@@ -306,6 +441,7 @@ func (l *kvLedger) PrivateDataMinBlockNum() (uint64, error) {
 
 // Close closes `KVLedger`
 func (l *kvLedger) Close() {
+	l.pruneWG.Wait()
 	l.blockStore.Shutdown()
 	l.txtmgmt.Shutdown()
 }
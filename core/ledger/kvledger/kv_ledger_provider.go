@@ -34,6 +34,8 @@ var (
 
 	underConstructionLedgerKey = []byte("underConstructionLedgerKey")
 	ledgerKeyPrefix            = []byte("l")
+	ledgerKeyStop              = []byte("m")
+	snapshotConfigBlockPrefix  = []byte("s")
 )
 
 // Provider implements interface ledger.PeerLedgerProvider
@@ -43,6 +45,7 @@ type Provider struct {
 	vdbProvider         privacyenabledstate.DBProvider
 	historydbProvider   historydb.HistoryDBProvider
 	stateListeners      ledger.StateListeners
+	fileLock            *leveldbhelper.FileLock
 }
 
 // NewProvider instantiates a new Provider.
@@ -51,6 +54,12 @@ func NewProvider() (ledger.PeerLedgerProvider, error) {
 
 	logger.Info("Initializing ledger provider")
 
+	// Acquire the file lock so that the offline ledger management commands cannot run while the ledger is in use
+	fileLock, err := acquireFileLock()
+	if err != nil {
+		return nil, err
+	}
+
 	// Initialize the ID store (inventory of chainIds/ledgerIds)
 	idStore := openIDStore(ledgerconfig.GetLedgerProviderPath())
 
@@ -59,6 +68,9 @@ func NewProvider() (ledger.PeerLedgerProvider, error) {
 	// Initialize the versioned database (state database)
 	vdbProvider, err := privacyenabledstate.NewCommonStorageDBProvider()
 	if err != nil {
+		idStore.close()
+		ledgerStoreProvider.Close()
+		fileLock.Unlock()
 		return nil, err
 	}
 
@@ -67,7 +79,7 @@ func NewProvider() (ledger.PeerLedgerProvider, error) {
 	historydbProvider = historyleveldb.NewHistoryDBProvider()
 
 	logger.Info("ledger provider Initialized")
-	provider := &Provider{idStore, ledgerStoreProvider, vdbProvider, historydbProvider, nil}
+	provider := &Provider{idStore, ledgerStoreProvider, vdbProvider, historydbProvider, nil, fileLock}
 	provider.recoverUnderConstructionLedger()
 	return provider, nil
 }
@@ -153,6 +165,11 @@ func (provider *Provider) openInternal(ledgerID string) (ledger.PeerLedger, erro
 	if err != nil {
 		return nil, err
 	}
+	// A ledger bootstrapped from a snapshot does not have the blocks before the snapshot, including
+	// the config block of the snapshot, which is hence retained in the id store
+	if l.snapshotConfigBlock, err = provider.idStore.getSnapshotConfigBlock(ledgerID); err != nil {
+		return nil, err
+	}
 	return l, nil
 }
 
@@ -172,6 +189,7 @@ func (provider *Provider) Close() {
 	provider.ledgerStoreProvider.Close()
 	provider.vdbProvider.Close()
 	provider.historydbProvider.Close()
+	provider.fileLock.Unlock()
 }
 
 // recoverUnderConstructionLedger checks whether the under construction flag is set - this would be the case
@@ -192,13 +210,19 @@ func (provider *Provider) recoverUnderConstructionLedger() {
 	bcInfo, err := ledger.GetBlockchainInfo()
 	panicOnErr(err, "Error while getting blockchain info for the under construction ledger [%s]", ledgerID)
 	ledger.Close()
+	snapshotConfigBlock, err := provider.idStore.getSnapshotConfigBlock(ledgerID)
+	panicOnErr(err, "Error while retrieving snapshot config block for the under construction ledger [%s]", ledgerID)
 
-	switch bcInfo.Height {
-	case 0:
+	switch {
+	case bcInfo.Height == 0:
 		logger.Infof("Genesis block was not committed. Hence, the peer ledger not created. unsetting the under construction flag")
 		panicOnErr(provider.runCleanup(ledgerID), "Error while running cleanup for ledger id [%s]", ledgerID)
 		panicOnErr(provider.idStore.unsetUnderConstructionFlag(), "Error while unsetting under construction flag")
-	case 1:
+	case snapshotConfigBlock != nil:
+		// the block store is bootstrapped only after the state has been imported from the snapshot
+		logger.Infof("Ledger was bootstrapped from a snapshot. Hence, marking the peer ledger as created")
+		panicOnErr(provider.idStore.createLedgerID(ledgerID, snapshotConfigBlock), "Error while adding ledgerID [%s] to created list", ledgerID)
+	case bcInfo.Height == 1:
 		logger.Infof("Genesis block was committed. Hence, marking the peer ledger as created")
 		genesisBlock, err := ledger.GetBlockByNumber(0)
 		panicOnErr(err, "Error while retrieving genesis block from blockchain for ledger [%s]", ledgerID)
@@ -214,6 +238,9 @@ func (provider *Provider) recoverUnderConstructionLedger() {
 // runCleanup cleans up blockstorage, statedb, and historydb for what
 // may have got created during in-complete ledger creation
 func (provider *Provider) runCleanup(ledgerID string) error {
+	if err := provider.idStore.deleteSnapshotConfigBlock(ledgerID); err != nil {
+		return err
+	}
 	// TODO - though, not having this is harmless for kv ledger.
 	// If we want, following could be done:
 	// - blockstorage could remove empty folders
@@ -290,10 +317,12 @@ func (s *idStore) ledgerIDExists(ledgerID string) (bool, error) {
 
 func (s *idStore) getAllLedgerIds() ([]string, error) {
 	var ids []string
-	itr := s.db.GetIterator(nil, nil)
+	itr := s.db.GetIterator(ledgerKeyPrefix, ledgerKeyStop)
+	defer itr.Release()
 	itr.First()
 	for itr.Valid() {
 		if bytes.Equal(itr.Key(), underConstructionLedgerKey) {
+			itr.Next()
 			continue
 		}
 		id := string(s.decodeLedgerID(itr.Key()))
@@ -303,6 +332,33 @@ func (s *idStore) getAllLedgerIds() ([]string, error) {
 	return ids, nil
 }
 
+// setSnapshotConfigBlock persists the config block of the snapshot from which the ledger is being created
+func (s *idStore) setSnapshotConfigBlock(ledgerID string, configBlock *common.Block) error {
+	val, err := proto.Marshal(configBlock)
+	if err != nil {
+		return err
+	}
+	return s.db.Put(s.encodeSnapshotConfigBlockKey(ledgerID), val, true)
+}
+
+// getSnapshotConfigBlock returns the config block of the snapshot from which the ledger was created.
+// A nil block is returned if the ledger was not created from a snapshot
+func (s *idStore) getSnapshotConfigBlock(ledgerID string) (*common.Block, error) {
+	val, err := s.db.Get(s.encodeSnapshotConfigBlockKey(ledgerID))
+	if err != nil || val == nil {
+		return nil, err
+	}
+	configBlock := &common.Block{}
+	if err := proto.Unmarshal(val, configBlock); err != nil {
+		return nil, err
+	}
+	return configBlock, nil
+}
+
+func (s *idStore) deleteSnapshotConfigBlock(ledgerID string) error {
+	return s.db.Delete(s.encodeSnapshotConfigBlockKey(ledgerID), true)
+}
+
 func (s *idStore) close() {
 	s.db.Close()
 }
@@ -311,6 +367,10 @@ func (s *idStore) encodeLedgerKey(ledgerID string) []byte {
 	return append(ledgerKeyPrefix, []byte(ledgerID)...)
 }
 
+func (s *idStore) encodeSnapshotConfigBlockKey(ledgerID string) []byte {
+	return append(append([]byte{}, snapshotConfigBlockPrefix...), []byte(ledgerID)...)
+}
+
 func (s *idStore) decodeLedgerID(key []byte) string {
 	return string(key[len(ledgerKeyPrefix):])
 }
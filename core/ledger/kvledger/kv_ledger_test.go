@@ -21,9 +21,12 @@ import (
 	"os"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/common/privdata"
 	lgr "github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	ledgertestutil "github.com/hyperledger/fabric/core/ledger/testutil"
@@ -205,6 +208,9 @@ func TestKVLedgerDBRecovery(t *testing.T) {
 		},
 	)
 
+	// committing the collection config for the pvt data of the subsequent blocks
+	collConfigBlock := commitCollConfigForTest(t, ledger, bg, "ns", "coll")
+
 	// creating and committing the first block
 	blockAndPvtdata1 := prepareNextBlockForTest(t, ledger, bg, "SimulateForBlk1",
 		map[string]string{"key1": "value1.1", "key2": "value2.1", "key3": "value3.1"},
@@ -212,9 +218,9 @@ func TestKVLedgerDBRecovery(t *testing.T) {
 	testutil.AssertNoError(t, ledger.CommitWithPvtData(blockAndPvtdata1), "")
 	checkBCSummaryForTest(t, ledger,
 		&bcSummary{
-			bcInfo: &common.BlockchainInfo{Height: 2,
+			bcInfo: &common.BlockchainInfo{Height: 3,
 				CurrentBlockHash:  blockAndPvtdata1.Block.Header.Hash(),
-				PreviousBlockHash: gbHash},
+				PreviousBlockHash: collConfigBlock.Header.Hash()},
 		},
 	)
 
@@ -232,15 +238,15 @@ func TestKVLedgerDBRecovery(t *testing.T) {
 	// block storage should be as of block-2 but the state and history db should be as of block-1
 	checkBCSummaryForTest(t, ledger,
 		&bcSummary{
-			bcInfo: &common.BlockchainInfo{Height: 3,
+			bcInfo: &common.BlockchainInfo{Height: 4,
 				CurrentBlockHash:  blockAndPvtdata2.Block.Header.Hash(),
 				PreviousBlockHash: blockAndPvtdata1.Block.Header.Hash()},
 
-			stateDBSavePoint: uint64(1),
+			stateDBSavePoint: uint64(2),
 			stateDBKVs:       map[string]string{"key1": "value1.1", "key2": "value2.1", "key3": "value3.1"},
 			stateDBPvtKVs:    map[string]string{"key1": "pvtValue1.1", "key2": "pvtValue2.1", "key3": "pvtValue3.1"},
 
-			historyDBSavePoint: uint64(1),
+			historyDBSavePoint: uint64(2),
 			historyKey:         "key1",
 			historyVals:        []string{"value1.1"},
 		},
@@ -255,11 +261,11 @@ func TestKVLedgerDBRecovery(t *testing.T) {
 	ledger, _ = provider.Open(testLedgerid)
 	checkBCSummaryForTest(t, ledger,
 		&bcSummary{
-			stateDBSavePoint: uint64(2),
+			stateDBSavePoint: uint64(3),
 			stateDBKVs:       map[string]string{"key1": "value1.2", "key2": "value2.2", "key3": "value3.2"},
 			stateDBPvtKVs:    map[string]string{"key1": "pvtValue1.2", "key2": "pvtValue2.2", "key3": "pvtValue3.2"},
 
-			historyDBSavePoint: uint64(2),
+			historyDBSavePoint: uint64(3),
 			historyKey:         "key1",
 			historyVals:        []string{"value1.1", "value1.2"},
 		},
@@ -281,15 +287,15 @@ func TestKVLedgerDBRecovery(t *testing.T) {
 	// assume that peer fails here after committing the transaction to state DB but before history DB
 	checkBCSummaryForTest(t, ledger,
 		&bcSummary{
-			bcInfo: &common.BlockchainInfo{Height: 4,
+			bcInfo: &common.BlockchainInfo{Height: 5,
 				CurrentBlockHash:  blockAndPvtdata3.Block.Header.Hash(),
 				PreviousBlockHash: blockAndPvtdata2.Block.Header.Hash()},
 
-			stateDBSavePoint: uint64(3),
+			stateDBSavePoint: uint64(4),
 			stateDBKVs:       map[string]string{"key1": "value1.3", "key2": "value2.3", "key3": "value3.3"},
 			stateDBPvtKVs:    map[string]string{"key1": "pvtValue1.3", "key2": "pvtValue2.3", "key3": "pvtValue3.3"},
 
-			historyDBSavePoint: uint64(2),
+			historyDBSavePoint: uint64(3),
 			historyKey:         "key1",
 			historyVals:        []string{"value1.1", "value1.2"},
 		},
@@ -304,11 +310,11 @@ func TestKVLedgerDBRecovery(t *testing.T) {
 
 	checkBCSummaryForTest(t, ledger,
 		&bcSummary{
-			stateDBSavePoint: uint64(3),
+			stateDBSavePoint: uint64(4),
 			stateDBKVs:       map[string]string{"key1": "value1.3", "key2": "value2.3", "key3": "value3.3"},
 			stateDBPvtKVs:    map[string]string{"key1": "pvtValue1.3", "key2": "pvtValue2.3", "key3": "pvtValue3.3"},
 
-			historyDBSavePoint: uint64(3),
+			historyDBSavePoint: uint64(4),
 			historyKey:         "key1",
 			historyVals:        []string{"value1.1", "value1.2", "value1.3"},
 		},
@@ -330,15 +336,15 @@ func TestKVLedgerDBRecovery(t *testing.T) {
 
 	checkBCSummaryForTest(t, ledger,
 		&bcSummary{
-			bcInfo: &common.BlockchainInfo{Height: 5,
+			bcInfo: &common.BlockchainInfo{Height: 6,
 				CurrentBlockHash:  blockAndPvtdata4.Block.Header.Hash(),
 				PreviousBlockHash: blockAndPvtdata3.Block.Header.Hash()},
 
-			stateDBSavePoint: uint64(3),
+			stateDBSavePoint: uint64(4),
 			stateDBKVs:       map[string]string{"key1": "value1.3", "key2": "value2.3", "key3": "value3.3"},
 			stateDBPvtKVs:    map[string]string{"key1": "pvtValue1.3", "key2": "pvtValue2.3", "key3": "pvtValue3.3"},
 
-			historyDBSavePoint: uint64(4),
+			historyDBSavePoint: uint64(5),
 			historyKey:         "key1",
 			historyVals:        []string{"value1.1", "value1.2", "value1.3", "value1.4"},
 		},
@@ -352,17 +358,65 @@ func TestKVLedgerDBRecovery(t *testing.T) {
 	ledger, _ = provider.Open(testLedgerid)
 	checkBCSummaryForTest(t, ledger,
 		&bcSummary{
-			stateDBSavePoint: uint64(4),
+			stateDBSavePoint: uint64(5),
 			stateDBKVs:       map[string]string{"key1": "value1.4", "key2": "value2.4", "key3": "value3.4"},
 			stateDBPvtKVs:    map[string]string{"key1": "pvtValue1.4", "key2": "pvtValue2.4", "key3": "pvtValue3.4"},
 
-			historyDBSavePoint: uint64(4),
+			historyDBSavePoint: uint64(5),
 			historyKey:         "key1",
 			historyVals:        []string{"value1.1", "value1.2", "value1.3", "value1.4"},
 		},
 	)
 }
 
+func TestKVLedgerCommitPvtDataOfOldBlocks(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	defer provider.Close()
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, _ := provider.Create(gb)
+	defer ledger.Close()
+	commitCollConfigForTest(t, ledger, bg, "ns", "coll")
+
+	// block 2 is committed without the pvt data of the transaction
+	blockAndPvtdata2 := prepareNextBlockForTest(t, ledger, bg, "SimulateForBlk2",
+		map[string]string{"key1": "value1.2", "key2": "value2.2"},
+		map[string]string{"key1": "pvtValue1.2", "key2": "pvtValue2.2"})
+	pvtDataOfBlk2 := blockAndPvtdata2.BlockPvtData
+	blockAndPvtdata2.BlockPvtData = nil
+	blockAndPvtdata2.Missing = []lgr.MissingPrivateData{{TxId: "SimulateForBlk2", SeqInBlock: 0, Namespace: "ns", Collection: "coll"}}
+	assert.NoError(t, ledger.CommitWithPvtData(blockAndPvtdata2))
+
+	// block 3 updates key2 along with its pvt data
+	blockAndPvtdata3 := prepareNextBlockForTest(t, ledger, bg, "SimulateForBlk3",
+		map[string]string{"key2": "value2.3"},
+		map[string]string{"key2": "pvtValue2.3"})
+	assert.NoError(t, ledger.CommitWithPvtData(blockAndPvtdata3))
+
+	missingInfo, err := ledger.GetMissingPvtDataInfoForMostRecentBlocks(10)
+	assert.NoError(t, err)
+	assert.Equal(t, lgr.MissingPvtDataInfo{
+		2: {{TxId: "SimulateForBlk2", SeqInBlock: 0, Namespace: "ns", Collection: "coll"}},
+	}, missingInfo)
+
+	assert.NoError(t, ledger.CommitPvtDataOfOldBlocks([]*lgr.BlockPvtData{{BlockNum: 2, WriteSets: pvtDataOfBlk2}}))
+
+	// the pvt data of block 2 is no longer missing and is available in the pvt data store
+	missingInfo, err = ledger.GetMissingPvtDataInfoForMostRecentBlocks(10)
+	assert.NoError(t, err)
+	assert.Empty(t, missingInfo)
+	pvtData, err := ledger.GetPvtDataByNum(2, nil)
+	assert.NoError(t, err)
+	assert.Len(t, pvtData, 1)
+	assert.True(t, proto.Equal(pvtDataOfBlk2[0].WriteSet, pvtData[0].WriteSet))
+
+	// key1 is reconciled in the state db while key2 retains the value committed by block 3
+	checkStateDBForTest(t, ledger,
+		map[string]string{"key1": "value1.2", "key2": "value2.3"},
+		map[string]string{"key1": "pvtValue1.2", "key2": "pvtValue2.3"})
+}
+
 func TestLedgerWithCouchDbEnabledWithBinaryAndJSONData(t *testing.T) {
 
 	//call a helper method to load the core.yaml
@@ -497,6 +551,28 @@ func prepareNextBlockForTest(t *testing.T, l lgr.PeerLedger, bg *testutil.BlockG
 	}
 }
 
+func commitCollConfigForTest(t *testing.T, l lgr.PeerLedger, bg *testutil.BlockGenerator,
+	ns string, collNames ...string) *common.Block {
+	collConfigPkg := &common.CollectionConfigPackage{}
+	for _, collName := range collNames {
+		collConfigPkg.Config = append(collConfigPkg.Config, &common.CollectionConfig{
+			Payload: &common.CollectionConfig_StaticCollectionConfig{
+				StaticCollectionConfig: &common.StaticCollectionConfig{Name: collName},
+			},
+		})
+	}
+	collConfigPkgBytes, err := proto.Marshal(collConfigPkg)
+	assert.NoError(t, err)
+	simulator, _ := l.NewTxSimulator("DeployCollConfig")
+	simulator.SetState(lsccNamespace, privdata.BuildCollectionKVSKey(ns), collConfigPkgBytes)
+	simulator.Done()
+	simRes, _ := simulator.GetTxSimulationResults()
+	pubSimBytes, _ := simRes.GetPubSimulationBytes()
+	block := bg.NextBlock([][]byte{pubSimBytes})
+	assert.NoError(t, l.CommitWithPvtData(&lgr.BlockAndPvtData{Block: block}))
+	return block
+}
+
 func checkBCSummaryForTest(t *testing.T, l lgr.PeerLedger, expectedBCSummary *bcSummary) {
 	if expectedBCSummary.bcInfo != nil {
 		actualBCInfo, _ := l.GetBlockchainInfo()
@@ -561,3 +637,105 @@ type bcSummary struct {
 	historyKey         string
 	historyVals        []string
 }
+
+func TestKVLedgerPrune(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	viper.Set("ledger.blockchain.maxBlockfileSize", 4096)
+	defer viper.Set("ledger.blockchain.maxBlockfileSize", 64*1024*1024)
+	provider, _ := NewProvider()
+	defer provider.Close()
+
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, _ := provider.Create(gb)
+	defer ledger.Close()
+
+	var blocks []*common.Block
+	commitBlock := func(lastConfigBlockNum uint64, value string) {
+		simulator, _ := ledger.NewTxSimulator(util.GenerateUUID())
+		simulator.SetState("ns1", "key1", []byte(value))
+		simulator.Done()
+		simRes, _ := simulator.GetTxSimulationResults()
+		pubSimBytes, _ := simRes.GetPubSimulationBytes()
+		block := bg.NextBlock([][]byte{pubSimBytes})
+		block.Metadata.Metadata[common.BlockMetadataIndex_LAST_CONFIG] = putils.MarshalOrPanic(&common.Metadata{
+			Value: putils.MarshalOrPanic(&common.LastConfig{Index: lastConfigBlockNum}),
+		})
+		assert.NoError(t, ledger.CommitWithPvtData(&lgr.BlockAndPvtData{Block: block}))
+		blocks = append(blocks, block)
+	}
+	for i := 1; i < 30; i++ {
+		commitBlock(0, fmt.Sprintf("value%d", i))
+	}
+
+	assert.EqualError(t, ledger.Prune("unknownPolicy"), "unsupported prune policy type [string]")
+
+	// the genesis block is the latest config block and hence no block gets pruned
+	assert.NoError(t, ledger.Prune(&blkstorage.KeepLastNBlocksPolicy{N: 5}))
+	_, err := ledger.GetBlockByNumber(0)
+	assert.NoError(t, err)
+
+	commitBlock(25, "value30")
+	assert.NoError(t, ledger.Prune(&blkstorage.KeepLastNBlocksPolicy{N: 5}))
+	_, err = ledger.GetBlockByNumber(0)
+	assert.IsType(t, &blkstorage.ErrBlockPruned{}, err)
+	for blockNum := uint64(25); blockNum <= 30; blockNum++ {
+		block, err := ledger.GetBlockByNumber(blockNum)
+		assert.NoError(t, err)
+		assert.Equal(t, blocks[blockNum-1].Header, block.Header)
+	}
+
+	// a pruned transaction is reported as pruned while its validation code continues to be available
+	txEnv, err := putils.GetEnvelopeFromBlock(blocks[0].Data.Data[0])
+	assert.NoError(t, err)
+	chdr, err := putils.ChannelHeader(txEnv)
+	assert.NoError(t, err)
+	_, err = ledger.GetTransactionByID(chdr.TxId)
+	assert.IsType(t, &blkstorage.ErrBlockPruned{}, err)
+	validationCode, err := ledger.GetTxValidationCodeByTxID(chdr.TxId)
+	assert.NoError(t, err)
+	assert.Equal(t, peer.TxValidationCode_VALID, validationCode)
+
+	// the state is not affected and the history skips the pruned blocks
+	qe, _ := ledger.NewQueryExecutor()
+	value, err := qe.GetState("ns1", "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value30"), value)
+	qe.Done()
+	hqe, err := ledger.NewHistoryQueryExecutor()
+	assert.NoError(t, err)
+	itr, err := hqe.GetHistoryForKey("ns1", "key1")
+	assert.NoError(t, err)
+	defer itr.Close()
+	numModifications := 0
+	for {
+		kmod, err := itr.Next()
+		assert.NoError(t, err)
+		if kmod == nil {
+			break
+		}
+		numModifications++
+	}
+	assert.True(t, numModifications >= 6 && numModifications < 30)
+}
+
+func TestNewBlockPrunePolicyFromConfig(t *testing.T) {
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	viper.Set("ledger.blockchain.pruning.policy", "keepLastNBlocks")
+	viper.Set("ledger.blockchain.pruning.keepLastNBlocks", 10)
+	policy, err := newBlockPrunePolicyFromConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, &blkstorage.KeepLastNBlocksPolicy{N: 10}, policy)
+
+	viper.Set("ledger.blockchain.pruning.policy", "keepBlocksNewerThan")
+	viper.Set("ledger.blockchain.pruning.archiveDir", "/tmp/archive")
+	policy, err = newBlockPrunePolicyFromConfig()
+	assert.NoError(t, err)
+	assert.IsType(t, &blkstorage.ArchiveThenDeletePolicy{}, policy)
+	assert.Equal(t, "/tmp/archive", blkstorage.ArchiveDirForPolicy(policy))
+	assert.IsType(t, &blkstorage.KeepBlocksNewerThanPolicy{}, policy.(*blkstorage.ArchiveThenDeletePolicy).Policy)
+
+	viper.Set("ledger.blockchain.pruning.policy", "keepNothing")
+	_, err = newBlockPrunePolicyFromConfig()
+	assert.EqualError(t, err, "unknown prune policy [keepNothing]")
+}
@@ -0,0 +1,135 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/statecouchdb"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/ledger/ledgerstorage"
+)
+
+// RollbackKVLedger rolls back the ledger with the given id such that the block `blockNum` becomes the last block.
+// The state database, the history database and the internal bookkeeping data of all the ledgers are dropped, these
+// are rebuilt from the block store when the peer is started next time. This function is expected to be invoked
+// only when the peer is stopped, an error is returned if the ledgers are in use
+func RollbackKVLedger(ledgerID string, blockNum uint64) error {
+	fileLock, err := acquireFileLock()
+	if err != nil {
+		return err
+	}
+	defer fileLock.Unlock()
+
+	ledgerIDs, err := loadLedgerIDs()
+	if err != nil {
+		return err
+	}
+	if !contains(ledgerIDs, ledgerID) {
+		return fmt.Errorf("ledger [%s] does not exist", ledgerID)
+	}
+	if err = checkLedgersRebuildable(ledgerIDs); err != nil {
+		return err
+	}
+	if err = dropDBs(); err != nil {
+		return err
+	}
+	logger.Infof("Rolling back ledger [%s] to block [%d]", ledgerID, blockNum)
+	return ledgerstorage.Rollback(ledgerID, blockNum)
+}
+
+// RebuildDBs drops the state database, the history database and the internal bookkeeping data of all the ledgers.
+// These are rebuilt from the block store when the peer is started next time. This function is expected to be
+// invoked only when the peer is stopped, an error is returned if the ledgers are in use
+func RebuildDBs() error {
+	fileLock, err := acquireFileLock()
+	if err != nil {
+		return err
+	}
+	defer fileLock.Unlock()
+
+	ledgerIDs, err := loadLedgerIDs()
+	if err != nil {
+		return err
+	}
+	if err = checkLedgersRebuildable(ledgerIDs); err != nil {
+		return err
+	}
+	return dropDBs()
+}
+
+// acquireFileLock acquires the lock that is held by the ledger provider while the peer is running and by
+// the offline ledger management commands while they run
+func acquireFileLock() (*leveldbhelper.FileLock, error) {
+	fileLock := leveldbhelper.NewFileLock(ledgerconfig.GetFileLockPath())
+	if err := fileLock.Lock(); err != nil {
+		return nil, fmt.Errorf("Error while acquiring the ledger lock, the ledger is in use by a running peer "+
+			"or by another peer node command: %s", err)
+	}
+	return fileLock, nil
+}
+
+func loadLedgerIDs() ([]string, error) {
+	idStore := openIDStore(ledgerconfig.GetLedgerProviderPath())
+	defer idStore.close()
+	return idStore.getAllLedgerIds()
+}
+
+// checkLedgersRebuildable returns an error if the block store of any of the given ledgers does not start
+// from the genesis block, e.g., if the blocks have been pruned or the ledger was created from a snapshot.
+// The dbs of such a ledger cannot be rebuilt from its block store
+func checkLedgersRebuildable(ledgerIDs []string) error {
+	ledgerStoreProvider := ledgerstorage.NewProvider()
+	defer ledgerStoreProvider.Close()
+	for _, ledgerID := range ledgerIDs {
+		store, err := ledgerStoreProvider.Open(ledgerID)
+		if err != nil {
+			return err
+		}
+		firstAvailableBlockNum, err := store.GetFirstAvailableBlockNum()
+		store.Shutdown()
+		if err != nil {
+			return err
+		}
+		if firstAvailableBlockNum != 0 {
+			return fmt.Errorf("the dbs of ledger [%s] cannot be rebuilt as its block store starts at block [%d]",
+				ledgerID, firstAvailableBlockNum)
+		}
+	}
+	return nil
+}
+
+// dropDBs drops the state database, the history database and the internal bookkeeping data of all the ledgers
+func dropDBs() error {
+	if ledgerconfig.IsCouchDBEnabled() {
+		if err := statecouchdb.DropApplicationDBs(); err != nil {
+			return err
+		}
+	}
+	for _, path := range []string{
+		ledgerconfig.GetStateLevelDBPath(),
+		ledgerconfig.GetHistoryLevelDBPath(),
+		ledgerconfig.GetInternalBookkeeperPath(),
+	} {
+		logger.Infof("Dropping [%s]", path)
+		if err := os.RemoveAll(path); err != nil {
+			return fmt.Errorf("Error while dropping [%s]: %s", path, err)
+		}
+	}
+	return nil
+}
+
+func contains(ledgerIDs []string, ledgerID string) bool {
+	for _, id := range ledgerIDs {
+		if id == ledgerID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollbackKVLedger(t *testing.T) {
+	viper.Set("ledger.history.enableHistoryDatabase", true)
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, _ := provider.Create(gb)
+	commitCollConfigForTest(t, ledger, bg, "ns", "coll")
+	blockAndPvtdata2 := prepareNextBlockForTest(t, ledger, bg, "SimulateForBlk2",
+		map[string]string{"key1": "value1.2", "key2": "value2.2"},
+		map[string]string{"key1": "pvtValue1.2", "key2": "pvtValue2.2"})
+	assert.NoError(t, ledger.CommitWithPvtData(blockAndPvtdata2))
+	blockAndPvtdata3 := prepareNextBlockForTest(t, ledger, bg, "SimulateForBlk3",
+		map[string]string{"key2": "value2.3"},
+		map[string]string{"key2": "pvtValue2.3"})
+	assert.NoError(t, ledger.CommitWithPvtData(blockAndPvtdata3))
+
+	// the ledger cannot be rolled back while it is in use
+	err := RollbackKVLedger("testLedger", 2)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the ledger is in use")
+	ledger.Close()
+	provider.Close()
+
+	assert.EqualError(t, RollbackKVLedger("nonExistentLedger", 2), "ledger [nonExistentLedger] does not exist")
+	assert.NoError(t, RollbackKVLedger("testLedger", 2))
+
+	// the state and the history are rebuilt upto the block 2
+	provider, _ = NewProvider()
+	defer provider.Close()
+	ledger, err = provider.Open("testLedger")
+	assert.NoError(t, err)
+	defer ledger.Close()
+	checkBCSummaryForTest(t, ledger, &bcSummary{
+		stateDBSavePoint:   2,
+		stateDBKVs:         map[string]string{"key1": "value1.2", "key2": "value2.2"},
+		stateDBPvtKVs:      map[string]string{"key1": "pvtValue1.2", "key2": "pvtValue2.2"},
+		historyDBSavePoint: 2,
+		historyKey:         "key2",
+		historyVals:        []string{"value2.2"},
+	})
+	bcInfo, _ := ledger.GetBlockchainInfo()
+	assert.Equal(t, uint64(3), bcInfo.Height)
+	assert.Equal(t, blockAndPvtdata2.Block.Header.Hash(), bcInfo.CurrentBlockHash)
+
+	// the rolled back block can be committed again
+	assert.NoError(t, ledger.CommitWithPvtData(blockAndPvtdata3))
+	checkStateDBForTest(t, ledger, map[string]string{"key2": "value2.3"}, map[string]string{"key2": "pvtValue2.3"})
+}
+
+func TestRebuildDBs(t *testing.T) {
+	viper.Set("ledger.history.enableHistoryDatabase", true)
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, _ := provider.Create(gb)
+	commitCollConfigForTest(t, ledger, bg, "ns", "coll")
+	blockAndPvtdata := prepareNextBlockForTest(t, ledger, bg, "SimulateForBlk2",
+		map[string]string{"key1": "value1.2"},
+		map[string]string{"key1": "pvtValue1.2"})
+	assert.NoError(t, ledger.CommitWithPvtData(blockAndPvtdata))
+
+	err := RebuildDBs()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the ledger is in use")
+	ledger.Close()
+	provider.Close()
+
+	assert.NoError(t, RebuildDBs())
+
+	provider, _ = NewProvider()
+	defer provider.Close()
+	ledger, err = provider.Open("testLedger")
+	assert.NoError(t, err)
+	defer ledger.Close()
+	checkBCSummaryForTest(t, ledger, &bcSummary{
+		stateDBSavePoint:   2,
+		stateDBKVs:         map[string]string{"key1": "value1.2"},
+		stateDBPvtKVs:      map[string]string{"key1": "pvtValue1.2"},
+		historyDBSavePoint: 2,
+	})
+}
+
+func TestRebuildDBsOfSnapshotLedger(t *testing.T) {
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, _ := provider.Create(gb)
+	commitCollConfigForTest(t, ledger, bg, "ns", "coll")
+	ledger.Close()
+	// the block store of a ledger created from a snapshot does not start from the genesis block
+	ledgerStore, err := provider.(*Provider).ledgerStoreProvider.Open("snapshotLedger")
+	assert.NoError(t, err)
+	_, snapshotGB := testutil.NewBlockGenerator(t, "snapshotLedger", false)
+	snapshotGB.Header.Number = 5
+	assert.NoError(t, ledgerStore.BootstrapFromSnapshot(snapshotGB))
+	ledgerStore.Shutdown()
+	assert.NoError(t, provider.(*Provider).idStore.createLedgerID("snapshotLedger", snapshotGB))
+	provider.Close()
+
+	assert.EqualError(t, RebuildDBs(), "the dbs of ledger [snapshotLedger] cannot be rebuilt as its block store starts at block [5]")
+	assert.EqualError(t, RollbackKVLedger("testLedger", 0), "the dbs of ledger [snapshotLedger] cannot be rebuilt as its block store starts at block [5]")
+}
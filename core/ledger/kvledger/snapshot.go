@@ -0,0 +1,452 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// A snapshot is a directory that contains the following files
+// - the public state and the hashed state of the collections, as of the last block of the snapshot
+// - the last block and the last config block, which are marshaled protos
+// - the snapshot metadata, which records the hashes of the above files
+// The private data and the blocks before the last block are not included. A ledger bootstrapped from
+// a snapshot serves the blocks only from the last block of the snapshot onward
+const (
+	snapshotStateFileName           = "public_and_hashed_state.data"
+	snapshotLastBlockFileName       = "last_block.data"
+	snapshotLastConfigBlockFileName = "last_config_block.data"
+	snapshotMetadataFileName        = "snapshot_metadata.json"
+)
+
+// snapshotMetadata is persisted as json in the snapshot directory. As the metadata includes the hashes of all
+// the other files, the hash of the metadata can be compared across the snapshots exported by different peers
+type snapshotMetadata struct {
+	ChannelName           string            `json:"channel_name"`
+	LastBlockNumber       uint64            `json:"last_block_number"`
+	LastBlockHash         string            `json:"last_block_hash"`
+	PreviousBlockHash     string            `json:"previous_block_hash"`
+	LastConfigBlockNumber uint64            `json:"last_config_block_number"`
+	FilesSHA256           map[string]string `json:"files_sha256"`
+}
+
+// ExportSnapshot implements method in interface `ledger.PeerLedger`. The commits are blocked while the
+// snapshot is being exported so that the exported state is consistent with the last block
+func (l *kvLedger) ExportSnapshot(snapshotDir string) ([]byte, error) {
+	l.blockAPIsRWLock.RLock()
+	defer l.blockAPIsRWLock.RUnlock()
+
+	bcInfo, err := l.blockStore.GetBlockchainInfo()
+	if err != nil {
+		return nil, err
+	}
+	if bcInfo.Height == 0 {
+		return nil, fmt.Errorf("ledger [%s] is empty", l.ledgerID)
+	}
+	lastBlock, err := l.retrieveBlockByNumber(bcInfo.Height - 1)
+	if err != nil {
+		return nil, err
+	}
+	lastConfigBlockNum, err := utils.GetLastConfigIndexFromBlock(lastBlock)
+	if err != nil {
+		return nil, err
+	}
+	lastConfigBlock, err := l.retrieveBlockByNumber(lastConfigBlockNum)
+	if err != nil {
+		return nil, fmt.Errorf("error while retrieving the last config block [%d]: %s", lastConfigBlockNum, err)
+	}
+	savepoint, err := l.versionedDB.GetLatestSavePoint()
+	if err != nil {
+		return nil, err
+	}
+	if savepoint == nil || savepoint.BlockNum != lastBlock.Header.Number {
+		return nil, fmt.Errorf("state database of ledger [%s] is not in sync with the block store", l.ledgerID)
+	}
+	stateItr, err := l.versionedDB.GetPubAndHashedStateIterator()
+	if err != nil {
+		return nil, err
+	}
+	defer stateItr.Close()
+
+	logger.Infof("[%s] Exporting snapshot at block [%d] to [%s]", l.ledgerID, lastBlock.Header.Number, snapshotDir)
+	return writeSnapshot(snapshotDir, l.ledgerID, lastBlock, lastConfigBlock, stateItr)
+}
+
+// bootstrapFromSnapshot loads the snapshot into the empty ledger. The block store is bootstrapped last,
+// so that a ledger with a non-empty block store always has the complete state
+func (l *kvLedger) bootstrapFromSnapshot(s *snapshotReader) error {
+	stateItr, err := s.newStateIterator()
+	if err != nil {
+		return err
+	}
+	defer stateItr.Close()
+	lastBlock := s.lastBlock
+	savepoint := version.NewHeight(lastBlock.Header.Number, uint64(len(lastBlock.Data.Data)-1))
+	if err := l.versionedDB.ImportPubAndHashedState(stateItr, savepoint); err != nil {
+		return fmt.Errorf("error while importing state from snapshot: %s", err)
+	}
+	// the history db records only the writes of the last block, the commit sets the savepoint of the
+	// history db so that it is not recovered from the blocks that are not available
+	if err := l.historyDB.Commit(lastBlock); err != nil {
+		return err
+	}
+	l.blockAPIsRWLock.Lock()
+	defer l.blockAPIsRWLock.Unlock()
+	if err := l.blockStore.BootstrapFromSnapshot(lastBlock); err != nil {
+		return err
+	}
+	l.snapshotConfigBlock = s.lastConfigBlock
+	return nil
+}
+
+// CreateFromSnapshot implements the corresponding method from interface ledger.PeerLedgerProvider.
+// Similar to the function `Create`, the under construction flag is set before creating the ledger. In addition,
+// the config block of the snapshot is persisted so that a crash after the ledger has been bootstrapped can be
+// recovered by the function 'recoverUnderConstructionLedger'
+func (provider *Provider) CreateFromSnapshot(snapshotDir string, verifier ledger.SnapshotBlocksVerifier) (ledger.PeerLedger, error) {
+	snapshot, err := openSnapshot(snapshotDir)
+	if err != nil {
+		return nil, err
+	}
+	if verifier != nil {
+		if err := verifier(snapshot.lastBlock, snapshot.lastConfigBlock); err != nil {
+			return nil, fmt.Errorf("verification of the snapshot blocks failed: %s", err)
+		}
+	}
+	ledgerID := snapshot.metadata.ChannelName
+	exists, err := provider.idStore.ledgerIDExists(ledgerID)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		return nil, ErrLedgerIDExists
+	}
+	if err = provider.idStore.setUnderConstructionFlag(ledgerID); err != nil {
+		return nil, err
+	}
+	if err = provider.idStore.setSnapshotConfigBlock(ledgerID, snapshot.lastConfigBlock); err != nil {
+		return nil, err
+	}
+	lgr, err := provider.openInternal(ledgerID)
+	if err != nil {
+		logger.Errorf("Error in opening a new empty ledger. Unsetting under construction flag. Err: %s", err)
+		panicOnErr(provider.runCleanup(ledgerID), "Error while running cleanup for ledger id [%s]", ledgerID)
+		panicOnErr(provider.idStore.unsetUnderConstructionFlag(), "Error while unsetting under construction flag")
+		return nil, err
+	}
+	if err := lgr.(*kvLedger).bootstrapFromSnapshot(snapshot); err != nil {
+		lgr.Close()
+		return nil, err
+	}
+	panicOnErr(provider.idStore.createLedgerID(ledgerID, snapshot.lastConfigBlock), "Error while marking ledger as created")
+	logger.Infof("Created ledger [%s] from snapshot at block [%d], snapshot metadata hash [%x]",
+		ledgerID, snapshot.metadata.LastBlockNumber, snapshot.metadataHash)
+	return lgr, nil
+}
+
+// writeSnapshot writes the snapshot files in a temporary directory, which is renamed to the snapshot directory
+// after all the files are written. This function returns the hash of the snapshot metadata
+func writeSnapshot(snapshotDir, ledgerID string, lastBlock, lastConfigBlock *common.Block, stateItr statedb.ResultsIterator) ([]byte, error) {
+	if _, err := os.Stat(snapshotDir); !os.IsNotExist(err) {
+		return nil, fmt.Errorf("snapshot directory [%s] already exists", snapshotDir)
+	}
+	tmpDir := snapshotDir + ".tmp"
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(tmpDir, 0755); err != nil {
+		return nil, err
+	}
+	metadata := &snapshotMetadata{
+		ChannelName:           ledgerID,
+		LastBlockNumber:       lastBlock.Header.Number,
+		LastBlockHash:         hex.EncodeToString(lastBlock.Header.Hash()),
+		PreviousBlockHash:     hex.EncodeToString(lastBlock.Header.PreviousHash),
+		LastConfigBlockNumber: lastConfigBlock.Header.Number,
+		FilesSHA256:           make(map[string]string),
+	}
+	for fileName, block := range map[string]*common.Block{
+		snapshotLastBlockFileName:       lastBlock,
+		snapshotLastConfigBlockFileName: lastConfigBlock,
+	} {
+		blockBytes, err := proto.Marshal(block)
+		if err != nil {
+			return nil, err
+		}
+		if err := ioutil.WriteFile(filepath.Join(tmpDir, fileName), blockBytes, 0644); err != nil {
+			return nil, err
+		}
+		metadata.FilesSHA256[fileName] = hex.EncodeToString(computeSHA256(blockBytes))
+	}
+	stateHash, err := writeSnapshotState(filepath.Join(tmpDir, snapshotStateFileName), stateItr)
+	if err != nil {
+		return nil, err
+	}
+	metadata.FilesSHA256[snapshotStateFileName] = hex.EncodeToString(stateHash)
+
+	metadataBytes, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(filepath.Join(tmpDir, snapshotMetadataFileName), metadataBytes, 0644); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmpDir, snapshotDir); err != nil {
+		return nil, err
+	}
+	return computeSHA256(metadataBytes), nil
+}
+
+// writeSnapshotState writes the state entries as length prefixed records and returns the hash of the file
+func writeSnapshotState(filePath string, stateItr statedb.ResultsIterator) ([]byte, error) {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	hasher := sha256.New()
+	writer := bufio.NewWriter(io.MultiWriter(file, hasher))
+	for {
+		res, err := stateItr.Next()
+		if err != nil {
+			return nil, err
+		}
+		if res == nil {
+			break
+		}
+		recordBytes, err := encodeSnapshotKV(res.(*privacyenabledstate.SnapshotKV))
+		if err != nil {
+			return nil, err
+		}
+		if _, err := writer.Write(proto.EncodeVarint(uint64(len(recordBytes)))); err != nil {
+			return nil, err
+		}
+		if _, err := writer.Write(recordBytes); err != nil {
+			return nil, err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return nil, err
+	}
+	if err := file.Sync(); err != nil {
+		return nil, err
+	}
+	return hasher.Sum(nil), nil
+}
+
+// snapshotReader provides access to the contents of a snapshot directory
+type snapshotReader struct {
+	dir             string
+	metadata        *snapshotMetadata
+	metadataHash    []byte
+	lastBlock       *common.Block
+	lastConfigBlock *common.Block
+}
+
+// openSnapshot loads the snapshot metadata and the blocks, and verifies them against the hashes recorded in the metadata
+func openSnapshot(snapshotDir string) (*snapshotReader, error) {
+	metadataBytes, err := ioutil.ReadFile(filepath.Join(snapshotDir, snapshotMetadataFileName))
+	if err != nil {
+		return nil, fmt.Errorf("error while reading snapshot metadata: %s", err)
+	}
+	metadata := &snapshotMetadata{}
+	if err := json.Unmarshal(metadataBytes, metadata); err != nil {
+		return nil, fmt.Errorf("error while unmarshaling snapshot metadata: %s", err)
+	}
+	for _, fileName := range []string{snapshotStateFileName, snapshotLastBlockFileName, snapshotLastConfigBlockFileName} {
+		fileHash, err := computeFileSHA256(filepath.Join(snapshotDir, fileName))
+		if err != nil {
+			return nil, err
+		}
+		if hex.EncodeToString(fileHash) != metadata.FilesSHA256[fileName] {
+			return nil, fmt.Errorf("hash of snapshot file [%s] does not match the snapshot metadata", fileName)
+		}
+	}
+	s := &snapshotReader{dir: snapshotDir, metadata: metadata, metadataHash: computeSHA256(metadataBytes)}
+	if s.lastBlock, err = readSnapshotBlock(snapshotDir, snapshotLastBlockFileName); err != nil {
+		return nil, err
+	}
+	if s.lastConfigBlock, err = readSnapshotBlock(snapshotDir, snapshotLastConfigBlockFileName); err != nil {
+		return nil, err
+	}
+	if err := s.verifyBlocks(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// verifyBlocks verifies that the blocks of the snapshot are consistent with the metadata and with each other.
+// The header of the last block, which is the one signed by the ordering service, must cover its data, so that
+// the signatures, verified by the caller of `CreateFromSnapshot`, vouch for the transactions of the block
+func (s *snapshotReader) verifyBlocks() error {
+	lastBlock, lastConfigBlock, metadata := s.lastBlock, s.lastConfigBlock, s.metadata
+	if lastBlock.Header == nil || lastBlock.Header.Number != metadata.LastBlockNumber ||
+		hex.EncodeToString(lastBlock.Header.Hash()) != metadata.LastBlockHash ||
+		hex.EncodeToString(lastBlock.Header.PreviousHash) != metadata.PreviousBlockHash {
+		return fmt.Errorf("last block of the snapshot does not match the snapshot metadata")
+	}
+	if lastBlock.Data == nil || len(lastBlock.Data.Data) == 0 {
+		return fmt.Errorf("last block of the snapshot does not contain any transaction")
+	}
+	if !bytes.Equal(lastBlock.Data.Hash(), lastBlock.Header.DataHash) {
+		return fmt.Errorf("data hash of the last block of the snapshot does not match its header")
+	}
+	if lastConfigBlock.Header == nil || lastConfigBlock.Data == nil ||
+		!bytes.Equal(lastConfigBlock.Data.Hash(), lastConfigBlock.Header.DataHash) {
+		return fmt.Errorf("data hash of the last config block of the snapshot does not match its header")
+	}
+	if lastConfigBlock.Header.Number == lastBlock.Header.Number &&
+		!bytes.Equal(lastConfigBlock.Header.Hash(), lastBlock.Header.Hash()) {
+		return fmt.Errorf("last config block of the snapshot does not match the last block")
+	}
+	chainID, err := utils.GetChainIDFromBlock(lastConfigBlock)
+	if err != nil {
+		return err
+	}
+	if chainID != metadata.ChannelName {
+		return fmt.Errorf("config block of channel [%s] found in the snapshot of channel [%s]", chainID, metadata.ChannelName)
+	}
+	lastConfigBlockNum, err := utils.GetLastConfigIndexFromBlock(lastBlock)
+	if err != nil {
+		return err
+	}
+	if lastConfigBlock.Header == nil || lastConfigBlock.Header.Number != lastConfigBlockNum ||
+		lastConfigBlockNum != metadata.LastConfigBlockNumber {
+		return fmt.Errorf("last config block of the snapshot does not match the last block")
+	}
+	return nil
+}
+
+// newStateIterator returns an iterator over the state entries of the snapshot.
+// The returned ResultsIterator contains results of type *privacyenabledstate.SnapshotKV
+func (s *snapshotReader) newStateIterator() (statedb.ResultsIterator, error) {
+	file, err := os.Open(filepath.Join(s.dir, snapshotStateFileName))
+	if err != nil {
+		return nil, err
+	}
+	return &snapshotStateItr{file: file, reader: bufio.NewReader(file)}, nil
+}
+
+type snapshotStateItr struct {
+	file   *os.File
+	reader *bufio.Reader
+}
+
+func (itr *snapshotStateItr) Next() (statedb.QueryResult, error) {
+	recordLen, err := binary.ReadUvarint(itr.reader)
+	if err == io.EOF {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	recordBytes := make([]byte, recordLen)
+	if _, err := io.ReadFull(itr.reader, recordBytes); err != nil {
+		return nil, fmt.Errorf("error while reading snapshot state: %s", err)
+	}
+	return decodeSnapshotKV(recordBytes)
+}
+
+func (itr *snapshotStateItr) Close() {
+	itr.file.Close()
+}
+
+func encodeSnapshotKV(kv *privacyenabledstate.SnapshotKV) ([]byte, error) {
+	buffer := proto.NewBuffer(nil)
+	for _, s := range []string{kv.Namespace, kv.Collection, kv.Key} {
+		if err := buffer.EncodeStringBytes(s); err != nil {
+			return nil, err
+		}
+	}
+	for _, b := range [][]byte{kv.Value, kv.Metadata} {
+		if err := buffer.EncodeRawBytes(b); err != nil {
+			return nil, err
+		}
+	}
+	for _, n := range []uint64{kv.Version.BlockNum, kv.Version.TxNum} {
+		if err := buffer.EncodeVarint(n); err != nil {
+			return nil, err
+		}
+	}
+	return buffer.Bytes(), nil
+}
+
+func decodeSnapshotKV(b []byte) (*privacyenabledstate.SnapshotKV, error) {
+	buffer := proto.NewBuffer(b)
+	kv := &privacyenabledstate.SnapshotKV{}
+	var err error
+	for _, s := range []*string{&kv.Namespace, &kv.Collection, &kv.Key} {
+		if *s, err = buffer.DecodeStringBytes(); err != nil {
+			return nil, err
+		}
+	}
+	for _, b := range []*[]byte{&kv.Value, &kv.Metadata} {
+		if *b, err = buffer.DecodeRawBytes(true); err != nil {
+			return nil, err
+		}
+	}
+	if len(kv.Metadata) == 0 {
+		kv.Metadata = nil
+	}
+	var blockNum, txNum uint64
+	if blockNum, err = buffer.DecodeVarint(); err != nil {
+		return nil, err
+	}
+	if txNum, err = buffer.DecodeVarint(); err != nil {
+		return nil, err
+	}
+	kv.Version = version.NewHeight(blockNum, txNum)
+	return kv, nil
+}
+
+func readSnapshotBlock(snapshotDir, fileName string) (*common.Block, error) {
+	blockBytes, err := ioutil.ReadFile(filepath.Join(snapshotDir, fileName))
+	if err != nil {
+		return nil, err
+	}
+	block := &common.Block{}
+	if err := proto.Unmarshal(blockBytes, block); err != nil {
+		return nil, fmt.Errorf("error while unmarshaling snapshot file [%s]: %s", fileName, err)
+	}
+	return block, nil
+}
+
+func computeFileSHA256(filePath string) ([]byte, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return nil, err
+	}
+	return hasher.Sum(nil), nil
+}
+
+func computeSHA256(b []byte) []byte {
+	h := sha256.Sum256(b)
+	return h[:]
+}
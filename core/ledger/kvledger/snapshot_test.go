@@ -0,0 +1,235 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package kvledger
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/blkstorage"
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	commonutil "github.com/hyperledger/fabric/common/util"
+	lgr "github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotExportAndCreateFromSnapshot(t *testing.T) {
+	snapshotsDir, err := ioutil.TempDir("", "snapshots")
+	assert.NoError(t, err)
+	defer os.RemoveAll(snapshotsDir)
+	snapshotDir := filepath.Join(snapshotsDir, "snapshot")
+
+	// export a snapshot of a ledger with public and private data
+	env := newTestEnv(t)
+	provider, _ := NewProvider()
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, _ := provider.Create(gb)
+	commitCollConfigForTest(t, ledger, bg, "ns", "coll")
+	blockAndPvtdata2 := prepareNextBlockForTest(t, ledger, bg, "SimulateForBlk2",
+		map[string]string{"key1": "value1.2", "key2": "value2.2"},
+		map[string]string{"key1": "pvtValue1.2", "key2": "pvtValue2.2"})
+	assert.NoError(t, ledger.CommitWithPvtData(blockAndPvtdata2))
+	blockAndPvtdata3 := prepareNextBlockForTest(t, ledger, bg, "SimulateForBlk3",
+		map[string]string{"key2": "value2.3"},
+		map[string]string{"key2": "pvtValue2.3"})
+	assert.NoError(t, ledger.CommitWithPvtData(blockAndPvtdata3))
+	bcInfo, _ := ledger.GetBlockchainInfo()
+
+	snapshotHash, err := ledger.ExportSnapshot(snapshotDir)
+	assert.NoError(t, err)
+	metadataBytes, err := ioutil.ReadFile(filepath.Join(snapshotDir, snapshotMetadataFileName))
+	assert.NoError(t, err)
+	expectedHash := sha256.Sum256(metadataBytes)
+	assert.Equal(t, expectedHash[:], snapshotHash)
+	_, err = ledger.ExportSnapshot(snapshotDir)
+	assert.EqualError(t, err, "snapshot directory ["+snapshotDir+"] already exists")
+	ledger.Close()
+	provider.Close()
+	env.cleanup()
+
+	// bootstrap a ledger on a fresh peer from the snapshot
+	env = newTestEnv(t)
+	defer env.cleanup()
+	provider, _ = NewProvider()
+	ledger, err = provider.CreateFromSnapshot(snapshotDir, nil)
+	assert.NoError(t, err)
+	actualBCInfo, _ := ledger.GetBlockchainInfo()
+	assert.Equal(t, bcInfo, actualBCInfo)
+	block, err := ledger.GetBlockByNumber(3)
+	assert.NoError(t, err)
+	assert.Equal(t, blockAndPvtdata3.Block.Header, block.Header)
+	_, err = ledger.GetBlockByNumber(2)
+	assert.IsType(t, &blkstorage.ErrBlockPruned{}, err)
+	// the config block of the snapshot is available
+	block, err = ledger.GetBlockByNumber(0)
+	assert.NoError(t, err)
+	assert.Equal(t, gb.Header, block.Header)
+
+	// the public state and the hashes of the private data are available, the private data is not
+	checkStateDBForTest(t, ledger, map[string]string{"key1": "value1.2", "key2": "value2.3"}, nil)
+	qe, _ := ledger.NewQueryExecutor()
+	_, err = qe.GetPrivateData("ns", "coll", "key2")
+	assert.Contains(t, err.Error(), "Private data matching public hash version is not available")
+	qe.Done()
+	valueHash, err := ledger.(*kvLedger).versionedDB.GetValueHash("ns", "coll", util.ComputeStringHash("key2"))
+	assert.NoError(t, err)
+	assert.Equal(t, util.ComputeHash([]byte("pvtValue2.3")), valueHash.Value)
+
+	// the blocks after the snapshot are committed in the normal course
+	blockAndPvtdata4 := prepareNextBlockForTest(t, ledger, bg, "SimulateForBlk4",
+		map[string]string{"key1": "value1.4"},
+		map[string]string{"key1": "pvtValue1.4"})
+	assert.NoError(t, ledger.CommitWithPvtData(blockAndPvtdata4))
+	checkStateDBForTest(t, ledger, map[string]string{"key1": "value1.4", "key2": "value2.3"},
+		map[string]string{"key1": "pvtValue1.4"})
+
+	_, err = provider.CreateFromSnapshot(snapshotDir, nil)
+	assert.Equal(t, ErrLedgerIDExists, err)
+	ledger.Close()
+	provider.Close()
+
+	// the bootstrapped ledger survives a restart
+	provider, _ = NewProvider()
+	defer provider.Close()
+	ledgerIDs, err := provider.List()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"testLedger"}, ledgerIDs)
+	ledger, err = provider.Open("testLedger")
+	assert.NoError(t, err)
+	defer ledger.Close()
+	block, err = ledger.GetBlockByNumber(0)
+	assert.NoError(t, err)
+	assert.Equal(t, gb.Header, block.Header)
+	block, err = ledger.GetBlockByNumber(4)
+	assert.NoError(t, err)
+	assert.Equal(t, blockAndPvtdata4.Block.Header, block.Header)
+	checkStateDBForTest(t, ledger, map[string]string{"key1": "value1.4", "key2": "value2.3"}, nil)
+}
+
+func TestCreateFromSnapshotRecovery(t *testing.T) {
+	snapshotsDir, err := ioutil.TempDir("", "snapshots")
+	assert.NoError(t, err)
+	defer os.RemoveAll(snapshotsDir)
+	snapshotDir := filepath.Join(snapshotsDir, "snapshot")
+	env := newTestEnv(t)
+	provider, _ := NewProvider()
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, _ := provider.Create(gb)
+	commitPubDataBlockForTest(t, ledger, bg, "key1", "value1.1")
+	_, err = ledger.ExportSnapshot(snapshotDir)
+	assert.NoError(t, err)
+	ledger.Close()
+	provider.Close()
+	env.cleanup()
+
+	env = newTestEnv(t)
+	defer env.cleanup()
+	provider, _ = NewProvider()
+	snapshot, err := openSnapshot(snapshotDir)
+	assert.NoError(t, err)
+	// simulate a crash after the ledger is bootstrapped but before it is marked as created
+	p := provider.(*Provider)
+	assert.NoError(t, p.idStore.setUnderConstructionFlag("testLedger"))
+	assert.NoError(t, p.idStore.setSnapshotConfigBlock("testLedger", snapshot.lastConfigBlock))
+	ledger, err = p.openInternal("testLedger")
+	assert.NoError(t, err)
+	assert.NoError(t, ledger.(*kvLedger).bootstrapFromSnapshot(snapshot))
+	ledger.Close()
+	provider.Close()
+
+	provider, _ = NewProvider()
+	defer provider.Close()
+	flag, err := provider.(*Provider).idStore.getUnderConstructionFlag()
+	assert.NoError(t, err)
+	assert.Equal(t, "", flag)
+	ledger, err = provider.Open("testLedger")
+	assert.NoError(t, err)
+	defer ledger.Close()
+	bcInfo, _ := ledger.GetBlockchainInfo()
+	assert.Equal(t, uint64(2), bcInfo.Height)
+	checkStateDBForTest(t, ledger, map[string]string{"key1": "value1.1"}, nil)
+}
+
+func TestOpenSnapshotVerification(t *testing.T) {
+	snapshotsDir, err := ioutil.TempDir("", "snapshots")
+	assert.NoError(t, err)
+	defer os.RemoveAll(snapshotsDir)
+	snapshotDir := filepath.Join(snapshotsDir, "snapshot")
+	env := newTestEnv(t)
+	defer env.cleanup()
+	provider, _ := NewProvider()
+	defer provider.Close()
+	bg, gb := testutil.NewBlockGenerator(t, "testLedger", false)
+	ledger, _ := provider.Create(gb)
+	defer ledger.Close()
+	commitPubDataBlockForTest(t, ledger, bg, "key1", "value1.1")
+	_, err = ledger.ExportSnapshot(snapshotDir)
+	assert.NoError(t, err)
+	snapshot, err := openSnapshot(snapshotDir)
+	assert.NoError(t, err)
+
+	// the transactions of the last block must match its header
+	snapshot.lastBlock.Data.Data[0] = []byte("tampered-tx")
+	assert.EqualError(t, snapshot.verifyBlocks(), "data hash of the last block of the snapshot does not match its header")
+
+	// the blocks are passed to the verifier before the ledger is created
+	_, err = provider.CreateFromSnapshot(snapshotDir, func(lastBlock, lastConfigBlock *common.Block) error {
+		assert.Equal(t, uint64(1), lastBlock.Header.Number)
+		assert.Equal(t, gb.Header, lastConfigBlock.Header)
+		return errors.New("bad signature")
+	})
+	assert.EqualError(t, err, "verification of the snapshot blocks failed: bad signature")
+
+	// a tampered state file is detected
+	stateFile := filepath.Join(snapshotDir, snapshotStateFileName)
+	assert.NoError(t, ioutil.WriteFile(stateFile, []byte("tampered-state"), 0644))
+	_, err = openSnapshot(snapshotDir)
+	assert.EqualError(t, err, "hash of snapshot file ["+snapshotStateFileName+"] does not match the snapshot metadata")
+
+	_, err = openSnapshot(filepath.Join(snapshotsDir, "non-existing"))
+	assert.Error(t, err)
+}
+
+func TestSnapshotKVEncoding(t *testing.T) {
+	kv := &privacyenabledstate.SnapshotKV{
+		Namespace:  "ns",
+		Collection: "coll",
+		Key:        string(util.ComputeStringHash("key")),
+		VersionedValue: statedb.VersionedValue{
+			Value:    []byte("value"),
+			Metadata: []byte("metadata"),
+			Version:  version.NewHeight(10, 20),
+		},
+	}
+	b, err := encodeSnapshotKV(kv)
+	assert.NoError(t, err)
+	decodedKV, err := decodeSnapshotKV(b)
+	assert.NoError(t, err)
+	assert.Equal(t, kv, decodedKV)
+
+	kv.Collection, kv.Metadata = "", nil
+	b, err = encodeSnapshotKV(kv)
+	assert.NoError(t, err)
+	decodedKV, err = decodeSnapshotKV(b)
+	assert.NoError(t, err)
+	assert.Equal(t, kv, decodedKV)
+}
+
+func commitPubDataBlockForTest(t *testing.T, l lgr.PeerLedger, bg *testutil.BlockGenerator, key, value string) {
+	blockAndPvtdata := prepareNextBlockForTest(t, l, bg, commonutil.GenerateUUID(), map[string]string{key: value}, nil)
+	blockAndPvtdata.BlockPvtData = nil
+	assert.NoError(t, l.CommitWithPvtData(blockAndPvtdata))
+}
@@ -9,7 +9,10 @@ package privacyenabledstate
 import (
 	"encoding/base64"
 	"fmt"
+	"math"
 
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
 
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
@@ -17,8 +20,12 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb/stateleveldb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/ledger/pvtdatapolicy"
+	"github.com/hyperledger/fabric/core/ledger/util"
 )
 
+var logger = flogging.MustGetLogger("privacyenabledstate")
+
 const (
 	nsJoiner       = "$$"
 	pvtDataPrefix  = "p"
@@ -28,6 +35,7 @@ const (
 // CommonStorageDBProvider implements interface DBProvider
 type CommonStorageDBProvider struct {
 	statedb.VersionedDBProvider
+	bookkeepingProvider *leveldbhelper.Provider
 }
 
 // NewCommonStorageDBProvider constructs an instance of DBProvider
@@ -41,7 +49,8 @@ func NewCommonStorageDBProvider() (DBProvider, error) {
 	} else {
 		vdbProvider = stateleveldb.NewVersionedDBProvider()
 	}
-	return &CommonStorageDBProvider{vdbProvider}, nil
+	bookkeepingProvider := leveldbhelper.NewProvider(&leveldbhelper.Conf{DBPath: ledgerconfig.GetInternalBookkeeperPath()})
+	return &CommonStorageDBProvider{vdbProvider, bookkeepingProvider}, nil
 }
 
 // GetDBHandle implements function from interface DBProvider
@@ -50,24 +59,33 @@ func (p *CommonStorageDBProvider) GetDBHandle(id string) (DB, error) {
 	if err != nil {
 		return nil, err
 	}
-	return NewCommonStorageDB(vdb, id)
+	return NewCommonStorageDB(vdb, id, p.bookkeepingProvider.GetDBHandle(id))
 }
 
 // Close implements function from interface DBProvider
 func (p *CommonStorageDBProvider) Close() {
 	p.VersionedDBProvider.Close()
+	p.bookkeepingProvider.Close()
 }
 
 // CommonStorageDB implements interface DB. This implementation uses a single database to maintain
 // both the public and private data
 type CommonStorageDB struct {
 	statedb.VersionedDB
+	btlPolicy    pvtdatapolicy.BTLPolicy
+	expiryKeeper *expiryKeeper
 }
 
 // NewCommonStorageDB wraps a VersionedDB instance. The public data is managed directly by the wrapped versionedDB.
-// For managing the hashed data and private data, this implementation creates separate namespaces in the wrapped db
-func NewCommonStorageDB(vdb statedb.VersionedDB, ledgerid string) (DB, error) {
-	return &CommonStorageDB{VersionedDB: vdb}, nil
+// For managing the hashed data and private data, this implementation creates separate namespaces in the wrapped db.
+// The expiry of the private data is tracked in the supplied bookkeeping db
+func NewCommonStorageDB(vdb statedb.VersionedDB, ledgerid string, bookkeepingDB *leveldbhelper.DBHandle) (DB, error) {
+	return &CommonStorageDB{VersionedDB: vdb, expiryKeeper: newExpiryKeeper(bookkeepingDB)}, nil
+}
+
+// Init implements corresponding function in interface DB
+func (s *CommonStorageDB) Init(btlPolicy pvtdatapolicy.BTLPolicy) {
+	s.btlPolicy = btlPolicy
 }
 
 // IsBulkOptimizable implements corresponding function in interface DB
@@ -184,11 +202,166 @@ func (s *CommonStorageDB) ApplyUpdates(batch *statedb.UpdateBatch, height *versi
 	return fmt.Errorf("This function should not be invoked on this type. Please invoke function 'ApplyPrivacyAwareUpdates'")
 }
 
-// ApplyPrivacyAwareUpdates implements corresponding function in interface DB
+// ApplyPrivacyAwareUpdates implements corresponding function in interface DB.
+// In addition to the supplied updates, the private keys and the key hashes that expire at the
+// block being committed are deleted
 func (s *CommonStorageDB) ApplyPrivacyAwareUpdates(updates *UpdateBatch, height *version.Height) error {
+	expired, err := s.addExpiredKeysDeletes(updates, height)
+	if err != nil {
+		return err
+	}
+	toTrack, err := s.expiryInfoForUpdates(updates, height.BlockNum)
+	if err != nil {
+		return err
+	}
+	// The new entries are persisted before the updates so that they are not lost in a crash.
+	// The expired entries are removed only after the updates, if a crash happens before that, the block
+	// is recommitted during recovery and the expired entries are processed again
+	if len(toTrack) > 0 {
+		if err := s.expiryKeeper.update(toTrack, nil); err != nil {
+			return err
+		}
+	}
 	addPvtUpdates(updates.PubUpdates, updates.PvtUpdates)
 	addHashedUpdates(updates.PubUpdates, updates.HashUpdates, !s.BytesKeySuppoted())
-	return s.VersionedDB.ApplyUpdates(updates.PubUpdates.UpdateBatch, height)
+	if err := s.VersionedDB.ApplyUpdates(updates.PubUpdates.UpdateBatch, height); err != nil {
+		return err
+	}
+	if len(expired) > 0 {
+		return s.expiryKeeper.update(nil, expired)
+	}
+	return nil
+}
+
+// ApplyPvtUpdatesOfOldBlocks implements corresponding function in interface DB
+func (s *CommonStorageDB) ApplyPvtUpdatesOfOldBlocks(pvtUpdates *PvtUpdateBatch) error {
+	savepoint, err := s.GetLatestSavePoint()
+	if err != nil {
+		return err
+	}
+	if savepoint == nil {
+		return fmt.Errorf("no block has been committed to the state db")
+	}
+	toTrack, err := s.expiryInfoForPvtUpdates(pvtUpdates)
+	if err != nil {
+		return err
+	}
+	// an expiry entry is already present for the key hash, the entry is overwritten with the one that includes the key
+	if len(toTrack) > 0 {
+		if err := s.expiryKeeper.update(toTrack, nil); err != nil {
+			return err
+		}
+	}
+	pubUpdates := NewPubUpdateBatch()
+	addPvtUpdates(pubUpdates, pvtUpdates)
+	return s.VersionedDB.ApplyUpdates(pubUpdates.UpdateBatch, savepoint)
+}
+
+// addExpiredKeysDeletes adds to the batch the deletes for the private keys and the key hashes that expire
+// at the block being committed. A key is not deleted if it has been updated after the expiring write because
+// the latest write is tracked independently
+func (s *CommonStorageDB) addExpiredKeysDeletes(updates *UpdateBatch, height *version.Height) ([]*expiryInfo, error) {
+	expired, err := s.expiryKeeper.retrieve(height.BlockNum)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range expired {
+		if !updates.HashUpdates.Contains(e.ns, e.coll, e.keyHash) {
+			keyHashVersion, err := s.GetKeyHashVersion(e.ns, e.coll, e.keyHash)
+			if err != nil {
+				return nil, err
+			}
+			if keyHashVersion != nil && keyHashVersion.BlockNum == e.committingBlk {
+				updates.HashUpdates.Delete(e.ns, e.coll, e.keyHash, height)
+			}
+		}
+		if e.key == "" || updates.PvtUpdates.Contains(e.ns, e.coll, e.key) {
+			continue
+		}
+		vv, err := s.GetPrivateData(e.ns, e.coll, e.key)
+		if err != nil {
+			return nil, err
+		}
+		if vv != nil && vv.Version.BlockNum == e.committingBlk {
+			updates.PvtUpdates.Delete(e.ns, e.coll, e.key, height)
+		}
+	}
+	return expired, nil
+}
+
+// expiryInfoForUpdates returns the expiry entries for the key hashes written in the block being committed.
+// The writes of the collections that do not have a BlockToLive configured never expire
+func (s *CommonStorageDB) expiryInfoForUpdates(updates *UpdateBatch, committingBlk uint64) ([]*expiryInfo, error) {
+	if s.btlPolicy == nil {
+		return nil, nil
+	}
+	var toTrack []*expiryInfo
+	for ns, nsBatch := range updates.HashUpdates.UpdateMap {
+		for _, coll := range nsBatch.GetCollectionNames() {
+			expiringBlk, err := s.btlPolicy.GetExpiringBlock(ns, coll, committingBlk)
+			if err != nil {
+				return nil, err
+			}
+			if expiringBlk == math.MaxUint64 {
+				continue
+			}
+			keysByHash := make(map[string]string)
+			if pvtNsBatch, ok := updates.PvtUpdates.UpdateMap[ns]; ok {
+				for key := range pvtNsBatch.GetUpdates(coll) {
+					keysByHash[string(util.ComputeStringHash(key))] = key
+				}
+			}
+			for keyHash, vv := range nsBatch.GetUpdates(coll) {
+				if vv.Value == nil {
+					continue
+				}
+				toTrack = append(toTrack, &expiryInfo{
+					expiringBlk:   expiringBlk,
+					committingBlk: committingBlk,
+					ns:            ns,
+					coll:          coll,
+					keyHash:       []byte(keyHash),
+					key:           keysByHash[keyHash],
+				})
+			}
+		}
+	}
+	return toTrack, nil
+}
+
+// expiryInfoForPvtUpdates returns the expiry entries for the private keys written by the transactions of already
+// committed blocks. The committing block of a key is derived from the version of the key
+func (s *CommonStorageDB) expiryInfoForPvtUpdates(pvtUpdates *PvtUpdateBatch) ([]*expiryInfo, error) {
+	if s.btlPolicy == nil {
+		return nil, nil
+	}
+	var toTrack []*expiryInfo
+	for ns, nsBatch := range pvtUpdates.UpdateMap {
+		for _, coll := range nsBatch.GetCollectionNames() {
+			for key, vv := range nsBatch.GetUpdates(coll) {
+				if vv.Value == nil {
+					continue
+				}
+				committingBlk := vv.Version.BlockNum
+				expiringBlk, err := s.btlPolicy.GetExpiringBlock(ns, coll, committingBlk)
+				if err != nil {
+					return nil, err
+				}
+				if expiringBlk == math.MaxUint64 {
+					continue
+				}
+				toTrack = append(toTrack, &expiryInfo{
+					expiringBlk:   expiringBlk,
+					committingBlk: committingBlk,
+					ns:            ns,
+					coll:          coll,
+					keyHash:       util.ComputeStringHash(key),
+					key:           key,
+				})
+			}
+		}
+	}
+	return toTrack, nil
 }
 
 func derivePvtDataNs(namespace, collection string) string {
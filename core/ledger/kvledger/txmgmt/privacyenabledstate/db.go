@@ -10,6 +10,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/cceventmgmt"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/pvtdatapolicy"
 )
 
 // DBProvider provides handle to a PvtVersionedDB
@@ -23,6 +24,9 @@ type DBProvider interface {
 // DB extends VersionedDB interface. This interface provides additional functions for managing private data state
 type DB interface {
 	statedb.VersionedDB
+	// Init sets the BlockToLive policy as per which the private keys and the key hashes expire.
+	// If no policy is set, the private data never expires
+	Init(btlPolicy pvtdatapolicy.BTLPolicy)
 	IsBulkOptimizable() bool
 	LoadCommittedVersionsOfPubAndHashedKeys(pubKeys []*statedb.CompositeKey, hashedKeys []*HashedCompositeKey) error
 	GetCachedKeyHashVersion(namespace, collection string, keyHash []byte) (*version.Height, bool)
@@ -35,6 +39,17 @@ type DB interface {
 	GetPrivateDataRangeScanIterator(namespace, collection, startKey, endKey string) (statedb.ResultsIterator, error)
 	ExecuteQueryOnPrivateData(namespace, collection, query string) (statedb.ResultsIterator, error)
 	ApplyPrivacyAwareUpdates(updates *UpdateBatch, height *version.Height) error
+	// ApplyPvtUpdatesOfOldBlocks applies the updates to the private keys that were missing at the time of
+	// the commit of the corresponding blocks. The savepoint of the db is not changed and the expiry of the
+	// private keys is tracked as per the blocks in which the keys were committed
+	ApplyPvtUpdatesOfOldBlocks(pvtUpdates *PvtUpdateBatch) error
+	// GetPubAndHashedStateIterator returns an iterator over the public state and the hashed state of all the
+	// collections, which is exported as a part of a ledger snapshot.
+	// The returned ResultsIterator contains results of type *SnapshotKV
+	GetPubAndHashedStateIterator() (statedb.ResultsIterator, error)
+	// ImportPubAndHashedState loads the entries returned by the iterator (of type *SnapshotKV) into an empty db
+	// and sets the savepoint to the given height
+	ImportPubAndHashedState(itr statedb.ResultsIterator, savepoint *version.Height) error
 }
 
 // HashedCompositeKey encloses Namespace, CollectionName and KeyHash components
@@ -105,6 +120,11 @@ func (b UpdateMap) Put(ns, coll, key string, value []byte, version *version.Heig
 	b.getOrCreateNsBatch(ns).Put(coll, key, value, version)
 }
 
+// PutValAndMetadata sets the value and the metadata in the batch for a given combination of namespace and collection name
+func (b UpdateMap) PutValAndMetadata(ns, coll, key string, value []byte, metadata []byte, version *version.Height) {
+	b.getOrCreateNsBatch(ns).PutValAndMetadata(coll, key, value, metadata, version)
+}
+
 // Delete removes the entry from the batch for a given combination of namespace and collection name
 func (b UpdateMap) Delete(ns, coll, key string, version *version.Height) {
 	b.getOrCreateNsBatch(ns).Delete(coll, key, version)
@@ -132,6 +152,15 @@ func (b UpdateMap) getOrCreateNsBatch(ns string) nsBatch {
 	return batch
 }
 
+// Contains returns true if the given <ns,coll,key> tuple is present in the batch
+func (b PvtUpdateBatch) Contains(ns, coll, key string) bool {
+	nsBatch, ok := b.UpdateMap[ns]
+	if !ok {
+		return false
+	}
+	return nsBatch.Exists(coll, key)
+}
+
 // Contains returns true if the given <ns,coll,keyHash> tuple is present in the batch
 func (h HashedUpdateBatch) Contains(ns, coll string, keyHash []byte) bool {
 	nsBatch, ok := h.UpdateMap[ns]
@@ -146,6 +175,11 @@ func (h HashedUpdateBatch) Put(ns, coll string, key []byte, value []byte, versio
 	h.UpdateMap.Put(ns, coll, string(key), value, version)
 }
 
+// PutValHashAndMetadata adds a key with the hash of its value and its metadata to the batch
+func (h HashedUpdateBatch) PutValHashAndMetadata(ns, coll string, key []byte, valueHash []byte, metadata []byte, version *version.Height) {
+	h.UpdateMap.PutValAndMetadata(ns, coll, string(key), valueHash, metadata, version)
+}
+
 // Delete overrides the function in UpdateMap for allowing the key to be a []byte instead of a string
 func (h HashedUpdateBatch) Delete(ns, coll string, key []byte, version *version.Height) {
 	h.UpdateMap.Delete(ns, coll, string(key), version)
@@ -15,6 +15,7 @@ import (
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	btltestutil "github.com/hyperledger/fabric/core/ledger/pvtdatapolicy/testutil"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -131,6 +132,73 @@ func testDB(t *testing.T, env TestEnv) {
 
 //TODO add tests for functions GetPrivateStateMultipleKeys and GetPrivateStateRangeScanIterator
 
+func TestPvtDataExpiry(t *testing.T) {
+	for _, env := range testEnvs {
+		t.Run(env.GetName(), func(t *testing.T) {
+			testPvtDataExpiry(t, env)
+		})
+	}
+}
+
+func testPvtDataExpiry(t *testing.T, env TestEnv) {
+	env.Init(t)
+	defer env.Cleanup()
+	db := env.GetDBHandle("test-ledger-pvtdata-expiry")
+	db.Init(btltestutil.SampleBTLPolicy(
+		map[[2]string]uint64{
+			{"ns1", "coll1"}: 1,
+		},
+	))
+
+	// block 1 writes the keys of a collection with BTL and of a collection without BTL.
+	// The pvt data of key4 is not available to this peer
+	batch := NewUpdateBatch()
+	putPvtAndHashUpdates(t, batch, "ns1", "coll1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	putPvtAndHashUpdates(t, batch, "ns1", "coll1", "key2", []byte("value2"), version.NewHeight(1, 2))
+	putPvtAndHashUpdates(t, batch, "ns1", "coll2", "key3", []byte("value3"), version.NewHeight(1, 3))
+	batch.HashUpdates.Put("ns1", "coll1", util.ComputeStringHash("key4"), util.ComputeHash([]byte("value4")), version.NewHeight(1, 4))
+	assert.NoError(t, db.ApplyPrivacyAwareUpdates(batch, version.NewHeight(1, 4)))
+
+	// block 2 updates key2, which delays its expiry
+	batch = NewUpdateBatch()
+	putPvtAndHashUpdates(t, batch, "ns1", "coll1", "key2", []byte("value2-new"), version.NewHeight(2, 1))
+	assert.NoError(t, db.ApplyPrivacyAwareUpdates(batch, version.NewHeight(2, 1)))
+	assertPvtDataAndHash(t, db, "ns1", "coll1", "key1", []byte("value1"))
+
+	// the writes of block 1 in the collection with BTL expire at block 3
+	assert.NoError(t, db.ApplyPrivacyAwareUpdates(NewUpdateBatch(), version.NewHeight(3, 1)))
+	assertPvtDataAndHash(t, db, "ns1", "coll1", "key1", nil)
+	assertPvtDataAndHash(t, db, "ns1", "coll1", "key2", []byte("value2-new"))
+	assertPvtDataAndHash(t, db, "ns1", "coll2", "key3", []byte("value3"))
+	vv, err := db.GetValueHash("ns1", "coll1", util.ComputeStringHash("key4"))
+	assert.NoError(t, err)
+	assert.Nil(t, vv)
+
+	// the write of block 2 expires at block 4
+	assert.NoError(t, db.ApplyPrivacyAwareUpdates(NewUpdateBatch(), version.NewHeight(4, 1)))
+	assertPvtDataAndHash(t, db, "ns1", "coll1", "key2", nil)
+	assertPvtDataAndHash(t, db, "ns1", "coll2", "key3", []byte("value3"))
+}
+
+func putPvtAndHashUpdates(t *testing.T, batch *UpdateBatch, ns, coll, key string, value []byte, ver *version.Height) {
+	batch.PvtUpdates.Put(ns, coll, key, value, ver)
+	batch.HashUpdates.Put(ns, coll, util.ComputeStringHash(key), util.ComputeHash(value), ver)
+}
+
+func assertPvtDataAndHash(t *testing.T, db DB, ns, coll, key string, expectedValue []byte) {
+	vv, err := db.GetPrivateData(ns, coll, key)
+	assert.NoError(t, err)
+	hashVV, err := db.GetValueHash(ns, coll, util.ComputeStringHash(key))
+	assert.NoError(t, err)
+	if expectedValue == nil {
+		assert.Nil(t, vv)
+		assert.Nil(t, hashVV)
+		return
+	}
+	assert.Equal(t, expectedValue, vv.Value)
+	assert.Equal(t, util.ComputeHash(expectedValue), hashVV.Value)
+}
+
 func TestGetStateMultipleKeys(t *testing.T) {
 	for _, env := range testEnvs {
 		t.Run(env.GetName(), func(t *testing.T) {
@@ -0,0 +1,98 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privacyenabledstate
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/hyperledger/fabric/common/ledger/util"
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+)
+
+var compositeKeySep = []byte{0x00}
+
+// expiryInfo identifies a private key (and its hash) committed in the block `committingBlk`
+// that expires when the block `expiringBlk` is committed. The `key` is empty if the
+// private data was not available to this peer at the time of the commit
+type expiryInfo struct {
+	expiringBlk   uint64
+	committingBlk uint64
+	ns            string
+	coll          string
+	keyHash       []byte
+	key           string
+}
+
+// expiryKeeper keeps track of the private keys and the key hashes in the state db that expire at a given block.
+// The entries are persisted in a separate leveldb, keyed by the expiring block so that the entries that
+// expire at a block can be retrieved with a range scan
+type expiryKeeper struct {
+	db *leveldbhelper.DBHandle
+}
+
+func newExpiryKeeper(db *leveldbhelper.DBHandle) *expiryKeeper {
+	return &expiryKeeper{db}
+}
+
+// update persists the entries in `toTrack` and removes the entries in `toClear` in a single batch
+func (ek *expiryKeeper) update(toTrack []*expiryInfo, toClear []*expiryInfo) error {
+	batch := leveldbhelper.NewUpdateBatch()
+	for _, e := range toTrack {
+		batch.Put(encodeExpiryInfoKey(e), []byte(e.key))
+	}
+	for _, e := range toClear {
+		batch.Delete(encodeExpiryInfoKey(e))
+	}
+	return ek.db.WriteBatch(batch, true)
+}
+
+// retrieve returns the entries that expire when the given block is committed
+func (ek *expiryKeeper) retrieve(expiringBlk uint64) ([]*expiryInfo, error) {
+	startKey := util.EncodeOrderPreservingVarUint64(expiringBlk)
+	endKey := util.EncodeOrderPreservingVarUint64(expiringBlk + 1)
+	itr := ek.db.GetIterator(startKey, endKey)
+	defer itr.Release()
+
+	var expiryInfos []*expiryInfo
+	for itr.Next() {
+		e, err := decodeExpiryInfo(itr.Key(), itr.Value())
+		if err != nil {
+			return nil, err
+		}
+		expiryInfos = append(expiryInfos, e)
+	}
+	return expiryInfos, nil
+}
+
+func encodeExpiryInfoKey(e *expiryInfo) []byte {
+	key := util.EncodeOrderPreservingVarUint64(e.expiringBlk)
+	key = append(key, util.EncodeOrderPreservingVarUint64(e.committingBlk)...)
+	key = append(key, []byte(e.ns)...)
+	key = append(key, compositeKeySep...)
+	key = append(key, []byte(e.coll)...)
+	key = append(key, compositeKeySep...)
+	// the key hash may contain the separator byte, hence it is kept as the last component
+	return append(key, e.keyHash...)
+}
+
+func decodeExpiryInfo(keyBytes, valueBytes []byte) (*expiryInfo, error) {
+	expiringBlk, n1 := util.DecodeOrderPreservingVarUint64(keyBytes)
+	committingBlk, n2 := util.DecodeOrderPreservingVarUint64(keyBytes[n1:])
+	split := bytes.SplitN(keyBytes[n1+n2:], compositeKeySep, 3)
+	if len(split) != 3 {
+		return nil, fmt.Errorf("invalid expiry entry key [%#v]", keyBytes)
+	}
+	return &expiryInfo{
+		expiringBlk:   expiringBlk,
+		committingBlk: committingBlk,
+		ns:            string(split[0]),
+		coll:          string(split[1]),
+		keyHash:       append([]byte{}, split[2]...),
+		key:           string(valueBytes),
+	}, nil
+}
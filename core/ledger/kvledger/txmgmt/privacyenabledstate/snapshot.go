@@ -0,0 +1,198 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privacyenabledstate
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+)
+
+// maxSnapshotImportBatchSize is the number of entries that are loaded in the state db in a single batch
+// while importing a snapshot
+var maxSnapshotImportBatchSize = 1000
+
+// SnapshotKV is an entry of the public state or of the hashed state of a collection, as exported in a
+// ledger snapshot. The `Collection` is empty for an entry of the public state and the `Key` is the hash
+// of the private key for an entry of the hashed state
+type SnapshotKV struct {
+	Namespace  string
+	Collection string
+	Key        string
+	statedb.VersionedValue
+}
+
+// GetPubAndHashedStateIterator implements corresponding function in interface DB. The private data is not
+// included. All the public entries are returned before the hashed entries so that, while importing, the
+// collection configurations are loaded before computing the expiry of the key hashes
+func (s *CommonStorageDB) GetPubAndHashedStateIterator() (statedb.ResultsIterator, error) {
+	fullScannable, ok := s.VersionedDB.(statedb.FullScannable)
+	if !ok {
+		return nil, fmt.Errorf("the state database does not support exporting a snapshot")
+	}
+	pubItr, err := fullScannable.GetFullScanIterator(func(ns string) bool {
+		_, _, _, isDerived := decodeDerivedNs(ns)
+		return isDerived
+	})
+	if err != nil {
+		return nil, err
+	}
+	hashedItr, err := fullScannable.GetFullScanIterator(func(ns string) bool {
+		_, prefix, _, isDerived := decodeDerivedNs(ns)
+		return !isDerived || prefix != hashDataPrefix
+	})
+	if err != nil {
+		pubItr.Close()
+		return nil, err
+	}
+	return &snapshotItr{
+		itrs:      []statedb.ResultsIterator{pubItr, hashedItr},
+		base64Key: !s.BytesKeySuppoted(),
+	}, nil
+}
+
+// ImportPubAndHashedState implements corresponding function in interface DB
+func (s *CommonStorageDB) ImportPubAndHashedState(itr statedb.ResultsIterator, savepoint *version.Height) error {
+	existingSavepoint, err := s.GetLatestSavePoint()
+	if err != nil {
+		return err
+	}
+	if existingSavepoint != nil {
+		return fmt.Errorf("the state database is not empty, savepoint = %#v", existingSavepoint)
+	}
+	batch := NewUpdateBatch()
+	numEntries := 0
+	for {
+		res, err := itr.Next()
+		if err != nil {
+			return err
+		}
+		if res == nil {
+			break
+		}
+		kv := res.(*SnapshotKV)
+		if kv.Collection == "" {
+			batch.PubUpdates.PutValAndMetadata(kv.Namespace, kv.Key, kv.Value, kv.Metadata, kv.Version)
+		} else {
+			batch.HashUpdates.PutValHashAndMetadata(kv.Namespace, kv.Collection, []byte(kv.Key), kv.Value, kv.Metadata, kv.Version)
+		}
+		numEntries++
+		if numEntries%maxSnapshotImportBatchSize == 0 {
+			if err := s.applySnapshotBatch(batch, savepoint); err != nil {
+				return err
+			}
+			batch = NewUpdateBatch()
+		}
+	}
+	// the last batch is applied even if empty so that the savepoint is always set
+	if err := s.applySnapshotBatch(batch, savepoint); err != nil {
+		return err
+	}
+	logger.Infof("Imported [%d] entries of public and hashed state at savepoint %#v", numEntries, savepoint)
+	return nil
+}
+
+// applySnapshotBatch loads a batch of snapshot entries in the state db. Unlike a block commit, the key hashes
+// expire as per the blocks in which they were originally committed, which is derived from their versions.
+// The expiry entries are computed after the batch is applied, as the batch may carry the collection configurations
+func (s *CommonStorageDB) applySnapshotBatch(batch *UpdateBatch, savepoint *version.Height) error {
+	addHashedUpdates(batch.PubUpdates, batch.HashUpdates, !s.BytesKeySuppoted())
+	if err := s.VersionedDB.ApplyUpdates(batch.PubUpdates.UpdateBatch, savepoint); err != nil {
+		return err
+	}
+	toTrack, err := s.expiryInfoForSnapshotBatch(batch.HashUpdates, savepoint.BlockNum)
+	if err != nil || len(toTrack) == 0 {
+		return err
+	}
+	return s.expiryKeeper.update(toTrack, nil)
+}
+
+func (s *CommonStorageDB) expiryInfoForSnapshotBatch(hashUpdates *HashedUpdateBatch, lastBlockNum uint64) ([]*expiryInfo, error) {
+	if s.btlPolicy == nil {
+		return nil, nil
+	}
+	var toTrack []*expiryInfo
+	for ns, nsBatch := range hashUpdates.UpdateMap {
+		for _, coll := range nsBatch.GetCollectionNames() {
+			for keyHash, vv := range nsBatch.GetUpdates(coll) {
+				committingBlk := vv.Version.BlockNum
+				expiringBlk, err := s.btlPolicy.GetExpiringBlock(ns, coll, committingBlk)
+				if err != nil {
+					return nil, err
+				}
+				if expiringBlk == math.MaxUint64 || expiringBlk <= lastBlockNum {
+					continue
+				}
+				toTrack = append(toTrack, &expiryInfo{
+					expiringBlk:   expiringBlk,
+					committingBlk: committingBlk,
+					ns:            ns,
+					coll:          coll,
+					keyHash:       []byte(keyHash),
+				})
+			}
+		}
+	}
+	return toTrack, nil
+}
+
+// snapshotItr chains the iterators over the public state and the hashed state and converts
+// the entries of the derived namespaces of the hashed state into `SnapshotKV`s
+type snapshotItr struct {
+	itrs      []statedb.ResultsIterator
+	base64Key bool
+}
+
+func (itr *snapshotItr) Next() (statedb.QueryResult, error) {
+	for len(itr.itrs) > 0 {
+		res, err := itr.itrs[0].Next()
+		if err != nil {
+			return nil, err
+		}
+		if res == nil {
+			itr.itrs[0].Close()
+			itr.itrs = itr.itrs[1:]
+			continue
+		}
+		kv := res.(*statedb.VersionedKV)
+		ns, coll, key := kv.Namespace, "", kv.Key
+		if chaincodeNs, _, collection, isDerived := decodeDerivedNs(ns); isDerived {
+			ns, coll = chaincodeNs, collection
+			if itr.base64Key {
+				keyHash, err := base64.StdEncoding.DecodeString(key)
+				if err != nil {
+					return nil, err
+				}
+				key = string(keyHash)
+			}
+		}
+		return &SnapshotKV{Namespace: ns, Collection: coll, Key: key, VersionedValue: kv.VersionedValue}, nil
+	}
+	return nil, nil
+}
+
+func (itr *snapshotItr) Close() {
+	for _, i := range itr.itrs {
+		i.Close()
+	}
+	itr.itrs = nil
+}
+
+// decodeDerivedNs splits a namespace derived for the private data or the hashed data of a collection
+// into the chaincode namespace and the collection name. The prefix identifies the kind of data
+func decodeDerivedNs(derivedNs string) (ns, prefix, coll string, isDerived bool) {
+	idx := strings.Index(derivedNs, nsJoiner)
+	if idx < 0 || len(derivedNs) <= idx+len(nsJoiner) {
+		return "", "", "", false
+	}
+	prefixAndColl := derivedNs[idx+len(nsJoiner):]
+	return derivedNs[:idx], prefixAndColl[:1], prefixAndColl[1:], true
+}
@@ -0,0 +1,130 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privacyenabledstate
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	btltestutil "github.com/hyperledger/fabric/core/ledger/pvtdatapolicy/testutil"
+	"github.com/hyperledger/fabric/core/ledger/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnapshotExportImport(t *testing.T) {
+	env := &LevelDBCommonStorageTestEnv{}
+	env.Init(t)
+	defer env.Cleanup()
+	btlPolicy := btltestutil.SampleBTLPolicy(
+		map[[2]string]uint64{
+			{"ns1", "coll1"}: 2,
+		},
+	)
+	db := env.GetDBHandle("test-ledger-snapshot-export")
+	db.Init(btlPolicy)
+
+	batch := NewUpdateBatch()
+	batch.PubUpdates.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	batch.PubUpdates.PutValAndMetadata("ns2", "key1", []byte("value2"), []byte("metadata"), version.NewHeight(1, 2))
+	putPvtAndHashUpdates(t, batch, "ns1", "coll1", "key1", []byte("pvt-value1"), version.NewHeight(1, 3))
+	putPvtAndHashUpdates(t, batch, "ns1", "coll2", "key2", []byte("pvt-value2"), version.NewHeight(1, 4))
+	assert.NoError(t, db.ApplyPrivacyAwareUpdates(batch, version.NewHeight(1, 4)))
+	batch = NewUpdateBatch()
+	putPvtAndHashUpdates(t, batch, "ns1", "coll1", "key3", []byte("pvt-value3"), version.NewHeight(2, 1))
+	assert.NoError(t, db.ApplyPrivacyAwareUpdates(batch, version.NewHeight(2, 1)))
+
+	itr, err := db.GetPubAndHashedStateIterator()
+	assert.NoError(t, err)
+	var exported []*SnapshotKV
+	for {
+		res, err := itr.Next()
+		assert.NoError(t, err)
+		if res == nil {
+			break
+		}
+		exported = append(exported, res.(*SnapshotKV))
+	}
+	itr.Close()
+	// the public entries precede the hashed entries and the private data is not exported
+	assert.Len(t, exported, 5)
+	assert.Equal(t, &SnapshotKV{Namespace: "ns1", Key: "key1",
+		VersionedValue: statedb.VersionedValue{Value: []byte("value1"), Version: version.NewHeight(1, 1)}}, exported[0])
+	assert.Equal(t, "ns2", exported[1].Namespace)
+	assert.Equal(t, []byte("metadata"), exported[1].Metadata)
+	for _, kv := range exported[2:] {
+		assert.Equal(t, "ns1", kv.Namespace)
+		assert.NotEmpty(t, kv.Collection)
+	}
+
+	importedDB := env.GetDBHandle("test-ledger-snapshot-import")
+	importedDB.Init(btlPolicy)
+	assert.NoError(t, importedDB.ImportPubAndHashedState(&sliceItr{exported}, version.NewHeight(2, 1)))
+	savepoint, err := importedDB.GetLatestSavePoint()
+	assert.NoError(t, err)
+	assert.Equal(t, version.NewHeight(2, 1), savepoint)
+	vv, err := importedDB.GetState("ns2", "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value2"), vv.Value)
+	assert.Equal(t, []byte("metadata"), vv.Metadata)
+	for _, key := range []string{"key1", "key2", "key3"} {
+		coll := "coll1"
+		if key == "key2" {
+			coll = "coll2"
+		}
+		hashVV, err := importedDB.GetValueHash("ns1", coll, util.ComputeStringHash(key))
+		assert.NoError(t, err)
+		assert.NotNil(t, hashVV)
+		pvtVV, err := importedDB.GetPrivateData("ns1", coll, key)
+		assert.NoError(t, err)
+		assert.Nil(t, pvtVV)
+	}
+	// a snapshot can only be imported in an empty db
+	assert.Error(t, importedDB.ImportPubAndHashedState(&sliceItr{exported}, version.NewHeight(2, 1)))
+
+	// the imported key hashes expire as per the blocks in which they were committed
+	assert.NoError(t, importedDB.ApplyPrivacyAwareUpdates(NewUpdateBatch(), version.NewHeight(4, 1)))
+	hashVV, err := importedDB.GetValueHash("ns1", "coll1", util.ComputeStringHash("key1"))
+	assert.NoError(t, err)
+	assert.Nil(t, hashVV)
+	hashVV, err = importedDB.GetValueHash("ns1", "coll1", util.ComputeStringHash("key3"))
+	assert.NoError(t, err)
+	assert.NotNil(t, hashVV)
+	assert.NoError(t, importedDB.ApplyPrivacyAwareUpdates(NewUpdateBatch(), version.NewHeight(5, 1)))
+	hashVV, err = importedDB.GetValueHash("ns1", "coll1", util.ComputeStringHash("key3"))
+	assert.NoError(t, err)
+	assert.Nil(t, hashVV)
+	hashVV, err = importedDB.GetValueHash("ns1", "coll2", util.ComputeStringHash("key2"))
+	assert.NoError(t, err)
+	assert.NotNil(t, hashVV)
+}
+
+func TestDecodeDerivedNs(t *testing.T) {
+	ns, prefix, coll, isDerived := decodeDerivedNs(derivePvtDataNs("ns1", "coll1"))
+	assert.True(t, isDerived)
+	assert.Equal(t, []string{"ns1", pvtDataPrefix, "coll1"}, []string{ns, prefix, coll})
+	ns, prefix, coll, isDerived = decodeDerivedNs(deriveHashedDataNs("ns1", "coll1"))
+	assert.True(t, isDerived)
+	assert.Equal(t, []string{"ns1", hashDataPrefix, "coll1"}, []string{ns, prefix, coll})
+	_, _, _, isDerived = decodeDerivedNs("ns1")
+	assert.False(t, isDerived)
+}
+
+type sliceItr struct {
+	kvs []*SnapshotKV
+}
+
+func (itr *sliceItr) Next() (statedb.QueryResult, error) {
+	if len(itr.kvs) == 0 {
+		return nil, nil
+	}
+	kv := itr.kvs[0]
+	itr.kvs = itr.kvs[1:]
+	return kv, nil
+}
+
+func (itr *sliceItr) Close() {}
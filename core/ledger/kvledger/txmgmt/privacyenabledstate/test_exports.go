@@ -113,6 +113,7 @@ func (env *CouchDBCommonStorageTestEnv) Cleanup() {
 		statecouchdb.CleanupDB(id)
 	}
 	env.provider.Close()
+	removeBookkeeperPath(env.t)
 }
 
 func removeDBPath(t testing.TB) {
@@ -121,4 +122,12 @@ func removeDBPath(t testing.TB) {
 		t.Fatalf("Err: %s", err)
 		t.FailNow()
 	}
+	removeBookkeeperPath(t)
+}
+
+func removeBookkeeperPath(t testing.TB) {
+	if err := os.RemoveAll(ledgerconfig.GetInternalBookkeeperPath()); err != nil {
+		t.Fatalf("Err: %s", err)
+		t.FailNow()
+	}
 }
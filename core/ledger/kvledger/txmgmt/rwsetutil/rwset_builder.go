@@ -19,6 +19,7 @@ package rwsetutil
 import (
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statemetadata"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/ledger/rwset"
@@ -37,16 +38,18 @@ type nsPubRwBuilder struct {
 	namespace         string
 	readMap           map[string]*kvrwset.KVRead //for mvcc validation
 	writeMap          map[string]*kvrwset.KVWrite
+	metadataWriteMap  map[string]*kvrwset.KVMetadataWrite
 	rangeQueriesMap   map[rangeQueryKey]*kvrwset.RangeQueryInfo //for phantom read validation
 	rangeQueriesKeys  []rangeQueryKey
 	collHashRwBuilder map[string]*collHashRwBuilder
 }
 
 type collHashRwBuilder struct {
-	collName    string
-	readMap     map[string]*kvrwset.KVReadHash
-	writeMap    map[string]*kvrwset.KVWriteHash
-	pvtDataHash []byte
+	collName         string
+	readMap          map[string]*kvrwset.KVReadHash
+	writeMap         map[string]*kvrwset.KVWriteHash
+	metadataWriteMap map[string]*kvrwset.KVMetadataWriteHash
+	pvtDataHash      []byte
 }
 
 type nsPvtRwBuilder struct {
@@ -55,8 +58,9 @@ type nsPvtRwBuilder struct {
 }
 
 type collPvtRwBuilder struct {
-	collectionName string
-	writeMap       map[string]*kvrwset.KVWrite
+	collectionName   string
+	writeMap         map[string]*kvrwset.KVWrite
+	metadataWriteMap map[string]*kvrwset.KVMetadataWrite
 }
 
 type rangeQueryKey struct {
@@ -82,6 +86,13 @@ func (b *RWSetBuilder) AddToWriteSet(ns string, key string, value []byte) {
 	nsPubRwBuilder.writeMap[key] = newKVWrite(key, value)
 }
 
+// AddToMetadataWriteSet adds the metadata of a key to the metadata write-set.
+// An empty metadata stands for the deletion of the metadata of the key
+func (b *RWSetBuilder) AddToMetadataWriteSet(ns string, key string, metadata map[string][]byte) {
+	nsPubRwBuilder := b.getOrCreateNsPubRwBuilder(ns)
+	nsPubRwBuilder.metadataWriteMap[key] = &kvrwset.KVMetadataWrite{Key: key, Entries: statemetadata.ToEntries(metadata)}
+}
+
 // AddToRangeQuerySet adds a range query info for performing phantom read validation
 func (b *RWSetBuilder) AddToRangeQuerySet(ns string, rqi *kvrwset.RangeQueryInfo) {
 	nsPubRwBuilder := b.getOrCreateNsPubRwBuilder(ns)
@@ -114,6 +125,16 @@ func (b *RWSetBuilder) AddToPvtAndHashedWriteSet(ns string, coll string, key str
 	return nil
 }
 
+// AddToPvtAndHashedMetadataWriteSet adds the metadata of a private key to the private and hashed metadata write-set
+func (b *RWSetBuilder) AddToPvtAndHashedMetadataWriteSet(ns string, coll string, key string, metadata map[string][]byte) {
+	entries := statemetadata.ToEntries(metadata)
+	b.getOrCreateCollPvtRwBuilder(ns, coll).metadataWriteMap[key] = &kvrwset.KVMetadataWrite{Key: key, Entries: entries}
+	b.getOrCreateCollHashedRwBuilder(ns, coll).metadataWriteMap[key] = &kvrwset.KVMetadataWriteHash{
+		KeyHash: util.ComputeStringHash(key),
+		Entries: entries,
+	}
+}
+
 // GetTxSimulationResults returns the proto bytes of public rwset
 // (public data + hashes of private data) and the private rwset for the transaction
 func (b *RWSetBuilder) GetTxSimulationResults() (*ledger.TxSimulationResults, error) {
@@ -183,12 +204,15 @@ func (b *RWSetBuilder) getTxPvtReadWriteSet() *TxPvtRwSet {
 func (b *nsPubRwBuilder) build() *NsRwSet {
 	var readSet []*kvrwset.KVRead
 	var writeSet []*kvrwset.KVWrite
+	var metadataWriteSet []*kvrwset.KVMetadataWrite
 	var rangeQueriesInfo []*kvrwset.RangeQueryInfo
 	var collHashedRwSet []*CollHashedRwSet
 	//add read set
 	util.GetValuesBySortedKeys(&(b.readMap), &readSet)
 	//add write set
 	util.GetValuesBySortedKeys(&(b.writeMap), &writeSet)
+	//add metadata write set
+	util.GetValuesBySortedKeys(&(b.metadataWriteMap), &metadataWriteSet)
 	//add range query info
 	for _, key := range b.rangeQueriesKeys {
 		rangeQueriesInfo = append(rangeQueriesInfo, b.rangeQueriesMap[key])
@@ -201,7 +225,7 @@ func (b *nsPubRwBuilder) build() *NsRwSet {
 	}
 	return &NsRwSet{
 		NameSpace:        b.namespace,
-		KvRwSet:          &kvrwset.KVRWSet{Reads: readSet, Writes: writeSet, RangeQueriesInfo: rangeQueriesInfo},
+		KvRwSet:          &kvrwset.KVRWSet{Reads: readSet, Writes: writeSet, RangeQueriesInfo: rangeQueriesInfo, MetadataWrites: metadataWriteSet},
 		CollHashedRwSets: collHashedRwSet,
 	}
 }
@@ -220,13 +244,16 @@ func (b *nsPvtRwBuilder) build() *NsPvtRwSet {
 func (b *collHashRwBuilder) build() *CollHashedRwSet {
 	var readSet []*kvrwset.KVReadHash
 	var writeSet []*kvrwset.KVWriteHash
+	var metadataWriteSet []*kvrwset.KVMetadataWriteHash
 	util.GetValuesBySortedKeys(&(b.readMap), &readSet)
 	util.GetValuesBySortedKeys(&(b.writeMap), &writeSet)
+	util.GetValuesBySortedKeys(&(b.metadataWriteMap), &metadataWriteSet)
 	return &CollHashedRwSet{
 		CollectionName: b.collName,
 		HashedRwSet: &kvrwset.HashedRWSet{
-			HashedReads:  readSet,
-			HashedWrites: writeSet,
+			HashedReads:    readSet,
+			HashedWrites:   writeSet,
+			MetadataWrites: metadataWriteSet,
 		},
 		PvtRwSetHash: b.pvtDataHash,
 	}
@@ -234,11 +261,14 @@ func (b *collHashRwBuilder) build() *CollHashedRwSet {
 
 func (b *collPvtRwBuilder) build() *CollPvtRwSet {
 	var writeSet []*kvrwset.KVWrite
+	var metadataWriteSet []*kvrwset.KVMetadataWrite
 	util.GetValuesBySortedKeys(&(b.writeMap), &writeSet)
+	util.GetValuesBySortedKeys(&(b.metadataWriteMap), &metadataWriteSet)
 	return &CollPvtRwSet{
 		CollectionName: b.collectionName,
 		KvRwSet: &kvrwset.KVRWSet{
-			Writes: writeSet,
+			Writes:         writeSet,
+			MetadataWrites: metadataWriteSet,
 		},
 	}
 }
@@ -286,6 +316,7 @@ func newNsPubRwBuilder(namespace string) *nsPubRwBuilder {
 		namespace,
 		make(map[string]*kvrwset.KVRead),
 		make(map[string]*kvrwset.KVWrite),
+		make(map[string]*kvrwset.KVMetadataWrite),
 		make(map[rangeQueryKey]*kvrwset.RangeQueryInfo),
 		nil,
 		make(map[string]*collHashRwBuilder),
@@ -301,10 +332,11 @@ func newCollHashRwBuilder(collName string) *collHashRwBuilder {
 		collName,
 		make(map[string]*kvrwset.KVReadHash),
 		make(map[string]*kvrwset.KVWriteHash),
+		make(map[string]*kvrwset.KVMetadataWriteHash),
 		nil,
 	}
 }
 
 func newCollPvtRwBuilder(collName string) *collPvtRwBuilder {
-	return &collPvtRwBuilder{collName, make(map[string]*kvrwset.KVWrite)}
+	return &collPvtRwBuilder{collName, make(map[string]*kvrwset.KVWrite), make(map[string]*kvrwset.KVMetadataWrite)}
 }
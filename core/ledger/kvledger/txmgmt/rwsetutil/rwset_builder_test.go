@@ -243,3 +243,47 @@ func serializeTestProtoMsg(t *testing.T, protoMsg proto.Message) []byte {
 	testutil.AssertNoError(t, err, "")
 	return msgBytes
 }
+
+func TestTxSimulationResultWithMetadata(t *testing.T) {
+	rwSetBuilder := NewRWSetBuilder()
+	rwSetBuilder.AddToWriteSet("ns1", "key1", []byte("value1"))
+	rwSetBuilder.AddToMetadataWriteSet("ns1", "key2", map[string][]byte{"name2": []byte("value2"), "name1": []byte("value1")})
+	rwSetBuilder.AddToMetadataWriteSet("ns1", "key1", nil)
+	rwSetBuilder.AddToPvtAndHashedMetadataWriteSet("ns1", "coll1", "key3", map[string][]byte{"name1": []byte("value1")})
+
+	txSimulationResults, err := rwSetBuilder.GetTxSimulationResults()
+	testutil.AssertNoError(t, err, "")
+
+	entries := []*kvrwset.KVMetadataEntry{{Name: "name1", Value: []byte("value1")}}
+	ns1KVRWSet := &kvrwset.KVRWSet{
+		Writes: []*kvrwset.KVWrite{newKVWrite("key1", []byte("value1"))},
+		MetadataWrites: []*kvrwset.KVMetadataWrite{
+			{Key: "key1"},
+			{Key: "key2", Entries: append(entries, &kvrwset.KVMetadataEntry{Name: "name2", Value: []byte("value2")})},
+		},
+	}
+	collPvtKVRWSet := &kvrwset.KVRWSet{MetadataWrites: []*kvrwset.KVMetadataWrite{{Key: "key3", Entries: entries}}}
+	collPvtRWSet := &rwset.CollectionPvtReadWriteSet{
+		CollectionName: "coll1",
+		Rwset:          serializeTestProtoMsg(t, collPvtKVRWSet),
+	}
+	collHashedRWSet := &kvrwset.HashedRWSet{
+		MetadataWrites: []*kvrwset.KVMetadataWriteHash{{KeyHash: util.ComputeStringHash("key3"), Entries: entries}},
+	}
+
+	expectedPubRWSet := &rwset.TxReadWriteSet{NsRwset: []*rwset.NsReadWriteSet{{
+		Namespace: "ns1",
+		Rwset:     serializeTestProtoMsg(t, ns1KVRWSet),
+		CollectionHashedRwset: []*rwset.CollectionHashedReadWriteSet{{
+			CollectionName: "coll1",
+			HashedRwset:    serializeTestProtoMsg(t, collHashedRWSet),
+			PvtRwsetHash:   util.ComputeHash(collPvtRWSet.Rwset),
+		}},
+	}}}
+	expectedPvtRWSet := &rwset.TxPvtReadWriteSet{NsPvtRwset: []*rwset.NsPvtReadWriteSet{{
+		Namespace:          "ns1",
+		CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{collPvtRWSet},
+	}}}
+	testutil.AssertEquals(t, txSimulationResults.PubSimulationResults, expectedPubRWSet)
+	testutil.AssertEquals(t, txSimulationResults.PvtSimulationResults, expectedPvtRWSet)
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package commontests
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -203,8 +204,58 @@ func TestIterator(t *testing.T, dbProvider statedb.VersionedDBProvider) {
 	testItr(t, itr4, []string{"key5", "key6"})
 }
 
+// TestPaginatedRangeQuery tests the range queries with a limit on the number of records
+func TestPaginatedRangeQuery(t *testing.T, dbProvider statedb.VersionedDBProvider) {
+	db, err := dbProvider.GetDBHandle("testpaginatedrangequery")
+	testutil.AssertNoError(t, err, "")
+	db.Open()
+	defer db.Close()
+	batch := statedb.NewUpdateBatch()
+	for i := 1; i <= 9; i++ {
+		batch.Put("ns1", fmt.Sprintf("key%d", i), []byte(fmt.Sprintf("value%d", i)), version.NewHeight(1, uint64(i)))
+	}
+	batch.Put("ns2", "key1", []byte("value1"), version.NewHeight(1, 10))
+	db.ApplyUpdates(batch, version.NewHeight(2, 10))
+
+	// the bookmark of a page is used as the start key of the next page
+	metadata := map[string]interface{}{"limit": int32(4)}
+	itr, err := db.GetStateRangeScanIteratorWithMetadata("ns1", "key1", "", metadata)
+	testutil.AssertNoError(t, err, "")
+	testItrWithoutClose(t, itr, []string{"key1", "key2", "key3", "key4"})
+	testutil.AssertEquals(t, itr.GetBookmarkAndClose(), "key5")
+
+	itr, err = db.GetStateRangeScanIteratorWithMetadata("ns1", "key5", "", metadata)
+	testutil.AssertNoError(t, err, "")
+	testItrWithoutClose(t, itr, []string{"key5", "key6", "key7", "key8"})
+	testutil.AssertEquals(t, itr.GetBookmarkAndClose(), "key9")
+
+	itr, err = db.GetStateRangeScanIteratorWithMetadata("ns1", "key9", "", metadata)
+	testutil.AssertNoError(t, err, "")
+	testItrWithoutClose(t, itr, []string{"key9"})
+	testutil.AssertEquals(t, itr.GetBookmarkAndClose(), "")
+
+	// the end key is honored
+	itr, err = db.GetStateRangeScanIteratorWithMetadata("ns1", "key1", "key4", metadata)
+	testutil.AssertNoError(t, err, "")
+	testItrWithoutClose(t, itr, []string{"key1", "key2", "key3"})
+	testutil.AssertEquals(t, itr.GetBookmarkAndClose(), "")
+
+	// no limit returns all the keys
+	itr, err = db.GetStateRangeScanIteratorWithMetadata("ns1", "", "", map[string]interface{}{})
+	testutil.AssertNoError(t, err, "")
+	testItrWithoutClose(t, itr, []string{"key1", "key2", "key3", "key4", "key5", "key6", "key7", "key8", "key9"})
+	testutil.AssertEquals(t, itr.GetBookmarkAndClose(), "")
+
+	_, err = db.GetStateRangeScanIteratorWithMetadata("ns1", "", "", map[string]interface{}{"limit": "4"})
+	testutil.AssertError(t, err, "limit should be an int32")
+}
+
 func testItr(t *testing.T, itr statedb.ResultsIterator, expectedKeys []string) {
 	defer itr.Close()
+	testItrWithoutClose(t, itr, expectedKeys)
+}
+
+func testItrWithoutClose(t *testing.T, itr statedb.ResultsIterator, expectedKeys []string) {
 	for _, expectedKey := range expectedKeys {
 		queryResult, _ := itr.Next()
 		vkv := queryResult.(*statedb.VersionedKV)
@@ -495,6 +546,44 @@ func TestQuery(t *testing.T, dbProvider statedb.VersionedDBProvider) {
 
 }
 
+// TestPaginatedQuery tests the rich queries with a limit on the number of records and a bookmark
+func TestPaginatedQuery(t *testing.T, dbProvider statedb.VersionedDBProvider) {
+	db, err := dbProvider.GetDBHandle("testpaginatedquery")
+	testutil.AssertNoError(t, err, "")
+	db.Open()
+	defer db.Close()
+	batch := statedb.NewUpdateBatch()
+	for i := 1; i <= 8; i++ {
+		jsonValue := fmt.Sprintf("{\"asset_name\": \"marble%d\",\"color\": \"blue\",\"size\": %d}", i, i)
+		batch.Put("ns1", fmt.Sprintf("key%d", i), []byte(jsonValue), version.NewHeight(1, uint64(i)))
+	}
+	jsonValue := "{\"asset_name\": \"marble9\",\"color\": \"green\",\"size\": 9}"
+	batch.Put("ns1", "key9", []byte(jsonValue), version.NewHeight(1, 9))
+	db.ApplyUpdates(batch, version.NewHeight(2, 9))
+
+	query := "{\"selector\":{\"color\":\"blue\"}}"
+	metadata := map[string]interface{}{"limit": int32(3)}
+	itr, err := db.ExecuteQueryWithMetadata("ns1", query, metadata)
+	testutil.AssertNoError(t, err, "")
+	testItrWithoutClose(t, itr, []string{"key1", "key2", "key3"})
+	bookmark := itr.GetBookmarkAndClose()
+	testutil.AssertNotEquals(t, bookmark, "")
+
+	metadata["bookmark"] = bookmark
+	itr, err = db.ExecuteQueryWithMetadata("ns1", query, metadata)
+	testutil.AssertNoError(t, err, "")
+	testItrWithoutClose(t, itr, []string{"key4", "key5", "key6"})
+	metadata["bookmark"] = itr.GetBookmarkAndClose()
+
+	itr, err = db.ExecuteQueryWithMetadata("ns1", query, metadata)
+	testutil.AssertNoError(t, err, "")
+	testItrWithoutClose(t, itr, []string{"key7", "key8"})
+	itr.GetBookmarkAndClose()
+
+	_, err = db.ExecuteQueryWithMetadata("ns1", query, map[string]interface{}{"skip": int32(3)})
+	testutil.AssertError(t, err, "skip is not a supported option")
+}
+
 // TestGetVersion tests retrieving the version by namespace and key
 func TestGetVersion(t *testing.T, dbProvider statedb.VersionedDBProvider) {
 
@@ -730,3 +819,40 @@ func TestBatchWithIndividualRetry(t *testing.T, dbProvider statedb.VersionedDBPr
 	testutil.AssertNoError(t, err, "")
 
 }
+
+// TestValueAndMetadataWrites tests statedb for value and metadata read-writes
+func TestValueAndMetadataWrites(t *testing.T, dbProvider statedb.VersionedDBProvider) {
+	db, err := dbProvider.GetDBHandle("testvalueandmetadata")
+	testutil.AssertNoError(t, err, "")
+	batch := statedb.NewUpdateBatch()
+
+	vv1 := statedb.VersionedValue{Value: []byte("value1"), Metadata: []byte("metadata1"), Version: version.NewHeight(1, 1)}
+	vv2 := statedb.VersionedValue{Value: []byte("value2"), Metadata: []byte("metadata2"), Version: version.NewHeight(1, 2)}
+	vv3 := statedb.VersionedValue{Value: []byte(`{"asset":"marble1"}`), Metadata: []byte("metadata3"), Version: version.NewHeight(1, 3)}
+	vv4 := statedb.VersionedValue{Value: []byte("value4"), Version: version.NewHeight(1, 4)}
+
+	batch.PutValAndMetadata("ns1", "key1", vv1.Value, vv1.Metadata, vv1.Version)
+	batch.PutValAndMetadata("ns1", "key2", vv2.Value, vv2.Metadata, vv2.Version)
+	batch.PutValAndMetadata("ns2", "key3", vv3.Value, vv3.Metadata, vv3.Version)
+	batch.PutValAndMetadata("ns2", "key4", vv4.Value, vv4.Metadata, vv4.Version)
+	db.ApplyUpdates(batch, version.NewHeight(2, 5))
+
+	vv, _ := db.GetState("ns1", "key1")
+	testutil.AssertEquals(t, vv, &vv1)
+
+	vv, _ = db.GetState("ns2", "key3")
+	testutil.AssertEquals(t, vv, &vv3)
+
+	vv, _ = db.GetState("ns2", "key4")
+	testutil.AssertEquals(t, vv, &vv4)
+
+	itr, err := db.GetStateRangeScanIterator("ns1", "", "")
+	testutil.AssertNoError(t, err, "")
+	defer itr.Close()
+	queryResult, err := itr.Next()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, queryResult.(*statedb.VersionedKV).VersionedValue, vv1)
+	queryResult, err = itr.Next()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, queryResult.(*statedb.VersionedKV).VersionedValue, vv2)
+}
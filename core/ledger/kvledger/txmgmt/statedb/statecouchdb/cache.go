@@ -0,0 +1,182 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statecouchdb
+
+import (
+	"container/list"
+	"sync"
+	"sync/atomic"
+
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+)
+
+// stateCache is a bounded LRU cache of the committed state of a channel,
+// keyed by namespace and key. It sits in front of CouchDB so that repeated
+// reads of the same keys during endorsement and validation do not each cost
+// an HTTP round trip. A nil value is cached for keys known not to exist.
+//
+// Reads populate the cache through add, which is rejected if a commit has
+// completed since the read started. Commits update the cache through
+// update once the block has been written to CouchDB, so a value read from
+// CouchDB before a commit can never overwrite the value of that commit.
+type stateCache struct {
+	capacity int
+
+	mux      sync.Mutex
+	lru      *list.List
+	entries  map[statedb.CompositeKey]*list.Element
+	sequence uint64
+
+	hits     uint64
+	misses   uint64
+	reported cacheStats
+}
+
+type cacheEntry struct {
+	key statedb.CompositeKey
+	val *statedb.VersionedValue
+}
+
+// cacheStats holds the hit and miss counters of a stateCache
+type cacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// newStateCache returns a stateCache holding up to capacity keys, or nil if
+// capacity is not positive. All methods of stateCache accept a nil receiver
+// and then behave as an always empty cache.
+func newStateCache(capacity int) *stateCache {
+	if capacity <= 0 {
+		return nil
+	}
+	return &stateCache{
+		capacity: capacity,
+		lru:      list.New(),
+		entries:  make(map[statedb.CompositeKey]*list.Element),
+	}
+}
+
+// get returns the cached value of the key, if any, along with the sequence
+// number to pass to add when the value has to be read from CouchDB instead
+func (c *stateCache) get(namespace, key string) (*statedb.VersionedValue, bool, uint64) {
+	if c == nil {
+		return nil, false, 0
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	elem, ok := c.entries[statedb.CompositeKey{Namespace: namespace, Key: key}]
+	if !ok {
+		atomic.AddUint64(&c.misses, 1)
+		return nil, false, c.sequence
+	}
+	atomic.AddUint64(&c.hits, 1)
+	c.lru.MoveToFront(elem)
+	return elem.Value.(*cacheEntry).val, true, c.sequence
+}
+
+// add caches the value read from CouchDB for the key, unless the state has
+// been updated since get returned the given sequence number
+func (c *stateCache) add(namespace, key string, val *statedb.VersionedValue, sequence uint64) {
+	if c == nil {
+		return
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if sequence != c.sequence {
+		return
+	}
+	c.put(statedb.CompositeKey{Namespace: namespace, Key: key}, val)
+}
+
+// update applies the committed batch to the cache. Keys of the batch which
+// are not cached are not added, as being written is no indication of being
+// read soon.
+func (c *stateCache) update(batch *statedb.UpdateBatch) {
+	if c == nil {
+		return
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.sequence++
+	for _, ns := range batch.GetUpdatedNamespaces() {
+		for key, vv := range batch.GetUpdates(ns) {
+			compositeKey := statedb.CompositeKey{Namespace: ns, Key: key}
+			if _, ok := c.entries[compositeKey]; !ok {
+				continue
+			}
+			if vv.Value == nil {
+				// deleted keys are cached as missing, as GetState reports them
+				vv = nil
+			}
+			c.put(compositeKey, vv)
+		}
+	}
+}
+
+// clear empties the cache, it is used when the state in CouchDB is no longer
+// known, e.g. after a failed commit
+func (c *stateCache) clear() {
+	if c == nil {
+		return
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	c.sequence++
+	c.lru.Init()
+	c.entries = make(map[statedb.CompositeKey]*list.Element)
+}
+
+// put must be called with the lock held
+func (c *stateCache) put(key statedb.CompositeKey, val *statedb.VersionedValue) {
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*cacheEntry).val = val
+		c.lru.MoveToFront(elem)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&cacheEntry{key: key, val: val})
+	for c.lru.Len() > c.capacity {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+func (c *stateCache) len() int {
+	if c == nil {
+		return 0
+	}
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.lru.Len()
+}
+
+func (c *stateCache) stats() cacheStats {
+	if c == nil {
+		return cacheStats{}
+	}
+	return cacheStats{Hits: atomic.LoadUint64(&c.hits), Misses: atomic.LoadUint64(&c.misses)}
+}
+
+// reportMetrics emits the hits and misses since the previous report and the
+// current size of the cache. It is called once per committed block rather
+// than on every read to keep the read path cheap.
+func (c *stateCache) reportMetrics(channelID string) {
+	if c == nil {
+		return
+	}
+	current := c.stats()
+	scope := metrics.RootScope.SubScope("statecouchdb").Tagged(map[string]string{"channel": channelID})
+	scope.Counter("state_cache_hits").Inc(int64(current.Hits - c.reported.Hits))
+	scope.Counter("state_cache_misses").Inc(int64(current.Misses - c.reported.Misses))
+	scope.Gauge("state_cache_size").Update(float64(c.len()))
+	c.reported = current
+}
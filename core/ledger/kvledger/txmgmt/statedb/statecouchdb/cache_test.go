@@ -0,0 +1,138 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statecouchdb
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+)
+
+func TestStateCacheDisabled(t *testing.T) {
+	cache := newStateCache(0)
+	testutil.AssertNil(t, cache)
+
+	cache.add("ns", "key1", &statedb.VersionedValue{Value: []byte("value1")}, 0)
+	_, found, _ := cache.get("ns", "key1")
+	testutil.AssertEquals(t, found, false)
+	cache.update(statedb.NewUpdateBatch())
+	cache.clear()
+	cache.reportMetrics("testchannel")
+	testutil.AssertEquals(t, cache.len(), 0)
+	testutil.AssertEquals(t, cache.stats(), cacheStats{})
+}
+
+func TestStateCacheGetAdd(t *testing.T) {
+	cache := newStateCache(10)
+	vv := &statedb.VersionedValue{Value: []byte("value1"), Version: version.NewHeight(1, 1)}
+
+	_, found, sequence := cache.get("ns", "key1")
+	testutil.AssertEquals(t, found, false)
+	cache.add("ns", "key1", vv, sequence)
+
+	_, found, sequence = cache.get("ns", "key2")
+	testutil.AssertEquals(t, found, false)
+	cache.add("ns", "key2", nil, sequence)
+
+	val, found, _ := cache.get("ns", "key1")
+	testutil.AssertEquals(t, found, true)
+	testutil.AssertEquals(t, val, vv)
+
+	// missing keys are cached too
+	val, found, _ = cache.get("ns", "key2")
+	testutil.AssertEquals(t, found, true)
+	testutil.AssertNil(t, val)
+
+	// keys are scoped by namespace
+	_, found, _ = cache.get("ns2", "key1")
+	testutil.AssertEquals(t, found, false)
+
+	testutil.AssertEquals(t, cache.stats(), cacheStats{Hits: 2, Misses: 3})
+}
+
+func TestStateCacheEviction(t *testing.T) {
+	cache := newStateCache(2)
+	for _, key := range []string{"key1", "key2"} {
+		cache.add("ns", key, &statedb.VersionedValue{Value: []byte(key)}, 0)
+	}
+
+	// reading key1 makes key2 the least recently used key
+	_, found, _ := cache.get("ns", "key1")
+	testutil.AssertEquals(t, found, true)
+	cache.add("ns", "key3", &statedb.VersionedValue{Value: []byte("key3")}, 0)
+	testutil.AssertEquals(t, cache.len(), 2)
+
+	_, found, _ = cache.get("ns", "key2")
+	testutil.AssertEquals(t, found, false)
+	for _, key := range []string{"key1", "key3"} {
+		_, found, _ = cache.get("ns", key)
+		testutil.AssertEquals(t, found, true)
+	}
+}
+
+func TestStateCacheUpdate(t *testing.T) {
+	cache := newStateCache(10)
+	cache.add("ns", "key1", &statedb.VersionedValue{Value: []byte("value1"), Version: version.NewHeight(1, 1)}, 0)
+	cache.add("ns", "key2", &statedb.VersionedValue{Value: []byte("value2"), Version: version.NewHeight(1, 2)}, 0)
+	cache.add("ns", "key3", nil, 0)
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns", "key1", []byte("value1_new"), version.NewHeight(2, 1))
+	batch.Delete("ns", "key2", version.NewHeight(2, 2))
+	batch.Put("ns", "key3", []byte("value3"), version.NewHeight(2, 3))
+	batch.Put("ns", "key4", []byte("value4"), version.NewHeight(2, 4))
+	cache.update(batch)
+
+	val, found, _ := cache.get("ns", "key1")
+	testutil.AssertEquals(t, found, true)
+	testutil.AssertEquals(t, val, &statedb.VersionedValue{Value: []byte("value1_new"), Version: version.NewHeight(2, 1)})
+
+	val, found, _ = cache.get("ns", "key2")
+	testutil.AssertEquals(t, found, true)
+	testutil.AssertNil(t, val)
+
+	val, found, _ = cache.get("ns", "key3")
+	testutil.AssertEquals(t, found, true)
+	testutil.AssertEquals(t, val, &statedb.VersionedValue{Value: []byte("value3"), Version: version.NewHeight(2, 3)})
+
+	// written keys are not added to the cache
+	_, found, _ = cache.get("ns", "key4")
+	testutil.AssertEquals(t, found, false)
+}
+
+func TestStateCacheStaleAdd(t *testing.T) {
+	cache := newStateCache(10)
+
+	// a value read from CouchDB before a commit must not be cached after it
+	_, found, sequence := cache.get("ns", "key1")
+	testutil.AssertEquals(t, found, false)
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns", "key1", []byte("value1_new"), version.NewHeight(2, 1))
+	cache.update(batch)
+	cache.add("ns", "key1", &statedb.VersionedValue{Value: []byte("value1"), Version: version.NewHeight(1, 1)}, sequence)
+	_, found, _ = cache.get("ns", "key1")
+	testutil.AssertEquals(t, found, false)
+
+	// neither after the cache was cleared
+	_, _, sequence = cache.get("ns", "key1")
+	cache.clear()
+	cache.add("ns", "key1", &statedb.VersionedValue{Value: []byte("value1"), Version: version.NewHeight(1, 1)}, sequence)
+	testutil.AssertEquals(t, cache.len(), 0)
+}
+
+func TestStateCacheClear(t *testing.T) {
+	cache := newStateCache(10)
+	cache.add("ns", "key1", &statedb.VersionedValue{Value: []byte("value1")}, 0)
+	cache.add("ns", "key2", nil, 0)
+	testutil.AssertEquals(t, cache.len(), 2)
+
+	cache.clear()
+	testutil.AssertEquals(t, cache.len(), 0)
+	_, found, _ := cache.get("ns", "key1")
+	testutil.AssertEquals(t, found, false)
+}
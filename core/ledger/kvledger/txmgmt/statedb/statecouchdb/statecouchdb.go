@@ -7,6 +7,7 @@ package statecouchdb
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -32,15 +33,12 @@ const (
 	idField       = "_id"
 	revField      = "_rev"
 	versionField  = "~version"
+	metadataField = "~metadata"
 	deletedField  = "_deleted"
 )
 
 var dbArtifactsDirFilter = map[string]bool{"META-INF/statedb/couchdb/indexes": true}
 
-// querySkip is implemented for future use by query paging
-// currently defaulted to 0 and is not used
-const querySkip = 0
-
 //BatchableDocument defines a document for a batch
 type BatchableDocument struct {
 	CouchDoc couchdb.CouchDoc
@@ -80,6 +78,30 @@ func NewVersionedDBProvider() (*VersionedDBProvider, error) {
 	return &VersionedDBProvider{couchInstance, make(map[string]*VersionedDB), sync.Mutex{}, 0}, nil
 }
 
+// DropApplicationDBs drops all the application databases, i.e., the state databases of all the channels
+// including the databases of the private data hashes, from the CouchDB instance configured for the peer
+func DropApplicationDBs() error {
+	logger.Info("Dropping CouchDB application databases ...")
+	couchDBDef := couchdb.GetCouchDBDefinition()
+	couchInstance, err := couchdb.CreateCouchInstance(couchDBDef.URL, couchDBDef.Username, couchDBDef.Password,
+		couchDBDef.MaxRetries, couchDBDef.MaxRetriesOnStartup, couchDBDef.RequestTimeout)
+	if err != nil {
+		return err
+	}
+	dbNames, err := couchInstance.RetrieveApplicationDBNames()
+	if err != nil {
+		return err
+	}
+	for _, dbName := range dbNames {
+		db := &couchdb.CouchDatabase{CouchInstance: *couchInstance, DBName: dbName}
+		if _, err = db.DropDatabase(); err != nil {
+			return fmt.Errorf("Error while dropping database [%s]: %s", dbName, err)
+		}
+		logger.Infof("Dropped database [%s]", dbName)
+	}
+	return nil
+}
+
 //HandleChaincodeDeploy initializes database artifacts for the database associated with the namespace
 // This function delibrately suppresses the errors that occur during the creation of the indexes on couchdb.
 // This is because, in the present code, we do not differentiate between the errors because of couchdb interaction
@@ -148,6 +170,7 @@ type VersionedDB struct {
 	namespaceDBs  map[string]*couchdb.CouchDatabase // One database per deployed chaincode.
 	//TODO: Decide whether to split committedDataCache into multiple cahces, i.e., one per namespace.
 	committedDataCache *CommittedVersions // Used as a local cache during bulk processing of a block.
+	stateCache         *stateCache        // LRU cache of committed state, nil if disabled.
 	mux                sync.RWMutex
 }
 
@@ -168,7 +191,9 @@ func newVersionedDB(couchInstance *couchdb.CouchInstance, dbName string) (*Versi
 
 	committedDataCache := &CommittedVersions{committedVersions: versionMap, revisionNumbers: revMap}
 
-	return &VersionedDB{couchInstance, metadataDB, chainName, namespaceDBMap, committedDataCache, sync.RWMutex{}}, nil
+	stateCache := newStateCache(ledgerconfig.GetStateCacheSize())
+
+	return &VersionedDB{couchInstance, metadataDB, chainName, namespaceDBMap, committedDataCache, stateCache, sync.RWMutex{}}, nil
 }
 
 // getNamespaceDBHandle gets the handle to a named chaincode database
@@ -244,6 +269,11 @@ func (vdb *VersionedDB) BytesKeySuppoted() bool {
 func (vdb *VersionedDB) GetState(namespace string, key string) (*statedb.VersionedValue, error) {
 	logger.Debugf("GetState(). ns=%s, key=%s", namespace, key)
 
+	cachedValue, keyFound, sequence := vdb.stateCache.get(namespace, key)
+	if keyFound {
+		return cachedValue, nil
+	}
+
 	db, err := vdb.getNamespaceDBHandle(namespace)
 	if err != nil {
 		return nil, err
@@ -253,16 +283,17 @@ func (vdb *VersionedDB) GetState(namespace string, key string) (*statedb.Version
 		return nil, err
 	}
 	if couchDoc == nil {
+		vdb.stateCache.add(namespace, key, nil, sequence)
 		return nil, nil
 	}
 
-	// remove the reserved fields from the CouchDB JSON and return the value and version
-	returnValue, returnVersion, err := getValueAndVersionFromDoc(couchDoc.JSONValue, couchDoc.Attachments)
+	// remove the reserved fields from the CouchDB JSON and return the value, metadata and version
+	vv, err := getVersionedValueFromDoc(couchDoc.JSONValue, couchDoc.Attachments)
 	if err != nil {
 		return nil, err
 	}
-
-	return &statedb.VersionedValue{Value: returnValue, Version: returnVersion}, nil
+	vdb.stateCache.add(namespace, key, vv, sequence)
+	return vv, nil
 }
 
 //GetCachedVersion implements method in VersionedDB interface
@@ -290,7 +321,18 @@ func (vdb *VersionedDB) GetVersion(namespace string, key string) (*version.Heigh
 
 	returnVersion, keyFound := vdb.GetCachedVersion(namespace, key)
 
-	// If the version was not found in the committed data cache, retrieve it from statedb.
+	// If the version was not found in the committed data cache, look it up in the state cache
+	if !keyFound {
+		var cachedValue *statedb.VersionedValue
+		if cachedValue, keyFound, _ = vdb.stateCache.get(namespace, key); keyFound {
+			if cachedValue == nil {
+				return nil, nil
+			}
+			return cachedValue.Version, nil
+		}
+	}
+
+	// If the version was not found in either cache, retrieve it from statedb.
 	if !keyFound {
 
 		db, err := vdb.getNamespaceDBHandle(namespace)
@@ -322,8 +364,8 @@ func (vdb *VersionedDB) GetVersion(namespace string, key string) (*version.Heigh
 	return returnVersion, nil
 }
 
-// remove the reserved fields from CouchDB JSON and return the value and version
-func getValueAndVersionFromDoc(persistedValue []byte, attachments []*couchdb.AttachmentInfo) ([]byte, *version.Height, error) {
+// remove the reserved fields from CouchDB JSON and return the value, metadata and version
+func getVersionedValueFromDoc(persistedValue []byte, attachments []*couchdb.AttachmentInfo) (*statedb.VersionedValue, error) {
 
 	// initialize the return value
 	returnValue := []byte{}
@@ -336,21 +378,30 @@ func getValueAndVersionFromDoc(persistedValue []byte, attachments []*couchdb.Att
 	decoder.UseNumber()
 	err := decoder.Decode(&jsonResult)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	// verify the version field exists
 	if _, fieldFound := jsonResult[versionField]; !fieldFound {
-		return nil, nil, fmt.Errorf("The version field %s was not found", versionField)
+		return nil, fmt.Errorf("The version field %s was not found", versionField)
 	}
 
 	// create the return version from the version field in the JSON
 	returnVersion := createVersionHeightFromVersionString(jsonResult[versionField].(string))
 
-	// remove the _id, _rev and version fields
+	// the metadata field is stored base64 encoded and only exists if metadata was set for the key
+	var returnMetadata []byte
+	if encodedMetadata, fieldFound := jsonResult[metadataField]; fieldFound {
+		if returnMetadata, err = base64.StdEncoding.DecodeString(encodedMetadata.(string)); err != nil {
+			return nil, err
+		}
+	}
+
+	// remove the _id, _rev, version and metadata fields
 	delete(jsonResult, idField)
 	delete(jsonResult, revField)
 	delete(jsonResult, versionField)
+	delete(jsonResult, metadataField)
 
 	// handle binary or json data
 	if attachments != nil { // binary attachment
@@ -365,12 +416,12 @@ func getValueAndVersionFromDoc(persistedValue []byte, attachments []*couchdb.Att
 		// marshal the returned JSON data.
 		returnValue, err = json.Marshal(jsonResult)
 		if err != nil {
-			return nil, nil, err
+			return nil, err
 		}
 
 	}
 
-	return returnValue, returnVersion, nil
+	return &statedb.VersionedValue{Value: returnValue, Metadata: returnMetadata, Version: returnVersion}, nil
 
 }
 
@@ -393,59 +444,96 @@ func (vdb *VersionedDB) GetStateMultipleKeys(namespace string, keys []string) ([
 // startKey is inclusive
 // endKey is exclusive
 func (vdb *VersionedDB) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (statedb.ResultsIterator, error) {
+	return vdb.GetStateRangeScanIteratorWithMetadata(namespace, startKey, endKey, nil)
+}
 
-	// Get the querylimit from core.yaml
-	queryLimit := ledgerconfig.GetQueryLimit()
-
+// GetStateRangeScanIteratorWithMetadata implements method in VersionedDB interface
+// startKey is inclusive
+// endKey is exclusive
+// metadata contains the limit on the number of records to be returned
+func (vdb *VersionedDB) GetStateRangeScanIteratorWithMetadata(namespace string, startKey string, endKey string, metadata map[string]interface{}) (statedb.QueryResultsIterator, error) {
+	requestedLimit := int32(0)
+	if metadata != nil {
+		if err := statedb.ValidateRangeMetadata(metadata); err != nil {
+			return nil, err
+		}
+		if limit, ok := metadata[statedb.QueryOptionLimit]; ok {
+			requestedLimit = limit.(int32)
+		}
+	}
 	db, err := vdb.getNamespaceDBHandle(namespace)
 	if err != nil {
 		return nil, err
 	}
-
-	queryResult, err := db.ReadDocRange(startKey, endKey, queryLimit, querySkip)
-	if err != nil {
+	scanner := &queryScanner{
+		namespace:      namespace,
+		db:             db,
+		startKey:       startKey,
+		endKey:         endKey,
+		requestedLimit: requestedLimit,
+	}
+	if err = scanner.fetchNextBatch(); err != nil {
 		logger.Debugf("Error calling ReadDocRange(): %s\n", err.Error())
 		return nil, err
 	}
 	logger.Debugf("Exiting GetStateRangeScanIterator")
-	return newKVScanner(namespace, *queryResult), nil
-
+	return scanner, nil
 }
 
 // ExecuteQuery implements method in VersionedDB interface
 func (vdb *VersionedDB) ExecuteQuery(namespace, query string) (statedb.ResultsIterator, error) {
+	return vdb.ExecuteQueryWithMetadata(namespace, query, nil)
+}
 
-	// Get the querylimit from core.yaml
-	queryLimit := ledgerconfig.GetQueryLimit()
-
-	// Explicit paging not yet supported.
-	// Use queryLimit from config and 0 skip.
-	queryString, err := applyAdditionalQueryOptions(query, queryLimit, 0)
-	if err != nil {
+// ExecuteQueryWithMetadata implements method in VersionedDB interface
+// metadata contains the limit on the number of records to be returned and the bookmark
+// returned by the previous page of the same query
+func (vdb *VersionedDB) ExecuteQueryWithMetadata(namespace, query string, metadata map[string]interface{}) (statedb.QueryResultsIterator, error) {
+	requestedLimit := int32(0)
+	bookmark := ""
+	if metadata != nil {
+		if err := statedb.ValidateQueryMetadata(metadata); err != nil {
+			return nil, err
+		}
+		if limit, ok := metadata[statedb.QueryOptionLimit]; ok {
+			requestedLimit = limit.(int32)
+		}
+		if bookmarkOption, ok := metadata[statedb.QueryOptionBookmark]; ok {
+			bookmark = bookmarkOption.(string)
+		}
+	}
+	// validate the query before reaching out to couchdb
+	if _, err := applyAdditionalQueryOptions(query, 0, ""); err != nil {
 		logger.Debugf("Error calling applyAdditionalQueryOptions(): %s\n", err.Error())
 		return nil, err
 	}
-
 	db, err := vdb.getNamespaceDBHandle(namespace)
 	if err != nil {
 		return nil, err
 	}
-	queryResult, err := db.QueryDocuments(queryString)
-	if err != nil {
+	scanner := &queryScanner{
+		namespace:      namespace,
+		db:             db,
+		query:          query,
+		bookmark:       bookmark,
+		requestedLimit: requestedLimit,
+	}
+	if err = scanner.fetchNextBatch(); err != nil {
 		logger.Debugf("Error calling QueryDocuments(): %s\n", err.Error())
 		return nil, err
 	}
-
 	logger.Debugf("Exiting ExecuteQuery")
-	return newQueryScanner(namespace, *queryResult), nil
+	return scanner, nil
 }
 
 // applyAdditionalQueryOptions will add additional fields to the query required for query processing
-func applyAdditionalQueryOptions(queryString string, queryLimit, querySkip int) (string, error) {
+// The limit and the bookmark override the ones passed in the query, if any. The bookmark is not added if empty
+func applyAdditionalQueryOptions(queryString string, queryLimit int, queryBookmark string) (string, error) {
 
 	const jsonQueryFields = "fields"
 	const jsonQueryLimit = "limit"
 	const jsonQuerySkip = "skip"
+	const jsonQueryBookmark = "bookmark"
 
 	//create a generic map for the query json
 	jsonQueryMap := make(map[string]interface{})
@@ -464,9 +552,9 @@ func applyAdditionalQueryOptions(queryString string, queryLimit, querySkip int)
 
 		case []interface{}:
 
-			//Add the "_id", and "version" fields,  these are needed by default
+			//Add the "_id", "version" and "metadata" fields,  these are needed by default
 			jsonQueryMap[jsonQueryFields] = append(fieldsJSONArray.([]interface{}),
-				idField, versionField)
+				idField, versionField, metadataField)
 
 		default:
 			return "", fmt.Errorf("Fields definition must be an array.")
@@ -477,13 +565,21 @@ func applyAdditionalQueryOptions(queryString string, queryLimit, querySkip int)
 
 	// Add limit
 	// This will override any limit passed in the query.
-	// Explicit paging not yet supported.
+	// The results are fetched from couchdb in batches of this size.
 	jsonQueryMap[jsonQueryLimit] = queryLimit
 
 	// Add skip of 0.
 	// This will override any skip passed in the query.
-	// Explicit paging not yet supported.
-	jsonQueryMap[jsonQuerySkip] = querySkip
+	// Paging is done through the bookmark.
+	jsonQueryMap[jsonQuerySkip] = 0
+
+	// Add the bookmark, if any.
+	// This will override any bookmark passed in the query.
+	if queryBookmark != "" {
+		jsonQueryMap[jsonQueryBookmark] = queryBookmark
+	} else {
+		delete(jsonQueryMap, jsonQueryBookmark)
+	}
 
 	//Marshal the updated json query
 	editedQuery, err := json.Marshal(jsonQueryMap)
@@ -601,9 +697,14 @@ func (vdb *VersionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version
 	// TODO: Currently, we are returing only one error. We need to create a new error type
 	// that can encapsulate all the errors and return that type
 	if len(errResponses) > 0 {
+		// Some of the updates may have been written, so the cached state can no longer be trusted
+		vdb.stateCache.clear()
 		return <-errResponses
 	}
 
+	vdb.stateCache.update(batch)
+	vdb.stateCache.reportMetrics(vdb.chainName)
+
 	// Record a savepoint at a given height
 	err := vdb.recordSavepoint(height, namespaces)
 	if err != nil {
@@ -666,7 +767,7 @@ func (vdb *VersionedDB) processUpdateBatch(updateBatch *statedb.UpdateBatch, mis
 
 			if isDelete {
 				// this is a deleted record.  Set the _deleted property to true
-				couchDoc.JSONValue, err = createCouchdbDocJSON(key, revision, nil, vv.Version, true)
+				couchDoc.JSONValue, err = createCouchdbDocJSON(key, revision, nil, nil, vv.Version, true)
 				if err != nil {
 					return err
 				}
@@ -675,7 +776,7 @@ func (vdb *VersionedDB) processUpdateBatch(updateBatch *statedb.UpdateBatch, mis
 
 				if couchdb.IsJSON(string(vv.Value)) {
 					// Handle as json
-					couchDoc.JSONValue, err = createCouchdbDocJSON(key, revision, vv.Value, vv.Version, false)
+					couchDoc.JSONValue, err = createCouchdbDocJSON(key, revision, vv.Value, vv.Metadata, vv.Version, false)
 					if err != nil {
 						return err
 					}
@@ -689,7 +790,7 @@ func (vdb *VersionedDB) processUpdateBatch(updateBatch *statedb.UpdateBatch, mis
 					attachments := append([]*couchdb.AttachmentInfo{}, attachment)
 
 					couchDoc.Attachments = attachments
-					couchDoc.JSONValue, err = createCouchdbDocJSON(key, revision, nil, vv.Version, false)
+					couchDoc.JSONValue, err = createCouchdbDocJSON(key, revision, nil, vv.Metadata, vv.Version, false)
 					if err != nil {
 						return err
 					}
@@ -970,8 +1071,9 @@ func (vdb *VersionedDB) ClearCachedVersions() {
 // _rev - couchdb document revision, needed for updating or deleting existing documents
 // _deleted - flag used in batch operations for deleting a couchdb document
 // version - used for state validation
+// metadata - the metadata associated with the key, only added if set
 // The return value is the CouchDoc.JSONValue with the header fields populated
-func createCouchdbDocJSON(id, revision string, value []byte, version *version.Height, deleted bool) ([]byte, error) {
+func createCouchdbDocJSON(id, revision string, value []byte, metadata []byte, version *version.Height, deleted bool) ([]byte, error) {
 
 	// create a new genericMap
 	jsonMap := map[string]interface{}{}
@@ -992,6 +1094,11 @@ func createCouchdbDocJSON(id, revision string, value []byte, version *version.He
 	// add the version
 	jsonMap[versionField] = fmt.Sprintf("%v:%v", version.BlockNum, version.TxNum)
 
+	// add the metadata, which is marshaled as a base64 string
+	if metadata != nil {
+		jsonMap[metadataField] = metadata
+	}
+
 	// add the ID
 	jsonMap[idField] = id
 
@@ -1020,7 +1127,7 @@ func createCouchdbDocJSON(id, revision string, value []byte, version *version.He
 // checkReservedFieldsNotUsed verifies that the reserve field was not included
 func checkReservedFieldsNotUsed(jsonMap map[string]interface{}) error {
 	for fieldName := range jsonMap {
-		if fieldName == versionField || strings.HasPrefix(fieldName, "_") {
+		if fieldName == versionField || fieldName == metadataField || strings.HasPrefix(fieldName, "_") {
 			return fmt.Errorf("The field [%s] is not valid for the CouchDB state database", fieldName)
 		}
 	}
@@ -1150,76 +1257,116 @@ func splitCompositeKey(compositeKey []byte) (string, string) {
 }
 */
 
-type kvScanner struct {
-	cursor    int
+// queryScanner returns the results of a range query or of a rich query. The results are fetched from couchdb
+// in batches of the size `queryLimit` from the ledger config. When requestedLimit is greater than zero, the
+// scanner stops after returning requestedLimit results, and the bookmark for fetching the next page of the
+// results can be obtained by `GetBookmarkAndClose`
+type queryScanner struct {
 	namespace string
-	results   []couchdb.QueryResult
+	db        *couchdb.CouchDatabase
+	// startKey and endKey are used by a range query, startKey is advanced after fetching each batch
+	startKey string
+	endKey   string
+	// query is used by a rich query, bookmark is advanced after fetching each batch
+	query    string
+	bookmark string
+
+	requestedLimit       int32
+	totalRecordsReturned int32
+	results              []couchdb.QueryResult
+	cursor               int
+	exhausted            bool
 }
 
-func newKVScanner(namespace string, queryResults []couchdb.QueryResult) *kvScanner {
-	return &kvScanner{-1, namespace, queryResults}
+func (scanner *queryScanner) isRangeQuery() bool {
+	return scanner.query == ""
 }
 
-func (scanner *kvScanner) Next() (statedb.QueryResult, error) {
-
-	scanner.cursor++
-
-	if scanner.cursor >= len(scanner.results) {
-		return nil, nil
+// fetchNextBatch fetches the next batch of results from couchdb
+func (scanner *queryScanner) fetchNextBatch() error {
+	batchLimit := ledgerconfig.GetQueryLimit()
+	if scanner.requestedLimit > 0 {
+		if remaining := int(scanner.requestedLimit - scanner.totalRecordsReturned); remaining < batchLimit {
+			batchLimit = remaining
+		}
 	}
-
-	selectedKV := scanner.results[scanner.cursor]
-
-	key := selectedKV.ID
-
-	// remove the reserved fields from CouchDB JSON and return the value and version
-	returnValue, returnVersion, err := getValueAndVersionFromDoc(selectedKV.Value, selectedKV.Attachments)
-	if err != nil {
-		return nil, err
+	var queryResults *[]couchdb.QueryResult
+	var err error
+	if scanner.isRangeQuery() {
+		queryResults, scanner.startKey, err = scanner.db.ReadDocRange(scanner.startKey, scanner.endKey, batchLimit, 0)
+		if err != nil {
+			return err
+		}
+		scanner.exhausted = scanner.startKey == ""
+	} else {
+		queryString, err := applyAdditionalQueryOptions(scanner.query, batchLimit, scanner.bookmark)
+		if err != nil {
+			return err
+		}
+		queryResults, scanner.bookmark, err = scanner.db.QueryDocuments(queryString)
+		if err != nil {
+			return err
+		}
+		scanner.exhausted = len(*queryResults) < batchLimit
 	}
-
-	return &statedb.VersionedKV{
-		CompositeKey:   statedb.CompositeKey{Namespace: scanner.namespace, Key: key},
-		VersionedValue: statedb.VersionedValue{Value: returnValue, Version: returnVersion}}, nil
-}
-
-func (scanner *kvScanner) Close() {
-	scanner = nil
-}
-
-type queryScanner struct {
-	cursor    int
-	namespace string
-	results   []couchdb.QueryResult
-}
-
-func newQueryScanner(namespace string, queryResults []couchdb.QueryResult) *queryScanner {
-	return &queryScanner{-1, namespace, queryResults}
+	scanner.results = *queryResults
+	scanner.cursor = -1
+	return nil
 }
 
 func (scanner *queryScanner) Next() (statedb.QueryResult, error) {
+	if scanner.requestedLimit > 0 && scanner.totalRecordsReturned >= scanner.requestedLimit {
+		return nil, nil
+	}
 
 	scanner.cursor++
 
 	if scanner.cursor >= len(scanner.results) {
-		return nil, nil
+		if scanner.exhausted {
+			return nil, nil
+		}
+		if err := scanner.fetchNextBatch(); err != nil {
+			return nil, err
+		}
+		scanner.cursor++
+		if len(scanner.results) == 0 {
+			return nil, nil
+		}
 	}
 
 	selectedResultRecord := scanner.results[scanner.cursor]
 
 	key := selectedResultRecord.ID
 
-	// remove the reserved fields from CouchDB JSON and return the value and version
-	returnValue, returnVersion, err := getValueAndVersionFromDoc(selectedResultRecord.Value, selectedResultRecord.Attachments)
+	// remove the reserved fields from CouchDB JSON and return the value, metadata and version
+	vv, err := getVersionedValueFromDoc(selectedResultRecord.Value, selectedResultRecord.Attachments)
 	if err != nil {
 		return nil, err
 	}
+	scanner.totalRecordsReturned++
 
 	return &statedb.VersionedKV{
 		CompositeKey:   statedb.CompositeKey{Namespace: scanner.namespace, Key: key},
-		VersionedValue: statedb.VersionedValue{Value: returnValue, Version: returnVersion}}, nil
+		VersionedValue: *vv}, nil
 }
 
 func (scanner *queryScanner) Close() {
-	scanner = nil
+	scanner.results = nil
+}
+
+// GetBookmarkAndClose implements method in QueryResultsIterator interface. For a range query, the bookmark
+// is the key that follows the last returned key, i.e., the start key of the next page. For a rich query,
+// the bookmark is the one returned by couchdb. An empty bookmark is returned if there are no more results
+func (scanner *queryScanner) GetBookmarkAndClose() string {
+	bookmark := ""
+	switch {
+	case scanner.isRangeQuery() && scanner.cursor+1 < len(scanner.results):
+		bookmark = scanner.results[scanner.cursor+1].ID
+	case scanner.isRangeQuery():
+		bookmark = scanner.startKey
+	case !scanner.exhausted || scanner.cursor+1 < len(scanner.results):
+		bookmark = scanner.bookmark
+	}
+	scanner.Close()
+	return bookmark
 }
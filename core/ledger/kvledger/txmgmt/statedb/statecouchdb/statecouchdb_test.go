@@ -110,6 +110,20 @@ func TestIterator(t *testing.T) {
 	commontests.TestIterator(t, env.DBProvider)
 }
 
+func TestPaginatedRangeQuery(t *testing.T) {
+	// a small query limit makes the scanner fetch the results from couchdb in multiple requests
+	defer viper.Set("ledger.state.couchDBConfig.queryLimit", viper.GetInt("ledger.state.couchDBConfig.queryLimit"))
+	viper.Set("ledger.state.couchDBConfig.queryLimit", 2)
+	env := NewTestVDBEnv(t)
+	env.Cleanup("testpaginatedrangequery_")
+	env.Cleanup("testpaginatedrangequery_ns1")
+	env.Cleanup("testpaginatedrangequery_ns2")
+	defer env.Cleanup("testpaginatedrangequery_")
+	defer env.Cleanup("testpaginatedrangequery_ns1")
+	defer env.Cleanup("testpaginatedrangequery_ns2")
+	commontests.TestPaginatedRangeQuery(t, env.DBProvider)
+}
+
 func TestEncodeDecodeValueAndVersion(t *testing.T) {
 	testValueAndVersionEncoding(t, []byte("value1"), version.NewHeight(1, 2))
 	testValueAndVersionEncoding(t, []byte{}, version.NewHeight(50, 50))
@@ -137,6 +151,17 @@ func TestQuery(t *testing.T) {
 	commontests.TestQuery(t, env.DBProvider)
 }
 
+func TestPaginatedQuery(t *testing.T) {
+	defer viper.Set("ledger.state.couchDBConfig.queryLimit", viper.GetInt("ledger.state.couchDBConfig.queryLimit"))
+	viper.Set("ledger.state.couchDBConfig.queryLimit", 2)
+	env := NewTestVDBEnv(t)
+	env.Cleanup("testpaginatedquery_")
+	env.Cleanup("testpaginatedquery_ns1")
+	defer env.Cleanup("testpaginatedquery_")
+	defer env.Cleanup("testpaginatedquery_ns1")
+	commontests.TestPaginatedQuery(t, env.DBProvider)
+}
+
 func TestGetStateMultipleKeys(t *testing.T) {
 
 	env := NewTestVDBEnv(t)
@@ -160,6 +185,64 @@ func TestGetVersion(t *testing.T) {
 	commontests.TestGetVersion(t, env.DBProvider)
 }
 
+func TestStateCache(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	env.Cleanup("teststatecache_")
+	env.Cleanup("teststatecache_ns")
+	defer env.Cleanup("teststatecache_")
+	defer env.Cleanup("teststatecache_ns")
+
+	db, err := env.DBProvider.GetDBHandle("teststatecache")
+	testutil.AssertNoError(t, err, "")
+	db.Open()
+	defer db.Close()
+
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns", "key1", []byte("value1"), version.NewHeight(1, 1))
+	testutil.AssertNoError(t, db.ApplyUpdates(batch, version.NewHeight(1, 1)), "")
+
+	// the first reads go to CouchDB, the following ones are served from the cache
+	for i := 0; i < 2; i++ {
+		vv, err := db.GetState("ns", "key1")
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertEquals(t, vv.Value, []byte("value1"))
+		vv, err = db.GetState("ns", "key2")
+		testutil.AssertNoError(t, err, "")
+		testutil.AssertNil(t, vv)
+	}
+	cache := db.(*VersionedDB).stateCache
+	testutil.AssertEquals(t, cache.stats(), cacheStats{Hits: 2, Misses: 2})
+
+	ver, err := db.GetVersion("ns", "key1")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, ver, version.NewHeight(1, 1))
+
+	// the cache is kept up to date on commit
+	batch = statedb.NewUpdateBatch()
+	batch.Delete("ns", "key1", version.NewHeight(2, 1))
+	batch.Put("ns", "key2", []byte("value2"), version.NewHeight(2, 2))
+	testutil.AssertNoError(t, db.ApplyUpdates(batch, version.NewHeight(2, 2)), "")
+
+	vv, err := db.GetState("ns", "key1")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, vv)
+	vv, err = db.GetState("ns", "key2")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, vv.Value, []byte("value2"))
+	testutil.AssertEquals(t, cache.stats(), cacheStats{Hits: 5, Misses: 2})
+}
+
+func TestValueAndMetadataWrites(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	env.Cleanup("testvalueandmetadata_")
+	env.Cleanup("testvalueandmetadata_ns1")
+	env.Cleanup("testvalueandmetadata_ns2")
+	defer env.Cleanup("testvalueandmetadata_")
+	defer env.Cleanup("testvalueandmetadata_ns1")
+	defer env.Cleanup("testvalueandmetadata_ns2")
+	commontests.TestValueAndMetadataWrites(t, env.DBProvider)
+}
+
 func TestSmallBatchSize(t *testing.T) {
 	viper.Set("ledger.state.couchDBConfig.maxBatchUpdateSize", 2)
 	env := NewTestVDBEnv(t)
@@ -6,6 +6,7 @@ SPDX-License-Identifier: Apache-2.0
 package statedb
 
 import (
+	"fmt"
 	"sort"
 
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
@@ -33,8 +34,18 @@ type VersionedDB interface {
 	// endKey is exclusive
 	// The returned ResultsIterator contains results of type *VersionedKV
 	GetStateRangeScanIterator(namespace string, startKey string, endKey string) (ResultsIterator, error)
+	// GetStateRangeScanIteratorWithMetadata returns an iterator that contains all the key-values between given key ranges.
+	// startKey is inclusive
+	// endKey is exclusive
+	// metadata is a map of additional query parameters, see `ValidateRangeMetadata` for the supported parameters
+	// The returned QueryResultsIterator contains results of type *VersionedKV
+	GetStateRangeScanIteratorWithMetadata(namespace string, startKey string, endKey string, metadata map[string]interface{}) (QueryResultsIterator, error)
 	// ExecuteQuery executes the given query and returns an iterator that contains results of type *VersionedKV.
 	ExecuteQuery(namespace, query string) (ResultsIterator, error)
+	// ExecuteQueryWithMetadata executes the given query with the associated query options and
+	// returns an iterator that contains results of type *VersionedKV.
+	// metadata is a map of additional query parameters, see `ValidateQueryMetadata` for the supported parameters
+	ExecuteQueryWithMetadata(namespace, query string, metadata map[string]interface{}) (QueryResultsIterator, error)
 	// ApplyUpdates applies the batch to the underlying db.
 	// height is the height of the highest transaction in the Batch that
 	// a state db implementation is expected to ues as a save point
@@ -62,16 +73,69 @@ type BulkOptimizable interface {
 	ClearCachedVersions()
 }
 
+// FullScannable interface is implemented by the databases that support iterating over all the keys
+// of all the namespaces, which is required for exporting a snapshot of the state
+type FullScannable interface {
+	// GetFullScanIterator returns an iterator over all the keys ordered by namespace and key.
+	// The keys of the namespaces for which `skipNamespace` returns true are not included in the results.
+	// The returned ResultsIterator contains results of type *VersionedKV
+	GetFullScanIterator(skipNamespace func(namespace string) bool) (ResultsIterator, error)
+}
+
+const (
+	// QueryOptionLimit is the metadata key for the maximum number of records, of type int32, to be returned by a query
+	QueryOptionLimit = "limit"
+	// QueryOptionBookmark is the metadata key for the bookmark, of type string, returned by the previous page of a query
+	QueryOptionBookmark = "bookmark"
+)
+
+// ValidateRangeMetadata validates the metadata of a range query. The only supported option is `QueryOptionLimit`.
+// The bookmark of a range query is the key from which the next page starts, hence it is supplied as the start key
+func ValidateRangeMetadata(metadata map[string]interface{}) error {
+	for option, value := range metadata {
+		switch option {
+		case QueryOptionLimit:
+			if _, ok := value.(int32); !ok {
+				return fmt.Errorf("invalid entry, \"%s\" must be an int32", QueryOptionLimit)
+			}
+		default:
+			return fmt.Errorf("invalid entry, option [%s] not recognized", option)
+		}
+	}
+	return nil
+}
+
+// ValidateQueryMetadata validates the metadata of a rich query. The supported options are
+// `QueryOptionLimit` and `QueryOptionBookmark`
+func ValidateQueryMetadata(metadata map[string]interface{}) error {
+	for option, value := range metadata {
+		switch option {
+		case QueryOptionLimit:
+			if _, ok := value.(int32); !ok {
+				return fmt.Errorf("invalid entry, \"%s\" must be an int32", QueryOptionLimit)
+			}
+		case QueryOptionBookmark:
+			if _, ok := value.(string); !ok {
+				return fmt.Errorf("invalid entry, \"%s\" must be a string", QueryOptionBookmark)
+			}
+		default:
+			return fmt.Errorf("invalid entry, option [%s] not recognized", option)
+		}
+	}
+	return nil
+}
+
 // CompositeKey encloses Namespace and Key components
 type CompositeKey struct {
 	Namespace string
 	Key       string
 }
 
-// VersionedValue encloses value and corresponding version
+// VersionedValue encloses value, metadata and corresponding version
 type VersionedValue struct {
-	Value   []byte
-	Version *version.Height
+	Value    []byte
+	Metadata []byte
+	Version  *version.Height
 }
 
 // VersionedKV encloses key and corresponding VersionedValue
@@ -86,6 +150,14 @@ type ResultsIterator interface {
 	Close()
 }
 
+// QueryResultsIterator adds GetBookmarkAndClose method
+type QueryResultsIterator interface {
+	ResultsIterator
+	// GetBookmarkAndClose returns the bookmark for fetching the next page of the results and releases the
+	// resources held by the iterator. An empty bookmark is returned if there are no more results
+	GetBookmarkAndClose() string
+}
+
 // QueryResult - a general interface for supporting different types of query results. Actual types differ for different queries
 type QueryResult interface{}
 
@@ -125,12 +197,20 @@ func (batch *UpdateBatch) Put(ns string, key string, value []byte, version *vers
 	if value == nil {
 		panic("Nil value not allowed")
 	}
-	batch.Update(ns, key, &VersionedValue{value, version})
+	batch.Update(ns, key, &VersionedValue{Value: value, Version: version})
+}
+
+// PutValAndMetadata adds a VersionedKV along with the metadata associated with the key
+func (batch *UpdateBatch) PutValAndMetadata(ns string, key string, value []byte, metadata []byte, version *version.Height) {
+	if value == nil {
+		panic("Nil value not allowed")
+	}
+	batch.Update(ns, key, &VersionedValue{Value: value, Metadata: metadata, Version: version})
 }
 
 // Delete deletes a Key and associated value
 func (batch *UpdateBatch) Delete(ns string, key string, version *version.Height) {
-	batch.Update(ns, key, &VersionedValue{nil, version})
+	batch.Update(ns, key, &VersionedValue{Version: version})
 }
 
 // Exists checks whether the given key exists in the batch
@@ -225,7 +305,7 @@ func (itr *nsIterator) Next() (QueryResult, error) {
 	key := itr.sortedKeys[itr.nextIndex]
 	vv := itr.nsUpdates.m[key]
 	itr.nextIndex++
-	return &VersionedKV{CompositeKey{itr.ns, key}, VersionedValue{vv.Value, vv.Version}}, nil
+	return &VersionedKV{CompositeKey{itr.ns, key}, VersionedValue{Value: vv.Value, Metadata: vv.Metadata, Version: vv.Version}}, nil
 }
 
 // Close implements the method from QueryResult interface
@@ -105,19 +105,19 @@ func TestUpdateBatchIterator(t *testing.T) {
 	batch.Put("ns2", "key4", []byte("value4"), version.NewHeight(2, 1))
 
 	checkItrResults(t, batch.GetRangeScanIterator("ns1", "key2", "key3"), []*VersionedKV{
-		{CompositeKey{"ns1", "key2"}, VersionedValue{[]byte("value2"), version.NewHeight(1, 2)}},
+		{CompositeKey{"ns1", "key2"}, VersionedValue{Value: []byte("value2"), Version: version.NewHeight(1, 2)}},
 	})
 
 	checkItrResults(t, batch.GetRangeScanIterator("ns2", "key0", "key8"), []*VersionedKV{
-		{CompositeKey{"ns2", "key4"}, VersionedValue{[]byte("value4"), version.NewHeight(2, 1)}},
-		{CompositeKey{"ns2", "key5"}, VersionedValue{[]byte("value5"), version.NewHeight(2, 2)}},
-		{CompositeKey{"ns2", "key6"}, VersionedValue{[]byte("value6"), version.NewHeight(2, 3)}},
+		{CompositeKey{"ns2", "key4"}, VersionedValue{Value: []byte("value4"), Version: version.NewHeight(2, 1)}},
+		{CompositeKey{"ns2", "key5"}, VersionedValue{Value: []byte("value5"), Version: version.NewHeight(2, 2)}},
+		{CompositeKey{"ns2", "key6"}, VersionedValue{Value: []byte("value6"), Version: version.NewHeight(2, 3)}},
 	})
 
 	checkItrResults(t, batch.GetRangeScanIterator("ns2", "", ""), []*VersionedKV{
-		{CompositeKey{"ns2", "key4"}, VersionedValue{[]byte("value4"), version.NewHeight(2, 1)}},
-		{CompositeKey{"ns2", "key5"}, VersionedValue{[]byte("value5"), version.NewHeight(2, 2)}},
-		{CompositeKey{"ns2", "key6"}, VersionedValue{[]byte("value6"), version.NewHeight(2, 3)}},
+		{CompositeKey{"ns2", "key4"}, VersionedValue{Value: []byte("value4"), Version: version.NewHeight(2, 1)}},
+		{CompositeKey{"ns2", "key5"}, VersionedValue{Value: []byte("value5"), Version: version.NewHeight(2, 2)}},
+		{CompositeKey{"ns2", "key6"}, VersionedValue{Value: []byte("value6"), Version: version.NewHeight(2, 3)}},
 	})
 
 	checkItrResults(t, batch.GetRangeScanIterator("non-existing-ns", "", ""), nil)
@@ -133,3 +133,13 @@ func checkItrResults(t *testing.T, itr ResultsIterator, expectedResults []*Versi
 	testutil.AssertNil(t, lastRes)
 	itr.Close()
 }
+
+func TestValidateMetadata(t *testing.T) {
+	testutil.AssertNoError(t, ValidateRangeMetadata(map[string]interface{}{"limit": int32(10)}), "")
+	testutil.AssertError(t, ValidateRangeMetadata(map[string]interface{}{"limit": 10}), "limit should be an int32")
+	testutil.AssertError(t, ValidateRangeMetadata(map[string]interface{}{"bookmark": "key1"}), "bookmark is not supported for range queries")
+
+	testutil.AssertNoError(t, ValidateQueryMetadata(map[string]interface{}{"limit": int32(10), "bookmark": "bookmark1"}), "")
+	testutil.AssertError(t, ValidateQueryMetadata(map[string]interface{}{"bookmark": 10}), "bookmark should be a string")
+	testutil.AssertError(t, ValidateQueryMetadata(map[string]interface{}{"skip": int32(10)}), "skip is not supported")
+}
@@ -89,8 +89,8 @@ func (vdb *versionedDB) GetState(namespace string, key string) (*statedb.Version
 	if dbVal == nil {
 		return nil, nil
 	}
-	val, ver := statedb.DecodeValue(dbVal)
-	return &statedb.VersionedValue{Value: val, Version: ver}, nil
+	val, metadata, ver := statedb.DecodeValueAndMetadata(dbVal)
+	return &statedb.VersionedValue{Value: val, Metadata: metadata, Version: ver}, nil
 }
 
 // GetVersion implements method in VersionedDB interface
@@ -122,13 +122,30 @@ func (vdb *versionedDB) GetStateMultipleKeys(namespace string, keys []string) ([
 // startKey is inclusive
 // endKey is exclusive
 func (vdb *versionedDB) GetStateRangeScanIterator(namespace string, startKey string, endKey string) (statedb.ResultsIterator, error) {
+	return vdb.GetStateRangeScanIteratorWithMetadata(namespace, startKey, endKey, nil)
+}
+
+// GetStateRangeScanIteratorWithMetadata implements method in VersionedDB interface
+// startKey is inclusive
+// endKey is exclusive
+// metadata contains the limit on the number of records to be returned
+func (vdb *versionedDB) GetStateRangeScanIteratorWithMetadata(namespace string, startKey string, endKey string, metadata map[string]interface{}) (statedb.QueryResultsIterator, error) {
+	requestedLimit := int32(0)
+	if metadata != nil {
+		if err := statedb.ValidateRangeMetadata(metadata); err != nil {
+			return nil, err
+		}
+		if limit, ok := metadata[statedb.QueryOptionLimit]; ok {
+			requestedLimit = limit.(int32)
+		}
+	}
 	compositeStartKey := constructCompositeKey(namespace, startKey)
 	compositeEndKey := constructCompositeKey(namespace, endKey)
 	if endKey == "" {
 		compositeEndKey[len(compositeEndKey)-1] = lastKeyIndicator
 	}
 	dbItr := vdb.db.GetIterator(compositeStartKey, compositeEndKey)
-	return newKVScanner(namespace, dbItr), nil
+	return newKVScanner(namespace, dbItr, requestedLimit), nil
 }
 
 // ExecuteQuery implements method in VersionedDB interface
@@ -136,6 +153,17 @@ func (vdb *versionedDB) ExecuteQuery(namespace, query string) (statedb.ResultsIt
 	return nil, errors.New("ExecuteQuery not supported for leveldb")
 }
 
+// ExecuteQueryWithMetadata implements method in VersionedDB interface
+func (vdb *versionedDB) ExecuteQueryWithMetadata(namespace, query string, metadata map[string]interface{}) (statedb.QueryResultsIterator, error) {
+	return nil, errors.New("ExecuteQueryWithMetadata not supported for leveldb")
+}
+
+// GetFullScanIterator implements method in FullScannable interface
+func (vdb *versionedDB) GetFullScanIterator(skipNamespace func(namespace string) bool) (statedb.ResultsIterator, error) {
+	dbItr := vdb.db.GetIterator(nil, nil)
+	return &fullDBScanner{dbItr: dbItr, skipNamespace: skipNamespace}, nil
+}
+
 // ApplyUpdates implements method in VersionedDB interface
 func (vdb *versionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version.Height) error {
 	dbBatch := leveldbhelper.NewUpdateBatch()
@@ -149,7 +177,7 @@ func (vdb *versionedDB) ApplyUpdates(batch *statedb.UpdateBatch, height *version
 			if vv.Value == nil {
 				dbBatch.Delete(compositeKey)
 			} else {
-				dbBatch.Put(compositeKey, statedb.EncodeValue(vv.Value, vv.Version))
+				dbBatch.Put(compositeKey, statedb.EncodeValueAndMetadata(vv.Value, vv.Metadata, vv.Version))
 			}
 		}
 	}
@@ -183,30 +211,85 @@ func splitCompositeKey(compositeKey []byte) (string, string) {
 	return string(split[0]), string(split[1])
 }
 
+// kvScanner returns the key-values of a range query. When requestedLimit is greater than zero,
+// the scanner stops after returning requestedLimit results
 type kvScanner struct {
-	namespace string
-	dbItr     iterator.Iterator
+	namespace            string
+	dbItr                iterator.Iterator
+	requestedLimit       int32
+	totalRecordsReturned int32
 }
 
-func newKVScanner(namespace string, dbItr iterator.Iterator) *kvScanner {
-	return &kvScanner{namespace, dbItr}
+func newKVScanner(namespace string, dbItr iterator.Iterator, requestedLimit int32) *kvScanner {
+	return &kvScanner{namespace, dbItr, requestedLimit, 0}
 }
 
 func (scanner *kvScanner) Next() (statedb.QueryResult, error) {
+	if scanner.requestedLimit > 0 && scanner.totalRecordsReturned >= scanner.requestedLimit {
+		return nil, nil
+	}
 	if !scanner.dbItr.Next() {
 		return nil, nil
 	}
+	scanner.totalRecordsReturned++
 	dbKey := scanner.dbItr.Key()
 	dbVal := scanner.dbItr.Value()
 	dbValCopy := make([]byte, len(dbVal))
 	copy(dbValCopy, dbVal)
 	_, key := splitCompositeKey(dbKey)
-	value, version := statedb.DecodeValue(dbValCopy)
+	value, metadata, version := statedb.DecodeValueAndMetadata(dbValCopy)
 	return &statedb.VersionedKV{
 		CompositeKey:   statedb.CompositeKey{Namespace: scanner.namespace, Key: key},
-		VersionedValue: statedb.VersionedValue{Value: value, Version: version}}, nil
+		VersionedValue: statedb.VersionedValue{Value: value, Metadata: metadata, Version: version}}, nil
 }
 
 func (scanner *kvScanner) Close() {
 	scanner.dbItr.Release()
 }
+
+// GetBookmarkAndClose implements method in QueryResultsIterator interface. The bookmark
+// is the key that follows the last returned key, i.e., the start key of the next page
+func (scanner *kvScanner) GetBookmarkAndClose() string {
+	bookmark := ""
+	if scanner.dbItr.Next() {
+		_, bookmark = splitCompositeKey(scanner.dbItr.Key())
+	}
+	scanner.Close()
+	return bookmark
+}
+
+type fullDBScanner struct {
+	dbItr         iterator.Iterator
+	skipNamespace func(namespace string) bool
+	lastNamespace string
+	skipLast      bool
+}
+
+func (scanner *fullDBScanner) Next() (statedb.QueryResult, error) {
+	for scanner.dbItr.Next() {
+		dbKey := scanner.dbItr.Key()
+		if bytes.Equal(dbKey, savePointKey) {
+			continue
+		}
+		namespace, key := splitCompositeKey(dbKey)
+		if namespace != scanner.lastNamespace {
+			scanner.lastNamespace = namespace
+			scanner.skipLast = scanner.skipNamespace(namespace)
+		}
+		if scanner.skipLast {
+			continue
+		}
+		dbVal := scanner.dbItr.Value()
+		dbValCopy := make([]byte, len(dbVal))
+		copy(dbValCopy, dbVal)
+		value, metadata, version := statedb.DecodeValueAndMetadata(dbValCopy)
+		return &statedb.VersionedKV{
+			CompositeKey:   statedb.CompositeKey{Namespace: namespace, Key: key},
+			VersionedValue: statedb.VersionedValue{Value: value, Metadata: metadata, Version: version}}, nil
+	}
+	return nil, nil
+}
+
+func (scanner *fullDBScanner) Close() {
+	scanner.dbItr.Release()
+}
@@ -56,6 +56,12 @@ func TestIterator(t *testing.T) {
 	commontests.TestIterator(t, env.DBProvider)
 }
 
+func TestPaginatedRangeQuery(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestPaginatedRangeQuery(t, env.DBProvider)
+}
+
 func TestEncodeDecodeValueAndVersion(t *testing.T) {
 	testValueAndVersionEncoding(t, []byte("value1"), version.NewHeight(1, 2))
 	testValueAndVersionEncoding(t, []byte{}, version.NewHeight(50, 50))
@@ -116,6 +122,12 @@ func TestGetVersion(t *testing.T) {
 	commontests.TestGetVersion(t, env.DBProvider)
 }
 
+func TestValueAndMetadataWrites(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	commontests.TestValueAndMetadataWrites(t, env.DBProvider)
+}
+
 func TestUtilityFunctions(t *testing.T) {
 	env := NewTestVDBEnv(t)
 	defer env.Cleanup()
@@ -130,3 +142,36 @@ func TestUtilityFunctions(t *testing.T) {
 	// ValidateKeyValue should return nil for a valid key and value
 	testutil.AssertNoError(t, db.ValidateKeyValue("testKey", []byte("testValue")), "leveldb should accept all key-values")
 }
+
+func TestFullScanIterator(t *testing.T) {
+	env := NewTestVDBEnv(t)
+	defer env.Cleanup()
+	db, err := env.DBProvider.GetDBHandle("testfullscan")
+	testutil.AssertNoError(t, err, "")
+	batch := statedb.NewUpdateBatch()
+	batch.Put("ns1", "key1", []byte("value1"), version.NewHeight(1, 1))
+	batch.Put("ns1", "key2", []byte("value2"), version.NewHeight(1, 2))
+	batch.Put("ns2", "key1", []byte("value3"), version.NewHeight(1, 3))
+	batch.PutValAndMetadata("ns3", "key1", []byte("value4"), []byte("metadata"), version.NewHeight(2, 1))
+	db.ApplyUpdates(batch, version.NewHeight(2, 1))
+
+	itr, err := db.(statedb.FullScannable).GetFullScanIterator(func(ns string) bool { return ns == "ns2" })
+	testutil.AssertNoError(t, err, "")
+	defer itr.Close()
+	var results []*statedb.VersionedKV
+	for {
+		res, err := itr.Next()
+		testutil.AssertNoError(t, err, "")
+		if res == nil {
+			break
+		}
+		results = append(results, res.(*statedb.VersionedKV))
+	}
+	testutil.AssertEquals(t, len(results), 3)
+	testutil.AssertEquals(t, results[0].CompositeKey, statedb.CompositeKey{Namespace: "ns1", Key: "key1"})
+	testutil.AssertEquals(t, results[1].CompositeKey, statedb.CompositeKey{Namespace: "ns1", Key: "key2"})
+	testutil.AssertEquals(t, results[2].CompositeKey, statedb.CompositeKey{Namespace: "ns3", Key: "key1"})
+	testutil.AssertEquals(t, results[2].Value, []byte("value4"))
+	testutil.AssertEquals(t, results[2].Metadata, []byte("metadata"))
+	testutil.AssertEquals(t, results[2].Version, version.NewHeight(2, 1))
+}
@@ -16,7 +16,15 @@ limitations under the License.
 
 package statedb
 
-import "github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+)
+
+// metadataMarker prefixes the encoding of a value that carries metadata. The encoding
+// produced by EncodeValue starts with the size of the block number (0 to 8), hence
+// it can never start with this byte
+const metadataMarker = byte(0xff)
 
 //EncodeValue appends the value to the version, allows storage of version and value in binary form
 func EncodeValue(value []byte, version *version.Height) []byte {
@@ -33,3 +41,30 @@ func DecodeValue(encodedValue []byte) ([]byte, *version.Height) {
 	value := encodedValue[n:]
 	return value, height
 }
+
+//EncodeValueAndMetadata encodes the value, the metadata and the version. When there is no metadata,
+//the encoding is the same as the one produced by EncodeValue
+func EncodeValueAndMetadata(value []byte, metadata []byte, version *version.Height) []byte {
+	if metadata == nil {
+		return EncodeValue(value, version)
+	}
+	encodedValue := append([]byte{metadataMarker}, version.ToBytes()...)
+	encodedValue = append(encodedValue, proto.EncodeVarint(uint64(len(metadata)))...)
+	encodedValue = append(encodedValue, metadata...)
+	return append(encodedValue, value...)
+}
+
+//DecodeValueAndMetadata separates the version, the metadata and the value from a binary value
+//produced either by EncodeValueAndMetadata or by EncodeValue
+func DecodeValueAndMetadata(encodedValue []byte) ([]byte, []byte, *version.Height) {
+	if len(encodedValue) == 0 || encodedValue[0] != metadataMarker {
+		value, height := DecodeValue(encodedValue)
+		return value, nil, height
+	}
+	height, n := version.NewHeightFromBytes(encodedValue[1:])
+	encodedValue = encodedValue[1+n:]
+	metadataLen, n := proto.DecodeVarint(encodedValue)
+	metadata := encodedValue[n : n+int(metadataLen)]
+	value := encodedValue[n+int(metadataLen):]
+	return value, metadata, height
+}
@@ -52,3 +52,22 @@ func TestEncodeDecodeJSON(t *testing.T) {
 	testutil.AssertEquals(t, decodedVersion, version2)
 
 }
+
+// TestEncodeDecodeValueAndMetadata tests encoding and decoding a value along with its metadata
+func TestEncodeDecodeValueAndMetadata(t *testing.T) {
+	value := []byte("value1")
+	metadata := []byte("metadata1")
+	version1 := version.NewHeight(0, 0)
+
+	decodedValue, decodedMetadata, decodedVersion := DecodeValueAndMetadata(EncodeValueAndMetadata(value, metadata, version1))
+	testutil.AssertEquals(t, decodedValue, value)
+	testutil.AssertEquals(t, decodedMetadata, metadata)
+	testutil.AssertEquals(t, decodedVersion, version1)
+
+	// values encoded without metadata remain decodable
+	decodedValue, decodedMetadata, decodedVersion = DecodeValueAndMetadata(EncodeValue(value, version1))
+	testutil.AssertEquals(t, decodedValue, value)
+	testutil.AssertNil(t, decodedMetadata)
+	testutil.AssertEquals(t, decodedVersion, version1)
+	testutil.AssertEquals(t, EncodeValueAndMetadata(value, nil, version1), EncodeValue(value, version1))
+}
@@ -0,0 +1,67 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statemetadata
+
+import (
+	"sort"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	"github.com/pkg/errors"
+)
+
+// ToEntries converts the given metadata into entries sorted by name.
+// An empty metadata results in no entries, which stands for the deletion of the metadata of a key
+func ToEntries(metadata map[string][]byte) []*kvrwset.KVMetadataEntry {
+	var names []string
+	for name := range metadata {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var entries []*kvrwset.KVMetadataEntry
+	for _, name := range names {
+		entries = append(entries, &kvrwset.KVMetadataEntry{Name: name, Value: metadata[name]})
+	}
+	return entries
+}
+
+// ToMap converts the given metadata entries into a map keyed by the names of the entries
+func ToMap(entries []*kvrwset.KVMetadataEntry) map[string][]byte {
+	if len(entries) == 0 {
+		return nil
+	}
+	metadata := make(map[string][]byte)
+	for _, entry := range entries {
+		metadata[entry.Name] = entry.Value
+	}
+	return metadata
+}
+
+// Serialize converts the given metadata entries into the bytes that are stored in the state db
+// along with the value of a key. No entries result in nil bytes
+func Serialize(entries []*kvrwset.KVMetadataEntry) ([]byte, error) {
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	b, err := proto.Marshal(&kvrwset.KVMetadataWrite{Entries: entries})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed serializing metadata")
+	}
+	return b, nil
+}
+
+// Deserialize converts the bytes produced by Serialize back into a map keyed by the names of the entries
+func Deserialize(b []byte) (map[string][]byte, error) {
+	if b == nil {
+		return nil, nil
+	}
+	metadata := &kvrwset.KVMetadataWrite{}
+	if err := proto.Unmarshal(b, metadata); err != nil {
+		return nil, errors.Wrap(err, "failed deserializing metadata")
+	}
+	return ToMap(metadata.Entries), nil
+}
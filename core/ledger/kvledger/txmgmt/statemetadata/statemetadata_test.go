@@ -0,0 +1,46 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package statemetadata
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEntries(t *testing.T) {
+	entries := ToEntries(map[string][]byte{"name2": []byte("value2"), "name1": []byte("value1")})
+	assert.Equal(t, []*kvrwset.KVMetadataEntry{
+		{Name: "name1", Value: []byte("value1")},
+		{Name: "name2", Value: []byte("value2")},
+	}, entries)
+	assert.Equal(t, map[string][]byte{"name2": []byte("value2"), "name1": []byte("value1")}, ToMap(entries))
+
+	assert.Nil(t, ToEntries(nil))
+	assert.Nil(t, ToMap(nil))
+}
+
+func TestSerialization(t *testing.T) {
+	metadata := map[string][]byte{"name1": []byte("value1"), "name2": nil}
+	b, err := Serialize(ToEntries(metadata))
+	assert.NoError(t, err)
+	deserialized, err := Deserialize(b)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value1"), deserialized["name1"])
+	assert.Contains(t, deserialized, "name2")
+
+	b, err = Serialize(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, b)
+	deserialized, err = Deserialize(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, deserialized)
+
+	_, err = Deserialize([]byte{1, 2, 3})
+	assert.Contains(t, err.Error(), "failed deserializing metadata")
+}
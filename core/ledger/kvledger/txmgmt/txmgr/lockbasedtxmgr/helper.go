@@ -25,6 +25,7 @@ import (
 	commonledger "github.com/hyperledger/fabric/common/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statemetadata"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/util"
@@ -74,11 +75,15 @@ func (h *queryHelper) getStateMultipleKeys(namespace string, keys []string) ([][
 	return values, nil
 }
 
-func (h *queryHelper) getStateRangeScanIterator(namespace string, startKey string, endKey string) (commonledger.ResultsIterator, error) {
+func (h *queryHelper) getStateRangeScanIterator(namespace string, startKey string, endKey string) (commonledger.QueryResultsIterator, error) {
+	return h.getStateRangeScanIteratorWithMetadata(namespace, startKey, endKey, nil)
+}
+
+func (h *queryHelper) getStateRangeScanIteratorWithMetadata(namespace string, startKey string, endKey string, metadata map[string]interface{}) (commonledger.QueryResultsIterator, error) {
 	if err := h.checkDone(); err != nil {
 		return nil, err
 	}
-	itr, err := newResultsItr(namespace, startKey, endKey, h.txmgr.db, h.rwsetBuilder,
+	itr, err := newResultsItr(namespace, startKey, endKey, metadata, h.txmgr.db, h.rwsetBuilder,
 		ledgerconfig.IsQueryReadsHashingEnabled(), ledgerconfig.GetMaxDegreeQueryReadsHashing())
 	if err != nil {
 		return nil, err
@@ -98,6 +103,17 @@ func (h *queryHelper) executeQuery(namespace, query string) (commonledger.Result
 	return &queryResultsItr{DBItr: dbItr, RWSetBuilder: h.rwsetBuilder}, nil
 }
 
+func (h *queryHelper) executeQueryWithMetadata(namespace, query string, metadata map[string]interface{}) (commonledger.QueryResultsIterator, error) {
+	if err := h.checkDone(); err != nil {
+		return nil, err
+	}
+	dbItr, err := h.txmgr.db.ExecuteQueryWithMetadata(namespace, query, metadata)
+	if err != nil {
+		return nil, err
+	}
+	return &queryResultsItr{DBItr: dbItr, RWSetBuilder: h.rwsetBuilder}, nil
+}
+
 func (h *queryHelper) getPrivateData(ns, coll, key string) ([]byte, error) {
 	if err := h.checkDone(); err != nil {
 		return nil, err
@@ -169,6 +185,50 @@ func (h *queryHelper) executeQueryOnPrivateData(namespace, collection, query str
 	return &pvtdataResultsItr{namespace, collection, dbItr}, nil
 }
 
+func (h *queryHelper) getStateMetadata(ns string, key string) (map[string][]byte, error) {
+	if err := h.checkDone(); err != nil {
+		return nil, err
+	}
+	versionedValue, err := h.txmgr.db.GetState(ns, key)
+	if err != nil {
+		return nil, err
+	}
+	metadataBytes, ver := decomposeVersionedValueMetadata(versionedValue)
+	if h.rwsetBuilder != nil {
+		h.rwsetBuilder.AddToReadSet(ns, key, ver)
+	}
+	return statemetadata.Deserialize(metadataBytes)
+}
+
+func (h *queryHelper) getPrivateDataMetadata(ns, coll, key string) (map[string][]byte, error) {
+	if err := h.checkDone(); err != nil {
+		return nil, err
+	}
+	versionedValue, err := h.txmgr.db.GetValueHash(ns, coll, util.ComputeStringHash(key))
+	if err != nil {
+		return nil, err
+	}
+	metadataBytes, ver := decomposeVersionedValueMetadata(versionedValue)
+	if h.rwsetBuilder != nil {
+		if err := h.rwsetBuilder.AddToHashedReadSet(ns, coll, key, ver); err != nil {
+			return nil, err
+		}
+	}
+	return statemetadata.Deserialize(metadataBytes)
+}
+
+func (h *queryHelper) getPrivateDataMetadataByHash(ns, coll string, keyhash []byte) (map[string][]byte, error) {
+	if err := h.checkDone(); err != nil {
+		return nil, err
+	}
+	versionedValue, err := h.txmgr.db.GetValueHash(ns, coll, keyhash)
+	if err != nil {
+		return nil, err
+	}
+	metadataBytes, _ := decomposeVersionedValueMetadata(versionedValue)
+	return statemetadata.Deserialize(metadataBytes)
+}
+
 func (h *queryHelper) done() {
 	if h.doneInvoked {
 		return
@@ -220,9 +280,15 @@ type resultsItr struct {
 	rangeQueryResultsHelper *rwsetutil.RangeQueryResultsHelper
 }
 
-func newResultsItr(ns string, startKey string, endKey string,
+func newResultsItr(ns string, startKey string, endKey string, metadata map[string]interface{},
 	db statedb.VersionedDB, rwsetBuilder *rwsetutil.RWSetBuilder, enableHashing bool, maxDegree uint32) (*resultsItr, error) {
-	dbItr, err := db.GetStateRangeScanIterator(ns, startKey, endKey)
+	var dbItr statedb.ResultsIterator
+	var err error
+	if metadata == nil {
+		dbItr, err = db.GetStateRangeScanIterator(ns, startKey, endKey)
+	} else {
+		dbItr, err = db.GetStateRangeScanIteratorWithMetadata(ns, startKey, endKey, metadata)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -291,6 +357,11 @@ func (itr *resultsItr) Close() {
 	itr.dbItr.Close()
 }
 
+// GetBookmarkAndClose implements method in interface ledger.QueryResultsIterator
+func (itr *resultsItr) GetBookmarkAndClose() string {
+	return getBookmarkAndClose(itr.dbItr)
+}
+
 type queryResultsItr struct {
 	DBItr        statedb.ResultsIterator
 	RWSetBuilder *rwsetutil.RWSetBuilder
@@ -320,6 +391,21 @@ func (itr *queryResultsItr) Close() {
 	itr.DBItr.Close()
 }
 
+// GetBookmarkAndClose implements method in interface ledger.QueryResultsIterator
+func (itr *queryResultsItr) GetBookmarkAndClose() string {
+	return getBookmarkAndClose(itr.DBItr)
+}
+
+// getBookmarkAndClose returns the bookmark of the given db iterator if it supports pagination,
+// otherwise, it closes the iterator and returns an empty bookmark
+func getBookmarkAndClose(dbItr statedb.ResultsIterator) string {
+	if queryResultsItr, ok := dbItr.(statedb.QueryResultsIterator); ok {
+		return queryResultsItr.GetBookmarkAndClose()
+	}
+	dbItr.Close()
+	return ""
+}
+
 func decomposeVersionedValue(versionedValue *statedb.VersionedValue) ([]byte, *version.Height) {
 	var value []byte
 	var ver *version.Height
@@ -330,6 +416,16 @@ func decomposeVersionedValue(versionedValue *statedb.VersionedValue) ([]byte, *v
 	return value, ver
 }
 
+func decomposeVersionedValueMetadata(versionedValue *statedb.VersionedValue) ([]byte, *version.Height) {
+	var metadata []byte
+	var ver *version.Height
+	if versionedValue != nil {
+		metadata = versionedValue.Metadata
+		ver = versionedValue.Version
+	}
+	return metadata, ver
+}
+
 // pvtdataResultsItr iterates over results of a query on pvt data
 type pvtdataResultsItr struct {
 	ns    string
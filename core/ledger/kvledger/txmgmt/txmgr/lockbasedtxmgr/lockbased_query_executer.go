@@ -50,11 +50,25 @@ func (q *lockBasedQueryExecutor) GetStateRangeScanIterator(namespace string, sta
 	return q.helper.getStateRangeScanIterator(namespace, startKey, endKey)
 }
 
+// GetStateRangeScanIteratorWithMetadata implements method in interface `ledger.QueryExecutor`
+// startKey is included in the results and endKey is excluded. An empty startKey refers to the first available key
+// and an empty endKey refers to the last available key. For scanning all the keys, both the startKey and the endKey
+// can be supplied as empty strings. However, a full scan should be used judiciously for performance reasons.
+// metadata is a map of additional query parameters
+func (q *lockBasedQueryExecutor) GetStateRangeScanIteratorWithMetadata(namespace string, startKey string, endKey string, metadata map[string]interface{}) (ledger.QueryResultsIterator, error) {
+	return q.helper.getStateRangeScanIteratorWithMetadata(namespace, startKey, endKey, metadata)
+}
+
 // ExecuteQuery implements method in interface `ledger.QueryExecutor`
 func (q *lockBasedQueryExecutor) ExecuteQuery(namespace, query string) (ledger.ResultsIterator, error) {
 	return q.helper.executeQuery(namespace, query)
 }
 
+// ExecuteQueryWithMetadata implements method in interface `ledger.QueryExecutor`
+func (q *lockBasedQueryExecutor) ExecuteQueryWithMetadata(namespace, query string, metadata map[string]interface{}) (ledger.QueryResultsIterator, error) {
+	return q.helper.executeQueryWithMetadata(namespace, query, metadata)
+}
+
 // GetPrivateData implements method in interface `ledger.QueryExecutor`
 func (q *lockBasedQueryExecutor) GetPrivateData(namespace, collection, key string) ([]byte, error) {
 	return q.helper.getPrivateData(namespace, collection, key)
@@ -75,6 +89,21 @@ func (q *lockBasedQueryExecutor) ExecuteQueryOnPrivateData(namespace, collection
 	return q.helper.executeQueryOnPrivateData(namespace, collection, query)
 }
 
+// GetStateMetadata implements method in interface `ledger.QueryExecutor`
+func (q *lockBasedQueryExecutor) GetStateMetadata(namespace, key string) (map[string][]byte, error) {
+	return q.helper.getStateMetadata(namespace, key)
+}
+
+// GetPrivateDataMetadata implements method in interface `ledger.QueryExecutor`
+func (q *lockBasedQueryExecutor) GetPrivateDataMetadata(namespace, collection, key string) (map[string][]byte, error) {
+	return q.helper.getPrivateDataMetadata(namespace, collection, key)
+}
+
+// GetPrivateDataMetadataByHash implements method in interface `ledger.QueryExecutor`
+func (q *lockBasedQueryExecutor) GetPrivateDataMetadataByHash(namespace, collection string, keyhash []byte) (map[string][]byte, error) {
+	return q.helper.getPrivateDataMetadataByHash(namespace, collection, keyhash)
+}
+
 // Done implements method in interface `ledger.QueryExecutor`
 func (q *lockBasedQueryExecutor) Done() {
 	logger.Debugf("Done with transaction simulation / query execution [%s]", q.txid)
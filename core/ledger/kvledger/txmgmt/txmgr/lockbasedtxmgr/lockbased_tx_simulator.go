@@ -29,16 +29,17 @@ import (
 // LockBasedTxSimulator is a transaction simulator used in `LockBasedTxMgr`
 type lockBasedTxSimulator struct {
 	lockBasedQueryExecutor
-	rwsetBuilder            *rwsetutil.RWSetBuilder
-	writePerformed          bool
-	pvtdataQueriesPerformed bool
+	rwsetBuilder              *rwsetutil.RWSetBuilder
+	writePerformed            bool
+	pvtdataQueriesPerformed   bool
+	paginatedQueriesPerformed bool
 }
 
 func newLockBasedTxSimulator(txmgr *LockBasedTxMgr, txid string) (*lockBasedTxSimulator, error) {
 	rwsetBuilder := rwsetutil.NewRWSetBuilder()
 	helper := &queryHelper{txmgr: txmgr, rwsetBuilder: rwsetBuilder}
 	logger.Debugf("constructing new tx simulator txid = [%s]", txid)
-	return &lockBasedTxSimulator{lockBasedQueryExecutor{helper, txid}, rwsetBuilder, false, false}, nil
+	return &lockBasedTxSimulator{lockBasedQueryExecutor{helper, txid}, rwsetBuilder, false, false, false}, nil
 }
 
 // GetState implements method in interface `ledger.TxSimulator`
@@ -76,6 +77,26 @@ func (s *lockBasedTxSimulator) SetStateMultipleKeys(namespace string, kvs map[st
 	return nil
 }
 
+// SetStateMetadata implements method in interface `ledger.TxSimulator`
+func (s *lockBasedTxSimulator) SetStateMetadata(namespace, key string, metadata map[string][]byte) error {
+	if err := s.helper.checkDone(); err != nil {
+		return err
+	}
+	if err := s.checkBeforeWrite(); err != nil {
+		return err
+	}
+	if err := s.helper.txmgr.db.ValidateKeyValue(key, nil); err != nil {
+		return err
+	}
+	s.rwsetBuilder.AddToMetadataWriteSet(namespace, key, metadata)
+	return nil
+}
+
+// DeleteStateMetadata implements method in interface `ledger.TxSimulator`
+func (s *lockBasedTxSimulator) DeleteStateMetadata(namespace, key string) error {
+	return s.SetStateMetadata(namespace, key, nil)
+}
+
 // SetPrivateData implements method in interface `ledger.TxSimulator`
 func (s *lockBasedTxSimulator) SetPrivateData(ns, coll, key string, value []byte) error {
 	if err := s.helper.checkDone(); err != nil {
@@ -106,6 +127,26 @@ func (s *lockBasedTxSimulator) SetPrivateDataMultipleKeys(ns, coll string, kvs m
 	return nil
 }
 
+// SetPrivateDataMetadata implements method in interface `ledger.TxSimulator`
+func (s *lockBasedTxSimulator) SetPrivateDataMetadata(namespace, collection, key string, metadata map[string][]byte) error {
+	if err := s.helper.checkDone(); err != nil {
+		return err
+	}
+	if err := s.checkBeforeWrite(); err != nil {
+		return err
+	}
+	if err := s.helper.txmgr.db.ValidateKeyValue(key, nil); err != nil {
+		return err
+	}
+	s.rwsetBuilder.AddToPvtAndHashedMetadataWriteSet(namespace, collection, key, metadata)
+	return nil
+}
+
+// DeletePrivateDataMetadata implements method in interface `ledger.TxSimulator`
+func (s *lockBasedTxSimulator) DeletePrivateDataMetadata(namespace, collection, key string) error {
+	return s.SetPrivateDataMetadata(namespace, collection, key, nil)
+}
+
 // GetPrivateDataRangeScanIterator implements method in interface `ledger.TxSimulator`
 func (s *lockBasedTxSimulator) GetPrivateDataRangeScanIterator(namespace, collection, startKey, endKey string) (commonledger.ResultsIterator, error) {
 	if err := s.checkBeforePvtdataQueries(); err != nil {
@@ -122,6 +163,22 @@ func (s *lockBasedTxSimulator) ExecuteQueryOnPrivateData(namespace, collection,
 	return s.lockBasedQueryExecutor.ExecuteQueryOnPrivateData(namespace, collection, query)
 }
 
+// GetStateRangeScanIteratorWithMetadata implements method in interface `ledger.QueryExecutor`
+func (s *lockBasedTxSimulator) GetStateRangeScanIteratorWithMetadata(namespace string, startKey string, endKey string, metadata map[string]interface{}) (commonledger.QueryResultsIterator, error) {
+	if err := s.checkBeforePaginatedQueries(); err != nil {
+		return nil, err
+	}
+	return s.lockBasedQueryExecutor.GetStateRangeScanIteratorWithMetadata(namespace, startKey, endKey, metadata)
+}
+
+// ExecuteQueryWithMetadata implements method in interface `ledger.QueryExecutor`
+func (s *lockBasedTxSimulator) ExecuteQueryWithMetadata(namespace, query string, metadata map[string]interface{}) (commonledger.QueryResultsIterator, error) {
+	if err := s.checkBeforePaginatedQueries(); err != nil {
+		return nil, err
+	}
+	return s.lockBasedQueryExecutor.ExecuteQueryWithMetadata(namespace, query, metadata)
+}
+
 // GetTxSimulationResults implements method in interface `ledger.TxSimulator`
 func (s *lockBasedTxSimulator) GetTxSimulationResults() (*ledger.TxSimulationResults, error) {
 	logger.Debugf("Simulation completed, getting simulation results")
@@ -143,6 +200,11 @@ func (s *lockBasedTxSimulator) checkBeforeWrite() error {
 			Msg: fmt.Sprintf("Tx [%s]: Transaction has already performed queries on pvt data. Writes are not allowed", s.txid),
 		}
 	}
+	if s.paginatedQueriesPerformed {
+		return &txmgr.ErrUnsupportedTransaction{
+			Msg: fmt.Sprintf("Tx [%s]: Transaction has already performed a paginated query. Writes are not allowed", s.txid),
+		}
+	}
 	s.writePerformed = true
 	return nil
 }
@@ -156,3 +218,13 @@ func (s *lockBasedTxSimulator) checkBeforePvtdataQueries() error {
 	s.pvtdataQueriesPerformed = true
 	return nil
 }
+
+func (s *lockBasedTxSimulator) checkBeforePaginatedQueries() error {
+	if s.writePerformed {
+		return &txmgr.ErrUnsupportedTransaction{
+			Msg: fmt.Sprintf("Tx [%s]: Paginated queries are supported only in a read-only transaction", s.txid),
+		}
+	}
+	s.paginatedQueriesPerformed = true
+	return nil
+}
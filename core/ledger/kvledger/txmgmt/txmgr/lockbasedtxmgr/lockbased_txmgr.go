@@ -6,6 +6,7 @@ SPDX-License-Identifier: Apache-2.0
 package lockbasedtxmgr
 
 import (
+	"bytes"
 	"sync"
 
 	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
@@ -13,9 +14,11 @@ import (
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validator"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validator/valimpl"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
 )
 
@@ -166,3 +169,73 @@ func (txmgr *LockBasedTxMgr) CommitLostBlock(blockAndPvtdata *ledger.BlockAndPvt
 	logger.Debugf("Committing block %d to state database", block.Header.Number)
 	return txmgr.Commit()
 }
+
+// RemoveStaleAndCommitPvtDataOfOldBlocks implements method in interface `txmgmt.TxMgr`.
+// A private write is applied to the state db only if the hashed state carries the hash
+// of the write at the same version, i.e., the key has not been updated by a later transaction
+// and has not been purged on expiry
+func (txmgr *LockBasedTxMgr) RemoveStaleAndCommitPvtDataOfOldBlocks(blocksPvtData []*ledger.BlockPvtData) error {
+	txmgr.commitRWLock.Lock()
+	defer txmgr.commitRWLock.Unlock()
+
+	pvtUpdates := privacyenabledstate.NewPvtUpdateBatch()
+	for _, blockPvtData := range blocksPvtData {
+		for seqInBlock, txPvtData := range blockPvtData.WriteSets {
+			if txPvtData == nil || txPvtData.WriteSet == nil {
+				continue
+			}
+			txPvtRWSet, err := rwsetutil.TxPvtRwSetFromProtoMsg(txPvtData.WriteSet)
+			if err != nil {
+				return err
+			}
+			ver := version.NewHeight(blockPvtData.BlockNum, seqInBlock)
+			for _, nsPvtRWSet := range txPvtRWSet.NsPvtRwSet {
+				for _, collPvtRWSet := range nsPvtRWSet.CollPvtRwSets {
+					for _, write := range collPvtRWSet.KvRwSet.Writes {
+						isStale, err := txmgr.isStalePvtWrite(nsPvtRWSet.NameSpace, collPvtRWSet.CollectionName, write, ver)
+						if err != nil {
+							return err
+						}
+						if isStale {
+							logger.Debugf("Skipping stale pvt write for key [%s] in [%s:%s] at version [%#v]",
+								write.Key, nsPvtRWSet.NameSpace, collPvtRWSet.CollectionName, ver)
+							continue
+						}
+						if write.IsDelete {
+							pvtUpdates.Delete(nsPvtRWSet.NameSpace, collPvtRWSet.CollectionName, write.Key, ver)
+						} else {
+							pvtUpdates.Put(nsPvtRWSet.NameSpace, collPvtRWSet.CollectionName, write.Key, write.Value, ver)
+						}
+					}
+				}
+			}
+		}
+	}
+	if pvtUpdates.IsEmpty() {
+		return nil
+	}
+	return txmgr.db.ApplyPvtUpdatesOfOldBlocks(pvtUpdates)
+}
+
+// isStalePvtWrite returns true if the given pvt write of a transaction committed at version `ver`
+// does not correspond to the current hashed state
+func (txmgr *LockBasedTxMgr) isStalePvtWrite(ns, coll string, write *kvrwset.KVWrite, ver *version.Height) (bool, error) {
+	hashedVV, err := txmgr.db.GetValueHash(ns, coll, util.ComputeStringHash(write.Key))
+	if err != nil {
+		return false, err
+	}
+	if write.IsDelete {
+		if hashedVV != nil {
+			return true, nil
+		}
+		pvtVV, err := txmgr.db.GetPrivateData(ns, coll, write.Key)
+		if err != nil {
+			return false, err
+		}
+		return pvtVV != nil && pvtVV.Version.Compare(ver) >= 0, nil
+	}
+	if hashedVV == nil || hashedVV.Version.Compare(ver) != 0 {
+		return true, nil
+	}
+	return !bytes.Equal(hashedVV.Value, util.ComputeHash(write.Value)), nil
+}
@@ -12,14 +12,18 @@ import (
 
 	"os"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
+	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/txmgr"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
 	ledgertestutil "github.com/hyperledger/fabric/core/ledger/testutil"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/ledger/queryresult"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 )
@@ -647,6 +651,67 @@ func TestTxSimulatorUnsupportedTx(t *testing.T) {
 	testutil.AssertEquals(t, ok, true)
 }
 
+// TestTxSimulatorUnsupportedTxPaginatedQueries verifies that paginated queries are
+// supported only in a read-only transaction
+func TestTxSimulatorUnsupportedTxPaginatedQueries(t *testing.T) {
+	testEnv := testEnvs[0]
+	testEnv.init(t, "TestTxSimulatorUnsupportedTxPaginatedQueries")
+	defer testEnv.cleanup()
+	txMgr := testEnv.getTxMgr()
+
+	simulator, _ := txMgr.NewTxSimulator("txid1")
+	err := simulator.SetState("ns", "key", []byte("value"))
+	testutil.AssertNoError(t, err, "")
+	_, err = simulator.GetStateRangeScanIteratorWithMetadata("ns1", "startKey", "endKey", map[string]interface{}{"limit": int32(2)})
+	_, ok := err.(*txmgr.ErrUnsupportedTransaction)
+	testutil.AssertEquals(t, ok, true)
+
+	simulator, _ = txMgr.NewTxSimulator("txid2")
+	_, err = simulator.GetStateRangeScanIteratorWithMetadata("ns1", "startKey", "endKey", map[string]interface{}{"limit": int32(2)})
+	testutil.AssertNoError(t, err, "")
+	err = simulator.SetState("ns", "key", []byte("value"))
+	_, ok = err.(*txmgr.ErrUnsupportedTransaction)
+	testutil.AssertEquals(t, ok, true)
+}
+
+func TestPaginatedRangeQuery(t *testing.T) {
+	testEnv := testEnvs[0]
+	testEnv.init(t, "TestPaginatedRangeQuery")
+	defer testEnv.cleanup()
+	txMgr := testEnv.getTxMgr()
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+
+	s, _ := txMgr.NewTxSimulator("test_tx1")
+	for i := 1; i <= 5; i++ {
+		s.SetState("cid", createTestKey(i), createTestValue(i))
+	}
+	s.Done()
+	txRWSet, _ := s.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet.PubSimulationResults)
+
+	queryExecuter, _ := txMgr.NewQueryExecutor("test_tx2")
+	defer queryExecuter.Done()
+	startKey := createTestKey(1)
+	var keys []string
+	for pages := 0; ; pages++ {
+		itr, err := queryExecuter.GetStateRangeScanIteratorWithMetadata("cid", startKey, "", map[string]interface{}{"limit": int32(2)})
+		testutil.AssertNoError(t, err, "")
+		for {
+			kv, _ := itr.Next()
+			if kv == nil {
+				break
+			}
+			keys = append(keys, kv.(*queryresult.KV).Key)
+		}
+		startKey = itr.GetBookmarkAndClose()
+		if startKey == "" {
+			testutil.AssertEquals(t, pages, 2)
+			break
+		}
+	}
+	testutil.AssertEquals(t, keys, []string{createTestKey(1), createTestKey(2), createTestKey(3), createTestKey(4), createTestKey(5)})
+}
+
 func TestTxSimulatorMissingPvtdata(t *testing.T) {
 	testEnv := testEnvs[0]
 	testEnv.init(t, "TestTxSimulatorUnsupportedTxQueries")
@@ -689,6 +754,76 @@ func TestTxSimulatorMissingPvtdata(t *testing.T) {
 	testutil.AssertNil(t, val)
 }
 
+func TestTxSimulatorWithStateMetadata(t *testing.T) {
+	testEnv := testEnvs[0]
+	testEnv.init(t, "TestTxSimulatorWithStateMetadata")
+	defer testEnv.cleanup()
+	txMgr := testEnv.getTxMgr()
+	txMgrHelper := newTxMgrTestHelper(t, txMgr)
+
+	// the metadata of a non-existing key is ignored
+	s1, _ := txMgr.NewTxSimulator("test_tx1")
+	s1.SetState("ns1", "key1", []byte("value1"))
+	s1.SetStateMetadata("ns1", "key1", map[string][]byte{"entry1": []byte("metadata1")})
+	s1.SetStateMetadata("ns1", "key2", map[string][]byte{"entry1": []byte("metadata2")})
+	s1.Done()
+	txRWSet1, _ := s1.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet1.PubSimulationResults)
+
+	qe, _ := txMgr.NewQueryExecutor("test_tx2")
+	metadata, err := qe.GetStateMetadata("ns1", "key1")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, metadata, map[string][]byte{"entry1": []byte("metadata1")})
+	metadata, err = qe.GetStateMetadata("ns1", "key2")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertNil(t, metadata)
+	qe.Done()
+
+	// a write of the value retains the committed metadata
+	s2, _ := txMgr.NewTxSimulator("test_tx3")
+	s2.SetState("ns1", "key1", []byte("value1_1"))
+	s2.Done()
+	txRWSet2, _ := s2.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet2.PubSimulationResults)
+
+	qe, _ = txMgr.NewQueryExecutor("test_tx4")
+	metadata, _ = qe.GetStateMetadata("ns1", "key1")
+	testutil.AssertEquals(t, metadata, map[string][]byte{"entry1": []byte("metadata1")})
+	qe.Done()
+
+	// a deletion of the metadata retains the committed value and updates the version of the key
+	s3, _ := txMgr.NewTxSimulator("test_tx5")
+	s3.DeleteStateMetadata("ns1", "key1")
+	s3.Done()
+	txRWSet3, _ := s3.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet3.PubSimulationResults)
+
+	vv, _ := testEnv.getVDB().GetState("ns1", "key1")
+	testutil.AssertEquals(t, vv.Value, []byte("value1_1"))
+	testutil.AssertNil(t, vv.Metadata)
+	testutil.AssertEquals(t, vv.Version, version.NewHeight(3, 0))
+
+	// the metadata of private data is maintained along with the hash of the private key
+	updateBatch := privacyenabledstate.NewUpdateBatch()
+	updateBatch.HashUpdates.Put("ns1", "coll1", util.ComputeStringHash("key3"), util.ComputeStringHash("value3"), version.NewHeight(4, 0))
+	testEnv.getVDB().ApplyPrivacyAwareUpdates(updateBatch, version.NewHeight(4, 0))
+
+	s4, _ := txMgr.NewTxSimulator("test_tx6")
+	s4.SetPrivateDataMetadata("ns1", "coll1", "key3", map[string][]byte{"entry1": []byte("metadata3")})
+	s4.Done()
+	txRWSet4, _ := s4.GetTxSimulationResults()
+	txMgrHelper.validateAndCommitRWSet(txRWSet4.PubSimulationResults)
+
+	qe, _ = txMgr.NewQueryExecutor("test_tx7")
+	defer qe.Done()
+	metadata, err = qe.GetPrivateDataMetadata("ns1", "coll1", "key3")
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, metadata, map[string][]byte{"entry1": []byte("metadata3")})
+	metadata, err = qe.GetPrivateDataMetadataByHash("ns1", "coll1", util.ComputeStringHash("key3"))
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, metadata, map[string][]byte{"entry1": []byte("metadata3")})
+}
+
 func TestDeleteOnCursor(t *testing.T) {
 	cID := "cid"
 	env := testEnvs[0]
@@ -736,3 +871,81 @@ func TestDeleteOnCursor(t *testing.T) {
 	itr3.Close()
 	s3.Done()
 }
+
+func TestRemoveStaleAndCommitPvtDataOfOldBlocks(t *testing.T) {
+	testEnv := testEnvs[0]
+	testEnv.init(t, "TestRemoveStaleAndCommitPvtDataOfOldBlocks")
+	defer testEnv.cleanup()
+	db := testEnv.getVDB()
+
+	// block 1 commits the hashes of key1, key2 and key4 without the pvt data, and key3 with the pvt data
+	updateBatch := privacyenabledstate.NewUpdateBatch()
+	updateBatch.HashUpdates.Put("ns1", "coll1", util.ComputeStringHash("key1"), util.ComputeStringHash("value1"), version.NewHeight(1, 1))
+	updateBatch.HashUpdates.Put("ns1", "coll1", util.ComputeStringHash("key2"), util.ComputeStringHash("value2"), version.NewHeight(1, 1))
+	updateBatch.HashUpdates.Put("ns1", "coll1", util.ComputeStringHash("key4"), util.ComputeStringHash("value4"), version.NewHeight(1, 1))
+	updateBatch.HashUpdates.Put("ns1", "coll1", util.ComputeStringHash("key3"), util.ComputeStringHash("value3"), version.NewHeight(1, 0))
+	updateBatch.PvtUpdates.Put("ns1", "coll1", "key3", []byte("value3"), version.NewHeight(1, 0))
+	assert.NoError(t, db.ApplyPrivacyAwareUpdates(updateBatch, version.NewHeight(1, 1)))
+
+	// block 2 updates key2 and deletes key3, again without the pvt data
+	updateBatch = privacyenabledstate.NewUpdateBatch()
+	updateBatch.HashUpdates.Put("ns1", "coll1", util.ComputeStringHash("key2"), util.ComputeStringHash("value2_new"), version.NewHeight(2, 0))
+	updateBatch.HashUpdates.Delete("ns1", "coll1", util.ComputeStringHash("key3"), version.NewHeight(2, 0))
+	assert.NoError(t, db.ApplyPrivacyAwareUpdates(updateBatch, version.NewHeight(2, 0)))
+
+	blocksPvtData := []*ledger.BlockPvtData{
+		{
+			BlockNum: 1,
+			WriteSets: map[uint64]*ledger.TxPvtData{
+				1: {SeqInBlock: 1, WriteSet: samplePvtWriteSet(t, "ns1", "coll1",
+					&kvrwset.KVWrite{Key: "key1", Value: []byte("value1")},
+					// stale, updated by block 2
+					&kvrwset.KVWrite{Key: "key2", Value: []byte("value2")},
+					// does not match the hash
+					&kvrwset.KVWrite{Key: "key4", Value: []byte("value4_bogus")},
+				)},
+			},
+		},
+		{
+			BlockNum: 2,
+			WriteSets: map[uint64]*ledger.TxPvtData{
+				0: {SeqInBlock: 0, WriteSet: samplePvtWriteSet(t, "ns1", "coll1",
+					&kvrwset.KVWrite{Key: "key3", IsDelete: true},
+				)},
+			},
+		},
+	}
+	assert.NoError(t, testEnv.getTxMgr().RemoveStaleAndCommitPvtDataOfOldBlocks(blocksPvtData))
+
+	vv, err := db.GetPrivateData("ns1", "coll1", "key1")
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("value1"), vv.Value)
+	assert.Equal(t, version.NewHeight(1, 1), vv.Version)
+
+	for _, key := range []string{"key2", "key3", "key4"} {
+		vv, err = db.GetPrivateData("ns1", "coll1", key)
+		assert.NoError(t, err)
+		assert.Nil(t, vv, "pvt data of %s should not be present", key)
+	}
+
+	// the savepoint is not changed
+	savepoint, err := db.GetLatestSavePoint()
+	assert.NoError(t, err)
+	assert.Equal(t, version.NewHeight(2, 0), savepoint)
+}
+
+func samplePvtWriteSet(t *testing.T, ns, coll string, writes ...*kvrwset.KVWrite) *rwset.TxPvtReadWriteSet {
+	kvRWSetBytes, err := proto.Marshal(&kvrwset.KVRWSet{Writes: writes})
+	assert.NoError(t, err)
+	return &rwset.TxPvtReadWriteSet{
+		DataModel: rwset.TxReadWriteSet_KV,
+		NsPvtRwset: []*rwset.NsPvtReadWriteSet{
+			{
+				Namespace: ns,
+				CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{
+					{CollectionName: coll, Rwset: kvRWSetBytes},
+				},
+			},
+		},
+	}
+}
@@ -24,13 +24,14 @@ var logger = flogging.MustGetLogger("valimpl")
 // valinternal.InternalValidator) such as statebased validator
 type DefaultImpl struct {
 	txmgr txmgr.TxMgr
+	db    privacyenabledstate.DB
 	valinternal.InternalValidator
 }
 
 // NewStatebasedValidator constructs a validator that internally manages statebased validator and in addition
 // handles the tasks that are agnostic to a particular validation scheme such as parsing the block and handling the pvt data
 func NewStatebasedValidator(txmgr txmgr.TxMgr, db privacyenabledstate.DB) validator.Validator {
-	return &DefaultImpl{txmgr, statebasedval.NewValidator(db)}
+	return &DefaultImpl{txmgr, db, statebasedval.NewValidator(db)}
 }
 
 // ValidateAndPrepareBatch implements the function in interface validator.Validator
@@ -52,7 +53,7 @@ func (impl *DefaultImpl) ValidateAndPrepareBatch(blockAndPvtdata *ledger.BlockAn
 		return nil, err
 	}
 	logger.Debug("validating rwset...")
-	if pvtUpdates, err = validateAndPreparePvtBatch(internalBlock, blockAndPvtdata.BlockPvtData); err != nil {
+	if pvtUpdates, err = validateAndPreparePvtBatch(internalBlock, blockAndPvtdata.BlockPvtData, impl.db); err != nil {
 		return nil, err
 	}
 	logger.Debug("postprocessing ProtoBlock...")
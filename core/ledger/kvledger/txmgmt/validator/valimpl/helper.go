@@ -14,6 +14,7 @@ import (
 	"github.com/hyperledger/fabric/core/ledger/customtx"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/txmgr"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validator"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/validator/valinternal"
@@ -28,7 +29,7 @@ import (
 // validateAndPreparePvtBatch pulls out the private write-set for the transactions that are marked as valid
 // by the internal public data validator. Finally, it validates (if not already self-endorsed) the pvt rwset against the
 // corresponding hash present in the public rwset
-func validateAndPreparePvtBatch(block *valinternal.Block, pvtdata map[uint64]*ledger.TxPvtData) (*privacyenabledstate.PvtUpdateBatch, error) {
+func validateAndPreparePvtBatch(block *valinternal.Block, pvtdata map[uint64]*ledger.TxPvtData, db privacyenabledstate.DB) (*privacyenabledstate.PvtUpdateBatch, error) {
 	pvtUpdates := privacyenabledstate.NewPvtUpdateBatch()
 	for _, tx := range block.Txs {
 		if tx.ValidationCode != peer.TxValidationCode_VALID {
@@ -51,7 +52,9 @@ func validateAndPreparePvtBatch(block *valinternal.Block, pvtdata map[uint64]*le
 		if pvtRWSet, err = rwsetutil.TxPvtRwSetFromProtoMsg(txPvtdata.WriteSet); err != nil {
 			return nil, err
 		}
-		addPvtRWSetToPvtUpdateBatch(pvtRWSet, pvtUpdates, version.NewHeight(block.Num, uint64(tx.IndexInBlock)))
+		if err = addPvtRWSetToPvtUpdateBatch(pvtRWSet, pvtUpdates, version.NewHeight(block.Num, uint64(tx.IndexInBlock)), db); err != nil {
+			return nil, err
+		}
 	}
 	return pvtUpdates, nil
 }
@@ -189,16 +192,38 @@ func postprocessProtoBlock(block *common.Block, validatedBlock *valinternal.Bloc
 	block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = txsFilter
 }
 
-func addPvtRWSetToPvtUpdateBatch(pvtRWSet *rwsetutil.TxPvtRwSet, pvtUpdateBatch *privacyenabledstate.PvtUpdateBatch, ver *version.Height) {
+// addPvtRWSetToPvtUpdateBatch adds the private writes to the batch. A write of the metadata alone
+// retains the value of the private key at the new version, so that it matches the version of the hashed key
+func addPvtRWSetToPvtUpdateBatch(pvtRWSet *rwsetutil.TxPvtRwSet, pvtUpdateBatch *privacyenabledstate.PvtUpdateBatch,
+	ver *version.Height, db privacyenabledstate.DB) error {
 	for _, ns := range pvtRWSet.NsPvtRwSet {
 		for _, coll := range ns.CollPvtRwSets {
+			writtenKeys := make(map[string]struct{})
 			for _, kvwrite := range coll.KvRwSet.Writes {
+				writtenKeys[kvwrite.Key] = struct{}{}
 				if !kvwrite.IsDelete {
 					pvtUpdateBatch.Put(ns.NameSpace, coll.CollectionName, kvwrite.Key, kvwrite.Value, ver)
 				} else {
 					pvtUpdateBatch.Delete(ns.NameSpace, coll.CollectionName, kvwrite.Key, ver)
 				}
 			}
+			for _, metadataWrite := range coll.KvRwSet.MetadataWrites {
+				if _, written := writtenKeys[metadataWrite.Key]; written {
+					continue
+				}
+				var vv *statedb.VersionedValue
+				var err error
+				if pvtUpdateBatch.Contains(ns.NameSpace, coll.CollectionName, metadataWrite.Key) {
+					vv = pvtUpdateBatch.Get(ns.NameSpace, coll.CollectionName, metadataWrite.Key)
+				} else if vv, err = db.GetPrivateData(ns.NameSpace, coll.CollectionName, metadataWrite.Key); err != nil {
+					return err
+				}
+				if vv == nil || vv.Value == nil {
+					continue
+				}
+				pvtUpdateBatch.Put(ns.NameSpace, coll.CollectionName, metadataWrite.Key, vv.Value, ver)
+			}
 		}
 	}
+	return nil
 }
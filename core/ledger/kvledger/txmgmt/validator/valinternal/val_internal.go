@@ -9,7 +9,10 @@ package valinternal
 import (
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/privacyenabledstate"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statedb"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/statemetadata"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/version"
+	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
 	"github.com/hyperledger/fabric/protos/peer"
 )
 
@@ -78,27 +81,117 @@ func (t *Transaction) RetrieveHash(ns string, coll string) []byte {
 	return nil
 }
 
-// ApplyWriteSet adds (or deletes) the key/values present in the write set to the PubAndHashUpdates
-func (u *PubAndHashUpdates) ApplyWriteSet(txRWSet *rwsetutil.TxRwSet, txHeight *version.Height) {
+// ApplyWriteSet adds (or deletes) the key/values present in the write set to the PubAndHashUpdates.
+// A write of a value retains the committed metadata of the key, unless the transaction writes the metadata as well.
+// A write of the metadata alone retains the committed value of the key and is ignored if the key doesn't exist
+func (u *PubAndHashUpdates) ApplyWriteSet(txRWSet *rwsetutil.TxRwSet, txHeight *version.Height, db privacyenabledstate.DB) error {
 	for _, nsRWSet := range txRWSet.NsRwSets {
 		ns := nsRWSet.NameSpace
+		metadataWrites := make(map[string][]*kvrwset.KVMetadataEntry)
+		for _, metadataWrite := range nsRWSet.KvRwSet.MetadataWrites {
+			metadataWrites[metadataWrite.Key] = metadataWrite.Entries
+		}
+		committed := func(key string) (*statedb.VersionedValue, error) {
+			if u.PubUpdates.Exists(ns, key) {
+				return u.PubUpdates.Get(ns, key), nil
+			}
+			return db.GetState(ns, key)
+		}
+		writtenKeys := make(map[string]struct{})
 		for _, kvWrite := range nsRWSet.KvRwSet.Writes {
+			writtenKeys[kvWrite.Key] = struct{}{}
 			if kvWrite.IsDelete {
 				u.PubUpdates.Delete(ns, kvWrite.Key, txHeight)
-			} else {
-				u.PubUpdates.Put(ns, kvWrite.Key, kvWrite.Value, txHeight)
+				continue
+			}
+			metadata, err := metadataToApply(kvWrite.Key, metadataWrites, committed)
+			if err != nil {
+				return err
+			}
+			u.PubUpdates.PutValAndMetadata(ns, kvWrite.Key, kvWrite.Value, metadata, txHeight)
+		}
+		for _, metadataWrite := range nsRWSet.KvRwSet.MetadataWrites {
+			if _, written := writtenKeys[metadataWrite.Key]; written {
+				continue
+			}
+			vv, err := committed(metadataWrite.Key)
+			if err != nil {
+				return err
 			}
+			if vv == nil || vv.Value == nil {
+				continue
+			}
+			metadata, err := statemetadata.Serialize(metadataWrite.Entries)
+			if err != nil {
+				return err
+			}
+			u.PubUpdates.PutValAndMetadata(ns, metadataWrite.Key, vv.Value, metadata, txHeight)
 		}
 
 		for _, collHashRWset := range nsRWSet.CollHashedRwSets {
 			coll := collHashRWset.CollectionName
-			for _, hashedWrite := range collHashRWset.HashedRwSet.HashedWrites {
-				if hashedWrite.IsDelete {
-					u.HashUpdates.Delete(ns, coll, hashedWrite.KeyHash, txHeight)
-				} else {
-					u.HashUpdates.Put(ns, coll, hashedWrite.KeyHash, hashedWrite.ValueHash, txHeight)
-				}
+			if err := u.applyHashedWriteSet(ns, coll, collHashRWset.HashedRwSet, txHeight, db); err != nil {
+				return err
 			}
 		}
 	}
+	return nil
+}
+
+func (u *PubAndHashUpdates) applyHashedWriteSet(ns, coll string, hashedRWSet *kvrwset.HashedRWSet, txHeight *version.Height, db privacyenabledstate.DB) error {
+	metadataWrites := make(map[string][]*kvrwset.KVMetadataEntry)
+	for _, metadataWrite := range hashedRWSet.MetadataWrites {
+		metadataWrites[string(metadataWrite.KeyHash)] = metadataWrite.Entries
+	}
+	committed := func(keyHash string) (*statedb.VersionedValue, error) {
+		if u.HashUpdates.Contains(ns, coll, []byte(keyHash)) {
+			return u.HashUpdates.Get(ns, coll, keyHash), nil
+		}
+		return db.GetValueHash(ns, coll, []byte(keyHash))
+	}
+	writtenKeyHashes := make(map[string]struct{})
+	for _, hashedWrite := range hashedRWSet.HashedWrites {
+		writtenKeyHashes[string(hashedWrite.KeyHash)] = struct{}{}
+		if hashedWrite.IsDelete {
+			u.HashUpdates.Delete(ns, coll, hashedWrite.KeyHash, txHeight)
+			continue
+		}
+		metadata, err := metadataToApply(string(hashedWrite.KeyHash), metadataWrites, committed)
+		if err != nil {
+			return err
+		}
+		u.HashUpdates.PutValHashAndMetadata(ns, coll, hashedWrite.KeyHash, hashedWrite.ValueHash, metadata, txHeight)
+	}
+	for _, metadataWrite := range hashedRWSet.MetadataWrites {
+		if _, written := writtenKeyHashes[string(metadataWrite.KeyHash)]; written {
+			continue
+		}
+		vv, err := committed(string(metadataWrite.KeyHash))
+		if err != nil {
+			return err
+		}
+		if vv == nil || vv.Value == nil {
+			continue
+		}
+		metadata, err := statemetadata.Serialize(metadataWrite.Entries)
+		if err != nil {
+			return err
+		}
+		u.HashUpdates.PutValHashAndMetadata(ns, coll, metadataWrite.KeyHash, vv.Value, metadata, txHeight)
+	}
+	return nil
+}
+
+// metadataToApply returns the metadata that is written along with the value of the given key,
+// which is either the metadata written by the transaction or the committed metadata of the key
+func metadataToApply(key string, metadataWrites map[string][]*kvrwset.KVMetadataEntry,
+	committed func(key string) (*statedb.VersionedValue, error)) ([]byte, error) {
+	if entries, written := metadataWrites[key]; written {
+		return statemetadata.Serialize(entries)
+	}
+	vv, err := committed(key)
+	if err != nil || vv == nil {
+		return nil, err
+	}
+	return vv.Metadata, nil
 }
@@ -31,6 +31,11 @@ type PeerLedgerProvider interface {
 	// This function guarantees that the creation of ledger and committing the genesis block would an atomic action
 	// The chain id retrieved from the genesis block is treated as a ledger id
 	Create(genesisBlock *common.Block) (PeerLedger, error)
+	// CreateFromSnapshot creates a new ledger from the snapshot in the given directory, as exported by
+	// `PeerLedger.ExportSnapshot`. The ledger contains the blocks only from the last block of the snapshot onward.
+	// The channel name recorded in the snapshot is treated as a ledger id. The blocks of the snapshot are
+	// passed to the verifier, if not nil, before the ledger is created
+	CreateFromSnapshot(snapshotDir string, verifier SnapshotBlocksVerifier) (PeerLedger, error)
 	// Open opens an already created ledger
 	Open(ledgerID string) (PeerLedger, error)
 	// Exists tells whether the ledger with given id exists
@@ -41,6 +46,10 @@ type PeerLedgerProvider interface {
 	Close()
 }
 
+// SnapshotBlocksVerifier verifies the last block and the last config block of a snapshot, typically
+// the signatures of the ordering service on the last block against the config of the snapshot
+type SnapshotBlocksVerifier func(lastBlock, lastConfigBlock *common.Block) error
+
 // PeerLedger differs from the OrdererLedger in that PeerLedger locally maintain a bitmask
 // that tells apart valid transactions from invalid ones
 type PeerLedger interface {
@@ -75,6 +84,13 @@ type PeerLedger interface {
 	GetPvtDataByNum(blockNum uint64, filter PvtNsCollFilter) ([]*TxPvtData, error)
 	// CommitWithPvtData commits the block and the corresponding pvt data in an atomic operation
 	CommitWithPvtData(blockAndPvtdata *BlockAndPvtData) error
+	// CommitPvtDataOfOldBlocks commits the pvt data that was missing at the time of the commit of the
+	// corresponding blocks. The pvt data is added to the pvt data store and to the state db. A key in the
+	// state db is not updated if a later transaction has updated the key or if the pvt data has expired
+	CommitPvtDataOfOldBlocks(blocksPvtData []*BlockPvtData) error
+	// GetMissingPvtDataInfoForMostRecentBlocks returns the information about the pvt data that is still missing
+	// for at most `maxBlocks` most recent blocks that have some missing pvt data
+	GetMissingPvtDataInfoForMostRecentBlocks(maxBlocks int) (MissingPvtDataInfo, error)
 	// Purge removes private read-writes set generated by endorsers at block height lesser than
 	// a given maxBlockNumToRetain. In other words, Purge only retains private read-write sets
 	// that were generated at block height of maxBlockNumToRetain or higher.
@@ -83,6 +99,10 @@ type PeerLedger interface {
 	PrivateDataMinBlockNum() (uint64, error)
 	//Prune prunes the blocks/transactions that satisfy the given policy
 	Prune(policy commonledger.PrunePolicy) error
+	// ExportSnapshot exports a snapshot of the ledger at its current height to the given directory, which must not exist.
+	// The snapshot contains the public state, the hashes of the private data, and the last block and the last config block.
+	// The returned hash covers all the contents of the snapshot and can be compared with the snapshots exported by other peers
+	ExportSnapshot(snapshotDir string) (snapshotHash []byte, err error)
 }
 
 // ValidatedLedger represents the 'final ledger' after filtering out invalid transactions from PeerLedger.
@@ -112,6 +132,23 @@ type QueryExecutor interface {
 	// For a chaincode, the namespace corresponds to the chaincodeId
 	// The returned ResultsIterator contains results of type *KV which is defined in protos/ledger/queryresult.
 	ExecuteQuery(namespace, query string) (commonledger.ResultsIterator, error)
+	// GetStateRangeScanIteratorWithMetadata returns an iterator that contains all the key-values between given key ranges.
+	// startKey is included in the results and endKey is excluded. An empty startKey refers to the first available key
+	// and an empty endKey refers to the last available key. For scanning all the keys, both the startKey and the endKey
+	// can be supplied as empty strings. However, a full scan should be used judiciously for performance reasons.
+	// metadata is a map of additional query parameters, the supported parameter is "limit" of type int32, which is the
+	// maximum number of results to be returned. The bookmark of the returned QueryResultsIterator is the start key of the
+	// next page of the results.
+	// The returned QueryResultsIterator contains results of type *KV which is defined in protos/ledger/queryresult.
+	GetStateRangeScanIteratorWithMetadata(namespace string, startKey, endKey string, metadata map[string]interface{}) (commonledger.QueryResultsIterator, error)
+	// ExecuteQueryWithMetadata executes the given query and returns an iterator that contains results of type specific
+	// to the underlying data store. metadata is a map of additional query parameters, the supported parameters are
+	// "limit" of type int32, which is the maximum number of results to be returned, and "bookmark" of type string, which
+	// is the bookmark of the previous page of the same query.
+	// Only used for state databases that support query
+	// For a chaincode, the namespace corresponds to the chaincodeId
+	// The returned QueryResultsIterator contains results of type *KV which is defined in protos/ledger/queryresult.
+	ExecuteQueryWithMetadata(namespace, query string, metadata map[string]interface{}) (commonledger.QueryResultsIterator, error)
 	// GetPrivateData gets the value of a private data item identified by a tuple <namespace, collection, key>
 	GetPrivateData(namespace, collection, key string) ([]byte, error)
 	// GetPrivateDataMultipleKeys gets the values for the multiple private data items in a single call
@@ -127,6 +164,13 @@ type QueryExecutor interface {
 	// For a chaincode, the namespace corresponds to the chaincodeId
 	// The returned ResultsIterator contains results of type *KV which is defined in protos/ledger/queryresult.
 	ExecuteQueryOnPrivateData(namespace, collection, query string) (commonledger.ResultsIterator, error)
+	// GetStateMetadata returns the metadata for given namespace and key
+	GetStateMetadata(namespace, key string) (map[string][]byte, error)
+	// GetPrivateDataMetadata gets the metadata of a private data item identified by a tuple <namespace, collection, key>
+	GetPrivateDataMetadata(namespace, collection, key string) (map[string][]byte, error)
+	// GetPrivateDataMetadataByHash gets the metadata of a private data item identified by a tuple <namespace, collection, keyhash>.
+	// This is intended to be used by the validation of transactions, as the peer may not have the private data
+	GetPrivateDataMetadataByHash(namespace, collection string, keyhash []byte) (map[string][]byte, error)
 	// Done releases resources occupied by the QueryExecutor
 	Done()
 }
@@ -148,6 +192,10 @@ type TxSimulator interface {
 	DeleteState(namespace string, key string) error
 	// SetMultipleKeys sets the values for multiple keys in a single call
 	SetStateMultipleKeys(namespace string, kvs map[string][]byte) error
+	// SetStateMetadata sets the metadata associated with an existing key-tuple <namespace, key>
+	SetStateMetadata(namespace, key string, metadata map[string][]byte) error
+	// DeleteStateMetadata deletes the metadata (if any) associated with an existing key-tuple <namespace, key>
+	DeleteStateMetadata(namespace, key string) error
 	// ExecuteUpdate for supporting rich data model (see comments on QueryExecutor above)
 	ExecuteUpdate(query string) error
 	// SetPrivateData sets the given value to a key in the private data state represented by the tuple <namespace, collection, key>
@@ -156,6 +204,10 @@ type TxSimulator interface {
 	SetPrivateDataMultipleKeys(namespace, collection string, kvs map[string][]byte) error
 	// DeletePrivateData deletes the given tuple <namespace, collection, key> from private data
 	DeletePrivateData(namespace, collection, key string) error
+	// SetPrivateDataMetadata sets the metadata associated with an existing key-tuple <namespace, collection, key>
+	SetPrivateDataMetadata(namespace, collection, key string, metadata map[string][]byte) error
+	// DeletePrivateDataMetadata deletes the metadata associated with an existing key-tuple <namespace, collection, key>
+	DeletePrivateDataMetadata(namespace, collection, key string) error
 	// GetTxSimulationResults encapsulates the results of the transaction simulation.
 	// This should contain enough detail for
 	// - The update in the state that would be caused if the transaction is to be committed
@@ -185,6 +237,22 @@ type MissingPrivateData struct {
 	Collection string
 }
 
+// MissingPvtDataInfo maintains, by block number, the private RWSets that are missing
+// for the already committed blocks
+type MissingPvtDataInfo map[uint64][]MissingPrivateData
+
+// Add adds the given missing private RWSet of the given block
+func (info MissingPvtDataInfo) Add(blockNum uint64, missing MissingPrivateData) {
+	info[blockNum] = append(info[blockNum], missing)
+}
+
+// BlockPvtData encapsulates the block number and the pvt data of an already committed block.
+// The map contains the tuples <seqInBlock, *TxPvtData>
+type BlockPvtData struct {
+	BlockNum  uint64
+	WriteSets map[uint64]*TxPvtData
+}
+
 // BlockAndPvtData encapsulates the block and a map that contains the tuples <seqInBlock, *TxPvtData>
 // The map is expected to contain the entries only for the transactions that has associated pvt data
 type BlockAndPvtData struct {
@@ -18,6 +18,7 @@ package ledgerconfig
 
 import (
 	"path/filepath"
+	"time"
 
 	"github.com/hyperledger/fabric/core/config"
 	"github.com/spf13/viper"
@@ -40,11 +41,21 @@ const confHistoryLeveldb = "historyLeveldb"
 const confPvtWritesetStore = "pvtWritesetStore"
 const confChains = "chains"
 const confPvtdataStore = "pvtdataStore"
+const confBookkeeper = "bookkeeper"
+const confFileLock = "fileLock"
 const confQueryLimit = "ledger.state.couchDBConfig.queryLimit"
 const confEnableHistoryDatabase = "ledger.history.enableHistoryDatabase"
 const confMaxBatchSize = "ledger.state.couchDBConfig.maxBatchUpdateSize"
 const confAutoWarmIndexes = "ledger.state.couchDBConfig.autoWarmIndexes"
 const confWarmIndexesAfterNBlocks = "ledger.state.couchDBConfig.warmIndexesAfterNBlocks"
+const confStateCacheSize = "ledger.state.couchDBConfig.cacheSize"
+const confMaxBlockfileSize = "ledger.blockchain.maxBlockfileSize"
+const confPrunePolicy = "ledger.blockchain.pruning.policy"
+const confPruneKeepLastNBlocks = "ledger.blockchain.pruning.keepLastNBlocks"
+const confPruneKeepBlocksNewerThan = "ledger.blockchain.pruning.keepBlocksNewerThan"
+const confPruneArchiveDir = "ledger.blockchain.pruning.archiveDir"
+const confPruneIntervalBlocks = "ledger.blockchain.pruning.intervalBlocks"
+const confPvtdataStorePurgeInterval = "ledger.pvtdataStore.purgeInterval"
 
 // GetRootPath returns the filesystem path.
 // All ledger related contents are expected to be stored under this path
@@ -83,9 +94,71 @@ func GetPvtdataStorePath() string {
 	return filepath.Join(GetRootPath(), confPvtdataStore)
 }
 
+// GetInternalBookkeeperPath returns the filesystem path that is used for the internal bookkeeping of the ledger, such as the expiry of the private data
+func GetInternalBookkeeperPath() string {
+	return filepath.Join(GetRootPath(), confBookkeeper)
+}
+
+// GetFileLockPath returns the filesystem path that is used to create a file lock, which is held by the
+// ledger provider while the peer is running and by the offline ledger management commands while they run
+func GetFileLockPath() string {
+	return filepath.Join(GetRootPath(), confFileLock)
+}
+
+// GetPvtdataStorePurgeInterval returns the number of blocks to be committed between two consecutive
+// purges of the expired data from the private data store
+func GetPvtdataStorePurgeInterval() uint64 {
+	purgeInterval := viper.GetInt(confPvtdataStorePurgeInterval)
+	// if purgeInterval was unset, default to 100
+	if purgeInterval <= 0 {
+		purgeInterval = 100
+	}
+	return uint64(purgeInterval)
+}
+
 // GetMaxBlockfileSize returns maximum size of the block file
 func GetMaxBlockfileSize() int {
-	return 64 * 1024 * 1024
+	maxBlockfileSize := viper.GetInt(confMaxBlockfileSize)
+	// if maxBlockfileSize was unset, default to 64 MB
+	if maxBlockfileSize <= 0 {
+		maxBlockfileSize = 64 * 1024 * 1024
+	}
+	return maxBlockfileSize
+}
+
+// GetPrunePolicy returns the name of the policy used for pruning the block store periodically.
+// An empty string indicates that the periodic pruning is disabled
+func GetPrunePolicy() string {
+	return viper.GetString(confPrunePolicy)
+}
+
+// GetPruneKeepLastNBlocks returns the number of latest blocks retained by the `keepLastNBlocks` prune policy
+func GetPruneKeepLastNBlocks() uint64 {
+	return uint64(viper.GetInt(confPruneKeepLastNBlocks))
+}
+
+// GetPruneKeepBlocksNewerThan returns the age of the oldest block retained by the `keepBlocksNewerThan` prune policy
+func GetPruneKeepBlocksNewerThan() time.Duration {
+	return viper.GetDuration(confPruneKeepBlocksNewerThan)
+}
+
+// GetPruneArchiveDir returns the directory to which the pruned block files are moved.
+// An empty string indicates that the pruned block files are deleted
+func GetPruneArchiveDir() string {
+	if !viper.IsSet(confPruneArchiveDir) || viper.GetString(confPruneArchiveDir) == "" {
+		return ""
+	}
+	return config.GetPath(confPruneArchiveDir)
+}
+
+// GetPruneIntervalBlocks returns the number of blocks to be committed between two consecutive periodic prunings
+func GetPruneIntervalBlocks() uint64 {
+	intervalBlocks := viper.GetInt(confPruneIntervalBlocks)
+	// if intervalBlocks was unset, default to 1000
+	if intervalBlocks <= 0 {
+		intervalBlocks = 1000
+	}
+	return uint64(intervalBlocks)
 }
 
 //GetQueryLimit exposes the queryLimit variable
@@ -145,3 +218,14 @@ func GetWarmIndexesAfterNBlocks() int {
 	}
 	return warmAfterNBlocks
 }
+
+//GetStateCacheSize exposes the cacheSize variable, the number of keys of the
+//state cache of each channel. A value of 0 disables the cache.
+func GetStateCacheSize() int {
+	cacheSize := viper.GetInt(confStateCacheSize)
+	// if cacheSize was unset, default to 10000
+	if !viper.IsSet(confStateCacheSize) {
+		cacheSize = 10000
+	}
+	return cacheSize
+}
@@ -18,6 +18,7 @@ package ledgerconfig
 
 import (
 	"testing"
+	"time"
 
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	ledgertestutil "github.com/hyperledger/fabric/core/ledger/testutil"
@@ -63,6 +64,9 @@ func TestLedgerConfigPathDefault(t *testing.T) {
 	testutil.AssertEquals(t,
 		GetBlockStorePath(),
 		"/var/hyperledger/production/ledgersData/chains")
+	testutil.AssertEquals(t,
+		GetFileLockPath(),
+		"/var/hyperledger/production/ledgersData/fileLock")
 }
 
 func TestLedgerConfigPath(t *testing.T) {
@@ -84,6 +88,9 @@ func TestLedgerConfigPath(t *testing.T) {
 	testutil.AssertEquals(t,
 		GetBlockStorePath(),
 		"/tmp/hyperledger/production/ledgersData/chains")
+	testutil.AssertEquals(t,
+		GetFileLockPath(),
+		"/tmp/hyperledger/production/ledgersData/fileLock")
 }
 
 func TestGetQueryLimitDefault(t *testing.T) {
@@ -164,6 +171,58 @@ func TestGetWarmIndexesAfterNBlocks(t *testing.T) {
 	testutil.AssertEquals(t, updatedValue, 10)
 }
 
+func TestGetStateCacheSizeDefault(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defaultValue := GetStateCacheSize()
+	testutil.AssertEquals(t, defaultValue, 10000) //test default config is 10000
+}
+
+func TestGetStateCacheSizeUnset(t *testing.T) {
+	viper.Reset()
+	defaultValue := GetStateCacheSize()
+	testutil.AssertEquals(t, defaultValue, 10000) //test default config is 10000
+}
+
+func TestGetStateCacheSize(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	viper.Set("ledger.state.couchDBConfig.cacheSize", 0)
+	updatedValue := GetStateCacheSize()
+	testutil.AssertEquals(t, updatedValue, 0) //test config returns 0
+}
+
+func TestGetMaxBlockfileSize(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	testutil.AssertEquals(t, GetMaxBlockfileSize(), 64*1024*1024)
+	viper.Set("ledger.blockchain.maxBlockfileSize", 1024)
+	testutil.AssertEquals(t, GetMaxBlockfileSize(), 1024)
+	viper.Set("ledger.blockchain.maxBlockfileSize", 0)
+	testutil.AssertEquals(t, GetMaxBlockfileSize(), 64*1024*1024)
+}
+
+func TestGetPruneConfigDefault(t *testing.T) {
+	setUpCoreYAMLConfig()
+	testutil.AssertEquals(t, GetPrunePolicy(), "")
+	testutil.AssertEquals(t, GetPruneKeepLastNBlocks(), uint64(100000))
+	testutil.AssertEquals(t, GetPruneKeepBlocksNewerThan(), 720*time.Hour)
+	testutil.AssertEquals(t, GetPruneArchiveDir(), "")
+	testutil.AssertEquals(t, GetPruneIntervalBlocks(), uint64(1000))
+}
+
+func TestGetPruneConfig(t *testing.T) {
+	setUpCoreYAMLConfig()
+	defer ledgertestutil.ResetConfigToDefaultValues()
+	viper.Set("ledger.blockchain.pruning.policy", "keepLastNBlocks")
+	viper.Set("ledger.blockchain.pruning.keepLastNBlocks", 20)
+	viper.Set("ledger.blockchain.pruning.archiveDir", "/tmp/archive")
+	viper.Set("ledger.blockchain.pruning.intervalBlocks", 5)
+	testutil.AssertEquals(t, GetPrunePolicy(), "keepLastNBlocks")
+	testutil.AssertEquals(t, GetPruneKeepLastNBlocks(), uint64(20))
+	testutil.AssertEquals(t, GetPruneArchiveDir(), "/tmp/archive")
+	testutil.AssertEquals(t, GetPruneIntervalBlocks(), uint64(5))
+}
+
 func setUpCoreYAMLConfig() {
 	//call a helper method to load the core.yaml
 	ledgertestutil.SetupCoreYAMLConfig()
@@ -95,6 +95,52 @@ func CreateLedger(genesisBlock *common.Block) (ledger.PeerLedger, error) {
 	return l, nil
 }
 
+// CreateLedgerFromSnapshot creates a new ledger from the snapshot present in the given directory, once the
+// blocks of the snapshot are verified by the verifier. The chain id retrieved from the last config block in
+// the snapshot is treated as a ledger id
+func CreateLedgerFromSnapshot(snapshotDir string, verifier ledger.SnapshotBlocksVerifier) (ledger.PeerLedger, error) {
+	lock.Lock()
+	defer lock.Unlock()
+	if !initialized {
+		return nil, ErrLedgerMgmtNotInitialized
+	}
+	logger.Infof("Creating ledger from snapshot [%s]", snapshotDir)
+	l, err := ledgerProvider.CreateFromSnapshot(snapshotDir, verifier)
+	if err != nil {
+		return nil, err
+	}
+	info, err := l.GetBlockchainInfo()
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	lastBlock, err := l.GetBlockByNumber(info.Height - 1)
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	// the ledger id is retrieved from the last config block, which is always a part of the snapshot
+	configBlockNum, err := utils.GetLastConfigIndexFromBlock(lastBlock)
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	configBlock, err := l.GetBlockByNumber(configBlockNum)
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	id, err := utils.GetChainIDFromBlock(configBlock)
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	l = wrapLedger(id, l)
+	openedLedgers[id] = l
+	logger.Infof("Created ledger [%s] from snapshot", id)
+	return l, nil
+}
+
 // OpenLedger returns a ledger for the given id
 func OpenLedger(id string) (ledger.PeerLedger, error) {
 	logger.Infof("Opening ledger with id = %s", id)
@@ -18,6 +18,8 @@ package ledgermgmt
 
 import (
 	"fmt"
+	"io/ioutil"
+	"path/filepath"
 	"testing"
 
 	"os"
@@ -91,6 +93,37 @@ func TestLedgerMgmt(t *testing.T) {
 	Close()
 }
 
+func TestCreateLedgerFromSnapshot(t *testing.T) {
+	snapshotRootDir, err := ioutil.TempDir("", "ledgermgmt-snapshot")
+	testutil.AssertNoError(t, err, "")
+	defer os.RemoveAll(snapshotRootDir)
+	snapshotDir := filepath.Join(snapshotRootDir, "snapshot")
+
+	InitializeTestEnv()
+	ledgerID := constructTestLedgerID(0)
+	gb, _ := test.MakeGenesisBlock(ledgerID)
+	l, err := CreateLedger(gb)
+	testutil.AssertNoError(t, err, "")
+	_, err = l.ExportSnapshot(snapshotDir)
+	testutil.AssertNoError(t, err, "")
+	CleanupTestEnv()
+
+	InitializeTestEnv()
+	defer CleanupTestEnv()
+	_, err = CreateLedgerFromSnapshot(filepath.Join(snapshotRootDir, "non-existent"), nil)
+	testutil.AssertError(t, err, "")
+	l, err = CreateLedgerFromSnapshot(snapshotDir, nil)
+	testutil.AssertNoError(t, err, "")
+	bcInfo, err := l.GetBlockchainInfo()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, bcInfo.Height, uint64(1))
+
+	ids, _ := GetLedgerIDs()
+	testutil.AssertEquals(t, ids, []string{ledgerID})
+	_, err = OpenLedger(ledgerID)
+	testutil.AssertEquals(t, err, ErrLedgerAlreadyOpened)
+}
+
 func constructTestLedgerID(i int) string {
 	return fmt.Sprintf("ledger_%06d", i)
 }
@@ -20,14 +20,18 @@ import (
 	"fmt"
 	"sync"
 
+	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage"
 	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/ledger/pvtdatapolicy"
 	"github.com/hyperledger/fabric/core/ledger/pvtdatastorage"
 	"github.com/hyperledger/fabric/protos/common"
 )
 
+var logger = flogging.MustGetLogger("ledgerstorage")
+
 // Provider encapusaltes two providers 1) block store provider and 2) and pvt data store provider
 type Provider struct {
 	blkStoreProvider     blkstorage.BlockStoreProvider
@@ -44,6 +48,33 @@ type Store struct {
 // NewProvider returns the handle to the provider
 func NewProvider() *Provider {
 	// Initialize the block storage
+	blockStoreProvider := fsblkstorage.NewProvider(blockStoreConf(), blockStoreIndexConfig())
+	pvtStoreProvider := pvtdatastorage.NewProvider()
+	return &Provider{blockStoreProvider, pvtStoreProvider}
+}
+
+// Rollback rolls back the block store and the pvt data store of the given ledger such that the block
+// `blockNum` becomes the last block. The block store is rolled back first, a crash before the pvt data store
+// is rolled back is recovered when the store is opened next time. This function is expected to be invoked
+// only when none of the ledgers is open
+func Rollback(ledgerid string, blockNum uint64) error {
+	if err := fsblkstorage.Rollback(blockStoreConf(), blockStoreIndexConfig(), ledgerid, blockNum); err != nil {
+		return err
+	}
+	pvtStoreProvider := pvtdatastorage.NewProvider()
+	defer pvtStoreProvider.Close()
+	pvtdataStore, err := pvtStoreProvider.OpenStore(ledgerid)
+	if err != nil {
+		return err
+	}
+	return pvtdataStore.RollbackToBlock(blockNum)
+}
+
+func blockStoreConf() *fsblkstorage.Conf {
+	return fsblkstorage.NewConf(ledgerconfig.GetBlockStorePath(), ledgerconfig.GetMaxBlockfileSize())
+}
+
+func blockStoreIndexConfig() *blkstorage.IndexConfig {
 	attrsToIndex := []blkstorage.IndexableAttr{
 		blkstorage.IndexableAttrBlockHash,
 		blkstorage.IndexableAttrBlockNum,
@@ -52,13 +83,7 @@ func NewProvider() *Provider {
 		blkstorage.IndexableAttrBlockTxID,
 		blkstorage.IndexableAttrTxValidationCode,
 	}
-	indexConfig := &blkstorage.IndexConfig{AttrsToIndex: attrsToIndex}
-	blockStoreProvider := fsblkstorage.NewProvider(
-		fsblkstorage.NewConf(ledgerconfig.GetBlockStorePath(), ledgerconfig.GetMaxBlockfileSize()),
-		indexConfig)
-
-	pvtStoreProvider := pvtdatastorage.NewProvider()
-	return &Provider{blockStoreProvider, pvtStoreProvider}
+	return &blkstorage.IndexConfig{AttrsToIndex: attrsToIndex}
 }
 
 // Open opens the store
@@ -86,6 +111,11 @@ func (p *Provider) Close() {
 	p.pvtdataStoreProvider.Close()
 }
 
+// Init initializes store with essential configurations
+func (s *Store) Init(btlPolicy pvtdatapolicy.BTLPolicy) {
+	s.pvtdataStore.Init(btlPolicy)
+}
+
 // CommitWithPvtData commits the block and the corresponding pvt data in an atomic operation
 func (s *Store) CommitWithPvtData(blockAndPvtdata *ledger.BlockAndPvtData) error {
 	s.rwlock.Lock()
@@ -94,7 +124,7 @@ func (s *Store) CommitWithPvtData(blockAndPvtdata *ledger.BlockAndPvtData) error
 	for _, v := range blockAndPvtdata.BlockPvtData {
 		pvtdata = append(pvtdata, v)
 	}
-	if err := s.pvtdataStore.Prepare(blockAndPvtdata.Block.Header.Number, pvtdata); err != nil {
+	if err := s.pvtdataStore.Prepare(blockAndPvtdata.Block.Header.Number, pvtdata, blockAndPvtdata.Missing); err != nil {
 		return err
 	}
 	if err := s.AddBlock(blockAndPvtdata.Block); err != nil {
@@ -104,6 +134,40 @@ func (s *Store) CommitWithPvtData(blockAndPvtdata *ledger.BlockAndPvtData) error
 	return s.pvtdataStore.Commit()
 }
 
+// BootstrapFromSnapshot adds the last block of a ledger snapshot as the first block of the empty block store
+// and brings the pvt data store upto the same block. No pvt data is available for the blocks upto the snapshot
+func (s *Store) BootstrapFromSnapshot(lastBlock *common.Block) error {
+	s.rwlock.Lock()
+	defer s.rwlock.Unlock()
+	if err := s.BlockStore.BootstrapFromSnapshot(lastBlock); err != nil {
+		return err
+	}
+	_, err := s.initPvtdataStoreFromExistingBlockchain()
+	return err
+}
+
+// CommitPvtDataOfOldBlocks commits the pvt data of the already committed blocks that was missing
+// at the time of the commit of the corresponding blocks
+func (s *Store) CommitPvtDataOfOldBlocks(blocksPvtData []*ledger.BlockPvtData) error {
+	s.rwlock.Lock()
+	defer s.rwlock.Unlock()
+	pvtdata := make(map[uint64][]*ledger.TxPvtData)
+	for _, blockPvtData := range blocksPvtData {
+		for _, v := range blockPvtData.WriteSets {
+			pvtdata[blockPvtData.BlockNum] = append(pvtdata[blockPvtData.BlockNum], v)
+		}
+	}
+	return s.pvtdataStore.CommitPvtDataOfOldBlocks(pvtdata)
+}
+
+// GetMissingPvtDataInfoForMostRecentBlocks returns the information about the missing pvt data
+// for at most `maxBlocks` most recent blocks that have some missing pvt data
+func (s *Store) GetMissingPvtDataInfoForMostRecentBlocks(maxBlocks int) (ledger.MissingPvtDataInfo, error) {
+	s.rwlock.RLock()
+	defer s.rwlock.RUnlock()
+	return s.pvtdataStore.GetMissingPvtDataInfoForMostRecentBlocks(maxBlocks)
+}
+
 // GetPvtDataAndBlockByNum returns the block and the corresponding pvt data.
 // The pvt data is filtered by the list of 'collections' supplied
 func (s *Store) GetPvtDataAndBlockByNum(blockNum uint64, filter ledger.PvtNsCollFilter) (*ledger.BlockAndPvtData, error) {
@@ -190,18 +254,13 @@ func (s *Store) initPvtdataStoreFromExistingBlockchain() (bool, error) {
 // of pvt data that was not committed. If a pending batch exists, the check is made
 // whether the associated block was successfully committed in the block storage (before the crash)
 // or not. If the block was committed, the private data batch is committed
-// otherwise, the pvt data batch is rolledback
+// otherwise, the pvt data batch is rolledback. If the pvt data store is ahead of the block storage
+// (possibly from a system crash during the rollback of the ledger), the pvt data store is rolled back
+// to the last block in the block storage
 func (s *Store) syncPvtdataStoreWithBlockStore() error {
-	var pendingPvtbatch bool
-	var err error
-	if pendingPvtbatch, err = s.pvtdataStore.HasPendingBatch(); err != nil {
-		return err
-	}
-	if !pendingPvtbatch {
-		return nil
-	}
 	var bcInfo *common.BlockchainInfo
 	var pvtdataStoreHt uint64
+	var err error
 
 	if bcInfo, err = s.GetBlockchainInfo(); err != nil {
 		return err
@@ -209,6 +268,19 @@ func (s *Store) syncPvtdataStoreWithBlockStore() error {
 	if pvtdataStoreHt, err = s.pvtdataStore.LastCommittedBlockHeight(); err != nil {
 		return err
 	}
+	if bcInfo.Height > 0 && pvtdataStoreHt > bcInfo.Height {
+		logger.Infof("Pvt data store height [%d] is ahead of block store height [%d], rolling back the pvt data store",
+			pvtdataStoreHt, bcInfo.Height)
+		return s.pvtdataStore.RollbackToBlock(bcInfo.Height - 1)
+	}
+
+	var pendingPvtbatch bool
+	if pendingPvtbatch, err = s.pvtdataStore.HasPendingBatch(); err != nil {
+		return err
+	}
+	if !pendingPvtbatch {
+		return nil
+	}
 
 	if bcInfo.Height == pvtdataStoreHt {
 		return s.pvtdataStore.Rollback()
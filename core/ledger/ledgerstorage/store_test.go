@@ -151,6 +151,98 @@ func TestStoreWithExistingBlockchain(t *testing.T) {
 	assert.Equal(t, uint64(10), pvtdataBlockHt)
 }
 
+func TestStoreBootstrapFromSnapshot(t *testing.T) {
+	testEnv := newTestEnv(t)
+	defer testEnv.cleanup()
+	provider := NewProvider()
+	defer provider.Close()
+	store, err := provider.Open("testLedger")
+	assert.NoError(t, err)
+	defer store.Shutdown()
+
+	testBlocks := testutil.ConstructTestBlocks(t, 10)
+	assert.NoError(t, store.BootstrapFromSnapshot(testBlocks[7]))
+	firstAvailable, err := store.GetFirstAvailableBlockNum()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(7), firstAvailable)
+	pvtdataBlockHt, err := store.pvtdataStore.LastCommittedBlockHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(8), pvtdataBlockHt)
+
+	// the blocks after the snapshot are committed in the normal course
+	pvtdata := samplePvtData(t, []uint64{0})
+	assert.NoError(t, store.CommitWithPvtData(&ledger.BlockAndPvtData{Block: testBlocks[8], BlockPvtData: pvtdata}))
+	blockAndPvtdata, err := store.GetPvtDataAndBlockByNum(8, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, testBlocks[8].Header, blockAndPvtdata.Block.Header)
+	assert.Equal(t, 1, len(blockAndPvtdata.BlockPvtData))
+}
+
+func TestStoreRollback(t *testing.T) {
+	testEnv := newTestEnv(t)
+	defer testEnv.cleanup()
+	provider := NewProvider()
+	store, err := provider.Open("testLedger")
+	assert.NoError(t, err)
+	sampleData := sampleData(t)
+	for _, sampleDatum := range sampleData {
+		assert.NoError(t, store.CommitWithPvtData(sampleDatum))
+	}
+	store.Shutdown()
+	provider.Close()
+
+	assert.Error(t, Rollback("testLedger", 9))
+	assert.NoError(t, Rollback("testLedger", 2))
+
+	provider = NewProvider()
+	store, err = provider.Open("testLedger")
+	assert.NoError(t, err)
+	bcInfo, err := store.GetBlockchainInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), bcInfo.Height)
+	pvtdataBlockHt, err := store.pvtdataStore.LastCommittedBlockHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), pvtdataBlockHt)
+	blockAndPvtdata, err := store.GetPvtDataAndBlockByNum(2, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, sampleData[2], blockAndPvtdata)
+
+	// the blocks after the rollback target are committed again
+	for _, sampleDatum := range sampleData[3:] {
+		assert.NoError(t, store.CommitWithPvtData(sampleDatum))
+	}
+	blockAndPvtdata, err = store.GetPvtDataAndBlockByNum(3, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, sampleData[3], blockAndPvtdata)
+	store.Shutdown()
+	provider.Close()
+}
+
+func TestStoreSyncAfterInterruptedRollback(t *testing.T) {
+	testEnv := newTestEnv(t)
+	defer testEnv.cleanup()
+	provider := NewProvider()
+	store, err := provider.Open("testLedger")
+	assert.NoError(t, err)
+	for _, sampleDatum := range sampleData(t) {
+		assert.NoError(t, store.CommitWithPvtData(sampleDatum))
+	}
+	store.Shutdown()
+	provider.Close()
+
+	// simulate a crash after the block store is rolled back but before the pvt data store is rolled back
+	assert.NoError(t, fsblkstorage.Rollback(blockStoreConf(), blockStoreIndexConfig(), "testLedger", 5))
+
+	provider = NewProvider()
+	defer provider.Close()
+	store, err = provider.Open("testLedger")
+	assert.NoError(t, err)
+	defer store.Shutdown()
+	pvtdataBlockHt, err := store.pvtdataStore.LastCommittedBlockHeight()
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(6), pvtdataBlockHt)
+}
+
 func sampleData(t *testing.T) []*ledger.BlockAndPvtData {
 	var blockAndpvtdata []*ledger.BlockAndPvtData
 	blocks := testutil.ConstructTestBlocks(t, 10)
@@ -0,0 +1,96 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pvtdatapolicy
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	"github.com/hyperledger/fabric/protos/common"
+)
+
+var defaultBTL uint64 = math.MaxUint64
+
+// BTLPolicy BlockToLive policy for the pvt data
+type BTLPolicy interface {
+	// GetBTL returns BlockToLive for a given namespace and collection
+	GetBTL(ns string, coll string) (uint64, error)
+	// GetExpiringBlock returns the block number by which the pvtdata for given namespace,collection, and committingBlock should expire
+	GetExpiringBlock(namespace string, collection string, committingBlock uint64) (uint64, error)
+}
+
+// CollectionInfoProvider provides the configuration of the collections of a chaincode
+type CollectionInfoProvider interface {
+	// CollectionInfo returns the configuration of the given collection of the given chaincode.
+	// A nil config is returned if the collection does not exist
+	CollectionInfo(chaincodeName, collectionName string) (*common.StaticCollectionConfig, error)
+}
+
+// LSCCBasedBTLPolicy implements interface BTLPolicy.
+// This implementation loads the BTL policy from lscc namespace which is populated
+// with the collection configuration during chaincode initialization.
+// As the collections are immutable, the BTL of a collection is cached once loaded
+type LSCCBasedBTLPolicy struct {
+	collInfoProvider CollectionInfoProvider
+	cache            map[btlkey]uint64
+	lock             sync.Mutex
+}
+
+type btlkey struct {
+	ns   string
+	coll string
+}
+
+// NewBTLPolicy constructs an instance of LSCCBasedBTLPolicy
+func NewBTLPolicy(collInfoProvider CollectionInfoProvider) BTLPolicy {
+	return &LSCCBasedBTLPolicy{
+		collInfoProvider: collInfoProvider,
+		cache:            make(map[btlkey]uint64),
+	}
+}
+
+// GetBTL implements corresponding function in interface `BTLPolicy`.
+// A collection without a configured BlockToLive never expires, this is
+// indicated by the return value `math.MaxUint64`
+func (p *LSCCBasedBTLPolicy) GetBTL(namespace string, collection string) (uint64, error) {
+	key := btlkey{namespace, collection}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	btl, ok := p.cache[key]
+	if ok {
+		return btl, nil
+	}
+	collConfig, err := p.collInfoProvider.CollectionInfo(namespace, collection)
+	if err != nil {
+		return 0, err
+	}
+	if collConfig == nil {
+		return 0, fmt.Errorf("collection [%s:%s] not found", namespace, collection)
+	}
+	btl = collConfig.BlockToLive
+	if btl == 0 {
+		btl = defaultBTL
+	}
+	p.cache[key] = btl
+	return btl, nil
+}
+
+// GetExpiringBlock implements function from the interface `BTLPolicy`.
+// The pvt data committed in the `committingBlock` expires when the returned block number is committed.
+// `math.MaxUint64` is returned if the pvt data never expires
+func (p *LSCCBasedBTLPolicy) GetExpiringBlock(namespace string, collection string, committingBlock uint64) (uint64, error) {
+	btl, err := p.GetBTL(namespace, collection)
+	if err != nil {
+		return 0, err
+	}
+	expiryBlk := committingBlock + btl + uint64(1)
+	if expiryBlk <= committingBlock { // committingBlk + btl overflows uint64-max
+		expiryBlk = math.MaxUint64
+	}
+	return expiryBlk, nil
+}
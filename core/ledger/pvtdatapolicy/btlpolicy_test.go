@@ -0,0 +1,65 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package pvtdatapolicy
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockCollectionInfoProvider struct {
+	configs map[[2]string]*common.StaticCollectionConfig
+	calls   int
+	err     error
+}
+
+func (p *mockCollectionInfoProvider) CollectionInfo(chaincodeName, collectionName string) (*common.StaticCollectionConfig, error) {
+	p.calls++
+	return p.configs[[2]string{chaincodeName, collectionName}], p.err
+}
+
+func TestBTLPolicy(t *testing.T) {
+	collInfoProvider := &mockCollectionInfoProvider{
+		configs: map[[2]string]*common.StaticCollectionConfig{
+			{"ns1", "coll1"}: {Name: "coll1", BlockToLive: 100},
+			{"ns1", "coll2"}: {Name: "coll2"},
+			{"ns1", "coll3"}: {Name: "coll3", BlockToLive: math.MaxUint64 - 10},
+		},
+	}
+	btlPolicy := NewBTLPolicy(collInfoProvider)
+
+	btl, err := btlPolicy.GetBTL("ns1", "coll1")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(100), btl)
+	expiringBlk, err := btlPolicy.GetExpiringBlock("ns1", "coll1", 50)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(151), expiringBlk)
+	// BTL of a collection is loaded only once
+	assert.Equal(t, 1, collInfoProvider.calls)
+
+	btl, err = btlPolicy.GetBTL("ns1", "coll2")
+	assert.NoError(t, err)
+	assert.Equal(t, defaultBTL, btl)
+	expiringBlk, err = btlPolicy.GetExpiringBlock("ns1", "coll2", 50)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(math.MaxUint64), expiringBlk)
+
+	expiringBlk, err = btlPolicy.GetExpiringBlock("ns1", "coll3", 50)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(math.MaxUint64), expiringBlk)
+
+	_, err = btlPolicy.GetBTL("ns1", "non-existing-coll")
+	assert.EqualError(t, err, "collection [ns1:non-existing-coll] not found")
+
+	collInfoProvider.err = errors.New("error while retrieving collection config")
+	_, err = btlPolicy.GetExpiringBlock("ns2", "coll1", 50)
+	assert.EqualError(t, err, "error while retrieving collection config")
+}
@@ -0,0 +1,39 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package testutil
+
+import (
+	"github.com/hyperledger/fabric/core/ledger/pvtdatapolicy"
+	"github.com/hyperledger/fabric/protos/common"
+)
+
+// SampleBTLPolicy helps tests create a sample BTLPolicy
+// The example below creates a BTLPolicy with 2 namespaces with 2 collections each
+// ns1:coll1 has BTL of 100, ns1:coll2 has BTL of 200 and so on
+// The collections that are not specified have no BTL i.e., their pvt data never expires
+// testutil.SampleBTLPolicy(
+// 	map[[2]string]uint64{
+// 		[2]string{"ns1", "coll1"}: 100,
+// 		[2]string{"ns1", "coll2"}: 200,
+// 		[2]string{"ns2", "coll1"}: 300,
+// 		[2]string{"ns2", "coll2"}: 400,
+// 	},
+// )
+func SampleBTLPolicy(m map[[2]string]uint64) pvtdatapolicy.BTLPolicy {
+	return pvtdatapolicy.NewBTLPolicy(&mockCollectionInfoProvider{m})
+}
+
+type mockCollectionInfoProvider struct {
+	btlMap map[[2]string]uint64
+}
+
+func (p *mockCollectionInfoProvider) CollectionInfo(chaincodeName, collectionName string) (*common.StaticCollectionConfig, error) {
+	return &common.StaticCollectionConfig{
+		Name:        collectionName,
+		BlockToLive: p.btlMap[[2]string{chaincodeName, collectionName}],
+	}, nil
+}
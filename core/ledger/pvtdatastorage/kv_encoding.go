@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package pvtdatastorage
 
 import (
+	"bytes"
 	"math"
 
 	"github.com/golang/protobuf/proto"
@@ -15,10 +16,13 @@ import (
 )
 
 var (
-	pendingCommitKey    = []byte{0}
-	lastCommittedBlkkey = []byte{1}
-	pvtDataKeyPrefix    = []byte{2}
+	pendingCommitKey     = []byte{0}
+	lastCommittedBlkkey  = []byte{1}
+	pvtDataKeyPrefix     = []byte{2}
+	expiryKeyPrefix      = []byte{3}
+	missingDataKeyPrefix = []byte{4}
 
+	nilByte    = byte(0)
 	emptyValue = []byte{}
 )
 
@@ -31,12 +35,123 @@ func decodePK(key blkTranNumKey) (blockNum uint64, tranNum uint64) {
 	return height.BlockNum, height.TxNum
 }
 
+func encodeExpiryKey(expiryKey *expiryKey) []byte {
+	// reusing version encoding scheme here
+	return append(expiryKeyPrefix, version.NewHeight(expiryKey.expiringBlk, expiryKey.committingBlk).ToBytes()...)
+}
+
+func decodeExpiryKey(expiryKeyBytes []byte) *expiryKey {
+	height, _ := version.NewHeightFromBytes(expiryKeyBytes[1:])
+	return &expiryKey{expiringBlk: height.BlockNum, committingBlk: height.TxNum}
+}
+
+// encodeExpiryValue encodes the <tranNum, namespace, collection> tuples as a sequence of
+// varint(tranNum), string(namespace), string(collection) preceded by the number of tuples
+func encodeExpiryValue(expiryData expiryData) []byte {
+	numTuples := 0
+	for _, nsColls := range expiryData {
+		for _, colls := range nsColls {
+			numTuples += len(colls)
+		}
+	}
+	buf := proto.NewBuffer(nil)
+	buf.EncodeVarint(uint64(numTuples))
+	for tranNum, nsColls := range expiryData {
+		for ns, colls := range nsColls {
+			for coll := range colls {
+				buf.EncodeVarint(tranNum)
+				buf.EncodeStringBytes(ns)
+				buf.EncodeStringBytes(coll)
+			}
+		}
+	}
+	return buf.Bytes()
+}
+
+func decodeExpiryValue(expiryValueBytes []byte) (expiryData, error) {
+	buf := proto.NewBuffer(expiryValueBytes)
+	numTuples, err := buf.DecodeVarint()
+	if err != nil {
+		return nil, err
+	}
+	expiryData := make(expiryData)
+	for i := uint64(0); i < numTuples; i++ {
+		tranNum, err := buf.DecodeVarint()
+		if err != nil {
+			return nil, err
+		}
+		ns, err := buf.DecodeStringBytes()
+		if err != nil {
+			return nil, err
+		}
+		coll, err := buf.DecodeStringBytes()
+		if err != nil {
+			return nil, err
+		}
+		expiryData.add(tranNum, ns, coll)
+	}
+	return expiryData, nil
+}
+
+func encodeMissingDataKey(key *missingDataKey) []byte {
+	encKey := append(missingDataKeyPrefix, version.NewHeight(key.blkNum, key.txNum).ToBytes()...)
+	encKey = append(encKey, []byte(key.ns)...)
+	encKey = append(encKey, nilByte)
+	return append(encKey, []byte(key.coll)...)
+}
+
+func decodeMissingDataKey(keyBytes []byte) *missingDataKey {
+	height, n := version.NewHeightFromBytes(keyBytes[1:])
+	split := bytes.SplitN(keyBytes[n+1:], []byte{nilByte}, 2)
+	key := &missingDataKey{blkNum: height.BlockNum, txNum: height.TxNum, ns: string(split[0])}
+	if len(split) == 2 {
+		key.coll = string(split[1])
+	}
+	return key
+}
+
 func getKeysForRangeScanByBlockNum(blockNum uint64) (startKey []byte, endKey []byte) {
 	startKey = encodePK(blockNum, 0)
 	endKey = encodePK(blockNum, math.MaxUint64)
 	return
 }
 
+func getKeysForRangeScanOfExpiryEntries(maxExpiringBlk uint64) (startKey []byte, endKey []byte) {
+	startKey = encodeExpiryKey(&expiryKey{0, 0})
+	endKey = encodeExpiryKey(&expiryKey{maxExpiringBlk, math.MaxUint64})
+	return
+}
+
+func getKeysForRangeScanOfMissingDataEntries(blockNum uint64) (startKey []byte, endKey []byte) {
+	startKey = append(missingDataKeyPrefix, version.NewHeight(blockNum, 0).ToBytes()...)
+	endKey = append(missingDataKeyPrefix, version.NewHeight(blockNum+1, 0).ToBytes()...)
+	return
+}
+
+func getKeysForRangeScanOfAllMissingDataEntries() (startKey []byte, endKey []byte) {
+	startKey = missingDataKeyPrefix
+	endKey = []byte{missingDataKeyPrefix[0] + 1}
+	return
+}
+
+func getKeysForRangeScanOfDataEntriesAfter(blockNum uint64) (startKey []byte, endKey []byte) {
+	startKey = encodePK(blockNum+1, 0)
+	endKey = []byte{pvtDataKeyPrefix[0] + 1}
+	return
+}
+
+func getKeysForRangeScanOfMissingDataEntriesAfter(blockNum uint64) (startKey []byte, endKey []byte) {
+	startKey = append(missingDataKeyPrefix, version.NewHeight(blockNum+1, 0).ToBytes()...)
+	endKey = []byte{missingDataKeyPrefix[0] + 1}
+	return
+}
+
+func getKeysForRangeScanOfAllExpiryEntries() (startKey []byte, endKey []byte) {
+	startKey = expiryKeyPrefix
+	endKey = []byte{expiryKeyPrefix[0] + 1}
+	return
+}
+
 func encodePvtRwSet(txPvtRwSet *rwset.TxPvtReadWriteSet) ([]byte, error) {
 	return proto.Marshal(txPvtRwSet)
 }
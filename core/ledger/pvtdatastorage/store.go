@@ -8,6 +8,7 @@ package pvtdatastorage
 
 import (
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/pvtdatapolicy"
 )
 
 // Provider provides handle to specific 'Store' that in turn manages
@@ -28,6 +29,11 @@ type Provider interface {
 // on whether the block was written successfully or not. The store implementation
 // is expected to survive a server crash between the call to `Prepare` and `Commit`/`Rollback`
 type Store interface {
+	// Init initializes the store with the BlockToLive policy of the pvt data. This function is expected to be
+	// invoked before using the store. The pvt data committed in a block expires as per the policy and is not
+	// returned by the store anymore. The expired pvt data is purged from the store periodically.
+	// If no policy is supplied, the pvt data never expires
+	Init(btlPolicy pvtdatapolicy.BTLPolicy)
 	// InitLastCommittedBlockHeight sets the last commited block height into the pvt data store
 	// This function is used in a special case where the peer is started up with the blockchain
 	// from an earlier version of a peer when the pvt data feature (and hence this store) was not
@@ -40,16 +46,29 @@ type Store interface {
 	// The pvt data is filtered by the list of 'ns/collections' supplied in the filter
 	// A nil filter does not filter any results
 	GetPvtDataByBlockNum(blockNum uint64, filter ledger.PvtNsCollFilter) ([]*ledger.TxPvtData, error)
+	// GetMissingPvtDataInfoForMostRecentBlocks returns the information about the pvt data that was missing at the time
+	// of the commit of the corresponding blocks and that has not been committed since. The information is returned for
+	// at most `maxBlocks` most recent blocks that have some missing pvt data. The expired pvt data is not included
+	GetMissingPvtDataInfoForMostRecentBlocks(maxBlocks int) (ledger.MissingPvtDataInfo, error)
 	// Prepare prepares the Store for commiting the pvt data. This call does not commit the pvt data.
 	// Subsequently, the caller is expected to call either `Commit` or `Rollback` function.
 	// Return from this should ensure that enough preparation is done such that `Commit` function invoked afterwards
 	// can commit the data and the store is capable of surviving a crash between this function call and the next
-	// invoke to the `Commit`
-	Prepare(blockNum uint64, pvtData []*ledger.TxPvtData) error
+	// invoke to the `Commit`. The `missingPvtData` is recorded so that it can be committed later via the function
+	// `CommitPvtDataOfOldBlocks`
+	Prepare(blockNum uint64, pvtData []*ledger.TxPvtData, missingPvtData []ledger.MissingPrivateData) error
 	// Commit commits the pvt data passed in the previous invoke to the `Prepare` function
 	Commit() error
 	// Rollback rolls back the pvt data passed in the previous invoke to the `Prepare` function
 	Rollback() error
+	// CommitPvtDataOfOldBlocks commits the pvt data of the already committed blocks. Only the pvt data that
+	// was recorded as missing at the time of the commit of the corresponding block is committed, the rest
+	// of the supplied pvt data, including the expired pvt data, is ignored
+	CommitPvtDataOfOldBlocks(blocksPvtData map[uint64][]*ledger.TxPvtData) error
+	// RollbackToBlock removes the pvt data, the missing data entries and the expiry entries of the blocks committed
+	// after the given block and makes the given block the last committed block. Any pending batch is discarded.
+	// This function is expected to be invoked only when the ledger is not in use, i.e., while the peer is stopped
+	RollbackToBlock(blockNum uint64) error
 	// IsEmpty returns true if the store does not have any block committed yet
 	IsEmpty() (bool, error)
 	// LastCommittedBlockHeight returns the height of the last committed block
@@ -8,11 +8,13 @@ package pvtdatastorage
 
 import (
 	"fmt"
+	"math"
 
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
 	"github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/hyperledger/fabric/core/ledger/pvtdatapolicy"
 	"github.com/hyperledger/fabric/protos/ledger/rwset"
 )
 
@@ -25,6 +27,8 @@ type provider struct {
 type store struct {
 	db                 *leveldbhelper.DBHandle
 	ledgerid           string
+	btlPolicy          pvtdatapolicy.BTLPolicy
+	purgeInterval      uint64
 	isEmpty            bool
 	lastCommittedBlock uint64
 	batchPending       bool
@@ -32,6 +36,35 @@ type store struct {
 
 type blkTranNumKey []byte
 
+// expiryKey identifies the pvt data committed in the block `committingBlk`
+// that expires when the block `expiringBlk` is committed
+type expiryKey struct {
+	expiringBlk   uint64
+	committingBlk uint64
+}
+
+// missingDataKey identifies the pvt data of a collection, written by the transaction `txNum` of the
+// block `blkNum`, that was missing at the time of the commit of the block
+type missingDataKey struct {
+	blkNum uint64
+	txNum  uint64
+	ns     string
+	coll   string
+}
+
+// expiryData maintains, by tran number, the <namespace, collection> tuples
+// whose pvt data expire at the same block
+type expiryData map[uint64]ledger.PvtNsCollFilter
+
+func (e expiryData) add(tranNum uint64, ns, coll string) {
+	nsColls, ok := e[tranNum]
+	if !ok {
+		nsColls = ledger.NewPvtNsCollFilter()
+		e[tranNum] = nsColls
+	}
+	nsColls.Add(ns, coll)
+}
+
 // NewProvider instantiates a StoreProvider
 func NewProvider() Provider {
 	dbPath := ledgerconfig.GetPvtdataStorePath()
@@ -42,7 +75,7 @@ func NewProvider() Provider {
 // OpenStore returns a handle to a store
 func (p *provider) OpenStore(ledgerid string) (Store, error) {
 	dbHandle := p.dbProvider.GetDBHandle(ledgerid)
-	s := &store{db: dbHandle, ledgerid: ledgerid}
+	s := &store{db: dbHandle, ledgerid: ledgerid, purgeInterval: ledgerconfig.GetPvtdataStorePurgeInterval()}
 	if err := s.initState(); err != nil {
 		return nil, err
 	}
@@ -54,6 +87,11 @@ func (p *provider) Close() {
 	p.dbProvider.Close()
 }
 
+// Init implements the function in the interface `Store`
+func (s *store) Init(btlPolicy pvtdatapolicy.BTLPolicy) {
+	s.btlPolicy = btlPolicy
+}
+
 func (s *store) initState() error {
 	var err error
 	if s.isEmpty, s.lastCommittedBlock, err = s.getLastCommittedBlockNum(); err != nil {
@@ -66,7 +104,7 @@ func (s *store) initState() error {
 }
 
 // Prepare implements the function in the interface `Store`
-func (s *store) Prepare(blockNum uint64, pvtData []*ledger.TxPvtData) error {
+func (s *store) Prepare(blockNum uint64, pvtData []*ledger.TxPvtData, missingPvtData []ledger.MissingPrivateData) error {
 	if s.batchPending {
 		return &ErrIllegalCall{`A pending batch exists as as result of last invoke to "Prepare" call.
 			 Invoke "Commit" or "Rollback" on the pending batch before invoking "Prepare" function`}
@@ -87,12 +125,24 @@ func (s *store) Prepare(blockNum uint64, pvtData []*ledger.TxPvtData) error {
 		logger.Debugf("Adding private data to LevelDB batch for block [%d], tran [%d]", blockNum, txPvtData.SeqInBlock)
 		batch.Put(key, value)
 	}
+	for _, missing := range missingPvtData {
+		key = encodeMissingDataKey(&missingDataKey{blockNum, uint64(missing.SeqInBlock), missing.Namespace, missing.Collection})
+		batch.Put(key, []byte(missing.TxId))
+	}
+	expiryEntries, err := s.prepareExpiryEntries(blockNum, pvtData, missingPvtData)
+	if err != nil {
+		return err
+	}
+	for expKey, expData := range expiryEntries {
+		batch.Put(encodeExpiryKey(&expKey), encodeExpiryValue(expData))
+	}
 	batch.Put(pendingCommitKey, emptyValue)
 	if err := s.db.WriteBatch(batch, true); err != nil {
 		return err
 	}
 	s.batchPending = true
-	logger.Debugf("Saved %d private data write sets for block [%d]", len(pvtData), blockNum)
+	logger.Debugf("Saved %d private data write sets and %d missing private data entries for block [%d]",
+		len(pvtData), len(missingPvtData), blockNum)
 	return nil
 }
 
@@ -113,6 +163,13 @@ func (s *store) Commit() error {
 	s.isEmpty = false
 	s.lastCommittedBlock = committingBlockNum
 	logger.Debugf("Committed private data for block [%d]", committingBlockNum)
+	if committingBlockNum%s.purgeInterval == 0 {
+		// a failed purge is not fatal, the expired data remains filtered out
+		// from the results and is purged at a subsequent purge interval
+		if err := s.purgeExpiredData(committingBlockNum); err != nil {
+			logger.Errorf("Error while purging expired private data up to block [%d]: %s", committingBlockNum, err)
+		}
+	}
 	return nil
 }
 
@@ -142,6 +199,94 @@ func (s *store) Rollback() error {
 	return nil
 }
 
+// CommitPvtDataOfOldBlocks implements the function in the interface `Store`
+func (s *store) CommitPvtDataOfOldBlocks(blocksPvtData map[uint64][]*ledger.TxPvtData) error {
+	if s.batchPending {
+		return &ErrIllegalCall{`A pending batch exists as as result of last invoke to "Prepare" call.
+			 Invoke "Commit" or "Rollback" on the pending batch before invoking "CommitPvtDataOfOldBlocks" function`}
+	}
+	batch := leveldbhelper.NewUpdateBatch()
+	numCommitted := 0
+	for blockNum, pvtData := range blocksPvtData {
+		if s.isEmpty || blockNum > s.lastCommittedBlock {
+			return &ErrOutOfRange{fmt.Sprintf("Last committed block=%d, pvt data supplied for block=%d", s.lastCommittedBlock, blockNum)}
+		}
+		for _, txPvtData := range pvtData {
+			missingWSet, missingKeys, err := s.retainMissingCollections(blockNum, txPvtData)
+			if err != nil {
+				return err
+			}
+			if missingWSet == nil {
+				continue
+			}
+			dataKey := encodePK(blockNum, txPvtData.SeqInBlock)
+			encodedWSet, err := s.db.Get(dataKey)
+			if err != nil {
+				return err
+			}
+			pvtWSet := missingWSet
+			if encodedWSet != nil {
+				existingWSet, err := decodePvtRwSet(encodedWSet)
+				if err != nil {
+					return err
+				}
+				pvtWSet = mergePvtWSets(existingWSet, missingWSet)
+			}
+			if encodedWSet, err = encodePvtRwSet(pvtWSet); err != nil {
+				return err
+			}
+			batch.Put(dataKey, encodedWSet)
+			for _, missingKey := range missingKeys {
+				batch.Delete(encodeMissingDataKey(missingKey))
+			}
+			numCommitted += len(missingKeys)
+		}
+	}
+	if numCommitted == 0 {
+		return nil
+	}
+	if err := s.db.WriteBatch(batch, true); err != nil {
+		return err
+	}
+	logger.Debugf("Committed %d missing private data write sets of old blocks", numCommitted)
+	return nil
+}
+
+// GetMissingPvtDataInfoForMostRecentBlocks implements the function in the interface `Store`
+func (s *store) GetMissingPvtDataInfoForMostRecentBlocks(maxBlocks int) (ledger.MissingPvtDataInfo, error) {
+	missingPvtDataInfo := make(ledger.MissingPvtDataInfo)
+	if maxBlocks < 1 {
+		return missingPvtDataInfo, nil
+	}
+	startKey, endKey := getKeysForRangeScanOfAllMissingDataEntries()
+	itr := s.db.GetIterator(startKey, endKey)
+	defer itr.Release()
+
+	// the missing data entries are ordered by the block number, hence scanning backwards
+	// returns the entries of the most recent blocks first
+	for ok := itr.Last(); ok; ok = itr.Prev() {
+		missingKey := decodeMissingDataKey(itr.Key())
+		if _, ok := missingPvtDataInfo[missingKey.blkNum]; !ok && len(missingPvtDataInfo) == maxBlocks {
+			break
+		}
+		expired, err := s.isExpired(missingKey.ns, missingKey.coll, missingKey.blkNum)
+		if err != nil {
+			return nil, err
+		}
+		if expired {
+			// the expired entry has not been purged yet
+			continue
+		}
+		missingPvtDataInfo.Add(missingKey.blkNum, ledger.MissingPrivateData{
+			TxId:       string(itr.Value()),
+			SeqInBlock: int(missingKey.txNum),
+			Namespace:  missingKey.ns,
+			Collection: missingKey.coll,
+		})
+	}
+	return missingPvtDataInfo, nil
+}
+
 // GetPvtDataByBlockNum implements the function in the interface `Store`.
 // If the store is empty or the last committed block number is smaller then the
 // requested block number, an 'ErrOutOfRange' is thrown
@@ -168,6 +313,16 @@ func (s *store) GetPvtDataByBlockNum(blockNum uint64, filter ledger.PvtNsCollFil
 			return nil, err
 		}
 		logger.Debugf("Retrieved private data write set for block [%d] tran [%d]", bNum, tNum)
+		expired, err := s.expiredCollections(bNum, pvtWSet)
+		if err != nil {
+			return nil, err
+		}
+		if expired != nil {
+			// the expired pvt data has not been purged yet
+			if pvtWSet = removeCollections(pvtWSet, expired); pvtWSet == nil {
+				continue
+			}
+		}
 		filteredWSet := TrimPvtWSet(pvtWSet, filter)
 		pvtData = append(pvtData, &ledger.TxPvtData{SeqInBlock: tNum, WriteSet: filteredWSet})
 	}
@@ -190,6 +345,44 @@ func (s *store) InitLastCommittedBlock(blockNum uint64) error {
 	return nil
 }
 
+// RollbackToBlock implements the function in the interface `Store`
+func (s *store) RollbackToBlock(blockNum uint64) error {
+	if s.isEmpty || blockNum > s.lastCommittedBlock {
+		return &ErrIllegalArgs{fmt.Sprintf("Cannot roll back to block [%d], last committed block is [%d]",
+			blockNum, s.lastCommittedBlock)}
+	}
+	batch := leveldbhelper.NewUpdateBatch()
+	startKey, endKey := getKeysForRangeScanOfDataEntriesAfter(blockNum)
+	s.addKeysInRangeToBeDeleted(batch, startKey, endKey)
+	startKey, endKey = getKeysForRangeScanOfMissingDataEntriesAfter(blockNum)
+	s.addKeysInRangeToBeDeleted(batch, startKey, endKey)
+	startKey, endKey = getKeysForRangeScanOfAllExpiryEntries()
+	itr := s.db.GetIterator(startKey, endKey)
+	for itr.Next() {
+		if decodeExpiryKey(itr.Key()).committingBlk > blockNum {
+			batch.Delete(itr.Key())
+		}
+	}
+	itr.Release()
+	batch.Delete(pendingCommitKey)
+	batch.Put(lastCommittedBlkkey, encodeBlockNum(blockNum))
+	if err := s.db.WriteBatch(batch, true); err != nil {
+		return err
+	}
+	s.batchPending = false
+	s.lastCommittedBlock = blockNum
+	logger.Infof("Rolled back pvt data store of ledger [%s] to block [%d]", s.ledgerid, blockNum)
+	return nil
+}
+
+func (s *store) addKeysInRangeToBeDeleted(batch *leveldbhelper.UpdateBatch, startKey, endKey []byte) {
+	itr := s.db.GetIterator(startKey, endKey)
+	defer itr.Release()
+	for itr.Next() {
+		batch.Delete(itr.Key())
+	}
+}
+
 // LastCommittedBlockHeight implements the function in the interface `Store`
 func (s *store) LastCommittedBlockHeight() (uint64, error) {
 	if s.isEmpty {
@@ -222,13 +415,208 @@ func (s *store) nextBlockNum() uint64 {
 
 func (s *store) retrievePendingBatchKeys() ([]blkTranNumKey, error) {
 	var pendingBatchKeys []blkTranNumKey
-	itr := s.db.GetIterator(encodePK(s.nextBlockNum(), 0), nil)
+	pendingBlockNum := s.nextBlockNum()
+	startKey, endKey := getKeysForRangeScanByBlockNum(pendingBlockNum)
+	itr := s.db.GetIterator(startKey, endKey)
+	defer itr.Release()
 	for itr.Next() {
-		pendingBatchKeys = append(pendingBatchKeys, itr.Key())
+		pendingBatchKeys = append(pendingBatchKeys, append([]byte{}, itr.Key()...))
+	}
+
+	startKey, endKey = getKeysForRangeScanOfMissingDataEntries(pendingBlockNum)
+	missingDataItr := s.db.GetIterator(startKey, endKey)
+	defer missingDataItr.Release()
+	for missingDataItr.Next() {
+		pendingBatchKeys = append(pendingBatchKeys, append([]byte{}, missingDataItr.Key()...))
+	}
+
+	// the expiry entries are ordered by the expiring block, hence all of them are scanned
+	startKey, endKey = getKeysForRangeScanOfAllExpiryEntries()
+	expiryItr := s.db.GetIterator(startKey, endKey)
+	defer expiryItr.Release()
+	for expiryItr.Next() {
+		if decodeExpiryKey(expiryItr.Key()).committingBlk == pendingBlockNum {
+			pendingBatchKeys = append(pendingBatchKeys, append([]byte{}, expiryItr.Key()...))
+		}
 	}
 	return pendingBatchKeys, nil
 }
 
+// prepareExpiryEntries computes the expiry entries for the pvt data, and for the missing pvt data, being committed
+// with the given block. The pvt data of the collections that do not have a BlockToLive configured never expire
+func (s *store) prepareExpiryEntries(committingBlk uint64, pvtData []*ledger.TxPvtData,
+	missingPvtData []ledger.MissingPrivateData) (map[expiryKey]expiryData, error) {
+	expiryEntries := make(map[expiryKey]expiryData)
+	if s.btlPolicy == nil {
+		return expiryEntries, nil
+	}
+	addEntry := func(tranNum uint64, ns, coll string) error {
+		expiringBlk, err := s.btlPolicy.GetExpiringBlock(ns, coll, committingBlk)
+		if err != nil {
+			return err
+		}
+		if expiringBlk == math.MaxUint64 {
+			return nil
+		}
+		expKey := expiryKey{expiringBlk: expiringBlk, committingBlk: committingBlk}
+		expData, ok := expiryEntries[expKey]
+		if !ok {
+			expData = make(expiryData)
+			expiryEntries[expKey] = expData
+		}
+		expData.add(tranNum, ns, coll)
+		return nil
+	}
+	for _, txPvtData := range pvtData {
+		for _, nsPvtRwset := range txPvtData.WriteSet.GetNsPvtRwset() {
+			for _, collPvtRwset := range nsPvtRwset.CollectionPvtRwset {
+				if err := addEntry(txPvtData.SeqInBlock, nsPvtRwset.Namespace, collPvtRwset.CollectionName); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+	for _, missing := range missingPvtData {
+		if err := addEntry(uint64(missing.SeqInBlock), missing.Namespace, missing.Collection); err != nil {
+			return nil, err
+		}
+	}
+	return expiryEntries, nil
+}
+
+// retainMissingCollections returns the part of the supplied pvt data of an old block that was recorded as missing
+// and that has not expired, along with the keys of the corresponding missing data entries
+func (s *store) retainMissingCollections(blockNum uint64, txPvtData *ledger.TxPvtData) (*rwset.TxPvtReadWriteSet, []*missingDataKey, error) {
+	var missingKeys []*missingDataKey
+	toRetain := ledger.NewPvtNsCollFilter()
+	for _, nsPvtRwset := range txPvtData.WriteSet.GetNsPvtRwset() {
+		for _, collPvtRwset := range nsPvtRwset.CollectionPvtRwset {
+			missingKey := &missingDataKey{blockNum, txPvtData.SeqInBlock, nsPvtRwset.Namespace, collPvtRwset.CollectionName}
+			v, err := s.db.Get(encodeMissingDataKey(missingKey))
+			if err != nil {
+				return nil, nil, err
+			}
+			if v == nil {
+				logger.Debugf("Ignoring private data for block [%d], tran [%d], namespace [%s], collection [%s] as it is not missing",
+					blockNum, txPvtData.SeqInBlock, nsPvtRwset.Namespace, collPvtRwset.CollectionName)
+				continue
+			}
+			expired, err := s.isExpired(nsPvtRwset.Namespace, collPvtRwset.CollectionName, blockNum)
+			if err != nil {
+				return nil, nil, err
+			}
+			if expired {
+				continue
+			}
+			toRetain.Add(nsPvtRwset.Namespace, collPvtRwset.CollectionName)
+			missingKeys = append(missingKeys, missingKey)
+		}
+	}
+	if len(missingKeys) == 0 {
+		return nil, nil, nil
+	}
+	return TrimPvtWSet(txPvtData.WriteSet, toRetain), missingKeys, nil
+}
+
+// isExpired returns true if the pvt data of the given collection committed in the given block
+// is expired as of the last committed block
+func (s *store) isExpired(ns, coll string, committingBlk uint64) (bool, error) {
+	if s.btlPolicy == nil {
+		return false, nil
+	}
+	expiringBlk, err := s.btlPolicy.GetExpiringBlock(ns, coll, committingBlk)
+	if err != nil {
+		return false, err
+	}
+	return expiringBlk <= s.lastCommittedBlock, nil
+}
+
+// expiredCollections returns the <namespace, collection> tuples of the given write set, committed in the
+// given block, that are expired as of the last committed block. A nil is returned if none is expired
+func (s *store) expiredCollections(committingBlk uint64, pvtWSet *rwset.TxPvtReadWriteSet) (ledger.PvtNsCollFilter, error) {
+	if s.btlPolicy == nil {
+		return nil, nil
+	}
+	var expired ledger.PvtNsCollFilter
+	for _, nsPvtRwset := range pvtWSet.GetNsPvtRwset() {
+		for _, collPvtRwset := range nsPvtRwset.CollectionPvtRwset {
+			isExpired, err := s.isExpired(nsPvtRwset.Namespace, collPvtRwset.CollectionName, committingBlk)
+			if err != nil {
+				return nil, err
+			}
+			if !isExpired {
+				continue
+			}
+			if expired == nil {
+				expired = ledger.NewPvtNsCollFilter()
+			}
+			expired.Add(nsPvtRwset.Namespace, collPvtRwset.CollectionName)
+		}
+	}
+	return expired, nil
+}
+
+// purgeExpiredData removes the pvt data, and the missing data entries, that expire at or before the given block
+// along with the corresponding expiry entries. A write set is removed entirely once all of its collections expire
+func (s *store) purgeExpiredData(maxExpiringBlk uint64) error {
+	startKey, endKey := getKeysForRangeScanOfExpiryEntries(maxExpiringBlk)
+	itr := s.db.GetIterator(startKey, endKey)
+	defer itr.Release()
+
+	batch := leveldbhelper.NewUpdateBatch()
+	updatedWSets := make(map[string]*rwset.TxPvtReadWriteSet)
+	numExpiryEntries := 0
+	for itr.Next() {
+		expKey := decodeExpiryKey(itr.Key())
+		expData, err := decodeExpiryValue(itr.Value())
+		if err != nil {
+			return err
+		}
+		for tranNum, nsColls := range expData {
+			for ns, colls := range nsColls {
+				for coll := range colls {
+					batch.Delete(encodeMissingDataKey(&missingDataKey{expKey.committingBlk, tranNum, ns, coll}))
+				}
+			}
+			dataKey := encodePK(expKey.committingBlk, tranNum)
+			pvtWSet, ok := updatedWSets[string(dataKey)]
+			if !ok {
+				encodedWSet, err := s.db.Get(dataKey)
+				if err != nil {
+					return err
+				}
+				if encodedWSet == nil {
+					continue
+				}
+				if pvtWSet, err = decodePvtRwSet(encodedWSet); err != nil {
+					return err
+				}
+			}
+			pvtWSet = removeCollections(pvtWSet, nsColls)
+			updatedWSets[string(dataKey)] = pvtWSet
+			if pvtWSet == nil {
+				batch.Delete(dataKey)
+				continue
+			}
+			encodedWSet, err := encodePvtRwSet(pvtWSet)
+			if err != nil {
+				return err
+			}
+			batch.Put(dataKey, encodedWSet)
+		}
+		batch.Delete(itr.Key())
+		numExpiryEntries++
+	}
+	if numExpiryEntries == 0 {
+		return nil
+	}
+	if err := s.db.WriteBatch(batch, true); err != nil {
+		return err
+	}
+	logger.Debugf("Purged private data of %d expiry entries expiring at or before block [%d]", numExpiryEntries, maxExpiringBlk)
+	return nil
+}
+
 func (s *store) hasPendingCommit() (bool, error) {
 	var v []byte
 	var err error
@@ -247,6 +635,67 @@ func (s *store) getLastCommittedBlockNum() (bool, uint64, error) {
 	return false, decodeBlockNum(v), nil
 }
 
+// removeCollections returns a `TxPvtReadWriteSet` that excludes the list of 'ns/collections' supplied in `toRemove`.
+// A nil is returned if no collection remains
+func removeCollections(pvtWSet *rwset.TxPvtReadWriteSet, toRemove ledger.PvtNsCollFilter) *rwset.TxPvtReadWriteSet {
+	var remainingNsRwSet []*rwset.NsPvtReadWriteSet
+	for _, ns := range pvtWSet.GetNsPvtRwset() {
+		var remainingCollRwSet []*rwset.CollectionPvtReadWriteSet
+		for _, coll := range ns.CollectionPvtRwset {
+			if !toRemove.Has(ns.Namespace, coll.CollectionName) {
+				remainingCollRwSet = append(remainingCollRwSet, coll)
+			}
+		}
+		if remainingCollRwSet != nil {
+			remainingNsRwSet = append(remainingNsRwSet,
+				&rwset.NsPvtReadWriteSet{
+					Namespace:          ns.Namespace,
+					CollectionPvtRwset: remainingCollRwSet,
+				},
+			)
+		}
+	}
+	if remainingNsRwSet == nil {
+		return nil
+	}
+	return &rwset.TxPvtReadWriteSet{
+		DataModel:  pvtWSet.GetDataModel(),
+		NsPvtRwset: remainingNsRwSet,
+	}
+}
+
+// mergePvtWSets returns a `TxPvtReadWriteSet` that contains the collections of `pvtWSet` and the collections
+// of `toAdd` that are not present in `pvtWSet`
+func mergePvtWSets(pvtWSet *rwset.TxPvtReadWriteSet, toAdd *rwset.TxPvtReadWriteSet) *rwset.TxPvtReadWriteSet {
+	merged := &rwset.TxPvtReadWriteSet{DataModel: pvtWSet.GetDataModel()}
+	nsRwSets := make(map[string]*rwset.NsPvtReadWriteSet)
+	existing := ledger.NewPvtNsCollFilter()
+	for _, ns := range pvtWSet.GetNsPvtRwset() {
+		nsRwSet := &rwset.NsPvtReadWriteSet{Namespace: ns.Namespace}
+		for _, coll := range ns.CollectionPvtRwset {
+			nsRwSet.CollectionPvtRwset = append(nsRwSet.CollectionPvtRwset, coll)
+			existing.Add(ns.Namespace, coll.CollectionName)
+		}
+		nsRwSets[ns.Namespace] = nsRwSet
+		merged.NsPvtRwset = append(merged.NsPvtRwset, nsRwSet)
+	}
+	for _, ns := range toAdd.GetNsPvtRwset() {
+		for _, coll := range ns.CollectionPvtRwset {
+			if existing.Has(ns.Namespace, coll.CollectionName) {
+				continue
+			}
+			nsRwSet, ok := nsRwSets[ns.Namespace]
+			if !ok {
+				nsRwSet = &rwset.NsPvtReadWriteSet{Namespace: ns.Namespace}
+				nsRwSets[ns.Namespace] = nsRwSet
+				merged.NsPvtRwset = append(merged.NsPvtRwset, nsRwSet)
+			}
+			nsRwSet.CollectionPvtRwset = append(nsRwSet.CollectionPvtRwset, coll)
+		}
+	}
+	return merged
+}
+
 // TrimPvtWSet returns a `TxPvtReadWriteSet` that retains only list of 'ns/collections' supplied in the filter
 // A nil filter does not filter any results and returns the original `pvtWSet` as is
 func TrimPvtWSet(pvtWSet *rwset.TxPvtReadWriteSet, filter ledger.PvtNsCollFilter) *rwset.TxPvtReadWriteSet {
@@ -12,6 +12,7 @@ import (
 
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/ledger"
+	btltestutil "github.com/hyperledger/fabric/core/ledger/pvtdatapolicy/testutil"
 	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
@@ -40,15 +41,15 @@ func TestStoreBasicCommitAndRetrieval(t *testing.T) {
 	testData := samplePvtData(t, []uint64{2, 4})
 
 	// no pvt data with block 0
-	assert.NoError(store.Prepare(0, nil))
+	assert.NoError(store.Prepare(0, nil, nil))
 	assert.NoError(store.Commit())
 
 	// pvt data with block 1 - commit
-	assert.NoError(store.Prepare(1, testData))
+	assert.NoError(store.Prepare(1, testData, nil))
 	assert.NoError(store.Commit())
 
 	// pvt data with block 2 - rollback
-	assert.NoError(store.Prepare(2, testData))
+	assert.NoError(store.Prepare(2, testData, nil))
 	assert.NoError(store.Rollback())
 
 	// pvt data retrieval for block 0 should return nil
@@ -86,14 +87,14 @@ func TestStoreState(t *testing.T) {
 	store := env.TestStore
 	testData := samplePvtData(t, []uint64{0})
 
-	_, ok := store.Prepare(1, testData).(*ErrIllegalArgs)
+	_, ok := store.Prepare(1, testData, nil).(*ErrIllegalArgs)
 	assert.True(ok)
 
-	assert.Nil(store.Prepare(0, testData))
+	assert.Nil(store.Prepare(0, testData, nil))
 	assert.NoError(store.Commit())
 
-	assert.Nil(store.Prepare(1, testData))
-	_, ok = store.Prepare(2, testData).(*ErrIllegalCall)
+	assert.Nil(store.Prepare(1, testData, nil))
+	_, ok = store.Prepare(2, testData, nil).(*ErrIllegalCall)
 	assert.True(ok)
 }
 
@@ -119,6 +120,274 @@ func TestInitLastCommittedBlock(t *testing.T) {
 	assert.True(ok)
 }
 
+func TestExpiredDataIsNotReturned(t *testing.T) {
+	env := NewTestStoreEnv(t)
+	defer env.Cleanup()
+	assert := assert.New(t)
+	store := env.TestStore
+	store.Init(btltestutil.SampleBTLPolicy(
+		map[[2]string]uint64{
+			{"ns-1", "coll-1"}: 1,
+			{"ns-1", "coll-2"}: 2,
+			{"ns-2", "coll-1"}: 1,
+			{"ns-2", "coll-2"}: 2,
+		},
+	))
+	testData := samplePvtData(t, []uint64{2, 4})
+
+	assert.NoError(store.Prepare(0, nil, nil))
+	assert.NoError(store.Commit())
+	assert.NoError(store.Prepare(1, testData, nil))
+	assert.NoError(store.Commit())
+
+	// all the pvt data of block 1 is available till block 2
+	assert.NoError(store.Prepare(2, nil, nil))
+	assert.NoError(store.Commit())
+	retrievedData, err := store.GetPvtDataByBlockNum(1, nil)
+	assert.NoError(err)
+	assert.Equal(testData, retrievedData)
+
+	// the pvt data of collections with BTL 1 expires at block 3
+	assert.NoError(store.Prepare(3, nil, nil))
+	assert.NoError(store.Commit())
+	retrievedData, err = store.GetPvtDataByBlockNum(1, nil)
+	assert.NoError(err)
+	assert.Len(retrievedData, 2)
+	for _, txPvtData := range retrievedData {
+		assert.False(txPvtData.Has("ns-1", "coll-1"))
+		assert.True(txPvtData.Has("ns-1", "coll-2"))
+		assert.False(txPvtData.Has("ns-2", "coll-1"))
+		assert.True(txPvtData.Has("ns-2", "coll-2"))
+	}
+
+	// the pvt data of all the collections expires at block 4
+	assert.NoError(store.Prepare(4, nil, nil))
+	assert.NoError(store.Commit())
+	retrievedData, err = store.GetPvtDataByBlockNum(1, nil)
+	assert.NoError(err)
+	assert.Nil(retrievedData)
+}
+
+func TestStorePurge(t *testing.T) {
+	viper.Set("ledger.pvtdataStore.purgeInterval", 2)
+	defer viper.Set("ledger.pvtdataStore.purgeInterval", 0)
+	env := NewTestStoreEnv(t)
+	defer env.Cleanup()
+	assert := assert.New(t)
+	s := env.TestStore
+	s.Init(btltestutil.SampleBTLPolicy(
+		map[[2]string]uint64{
+			{"ns-1", "coll-1"}: 1,
+			{"ns-1", "coll-2"}: 0,
+			{"ns-2", "coll-1"}: 1,
+			{"ns-2", "coll-2"}: 3,
+		},
+	))
+	testData := samplePvtData(t, []uint64{2, 4})
+
+	assert.NoError(s.Prepare(0, nil, nil))
+	assert.NoError(s.Commit())
+	assert.NoError(s.Prepare(1, testData, nil))
+	assert.NoError(s.Commit())
+	// the expiry entries of a rolled back block are removed
+	assert.NoError(s.Prepare(2, testData, nil))
+	assert.NoError(s.Rollback())
+	assert.Equal(2, countExpiryEntries(t, s))
+
+	// the pvt data of collections with BTL 1 expires at block 3 and is purged at block 4
+	for blkNum := uint64(2); blkNum <= 4; blkNum++ {
+		assert.NoError(s.Prepare(blkNum, nil, nil))
+		assert.NoError(s.Commit())
+	}
+	assert.Equal(1, countExpiryEntries(t, s))
+	assertPersistedCollections(t, s, 1, 2, map[[2]string]bool{{"ns-1", "coll-2"}: true, {"ns-2", "coll-2"}: true})
+
+	// the pvt data of collections with BTL 3 expires at block 5 and is purged at block 6.
+	// The pvt data of the collections without BTL is never purged
+	for blkNum := uint64(5); blkNum <= 6; blkNum++ {
+		assert.NoError(s.Prepare(blkNum, nil, nil))
+		assert.NoError(s.Commit())
+	}
+	assert.Equal(0, countExpiryEntries(t, s))
+	assertPersistedCollections(t, s, 1, 4, map[[2]string]bool{{"ns-1", "coll-2"}: true})
+
+	// the purged data stays purged after reopening the store
+	env.CloseAndReopen()
+	assertPersistedCollections(t, env.TestStore, 1, 2, map[[2]string]bool{{"ns-1", "coll-2"}: true})
+}
+
+func TestMissingPvtDataAndCommitOfOldBlocks(t *testing.T) {
+	env := NewTestStoreEnv(t)
+	defer env.Cleanup()
+	assert := assert.New(t)
+	s := env.TestStore
+	s.Init(btltestutil.SampleBTLPolicy(
+		map[[2]string]uint64{
+			{"ns-1", "coll-1"}: 1,
+		},
+	))
+
+	// block 1 has the pvt data of ns-2 for tran 2 and misses the rest of the pvt data
+	assert.NoError(s.Prepare(0, nil, nil))
+	assert.NoError(s.Commit())
+	blk1PvtData := samplePvtData(t, []uint64{2})
+	blk1PvtData[0].WriteSet = TrimPvtWSet(blk1PvtData[0].WriteSet, filterOf("ns-2", "coll-1", "ns-2", "coll-2"))
+	assert.NoError(s.Prepare(1, blk1PvtData, []ledger.MissingPrivateData{
+		{TxId: "tx2", SeqInBlock: 2, Namespace: "ns-1", Collection: "coll-1"},
+		{TxId: "tx2", SeqInBlock: 2, Namespace: "ns-1", Collection: "coll-2"},
+		{TxId: "tx4", SeqInBlock: 4, Namespace: "ns-2", Collection: "coll-1"},
+	}))
+	assert.NoError(s.Commit())
+	assert.NoError(s.Prepare(2, nil, []ledger.MissingPrivateData{
+		{TxId: "tx1", SeqInBlock: 1, Namespace: "ns-1", Collection: "coll-1"},
+		{TxId: "tx1", SeqInBlock: 1, Namespace: "ns-1", Collection: "coll-2"},
+	}))
+	assert.NoError(s.Commit())
+	// the missing data entries of a rolled back block are removed
+	assert.NoError(s.Prepare(3, nil, []ledger.MissingPrivateData{
+		{TxId: "tx1", SeqInBlock: 1, Namespace: "ns-1", Collection: "coll-1"},
+	}))
+	assert.NoError(s.Rollback())
+
+	missingPvtDataInfo, err := s.GetMissingPvtDataInfoForMostRecentBlocks(1)
+	assert.NoError(err)
+	assert.Equal(ledger.MissingPvtDataInfo{
+		2: {
+			{TxId: "tx1", SeqInBlock: 1, Namespace: "ns-1", Collection: "coll-2"},
+			{TxId: "tx1", SeqInBlock: 1, Namespace: "ns-1", Collection: "coll-1"},
+		},
+	}, missingPvtDataInfo)
+	missingPvtDataInfo, err = s.GetMissingPvtDataInfoForMostRecentBlocks(10)
+	assert.NoError(err)
+	assert.Len(missingPvtDataInfo, 2)
+	assert.Len(missingPvtDataInfo[1], 3)
+
+	// only the missing pvt data is committed, the pvt data of ns-2 for tran 2 is already present
+	assert.NoError(s.CommitPvtDataOfOldBlocks(map[uint64][]*ledger.TxPvtData{
+		1: samplePvtData(t, []uint64{2, 4}),
+	}))
+	assertPersistedCollections(t, s, 1, 2, map[[2]string]bool{
+		{"ns-1", "coll-1"}: true, {"ns-1", "coll-2"}: true, {"ns-2", "coll-1"}: true, {"ns-2", "coll-2"}: true,
+	})
+	assertPersistedCollections(t, s, 1, 4, map[[2]string]bool{{"ns-2", "coll-1"}: true})
+	missingPvtDataInfo, err = s.GetMissingPvtDataInfoForMostRecentBlocks(10)
+	assert.NoError(err)
+	assert.Len(missingPvtDataInfo, 1)
+	assert.Contains(missingPvtDataInfo, uint64(2))
+
+	// the pvt data of ns-1:coll-1 committed in block 2 expires at block 4, hence is not reported as missing anymore
+	// and is not committed if supplied
+	for blkNum := uint64(3); blkNum <= 4; blkNum++ {
+		assert.NoError(s.Prepare(blkNum, nil, nil))
+		assert.NoError(s.Commit())
+	}
+	missingPvtDataInfo, err = s.GetMissingPvtDataInfoForMostRecentBlocks(10)
+	assert.NoError(err)
+	assert.Equal(ledger.MissingPvtDataInfo{
+		2: {{TxId: "tx1", SeqInBlock: 1, Namespace: "ns-1", Collection: "coll-2"}},
+	}, missingPvtDataInfo)
+	assert.NoError(s.CommitPvtDataOfOldBlocks(map[uint64][]*ledger.TxPvtData{
+		2: samplePvtData(t, []uint64{1}),
+	}))
+	assertPersistedCollections(t, s, 2, 1, map[[2]string]bool{{"ns-1", "coll-2"}: true})
+	missingPvtDataInfo, err = s.GetMissingPvtDataInfoForMostRecentBlocks(10)
+	assert.NoError(err)
+	assert.Empty(missingPvtDataInfo)
+
+	// the pvt data of a block that is not committed yet is not accepted
+	_, ok := s.CommitPvtDataOfOldBlocks(map[uint64][]*ledger.TxPvtData{
+		5: samplePvtData(t, []uint64{1}),
+	}).(*ErrOutOfRange)
+	assert.True(ok)
+}
+
+func TestRollbackToBlock(t *testing.T) {
+	env := NewTestStoreEnv(t)
+	defer env.Cleanup()
+	assert := assert.New(t)
+	s := env.TestStore
+	s.Init(btltestutil.SampleBTLPolicy(
+		map[[2]string]uint64{
+			{"ns-1", "coll-1"}: 1,
+		},
+	))
+
+	assert.NoError(s.Prepare(0, nil, nil))
+	assert.NoError(s.Commit())
+	for blkNum := uint64(1); blkNum <= 4; blkNum++ {
+		assert.NoError(s.Prepare(blkNum, samplePvtData(t, []uint64{2}), []ledger.MissingPrivateData{
+			{TxId: "tx4", SeqInBlock: 4, Namespace: "ns-1", Collection: "coll-1"},
+		}))
+		assert.NoError(s.Commit())
+	}
+	assert.NoError(s.Prepare(5, samplePvtData(t, []uint64{2}), nil))
+	expiryEntriesBeforeRollback := countExpiryEntries(t, s)
+
+	_, ok := s.RollbackToBlock(5).(*ErrIllegalArgs)
+	assert.True(ok)
+	assert.NoError(s.RollbackToBlock(2))
+	testPendingBatch(false, assert, s)
+	testLastCommittedBlockHeight(3, assert, s)
+	assert.True(countExpiryEntries(t, s) < expiryEntriesBeforeRollback)
+
+	env.CloseAndReopen()
+	s = env.TestStore
+	testPendingBatch(false, assert, s)
+	testLastCommittedBlockHeight(3, assert, s)
+	var nilFilter ledger.PvtNsCollFilter
+	retrievedData, err := s.GetPvtDataByBlockNum(2, nilFilter)
+	assert.NoError(err)
+	assert.Len(retrievedData, 1)
+	_, err = s.GetPvtDataByBlockNum(3, nilFilter)
+	assert.IsType(&ErrOutOfRange{}, err)
+	encodedWSet, err := s.(*store).db.Get(encodePK(4, 2))
+	assert.NoError(err)
+	assert.Nil(encodedWSet)
+	missingPvtDataInfo, err := s.GetMissingPvtDataInfoForMostRecentBlocks(10)
+	assert.NoError(err)
+	assert.NotContains(missingPvtDataInfo, uint64(3))
+	assert.NotContains(missingPvtDataInfo, uint64(4))
+
+	// the blocks after the rollback target can be committed again
+	assert.NoError(s.Prepare(3, samplePvtData(t, []uint64{2}), nil))
+	assert.NoError(s.Commit())
+	testLastCommittedBlockHeight(4, assert, s)
+}
+
+func filterOf(nsColls ...string) ledger.PvtNsCollFilter {
+	filter := ledger.NewPvtNsCollFilter()
+	for i := 0; i < len(nsColls); i += 2 {
+		filter.Add(nsColls[i], nsColls[i+1])
+	}
+	return filter
+}
+
+func countExpiryEntries(t *testing.T, s Store) int {
+	startKey, endKey := getKeysForRangeScanOfAllExpiryEntries()
+	itr := s.(*store).db.GetIterator(startKey, endKey)
+	defer itr.Release()
+	count := 0
+	for itr.Next() {
+		count++
+	}
+	return count
+}
+
+// assertPersistedCollections asserts the collections persisted for a tran irrespective of their expiry
+func assertPersistedCollections(t *testing.T, s Store, blkNum, tranNum uint64, expected map[[2]string]bool) {
+	encodedWSet, err := s.(*store).db.Get(encodePK(blkNum, tranNum))
+	assert.NoError(t, err)
+	pvtWSet, err := decodePvtRwSet(encodedWSet)
+	assert.NoError(t, err)
+	persisted := make(map[[2]string]bool)
+	for _, ns := range pvtWSet.NsPvtRwset {
+		for _, coll := range ns.CollectionPvtRwset {
+			persisted[[2]string{ns.Namespace, coll.CollectionName}] = true
+		}
+	}
+	assert.Equal(t, expected, persisted)
+}
+
 // TODO Add tests for simulating a crash between calls `Prepare` and `Commit`/`Rollback`
 
 func testEmpty(expectedEmpty bool, assert *assert.Assertions, store Store) {
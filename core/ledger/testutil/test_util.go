@@ -94,6 +94,12 @@ func ResetConfigToDefaultValues() {
 	viper.Set("ledger.history.enableHistoryDatabase", false)
 	viper.Set("ledger.state.couchDBConfig.autoWarmIndexes", true)
 	viper.Set("ledger.state.couchDBConfig.warmIndexesAfterNBlocks", 1)
+	viper.Set("ledger.state.couchDBConfig.cacheSize", 10000)
+	viper.Set("ledger.blockchain.maxBlockfileSize", 64*1024*1024)
+	viper.Set("ledger.blockchain.pruning.policy", "")
+	viper.Set("ledger.blockchain.pruning.keepLastNBlocks", 100000)
+	viper.Set("ledger.blockchain.pruning.archiveDir", "")
+	viper.Set("ledger.blockchain.pruning.intervalBlocks", 1000)
 	viper.Set("peer.fileSystemPath", "/var/hyperledger/production")
 }
 
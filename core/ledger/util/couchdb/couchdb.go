@@ -19,6 +19,7 @@ package couchdb
 import (
 	"bytes"
 	"compress/gzip"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
@@ -101,8 +102,9 @@ type RangeQueryResponse struct {
 
 //QueryResponse is used for processing REST query responses from CouchDB
 type QueryResponse struct {
-	Warning string            `json:"warning"`
-	Docs    []json.RawMessage `json:"docs"`
+	Warning  string            `json:"warning"`
+	Docs     []json.RawMessage `json:"docs"`
+	Bookmark string            `json:"bookmark"`
 }
 
 // DocMetadata is used for capturing CouchDB document header info,
@@ -419,6 +421,67 @@ func (couchInstance *CouchInstance) VerifyCouchConfig() (*ConnectionInfo, *DBRet
 	return dbResponse, couchDBReturn, nil
 }
 
+// HealthCheck checks if the peer is able to communicate with CouchDB
+func (couchInstance *CouchInstance) HealthCheck(ctx context.Context) error {
+	connectURL, err := url.Parse(couchInstance.conf.URL)
+	if err != nil {
+		logger.Errorf("URL parse error: %s", err.Error())
+		return err
+	}
+	connectURL.Path = "/"
+
+	req, err := http.NewRequest(http.MethodGet, connectURL.String(), nil)
+	if err != nil {
+		return err
+	}
+	if couchInstance.conf.Username != "" && couchInstance.conf.Password != "" {
+		req.SetBasicAuth(couchInstance.conf.Username, couchInstance.conf.Password)
+	}
+
+	resp, err := couchInstance.client.Do(req.WithContext(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to connect to couch db [%s]", err)
+	}
+	defer closeResponseBody(resp)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("couch db returned status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// RetrieveApplicationDBNames returns the names of all the databases in the CouchDB instance
+// except the system databases, i.e., the databases whose names begin with an underscore
+func (couchInstance *CouchInstance) RetrieveApplicationDBNames() ([]string, error) {
+	connectURL, err := url.Parse(couchInstance.conf.URL)
+	if err != nil {
+		logger.Errorf("URL parse error: %s", err.Error())
+		return nil, err
+	}
+	connectURL.Path = "/_all_dbs"
+
+	//get the number of retries
+	maxRetries := couchInstance.conf.MaxRetries
+
+	resp, _, err := couchInstance.handleRequest(http.MethodGet, connectURL.String(), nil, "", "", maxRetries, true)
+	if err != nil {
+		return nil, err
+	}
+	defer closeResponseBody(resp)
+
+	var dbNames []string
+	if err = json.NewDecoder(resp.Body).Decode(&dbNames); err != nil {
+		return nil, err
+	}
+	var applicationDBNames []string
+	for _, dbName := range dbNames {
+		if !strings.HasPrefix(dbName, "_") {
+			applicationDBNames = append(applicationDBNames, dbName)
+		}
+	}
+	return applicationDBNames, nil
+}
+
 //DropDatabase provides method to drop an existing database
 func (dbclient *CouchDatabase) DropDatabase() (*DBOperationResponse, error) {
 
@@ -857,7 +920,9 @@ func (dbclient *CouchDatabase) ReadDoc(id string) (*CouchDoc, string, error) {
 //startKey and endKey can also be empty strings.  If startKey and endKey are empty, all documents are returned
 //This function provides a limit option to specify the max number of entries and is supplied by config.
 //Skip is reserved for possible future future use.
-func (dbclient *CouchDatabase) ReadDocRange(startKey, endKey string, limit, skip int) (*[]QueryResult, error) {
+//Along with the documents, the key of the document that follows the last returned document is returned,
+//which is the start key for reading the next set of documents. It is empty if there are no more documents
+func (dbclient *CouchDatabase) ReadDocRange(startKey, endKey string, limit, skip int) (*[]QueryResult, string, error) {
 
 	logger.Debugf("Entering ReadDocRange()  startKey=%s, endKey=%s", startKey, endKey)
 
@@ -866,12 +931,13 @@ func (dbclient *CouchDatabase) ReadDocRange(startKey, endKey string, limit, skip
 	rangeURL, err := url.Parse(dbclient.CouchInstance.conf.URL)
 	if err != nil {
 		logger.Errorf("URL parse error: %s", err.Error())
-		return nil, err
+		return nil, "", err
 	}
 	rangeURL.Path = dbclient.DBName + "/_all_docs"
 
 	queryParms := rangeURL.Query()
-	queryParms.Set("limit", strconv.Itoa(limit))
+	// read one extra document for finding the start key of the next set of documents
+	queryParms.Set("limit", strconv.Itoa(limit+1))
 	queryParms.Add("skip", strconv.Itoa(skip))
 	queryParms.Add("include_docs", "true")
 	queryParms.Add("inclusive_end", "false") // endkey should be exclusive to be consistent with goleveldb
@@ -880,7 +946,7 @@ func (dbclient *CouchDatabase) ReadDocRange(startKey, endKey string, limit, skip
 
 	if startKey != "" {
 		if startKey, err = encodeForJSON(startKey); err != nil {
-			return nil, err
+			return nil, "", err
 		}
 		queryParms.Add("startkey", "\""+startKey+"\"")
 	}
@@ -889,7 +955,7 @@ func (dbclient *CouchDatabase) ReadDocRange(startKey, endKey string, limit, skip
 	if endKey != "" {
 		var err error
 		if endKey, err = encodeForJSON(endKey); err != nil {
-			return nil, err
+			return nil, "", err
 		}
 		queryParms.Add("endkey", "\""+endKey+"\"")
 	}
@@ -901,7 +967,7 @@ func (dbclient *CouchDatabase) ReadDocRange(startKey, endKey string, limit, skip
 
 	resp, _, err := dbclient.CouchInstance.handleRequest(http.MethodGet, rangeURL.String(), nil, "", "", maxRetries, true)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer closeResponseBody(resp)
 
@@ -916,23 +982,29 @@ func (dbclient *CouchDatabase) ReadDocRange(startKey, endKey string, limit, skip
 	//handle as JSON document
 	jsonResponseRaw, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	var jsonResponse = &RangeQueryResponse{}
 	err2 := json.Unmarshal(jsonResponseRaw, &jsonResponse)
 	if err2 != nil {
-		return nil, err2
+		return nil, "", err2
 	}
 
 	logger.Debugf("Total Rows: %d", jsonResponse.TotalRows)
 
+	nextStartKey := ""
+	if len(jsonResponse.Rows) > limit {
+		nextStartKey = jsonResponse.Rows[limit].ID
+		jsonResponse.Rows = jsonResponse.Rows[:limit]
+	}
+
 	for _, row := range jsonResponse.Rows {
 
 		var docMetadata = &DocMetadata{}
 		err3 := json.Unmarshal(row.Doc, &docMetadata)
 		if err3 != nil {
-			return nil, err3
+			return nil, "", err3
 		}
 
 		if docMetadata.AttachmentsInfo != nil {
@@ -941,7 +1013,7 @@ func (dbclient *CouchDatabase) ReadDocRange(startKey, endKey string, limit, skip
 
 			couchDoc, _, err := dbclient.ReadDoc(docMetadata.ID)
 			if err != nil {
-				return nil, err
+				return nil, "", err
 			}
 
 			var addDocument = &QueryResult{docMetadata.ID, couchDoc.JSONValue, couchDoc.Attachments}
@@ -960,7 +1032,7 @@ func (dbclient *CouchDatabase) ReadDocRange(startKey, endKey string, limit, skip
 
 	logger.Debugf("Exiting ReadDocRange()")
 
-	return &results, nil
+	return &results, nextStartKey, nil
 
 }
 
@@ -1004,7 +1076,9 @@ func (dbclient *CouchDatabase) DeleteDoc(id, rev string) error {
 }
 
 //QueryDocuments method provides function for processing a query
-func (dbclient *CouchDatabase) QueryDocuments(query string) (*[]QueryResult, error) {
+//Along with the documents, the bookmark returned by CouchDB is returned, which can be
+//added to the query for fetching the next set of documents
+func (dbclient *CouchDatabase) QueryDocuments(query string) (*[]QueryResult, string, error) {
 
 	logger.Debugf("Entering QueryDocuments()  query=%s", query)
 
@@ -1013,7 +1087,7 @@ func (dbclient *CouchDatabase) QueryDocuments(query string) (*[]QueryResult, err
 	queryURL, err := url.Parse(dbclient.CouchInstance.conf.URL)
 	if err != nil {
 		logger.Errorf("URL parse error: %s", err.Error())
-		return nil, err
+		return nil, "", err
 	}
 
 	queryURL.Path = dbclient.DBName + "/_find"
@@ -1023,7 +1097,7 @@ func (dbclient *CouchDatabase) QueryDocuments(query string) (*[]QueryResult, err
 
 	resp, _, err := dbclient.CouchInstance.handleRequest(http.MethodPost, queryURL.String(), []byte(query), "", "", maxRetries, true)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	defer closeResponseBody(resp)
 
@@ -1038,14 +1112,14 @@ func (dbclient *CouchDatabase) QueryDocuments(query string) (*[]QueryResult, err
 	//handle as JSON document
 	jsonResponseRaw, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	var jsonResponse = &QueryResponse{}
 
 	err2 := json.Unmarshal(jsonResponseRaw, &jsonResponse)
 	if err2 != nil {
-		return nil, err2
+		return nil, "", err2
 	}
 
 	if jsonResponse.Warning != "" {
@@ -1057,7 +1131,7 @@ func (dbclient *CouchDatabase) QueryDocuments(query string) (*[]QueryResult, err
 		var docMetadata = &DocMetadata{}
 		err3 := json.Unmarshal(row, &docMetadata)
 		if err3 != nil {
-			return nil, err3
+			return nil, "", err3
 		}
 
 		if docMetadata.AttachmentsInfo != nil {
@@ -1066,7 +1140,7 @@ func (dbclient *CouchDatabase) QueryDocuments(query string) (*[]QueryResult, err
 
 			couchDoc, _, err := dbclient.ReadDoc(docMetadata.ID)
 			if err != nil {
-				return nil, err
+				return nil, "", err
 			}
 			var addDocument = &QueryResult{ID: docMetadata.ID, Value: couchDoc.JSONValue, Attachments: couchDoc.Attachments}
 			results = append(results, *addDocument)
@@ -1081,7 +1155,7 @@ func (dbclient *CouchDatabase) QueryDocuments(query string) (*[]QueryResult, err
 	}
 	logger.Debugf("Exiting QueryDocuments()")
 
-	return &results, nil
+	return &results, jsonResponse.Bookmark, nil
 
 }
 
@@ -17,10 +17,13 @@ limitations under the License.
 package couchdb
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/http/httptest"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -136,6 +139,90 @@ func TestEncodePathElement(t *testing.T) {
 
 }
 
+func TestHealthCheck(t *testing.T) {
+	statusCode := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testutil.AssertEquals(t, r.URL.Path, "/")
+		w.WriteHeader(statusCode)
+	}))
+	defer server.Close()
+
+	couchInstance := &CouchInstance{conf: CouchConnectionDef{URL: server.URL}, client: &http.Client{}}
+	err := couchInstance.HealthCheck(context.Background())
+	testutil.AssertNoError(t, err, "Health check should have succeeded")
+
+	statusCode = http.StatusInternalServerError
+	err = couchInstance.HealthCheck(context.Background())
+	testutil.AssertEquals(t, err.Error(), "couch db returned status code 500")
+
+	server.Close()
+	err = couchInstance.HealthCheck(context.Background())
+	testutil.AssertError(t, err, "Health check should have failed with a closed server")
+
+	badCouchInstance := &CouchInstance{conf: CouchConnectionDef{URL: badParseConnectURL}, client: &http.Client{}}
+	err = badCouchInstance.HealthCheck(context.Background())
+	testutil.AssertError(t, err, "Health check should have failed with a bad URL")
+}
+
+func TestRetrieveApplicationDBNames(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		testutil.AssertEquals(t, r.URL.Path, "/_all_dbs")
+		w.Write([]byte(`["_global_changes","_replicator","_users","mychannel_","mychannel_mycc"]`))
+	}))
+	defer server.Close()
+
+	couchInstance := &CouchInstance{conf: CouchConnectionDef{URL: server.URL, MaxRetries: 1}, client: &http.Client{}}
+	dbNames, err := couchInstance.RetrieveApplicationDBNames()
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, dbNames, []string{"mychannel_", "mychannel_mycc"})
+
+	badCouchInstance := &CouchInstance{conf: CouchConnectionDef{URL: badParseConnectURL}, client: &http.Client{}}
+	_, err = badCouchInstance.RetrieveApplicationDBNames()
+	testutil.AssertError(t, err, "Retrieving database names should have failed with a bad URL")
+}
+
+func TestReadDocRangeAndQueryDocumentsPaging(t *testing.T) {
+	keys := []string{"key1", "key2", "key3", "key4", "key5"}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/testdb/_all_docs":
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			startKey := strings.Trim(r.URL.Query().Get("startkey"), "\"")
+			var rows []string
+			for _, key := range keys {
+				if key >= startKey && len(rows) < limit {
+					rows = append(rows, fmt.Sprintf(`{"id":"%s","key":"%s","doc":{"_id":"%s"}}`, key, key, key))
+				}
+			}
+			w.Write([]byte(`{"total_rows":5,"offset":0,"rows":[` + strings.Join(rows, ",") + `]}`))
+		case "/testdb/_find":
+			w.Write([]byte(`{"docs":[{"_id":"key1"},{"_id":"key2"}],"bookmark":"bookmark2"}`))
+		default:
+			t.Fatalf("Unexpected request [%s]", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	couchInstance := CouchInstance{conf: CouchConnectionDef{URL: server.URL, MaxRetries: 1}, client: &http.Client{}}
+	db := &CouchDatabase{CouchInstance: couchInstance, DBName: "testdb"}
+
+	results, nextStartKey, err := db.ReadDocRange("key1", "", 2, 0)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, len(*results), 2)
+	testutil.AssertEquals(t, nextStartKey, "key3")
+
+	results, nextStartKey, err = db.ReadDocRange("key4", "", 2, 0)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, len(*results), 2)
+	testutil.AssertEquals(t, (*results)[1].ID, "key5")
+	testutil.AssertEquals(t, nextStartKey, "")
+
+	results, bookmark, err := db.QueryDocuments(`{"selector":{},"limit":2}`)
+	testutil.AssertNoError(t, err, "")
+	testutil.AssertEquals(t, len(*results), 2)
+	testutil.AssertEquals(t, bookmark, "bookmark2")
+}
+
 func TestBadCouchDBInstance(t *testing.T) {
 
 	//TODO continue changes to return and removal of sprintf in followon changes
@@ -196,11 +283,11 @@ func TestBadCouchDBInstance(t *testing.T) {
 	testutil.AssertError(t, err, "Error should have been thrown with DeleteDoc and invalid connection")
 
 	//Test ReadDocRange with bad connection
-	_, err = badDB.ReadDocRange("1", "2", 1000, 0)
+	_, _, err = badDB.ReadDocRange("1", "2", 1000, 0)
 	testutil.AssertError(t, err, "Error should have been thrown with ReadDocRange and invalid connection")
 
 	//Test QueryDocuments with bad connection
-	_, err = badDB.QueryDocuments("1")
+	_, _, err = badDB.QueryDocuments("1")
 	testutil.AssertError(t, err, "Error should have been thrown with QueryDocuments and invalid connection")
 
 	//Test BatchRetrieveDocumentMetadata with bad connection
@@ -769,7 +856,7 @@ func TestPrefixScan(t *testing.T) {
 		_, _, geterr := db.ReadDoc(endKey)
 		testutil.AssertNoError(t, geterr, fmt.Sprintf("Error when trying to get lastkey"))
 
-		resultsPtr, geterr := db.ReadDocRange(startKey, endKey, 1000, 0)
+		resultsPtr, _, geterr := db.ReadDocRange(startKey, endKey, 1000, 0)
 		testutil.AssertNoError(t, geterr, fmt.Sprintf("Error when trying to perform a range scan"))
 		testutil.AssertNotNil(t, resultsPtr)
 		results := *resultsPtr
@@ -1030,7 +1117,7 @@ func TestIndexOperations(t *testing.T) {
 	queryString := "{\"selector\":{\"size\": {\"$gt\": 0}},\"fields\": [\"_id\", \"_rev\", \"owner\", \"asset_name\", \"color\", \"size\"], \"sort\":[{\"size\":\"desc\"}], \"limit\": 10,\"skip\": 0}"
 
 	//Execute a query with a sort, this should throw the exception
-	_, err = db.QueryDocuments(queryString)
+	_, _, err = db.QueryDocuments(queryString)
 	testutil.AssertError(t, err, fmt.Sprintf("Error thrown while querying without a valid index"))
 
 	//Create the index
@@ -1041,7 +1128,7 @@ func TestIndexOperations(t *testing.T) {
 	time.Sleep(100 * time.Millisecond)
 
 	//Execute a query with an index,  this should succeed
-	_, err = db.QueryDocuments(queryString)
+	_, _, err = db.QueryDocuments(queryString)
 	testutil.AssertNoError(t, err, fmt.Sprintf("Error thrown while querying with an index"))
 
 	//Create another index definition
@@ -1257,13 +1344,13 @@ func TestRichQuery(t *testing.T) {
 			//Test query with invalid JSON -------------------------------------------------------------------
 			queryString := "{\"selector\":{\"owner\":}}"
 
-			_, err = db.QueryDocuments(queryString)
+			_, _, err = db.QueryDocuments(queryString)
 			testutil.AssertError(t, err, fmt.Sprintf("Error should have been thrown for bad json"))
 
 			//Test query with object  -------------------------------------------------------------------
 			queryString = "{\"selector\":{\"owner\":{\"$eq\":\"jerry\"}}}"
 
-			queryResult, err := db.QueryDocuments(queryString)
+			queryResult, _, err := db.QueryDocuments(queryString)
 			testutil.AssertNoError(t, err, fmt.Sprintf("Error when attempting to execute a query"))
 
 			//There should be 3 results for owner="jerry"
@@ -1272,7 +1359,7 @@ func TestRichQuery(t *testing.T) {
 			//Test query with implicit operator   --------------------------------------------------------------
 			queryString = "{\"selector\":{\"owner\":\"jerry\"}}"
 
-			queryResult, err = db.QueryDocuments(queryString)
+			queryResult, _, err = db.QueryDocuments(queryString)
 			testutil.AssertNoError(t, err, fmt.Sprintf("Error when attempting to execute a query"))
 
 			//There should be 3 results for owner="jerry"
@@ -1281,7 +1368,7 @@ func TestRichQuery(t *testing.T) {
 			//Test query with specified fields   -------------------------------------------------------------------
 			queryString = "{\"selector\":{\"owner\":{\"$eq\":\"jerry\"}},\"fields\": [\"owner\",\"asset_name\",\"color\",\"size\"]}"
 
-			queryResult, err = db.QueryDocuments(queryString)
+			queryResult, _, err = db.QueryDocuments(queryString)
 			testutil.AssertNoError(t, err, fmt.Sprintf("Error when attempting to execute a query"))
 
 			//There should be 3 results for owner="jerry"
@@ -1290,7 +1377,7 @@ func TestRichQuery(t *testing.T) {
 			//Test query with a leading operator   -------------------------------------------------------------------
 			queryString = "{\"selector\":{\"$or\":[{\"owner\":{\"$eq\":\"jerry\"}},{\"owner\": {\"$eq\": \"frank\"}}]}}"
 
-			queryResult, err = db.QueryDocuments(queryString)
+			queryResult, _, err = db.QueryDocuments(queryString)
 			testutil.AssertNoError(t, err, fmt.Sprintf("Error when attempting to execute a query"))
 
 			//There should be 4 results for owner="jerry" or owner="frank"
@@ -1299,7 +1386,7 @@ func TestRichQuery(t *testing.T) {
 			//Test query implicit and explicit operator   ------------------------------------------------------------------
 			queryString = "{\"selector\":{\"color\":\"green\",\"$or\":[{\"owner\":\"tom\"},{\"owner\":\"frank\"}]}}"
 
-			queryResult, err = db.QueryDocuments(queryString)
+			queryResult, _, err = db.QueryDocuments(queryString)
 			testutil.AssertNoError(t, err, fmt.Sprintf("Error when attempting to execute a query"))
 
 			//There should be 2 results for color="green" and (owner="jerry" or owner="frank")
@@ -1308,7 +1395,7 @@ func TestRichQuery(t *testing.T) {
 			//Test query with a leading operator  -------------------------------------------------------------------------
 			queryString = "{\"selector\":{\"$and\":[{\"size\":{\"$gte\":2}},{\"size\":{\"$lte\":5}}]}}"
 
-			queryResult, err = db.QueryDocuments(queryString)
+			queryResult, _, err = db.QueryDocuments(queryString)
 			testutil.AssertNoError(t, err, fmt.Sprintf("Error when attempting to execute a query"))
 
 			//There should be 4 results for size >= 2 and size <= 5
@@ -1317,7 +1404,7 @@ func TestRichQuery(t *testing.T) {
 			//Test query with leading and embedded operator  -------------------------------------------------------------
 			queryString = "{\"selector\":{\"$and\":[{\"size\":{\"$gte\":3}},{\"size\":{\"$lte\":10}},{\"$not\":{\"size\":7}}]}}"
 
-			queryResult, err = db.QueryDocuments(queryString)
+			queryResult, _, err = db.QueryDocuments(queryString)
 			testutil.AssertNoError(t, err, fmt.Sprintf("Error when attempting to execute a query"))
 
 			//There should be 7 results for size >= 3 and size <= 10 and not 7
@@ -1326,23 +1413,38 @@ func TestRichQuery(t *testing.T) {
 			//Test query with leading operator and array of objects ----------------------------------------------------------
 			queryString = "{\"selector\":{\"$and\":[{\"size\":{\"$gte\":2}},{\"size\":{\"$lte\":10}},{\"$nor\":[{\"size\":3},{\"size\":5},{\"size\":7}]}]}}"
 
-			queryResult, err = db.QueryDocuments(queryString)
+			queryResult, _, err = db.QueryDocuments(queryString)
 			testutil.AssertNoError(t, err, fmt.Sprintf("Error when attempting to execute a query"))
 
 			//There should be 6 results for size >= 2 and size <= 10 and not 3,5 or 7
 			testutil.AssertEquals(t, len(*queryResult), 6)
 
 			//Test a range query ---------------------------------------------------------------------------------------------
-			queryResult, err = db.ReadDocRange("marble02", "marble06", 10000, 0)
+			queryResult, nextStartKey, err := db.ReadDocRange("marble02", "marble06", 10000, 0)
 			testutil.AssertNoError(t, err, fmt.Sprintf("Error when attempting to execute a range query"))
 
 			//There should be 4 results
 			testutil.AssertEquals(t, len(*queryResult), 4)
+			testutil.AssertEquals(t, nextStartKey, "")
+
+			//Test a range query with a limit ---------------------------------------------------------------------------------
+			queryResult, nextStartKey, err = db.ReadDocRange("marble02", "marble06", 2, 0)
+			testutil.AssertNoError(t, err, fmt.Sprintf("Error when attempting to execute a range query"))
+
+			//There should be 2 results and the next start key should be the key of the third document
+			testutil.AssertEquals(t, len(*queryResult), 2)
+			testutil.AssertEquals(t, nextStartKey, "marble04")
+
+			queryResult, nextStartKey, err = db.ReadDocRange(nextStartKey, "marble06", 2, 0)
+			testutil.AssertNoError(t, err, fmt.Sprintf("Error when attempting to execute a range query"))
+			testutil.AssertEquals(t, len(*queryResult), 2)
+			testutil.AssertEquals(t, (*queryResult)[0].ID, "marble04")
+			testutil.AssertEquals(t, nextStartKey, "")
 
 			//Test query with for tom  -------------------------------------------------------------------
 			queryString = "{\"selector\":{\"owner\":{\"$eq\":\"tom\"}}}"
 
-			queryResult, err = db.QueryDocuments(queryString)
+			queryResult, _, err = db.QueryDocuments(queryString)
 			testutil.AssertNoError(t, err, fmt.Sprintf("Error when attempting to execute a query"))
 
 			//There should be 8 results for owner="tom"
@@ -1351,12 +1453,24 @@ func TestRichQuery(t *testing.T) {
 			//Test query with for tom with limit  -------------------------------------------------------------------
 			queryString = "{\"selector\":{\"owner\":{\"$eq\":\"tom\"}},\"limit\":2}"
 
-			queryResult, err = db.QueryDocuments(queryString)
+			queryResult, _, err = db.QueryDocuments(queryString)
 			testutil.AssertNoError(t, err, fmt.Sprintf("Error when attempting to execute a query"))
 
 			//There should be 2 results for owner="tom" with a limit of 2
 			testutil.AssertEquals(t, len(*queryResult), 2)
 
+			//Test query with for tom with limit and bookmark  -------------------------------------------------------------
+			queryResult, bookmark, err := db.QueryDocuments(queryString)
+			testutil.AssertNoError(t, err, fmt.Sprintf("Error when attempting to execute a query"))
+			testutil.AssertNotEquals(t, bookmark, "")
+
+			queryString = "{\"selector\":{\"owner\":{\"$eq\":\"tom\"}},\"limit\":10,\"bookmark\":\"" + bookmark + "\"}"
+			queryResult, _, err = db.QueryDocuments(queryString)
+			testutil.AssertNoError(t, err, fmt.Sprintf("Error when attempting to execute a query"))
+
+			//There should be the remaining 6 results for owner="tom"
+			testutil.AssertEquals(t, len(*queryResult), 6)
+
 		}
 	}
 }
@@ -66,10 +66,18 @@ func (m *MockTxSim) GetStateRangeScanIterator(namespace string, startKey string,
 	return nil, nil
 }
 
+func (m *MockTxSim) GetStateRangeScanIteratorWithMetadata(namespace, startKey, endKey string, metadata map[string]interface{}) (commonledger.QueryResultsIterator, error) {
+	return nil, nil
+}
+
 func (m *MockTxSim) ExecuteQuery(namespace, query string) (commonledger.ResultsIterator, error) {
 	return nil, nil
 }
 
+func (m *MockTxSim) ExecuteQueryWithMetadata(namespace, query string, metadata map[string]interface{}) (commonledger.QueryResultsIterator, error) {
+	return nil, nil
+}
+
 func (m *MockTxSim) Done() {
 }
 
@@ -97,6 +105,34 @@ func (m *MockTxSim) DeletePrivateData(namespace, collection, key string) error {
 	return nil
 }
 
+func (m *MockTxSim) SetStateMetadata(namespace, key string, metadata map[string][]byte) error {
+	return nil
+}
+
+func (m *MockTxSim) DeleteStateMetadata(namespace, key string) error {
+	return nil
+}
+
+func (m *MockTxSim) SetPrivateDataMetadata(namespace, collection, key string, metadata map[string][]byte) error {
+	return nil
+}
+
+func (m *MockTxSim) DeletePrivateDataMetadata(namespace, collection, key string) error {
+	return nil
+}
+
+func (m *MockTxSim) GetStateMetadata(namespace, key string) (map[string][]byte, error) {
+	return nil, nil
+}
+
+func (m *MockTxSim) GetPrivateDataMetadata(namespace, collection, key string) (map[string][]byte, error) {
+	return nil, nil
+}
+
+func (m *MockTxSim) GetPrivateDataMetadataByHash(namespace, collection string, keyhash []byte) (map[string][]byte, error) {
+	return nil, nil
+}
+
 func (m *MockTxSim) ExecuteQueryOnPrivateData(namespace, collection, query string) (commonledger.ResultsIterator, error) {
 	return nil, nil
 }
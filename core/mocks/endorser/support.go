@@ -9,6 +9,7 @@ package endorser
 import (
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/resourcesconfig"
+	"github.com/hyperledger/fabric/core/endorser"
 	"github.com/hyperledger/fabric/core/ledger"
 	mc "github.com/hyperledger/fabric/core/mocks/ccprovider"
 	"github.com/hyperledger/fabric/protos/common"
@@ -39,6 +40,7 @@ type MockSupport struct {
 	IsJavaErr                        error
 	GetApplicationConfigRv           channelconfig.Application
 	GetApplicationConfigBoolRv       bool
+	EndorseWithPluginErr             error
 }
 
 func (s *MockSupport) IsSysCCAndNotInvokableExternal(name string) bool {
@@ -103,3 +105,10 @@ func (s *MockSupport) CheckInstantiationPolicy(name, version string, cd resource
 func (s *MockSupport) GetApplicationConfig(cid string) (channelconfig.Application, bool) {
 	return s.GetApplicationConfigRv, s.GetApplicationConfigBoolRv
 }
+
+func (s *MockSupport) EndorseWithPlugin(ctx endorser.Context) (*pb.ProposalResponse, error) {
+	if s.EndorseWithPluginErr != nil {
+		return nil, s.EndorseWithPluginErr
+	}
+	return &pb.ProposalResponse{Response: &pb.Response{}}, nil
+}
@@ -31,6 +31,7 @@ type Support struct {
 	MSPManagerVal msp.MSPManager
 	ApplyVal      error
 	ACVal         channelconfig.ApplicationCapabilities
+	PolicyMgrVal  policies.Manager
 }
 
 func (ms *Support) ChaincodeByName(chainname, ccname string) (resourcesconfig.ChaincodeDefinition, bool) {
@@ -56,7 +57,11 @@ func (ms *Support) Apply(configtx *common.ConfigEnvelope) error {
 	return ms.ApplyVal
 }
 
+// PolicyManager returns PolicyMgrVal, or an empty policy manager if unset
 func (ms *Support) PolicyManager() policies.Manager {
+	if ms.PolicyMgrVal != nil {
+		return ms.PolicyMgrVal
+	}
 	return &mockpolicies.Manager{}
 }
 
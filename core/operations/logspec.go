@@ -0,0 +1,59 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operations
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/hyperledger/fabric/common/flogging"
+)
+
+// LogSpec is the JSON body used to get and set the logging specification
+type LogSpec struct {
+	Spec string `json:"spec,omitempty"`
+}
+
+// ErrorResponse is the JSON body returned when a request fails
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// LogSpecHandler gets and sets the flogging specification of the process
+type LogSpecHandler struct{}
+
+// ServeHTTP returns the active logging specification on GET
+// and applies the specification in the request body on PUT
+func (h *LogSpecHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	switch req.Method {
+	case http.MethodGet:
+		sendResponse(rw, http.StatusOK, &LogSpec{Spec: flogging.Spec()})
+	case http.MethodPut:
+		var logSpec LogSpec
+		if err := json.NewDecoder(req.Body).Decode(&logSpec); err != nil {
+			sendResponse(rw, http.StatusBadRequest, &ErrorResponse{Error: err.Error()})
+			return
+		}
+		if logSpec.Spec == "" {
+			sendResponse(rw, http.StatusBadRequest, &ErrorResponse{Error: "spec is missing"})
+			return
+		}
+		logger.Infof("Setting logging spec to %s", logSpec.Spec)
+		flogging.InitFromSpec(logSpec.Spec)
+		rw.WriteHeader(http.StatusNoContent)
+	default:
+		sendResponse(rw, http.StatusBadRequest, &ErrorResponse{Error: "invalid request method"})
+	}
+}
+
+func sendResponse(rw http.ResponseWriter, code int, payload interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(code)
+	if err := json.NewEncoder(rw).Encode(payload); err != nil {
+		logger.Errorf("Failed encoding response: %s", err)
+	}
+}
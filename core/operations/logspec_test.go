@@ -0,0 +1,52 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operations
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLogSpecHandler(t *testing.T) {
+	defer flogging.Reset()
+	handler := &LogSpecHandler{}
+
+	serve := func(method, body string) *httptest.ResponseRecorder {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest(method, "/logspec", strings.NewReader(body)))
+		return resp
+	}
+
+	flogging.InitFromSpec("info:gossip=warning")
+	resp := serve(http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"spec":"info:gossip=warning"}`, resp.Body.String())
+
+	resp = serve(http.MethodPut, `{"spec":"debug:endorser=error"}`)
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Equal(t, "debug:endorser=error", flogging.Spec())
+	assert.Equal(t, "ERROR", flogging.GetModuleLevel("endorser"))
+
+	resp = serve(http.MethodPut, `{"spec":`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.Contains(t, resp.Body.String(), "unexpected EOF")
+
+	resp = serve(http.MethodPut, `{}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error":"spec is missing"}`, resp.Body.String())
+
+	resp = serve(http.MethodPost, `{"spec":"info"}`)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	assert.JSONEq(t, `{"error":"invalid request method"}`, resp.Body.String())
+	assert.Equal(t, "debug:endorser=error", flogging.Spec())
+}
@@ -0,0 +1,157 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operations
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/healthz"
+	"github.com/hyperledger/fabric/common/metrics"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("operations")
+
+const shutdownTimeout = 5 * time.Second
+
+// TLS contains the TLS configuration of the operations endpoint
+type TLS struct {
+	Enabled            bool
+	CertFile           string
+	KeyFile            string
+	ClientCertRequired bool
+	ClientRootCAs      []string
+}
+
+// Options contains the configuration of the operations System
+type Options struct {
+	ListenAddress string
+	TLS           TLS
+}
+
+// System is the HTTP server exposing the operations endpoints of a
+// peer or an orderer:
+//
+//	/healthz  runs the registered health checks
+//	/logspec  gets and sets the logging specification
+//	/metrics  serves the metrics when they are reported to Prometheus
+//
+// along with the endpoints registered with RegisterHandler.
+type System struct {
+	*healthz.HealthHandler
+	options  Options
+	mux      *http.ServeMux
+	listener net.Listener
+	server   *http.Server
+}
+
+// NewSystem creates a new operations System with the given options
+func NewSystem(o Options) *System {
+	s := &System{
+		HealthHandler: healthz.NewHealthHandler(),
+		options:       o,
+		mux:           http.NewServeMux(),
+	}
+	s.mux.Handle("/healthz", s.HealthHandler)
+	s.mux.Handle("/logspec", &LogSpecHandler{})
+	return s
+}
+
+// RegisterHandler serves the given handler for the given pattern, as
+// interpreted by http.ServeMux. It must be called before Start.
+func (s *System) RegisterHandler(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Start starts serving the operations endpoints. The metrics endpoint is
+// enabled only if metrics have been initialized with the Prometheus reporter
+// before Start is called.
+func (s *System) Start() error {
+	if handler := metrics.PrometheusHandler(); handler != nil {
+		s.mux.Handle("/metrics", handler)
+	}
+
+	tlsConfig, err := s.tlsConfig()
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", s.options.ListenAddress)
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen on %s", s.options.ListenAddress)
+	}
+	if tlsConfig != nil {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+
+	s.listener = listener
+	s.server = &http.Server{Handler: s.mux}
+	go func() {
+		if err := s.server.Serve(listener); err != nil && err != http.ErrServerClosed {
+			logger.Errorf("Operations server failed: %s", err)
+		}
+	}()
+
+	logger.Infof("Operations server listening on %s", listener.Addr())
+	return nil
+}
+
+// Stop stops serving the operations endpoints
+func (s *System) Stop() error {
+	if s.server == nil {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return s.server.Shutdown(ctx)
+}
+
+// Addr returns the address the System listens on, once started
+func (s *System) Addr() string {
+	if s.listener == nil {
+		return ""
+	}
+	return s.listener.Addr().String()
+}
+
+func (s *System) tlsConfig() (*tls.Config, error) {
+	if !s.options.TLS.Enabled {
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(s.options.TLS.CertFile, s.options.TLS.KeyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load the TLS key pair of the operations server")
+	}
+	config := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   tls.VersionTLS12,
+	}
+
+	if s.options.TLS.ClientCertRequired {
+		clientRootCAs := x509.NewCertPool()
+		for _, caFile := range s.options.TLS.ClientRootCAs {
+			caPEM, err := ioutil.ReadFile(caFile)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read client root CA %s", caFile)
+			}
+			if !clientRootCAs.AppendCertsFromPEM(caPEM) {
+				return nil, errors.Errorf("failed to parse client root CA %s", caFile)
+			}
+		}
+		config.ClientCAs = clientRootCAs
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return config, nil
+}
@@ -0,0 +1,218 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package operations
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hyperledger/fabric/common/healthz"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockChecker struct {
+	err error
+}
+
+func (c *mockChecker) HealthCheck(context.Context) error {
+	return c.err
+}
+
+func TestSystem(t *testing.T) {
+	system := NewSystem(Options{ListenAddress: "127.0.0.1:0"})
+	system.RegisterHandler("/custom/", http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		rw.WriteHeader(http.StatusTeapot)
+	}))
+	assert.Equal(t, "", system.Addr())
+	assert.NoError(t, system.Start())
+	defer system.Stop()
+
+	url := fmt.Sprintf("http://%s", system.Addr())
+
+	resp, err := http.Get(url + "/healthz")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	assert.NoError(t, system.RegisterChecker("docker", &mockChecker{err: errors.New("docker is down")}))
+	resp, err = http.Get(url + "/healthz")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	var status healthz.HealthStatus
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&status))
+	resp.Body.Close()
+	assert.Equal(t, []healthz.FailedCheck{{Component: "docker", Reason: "docker is down"}}, status.FailedChecks)
+
+	resp, err = http.Get(url + "/logspec")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+
+	resp, err = http.Get(url + "/custom/path")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusTeapot, resp.StatusCode)
+	resp.Body.Close()
+
+	// metrics aren't reported to prometheus
+	resp, err = http.Get(url + "/metrics")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	resp.Body.Close()
+
+	assert.NoError(t, system.Stop())
+	_, err = http.Get(url + "/healthz")
+	assert.Error(t, err)
+}
+
+func TestSystemStartFailures(t *testing.T) {
+	system := NewSystem(Options{ListenAddress: "bad-address"})
+	err := system.Start()
+	assert.Contains(t, err.Error(), "failed to listen on bad-address")
+
+	system = NewSystem(Options{
+		ListenAddress: "127.0.0.1:0",
+		TLS:           TLS{Enabled: true, CertFile: "missing-cert.pem", KeyFile: "missing-key.pem"},
+	})
+	err = system.Start()
+	assert.Contains(t, err.Error(), "failed to load the TLS key pair of the operations server")
+
+	tempDir, err := ioutil.TempDir("", "operations")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	certs := generateCertificates(t, tempDir)
+
+	system = NewSystem(Options{
+		ListenAddress: "127.0.0.1:0",
+		TLS: TLS{
+			Enabled:            true,
+			CertFile:           certs.serverCert,
+			KeyFile:            certs.serverKey,
+			ClientCertRequired: true,
+			ClientRootCAs:      []string{certs.serverKey},
+		},
+	})
+	err = system.Start()
+	assert.Contains(t, err.Error(), "failed to parse client root CA")
+}
+
+func TestSystemMutualTLS(t *testing.T) {
+	tempDir, err := ioutil.TempDir("", "operations")
+	assert.NoError(t, err)
+	defer os.RemoveAll(tempDir)
+	certs := generateCertificates(t, tempDir)
+
+	system := NewSystem(Options{
+		ListenAddress: "127.0.0.1:0",
+		TLS: TLS{
+			Enabled:            true,
+			CertFile:           certs.serverCert,
+			KeyFile:            certs.serverKey,
+			ClientCertRequired: true,
+			ClientRootCAs:      []string{certs.caCert},
+		},
+	})
+	assert.NoError(t, system.Start())
+	defer system.Stop()
+
+	caPEM, err := ioutil.ReadFile(certs.caCert)
+	assert.NoError(t, err)
+	rootCAs := x509.NewCertPool()
+	rootCAs.AppendCertsFromPEM(caPEM)
+	url := fmt.Sprintf("https://%s/healthz", system.Addr())
+
+	// without a client certificate the handshake fails
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: rootCAs}}}
+	_, err = client.Get(url)
+	assert.Error(t, err)
+
+	clientCert, err := tls.LoadX509KeyPair(certs.clientCert, certs.clientKey)
+	assert.NoError(t, err)
+	client = &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		RootCAs:      rootCAs,
+		Certificates: []tls.Certificate{clientCert},
+	}}}
+	resp, err := client.Get(url)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp.Body.Close()
+}
+
+type testCertificates struct {
+	caCert     string
+	serverCert string
+	serverKey  string
+	clientCert string
+	clientKey  string
+}
+
+// generateCertificates writes a CA and a server and a client
+// key pair issued by it to the given directory
+func generateCertificates(t *testing.T, dir string) testCertificates {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	assert.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	assert.NoError(t, err)
+
+	issue := func(name string, serial int64, extKeyUsage x509.ExtKeyUsage) (string, string) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		assert.NoError(t, err)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: name},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{extKeyUsage},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		assert.NoError(t, err)
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		assert.NoError(t, err)
+		return writePEM(t, dir, name+"-cert.pem", "CERTIFICATE", der), writePEM(t, dir, name+"-key.pem", "EC PRIVATE KEY", keyDER)
+	}
+
+	certs := testCertificates{caCert: writePEM(t, dir, "ca-cert.pem", "CERTIFICATE", caDER)}
+	certs.serverCert, certs.serverKey = issue("server", 2, x509.ExtKeyUsageServerAuth)
+	certs.clientCert, certs.clientKey = issue("client", 3, x509.ExtKeyUsageClientAuth)
+	return certs
+}
+
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	path := filepath.Join(dir, name)
+	err := ioutil.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0600)
+	assert.NoError(t, err)
+	return path
+}
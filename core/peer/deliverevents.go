@@ -25,6 +25,7 @@ import (
 	"github.com/hyperledger/fabric/core/aclmgmt/resources"
 	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/op/go-logging"
@@ -44,12 +45,20 @@ func init() {
 // poicy checker
 type PolicyCheckerProvider func(resourceName string) deliver.PolicyChecker
 
+// PvtDataProvider provides the private data of committed blocks
+type PvtDataProvider interface {
+	// GetEligiblePvtData returns the private data of the block with the given number
+	// which the signer of signedData is eligible to receive, keyed by transaction index
+	GetEligiblePvtData(channelID string, blockNum uint64, signedData *common.SignedData) (map[uint64]*rwset.TxPvtReadWriteSet, error)
+}
+
 // sever deliver events server which
 // leverages deliver handler being used
 // for atomic broadcast
 type server struct {
 	dh                    deliver.Handler
 	policyCheckerProvider PolicyCheckerProvider
+	pvtDataProvider       PvtDataProvider
 }
 
 // support abstact common functionality of creating
@@ -99,6 +108,58 @@ func (d *deliverFilteredBlockSupport) CreateBlockReply(block *common.Block) prot
 	}
 }
 
+// deliverWithPvtDataSupport support structure used to generate
+// block and private data responses
+type deliverWithPvtDataSupport struct {
+	support
+	peer.Deliver_DeliverWithPrivateDataServer
+	pvtDataProvider PvtDataProvider
+	channelID       string
+	signedData      *common.SignedData
+}
+
+// Recv receives the next deliver request and records its channel and signer,
+// which determine the private data sent along with the blocks
+func (d *deliverWithPvtDataSupport) Recv() (*common.Envelope, error) {
+	envelope, err := d.Deliver_DeliverWithPrivateDataServer.Recv()
+	if err != nil {
+		return envelope, err
+	}
+	d.channelID, d.signedData = "", nil
+	// malformed requests are rejected by the deliver handler
+	chdr, err := utils.ChannelHeader(envelope)
+	if err != nil {
+		return envelope, nil
+	}
+	signedData, err := envelope.AsSignedData()
+	if err != nil {
+		return envelope, nil
+	}
+	d.channelID, d.signedData = chdr.ChannelId, signedData[0]
+	return envelope, nil
+}
+
+// CreateBlockReply generates deliver response with block and private data message
+func (d *deliverWithPvtDataSupport) CreateBlockReply(block *common.Block) proto.Message {
+	if d.signedData == nil {
+		logger.Warningf("Failed to determine the signer of the deliver request for block [%d]", block.Header.Number)
+		return d.CreateStatusReply(common.Status_BAD_REQUEST)
+	}
+	pvtData, err := d.pvtDataProvider.GetEligiblePvtData(d.channelID, block.Header.Number, d.signedData)
+	if err != nil {
+		logger.Warningf("Failed to retrieve private data of block [%d] due to: %s", block.Header.Number, err)
+		return d.CreateStatusReply(common.Status_INTERNAL_SERVER_ERROR)
+	}
+	return &peer.DeliverResponse{
+		Type: &peer.DeliverResponse_BlockAndPrivateData{
+			BlockAndPrivateData: &peer.BlockAndPrivateData{
+				Block:          block,
+				PrivateDataMap: pvtData,
+			},
+		},
+	}
+}
+
 // transactionActions aliasing for peer.TransactionAction pointers slice
 type transactionActions []*peer.TransactionAction
 
@@ -128,9 +189,23 @@ func (s *server) Deliver(srv peer.Deliver_DeliverServer) error {
 	return s.dh.Handle(deliver.NewDeliverServer(srvSupport, s.policyCheckerProvider(resources.BLOCKEVENT), s.sendProducer(srv)))
 }
 
-// NewDeliverEventsServer creates a peer.Deliver server to deliver block and
-// filtered block events
-func NewDeliverEventsServer(mutualTLS bool, policyCheckerProvider PolicyCheckerProvider, supportManager deliver.SupportManager) peer.DeliverServer {
+// DeliverWithPrivateData sends a stream of blocks along with the private data
+// the client is eligible for to a client after commitment
+func (s *server) DeliverWithPrivateData(srv peer.Deliver_DeliverWithPrivateDataServer) error {
+	logger.Debugf("Starting new DeliverWithPrivateData handler")
+	defer dumpStacktraceOnPanic()
+	srvSupport := &deliverWithPvtDataSupport{
+		Deliver_DeliverWithPrivateDataServer: srv,
+		pvtDataProvider:                      s.pvtDataProvider,
+	}
+	// the access to the private data of each collection is checked against its member
+	// policy, thus the stream is subject to the same policy as the one of the full blocks
+	return s.dh.Handle(deliver.NewDeliverServer(srvSupport, s.policyCheckerProvider(resources.BLOCKEVENT), s.sendProducer(srv)))
+}
+
+// NewDeliverEventsServer creates a peer.Deliver server to deliver block,
+// filtered block and block with private data events
+func NewDeliverEventsServer(mutualTLS bool, policyCheckerProvider PolicyCheckerProvider, supportManager deliver.SupportManager, pvtDataProvider PvtDataProvider) peer.DeliverServer {
 	timeWindow := viper.GetDuration("peer.authentication.timewindow")
 	if timeWindow == 0 {
 		defaultTimeWindow := 15 * time.Minute
@@ -140,6 +215,7 @@ func NewDeliverEventsServer(mutualTLS bool, policyCheckerProvider PolicyCheckerP
 	return &server{
 		dh: deliver.NewHandlerImpl(supportManager, timeWindow, mutualTLS),
 		policyCheckerProvider: policyCheckerProvider,
+		pvtDataProvider:       pvtDataProvider,
 	}
 }
 
@@ -17,9 +17,11 @@ import (
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	"github.com/hyperledger/fabric/protos/orderer"
 	"github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -371,7 +373,7 @@ func TestEventsServer_DeliverFiltered(t *testing.T) {
 			wg := &sync.WaitGroup{}
 			supportManager, deliverServer := test.prepare(wg)
 
-			server := NewDeliverEventsServer(false, defaultPolicyCheckerProvider, supportManager)
+			server := NewDeliverEventsServer(false, defaultPolicyCheckerProvider, supportManager, nil)
 			err := server.DeliverFiltered(deliverServer)
 			wg.Wait()
 			// no error expected
@@ -379,6 +381,119 @@ func TestEventsServer_DeliverFiltered(t *testing.T) {
 		})
 	}
 }
+// mockPvtDataProvider mock implementation of the PvtDataProvider interface
+type mockPvtDataProvider struct {
+	mock.Mock
+}
+
+func (m *mockPvtDataProvider) GetEligiblePvtData(channelID string, blockNum uint64, signedData *common.SignedData) (map[uint64]*rwset.TxPvtReadWriteSet, error) {
+	args := m.Called(channelID, blockNum, signedData)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(map[uint64]*rwset.TxPvtReadWriteSet), args.Error(1)
+}
+
+func TestEventsServer_DeliverWithPrivateData(t *testing.T) {
+	viper.Set("peer.authentication.timewindow", "1s")
+	config := testConfig{
+		channelID:     "testChainID",
+		eventName:     "testEvent",
+		chaincodeName: "mycc",
+		txID:          "testID",
+		payload: &common.Payload{
+			Header: &common.Header{
+				ChannelHeader: utils.MarshalOrPanic(&common.ChannelHeader{
+					ChannelId: "testChainID",
+					Timestamp: util.CreateUtcTimestamp(),
+				}),
+				SignatureHeader: utils.MarshalOrPanic(&common.SignatureHeader{Creator: []byte("creator")}),
+			},
+			Data: utils.MarshalOrPanic(&orderer.SeekInfo{
+				Start:    &orderer.SeekPosition{Type: &orderer.SeekPosition_Specified{Specified: &orderer.SeekSpecified{Number: 0}}},
+				Stop:     &orderer.SeekPosition{Type: &orderer.SeekPosition_Newest{Newest: &orderer.SeekNewest{}}},
+				Behavior: orderer.SeekInfo_BLOCK_UNTIL_READY,
+			}),
+		},
+		Assertions: assert.New(t),
+	}
+	pvtData := map[uint64]*rwset.TxPvtReadWriteSet{
+		0: {
+			NsPvtRwset: []*rwset.NsPvtReadWriteSet{{
+				Namespace:          "mycc",
+				CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{{CollectionName: "coll", Rwset: []byte("rwset")}},
+			}},
+		},
+	}
+
+	for _, test := range []struct {
+		name           string
+		pvtData        map[uint64]*rwset.TxPvtReadWriteSet
+		pvtDataErr     error
+		expectedStatus []common.Status
+	}{
+		{
+			name:           "Testing deliver of blocks with private data",
+			pvtData:        pvtData,
+			expectedStatus: []common.Status{common.Status_SUCCESS},
+		},
+		{
+			name:           "Testing deliver of blocks with private data when the private data cannot be retrieved",
+			pvtDataErr:     errors.New("pvtdata store unavailable"),
+			expectedStatus: []common.Status{common.Status_INTERNAL_SERVER_ERROR, common.Status_SUCCESS},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			wg := &sync.WaitGroup{}
+			wg.Add(2)
+			p := &peer2.Peer{}
+			chaincodeActionPayload, err := createChaincodeAction(config.chaincodeName, config.eventName, config.txID)
+			config.NoError(err)
+			supportManager := createDefaultSupportMamangerMock(config, chaincodeActionPayload)
+
+			pvtDataProvider := &mockPvtDataProvider{}
+			pvtDataProvider.On("GetEligiblePvtData", config.channelID, uint64(0), &common.SignedData{
+				Data:      utils.MarshalOrPanic(config.payload),
+				Identity:  []byte("creator"),
+				Signature: []byte("signature"),
+			}).Return(test.pvtData, test.pvtDataErr)
+
+			var statuses []common.Status
+			deliverServer := &mockDeliverServer{}
+			deliverServer.On("Context").Return(peer2.NewContext(context.TODO(), p))
+			deliverServer.On("Recv").Return(&common.Envelope{
+				Payload:   utils.MarshalOrPanic(config.payload),
+				Signature: []byte("signature"),
+			}, nil).Run(func(_ mock.Arguments) {
+				deliverServer.Mock = mock.Mock{}
+				deliverServer.On("Context").Return(peer2.NewContext(context.TODO(), p))
+				deliverServer.On("Recv").Return(&common.Envelope{}, io.EOF)
+				deliverServer.On("Send", mock.Anything).Run(func(args mock.Arguments) {
+					defer wg.Done()
+					response := args.Get(0).(*peer.DeliverResponse)
+					switch response.Type.(type) {
+					case *peer.DeliverResponse_Status:
+						statuses = append(statuses, response.GetStatus())
+					case *peer.DeliverResponse_BlockAndPrivateData:
+						blockAndPvtData := response.GetBlockAndPrivateData()
+						config.Equal(uint64(0), blockAndPvtData.Block.Header.Number)
+						config.Equal(test.pvtData, blockAndPvtData.PrivateDataMap)
+					default:
+						config.FailNow("Unexpected response type")
+					}
+				}).Return(nil)
+			})
+
+			server := NewDeliverEventsServer(false, defaultPolicyCheckerProvider, supportManager, pvtDataProvider)
+			err = server.DeliverWithPrivateData(deliverServer)
+			wg.Wait()
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedStatus, statuses)
+			pvtDataProvider.AssertExpectations(t)
+		})
+	}
+}
+
 func createDefaultSupportMamangerMock(config testConfig, chaincodeActionPayload *peer.ChaincodeActionPayload) *mockSupportManager {
 	supportManager := &mockSupportManager{}
 	iter := &mockIterator{}
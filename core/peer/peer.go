@@ -26,6 +26,7 @@ import (
 	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
 	"github.com/hyperledger/fabric/common/policies"
 	"github.com/hyperledger/fabric/common/resourcesconfig"
+	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/comm"
 	"github.com/hyperledger/fabric/core/committer"
 	"github.com/hyperledger/fabric/core/committer/txvalidator"
@@ -39,6 +40,7 @@ import (
 	"github.com/hyperledger/fabric/msp"
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/pkg/errors"
@@ -179,6 +181,10 @@ func MockInitialize() {
 
 var chainInitializer func(string)
 
+// pluginMapper resolves the validation plugins that
+// the validators of the channels use
+var pluginMapper txvalidator.PluginMapper = txvalidator.MapBasedPluginMapper{}
+
 var mockMSPIDGetter func(string) []string
 
 func MockSetMSPIDGetter(mspIDGetter func(string) []string) {
@@ -191,8 +197,9 @@ var validationWorkersSemaphore *semaphore.Weighted
 
 // Initialize sets up any chains that the peer has from the persistence. This
 // function should be called at the start up when the ledger and gossip
-// ready
-func Initialize(init func(string)) {
+// ready; the transactions of the chains are validated with the validation
+// plugins resolved by the given PluginMapper
+func Initialize(init func(string), pm txvalidator.PluginMapper) {
 	nWorkers := viper.GetInt("peer.validatorPoolSize")
 	if nWorkers <= 0 {
 		nWorkers = runtime.NumCPU()
@@ -200,6 +207,7 @@ func Initialize(init func(string)) {
 	validationWorkersSemaphore = semaphore.NewWeighted(int64(nWorkers))
 
 	chainInitializer = init
+	pluginMapper = pm
 
 	var cb *common.Block
 	var ledger ledger.PeerLedger
@@ -382,7 +390,7 @@ func createChain(cid string, ledger ledger.PeerLedger, cb *common.Block) error {
 		*semaphore.Weighted
 		Support
 	}{cs, validationWorkersSemaphore, GetSupport()}
-	validator := txvalidator.NewTxValidator(cid, vcs)
+	validator := txvalidator.NewTxValidator(cid, vcs, pluginMapper)
 	c := committer.NewLedgerCommitterReactive(ledger, func(block *common.Block) error {
 		chainID, err := utils.GetChainIDFromBlock(block)
 		if err != nil {
@@ -437,6 +445,72 @@ func CreateChainFromBlock(cb *common.Block) error {
 	return createChain(cid, l, cb)
 }
 
+// CreateChainFromSnapshot creates a new chain from the ledger snapshot present in the given
+// directory and returns the id of the chain. The configuration of the chain is loaded from the
+// last config block included in the snapshot
+func CreateChainFromSnapshot(snapshotDir string) (string, error) {
+	l, err := ledgermgmt.CreateLedgerFromSnapshot(snapshotDir, verifySnapshotBlocks)
+	if err != nil {
+		return "", fmt.Errorf("Cannot create ledger from snapshot, due to %s", err)
+	}
+
+	cb, err := getCurrConfigBlockFromLedger(l)
+	if err != nil {
+		l.Close()
+		return "", err
+	}
+
+	cid, err := utils.GetChainIDFromBlock(cb)
+	if err != nil {
+		l.Close()
+		return "", err
+	}
+
+	return cid, createChain(cid, l, cb)
+}
+
+// verifySnapshotBlocks verifies that the last block of a snapshot is signed by the ordering service, as
+// required by the block validation policy of the channel config in the last config block of the snapshot
+func verifySnapshotBlocks(lastBlock, lastConfigBlock *common.Block) error {
+	if lastBlock.Header.Number == 0 {
+		// the genesis block is not signed, it is trusted as when joining the channel by the genesis block
+		return nil
+	}
+	envelopeConfig, err := utils.ExtractEnvelope(lastConfigBlock, 0)
+	if err != nil {
+		return errors.WithMessage(err, "failed to extract the config envelope")
+	}
+	bundle, err := channelconfig.NewBundleFromEnvelope(envelopeConfig)
+	if err != nil {
+		return errors.WithMessage(err, "failed to load the channel config")
+	}
+	policy, ok := bundle.PolicyManager().GetPolicy(policies.BlockValidation)
+	if !ok {
+		return errors.Errorf("policy %s not found in the channel config", policies.BlockValidation)
+	}
+
+	metadata, err := utils.GetMetadataFromBlock(lastBlock, common.BlockMetadataIndex_SIGNATURES)
+	if err != nil {
+		return errors.WithMessage(err, "failed to unmarshal the signatures of the last block")
+	}
+	var signatureSet []*common.SignedData
+	for _, metadataSignature := range metadata.Signatures {
+		shdr, err := utils.GetSignatureHeader(metadataSignature.SignatureHeader)
+		if err != nil {
+			return errors.WithMessage(err, "failed to unmarshal the signature header of the last block")
+		}
+		signatureSet = append(signatureSet, &common.SignedData{
+			Identity:  shdr.Creator,
+			Data:      util.ConcatenateBytes(metadata.Value, metadataSignature.SignatureHeader, lastBlock.Header.Bytes()),
+			Signature: metadataSignature.Signature,
+		})
+	}
+	if err := policy.Evaluate(signatureSet); err != nil {
+		return errors.WithMessage(err, "the last block does not satisfy the block validation policy")
+	}
+	return nil
+}
+
 // MockCreateChain used for creating a ledger for a chain for tests
 // without having to join
 func MockCreateChain(cid string) error {
@@ -769,6 +843,80 @@ func (dsm DeliverSupportManager) GetChain(chainID string) (deliver.Support, bool
 	return channel.cs, ok
 }
 
+// DeliverPvtDataProvider provides the private data of the blocks of the
+// channels the peer is joined to for deliver
+type DeliverPvtDataProvider struct {
+}
+
+// GetEligiblePvtData returns the private data of the block with the given number
+// which the signer of signedData is eligible to receive, keyed by transaction index
+func (DeliverPvtDataProvider) GetEligiblePvtData(channelID string, blockNum uint64, signedData *common.SignedData) (map[uint64]*rwset.TxPvtReadWriteSet, error) {
+	l := GetLedger(channelID)
+	if l == nil {
+		return nil, errors.Errorf("channel %s not found", channelID)
+	}
+	pvtData, err := l.GetPvtDataByNum(blockNum, nil)
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("failed retrieving private data of block %d", blockNum))
+	}
+	cs := privdata.NewSimpleCollectionStore(&collectionSupport{PeerLedger: l})
+	return eligiblePvtData(channelID, pvtData, cs, signedData), nil
+}
+
+// eligiblePvtData filters the private data of a block down to the collections
+// whose member policy is satisfied by the signer of signedData
+func eligiblePvtData(channelID string, pvtData []*ledger.TxPvtData, cs privdata.CollectionStore, signedData *common.SignedData) map[uint64]*rwset.TxPvtReadWriteSet {
+	type nsColl struct {
+		namespace, collection string
+	}
+	eligible := make(map[nsColl]bool)
+	isEligible := func(namespace, collection string) bool {
+		key := nsColl{namespace: namespace, collection: collection}
+		if res, exists := eligible[key]; exists {
+			return res
+		}
+		ap, err := cs.RetrieveCollectionAccessPolicy(common.CollectionCriteria{
+			Channel:    channelID,
+			Namespace:  namespace,
+			Collection: collection,
+		})
+		if err != nil {
+			peerLogger.Warningf("Failed retrieving access policy of collection %s of chaincode %s: %s", collection, namespace, err)
+		}
+		eligible[key] = err == nil && ap.AccessFilter()(*signedData)
+		return eligible[key]
+	}
+
+	res := make(map[uint64]*rwset.TxPvtReadWriteSet)
+	for _, txPvtData := range pvtData {
+		if txPvtData.WriteSet == nil {
+			continue
+		}
+		var nsPvtRwsets []*rwset.NsPvtReadWriteSet
+		for _, nsPvtRwset := range txPvtData.WriteSet.NsPvtRwset {
+			var collPvtRwsets []*rwset.CollectionPvtReadWriteSet
+			for _, collPvtRwset := range nsPvtRwset.CollectionPvtRwset {
+				if isEligible(nsPvtRwset.Namespace, collPvtRwset.CollectionName) {
+					collPvtRwsets = append(collPvtRwsets, collPvtRwset)
+				}
+			}
+			if len(collPvtRwsets) > 0 {
+				nsPvtRwsets = append(nsPvtRwsets, &rwset.NsPvtReadWriteSet{
+					Namespace:          nsPvtRwset.Namespace,
+					CollectionPvtRwset: collPvtRwsets,
+				})
+			}
+		}
+		if len(nsPvtRwsets) > 0 {
+			res[txPvtData.SeqInBlock] = &rwset.TxPvtReadWriteSet{
+				DataModel:  txPvtData.WriteSet.DataModel,
+				NsPvtRwset: nsPvtRwsets,
+			}
+		}
+	}
+	return res
+}
+
 // fileLedgerBlockStore implements the interface expected by
 // common/ledger/blockledger/file to interact with a file ledger for deliver
 type fileLedgerBlockStore struct {
@@ -8,6 +8,7 @@ package peer
 
 import (
 	"fmt"
+	"io/ioutil"
 	"net"
 	"os"
 	"testing"
@@ -15,11 +16,15 @@ import (
 	configtxtest "github.com/hyperledger/fabric/common/configtx/test"
 	"github.com/hyperledger/fabric/common/localmsp"
 	mscc "github.com/hyperledger/fabric/common/mocks/scc"
+	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/comm"
 	ccp "github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
 	"github.com/hyperledger/fabric/core/deliverservice"
 	"github.com/hyperledger/fabric/core/deliverservice/blocksprovider"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
 	"github.com/hyperledger/fabric/core/mocks/ccprovider"
 	"github.com/hyperledger/fabric/gossip/api"
 	"github.com/hyperledger/fabric/gossip/service"
@@ -27,6 +32,9 @@ import (
 	"github.com/hyperledger/fabric/msp/mgmt/testtools"
 	peergossip "github.com/hyperledger/fabric/peer/gossip"
 	"github.com/hyperledger/fabric/peer/gossip/mocks"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/hyperledger/fabric/protos/utils"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
 	"google.golang.org/grpc"
@@ -92,7 +100,7 @@ func TestInitialize(t *testing.T) {
 	ccp.RegisterChaincodeProviderFactory(&ccprovider.MockCcProviderFactory{})
 	sysccprovider.RegisterSystemChaincodeProviderFactory(&mscc.MocksccProviderFactory{})
 
-	Initialize(nil)
+	Initialize(nil, nil)
 }
 
 func TestCreateChainFromBlock(t *testing.T) {
@@ -193,7 +201,7 @@ func TestCreateChainFromBlock(t *testing.T) {
 	assert.Equal(t, true, ok, "expected Manage() to return true")
 
 	// Chaos monkey test
-	Initialize(nil)
+	Initialize(nil, nil)
 
 	SetCurrConfigBlock(block, testChainID)
 
@@ -203,6 +211,49 @@ func TestCreateChainFromBlock(t *testing.T) {
 	}
 }
 
+func TestCreateChainFromSnapshotErrors(t *testing.T) {
+	MockInitialize()
+	defer ledgermgmt.CleanupTestEnv()
+
+	snapshotDir, err := ioutil.TempDir("", "peer-snapshot")
+	assert.NoError(t, err)
+	defer os.RemoveAll(snapshotDir)
+
+	_, err = CreateChainFromSnapshot(snapshotDir)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Cannot create ledger from snapshot")
+	assert.Empty(t, GetChannelsInfo())
+}
+
+func TestVerifySnapshotBlocks(t *testing.T) {
+	msptesttools.LoadMSPSetupForTesting()
+	configBlock, err := configtxtest.MakeGenesisBlock("mytestchainid")
+	assert.NoError(t, err)
+	// the genesis block is not signed
+	assert.NoError(t, verifySnapshotBlocks(configBlock, configBlock))
+
+	lastBlock := common.NewBlock(1, configBlock.Header.Hash())
+	lastBlock.Data.Data = [][]byte{[]byte("tx")}
+	lastBlock.Header.DataHash = lastBlock.Data.Hash()
+	utils.InitBlockMetadata(lastBlock)
+	err = verifySnapshotBlocks(lastBlock, configBlock)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the last block does not satisfy the block validation policy")
+
+	// the last block is signed by the orderer organization of the channel
+	signer := localmsp.NewSigner()
+	blockSignature := &common.MetadataSignature{SignatureHeader: utils.MarshalOrPanic(utils.NewSignatureHeaderOrPanic(signer))}
+	blockSignature.Signature = utils.SignOrPanic(signer, util.ConcatenateBytes(nil, blockSignature.SignatureHeader, lastBlock.Header.Bytes()))
+	lastBlock.Metadata.Metadata[common.BlockMetadataIndex_SIGNATURES] = utils.MarshalOrPanic(&common.Metadata{
+		Signatures: []*common.MetadataSignature{blockSignature},
+	})
+	assert.NoError(t, verifySnapshotBlocks(lastBlock, configBlock))
+
+	// the signature doesn't cover a modified header
+	lastBlock.Header.Number = 2
+	assert.Error(t, verifySnapshotBlocks(lastBlock, configBlock))
+}
+
 func TestGetLocalIP(t *testing.T) {
 	ip := GetLocalIP()
 	t.Log(ip)
@@ -222,3 +273,116 @@ func TestDeliverSupportManager(t *testing.T) {
 	assert.NotNil(t, chainSupport, "chain support should not be nil")
 	assert.True(t, ok, "Should find testchain channel")
 }
+
+type mockCollectionStore struct {
+	// members maps namespace/collection to the MSP IDs of its members
+	members map[string][]string
+}
+
+func (cs *mockCollectionStore) RetrieveCollectionAccessPolicy(cc common.CollectionCriteria) (privdata.CollectionAccessPolicy, error) {
+	members, exists := cs.members[cc.Namespace+"/"+cc.Collection]
+	if !exists {
+		return nil, privdata.NoSuchCollectionError(cc)
+	}
+	return &mockCollectionAccessPolicy{members: members}, nil
+}
+
+func (cs *mockCollectionStore) RetrieveCollection(common.CollectionCriteria) (privdata.Collection, error) {
+	panic("implement me")
+}
+
+func (cs *mockCollectionStore) RetrieveCollectionConfigPackage(common.CollectionCriteria) (*common.CollectionConfigPackage, error) {
+	panic("implement me")
+}
+
+type mockCollectionAccessPolicy struct {
+	members []string
+}
+
+func (ap *mockCollectionAccessPolicy) AccessFilter() privdata.Filter {
+	return func(sd common.SignedData) bool {
+		for _, member := range ap.members {
+			if string(sd.Identity) == member {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func (ap *mockCollectionAccessPolicy) RequiredPeerCount() int {
+	return 0
+}
+
+func (ap *mockCollectionAccessPolicy) MaximumPeerCount() int {
+	return 0
+}
+
+func (ap *mockCollectionAccessPolicy) BlockToLive() uint64 {
+	return 0
+}
+
+func (ap *mockCollectionAccessPolicy) MemberOrgs() []string {
+	return ap.members
+}
+
+func TestEligiblePvtData(t *testing.T) {
+	collPvtRwset := func(name string) *rwset.CollectionPvtReadWriteSet {
+		return &rwset.CollectionPvtReadWriteSet{CollectionName: name, Rwset: []byte(name)}
+	}
+	pvtData := []*ledger.TxPvtData{
+		{
+			SeqInBlock: 0,
+			WriteSet: &rwset.TxPvtReadWriteSet{
+				NsPvtRwset: []*rwset.NsPvtReadWriteSet{
+					{Namespace: "cc1", CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{collPvtRwset("org1"), collPvtRwset("all")}},
+					{Namespace: "cc2", CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{collPvtRwset("org2")}},
+				},
+			},
+		},
+		{
+			SeqInBlock: 2,
+			WriteSet: &rwset.TxPvtReadWriteSet{
+				NsPvtRwset: []*rwset.NsPvtReadWriteSet{
+					{Namespace: "cc2", CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{collPvtRwset("org2"), collPvtRwset("unknown")}},
+				},
+			},
+		},
+		{
+			SeqInBlock: 3,
+		},
+	}
+	cs := &mockCollectionStore{
+		members: map[string][]string{
+			"cc1/org1": {"Org1MSP"},
+			"cc1/all":  {"Org1MSP", "Org2MSP"},
+			"cc2/org2": {"Org2MSP"},
+		},
+	}
+
+	res := eligiblePvtData("testchain", pvtData, cs, &common.SignedData{Identity: []byte("Org1MSP")})
+	assert.Len(t, res, 1)
+	assert.Len(t, res[0].NsPvtRwset, 1)
+	assert.Equal(t, "cc1", res[0].NsPvtRwset[0].Namespace)
+	assert.Equal(t, []*rwset.CollectionPvtReadWriteSet{collPvtRwset("org1"), collPvtRwset("all")}, res[0].NsPvtRwset[0].CollectionPvtRwset)
+
+	res = eligiblePvtData("testchain", pvtData, cs, &common.SignedData{Identity: []byte("Org2MSP")})
+	assert.Len(t, res, 2)
+	assert.Len(t, res[0].NsPvtRwset, 2)
+	assert.Equal(t, []*rwset.CollectionPvtReadWriteSet{collPvtRwset("all")}, res[0].NsPvtRwset[0].CollectionPvtRwset)
+	assert.Equal(t, []*rwset.CollectionPvtReadWriteSet{collPvtRwset("org2")}, res[0].NsPvtRwset[1].CollectionPvtRwset)
+	assert.Len(t, res[2].NsPvtRwset, 1)
+	assert.Equal(t, []*rwset.CollectionPvtReadWriteSet{collPvtRwset("org2")}, res[2].NsPvtRwset[0].CollectionPvtRwset)
+
+	res = eligiblePvtData("testchain", pvtData, cs, &common.SignedData{Identity: []byte("Org3MSP")})
+	assert.Empty(t, res)
+}
+
+func TestDeliverPvtDataProvider(t *testing.T) {
+	// reset chains for testing
+	MockInitialize()
+
+	provider := &DeliverPvtDataProvider{}
+	_, err := provider.GetEligiblePvtData("fake", 0, &common.SignedData{})
+	assert.EqualError(t, err, "channel fake not found")
+}
@@ -54,6 +54,8 @@ var cnflogger = flogging.MustGetLogger("cscc")
 // These are function names from Invoke first parameter
 const (
 	JoinChain                string = "JoinChain"
+	JoinChainBySnapshot      string = "JoinChainBySnapshot"
+	ExportSnapshot           string = "ExportSnapshot"
 	GetConfigBlock           string = "GetConfigBlock"
 	GetChannels              string = "GetChannels"
 	GetConfigTree            string = "GetConfigTree"
@@ -84,7 +86,9 @@ func (e *PeerConfiger) Init(stub shim.ChaincodeStubInterface) pb.Response {
 // # args[0] is the function name, which must be JoinChain, GetConfigBlock or
 // UpdateConfigBlock
 // # args[1] is a configuration Block if args[0] is JoinChain or
-// UpdateConfigBlock; otherwise it is the chain id
+// UpdateConfigBlock, a snapshot directory if args[0] is JoinChainBySnapshot;
+// otherwise it is the chain id
+// # args[2] is a snapshot directory if args[0] is ExportSnapshot
 // TODO: Improve the scc interface to avoid marshal/unmarshal args
 func (e *PeerConfiger) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 	args := stub.GetArgs()
@@ -138,6 +142,32 @@ func (e *PeerConfiger) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 		}
 
 		return joinChain(cid, block)
+	case JoinChainBySnapshot:
+		if len(args[1]) == 0 {
+			return shim.Error("Cannot join the channel, no snapshot directory provided")
+		}
+
+		// 2. check local MSP Admins policy
+		// TODO: move to ACLProvider once it will support chainless ACLs
+		if err = e.policyChecker.CheckPolicyNoChannel(mgmt.Admins, sp); err != nil {
+			return shim.Error(fmt.Sprintf("\"JoinChainBySnapshot\" request failed authorization check "+
+				"for snapshot [%s]: [%s]", args[1], err))
+		}
+
+		return joinChainBySnapshot(string(args[1]))
+	case ExportSnapshot:
+		if len(args) < 3 || len(args[2]) == 0 {
+			return shim.Error(fmt.Sprintf("Incorrect number of arguments, %d", len(args)))
+		}
+
+		// 2. check local MSP Admins policy
+		// TODO: move to ACLProvider once it will support chainless ACLs
+		if err = e.policyChecker.CheckPolicyNoChannel(mgmt.Admins, sp); err != nil {
+			return shim.Error(fmt.Sprintf("\"ExportSnapshot\" request failed authorization check "+
+				"for channel [%s]: [%s]", args[1], err))
+		}
+
+		return exportSnapshot(string(args[1]), string(args[2]))
 	case GetConfigBlock:
 		// 2. check policy
 		if err = aclmgmt.GetACLProvider().CheckACL(resources.CSCC_GetConfigBlock, string(args[1]), sp); err != nil {
@@ -227,6 +257,36 @@ func joinChain(chainID string, block *common.Block) pb.Response {
 	return shim.Success(nil)
 }
 
+// joinChainBySnapshot will join the chain recorded in the ledger snapshot present in the
+// specified directory. The ledger starts at the height of the snapshot and the configuration
+// of the chain is loaded from the last config block included in the snapshot
+func joinChainBySnapshot(snapshotDir string) pb.Response {
+	chainID, err := peer.CreateChainFromSnapshot(snapshotDir)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	peer.InitChain(chainID)
+	cnflogger.Infof("Joined channel [%s] from snapshot [%s]", chainID, snapshotDir)
+	return shim.Success([]byte(chainID))
+}
+
+// exportSnapshot exports a snapshot of the ledger of the specified chainID at its current
+// height in the specified directory and returns the hash of the snapshot. If the peer
+// doesn't belong to the chain, return error
+func exportSnapshot(chainID, snapshotDir string) pb.Response {
+	l := peer.GetLedger(chainID)
+	if l == nil {
+		return shim.Error(fmt.Sprintf("Unknown chain ID, %s", chainID))
+	}
+	snapshotHash, err := l.ExportSnapshot(snapshotDir)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to export snapshot for chain ID %s: %s", chainID, err))
+	}
+
+	return shim.Success(snapshotHash)
+}
+
 // Return the current configuration block for the specified chainID. If the
 // peer doesn't belong to the chain, return error
 func getConfigBlock(chainID []byte) pb.Response {
@@ -310,6 +310,82 @@ func TestConfigerInvokeJoinChainCorrectParams(t *testing.T) {
 	}
 }
 
+func TestConfigerInvokeSnapshot(t *testing.T) {
+	sysccprovider.RegisterSystemChaincodeProviderFactory(&scc.MocksccProviderFactory{})
+
+	viper.Set("peer.fileSystemPath", "/tmp/hyperledgertest/")
+	viper.Set("chaincode.executetimeout", "3s")
+	os.Mkdir("/tmp/hyperledgertest", 0755)
+	defer os.RemoveAll("/tmp/hyperledgertest/")
+	snapshotDir := "/tmp/hyperledgertest/snapshot"
+
+	peer.MockInitialize()
+	ledgermgmt.InitializeTestEnv()
+	defer ledgermgmt.CleanupTestEnv()
+
+	e := new(PeerConfiger)
+	stub := shim.NewMockStub("PeerConfiger", e)
+
+	identityDeserializer := &policymocks.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
+	e.policyChecker = policy.NewPolicyChecker(
+		&policymocks.MockChannelPolicyManagerGetter{},
+		identityDeserializer,
+		&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+	)
+
+	identity, _ := mgmt.GetLocalSigningIdentityOrPanic().Serialize()
+	messageCryptoService := peergossip.NewMCS(&mocks.ChannelPolicyManagerGetter{}, localmsp.NewSigner(), mgmt.NewDeserializersManager())
+	secAdv := peergossip.NewSecurityAdvisor(mgmt.NewDeserializersManager())
+	err := service.InitGossipServiceCustomDeliveryFactory(identity, "localhost:13611", nil, nil, &mockDeliveryClientFactory{}, messageCryptoService, secAdv, nil)
+	assert.NoError(t, err)
+
+	sProp, _ := utils.MockSignedEndorserProposalOrPanic("", &pb.ChaincodeSpec{}, []byte("Alice"), []byte("msg1"))
+	identityDeserializer.Msg = sProp.ProposalBytes
+	sProp.Signature = sProp.ProposalBytes
+
+	res := stub.MockInvokeWithSignedProposal("1", [][]byte{[]byte(JoinChain), mockConfigBlock()}, sProp)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+
+	// Fail paths for ExportSnapshot
+	res = stub.MockInvokeWithSignedProposal("2", [][]byte{[]byte(ExportSnapshot), []byte("mytestchainid")}, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Equal(t, "Incorrect number of arguments, 2", res.Message)
+	res = stub.MockInvokeWithSignedProposal("2", [][]byte{[]byte(ExportSnapshot), []byte("BogusChain"), []byte(snapshotDir)}, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Equal(t, "Unknown chain ID, BogusChain", res.Message)
+	sProp.Signature = nil
+	res = stub.MockInvokeWithSignedProposal("2", [][]byte{[]byte(ExportSnapshot), []byte("mytestchainid"), []byte(snapshotDir)}, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "\"ExportSnapshot\" request failed authorization check for channel [mytestchainid]")
+	sProp.Signature = sProp.ProposalBytes
+
+	res = stub.MockInvokeWithSignedProposal("2", [][]byte{[]byte(ExportSnapshot), []byte("mytestchainid"), []byte(snapshotDir)}, sProp)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	assert.NotEmpty(t, res.Payload)
+
+	// Join the channel by the snapshot on a peer without the ledger
+	peer.MockInitialize()
+
+	// Fail paths for JoinChainBySnapshot
+	res = stub.MockInvokeWithSignedProposal("3", [][]byte{[]byte(JoinChainBySnapshot), nil}, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Equal(t, "Cannot join the channel, no snapshot directory provided", res.Message)
+	res = stub.MockInvokeWithSignedProposal("3", [][]byte{[]byte(JoinChainBySnapshot), []byte("/non/existent/dir")}, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "Cannot create ledger from snapshot")
+	sProp.Signature = nil
+	res = stub.MockInvokeWithSignedProposal("3", [][]byte{[]byte(JoinChainBySnapshot), []byte(snapshotDir)}, sProp)
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, "\"JoinChainBySnapshot\" request failed authorization check")
+	sProp.Signature = sProp.ProposalBytes
+
+	res = stub.MockInvokeWithSignedProposal("3", [][]byte{[]byte(JoinChainBySnapshot), []byte(snapshotDir)}, sProp)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	assert.Equal(t, "mytestchainid", string(res.Payload))
+	assert.NotNil(t, peer.GetCurrConfigBlock("mytestchainid"))
+	assert.Len(t, peer.GetChannelsInfo(), 1)
+}
+
 func TestPeerConfiger_SubmittingOrdererGenesis(t *testing.T) {
 
 	viper.Set("peer.fileSystemPath", "/tmp/hyperledgertest/")
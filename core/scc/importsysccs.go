@@ -10,6 +10,7 @@ import (
 	//import system chaincodes here
 	"github.com/hyperledger/fabric/core/scc/cscc"
 	"github.com/hyperledger/fabric/core/scc/escc"
+	"github.com/hyperledger/fabric/core/scc/lifecycle"
 	"github.com/hyperledger/fabric/core/scc/lscc"
 	"github.com/hyperledger/fabric/core/scc/qscc"
 	"github.com/hyperledger/fabric/core/scc/vscc"
@@ -34,6 +35,14 @@ var systemChaincodes = []*SystemChaincode{
 		InvokableExternal: true, // lscc is invoked to deploy new chaincodes
 		InvokableCC2CC:    true, // lscc can be invoked by other chaincodes
 	},
+	{
+		Enabled:           true,
+		Name:              "_lifecycle",
+		Path:              "github.com/hyperledger/fabric/core/scc/lifecycle",
+		InitArgs:          [][]byte{[]byte("")},
+		Chaincode:         lifecycle.New(),
+		InvokableExternal: true, // _lifecycle is invoked to approve and commit chaincode definitions
+	},
 	{
 		Enabled:   true,
 		Name:      "escc",
@@ -0,0 +1,48 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle
+
+import "fmt"
+
+//InvalidFunctionErr invalid function error
+type InvalidFunctionErr string
+
+func (f InvalidFunctionErr) Error() string {
+	return fmt.Sprintf("invalid function to _lifecycle %s", string(f))
+}
+
+//InvalidArgsLenErr invalid arguments length error
+type InvalidArgsLenErr int
+
+func (i InvalidArgsLenErr) Error() string {
+	return fmt.Sprintf("invalid number of arguments to _lifecycle %d", int(i))
+}
+
+//NotDefinedErr chaincode definition not committed error
+type NotDefinedErr string
+
+func (t NotDefinedErr) Error() string {
+	return fmt.Sprintf("namespace %s is not defined", string(t))
+}
+
+//InvalidSequenceErr chaincode definition sequence error
+type InvalidSequenceErr struct {
+	Name     string
+	Sequence int64
+	Expected int64
+}
+
+func (e InvalidSequenceErr) Error() string {
+	return fmt.Sprintf("requested sequence is %d, but new definition for %s must be sequence %d", e.Sequence, e.Name, e.Expected)
+}
+
+//NotApprovedErr chaincode definition not approved by the peer's org error
+type NotApprovedErr string
+
+func (t NotApprovedErr) Error() string {
+	return fmt.Sprintf("chaincode definition not agreed to by this org (%s)", string(t))
+}
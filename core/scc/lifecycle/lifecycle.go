@@ -0,0 +1,379 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/core/aclmgmt"
+	"github.com/hyperledger/fabric/core/aclmgmt/resources"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/lifecycle"
+	"github.com/hyperledger/fabric/core/handlers/library"
+	"github.com/hyperledger/fabric/core/policy"
+	"github.com/hyperledger/fabric/core/policyprovider"
+	"github.com/hyperledger/fabric/msp/mgmt"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+//The lifecycle system chaincode lets the organizations of a channel
+//agree on the definition of a chaincode. Each organization approves a
+//definition for itself; once enough organizations have approved it,
+//the definition can be committed to the channel.
+//     "Args":["ApproveChaincodeDefinitionForMyOrg",<ApproveChaincodeDefinitionForMyOrgArgs>]
+//     "Args":["CheckCommitReadiness",<CheckCommitReadinessArgs>]
+//     "Args":["CommitChaincodeDefinition",<CommitChaincodeDefinitionArgs>]
+//     "Args":["QueryChaincodeDefinition",<QueryChaincodeDefinitionArgs>]
+//     "Args":["QueryChaincodeDefinitions",<QueryChaincodeDefinitionsArgs>]
+
+var logger = flogging.MustGetLogger("lifecycle")
+
+const (
+	//ApproveFuncName approves a chaincode definition for the peer's org
+	ApproveFuncName = "ApproveChaincodeDefinitionForMyOrg"
+
+	//CheckCommitReadinessFuncName reports which orgs approved a definition
+	CheckCommitReadinessFuncName = "CheckCommitReadiness"
+
+	//CommitFuncName commits a chaincode definition to the channel
+	CommitFuncName = "CommitChaincodeDefinition"
+
+	//QueryChaincodeDefinitionFuncName returns a committed definition
+	QueryChaincodeDefinitionFuncName = "QueryChaincodeDefinition"
+
+	//QueryChaincodeDefinitionsFuncName returns all committed definitions
+	QueryChaincodeDefinitionsFuncName = "QueryChaincodeDefinitions"
+
+	allowedCharsChaincodeName = "[A-Za-z0-9_-]+"
+	allowedCharsVersion       = "[A-Za-z0-9_.+-]+"
+)
+
+var (
+	chaincodeNameRegExp = regexp.MustCompile("^" + allowedCharsChaincodeName + "$")
+	versionRegExp       = regexp.MustCompile("^" + allowedCharsVersion + "$")
+)
+
+// Support contains functions that the lifecycle
+// system chaincode requires to execute its tasks
+type Support interface {
+	// GetMSPIDs returns the IDs of the application
+	// organizations of the supplied channel
+	GetMSPIDs(channel string) []string
+
+	// GetLocalMSPID returns the ID of the organization
+	// this peer belongs to
+	GetLocalMSPID() (string, error)
+}
+
+// SCC implements the decentralized chaincode lifecycle
+type SCC struct {
+	// policyChecker is the interface used to perform
+	// access control
+	policyChecker policy.PolicyChecker
+
+	// support provides the implementation of several
+	// static functions
+	support Support
+}
+
+// New returns a new instance of the lifecycle system chaincode
+func New() *SCC {
+	return &SCC{support: &supportImpl{}}
+}
+
+//Init only initializes the policy checker
+func (scc *SCC) Init(stub shim.ChaincodeStubInterface) pb.Response {
+	scc.policyChecker = policyprovider.GetPolicyChecker()
+	return shim.Success(nil)
+}
+
+// Invoke dispatches to the lifecycle functions. Each function takes
+// a single argument, the marshaled arguments message of the function,
+// and returns its marshaled result message.
+func (scc *SCC) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
+	args := stub.GetArgs()
+	if len(args) != 2 {
+		return shim.Error(InvalidArgsLenErr(len(args)).Error())
+	}
+
+	function := string(args[0])
+	channel := stub.GetChannelID()
+	if channel == "" {
+		return shim.Error(fmt.Sprintf("%s must be invoked on a channel", function))
+	}
+
+	sp, err := stub.GetSignedProposal()
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed retrieving signed proposal on executing %s with error %s", function, err))
+	}
+
+	var resource string
+	switch function {
+	case ApproveFuncName:
+		resource = resources.Lifecycle_ApproveChaincodeDefinitionForMyOrg
+	case CheckCommitReadinessFuncName:
+		resource = resources.Lifecycle_CheckCommitReadiness
+	case CommitFuncName:
+		resource = resources.Lifecycle_CommitChaincodeDefinition
+	case QueryChaincodeDefinitionFuncName:
+		resource = resources.Lifecycle_QueryChaincodeDefinition
+	case QueryChaincodeDefinitionsFuncName:
+		resource = resources.Lifecycle_QueryChaincodeDefinitions
+	default:
+		return shim.Error(InvalidFunctionErr(function).Error())
+	}
+	if err = aclmgmt.GetACLProvider().CheckACL(resource, channel, sp); err != nil {
+		return shim.Error(fmt.Sprintf("Authorization request for [%s] on channel [%s] failed: %s", function, channel, err))
+	}
+
+	var result proto.Message
+	switch function {
+	case ApproveFuncName:
+		// only the admins of the peer's org may approve on its behalf
+		if err = scc.policyChecker.CheckPolicyNoChannel(mgmt.Admins, sp); err != nil {
+			return shim.Error(fmt.Sprintf("Authorization for %s has been denied (error-%s)", function, err))
+		}
+		input := &lb.ApproveChaincodeDefinitionForMyOrgArgs{}
+		if err = proto.Unmarshal(args[1], input); err != nil {
+			return shim.Error(fmt.Sprintf("failed to decode input arg to %s: %s", function, err))
+		}
+		err = scc.approve(stub, channel, input.Name, input.Definition)
+	case CheckCommitReadinessFuncName:
+		input := &lb.CheckCommitReadinessArgs{}
+		if err = proto.Unmarshal(args[1], input); err != nil {
+			return shim.Error(fmt.Sprintf("failed to decode input arg to %s: %s", function, err))
+		}
+		result, err = scc.checkCommitReadiness(stub, channel, input.Name, input.Definition)
+	case CommitFuncName:
+		input := &lb.CommitChaincodeDefinitionArgs{}
+		if err = proto.Unmarshal(args[1], input); err != nil {
+			return shim.Error(fmt.Sprintf("failed to decode input arg to %s: %s", function, err))
+		}
+		err = scc.commit(stub, channel, input.Name, input.Definition)
+	case QueryChaincodeDefinitionFuncName:
+		input := &lb.QueryChaincodeDefinitionArgs{}
+		if err = proto.Unmarshal(args[1], input); err != nil {
+			return shim.Error(fmt.Sprintf("failed to decode input arg to %s: %s", function, err))
+		}
+		result, err = scc.queryDefinition(stub, channel, input.Name)
+	case QueryChaincodeDefinitionsFuncName:
+		result, err = scc.queryDefinitions(stub)
+	}
+	if err != nil {
+		return shim.Error(fmt.Sprintf("failed to invoke %s: %s", function, err))
+	}
+
+	if result == nil {
+		return shim.Success(nil)
+	}
+	resultBytes, err := proto.Marshal(result)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("failed to marshal result of %s: %s", function, err))
+	}
+	return shim.Success(resultBytes)
+}
+
+// approve records the approval of the peer's org for the supplied definition
+func (scc *SCC) approve(stub shim.ChaincodeStubInterface, channel, name string, cd *lb.ChaincodeDefinition) error {
+	cd, err := scc.prepareDefinition(stub, channel, name, cd)
+	if err != nil {
+		return err
+	}
+
+	org, err := scc.support.GetLocalMSPID()
+	if err != nil {
+		return err
+	}
+
+	cdBytes, err := proto.Marshal(cd)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal chaincode definition")
+	}
+
+	logger.Infof("Org %s approving definition of %s at sequence %d on channel %s", org, name, cd.Sequence, channel)
+	return stub.PutState(lifecycle.ApprovalKey(name, cd.Sequence, org), cdBytes)
+}
+
+// checkCommitReadiness reports which orgs of the channel approved the supplied definition
+func (scc *SCC) checkCommitReadiness(stub shim.ChaincodeStubInterface, channel, name string, cd *lb.ChaincodeDefinition) (*lb.CheckCommitReadinessResult, error) {
+	cd, err := scc.prepareDefinition(stub, channel, name, cd)
+	if err != nil {
+		return nil, err
+	}
+
+	approvals, err := scc.approvals(stub, channel, name, cd)
+	if err != nil {
+		return nil, err
+	}
+	return &lb.CheckCommitReadinessResult{Approvals: approvals}, nil
+}
+
+// commit writes the supplied definition to the channel. The peer only
+// endorses the commit if its own org approved the definition; whether
+// enough orgs agreed is enforced at validation time by the lifecycle
+// endorsement policy of the channel.
+func (scc *SCC) commit(stub shim.ChaincodeStubInterface, channel, name string, cd *lb.ChaincodeDefinition) error {
+	cd, err := scc.prepareDefinition(stub, channel, name, cd)
+	if err != nil {
+		return err
+	}
+
+	org, err := scc.support.GetLocalMSPID()
+	if err != nil {
+		return err
+	}
+
+	// reading every org's approval places them in the read set, so that
+	// the commit is invalidated if any of them changes before it commits
+	approvals, err := scc.approvals(stub, channel, name, cd)
+	if err != nil {
+		return err
+	}
+	if !approvals[org] {
+		return NotApprovedErr(org)
+	}
+
+	cdBytes, err := proto.Marshal(cd)
+	if err != nil {
+		return errors.Wrap(err, "could not marshal chaincode definition")
+	}
+
+	logger.Infof("Committing definition of %s at sequence %d on channel %s", name, cd.Sequence, channel)
+	return stub.PutState(lifecycle.DefinitionKey(name), cdBytes)
+}
+
+// queryDefinition returns the committed definition of a chaincode along
+// with the orgs that approved it
+func (scc *SCC) queryDefinition(stub shim.ChaincodeStubInterface, channel, name string) (*lb.QueryChaincodeDefinitionResult, error) {
+	cd, err := scc.committedDefinition(stub, name)
+	if err != nil {
+		return nil, err
+	}
+	if cd == nil {
+		return nil, NotDefinedErr(name)
+	}
+
+	approvals, err := scc.approvals(stub, channel, name, cd)
+	if err != nil {
+		return nil, err
+	}
+	return &lb.QueryChaincodeDefinitionResult{Definition: cd, Approvals: approvals}, nil
+}
+
+// queryDefinitions returns all committed definitions of the channel
+func (scc *SCC) queryDefinitions(stub shim.ChaincodeStubInterface) (*lb.QueryChaincodeDefinitionsResult, error) {
+	startKey, endKey := lifecycle.DefinitionKeyRange()
+	itr, err := stub.GetStateByRange(startKey, endKey)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not query chaincode definitions")
+	}
+	defer itr.Close()
+
+	result := &lb.QueryChaincodeDefinitionsResult{}
+	for itr.HasNext() {
+		kv, err := itr.Next()
+		if err != nil {
+			return nil, errors.WithMessage(err, "could not query chaincode definitions")
+		}
+		name, err := lifecycle.ParseDefinitionKey(kv.Key)
+		if err != nil {
+			return nil, err
+		}
+		cd := &lb.ChaincodeDefinition{}
+		if err = proto.Unmarshal(kv.Value, cd); err != nil {
+			return nil, errors.Wrapf(err, "could not unmarshal definition of %s", name)
+		}
+		result.ChaincodeDefinitions = append(result.ChaincodeDefinitions, &lb.NamedChaincodeDefinition{Name: name, Definition: cd})
+	}
+	return result, nil
+}
+
+// prepareDefinition validates the supplied definition, checks that its
+// sequence follows the committed one and fills in the defaults, so that
+// every org approves and commits the same definition
+func (scc *SCC) prepareDefinition(stub shim.ChaincodeStubInterface, channel, name string, cd *lb.ChaincodeDefinition) (*lb.ChaincodeDefinition, error) {
+	if !chaincodeNameRegExp.MatchString(name) {
+		return nil, errors.Errorf("invalid chaincode name '%s'. Names can only consist of alphanumerics, '_', and '-'", name)
+	}
+	if cd == nil {
+		return nil, errors.Errorf("no chaincode definition supplied for %s", name)
+	}
+	if !versionRegExp.MatchString(cd.Version) {
+		return nil, errors.Errorf("invalid chaincode version '%s'. Versions can only consist of alphanumerics, '_', '-', '+', and '.'", cd.Version)
+	}
+
+	committed, err := scc.committedDefinition(stub, name)
+	if err != nil {
+		return nil, err
+	}
+	expected := int64(1)
+	if committed != nil {
+		expected = committed.Sequence + 1
+	}
+	if cd.Sequence != expected {
+		return nil, InvalidSequenceErr{Name: name, Sequence: cd.Sequence, Expected: expected}
+	}
+
+	cd = proto.Clone(cd).(*lb.ChaincodeDefinition)
+	if cd.EndorsementPlugin == "" {
+		cd.EndorsementPlugin = library.DefaultEndorsementPlugin
+	}
+	if cd.ValidationPlugin == "" {
+		cd.ValidationPlugin = library.DefaultValidationPlugin
+	}
+	if len(cd.ValidationParameter) == 0 {
+		cd.ValidationParameter, err = utils.Marshal(cauthdsl.SignedByAnyMember(scc.support.GetMSPIDs(channel)))
+		if err != nil {
+			return nil, errors.Wrap(err, "could not marshal default endorsement policy")
+		}
+	}
+	return cd, nil
+}
+
+// committedDefinition returns the committed definition of
+// the given chaincode, or nil if there is none
+func (scc *SCC) committedDefinition(stub shim.ChaincodeStubInterface, name string) (*lb.ChaincodeDefinition, error) {
+	cdBytes, err := stub.GetState(lifecycle.DefinitionKey(name))
+	if err != nil {
+		return nil, errors.WithMessage(err, fmt.Sprintf("could not get definition of %s", name))
+	}
+	if cdBytes == nil {
+		return nil, nil
+	}
+	cd := &lb.ChaincodeDefinition{}
+	if err = proto.Unmarshal(cdBytes, cd); err != nil {
+		return nil, errors.Wrapf(err, "could not unmarshal definition of %s", name)
+	}
+	return cd, nil
+}
+
+// approvals reports, for each org of the channel, whether it approved
+// exactly the supplied definition
+func (scc *SCC) approvals(stub shim.ChaincodeStubInterface, channel, name string, cd *lb.ChaincodeDefinition) (map[string]bool, error) {
+	approvals := map[string]bool{}
+	for _, org := range scc.support.GetMSPIDs(channel) {
+		approvalBytes, err := stub.GetState(lifecycle.ApprovalKey(name, cd.Sequence, org))
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("could not get approval of %s", org))
+		}
+		approved := &lb.ChaincodeDefinition{}
+		if approvalBytes != nil {
+			if err = proto.Unmarshal(approvalBytes, approved); err != nil {
+				return nil, errors.Wrapf(err, "could not unmarshal approval of %s", org)
+			}
+		}
+		approvals[org] = approvalBytes != nil && proto.Equal(approved, cd)
+	}
+	return approvals, nil
+}
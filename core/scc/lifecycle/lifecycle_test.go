@@ -0,0 +1,199 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle
+
+import (
+	"os"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/core/aclmgmt"
+	"github.com/hyperledger/fabric/core/aclmgmt/mocks"
+	"github.com/hyperledger/fabric/core/aclmgmt/resources"
+	"github.com/hyperledger/fabric/core/chaincode/shim"
+	"github.com/hyperledger/fabric/core/common/lifecycle"
+	"github.com/hyperledger/fabric/core/policy"
+	policymocks "github.com/hyperledger/fabric/core/policy/mocks"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type mockSupport struct {
+	mspIDs     []string
+	localMSPID string
+}
+
+func (s *mockSupport) GetMSPIDs(channel string) []string {
+	return s.mspIDs
+}
+
+func (s *mockSupport) GetLocalMSPID() (string, error) {
+	if s.localMSPID == "" {
+		return "", errors.New("no local MSP")
+	}
+	return s.localMSPID, nil
+}
+
+var (
+	mockAclProvider      *mocks.MockACLProvider
+	identityDeserializer *policymocks.MockIdentityDeserializer
+)
+
+func TestMain(m *testing.M) {
+	mockAclProvider = &mocks.MockACLProvider{}
+	mockAclProvider.Reset()
+	aclmgmt.RegisterACLProvider(mockAclProvider)
+
+	os.Exit(m.Run())
+}
+
+func newTestSCC(t *testing.T) (*SCC, *mockSupport, *shim.MockStub) {
+	support := &mockSupport{mspIDs: []string{"org1", "org2", "org3"}, localMSPID: "org1"}
+	scc := &SCC{support: support}
+	stub := shim.NewMockStub(lifecycle.Namespace, scc)
+	stub.ChannelID = "testchannel"
+	res := stub.MockInit("1", nil)
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+
+	identityDeserializer = &policymocks.MockIdentityDeserializer{Identity: []byte("Alice"), Msg: []byte("msg1")}
+	scc.policyChecker = policy.NewPolicyChecker(
+		&policymocks.MockChannelPolicyManagerGetter{
+			Managers: map[string]policies.Manager{
+				"testchannel": &policymocks.MockChannelPolicyManager{MockPolicy: &policymocks.MockPolicy{Deserializer: identityDeserializer}},
+			},
+		},
+		identityDeserializer,
+		&policymocks.MockMSPPrincipalGetter{Principal: []byte("Alice")},
+	)
+
+	mockAclProvider.Reset()
+	mockAclProvider.On("CheckACL", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	return scc, support, stub
+}
+
+func invoke(t *testing.T, stub *shim.MockStub, caller, function string, input proto.Message) pb.Response {
+	sProp, _ := utils.MockSignedEndorserProposalOrPanic("testchannel", &pb.ChaincodeSpec{}, []byte(caller), []byte("msg1"))
+	sProp.Signature = sProp.ProposalBytes
+	identityDeserializer.Msg = sProp.ProposalBytes
+	return stub.MockInvokeWithSignedProposal("1", [][]byte{[]byte(function), utils.MarshalOrPanic(input)}, sProp)
+}
+
+func definition(sequence int64) *lb.ChaincodeDefinition {
+	return &lb.ChaincodeDefinition{Sequence: sequence, Version: "1.0", InitRequired: true}
+}
+
+func TestApproveAndCommit(t *testing.T) {
+	_, support, stub := newTestSCC(t)
+
+	res := invoke(t, stub, "Alice", ApproveFuncName, &lb.ApproveChaincodeDefinitionForMyOrgArgs{Name: "mycc", Definition: definition(1)})
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	assert.NotNil(t, stub.State[lifecycle.ApprovalKey("mycc", 1, "org1")])
+
+	res = invoke(t, stub, "Alice", CheckCommitReadinessFuncName, &lb.CheckCommitReadinessArgs{Name: "mycc", Definition: definition(1)})
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	readiness := &lb.CheckCommitReadinessResult{}
+	assert.NoError(t, proto.Unmarshal(res.Payload, readiness))
+	assert.Equal(t, map[string]bool{"org1": true, "org2": false, "org3": false}, readiness.Approvals)
+
+	// a different definition is not approved by anyone
+	other := definition(1)
+	other.Version = "2.0"
+	res = invoke(t, stub, "Alice", CheckCommitReadinessFuncName, &lb.CheckCommitReadinessArgs{Name: "mycc", Definition: other})
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	assert.NoError(t, proto.Unmarshal(res.Payload, readiness))
+	assert.Equal(t, map[string]bool{"org1": false, "org2": false, "org3": false}, readiness.Approvals)
+
+	// org2 cannot endorse the commit of a definition it did not approve
+	support.localMSPID = "org2"
+	res = invoke(t, stub, "Alice", CommitFuncName, &lb.CommitChaincodeDefinitionArgs{Name: "mycc", Definition: definition(1)})
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, NotApprovedErr("org2").Error())
+
+	res = invoke(t, stub, "Alice", ApproveFuncName, &lb.ApproveChaincodeDefinitionForMyOrgArgs{Name: "mycc", Definition: definition(1)})
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	res = invoke(t, stub, "Alice", CommitFuncName, &lb.CommitChaincodeDefinitionArgs{Name: "mycc", Definition: definition(1)})
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+
+	res = invoke(t, stub, "Alice", QueryChaincodeDefinitionFuncName, &lb.QueryChaincodeDefinitionArgs{Name: "mycc"})
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	committed := &lb.QueryChaincodeDefinitionResult{}
+	assert.NoError(t, proto.Unmarshal(res.Payload, committed))
+	assert.Equal(t, int64(1), committed.Definition.Sequence)
+	assert.Equal(t, "1.0", committed.Definition.Version)
+	assert.Equal(t, "DefaultEndorsement", committed.Definition.EndorsementPlugin)
+	assert.Equal(t, "DefaultValidation", committed.Definition.ValidationPlugin)
+	assert.NotEmpty(t, committed.Definition.ValidationParameter)
+	assert.True(t, committed.Definition.InitRequired)
+	assert.Equal(t, map[string]bool{"org1": true, "org2": true, "org3": false}, committed.Approvals)
+
+	res = invoke(t, stub, "Alice", QueryChaincodeDefinitionsFuncName, &lb.QueryChaincodeDefinitionsArgs{})
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+	all := &lb.QueryChaincodeDefinitionsResult{}
+	assert.NoError(t, proto.Unmarshal(res.Payload, all))
+	assert.Len(t, all.ChaincodeDefinitions, 1)
+	assert.Equal(t, "mycc", all.ChaincodeDefinitions[0].Name)
+	assert.True(t, proto.Equal(committed.Definition, all.ChaincodeDefinitions[0].Definition))
+
+	// the next definition must have the next sequence
+	res = invoke(t, stub, "Alice", ApproveFuncName, &lb.ApproveChaincodeDefinitionForMyOrgArgs{Name: "mycc", Definition: definition(1)})
+	assert.Equal(t, int32(shim.ERROR), res.Status)
+	assert.Contains(t, res.Message, InvalidSequenceErr{Name: "mycc", Sequence: 1, Expected: 2}.Error())
+	res = invoke(t, stub, "Alice", ApproveFuncName, &lb.ApproveChaincodeDefinitionForMyOrgArgs{Name: "mycc", Definition: definition(2)})
+	assert.Equal(t, int32(shim.OK), res.Status, res.Message)
+}
+
+func TestInvokeErrors(t *testing.T) {
+	_, support, stub := newTestSCC(t)
+
+	res := stub.MockInvoke("1", [][]byte{[]byte(ApproveFuncName)})
+	assert.Equal(t, InvalidArgsLenErr(1).Error(), res.Message)
+
+	res = invoke(t, stub, "Alice", "bogus", &lb.QueryChaincodeDefinitionsArgs{})
+	assert.Equal(t, InvalidFunctionErr("bogus").Error(), res.Message)
+
+	sProp, _ := utils.MockSignedEndorserProposalOrPanic("testchannel", &pb.ChaincodeSpec{}, []byte("Alice"), []byte("msg1"))
+	res = stub.MockInvokeWithSignedProposal("1", [][]byte{[]byte(CheckCommitReadinessFuncName), []byte("garbage")}, sProp)
+	assert.Contains(t, res.Message, "failed to decode input arg")
+
+	res = invoke(t, stub, "Bob", ApproveFuncName, &lb.ApproveChaincodeDefinitionForMyOrgArgs{Name: "mycc", Definition: definition(1)})
+	assert.Contains(t, res.Message, "Authorization for ApproveChaincodeDefinitionForMyOrg has been denied")
+
+	res = invoke(t, stub, "Alice", ApproveFuncName, &lb.ApproveChaincodeDefinitionForMyOrgArgs{Name: "my.cc", Definition: definition(1)})
+	assert.Contains(t, res.Message, "invalid chaincode name 'my.cc'")
+
+	res = invoke(t, stub, "Alice", ApproveFuncName, &lb.ApproveChaincodeDefinitionForMyOrgArgs{Name: "mycc"})
+	assert.Contains(t, res.Message, "no chaincode definition supplied for mycc")
+
+	badVersion := definition(1)
+	badVersion.Version = "1{}0"
+	res = invoke(t, stub, "Alice", ApproveFuncName, &lb.ApproveChaincodeDefinitionForMyOrgArgs{Name: "mycc", Definition: badVersion})
+	assert.Contains(t, res.Message, "invalid chaincode version '1{}0'")
+
+	res = invoke(t, stub, "Alice", ApproveFuncName, &lb.ApproveChaincodeDefinitionForMyOrgArgs{Name: "mycc", Definition: definition(2)})
+	assert.Contains(t, res.Message, InvalidSequenceErr{Name: "mycc", Sequence: 2, Expected: 1}.Error())
+
+	res = invoke(t, stub, "Alice", QueryChaincodeDefinitionFuncName, &lb.QueryChaincodeDefinitionArgs{Name: "mycc"})
+	assert.Contains(t, res.Message, NotDefinedErr("mycc").Error())
+
+	support.localMSPID = ""
+	res = invoke(t, stub, "Alice", ApproveFuncName, &lb.ApproveChaincodeDefinitionForMyOrgArgs{Name: "mycc", Definition: definition(1)})
+	assert.Contains(t, res.Message, "no local MSP")
+
+	mockAclProvider.Reset()
+	mockAclProvider.On("CheckACL", resources.Lifecycle_QueryChaincodeDefinitions, "testchannel", mock.Anything).Return(errors.New("Failed access control"))
+	res = invoke(t, stub, "Alice", QueryChaincodeDefinitionsFuncName, &lb.QueryChaincodeDefinitionsArgs{})
+	assert.Contains(t, res.Message, "Failed access control")
+
+	stub.ChannelID = ""
+	res = invoke(t, stub, "Alice", QueryChaincodeDefinitionsFuncName, &lb.QueryChaincodeDefinitionsArgs{})
+	assert.Equal(t, "QueryChaincodeDefinitions must be invoked on a channel", res.Message)
+}
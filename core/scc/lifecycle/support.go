@@ -0,0 +1,32 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle
+
+import (
+	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/msp/mgmt"
+	"github.com/pkg/errors"
+)
+
+type supportImpl struct {
+}
+
+// GetMSPIDs returns the IDs of the application
+// organizations of the supplied channel
+func (s *supportImpl) GetMSPIDs(channel string) []string {
+	return peer.GetMSPIDs(channel)
+}
+
+// GetLocalMSPID returns the ID of the organization
+// this peer belongs to
+func (s *supportImpl) GetLocalMSPID() (string, error) {
+	mspID, err := mgmt.GetLocalMSP().GetIdentifier()
+	if err != nil {
+		return "", errors.Wrap(err, "could not get the local MSP ID")
+	}
+	return mspID, nil
+}
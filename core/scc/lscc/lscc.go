@@ -132,14 +132,9 @@ func NewLifeCycleSysCC() *lifeCycleSysCC {
 
 //create the chaincode on the given chain
 func (lscc *lifeCycleSysCC) putChaincodeData(stub shim.ChaincodeStubInterface, cd *ccprovider.ChaincodeData) error {
-	// check that escc and vscc are real system chaincodes
-	if !lscc.sccprovider.IsSysCC(string(cd.Escc)) {
-		return fmt.Errorf("%s is not a valid endorsement system chaincode", string(cd.Escc))
-	}
-	if !lscc.sccprovider.IsSysCC(string(cd.Vscc)) {
-		return fmt.Errorf("%s is not a valid validation system chaincode", string(cd.Vscc))
-	}
-
+	// escc and vscc name the endorsement and validation plugins of the
+	// chaincode; these are resolved by the handlers configuration of each
+	// peer and therefore cannot be checked here
 	cdbytes, err := proto.Marshal(cd)
 	if err != nil {
 		return err
@@ -211,17 +211,11 @@ func TestDeploy(t *testing.T) {
 	stub = shim.NewMockStub("lscc", scc)
 	res = stub.MockInit("1", nil)
 	assert.Equal(t, res.Status, int32(shim.OK), res.Message)
-	scc.sccprovider.(*mscc.MocksccProviderImpl).SysCCMap = map[string]bool{"escc": false}
+	// escc and vscc name endorsement and validation plugins,
+	// which need not be system chaincodes
+	scc.sccprovider.(*mscc.MocksccProviderImpl).SysCCMap = map[string]bool{"vscc": false, "escc": false}
 
-	testDeploy(t, "example02", "1.0", path, false, false, true, "escc is not a valid endorsement system chaincode", scc, stub)
-
-	scc = &lifeCycleSysCC{support: &lscc.MockSupport{}}
-	stub = shim.NewMockStub("lscc", scc)
-	res = stub.MockInit("1", nil)
-	assert.Equal(t, res.Status, int32(shim.OK), res.Message)
-	scc.sccprovider.(*mscc.MocksccProviderImpl).SysCCMap = map[string]bool{"vscc": false, "escc": true}
-
-	testDeploy(t, "example02", "1.0", path, false, false, true, "vscc is not a valid validation system chaincode", scc, stub)
+	testDeploy(t, "example02", "1.0", path, false, false, true, "", scc, stub)
 }
 
 func testDeploy(t *testing.T, ccname string, version string, path string, forceBlankCCName bool, forceBlankVersion bool, install bool, expectedErrorMsg string, scc *lifeCycleSysCC, stub *shim.MockStub) {
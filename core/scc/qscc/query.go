@@ -18,14 +18,21 @@ package qscc
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+
 	"github.com/hyperledger/fabric/common/flogging"
 
 	"github.com/hyperledger/fabric/core/aclmgmt"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/util"
 	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
 )
@@ -35,6 +42,11 @@ import (
 // - GetBlockByNumber returns a block
 // - GetBlockByHash returns a block
 // - GetTransactionByID returns a transaction
+// - GetBlocksByRange returns a range of blocks
+// - GetBlockHeadersByRange returns the headers of a range of blocks
+// - GetTransactionsInfoByBlockNumber returns the txIDs and validation codes of a block
+// - GetTransactionsByChaincode returns the transactions of a range of blocks that invoked a chaincode
+// - GetTransactionsByCreator returns the transactions of a range of blocks created by members of an MSP
 type LedgerQuerier struct {
 }
 
@@ -47,8 +59,18 @@ const (
 	GetBlockByHash     string = "GetBlockByHash"
 	GetTransactionByID string = "GetTransactionByID"
 	GetBlockByTxID     string = "GetBlockByTxID"
+	// GetMissingPvtDataInfo lists the private data that is still missing for the most recent blocks
+	GetMissingPvtDataInfo            string = "GetMissingPvtDataInfo"
+	GetBlocksByRange                 string = "GetBlocksByRange"
+	GetBlockHeadersByRange           string = "GetBlockHeadersByRange"
+	GetTransactionsInfoByBlockNumber string = "GetTransactionsInfoByBlockNumber"
+	GetTransactionsByChaincode       string = "GetTransactionsByChaincode"
+	GetTransactionsByCreator         string = "GetTransactionsByCreator"
 )
 
+// MaxBlockRange is the maximum number of blocks a range query can span
+const MaxBlockRange = 100
+
 // Init is called once per chain when the chain is created.
 // This allows the chaincode to initialize any variables on the ledger prior
 // to any transaction execution on the chain.
@@ -65,6 +87,12 @@ func (e *LedgerQuerier) Init(stub shim.ChaincodeStubInterface) pb.Response {
 // # GetBlockByNumber: Return the block specified by block number in args[2]
 // # GetBlockByHash: Return the block specified by block hash in args[2]
 // # GetTransactionByID: Return the transaction specified by ID in args[2]
+// # GetMissingPvtDataInfo: Return the missing private data of at most args[2] most recent blocks
+// # GetBlocksByRange: Return the blocks from number args[2] to number args[3]
+// # GetBlockHeadersByRange: Return the headers of the blocks from number args[2] to number args[3]
+// # GetTransactionsInfoByBlockNumber: Return the txIDs and validation codes of the block specified by number in args[2]
+// # GetTransactionsByChaincode: Return the transactions of the blocks args[2] to args[3] that invoked chaincode args[4]
+// # GetTransactionsByCreator: Return the transactions of the blocks args[2] to args[3] created by members of MSP args[4]
 func (e *LedgerQuerier) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 	args := stub.GetArgs()
 
@@ -77,6 +105,16 @@ func (e *LedgerQuerier) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 	if fname != GetChainInfo && len(args) < 3 {
 		return shim.Error(fmt.Sprintf("missing 3rd argument for %s", fname))
 	}
+	switch fname {
+	case GetBlocksByRange, GetBlockHeadersByRange:
+		if len(args) < 4 {
+			return shim.Error(fmt.Sprintf("missing 4th argument for %s", fname))
+		}
+	case GetTransactionsByChaincode, GetTransactionsByCreator:
+		if len(args) < 5 {
+			return shim.Error(fmt.Sprintf("missing 5th argument for %s", fname))
+		}
+	}
 
 	targetLedger := peer.GetLedger(cid)
 	if targetLedger == nil {
@@ -109,6 +147,24 @@ func (e *LedgerQuerier) Invoke(stub shim.ChaincodeStubInterface) pb.Response {
 		return getChainInfo(targetLedger)
 	case GetBlockByTxID:
 		return getBlockByTxID(targetLedger, args[2])
+	case GetMissingPvtDataInfo:
+		return getMissingPvtDataInfo(targetLedger, args[2])
+	case GetBlocksByRange:
+		return getBlocksByRange(targetLedger, args[2], args[3])
+	case GetBlockHeadersByRange:
+		return getBlockHeadersByRange(targetLedger, args[2], args[3])
+	case GetTransactionsInfoByBlockNumber:
+		return getTransactionsInfoByBlockNumber(targetLedger, args[2])
+	case GetTransactionsByChaincode:
+		chaincode := string(args[4])
+		return getTransactionsByRange(targetLedger, args[2], args[3], func(info *common.TransactionInfo) bool {
+			return info.Chaincode == chaincode
+		})
+	case GetTransactionsByCreator:
+		mspID := string(args[4])
+		return getTransactionsByRange(targetLedger, args[2], args[3], func(info *common.TransactionInfo) bool {
+			return info.CreatorMspId == mspID
+		})
 	}
 
 	return shim.Error(fmt.Sprintf("Requested function %s not found.", fname))
@@ -208,6 +264,228 @@ func getBlockByTxID(vledger ledger.PeerLedger, rawTxID []byte) pb.Response {
 	return shim.Success(bytes)
 }
 
+func getMissingPvtDataInfo(vledger ledger.PeerLedger, maxBlocks []byte) pb.Response {
+	if maxBlocks == nil {
+		return shim.Error("Maximum number of blocks must not be nil.")
+	}
+	max, err := strconv.Atoi(string(maxBlocks))
+	if err != nil || max <= 0 {
+		return shim.Error(fmt.Sprintf("Failed to parse maximum number of blocks %s", string(maxBlocks)))
+	}
+	missingInfo, err := vledger.GetMissingPvtDataInfoForMostRecentBlocks(max)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get missing private data info, error %s", err))
+	}
+
+	blockNums := make([]uint64, 0, len(missingInfo))
+	for blockNum := range missingInfo {
+		blockNums = append(blockNums, blockNum)
+	}
+	// the most recent blocks first
+	sort.Slice(blockNums, func(i, j int) bool { return blockNums[i] > blockNums[j] })
+
+	info := &common.MissingPvtDataInfo{}
+	for _, blockNum := range blockNums {
+		for _, missing := range missingInfo[blockNum] {
+			info.Entries = append(info.Entries, &common.MissingPvtDataEntry{
+				BlockNum:   blockNum,
+				TxNum:      uint64(missing.SeqInBlock),
+				TxId:       missing.TxId,
+				Namespace:  missing.Namespace,
+				Collection: missing.Collection,
+			})
+		}
+	}
+
+	bytes, err := utils.Marshal(info)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(bytes)
+}
+
+// parseBlockRange parses the numbers of the first and the last block of a range.
+// The range must start below the height of the ledger and span at most MaxBlockRange
+// blocks; it is truncated to the last block of the ledger.
+func parseBlockRange(vledger ledger.PeerLedger, startNumber, endNumber []byte) (uint64, uint64, error) {
+	if startNumber == nil || endNumber == nil {
+		return 0, 0, errors.New("Block numbers must not be nil.")
+	}
+	start, err := strconv.ParseUint(string(startNumber), 10, 64)
+	if err != nil {
+		return 0, 0, errors.Errorf("Failed to parse start block number with error %s", err)
+	}
+	end, err := strconv.ParseUint(string(endNumber), 10, 64)
+	if err != nil {
+		return 0, 0, errors.Errorf("Failed to parse end block number with error %s", err)
+	}
+	if end < start {
+		return 0, 0, errors.Errorf("Invalid block range, end block number %d is lower than start block number %d", end, start)
+	}
+	if end-start >= MaxBlockRange {
+		return 0, 0, errors.Errorf("Invalid block range, it spans more than %d blocks", MaxBlockRange)
+	}
+
+	binfo, err := vledger.GetBlockchainInfo()
+	if err != nil {
+		return 0, 0, errors.Errorf("Failed to get block info with error %s", err)
+	}
+	if start >= binfo.Height {
+		return 0, 0, errors.Errorf("Invalid block range, start block number %d is not lower than the ledger height %d", start, binfo.Height)
+	}
+	if end >= binfo.Height {
+		end = binfo.Height - 1
+	}
+	return start, end, nil
+}
+
+func getBlocksByRange(vledger ledger.PeerLedger, startNumber, endNumber []byte) pb.Response {
+	start, end, err := parseBlockRange(vledger, startNumber, endNumber)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	blocks := &common.BlockList{}
+	for bnum := start; bnum <= end; bnum++ {
+		block, err := vledger.GetBlockByNumber(bnum)
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to get block number %d, error %s", bnum, err))
+		}
+		blocks.Blocks = append(blocks.Blocks, block)
+	}
+
+	bytes, err := utils.Marshal(blocks)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(bytes)
+}
+
+func getBlockHeadersByRange(vledger ledger.PeerLedger, startNumber, endNumber []byte) pb.Response {
+	start, end, err := parseBlockRange(vledger, startNumber, endNumber)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	headers := &common.BlockHeaderList{}
+	for bnum := start; bnum <= end; bnum++ {
+		block, err := vledger.GetBlockByNumber(bnum)
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to get block number %d, error %s", bnum, err))
+		}
+		headers.Headers = append(headers.Headers, block.Header)
+	}
+
+	bytes, err := utils.Marshal(headers)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(bytes)
+}
+
+func getTransactionsInfoByBlockNumber(vledger ledger.PeerLedger, number []byte) pb.Response {
+	if number == nil {
+		return shim.Error("Block number must not be nil.")
+	}
+	bnum, err := strconv.ParseUint(string(number), 10, 64)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to parse block number with error %s", err))
+	}
+	block, err := vledger.GetBlockByNumber(bnum)
+	if err != nil {
+		return shim.Error(fmt.Sprintf("Failed to get block number %d, error %s", bnum, err))
+	}
+
+	bytes, err := utils.Marshal(&common.TransactionInfoList{Transactions: transactionsInfo(block)})
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(bytes)
+}
+
+func getTransactionsByRange(vledger ledger.PeerLedger, startNumber, endNumber []byte, filter func(*common.TransactionInfo) bool) pb.Response {
+	start, end, err := parseBlockRange(vledger, startNumber, endNumber)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	txs := &common.TransactionInfoList{}
+	for bnum := start; bnum <= end; bnum++ {
+		block, err := vledger.GetBlockByNumber(bnum)
+		if err != nil {
+			return shim.Error(fmt.Sprintf("Failed to get block number %d, error %s", bnum, err))
+		}
+		for _, info := range transactionsInfo(block) {
+			if filter(info) {
+				txs.Transactions = append(txs.Transactions, info)
+			}
+		}
+	}
+
+	bytes, err := utils.Marshal(txs)
+	if err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(bytes)
+}
+
+// transactionsInfo summarizes the transactions of a committed block. The summary
+// of a transaction that cannot be parsed contains only its position and validation code.
+func transactionsInfo(block *common.Block) []*common.TransactionInfo {
+	var txsFilter util.TxValidationFlags
+	if block.Metadata != nil && len(block.Metadata.Metadata) > int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+		txsFilter = util.TxValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+	}
+	if len(txsFilter) != len(block.Data.Data) {
+		txsFilter = util.NewTxValidationFlags(len(block.Data.Data))
+	}
+
+	infos := make([]*common.TransactionInfo, len(block.Data.Data))
+	for txNum, envBytes := range block.Data.Data {
+		info := &common.TransactionInfo{
+			BlockNum:       block.Header.Number,
+			TxNum:          uint64(txNum),
+			ValidationCode: int32(txsFilter.Flag(txNum)),
+		}
+		infos[txNum] = info
+
+		env, err := utils.GetEnvelopeFromBlock(envBytes)
+		if err != nil {
+			qscclogger.Warningf("Failed to get envelope of transaction %d of block %d: %s", txNum, block.Header.Number, err)
+			continue
+		}
+		payload, err := utils.GetPayload(env)
+		if err != nil || payload.Header == nil {
+			qscclogger.Warningf("Failed to get payload of transaction %d of block %d: %s", txNum, block.Header.Number, err)
+			continue
+		}
+		chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		if err != nil {
+			qscclogger.Warningf("Failed to get channel header of transaction %d of block %d: %s", txNum, block.Header.Number, err)
+			continue
+		}
+		info.TxId = chdr.TxId
+		info.Type = common.HeaderType(chdr.Type)
+		if info.Type == common.HeaderType_ENDORSER_TRANSACTION {
+			if ext, err := utils.GetChaincodeHeaderExtension(payload.Header); err == nil && ext.ChaincodeId != nil {
+				info.Chaincode = ext.ChaincodeId.Name
+			}
+		}
+		if shdr, err := utils.GetSignatureHeader(payload.Header.SignatureHeader); err == nil {
+			sID := &msp.SerializedIdentity{}
+			if err := proto.Unmarshal(shdr.Creator, sID); err == nil {
+				info.CreatorMspId = sID.Mspid
+			}
+		}
+	}
+	return infos
+}
+
 func getACLResource(fname string) string {
 	return "QSCC." + fname
 }
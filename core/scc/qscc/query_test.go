@@ -20,6 +20,7 @@ import (
 	"os"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/ledger/testutil"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/core/aclmgmt"
@@ -28,6 +29,7 @@ import (
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	ledger2 "github.com/hyperledger/fabric/core/ledger"
 	"github.com/hyperledger/fabric/core/peer"
+	"github.com/hyperledger/fabric/msp/mgmt/testtools"
 	"github.com/hyperledger/fabric/protos/common"
 	peer2 "github.com/hyperledger/fabric/protos/peer"
 	"github.com/hyperledger/fabric/protos/utils"
@@ -165,6 +167,37 @@ func TestQueryGetBlockByTxID(t *testing.T) {
 	assert.Equal(t, int32(shim.ERROR), res.Status, "GetBlockByTxID should have failed with blank txId.")
 }
 
+func TestQueryGetMissingPvtDataInfo(t *testing.T) {
+	chainid := "mytestchainid7"
+	path := "/var/hyperledger/test7/"
+	stub, err := setupTestLedger(chainid, path)
+	defer os.RemoveAll(path)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// the genesis block does not have any missing private data
+	args := [][]byte{[]byte(GetMissingPvtDataInfo), []byte(chainid), []byte("10")}
+	prop := resetProvider(resources.QSCC_GetMissingPvtDataInfo, chainid, &peer2.SignedProposal{}, nil)
+	res := stub.MockInvokeWithSignedProposal("1", args, prop)
+	assert.Equal(t, int32(shim.OK), res.Status, "GetMissingPvtDataInfo failed with err: %s", res.Message)
+	info := &common.MissingPvtDataInfo{}
+	assert.NoError(t, proto.Unmarshal(res.Payload, info))
+	assert.Empty(t, info.Entries)
+
+	args = [][]byte{[]byte(GetMissingPvtDataInfo), []byte(chainid), []byte("abc")}
+	res = stub.MockInvoke("2", args)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetMissingPvtDataInfo should have failed with invalid number of blocks: abc")
+
+	args = [][]byte{[]byte(GetMissingPvtDataInfo), []byte(chainid), []byte("0")}
+	res = stub.MockInvoke("3", args)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetMissingPvtDataInfo should have failed with invalid number of blocks: 0")
+
+	args = [][]byte{[]byte(GetMissingPvtDataInfo), []byte(chainid), []byte(nil)}
+	res = stub.MockInvoke("4", args)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetMissingPvtDataInfo should have failed with nil number of blocks")
+}
+
 func TestFailingAccessControl(t *testing.T) {
 	chainid := "mytestchainid6"
 	path := "/var/hyperledger/test6/"
@@ -309,6 +342,151 @@ func TestQueryGeneratedBlock(t *testing.T) {
 	}
 }
 
+func TestQueryBlockRanges(t *testing.T) {
+	chainid := "mytestchainid9"
+	path := "/var/hyperledger/test9/"
+	stub, err := setupTestLedger(chainid, path)
+	defer os.RemoveAll(path)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	block1 := addBlockForTesting(t, chainid)
+
+	// the range is truncated to the height of the ledger
+	args := [][]byte{[]byte(GetBlocksByRange), []byte(chainid), []byte("0"), []byte("5")}
+	prop := resetProvider(resources.QSCC_GetBlocksByRange, chainid, &peer2.SignedProposal{}, nil)
+	res := stub.MockInvokeWithSignedProposal("1", args, prop)
+	assert.Equal(t, int32(shim.OK), res.Status, "GetBlocksByRange failed with err: %s", res.Message)
+	blocks := &common.BlockList{}
+	assert.NoError(t, proto.Unmarshal(res.Payload, blocks))
+	assert.Len(t, blocks.Blocks, 2)
+	assert.True(t, proto.Equal(block1.Header, blocks.Blocks[1].Header))
+
+	args = [][]byte{[]byte(GetBlockHeadersByRange), []byte(chainid), []byte("1"), []byte("1")}
+	prop = resetProvider(resources.QSCC_GetBlockHeadersByRange, chainid, &peer2.SignedProposal{}, nil)
+	res = stub.MockInvokeWithSignedProposal("2", args, prop)
+	assert.Equal(t, int32(shim.OK), res.Status, "GetBlockHeadersByRange failed with err: %s", res.Message)
+	headers := &common.BlockHeaderList{}
+	assert.NoError(t, proto.Unmarshal(res.Payload, headers))
+	assert.Len(t, headers.Headers, 1)
+	assert.True(t, proto.Equal(block1.Header, headers.Headers[0]))
+
+	prop = resetProvider(resources.QSCC_GetBlocksByRange, chainid, &peer2.SignedProposal{}, nil)
+	for _, tc := range []struct {
+		start, end string
+		msg        string
+	}{
+		{"2", "3", "start block number 2 is not lower than the ledger height 2"},
+		{"1", "0", "end block number 0 is lower than start block number 1"},
+		{"0", "100", fmt.Sprintf("it spans more than %d blocks", MaxBlockRange)},
+		{"a", "1", "Failed to parse start block number"},
+		{"0", "b", "Failed to parse end block number"},
+	} {
+		args = [][]byte{[]byte(GetBlocksByRange), []byte(chainid), []byte(tc.start), []byte(tc.end)}
+		res = stub.MockInvokeWithSignedProposal("3", args, prop)
+		assert.Equal(t, int32(shim.ERROR), res.Status)
+		assert.Contains(t, res.Message, tc.msg)
+	}
+
+	args = [][]byte{[]byte(GetBlockHeadersByRange), []byte(chainid), []byte("0")}
+	prop = resetProvider(resources.QSCC_GetBlockHeadersByRange, chainid, &peer2.SignedProposal{}, nil)
+	res = stub.MockInvokeWithSignedProposal("4", args, prop)
+	assert.Equal(t, "missing 4th argument for GetBlockHeadersByRange", res.Message)
+}
+
+func TestQueryTransactionsInfo(t *testing.T) {
+	chainid := "mytestchainid10"
+	path := "/var/hyperledger/test10/"
+	stub, err := setupTestLedger(chainid, path)
+	defer os.RemoveAll(path)
+	if err != nil {
+		t.Fatalf(err.Error())
+	}
+
+	// the transactions are created by a member of the DEFAULT msp and
+	// the second one is marked as invalid
+	assert.NoError(t, msptesttools.LoadMSPSetupForTesting())
+	bg, _ := testutil.NewBlockGenerator(t, chainid, true)
+	lgr := peer.GetLedger(chainid)
+	var simResBytes [][]byte
+	for i := 0; i < 2; i++ {
+		simulator, _ := lgr.NewTxSimulator(util.GenerateUUID())
+		simulator.SetState("ns1", fmt.Sprintf("key%d", i), []byte("value"))
+		simulator.Done()
+		simRes, _ := simulator.GetTxSimulationResults()
+		pubSimResBytes, _ := simRes.GetPubSimulationBytes()
+		simResBytes = append(simResBytes, pubSimResBytes)
+	}
+	block1 := bg.NextBlock(simResBytes)
+	block1.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER] = []byte{uint8(peer2.TxValidationCode_VALID), uint8(peer2.TxValidationCode_MVCC_READ_CONFLICT)}
+	assert.NoError(t, lgr.CommitWithPvtData(&ledger2.BlockAndPvtData{Block: block1}))
+	lgr.Close()
+
+	var txIDs []string
+	for _, envBytes := range block1.Data.Data {
+		env, err := utils.GetEnvelopeFromBlock(envBytes)
+		assert.NoError(t, err)
+		payload, err := utils.GetPayload(env)
+		assert.NoError(t, err)
+		chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+		assert.NoError(t, err)
+		txIDs = append(txIDs, chdr.TxId)
+	}
+	validationCodes := []peer2.TxValidationCode{peer2.TxValidationCode_VALID, peer2.TxValidationCode_MVCC_READ_CONFLICT}
+
+	args := [][]byte{[]byte(GetTransactionsInfoByBlockNumber), []byte(chainid), []byte("1")}
+	prop := resetProvider(resources.QSCC_GetTransactionsInfoByBlockNumber, chainid, &peer2.SignedProposal{}, nil)
+	res := stub.MockInvokeWithSignedProposal("1", args, prop)
+	assert.Equal(t, int32(shim.OK), res.Status, "GetTransactionsInfoByBlockNumber failed with err: %s", res.Message)
+	txs := &common.TransactionInfoList{}
+	assert.NoError(t, proto.Unmarshal(res.Payload, txs))
+	assert.Len(t, txs.Transactions, 2)
+	for i, tx := range txs.Transactions {
+		assert.Equal(t, uint64(1), tx.BlockNum)
+		assert.Equal(t, uint64(i), tx.TxNum)
+		assert.Equal(t, txIDs[i], tx.TxId)
+		assert.Equal(t, common.HeaderType_ENDORSER_TRANSACTION, tx.Type)
+		assert.Equal(t, "foo", tx.Chaincode)
+		assert.Equal(t, "DEFAULT", tx.CreatorMspId)
+		assert.Equal(t, int32(validationCodes[i]), tx.ValidationCode)
+	}
+
+	args = [][]byte{[]byte(GetTransactionsInfoByBlockNumber), []byte(chainid), []byte("2")}
+	res = stub.MockInvokeWithSignedProposal("2", args, prop)
+	assert.Equal(t, int32(shim.ERROR), res.Status, "GetTransactionsInfoByBlockNumber should have failed with invalid number: 2")
+
+	args = [][]byte{[]byte(GetTransactionsByChaincode), []byte(chainid), []byte("0"), []byte("1"), []byte("foo")}
+	prop = resetProvider(resources.QSCC_GetTransactionsByChaincode, chainid, &peer2.SignedProposal{}, nil)
+	res = stub.MockInvokeWithSignedProposal("3", args, prop)
+	assert.Equal(t, int32(shim.OK), res.Status, "GetTransactionsByChaincode failed with err: %s", res.Message)
+	assert.NoError(t, proto.Unmarshal(res.Payload, txs))
+	assert.Len(t, txs.Transactions, 2)
+
+	args = [][]byte{[]byte(GetTransactionsByChaincode), []byte(chainid), []byte("0"), []byte("1"), []byte("othercc")}
+	res = stub.MockInvokeWithSignedProposal("4", args, prop)
+	assert.Equal(t, int32(shim.OK), res.Status, "GetTransactionsByChaincode failed with err: %s", res.Message)
+	assert.NoError(t, proto.Unmarshal(res.Payload, txs))
+	assert.Empty(t, txs.Transactions)
+
+	args = [][]byte{[]byte(GetTransactionsByCreator), []byte(chainid), []byte("0"), []byte("1"), []byte("DEFAULT")}
+	prop = resetProvider(resources.QSCC_GetTransactionsByCreator, chainid, &peer2.SignedProposal{}, nil)
+	res = stub.MockInvokeWithSignedProposal("5", args, prop)
+	assert.Equal(t, int32(shim.OK), res.Status, "GetTransactionsByCreator failed with err: %s", res.Message)
+	assert.NoError(t, proto.Unmarshal(res.Payload, txs))
+	assert.Len(t, txs.Transactions, 2)
+
+	args = [][]byte{[]byte(GetTransactionsByCreator), []byte(chainid), []byte("0"), []byte("1"), []byte("othermsp")}
+	res = stub.MockInvokeWithSignedProposal("6", args, prop)
+	assert.Equal(t, int32(shim.OK), res.Status, "GetTransactionsByCreator failed with err: %s", res.Message)
+	assert.NoError(t, proto.Unmarshal(res.Payload, txs))
+	assert.Empty(t, txs.Transactions)
+
+	args = [][]byte{[]byte(GetTransactionsByCreator), []byte(chainid), []byte("0"), []byte("1")}
+	res = stub.MockInvokeWithSignedProposal("7", args, prop)
+	assert.Equal(t, "missing 5th argument for GetTransactionsByCreator", res.Message)
+}
+
 func addBlockForTesting(t *testing.T, chainid string) *common.Block {
 	bg, _ := testutil.NewBlockGenerator(t, chainid, false)
 	ledger := peer.GetLedger(chainid)
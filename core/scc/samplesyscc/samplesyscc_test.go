@@ -181,14 +181,30 @@ func (*mockStub) DelState(key string) error {
 	panic("implement me")
 }
 
+func (*mockStub) SetStateValidationParameter(key string, ep []byte) error {
+	panic("implement me")
+}
+
+func (*mockStub) GetStateValidationParameter(key string) ([]byte, error) {
+	panic("implement me")
+}
+
 func (*mockStub) GetStateByRange(startKey, endKey string) (shim.StateQueryIteratorInterface, error) {
 	panic("implement me")
 }
 
+func (*mockStub) GetStateByRangeWithPagination(startKey, endKey string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	panic("implement me")
+}
+
 func (*mockStub) GetStateByPartialCompositeKey(objectType string, keys []string) (shim.StateQueryIteratorInterface, error) {
 	panic("implement me")
 }
 
+func (*mockStub) GetStateByPartialCompositeKeyWithPagination(objectType string, keys []string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	panic("implement me")
+}
+
 func (*mockStub) CreateCompositeKey(objectType string, attributes []string) (string, error) {
 	panic("implement me")
 }
@@ -201,6 +217,10 @@ func (*mockStub) GetQueryResult(query string) (shim.StateQueryIteratorInterface,
 	panic("implement me")
 }
 
+func (*mockStub) GetQueryResultWithPagination(query string, pageSize int32, bookmark string) (shim.StateQueryIteratorInterface, *peer.QueryResponseMetadata, error) {
+	panic("implement me")
+}
+
 func (*mockStub) GetHistoryForKey(key string) (shim.HistoryQueryIteratorInterface, error) {
 	panic("implement me")
 }
@@ -247,6 +267,14 @@ func (stub *mockStub) DelPrivateData(collection string, key string) error {
 	panic("implement me")
 }
 
+func (stub *mockStub) SetPrivateDataValidationParameter(collection, key string, ep []byte) error {
+	panic("implement me")
+}
+
+func (stub *mockStub) GetPrivateDataValidationParameter(collection, key string) ([]byte, error) {
+	panic("implement me")
+}
+
 func (stub *mockStub) GetPrivateDataByRange(collection, startKey, endKey string) (shim.StateQueryIteratorInterface, error) {
 	panic("implement me")
 }
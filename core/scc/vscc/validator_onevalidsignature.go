@@ -22,15 +22,14 @@ import (
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/cauthdsl"
-	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/common/privdata"
 	"github.com/hyperledger/fabric/core/common/sysccprovider"
+	"github.com/hyperledger/fabric/core/handlers/validation/api/capabilities"
 	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
 	"github.com/hyperledger/fabric/core/scc/lscc"
-	m "github.com/hyperledger/fabric/msp"
 	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
 	"github.com/hyperledger/fabric/protos/common"
 	"github.com/hyperledger/fabric/protos/ledger/rwset/kvrwset"
@@ -46,38 +45,82 @@ const (
 	DUPLICATED_IDENTITY_ERROR = "Endorsement policy evaluation failure might be caused by duplicated identities"
 )
 
+// Support provides the ledger state, the evaluation of policies and
+// the channel capabilities that the validation logic depends on
+type Support interface {
+	// GetState returns the value of the given key in the
+	// given namespace from the ledger of the given channel
+	GetState(chainID, namespace, key string) ([]byte, error)
+
+	// EvaluatePolicy evaluates the given serialized policy against
+	// the given signature set, using the MSPs of the given channel
+	EvaluatePolicy(chainID string, policyBytes []byte, signatureSet []*common.SignedData) error
+
+	// Capabilities returns the capabilities of the given channel,
+	// and whether the application config of the channel exists
+	Capabilities(chainID string) (capabilities.Capabilities, bool)
+}
+
 // ValidatorOneValidSignature implements the default transaction validation policy,
 // which is to check the correctness of the read-write set and the endorsement
 // signatures against an endorsement policy that is supplied as argument to
 // every invoke
 type ValidatorOneValidSignature struct {
-	// sccprovider is the interface with which we call
-	// methods of the system chaincode package without
-	// import cycles
-	sccprovider sysccprovider.SystemChaincodeProvider
-
-	// collectionStore provides support to retrieve
-	// collections from the ledger
-	collectionStore privdata.CollectionStore
+	// support provides access to the ledger, the
+	// policies and the capabilities of the channel
+	support Support
 }
 
-// collectionStoreSupport implements privdata.Support
-type collectionStoreSupport struct {
+// New returns a ValidatorOneValidSignature that validates transactions
+// outside of a chaincode invocation, relying on the given Support
+func New(support Support) *ValidatorOneValidSignature {
+	return &ValidatorOneValidSignature{support: support}
+}
+
+// sccSupport implements Support on top of the
+// system chaincode provider and the MSP managers
+type sccSupport struct {
 	sysccprovider.SystemChaincodeProvider
 }
 
-func (c *collectionStoreSupport) GetCollectionKVSKey(cc common.CollectionCriteria) string {
-	return privdata.BuildCollectionKVSKey(cc.Namespace)
+func (s *sccSupport) GetState(chainID, namespace, key string) ([]byte, error) {
+	qe, err := s.GetQueryExecutorForLedger(chainID)
+	if err != nil {
+		return nil, fmt.Errorf("Could not retrieve QueryExecutor for channel %s, error %s", chainID, err)
+	}
+	defer qe.Done()
+
+	return qe.GetState(namespace, key)
 }
 
-func (c *collectionStoreSupport) GetIdentityDeserializer(chainID string) m.IdentityDeserializer {
-	return mspmgmt.GetIdentityDeserializer(chainID)
+func (s *sccSupport) EvaluatePolicy(chainID string, policyBytes []byte, signatureSet []*common.SignedData) error {
+	mgr := mspmgmt.GetManagerForChain(chainID)
+	if mgr == nil {
+		return fmt.Errorf("MSP manager for channel %s is nil, aborting", chainID)
+	}
+
+	pProvider := cauthdsl.NewPolicyProvider(mgr)
+	policy, _, err := pProvider.NewPolicy(policyBytes)
+	if err != nil {
+		return err
+	}
+
+	return policy.Evaluate(signatureSet)
+}
+
+func (s *sccSupport) Capabilities(chainID string) (capabilities.Capabilities, bool) {
+	ac, exists := s.GetApplicationConfig(chainID)
+	if !exists {
+		return nil, false
+	}
+	return ac.Capabilities(), true
 }
 
 // Init is called once when the chaincode started the first time
 func (vscc *ValidatorOneValidSignature) Init(stub shim.ChaincodeStubInterface) pb.Response {
-	vscc.sccprovider = sysccprovider.GetSystemChaincodeProvider()
-	vscc.collectionStore = privdata.NewSimpleCollectionStore(&collectionStoreSupport{vscc.sccprovider})
+	// the system chaincode provider is the interface with which we
+	// call methods of the system chaincode package without import cycles
+	vscc.support = &sccSupport{sysccprovider.GetSystemChaincodeProvider()}
 
 	return shim.Success(nil)
 }
@@ -110,52 +153,54 @@ func (vscc *ValidatorOneValidSignature) Invoke(stub shim.ChaincodeStubInterface)
 
 	logger.Debugf("VSCC invoked")
 
+	if err := vscc.Validate(args[1], args[2]); err != nil {
+		return shim.Error(err.Error())
+	}
+
+	return shim.Success(nil)
+}
+
+// Validate checks that the transaction in the supplied envelope contains
+// endorsements that comply with the supplied endorsement policy, and
+// performs the additional checks required by invocations of lscc
+func (vscc *ValidatorOneValidSignature) Validate(envBytes []byte, policyBytes []byte) error {
 	// get the envelope...
-	env, err := utils.GetEnvelopeFromBlock(args[1])
+	env, err := utils.GetEnvelopeFromBlock(envBytes)
 	if err != nil {
 		logger.Errorf("VSCC error: GetEnvelope failed, err %s", err)
-		return shim.Error(err.Error())
+		return err
 	}
 
 	// ...and the payload...
 	payl, err := utils.GetPayload(env)
 	if err != nil {
 		logger.Errorf("VSCC error: GetPayload failed, err %s", err)
-		return shim.Error(err.Error())
+		return err
 	}
 
 	chdr, err := utils.UnmarshalChannelHeader(payl.Header.ChannelHeader)
 	if err != nil {
-		return shim.Error(err.Error())
+		return err
 	}
 
-	ac, exists := vscc.sccprovider.GetApplicationConfig(chdr.ChannelId)
+	ac, exists := vscc.support.Capabilities(chdr.ChannelId)
 	if !exists {
-		err = errors.Wrap(err, "failure while unmarshalling VSCCArgs")
+		err = errors.Errorf("could not retrieve the application config of channel %s", chdr.ChannelId)
 		logger.Errorf(err.Error())
-		return shim.Error(err.Error())
-	}
-
-	// get the policy
-	mgr := mspmgmt.GetManagerForChain(chdr.ChannelId)
-	pProvider := cauthdsl.NewPolicyProvider(mgr)
-	policy, _, err := pProvider.NewPolicy(args[2])
-	if err != nil {
-		logger.Errorf("VSCC error: pProvider.NewPolicy failed, err %s", err)
-		return shim.Error(err.Error())
+		return err
 	}
 
 	// validate the payload type
 	if common.HeaderType(chdr.Type) != common.HeaderType_ENDORSER_TRANSACTION {
 		logger.Errorf("Only Endorser Transactions are supported, provided type %d", chdr.Type)
-		return shim.Error(fmt.Sprintf("Only Endorser Transactions are supported, provided type %d", chdr.Type))
+		return fmt.Errorf("Only Endorser Transactions are supported, provided type %d", chdr.Type)
 	}
 
 	// ...and the transaction...
 	tx, err := utils.GetTransaction(payl.Data)
 	if err != nil {
 		logger.Errorf("VSCC error: GetTransaction failed, err %s", err)
-		return shim.Error(err.Error())
+		return err
 	}
 
 	// loop through each of the actions within
@@ -163,63 +208,51 @@ func (vscc *ValidatorOneValidSignature) Invoke(stub shim.ChaincodeStubInterface)
 		cap, err := utils.GetChaincodeActionPayload(act.Payload)
 		if err != nil {
 			logger.Errorf("VSCC error: GetChaincodeActionPayload failed, err %s", err)
-			return shim.Error(err.Error())
+			return err
 		}
 
 		signatureSet, err := vscc.deduplicateIdentity(cap)
 		if err != nil {
-			return shim.Error(err.Error())
+			return err
 		}
 
 		// evaluate the signature set against the policy
-		err = policy.Evaluate(signatureSet)
+		err = vscc.support.EvaluatePolicy(chdr.ChannelId, policyBytes, signatureSet)
 		if err != nil {
 			logger.Warningf("Endorsement policy failure for transaction txid=%s, err: %s", chdr.GetTxId(), err.Error())
 			if len(signatureSet) < len(cap.Action.Endorsements) {
 				// Warning: duplicated identities exist, endorsement failure might be cause by this reason
-				return shim.Error(DUPLICATED_IDENTITY_ERROR)
+				return errors.New(DUPLICATED_IDENTITY_ERROR)
 			}
-			return shim.Error(fmt.Sprintf("VSCC error: endorsement policy failure, err: %s", err))
+			return fmt.Errorf("VSCC error: endorsement policy failure, err: %s", err)
 		}
 
 		hdrExt, err := utils.GetChaincodeHeaderExtension(payl.Header)
 		if err != nil {
 			logger.Errorf("VSCC error: GetChaincodeHeaderExtension failed, err %s", err)
-			return shim.Error(err.Error())
+			return err
 		}
 
 		// do some extra validation that is specific to lscc
 		if hdrExt.ChaincodeId.Name == "lscc" {
 			logger.Debugf("VSCC info: doing special validation for LSCC")
 
-			err = vscc.ValidateLSCCInvocation(stub, chdr.ChannelId, env, cap, payl, ac.Capabilities())
+			err = vscc.ValidateLSCCInvocation(chdr.ChannelId, env, cap, payl, ac)
 			if err != nil {
 				logger.Errorf("VSCC error: ValidateLSCCInvocation failed, err %s", err)
-				return shim.Error(err.Error())
+				return err
 			}
 		}
 	}
 
 	logger.Debugf("VSCC exists successfully")
 
-	return shim.Success(nil)
+	return nil
 }
 
 // checkInstantiationPolicy evaluates an instantiation policy against a signed proposal
 func (vscc *ValidatorOneValidSignature) checkInstantiationPolicy(chainName string, env *common.Envelope, instantiationPolicy []byte, payl *common.Payload) error {
-	// create a policy object from the policy bytes
-	mgr := mspmgmt.GetManagerForChain(chainName)
-	if mgr == nil {
-		return fmt.Errorf("MSP manager for channel %s is nil, aborting", chainName)
-	}
-
-	npp := cauthdsl.NewPolicyProvider(mgr)
-	instPol, _, err := npp.NewPolicy(instantiationPolicy)
-	if err != nil {
-		return err
-	}
-
-	logger.Debugf("VSCC info: checkInstantiationPolicy starts, policy is %#v", instPol)
+	logger.Debugf("VSCC info: checkInstantiationPolicy starts, policy is %x", instantiationPolicy)
 
 	// get the signature header
 	shdr, err := utils.GetSignatureHeader(payl.Header.SignatureHeader)
@@ -233,7 +266,7 @@ func (vscc *ValidatorOneValidSignature) checkInstantiationPolicy(chainName strin
 		Identity:  shdr.Creator,
 		Signature: env.Signature,
 	}}
-	err = instPol.Evaluate(sd)
+	err = vscc.support.EvaluatePolicy(chainName, instantiationPolicy, sd)
 	if err != nil {
 		return fmt.Errorf("chaincode instantiation policy violated, error %s", err)
 	}
@@ -281,15 +314,10 @@ func (vscc *ValidatorOneValidSignature) validateDeployRWSetAndCollection(
 			cdRWSet.Name, cdRWSet.Version)
 	}
 
-	ccp, err := vscc.collectionStore.RetrieveCollectionConfigPackage(common.CollectionCriteria{Channel: chid, Namespace: ccid})
+	ccp, err := vscc.support.GetState(chid, "lscc", privdata.BuildCollectionKVSKey(ccid))
 	if err != nil {
-		// fail if we get any error other than NoSuchCollectionError
-		// because it means something went wrong while looking up the
-		// older collection
-		if _, ok := err.(privdata.NoSuchCollectionError); !ok {
-			return errors.WithMessage(err, fmt.Sprintf("unable to check whether collection existed earlier for chaincode %s:%s",
-				cdRWSet.Name, cdRWSet.Version))
-		}
+		return errors.WithMessage(err, fmt.Sprintf("unable to check whether collection existed earlier for chaincode %s:%s",
+			cdRWSet.Name, cdRWSet.Version))
 	}
 	if ccp != nil {
 		return errors.Errorf("collection data should not exist for chaincode %s:%s", cdRWSet.Name, cdRWSet.Version)
@@ -309,13 +337,15 @@ func (vscc *ValidatorOneValidSignature) validateDeployRWSetAndCollection(
 	return nil
 }
 
+// ValidateLSCCInvocation performs the additional validation
+// required by the invocations of the deploy and upgrade
+// functions of lscc
 func (vscc *ValidatorOneValidSignature) ValidateLSCCInvocation(
-	stub shim.ChaincodeStubInterface,
 	chid string,
 	env *common.Envelope,
 	cap *pb.ChaincodeActionPayload,
 	payl *common.Payload,
-	ac channelconfig.ApplicationCapabilities,
+	ac capabilities.Capabilities,
 ) error {
 	cpp, err := utils.GetChaincodeProposalPayload(cap.ChaincodeProposalPayload)
 	if err != nil {
@@ -545,14 +575,7 @@ func (vscc *ValidatorOneValidSignature) ValidateLSCCInvocation(
 }
 
 func (vscc *ValidatorOneValidSignature) getInstantiatedCC(chid, ccid string) (cd *ccprovider.ChaincodeData, exists bool, err error) {
-	qe, err := vscc.sccprovider.GetQueryExecutorForLedger(chid)
-	if err != nil {
-		err = fmt.Errorf("Could not retrieve QueryExecutor for channel %s, error %s", chid, err)
-		return
-	}
-	defer qe.Done()
-
-	bytes, err := qe.GetState("lscc", ccid)
+	bytes, err := vscc.support.GetState(chid, "lscc", ccid)
 	if err != nil {
 		err = fmt.Errorf("Could not retrieve state for chaincode %s on channel %s, error %s", ccid, chid, err)
 		return
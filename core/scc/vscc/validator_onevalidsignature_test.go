@@ -1662,12 +1662,12 @@ func TestValidateDeployRWSetAndCollection(t *testing.T) {
 	err = v.validateDeployRWSetAndCollection(rwset, cd, lsccargs, chid, ccid)
 	assert.NoError(t, err)
 
-	State["lscc"][(&collectionStoreSupport{v.sccprovider}).GetCollectionKVSKey(common.CollectionCriteria{Channel: chid, Namespace: ccid})] = []byte("barf")
+	State["lscc"][privdata.BuildCollectionKVSKey(ccid)] = []byte("barf")
 
 	err = v.validateDeployRWSetAndCollection(rwset, cd, lsccargs, chid, ccid)
 	assert.Error(t, err)
 
-	State["lscc"][(&collectionStoreSupport{v.sccprovider}).GetCollectionKVSKey(common.CollectionCriteria{Channel: chid, Namespace: ccid})] = ccpBytes
+	State["lscc"][privdata.BuildCollectionKVSKey(ccid)] = ccpBytes
 
 	err = v.validateDeployRWSetAndCollection(rwset, cd, lsccargs, chid, ccid)
 	assert.Error(t, err)
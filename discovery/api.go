@@ -0,0 +1,55 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"github.com/hyperledger/fabric/gossip/api"
+	common2 "github.com/hyperledger/fabric/gossip/common"
+	discovery2 "github.com/hyperledger/fabric/gossip/discovery"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/discovery"
+)
+
+// AccessControlSupport checks if clients are eligible of being serviced
+type AccessControlSupport interface {
+	// EligibleForService returns whether the given peer is eligible for receiving
+	// service from the discovery service for a given channel
+	EligibleForService(channel string, data common.SignedData) error
+}
+
+// ConfigSupport provides access to channel configuration
+type ConfigSupport interface {
+	// Config returns the channel's configuration
+	Config(channel string) (*discovery.ConfigResult, error)
+}
+
+// EndorsementSupport provides knowledge of endorsement policy selection
+// for chaincodes
+type EndorsementSupport interface {
+	// PeersForEndorsement returns an EndorsementDescriptor for a given set of peers, channel, and chaincode
+	PeersForEndorsement(chainID common2.ChainID, interest *discovery.ChaincodeInterest) (*discovery.EndorsementDescriptor, error)
+}
+
+// GossipSupport aggregates abilities that the gossip module
+// provides to the discovery service, such as knowing information about peers
+type GossipSupport interface {
+	// PeersOfChannel returns the NetworkMembers considered alive
+	// and also subscribed to the channel given
+	PeersOfChannel(common2.ChainID) []discovery2.NetworkMember
+
+	// IdentityInfo returns identity information about peers
+	IdentityInfo() api.PeerIdentitySet
+}
+
+// Support defines an interface that allows the discovery service
+// to obtain information that other peer components have
+type Support interface {
+	AccessControlSupport
+	GossipSupport
+	EndorsementSupport
+	ConfigSupport
+}
@@ -0,0 +1,303 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/protos/discovery"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+func init() {
+	rand.Seed(time.Now().UnixNano())
+}
+
+// Signer signs a message and returns the signature and nil,
+// or nil and error on failure
+type Signer func(msg []byte) ([]byte, error)
+
+// Dialer connects to the server
+type Dialer func() (*grpc.ClientConn, error)
+
+// Client interacts with the discovery service of a peer
+type Client struct {
+	createConnection Dialer
+	signRequest      Signer
+}
+
+// NewClient creates a new Client that connects to the discovery service
+// using the given Dialer, and signs its requests using the given Signer
+func NewClient(createConnection Dialer, s Signer) *Client {
+	return &Client{
+		createConnection: createConnection,
+		signRequest:      s,
+	}
+}
+
+// queryKey identifies a query of a Request
+type queryKey struct {
+	channel    string
+	queryType  discovery.QueryType
+	chaincodes string
+}
+
+// Request aggregates several queries to the discovery service
+type Request struct {
+	lastChannel  string
+	queryMapping map[queryKey]int
+	*discovery.Request
+}
+
+// NewRequest creates a new request
+func NewRequest() *Request {
+	return &Request{
+		queryMapping: make(map[queryKey]int),
+		Request:      &discovery.Request{},
+	}
+}
+
+// OfChannel sets the channel of the queries that are subsequently added to the request
+func (req *Request) OfChannel(ch string) *Request {
+	req.lastChannel = ch
+	return req
+}
+
+// AddConfigQuery adds to the request a config query
+func (req *Request) AddConfigQuery() *Request {
+	q := &discovery.Query{
+		Query: &discovery.Query_ConfigQuery{
+			ConfigQuery: &discovery.ConfigQuery{},
+		},
+	}
+	return req.addQuery(q, "")
+}
+
+// AddPeersQuery adds to the request a peer membership query
+func (req *Request) AddPeersQuery() *Request {
+	q := &discovery.Query{
+		Query: &discovery.Query_PeerQuery{
+			PeerQuery: &discovery.PeerMembershipQuery{},
+		},
+	}
+	return req.addQuery(q, "")
+}
+
+// AddEndorsersQuery adds to the request a query for the endorsers of an invocation
+// of the first given chaincode, that calls the rest of the given chaincodes
+func (req *Request) AddEndorsersQuery(chaincodes ...string) (*Request, error) {
+	interest, err := discovery.NewChaincodeInterest(chaincodes...)
+	if err != nil {
+		return nil, err
+	}
+	q := &discovery.Query{
+		Query: &discovery.Query_CcQuery{
+			CcQuery: &discovery.ChaincodeQuery{
+				Interests: []*discovery.ChaincodeInterest{interest},
+			},
+		},
+	}
+	return req.addQuery(q, strings.Join(chaincodes, ",")), nil
+}
+
+func (req *Request) addQuery(q *discovery.Query, chaincodes string) *Request {
+	q.Channel = req.lastChannel
+	req.Queries = append(req.Queries, q)
+	key := queryKey{channel: req.lastChannel, queryType: q.GetType(), chaincodes: chaincodes}
+	req.queryMapping[key] = len(req.Queries) - 1
+	return req
+}
+
+// Send sends the request and returns the response, or error on failure
+func (c *Client) Send(ctx context.Context, req *Request, auth *discovery.AuthInfo) (Response, error) {
+	reqToBeSent := *req.Request
+	reqToBeSent.Authentication = auth
+	payload, err := proto.Marshal(&reqToBeSent)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed marshaling Request to bytes")
+	}
+
+	sig, err := c.signRequest(payload)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed signing Request")
+	}
+
+	conn, err := c.createConnection()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed connecting to discovery service")
+	}
+	defer conn.Close()
+
+	cl := discovery.NewDiscoveryClient(conn)
+	resp, err := cl.Discover(ctx, &discovery.SignedRequest{
+		Payload:   payload,
+		Signature: sig,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "discovery service refused our Request")
+	}
+	if n := len(resp.Results); n != len(req.Queries) {
+		return nil, errors.Errorf("sent %d queries but received %d responses back", len(req.Queries), n)
+	}
+	return &response{
+		Response:     resp,
+		queryMapping: req.queryMapping,
+	}, nil
+}
+
+// Peer aggregates the information about a peer of a channel
+type Peer struct {
+	MSPID        string
+	LedgerHeight uint64
+	Endpoint     string
+	Identity     []byte
+}
+
+// Endorsers defines a set of peers that are sufficient
+// for satisfying some chaincode's endorsement policy
+type Endorsers []*Peer
+
+// Response aggregates several responses from the discovery service
+type Response interface {
+	// ForChannel returns a ChannelResponse in the context of a given channel
+	ForChannel(string) ChannelResponse
+}
+
+// ChannelResponse aggregates responses for a given channel
+type ChannelResponse interface {
+	// Config returns a response for a config query, or error if something went wrong
+	Config() (*discovery.ConfigResult, error)
+
+	// Peers returns a response for a peer membership query, or error if something went wrong
+	Peers() ([]*Peer, error)
+
+	// Endorsers returns a set of peers that satisfy the endorsement
+	// policy of an invocation of the first given chaincode, that calls
+	// the rest of the given chaincodes
+	Endorsers(chaincodes ...string) (Endorsers, error)
+}
+
+type response struct {
+	*discovery.Response
+	queryMapping map[queryKey]int
+}
+
+// ForChannel returns a ChannelResponse in the context of a given channel
+func (r *response) ForChannel(ch string) ChannelResponse {
+	return &channelResponse{
+		response: r,
+		channel:  ch,
+	}
+}
+
+type channelResponse struct {
+	*response
+	channel string
+}
+
+func (cr *channelResponse) resultIndex(t discovery.QueryType, chaincodes string) (int, error) {
+	i, exists := cr.queryMapping[queryKey{channel: cr.channel, queryType: t, chaincodes: chaincodes}]
+	if !exists {
+		return 0, errors.Errorf("no such query was issued for channel %s", cr.channel)
+	}
+	return i, nil
+}
+
+// Config returns a response for a config query, or error if something went wrong
+func (cr *channelResponse) Config() (*discovery.ConfigResult, error) {
+	i, err := cr.resultIndex(discovery.ConfigQueryType, "")
+	if err != nil {
+		return nil, err
+	}
+	res, errRes := cr.ConfigAt(i)
+	if errRes != nil {
+		return nil, errors.New(errRes.Content)
+	}
+	return res, nil
+}
+
+// Peers returns a response for a peer membership query, or error if something went wrong
+func (cr *channelResponse) Peers() ([]*Peer, error) {
+	i, err := cr.resultIndex(discovery.PeerMembershipQueryType, "")
+	if err != nil {
+		return nil, err
+	}
+	res, errRes := cr.MembershipAt(i)
+	if errRes != nil {
+		return nil, errors.New(errRes.Content)
+	}
+	var peers []*Peer
+	for org, peersOfOrg := range res.PeersByOrg {
+		for _, p := range peersOfOrg.Peers {
+			peers = append(peers, &Peer{
+				MSPID:        org,
+				LedgerHeight: p.LedgerHeight,
+				Endpoint:     p.Endpoint,
+				Identity:     p.Identity,
+			})
+		}
+	}
+	return peers, nil
+}
+
+// Endorsers returns a set of peers that satisfy the endorsement
+// policy of an invocation of the first given chaincode, that calls
+// the rest of the given chaincodes
+func (cr *channelResponse) Endorsers(chaincodes ...string) (Endorsers, error) {
+	i, err := cr.resultIndex(discovery.ChaincodeQueryType, strings.Join(chaincodes, ","))
+	if err != nil {
+		return nil, err
+	}
+	res, errRes := cr.EndorsersAt(i)
+	if errRes != nil {
+		return nil, errors.New(errRes.Content)
+	}
+	if len(res.Content) == 0 {
+		return nil, errors.Errorf("no endorsement descriptor for %v", chaincodes)
+	}
+	return selectEndorsers(res.Content[0])
+}
+
+// selectEndorsers picks a random layout of the given descriptor,
+// and selects for each of its groups random peers according to the layout
+func selectEndorsers(desc *discovery.EndorsementDescriptor) (Endorsers, error) {
+	if len(desc.Layouts) == 0 {
+		return nil, errors.Errorf("no layouts for chaincode %s", desc.Chaincode)
+	}
+	layout := desc.Layouts[rand.Intn(len(desc.Layouts))]
+	var endorsers Endorsers
+	for group, quantity := range layout.QuantitiesByGroup {
+		peers := desc.EndorsersByGroups[group].GetPeers()
+		if len(peers) < int(quantity) {
+			return nil, errors.Errorf("group %s has %d peers but %d are required", group, len(peers), quantity)
+		}
+		for _, i := range rand.Perm(len(peers))[:quantity] {
+			p := peers[i]
+			endorsers = append(endorsers, &Peer{
+				MSPID:        mspIDOf(p.Identity),
+				LedgerHeight: p.LedgerHeight,
+				Endpoint:     p.Endpoint,
+				Identity:     p.Identity,
+			})
+		}
+	}
+	return endorsers, nil
+}
+
+func mspIDOf(identity []byte) string {
+	sID := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(identity, sID); err != nil {
+		return ""
+	}
+	return sID.Mspid
+}
@@ -0,0 +1,193 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package client
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/discovery"
+	"github.com/hyperledger/fabric/gossip/api"
+	gcommon "github.com/hyperledger/fabric/gossip/common"
+	discovery2 "github.com/hyperledger/fabric/gossip/discovery"
+	"github.com/hyperledger/fabric/protos/common"
+	discprotos "github.com/hyperledger/fabric/protos/discovery"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+func identityOf(mspID, name string) []byte {
+	b, _ := proto.Marshal(&msp.SerializedIdentity{Mspid: mspID, IdBytes: []byte(name)})
+	return b
+}
+
+var (
+	p1 = &discprotos.Peer{Endpoint: "p1:7051", Identity: identityOf("Org1MSP", "p1"), LedgerHeight: 10}
+	p2 = &discprotos.Peer{Endpoint: "p2:7051", Identity: identityOf("Org1MSP", "p2"), LedgerHeight: 11}
+	p3 = &discprotos.Peer{Endpoint: "p3:7051", Identity: identityOf("Org2MSP", "p3"), LedgerHeight: 12}
+)
+
+type mockSupport struct {
+}
+
+func (*mockSupport) EligibleForService(channel string, _ common.SignedData) error {
+	if channel == "forbidden" {
+		return errors.New("forbidden")
+	}
+	return nil
+}
+
+func (*mockSupport) Config(channel string) (*discprotos.ConfigResult, error) {
+	return &discprotos.ConfigResult{
+		Orderers: []*discprotos.Endpoint{{Host: "orderer", Port: 7050}},
+	}, nil
+}
+
+func (*mockSupport) PeersForEndorsement(_ gcommon.ChainID, interest *discprotos.ChaincodeInterest) (*discprotos.EndorsementDescriptor, error) {
+	if interest.Chaincodes[0].Name != "mycc" {
+		return nil, errors.New("chaincode not found")
+	}
+	return &discprotos.EndorsementDescriptor{
+		Chaincode: "mycc",
+		EndorsersByGroups: map[string]*discprotos.Peers{
+			"G0": {Peers: []*discprotos.Peer{p1, p2}},
+			"G1": {Peers: []*discprotos.Peer{p3}},
+		},
+		Layouts: []*discprotos.Layout{
+			{QuantitiesByGroup: map[string]uint32{"G0": 2}},
+			{QuantitiesByGroup: map[string]uint32{"G0": 1, "G1": 1}},
+		},
+	}, nil
+}
+
+func (*mockSupport) PeersOfChannel(_ gcommon.ChainID) []discovery2.NetworkMember {
+	return []discovery2.NetworkMember{{PKIid: gcommon.PKIidType("p1"), Endpoint: "p1:7051"}}
+}
+
+func (*mockSupport) IdentityInfo() api.PeerIdentitySet {
+	return api.PeerIdentitySet{
+		{PKIId: gcommon.PKIidType("p1"), Identity: identityOf("Org1MSP", "p1"), Organization: api.OrgIdentityType("Org1MSP")},
+	}
+}
+
+func createServer(t *testing.T) (*grpc.Server, string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+	s := grpc.NewServer()
+	discprotos.RegisterDiscoveryServer(s, discovery.NewService(discovery.Config{}, &mockSupport{}))
+	go s.Serve(l)
+	return s, l.Addr().String()
+}
+
+func TestClient(t *testing.T) {
+	s, addr := createServer(t)
+	defer s.Stop()
+
+	dialer := func() (*grpc.ClientConn, error) {
+		return grpc.Dial(addr, grpc.WithInsecure(), grpc.WithBlock(), grpc.WithTimeout(time.Second*5))
+	}
+	signer := func(msg []byte) ([]byte, error) {
+		return []byte("signature"), nil
+	}
+	cl := NewClient(dialer, signer)
+	auth := &discprotos.AuthInfo{ClientIdentity: identityOf("Org1MSP", "client")}
+
+	req := NewRequest().OfChannel("mychannel").AddConfigQuery().AddPeersQuery()
+	req, err := req.AddEndorsersQuery("mycc", "othercc")
+	assert.NoError(t, err)
+	req, err = req.AddEndorsersQuery("nocc")
+	assert.NoError(t, err)
+	req = req.OfChannel("forbidden").AddConfigQuery()
+	_, err = req.AddEndorsersQuery()
+	assert.Error(t, err)
+
+	resp, err := cl.Send(context.Background(), req, auth)
+	assert.NoError(t, err)
+
+	conf, err := resp.ForChannel("mychannel").Config()
+	assert.NoError(t, err)
+	assert.Equal(t, []*discprotos.Endpoint{{Host: "orderer", Port: 7050}}, conf.Orderers)
+
+	peers, err := resp.ForChannel("mychannel").Peers()
+	assert.NoError(t, err)
+	assert.Equal(t, []*Peer{{MSPID: "Org1MSP", Endpoint: "p1:7051", Identity: identityOf("Org1MSP", "p1")}}, peers)
+
+	for i := 0; i < 10; i++ {
+		endorsers, err := resp.ForChannel("mychannel").Endorsers("mycc", "othercc")
+		assert.NoError(t, err)
+		assert.Len(t, endorsers, 2)
+		if endorsers[0].MSPID == "Org1MSP" && endorsers[1].MSPID == "Org1MSP" {
+			assert.NotEqual(t, endorsers[0].Endpoint, endorsers[1].Endpoint)
+		}
+	}
+
+	_, err = resp.ForChannel("mychannel").Endorsers("nocc")
+	assert.Contains(t, err.Error(), "failed constructing descriptor")
+
+	_, err = resp.ForChannel("mychannel").Endorsers("mycc")
+	assert.EqualError(t, err, "no such query was issued for channel mychannel")
+
+	_, err = resp.ForChannel("forbidden").Config()
+	assert.EqualError(t, err, "access denied")
+
+	_, err = resp.ForChannel("forbidden").Peers()
+	assert.EqualError(t, err, "no such query was issued for channel forbidden")
+
+	// The server rejects requests without authentication info
+	_, err = cl.Send(context.Background(), req, nil)
+	assert.Contains(t, err.Error(), "discovery service refused our Request")
+}
+
+func TestClientFailures(t *testing.T) {
+	req := NewRequest().OfChannel("mychannel").AddConfigQuery()
+	auth := &discprotos.AuthInfo{ClientIdentity: []byte("identity")}
+
+	failingSigner := func(msg []byte) ([]byte, error) {
+		return nil, errors.New("no key")
+	}
+	cl := NewClient(nil, failingSigner)
+	_, err := cl.Send(context.Background(), req, auth)
+	assert.Contains(t, err.Error(), "failed signing Request")
+
+	signer := func(msg []byte) ([]byte, error) {
+		return []byte("signature"), nil
+	}
+	failingDialer := func() (*grpc.ClientConn, error) {
+		return nil, errors.New("unreachable")
+	}
+	cl = NewClient(failingDialer, signer)
+	_, err = cl.Send(context.Background(), req, auth)
+	assert.Contains(t, err.Error(), "failed connecting to discovery service")
+}
+
+func TestSelectEndorsers(t *testing.T) {
+	desc := &discprotos.EndorsementDescriptor{
+		Chaincode: "mycc",
+		EndorsersByGroups: map[string]*discprotos.Peers{
+			"G0": {Peers: []*discprotos.Peer{p1, p2}},
+		},
+	}
+	_, err := selectEndorsers(desc)
+	assert.EqualError(t, err, "no layouts for chaincode mycc")
+
+	desc.Layouts = []*discprotos.Layout{{QuantitiesByGroup: map[string]uint32{"G0": 3}}}
+	_, err = selectEndorsers(desc)
+	assert.EqualError(t, err, "group G0 has 2 peers but 3 are required")
+
+	desc.Layouts = []*discprotos.Layout{{QuantitiesByGroup: map[string]uint32{"G0": 2}}}
+	endorsers, err := selectEndorsers(desc)
+	assert.NoError(t, err)
+	assert.Len(t, endorsers, 2)
+	for _, e := range endorsers {
+		assert.Equal(t, "Org1MSP", e.MSPID)
+	}
+}
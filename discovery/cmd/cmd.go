@@ -0,0 +1,245 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"time"
+
+	"github.com/golang/protobuf/jsonpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/discovery/client"
+	mspmgmt "github.com/hyperledger/fabric/msp/mgmt"
+	"github.com/hyperledger/fabric/protos/discovery"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"gopkg.in/alecthomas/kingpin.v2"
+)
+
+const defaultTimeout = time.Second * 10
+
+// Config defines the parameters of a discovery request issued by the CLI
+type Config struct {
+	Server     string
+	Channel    string
+	Chaincodes []string
+	MSPDir     string
+	MSPID      string
+	TLSCA      string
+	TLSCert    string
+	TLSKey     string
+	Timeout    time.Duration
+}
+
+// Stub sends requests to the discovery service
+type Stub interface {
+	// Send sends the given request according to the given config,
+	// and returns the response or an error on failure
+	Send(conf Config, req *client.Request) (client.Response, error)
+}
+
+// CLI parses the command line arguments and issues discovery requests
+type CLI struct {
+	app        *kingpin.Application
+	out        io.Writer
+	stub       Stub
+	conf       Config
+	peers      *kingpin.CmdClause
+	config     *kingpin.CmdClause
+	endorsers  *kingpin.CmdClause
+	chaincodes *[]string
+}
+
+// NewCLI creates a new CLI that writes its output to the given writer
+// and sends the requests using the given stub
+func NewCLI(out io.Writer, stub Stub) *CLI {
+	cli := &CLI{
+		app:  kingpin.New("discover", "Command line client for the fabric discovery service"),
+		out:  out,
+		stub: stub,
+	}
+	cli.app.HelpFlag.Short('h')
+	cli.app.Flag("server", "Address of the peer to query").Required().StringVar(&cli.conf.Server)
+	cli.app.Flag("channel", "Channel to query").Required().StringVar(&cli.conf.Channel)
+	cli.app.Flag("MSP", "Path to the MSP directory of the client").Required().StringVar(&cli.conf.MSPDir)
+	cli.app.Flag("mspID", "MSP ID of the client").Required().StringVar(&cli.conf.MSPID)
+	cli.app.Flag("tlsCA", "Path to the TLS CA certificate of the peer").StringVar(&cli.conf.TLSCA)
+	cli.app.Flag("tlsCert", "Path to the client TLS certificate").StringVar(&cli.conf.TLSCert)
+	cli.app.Flag("tlsKey", "Path to the client TLS key").StringVar(&cli.conf.TLSKey)
+	cli.app.Flag("timeout", "Timeout of the request").Default(defaultTimeout.String()).DurationVar(&cli.conf.Timeout)
+
+	cli.peers = cli.app.Command("peers", "Discover the peers of the channel")
+	cli.config = cli.app.Command("config", "Discover the MSPs and orderers of the channel")
+	cli.endorsers = cli.app.Command("endorsers", "Discover peers that satisfy the endorsement policy of a chaincode invocation")
+	cli.chaincodes = cli.endorsers.Flag("chaincode", "Chaincode name, repeat for chaincode-to-chaincode invocations").Required().Strings()
+	return cli
+}
+
+// Run parses the given arguments and executes the corresponding command
+func (cli *CLI) Run(args []string) error {
+	command, err := cli.app.Parse(args)
+	if err != nil {
+		return err
+	}
+	cli.conf.Chaincodes = *cli.chaincodes
+
+	req := client.NewRequest().OfChannel(cli.conf.Channel)
+	switch command {
+	case cli.peers.FullCommand():
+		req = req.AddPeersQuery()
+	case cli.config.FullCommand():
+		req = req.AddConfigQuery()
+	case cli.endorsers.FullCommand():
+		if req, err = req.AddEndorsersQuery(cli.conf.Chaincodes...); err != nil {
+			return err
+		}
+	}
+
+	resp, err := cli.stub.Send(cli.conf, req)
+	if err != nil {
+		return err
+	}
+	chanResp := resp.ForChannel(cli.conf.Channel)
+
+	switch command {
+	case cli.peers.FullCommand():
+		peers, err := chanResp.Peers()
+		if err != nil {
+			return err
+		}
+		return cli.printPeers(peers)
+	case cli.config.FullCommand():
+		conf, err := chanResp.Config()
+		if err != nil {
+			return err
+		}
+		return cli.printConfig(conf)
+	default:
+		endorsers, err := chanResp.Endorsers(cli.conf.Chaincodes...)
+		if err != nil {
+			return err
+		}
+		return cli.printPeers(endorsers)
+	}
+}
+
+type peerView struct {
+	MSPID        string
+	LedgerHeight uint64
+	Endpoint     string
+	Identity     string
+}
+
+func (cli *CLI) printPeers(peers []*client.Peer) error {
+	views := []peerView{}
+	for _, p := range peers {
+		sID := &msp.SerializedIdentity{}
+		if err := proto.Unmarshal(p.Identity, sID); err != nil {
+			return errors.Wrap(err, "failed unmarshaling peer identity")
+		}
+		views = append(views, peerView{
+			MSPID:        p.MSPID,
+			LedgerHeight: p.LedgerHeight,
+			Endpoint:     p.Endpoint,
+			Identity:     string(sID.IdBytes),
+		})
+	}
+	b, err := json.MarshalIndent(views, "", "\t")
+	if err != nil {
+		return errors.Wrap(err, "failed marshaling peers to JSON")
+	}
+	_, err = fmt.Fprintln(cli.out, string(b))
+	return err
+}
+
+func (cli *CLI) printConfig(conf *discovery.ConfigResult) error {
+	m := &jsonpb.Marshaler{Indent: "\t"}
+	s, err := m.MarshalToString(conf)
+	if err != nil {
+		return errors.Wrap(err, "failed marshaling config to JSON")
+	}
+	_, err = fmt.Fprintln(cli.out, s)
+	return err
+}
+
+// ClientStub is a Stub that sends the requests to the discovery service of a peer
+// over gRPC, and signs them using the local MSP loaded from the configured directory
+type ClientStub struct {
+}
+
+// Send sends the given request according to the given config,
+// and returns the response or an error on failure
+func (stub *ClientStub) Send(conf Config, req *client.Request) (client.Response, error) {
+	if err := mspmgmt.LoadLocalMsp(conf.MSPDir, nil, conf.MSPID); err != nil {
+		return nil, errors.WithMessage(err, "failed loading MSP")
+	}
+	signer, err := mspmgmt.GetLocalMSP().GetDefaultSigningIdentity()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed obtaining signing identity")
+	}
+	identity, err := signer.Serialize()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed serializing signing identity")
+	}
+
+	secOpts, err := secureOptions(conf)
+	if err != nil {
+		return nil, err
+	}
+	grpcClient, err := comm.NewGRPCClient(comm.ClientConfig{
+		SecOpts: secOpts,
+		Timeout: conf.Timeout,
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed creating gRPC client")
+	}
+	auth := &discovery.AuthInfo{
+		ClientIdentity: identity,
+	}
+	if secOpts.RequireClientCert {
+		auth.ClientTlsCertHash = util.ComputeSHA256(grpcClient.Certificate().Certificate[0])
+	}
+
+	dialer := func() (*grpc.ClientConn, error) {
+		return grpcClient.NewConnection(conf.Server, "")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), conf.Timeout)
+	defer cancel()
+	return client.NewClient(dialer, signer.Sign).Send(ctx, req, auth)
+}
+
+func secureOptions(conf Config) (*comm.SecureOptions, error) {
+	if conf.TLSCA == "" {
+		return &comm.SecureOptions{}, nil
+	}
+	caCert, err := ioutil.ReadFile(conf.TLSCA)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed reading TLS CA certificate")
+	}
+	secOpts := &comm.SecureOptions{
+		UseTLS:        true,
+		ServerRootCAs: [][]byte{caCert},
+	}
+	if conf.TLSCert == "" && conf.TLSKey == "" {
+		return secOpts, nil
+	}
+	if secOpts.Certificate, err = ioutil.ReadFile(conf.TLSCert); err != nil {
+		return nil, errors.Wrap(err, "failed reading TLS certificate")
+	}
+	if secOpts.Key, err = ioutil.ReadFile(conf.TLSKey); err != nil {
+		return nil, errors.Wrap(err, "failed reading TLS key")
+	}
+	secOpts.RequireClientCert = true
+	return secOpts, nil
+}
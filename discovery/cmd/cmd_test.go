@@ -0,0 +1,145 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/discovery/client"
+	"github.com/hyperledger/fabric/protos/discovery"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockStub struct {
+	conf Config
+	req  *client.Request
+	resp client.Response
+	err  error
+}
+
+func (s *mockStub) Send(conf Config, req *client.Request) (client.Response, error) {
+	s.conf = conf
+	s.req = req
+	return s.resp, s.err
+}
+
+type mockResponse struct {
+	peers     []*client.Peer
+	config    *discovery.ConfigResult
+	endorsers client.Endorsers
+	err       error
+}
+
+func (r *mockResponse) ForChannel(string) client.ChannelResponse {
+	return r
+}
+
+func (r *mockResponse) Config() (*discovery.ConfigResult, error) {
+	return r.config, r.err
+}
+
+func (r *mockResponse) Peers() ([]*client.Peer, error) {
+	return r.peers, r.err
+}
+
+func (r *mockResponse) Endorsers(chaincodes ...string) (client.Endorsers, error) {
+	return r.endorsers, r.err
+}
+
+var globalFlags = []string{"--server", "peer0:7051", "--channel", "mychannel", "--MSP", "/msp", "--mspID", "Org1MSP"}
+
+func TestPeersAndEndorsers(t *testing.T) {
+	identity, _ := proto.Marshal(&msp.SerializedIdentity{Mspid: "Org1MSP", IdBytes: []byte("cert")})
+	peers := []*client.Peer{{MSPID: "Org1MSP", Endpoint: "p1:7051", LedgerHeight: 5, Identity: identity}}
+	stub := &mockStub{resp: &mockResponse{peers: peers, endorsers: peers}}
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, NewCLI(out, stub).Run(append(globalFlags, "peers")))
+	assert.Equal(t, "peer0:7051", stub.conf.Server)
+	assert.Equal(t, "Org1MSP", stub.conf.MSPID)
+	assert.Equal(t, discovery.PeerMembershipQueryType, stub.req.Queries[0].GetType())
+	assert.Equal(t, "mychannel", stub.req.Queries[0].Channel)
+	var views []peerView
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &views))
+	assert.Equal(t, []peerView{{MSPID: "Org1MSP", Endpoint: "p1:7051", LedgerHeight: 5, Identity: "cert"}}, views)
+
+	out.Reset()
+	assert.NoError(t, NewCLI(out, stub).Run(append(globalFlags, "endorsers", "--chaincode", "cc1", "--chaincode", "cc2")))
+	assert.Equal(t, []string{"cc1", "cc2"}, stub.conf.Chaincodes)
+	assert.Equal(t, []*discovery.ChaincodeCall{{Name: "cc1"}, {Name: "cc2"}}, stub.req.Queries[0].GetCcQuery().Interests[0].Chaincodes)
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &views))
+	assert.Len(t, views, 1)
+}
+
+func TestConfig(t *testing.T) {
+	conf := &discovery.ConfigResult{Orderers: []*discovery.Endpoint{{Host: "orderer", Port: 7050}}}
+	stub := &mockStub{resp: &mockResponse{config: conf}}
+	out := &bytes.Buffer{}
+	assert.NoError(t, NewCLI(out, stub).Run(append(globalFlags, "config")))
+	assert.Contains(t, out.String(), `"host": "orderer"`)
+	assert.Contains(t, out.String(), `"port": 7050`)
+}
+
+func TestFailures(t *testing.T) {
+	out := &bytes.Buffer{}
+	stub := &mockStub{err: errors.New("connection refused")}
+
+	// Missing global flags
+	assert.Error(t, NewCLI(out, stub).Run([]string{"peers"}))
+	// Missing chaincode
+	assert.Error(t, NewCLI(out, stub).Run(append(globalFlags, "endorsers")))
+
+	err := NewCLI(out, stub).Run(append(globalFlags, "peers"))
+	assert.EqualError(t, err, "connection refused")
+
+	stub = &mockStub{resp: &mockResponse{err: errors.New("access denied")}}
+	for _, command := range [][]string{{"peers"}, {"config"}, {"endorsers", "--chaincode", "cc1"}} {
+		err = NewCLI(out, stub).Run(append(globalFlags, command...))
+		assert.EqualError(t, err, "access denied")
+	}
+	assert.Empty(t, out.String())
+}
+
+func TestSecureOptions(t *testing.T) {
+	secOpts, err := secureOptions(Config{})
+	assert.NoError(t, err)
+	assert.False(t, secOpts.UseTLS)
+
+	dir, err := ioutil.TempDir("", "discover")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+	for _, f := range []string{"ca.pem", "cert.pem", "key.pem"} {
+		assert.NoError(t, ioutil.WriteFile(filepath.Join(dir, f), []byte(f), 0600))
+	}
+
+	_, err = secureOptions(Config{TLSCA: filepath.Join(dir, "nonexistent.pem")})
+	assert.Contains(t, err.Error(), "failed reading TLS CA certificate")
+
+	secOpts, err = secureOptions(Config{TLSCA: filepath.Join(dir, "ca.pem")})
+	assert.NoError(t, err)
+	assert.True(t, secOpts.UseTLS)
+	assert.False(t, secOpts.RequireClientCert)
+	assert.Equal(t, [][]byte{[]byte("ca.pem")}, secOpts.ServerRootCAs)
+
+	secOpts, err = secureOptions(Config{
+		TLSCA:   filepath.Join(dir, "ca.pem"),
+		TLSCert: filepath.Join(dir, "cert.pem"),
+		TLSKey:  filepath.Join(dir, "key.pem"),
+	})
+	assert.NoError(t, err)
+	assert.True(t, secOpts.RequireClientCert)
+	assert.Equal(t, []byte("cert.pem"), secOpts.Certificate)
+	assert.Equal(t, []byte("key.pem"), secOpts.Key)
+}
@@ -0,0 +1,294 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorsement
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/gossip/api"
+	"github.com/hyperledger/fabric/gossip/common"
+	discovery2 "github.com/hyperledger/fabric/gossip/discovery"
+	common2 "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/discovery"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("discovery/endorsement")
+
+type principalEvaluator interface {
+	// SatisfiesPrincipal returns whether a given peer identity satisfies a certain principal
+	// on a given channel
+	SatisfiesPrincipal(channel string, identity []byte, principal *msp.MSPPrincipal) error
+}
+
+type policyFetcher interface {
+	// PolicyByChaincode returns the endorsement policy of the given chaincode
+	// on the given channel, or nil if the chaincode isn't instantiated on it
+	PolicyByChaincode(channel string, cc string) *common2.SignaturePolicyEnvelope
+}
+
+type gossipSupport interface {
+	// IdentityInfo returns identity information about peers
+	IdentityInfo() api.PeerIdentitySet
+
+	// PeersOfChannel returns the NetworkMembers considered alive
+	// and also subscribed to the channel given
+	PeersOfChannel(common.ChainID) []discovery2.NetworkMember
+}
+
+type endorsementAnalyzer struct {
+	gossipSupport
+	principalEvaluator
+	policyFetcher
+}
+
+// NewEndorsementAnalyzer constructs an endorsementAnalyzer out of the given support
+func NewEndorsementAnalyzer(gs gossipSupport, pf policyFetcher, pe principalEvaluator) *endorsementAnalyzer {
+	return &endorsementAnalyzer{
+		gossipSupport:      gs,
+		policyFetcher:      pf,
+		principalEvaluator: pe,
+	}
+}
+
+// principalSet is a multiset of principals, represented as the number
+// of times each principal appears in it, keyed by the serialized principal
+type principalSet map[string]int
+
+// principalsByKey maps serialized principals to their object form
+type principalsByKey map[string]*msp.MSPPrincipal
+
+// PeersForEndorsement returns an EndorsementDescriptor for a given set of peers, channel, and chaincode
+func (ea *endorsementAnalyzer) PeersForEndorsement(chainID common.ChainID, interest *discovery.ChaincodeInterest) (*discovery.EndorsementDescriptor, error) {
+	if interest == nil || len(interest.Chaincodes) == 0 {
+		return nil, errors.New("no chaincodes specified")
+	}
+	channel := string(chainID)
+
+	principals := make(principalsByKey)
+	// Start from a single empty combination, and merge into it the combinations
+	// of principals that satisfy the policy of each chaincode in the invocation chain
+	combinations := []principalSet{{}}
+	for _, cc := range interest.Chaincodes {
+		policy := ea.PolicyByChaincode(channel, cc.Name)
+		if policy == nil {
+			return nil, errors.Errorf("policy of chaincode %s not found", cc.Name)
+		}
+		sets, err := principalSetsOfPolicy(policy, principals)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("failed analyzing policy of chaincode %s", cc.Name))
+		}
+		combinations = mergeCombinations(combinations, sets)
+	}
+
+	peersByPrincipal := ea.peersByPrincipal(channel, principals)
+	groups, layouts := computeLayouts(combinations, peersByPrincipal)
+	if len(layouts) == 0 {
+		return nil, errors.New("cannot satisfy any principal combination")
+	}
+	return &discovery.EndorsementDescriptor{
+		Chaincode:         interest.Chaincodes[0].Name,
+		EndorsersByGroups: groups,
+		Layouts:           layouts,
+	}, nil
+}
+
+// peersByPrincipal returns, for each of the given principals,
+// the peers of the channel that satisfy it
+func (ea *endorsementAnalyzer) peersByPrincipal(channel string, principals principalsByKey) map[string][]*discovery.Peer {
+	identities := ea.IdentityInfo().ByID()
+	res := make(map[string][]*discovery.Peer)
+	for _, member := range ea.PeersOfChannel(common.ChainID(channel)) {
+		id, exists := identities[string(member.PKIid)]
+		if !exists {
+			logger.Debug("Skipping", member.Endpoint, "because its identity isn't known")
+			continue
+		}
+		var ledgerHeight uint64
+		if member.Properties != nil {
+			ledgerHeight = member.Properties.LedgerHeight
+		}
+		for key, principal := range principals {
+			if err := ea.SatisfiesPrincipal(channel, id.Identity, principal); err != nil {
+				continue
+			}
+			res[key] = append(res[key], &discovery.Peer{
+				Endpoint:     member.Endpoint,
+				Identity:     id.Identity,
+				LedgerHeight: ledgerHeight,
+			})
+		}
+	}
+	return res
+}
+
+// computeLayouts converts the combinations of principals into layouts of groups of peers,
+// and retains only the layouts that can be satisfied by the peers of the channel
+func computeLayouts(combinations []principalSet, peersByPrincipal map[string][]*discovery.Peer) (map[string]*discovery.Peers, []*discovery.Layout) {
+	// Assign group names to the principals in a deterministic order
+	var keys []string
+	for key := range peersByPrincipal {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	groupNames := make(map[string]string)
+	for i, key := range keys {
+		groupNames[key] = fmt.Sprintf("G%d", i)
+	}
+
+	groups := make(map[string]*discovery.Peers)
+	var layouts []*discovery.Layout
+	seen := make(map[string]struct{})
+	for _, combination := range combinations {
+		quantities, satisfiable := make(map[string]uint32), true
+		for key, count := range combination {
+			if len(peersByPrincipal[key]) < count {
+				satisfiable = false
+				break
+			}
+			quantities[groupNames[key]] = uint32(count)
+		}
+		if !satisfiable {
+			continue
+		}
+		id := layoutID(quantities)
+		if _, exists := seen[id]; exists {
+			continue
+		}
+		seen[id] = struct{}{}
+		layouts = append(layouts, &discovery.Layout{QuantitiesByGroup: quantities})
+		for key := range combination {
+			groups[groupNames[key]] = &discovery.Peers{Peers: peersByPrincipal[key]}
+		}
+	}
+	return groups, layouts
+}
+
+func layoutID(quantities map[string]uint32) string {
+	var entries []string
+	for group, count := range quantities {
+		entries = append(entries, fmt.Sprintf("%s:%d", group, count))
+	}
+	sort.Strings(entries)
+	return strings.Join(entries, ",")
+}
+
+// principalSetsOfPolicy returns the combinations of principals that satisfy the given policy,
+// and records the principals it encounters in the given principalsByKey
+func principalSetsOfPolicy(policy *common2.SignaturePolicyEnvelope, principals principalsByKey) ([]principalSet, error) {
+	if policy.Rule == nil {
+		return nil, errors.New("policy has no rule")
+	}
+	keys := make([]string, len(policy.Identities))
+	for i, principal := range policy.Identities {
+		b, err := proto.Marshal(principal)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed marshaling principal")
+		}
+		keys[i] = string(b)
+		principals[keys[i]] = principal
+	}
+	return principalSetsOfRule(policy.Rule, keys)
+}
+
+func principalSetsOfRule(rule *common2.SignaturePolicy, keys []string) ([]principalSet, error) {
+	switch t := rule.Type.(type) {
+	case *common2.SignaturePolicy_SignedBy:
+		if t.SignedBy < 0 || int(t.SignedBy) >= len(keys) {
+			return nil, errors.Errorf("identity index %d out of range", t.SignedBy)
+		}
+		return []principalSet{{keys[t.SignedBy]: 1}}, nil
+	case *common2.SignaturePolicy_NOutOf_:
+		subSets := make([][]principalSet, len(t.NOutOf.Rules))
+		for i, subRule := range t.NOutOf.Rules {
+			sets, err := principalSetsOfRule(subRule, keys)
+			if err != nil {
+				return nil, err
+			}
+			subSets[i] = sets
+		}
+		var res []principalSet
+		for _, indices := range chooseN(len(subSets), int(t.NOutOf.N)) {
+			product := []principalSet{{}}
+			for _, i := range indices {
+				product = concatCombinations(product, subSets[i])
+			}
+			res = append(res, product...)
+		}
+		return res, nil
+	default:
+		return nil, errors.Errorf("unsupported policy rule type %T", rule.Type)
+	}
+}
+
+// concatCombinations returns the cartesian product of the given combinations,
+// where the principals of each pair are required together
+func concatCombinations(a, b []principalSet) []principalSet {
+	var res []principalSet
+	for _, x := range a {
+		for _, y := range b {
+			set := make(principalSet)
+			for key, count := range x {
+				set[key] += count
+			}
+			for key, count := range y {
+				set[key] += count
+			}
+			res = append(res, set)
+		}
+	}
+	return res
+}
+
+// mergeCombinations returns the cartesian product of the given combinations,
+// where an endorsement by a peer is shared by the chaincodes of both combinations
+func mergeCombinations(a, b []principalSet) []principalSet {
+	var res []principalSet
+	for _, x := range a {
+		for _, y := range b {
+			set := make(principalSet)
+			for key, count := range x {
+				set[key] = count
+			}
+			for key, count := range y {
+				if set[key] < count {
+					set[key] = count
+				}
+			}
+			res = append(res, set)
+		}
+	}
+	return res
+}
+
+// chooseN returns all subsets of size n of the indices [0, size)
+func chooseN(size, n int) [][]int {
+	if n <= 0 {
+		return [][]int{{}}
+	}
+	if n > size {
+		return nil
+	}
+	var res [][]int
+	var choose func(start int, chosen []int)
+	choose = func(start int, chosen []int) {
+		if len(chosen) == n {
+			res = append(res, append([]int(nil), chosen...))
+			return
+		}
+		for i := start; i < size; i++ {
+			choose(i+1, append(chosen, i))
+		}
+	}
+	choose(0, nil)
+	return res
+}
@@ -0,0 +1,215 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package endorsement
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/gossip/api"
+	"github.com/hyperledger/fabric/gossip/common"
+	discovery2 "github.com/hyperledger/fabric/gossip/discovery"
+	common2 "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/discovery"
+	"github.com/hyperledger/fabric/protos/gossip"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// peersOfOrgs maps peer identities to their organizations
+var peersOfOrgs = map[string]string{
+	"p1": "Org1MSP",
+	"p2": "Org1MSP",
+	"p3": "Org2MSP",
+	"p4": "Org3MSP",
+}
+
+type gossipMock struct {
+	peers []string
+}
+
+func (g *gossipMock) IdentityInfo() api.PeerIdentitySet {
+	var res api.PeerIdentitySet
+	for id, org := range peersOfOrgs {
+		res = append(res, api.PeerIdentityInfo{
+			PKIId:        common.PKIidType(id),
+			Identity:     api.PeerIdentityType(id),
+			Organization: api.OrgIdentityType(org),
+		})
+	}
+	return res
+}
+
+func (g *gossipMock) PeersOfChannel(_ common.ChainID) []discovery2.NetworkMember {
+	var res []discovery2.NetworkMember
+	for _, p := range g.peers {
+		res = append(res, discovery2.NetworkMember{
+			PKIid:      common.PKIidType(p),
+			Endpoint:   p + ":7051",
+			Properties: &gossip.Properties{LedgerHeight: 100},
+		})
+	}
+	return res
+}
+
+type policyFetcherMock map[string]string
+
+func (pf policyFetcherMock) PolicyByChaincode(_ string, cc string) *common2.SignaturePolicyEnvelope {
+	policy, exists := pf[cc]
+	if !exists {
+		return nil
+	}
+	envelope, err := cauthdsl.FromString(policy)
+	if err != nil {
+		panic(err)
+	}
+	return envelope
+}
+
+type principalEvaluatorMock struct {
+}
+
+func (*principalEvaluatorMock) SatisfiesPrincipal(_ string, identity []byte, principal *msp.MSPPrincipal) error {
+	role := &msp.MSPRole{}
+	if err := proto.Unmarshal(principal.Principal, role); err != nil {
+		return err
+	}
+	if peersOfOrgs[string(identity)] != role.MspIdentifier {
+		return errors.Errorf("%s isn't a member of %s", identity, role.MspIdentifier)
+	}
+	return nil
+}
+
+// layoutsByOrgs converts the layouts of the given descriptor into the required
+// number of endorsements from each organization, and verifies the peers of the groups
+func layoutsByOrgs(t *testing.T, desc *discovery.EndorsementDescriptor) []map[string]uint32 {
+	orgOfGroup := make(map[string]string)
+	for group, peers := range desc.EndorsersByGroups {
+		assert.NotEmpty(t, peers.Peers)
+		for _, p := range peers.Peers {
+			org := peersOfOrgs[string(p.Identity)]
+			if existing, exists := orgOfGroup[group]; exists {
+				assert.Equal(t, existing, org)
+			}
+			orgOfGroup[group] = org
+			assert.Equal(t, string(p.Identity)+":7051", p.Endpoint)
+			assert.Equal(t, uint64(100), p.LedgerHeight)
+		}
+	}
+	var res []map[string]uint32
+	for _, layout := range desc.Layouts {
+		quantities := make(map[string]uint32)
+		for group, quantity := range layout.QuantitiesByGroup {
+			quantities[orgOfGroup[group]] = quantity
+		}
+		res = append(res, quantities)
+	}
+	return res
+}
+
+// assertLayouts asserts that the given layouts are the expected ones, regardless of their order
+func assertLayouts(t *testing.T, expected, actual []map[string]uint32) {
+	ids := func(layouts []map[string]uint32) []string {
+		var res []string
+		for _, layout := range layouts {
+			res = append(res, layoutID(layout))
+		}
+		sort.Strings(res)
+		return res
+	}
+	assert.Equal(t, ids(expected), ids(actual))
+}
+
+func TestPeersForEndorsement(t *testing.T) {
+	pf := policyFetcherMock{
+		"cc1": "OR('Org1MSP.member', AND('Org2MSP.member', 'Org3MSP.member'))",
+		"cc2": "AND('Org1MSP.member', 'Org2MSP.member')",
+		"cc3": "OR('Org1MSP.member', 'Org3MSP.member')",
+		"cc4": "OutOf(2, 'Org1MSP.member', 'Org1MSP.member', 'Org2MSP.member')",
+		"cc5": "AND('Org3MSP.member', 'Org3MSP.member')",
+	}
+	g := &gossipMock{peers: []string{"p1", "p2", "p3"}}
+	ea := NewEndorsementAnalyzer(g, pf, &principalEvaluatorMock{})
+	channel := common.ChainID("mychannel")
+
+	endorse := func(chaincodes ...string) (*discovery.EndorsementDescriptor, error) {
+		interest, err := discovery.NewChaincodeInterest(chaincodes...)
+		assert.NoError(t, err)
+		return ea.PeersForEndorsement(channel, interest)
+	}
+
+	// Scenario I: no peer of Org3 is in the channel, hence only Org1 can endorse
+	desc, err := endorse("cc1")
+	assert.NoError(t, err)
+	assert.Equal(t, "cc1", desc.Chaincode)
+	assert.Equal(t, []map[string]uint32{{"Org1MSP": 1}}, layoutsByOrgs(t, desc))
+	assert.Len(t, desc.EndorsersByGroups, 1)
+
+	// Scenario II: a peer of Org3 joins the channel
+	g.peers = append(g.peers, "p4")
+	desc, err = endorse("cc1")
+	assert.NoError(t, err)
+	assertLayouts(t, []map[string]uint32{{"Org1MSP": 1}, {"Org2MSP": 1, "Org3MSP": 1}}, layoutsByOrgs(t, desc))
+
+	// Scenario III: cc2 calls cc3, and an endorsement of Org1 satisfies both
+	desc, err = endorse("cc2", "cc3")
+	assert.NoError(t, err)
+	assert.Equal(t, "cc2", desc.Chaincode)
+	assertLayouts(t, []map[string]uint32{
+		{"Org1MSP": 1, "Org2MSP": 1},
+		{"Org1MSP": 1, "Org2MSP": 1, "Org3MSP": 1},
+	}, layoutsByOrgs(t, desc))
+
+	// Scenario IV: the same principal is required twice
+	desc, err = endorse("cc4")
+	assert.NoError(t, err)
+	assertLayouts(t, []map[string]uint32{
+		{"Org1MSP": 2},
+		{"Org1MSP": 1, "Org2MSP": 1},
+	}, layoutsByOrgs(t, desc))
+
+	// Scenario V: Org3 has a single peer, hence the policy can't be satisfied
+	_, err = endorse("cc5")
+	assert.EqualError(t, err, "cannot satisfy any principal combination")
+
+	// Scenario VI: the chaincode isn't instantiated
+	_, err = endorse("cc1", "cc6")
+	assert.EqualError(t, err, "policy of chaincode cc6 not found")
+
+	_, err = ea.PeersForEndorsement(channel, &discovery.ChaincodeInterest{})
+	assert.EqualError(t, err, "no chaincodes specified")
+}
+
+func TestPrincipalSetsOfRule(t *testing.T) {
+	keys := []string{"a", "b", "c"}
+	sets, err := principalSetsOfRule(cauthdsl.NOutOf(2, []*common2.SignaturePolicy{
+		cauthdsl.SignedBy(0),
+		cauthdsl.SignedBy(1),
+		cauthdsl.Or(cauthdsl.SignedBy(0), cauthdsl.SignedBy(2)),
+	}), keys)
+	assert.NoError(t, err)
+	assert.Equal(t, []principalSet{
+		{"a": 1, "b": 1},
+		{"a": 2},
+		{"a": 1, "c": 1},
+		{"b": 1, "a": 1},
+		{"b": 1, "c": 1},
+	}, sets)
+
+	sets, err = principalSetsOfRule(cauthdsl.NOutOf(4, []*common2.SignaturePolicy{cauthdsl.SignedBy(0)}), keys)
+	assert.NoError(t, err)
+	assert.Empty(t, sets)
+
+	_, err = principalSetsOfRule(cauthdsl.SignedBy(3), keys)
+	assert.EqualError(t, err, "identity index 3 out of range")
+
+	_, err = principalSetsOfRule(&common2.SignaturePolicy{}, keys)
+	assert.Error(t, err)
+}
@@ -0,0 +1,201 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/comm"
+	common2 "github.com/hyperledger/fabric/gossip/common"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/discovery"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+)
+
+var logger = flogging.MustGetLogger("discovery")
+
+var accessDenied = wrapError(errors.New("access denied"))
+
+// certHashExtractor extracts the TLS certificate from a given context
+// and returns its hash
+type certHashExtractor func(ctx context.Context) []byte
+
+// Config defines the configuration of the discovery service
+type Config struct {
+	// TLS indicates whether the peer's gRPC server uses TLS,
+	// in which case the clients are required to bind their requests
+	// to their TLS certificate
+	TLS bool
+}
+
+type service struct {
+	config            Config
+	certHashExtractor certHashExtractor
+	Support
+}
+
+// NewService creates a new discovery service instance
+func NewService(config Config, sup Support) *service {
+	return &service{
+		config:            config,
+		certHashExtractor: comm.ExtractCertificateHashFromContext,
+		Support:           sup,
+	}
+}
+
+// Discover receives a signed request, and returns a response.
+func (s *service) Discover(ctx context.Context, request *discovery.SignedRequest) (*discovery.Response, error) {
+	addr := util.ExtractRemoteAddress(ctx)
+	req, err := s.validateStructure(ctx, request)
+	if err != nil {
+		logger.Warningf("Request from %s is malformed or invalid: %v", addr, err)
+		return nil, err
+	}
+	logger.Debugf("Processing request from %s: %v", addr, req)
+
+	var res []*discovery.QueryResult
+	for _, q := range req.Queries {
+		// Ensure the client is authorized to query the channel
+		if err := s.EligibleForService(q.Channel, common.SignedData{
+			Data:      request.Payload,
+			Signature: request.Signature,
+			Identity:  req.Authentication.ClientIdentity,
+		}); err != nil {
+			logger.Warningf("Client from %s isn't authorized to query channel %s: %v", addr, q.Channel, err)
+			res = append(res, accessDenied)
+			continue
+		}
+		res = append(res, s.processQuery(q))
+	}
+	logger.Debugf("Returning to %s a response containing: %v", addr, res)
+	return &discovery.Response{
+		Results: res,
+	}, nil
+}
+
+func (s *service) processQuery(query *discovery.Query) *discovery.QueryResult {
+	switch query.GetType() {
+	case discovery.ConfigQueryType:
+		return s.configQuery(query)
+	case discovery.PeerMembershipQueryType:
+		return s.channelMembershipResponse(query)
+	case discovery.ChaincodeQueryType:
+		return s.chaincodeQuery(query)
+	default:
+		return wrapError(errors.Errorf("unknown or missing request type"))
+	}
+}
+
+func (s *service) chaincodeQuery(q *discovery.Query) *discovery.QueryResult {
+	var descriptors []*discovery.EndorsementDescriptor
+	for _, interest := range q.GetCcQuery().Interests {
+		desc, err := s.PeersForEndorsement(common2.ChainID(q.Channel), interest)
+		if err != nil {
+			logger.Errorf("Failed constructing descriptor for chaincode %s: %v", interest, err)
+			return wrapError(errors.Errorf("failed constructing descriptor for %v", interest))
+		}
+		descriptors = append(descriptors, desc)
+	}
+
+	return &discovery.QueryResult{
+		Result: &discovery.QueryResult_CcQueryRes{
+			CcQueryRes: &discovery.ChaincodeQueryResult{
+				Content: descriptors,
+			},
+		},
+	}
+}
+
+func (s *service) configQuery(q *discovery.Query) *discovery.QueryResult {
+	conf, err := s.Config(q.Channel)
+	if err != nil {
+		logger.Errorf("Failed fetching config for channel %s: %v", q.Channel, err)
+		return wrapError(errors.Errorf("failed fetching config for channel %s", q.Channel))
+	}
+	return &discovery.QueryResult{
+		Result: &discovery.QueryResult_ConfigResult{
+			ConfigResult: conf,
+		},
+	}
+}
+
+func (s *service) channelMembershipResponse(q *discovery.Query) *discovery.QueryResult {
+	peersByOrg := make(map[string]*discovery.Peers)
+	identities := s.IdentityInfo().ByID()
+	for _, member := range s.PeersOfChannel(common2.ChainID(q.Channel)) {
+		id, exists := identities[string(member.PKIid)]
+		if !exists {
+			logger.Debug("Skipping", member.Endpoint, "because its identity isn't known")
+			continue
+		}
+		org := string(id.Organization)
+		if _, exists := peersByOrg[org]; !exists {
+			peersByOrg[org] = &discovery.Peers{}
+		}
+		var ledgerHeight uint64
+		if member.Properties != nil {
+			ledgerHeight = member.Properties.LedgerHeight
+		}
+		peersByOrg[org].Peers = append(peersByOrg[org].Peers, &discovery.Peer{
+			Endpoint:     member.Endpoint,
+			Identity:     id.Identity,
+			LedgerHeight: ledgerHeight,
+		})
+	}
+	return &discovery.QueryResult{
+		Result: &discovery.QueryResult_Members{
+			Members: &discovery.PeerMembershipResult{
+				PeersByOrg: peersByOrg,
+			},
+		},
+	}
+}
+
+// validateStructure validates that the request contains all the needed fields and that they are computed correctly
+func (s *service) validateStructure(ctx context.Context, request *discovery.SignedRequest) (*discovery.Request, error) {
+	if request == nil {
+		return nil, errors.New("nil request")
+	}
+	req, err := request.ToRequest()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed parsing request")
+	}
+	if req.Authentication == nil {
+		return nil, errors.New("access denied, no authentication info in request")
+	}
+	if len(req.Authentication.ClientIdentity) == 0 {
+		return nil, errors.New("access denied, client identity wasn't supplied")
+	}
+	if !s.config.TLS {
+		return req, nil
+	}
+	computedHash := s.certHashExtractor(ctx)
+	if len(computedHash) == 0 {
+		return nil, errors.New("client didn't send a TLS certificate")
+	}
+	if !bytes.Equal(computedHash, req.Authentication.ClientTlsCertHash) {
+		claimed := hex.EncodeToString(req.Authentication.ClientTlsCertHash)
+		logger.Warningf("client claimed TLS hash %s doesn't match computed TLS hash from gRPC stream %s", claimed, hex.EncodeToString(computedHash))
+		return nil, errors.New("client claimed TLS hash doesn't match computed TLS hash from gRPC stream")
+	}
+	return req, nil
+}
+
+func wrapError(err error) *discovery.QueryResult {
+	return &discovery.QueryResult{
+		Result: &discovery.QueryResult_Error{
+			Error: &discovery.Error{
+				Content: fmt.Sprintf("%v", err),
+			},
+		},
+	}
+}
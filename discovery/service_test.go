@@ -0,0 +1,180 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/gossip/api"
+	gcommon "github.com/hyperledger/fabric/gossip/common"
+	discovery2 "github.com/hyperledger/fabric/gossip/discovery"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/discovery"
+	"github.com/hyperledger/fabric/protos/gossip"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"golang.org/x/net/context"
+)
+
+type mockSupport struct {
+	mock.Mock
+}
+
+func (ms *mockSupport) EligibleForService(channel string, data common.SignedData) error {
+	return ms.Called(channel, data).Error(0)
+}
+
+func (ms *mockSupport) Config(channel string) (*discovery.ConfigResult, error) {
+	args := ms.Called(channel)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*discovery.ConfigResult), args.Error(1)
+}
+
+func (ms *mockSupport) PeersForEndorsement(chainID gcommon.ChainID, interest *discovery.ChaincodeInterest) (*discovery.EndorsementDescriptor, error) {
+	args := ms.Called(chainID, interest)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*discovery.EndorsementDescriptor), args.Error(1)
+}
+
+func (ms *mockSupport) PeersOfChannel(chainID gcommon.ChainID) []discovery2.NetworkMember {
+	return ms.Called(chainID).Get(0).([]discovery2.NetworkMember)
+}
+
+func (ms *mockSupport) IdentityInfo() api.PeerIdentitySet {
+	return ms.Called().Get(0).(api.PeerIdentitySet)
+}
+
+func signedRequest(t *testing.T, req *discovery.Request) *discovery.SignedRequest {
+	payload, err := proto.Marshal(req)
+	assert.NoError(t, err)
+	return &discovery.SignedRequest{Payload: payload, Signature: []byte("sig")}
+}
+
+func TestValidateStructure(t *testing.T) {
+	svc := NewService(Config{TLS: true}, &mockSupport{})
+	svc.certHashExtractor = func(_ context.Context) []byte {
+		return []byte("tlsCertHash")
+	}
+	ctx := context.Background()
+
+	_, err := svc.Discover(ctx, nil)
+	assert.EqualError(t, err, "nil request")
+
+	_, err = svc.Discover(ctx, &discovery.SignedRequest{Payload: []byte{1, 2, 3}})
+	assert.Contains(t, err.Error(), "failed parsing request")
+
+	_, err = svc.Discover(ctx, signedRequest(t, &discovery.Request{}))
+	assert.EqualError(t, err, "access denied, no authentication info in request")
+
+	_, err = svc.Discover(ctx, signedRequest(t, &discovery.Request{Authentication: &discovery.AuthInfo{}}))
+	assert.EqualError(t, err, "access denied, client identity wasn't supplied")
+
+	_, err = svc.Discover(ctx, signedRequest(t, &discovery.Request{Authentication: &discovery.AuthInfo{
+		ClientIdentity:    []byte("identity"),
+		ClientTlsCertHash: []byte("bogusHash"),
+	}}))
+	assert.EqualError(t, err, "client claimed TLS hash doesn't match computed TLS hash from gRPC stream")
+
+	svc.certHashExtractor = func(_ context.Context) []byte {
+		return nil
+	}
+	_, err = svc.Discover(ctx, signedRequest(t, &discovery.Request{Authentication: &discovery.AuthInfo{
+		ClientIdentity:    []byte("identity"),
+		ClientTlsCertHash: []byte("tlsCertHash"),
+	}}))
+	assert.EqualError(t, err, "client didn't send a TLS certificate")
+
+	// Without TLS, the TLS certificate hash isn't checked
+	svc.config.TLS = false
+	res, err := svc.Discover(ctx, signedRequest(t, &discovery.Request{Authentication: &discovery.AuthInfo{
+		ClientIdentity: []byte("identity"),
+	}}))
+	assert.NoError(t, err)
+	assert.Empty(t, res.Results)
+}
+
+func TestDiscover(t *testing.T) {
+	sup := &mockSupport{}
+	svc := NewService(Config{}, sup)
+	auth := &discovery.AuthInfo{ClientIdentity: []byte("identity")}
+
+	interest, _ := discovery.NewChaincodeInterest("cc1", "cc2")
+	badInterest, _ := discovery.NewChaincodeInterest("cc3")
+	req := &discovery.Request{
+		Authentication: auth,
+		Queries: []*discovery.Query{
+			{Channel: "forbidden", Query: &discovery.Query_ConfigQuery{ConfigQuery: &discovery.ConfigQuery{}}},
+			{Channel: "mychannel", Query: &discovery.Query_ConfigQuery{ConfigQuery: &discovery.ConfigQuery{}}},
+			{Channel: "mychannel", Query: &discovery.Query_PeerQuery{PeerQuery: &discovery.PeerMembershipQuery{}}},
+			{Channel: "mychannel", Query: &discovery.Query_CcQuery{CcQuery: &discovery.ChaincodeQuery{
+				Interests: []*discovery.ChaincodeInterest{interest},
+			}}},
+			{Channel: "mychannel", Query: &discovery.Query_CcQuery{CcQuery: &discovery.ChaincodeQuery{
+				Interests: []*discovery.ChaincodeInterest{badInterest},
+			}}},
+			{Channel: "mychannel"},
+			{Channel: "nochannel", Query: &discovery.Query_ConfigQuery{ConfigQuery: &discovery.ConfigQuery{}}},
+		},
+	}
+	sr := signedRequest(t, req)
+	signedData := common.SignedData{Data: sr.Payload, Signature: sr.Signature, Identity: auth.ClientIdentity}
+
+	configRes := &discovery.ConfigResult{Orderers: []*discovery.Endpoint{{Host: "orderer", Port: 7050}}}
+	descriptor := &discovery.EndorsementDescriptor{Chaincode: "cc1"}
+	sup.On("EligibleForService", "forbidden", signedData).Return(errors.New("forbidden"))
+	sup.On("EligibleForService", mock.Anything, mock.Anything).Return(nil)
+	sup.On("Config", "mychannel").Return(configRes, nil)
+	sup.On("Config", "nochannel").Return(nil, errors.New("channel nochannel doesn't exist"))
+	sup.On("PeersForEndorsement", gcommon.ChainID("mychannel"), interest).Return(descriptor, nil)
+	sup.On("PeersForEndorsement", gcommon.ChainID("mychannel"), badInterest).Return(nil, errors.New("no policy"))
+	sup.On("IdentityInfo").Return(api.PeerIdentitySet{
+		{PKIId: gcommon.PKIidType("p1"), Identity: api.PeerIdentityType("id1"), Organization: api.OrgIdentityType("Org1MSP")},
+		{PKIId: gcommon.PKIidType("p2"), Identity: api.PeerIdentityType("id2"), Organization: api.OrgIdentityType("Org2MSP")},
+	})
+	sup.On("PeersOfChannel", gcommon.ChainID("mychannel")).Return([]discovery2.NetworkMember{
+		{PKIid: gcommon.PKIidType("p1"), Endpoint: "p1:7051", Properties: &gossip.Properties{LedgerHeight: 10}},
+		{PKIid: gcommon.PKIidType("p2"), Endpoint: "p2:7051"},
+		// The identity of this peer isn't known, hence it is skipped
+		{PKIid: gcommon.PKIidType("p3"), Endpoint: "p3:7051"},
+	})
+
+	res, err := svc.Discover(context.Background(), sr)
+	assert.NoError(t, err)
+	assert.Len(t, res.Results, len(req.Queries))
+
+	_, errRes := res.ConfigAt(0)
+	assert.Equal(t, "access denied", errRes.Content)
+
+	conf, errRes := res.ConfigAt(1)
+	assert.Nil(t, errRes)
+	assert.Equal(t, configRes, conf)
+
+	members, errRes := res.MembershipAt(2)
+	assert.Nil(t, errRes)
+	assert.Equal(t, map[string]*discovery.Peers{
+		"Org1MSP": {Peers: []*discovery.Peer{{Endpoint: "p1:7051", Identity: []byte("id1"), LedgerHeight: 10}}},
+		"Org2MSP": {Peers: []*discovery.Peer{{Endpoint: "p2:7051", Identity: []byte("id2")}}},
+	}, members.PeersByOrg)
+
+	endorsers, errRes := res.EndorsersAt(3)
+	assert.Nil(t, errRes)
+	assert.Equal(t, []*discovery.EndorsementDescriptor{descriptor}, endorsers.Content)
+
+	_, errRes = res.EndorsersAt(4)
+	assert.Contains(t, errRes.Content, "failed constructing descriptor")
+
+	assert.Equal(t, "unknown or missing request type", res.Results[5].GetError().Content)
+
+	_, errRes = res.ConfigAt(6)
+	assert.Equal(t, "failed fetching config for channel nochannel", errRes.Content)
+}
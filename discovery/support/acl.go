@@ -0,0 +1,41 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package support
+
+import (
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/pkg/errors"
+)
+
+// ChannelPolicyManagerGetter returns the policy manager of a channel,
+// or nil if the peer isn't a member of the channel
+type ChannelPolicyManagerGetter func(channel string) policies.Manager
+
+type aclSupport struct {
+	getPolicyManager ChannelPolicyManagerGetter
+}
+
+// NewACLSupport returns an AccessControlSupport that grants service
+// to clients that satisfy the readers policy of the channel's application
+func NewACLSupport(getPolicyManager ChannelPolicyManagerGetter) *aclSupport {
+	return &aclSupport{getPolicyManager: getPolicyManager}
+}
+
+// EligibleForService returns whether the given peer is eligible for receiving
+// service from the discovery service for a given channel
+func (s *aclSupport) EligibleForService(channel string, data common.SignedData) error {
+	pm := s.getPolicyManager(channel)
+	if pm == nil {
+		return errors.Errorf("channel %s doesn't exist", channel)
+	}
+	policy, exists := pm.GetPolicy(policies.ChannelApplicationReaders)
+	if !exists {
+		return errors.Errorf("policy %s not found on channel %s", policies.ChannelApplicationReaders, channel)
+	}
+	return policy.Evaluate([]*common.SignedData{&data})
+}
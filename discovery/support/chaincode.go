@@ -0,0 +1,110 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package support
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/lifecycle"
+	"github.com/hyperledger/fabric/core/ledger"
+	msp2 "github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
+)
+
+// LedgerGetter returns the ledger of a channel,
+// or nil if the peer isn't a member of the channel
+type LedgerGetter func(channel string) ledger.PeerLedger
+
+type chaincodePolicySupport struct {
+	getLedger LedgerGetter
+}
+
+// NewChaincodePolicySupport returns a policyFetcher that reads the endorsement
+// policies of chaincodes from their definitions committed to the channels'
+// ledgers, or from the lscc namespace for the chaincodes lscc instantiated
+func NewChaincodePolicySupport(getLedger LedgerGetter) *chaincodePolicySupport {
+	return &chaincodePolicySupport{getLedger: getLedger}
+}
+
+// PolicyByChaincode returns the endorsement policy of the given chaincode
+// on the given channel, or nil if the chaincode isn't instantiated on it
+func (s *chaincodePolicySupport) PolicyByChaincode(channel string, cc string) *common.SignaturePolicyEnvelope {
+	policy, err := s.policyByChaincode(channel, cc)
+	if err != nil {
+		logger.Warningf("Failed retrieving the policy of chaincode %s on channel %s: %v", cc, channel, err)
+		return nil
+	}
+	return policy
+}
+
+func (s *chaincodePolicySupport) policyByChaincode(channel string, cc string) (*common.SignaturePolicyEnvelope, error) {
+	l := s.getLedger(channel)
+	if l == nil {
+		return nil, errors.Errorf("channel %s doesn't exist", channel)
+	}
+	qe, err := l.NewQueryExecutor()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer qe.Done()
+
+	def, err := lifecycle.QueryDefinition(qe, cc)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	var policyBytes []byte
+	if def != nil {
+		policyBytes = def.ValidationParameter
+	} else {
+		ccDataBytes, err := qe.GetState("lscc", cc)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		if ccDataBytes == nil {
+			return nil, errors.New("chaincode isn't instantiated")
+		}
+		ccData := &ccprovider.ChaincodeData{}
+		if err := proto.Unmarshal(ccDataBytes, ccData); err != nil {
+			return nil, errors.Wrap(err, "failed unmarshaling chaincode data")
+		}
+		policyBytes = ccData.Policy
+	}
+	policy := &common.SignaturePolicyEnvelope{}
+	if err := proto.Unmarshal(policyBytes, policy); err != nil {
+		return nil, errors.Wrap(err, "failed unmarshaling endorsement policy")
+	}
+	return policy, nil
+}
+
+// IdentityDeserializerGetter returns the identity deserializer of a channel
+type IdentityDeserializerGetter func(channel string) msp2.IdentityDeserializer
+
+type principalEvaluator struct {
+	getDeserializer IdentityDeserializerGetter
+}
+
+// NewPrincipalEvaluator returns a principalEvaluator that evaluates
+// principals using the MSPs of the channels
+func NewPrincipalEvaluator(getDeserializer IdentityDeserializerGetter) *principalEvaluator {
+	return &principalEvaluator{getDeserializer: getDeserializer}
+}
+
+// SatisfiesPrincipal returns whether a given peer identity satisfies a certain principal
+// on a given channel
+func (pe *principalEvaluator) SatisfiesPrincipal(channel string, identity []byte, principal *msp.MSPPrincipal) error {
+	deserializer := pe.getDeserializer(channel)
+	if deserializer == nil {
+		return errors.Errorf("channel %s doesn't exist", channel)
+	}
+	id, err := deserializer.DeserializeIdentity(identity)
+	if err != nil {
+		return errors.Wrap(err, "failed deserializing identity")
+	}
+	return id.SatisfiesPrincipal(principal)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package support
+
+import (
+	"net"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	msp2 "github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/discovery"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/pkg/errors"
+)
+
+// ChannelConfigGetter returns the configuration resources of a channel,
+// or nil if the peer isn't a member of the channel
+type ChannelConfigGetter func(channel string) channelconfig.Resources
+
+type configSupport struct {
+	getChannelConfig ChannelConfigGetter
+}
+
+// NewConfigSupport returns a ConfigSupport that extracts the MSPs
+// and the orderers of channels from their configuration
+func NewConfigSupport(getChannelConfig ChannelConfigGetter) *configSupport {
+	return &configSupport{getChannelConfig: getChannelConfig}
+}
+
+// Config returns the channel's configuration
+func (s *configSupport) Config(channel string) (*discovery.ConfigResult, error) {
+	res := s.getChannelConfig(channel)
+	if res == nil {
+		return nil, errors.Errorf("channel %s doesn't exist", channel)
+	}
+	conf := res.ConfigtxValidator().ConfigProto()
+	if conf == nil || conf.ChannelGroup == nil {
+		return nil, errors.Errorf("config of channel %s is empty", channel)
+	}
+
+	msps := make(map[string]*msp.FabricMSPConfig)
+	for _, groupKey := range []string{channelconfig.ApplicationGroupKey, channelconfig.OrdererGroupKey} {
+		group, exists := conf.ChannelGroup.Groups[groupKey]
+		if !exists {
+			continue
+		}
+		if err := appendMSPConfigs(group, msps); err != nil {
+			return nil, errors.WithMessage(err, "failed extracting MSP configs of group "+groupKey)
+		}
+	}
+
+	var orderers []*discovery.Endpoint
+	for _, addr := range res.ChannelConfig().OrdererAddresses() {
+		host, portStr, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed parsing orderer address %s", addr)
+		}
+		port, err := strconv.ParseUint(portStr, 10, 32)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed parsing port of orderer address %s", addr)
+		}
+		orderers = append(orderers, &discovery.Endpoint{Host: host, Port: uint32(port)})
+	}
+
+	return &discovery.ConfigResult{
+		Msps:     msps,
+		Orderers: orderers,
+	}, nil
+}
+
+func appendMSPConfigs(group *common.ConfigGroup, msps map[string]*msp.FabricMSPConfig) error {
+	for orgName, org := range group.Groups {
+		value, exists := org.Values[channelconfig.MSPKey]
+		if !exists {
+			return errors.Errorf("MSP of organization %s is missing", orgName)
+		}
+		mspConfig := &msp.MSPConfig{}
+		if err := proto.Unmarshal(value.Value, mspConfig); err != nil {
+			return errors.Wrapf(err, "failed unmarshaling MSP config of organization %s", orgName)
+		}
+		if mspConfig.Type != int32(msp2.FABRIC) {
+			continue
+		}
+		fabricConfig := &msp.FabricMSPConfig{}
+		if err := proto.Unmarshal(mspConfig.Config, fabricConfig); err != nil {
+			return errors.Wrapf(err, "failed unmarshaling fabric MSP config of organization %s", orgName)
+		}
+		msps[fabricConfig.Name] = fabricConfig
+	}
+	return nil
+}
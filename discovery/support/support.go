@@ -0,0 +1,37 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package support
+
+import (
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/discovery"
+)
+
+var logger = flogging.MustGetLogger("discovery/support")
+
+// DiscoverySupport aggregates all the support needed for the discovery service
+type DiscoverySupport struct {
+	discovery.AccessControlSupport
+	discovery.GossipSupport
+	discovery.EndorsementSupport
+	discovery.ConfigSupport
+}
+
+// NewDiscoverySupport returns an aggregated discovery support
+func NewDiscoverySupport(
+	access discovery.AccessControlSupport,
+	gossip discovery.GossipSupport,
+	endorsement discovery.EndorsementSupport,
+	config discovery.ConfigSupport,
+) *DiscoverySupport {
+	return &DiscoverySupport{
+		AccessControlSupport: access,
+		GossipSupport:        gossip,
+		EndorsementSupport:   endorsement,
+		ConfigSupport:        config,
+	}
+}
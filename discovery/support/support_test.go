@@ -0,0 +1,177 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package support
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	mockchannelconfig "github.com/hyperledger/fabric/common/mocks/config"
+	mockconfigtx "github.com/hyperledger/fabric/common/mocks/configtx"
+	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/core/common/ccprovider"
+	"github.com/hyperledger/fabric/core/common/lifecycle"
+	"github.com/hyperledger/fabric/core/ledger"
+	msp2 "github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/discovery"
+	"github.com/hyperledger/fabric/protos/msp"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestACLSupport(t *testing.T) {
+	pm := &mockpolicies.Manager{
+		PolicyMap: map[string]policies.Policy{
+			policies.ChannelApplicationReaders: &mockpolicies.Policy{Err: errors.New("signature mismatch")},
+		},
+	}
+	acl := NewACLSupport(func(channel string) policies.Manager {
+		if channel == "mychannel" {
+			return pm
+		}
+		return nil
+	})
+
+	err := acl.EligibleForService("nochannel", common.SignedData{})
+	assert.EqualError(t, err, "channel nochannel doesn't exist")
+
+	err = acl.EligibleForService("mychannel", common.SignedData{})
+	assert.EqualError(t, err, "signature mismatch")
+
+	pm.PolicyMap[policies.ChannelApplicationReaders] = &mockpolicies.Policy{}
+	assert.NoError(t, acl.EligibleForService("mychannel", common.SignedData{}))
+
+	pm.PolicyMap = map[string]policies.Policy{}
+	err = acl.EligibleForService("mychannel", common.SignedData{})
+	assert.Contains(t, err.Error(), "not found on channel mychannel")
+}
+
+func orgGroup(t *testing.T, mspID string, mspType msp2.ProviderType) *common.ConfigGroup {
+	fabricConfig, err := proto.Marshal(&msp.FabricMSPConfig{Name: mspID, RootCerts: [][]byte{[]byte("root")}})
+	assert.NoError(t, err)
+	mspConfig, err := proto.Marshal(&msp.MSPConfig{Type: int32(mspType), Config: fabricConfig})
+	assert.NoError(t, err)
+	return &common.ConfigGroup{
+		Values: map[string]*common.ConfigValue{
+			channelconfig.MSPKey: {Value: mspConfig},
+		},
+	}
+}
+
+func TestConfigSupport(t *testing.T) {
+	conf := &common.Config{
+		ChannelGroup: &common.ConfigGroup{
+			Groups: map[string]*common.ConfigGroup{
+				channelconfig.ApplicationGroupKey: {
+					Groups: map[string]*common.ConfigGroup{
+						"Org1": orgGroup(t, "Org1MSP", msp2.FABRIC),
+						"Org2": orgGroup(t, "Org2MSP", msp2.IDEMIX),
+					},
+				},
+				channelconfig.OrdererGroupKey: {
+					Groups: map[string]*common.ConfigGroup{
+						"OrdererOrg": orgGroup(t, "OrdererMSP", msp2.FABRIC),
+					},
+				},
+			},
+		},
+	}
+	resources := &mockchannelconfig.Resources{
+		ConfigtxValidatorVal: &mockconfigtx.Validator{ConfigProtoVal: conf},
+		ChannelConfigVal: &mockchannelconfig.Channel{
+			OrdererAddressesVal: []string{"orderer1:7050", "orderer2:8050"},
+		},
+	}
+	cs := NewConfigSupport(func(channel string) channelconfig.Resources {
+		if channel == "mychannel" {
+			return resources
+		}
+		return nil
+	})
+
+	_, err := cs.Config("nochannel")
+	assert.EqualError(t, err, "channel nochannel doesn't exist")
+
+	res, err := cs.Config("mychannel")
+	assert.NoError(t, err)
+	assert.Len(t, res.Msps, 2)
+	assert.Equal(t, [][]byte{[]byte("root")}, res.Msps["Org1MSP"].RootCerts)
+	assert.Equal(t, "OrdererMSP", res.Msps["OrdererMSP"].Name)
+	assert.Equal(t, []*discovery.Endpoint{{Host: "orderer1", Port: 7050}, {Host: "orderer2", Port: 8050}}, res.Orderers)
+
+	resources.ChannelConfigVal = &mockchannelconfig.Channel{OrdererAddressesVal: []string{"orderer1"}}
+	_, err = cs.Config("mychannel")
+	assert.Contains(t, err.Error(), "failed parsing orderer address orderer1")
+
+	delete(conf.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Groups["OrdererOrg"].Values, channelconfig.MSPKey)
+	_, err = cs.Config("mychannel")
+	assert.Contains(t, err.Error(), "MSP of organization OrdererOrg is missing")
+}
+
+type mockQueryExecutor struct {
+	ledger.QueryExecutor
+	state     map[string][]byte
+	lifecycle map[string][]byte
+}
+
+func (qe *mockQueryExecutor) GetState(namespace string, key string) ([]byte, error) {
+	switch namespace {
+	case "lscc":
+		return qe.state[key], nil
+	case lifecycle.Namespace:
+		return qe.lifecycle[key], nil
+	default:
+		return nil, errors.New("unexpected namespace")
+	}
+}
+
+func (qe *mockQueryExecutor) Done() {
+}
+
+type mockLedger struct {
+	ledger.PeerLedger
+	qe *mockQueryExecutor
+}
+
+func (l *mockLedger) NewQueryExecutor() (ledger.QueryExecutor, error) {
+	return l.qe, nil
+}
+
+func TestChaincodePolicySupport(t *testing.T) {
+	policy := cauthdsl.SignedByMspMember("Org1MSP")
+	policyBytes, _ := proto.Marshal(policy)
+	ccData, _ := proto.Marshal(&ccprovider.ChaincodeData{Name: "mycc", Version: "1.0", Policy: policyBytes})
+	definedPolicy := cauthdsl.SignedByMspMember("Org2MSP")
+	definedPolicyBytes, _ := proto.Marshal(definedPolicy)
+	definition, _ := proto.Marshal(&lb.ChaincodeDefinition{Sequence: 1, Version: "1.0", ValidationParameter: definedPolicyBytes})
+	l := &mockLedger{qe: &mockQueryExecutor{state: map[string][]byte{
+		"mycc":  ccData,
+		"badcc": {1, 2, 3},
+	}, lifecycle: map[string][]byte{
+		lifecycle.DefinitionKey("definedcc"): definition,
+		lifecycle.DefinitionKey("baddefcc"):  {1, 2, 3},
+	}}}
+	pf := NewChaincodePolicySupport(func(channel string) ledger.PeerLedger {
+		if channel == "mychannel" {
+			return l
+		}
+		return nil
+	})
+
+	assert.True(t, proto.Equal(policy, pf.PolicyByChaincode("mychannel", "mycc")))
+	assert.Nil(t, pf.PolicyByChaincode("nochannel", "mycc"))
+	assert.Nil(t, pf.PolicyByChaincode("mychannel", "nocc"))
+	assert.Nil(t, pf.PolicyByChaincode("mychannel", "badcc"))
+	// the policy of a committed definition is used
+	assert.True(t, proto.Equal(definedPolicy, pf.PolicyByChaincode("mychannel", "definedcc")))
+	assert.Nil(t, pf.PolicyByChaincode("mychannel", "baddefcc"))
+}
@@ -14,6 +14,9 @@ require (
 	github.com/alecthomas/template v0.0.0-20160405071501-a0175ee3bccc // indirect
 	github.com/alecthomas/units v0.0.0-20151022065526-2efee857e7cf // indirect
 	github.com/cactus/go-statsd-client/statsd v0.0.0-20190501063751-9a7692639588
+	github.com/coreos/etcd v3.3.10+incompatible
+	github.com/coreos/go-systemd v0.0.0-20180511133405-39ca1b05acc7 // indirect
+	github.com/coreos/pkg v0.0.0-20180108230652-97fdf19511ea // indirect
 	github.com/davecgh/go-spew v0.0.0-20151105211317-5215b55f46b2
 	github.com/docker/docker v0.0.0-20160726091210-bad654b00c95
 	github.com/docker/engine-api v0.0.0-20160530215809-6facb3f3c387 // indirect
@@ -23,6 +26,7 @@ require (
 	github.com/eapache/queue v1.1.0 // indirect
 	github.com/fatih/color v1.7.0 // indirect
 	github.com/fsouza/go-dockerclient v0.0.0-20160725134423-d433254bb83b
+	github.com/gogo/protobuf v1.2.0 // indirect
 	github.com/golang/groupcache v0.0.0-20190129154638-5b532d6fd5ef
 	github.com/golang/protobuf v1.2.0
 	github.com/golang/snappy v0.0.0-20160529050041-d9eb7a3d35ec // indirect
@@ -6,8 +6,25 @@ SPDX-License-Identifier: Apache-2.0
 
 package api
 
-import "testing"
+import (
+	"testing"
 
-func TestNoop(t *testing.T) {
-	// This is just to make this package included in the code-coverage statistics
+	"github.com/hyperledger/fabric/gossip/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPeerIdentitySet(t *testing.T) {
+	p1 := PeerIdentityInfo{PKIId: common.PKIidType("p1"), Identity: PeerIdentityType("id1"), Organization: OrgIdentityType("A")}
+	p2 := PeerIdentityInfo{PKIId: common.PKIidType("p2"), Identity: PeerIdentityType("id2"), Organization: OrgIdentityType("A")}
+	p3 := PeerIdentityInfo{PKIId: common.PKIidType("p3"), Identity: PeerIdentityType("id3"), Organization: OrgIdentityType("B")}
+	set := PeerIdentitySet{p1, p2, p3}
+
+	byOrg := set.ByOrg()
+	assert.Len(t, byOrg, 2)
+	assert.Equal(t, PeerIdentitySet{p1, p2}, byOrg["A"])
+	assert.Equal(t, PeerIdentitySet{p3}, byOrg["B"])
+
+	byID := set.ByID()
+	assert.Len(t, byID, 3)
+	assert.Equal(t, p2, byID["p2"])
 }
@@ -11,6 +11,7 @@ import (
 	"sync"
 	"sync/atomic"
 
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/gossip/common"
 	"github.com/hyperledger/fabric/gossip/util"
 	proto "github.com/hyperledger/fabric/protos/gossip"
@@ -191,6 +192,7 @@ func (cs *connectionStore) closeByPKIid(pkiID common.PKIidType) {
 }
 
 func newConnection(cl proto.GossipClient, c *grpc.ClientConn, cs proto.Gossip_GossipStreamClient, ss proto.Gossip_GossipStreamServer) *connection {
+	scope := metrics.RootScope.SubScope("gossip").SubScope("comm")
 	connection := &connection{
 		outBuff:      make(chan *msgSending, util.GetIntOrDefault("peer.gossip.sendBuffSize", defSendBuffSize)),
 		cl:           cl,
@@ -199,6 +201,8 @@ func newConnection(cl proto.GossipClient, c *grpc.ClientConn, cs proto.Gossip_Go
 		serverStream: ss,
 		stopFlag:     int32(0),
 		stopChan:     make(chan struct{}, 1),
+		msgsReceived: scope.Counter("messages_received"),
+		msgsSent:     scope.Counter("messages_sent"),
 	}
 	return connection
 }
@@ -216,6 +220,8 @@ type connection struct {
 	serverStream proto.Gossip_GossipStreamServer // server-side stream to remote endpoint
 	stopFlag     int32                           // indicates whether this connection is in process of stopping
 	stopChan     chan struct{}                   // a method to stop the server-side gRPC call from a different go-routine
+	msgsReceived metrics.Counter                 // counts the messages received from the remote endpoint
+	msgsSent     metrics.Counter                 // counts the messages sent to the remote endpoint
 	sync.RWMutex                                 // synchronizes access to shared variables
 }
 
@@ -298,6 +304,7 @@ func (conn *connection) serviceConnection() error {
 		case err := <-errChan:
 			return err
 		case msg := <-msgChan:
+			conn.msgsReceived.Inc(1)
 			conn.handler(msg)
 		}
 	}
@@ -318,6 +325,7 @@ func (conn *connection) writeToStream() {
 				go m.onErr(err)
 				return
 			}
+			conn.msgsSent.Inc(1)
 		case stop := <-conn.stopChan:
 			conn.logger.Debug("Closing writing to stream")
 			conn.stopChan <- stop
@@ -723,6 +723,16 @@ func (g *gossipServiceImpl) PeersOfChannel(channel common.ChainID) []discovery.N
 	return gc.GetPeers()
 }
 
+// IdentityInfo returns information about the known peer identities
+func (g *gossipServiceImpl) IdentityInfo() api.PeerIdentitySet {
+	var res api.PeerIdentitySet
+	for _, id := range g.idMapper.IdentityInfo() {
+		id.Organization = g.secAdvisor.OrgByPeerIdentity(id.Identity)
+		res = append(res, id)
+	}
+	return res
+}
+
 // PeerFilter receives a SubChannelSelectionCriteria and returns a RoutingFilter that selects
 // only peer identities that match the given criteria, and that they published their channel participation
 func (g *gossipServiceImpl) PeerFilter(channel common.ChainID, messagePredicate api.SubChannelSelectionCriteria) (filter.RoutingFilter, error) {
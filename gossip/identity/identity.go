@@ -47,6 +47,10 @@ type Mapper interface {
 	// SuspectPeers re-validates all peers that match the given predicate
 	SuspectPeers(isSuspected api.PeerSuspector)
 
+	// IdentityInfo returns information about the known identities.
+	// The organizations of the identities are not populated
+	IdentityInfo() api.PeerIdentitySet
+
 	// Stop stops all background computations of the Mapper
 	Stop()
 }
@@ -180,6 +184,21 @@ func (is *identityMapperImpl) GetPKIidOfCert(identity api.PeerIdentityType) comm
 	return is.mcs.GetPKIidOfCert(identity)
 }
 
+// IdentityInfo returns information about the known identities.
+// Retrieving the identities doesn't count as a usage of them
+func (is *identityMapperImpl) IdentityInfo() api.PeerIdentitySet {
+	is.RLock()
+	defer is.RUnlock()
+	var res api.PeerIdentitySet
+	for _, storedIdentity := range is.pkiID2Cert {
+		res = append(res, api.PeerIdentityInfo{
+			PKIId:    storedIdentity.pkiID,
+			Identity: storedIdentity.peerIdentity,
+		})
+	}
+	return res
+}
+
 // SuspectPeers re-validates all peers that match the given predicate
 func (is *identityMapperImpl) SuspectPeers(isSuspected api.PeerSuspector) {
 	for _, identity := range is.validateIdentities(isSuspected) {
@@ -123,6 +123,19 @@ func TestGet(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestIdentityInfo(t *testing.T) {
+	idStore := NewIdentityMapper(msgCryptoService, dummyID, noopPurgeTrigger)
+	identity := []byte("yacovm")
+	pkiID := msgCryptoService.GetPKIidOfCert(api.PeerIdentityType(identity))
+	assert.NoError(t, idStore.Put(pkiID, identity))
+	idSet := idStore.IdentityInfo().ByID()
+	assert.Len(t, idSet, 2)
+	assert.Equal(t, api.PeerIdentityType(identity), idSet[string(pkiID)].Identity)
+	assert.Equal(t, pkiID, idSet[string(pkiID)].PKIId)
+	selfPKIID := msgCryptoService.GetPKIidOfCert(dummyID)
+	assert.Equal(t, dummyID, idSet[string(selfPKIID)].Identity)
+}
+
 func TestVerify(t *testing.T) {
 	idStore := NewIdentityMapper(msgCryptoService, dummyID, noopPurgeTrigger)
 	identity := []byte("yacovm")
@@ -154,6 +154,19 @@ func (mock *committerMock) CommitWithPvtData(blockAndPvtData *ledger.BlockAndPvt
 	return args.Error(0)
 }
 
+func (mock *committerMock) CommitPvtDataOfOldBlocks(blocksPvtData []*ledger.BlockPvtData) error {
+	args := mock.Called(blocksPvtData)
+	return args.Error(0)
+}
+
+func (mock *committerMock) GetMissingPvtDataInfoForMostRecentBlocks(maxBlocks int) (ledger.MissingPvtDataInfo, error) {
+	args := mock.Called(maxBlocks)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(ledger.MissingPvtDataInfo), args.Error(1)
+}
+
 func (mock *committerMock) GetPvtDataAndBlockByNum(seqNum uint64) (*ledger.BlockAndPvtData, error) {
 	args := mock.Called(seqNum)
 	if args.Get(0) == nil {
@@ -348,6 +361,10 @@ func (cap *collectionAccessPolicy) MaximumPeerCount() int {
 	return 2
 }
 
+func (cap *collectionAccessPolicy) BlockToLive() uint64 {
+	return 0
+}
+
 func (cap *collectionAccessPolicy) AccessFilter() privdata.Filter {
 	return func(sd common.SignedData) bool {
 		that, _ := asn1.Marshal(sd)
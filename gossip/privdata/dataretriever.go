@@ -17,8 +17,9 @@ import (
 // StorageDataRetriever defines an API to retrieve private date from the storage
 type StorageDataRetriever interface {
 	// CollectionRWSet retrieves for give digest relevant private data if
-	// available otherwise returns nil
-	CollectionRWSet(dig *gossip2.PvtDataDigest) []util.PrivateRWSet
+	// available otherwise returns nil. Private data that is expired according
+	// to the given block-to-live is not returned
+	CollectionRWSet(dig *gossip2.PvtDataDigest, blockToLive uint64) []util.PrivateRWSet
 }
 
 // DataStore defines set of APIs need to get private data
@@ -48,8 +49,9 @@ func NewDataRetriever(store DataStore) StorageDataRetriever {
 }
 
 // CollectionRWSet retrieves for give digest relevant private data if
-// available otherwise returns nil
-func (dr *dataRetriever) CollectionRWSet(dig *gossip2.PvtDataDigest) []util.PrivateRWSet {
+// available otherwise returns nil. Private data that is expired according
+// to the given block-to-live is not returned
+func (dr *dataRetriever) CollectionRWSet(dig *gossip2.PvtDataDigest, blockToLive uint64) []util.PrivateRWSet {
 	filter := map[string]ledger.PvtCollFilter{
 		dig.Namespace: map[string]bool{
 			dig.Collection: true,
@@ -97,6 +99,11 @@ func (dr *dataRetriever) CollectionRWSet(dig *gossip2.PvtDataDigest) []util.Priv
 			pRWsets = append(pRWsets, dr.extractPvtRWsets(rws.NsPvtRwset, dig.Namespace, dig.Collection)...)
 		}
 	} else { // Since ledger height is above block sequence number private data is available in the ledger
+		if isExpired(dig.BlockSeq, blockToLive, height) {
+			logger.Debug("Private data for collection", dig.Collection, "txID", dig.TxId, "committed in block",
+				dig.BlockSeq, "has expired at ledger height", height, ", block to live is", blockToLive)
+			return nil
+		}
 		pvtData, err := dr.store.GetPvtDataByNum(dig.BlockSeq, filter)
 		if err != nil {
 			logger.Error("Wasn't able to obtain private data for collection", dig.Collection,
@@ -114,6 +121,21 @@ func (dr *dataRetriever) CollectionRWSet(dig *gossip2.PvtDataDigest) []util.Priv
 	return pRWsets
 }
 
+// isExpired returns true if private data committed in block `committingBlk` is expired
+// once the ledger reached the given height. Private data committed in block N
+// is purged when block N+blockToLive+1 is committed, and a zero block-to-live means no expiry
+func isExpired(committingBlk, blockToLive, height uint64) bool {
+	if blockToLive == 0 {
+		return false
+	}
+	expiringBlk := committingBlk + blockToLive + 1
+	if expiringBlk <= committingBlk {
+		// overflow, the data never expires
+		return false
+	}
+	return height > expiringBlk
+}
+
 func (dr *dataRetriever) extractPvtRWsets(pvtRWSets []*rwset.NsPvtReadWriteSet, namespace string, collectionName string) []util.PrivateRWSet {
 	pRWsets := []util.PrivateRWSet{}
 
@@ -114,7 +114,7 @@ func TestNewDataRetriever_GetDataFromTransientStore(t *testing.T) {
 		BlockSeq:   2,
 		TxId:       "testTxID",
 		SeqInBlock: 1,
-	})
+	}, 0)
 
 	assertion := assert.New(t)
 	assertion.NotNil(rwSets)
@@ -179,7 +179,7 @@ func TestNewDataRetriever_GetDataFromLedger(t *testing.T) {
 		BlockSeq:   uint64(5),
 		TxId:       "testTxID",
 		SeqInBlock: 1,
-	})
+	}, 0)
 
 	assertion := assert.New(t)
 	assertion.NotNil(rwSets)
@@ -217,7 +217,7 @@ func TestNewDataRetriever_FailGetPvtDataFromLedger(t *testing.T) {
 		BlockSeq:   uint64(5),
 		TxId:       "testTxID",
 		SeqInBlock: 1,
-	})
+	}, 0)
 
 	assertion := assert.New(t)
 	assertion.NotNil(rwSets)
@@ -282,7 +282,7 @@ func TestNewDataRetriever_GetOnlyRelevantPvtData(t *testing.T) {
 		BlockSeq:   uint64(5),
 		TxId:       "testTxID",
 		SeqInBlock: 1,
-	})
+	}, 0)
 
 	assertion := assert.New(t)
 	assertion.NotNil(rwSets)
@@ -299,3 +299,47 @@ func TestNewDataRetriever_GetOnlyRelevantPvtData(t *testing.T) {
 	assertion.Equal([]byte{1, 2}, mergedRWSet)
 
 }
+
+func TestNewDataRetriever_ExpiredPvtData(t *testing.T) {
+	t.Parallel()
+	dataStore := &mockedDataStore{}
+
+	namespace := "testChaincodeName1"
+	collectionName := "testCollectionName"
+
+	result := []*ledger.TxPvtData{{
+		WriteSet: &rwset.TxPvtReadWriteSet{
+			DataModel: rwset.TxReadWriteSet_KV,
+			NsPvtRwset: []*rwset.NsPvtReadWriteSet{
+				{
+					Namespace: namespace,
+					CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{{
+						CollectionName: collectionName,
+						Rwset:          []byte{1, 2},
+					}},
+				},
+			},
+		},
+		SeqInBlock: 1,
+	}}
+
+	dataStore.On("LedgerHeight").Return(uint64(10), nil)
+	dataStore.On("GetPvtDataByNum", uint64(5), mock.Anything).Return(result, nil)
+
+	retriever := NewDataRetriever(dataStore)
+	dig := &gossip2.PvtDataDigest{
+		Namespace:  namespace,
+		Collection: collectionName,
+		BlockSeq:   uint64(5),
+		TxId:       "testTxID",
+		SeqInBlock: 1,
+	}
+
+	assertion := assert.New(t)
+	// Private data committed in block 5 with block to live 3 is purged when block 9 is committed
+	assertion.Empty(retriever.CollectionRWSet(dig, 3))
+	// With block to live 4 the private data is still available at ledger height 10
+	assertion.Len(retriever.CollectionRWSet(dig, 4), 1)
+	// Zero block to live means the private data never expires
+	assertion.Len(retriever.CollectionRWSet(dig, 0), 1)
+}
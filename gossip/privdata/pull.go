@@ -35,8 +35,9 @@ const (
 // PrivateDataRetriever interfacce which defines API capable
 // of retrieving required private data
 type PrivateDataRetriever interface {
-	// CollectionRWSet returns the bytes of CollectionPvtReadWriteSet for a given txID and collection from the transient store
-	CollectionRWSet(dig *proto.PvtDataDigest) []util.PrivateRWSet
+	// CollectionRWSet returns the bytes of CollectionPvtReadWriteSet for a given txID and collection from the transient store.
+	// Private data that is expired according to the given block-to-live is not returned
+	CollectionRWSet(dig *proto.PvtDataDigest, blockToLive uint64) []util.PrivateRWSet
 }
 
 // gossip defines capabilities that the gossip module gives the Coordinator
@@ -159,7 +160,7 @@ func (p *puller) createResponse(message proto.ReceivedMessage) []*proto.PvtDataE
 			continue
 		}
 
-		rwSets := p.CollectionRWSet(dig)
+		rwSets := p.CollectionRWSet(dig, colAP.BlockToLive())
 		logger.Debug("Found", len(rwSets), "for TxID", dig.TxId, ", collection", dig.Collection, "for", message.GetConnectionInfo().Endpoint)
 		if len(rwSets) == 0 {
 			continue
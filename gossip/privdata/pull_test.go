@@ -99,12 +99,16 @@ func (mc *mockCollectionAccess) MaximumPeerCount() int {
 	return 0
 }
 
+func (mc *mockCollectionAccess) BlockToLive() uint64 {
+	return 0
+}
+
 type dataRetrieverMock struct {
 	mock.Mock
 }
 
-func (dr *dataRetrieverMock) CollectionRWSet(dig *proto.PvtDataDigest) []util.PrivateRWSet {
-	return dr.Called(dig).Get(0).([]util.PrivateRWSet)
+func (dr *dataRetrieverMock) CollectionRWSet(dig *proto.PvtDataDigest, blockToLive uint64) []util.PrivateRWSet {
+	return dr.Called(dig, blockToLive).Get(0).([]util.PrivateRWSet)
 }
 
 type receivedMsg struct {
@@ -236,10 +240,10 @@ func TestPullerFromOnly1Peer(t *testing.T) {
 		Collection: "col1",
 		Namespace:  "ns1",
 	}
-	p2.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig).Return(p2TransientStore)
+	p2.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig, uint64(0)).Return(p2TransientStore)
 
 	p3 := gn.newPuller("p3", newCollectionStore())
-	p3.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig).Run(func(_ mock.Arguments) {
+	p3.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig, uint64(0)).Run(func(_ mock.Arguments) {
 		t.Fatal("p3 shouldn't have been selected for pull")
 	})
 
@@ -269,10 +273,10 @@ func TestPullerDataNotAvailable(t *testing.T) {
 		Namespace:  "ns1",
 	}
 
-	p2.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig).Return([]util.PrivateRWSet{})
+	p2.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig, uint64(0)).Return([]util.PrivateRWSet{})
 
 	p3 := gn.newPuller("p3", newCollectionStore())
-	p3.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig).Run(func(_ mock.Arguments) {
+	p3.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig, uint64(0)).Run(func(_ mock.Arguments) {
 		t.Fatal("p3 shouldn't have been selected for pull")
 	})
 
@@ -328,13 +332,13 @@ func TestPullerPeerNotEligible(t *testing.T) {
 		Namespace:  "ns1",
 	}
 
-	p2.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig).Run(func(_ mock.Arguments) {
+	p2.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig, uint64(0)).Run(func(_ mock.Arguments) {
 		t.Fatal("p2 shouldn't have approved the pull")
 	})
 
 	policyStore = newCollectionStore().withPolicy("col1").thatMapsTo("p3")
 	p3 := gn.newPuller("p3", policyStore)
-	p3.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig).Run(func(_ mock.Arguments) {
+	p3.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig, uint64(0)).Run(func(_ mock.Arguments) {
 		t.Fatal("p3 shouldn't have approved the pull")
 	})
 	dasf := &digestsAndSourceFactory{}
@@ -362,7 +366,7 @@ func TestPullerDifferentPeersDifferentCollections(t *testing.T) {
 		Namespace:  "ns1",
 	}
 
-	p2.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig1).Return(p2TransientStore)
+	p2.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig1, uint64(0)).Return(p2TransientStore)
 
 	p3TransientStore := newPRWSet()
 	policyStore = newCollectionStore().withPolicy("col3").thatMapsTo("p1")
@@ -373,7 +377,7 @@ func TestPullerDifferentPeersDifferentCollections(t *testing.T) {
 		Namespace:  "ns1",
 	}
 
-	p3.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig2).Return(p3TransientStore)
+	p3.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig2, uint64(0)).Return(p3TransientStore)
 
 	dasf := &digestsAndSourceFactory{}
 	fetchedMessages, err := p1.fetch(dasf.mapDigest(dig1).toSources().mapDigest(dig2).toSources().create())
@@ -411,22 +415,22 @@ func TestPullerRetries(t *testing.T) {
 	// p2
 	policyStore = newCollectionStore().withPolicy("col1").thatMapsTo("p2")
 	p2 := gn.newPuller("p2", policyStore)
-	p2.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig).Return(transientStore)
+	p2.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig, uint64(0)).Return(transientStore)
 
 	// p3
 	policyStore = newCollectionStore().withPolicy("col1").thatMapsTo("p1")
 	p3 := gn.newPuller("p3", policyStore)
-	p3.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig).Return(transientStore)
+	p3.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig, uint64(0)).Return(transientStore)
 
 	// p4
 	policyStore = newCollectionStore().withPolicy("col1").thatMapsTo("p4")
 	p4 := gn.newPuller("p4", policyStore)
-	p4.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig).Return(transientStore)
+	p4.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig, uint64(0)).Return(transientStore)
 
 	// p5
 	policyStore = newCollectionStore().withPolicy("col1").thatMapsTo("p5")
 	p5 := gn.newPuller("p5", policyStore)
-	p5.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig).Return(transientStore)
+	p5.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig, uint64(0)).Return(transientStore)
 
 	// Fetch from someone
 	dasf := &digestsAndSourceFactory{}
@@ -472,11 +476,11 @@ func TestPullerPreferEndorsers(t *testing.T) {
 
 	// We only define an action for dig2 on p2, and the test would fail with panic if any other peer is asked for
 	// a private RWSet on dig2
-	p2.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig2).Return(p2TransientStore)
+	p2.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig2, uint64(0)).Return(p2TransientStore)
 
 	// We only define an action for dig1 on p3, and the test would fail with panic if any other peer is asked for
 	// a private RWSet on dig1
-	p3.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig1).Return(p3TransientStore)
+	p3.PrivateDataRetriever.(*dataRetrieverMock).On("CollectionRWSet", dig1, uint64(0)).Return(p3TransientStore)
 
 	dasf := &digestsAndSourceFactory{}
 	d2s := dasf.mapDigest(dig1).toSources("p3").mapDigest(dig2).toSources().create()
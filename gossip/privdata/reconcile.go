@@ -0,0 +1,288 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"encoding/hex"
+	"sync"
+	"time"
+
+	util2 "github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/committer"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/core/ledger/kvledger/txmgmt/rwsetutil"
+	"github.com/hyperledger/fabric/protos/common"
+	gossip2 "github.com/hyperledger/fabric/protos/gossip"
+	"github.com/hyperledger/fabric/protos/peer"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+const (
+	reconcileSleepIntervalConfigKey = "peer.gossip.pvtData.reconcileSleepInterval"
+	reconcileSleepIntervalDefault   = time.Minute
+	reconcileBatchSizeConfigKey     = "peer.gossip.pvtData.reconcileBatchSize"
+	reconcileBatchSizeDefault       = 10
+	reconciliationEnabledConfigKey  = "peer.gossip.pvtData.reconciliationEnabled"
+)
+
+// PvtDataReconciler completes the private data that was missing
+// at the time of the commit of the corresponding blocks
+type PvtDataReconciler interface {
+	// Start starts the periodic reconciliation of the missing private data
+	Start()
+	// Stop stops the reconciler
+	Stop()
+}
+
+// ReconcilerConfig holds the configuration of the private data reconciler
+type ReconcilerConfig struct {
+	SleepInterval time.Duration
+	BatchSize     int
+	IsEnabled     bool
+}
+
+// GetReconcilerConfig reads the reconciler configuration from the peer configuration
+func GetReconcilerConfig() *ReconcilerConfig {
+	sleepInterval := viper.GetDuration(reconcileSleepIntervalConfigKey)
+	if sleepInterval == 0 {
+		logger.Warning("Configuration key", reconcileSleepIntervalConfigKey, "isn't set, defaulting to", reconcileSleepIntervalDefault)
+		sleepInterval = reconcileSleepIntervalDefault
+	}
+	batchSize := viper.GetInt(reconcileBatchSizeConfigKey)
+	if batchSize <= 0 {
+		logger.Warning("Configuration key", reconcileBatchSizeConfigKey, "isn't set, defaulting to", reconcileBatchSizeDefault)
+		batchSize = reconcileBatchSizeDefault
+	}
+	isEnabled := true
+	if viper.IsSet(reconciliationEnabledConfigKey) {
+		isEnabled = viper.GetBool(reconciliationEnabledConfigKey)
+	}
+	return &ReconcilerConfig{SleepInterval: sleepInterval, BatchSize: batchSize, IsEnabled: isEnabled}
+}
+
+// NoOpReconciler is used when the reconciliation is disabled
+type NoOpReconciler struct {
+}
+
+// Start does nothing
+func (*NoOpReconciler) Start() {
+}
+
+// Stop does nothing
+func (*NoOpReconciler) Stop() {
+}
+
+// Reconciler periodically pulls from the other peers of the collections the private
+// data that is recorded as missing in the ledger, and commits it into the ledger
+type Reconciler struct {
+	channel string
+	config  *ReconcilerConfig
+	committer.Committer
+	Fetcher
+	stopChan  chan struct{}
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+// NewReconciler creates a new instance of the private data reconciler, or a
+// NoOpReconciler if the reconciliation is disabled in the given configuration
+func NewReconciler(channel string, c committer.Committer, fetcher Fetcher, config *ReconcilerConfig) PvtDataReconciler {
+	if !config.IsEnabled {
+		logger.Info("Private data reconciliation is disabled for channel", channel)
+		return &NoOpReconciler{}
+	}
+	return &Reconciler{
+		channel:   channel,
+		config:    config,
+		Committer: c,
+		Fetcher:   fetcher,
+		stopChan:  make(chan struct{}),
+	}
+}
+
+// Start starts the periodic reconciliation of the missing private data
+func (r *Reconciler) Start() {
+	r.startOnce.Do(func() {
+		go r.run()
+	})
+}
+
+// Stop stops the reconciler
+func (r *Reconciler) Stop() {
+	r.stopOnce.Do(func() {
+		close(r.stopChan)
+	})
+}
+
+func (r *Reconciler) run() {
+	for {
+		select {
+		case <-r.stopChan:
+			return
+		case <-time.After(r.config.SleepInterval):
+			logger.Debugf("[%s] Start reconcile missing private data", r.channel)
+			if err := r.reconcile(); err != nil {
+				logger.Errorf("[%s] Failed reconciling missing private data: %+v", r.channel, err)
+			}
+		}
+	}
+}
+
+// reconcile pulls the missing private data of the most recent blocks and commits
+// the write sets that match the hashes recorded in the blocks
+func (r *Reconciler) reconcile() error {
+	missingInfo, err := r.GetMissingPvtDataInfoForMostRecentBlocks(r.config.BatchSize)
+	if err != nil {
+		return errors.WithMessage(err, "failed obtaining the missing private data from the ledger")
+	}
+	if len(missingInfo) == 0 {
+		logger.Debugf("[%s] No missing private data to reconcile", r.channel)
+		return nil
+	}
+
+	dig2src := make(dig2sources)
+	for blockNum, missingList := range missingInfo {
+		for _, missing := range missingList {
+			dig := &gossip2.PvtDataDigest{
+				TxId:       missing.TxId,
+				SeqInBlock: uint64(missing.SeqInBlock),
+				Namespace:  missing.Namespace,
+				Collection: missing.Collection,
+				BlockSeq:   blockNum,
+			}
+			// the endorsers are not known, hence any peer of the collection can be asked for the data
+			dig2src[dig] = nil
+		}
+	}
+	fetchedData, err := r.fetch(dig2src)
+	if err != nil {
+		return errors.WithMessage(err, "failed fetching the missing private data from peers")
+	}
+
+	blocksPvtData, err := r.verifyFetchedData(missingInfo, fetchedData)
+	if err != nil {
+		return err
+	}
+	if len(blocksPvtData) == 0 {
+		logger.Debugf("[%s] None of the missing private data was fetched from peers", r.channel)
+		return nil
+	}
+	if err := r.CommitPvtDataOfOldBlocks(blocksPvtData); err != nil {
+		return errors.WithMessage(err, "failed committing the reconciled private data")
+	}
+	logger.Infof("[%s] Reconciled missing private data of %d blocks", r.channel, len(blocksPvtData))
+	return nil
+}
+
+// verifyFetchedData retains the fetched private write sets that are recorded as missing and
+// match the hashes of the corresponding transactions, and groups them by block
+func (r *Reconciler) verifyFetchedData(missingInfo ledger.MissingPvtDataInfo, fetchedData []*gossip2.PvtDataElement) ([]*ledger.BlockPvtData, error) {
+	expected, err := r.expectedRWSetKeys(missingInfo)
+	if err != nil {
+		return nil, err
+	}
+
+	rwsetsByBlock := make(map[uint64]rwsetByKeys)
+	for _, element := range fetchedData {
+		dig := element.Digest
+		for _, rws := range element.Payload {
+			key := rwSetKey{
+				txID:       dig.TxId,
+				seqInBlock: dig.SeqInBlock,
+				namespace:  dig.Namespace,
+				collection: dig.Collection,
+				hash:       hex.EncodeToString(util2.ComputeSHA256(rws)),
+			}
+			blockNum, isMissing := expected[key]
+			if !isMissing || blockNum != dig.BlockSeq {
+				logger.Debug("Ignoring", key, "because it doesn't match the missing private data of block", dig.BlockSeq)
+				continue
+			}
+			if _, exists := rwsetsByBlock[blockNum]; !exists {
+				rwsetsByBlock[blockNum] = make(rwsetByKeys)
+			}
+			rwsetsByBlock[blockNum][key] = rws
+		}
+	}
+
+	var blocksPvtData []*ledger.BlockPvtData
+	for blockNum, ownedRWsets := range rwsetsByBlock {
+		blockPvtData := &ledger.BlockPvtData{
+			BlockNum:  blockNum,
+			WriteSets: make(map[uint64]*ledger.TxPvtData),
+		}
+		for seqInBlock, nsRWS := range ownedRWsets.bySeqsInBlock() {
+			blockPvtData.WriteSets[seqInBlock] = &ledger.TxPvtData{
+				SeqInBlock: seqInBlock,
+				WriteSet:   nsRWS.toRWSet(),
+			}
+		}
+		blocksPvtData = append(blocksPvtData, blockPvtData)
+	}
+	return blocksPvtData, nil
+}
+
+// expectedRWSetKeys returns, for the missing private data, the keys that contain the
+// hashes of the private write sets as recorded in the valid transactions of the blocks
+func (r *Reconciler) expectedRWSetKeys(missingInfo ledger.MissingPvtDataInfo) (map[rwSetKey]uint64, error) {
+	var blockSeqs []uint64
+	for blockNum := range missingInfo {
+		blockSeqs = append(blockSeqs, blockNum)
+	}
+
+	expected := make(map[rwSetKey]uint64)
+	for _, block := range r.GetBlocks(blockSeqs) {
+		if block.Header == nil || block.Data == nil {
+			return nil, errors.New("block header or data is nil")
+		}
+		blockNum := block.Header.Number
+		isMissing := make(map[txAndSeqInBlock]map[string]map[string]struct{})
+		for _, missing := range missingInfo[blockNum] {
+			txAndSeq := txAndSeqInBlock{txID: missing.TxId, seqInBlock: uint64(missing.SeqInBlock)}
+			if _, exists := isMissing[txAndSeq]; !exists {
+				isMissing[txAndSeq] = make(map[string]map[string]struct{})
+			}
+			if _, exists := isMissing[txAndSeq][missing.Namespace]; !exists {
+				isMissing[txAndSeq][missing.Namespace] = make(map[string]struct{})
+			}
+			isMissing[txAndSeq][missing.Namespace][missing.Collection] = struct{}{}
+		}
+
+		if block.Metadata == nil || len(block.Metadata.Metadata) <= int(common.BlockMetadataIndex_TRANSACTIONS_FILTER) {
+			return nil, errors.Errorf("metadata of block [%d] lacks a Tx filter bitmap", blockNum)
+		}
+		txsFilter := txValidationFlags(block.Metadata.Metadata[common.BlockMetadataIndex_TRANSACTIONS_FILTER])
+		if len(txsFilter) != len(block.Data.Data) {
+			return nil, errors.Errorf("block [%d] data size(%d) is different from Tx filter size(%d)", blockNum, len(block.Data.Data), len(txsFilter))
+		}
+		_, err := blockData(block.Data.Data).forEachTxn(txsFilter, func(seqInBlock uint64, chdr *common.ChannelHeader, txRWSet *rwsetutil.TxRwSet, _ []*peer.Endorsement) {
+			missingNamespaces, exists := isMissing[txAndSeqInBlock{txID: chdr.TxId, seqInBlock: seqInBlock}]
+			if !exists {
+				return
+			}
+			for _, ns := range txRWSet.NsRwSets {
+				for _, hashedCollection := range ns.CollHashedRwSets {
+					if _, exists := missingNamespaces[ns.NameSpace][hashedCollection.CollectionName]; !exists {
+						continue
+					}
+					expected[rwSetKey{
+						txID:       chdr.TxId,
+						seqInBlock: seqInBlock,
+						namespace:  ns.NameSpace,
+						collection: hashedCollection.CollectionName,
+						hash:       hex.EncodeToString(hashedCollection.PvtRwSetHash),
+					}] = blockNum
+				}
+			}
+		})
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+	return expected, nil
+}
@@ -0,0 +1,167 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package privdata
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	util2 "github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/core/ledger"
+	"github.com/hyperledger/fabric/protos/common"
+	gossip2 "github.com/hyperledger/fabric/protos/gossip"
+	"github.com/hyperledger/fabric/protos/ledger/rwset"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestGetReconcilerConfig(t *testing.T) {
+	config := GetReconcilerConfig()
+	assert.Equal(t, reconcileSleepIntervalDefault, config.SleepInterval)
+	assert.Equal(t, reconcileBatchSizeDefault, config.BatchSize)
+	assert.True(t, config.IsEnabled)
+
+	viper.Set(reconcileSleepIntervalConfigKey, time.Second*5)
+	viper.Set(reconcileBatchSizeConfigKey, 3)
+	viper.Set(reconciliationEnabledConfigKey, false)
+	defer func() {
+		viper.Reset()
+		viper.Set("peer.gossip.pvtData.pullRetryThreshold", time.Second*3)
+	}()
+	config = GetReconcilerConfig()
+	assert.Equal(t, time.Second*5, config.SleepInterval)
+	assert.Equal(t, 3, config.BatchSize)
+	assert.False(t, config.IsEnabled)
+}
+
+func TestNewReconcilerDisabled(t *testing.T) {
+	r := NewReconciler("test", &committerMock{}, &fetcherMock{t: t}, &ReconcilerConfig{IsEnabled: false})
+	assert.IsType(t, &NoOpReconciler{}, r)
+	r.Start()
+	r.Stop()
+}
+
+func TestReconcileNothingMissing(t *testing.T) {
+	committer := &committerMock{}
+	committer.On("GetMissingPvtDataInfoForMostRecentBlocks", 10).Return(ledger.MissingPvtDataInfo{}, nil)
+	fetcher := &fetcherMock{t: t}
+	r := newTestReconciler(committer, fetcher)
+
+	assert.NoError(t, r.reconcile())
+	fetcher.AssertNotCalled(t, "fetch", mock.Anything)
+	committer.AssertNotCalled(t, "CommitPvtDataOfOldBlocks", mock.Anything)
+
+	committer = &committerMock{}
+	committer.On("GetMissingPvtDataInfoForMostRecentBlocks", 10).Return(nil, errors.New("ledger failure"))
+	r = newTestReconciler(committer, fetcher)
+	err := r.reconcile()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "ledger failure")
+}
+
+func TestReconcile(t *testing.T) {
+	hash := util2.ComputeSHA256([]byte("rws-pre-image"))
+	bf := &blockFactory{channelID: "test"}
+	block := bf.AddTxn("tx1", "ns1", hash, "c1", "c2").AddTxn("tx2", "ns2", hash, "c1").AddTxn("tx3", "ns3", hash, "c1").
+		withInvalidTxns(2).create()
+	block.Header.Number = 5
+
+	missingInfo := ledger.MissingPvtDataInfo{}
+	missingInfo.Add(5, ledger.MissingPrivateData{TxId: "tx1", SeqInBlock: 0, Namespace: "ns1", Collection: "c1"})
+	missingInfo.Add(5, ledger.MissingPrivateData{TxId: "tx1", SeqInBlock: 0, Namespace: "ns1", Collection: "c2"})
+	missingInfo.Add(5, ledger.MissingPrivateData{TxId: "tx2", SeqInBlock: 1, Namespace: "ns2", Collection: "c1"})
+	missingInfo.Add(5, ledger.MissingPrivateData{TxId: "tx3", SeqInBlock: 2, Namespace: "ns3", Collection: "c1"})
+
+	committer := &committerMock{}
+	committer.On("GetMissingPvtDataInfoForMostRecentBlocks", 10).Return(missingInfo, nil)
+	committer.On("GetBlocks", []uint64{5}).Return([]*common.Block{block})
+	var committed []*ledger.BlockPvtData
+	committer.On("CommitPvtDataOfOldBlocks", mock.Anything).Run(func(args mock.Arguments) {
+		committed = args.Get(0).([]*ledger.BlockPvtData)
+	}).Return(nil)
+
+	dig := func(txID string, seqInBlock uint64, ns, coll string) *gossip2.PvtDataDigest {
+		return &gossip2.PvtDataDigest{TxId: txID, SeqInBlock: seqInBlock, Namespace: ns, Collection: coll, BlockSeq: 5}
+	}
+	fetcher := &fetcherMock{t: t}
+	fetcher.On("fetch", mock.Anything).expectingDigests([]*gossip2.PvtDataDigest{
+		dig("tx1", 0, "ns1", "c1"), dig("tx1", 0, "ns1", "c2"), dig("tx2", 1, "ns2", "c1"), dig("tx3", 2, "ns3", "c1"),
+	}).Return([]*gossip2.PvtDataElement{
+		// matches the hash in the block
+		{Digest: dig("tx1", 0, "ns1", "c1"), Payload: [][]byte{[]byte("rws-pre-image")}},
+		// does not match the hash in the block
+		{Digest: dig("tx1", 0, "ns1", "c2"), Payload: [][]byte{[]byte("rws-bogus")}},
+		// the transaction is invalid
+		{Digest: dig("tx3", 2, "ns3", "c1"), Payload: [][]byte{[]byte("rws-pre-image")}},
+	}, nil)
+
+	r := newTestReconciler(committer, fetcher)
+	assert.NoError(t, r.reconcile())
+	assert.Equal(t, []*ledger.BlockPvtData{
+		{
+			BlockNum: 5,
+			WriteSets: map[uint64]*ledger.TxPvtData{
+				0: {
+					SeqInBlock: 0,
+					WriteSet: &rwset.TxPvtReadWriteSet{
+						DataModel: rwset.TxReadWriteSet_KV,
+						NsPvtRwset: []*rwset.NsPvtReadWriteSet{
+							{
+								Namespace: "ns1",
+								CollectionPvtRwset: []*rwset.CollectionPvtReadWriteSet{
+									{CollectionName: "c1", Rwset: []byte("rws-pre-image")},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, committed)
+
+	// Scenario II: the fetch fails, nothing is committed
+	committed = nil
+	fetcher = &fetcherMock{t: t}
+	fetcher.On("fetch", mock.Anything).expectingDigests([]*gossip2.PvtDataDigest{
+		dig("tx1", 0, "ns1", "c1"), dig("tx1", 0, "ns1", "c2"), dig("tx2", 1, "ns2", "c1"), dig("tx3", 2, "ns3", "c1"),
+	}).Return(nil, errors.New("Empty membership"))
+	r = newTestReconciler(committer, fetcher)
+	assert.Error(t, r.reconcile())
+	assert.Nil(t, committed)
+}
+
+func TestReconcilerStartStop(t *testing.T) {
+	committer := &committerMock{}
+	reconciled := make(chan struct{}, 10)
+	committer.On("GetMissingPvtDataInfoForMostRecentBlocks", 10).Run(func(_ mock.Arguments) {
+		reconciled <- struct{}{}
+	}).Return(ledger.MissingPvtDataInfo{}, nil)
+	r := NewReconciler("test", committer, &fetcherMock{t: t}, &ReconcilerConfig{
+		SleepInterval: time.Millisecond * 10,
+		BatchSize:     10,
+		IsEnabled:     true,
+	})
+	r.Start()
+	select {
+	case <-reconciled:
+	case <-time.After(time.Second * 5):
+		t.Fatal("Reconciliation didn't take place")
+	}
+	r.Stop()
+	// stopping twice is safe
+	r.Stop()
+}
+
+func newTestReconciler(c *committerMock, fetcher *fetcherMock) *Reconciler {
+	return NewReconciler("test", c, fetcher, &ReconcilerConfig{
+		SleepInterval: time.Minute,
+		BatchSize:     10,
+		IsEnabled:     true,
+	}).(*Reconciler)
+}
@@ -76,10 +76,12 @@ type privateHandler struct {
 	support     Support
 	coordinator privdata2.Coordinator
 	distributor privdata2.PvtDataDistributor
+	reconciler  privdata2.PvtDataReconciler
 }
 
 func (p privateHandler) close() {
 	p.coordinator.Close()
+	p.reconciler.Stop()
 }
 
 type gossipServiceImpl struct {
@@ -241,11 +243,15 @@ func (g *gossipServiceImpl) InitializeChannel(chainID string, endpoints []string
 		Fetcher:         fetcher,
 	}, g.createSelfSignedData())
 
+	reconciler := privdata2.NewReconciler(chainID, support.Committer, fetcher, privdata2.GetReconcilerConfig())
+
 	g.privateHandlers[chainID] = privateHandler{
 		support:     support,
 		coordinator: coordinator,
 		distributor: privdata2.NewDistributor(chainID, g),
+		reconciler:  reconciler,
 	}
+	reconciler.Start()
 	g.chains[chainID] = state.NewGossipStateProvider(chainID, servicesAdapter, coordinator)
 	if g.deliveryService[chainID] == nil {/*检查是否已存在Deliver服务实例*/
 		var err error
@@ -342,6 +342,14 @@ func (li *mockLedgerInfo) CommitWithPvtData(blockAndPvtData *ledger.BlockAndPvtD
 	panic("implement me")
 }
 
+func (li *mockLedgerInfo) CommitPvtDataOfOldBlocks(blocksPvtData []*ledger.BlockPvtData) error {
+	return nil
+}
+
+func (li *mockLedgerInfo) GetMissingPvtDataInfoForMostRecentBlocks(maxBlocks int) (ledger.MissingPvtDataInfo, error) {
+	return nil, nil
+}
+
 func (li *mockLedgerInfo) GetPvtDataAndBlockByNum(seqNum uint64) (*ledger.BlockAndPvtData, error) {
 	panic("implement me")
 }
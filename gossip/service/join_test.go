@@ -48,6 +48,10 @@ func (*gossipMock) SuspectPeers(s api.PeerSuspector) {
 	panic("implement me")
 }
 
+func (*gossipMock) IdentityInfo() api.PeerIdentitySet {
+	panic("implement me")
+}
+
 func (*gossipMock) Send(msg *proto.GossipMessage, peers ...*comm.RemotePeer) {
 	panic("implement me")
 }
@@ -30,6 +30,10 @@ func (g *GossipMock) SuspectPeers(s api.PeerSuspector) {
 
 }
 
+func (g *GossipMock) IdentityInfo() api.PeerIdentitySet {
+	panic("implement me")
+}
+
 func (g *GossipMock) LeaveChan(_ common.ChainID) {
 	panic("implement me")
 }
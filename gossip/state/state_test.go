@@ -206,6 +206,16 @@ func (mc *mockCommitter) CommitWithPvtData(blockAndPvtData *ledger.BlockAndPvtDa
 	return nil
 }
 
+func (mc *mockCommitter) CommitPvtDataOfOldBlocks(blocksPvtData []*ledger.BlockPvtData) error {
+	args := mc.Called(blocksPvtData)
+	return args.Error(0)
+}
+
+func (mc *mockCommitter) GetMissingPvtDataInfoForMostRecentBlocks(maxBlocks int) (ledger.MissingPvtDataInfo, error) {
+	args := mc.Called(maxBlocks)
+	return args.Get(0).(ledger.MissingPvtDataInfo), args.Error(1)
+}
+
 func (mc *mockCommitter) GetPvtDataAndBlockByNum(seqNum uint64) (*ledger.BlockAndPvtData, error) {
 	args := mc.Called(seqNum)
 	return args.Get(0).(*ledger.BlockAndPvtData), args.Error(1)
@@ -16,6 +16,10 @@ It has these top-level messages:
 	CredRequest
 	Signature
 	NymSignature
+	CredentialRevocationInformation
+	NonRevocationProof
+	WBBRevocationData
+	WBBNonRevocationProof
 */
 package idemix
 
@@ -335,19 +339,23 @@ func (m *CredRequest) GetProofS2() []byte {
 // ProofSRNym - a zero-knowledge proof of knowledge of the
 // user secret inside Nym
 type Signature struct {
-	APrime       *ECP     `protobuf:"bytes,1,opt,name=APrime" json:"APrime,omitempty"`
-	ABar         *ECP     `protobuf:"bytes,2,opt,name=ABar" json:"ABar,omitempty"`
-	BPrime       *ECP     `protobuf:"bytes,3,opt,name=BPrime" json:"BPrime,omitempty"`
-	ProofC       []byte   `protobuf:"bytes,4,opt,name=ProofC,proto3" json:"ProofC,omitempty"`
-	ProofSSk     []byte   `protobuf:"bytes,5,opt,name=ProofSSk,proto3" json:"ProofSSk,omitempty"`
-	ProofSE      []byte   `protobuf:"bytes,6,opt,name=ProofSE,proto3" json:"ProofSE,omitempty"`
-	ProofSR2     []byte   `protobuf:"bytes,7,opt,name=ProofSR2,proto3" json:"ProofSR2,omitempty"`
-	ProofSR3     []byte   `protobuf:"bytes,8,opt,name=ProofSR3,proto3" json:"ProofSR3,omitempty"`
-	ProofSSPrime []byte   `protobuf:"bytes,9,opt,name=ProofSSPrime,proto3" json:"ProofSSPrime,omitempty"`
-	ProofSAttrs  [][]byte `protobuf:"bytes,10,rep,name=ProofSAttrs,proto3" json:"ProofSAttrs,omitempty"`
-	Nonce        []byte   `protobuf:"bytes,11,opt,name=Nonce,proto3" json:"Nonce,omitempty"`
-	Nym          *ECP     `protobuf:"bytes,12,opt,name=Nym" json:"Nym,omitempty"`
-	ProofSRNym   []byte   `protobuf:"bytes,13,opt,name=ProofSRNym,proto3" json:"ProofSRNym,omitempty"`
+	APrime             *ECP                `protobuf:"bytes,1,opt,name=APrime" json:"APrime,omitempty"`
+	ABar               *ECP                `protobuf:"bytes,2,opt,name=ABar" json:"ABar,omitempty"`
+	BPrime             *ECP                `protobuf:"bytes,3,opt,name=BPrime" json:"BPrime,omitempty"`
+	ProofC             []byte              `protobuf:"bytes,4,opt,name=ProofC,proto3" json:"ProofC,omitempty"`
+	ProofSSk           []byte              `protobuf:"bytes,5,opt,name=ProofSSk,proto3" json:"ProofSSk,omitempty"`
+	ProofSE            []byte              `protobuf:"bytes,6,opt,name=ProofSE,proto3" json:"ProofSE,omitempty"`
+	ProofSR2           []byte              `protobuf:"bytes,7,opt,name=ProofSR2,proto3" json:"ProofSR2,omitempty"`
+	ProofSR3           []byte              `protobuf:"bytes,8,opt,name=ProofSR3,proto3" json:"ProofSR3,omitempty"`
+	ProofSSPrime       []byte              `protobuf:"bytes,9,opt,name=ProofSSPrime,proto3" json:"ProofSSPrime,omitempty"`
+	ProofSAttrs        [][]byte            `protobuf:"bytes,10,rep,name=ProofSAttrs,proto3" json:"ProofSAttrs,omitempty"`
+	Nonce              []byte              `protobuf:"bytes,11,opt,name=Nonce,proto3" json:"Nonce,omitempty"`
+	Nym                *ECP                `protobuf:"bytes,12,opt,name=Nym" json:"Nym,omitempty"`
+	ProofSRNym         []byte              `protobuf:"bytes,13,opt,name=ProofSRNym,proto3" json:"ProofSRNym,omitempty"`
+	RevocationEpochPk  *ECP2               `protobuf:"bytes,14,opt,name=RevocationEpochPk" json:"RevocationEpochPk,omitempty"`
+	RevocationPkSig    []byte              `protobuf:"bytes,15,opt,name=RevocationPkSig,proto3" json:"RevocationPkSig,omitempty"`
+	Epoch              int64               `protobuf:"varint,16,opt,name=Epoch" json:"Epoch,omitempty"`
+	NonRevocationProof *NonRevocationProof `protobuf:"bytes,17,opt,name=NonRevocationProof" json:"NonRevocationProof,omitempty"`
 }
 
 func (m *Signature) Reset()                    { *m = Signature{} }
@@ -446,6 +454,34 @@ func (m *Signature) GetProofSRNym() []byte {
 	return nil
 }
 
+func (m *Signature) GetRevocationEpochPk() *ECP2 {
+	if m != nil {
+		return m.RevocationEpochPk
+	}
+	return nil
+}
+
+func (m *Signature) GetRevocationPkSig() []byte {
+	if m != nil {
+		return m.RevocationPkSig
+	}
+	return nil
+}
+
+func (m *Signature) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *Signature) GetNonRevocationProof() *NonRevocationProof {
+	if m != nil {
+		return m.NonRevocationProof
+	}
+	return nil
+}
+
 // NymSignature specifies a signature object that signs a message
 // with respect to a pseudonym. It differs from the standard idemix.signature in the fact that
 // the  standard signature object also proves that the pseudonym is based on a secret certified by
@@ -495,6 +531,144 @@ func (m *NymSignature) GetNonce() []byte {
 	return nil
 }
 
+// CredentialRevocationInformation contains the information necessary for a user
+// to prove that her credential has not been revoked in a given epoch:
+// Epoch - the epoch this information is valid for
+// EpochPk - the public key of the revocation authority for this epoch
+// EpochPkSig - the signature of the long term revocation key on the epoch and EpochPk
+// RevocationAlg - the revocation algorithm used for this epoch
+// RevocationData - the algorithm specific revocation data
+type CredentialRevocationInformation struct {
+	Epoch          int64  `protobuf:"varint,1,opt,name=Epoch" json:"Epoch,omitempty"`
+	EpochPk        *ECP2  `protobuf:"bytes,2,opt,name=EpochPk" json:"EpochPk,omitempty"`
+	EpochPkSig     []byte `protobuf:"bytes,3,opt,name=EpochPkSig,proto3" json:"EpochPkSig,omitempty"`
+	RevocationAlg  int32  `protobuf:"varint,4,opt,name=RevocationAlg" json:"RevocationAlg,omitempty"`
+	RevocationData []byte `protobuf:"bytes,5,opt,name=RevocationData,proto3" json:"RevocationData,omitempty"`
+}
+
+func (m *CredentialRevocationInformation) Reset()         { *m = CredentialRevocationInformation{} }
+func (m *CredentialRevocationInformation) String() string { return proto.CompactTextString(m) }
+func (*CredentialRevocationInformation) ProtoMessage()    {}
+func (*CredentialRevocationInformation) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{8}
+}
+
+func (m *CredentialRevocationInformation) GetEpoch() int64 {
+	if m != nil {
+		return m.Epoch
+	}
+	return 0
+}
+
+func (m *CredentialRevocationInformation) GetEpochPk() *ECP2 {
+	if m != nil {
+		return m.EpochPk
+	}
+	return nil
+}
+
+func (m *CredentialRevocationInformation) GetEpochPkSig() []byte {
+	if m != nil {
+		return m.EpochPkSig
+	}
+	return nil
+}
+
+func (m *CredentialRevocationInformation) GetRevocationAlg() int32 {
+	if m != nil {
+		return m.RevocationAlg
+	}
+	return 0
+}
+
+func (m *CredentialRevocationInformation) GetRevocationData() []byte {
+	if m != nil {
+		return m.RevocationData
+	}
+	return nil
+}
+
+// NonRevocationProof contains a proof that the credential of the signer has not been
+// revoked, created with the algorithm RevocationAlg
+type NonRevocationProof struct {
+	RevocationAlg int32  `protobuf:"varint,1,opt,name=RevocationAlg" json:"RevocationAlg,omitempty"`
+	Proof         []byte `protobuf:"bytes,2,opt,name=Proof,proto3" json:"Proof,omitempty"`
+}
+
+func (m *NonRevocationProof) Reset()                    { *m = NonRevocationProof{} }
+func (m *NonRevocationProof) String() string            { return proto.CompactTextString(m) }
+func (*NonRevocationProof) ProtoMessage()               {}
+func (*NonRevocationProof) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *NonRevocationProof) GetRevocationAlg() int32 {
+	if m != nil {
+		return m.RevocationAlg
+	}
+	return 0
+}
+
+func (m *NonRevocationProof) GetProof() []byte {
+	if m != nil {
+		return m.Proof
+	}
+	return nil
+}
+
+// WBBRevocationData contains the weak Boneh-Boyen signatures of the revocation
+// authority on the revocation handles of the unrevoked credentials:
+// Sigs[i] is the signature on Handles[i]
+type WBBRevocationData struct {
+	Handles [][]byte `protobuf:"bytes,1,rep,name=Handles,proto3" json:"Handles,omitempty"`
+	Sigs    []*ECP   `protobuf:"bytes,2,rep,name=Sigs" json:"Sigs,omitempty"`
+}
+
+func (m *WBBRevocationData) Reset()                    { *m = WBBRevocationData{} }
+func (m *WBBRevocationData) String() string            { return proto.CompactTextString(m) }
+func (*WBBRevocationData) ProtoMessage()               {}
+func (*WBBRevocationData) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *WBBRevocationData) GetHandles() [][]byte {
+	if m != nil {
+		return m.Handles
+	}
+	return nil
+}
+
+func (m *WBBRevocationData) GetSigs() []*ECP {
+	if m != nil {
+		return m.Sigs
+	}
+	return nil
+}
+
+// WBBNonRevocationProof is a zero-knowledge proof of knowledge of a weak Boneh-Boyen
+// signature of the revocation authority on the (hidden) revocation handle of a credential:
+// SigmaPrime - the randomized signature
+// ProofSRho - the s-value proving knowledge of the randomness
+type WBBNonRevocationProof struct {
+	SigmaPrime *ECP   `protobuf:"bytes,1,opt,name=SigmaPrime" json:"SigmaPrime,omitempty"`
+	ProofSRho  []byte `protobuf:"bytes,2,opt,name=ProofSRho,proto3" json:"ProofSRho,omitempty"`
+}
+
+func (m *WBBNonRevocationProof) Reset()                    { *m = WBBNonRevocationProof{} }
+func (m *WBBNonRevocationProof) String() string            { return proto.CompactTextString(m) }
+func (*WBBNonRevocationProof) ProtoMessage()               {}
+func (*WBBNonRevocationProof) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *WBBNonRevocationProof) GetSigmaPrime() *ECP {
+	if m != nil {
+		return m.SigmaPrime
+	}
+	return nil
+}
+
+func (m *WBBNonRevocationProof) GetProofSRho() []byte {
+	if m != nil {
+		return m.ProofSRho
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*ECP)(nil), "ECP")
 	proto.RegisterType((*ECP2)(nil), "ECP2")
@@ -504,47 +678,64 @@ func init() {
 	proto.RegisterType((*CredRequest)(nil), "CredRequest")
 	proto.RegisterType((*Signature)(nil), "Signature")
 	proto.RegisterType((*NymSignature)(nil), "NymSignature")
+	proto.RegisterType((*CredentialRevocationInformation)(nil), "CredentialRevocationInformation")
+	proto.RegisterType((*NonRevocationProof)(nil), "NonRevocationProof")
+	proto.RegisterType((*WBBRevocationData)(nil), "WBBRevocationData")
+	proto.RegisterType((*WBBNonRevocationProof)(nil), "WBBNonRevocationProof")
 }
 
 func init() { proto.RegisterFile("idemix/idemix.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 591 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x54, 0x4d, 0x6b, 0xdb, 0x4c,
-	0x18, 0x64, 0xf5, 0xe1, 0xc4, 0x8f, 0xf5, 0xe6, 0x0d, 0x9b, 0x12, 0x96, 0x50, 0x8a, 0x2b, 0x4a,
-	0xc8, 0xc9, 0x21, 0xca, 0x2f, 0x90, 0x8c, 0x5a, 0x87, 0x82, 0x11, 0xd2, 0x21, 0x76, 0x6f, 0xb2,
-	0xbd, 0xb6, 0x85, 0x2d, 0x2b, 0x5d, 0x49, 0x10, 0xff, 0x8e, 0x5e, 0xfb, 0x4f, 0x7b, 0x29, 0xfb,
-	0xa1, 0x4f, 0xe8, 0xc9, 0x9a, 0x19, 0x3d, 0xfb, 0x8c, 0x67, 0x16, 0xc1, 0x4d, 0xb2, 0xa1, 0x69,
-	0xf2, 0xfe, 0x28, 0x7f, 0x26, 0x6f, 0x2c, 0x2b, 0x32, 0xfb, 0x33, 0xe8, 0xfe, 0x34, 0xc0, 0x16,
-	0xa0, 0x05, 0x41, 0x63, 0xf4, 0x60, 0x85, 0x68, 0xc1, 0xd1, 0x92, 0x68, 0x12, 0x2d, 0xed, 0xaf,
-	0x60, 0xf8, 0xd3, 0xc0, 0xc1, 0x57, 0xa0, 0x2d, 0x5c, 0xf5, 0x92, 0xb6, 0x70, 0x05, 0xf6, 0xd4,
-	0x6b, 0xda, 0xc2, 0xe3, 0x78, 0xe9, 0x12, 0x5d, 0xe2, 0xa5, 0xd0, 0x97, 0x1e, 0x31, 0x14, 0xf6,
-	0xec, 0xdf, 0x1a, 0xfc, 0xff, 0x92, 0xe7, 0x25, 0x65, 0x41, 0xb9, 0x3a, 0x26, 0xeb, 0xef, 0xf4,
-	0x8c, 0xef, 0xe1, 0xca, 0x2d, 0x0a, 0x96, 0xac, 0xca, 0x82, 0xce, 0xe3, 0x94, 0xe6, 0x04, 0x8d,
-	0xf5, 0x87, 0x61, 0xd8, 0x63, 0xf1, 0x2d, 0xe8, 0xb3, 0xe8, 0x20, 0x96, 0x8d, 0x1c, 0x63, 0xe2,
-	0x4f, 0x83, 0x90, 0x13, 0xf8, 0x0e, 0xcc, 0x59, 0x18, 0x9f, 0x36, 0x62, 0x6d, 0xa5, 0x48, 0x0a,
-	0x7f, 0x84, 0xc1, 0x8c, 0x1f, 0x93, 0x13, 0x63, 0xac, 0xd7, 0xa2, 0xe2, 0xf0, 0x0d, 0xa0, 0x57,
-	0x62, 0x8a, 0x29, 0x93, 0x0b, 0x4e, 0x88, 0x5e, 0xf9, 0x71, 0x5e, 0xcc, 0xbe, 0x3d, 0x91, 0x41,
-	0xfb, 0x38, 0x41, 0x55, 0x9a, 0x43, 0x2e, 0xfa, 0x9a, 0x83, 0x6f, 0x61, 0x10, 0xb0, 0x2c, 0xdb,
-	0x4e, 0xc9, 0xa5, 0xf8, 0xbb, 0x0a, 0xd5, 0x7c, 0x44, 0x86, 0x2d, 0x3e, 0xc2, 0x18, 0x8c, 0x59,
-	0x9c, 0xef, 0x09, 0x08, 0x56, 0x3c, 0xdb, 0x2e, 0x0c, 0x65, 0x3a, 0x3c, 0x97, 0x6b, 0xd0, 0x5f,
-	0xa2, 0x83, 0x0a, 0x9b, 0x3f, 0x62, 0x1b, 0xf4, 0x97, 0xa0, 0x4a, 0xe0, 0x7a, 0xd2, 0x0b, 0x32,
-	0xe4, 0xa2, 0xbd, 0x05, 0x98, 0x32, 0xba, 0xa1, 0xa7, 0x22, 0x89, 0x8f, 0x18, 0x03, 0x92, 0x75,
-	0x55, 0x66, 0x91, 0xcb, 0x39, 0xaf, 0x93, 0x22, 0xf2, 0x78, 0xdb, 0xbe, 0xaa, 0x0d, 0xf9, 0x1c,
-	0x45, 0xaa, 0x34, 0x14, 0xe1, 0x0f, 0x60, 0xca, 0x08, 0xcd, 0xb1, 0xfe, 0x60, 0x85, 0x12, 0xd8,
-	0xbf, 0x10, 0x8c, 0xf8, 0xa2, 0x90, 0xfe, 0x2c, 0x69, 0x5e, 0xf0, 0x76, 0xe6, 0xe7, 0xb4, 0xb3,
-	0x8b, 0x13, 0x78, 0x0c, 0x23, 0xe9, 0x73, 0x9e, 0x9d, 0xd6, 0x54, 0x5d, 0x95, 0x36, 0xd5, 0x0a,
-	0x4e, 0xef, 0x04, 0x47, 0xe0, 0x42, 0x46, 0xf5, 0xa4, 0xbc, 0x54, 0xb0, 0x51, 0x1c, 0xd1, 0x5e,
-	0xad, 0x38, 0xf6, 0x1f, 0x0d, 0x86, 0x51, 0xb2, 0x3b, 0xc5, 0x45, 0xc9, 0x28, 0x6f, 0xdf, 0x0d,
-	0x58, 0x92, 0xd2, 0x8e, 0x2d, 0xc5, 0x61, 0x02, 0x86, 0xeb, 0xc5, 0xac, 0x13, 0x85, 0x60, 0xf8,
-	0x9c, 0x27, 0xe7, 0xda, 0x57, 0x4a, 0x71, 0x2d, 0xbf, 0x46, 0xc7, 0xef, 0x1d, 0x5c, 0x4a, 0x1b,
-	0xd1, 0x41, 0xd9, 0xaa, 0x71, 0xe3, 0xd8, 0x17, 0xd7, 0xaa, 0x76, 0xec, 0x37, 0x53, 0xa1, 0xbc,
-	0x55, 0xf5, 0x54, 0xe8, 0xb4, 0xb4, 0x67, 0x75, 0xa9, 0x6a, 0x8c, 0x6d, 0xb0, 0xd4, 0xe9, 0xd2,
-	0xa9, 0xbc, 0x5c, 0x1d, 0x8e, 0x67, 0x2f, 0xb1, 0xec, 0x0f, 0x44, 0x7f, 0x6d, 0x8a, 0x77, 0x2b,
-	0x7b, 0x19, 0x89, 0x71, 0xb3, 0x6a, 0x44, 0x74, 0x69, 0xf5, 0xbb, 0xfc, 0x04, 0xa0, 0xf6, 0x73,
-	0xf9, 0x3f, 0x31, 0xd2, 0x62, 0xec, 0x77, 0xb0, 0xe6, 0xe7, 0xb4, 0xc9, 0xbf, 0x49, 0x0a, 0xfd,
-	0x33, 0x29, 0xad, 0x97, 0x54, 0x77, 0x87, 0xde, 0xdf, 0xd1, 0x38, 0x36, 0x5a, 0x8e, 0xbd, 0xfb,
-	0x1f, 0x5f, 0x76, 0x49, 0xb1, 0x2f, 0x57, 0x93, 0x75, 0x96, 0x3e, 0xee, 0xcf, 0x6f, 0x94, 0x1d,
-	0xe9, 0x66, 0x47, 0xd9, 0xe3, 0x36, 0x5e, 0xb1, 0x64, 0xad, 0x3e, 0x78, 0xab, 0x81, 0xf8, 0xe2,
-	0x3d, 0xff, 0x0d, 0x00, 0x00, 0xff, 0xff, 0x06, 0x88, 0xd9, 0x9e, 0x08, 0x05, 0x00, 0x00,
+	// 798 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x55, 0xdb, 0x6e, 0xea, 0x46,
+	0x14, 0xd5, 0xf8, 0x42, 0x0e, 0x1b, 0x4e, 0x2e, 0x93, 0xd3, 0xa3, 0xd1, 0xd1, 0x51, 0x43, 0xad,
+	0x28, 0xe2, 0x89, 0x28, 0xce, 0x17, 0xd8, 0xd4, 0x0d, 0xa8, 0x12, 0xb2, 0xec, 0x07, 0xa0, 0x7d,
+	0x32, 0x30, 0x80, 0x05, 0xb6, 0x53, 0x63, 0xaa, 0xf0, 0x1d, 0x7d, 0xed, 0x6f, 0xf4, 0x17, 0xfa,
+	0x5d, 0xd5, 0x5c, 0x8c, 0xc7, 0x40, 0x9f, 0x98, 0xb5, 0xb6, 0xf7, 0xec, 0xc5, 0x5a, 0x33, 0x36,
+	0xdc, 0xc7, 0x0b, 0x9a, 0xc4, 0x1f, 0xcf, 0xe2, 0xa7, 0xf7, 0x9e, 0x67, 0x45, 0x66, 0xfd, 0x04,
+	0xba, 0xd7, 0xf7, 0x71, 0x1b, 0xd0, 0x84, 0xa0, 0x0e, 0xea, 0xb6, 0x03, 0x34, 0x61, 0x68, 0x4a,
+	0x34, 0x81, 0xa6, 0xd6, 0x2f, 0x60, 0x78, 0x7d, 0xdf, 0xc6, 0xd7, 0xa0, 0x4d, 0x1c, 0xf9, 0x90,
+	0x36, 0x71, 0x38, 0x76, 0xe5, 0x63, 0xda, 0xc4, 0x65, 0x78, 0xea, 0x10, 0x5d, 0xe0, 0x29, 0xaf,
+	0x4f, 0x5d, 0x62, 0x48, 0xec, 0x5a, 0x7f, 0x6b, 0x70, 0x33, 0xdc, 0xed, 0xf6, 0x34, 0xf7, 0xf7,
+	0xb3, 0x6d, 0x3c, 0xff, 0x95, 0x1e, 0xf0, 0x13, 0x5c, 0x3b, 0x45, 0x91, 0xc7, 0xb3, 0x7d, 0x41,
+	0x47, 0x51, 0x42, 0x77, 0x04, 0x75, 0xf4, 0x6e, 0x33, 0x38, 0x61, 0xf1, 0x57, 0xd0, 0x07, 0xe1,
+	0x86, 0x0f, 0x6b, 0xd9, 0x46, 0xcf, 0xeb, 0xfb, 0x01, 0x23, 0xf0, 0x37, 0x30, 0x07, 0x41, 0x94,
+	0x2e, 0x88, 0xae, 0x54, 0x04, 0x85, 0xbf, 0x43, 0x63, 0xc0, 0xb6, 0xd9, 0x11, 0xa3, 0xa3, 0x1f,
+	0x8b, 0x92, 0xc3, 0xf7, 0x80, 0xc6, 0xc4, 0xe4, 0x5d, 0x26, 0x2b, 0xd8, 0x01, 0x1a, 0xb3, 0xed,
+	0xdc, 0x28, 0x7f, 0x7b, 0x21, 0x0d, 0x75, 0x3b, 0x4e, 0x95, 0x35, 0x9b, 0x5c, 0x9d, 0xd6, 0x6c,
+	0xfc, 0x15, 0x1a, 0x7e, 0x9e, 0x65, 0xcb, 0x3e, 0xf9, 0xc4, 0xff, 0xae, 0x44, 0x47, 0x3e, 0x24,
+	0x4d, 0x85, 0x0f, 0x31, 0x06, 0x63, 0x10, 0xed, 0xd6, 0x04, 0x38, 0xcb, 0xd7, 0x96, 0x03, 0x4d,
+	0xe1, 0x0e, 0xf3, 0xe5, 0x16, 0xf4, 0x61, 0xb8, 0x91, 0x66, 0xb3, 0x25, 0xb6, 0x40, 0x1f, 0xfa,
+	0xa5, 0x03, 0xb7, 0xbd, 0x13, 0x23, 0x03, 0x56, 0xb4, 0x96, 0x00, 0xfd, 0x9c, 0x2e, 0x68, 0x5a,
+	0xc4, 0xd1, 0x16, 0x63, 0x40, 0x22, 0xae, 0x52, 0x2c, 0x72, 0x18, 0xe7, 0xd6, 0x5c, 0x44, 0x2e,
+	0x4b, 0xdb, 0x93, 0xb1, 0x21, 0x8f, 0xa1, 0x50, 0x86, 0x86, 0x42, 0xfc, 0x05, 0x4c, 0x61, 0xa1,
+	0xd9, 0xd1, 0xbb, 0xed, 0x40, 0x00, 0xeb, 0x2f, 0x04, 0x2d, 0x36, 0x28, 0xa0, 0x7f, 0xec, 0xe9,
+	0xae, 0x60, 0xe9, 0x8c, 0x0e, 0x49, 0x6d, 0x16, 0x23, 0x70, 0x07, 0x5a, 0x42, 0xe7, 0x28, 0x4b,
+	0xe7, 0x54, 0x1e, 0x15, 0x95, 0x52, 0x8c, 0xd3, 0x6b, 0xc6, 0x11, 0xb8, 0xe2, 0xab, 0xf0, 0x45,
+	0x6a, 0x29, 0x61, 0x55, 0xb1, 0x89, 0xa9, 0x56, 0x6c, 0xeb, 0x1f, 0x03, 0x9a, 0x61, 0xbc, 0x4a,
+	0xa3, 0x62, 0x9f, 0x53, 0x96, 0xbe, 0xe3, 0xe7, 0x71, 0x42, 0x6b, 0xb2, 0x24, 0x87, 0x09, 0x18,
+	0x8e, 0x1b, 0xe5, 0x35, 0x2b, 0x38, 0xc3, 0xfa, 0x5c, 0xd1, 0xa7, 0x1e, 0x29, 0xc9, 0x29, 0x7a,
+	0x8d, 0x9a, 0xde, 0x6f, 0xf0, 0x49, 0xc8, 0x08, 0x37, 0x52, 0xd6, 0x11, 0x57, 0x8a, 0x3d, 0xd2,
+	0x50, 0x15, 0x7b, 0x55, 0x57, 0x20, 0x4e, 0xd5, 0xb1, 0x2b, 0xb0, 0x95, 0xda, 0xab, 0x3c, 0x54,
+	0x47, 0x8c, 0x2d, 0x68, 0xcb, 0xdd, 0x85, 0x52, 0x71, 0xb8, 0x6a, 0x1c, 0xf3, 0x5e, 0x60, 0x91,
+	0x1f, 0xf0, 0xfc, 0x54, 0x8a, 0x65, 0x2b, 0x72, 0x69, 0xf1, 0x76, 0xb3, 0x4c, 0x84, 0x67, 0xd9,
+	0x3e, 0xcd, 0xf2, 0x47, 0x00, 0x39, 0x9f, 0x95, 0x3f, 0xf3, 0x16, 0x85, 0xc1, 0xaf, 0x70, 0x17,
+	0xd0, 0x3f, 0xb3, 0x79, 0x54, 0xc4, 0x59, 0xea, 0xbd, 0x67, 0xf3, 0xb5, 0xbf, 0x21, 0xd7, 0xea,
+	0xfd, 0x3a, 0xaf, 0xe3, 0x2e, 0xdc, 0x54, 0xa4, 0xbf, 0x09, 0xe3, 0x15, 0xb9, 0xe1, 0x3b, 0x9f,
+	0xd2, 0x4c, 0x2c, 0x6f, 0x22, 0xb7, 0x1d, 0xd4, 0xd5, 0x03, 0x01, 0x70, 0x1f, 0xf0, 0x28, 0x4b,
+	0x95, 0x67, 0x99, 0x1e, 0x72, 0xc7, 0xa7, 0xde, 0xf7, 0xce, 0x4b, 0xc1, 0x85, 0xc7, 0xad, 0x0f,
+	0x68, 0x8f, 0x0e, 0x49, 0x75, 0x72, 0xaa, 0x8c, 0xd1, 0xff, 0x66, 0xac, 0x9d, 0x64, 0x5c, 0x77,
+	0x47, 0x3f, 0x73, 0xe7, 0xe8, 0xb5, 0xa1, 0x78, 0x6d, 0xfd, 0x8b, 0xe0, 0xa1, 0xba, 0xb0, 0x95,
+	0xae, 0x61, 0xba, 0xcc, 0xf2, 0x84, 0x2f, 0xab, 0x3f, 0x8e, 0xd4, 0x3f, 0xfe, 0x00, 0x57, 0xa5,
+	0xc7, 0x9a, 0xea, 0x71, 0xc9, 0x32, 0x41, 0x72, 0xc9, 0x4c, 0x95, 0x82, 0x2a, 0x06, 0x3f, 0xc2,
+	0xe7, 0x6a, 0x9e, 0xb3, 0x5d, 0x71, 0x61, 0x66, 0x50, 0x27, 0xd9, 0xeb, 0xb9, 0x22, 0x7e, 0x8e,
+	0x8a, 0x48, 0x1e, 0xee, 0x13, 0xd6, 0xf2, 0x2f, 0xe5, 0x70, 0x3e, 0x03, 0x5d, 0x9a, 0xf1, 0x05,
+	0x4c, 0x11, 0x9b, 0xf0, 0x54, 0x00, 0xeb, 0x0d, 0xee, 0xc6, 0xae, 0x5b, 0x1f, 0xc3, 0x6e, 0xd2,
+	0x20, 0x4a, 0x17, 0x5b, 0xf9, 0x99, 0x68, 0x07, 0x25, 0x64, 0xf7, 0x39, 0x8c, 0x57, 0x3b, 0xa2,
+	0x29, 0x6f, 0x7a, 0xce, 0x58, 0xbf, 0xc3, 0x0f, 0x63, 0xd7, 0xbd, 0xa8, 0x0e, 0xc2, 0x78, 0x95,
+	0x44, 0xe7, 0x2f, 0x09, 0x85, 0xc7, 0xdf, 0xa1, 0x29, 0x63, 0x5c, 0x67, 0x52, 0x61, 0x45, 0xb8,
+	0x4f, 0xbf, 0x3d, 0xae, 0xe2, 0x62, 0xbd, 0x9f, 0xf5, 0xe6, 0x59, 0xf2, 0xbc, 0x3e, 0xbc, 0xd3,
+	0x7c, 0x4b, 0x17, 0x2b, 0x9a, 0x3f, 0x2f, 0xa3, 0x59, 0x1e, 0xcf, 0xe5, 0xb7, 0x76, 0xd6, 0xe0,
+	0x1f, 0xdb, 0xd7, 0xff, 0x06, 0x00, 0xe3, 0xcb, 0x8d, 0x76, 0x83, 0x07, 0x00, 0x00,
 }
@@ -10,6 +10,7 @@ import (
 	"bytes"
 	"testing"
 
+	"github.com/golang/protobuf/proto"
 	"github.com/milagro-crypto/amcl/version3/go/amcl/FP256BN"
 	"github.com/stretchr/testify/assert"
 )
@@ -100,10 +101,10 @@ func TestIdemix(t *testing.T) {
 
 	disclosure := []byte{0, 0, 0, 0, 0}
 	msg := []byte{1, 2, 3, 4, 5}
-	sig, err := NewSignature(cred, sk, Nym, RandNym, key.IPk, disclosure, msg, rng)
+	sig, err := NewSignature(cred, sk, Nym, RandNym, key.IPk, disclosure, msg, 0, nil, rng)
 	assert.NoError(t, err)
 
-	err = sig.Ver(disclosure, key.IPk, msg, nil)
+	err = sig.Ver(disclosure, key.IPk, msg, nil, 0, nil, 0)
 	if err != nil {
 		t.Fatalf("Signature should be valid but verification returned error: %s", err)
 		return
@@ -111,15 +112,74 @@ func TestIdemix(t *testing.T) {
 
 	// Test signing selective disclosure
 	disclosure = []byte{0, 1, 1, 1, 1}
-	sig, err = NewSignature(cred, sk, Nym, RandNym, key.IPk, disclosure, msg, rng)
+	sig, err = NewSignature(cred, sk, Nym, RandNym, key.IPk, disclosure, msg, 0, nil, rng)
 	assert.NoError(t, err)
 
-	err = sig.Ver(disclosure, key.IPk, msg, attrs)
+	err = sig.Ver(disclosure, key.IPk, msg, attrs, 0, nil, 0)
 	if err != nil {
 		t.Fatalf("Signature should be valid but verification returned error: %s", err)
 		return
 	}
 
+	// Test signing with a non-revocation proof; the revocation handle is the first attribute
+	revocationKey, err := GenerateLongTermRevocationKey()
+	assert.NoError(t, err)
+	cri, err := CreateCRI(revocationKey, []*FP256BN.BIG{attrs[0]}, 1, ALG_WBB_SIGNATURE, rng)
+	assert.NoError(t, err)
+	sig, err = NewSignature(cred, sk, Nym, RandNym, key.IPk, disclosure, msg, 0, cri, rng)
+	assert.NoError(t, err)
+	assert.NoError(t, sig.Ver(disclosure, key.IPk, msg, attrs, 0, &revocationKey.PublicKey, 1), "signature with non-revocation proof should be valid")
+	assert.Error(t, sig.Ver(disclosure, key.IPk, msg, attrs, 0, &revocationKey.PublicKey, 2), "signature with a non-revocation proof of an old epoch should be invalid")
+
+	// The revocation handle must not be disclosed
+	_, err = NewSignature(cred, sk, Nym, RandNym, key.IPk, disclosure, msg, 1, cri, rng)
+	assert.Error(t, err, "signing with a disclosed revocation handle should fail")
+
+	// The non-revocation proof must be linked to the revocation handle of the credential
+	sigTwoHidden, err := NewSignature(cred, sk, Nym, RandNym, key.IPk, []byte{0, 0, 1, 1, 1}, msg, 0, cri, rng)
+	assert.NoError(t, err)
+	assert.NoError(t, sigTwoHidden.Ver([]byte{0, 0, 1, 1, 1}, key.IPk, msg, attrs, 0, &revocationKey.PublicKey, 1))
+	assert.Error(t, sigTwoHidden.Ver([]byte{0, 0, 1, 1, 1}, key.IPk, msg, attrs, 1, &revocationKey.PublicKey, 1), "signature verified with the wrong revocation handle index should be invalid")
+
+	// A verifier with a different revocation key does not accept the signature
+	otherRevocationKey, err := GenerateLongTermRevocationKey()
+	assert.NoError(t, err)
+	assert.Error(t, sig.Ver(disclosure, key.IPk, msg, attrs, 0, &otherRevocationKey.PublicKey, 1), "signature with an epoch key of another revocation authority should be invalid")
+
+	// A verifier that requires a non-revocation proof does not accept a signature without one
+	sigNoRevocation, err := NewSignature(cred, sk, Nym, RandNym, key.IPk, disclosure, msg, 0, nil, rng)
+	assert.NoError(t, err)
+	assert.Error(t, sigNoRevocation.Ver(disclosure, key.IPk, msg, attrs, 0, &revocationKey.PublicKey, 1), "signature without non-revocation proof should be invalid")
+
+	// Tampering with the non-revocation proof makes the signature invalid
+	proofBytes := sig.NonRevocationProof.Proof
+	wbbProof := &WBBNonRevocationProof{}
+	assert.NoError(t, proto.Unmarshal(proofBytes, wbbProof))
+	wbbProof.ProofSRho = BigToBytes(RandModOrder(rng))
+	sig.NonRevocationProof.Proof, err = proto.Marshal(wbbProof)
+	assert.NoError(t, err)
+	assert.Error(t, sig.Ver(disclosure, key.IPk, msg, attrs, 0, &revocationKey.PublicKey, 1), "signature with a broken non-revocation proof should be invalid")
+	sig.NonRevocationProof.Proof = proofBytes
+
+	// A revoked credential cannot prove that it is not revoked
+	cri, err = CreateCRI(revocationKey, []*FP256BN.BIG{attrs[1], attrs[2]}, 2, ALG_WBB_SIGNATURE, rng)
+	assert.NoError(t, err)
+	_, err = NewSignature(cred, sk, Nym, RandNym, key.IPk, disclosure, msg, 0, cri, rng)
+	assert.EqualError(t, err, "failed to compute non-revoked proof: the credential has been revoked in epoch 2")
+
+	// A credential cannot use the revocation information of another credential
+	cri.RevocationData, err = proto.Marshal(&WBBRevocationData{Handles: [][]byte{BigToBytes(attrs[0])}, Sigs: []*ECP{EcpToProto(GenG1)}})
+	assert.NoError(t, err)
+	_, err = NewSignature(cred, sk, Nym, RandNym, key.IPk, disclosure, msg, 0, cri, rng)
+	assert.Error(t, err, "signing with an invalid weak-BB signature should fail")
+
+	// Without revocation in an epoch, any credential can sign
+	cri, err = CreateCRI(revocationKey, nil, 3, ALG_NO_REVOCATION, rng)
+	assert.NoError(t, err)
+	sig, err = NewSignature(cred, sk, Nym, RandNym, key.IPk, disclosure, msg, 0, cri, rng)
+	assert.NoError(t, err)
+	assert.NoError(t, sig.Ver(disclosure, key.IPk, msg, attrs, 0, &revocationKey.PublicKey, 3), "signature with no-revocation proof should be valid")
+
 	// Test NymSignatures
 	nymsig, err := NewNymSignature(sk, Nym, RandNym, key.IPk, []byte("testing"), rng)
 	assert.NoError(t, err)
@@ -0,0 +1,175 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idemix
+
+import (
+	"bytes"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/milagro-crypto/amcl/version3/go/amcl"
+	"github.com/milagro-crypto/amcl/version3/go/amcl/FP256BN"
+	"github.com/pkg/errors"
+)
+
+// A nonRevokedProver is a prover that can prove that an identity mixer credential is not revoked.
+// The proof is part of the zero-knowledge proof of an idemix signature: the prover commits to
+// its randomness in getFSContribution, and completes the proof with the challenge of the signature.
+// The revocation handle is a hidden attribute of the credential; rRh is the randomness used for it
+// in the signature proof, which links the non-revocation proof to the credential.
+type nonRevokedProver interface {
+	getFSContribution(rh *FP256BN.BIG, rRh *FP256BN.BIG, cri *CredentialRevocationInformation, rng *amcl.RAND) ([]byte, error)
+	getNonRevokedProof(chal *FP256BN.BIG) (*NonRevocationProof, error)
+}
+
+// getNonRevocationProver returns the nonRevokedProver bound to the algorithm
+func getNonRevocationProver(algorithm RevocationAlgorithm) (nonRevokedProver, error) {
+	switch algorithm {
+	case ALG_NO_REVOCATION:
+		return &nopNonRevokedProver{}, nil
+	case ALG_WBB_SIGNATURE:
+		return &wbbNonRevokedProver{}, nil
+	default:
+		// unknown revocation algorithm
+		return nil, errors.Errorf("unknown revocation algorithm %d", algorithm)
+	}
+}
+
+// A nonRevocationVerifier recomputes the contribution of a non-revocation proof to the
+// Fiat-Shamir hash of a signature, given the challenge and the s-value of the revocation handle
+type nonRevocationVerifier interface {
+	recomputeFSContribution(proof *NonRevocationProof, chal *FP256BN.BIG, epochPK *FP256BN.ECP2, proofSRh *FP256BN.BIG) ([]byte, error)
+}
+
+// getNonRevocationVerifier returns the nonRevocationVerifier bound to the algorithm
+func getNonRevocationVerifier(algorithm RevocationAlgorithm) (nonRevocationVerifier, error) {
+	switch algorithm {
+	case ALG_NO_REVOCATION:
+		return &nopNonRevocationVerifier{}, nil
+	case ALG_WBB_SIGNATURE:
+		return &wbbNonRevocationVerifier{}, nil
+	default:
+		// unknown revocation algorithm
+		return nil, errors.Errorf("unknown revocation algorithm %d", algorithm)
+	}
+}
+
+// nopNonRevokedProver is used when no credentials are revoked in an epoch
+type nopNonRevokedProver struct{}
+
+func (prover *nopNonRevokedProver) getFSContribution(rh *FP256BN.BIG, rRh *FP256BN.BIG, cri *CredentialRevocationInformation, rng *amcl.RAND) ([]byte, error) {
+	return nil, nil
+}
+
+func (prover *nopNonRevokedProver) getNonRevokedProof(chal *FP256BN.BIG) (*NonRevocationProof, error) {
+	return &NonRevocationProof{RevocationAlg: int32(ALG_NO_REVOCATION)}, nil
+}
+
+// nopNonRevocationVerifier is used when no credentials are revoked in an epoch
+type nopNonRevocationVerifier struct{}
+
+func (verifier *nopNonRevocationVerifier) recomputeFSContribution(proof *NonRevocationProof, chal *FP256BN.BIG, epochPK *FP256BN.ECP2, proofSRh *FP256BN.BIG) ([]byte, error) {
+	return nil, nil
+}
+
+// wbbNonRevokedProver proves knowledge of a weak Boneh-Boyen signature sigma of the revocation
+// authority on the revocation handle rh, i.e., e(sigma, epochPK * g2^rh) = e(g1, g2).
+// The signature is randomized as sigmaPrime = sigma^rho, so that the relation becomes
+// e(sigmaPrime^rh * g1^(-rho), g2) = e(sigmaPrime, epochPK)^(-1), and knowledge of rh and rho
+// is proven with a Schnorr proof
+type wbbNonRevokedProver struct {
+	rho        *FP256BN.BIG
+	rRho       *FP256BN.BIG
+	sigmaPrime *FP256BN.ECP
+}
+
+func (prover *wbbNonRevokedProver) getFSContribution(rh *FP256BN.BIG, rRh *FP256BN.BIG, cri *CredentialRevocationInformation, rng *amcl.RAND) ([]byte, error) {
+	data := &WBBRevocationData{}
+	err := proto.Unmarshal(cri.RevocationData, data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal revocation data")
+	}
+	if len(data.Handles) != len(data.Sigs) {
+		return nil, errors.Errorf("revocation data is malformed")
+	}
+
+	rhBytes := BigToBytes(rh)
+	var sigma *FP256BN.ECP
+	for i, handle := range data.Handles {
+		if bytes.Equal(handle, rhBytes) {
+			sigma = EcpFromProto(data.Sigs[i])
+			break
+		}
+	}
+	if sigma == nil {
+		return nil, errors.Errorf("the credential has been revoked in epoch %d", cri.Epoch)
+	}
+	if err = WBBVerify(Ecp2FromProto(cri.EpochPk), sigma, rh); err != nil {
+		return nil, errors.WithMessage(err, "the revocation information of the credential is invalid")
+	}
+
+	prover.rho = RandModOrder(rng)
+	prover.rRho = RandModOrder(rng)
+	prover.sigmaPrime = FP256BN.G1mul(sigma, prover.rho)
+	prover.sigmaPrime.Affine()
+
+	// t = e(sigmaPrime^rRh * g1^(-rRho), g2)
+	t := prover.sigmaPrime.Mul2(rRh, GenG1, FP256BN.Modneg(prover.rRho, GroupOrder))
+	t.Affine()
+	return wbbFSContribution(prover.sigmaPrime, FP256BN.Fexp(FP256BN.Ate(GenG2, t))), nil
+}
+
+func (prover *wbbNonRevokedProver) getNonRevokedProof(chal *FP256BN.BIG) (*NonRevocationProof, error) {
+	if prover.sigmaPrime == nil {
+		return nil, errors.Errorf("no commitment to the non-revocation proof")
+	}
+	proofSRho := Modadd(prover.rRho, FP256BN.Modmul(chal, prover.rho, GroupOrder), GroupOrder)
+	proofBytes, err := proto.Marshal(&WBBNonRevocationProof{
+		SigmaPrime: EcpToProto(prover.sigmaPrime),
+		ProofSRho:  BigToBytes(proofSRho),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal non-revocation proof")
+	}
+	return &NonRevocationProof{RevocationAlg: int32(ALG_WBB_SIGNATURE), Proof: proofBytes}, nil
+}
+
+// wbbNonRevocationVerifier verifies the proofs of wbbNonRevokedProver
+type wbbNonRevocationVerifier struct{}
+
+func (verifier *wbbNonRevocationVerifier) recomputeFSContribution(proof *NonRevocationProof, chal *FP256BN.BIG, epochPK *FP256BN.ECP2, proofSRh *FP256BN.BIG) ([]byte, error) {
+	wbbProof := &WBBNonRevocationProof{}
+	err := proto.Unmarshal(proof.Proof, wbbProof)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal non-revocation proof")
+	}
+	if wbbProof.SigmaPrime == nil {
+		return nil, errors.Errorf("non-revocation proof invalid: no randomized signature")
+	}
+	sigmaPrime := EcpFromProto(wbbProof.SigmaPrime)
+	if sigmaPrime.Is_infinity() {
+		return nil, errors.Errorf("non-revocation proof invalid: SigmaPrime = 1")
+	}
+	proofSRho := FP256BN.FromBytes(wbbProof.ProofSRho)
+
+	// t = e(sigmaPrime^sRh * g1^(-sRho), g2) * e(sigmaPrime^c, epochPK)
+	t := sigmaPrime.Mul2(proofSRh, GenG1, FP256BN.Modneg(proofSRho, GroupOrder))
+	t.Affine()
+	sigmaPrimeC := FP256BN.G1mul(sigmaPrime, chal)
+	sigmaPrimeC.Affine()
+	W := FP256BN.NewECP2()
+	W.Copy(epochPK)
+	W.Affine()
+	return wbbFSContribution(sigmaPrime, FP256BN.Fexp(FP256BN.Ate2(GenG2, t, W, sigmaPrimeC))), nil
+}
+
+// wbbFSContribution serializes the randomized signature and the commitment of the proof
+func wbbFSContribution(sigmaPrime *FP256BN.ECP, t *FP256BN.FP12) []byte {
+	data := make([]byte, 2*FieldBytes+1+12*FieldBytes)
+	index := appendBytesG1(data, 0, sigmaPrime)
+	appendBytesGT(data, index, t)
+	return data
+}
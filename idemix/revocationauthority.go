@@ -0,0 +1,128 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idemix
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/asn1"
+	"math/big"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/milagro-crypto/amcl/version3/go/amcl"
+	"github.com/milagro-crypto/amcl/version3/go/amcl/FP256BN"
+	"github.com/pkg/errors"
+)
+
+// The revocation authority (RA) allows a user to prove that her credential has not been revoked.
+// Time is divided into epochs; at the beginning of every epoch the RA creates a fresh epoch key
+// pair and publishes the epoch public key, signed with its long term key, together with the
+// revocation information (CRI) a user needs to prove in zero-knowledge that her credential
+// (identified by a hidden revocation handle attribute) has not been revoked in that epoch.
+// A credential is revoked by no longer including its revocation handle in the CRI of the
+// next epochs; verifiers only accept non-revocation proofs for the current epoch.
+
+// RevocationAlgorithm identifies the revocation algorithm
+type RevocationAlgorithm int32
+
+const (
+	// ALG_NO_REVOCATION means that no credentials are revoked in the epoch
+	ALG_NO_REVOCATION RevocationAlgorithm = iota
+	// ALG_WBB_SIGNATURE means that the RA places a weak Boneh-Boyen signature
+	// with the epoch key on the revocation handle of every unrevoked credential
+	ALG_WBB_SIGNATURE
+)
+
+// GenerateLongTermRevocationKey generates a long term signing key that will be used for revocation
+func GenerateLongTermRevocationKey() (*ecdsa.PrivateKey, error) {
+	return ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+}
+
+// CreateCRI creates the Credential Revocation Information for a certain time period (epoch).
+// Users can use the CRI to prove that they are not revoked.
+// Note that when not using revocation (i.e., alg = ALG_NO_REVOCATION), the entered unrevokedHandles are not used,
+// and the resulting CRI can be used by any signer.
+func CreateCRI(key *ecdsa.PrivateKey, unrevokedHandles []*FP256BN.BIG, epoch int, alg RevocationAlgorithm, rng *amcl.RAND) (*CredentialRevocationInformation, error) {
+	if key == nil || rng == nil {
+		return nil, errors.Errorf("CreateCRI received nil input")
+	}
+
+	cri := &CredentialRevocationInformation{}
+	cri.RevocationAlg = int32(alg)
+	cri.Epoch = int64(epoch)
+
+	var epochSk *FP256BN.BIG
+	switch alg {
+	case ALG_NO_REVOCATION:
+		// put a dummy PK in the proto
+		cri.EpochPk = Ecp2ToProto(GenG2)
+	case ALG_WBB_SIGNATURE:
+		// create a fresh epoch key
+		var epochPk *FP256BN.ECP2
+		epochSk, epochPk = WBBKeyGen(rng)
+		cri.EpochPk = Ecp2ToProto(epochPk)
+	default:
+		return nil, errors.Errorf("the specified revocation algorithm is not supported")
+	}
+
+	// sign epoch + epoch key with the long term key
+	bytesToSign, err := proto.Marshal(cri)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal CRI")
+	}
+	digest := sha256.Sum256(bytesToSign)
+	cri.EpochPkSig, err = key.Sign(rand.Reader, digest[:], nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to sign epoch public key")
+	}
+
+	if alg == ALG_WBB_SIGNATURE {
+		data := &WBBRevocationData{}
+		for _, rh := range unrevokedHandles {
+			data.Handles = append(data.Handles, BigToBytes(rh))
+			data.Sigs = append(data.Sigs, EcpToProto(WBBSign(epochSk, rh)))
+		}
+		cri.RevocationData, err = proto.Marshal(data)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal revocation data")
+		}
+	}
+
+	return cri, nil
+}
+
+// VerifyEpochPK verifies that the revocation PK for a certain epoch is valid,
+// by checking that it was signed with the long term revocation key.
+// Note that even if we use no revocation (i.e., alg = ALG_NO_REVOCATION), we need
+// to verify the signature to make sure the issuer indeed signed that no revocation
+// is used in this epoch.
+func VerifyEpochPK(pk *ecdsa.PublicKey, epochPK *ECP2, epochPkSig []byte, epoch int, alg RevocationAlgorithm) error {
+	if pk == nil || epochPK == nil {
+		return errors.Errorf("EpochPK invalid: received nil input")
+	}
+	cri := &CredentialRevocationInformation{}
+	cri.RevocationAlg = int32(alg)
+	cri.EpochPk = epochPK
+	cri.Epoch = int64(epoch)
+	bytesToSign, err := proto.Marshal(cri)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal CRI")
+	}
+	digest := sha256.Sum256(bytesToSign)
+
+	var sig struct{ R, S *big.Int }
+	if _, err := asn1.Unmarshal(epochPkSig, &sig); err != nil {
+		return errors.Wrap(err, "failed unmarshalling signature")
+	}
+	if !ecdsa.Verify(pk, digest[:], sig.R, sig.S) {
+		return errors.Errorf("EpochPKSig invalid")
+	}
+
+	return nil
+}
@@ -7,6 +7,8 @@ SPDX-License-Identifier: Apache-2.0
 package idemix
 
 import (
+	"crypto/ecdsa"
+
 	"github.com/milagro-crypto/amcl/version3/go/amcl"
 	"github.com/milagro-crypto/amcl/version3/go/amcl/FP256BN"
 	"github.com/pkg/errors"
@@ -39,17 +41,46 @@ func hiddenIndices(Disclosure []byte) []int {
 	return HiddenIndices
 }
 
+// hiddenPosition returns the position of attribute index in HiddenIndices, or -1 if it is disclosed
+func hiddenPosition(HiddenIndices []int, index int) int {
+	for i, j := range HiddenIndices {
+		if j == index {
+			return i
+		}
+	}
+	return -1
+}
+
 // NewSignature creates a new idemix signature (Schnorr-type signature)
 // The []byte Disclosure steers which attributes are disclosed:
 // if Disclosure[i] == 0 then attribute i remains hidden and otherwise it is disclosed.
+// If cri is not nil, the signature includes a proof that the credential, whose revocation
+// handle is the (hidden) attribute at index rhIndex, is not revoked in the epoch of cri.
 // We use the zero-knowledge proof by http://eprint.iacr.org/2016/663.pdf to prove knowledge of a BBS+ signature
-func NewSignature(cred *Credential, sk *FP256BN.BIG, Nym *FP256BN.ECP, RNym *FP256BN.BIG, ipk *IssuerPublicKey, Disclosure []byte, msg []byte, rng *amcl.RAND) (*Signature, error) {
+func NewSignature(cred *Credential, sk *FP256BN.BIG, Nym *FP256BN.ECP, RNym *FP256BN.BIG, ipk *IssuerPublicKey, Disclosure []byte, msg []byte, rhIndex int, cri *CredentialRevocationInformation, rng *amcl.RAND) (*Signature, error) {
 	if cred == nil || sk == nil || Nym == nil || RNym == nil || ipk == nil || rng == nil {
 		return nil, errors.Errorf("cannot create idemix signature: received nil input")
 	}
 
 	HiddenIndices := hiddenIndices(Disclosure)
 
+	var prover nonRevokedProver
+	rhPosition := -1
+	if cri != nil {
+		if rhIndex < 0 || rhIndex >= len(cred.Attrs) {
+			return nil, errors.Errorf("cannot create idemix signature: invalid revocation handle index %d", rhIndex)
+		}
+		rhPosition = hiddenPosition(HiddenIndices, rhIndex)
+		if rhPosition < 0 {
+			return nil, errors.Errorf("cannot create idemix signature: the revocation handle must not be disclosed")
+		}
+		var err error
+		prover, err = getNonRevocationProver(RevocationAlgorithm(cri.RevocationAlg))
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Start sig
 	r1 := RandModOrder(rng)
 	r2 := RandModOrder(rng)
@@ -100,13 +131,25 @@ func NewSignature(cred *Credential, sk *FP256BN.BIG, Nym *FP256BN.ECP, RNym *FP2
 
 	t3 := HSk.Mul2(rSk, HRand, rRNym)
 
+	// commit to the non-revocation proof
+	var nonRevokedProofHashData []byte
+	if prover != nil {
+		var err error
+		nonRevokedProofHashData, err = prover.getFSContribution(FP256BN.FromBytes(cred.Attrs[rhIndex]), rAttrs[rhPosition], cri, rng)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to compute non-revoked proof")
+		}
+	}
+
 	// proofData is the data being hashed, it consists of:
 	// the signature label
 	// 7 elements of G1 each taking 2*FieldBytes+1 bytes
 	// one bigint (hash of the issuer public key) of length FieldBytes
 	// disclosed attributes
 	// message being signed
-	proofData := make([]byte, len([]byte(signLabel))+7*(2*FieldBytes+1)+FieldBytes+len(Disclosure)+len(msg))
+	// if the signature includes a non-revocation proof,
+	// the epoch public key and the contribution of the proof
+	proofData := make([]byte, len([]byte(signLabel))+7*(2*FieldBytes+1)+FieldBytes+len(Disclosure)+len(msg)+revocationBytes(cri != nil, nonRevokedProofHashData))
 	index := 0
 	index = appendBytesString(proofData, index, signLabel)
 	index = appendBytesG1(proofData, index, t1)
@@ -121,6 +164,11 @@ func NewSignature(cred *Credential, sk *FP256BN.BIG, Nym *FP256BN.ECP, RNym *FP2
 	copy(proofData[index:], Disclosure)
 	index = index + len(Disclosure)
 	copy(proofData[index:], msg)
+	index = index + len(msg)
+	if cri != nil {
+		index = appendBytesG2(proofData, index, Ecp2FromProto(cri.EpochPk))
+		copy(proofData[index:], nonRevokedProofHashData)
+	}
 	c := HashModOrder(proofData)
 
 	// add the previous hash and the nonce and hash again to compute a second hash (C value)
@@ -141,27 +189,51 @@ func NewSignature(cred *Credential, sk *FP256BN.BIG, Nym *FP256BN.ECP, RNym *FP2
 		ProofSAttrs[i] = BigToBytes(Modadd(rAttrs[i], FP256BN.Modmul(ProofC, FP256BN.FromBytes(cred.Attrs[j]), GroupOrder), GroupOrder))
 	}
 
-	return &Signature{
-			EcpToProto(APrime),
-			EcpToProto(ABar),
-			EcpToProto(BPrime),
-			BigToBytes(ProofC),
-			BigToBytes(ProofSSk),
-			BigToBytes(ProofSE),
-			BigToBytes(ProofSR2),
-			BigToBytes(ProofSR3),
-			BigToBytes(ProofSSPrime),
-			ProofSAttrs,
-			BigToBytes(Nonce),
-			EcpToProto(Nym),
-			BigToBytes(ProofSRNym)},
-		nil
+	sig := &Signature{
+		APrime:       EcpToProto(APrime),
+		ABar:         EcpToProto(ABar),
+		BPrime:       EcpToProto(BPrime),
+		ProofC:       BigToBytes(ProofC),
+		ProofSSk:     BigToBytes(ProofSSk),
+		ProofSE:      BigToBytes(ProofSE),
+		ProofSR2:     BigToBytes(ProofSR2),
+		ProofSR3:     BigToBytes(ProofSR3),
+		ProofSSPrime: BigToBytes(ProofSSPrime),
+		ProofSAttrs:  ProofSAttrs,
+		Nonce:        BigToBytes(Nonce),
+		Nym:          EcpToProto(Nym),
+		ProofSRNym:   BigToBytes(ProofSRNym),
+	}
+
+	if prover != nil {
+		nonRevokedProof, err := prover.getNonRevokedProof(ProofC)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to compute non-revoked proof")
+		}
+		sig.RevocationEpochPk = cri.EpochPk
+		sig.RevocationPkSig = cri.EpochPkSig
+		sig.Epoch = cri.Epoch
+		sig.NonRevocationProof = nonRevokedProof
+	}
+
+	return sig, nil
+}
+
+// revocationBytes returns the number of bytes the non-revocation proof adds to the
+// data hashed in the zero-knowledge proof of a signature
+func revocationBytes(revocation bool, nonRevokedProofHashData []byte) int {
+	if !revocation {
+		return 0
+	}
+	return 4*FieldBytes + len(nonRevokedProofHashData)
 }
 
 // Ver verifies an idemix signature
 // Disclosure steers which attributes it expects to be disclosed
 // attributeValues[i] contains the desired attribute value for the i-th undisclosed attribute in Disclosure
-func (sig *Signature) Ver(Disclosure []byte, ipk *IssuerPublicKey, msg []byte, attributeValues []*FP256BN.BIG) error {
+// If revPk is not nil, the signature must prove that the credential, whose revocation handle is the
+// (hidden) attribute at index rhIndex, is not revoked in the given epoch
+func (sig *Signature) Ver(Disclosure []byte, ipk *IssuerPublicKey, msg []byte, attributeValues []*FP256BN.BIG, rhIndex int, revPk *ecdsa.PublicKey, epoch int) error {
 	HiddenIndices := hiddenIndices(Disclosure)
 
 	APrime := EcpFromProto(sig.GetAPrime())
@@ -190,6 +262,35 @@ func (sig *Signature) Ver(Disclosure []byte, ipk *IssuerPublicKey, msg []byte, a
 	HRand := EcpFromProto(ipk.HRand)
 	HSk := EcpFromProto(ipk.HSk)
 
+	var verifier nonRevocationVerifier
+	rhPosition := -1
+	if sig.NonRevocationProof != nil {
+		rhPosition = hiddenPosition(HiddenIndices, rhIndex)
+		if rhPosition < 0 {
+			return errors.Errorf("signature invalid: the revocation handle must not be disclosed")
+		}
+		if sig.RevocationEpochPk == nil {
+			return errors.Errorf("signature invalid: no epoch public key")
+		}
+		var err error
+		verifier, err = getNonRevocationVerifier(RevocationAlgorithm(sig.NonRevocationProof.RevocationAlg))
+		if err != nil {
+			return errors.WithMessage(err, "signature invalid")
+		}
+	}
+	if revPk != nil {
+		if sig.NonRevocationProof == nil {
+			return errors.Errorf("signature invalid: no non-revocation proof")
+		}
+		if sig.Epoch != int64(epoch) {
+			return errors.Errorf("signature invalid: non-revocation proof is for epoch %d, expected epoch %d", sig.Epoch, epoch)
+		}
+		err := VerifyEpochPK(revPk, sig.RevocationEpochPk, sig.RevocationPkSig, int(sig.Epoch), RevocationAlgorithm(sig.NonRevocationProof.RevocationAlg))
+		if err != nil {
+			return errors.WithMessage(err, "signature invalid: epoch public key is not signed by the revocation authority")
+		}
+	}
+
 	if APrime.Is_infinity() {
 		return errors.Errorf("signature invalid: APrime = 1")
 	}
@@ -230,13 +331,25 @@ func (sig *Signature) Ver(Disclosure []byte, ipk *IssuerPublicKey, msg []byte, a
 	t3 := HSk.Mul2(ProofSSk, HRand, ProofSRNym)
 	t3.Sub(Nym.Mul(ProofC))
 
+	// recompute the commitment of the non-revocation proof
+	var nonRevokedProofHashData []byte
+	if verifier != nil {
+		var err error
+		nonRevokedProofHashData, err = verifier.recomputeFSContribution(sig.NonRevocationProof, ProofC, Ecp2FromProto(sig.RevocationEpochPk), ProofSAttrs[rhPosition])
+		if err != nil {
+			return errors.WithMessage(err, "signature invalid: non-revocation proof is invalid")
+		}
+	}
+
 	// proofData is the data being hashed, it consists of:
 	// the signature label
 	// 7 elements of G1 each taking 2*FieldBytes+1 bytes
 	// one bigint (hash of the issuer public key) of length FieldBytes
 	// disclosed attributes
 	// message that was signed
-	proofData := make([]byte, len([]byte(signLabel))+7*(2*FieldBytes+1)+FieldBytes+len(Disclosure)+len(msg))
+	// if the signature includes a non-revocation proof,
+	// the epoch public key and the contribution of the proof
+	proofData := make([]byte, len([]byte(signLabel))+7*(2*FieldBytes+1)+FieldBytes+len(Disclosure)+len(msg)+revocationBytes(verifier != nil, nonRevokedProofHashData))
 	index := 0
 	index = appendBytesString(proofData, index, signLabel)
 	index = appendBytesG1(proofData, index, t1)
@@ -251,6 +364,11 @@ func (sig *Signature) Ver(Disclosure []byte, ipk *IssuerPublicKey, msg []byte, a
 	copy(proofData[index:], Disclosure)
 	index = index + len(Disclosure)
 	copy(proofData[index:], msg)
+	index = index + len(msg)
+	if verifier != nil {
+		index = appendBytesG2(proofData, index, Ecp2FromProto(sig.RevocationEpochPk))
+		copy(proofData[index:], nonRevokedProofHashData)
+	}
 
 	c := HashModOrder(proofData)
 	index = 0
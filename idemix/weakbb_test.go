@@ -0,0 +1,48 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package idemix
+
+import (
+	"testing"
+
+	"github.com/milagro-crypto/amcl/version3/go/amcl/FP256BN"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWBB(t *testing.T) {
+	rng, err := GetRand()
+	assert.NoError(t, err)
+
+	sk, pk := WBBKeyGen(rng)
+	m := RandModOrder(rng)
+	sig := WBBSign(sk, m)
+	assert.NoError(t, WBBVerify(pk, sig, m), "weak-BB signature should be valid")
+
+	assert.Error(t, WBBVerify(pk, sig, RandModOrder(rng)), "weak-BB signature on another message should be invalid")
+	_, otherPk := WBBKeyGen(rng)
+	assert.Error(t, WBBVerify(otherPk, sig, m), "weak-BB signature with another key should be invalid")
+	assert.Error(t, WBBVerify(nil, sig, m), "weak-BB verification with nil input should fail")
+}
+
+func TestEpochPK(t *testing.T) {
+	rng, err := GetRand()
+	assert.NoError(t, err)
+	key, err := GenerateLongTermRevocationKey()
+	assert.NoError(t, err)
+
+	cri, err := CreateCRI(key, []*FP256BN.BIG{RandModOrder(rng)}, 5, ALG_WBB_SIGNATURE, rng)
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyEpochPK(&key.PublicKey, cri.EpochPk, cri.EpochPkSig, 5, ALG_WBB_SIGNATURE))
+	assert.EqualError(t, VerifyEpochPK(&key.PublicKey, cri.EpochPk, cri.EpochPkSig, 6, ALG_WBB_SIGNATURE), "EpochPKSig invalid")
+	assert.EqualError(t, VerifyEpochPK(&key.PublicKey, cri.EpochPk, cri.EpochPkSig, 5, ALG_NO_REVOCATION), "EpochPKSig invalid")
+	assert.EqualError(t, VerifyEpochPK(nil, cri.EpochPk, cri.EpochPkSig, 5, ALG_WBB_SIGNATURE), "EpochPK invalid: received nil input")
+
+	_, err = CreateCRI(key, nil, 5, RevocationAlgorithm(7), rng)
+	assert.EqualError(t, err, "the specified revocation algorithm is not supported")
+	_, err = CreateCRI(nil, nil, 5, ALG_NO_REVOCATION, rng)
+	assert.Error(t, err)
+}
@@ -12,6 +12,8 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/bccsp"
@@ -358,10 +360,12 @@ func getMspConfig(dir string, ID string, sigid *msp.SigningIdentityInfo) (*msp.M
 }
 
 const (
-	IdemixConfigDirMsp              = "msp"
-	IdemixConfigDirUser             = "user"
-	IdemixConfigFileIssuerPublicKey = "IssuerPublicKey"
-	IdemixConfigFileSigner          = "SignerConfig"
+	IdemixConfigDirMsp                  = "msp"
+	IdemixConfigDirUser                 = "user"
+	IdemixConfigFileIssuerPublicKey     = "IssuerPublicKey"
+	IdemixConfigFileSigner              = "SignerConfig"
+	IdemixConfigFileRevocationPublicKey = "RevocationPublicKey"
+	IdemixConfigFileRevocationEpoch     = "RevocationEpoch"
 )
 
 // GetIdemixMspConfig returns the configuration for the Idemix MSP
@@ -376,6 +380,20 @@ func GetIdemixMspConfig(dir string, ID string) (*msp.MSPConfig, error) {
 		IPk:  ipkBytes,
 	}
 
+	// the revocation public key is optional, idemix revocation is disabled without it
+	revocationPkBytes, err := readFile(filepath.Join(dir, IdemixConfigDirMsp, IdemixConfigFileRevocationPublicKey))
+	if err == nil {
+		idemixConfig.RevocationPk = revocationPkBytes
+
+		epochBytes, err := readFile(filepath.Join(dir, IdemixConfigDirMsp, IdemixConfigFileRevocationEpoch))
+		if err == nil {
+			idemixConfig.Epoch, err = strconv.ParseInt(strings.TrimSpace(string(epochBytes)), 10, 64)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to parse revocation epoch")
+			}
+		}
+	}
+
 	signerBytes, err := readFile(filepath.Join(dir, IdemixConfigDirUser, IdemixConfigFileSigner))
 	if err == nil {
 		signerConfig := &msp.IdemixMSPSignerConfig{}
@@ -8,7 +8,10 @@ package msp
 
 import (
 	"bytes"
+	"crypto/ecdsa"
+	"crypto/x509"
 	"encoding/hex"
+	"encoding/pem"
 	"time"
 
 	"github.com/golang/protobuf/proto"
@@ -26,17 +29,27 @@ const (
 
 	// AttributeNameRole is the attribute name of the Role attribute
 	AttributeNameRole = "Role"
+
+	// AttributeNameRevocationHandle is the attribute name of the revocation handle attribute
+	AttributeNameRevocationHandle = "RevocationHandle"
 )
 
-// discloseFlags will be passed to the idemix signing and verification routines.
-// It informs idemix to disclose both attributes (OU and Role) when signing.
-var discloseFlags = []byte{1, 1}
+// index of the revocation handle attribute in the credential
+const rhIndex = 2
 
 type idemixmsp struct {
 	ipk    *idemix.IssuerPublicKey
 	rng    *amcl.RAND
 	signer *idemixSigningIdentity
 	name   string
+	// discloseFlags will be passed to the idemix signing and verification routines.
+	// It informs idemix to disclose the OU and Role attributes when signing,
+	// and to keep the revocation handle (if any) hidden.
+	discloseFlags []byte
+	// revocationPK is the public key of the revocation authority; if it is set,
+	// identities must prove that their credential is not revoked in epoch
+	revocationPK *ecdsa.PublicKey
+	epoch        int
 }
 
 // newIdemixMsp creates a new instance of idemixmsp
@@ -80,6 +93,13 @@ func (msp *idemixmsp) Setup(conf1 *m.MSPConfig) error {
 	if len(ipk.AttributeNames) < 2 || ipk.AttributeNames[0] != AttributeNameOU || ipk.AttributeNames[1] != AttributeNameRole {
 		return errors.Errorf("ipk must have have attributes OU and Role")
 	}
+	if len(ipk.AttributeNames) > 3 || (len(ipk.AttributeNames) == 3 && ipk.AttributeNames[rhIndex] != AttributeNameRevocationHandle) {
+		return errors.Errorf("the third attribute of the ipk must be the revocation handle")
+	}
+	msp.discloseFlags = []byte{1, 1}
+	if len(ipk.AttributeNames) > rhIndex {
+		msp.discloseFlags = append(msp.discloseFlags, 0)
+	}
 
 	err = ipk.Check()
 	if err != nil {
@@ -87,6 +107,17 @@ func (msp *idemixmsp) Setup(conf1 *m.MSPConfig) error {
 	}
 	msp.ipk = ipk
 
+	if len(conf.RevocationPk) != 0 {
+		if len(ipk.AttributeNames) <= rhIndex {
+			return errors.Errorf("revocation requires the ipk to have the attribute %s", AttributeNameRevocationHandle)
+		}
+		msp.revocationPK, err = getRevocationPublicKey(conf.RevocationPk)
+		if err != nil {
+			return err
+		}
+		msp.epoch = int(conf.Epoch)
+	}
+
 	rng, err := idemix.GetRand()
 	if err != nil {
 		return errors.Wrap(err, "error initializing PRNG for idemix msp")
@@ -124,9 +155,9 @@ func (msp *idemixmsp) Setup(conf1 *m.MSPConfig) error {
 		CertifiersIdentifier:         ipk.Hash,
 	}
 
-	// Check if credential contains the right amount of attribute values (Role and OU)
-	if len(cred.Attrs) != 2 {
-		return errors.Errorf("Credential contains %d attribute values, but expected 2", len(cred.Attrs))
+	// Check if credential contains the right amount of attribute values (Role, OU and optionally the revocation handle)
+	if len(cred.Attrs) != len(ipk.AttributeNames) {
+		return errors.Errorf("Credential contains %d attribute values, but expected %d", len(cred.Attrs), len(ipk.AttributeNames))
 	}
 
 	// Check if credential contains the correct OU attribute value
@@ -146,8 +177,23 @@ func (msp *idemixmsp) Setup(conf1 *m.MSPConfig) error {
 		return errors.Wrap(err, "Credential is not cryptographically valid")
 	}
 
+	// Get the revocation information that proves that the credential is not revoked
+	var cri *idemix.CredentialRevocationInformation
+	if len(conf.Signer.CredentialRevocationInformation) != 0 {
+		if len(ipk.AttributeNames) <= rhIndex {
+			return errors.Errorf("credential revocation information requires the ipk to have the attribute %s", AttributeNameRevocationHandle)
+		}
+		cri = &idemix.CredentialRevocationInformation{}
+		err = proto.Unmarshal(conf.Signer.CredentialRevocationInformation, cri)
+		if err != nil {
+			return errors.Wrap(err, "failed to unmarshal credential revocation information")
+		}
+	} else if msp.revocationPK != nil {
+		return errors.New("revocation is enabled, but the signer config contains no credential revocation information")
+	}
+
 	// Create the cryptographic evidence that this identity is valid
-	proof, err := idemix.NewSignature(cred, sk, Nym, RandNym, ipk, discloseFlags, nil, rng)
+	proof, err := idemix.NewSignature(cred, sk, Nym, RandNym, ipk, msp.discloseFlags, nil, rhIndex, cri, rng)
 	if err != nil {
 		return errors.Wrap(err, "Failed to setup cryptographic proof of identity")
 	}
@@ -253,7 +299,24 @@ func (id *idemixidentity) verifyProof() error {
 	ouBytes := []byte(id.OU.OrganizationalUnitIdentifier)
 	attributeValues := []*FP256BN.BIG{idemix.HashModOrder(ouBytes), FP256BN.NewBIGint(int(id.Role.Role))}
 
-	return id.associationProof.Ver(discloseFlags, id.msp.ipk, nil, attributeValues)
+	return id.associationProof.Ver(id.msp.discloseFlags, id.msp.ipk, nil, attributeValues, rhIndex, id.msp.revocationPK, id.msp.epoch)
+}
+
+// getRevocationPublicKey parses the PEM encoded public key of the revocation authority
+func getRevocationPublicKey(pemBytes []byte) (*ecdsa.PublicKey, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, errors.New("failed to decode PEM block of the revocation public key")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse revocation public key")
+	}
+	pk, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, errors.New("the revocation public key is not an ECDSA public key")
+	}
+	return pk, nil
 }
 
 func (msp *idemixmsp) SatisfiesPrincipal(id Identity, principal *m.MSPPrincipal) error {
@@ -388,8 +451,8 @@ func newIdemixIdentity(msp *idemixmsp, nym *FP256BN.ECP, role *m.MSPRole, ou *m.
 }
 
 func (id *idemixidentity) ExpiresAt() time.Time {
-	// Idemix MSP currently does not use expiration dates or revocation,
-	// so we return the zero time to indicate this.
+	// Idemix MSP does not use expiration dates; revocation is handled
+	// with epochs instead, so we return the zero time to indicate this.
 	return time.Time{}
 }
 
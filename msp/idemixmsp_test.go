@@ -7,11 +7,15 @@ SPDX-License-Identifier: Apache-2.0
 package msp
 
 import (
+	"crypto/ecdsa"
+	"crypto/x509"
+	"encoding/pem"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/idemix"
 	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/milagro-crypto/amcl/version3/go/amcl/FP256BN"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 )
@@ -478,3 +482,118 @@ func TestPrincipalBad(t *testing.T) {
 	err = id1.SatisfiesPrincipal(principal)
 	assert.Error(t, err, "Principal with bad Classification should fail")
 }
+
+// getRevocationConfig creates the config of an idemix msp with revocation enabled in
+// verifierEpoch, whose signer has the revocation handle rh and the revocation information
+// of criEpoch, in which the handles unrevoked are not revoked
+func getRevocationConfig(t *testing.T, rh int, unrevoked []int, criEpoch int, verifierEpoch int) *msp.MSPConfig {
+	rng, err := idemix.GetRand()
+	assert.NoError(t, err)
+	key, err := idemix.NewIssuerKey([]string{AttributeNameOU, AttributeNameRole, AttributeNameRevocationHandle}, rng)
+	assert.NoError(t, err)
+	revocationKey, err := idemix.GenerateLongTermRevocationKey()
+	assert.NoError(t, err)
+
+	sk := idemix.RandModOrder(rng)
+	randCred := idemix.RandModOrder(rng)
+	credRequest := idemix.NewCredRequest(sk, randCred, idemix.RandModOrder(rng), key.IPk, rng)
+	attrs := []*FP256BN.BIG{idemix.HashModOrder([]byte("OU1")), FP256BN.NewBIGint(int(msp.MSPRole_MEMBER)), FP256BN.NewBIGint(rh)}
+	cred, err := idemix.NewCredential(key, credRequest, attrs, rng)
+	assert.NoError(t, err)
+	cred.Complete(randCred)
+
+	var unrevokedHandles []*FP256BN.BIG
+	for _, h := range unrevoked {
+		unrevokedHandles = append(unrevokedHandles, FP256BN.NewBIGint(h))
+	}
+	cri, err := idemix.CreateCRI(revocationKey, unrevokedHandles, criEpoch, idemix.ALG_WBB_SIGNATURE, rng)
+	assert.NoError(t, err)
+
+	ipkBytes, err := proto.Marshal(key.IPk)
+	assert.NoError(t, err)
+	credBytes, err := proto.Marshal(cred)
+	assert.NoError(t, err)
+	criBytes, err := proto.Marshal(cri)
+	assert.NoError(t, err)
+	pkBytes, err := x509.MarshalPKIXPublicKey(&revocationKey.PublicKey)
+	assert.NoError(t, err)
+
+	confBytes, err := proto.Marshal(&msp.IdemixMSPConfig{
+		Name:         "MSP1",
+		IPk:          ipkBytes,
+		RevocationPk: pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkBytes}),
+		Epoch:        int64(verifierEpoch),
+		Signer: &msp.IdemixMSPSignerConfig{
+			Cred:                            credBytes,
+			Sk:                              idemix.BigToBytes(sk),
+			OrganizationalUnitIdentifier:    "OU1",
+			CredentialRevocationInformation: criBytes,
+		},
+	})
+	assert.NoError(t, err)
+	return &msp.MSPConfig{Config: confBytes, Type: int32(IDEMIX)}
+}
+
+func TestIdemixRevocation(t *testing.T) {
+	// a credential that is not revoked in the current epoch is valid
+	msp1, err := newIdemixMsp()
+	assert.NoError(t, err)
+	assert.NoError(t, msp1.Setup(getRevocationConfig(t, 1, []int{1, 2}, 1, 1)))
+	id, err := getDefaultSigner(msp1)
+	assert.NoError(t, err)
+	idBytes, err := id.Serialize()
+	assert.NoError(t, err)
+	deserializedID, err := msp1.DeserializeIdentity(idBytes)
+	assert.NoError(t, err)
+	assert.NoError(t, deserializedID.Validate())
+
+	// a revoked credential cannot prove that it is not revoked
+	msp2, err := newIdemixMsp()
+	assert.NoError(t, err)
+	err = msp2.Setup(getRevocationConfig(t, 1, []int{2, 3}, 1, 1))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the credential has been revoked in epoch 1")
+
+	// the revocation information of an old epoch is not accepted
+	msp3, err := newIdemixMsp()
+	assert.NoError(t, err)
+	assert.NoError(t, msp3.Setup(getRevocationConfig(t, 1, []int{1}, 1, 2)))
+	_, err = getDefaultSigner(msp3)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "non-revocation proof is for epoch 1, expected epoch 2")
+}
+
+func TestIdemixRevocationBad(t *testing.T) {
+	conf := getRevocationConfig(t, 1, []int{1}, 1, 1)
+	idemixConfig := &msp.IdemixMSPConfig{}
+	assert.NoError(t, proto.Unmarshal(conf.Config, idemixConfig))
+
+	// revocation requires credential revocation information for the signer
+	criBytes := idemixConfig.Signer.CredentialRevocationInformation
+	idemixConfig.Signer.CredentialRevocationInformation = nil
+	msp1, err := newIdemixMsp()
+	assert.NoError(t, err)
+	conf.Config, err = proto.Marshal(idemixConfig)
+	assert.NoError(t, err)
+	assert.EqualError(t, msp1.Setup(conf), "revocation is enabled, but the signer config contains no credential revocation information")
+	idemixConfig.Signer.CredentialRevocationInformation = criBytes
+
+	// the revocation public key must be an ECDSA public key
+	idemixConfig.RevocationPk = []byte("barf")
+	conf.Config, err = proto.Marshal(idemixConfig)
+	assert.NoError(t, err)
+	assert.EqualError(t, msp1.Setup(conf), "failed to decode PEM block of the revocation public key")
+
+	// an identity with an epoch key signed by another revocation authority is invalid
+	otherKey, err := idemix.GenerateLongTermRevocationKey()
+	assert.NoError(t, err)
+	pkBytes, err := x509.MarshalPKIXPublicKey(otherKey.Public().(*ecdsa.PublicKey))
+	assert.NoError(t, err)
+	idemixConfig.RevocationPk = pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkBytes})
+	conf.Config, err = proto.Marshal(idemixConfig)
+	assert.NoError(t, err)
+	assert.NoError(t, msp1.Setup(conf))
+	_, err = getDefaultSigner(msp1)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "epoch public key is not signed by the revocation authority")
+}
@@ -38,6 +38,15 @@ type Receiver interface {
 	Cut() []*cb.Envelope
 }
 
+// BacklogReceiver is a Receiver which may keep messages pending when it cuts
+// a batch of messages ordered after them. It cuts the same batches when the
+// messages are ordered again only from a message ordered when it had no
+// pending message, so Kafka resumes from the offset of such a message.
+type BacklogReceiver interface {
+	Receiver
+	backlog()
+}
+
 type receiver struct {
 	sharedConfigFetcher   OrdererConfigFetcher
 	pendingBatch          []*cb.Envelope
@@ -19,6 +19,24 @@ type OrdererConfig struct {
 	consensusTypeReturnsOnCall map[int]struct {
 		result1 string
 	}
+	ConsensusMetadataStub        func() []byte
+	consensusMetadataMutex       sync.RWMutex
+	consensusMetadataArgsForCall []struct{}
+	consensusMetadataReturns     struct {
+		result1 []byte
+	}
+	consensusMetadataReturnsOnCall map[int]struct {
+		result1 []byte
+	}
+	ConsensusStateStub        func() ab.ConsensusType_State
+	consensusStateMutex       sync.RWMutex
+	consensusStateArgsForCall []struct{}
+	consensusStateReturns     struct {
+		result1 ab.ConsensusType_State
+	}
+	consensusStateReturnsOnCall map[int]struct {
+		result1 ab.ConsensusType_State
+	}
 	BatchSizeStub        func() *ab.BatchSize
 	batchSizeMutex       sync.RWMutex
 	batchSizeArgsForCall []struct{}
@@ -46,6 +64,15 @@ type OrdererConfig struct {
 	maxChannelsCountReturnsOnCall map[int]struct {
 		result1 uint64
 	}
+	BlockSchedulingStub        func() *ab.BlockScheduling
+	blockSchedulingMutex       sync.RWMutex
+	blockSchedulingArgsForCall []struct{}
+	blockSchedulingReturns     struct {
+		result1 *ab.BlockScheduling
+	}
+	blockSchedulingReturnsOnCall map[int]struct {
+		result1 *ab.BlockScheduling
+	}
 	KafkaBrokersStub        func() []string
 	kafkaBrokersMutex       sync.RWMutex
 	kafkaBrokersArgsForCall []struct{}
@@ -117,6 +144,86 @@ func (fake *OrdererConfig) ConsensusTypeReturnsOnCall(i int, result1 string) {
 	}{result1}
 }
 
+func (fake *OrdererConfig) ConsensusMetadata() []byte {
+	fake.consensusMetadataMutex.Lock()
+	ret, specificReturn := fake.consensusMetadataReturnsOnCall[len(fake.consensusMetadataArgsForCall)]
+	fake.consensusMetadataArgsForCall = append(fake.consensusMetadataArgsForCall, struct{}{})
+	fake.recordInvocation("ConsensusMetadata", []interface{}{})
+	fake.consensusMetadataMutex.Unlock()
+	if fake.ConsensusMetadataStub != nil {
+		return fake.ConsensusMetadataStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.consensusMetadataReturns.result1
+}
+
+func (fake *OrdererConfig) ConsensusMetadataCallCount() int {
+	fake.consensusMetadataMutex.RLock()
+	defer fake.consensusMetadataMutex.RUnlock()
+	return len(fake.consensusMetadataArgsForCall)
+}
+
+func (fake *OrdererConfig) ConsensusMetadataReturns(result1 []byte) {
+	fake.ConsensusMetadataStub = nil
+	fake.consensusMetadataReturns = struct {
+		result1 []byte
+	}{result1}
+}
+
+func (fake *OrdererConfig) ConsensusMetadataReturnsOnCall(i int, result1 []byte) {
+	fake.ConsensusMetadataStub = nil
+	if fake.consensusMetadataReturnsOnCall == nil {
+		fake.consensusMetadataReturnsOnCall = make(map[int]struct {
+			result1 []byte
+		})
+	}
+	fake.consensusMetadataReturnsOnCall[i] = struct {
+		result1 []byte
+	}{result1}
+}
+
+func (fake *OrdererConfig) ConsensusState() ab.ConsensusType_State {
+	fake.consensusStateMutex.Lock()
+	ret, specificReturn := fake.consensusStateReturnsOnCall[len(fake.consensusStateArgsForCall)]
+	fake.consensusStateArgsForCall = append(fake.consensusStateArgsForCall, struct{}{})
+	fake.recordInvocation("ConsensusState", []interface{}{})
+	fake.consensusStateMutex.Unlock()
+	if fake.ConsensusStateStub != nil {
+		return fake.ConsensusStateStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.consensusStateReturns.result1
+}
+
+func (fake *OrdererConfig) ConsensusStateCallCount() int {
+	fake.consensusStateMutex.RLock()
+	defer fake.consensusStateMutex.RUnlock()
+	return len(fake.consensusStateArgsForCall)
+}
+
+func (fake *OrdererConfig) ConsensusStateReturns(result1 ab.ConsensusType_State) {
+	fake.ConsensusStateStub = nil
+	fake.consensusStateReturns = struct {
+		result1 ab.ConsensusType_State
+	}{result1}
+}
+
+func (fake *OrdererConfig) ConsensusStateReturnsOnCall(i int, result1 ab.ConsensusType_State) {
+	fake.ConsensusStateStub = nil
+	if fake.consensusStateReturnsOnCall == nil {
+		fake.consensusStateReturnsOnCall = make(map[int]struct {
+			result1 ab.ConsensusType_State
+		})
+	}
+	fake.consensusStateReturnsOnCall[i] = struct {
+		result1 ab.ConsensusType_State
+	}{result1}
+}
+
 func (fake *OrdererConfig) BatchSize() *ab.BatchSize {
 	fake.batchSizeMutex.Lock()
 	ret, specificReturn := fake.batchSizeReturnsOnCall[len(fake.batchSizeArgsForCall)]
@@ -237,6 +344,46 @@ func (fake *OrdererConfig) MaxChannelsCountReturnsOnCall(i int, result1 uint64)
 	}{result1}
 }
 
+func (fake *OrdererConfig) BlockScheduling() *ab.BlockScheduling {
+	fake.blockSchedulingMutex.Lock()
+	ret, specificReturn := fake.blockSchedulingReturnsOnCall[len(fake.blockSchedulingArgsForCall)]
+	fake.blockSchedulingArgsForCall = append(fake.blockSchedulingArgsForCall, struct{}{})
+	fake.recordInvocation("BlockScheduling", []interface{}{})
+	fake.blockSchedulingMutex.Unlock()
+	if fake.BlockSchedulingStub != nil {
+		return fake.BlockSchedulingStub()
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	return fake.blockSchedulingReturns.result1
+}
+
+func (fake *OrdererConfig) BlockSchedulingCallCount() int {
+	fake.blockSchedulingMutex.RLock()
+	defer fake.blockSchedulingMutex.RUnlock()
+	return len(fake.blockSchedulingArgsForCall)
+}
+
+func (fake *OrdererConfig) BlockSchedulingReturns(result1 *ab.BlockScheduling) {
+	fake.BlockSchedulingStub = nil
+	fake.blockSchedulingReturns = struct {
+		result1 *ab.BlockScheduling
+	}{result1}
+}
+
+func (fake *OrdererConfig) BlockSchedulingReturnsOnCall(i int, result1 *ab.BlockScheduling) {
+	fake.BlockSchedulingStub = nil
+	if fake.blockSchedulingReturnsOnCall == nil {
+		fake.blockSchedulingReturnsOnCall = make(map[int]struct {
+			result1 *ab.BlockScheduling
+		})
+	}
+	fake.blockSchedulingReturnsOnCall[i] = struct {
+		result1 *ab.BlockScheduling
+	}{result1}
+}
+
 func (fake *OrdererConfig) KafkaBrokers() []string {
 	fake.kafkaBrokersMutex.Lock()
 	ret, specificReturn := fake.kafkaBrokersReturnsOnCall[len(fake.kafkaBrokersArgsForCall)]
@@ -362,12 +509,18 @@ func (fake *OrdererConfig) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.consensusTypeMutex.RLock()
 	defer fake.consensusTypeMutex.RUnlock()
+	fake.consensusMetadataMutex.RLock()
+	defer fake.consensusMetadataMutex.RUnlock()
+	fake.consensusStateMutex.RLock()
+	defer fake.consensusStateMutex.RUnlock()
 	fake.batchSizeMutex.RLock()
 	defer fake.batchSizeMutex.RUnlock()
 	fake.batchTimeoutMutex.RLock()
 	defer fake.batchTimeoutMutex.RUnlock()
 	fake.maxChannelsCountMutex.RLock()
 	defer fake.maxChannelsCountMutex.RUnlock()
+	fake.blockSchedulingMutex.RLock()
+	defer fake.blockSchedulingMutex.RUnlock()
 	fake.kafkaBrokersMutex.RLock()
 	defer fake.kafkaBrokersMutex.RUnlock()
 	fake.organizationsMutex.RLock()
@@ -0,0 +1,322 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockcutter
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/policies"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+)
+
+// SchedulingResources provides the config a fair receiver schedules the
+// messages of a channel with
+type SchedulingResources interface {
+	OrdererConfigFetcher
+	PolicyManager() policies.Manager
+}
+
+type fairReceiver struct {
+	resources SchedulingResources
+
+	// scheduling is the scheduling the pending messages were classified
+	// with, and classes holds their queues by class
+	scheduling       *ab.BlockScheduling
+	classes          []*classQueue
+	pendingCount     uint32
+	pendingSizeBytes uint32
+}
+
+// NewFairReceiver creates a Receiver which schedules the messages according to
+// the BlockScheduling of the channel config.
+//
+// The pending messages are queued by class, and by creator within a class.
+// Once they fill a batch, as for the Receiver returned by NewReceiverImpl, a
+// batch is cut by taking the messages of each class before those of the next
+// one, and the messages of the creators of a class in turns of as many
+// messages as their weights. The messages which do not fit in the batch stay
+// queued for the next one, and the turns carry over to it. A message of an
+// expedited class cuts all the pending messages.
+//
+// The batches only depend on the messages ordered since the receiver last had
+// no pending message, so the receiver is a BacklogReceiver.
+func NewFairReceiver(resources SchedulingResources) Receiver {
+	return &fairReceiver{resources: resources}
+}
+
+// Ordered should be invoked sequentially as messages are ordered, see the
+// Ordered method of the Receiver returned by NewReceiverImpl. Unlike it, more
+// than two batches may be cut, and the pending messages may have been ordered
+// before messages of the batches.
+func (r *fairReceiver) Ordered(msg *cb.Envelope) (messageBatches [][]*cb.Envelope, pending bool) {
+	ordererConfig, ok := r.resources.OrdererConfig()
+	if !ok {
+		logger.Panicf("Could not retrieve orderer config to query batch parameters, block cutting is not possible")
+	}
+	batchSize := ordererConfig.BatchSize()
+
+	messageSizeBytes := messageSizeBytes(msg)
+	if messageSizeBytes > batchSize.PreferredMaxBytes {
+		logger.Debugf("The current message, with %v bytes, is larger than the preferred batch size of %v bytes and will be isolated.", messageSizeBytes, batchSize.PreferredMaxBytes)
+		if r.pendingCount > 0 {
+			messageBatches = append(messageBatches, r.Cut())
+		}
+		return append(messageBatches, []*cb.Envelope{msg}), false
+	}
+
+	// the pending messages are all classified according to the same
+	// scheduling
+	if r.pendingCount == 0 {
+		r.scheduling = r.blockScheduling()
+		r.classes = make([]*classQueue, len(r.scheduling.Classes))
+	}
+	sm := &scheduledMessage{envelope: msg, class: len(r.scheduling.Classes) - 1}
+	if !unscheduled(r.scheduling) {
+		sm = r.classify(msg, r.scheduling)
+	}
+	r.enqueue(sm, messageSizeBytes)
+
+	expedite := r.scheduling.Classes[sm.class].Expedite
+	if expedite {
+		logger.Debugf("Message of an expedited class, cutting all pending messages")
+	}
+	for r.pendingCount > 0 && (expedite || r.pendingCount >= batchSize.MaxMessageCount || r.pendingSizeBytes > batchSize.PreferredMaxBytes) {
+		messageBatches = append(messageBatches, r.cutBatch(batchSize))
+	}
+	return messageBatches, r.pendingCount > 0
+}
+
+// Cut returns all the pending messages, in the order in which they are
+// scheduled, and forgets the turns of the creators
+func (r *fairReceiver) Cut() []*cb.Envelope {
+	var batch []*cb.Envelope
+	for r.pendingCount > 0 {
+		batch = append(batch, r.dequeue().envelope)
+	}
+	r.classes = nil
+	return batch
+}
+
+// backlog marks the fairReceiver as a BacklogReceiver
+func (r *fairReceiver) backlog() {}
+
+// cutBatch returns the next scheduled messages which fit in a batch
+func (r *fairReceiver) cutBatch(batchSize *ab.BatchSize) []*cb.Envelope {
+	var batch []*cb.Envelope
+	var batchSizeBytes uint32
+	for r.pendingCount > 0 && uint32(len(batch)) < batchSize.MaxMessageCount {
+		next := messageSizeBytes(r.head().envelope)
+		if len(batch) > 0 && batchSizeBytes+next > batchSize.PreferredMaxBytes {
+			break
+		}
+		batch = append(batch, r.dequeue().envelope)
+		batchSizeBytes += next
+	}
+	if r.pendingCount == 0 {
+		r.classes = nil
+	}
+	return batch
+}
+
+// enqueue appends the message to the queue of its creator in its class
+func (r *fairReceiver) enqueue(sm *scheduledMessage, sizeBytes uint32) {
+	if r.classes[sm.class] == nil {
+		r.classes[sm.class] = &classQueue{creators: make(map[string]*creatorQueue)}
+	}
+	weight := r.scheduling.OrganizationWeights[sm.mspID]
+	if weight == 0 {
+		weight = 1
+	}
+	r.classes[sm.class].push(sm, weight)
+	r.pendingCount++
+	r.pendingSizeBytes += sizeBytes
+}
+
+// head returns the next scheduled message, which there must be
+func (r *fairReceiver) head() *scheduledMessage {
+	for _, class := range r.classes {
+		if class != nil && class.pendingCount > 0 {
+			return class.head()
+		}
+	}
+	logger.Panicf("No pending message to schedule")
+	return nil
+}
+
+// dequeue removes the next scheduled message and returns it
+func (r *fairReceiver) dequeue() *scheduledMessage {
+	for _, class := range r.classes {
+		if class != nil && class.pendingCount > 0 {
+			sm := class.pop()
+			r.pendingCount--
+			r.pendingSizeBytes -= messageSizeBytes(sm.envelope)
+			return sm
+		}
+	}
+	logger.Panicf("No pending message to schedule")
+	return nil
+}
+
+// classQueue holds the pending messages of a class by creator. The creators
+// take turns in the order in which they first sent a message, each turn
+// taking as many messages of the creator as its weight. A creator keeps its
+// place in the turns when it has no pending message.
+type classQueue struct {
+	order        []*creatorQueue
+	creators     map[string]*creatorQueue
+	turn         int
+	taken        uint32
+	pendingCount int
+}
+
+type creatorQueue struct {
+	weight   uint32
+	messages []*scheduledMessage
+}
+
+func (c *classQueue) push(sm *scheduledMessage, weight uint32) {
+	q, ok := c.creators[sm.creator]
+	if !ok {
+		q = &creatorQueue{weight: weight}
+		c.creators[sm.creator] = q
+		c.order = append(c.order, q)
+	}
+	q.messages = append(q.messages, sm)
+	c.pendingCount++
+}
+
+// head returns the first message of the creator whose turn it is, or of the
+// next creator with a pending message
+func (c *classQueue) head() *scheduledMessage {
+	for i := range c.order {
+		q := c.order[(c.turn+i)%len(c.order)]
+		if len(q.messages) > 0 {
+			return q.messages[0]
+		}
+	}
+	return nil
+}
+
+// pop removes the message returned by head, passing the turn of the creators
+// with no pending message
+func (c *classQueue) pop() *scheduledMessage {
+	for len(c.order[c.turn].messages) == 0 {
+		c.next()
+	}
+	q := c.order[c.turn]
+	sm := q.messages[0]
+	q.messages = q.messages[1:]
+	c.pendingCount--
+	c.taken++
+	if c.taken >= q.weight {
+		c.next()
+	}
+	return sm
+}
+
+// next passes the turn to the following creator
+func (c *classQueue) next() {
+	c.turn = (c.turn + 1) % len(c.order)
+	c.taken = 0
+}
+
+// blockScheduling returns the BlockScheduling of the channel config, with an
+// implicit last class for the messages assigned to no class
+func (r *fairReceiver) blockScheduling() *ab.BlockScheduling {
+	ordererConfig, ok := r.resources.OrdererConfig()
+	if !ok {
+		logger.Panicf("Could not retrieve orderer config to query block scheduling, block cutting is not possible")
+	}
+	scheduling := ordererConfig.BlockScheduling()
+	return &ab.BlockScheduling{
+		Classes:             append(append([]*ab.BlockScheduling_Class{}, scheduling.GetClasses()...), &ab.BlockScheduling_Class{}),
+		OrganizationWeights: scheduling.GetOrganizationWeights(),
+	}
+}
+
+// unscheduled returns whether the scheduling leaves the messages in the order
+// they were ordered in, as there is only the implicit class and no weight
+func unscheduled(scheduling *ab.BlockScheduling) bool {
+	return len(scheduling.Classes) == 1 && len(scheduling.OrganizationWeights) == 0
+}
+
+type scheduledMessage struct {
+	envelope *cb.Envelope
+	class    int
+	creator  string
+	mspID    string
+}
+
+// classify assigns the message to the class it requests if its creator
+// satisfies the policy of the class, otherwise to the class of its header
+// type, otherwise to the implicit last class
+func (r *fairReceiver) classify(msg *cb.Envelope, scheduling *ab.BlockScheduling) *scheduledMessage {
+	defaultClass := len(scheduling.Classes) - 1
+	sm := &scheduledMessage{envelope: msg, class: defaultClass}
+
+	payload, err := utils.UnmarshalPayload(msg.Payload)
+	if err != nil || payload.Header == nil {
+		return sm
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return sm
+	}
+	shdr, err := utils.GetSignatureHeader(payload.Header.SignatureHeader)
+	if err != nil {
+		return sm
+	}
+	sm.creator = string(shdr.Creator)
+	sid := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(shdr.Creator, sid); err == nil {
+		sm.mspID = sid.Mspid
+	}
+
+	if chdr.PriorityClass != "" {
+		for i, class := range scheduling.Classes[:defaultClass] {
+			if class.Name != chdr.PriorityClass {
+				continue
+			}
+			if r.mayRequest(msg, class) {
+				sm.class = i
+				return sm
+			}
+			logger.Debugf("Creator of message %s may not request class %s", chdr.TxId, class.Name)
+			break
+		}
+	}
+
+	for i, class := range scheduling.Classes[:defaultClass] {
+		for _, headerType := range class.HeaderTypes {
+			if headerType == chdr.Type {
+				sm.class = i
+				return sm
+			}
+		}
+	}
+	return sm
+}
+
+// mayRequest returns whether the signer of the message satisfies the policy
+// required to request the class
+func (r *fairReceiver) mayRequest(msg *cb.Envelope, class *ab.BlockScheduling_Class) bool {
+	if class.Policy == "" {
+		return false
+	}
+	policy, ok := r.resources.PolicyManager().GetPolicy(class.Policy)
+	if !ok {
+		logger.Warningf("Could not find policy %s of block scheduling class %s", class.Policy, class.Name)
+		return false
+	}
+	signedData, err := msg.AsSignedData()
+	if err != nil {
+		return false
+	}
+	return policy.Evaluate(signedData) == nil
+}
@@ -0,0 +1,349 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package blockcutter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	mockpolicies "github.com/hyperledger/fabric/common/mocks/policies"
+	"github.com/hyperledger/fabric/common/policies"
+	"github.com/hyperledger/fabric/orderer/common/blockcutter/mock"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type schedulingResources struct {
+	*mock.OrdererConfigFetcher
+	policyManager policies.Manager
+}
+
+func (sr *schedulingResources) PolicyManager() policies.Manager {
+	return sr.policyManager
+}
+
+// mspPolicy is satisfied by the signers of an MSP
+type mspPolicy string
+
+func (p mspPolicy) Evaluate(signatureSet []*cb.SignedData) error {
+	sid := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(signatureSet[0].Identity, sid); err != nil {
+		return err
+	}
+	if sid.Mspid != string(p) {
+		return errors.Errorf("signer is not a member of %s", p)
+	}
+	return nil
+}
+
+// newFairReceiver returns a fair receiver for the block scheduling, which
+// cuts batches of maxMessageCount messages
+func newFairReceiver(scheduling *ab.BlockScheduling, maxMessageCount uint32) Receiver {
+	mockConfig := &mock.OrdererConfig{}
+	mockConfig.BatchSizeReturns(&ab.BatchSize{
+		MaxMessageCount:   maxMessageCount,
+		AbsoluteMaxBytes:  100000,
+		PreferredMaxBytes: 10000,
+	})
+	mockConfig.BlockSchedulingReturns(scheduling)
+	mockConfigFetcher := &mock.OrdererConfigFetcher{}
+	mockConfigFetcher.OrdererConfigReturns(mockConfig, true)
+
+	return NewFairReceiver(&schedulingResources{
+		OrdererConfigFetcher: mockConfigFetcher,
+		policyManager: &mockpolicies.Manager{
+			PolicyMap: map[string]policies.Policy{"/Channel/Orderer/Priority": mspPolicy("Org1MSP")},
+		},
+	})
+}
+
+// schedulingTx returns a message of the header type, created by the client
+// of the MSP, requesting the priority class
+func schedulingTx(headerType cb.HeaderType, mspID, client, class string) *cb.Envelope {
+	return &cb.Envelope{
+		Payload: utils.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{
+					Type:          int32(headerType),
+					ChannelId:     "testchannel",
+					TxId:          fmt.Sprintf("%s-%s", client, class),
+					PriorityClass: class,
+				}),
+				SignatureHeader: utils.MarshalOrPanic(&cb.SignatureHeader{
+					Creator: utils.MarshalOrPanic(&msp.SerializedIdentity{Mspid: mspID, IdBytes: []byte(client)}),
+				}),
+			},
+		}),
+		Signature: []byte(client),
+	}
+}
+
+func TestFairReceiverWithoutScheduling(t *testing.T) {
+	r := newFairReceiver(nil, 3)
+	messages := []*cb.Envelope{
+		schedulingTx(cb.HeaderType_ENDORSER_TRANSACTION, "Org1MSP", "alice", ""),
+		schedulingTx(cb.HeaderType_ENDORSER_TRANSACTION, "Org1MSP", "alice", "priority"),
+		schedulingTx(cb.HeaderType_MESSAGE, "Org2MSP", "bob", ""),
+	}
+
+	batches, pending := r.Ordered(messages[0])
+	assert.Empty(t, batches)
+	assert.True(t, pending)
+	batches, pending = r.Ordered(messages[1])
+	assert.Empty(t, batches)
+	assert.True(t, pending)
+	batches, pending = r.Ordered(messages[2])
+	assert.Equal(t, [][]*cb.Envelope{messages}, batches, "Should have kept the order of the messages")
+	assert.False(t, pending)
+}
+
+func TestFairReceiverClasses(t *testing.T) {
+	r := newFairReceiver(&ab.BlockScheduling{
+		Classes: []*ab.BlockScheduling_Class{
+			{Name: "priority", Policy: "/Channel/Orderer/Priority"},
+			{Name: "admin", HeaderTypes: []int32{int32(cb.HeaderType_MESSAGE)}},
+		},
+	}, 10)
+
+	bulk := schedulingTx(cb.HeaderType_ENDORSER_TRANSACTION, "Org2MSP", "bob", "")
+	admin := schedulingTx(cb.HeaderType_MESSAGE, "Org2MSP", "bob", "")
+	priority := schedulingTx(cb.HeaderType_ENDORSER_TRANSACTION, "Org1MSP", "alice", "priority")
+	unauthorized := schedulingTx(cb.HeaderType_ENDORSER_TRANSACTION, "Org2MSP", "bob", "priority")
+	unknown := schedulingTx(cb.HeaderType_ENDORSER_TRANSACTION, "Org1MSP", "alice", "unknown")
+	unparseable := &cb.Envelope{Payload: []byte("GOOD")}
+
+	for _, msg := range []*cb.Envelope{bulk, unparseable, unauthorized, admin, unknown, priority} {
+		batches, pending := r.Ordered(msg)
+		assert.Empty(t, batches)
+		assert.True(t, pending)
+	}
+	// the messages assigned to no class are interleaved by creator
+	assert.Equal(t, []*cb.Envelope{priority, admin, bulk, unparseable, unknown, unauthorized}, r.Cut())
+	assert.Empty(t, r.Cut())
+}
+
+func TestFairReceiverWeights(t *testing.T) {
+	r := newFairReceiver(&ab.BlockScheduling{
+		OrganizationWeights: map[string]uint32{"Org1MSP": 2},
+	}, 8)
+
+	alice := schedulingTx(cb.HeaderType_ENDORSER_TRANSACTION, "Org1MSP", "alice", "")
+	bob := schedulingTx(cb.HeaderType_ENDORSER_TRANSACTION, "Org2MSP", "bob", "")
+	carol := schedulingTx(cb.HeaderType_ENDORSER_TRANSACTION, "Org2MSP", "carol", "")
+
+	var batches [][]*cb.Envelope
+	for _, msg := range []*cb.Envelope{bob, bob, bob, alice, alice, alice, alice, carol} {
+		batches, _ = r.Ordered(msg)
+	}
+	assert.Equal(t, [][]*cb.Envelope{{bob, alice, alice, carol, bob, alice, alice, bob}}, batches)
+}
+
+func TestFairReceiverExpedite(t *testing.T) {
+	scheduling := &ab.BlockScheduling{
+		Classes: []*ab.BlockScheduling_Class{
+			{Name: "urgent", Policy: "/Channel/Orderer/Priority", Expedite: true},
+		},
+	}
+	bulk := schedulingTx(cb.HeaderType_ENDORSER_TRANSACTION, "Org2MSP", "bob", "")
+	urgent := schedulingTx(cb.HeaderType_ENDORSER_TRANSACTION, "Org1MSP", "alice", "urgent")
+
+	t.Run("Pending", func(t *testing.T) {
+		r := newFairReceiver(scheduling, 10)
+		r.Ordered(bulk)
+		batches, pending := r.Ordered(urgent)
+		assert.Equal(t, [][]*cb.Envelope{{urgent, bulk}}, batches)
+		assert.False(t, pending)
+	})
+
+	t.Run("Alone", func(t *testing.T) {
+		r := newFairReceiver(scheduling, 10)
+		batches, pending := r.Ordered(urgent)
+		assert.Equal(t, [][]*cb.Envelope{{urgent}}, batches)
+		assert.False(t, pending)
+	})
+
+	t.Run("Overflow", func(t *testing.T) {
+		r := newFairReceiver(scheduling, 10)
+		bigBulk := schedulingTx(cb.HeaderType_ENDORSER_TRANSACTION, "Org2MSP", "bob", "")
+		bigBulk.Signature = make([]byte, 9900)
+		_, pending := r.Ordered(bigBulk)
+		assert.True(t, pending)
+		batches, pending := r.Ordered(urgent)
+		assert.Equal(t, [][]*cb.Envelope{{urgent}, {bigBulk}}, batches, "Should have cut the expedited message, then the message it did not fit with")
+		assert.False(t, pending)
+	})
+}
+
+// countingPolicy counts its evaluations
+type countingPolicy struct {
+	policies.Policy
+	evaluations int
+}
+
+func (p *countingPolicy) Evaluate(signatureSet []*cb.SignedData) error {
+	p.evaluations++
+	return p.Policy.Evaluate(signatureSet)
+}
+
+func TestFairReceiverClassifiesOnce(t *testing.T) {
+	mockConfig := &mock.OrdererConfig{}
+	mockConfig.BatchSizeReturns(&ab.BatchSize{MaxMessageCount: 10, AbsoluteMaxBytes: 100000, PreferredMaxBytes: 10000})
+	mockConfig.BlockSchedulingReturns(&ab.BlockScheduling{
+		Classes: []*ab.BlockScheduling_Class{
+			{Name: "urgent", Policy: "/Channel/Orderer/Priority", Expedite: true},
+			{Name: "priority", Policy: "/Channel/Orderer/Priority"},
+		},
+	})
+	mockConfigFetcher := &mock.OrdererConfigFetcher{}
+	mockConfigFetcher.OrdererConfigReturns(mockConfig, true)
+	policy := &countingPolicy{Policy: mspPolicy("Org1MSP")}
+	r := NewFairReceiver(&schedulingResources{
+		OrdererConfigFetcher: mockConfigFetcher,
+		policyManager: &mockpolicies.Manager{
+			PolicyMap: map[string]policies.Policy{"/Channel/Orderer/Priority": policy},
+		},
+	})
+
+	bulk := schedulingTx(cb.HeaderType_ENDORSER_TRANSACTION, "Org2MSP", "bob", "")
+	priority := schedulingTx(cb.HeaderType_ENDORSER_TRANSACTION, "Org1MSP", "alice", "priority")
+	urgent := schedulingTx(cb.HeaderType_ENDORSER_TRANSACTION, "Org1MSP", "alice", "urgent")
+
+	r.Ordered(bulk)
+	r.Ordered(priority)
+	batches, pending := r.Ordered(urgent)
+	assert.Equal(t, [][]*cb.Envelope{{urgent, priority, bulk}}, batches)
+	assert.False(t, pending)
+	assert.Equal(t, 2, policy.evaluations, "Should have classified each message requesting a class once")
+
+	r.Ordered(priority)
+	assert.Equal(t, []*cb.Envelope{priority}, r.Cut())
+	assert.Equal(t, 3, policy.evaluations, "Should not have classified the message again when cutting")
+}
+
+// sizedTx returns a message of the client of the MSP of about sizeBytes
+func sizedTx(mspID, client string, sizeBytes int) *cb.Envelope {
+	env := schedulingTx(cb.HeaderType_ENDORSER_TRANSACTION, mspID, client, "")
+	env.Signature = make([]byte, sizeBytes)
+	return env
+}
+
+func TestFairReceiverBacklog(t *testing.T) {
+	r := newFairReceiver(&ab.BlockScheduling{
+		Classes: []*ab.BlockScheduling_Class{
+			{Name: "priority", Policy: "/Channel/Orderer/Priority"},
+		},
+	}, 10)
+
+	bulk := []*cb.Envelope{sizedTx("Org2MSP", "bob", 3000), sizedTx("Org2MSP", "bob", 3000), sizedTx("Org2MSP", "bob", 3000)}
+	priority := schedulingTx(cb.HeaderType_ENDORSER_TRANSACTION, "Org1MSP", "alice", "priority")
+	priority.Signature = make([]byte, 3000)
+
+	for _, msg := range bulk {
+		batches, pending := r.Ordered(msg)
+		assert.Empty(t, batches)
+		assert.True(t, pending)
+	}
+	batches, pending := r.Ordered(priority)
+	assert.Equal(t, [][]*cb.Envelope{{priority, bulk[0], bulk[1]}}, batches, "Should have cut the priority message first")
+	assert.True(t, pending, "The message which did not fit should be pending")
+	assert.Equal(t, []*cb.Envelope{bulk[2]}, r.Cut())
+	assert.Empty(t, r.Cut())
+}
+
+func TestFairReceiverTurnsCarryOver(t *testing.T) {
+	r := newFairReceiver(&ab.BlockScheduling{
+		OrganizationWeights: map[string]uint32{"Org1MSP": 1},
+	}, 10)
+
+	bob := []*cb.Envelope{sizedTx("Org2MSP", "bob", 3000), sizedTx("Org2MSP", "bob", 3000), sizedTx("Org2MSP", "bob", 3000)}
+	carol := []*cb.Envelope{sizedTx("Org2MSP", "carol", 3000), sizedTx("Org2MSP", "carol", 3000)}
+
+	for _, msg := range bob {
+		r.Ordered(msg)
+	}
+	batches, pending := r.Ordered(carol[0])
+	assert.Equal(t, [][]*cb.Envelope{{bob[0], carol[0], bob[1]}}, batches)
+	assert.True(t, pending)
+
+	// bob took the last turn, so carol goes first
+	batches, pending = r.Ordered(carol[1])
+	assert.Empty(t, batches)
+	assert.True(t, pending)
+	assert.Equal(t, []*cb.Envelope{carol[1], bob[2]}, r.Cut())
+}
+
+// The batches only depend on the messages ordered since the receiver last had
+// no pending message, so that Kafka cuts the same blocks when it replays the
+// messages of a partition from the offset of such a message.
+func TestFairReceiverDeterministic(t *testing.T) {
+	scheduling := &ab.BlockScheduling{
+		Classes: []*ab.BlockScheduling_Class{
+			{Name: "urgent", Policy: "/Channel/Orderer/Priority", Expedite: true},
+			{Name: "admin", HeaderTypes: []int32{int32(cb.HeaderType_MESSAGE)}},
+		},
+		OrganizationWeights: map[string]uint32{"Org2MSP": 3},
+	}
+
+	var messages []*cb.Envelope
+	for i := 0; i < 200; i++ {
+		var msg *cb.Envelope
+		switch {
+		case i%37 == 0:
+			msg = schedulingTx(cb.HeaderType_ENDORSER_TRANSACTION, "Org1MSP", "alice", "urgent")
+		case i%5 == 0:
+			msg = schedulingTx(cb.HeaderType_MESSAGE, "Org1MSP", fmt.Sprintf("admin%d", i%2), "")
+		default:
+			msg = schedulingTx(cb.HeaderType_ENDORSER_TRANSACTION, "Org2MSP", fmt.Sprintf("client%d", i%3), "")
+		}
+		msg.Signature = make([]byte, i*731%4000)
+		messages = append(messages, msg)
+	}
+
+	// orders the messages from the given one, returning the batches, the
+	// number of batches cut before each message was ordered, and the messages
+	// after which no message was pending
+	order := func(from int) ([][]*cb.Envelope, map[int]int, []int) {
+		r := newFairReceiver(scheduling, 7)
+		var batches [][]*cb.Envelope
+		cutBefore := make(map[int]int)
+		var empty []int
+		for i := from; i < len(messages); i++ {
+			cutBefore[i] = len(batches)
+			cut, pending := r.Ordered(messages[i])
+			batches = append(batches, cut...)
+			if !pending {
+				empty = append(empty, i)
+			}
+		}
+		if batch := r.Cut(); len(batch) > 0 {
+			batches = append(batches, batch)
+		}
+		return batches, cutBefore, empty
+	}
+
+	batches, cutBefore, empty := order(0)
+	assert.True(t, len(batches) > 10)
+	assert.True(t, len(empty) > 3)
+	var ordered []*cb.Envelope
+	for _, batch := range batches {
+		ordered = append(ordered, batch...)
+	}
+	assert.Len(t, ordered, len(messages), "Should have cut each message once")
+	for i, msg := range messages {
+		assert.Contains(t, ordered, msg, "Should have cut message %d", i)
+	}
+
+	for _, i := range empty[:len(empty)-1] {
+		replayed, _, _ := order(i + 1)
+		assert.Equal(t, batches[cutBefore[i+1]:], replayed, "Should have cut the same batches when replaying from message %d", i+1)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"io"
 
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/common/util"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
 	cb "github.com/hyperledger/fabric/protos/common"
@@ -65,13 +66,21 @@ type Consenter interface {
 }
 
 type handlerImpl struct {
-	sm ChannelSupportRegistrar
+	sm        ChannelSupportRegistrar
+	rateLimit msgprocessor.Rule
 }
 
-// NewHandlerImpl constructs a new implementation of the Handler interface
-func NewHandlerImpl(sm ChannelSupportRegistrar) Handler {
+// NewHandlerImpl constructs a new implementation of the Handler interface.
+// The rateLimit rule, if not nil, is applied once to every message which passes
+// the checks of its channel, and the messages it rejects are answered with
+// SERVICE_UNAVAILABLE without closing the stream so that the client can retry.
+func NewHandlerImpl(sm ChannelSupportRegistrar, rateLimit msgprocessor.Rule) Handler {
+	if rateLimit == nil {
+		rateLimit = msgprocessor.AcceptRule
+	}
 	return &handlerImpl{
-		sm: sm,
+		sm:        sm,
+		rateLimit: rateLimit,
 	}
 }
 
@@ -103,12 +112,14 @@ func (bh *handlerImpl) Handle(srv ab.AtomicBroadcast_BroadcastServer) error {
 				channelID = chdr.ChannelId
 			}
 			logger.Warningf("[channel: %s] Could not get message processor for serving %s: %s", channelID, addr, err)
+			countBroadcast(channelID, isConfig, cb.Status_BAD_REQUEST)
 			return srv.Send(&ab.BroadcastResponse{Status: cb.Status_BAD_REQUEST, Info: err.Error()})
 		}
 
 		// 检查共识组件连对象是否准备好接收新的交易消息
 		if err = processor.WaitReady(); err != nil {
 			logger.Warningf("[channel: %s] Rejecting broadcast of message from %s with SERVICE_UNAVAILABLE: rejected by Consenter: %s", chdr.ChannelId, addr, err)
+			countBroadcast(chdr.ChannelId, isConfig, cb.Status_SERVICE_UNAVAILABLE)
 			return srv.Send(&ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: err.Error()})
 		}
 
@@ -123,13 +134,23 @@ func (bh *handlerImpl) Handle(srv ab.AtomicBroadcast_BroadcastServer) error {
 			configSeq, err := processor.ProcessNormalMsg(msg) // 解析获取通道的最新配置序号
 			if err != nil {
 				logger.Warningf("[channel: %s] Rejecting broadcast of normal message from %s because of error: %s", chdr.ChannelId, addr, err)
-				return srv.Send(&ab.BroadcastResponse{Status: ClassifyError(err), Info: err.Error()})
+				status := ClassifyError(err)
+				countBroadcast(chdr.ChannelId, isConfig, status)
+				return srv.Send(&ab.BroadcastResponse{Status: status, Info: err.Error()})
+			}
+
+			if err = bh.rateLimit.Apply(msg); err != nil {
+				if err = bh.sendRateLimited(srv, chdr, isConfig, addr, err); err != nil {
+					return err
+				}
+				continue
 			}
 
 			// 构造新的普通交易消息并发送到共识组件链对象请求处理
 			err = processor.Order(msg, configSeq)
 			if err != nil {
 				logger.Warningf("[channel: %s] Rejecting broadcast of normal message from %s with SERVICE_UNAVAILABLE: rejected by Order: %s", chdr.ChannelId, addr, err)
+				countBroadcast(chdr.ChannelId, isConfig, cb.Status_SERVICE_UNAVAILABLE)
 				return srv.Send(&ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: err.Error()})
 			}
 		} else { // isConfig
@@ -139,13 +160,23 @@ func (bh *handlerImpl) Handle(srv ab.AtomicBroadcast_BroadcastServer) error {
 			config, configSeq, err := processor.ProcessConfigUpdateMsg(msg)
 			if err != nil {
 				logger.Warningf("[channel: %s] Rejecting broadcast of config message from %s because of error: %s", chdr.ChannelId, addr, err)
-				return srv.Send(&ab.BroadcastResponse{Status: ClassifyError(err), Info: err.Error()})
+				status := ClassifyError(err)
+				countBroadcast(chdr.ChannelId, isConfig, status)
+				return srv.Send(&ab.BroadcastResponse{Status: status, Info: err.Error()})
+			}
+
+			if err = bh.rateLimit.Apply(msg); err != nil {
+				if err = bh.sendRateLimited(srv, chdr, isConfig, addr, err); err != nil {
+					return err
+				}
+				continue
 			}
 
 			// 构造新的配置交易消息并发送到共识组件链对象请求处理
 			err = processor.Configure(config, configSeq)
 			if err != nil {
 				logger.Warningf("[channel: %s] Rejecting broadcast of config message from %s with SERVICE_UNAVAILABLE: rejected by Configure: %s", chdr.ChannelId, addr, err)
+				countBroadcast(chdr.ChannelId, isConfig, cb.Status_SERVICE_UNAVAILABLE)
 				return srv.Send(&ab.BroadcastResponse{Status: cb.Status_SERVICE_UNAVAILABLE, Info: err.Error()})
 			}
 		}
@@ -153,6 +184,7 @@ func (bh *handlerImpl) Handle(srv ab.AtomicBroadcast_BroadcastServer) error {
 		logger.Debugf("[channel: %s] Broadcast has successfully enqueued message of type %s from %s", chdr.ChannelId, cb.HeaderType_name[chdr.Type], addr)
 
 		// 发送成功处理状态响应消息
+		countBroadcast(chdr.ChannelId, isConfig, cb.Status_SUCCESS)
 		err = srv.Send(&ab.BroadcastResponse{Status: cb.Status_SUCCESS})
 		if err != nil {
 			logger.Warningf("[channel: %s] Error sending to %s: %s", chdr.ChannelId, addr, err)
@@ -161,6 +193,41 @@ func (bh *handlerImpl) Handle(srv ab.AtomicBroadcast_BroadcastServer) error {
 	}
 }
 
+// sendRateLimited answers a message rejected by the rate limit rule
+func (bh *handlerImpl) sendRateLimited(srv ab.AtomicBroadcast_BroadcastServer, chdr *cb.ChannelHeader, isConfig bool, addr string, err error) error {
+	logger.Warningf("[channel: %s] Rejecting broadcast of message from %s with SERVICE_UNAVAILABLE: %s", chdr.ChannelId, addr, err)
+	status := ClassifyError(err)
+	countBroadcast(chdr.ChannelId, isConfig, status)
+
+	limit := "unknown"
+	if rateLimitErr, ok := err.(*msgprocessor.RateLimitError); ok {
+		limit = rateLimitErr.Limit
+	}
+	metrics.RootScope.SubScope("broadcast").Tagged(map[string]string{
+		"channel": chdr.ChannelId,
+		"limit":   limit,
+	}).Counter("rate_limited").Inc(1)
+
+	if err = srv.Send(&ab.BroadcastResponse{Status: status, Info: err.Error()}); err != nil {
+		logger.Warningf("[channel: %s] Error sending to %s: %s", chdr.ChannelId, addr, err)
+		return err
+	}
+	return nil
+}
+
+// countBroadcast counts the broadcast messages by channel, type and returned status
+func countBroadcast(channelID string, isConfig bool, status cb.Status) {
+	msgType := "normal"
+	if isConfig {
+		msgType = "config"
+	}
+	metrics.RootScope.SubScope("broadcast").Tagged(map[string]string{
+		"channel": channelID,
+		"type":    msgType,
+		"status":  status.String(),
+	}).Counter("processed").Inc(1)
+}
+
 // ClassifyError converts an error type into a status code.
 func ClassifyError(err error) cb.Status {
 	switch errors.Cause(err) {
@@ -168,6 +235,8 @@ func ClassifyError(err error) cb.Status {
 		return cb.Status_NOT_FOUND
 	case msgprocessor.ErrPermissionDenied:
 		return cb.Status_FORBIDDEN
+	case msgprocessor.ErrRateLimited, msgprocessor.ErrMaintenanceMode:
+		return cb.Status_SERVICE_UNAVAILABLE
 	default:
 		return cb.Status_BAD_REQUEST
 	}
@@ -154,7 +154,7 @@ func getMockSupportManager() *mockSupportManager {
 
 func TestEnqueueFailure(t *testing.T) {
 	mm := getMockSupportManager()
-	bh := NewHandlerImpl(mm)
+	bh := NewHandlerImpl(mm, nil)
 	m := newMockB()
 	defer close(m.recvChan)
 	done := make(chan struct{})
@@ -192,6 +192,12 @@ func TestClassifyError(t *testing.T) {
 	t.Run("Forbidden", func(t *testing.T) {
 		assert.Equal(t, cb.Status_FORBIDDEN, ClassifyError(msgprocessor.ErrPermissionDenied))
 	})
+	t.Run("RateLimited", func(t *testing.T) {
+		assert.Equal(t, cb.Status_SERVICE_UNAVAILABLE, ClassifyError(&msgprocessor.RateLimitError{Limit: msgprocessor.ClientRateLimit}))
+	})
+	t.Run("MaintenanceMode", func(t *testing.T) {
+		assert.Equal(t, cb.Status_SERVICE_UNAVAILABLE, ClassifyError(errors.WithMessage(msgprocessor.ErrMaintenanceMode, "only config updates are accepted")))
+	})
 	t.Run("WrappedErr", func(t *testing.T) {
 		assert.Equal(t, cb.Status_NOT_FOUND, ClassifyError(errors.Wrap(msgprocessor.ErrChannelDoesNotExist, "A wrapped error")))
 	})
@@ -203,7 +209,7 @@ func TestClassifyError(t *testing.T) {
 func TestBadChannelId(t *testing.T) {
 	mm := getMockSupportManager()
 	mm.MsgProcessorVal = &mockSupport{ProcessErr: msgprocessor.ErrChannelDoesNotExist}
-	bh := NewHandlerImpl(mm)
+	bh := NewHandlerImpl(mm, nil)
 	m := newMockB()
 	defer close(m.recvChan)
 	done := make(chan struct{})
@@ -228,7 +234,7 @@ func TestBadChannelId(t *testing.T) {
 func TestGoodConfigUpdate(t *testing.T) {
 	mm := getMockSupportManager()
 	mm.MsgProcessorIsConfig = true
-	bh := NewHandlerImpl(mm)
+	bh := NewHandlerImpl(mm, nil)
 	m := newMockB()
 	defer close(m.recvChan)
 	go bh.Handle(m)
@@ -242,7 +248,7 @@ func TestBadConfigUpdate(t *testing.T) {
 	mm := getMockSupportManager()
 	mm.MsgProcessorIsConfig = true
 	mm.MsgProcessorVal.ProcessErr = fmt.Errorf("Error")
-	bh := NewHandlerImpl(mm)
+	bh := NewHandlerImpl(mm, nil)
 	m := newMockB()
 	defer close(m.recvChan)
 	go bh.Handle(m)
@@ -253,7 +259,7 @@ func TestBadConfigUpdate(t *testing.T) {
 }
 
 func TestGracefulShutdown(t *testing.T) {
-	bh := NewHandlerImpl(nil)
+	bh := NewHandlerImpl(nil, nil)
 	m := newMockB()
 	close(m.recvChan)
 	assert.NoError(t, bh.Handle(m), "Should exit normally upon EOF")
@@ -264,7 +270,7 @@ func TestRejected(t *testing.T) {
 		MsgProcessorVal: &mockSupport{ProcessErr: fmt.Errorf("Reject")},
 		ChdrVal:         &cb.ChannelHeader{},
 	}
-	bh := NewHandlerImpl(mm)
+	bh := NewHandlerImpl(mm, nil)
 	m := newMockB()
 	defer close(m.recvChan)
 	go bh.Handle(m)
@@ -276,13 +282,13 @@ func TestRejected(t *testing.T) {
 }
 
 func TestBadStreamRecv(t *testing.T) {
-	bh := NewHandlerImpl(nil)
+	bh := NewHandlerImpl(nil, nil)
 	assert.Error(t, bh.Handle(&erroneousRecvMockB{}), "Should catch unexpected stream error")
 }
 
 func TestBadStreamSend(t *testing.T) {
 	mm := getMockSupportManager()
-	bh := NewHandlerImpl(mm)
+	bh := NewHandlerImpl(mm, nil)
 	m := &erroneousSendMockB{recvVal: nil}
 	assert.Error(t, bh.Handle(m), "Should catch unexpected stream error")
 }
@@ -291,7 +297,7 @@ func TestMalformedHeader(t *testing.T) {
 	mm := getMockSupportManager()
 	mm.ChdrVal = nil
 	mm.MsgProcessorErr = errors.New("Mocked Error")
-	bh := NewHandlerImpl(mm)
+	bh := NewHandlerImpl(mm, nil)
 	m := newMockB()
 	defer close(m.recvChan)
 	done := make(chan struct{})
@@ -312,3 +318,44 @@ func TestMalformedHeader(t *testing.T) {
 		t.Fatalf("Should have terminated the stream")
 	}
 }
+
+type mockRateLimit struct {
+	err error
+}
+
+func (r *mockRateLimit) Apply(message *cb.Envelope) error {
+	return r.err
+}
+
+func TestRateLimited(t *testing.T) {
+	for _, isConfig := range []bool{false, true} {
+		mm := getMockSupportManager()
+		mm.MsgProcessorIsConfig = isConfig
+		rateLimit := &mockRateLimit{err: &msgprocessor.RateLimitError{Limit: msgprocessor.ClientRateLimit, Key: "foo/SampleOrg/0123", RetryAfter: 250 * time.Millisecond}}
+		bh := NewHandlerImpl(mm, rateLimit)
+		m := newMockB()
+		done := make(chan struct{})
+		go func() {
+			bh.Handle(m)
+			close(done)
+		}()
+
+		m.recvChan <- nil
+		reply := <-m.sendChan
+		assert.Equal(t, cb.Status_SERVICE_UNAVAILABLE, reply.Status, "Should have rejected the message over the rate limit")
+		assert.Equal(t, "rate limit exceeded: client limit of foo/SampleOrg/0123 reached, retry after 250ms", reply.Info)
+
+		// the stream is kept open, so the client can retry
+		rateLimit.err = nil
+		m.recvChan <- nil
+		reply = <-m.sendChan
+		assert.Equal(t, cb.Status_SUCCESS, reply.Status, "Should have accepted the retried message")
+
+		close(m.recvChan)
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("Should have terminated the stream")
+		}
+	}
+}
@@ -0,0 +1,200 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package channelparticipation implements the channel participation API of
+// the orderer, which lets an operator join the orderer to channels and remove
+// it from channels without going through a system channel.
+package channelparticipation
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/gorilla/mux"
+	"github.com/hyperledger/fabric/common/flogging"
+	localconfig "github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+var logger = flogging.MustGetLogger("orderer/common/channelparticipation")
+
+const (
+	// URLBaseV1 is the prefix of the paths of the channel participation API
+	URLBaseV1 = "/participation/v1/"
+	// URLBaseV1Channels is the path of the channels resource
+	URLBaseV1Channels = URLBaseV1 + "channels"
+	// FormDataConfigBlockKey is the name of the multipart form field holding
+	// the genesis block of the channel to join
+	FormDataConfigBlockKey = "config-block"
+
+	channelIDKey = "channelID"
+)
+
+// ChannelManagement joins, removes and describes the channels of the orderer.
+// It is implemented by the multichannel.Registrar.
+type ChannelManagement interface {
+	ChannelList() types.ChannelList
+	ChannelInfo(channelID string) (types.ChannelInfo, error)
+	JoinChannel(channelID string, genesisBlock *cb.Block) (types.ChannelInfo, error)
+	RemoveChannel(channelID string) error
+}
+
+// ErrorResponse is the JSON body returned when a request fails
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// HTTPHandler serves the channel participation API:
+//
+//	GET    /participation/v1/channels              lists the channels
+//	POST   /participation/v1/channels              joins the channel of the genesis block in the 'config-block' form field
+//	GET    /participation/v1/channels/{channelID}  describes a channel
+//	DELETE /participation/v1/channels/{channelID}  removes a channel
+type HTTPHandler struct {
+	config    localconfig.ChannelParticipation
+	registrar ChannelManagement
+	router    *mux.Router
+}
+
+// NewHTTPHandler creates the HTTPHandler of the channel participation API
+func NewHTTPHandler(config localconfig.ChannelParticipation, registrar ChannelManagement) *HTTPHandler {
+	handler := &HTTPHandler{
+		config:    config,
+		registrar: registrar,
+		router:    mux.NewRouter(),
+	}
+
+	handler.router.HandleFunc(URLBaseV1Channels, handler.serveListAll).Methods(http.MethodGet)
+	handler.router.HandleFunc(URLBaseV1Channels, handler.serveJoin).Methods(http.MethodPost)
+	handler.router.HandleFunc(URLBaseV1Channels, handler.serveNotAllowed(http.MethodGet, http.MethodPost))
+
+	channelPath := URLBaseV1Channels + "/{" + channelIDKey + "}"
+	handler.router.HandleFunc(channelPath, handler.serveListOne).Methods(http.MethodGet)
+	handler.router.HandleFunc(channelPath, handler.serveRemove).Methods(http.MethodDelete)
+	handler.router.HandleFunc(channelPath, handler.serveNotAllowed(http.MethodGet, http.MethodDelete))
+
+	return handler
+}
+
+func (h *HTTPHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	h.router.ServeHTTP(resp, req)
+}
+
+// serveListAll lists the channels of the orderer
+func (h *HTTPHandler) serveListAll(resp http.ResponseWriter, req *http.Request) {
+	list := h.registrar.ChannelList()
+	if list.SystemChannel != nil {
+		list.SystemChannel.URL = channelURL(list.SystemChannel.Name)
+	}
+	for i := range list.Channels {
+		list.Channels[i].URL = channelURL(list.Channels[i].Name)
+	}
+	h.sendResponse(resp, http.StatusOK, list)
+}
+
+// serveListOne describes a channel of the orderer
+func (h *HTTPHandler) serveListOne(resp http.ResponseWriter, req *http.Request) {
+	channelID := mux.Vars(req)[channelIDKey]
+	info, err := h.registrar.ChannelInfo(channelID)
+	if err != nil {
+		h.sendError(resp, err)
+		return
+	}
+	info.URL = channelURL(channelID)
+	h.sendResponse(resp, http.StatusOK, info)
+}
+
+// serveJoin joins the orderer to the channel of the posted genesis block
+func (h *HTTPHandler) serveJoin(resp http.ResponseWriter, req *http.Request) {
+	if !strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/form-data") {
+		h.sendResponse(resp, http.StatusBadRequest, &ErrorResponse{Error: "unsupported Content-Type, expected multipart/form-data"})
+		return
+	}
+
+	req.Body = http.MaxBytesReader(resp, req.Body, int64(h.config.MaxRequestBodySize))
+	file, _, err := req.FormFile(FormDataConfigBlockKey)
+	if err != nil {
+		h.sendResponse(resp, http.StatusBadRequest, &ErrorResponse{Error: errors.Wrapf(err, "error with field '%s'", FormDataConfigBlockKey).Error()})
+		return
+	}
+	defer file.Close()
+
+	blockBytes, err := ioutil.ReadAll(file)
+	if err != nil {
+		h.sendResponse(resp, http.StatusBadRequest, &ErrorResponse{Error: errors.Wrapf(err, "error reading field '%s'", FormDataConfigBlockKey).Error()})
+		return
+	}
+	block := &cb.Block{}
+	if err := proto.Unmarshal(blockBytes, block); err != nil {
+		h.sendResponse(resp, http.StatusBadRequest, &ErrorResponse{Error: errors.Wrap(err, "error unmarshaling the config block").Error()})
+		return
+	}
+	channelID, err := utils.GetChainIDFromBlock(block)
+	if err != nil {
+		h.sendResponse(resp, http.StatusBadRequest, &ErrorResponse{Error: errors.Wrap(err, "error reading the channel ID of the config block").Error()})
+		return
+	}
+
+	info, err := h.registrar.JoinChannel(channelID, block)
+	if err != nil {
+		h.sendError(resp, errors.WithMessage(err, "cannot join channel "+channelID))
+		return
+	}
+	info.URL = channelURL(channelID)
+	resp.Header().Set("Location", info.URL)
+	logger.Infof("Joined channel %s", channelID)
+	h.sendResponse(resp, http.StatusCreated, info)
+}
+
+// serveRemove removes the orderer from a channel
+func (h *HTTPHandler) serveRemove(resp http.ResponseWriter, req *http.Request) {
+	channelID := mux.Vars(req)[channelIDKey]
+	if err := h.registrar.RemoveChannel(channelID); err != nil {
+		h.sendError(resp, errors.WithMessage(err, "cannot remove channel "+channelID))
+		return
+	}
+	logger.Infof("Removed channel %s", channelID)
+	resp.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HTTPHandler) serveNotAllowed(allowedMethods ...string) http.HandlerFunc {
+	return func(resp http.ResponseWriter, req *http.Request) {
+		resp.Header().Set("Allow", strings.Join(allowedMethods, ", "))
+		h.sendResponse(resp, http.StatusMethodNotAllowed, &ErrorResponse{Error: "invalid request method " + req.Method})
+	}
+}
+
+// sendError maps the errors of the registrar to HTTP status codes
+func (h *HTTPHandler) sendError(resp http.ResponseWriter, err error) {
+	code := http.StatusBadRequest
+	switch errors.Cause(err) {
+	case types.ErrChannelNotExist:
+		code = http.StatusNotFound
+	case types.ErrChannelAlreadyExists:
+		code = http.StatusConflict
+	case types.ErrSystemChannelExists:
+		code = http.StatusMethodNotAllowed
+	}
+	h.sendResponse(resp, code, &ErrorResponse{Error: err.Error()})
+}
+
+func (h *HTTPHandler) sendResponse(resp http.ResponseWriter, code int, payload interface{}) {
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(code)
+	if err := json.NewEncoder(resp).Encode(payload); err != nil {
+		logger.Errorf("Failed encoding response: %s", err)
+	}
+}
+
+func channelURL(channelID string) string {
+	return URLBaseV1Channels + "/" + channelID
+}
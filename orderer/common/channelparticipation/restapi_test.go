@@ -0,0 +1,260 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channelparticipation
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	localconfig "github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/hyperledger/fabric/orderer/common/types"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockChannelManagement struct {
+	list       types.ChannelList
+	infos      map[string]types.ChannelInfo
+	joinErr    error
+	removeErr  error
+	joined     *cb.Block
+	removedIDs []string
+}
+
+func (m *mockChannelManagement) ChannelList() types.ChannelList {
+	return m.list
+}
+
+func (m *mockChannelManagement) ChannelInfo(channelID string) (types.ChannelInfo, error) {
+	info, ok := m.infos[channelID]
+	if !ok {
+		return types.ChannelInfo{}, types.ErrChannelNotExist
+	}
+	return info, nil
+}
+
+func (m *mockChannelManagement) JoinChannel(channelID string, genesisBlock *cb.Block) (types.ChannelInfo, error) {
+	if m.joinErr != nil {
+		return types.ChannelInfo{}, m.joinErr
+	}
+	m.joined = genesisBlock
+	return types.ChannelInfo{Name: channelID, ConsensusType: "etcdraft", Status: types.StatusActive, Height: 1}, nil
+}
+
+func (m *mockChannelManagement) RemoveChannel(channelID string) error {
+	if m.removeErr != nil {
+		return m.removeErr
+	}
+	m.removedIDs = append(m.removedIDs, channelID)
+	return nil
+}
+
+var testConfig = localconfig.ChannelParticipation{Enabled: true, MaxRequestBodySize: 1024 * 1024}
+
+func genesisBlock(channelID string) *cb.Block {
+	env := &cb.Envelope{
+		Payload: utils.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{
+					Type:      int32(cb.HeaderType_CONFIG),
+					ChannelId: channelID,
+				}),
+			},
+		}),
+	}
+	block := cb.NewBlock(0, nil)
+	block.Data.Data = [][]byte{utils.MarshalOrPanic(env)}
+	return block
+}
+
+func joinRequest(t *testing.T, field string, content []byte) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile(field, "genesis.block")
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req := httptest.NewRequest(http.MethodPost, URLBaseV1Channels, body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func decodeError(t *testing.T, resp *httptest.ResponseRecorder) string {
+	errResp := &ErrorResponse{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), errResp))
+	return errResp.Error
+}
+
+func TestListAll(t *testing.T) {
+	registrar := &mockChannelManagement{
+		list: types.ChannelList{
+			Channels: []types.ChannelInfoShort{{Name: "foo"}, {Name: "bar"}},
+		},
+	}
+	handler := NewHTTPHandler(testConfig, registrar)
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, URLBaseV1Channels, nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+
+	list := types.ChannelList{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+	assert.Equal(t, types.ChannelList{
+		Channels: []types.ChannelInfoShort{
+			{Name: "foo", URL: "/participation/v1/channels/foo"},
+			{Name: "bar", URL: "/participation/v1/channels/bar"},
+		},
+	}, list)
+
+	registrar.list = types.ChannelList{SystemChannel: &types.ChannelInfoShort{Name: "system"}}
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, URLBaseV1Channels, nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	list = types.ChannelList{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &list))
+	assert.Equal(t, &types.ChannelInfoShort{Name: "system", URL: "/participation/v1/channels/system"}, list.SystemChannel)
+}
+
+func TestListOne(t *testing.T) {
+	registrar := &mockChannelManagement{
+		infos: map[string]types.ChannelInfo{
+			"foo": {Name: "foo", ConsensusType: "etcdraft", Status: types.StatusActive, Height: 7},
+		},
+	}
+	handler := NewHTTPHandler(testConfig, registrar)
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, URLBaseV1Channels+"/foo", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	info := types.ChannelInfo{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &info))
+	assert.Equal(t, types.ChannelInfo{Name: "foo", URL: "/participation/v1/channels/foo", ConsensusType: "etcdraft", Status: types.StatusActive, Height: 7}, info)
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, URLBaseV1Channels+"/bar", nil))
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+	assert.Equal(t, "channel does not exist", decodeError(t, resp))
+}
+
+func TestJoin(t *testing.T) {
+	registrar := &mockChannelManagement{}
+	handler := NewHTTPHandler(testConfig, registrar)
+
+	block := genesisBlock("foo")
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, joinRequest(t, FormDataConfigBlockKey, utils.MarshalOrPanic(block)))
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Equal(t, "/participation/v1/channels/foo", resp.Header().Get("Location"))
+	info := types.ChannelInfo{}
+	require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &info))
+	assert.Equal(t, types.ChannelInfo{Name: "foo", URL: "/participation/v1/channels/foo", ConsensusType: "etcdraft", Status: types.StatusActive, Height: 1}, info)
+	assert.Equal(t, block.Data.Data, registrar.joined.Data.Data)
+
+	t.Run("RegistrarErrors", func(t *testing.T) {
+		for _, testCase := range []struct {
+			err  error
+			code int
+		}{
+			{types.ErrChannelAlreadyExists, http.StatusConflict},
+			{types.ErrSystemChannelExists, http.StatusMethodNotAllowed},
+			{errors.New("bad genesis block"), http.StatusBadRequest},
+		} {
+			handler := NewHTTPHandler(testConfig, &mockChannelManagement{joinErr: testCase.err})
+			resp := httptest.NewRecorder()
+			handler.ServeHTTP(resp, joinRequest(t, FormDataConfigBlockKey, utils.MarshalOrPanic(block)))
+			assert.Equal(t, testCase.code, resp.Code)
+			assert.Equal(t, "cannot join channel foo: "+testCase.err.Error(), decodeError(t, resp))
+		}
+	})
+
+	t.Run("BadContentType", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodPost, URLBaseV1Channels, bytes.NewReader(utils.MarshalOrPanic(block)))
+		req.Header.Set("Content-Type", "application/octet-stream")
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, req)
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Equal(t, "unsupported Content-Type, expected multipart/form-data", decodeError(t, resp))
+	})
+
+	t.Run("MissingField", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, joinRequest(t, "other", utils.MarshalOrPanic(block)))
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Contains(t, decodeError(t, resp), "error with field 'config-block'")
+	})
+
+	t.Run("BadBlock", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, joinRequest(t, FormDataConfigBlockKey, []byte{1, 2, 3}))
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Contains(t, decodeError(t, resp), "error unmarshaling the config block")
+	})
+
+	t.Run("EmptyBlock", func(t *testing.T) {
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, joinRequest(t, FormDataConfigBlockKey, utils.MarshalOrPanic(cb.NewBlock(0, nil))))
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Contains(t, decodeError(t, resp), "error reading the channel ID of the config block")
+	})
+
+	t.Run("BodyTooLarge", func(t *testing.T) {
+		handler := NewHTTPHandler(localconfig.ChannelParticipation{Enabled: true, MaxRequestBodySize: 10}, registrar)
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, joinRequest(t, FormDataConfigBlockKey, utils.MarshalOrPanic(block)))
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+}
+
+func TestRemove(t *testing.T) {
+	registrar := &mockChannelManagement{}
+	handler := NewHTTPHandler(testConfig, registrar)
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, URLBaseV1Channels+"/foo", nil))
+	assert.Equal(t, http.StatusNoContent, resp.Code)
+	assert.Equal(t, []string{"foo"}, registrar.removedIDs)
+
+	for _, testCase := range []struct {
+		err  error
+		code int
+	}{
+		{types.ErrChannelNotExist, http.StatusNotFound},
+		{types.ErrSystemChannelExists, http.StatusMethodNotAllowed},
+		{errors.New("disk on fire"), http.StatusBadRequest},
+	} {
+		handler := NewHTTPHandler(testConfig, &mockChannelManagement{removeErr: testCase.err})
+		resp := httptest.NewRecorder()
+		handler.ServeHTTP(resp, httptest.NewRequest(http.MethodDelete, URLBaseV1Channels+"/foo", nil))
+		assert.Equal(t, testCase.code, resp.Code)
+		assert.Equal(t, "cannot remove channel foo: "+testCase.err.Error(), decodeError(t, resp))
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	handler := NewHTTPHandler(testConfig, &mockChannelManagement{})
+
+	resp := httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPut, URLBaseV1Channels, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+	assert.Equal(t, "GET, POST", resp.Header().Get("Allow"))
+	assert.Equal(t, "invalid request method PUT", decodeError(t, resp))
+
+	resp = httptest.NewRecorder()
+	handler.ServeHTTP(resp, httptest.NewRequest(http.MethodPost, URLBaseV1Channels+"/foo", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, resp.Code)
+	assert.Equal(t, "GET, DELETE", resp.Header().Get("Allow"))
+}
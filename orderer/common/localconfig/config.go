@@ -18,6 +18,7 @@ package config
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"time"
 
@@ -66,7 +67,13 @@ type TopLevel struct {
 	FileLedger FileLedger
 	RAMLedger  RAMLedger
 	Kafka      Kafka
+	Raft       Raft
 	Debug      Debug
+	Broadcast  Broadcast
+	Operations Operations
+	Metrics    Metrics
+
+	ChannelParticipation ChannelParticipation
 }
 
 // General contains config which should be common among all orderer types.
@@ -75,6 +82,7 @@ type General struct {
 	ListenAddress  string
 	ListenPort     uint16
 	TLS            TLS
+	Cluster        Cluster
 	Keepalive      Keepalive
 	GenesisMethod  string
 	GenesisProfile string
@@ -106,6 +114,16 @@ type TLS struct {
 	ClientRootCAs      []string
 }
 
+// Cluster contains configuration for the connections the Raft consenter
+// establishes to the other consenters.
+type Cluster struct {
+	ClientCertificate string
+	ClientPrivateKey  string
+	RootCAs           []string
+	DialTimeout       time.Duration
+	RPCTimeout        time.Duration
+}
+
 // Authentication contains configuration parameters related to authenticating
 // client messages
 type Authentication struct {
@@ -120,8 +138,17 @@ type Profile struct {
 
 // FileLedger contains configuration for the file-based ledger.
 type FileLedger struct {
-	Location string
-	Prefix   string
+	Location  string
+	Prefix    string
+	Retention Retention
+}
+
+// Retention contains the retention policy of the file ledger, which keeps
+// the last Blocks blocks and the blocks younger than Days days. Zero values
+// put no bound.
+type Retention struct {
+	Blocks uint64
+	Days   uint
 }
 
 // RAMLedger contains configuration for the RAM ledger.
@@ -180,12 +207,79 @@ type Consumer struct {
 	RetryBackoff time.Duration
 }
 
+// Raft contains configuration for the Raft-based orderer.
+type Raft struct {
+	WALDir  string
+	SnapDir string
+}
+
 // Debug contains configuration for the orderer's debug parameters
 type Debug struct {
 	BroadcastTraceDir string
 	DeliverTraceDir   string
 }
 
+// Broadcast contains configuration for the Broadcast service.
+type Broadcast struct {
+	RateLimit RateLimit
+}
+
+// RateLimit contains the token bucket limits on the messages accepted by
+// Broadcast, per channel, per organization of a channel and per client of a
+// channel. A limit with a zero Rate is disabled.
+type RateLimit struct {
+	Enabled      bool
+	Channel      TokenBucket
+	Organization TokenBucket
+	Client       TokenBucket
+}
+
+// TokenBucket allows Rate messages per second on average, and bursts of up
+// to Burst messages.
+type TokenBucket struct {
+	Rate  float64
+	Burst int
+}
+
+// Operations contains configuration for the operations endpoint of the
+// orderer, which serves the health checks, the logging specification and
+// the Prometheus metrics.
+type Operations struct {
+	ListenAddress string
+	TLS           TLS
+}
+
+// ChannelParticipation contains configuration for the channel participation
+// API, served on the operations endpoint, which joins the orderer to channels
+// and removes it from channels when it runs without a system channel.
+// As the API changes the channels of the orderer, it can only be enabled if
+// the operations endpoint requires TLS client authentication.
+type ChannelParticipation struct {
+	Enabled            bool
+	MaxRequestBodySize uint32
+}
+
+// Metrics contains configuration for the metrics reported by the orderer.
+type Metrics struct {
+	Enabled        bool
+	Reporter       string
+	Interval       time.Duration
+	StatsdReporter StatsdReporter
+	PromReporter   PromReporter
+}
+
+// StatsdReporter contains configuration for pushing metrics to statsd.
+type StatsdReporter struct {
+	Address       string
+	FlushInterval time.Duration
+	FlushBytes    int
+}
+
+// PromReporter contains configuration for the Prometheus metrics reporter.
+type PromReporter struct {
+	ListenAddress string
+}
+
 var defaults = TopLevel{
 	General: General{
 		LedgerType:     "file",
@@ -204,6 +298,10 @@ var defaults = TopLevel{
 		LocalMSPDir: "msp",
 		LocalMSPID:  "DEFAULT",
 		BCCSP:       bccsp.GetDefaultOpts(),
+		Cluster: Cluster{
+			DialTimeout: 5 * time.Second,
+			RPCTimeout:  7 * time.Second,
+		},
 		Authentication: Authentication{
 			TimeWindow: time.Duration(15 * time.Minute),
 		},
@@ -248,6 +346,27 @@ var defaults = TopLevel{
 		BroadcastTraceDir: "",
 		DeliverTraceDir:   "",
 	},
+	Broadcast: Broadcast{
+		RateLimit: RateLimit{
+			Enabled: false,
+		},
+	},
+	Operations: Operations{
+		ListenAddress: "127.0.0.1:8443",
+	},
+	Metrics: Metrics{
+		Enabled:  false,
+		Reporter: "statsd",
+		Interval: 1 * time.Second,
+		StatsdReporter: StatsdReporter{
+			FlushInterval: 2 * time.Second,
+			FlushBytes:    1432,
+		},
+	},
+	ChannelParticipation: ChannelParticipation{
+		Enabled:            false,
+		MaxRequestBodySize: 1024 * 1024,
+	},
 }
 
 // Load parses the orderer.yaml file and environment, producing a struct suitable for config use, returning error on failure
@@ -284,8 +403,22 @@ func (c *TopLevel) completeInitialization(configDir string) {
 		c.General.TLS.ClientRootCAs = translateCAs(configDir, c.General.TLS.ClientRootCAs)
 		cf.TranslatePathInPlace(configDir, &c.General.TLS.PrivateKey)
 		cf.TranslatePathInPlace(configDir, &c.General.TLS.Certificate)
+		c.General.Cluster.RootCAs = translateCAs(configDir, c.General.Cluster.RootCAs)
+		// Unset paths of the cluster and the Raft config fall back to
+		// other settings, so they are only translated when they are set
+		for _, p := range []*string{&c.General.Cluster.ClientPrivateKey, &c.General.Cluster.ClientCertificate, &c.Raft.WALDir, &c.Raft.SnapDir} {
+			if *p != "" {
+				cf.TranslatePathInPlace(configDir, p)
+			}
+		}
 		cf.TranslatePathInPlace(configDir, &c.General.GenesisFile)
 		cf.TranslatePathInPlace(configDir, &c.General.LocalMSPDir)
+		c.Operations.TLS.ClientRootCAs = translateCAs(configDir, c.Operations.TLS.ClientRootCAs)
+		for _, p := range []*string{&c.Operations.TLS.PrivateKey, &c.Operations.TLS.Certificate} {
+			if *p != "" {
+				cf.TranslatePathInPlace(configDir, p)
+			}
+		}
 	}()
 
 	for {
@@ -339,6 +472,15 @@ func (c *TopLevel) completeInitialization(configDir string) {
 			logger.Infof("General.Authentication.TimeWindow unset, setting to %s", defaults.General.Authentication.TimeWindow)
 			c.General.Authentication.TimeWindow = defaults.General.Authentication.TimeWindow
 
+		case c.General.Cluster.ClientCertificate != "" && c.General.Cluster.ClientPrivateKey == "":
+			logger.Panicf("General.Cluster.ClientPrivateKey must be set if General.Cluster.ClientCertificate is set.")
+		case c.General.Cluster.DialTimeout == 0:
+			logger.Infof("General.Cluster.DialTimeout unset, setting to %v", defaults.General.Cluster.DialTimeout)
+			c.General.Cluster.DialTimeout = defaults.General.Cluster.DialTimeout
+		case c.General.Cluster.RPCTimeout == 0:
+			logger.Infof("General.Cluster.RPCTimeout unset, setting to %v", defaults.General.Cluster.RPCTimeout)
+			c.General.Cluster.RPCTimeout = defaults.General.Cluster.RPCTimeout
+
 		case c.FileLedger.Prefix == "":
 			logger.Infof("FileLedger.Prefix unset, setting to %s", defaults.FileLedger.Prefix)
 			c.FileLedger.Prefix = defaults.FileLedger.Prefix
@@ -388,6 +530,45 @@ func (c *TopLevel) completeInitialization(configDir string) {
 			logger.Infof("Kafka.Version unset, setting to %v", defaults.Kafka.Version)
 			c.Kafka.Version = defaults.Kafka.Version
 
+		case c.Broadcast.RateLimit.Channel.Rate < 0 || c.Broadcast.RateLimit.Organization.Rate < 0 || c.Broadcast.RateLimit.Client.Rate < 0:
+			logger.Panicf("Broadcast.RateLimit rates must not be negative.")
+		case c.Broadcast.RateLimit.Channel.Rate > 0 && c.Broadcast.RateLimit.Channel.Burst <= 0:
+			c.Broadcast.RateLimit.Channel.Burst = burstOf(c.Broadcast.RateLimit.Channel.Rate)
+			logger.Infof("Broadcast.RateLimit.Channel.Burst unset, setting to %d", c.Broadcast.RateLimit.Channel.Burst)
+		case c.Broadcast.RateLimit.Organization.Rate > 0 && c.Broadcast.RateLimit.Organization.Burst <= 0:
+			c.Broadcast.RateLimit.Organization.Burst = burstOf(c.Broadcast.RateLimit.Organization.Rate)
+			logger.Infof("Broadcast.RateLimit.Organization.Burst unset, setting to %d", c.Broadcast.RateLimit.Organization.Burst)
+		case c.Broadcast.RateLimit.Client.Rate > 0 && c.Broadcast.RateLimit.Client.Burst <= 0:
+			c.Broadcast.RateLimit.Client.Burst = burstOf(c.Broadcast.RateLimit.Client.Rate)
+			logger.Infof("Broadcast.RateLimit.Client.Burst unset, setting to %d", c.Broadcast.RateLimit.Client.Burst)
+
+		case c.Operations.ListenAddress == "":
+			logger.Infof("Operations.ListenAddress unset, setting to %s", defaults.Operations.ListenAddress)
+			c.Operations.ListenAddress = defaults.Operations.ListenAddress
+		case c.Operations.TLS.Enabled && c.Operations.TLS.Certificate == "":
+			logger.Panicf("Operations.TLS.Certificate must be set if Operations.TLS.Enabled is set to true.")
+		case c.Operations.TLS.Enabled && c.Operations.TLS.PrivateKey == "":
+			logger.Panicf("Operations.TLS.PrivateKey must be set if Operations.TLS.Enabled is set to true.")
+
+		case c.Metrics.Reporter == "":
+			logger.Infof("Metrics.Reporter unset, setting to %s", defaults.Metrics.Reporter)
+			c.Metrics.Reporter = defaults.Metrics.Reporter
+		case c.Metrics.Interval == 0:
+			logger.Infof("Metrics.Interval unset, setting to %v", defaults.Metrics.Interval)
+			c.Metrics.Interval = defaults.Metrics.Interval
+		case c.Metrics.StatsdReporter.FlushInterval == 0:
+			logger.Infof("Metrics.StatsdReporter.FlushInterval unset, setting to %v", defaults.Metrics.StatsdReporter.FlushInterval)
+			c.Metrics.StatsdReporter.FlushInterval = defaults.Metrics.StatsdReporter.FlushInterval
+		case c.Metrics.StatsdReporter.FlushBytes == 0:
+			logger.Infof("Metrics.StatsdReporter.FlushBytes unset, setting to %v", defaults.Metrics.StatsdReporter.FlushBytes)
+			c.Metrics.StatsdReporter.FlushBytes = defaults.Metrics.StatsdReporter.FlushBytes
+
+		case c.ChannelParticipation.Enabled && !(c.Operations.TLS.Enabled && c.Operations.TLS.ClientAuthRequired):
+			logger.Panicf("Operations.TLS.Enabled and Operations.TLS.ClientAuthRequired must be set to true if ChannelParticipation.Enabled is set to true.")
+		case c.ChannelParticipation.MaxRequestBodySize == 0:
+			logger.Infof("ChannelParticipation.MaxRequestBodySize unset, setting to %v", defaults.ChannelParticipation.MaxRequestBodySize)
+			c.ChannelParticipation.MaxRequestBodySize = defaults.ChannelParticipation.MaxRequestBodySize
+
 		default:
 			return
 		}
@@ -402,3 +583,8 @@ func translateCAs(configDir string, certificateAuthorities []string) []string {
 	}
 	return results
 }
+
+// burstOf returns the default burst of a token bucket, one second of messages
+func burstOf(rate float64) int {
+	return int(math.Ceil(rate))
+}
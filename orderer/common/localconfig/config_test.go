@@ -123,6 +123,31 @@ func TestKafkaTLSConfig(t *testing.T) {
 	}
 }
 
+func TestChannelParticipationConfig(t *testing.T) {
+	testCases := []struct {
+		name        string
+		tls         TLS
+		shouldPanic bool
+	}{
+		{"OperationsTLSDisabled", TLS{Enabled: false}, true},
+		{"NoClientAuth", TLS{Enabled: true, PrivateKey: "private.key", Certificate: "public.key"}, true},
+		{"ClientAuthRequired", TLS{Enabled: true, PrivateKey: "private.key", Certificate: "public.key", ClientAuthRequired: true}, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			uconf := &TopLevel{
+				Operations:           Operations{TLS: tc.tls},
+				ChannelParticipation: ChannelParticipation{Enabled: true},
+			}
+			if tc.shouldPanic {
+				assert.Panics(t, func() { uconf.completeInitialization(DummyPath) }, "should panic")
+			} else {
+				assert.NotPanics(t, func() { uconf.completeInitialization(DummyPath) }, "should not panic")
+			}
+		})
+	}
+}
+
 func TestSystemChannel(t *testing.T) {
 	conf, _ := Load()
 	assert.Equal(t, genesisconfig.TestChainID, conf.General.SystemChannel, "System channel ID should be '%s' by default", genesisconfig.TestChainID)
@@ -133,3 +158,19 @@ func TestProfileConfig(t *testing.T) {
 	uconf.completeInitialization(DummyPath)
 	assert.Equal(t, defaults.General.Profile.Address, uconf.General.Profile.Address, "Expected profile address to be filled with default value")
 }
+
+func TestRateLimitConfig(t *testing.T) {
+	uconf := &TopLevel{Broadcast: Broadcast{RateLimit: RateLimit{
+		Enabled:      true,
+		Channel:      TokenBucket{Rate: 2.5},
+		Organization: TokenBucket{Rate: 0.5},
+		Client:       TokenBucket{Rate: 1, Burst: 5},
+	}}}
+	uconf.completeInitialization(DummyPath)
+	assert.Equal(t, TokenBucket{Rate: 2.5, Burst: 3}, uconf.Broadcast.RateLimit.Channel, "Expected burst to be one second of messages")
+	assert.Equal(t, TokenBucket{Rate: 0.5, Burst: 1}, uconf.Broadcast.RateLimit.Organization, "Expected burst to be at least one message")
+	assert.Equal(t, TokenBucket{Rate: 1, Burst: 5}, uconf.Broadcast.RateLimit.Client, "Expected burst to be kept")
+
+	uconf = &TopLevel{Broadcast: Broadcast{RateLimit: RateLimit{Client: TokenBucket{Rate: -1}}}}
+	assert.Panics(t, func() { uconf.completeInitialization(DummyPath) }, "should panic on a negative rate")
+}
@@ -0,0 +1,51 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msgprocessor
+
+import (
+	"github.com/hyperledger/fabric/common/channelconfig"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// NewMaintenanceFilter creates a filter which rejects the messages other than
+// config updates while the channel is in maintenance mode
+func NewMaintenanceFilter(support channelconfig.Resources) *MaintenanceFilter {
+	return &MaintenanceFilter{support: support}
+}
+
+// MaintenanceFilter implements the Rule interface. While the channel is in
+// maintenance mode, typically to migrate it to another consensus type, only
+// the messages which reconfigure the channel are accepted.
+type MaintenanceFilter struct {
+	support channelconfig.Resources
+}
+
+// Apply returns an error wrapping ErrMaintenanceMode if the channel is in
+// maintenance mode and the message is not of type CONFIG_UPDATE or CONFIG
+func (mf *MaintenanceFilter) Apply(message *cb.Envelope) error {
+	ordererConfig, ok := mf.support.OrdererConfig()
+	if !ok {
+		logger.Panicf("Missing orderer config")
+	}
+	if ordererConfig.ConsensusState() != ab.ConsensusType_STATE_MAINTENANCE {
+		return nil
+	}
+
+	chdr, err := utils.ChannelHeader(message)
+	if err != nil {
+		return errors.WithMessage(err, "could not get channel header")
+	}
+	switch chdr.Type {
+	case int32(cb.HeaderType_CONFIG_UPDATE), int32(cb.HeaderType_CONFIG):
+		return nil
+	default:
+		return errors.WithMessage(ErrMaintenanceMode, "only config updates are accepted")
+	}
+}
@@ -0,0 +1,63 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msgprocessor
+
+import (
+	"testing"
+
+	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func envelopeOfType(headerType cb.HeaderType) *cb.Envelope {
+	return &cb.Envelope{
+		Payload: utils.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{
+				ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{
+					Type:      int32(headerType),
+					ChannelId: "foo",
+				}),
+			},
+		}),
+	}
+}
+
+func TestMaintenanceFilter(t *testing.T) {
+	ordererConfig := &mockconfig.Orderer{ConsensusTypeVal: "kafka"}
+	mf := NewMaintenanceFilter(&mockconfig.Resources{OrdererConfigVal: ordererConfig})
+
+	t.Run("Normal", func(t *testing.T) {
+		for _, headerType := range []cb.HeaderType{cb.HeaderType_ENDORSER_TRANSACTION, cb.HeaderType_ORDERER_TRANSACTION, cb.HeaderType_CONFIG_UPDATE, cb.HeaderType_CONFIG} {
+			assert.NoError(t, mf.Apply(envelopeOfType(headerType)), "Expected %s messages to be accepted", headerType)
+		}
+		assert.NoError(t, mf.Apply(&cb.Envelope{Payload: []byte("garbage")}))
+	})
+
+	t.Run("Maintenance", func(t *testing.T) {
+		ordererConfig.ConsensusStateVal = ab.ConsensusType_STATE_MAINTENANCE
+		defer func() { ordererConfig.ConsensusStateVal = ab.ConsensusType_STATE_NORMAL }()
+
+		for _, headerType := range []cb.HeaderType{cb.HeaderType_ENDORSER_TRANSACTION, cb.HeaderType_MESSAGE, cb.HeaderType_ORDERER_TRANSACTION} {
+			err := mf.Apply(envelopeOfType(headerType))
+			assert.EqualError(t, err, "only config updates are accepted: maintenance mode")
+			assert.Equal(t, ErrMaintenanceMode, errors.Cause(err))
+		}
+		for _, headerType := range []cb.HeaderType{cb.HeaderType_CONFIG_UPDATE, cb.HeaderType_CONFIG} {
+			assert.NoError(t, mf.Apply(envelopeOfType(headerType)), "Expected %s messages to be accepted", headerType)
+		}
+		assert.Error(t, mf.Apply(&cb.Envelope{Payload: []byte("garbage")}))
+	})
+
+	t.Run("MissingOrdererConfig", func(t *testing.T) {
+		mf := NewMaintenanceFilter(&mockconfig.Resources{})
+		assert.Panics(t, func() { mf.Apply(envelopeOfType(cb.HeaderType_ENDORSER_TRANSACTION)) })
+	})
+}
@@ -38,6 +38,10 @@ var ErrChannelDoesNotExist = errors.New("channel does not exist")
 // which are not permitted due to an authorization failure.
 var ErrPermissionDenied = errors.New("permission denied")
 
+// ErrMaintenanceMode is returned for the messages which are rejected because
+// the channel is in maintenance mode.
+var ErrMaintenanceMode = errors.New("maintenance mode")
+
 // Classification represents the possible message types for the system.
 type Classification int
 
@@ -0,0 +1,225 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msgprocessor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// ErrRateLimited is the cause of the errors returned by the RateLimitRule
+// when a message exceeds one of its limits.
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// The limits of the RateLimitRule, in the order in which they are checked
+const (
+	ChannelRateLimit      = "channel"
+	OrganizationRateLimit = "organization"
+	ClientRateLimit       = "client"
+)
+
+// bucketSweepInterval is how often the RateLimitRule drops the buckets which
+// have been refilled, so that one-off clients do not accumulate in memory
+const bucketSweepInterval = time.Minute
+
+// TokenBucketLimit allows Rate messages per second on average, and bursts of
+// up to Burst messages. A limit with a Rate that is not positive is disabled.
+type TokenBucketLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimits holds the limits of a RateLimitRule. The Channel limit applies
+// to all the messages of a channel, the Organization limit to the messages of
+// a channel signed by an MSP, and the Client limit to the messages of a
+// channel signed by an identity.
+type RateLimits struct {
+	Channel      TokenBucketLimit
+	Organization TokenBucketLimit
+	Client       TokenBucketLimit
+}
+
+// RateLimitError is returned by the RateLimitRule when a message exceeds a
+// limit. Its cause is ErrRateLimited.
+type RateLimitError struct {
+	// Limit is the name of the limit which was exceeded
+	Limit string
+	// Key identifies the channel, organization or client which exceeded it
+	Key string
+	// RetryAfter is the time after which the message would be accepted
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%s: %s limit of %s reached, retry after %s", ErrRateLimited, e.Limit, e.Key, e.RetryAfter)
+}
+
+// Cause returns ErrRateLimited
+func (e *RateLimitError) Cause() error {
+	return ErrRateLimited
+}
+
+type namedLimit struct {
+	name string
+	TokenBucketLimit
+}
+
+type tokenBucket struct {
+	limit  TokenBucketLimit
+	tokens float64
+	last   time.Time
+}
+
+// refill adds the tokens accumulated since the last refill
+func (b *tokenBucket) refill(now time.Time) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.Rate)
+		b.last = now
+	}
+}
+
+// RateLimitRule implements the Rule interface. It rejects the messages which
+// exceed the token bucket limits of their channel, of the MSP of their
+// signer, or of their signer.
+//
+// The rule consumes a token of each limit per accepted message, so it must
+// be applied once per message received, rather than as part of the filters
+// of a channel, which are applied again when messages are revalidated.
+// Signers are identified by the creator of the signature header, so the rule
+// should be applied after the signature of the message has been checked.
+type RateLimitRule struct {
+	limits []namedLimit
+	now    func() time.Time
+
+	mutex     sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+// NewRateLimitRule creates a rule enforcing the given limits
+func NewRateLimitRule(limits RateLimits) *RateLimitRule {
+	rule := &RateLimitRule{
+		now:     time.Now,
+		buckets: make(map[string]*tokenBucket),
+	}
+	for _, limit := range []namedLimit{
+		{name: ChannelRateLimit, TokenBucketLimit: limits.Channel},
+		{name: OrganizationRateLimit, TokenBucketLimit: limits.Organization},
+		{name: ClientRateLimit, TokenBucketLimit: limits.Client},
+	} {
+		if limit.Rate <= 0 {
+			continue
+		}
+		if limit.Burst < 1 {
+			limit.Burst = 1
+		}
+		rule.limits = append(rule.limits, limit)
+	}
+	return rule
+}
+
+// Apply takes a token from each bucket of the message, or returns a
+// RateLimitError without taking any if one of them is empty
+func (r *RateLimitRule) Apply(message *cb.Envelope) error {
+	if len(r.limits) == 0 {
+		return nil
+	}
+
+	keys, err := rateLimitKeys(message)
+	if err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	now := r.now()
+	r.sweep(now)
+
+	var rateLimitErr *RateLimitError
+	buckets := make([]*tokenBucket, 0, len(r.limits))
+	for _, limit := range r.limits {
+		key := keys[limit.name]
+		bucket := r.bucket(limit, key, now)
+		bucket.refill(now)
+		if bucket.tokens >= 1 {
+			buckets = append(buckets, bucket)
+			continue
+		}
+		retryAfter := time.Duration((1 - bucket.tokens) / limit.Rate * float64(time.Second))
+		if rateLimitErr == nil || retryAfter > rateLimitErr.RetryAfter {
+			rateLimitErr = &RateLimitError{Limit: limit.name, Key: key, RetryAfter: retryAfter}
+		}
+	}
+	if rateLimitErr != nil {
+		return rateLimitErr
+	}
+
+	for _, bucket := range buckets {
+		bucket.tokens--
+	}
+	return nil
+}
+
+// bucket returns the bucket of the key for the limit, new buckets are full
+func (r *RateLimitRule) bucket(limit namedLimit, key string, now time.Time) *tokenBucket {
+	bucketKey := limit.name + "|" + key
+	bucket, ok := r.buckets[bucketKey]
+	if !ok {
+		bucket = &tokenBucket{limit: limit.TokenBucketLimit, tokens: float64(limit.Burst), last: now}
+		r.buckets[bucketKey] = bucket
+	}
+	return bucket
+}
+
+// sweep drops the full buckets, which are equivalent to new ones
+func (r *RateLimitRule) sweep(now time.Time) {
+	if now.Sub(r.lastSweep) < bucketSweepInterval {
+		return
+	}
+	r.lastSweep = now
+	for key, bucket := range r.buckets {
+		bucket.refill(now)
+		if bucket.tokens >= float64(bucket.limit.Burst) {
+			delete(r.buckets, key)
+		}
+	}
+}
+
+// rateLimitKeys returns the key of the message for each limit
+func rateLimitKeys(message *cb.Envelope) (map[string]string, error) {
+	chdr, err := utils.ChannelHeader(message)
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not get channel header")
+	}
+	signedData, err := message.AsSignedData()
+	if err != nil {
+		return nil, errors.WithMessage(err, "could not convert message to signedData")
+	}
+	sid := &msp.SerializedIdentity{}
+	if err := proto.Unmarshal(signedData[0].Identity, sid); err != nil {
+		return nil, errors.Wrap(err, "could not unmarshal creator")
+	}
+
+	idHash := sha256.Sum256(sid.IdBytes)
+	organization := chdr.ChannelId + "/" + sid.Mspid
+	return map[string]string{
+		ChannelRateLimit:      chdr.ChannelId,
+		OrganizationRateLimit: organization,
+		ClientRateLimit:       organization + "/" + hex.EncodeToString(idHash[:8]),
+	}, nil
+}
@@ -0,0 +1,126 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package msgprocessor
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	cb "github.com/hyperledger/fabric/protos/common"
+	"github.com/hyperledger/fabric/protos/msp"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func createRateLimitEnvelope(channelID, mspID, cert string) *cb.Envelope {
+	creator := utils.MarshalOrPanic(&msp.SerializedIdentity{Mspid: mspID, IdBytes: []byte(cert)})
+	payload := &cb.Payload{
+		Header: utils.MakePayloadHeader(
+			utils.MakeChannelHeader(cb.HeaderType_ENDORSER_TRANSACTION, 0, channelID, 0),
+			utils.MakeSignatureHeader(creator, nil),
+		),
+	}
+	return &cb.Envelope{Payload: utils.MarshalOrPanic(payload)}
+}
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newTestRateLimitRule(limits RateLimits) (*RateLimitRule, *fakeClock) {
+	clock := &fakeClock{now: time.Unix(1000, 0)}
+	rule := NewRateLimitRule(limits)
+	rule.now = clock.Now
+	return rule, clock
+}
+
+func TestRateLimitDisabled(t *testing.T) {
+	rule := NewRateLimitRule(RateLimits{})
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, rule.Apply(&cb.Envelope{}))
+	}
+}
+
+func TestRateLimitClient(t *testing.T) {
+	rule, clock := newTestRateLimitRule(RateLimits{Client: TokenBucketLimit{Rate: 2, Burst: 3}})
+	env := createRateLimitEnvelope("foo", "Org1MSP", "client1")
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, rule.Apply(env), "Should have accepted the burst")
+	}
+	err := rule.Apply(env)
+	assert.Equal(t, ErrRateLimited, errors.Cause(err))
+	rateLimitErr, ok := err.(*RateLimitError)
+	assert.True(t, ok, "Should have returned a RateLimitError")
+	assert.Equal(t, ClientRateLimit, rateLimitErr.Limit)
+	assert.Contains(t, rateLimitErr.Key, "foo/Org1MSP/")
+	assert.Equal(t, 500*time.Millisecond, rateLimitErr.RetryAfter)
+
+	// other clients, and the same client on other channels, have their own buckets
+	assert.NoError(t, rule.Apply(createRateLimitEnvelope("foo", "Org1MSP", "client2")))
+	assert.NoError(t, rule.Apply(createRateLimitEnvelope("bar", "Org1MSP", "client1")))
+
+	clock.now = clock.now.Add(500 * time.Millisecond)
+	assert.NoError(t, rule.Apply(env), "Should have accepted the message after the retry hint")
+	assert.Error(t, rule.Apply(env))
+}
+
+func TestRateLimitOrganizationAndChannel(t *testing.T) {
+	rule, clock := newTestRateLimitRule(RateLimits{
+		Channel:      TokenBucketLimit{Rate: 1, Burst: 3},
+		Organization: TokenBucketLimit{Rate: 1, Burst: 2},
+	})
+
+	assert.NoError(t, rule.Apply(createRateLimitEnvelope("foo", "Org1MSP", "client1")))
+	assert.NoError(t, rule.Apply(createRateLimitEnvelope("foo", "Org1MSP", "client2")))
+	err := rule.Apply(createRateLimitEnvelope("foo", "Org1MSP", "client3"))
+	assert.Equal(t, OrganizationRateLimit, err.(*RateLimitError).Limit)
+	assert.Equal(t, "rate limit exceeded: organization limit of foo/Org1MSP reached, retry after 1s", err.Error())
+
+	// the rejected message took no token from the channel limit
+	assert.NoError(t, rule.Apply(createRateLimitEnvelope("foo", "Org2MSP", "client1")))
+	err = rule.Apply(createRateLimitEnvelope("foo", "Org2MSP", "client2"))
+	assert.Equal(t, ChannelRateLimit, err.(*RateLimitError).Limit)
+
+	clock.now = clock.now.Add(time.Second)
+	assert.NoError(t, rule.Apply(createRateLimitEnvelope("foo", "Org2MSP", "client2")))
+}
+
+func TestRateLimitDefaultBurst(t *testing.T) {
+	rule, _ := newTestRateLimitRule(RateLimits{Channel: TokenBucketLimit{Rate: 10}})
+	env := createRateLimitEnvelope("foo", "Org1MSP", "client1")
+	assert.NoError(t, rule.Apply(env))
+	assert.Error(t, rule.Apply(env), "Should have defaulted to a burst of one message")
+}
+
+func TestRateLimitSweep(t *testing.T) {
+	rule, clock := newTestRateLimitRule(RateLimits{Client: TokenBucketLimit{Rate: 1, Burst: 1}})
+	assert.NoError(t, rule.Apply(createRateLimitEnvelope("foo", "Org1MSP", "client1")))
+	assert.NoError(t, rule.Apply(createRateLimitEnvelope("foo", "Org1MSP", "client2")))
+	assert.Len(t, rule.buckets, 2)
+
+	clock.now = clock.now.Add(bucketSweepInterval)
+	assert.NoError(t, rule.Apply(createRateLimitEnvelope("foo", "Org1MSP", "client3")))
+	assert.Len(t, rule.buckets, 1, "Should have dropped the refilled buckets")
+}
+
+func TestRateLimitMalformedMessage(t *testing.T) {
+	rule := NewRateLimitRule(RateLimits{Client: TokenBucketLimit{Rate: 1}})
+	assert.Error(t, rule.Apply(&cb.Envelope{Payload: []byte("garbage")}))
+
+	env := createRateLimitEnvelope("foo", "Org1MSP", "client1")
+	payload := utils.UnmarshalPayloadOrPanic(env.Payload)
+	payload.Header.SignatureHeader = utils.MarshalOrPanic(&cb.SignatureHeader{Creator: []byte("garbage")})
+	env.Payload, _ = proto.Marshal(payload)
+	assert.Error(t, rule.Apply(env))
+}
@@ -52,6 +52,7 @@ func CreateStandardChannelFilters(filterSupport channelconfig.Resources) *RuleSe
 	}
 	return NewRuleSet([]Rule{
 		EmptyRejectRule,
+		NewMaintenanceFilter(filterSupport),
 		NewExpirationRejectRule(filterSupport),
 		NewSizeFilter(ordererConfig),
 		NewSigFilter(policies.ChannelWriters, filterSupport),
@@ -39,6 +39,10 @@ type BlockWriter struct {
 	lastConfigSeq      uint64
 	lastBlock          *cb.Block
 	committingBlock    sync.Mutex
+	// consensusSwitched is set once a config block changing the consensus
+	// type has been written, the chain is then restarted under the new
+	// consenter, and the blocks of the old one are dropped
+	consensusSwitched bool
 }
 
 // 创建 BlockWriter
@@ -96,6 +100,11 @@ func (bw *BlockWriter) CreateNextBlock(messages []*cb.Envelope) *cb.Block {
 // This call will block until the new config has taken effect, then will return
 // while the block is written asynchronously to disk.
 func (bw *BlockWriter) WriteConfigBlock(block *cb.Block, encodedMetadataValue []byte) {
+	if bw.consensusSwitched {
+		logger.Warningf("[channel: %s] Dropping config block %d, the chain is being restarted under a new consensus type", bw.support.ChainID(), block.Header.Number)
+		return
+	}
+
 	// 从配置区块中提取第一个交易消息
 	ctx, err := utils.ExtractEnvelope(block, 0)
 	if err != nil {
@@ -145,8 +154,27 @@ func (bw *BlockWriter) WriteConfigBlock(block *cb.Block, encodedMetadataValue []
 			logger.Panicf("Told to write a config block with a new config, but could not convert it to a bundle: %s", err)
 		}
 
+		oldConsensusType, err := consensusTypeOf(bw.support.ConfigProto())
+		if err != nil {
+			logger.Panicf("Told to write a config block, but the current consensus type is invalid: %s", err)
+		}
+		newConsensusType, err := consensusTypeOf(configEnvelope.Config)
+		if err != nil {
+			logger.Panicf("Told to write a config block with an invalid consensus type: %s", err)
+		}
+
 		// 更新通道上链支持对象的通道配置实体
 		bw.support.Update(bundle)
+
+		if oldConsensusType != "" && newConsensusType != "" && oldConsensusType != newConsensusType {
+			bw.WriteBlock(block, encodedMetadataValue)
+			bw.consensusSwitched = true
+			logger.Infof("[channel: %s] Consensus type changed from %s to %s at block %d", chdr.ChannelId, oldConsensusType, newConsensusType, block.Header.Number)
+			// the chain is halted, which waits for the current consenter call
+			// to return for some consenters, so it is restarted asynchronously
+			go bw.registrar.switchConsensus(chdr.ChannelId, oldConsensusType)
+			return
+		}
 	default:
 		logger.Panicf("Told to write a config block with unknown header type: %v", chdr.Type)
 	}
@@ -164,6 +192,10 @@ func (bw *BlockWriter) WriteConfigBlock(block *cb.Block, encodedMetadataValue []
 	写入区块到账本中
 */
 func (bw *BlockWriter) WriteBlock(block *cb.Block, encodedMetadataValue []byte) {
+	if bw.consensusSwitched {
+		logger.Warningf("[channel: %s] Dropping block %d, the chain is being restarted under a new consensus type", bw.support.ChainID(), block.Header.Number)
+		return
+	}
 	bw.committingBlock.Lock() // 锁
 	// 更新最新区块
 	bw.lastBlock = block
@@ -192,3 +192,25 @@ func TestGoodWriteConfig(t *testing.T) {
 	omd := utils.GetMetadataFromBlockOrPanic(block, cb.BlockMetadataIndex_ORDERER)
 	assert.Equal(t, consenterMetadata, omd.Value)
 }
+
+func TestWriteAfterConsensusSwitch(t *testing.T) {
+	l := NewRAMLedger(10)
+
+	bw := &BlockWriter{
+		support: &mockBlockWriterSupport{
+			LocalSigner: mockCrypto(),
+			ReadWriter:  l,
+			Validator:   &mockconfigtx.Validator{},
+		},
+		lastBlock:         genesisBlock,
+		consensusSwitched: true,
+	}
+
+	bw.WriteBlock(bw.CreateNextBlock([]*cb.Envelope{makeNormalTx(genesisconfig.TestChainID, 1)}), nil)
+	bw.WriteConfigBlock(bw.CreateNextBlock([]*cb.Envelope{makeConfigTx(genesisconfig.TestChainID, 2)}), nil)
+
+	bw.committingBlock.Lock()
+	bw.committingBlock.Unlock()
+	assert.Equal(t, uint64(1), l.Height(), "Blocks written after a consensus switch should have been dropped")
+	assert.Equal(t, genesisBlock, bw.lastBlock)
+}
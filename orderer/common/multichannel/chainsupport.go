@@ -13,11 +13,16 @@ import (
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
 	"github.com/hyperledger/fabric/orderer/consensus"
 	cb "github.com/hyperledger/fabric/protos/common"
-	"github.com/hyperledger/fabric/protos/utils"
 
 	"github.com/pkg/errors"
 )
 
+// retentionSuspender is implemented by the ledgers which enforce a retention
+// policy that can be suspended.
+type retentionSuspender interface {
+	SuspendRetention(suspend bool)
+}
+
 // ChainSupport holds the resources for a particular channel.
 type ChainSupport struct {
 	*ledgerResources	// 账本资源对象
@@ -33,22 +38,39 @@ func newChainSupport(
 	ledgerResources *ledgerResources,
 	consenters map[string]consensus.Consenter,
 	signer crypto.LocalSigner,
-) *ChainSupport {
+) (*ChainSupport, error) {
+	// 获取共识组件类型
+	consenterType := ledgerResources.SharedConfig().ConsensusType()
+	// 获取共识组件对象
+	consenter, ok := consenters[consenterType]
+	if !ok {
+		return nil, errors.Errorf("error retrieving consenter of type: %s", consenterType)
+	}
+
+	// The blocks of the ledger are retained for the consenters which may read
+	// any of them
+	if suspender, ok := ledgerResources.ReadWriter.(retentionSuspender); ok {
+		historyReader, ok := consenter.(consensus.HistoryReader)
+		suspender.SuspendRetention(ok && historyReader.ReadsHistory())
+	}
+
 	// Read in the last block and metadata for the channel
 	lastBlock := blockledger.GetBlock(ledgerResources, ledgerResources.Height()-1)
 
-	metadata, err := utils.GetMetadataFromBlock(lastBlock, cb.BlockMetadataIndex_ORDERER)
-	// Assuming a block created with cb.NewBlock(), this should not
-	// error even if the orderer metadata is an empty byte slice
+	// The consenters which keep their state outside of the ledger start afresh
+	// when the channel has been migrated to them, the others resume from the
+	// last block they wrote
+	_, removable := consenter.(consensus.ChainRemover)
+	metadata, err := consenterMetadata(ledgerResources, consenterType, !removable)
 	if err != nil {
-		logger.Fatalf("[channel: %s] Error extracting orderer metadata: %s", ledgerResources.ConfigtxValidator().ChainID(), err)
+		return nil, errors.WithMessage(err, "error extracting orderer metadata")
 	}
 
 	// Construct limited support needed as a parameter for additional support
 	cs := &ChainSupport{
 		ledgerResources: ledgerResources,
 		LocalSigner:     signer,
-		cutter:          blockcutter.NewReceiverImpl(ledgerResources), // 消息切割组件（receiver 类型）
+		cutter:          newBlockCutter(ledgerResources), // 消息切割组件（receiver 类型）
 	}
 
 	// Set up the msgprocessor
@@ -60,26 +82,30 @@ func newChainSupport(
 	cs.BlockWriter = newBlockWriter(lastBlock, registrar, cs)
 
 	// Set up the consenter
-	// 获取共识组件类型
-	consenterType := ledgerResources.SharedConfig().ConsensusType()
-	// 获取共识组件对象
-	consenter, ok := consenters[consenterType]
-	if !ok {
-		logger.Panicf("Error retrieving consenter of type: %s", consenterType)
-	}
-
 	/*
 		链支持对象 cs 实现了 ConsenterSupport 接口，以支持 solo 和 kafka 共识组件链对象
 		solo 共识组件只使用了 cs 参数，kafka 共识组件则使用了两个参数
 	*/
 	cs.Chain, err = consenter.HandleChain(cs, metadata) // 创建共识组价链对象
 	if err != nil {
-		logger.Panicf("[channel: %s] Error creating consenter: %s", cs.ChainID(), err)
+		return nil, errors.WithMessage(err, "error creating consenter")
 	}
 
 	logger.Debugf("[channel: %s] Done creating channel support resources", cs.ChainID())
 
-	return cs
+	return cs, nil
+}
+
+// newBlockCutter returns a receiver which schedules the messages of the channel
+// if its config defines block scheduling classes or organization weights, or
+// one which keeps the messages in the order in which they are ordered
+// otherwise.
+func newBlockCutter(ledgerResources *ledgerResources) blockcutter.Receiver {
+	scheduling := ledgerResources.SharedConfig().BlockScheduling()
+	if len(scheduling.GetClasses()) == 0 && len(scheduling.GetOrganizationWeights()) == 0 {
+		return blockcutter.NewReceiverImpl(ledgerResources)
+	}
+	return blockcutter.NewFairReceiver(ledgerResources)
 }
 
 func (cs *ChainSupport) Reader() blockledger.Reader {
@@ -121,7 +147,15 @@ func (cs *ChainSupport) ProposeConfigUpdate(configtx *cb.Envelope) (*cb.ConfigEn
 		return nil, errors.Wrap(err, "config update is not compatible")
 	}
 
-	return env, cs.ValidateNew(bundle)
+	if err = cs.ValidateNew(bundle); err != nil {
+		return nil, err
+	}
+
+	if err = cs.validateConsensusMigration(bundle); err != nil {
+		return nil, errors.WithMessage(err, "invalid consensus type migration")
+	}
+
+	return env, nil
 }
 
 // ChainID passes through to the underlying configtx.Validator
@@ -134,6 +168,15 @@ func (cs *ChainSupport) ConfigProto() *cb.Config {
 	return cs.ConfigtxValidator().ConfigProto()
 }
 
+// Block returns the block with the given number,
+// or nil if such a block doesn't exist.
+func (cs *ChainSupport) Block(number uint64) *cb.Block {
+	if cs.Height() <= number {
+		return nil
+	}
+	return blockledger.GetBlock(cs.Reader(), number)
+}
+
 // Sequence passes through to the underlying configtx.Validator
 func (cs *ChainSupport) Sequence() uint64 {
 	return cs.ConfigtxValidator().Sequence()
@@ -0,0 +1,239 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package multichannel
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/configtx"
+	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
+	"github.com/hyperledger/fabric/orderer/consensus"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// A channel is migrated to another consensus type through config updates:
+//
+// 1. The channel is put in maintenance mode, in which only config updates
+//    are ordered.
+// 2. The consensus type is changed, along with its metadata. Once the config
+//    block is written, the chain is halted, and restarted from the last block
+//    under the consenter of the new consensus type.
+// 3. The channel is put back in normal mode.
+//
+// The migration is aborted by changing the consensus type back at step 3,
+// before leaving maintenance mode. The chain is then restarted under the
+// consenter of the original type, which resumes from its last block.
+
+// consensusTypeOf returns the consensus type defined by the given config, or
+// an empty string if it defines none
+func consensusTypeOf(config *cb.Config) (string, error) {
+	if config == nil || config.ChannelGroup == nil {
+		return "", nil
+	}
+	ordererGroup, ok := config.ChannelGroup.Groups[channelconfig.OrdererGroupKey]
+	if !ok {
+		return "", nil
+	}
+	value, ok := ordererGroup.Values[channelconfig.ConsensusTypeKey]
+	if !ok {
+		return "", nil
+	}
+	consensusType := &ab.ConsensusType{}
+	if err := proto.Unmarshal(value.Value, consensusType); err != nil {
+		return "", errors.Wrap(err, "failed to unmarshal consensus type")
+	}
+	return consensusType.Type, nil
+}
+
+// configBlockConsensusType returns the consensus type defined by the config
+// block with the given number
+func configBlockConsensusType(reader blockledger.Reader, number uint64) (string, error) {
+	block := blockledger.GetBlock(reader, number)
+	if block == nil {
+		return "", errors.Errorf("config block %d is not available", number)
+	}
+	env, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return "", err
+	}
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return "", err
+	}
+	configEnv, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+	if err != nil {
+		return "", err
+	}
+	return consensusTypeOf(configEnv.Config)
+}
+
+// lastConfigIndex returns the number of the config block in effect when the
+// block with the given number was written
+func lastConfigIndex(reader blockledger.Reader, number uint64) (uint64, error) {
+	if number == 0 {
+		return 0, nil
+	}
+	block := blockledger.GetBlock(reader, number)
+	if block == nil {
+		return 0, errors.Errorf("block %d is not available", number)
+	}
+	return utils.GetLastConfigIndexFromBlock(block)
+}
+
+// consenterMetadata returns the orderer metadata the consenter of the given
+// consensus type, which is the type of the current config, starts a chain
+// from. It is the metadata of the last block if the consenter wrote it.
+// Otherwise the channel has just been migrated to the consensus type, and the
+// metadata is that of the last block written by the consenter before, if it is
+// resumable, or nil if the consenter has to start afresh. A block is written
+// by the consenter of the config in effect before it.
+func consenterMetadata(reader blockledger.Reader, consensusType string, resumable bool) (*cb.Metadata, error) {
+	number := reader.Height() - 1
+	configIndex, err := lastConfigIndex(reader, number)
+	if err != nil {
+		return nil, err
+	}
+	if number == 0 || number != configIndex {
+		return blockOrdererMetadata(reader, number)
+	}
+
+	for first := true; ; first = false {
+		prevConfigIndex, prevType, err := previousConfig(reader, number)
+		if err == errBlockPruned {
+			// the history was pruned by the retention policy of the ledger
+			logger.Warningf("Cannot find the consensus type block %d was written by, assuming %s", number, consensusType)
+			if first {
+				return blockOrdererMetadata(reader, number)
+			}
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if prevType == consensusType {
+			if !first {
+				logger.Infof("Consensus type %s resumes from block %d", consensusType, number)
+			}
+			return blockOrdererMetadata(reader, number)
+		}
+		if !resumable || prevConfigIndex == 0 {
+			return nil, nil
+		}
+		// the blocks after prevConfigIndex were written by the consenter
+		// of prevType, the one of prevConfigIndex by the previous one
+		number = prevConfigIndex
+	}
+}
+
+// errBlockPruned is returned when a block is below the oldest block retained
+// by the ledger
+var errBlockPruned = errors.New("block is not available")
+
+// previousConfig returns the number and the consensus type of the config in
+// effect before the block with the given number, which is not 0
+func previousConfig(reader blockledger.Reader, number uint64) (uint64, string, error) {
+	if blockledger.GetBlock(reader, number-1) == nil {
+		return 0, "", errBlockPruned
+	}
+	configIndex, err := lastConfigIndex(reader, number-1)
+	if err != nil {
+		return 0, "", err
+	}
+	if blockledger.GetBlock(reader, configIndex) == nil {
+		return 0, "", errBlockPruned
+	}
+	consensusType, err := configBlockConsensusType(reader, configIndex)
+	if err != nil {
+		return 0, "", err
+	}
+	return configIndex, consensusType, nil
+}
+
+// blockOrdererMetadata returns the orderer metadata of the given block
+func blockOrdererMetadata(reader blockledger.Reader, number uint64) (*cb.Metadata, error) {
+	block := blockledger.GetBlock(reader, number)
+	if block == nil {
+		return nil, errors.Errorf("block %d is not available", number)
+	}
+	return utils.GetMetadataFromBlock(block, cb.BlockMetadataIndex_ORDERER)
+}
+
+// validateConsensusMigration checks that the channel can be migrated to the
+// consensus type of the given config if it differs from the current one
+func (cs *ChainSupport) validateConsensusMigration(bundle *channelconfig.Bundle) error {
+	noc, ok := bundle.OrdererConfig()
+	if !ok {
+		return errors.New("config does not contain orderer config")
+	}
+	consensusType := noc.ConsensusType()
+	if consensusType == cs.SharedConfig().ConsensusType() {
+		return nil
+	}
+
+	consenter, ok := cs.registrar.consenters[consensusType]
+	if !ok {
+		return errors.Errorf("consensus type %s is not supported", consensusType)
+	}
+	if validator, ok := consenter.(consensus.MetadataValidator); ok {
+		if err := validator.ValidateConsensusMetadata(noc.ConsensusMetadata()); err != nil {
+			return errors.WithMessage(err, "invalid metadata for consensus type "+consensusType)
+		}
+	}
+	return nil
+}
+
+// switchConsensus restarts the chain of a channel under the consenter of its
+// current consensus type, once the config block which changed it from the
+// given type has been written by the consenter of that type.
+func (r *Registrar) switchConsensus(chainID, fromType string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	cs, ok := r.chains[chainID]
+	if !ok {
+		logger.Warningf("[channel: %s] Not restarting the chain, the channel has been removed", chainID)
+		return
+	}
+	cs.Halt()
+	// wait for the config block to be committed
+	cs.BlockWriter.committingBlock.Lock()
+	cs.BlockWriter.committingBlock.Unlock()
+
+	toType := cs.SharedConfig().ConsensusType()
+	for _, consensusType := range []string{fromType, toType} {
+		// the state the consenters keep outside of the ledger is stale once
+		// the chain is migrated to or from them
+		if remover, ok := r.consenters[consensusType].(consensus.ChainRemover); ok {
+			if err := remover.RemoveChain(chainID); err != nil {
+				logger.Errorf("[channel: %s] Failed to remove the %s consensus data: %s", chainID, consensusType, err)
+			}
+		}
+	}
+
+	newCS, err := newChainSupport(r, cs.ledgerResources, r.consenters, r.signer)
+	if err != nil {
+		logger.Panicf("[channel: %s] Failed to restart the chain under consensus type %s: %s", chainID, toType, err)
+	}
+	if chainID == r.systemChannelID {
+		r.templator = msgprocessor.NewDefaultTemplator(newCS)
+		newCS.Processor = msgprocessor.NewSystemChannel(newCS, r.templator, msgprocessor.CreateSystemChannelFilters(r, newCS))
+		r.systemChannel = newCS
+	}
+
+	newChains := make(map[string]*ChainSupport)
+	for key, value := range r.chains {
+		newChains[key] = value
+	}
+	newChains[chainID] = newCS
+	logger.Infof("[channel: %s] Restarting the chain under consensus type %s from block %d", chainID, toType, newCS.Height()-1)
+	newCS.start()
+	r.chains = newChains
+}
@@ -0,0 +1,239 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package multichannel
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	ramledger "github.com/hyperledger/fabric/common/ledger/blockledger/ram"
+	"github.com/hyperledger/fabric/common/tools/configtxlator/update"
+	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
+	"github.com/hyperledger/fabric/orderer/consensus"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockRemovableConsenter is a consenter which keeps state outside of the
+// ledger and validates its metadata
+type mockRemovableConsenter struct {
+	mockConsenter
+
+	lock    sync.Mutex
+	removed []string
+}
+
+func (mc *mockRemovableConsenter) RemoveChain(chainID string) error {
+	mc.lock.Lock()
+	defer mc.lock.Unlock()
+	mc.removed = append(mc.removed, chainID)
+	return nil
+}
+
+func (mc *mockRemovableConsenter) ValidateConsensusMetadata(metadata []byte) error {
+	if string(metadata) != "good" {
+		return errors.Errorf("bad metadata %s", metadata)
+	}
+	return nil
+}
+
+func (mc *mockRemovableConsenter) removedChains() []string {
+	mc.lock.Lock()
+	defer mc.lock.Unlock()
+	return append([]string(nil), mc.removed...)
+}
+
+// consensusConfigTx returns a config transaction setting the consensus type
+func consensusConfigTx(chainID, consensusType string) *cb.Envelope {
+	group := cb.NewConfigGroup()
+	group.Groups[channelconfig.OrdererGroupKey] = cb.NewConfigGroup()
+	group.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.ConsensusTypeKey] = &cb.ConfigValue{
+		Value: utils.MarshalOrPanic(&ab.ConsensusType{Type: consensusType}),
+	}
+	return makeConfigTxFromConfigUpdateEnvelope(chainID, &cb.ConfigUpdateEnvelope{
+		ConfigUpdate: utils.MarshalOrPanic(&cb.ConfigUpdate{WriteSet: group}),
+	})
+}
+
+// appendTestBlock appends a block with the given transaction, marking its
+// orderer metadata with its number
+func appendTestBlock(t *testing.T, rl blockledger.ReadWriter, env *cb.Envelope, lastConfig uint64) {
+	block := blockledger.CreateNextBlock(rl, []*cb.Envelope{env})
+	block.Metadata.Metadata[cb.BlockMetadataIndex_ORDERER] = utils.MarshalOrPanic(&cb.Metadata{Value: []byte{byte(block.Header.Number)}})
+	block.Metadata.Metadata[cb.BlockMetadataIndex_LAST_CONFIG] = utils.MarshalOrPanic(&cb.Metadata{
+		Value: utils.MarshalOrPanic(&cb.LastConfig{Index: lastConfig}),
+	})
+	require.NoError(t, rl.Append(block))
+}
+
+func TestConsenterMetadata(t *testing.T) {
+	rl, err := ramledger.New(10).GetOrCreate("foo")
+	require.NoError(t, err)
+
+	assertMetadata := func(consensusType string, resumable bool, expected interface{}) {
+		metadata, err := consenterMetadata(rl, consensusType, resumable)
+		assert.NoError(t, err)
+		if expected == nil {
+			assert.Nil(t, metadata, "Expected %s to start afresh at height %d", consensusType, rl.Height())
+			return
+		}
+		require.NotNil(t, metadata, "Expected %s to resume at height %d", consensusType, rl.Height())
+		assert.Equal(t, []byte{byte(expected.(int))}, metadata.Value)
+	}
+
+	appendTestBlock(t, rl, consensusConfigTx("foo", "solo"), 0)
+	assertMetadata("solo", true, 0)
+
+	appendTestBlock(t, rl, makeNormalTx("foo", 1), 0)
+	assertMetadata("solo", true, 1)
+
+	// a config block which does not change the consensus type
+	appendTestBlock(t, rl, consensusConfigTx("foo", "solo"), 2)
+	assertMetadata("solo", true, 2)
+
+	// migrated to raft, which starts afresh
+	appendTestBlock(t, rl, consensusConfigTx("foo", "raft"), 3)
+	assertMetadata("raft", false, nil)
+	assertMetadata("raft", true, nil)
+
+	appendTestBlock(t, rl, makeNormalTx("foo", 4), 3)
+	assertMetadata("raft", false, 4)
+
+	// migrated back to solo, which resumes from the last block it wrote
+	appendTestBlock(t, rl, consensusConfigTx("foo", "solo"), 5)
+	assertMetadata("solo", true, 3)
+	assertMetadata("solo", false, nil)
+
+	t.Run("Pruned", func(t *testing.T) {
+		retained := func(size int) blockledger.ReadWriter {
+			pruned, err := ramledger.New(size).GetOrCreate("foo")
+			require.NoError(t, err)
+			for number := uint64(0); number < rl.Height(); number++ {
+				require.NoError(t, pruned.Append(blockledger.GetBlock(rl, number)))
+			}
+			return pruned
+		}
+
+		// the config before the last block is not retained
+		metadata, err := consenterMetadata(retained(2), "solo", true)
+		assert.NoError(t, err)
+		require.NotNil(t, metadata)
+		assert.Equal(t, []byte{5}, metadata.Value)
+
+		// the last block written by solo is not retained
+		metadata, err = consenterMetadata(retained(3), "solo", true)
+		assert.NoError(t, err)
+		assert.Nil(t, metadata)
+	})
+}
+
+// consensusUpdate returns a config update of the channel, setting its
+// consensus type, metadata and state
+func consensusUpdate(t *testing.T, cs *ChainSupport, consensusType *ab.ConsensusType) *cb.Envelope {
+	original := cs.ConfigProto()
+	updated := proto.Clone(original).(*cb.Config)
+	updated.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.ConsensusTypeKey].Value = utils.MarshalOrPanic(consensusType)
+	configUpdate, err := update.Compute(original, updated)
+	require.NoError(t, err)
+	configUpdate.ChannelId = cs.ChainID()
+	env, err := utils.CreateSignedEnvelope(cb.HeaderType_CONFIG_UPDATE, cs.ChainID(), mockCrypto(), &cb.ConfigUpdateEnvelope{
+		ConfigUpdate: utils.MarshalOrPanic(configUpdate),
+	}, msgVersion, epoch)
+	require.NoError(t, err)
+	return env
+}
+
+// waitFor polls the condition until it holds or a timeout expires
+func waitFor(t *testing.T, condition func() bool, msg string) {
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatal(msg)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestConsensusMigration(t *testing.T) {
+	raft := &mockRemovableConsenter{}
+	consenters := map[string]consensus.Consenter{conf.Orderer.OrdererType: &mockConsenter{}, "raft": raft}
+	registrar := NewRegistrar(ramledger.New(10), consenters, mockCrypto())
+	_, err := registrar.JoinChannel("foo", appChannelGenesisBlock("foo"))
+	require.NoError(t, err)
+	cs, _ := registrar.GetChain("foo")
+
+	// configure submits the config update to the chain, and waits for the
+	// config block to be written, or the chain to be restarted
+	configure := func(consensusType *ab.ConsensusType) {
+		previous, height := cs, cs.Height()
+		switching := consensusType.Type != previous.SharedConfig().ConsensusType()
+		config, configSeq, err := cs.ProcessConfigUpdateMsg(consensusUpdate(t, cs, consensusType))
+		require.NoError(t, err)
+		require.NoError(t, cs.Configure(config, configSeq))
+		waitFor(t, func() bool { return previous.Height() > height }, "Config block was not written")
+		if switching {
+			waitFor(t, func() bool {
+				cs, _ = registrar.GetChain("foo")
+				return cs != previous
+			}, "Chain was not restarted")
+		}
+	}
+	submitNormalTx := func() error {
+		_, err := cs.ProcessNormalMsg(makeNormalTx("foo", 0))
+		return err
+	}
+
+	soloType := conf.Orderer.OrdererType
+	_, _, err = cs.ProcessConfigUpdateMsg(consensusUpdate(t, cs, &ab.ConsensusType{Type: "raft", Metadata: []byte("good")}))
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "not in maintenance mode")
+
+	configure(&ab.ConsensusType{Type: soloType, State: ab.ConsensusType_STATE_MAINTENANCE})
+	assert.Equal(t, msgprocessor.ErrMaintenanceMode, errors.Cause(submitNormalTx()))
+
+	t.Run("UnsupportedConsensusType", func(t *testing.T) {
+		_, _, err := cs.ProcessConfigUpdateMsg(consensusUpdate(t, cs, &ab.ConsensusType{Type: "unknown", State: ab.ConsensusType_STATE_MAINTENANCE}))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid consensus type migration: consensus type unknown is not supported")
+	})
+
+	t.Run("InvalidMetadata", func(t *testing.T) {
+		_, _, err := cs.ProcessConfigUpdateMsg(consensusUpdate(t, cs, &ab.ConsensusType{Type: "raft", Metadata: []byte("bad"), State: ab.ConsensusType_STATE_MAINTENANCE}))
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid metadata for consensus type raft: bad metadata bad")
+	})
+
+	// migrate to raft
+	soloChain := cs.Chain.(*mockChain)
+	configure(&ab.ConsensusType{Type: "raft", Metadata: []byte("good"), State: ab.ConsensusType_STATE_MAINTENANCE})
+	<-soloChain.done
+	assert.Equal(t, "raft", cs.SharedConfig().ConsensusType())
+	assert.Nil(t, cs.Chain.(*mockChain).metadata, "raft should have started afresh")
+	assert.Equal(t, []string{"foo"}, raft.removedChains())
+	assert.Equal(t, uint64(3), cs.Height())
+
+	// abort the migration, back to solo
+	raftChain := cs.Chain.(*mockChain)
+	configure(&ab.ConsensusType{Type: soloType, State: ab.ConsensusType_STATE_MAINTENANCE})
+	<-raftChain.done
+	assert.Equal(t, soloType, cs.SharedConfig().ConsensusType())
+	assert.NotNil(t, cs.Chain.(*mockChain).metadata, "solo should have resumed")
+	assert.Equal(t, []string{"foo", "foo"}, raft.removedChains())
+	assert.Equal(t, msgprocessor.ErrMaintenanceMode, errors.Cause(submitNormalTx()))
+
+	configure(&ab.ConsensusType{Type: soloType})
+	assert.NoError(t, submitNormalTx())
+	assert.Equal(t, uint64(5), cs.Height())
+}
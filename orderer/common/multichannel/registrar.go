@@ -11,6 +11,7 @@ package multichannel
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/configtx"
@@ -18,6 +19,7 @@ import (
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
+	"github.com/hyperledger/fabric/orderer/common/types"
 	"github.com/hyperledger/fabric/orderer/consensus"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
@@ -104,6 +106,7 @@ type Registrar struct {
 	systemChannel   *ChainSupport	// 系统通道链支持对象
 	templator       msgprocessor.ChannelConfigTemplator	// 通道配置末班，用于生成消息处理器 ？？？
 	callbacks       []func(bundle *channelconfig.Bundle)	// tls 认证连接回调函数列表 ？？？
+	lock            sync.RWMutex                         // serializes the changes to chains
 }
 
 //获取
@@ -162,11 +165,14 @@ func NewRegistrar(ledgerFactory blockledger.Factory, consenters map[string]conse
 				logger.Panicf("There appear to be two system chains %s and %s", r.systemChannelID, chainID)
 			}
 			// 创建该通道的链支持对象
-			chain := newChainSupport(
+			chain, err := newChainSupport(
 				r,	// 多通道管理器
 				ledgerResources, // 账本资源对象
 				consenters, // 共识组件字典
 				signer) // 签名者实体
+			if err != nil {
+				logger.Panicf("[channel: %s] %s", chainID, err)
+			}
 
 			// 创建默认通道配置模板 ？？？
 			// TODO: READ
@@ -201,18 +207,21 @@ func NewRegistrar(ledgerFactory blockledger.Factory, consenters map[string]conse
 				直接创建链支持对象，注册到多通道注册管理器中
 			*/
 			logger.Debugf("Starting chain: %s", chainID)
-			chain := newChainSupport(
+			chain, err := newChainSupport(
 				r,
 				ledgerResources,
 				consenters,
 				signer)
+			if err != nil {
+				logger.Panicf("[channel: %s] %s", chainID, err)
+			}
 			r.chains[chainID] = chain
 			chain.start()	// TODO: 为什么此处不需要 defer
 		}
 	}
 
 	if r.systemChannelID == "" {
-		logger.Panicf("No system chain found.  If bootstrapping, does your system channel contain a consortiums group definition?")
+		logger.Infof("No system channel found, channels are joined and removed through the channel participation API")
 	}
 
 	return r
@@ -234,6 +243,9 @@ func (r *Registrar) BroadcastChannelSupport(msg *cb.Envelope) (*cb.ChannelHeader
 
 	cs, ok := r.chains[chdr.ChannelId]
 	if !ok {
+		if r.systemChannel == nil {
+			return chdr, false, nil, errors.Errorf("channel %s does not exist", chdr.ChannelId)
+		}
 		cs = r.systemChannel
 	}
 
@@ -298,6 +310,9 @@ func (r *Registrar) newLedgerResources(configTx *cb.Envelope) *ledgerResources {
 
 // 创建应用通道
 func (r *Registrar) newChain(configtx *cb.Envelope) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
 	// 基于给定的配置交易消息创建新的账本资源对象
 	ledgerResources := r.newLedgerResources(configtx)
 	// 添加当前新应用通道的创世区块
@@ -313,10 +328,13 @@ func (r *Registrar) newChain(configtx *cb.Envelope) {
 		newChains[key] = value // 复制链支持对象字典
 	}
 
-	// 创建新的链支持对象
-	cs := newChainSupport(r, ledgerResources, r.consenters, r.signer)
 	// 重新获得通道 id
 	chainID := ledgerResources.ConfigtxValidator().ChainID()
+	// 创建新的链支持对象
+	cs, err := newChainSupport(r, ledgerResources, r.consenters, r.signer)
+	if err != nil {
+		logger.Panicf("[channel: %s] %s", chainID, err)
+	}
 
 	logger.Infof("Created and starting new chain %s", chainID)
 
@@ -341,3 +359,198 @@ func (r *Registrar) NewChannelConfig(envConfigUpdate *cb.Envelope) (channelconfi
 func (r *Registrar) CreateBundle(channelID string, config *cb.Config) (channelconfig.Resources, error) {
 	return channelconfig.NewBundle(channelID, config)
 }
+
+// ChannelList returns the channels served by the orderer.
+func (r *Registrar) ChannelList() types.ChannelList {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	list := types.ChannelList{Channels: []types.ChannelInfoShort{}}
+	for chainID := range r.chains {
+		if chainID == r.systemChannelID {
+			list.SystemChannel = &types.ChannelInfoShort{Name: chainID}
+			continue
+		}
+		list.Channels = append(list.Channels, types.ChannelInfoShort{Name: chainID})
+	}
+	return list
+}
+
+// ChannelInfo returns the status and the height of a channel served by the orderer.
+func (r *Registrar) ChannelInfo(channelID string) (types.ChannelInfo, error) {
+	cs, ok := r.GetChain(channelID)
+	if !ok {
+		return types.ChannelInfo{}, types.ErrChannelNotExist
+	}
+
+	info := types.ChannelInfo{
+		Name:          channelID,
+		ConsensusType: cs.SharedConfig().ConsensusType(),
+		Status:        types.StatusActive,
+		Height:        cs.Height(),
+	}
+	select {
+	case <-cs.Errored():
+		info.Status = types.StatusInactive
+	default:
+	}
+	return info, nil
+}
+
+// JoinChannel starts serving the application channel created by the given
+// genesis block. Channels can be joined only by an orderer without a system
+// channel, as otherwise the system channel creates them.
+func (r *Registrar) JoinChannel(channelID string, genesisBlock *cb.Block) (types.ChannelInfo, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.systemChannelID != "" {
+		return types.ChannelInfo{}, types.ErrSystemChannelExists
+	}
+	if _, ok := r.chains[channelID]; ok {
+		return types.ChannelInfo{}, types.ErrChannelAlreadyExists
+	}
+
+	configTx, err := r.validateGenesisBlock(channelID, genesisBlock)
+	if err != nil {
+		return types.ChannelInfo{}, err
+	}
+
+	ledger, err := r.ledgerFactory.GetOrCreate(channelID)
+	if err != nil {
+		return types.ChannelInfo{}, errors.WithMessage(err, "failed to create the ledger")
+	}
+	if ledger.Height() != 0 {
+		return types.ChannelInfo{}, errors.Errorf("ledger of channel %s is not empty", channelID)
+	}
+	if err := ledger.Append(genesisBlock); err != nil {
+		r.removeLedger(channelID)
+		return types.ChannelInfo{}, errors.WithMessage(err, "failed to append the genesis block")
+	}
+
+	cs, err := newChainSupport(r, r.newLedgerResources(configTx), r.consenters, r.signer)
+	if err != nil {
+		r.removeLedger(channelID)
+		return types.ChannelInfo{}, err
+	}
+
+	newChains := make(map[string]*ChainSupport)
+	for key, value := range r.chains {
+		newChains[key] = value
+	}
+	newChains[channelID] = cs
+	logger.Infof("Joined channel %s with genesis block hash %x and orderer type %s", channelID, genesisBlock.Header.Hash(), cs.SharedConfig().ConsensusType())
+	cs.start()
+	r.chains = newChains
+
+	return types.ChannelInfo{
+		Name:          channelID,
+		ConsensusType: cs.SharedConfig().ConsensusType(),
+		Status:        types.StatusActive,
+		Height:        cs.Height(),
+	}, nil
+}
+
+// validateGenesisBlock checks that the block is the genesis block of the
+// given application channel, with a config this orderer can serve, and
+// returns its config transaction.
+func (r *Registrar) validateGenesisBlock(channelID string, block *cb.Block) (*cb.Envelope, error) {
+	if block == nil || block.Header == nil || block.Data == nil {
+		return nil, errors.New("block is empty")
+	}
+	if block.Header.Number != 0 {
+		return nil, errors.Errorf("block %d is not a genesis block, channels can only be joined from their genesis block", block.Header.Number)
+	}
+	if len(block.Data.Data) != 1 {
+		return nil, errors.Errorf("genesis block must contain a single transaction, found %d", len(block.Data.Data))
+	}
+
+	configTx, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := utils.UnmarshalPayload(configTx.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Header == nil {
+		return nil, errors.New("genesis block transaction has no header")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+	if chdr.Type != int32(cb.HeaderType_CONFIG) {
+		return nil, errors.Errorf("genesis block transaction is of type %s, not a config transaction", cb.HeaderType(chdr.Type))
+	}
+	if chdr.ChannelId != channelID {
+		return nil, errors.Errorf("genesis block is for channel %s, not %s", chdr.ChannelId, channelID)
+	}
+	configEnvelope, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle, err := channelconfig.NewBundle(channelID, configEnvelope.Config)
+	if err != nil {
+		return nil, errors.WithMessage(err, "invalid channel config")
+	}
+	if err := checkResources(bundle); err != nil {
+		return nil, err
+	}
+	if _, ok := bundle.ConsortiumsConfig(); ok {
+		return nil, errors.New("genesis block is the genesis block of a system channel")
+	}
+	// the presence of the orderer config was checked by checkResources
+	oc, _ := bundle.OrdererConfig()
+	if _, ok := r.consenters[oc.ConsensusType()]; !ok {
+		return nil, errors.Errorf("consensus type %s is not supported", oc.ConsensusType())
+	}
+	return configTx, nil
+}
+
+// RemoveChannel stops serving the given channel and deletes its ledger.
+// Like JoinChannel, it is reserved to orderers without a system channel.
+func (r *Registrar) RemoveChannel(channelID string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.systemChannelID != "" {
+		return types.ErrSystemChannelExists
+	}
+	cs, ok := r.chains[channelID]
+	if !ok {
+		return types.ErrChannelNotExist
+	}
+
+	newChains := make(map[string]*ChainSupport)
+	for key, value := range r.chains {
+		if key != channelID {
+			newChains[key] = value
+		}
+	}
+	r.chains = newChains
+
+	cs.Halt()
+	// wait for the block being committed, if any
+	cs.BlockWriter.committingBlock.Lock()
+	defer cs.BlockWriter.committingBlock.Unlock()
+
+	if remover, ok := r.consenters[cs.SharedConfig().ConsensusType()].(consensus.ChainRemover); ok {
+		if err := remover.RemoveChain(channelID); err != nil {
+			return errors.WithMessage(err, "failed to remove the consensus data")
+		}
+	}
+	if err := r.ledgerFactory.Remove(channelID); err != nil {
+		return errors.WithMessage(err, "failed to remove the ledger")
+	}
+	logger.Infof("Removed channel %s", channelID)
+	return nil
+}
+
+// removeLedger deletes the ledger of a channel which could not be joined
+func (r *Registrar) removeLedger(channelID string) {
+	if err := r.ledgerFactory.Remove(channelID); err != nil {
+		logger.Errorf("Failed to remove the ledger of channel %s: %s", channelID, err)
+	}
+}
@@ -12,6 +12,8 @@ import (
 	"time"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	"github.com/hyperledger/fabric/common/configtx"
 	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
@@ -22,6 +24,8 @@ import (
 	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
 	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/orderer/common/blockcutter"
+	"github.com/hyperledger/fabric/orderer/common/types"
 	"github.com/hyperledger/fabric/orderer/consensus"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
@@ -103,14 +107,20 @@ func TestGetConfigTxFailure(t *testing.T) {
 	assert.Panics(t, func() { getConfigTx(rl) }, "Should have panicked because of bad last config metadata")
 }
 
-// This test checks to make sure the orderer refuses to come up if it cannot find a system channel
+// This test checks that the orderer comes up without a system channel, and then rejects channel creation requests
 func TestNoSystemChain(t *testing.T) {
 	lf := ramledger.New(10)
 
 	consenters := make(map[string]consensus.Consenter)
 	consenters[conf.Orderer.OrdererType] = &mockConsenter{}
 
-	assert.Panics(t, func() { NewRegistrar(lf, consenters, mockCrypto()) }, "Should have panicked when starting without a system chain")
+	var registrar *Registrar
+	assert.NotPanics(t, func() { registrar = NewRegistrar(lf, consenters, mockCrypto()) }, "Should have started without a system chain")
+	assert.Equal(t, "", registrar.SystemChannelID())
+	assert.Equal(t, 0, registrar.ChannelsCount())
+
+	_, _, _, err := registrar.BroadcastChannelSupport(makeNormalTx("foo", 1))
+	assert.EqualError(t, err, "channel foo does not exist")
 }
 
 // This test checks to make sure that the orderer refuses to come up if there are multiple system channels
@@ -270,7 +280,8 @@ func TestNewChain(t *testing.T) {
 		t.Fatalf("Block 1 not produced after timeout on new chain")
 	}
 
-	rcs := newChainSupport(manager, chainSupport.ledgerResources, consenters, mockCrypto())
+	rcs, err := newChainSupport(manager, chainSupport.ledgerResources, consenters, mockCrypto())
+	assert.NoError(t, err)
 	assert.Equal(t, expectedLastConfigSeq, rcs.lastConfigSeq, "On restart, incorrect lastConfigSeq")
 }
 
@@ -358,3 +369,202 @@ func TestBroadcastChannelSupportRejection(t *testing.T) {
 	_, _, _, err := registrar.BroadcastChannelSupport(configTx)
 	assert.Error(t, err, "Messages of type HeaderType_CONFIG should return an error.")
 }
+
+// appChannelGenesisBlock returns the genesis block of an application channel
+func appChannelGenesisBlock(channelID string) *cb.Block {
+	profile := *conf
+	profile.Consortiums = nil
+	profile.Application = &genesisconfig.Application{}
+	return encoder.New(&profile).GenesisBlockForChannel(channelID)
+}
+
+// withBlockScheduling returns the genesis block with the block scheduling
+// set in its orderer config
+func withBlockScheduling(t *testing.T, block *cb.Block, scheduling *ab.BlockScheduling) *cb.Block {
+	env, err := utils.ExtractEnvelope(block, 0)
+	assert.NoError(t, err)
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	assert.NoError(t, err)
+	configEnv, err := configtx.UnmarshalConfigEnvelope(payload.Data)
+	assert.NoError(t, err)
+	configEnv.Config.ChannelGroup.Groups[channelconfig.OrdererGroupKey].Values[channelconfig.BlockSchedulingKey] = &cb.ConfigValue{
+		Value:     utils.MarshalOrPanic(scheduling),
+		ModPolicy: channelconfig.AdminsPolicyKey,
+	}
+	payload.Data = utils.MarshalOrPanic(configEnv)
+	env.Payload = utils.MarshalOrPanic(payload)
+	block.Data.Data[0] = utils.MarshalOrPanic(env)
+	block.Header.DataHash = block.Data.Hash()
+	return block
+}
+
+func TestBlockCutterScheduling(t *testing.T) {
+	registrar := NewRegistrar(ramledger.New(10), map[string]consensus.Consenter{conf.Orderer.OrdererType: &mockConsenter{}}, mockCrypto())
+
+	_, err := registrar.JoinChannel("fifo", withBlockScheduling(t, appChannelGenesisBlock("fifo"), &ab.BlockScheduling{}))
+	assert.NoError(t, err)
+	cs, _ := registrar.GetChain("fifo")
+	assert.IsType(t, blockcutter.NewReceiverImpl(nil), cs.BlockCutter(), "Should keep the messages in order without scheduling")
+
+	scheduling := &ab.BlockScheduling{OrganizationWeights: map[string]uint32{"SampleOrg": 2}}
+	_, err = registrar.JoinChannel("fair", withBlockScheduling(t, appChannelGenesisBlock("fair"), scheduling))
+	assert.NoError(t, err)
+	cs, _ = registrar.GetChain("fair")
+	assert.IsType(t, blockcutter.NewFairReceiver(nil), cs.BlockCutter(), "Should schedule the messages")
+}
+
+func TestJoinChannel(t *testing.T) {
+	lf := ramledger.New(10)
+	consenters := map[string]consensus.Consenter{conf.Orderer.OrdererType: &mockConsenter{}}
+	registrar := NewRegistrar(lf, consenters, mockCrypto())
+
+	info, err := registrar.JoinChannel("foo", appChannelGenesisBlock("foo"))
+	assert.NoError(t, err)
+	assert.Equal(t, types.ChannelInfo{Name: "foo", ConsensusType: conf.Orderer.OrdererType, Status: types.StatusActive, Height: 1}, info)
+
+	cs, ok := registrar.GetChain("foo")
+	assert.True(t, ok, "Should have found the joined channel")
+	assert.NoError(t, cs.Order(makeNormalTx("foo", 0), 0))
+	assert.Equal(t, []string{"foo"}, lf.ChainIDs())
+
+	info, err = registrar.ChannelInfo("foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "foo", info.Name)
+	assert.Equal(t, types.ChannelList{Channels: []types.ChannelInfoShort{{Name: "foo"}}}, registrar.ChannelList())
+
+	_, err = registrar.JoinChannel("foo", appChannelGenesisBlock("foo"))
+	assert.Equal(t, types.ErrChannelAlreadyExists, err)
+
+	t.Run("WrongChannel", func(t *testing.T) {
+		_, err := registrar.JoinChannel("bar", appChannelGenesisBlock("baz"))
+		assert.EqualError(t, err, "genesis block is for channel baz, not bar")
+	})
+
+	t.Run("NotGenesisBlock", func(t *testing.T) {
+		block := appChannelGenesisBlock("bar")
+		block.Header.Number = 5
+		_, err := registrar.JoinChannel("bar", block)
+		assert.EqualError(t, err, "block 5 is not a genesis block, channels can only be joined from their genesis block")
+	})
+
+	t.Run("NotConfigBlock", func(t *testing.T) {
+		block := appChannelGenesisBlock("bar")
+		block.Data.Data = [][]byte{utils.MarshalOrPanic(makeNormalTx("bar", 0))}
+		_, err := registrar.JoinChannel("bar", block)
+		assert.EqualError(t, err, "genesis block transaction is of type ENDORSER_TRANSACTION, not a config transaction")
+	})
+
+	t.Run("SystemChannel", func(t *testing.T) {
+		_, err := registrar.JoinChannel("bar", encoder.New(conf).GenesisBlockForChannel("bar"))
+		assert.EqualError(t, err, "genesis block is the genesis block of a system channel")
+	})
+
+	t.Run("UnsupportedConsensusType", func(t *testing.T) {
+		registrar := NewRegistrar(ramledger.New(10), map[string]consensus.Consenter{"other": &mockConsenter{}}, mockCrypto())
+		_, err := registrar.JoinChannel("bar", appChannelGenesisBlock("bar"))
+		assert.EqualError(t, err, "consensus type solo is not supported")
+	})
+
+	t.Run("ConsenterFailure", func(t *testing.T) {
+		lf := ramledger.New(10)
+		consenters := map[string]consensus.Consenter{conf.Orderer.OrdererType: &mockConsenter{err: errors.New("not a consenter")}}
+		registrar := NewRegistrar(lf, consenters, mockCrypto())
+		_, err := registrar.JoinChannel("bar", appChannelGenesisBlock("bar"))
+		assert.EqualError(t, err, "error creating consenter: not a consenter")
+		assert.Empty(t, lf.ChainIDs(), "Should have removed the ledger of the channel")
+		assert.Equal(t, 0, registrar.ChannelsCount())
+	})
+
+	assert.Equal(t, 1, registrar.ChannelsCount())
+}
+
+func TestRemoveChannel(t *testing.T) {
+	lf := ramledger.New(10)
+	consenters := map[string]consensus.Consenter{conf.Orderer.OrdererType: &mockConsenter{}}
+	registrar := NewRegistrar(lf, consenters, mockCrypto())
+
+	for _, channelID := range []string{"foo", "bar"} {
+		_, err := registrar.JoinChannel(channelID, appChannelGenesisBlock(channelID))
+		assert.NoError(t, err)
+	}
+
+	assert.NoError(t, registrar.RemoveChannel("foo"))
+	_, ok := registrar.GetChain("foo")
+	assert.False(t, ok, "Should not have found the removed channel")
+	_, err := registrar.ChannelInfo("foo")
+	assert.Equal(t, types.ErrChannelNotExist, err)
+	assert.Equal(t, []string{"bar"}, lf.ChainIDs())
+	assert.Equal(t, types.ChannelList{Channels: []types.ChannelInfoShort{{Name: "bar"}}}, registrar.ChannelList())
+
+	assert.Equal(t, types.ErrChannelNotExist, registrar.RemoveChannel("foo"))
+
+	// the channel can be joined again
+	info, err := registrar.JoinChannel("foo", appChannelGenesisBlock("foo"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), info.Height)
+}
+
+// suspendableLedgerFactory creates ledgers recording whether their retention
+// policy is suspended
+type suspendableLedgerFactory struct {
+	blockledger.Factory
+	ledgers map[string]*suspendableLedger
+}
+
+type suspendableLedger struct {
+	blockledger.ReadWriter
+	suspended bool
+}
+
+func (sl *suspendableLedger) SuspendRetention(suspend bool) {
+	sl.suspended = suspend
+}
+
+func (slf *suspendableLedgerFactory) GetOrCreate(chainID string) (blockledger.ReadWriter, error) {
+	if ledger, ok := slf.ledgers[chainID]; ok {
+		return ledger, nil
+	}
+	rw, err := slf.Factory.GetOrCreate(chainID)
+	if err != nil {
+		return nil, err
+	}
+	slf.ledgers[chainID] = &suspendableLedger{ReadWriter: rw}
+	return slf.ledgers[chainID], nil
+}
+
+type mockHistoryReader struct {
+	mockConsenter
+}
+
+func (mhr *mockHistoryReader) ReadsHistory() bool {
+	return true
+}
+
+func TestRetentionSuspendedForHistoryReaders(t *testing.T) {
+	lf := &suspendableLedgerFactory{Factory: ramledger.New(10), ledgers: make(map[string]*suspendableLedger)}
+
+	registrar := NewRegistrar(lf, map[string]consensus.Consenter{conf.Orderer.OrdererType: &mockConsenter{}}, mockCrypto())
+	_, err := registrar.JoinChannel("foo", appChannelGenesisBlock("foo"))
+	assert.NoError(t, err)
+	assert.False(t, lf.ledgers["foo"].suspended, "Expected the retention to be enforced")
+
+	registrar = NewRegistrar(lf, map[string]consensus.Consenter{conf.Orderer.OrdererType: &mockHistoryReader{}}, mockCrypto())
+	_, err = registrar.JoinChannel("bar", appChannelGenesisBlock("bar"))
+	assert.NoError(t, err)
+	assert.True(t, lf.ledgers["bar"].suspended, "Expected the retention to be suspended")
+}
+
+func TestChannelParticipationWithSystemChannel(t *testing.T) {
+	lf, _ := NewRAMLedgerAndFactory(10)
+	consenters := map[string]consensus.Consenter{conf.Orderer.OrdererType: &mockConsenter{}}
+	registrar := NewRegistrar(lf, consenters, mockCrypto())
+
+	assert.Equal(t, types.ChannelList{
+		SystemChannel: &types.ChannelInfoShort{Name: genesisconfig.TestChainID},
+		Channels:      []types.ChannelInfoShort{},
+	}, registrar.ChannelList())
+
+	_, err := registrar.JoinChannel("foo", appChannelGenesisBlock("foo"))
+	assert.Equal(t, types.ErrSystemChannelExists, err)
+	assert.Equal(t, types.ErrSystemChannelExists, registrar.RemoveChannel(genesisconfig.TestChainID))
+}
@@ -20,9 +20,13 @@ import (
 )
 
 type mockConsenter struct {
+	err error
 }
 
 func (mc *mockConsenter) HandleChain(support consensus.ConsenterSupport, metadata *cb.Metadata) (consensus.Chain, error) {
+	if mc.err != nil {
+		return nil, mc.err
+	}
 	return &mockChain{
 		queue:    make(chan *cb.Envelope),
 		cutter:   support.BlockCutter(),
@@ -20,17 +20,22 @@ import (
 	"github.com/hyperledger/fabric/common/channelconfig"
 	"github.com/hyperledger/fabric/common/crypto"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/healthz"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/common/tools/configtxgen/encoder"
 	genesisconfig "github.com/hyperledger/fabric/common/tools/configtxgen/localconfig"
 	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/core/operations"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/orderer/common/bootstrap/file"
+	"github.com/hyperledger/fabric/orderer/common/channelparticipation"
 	"github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/common/metadata"
 	"github.com/hyperledger/fabric/orderer/common/multichannel"
 	"github.com/hyperledger/fabric/orderer/consensus"
 	"github.com/hyperledger/fabric/orderer/consensus/kafka"
+	"github.com/hyperledger/fabric/orderer/consensus/raft"
 	"github.com/hyperledger/fabric/orderer/consensus/solo"
 	cb "github.com/hyperledger/fabric/protos/common"
 	ab "github.com/hyperledger/fabric/protos/orderer"
@@ -110,8 +115,14 @@ func Start(cmd string, conf *config.TopLevel) {
 			updateTrustedRoots(grpcServer, caSupport, bundle) // 执行回调函数更新根 CA 证书
 		}
 	}
+	initializeMetrics(conf)
+	opsSystem := newOperationsSystem(conf)
+
 	// 初始化多channel管理器对象
-	manager := InitializeMultichannelRegistrar(conf, signer, tlsCallback)
+	manager := InitializeMultichannelRegistrar(conf, signer, grpcServer, opsSystem, tlsCallback)
+	if conf.ChannelParticipation.Enabled {
+		opsSystem.RegisterHandler(channelparticipation.URLBaseV1, channelparticipation.NewHTTPHandler(conf.ChannelParticipation, manager))
+	}
 	// 设置 tls 双向认证标志
 	mutualTLS := serverConfig.SecOpts.UseTLS && serverConfig.SecOpts.RequireClientCert
 	// 创建 orderer 排序服务器
@@ -119,13 +130,18 @@ func Start(cmd string, conf *config.TopLevel) {
 		signer,
 		&conf.Debug,
 		conf.General.Authentication.TimeWindow,
-		mutualTLS)
+		mutualTLS,
+		&conf.Broadcast.RateLimit)
 
 	switch cmd {
 	case start.FullCommand(): // "start" command
 		logger.Infof("Starting %s", metadata.GetVersionInfo())
 		//
 		initializeProfilingService(conf)
+		if err := opsSystem.Start(); err != nil {
+			logger.Panicf("Failed to start the operations system: %s", err)
+		}
+		defer opsSystem.Stop()
 		//
 		ab.RegisterAtomicBroadcastServer(grpcServer.Server(), server)
 		logger.Info("Beginning to serve requests")
@@ -156,6 +172,44 @@ func initializeProfilingService(conf *config.TopLevel) {
 	}
 }
 
+// Initialize the root metrics scope and start reporting if enabled.
+func initializeMetrics(conf *config.TopLevel) {
+	opts := metrics.Opts{
+		Enabled:  conf.Metrics.Enabled,
+		Reporter: conf.Metrics.Reporter,
+		Interval: conf.Metrics.Interval,
+		StatsdReporterOpts: metrics.StatsdReporterOpts{
+			Address:       conf.Metrics.StatsdReporter.Address,
+			FlushInterval: conf.Metrics.StatsdReporter.FlushInterval,
+			FlushBytes:    conf.Metrics.StatsdReporter.FlushBytes,
+		},
+		PromReporterOpts: metrics.PromReporterOpts{
+			ListenAddress: conf.Metrics.PromReporter.ListenAddress,
+		},
+	}
+	if err := metrics.Init(opts); err != nil {
+		logger.Panicf("Failed to initialize metrics: %s", err)
+	}
+	go func() {
+		if err := metrics.Start(); err != nil {
+			logger.Errorf("Failed to start metrics reporting: %s", err)
+		}
+	}()
+}
+
+func newOperationsSystem(conf *config.TopLevel) *operations.System {
+	return operations.NewSystem(operations.Options{
+		ListenAddress: conf.Operations.ListenAddress,
+		TLS: operations.TLS{
+			Enabled:            conf.Operations.TLS.Enabled,
+			CertFile:           conf.Operations.TLS.Certificate,
+			KeyFile:            conf.Operations.TLS.PrivateKey,
+			ClientCertRequired: conf.Operations.TLS.ClientAuthRequired,
+			ClientRootCAs:      conf.Operations.TLS.ClientRootCAs,
+		},
+	})
+}
+
 // 初始化TLS认证的安全配置
 func initializeServerConfig(conf *config.TopLevel) comm.ServerConfig {
 	// secure server config
@@ -224,6 +278,9 @@ func initializeBootstrapChannel(conf *config.TopLevel, lf blockledger.Factory) {
 		genesisBlock = encoder.New(genesisconfig.Load(conf.General.GenesisProfile)).GenesisBlockForChannel(conf.General.SystemChannel)
 	case "file":
 		genesisBlock = file.New(conf.General.GenesisFile).GenesisBlock()
+	case "none":
+		logger.Info("Not bootstrapping because the genesis method is none, starting without a system channel")
+		return
 	default:
 		logger.Panic("Unknown genesis method:", conf.General.GenesisMethod)
 	}
@@ -272,9 +329,14 @@ func initializeLocalMsp(conf *config.TopLevel) {
 	}
 }
 
-func InitializeMultichannelRegistrar(conf *config.TopLevel, signer crypto.LocalSigner,
-	callbacks ...func(bundle *channelconfig.Bundle)) *multichannel.Registrar {
-	lf, _ := createLedgerFactory(conf)
+// healthChecker registers the health checks of the components of the orderer
+type healthChecker interface {
+	RegisterChecker(component string, checker healthz.HealthChecker) error
+}
+
+func InitializeMultichannelRegistrar(conf *config.TopLevel, signer crypto.LocalSigner, srv comm.GRPCServer,
+	healthChecker healthChecker, callbacks ...func(bundle *channelconfig.Bundle)) *multichannel.Registrar {
+	lf, ld := createLedgerFactory(conf)
 	// Are we bootstrapping?
 	if len(lf.ChainIDs()) == 0 {
 		// 初始化 Bootstrap channel
@@ -285,7 +347,12 @@ func InitializeMultichannelRegistrar(conf *config.TopLevel, signer crypto.LocalS
 
 	consenters := make(map[string]consensus.Consenter)
 	consenters["solo"] = solo.New()
-	consenters["kafka"] = kafka.New(conf.Kafka)
+	consenters["kafka"] = kafka.New(conf.Kafka, healthChecker)
+	raftConsenter, err := raft.New(conf, ld, srv)
+	if err != nil {
+		logger.Panicf("Failed to create raft consenter: %s", err)
+	}
+	consenters[raft.ConsensusType] = raftConsenter
 
 	// 创建多channel 注册管理器对象
 	return multichannel.NewRegistrar(lf, consenters, signer, callbacks...)
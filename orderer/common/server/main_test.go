@@ -155,6 +155,7 @@ func TestInitializeBootstrapChannel(t *testing.T) {
 		{"provisional", "ram", false},
 		{"provisional", "file", false},
 		{"provisional", "json", false},
+		{"none", "ram", false},
 		{"invalid", "ram", true},
 		{"file", "ram", true},
 	}
@@ -237,7 +238,7 @@ func TestInitializeMultiChainManager(t *testing.T) {
 	conf := genesisConfig(t)
 	assert.NotPanics(t, func() {
 		initializeLocalMsp(conf)
-		initializeMultichannelRegistrar(conf, localmsp.NewSigner())
+		InitializeMultichannelRegistrar(conf, localmsp.NewSigner(), nil, nil)
 	})
 }
 
@@ -296,7 +297,7 @@ func TestUpdateTrustedRoots(t *testing.T) {
 			updateTrustedRoots(grpcServer, caSupport, bundle)
 		}
 	}
-	initializeMultichannelRegistrar(genesisConfig(t), localmsp.NewSigner(), callback)
+	InitializeMultichannelRegistrar(genesisConfig(t), localmsp.NewSigner(), grpcServer, nil, callback)
 	t.Logf("# app CAs: %d", len(caSupport.AppRootCAsByChain[genesisconfig.TestChainID]))
 	t.Logf("# orderer CAs: %d", len(caSupport.OrdererRootCAsByChain[genesisconfig.TestChainID]))
 	// mutual TLS not required so no updates should have occurred
@@ -327,7 +328,7 @@ func TestUpdateTrustedRoots(t *testing.T) {
 			updateTrustedRoots(grpcServer, caSupport, bundle)
 		}
 	}
-	initializeMultichannelRegistrar(genesisConfig(t), localmsp.NewSigner(), callback)
+	InitializeMultichannelRegistrar(genesisConfig(t), localmsp.NewSigner(), grpcServer, nil, callback)
 	t.Logf("# app CAs: %d", len(caSupport.AppRootCAsByChain[genesisconfig.TestChainID]))
 	t.Logf("# orderer CAs: %d", len(caSupport.OrdererRootCAsByChain[genesisconfig.TestChainID]))
 	// mutual TLS is required so updates should have occurred
@@ -68,7 +68,7 @@ func (*deliverHandlerSupport) CreateBlockReply(block *cb.Block) proto.Message {
 }
 
 // NewServer creates an ab.AtomicBroadcastServer based on the broadcast target and ledger Reader
-func NewServer(r *multichannel.Registrar, _ crypto.LocalSigner, debug *localconfig.Debug, timeWindow time.Duration, mutualTLS bool) ab.AtomicBroadcastServer {
+func NewServer(r *multichannel.Registrar, _ crypto.LocalSigner, debug *localconfig.Debug, timeWindow time.Duration, mutualTLS bool, rateLimit *localconfig.RateLimit) ab.AtomicBroadcastServer {
 	// 创建 orderer 排序服务器
 	s := &server{
 		dh: deliver.NewHandlerImpl(	// Deliver 服务处理句柄
@@ -78,13 +78,28 @@ func NewServer(r *multichannel.Registrar, _ crypto.LocalSigner, debug *localconf
 		bh: broadcast.NewHandlerImpl(	// Broadcast 服务处理句柄
 			broadcastSupport{
 				Registrar: r,
-			}),
+			},
+			newRateLimitRule(rateLimit)),
 		debug: debug, // 调试信息
 		Registrar: r,	// 多通道注册管理器
 	}
 	return s
 }
 
+// newRateLimitRule creates the rate limit rule applied to Broadcast, or
+// returns nil if rate limiting is disabled
+func newRateLimitRule(rateLimit *localconfig.RateLimit) msgprocessor.Rule {
+	if rateLimit == nil || !rateLimit.Enabled {
+		return nil
+	}
+	logger.Infof("Broadcast rate limits: channel %+v, organization %+v, client %+v", rateLimit.Channel, rateLimit.Organization, rateLimit.Client)
+	return msgprocessor.NewRateLimitRule(msgprocessor.RateLimits{
+		Channel:      msgprocessor.TokenBucketLimit(rateLimit.Channel),
+		Organization: msgprocessor.TokenBucketLimit(rateLimit.Organization),
+		Client:       msgprocessor.TokenBucketLimit(rateLimit.Client),
+	})
+}
+
 type msgTracer struct {
 	function string
 	debug    *localconfig.Debug
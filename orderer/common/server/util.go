@@ -10,6 +10,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/hyperledger/fabric/common/ledger/blkstorage/fsblkstorage"
 	"github.com/hyperledger/fabric/common/ledger/blockledger"
@@ -29,7 +30,10 @@ func createLedgerFactory(conf *config.TopLevel) (blockledger.Factory, string) {
 			ld = createTempDir(conf.FileLedger.Prefix)
 		}
 		logger.Debug("Ledger dir:", ld)
-		lf = fileledger.New(ld) // 工厂模式创建 file lf
+		lf = fileledger.NewWithRetention(ld, fileledger.RetentionPolicy{ // 工厂模式创建 file lf
+			MaxBlocks: conf.FileLedger.Retention.Blocks,
+			MaxAge:    time.Duration(conf.FileLedger.Retention.Days) * 24 * time.Hour,
+		})
 		// The file-based ledger stores the blocks for each channel
 		// in a fsblkstorage.ChainsDir sub-directory that we have
 		// to create separately. Otherwise the call to the ledger
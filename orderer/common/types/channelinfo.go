@@ -0,0 +1,59 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+// Package types holds the types exchanged between the orderer components
+// serving the channel participation API.
+package types
+
+import "github.com/pkg/errors"
+
+// Status of a channel served by the orderer
+const (
+	// StatusActive means that the chain of the channel is running
+	StatusActive = "active"
+	// StatusInactive means that the chain of the channel reported an error,
+	// e.g. the consensus service is unreachable
+	StatusInactive = "inactive"
+)
+
+var (
+	// ErrSystemChannelExists is returned when the channels of the orderer are
+	// managed through a system channel, which excludes joining or removing
+	// channels directly.
+	ErrSystemChannelExists = errors.New("system channel exists")
+
+	// ErrChannelAlreadyExists is returned when joining a channel the orderer
+	// already serves.
+	ErrChannelAlreadyExists = errors.New("channel already exists")
+
+	// ErrChannelNotExist is returned when the channel is not served by the
+	// orderer.
+	ErrChannelNotExist = errors.New("channel does not exist")
+)
+
+// ChannelList is the list of the channels served by the orderer
+type ChannelList struct {
+	// SystemChannel is nil if the orderer runs without a system channel
+	SystemChannel *ChannelInfoShort  `json:"systemChannel"`
+	Channels      []ChannelInfoShort `json:"channels"`
+}
+
+// ChannelInfoShort identifies a channel in a ChannelList
+type ChannelInfoShort struct {
+	Name string `json:"name"`
+	// URL is the location of the ChannelInfo of the channel
+	URL string `json:"url"`
+}
+
+// ChannelInfo describes a channel served by the orderer
+type ChannelInfo struct {
+	Name string `json:"name"`
+	// URL is the location of the ChannelInfo of the channel
+	URL           string `json:"url"`
+	ConsensusType string `json:"consensusType"`
+	Status        string `json:"status"`
+	Height        uint64 `json:"height"`
+}
@@ -26,6 +26,30 @@ type Consenter interface {
 	HandleChain(support ConsenterSupport, metadata *cb.Metadata) (Chain, error)
 }
 
+// ChainRemover is implemented by the consenters which keep state of their
+// chains outside of the ledger. RemoveChain is invoked once the chain has
+// been halted, when the orderer leaves the channel, and must delete that state
+// so that the orderer can join the channel again later.
+type ChainRemover interface {
+	RemoveChain(chainID string) error
+}
+
+// HistoryReader is implemented by the consenters whose chains may read any
+// past block of the ledger, such as to bring a lagging consenter up to date.
+// When ReadsHistory returns true, the retention policy of the ledger is not
+// enforced on the channels of the consenter.
+type HistoryReader interface {
+	ReadsHistory() bool
+}
+
+// MetadataValidator is implemented by the consenters which can check their
+// consensus metadata. ValidateConsensusMetadata is invoked when a config
+// update migrates a channel to the consensus type of the consenter, so that
+// the update is rejected before the chain is restarted under the consenter.
+type MetadataValidator interface {
+	ValidateConsensusMetadata(metadata []byte) error
+}
+
 // Chain defines a way to inject messages for ordering.
 // Note, that in order to allow flexibility in the implementation, it is the responsibility of the implementer
 // to take the ordered messages, send them through the blockcutter.Receiver supplied via HandleChain to cut blocks,
@@ -104,4 +128,8 @@ type ConsenterSupport interface {
 
 	// Height returns the number of blocks in the chain this channel is associated with.
 	Height() uint64	// 返回关联的区块链结构高度
+
+	// Block returns a block with the given number,
+	// or nil if such a block doesn't exist.
+	Block(number uint64) *cb.Block
 }
@@ -7,12 +7,15 @@ SPDX-License-Identifier: Apache-2.0
 package kafka
 
 import (
+	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/Shopify/sarama"
 	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	localconfig "github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/common/msgprocessor"
 	"github.com/hyperledger/fabric/orderer/consensus"
@@ -71,6 +74,7 @@ func newChain(
 		lastOriginalOffsetProcessed: lastOriginalOffsetProcessed,
 		lastResubmittedConfigOffset: lastResubmittedConfigOffset,
 		lastCutBlockNumber:          lastCutBlockNumber,
+		lastEmptyOffset:             lastOffsetPersisted,
 
 		errorChan:                   errorChan,
 		haltChan:                    make(chan struct{}),
@@ -88,6 +92,9 @@ type chainImpl struct {
 	lastOriginalOffsetProcessed int64
 	lastResubmittedConfigOffset int64
 	lastCutBlockNumber          uint64
+	// lastEmptyOffset is the offset of the last message after which the
+	// block cutter had no pending message
+	lastEmptyOffset int64
 
 	producer        sarama.SyncProducer
 	parentConsumer  sarama.Consumer
@@ -111,6 +118,26 @@ type chainImpl struct {
 	timer <-chan time.Time
 }
 
+// HealthCheck checks that at least one of the Kafka brokers of the channel is
+// reachable. It is registered with the operations endpoint of the orderer.
+func (chain *chainImpl) HealthCheck(ctx context.Context) error {
+	var errs []string
+	for _, address := range chain.SharedConfig().KafkaBrokers() {
+		broker := sarama.NewBroker(address)
+		err := broker.Open(chain.consenter.brokerConfig())
+		if err == nil {
+			_, err = broker.Connected()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %s", address, err))
+			continue
+		}
+		broker.Close()
+		return nil
+	}
+	return fmt.Errorf("[channel: %s] no Kafka broker is reachable: [%s]", chain.ChainID(), strings.Join(errs, ", "))
+}
+
 // Errored returns a channel which will close when a partition consumer error
 // has occurred. Checked by Deliver().
 func (chain *chainImpl) Errored() <-chan struct{} {
@@ -491,6 +518,29 @@ func getLastCutBlockNumber(blockchainHeight uint64) uint64 {
 	return blockchainHeight - 1
 }
 
+// getResumeBlock returns the number and the orderer metadata of the first of
+// the last blocks sharing the same `LastOffsetPersisted`. When the block
+// cutter keeps messages pending while cutting later ones, that block is the
+// last one cut when the block cutter had no pending message, and the blocks
+// after it are cut again as the messages are replayed from that offset.
+func getResumeBlock(support consensus.ConsenterSupport, metadata *cb.Metadata) (uint64, *cb.Metadata) {
+	number := getLastCutBlockNumber(support.Height())
+	lastOffsetPersisted, _, _ := getOffsets(metadata.Value, support.ChainID())
+	for number > 0 {
+		previous, err := utils.GetMetadataFromBlock(support.Block(number-1), cb.BlockMetadataIndex_ORDERER)
+		if err != nil {
+			logger.Panicf("[channel: %s] Ledger may be corrupted:"+
+				"cannot unmarshal orderer metadata in block %d", support.ChainID(), number-1)
+		}
+		if offset, _, _ := getOffsets(previous.Value, support.ChainID()); offset != lastOffsetPersisted {
+			break
+		}
+		number--
+		metadata = previous
+	}
+	return number, metadata
+}
+
 func getOffsets(metadataValue []byte, chainID string) (persisted int64, processed int64, resubmitted int64) {
 	if metadataValue != nil {
 		// Extract orderer-related metadata from the tip of the ledger first
@@ -590,45 +640,35 @@ func (chain *chainImpl) processRegular(regularMessage *ab.KafkaMessageRegular, r
 
 		chain.timer = nil
 
-		offset := receivedOffset
-		if pending || len(batches) == 2 {
-			// If the newest envelope is not encapsulated into the first batch,
-			// the `LastOffsetPersisted` should be `receivedOffset` - 1.
-			offset--
-		} else {
-			// We are just cutting exactly one block, so it is safe to update
-			// `lastOriginalOffsetProcessed` with `newOffset` here, and then
-			// encapsulate it into this block. Otherwise, if we are cutting two
-			// blocks, the first one should use current `lastOriginalOffsetProcessed`
-			// and the second one should use `newOffset`, which is also used to
-			// update `lastOriginalOffsetProcessed`
-			chain.lastOriginalOffsetProcessed = newOffset
+		// When the block cutter keeps messages pending while cutting a batch
+		// of messages ordered after them, the chain can only resume from an
+		// offset after which no message was pending. Otherwise, if the newest
+		// envelope is not encapsulated into a batch, or is only encapsulated
+		// into the last of them, the `LastOffsetPersisted` of the batches
+		// preceding it should be `receivedOffset` - 1.
+		resumeOffset := receivedOffset - 1
+		if _, ok := chain.BlockCutter().(blockcutter.BacklogReceiver); ok {
+			resumeOffset = chain.lastEmptyOffset
 		}
 
-		// Commit the first block
-		block := chain.CreateNextBlock(batches[0])
-		metadata := utils.MarshalOrPanic(&ab.KafkaMetadata{
-			LastOffsetPersisted:         offset,
-			LastOriginalOffsetProcessed: chain.lastOriginalOffsetProcessed,
-			LastResubmittedConfigOffset: chain.lastResubmittedConfigOffset,
-		})
-		chain.WriteBlock(block, metadata)
-		chain.lastCutBlockNumber++
-		logger.Debugf("[channel: %s] Batch filled, just cut block %d - last persisted offset is now %d", chain.ChainID(), chain.lastCutBlockNumber, offset)
-
-		// Commit the second block if exists
-		if len(batches) == 2 {
-			chain.lastOriginalOffsetProcessed = newOffset
-			offset++
+		for i, batch := range batches {
+			offset := resumeOffset
+			if i == len(batches)-1 && !pending {
+				// It is safe to update `lastOriginalOffsetProcessed` with
+				// `newOffset` and encapsulate it into the last block only
+				// once the newest envelope has been cut, the blocks
+				// before it use the current `lastOriginalOffsetProcessed`
+				offset = receivedOffset
+				chain.lastOriginalOffsetProcessed = newOffset
+				chain.lastEmptyOffset = receivedOffset
+			}
 
-			block := chain.CreateNextBlock(batches[1])
 			metadata := utils.MarshalOrPanic(&ab.KafkaMetadata{
 				LastOffsetPersisted:         offset,
-				LastOriginalOffsetProcessed: newOffset,
+				LastOriginalOffsetProcessed: chain.lastOriginalOffsetProcessed,
 				LastResubmittedConfigOffset: chain.lastResubmittedConfigOffset,
 			})
-			chain.WriteBlock(block, metadata)
-			chain.lastCutBlockNumber++
+			chain.writeBlock(batch, metadata)
 			logger.Debugf("[channel: %s] Batch filled, just cut block %d - last persisted offset is now %d", chain.ChainID(), chain.lastCutBlockNumber, offset)
 		}
 	}
@@ -658,6 +698,7 @@ func (chain *chainImpl) processRegular(regularMessage *ab.KafkaMessageRegular, r
 
 		logger.Debugf("[channel: %s] Creating isolated block for config message", chain.ChainID())
 		chain.lastOriginalOffsetProcessed = newOffset
+		chain.lastEmptyOffset = receivedOffset
 		block := chain.CreateNextBlock([]*cb.Envelope{message})
 		metadata := utils.MarshalOrPanic(&ab.KafkaMetadata{
 			LastOffsetPersisted:         receivedOffset,
@@ -867,6 +908,19 @@ func (chain *chainImpl) processRegular(regularMessage *ab.KafkaMessageRegular, r
 	return nil
 }
 
+// writeBlock cuts the batch into the next block. When the block was already
+// written before the chain restarted, and its messages are replayed from the
+// offset the chain resumed from, it is only counted.
+func (chain *chainImpl) writeBlock(batch []*cb.Envelope, encodedMetadataValue []byte) {
+	chain.lastCutBlockNumber++
+	if chain.lastCutBlockNumber < chain.Height() {
+		logger.Debugf("[channel: %s] Block %d was already written, skipping it", chain.ChainID(), chain.lastCutBlockNumber)
+		return
+	}
+	block := chain.CreateNextBlock(batch)
+	chain.WriteBlock(block, encodedMetadataValue)
+}
+
 func (chain *chainImpl) processTimeToCut(ttcMessage *ab.KafkaMessageTimeToCut, receivedOffset int64) error {
 	ttcNumber := ttcMessage.GetBlockNumber()
 	logger.Debugf("[channel: %s] It's a time-to-cut message for block %d", chain.ChainID(), ttcNumber)
@@ -878,13 +932,12 @@ func (chain *chainImpl) processTimeToCut(ttcMessage *ab.KafkaMessageTimeToCut, r
 			return fmt.Errorf("got right time-to-cut message (for block %d),"+
 				" no pending requests though; this might indicate a bug", chain.lastCutBlockNumber+1)
 		}
-		block := chain.CreateNextBlock(batch)
 		metadata := utils.MarshalOrPanic(&ab.KafkaMetadata{
 			LastOffsetPersisted:         receivedOffset,
 			LastOriginalOffsetProcessed: chain.lastOriginalOffsetProcessed,
 		})
-		chain.WriteBlock(block, metadata)
-		chain.lastCutBlockNumber++
+		chain.writeBlock(batch, metadata)
+		chain.lastEmptyOffset = receivedOffset
 		logger.Debugf("[channel: %s] Proper time-to-cut received, just cut block %d", chain.ChainID(), chain.lastCutBlockNumber)
 		return nil
 	} else if ttcNumber > chain.lastCutBlockNumber+1 {
@@ -7,6 +7,7 @@ SPDX-License-Identifier: Apache-2.0
 package kafka
 
 import (
+	"context"
 	"fmt"
 	"testing"
 	"time"
@@ -446,6 +447,39 @@ func TestChain(t *testing.T) {
 	})
 }
 
+func TestHealthCheck(t *testing.T) {
+	mockChannel := newChannel(channelNameForTest(t), defaultPartition)
+	mockBroker := sarama.NewMockBroker(t, 0)
+	defer mockBroker.Close()
+
+	unreachableBroker := sarama.NewMockBroker(t, 1)
+	unreachableAddr := unreachableBroker.Addr()
+	unreachableBroker.Close()
+
+	newChainWithBrokers := func(brokers ...string) *chainImpl {
+		mockSupport := &mockmultichannel.ConsenterSupport{
+			ChainIDVal:      mockChannel.topic(),
+			HeightVal:       uint64(3),
+			SharedConfigVal: &mockconfig.Orderer{KafkaBrokersVal: brokers},
+		}
+		chain, err := newChain(mockConsenter, mockSupport, int64(0), int64(0), int64(0))
+		assert.NoError(t, err)
+		return chain
+	}
+
+	t.Run("Reachable", func(t *testing.T) {
+		chain := newChainWithBrokers(unreachableAddr, mockBroker.Addr())
+		assert.NoError(t, chain.HealthCheck(context.Background()))
+	})
+
+	t.Run("Unreachable", func(t *testing.T) {
+		chain := newChainWithBrokers(unreachableAddr)
+		err := chain.HealthCheck(context.Background())
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), fmt.Sprintf("[channel: %s] no Kafka broker is reachable: [%s: ", mockChannel.topic(), unreachableAddr))
+	})
+}
+
 func TestSetupProducerForChannel(t *testing.T) {
 	if testing.Short() {
 		t.Skip("Skipping test in short mode")
@@ -678,6 +712,67 @@ func TestGetLastOffsetPersisted(t *testing.T) {
 	}
 }
 
+func TestGetResumeBlock(t *testing.T) {
+	// newLedger returns a ledger whose blocks after the genesis block persist
+	// the given offsets, and the orderer metadata of its last block
+	newLedger := func(persisted ...int64) (*mockmultichannel.ConsenterSupport, *cb.Metadata) {
+		support := &mockmultichannel.ConsenterSupport{
+			ChainIDVal:   channelNameForTest(t),
+			HeightVal:    uint64(len(persisted) + 1),
+			BlockByIndex: map[uint64]*cb.Block{0: cb.NewBlock(0, nil)},
+		}
+		metadata := &cb.Metadata{}
+		for i, offset := range persisted {
+			metadata = &cb.Metadata{Value: utils.MarshalOrPanic(&ab.KafkaMetadata{
+				LastOffsetPersisted:         offset,
+				LastOriginalOffsetProcessed: int64(i),
+			})}
+			block := cb.NewBlock(uint64(i+1), nil)
+			block.Metadata.Metadata[cb.BlockMetadataIndex_ORDERER] = utils.MarshalOrPanic(metadata)
+			support.BlockByIndex[uint64(i+1)] = block
+		}
+		return support, metadata
+	}
+
+	testCases := []struct {
+		name              string
+		persisted         []int64
+		expectedNumber    uint64
+		expectedProcessed int64
+	}{
+		{"LastBlock", []int64{3, 7}, 2, 1},
+		{"Backlog", []int64{3, 7, 7, 7}, 2, 1},
+		{"Genesis", []int64{sarama.OffsetOldest - 1, sarama.OffsetOldest - 1}, 0, 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			support, metadata := newLedger(tc.persisted...)
+			number, metadata := getResumeBlock(support, metadata)
+			assert.Equal(t, tc.expectedNumber, number)
+			persisted, processed, _ := getOffsets(metadata.Value, support.ChainID())
+			assert.Equal(t, tc.persisted[len(tc.persisted)-1], persisted)
+			assert.Equal(t, tc.expectedProcessed, processed)
+		})
+	}
+}
+
+func TestWriteBlockReplayed(t *testing.T) {
+	support := &mockmultichannel.ConsenterSupport{
+		ChainIDVal: channelNameForTest(t),
+		HeightVal:  3,
+		Blocks:     make(chan *cb.Block, 1),
+	}
+	chain := &chainImpl{ConsenterSupport: support, lastCutBlockNumber: 1}
+
+	chain.writeBlock([]*cb.Envelope{{Payload: []byte("replayed")}}, nil)
+	assert.Equal(t, uint64(2), chain.lastCutBlockNumber)
+	assert.Empty(t, support.Blocks, "Should not have written the block the ledger already has")
+
+	chain.writeBlock([]*cb.Envelope{{Payload: []byte("new")}}, nil)
+	assert.Equal(t, uint64(3), chain.lastCutBlockNumber)
+	assert.Len(t, support.Blocks, 1, "Should have written the block following the ledger")
+}
+
 func TestSendConnectMessage(t *testing.T) {
 	mockBroker := sarama.NewMockBroker(t, 0)
 	defer func() { mockBroker.Close() }()
@@ -3152,7 +3247,7 @@ func TestDeliverSession(t *testing.T) {
 		defer env.broker2.Close()
 
 		// initialize consenter
-		consenter := New(mockLocalConfig.Kafka)
+		consenter := New(mockLocalConfig.Kafka, nil)
 
 		// initialize chain
 		metadata := &cb.Metadata{Value: utils.MarshalOrPanic(&ab.KafkaMetadata{LastOffsetPersisted: env.height})}
@@ -3241,7 +3336,7 @@ func TestDeliverSession(t *testing.T) {
 		defer env.broker0.Close()
 
 		// initialize consenter
-		consenter := New(mockLocalConfig.Kafka)
+		consenter := New(mockLocalConfig.Kafka, nil)
 
 		// initialize chain
 		metadata := &cb.Metadata{Value: utils.MarshalOrPanic(&ab.KafkaMetadata{LastOffsetPersisted: env.height})}
@@ -3303,7 +3398,7 @@ func TestDeliverSession(t *testing.T) {
 		defer env.broker0.Close()
 
 		// initialize consenter
-		consenter := New(mockLocalConfig.Kafka)
+		consenter := New(mockLocalConfig.Kafka, nil)
 
 		// initialize chain
 		metadata := &cb.Metadata{Value: utils.MarshalOrPanic(&ab.KafkaMetadata{LastOffsetPersisted: env.height})}
@@ -3453,3 +3548,8 @@ func (c *mockConsenterSupport) Height() uint64 {
 	args := c.Called()
 	return args.Get(0).(uint64)
 }
+
+func (c *mockConsenterSupport) Block(number uint64) *cb.Block {
+	args := c.Called(number)
+	return args.Get(0).(*cb.Block)
+}
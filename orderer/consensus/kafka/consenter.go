@@ -8,14 +8,24 @@ package kafka
 
 import (
 	"github.com/Shopify/sarama"
+	"github.com/hyperledger/fabric/common/healthz"
+	"github.com/hyperledger/fabric/orderer/common/blockcutter"
 	localconfig "github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/consensus"
 	cb "github.com/hyperledger/fabric/protos/common"
 	logging "github.com/op/go-logging"
 )
 
+// healthChecker registers the health checks of the chains with the
+// operations endpoint of the orderer
+type healthChecker interface {
+	RegisterChecker(component string, checker healthz.HealthChecker) error
+}
+
 // New creates a Kafka-based consenter. Called by orderer's main.go.
-func New(config localconfig.Kafka) consensus.Consenter {
+// The health checks of the chains are registered with the healthChecker,
+// if it is not nil.
+func New(config localconfig.Kafka, healthChecker healthChecker) consensus.Consenter {
 	if config.Verbose {
 		logging.SetLevel(logging.DEBUG, saramaLogID)
 	}
@@ -25,6 +35,7 @@ func New(config localconfig.Kafka) consensus.Consenter {
 		tlsConfigVal:    config.TLS,
 		retryOptionsVal: config.Retry,
 		kafkaVersionVal: config.Version,
+		healthChecker:   healthChecker,
 	}
 }
 
@@ -36,6 +47,7 @@ type consenterImpl struct {
 	tlsConfigVal    localconfig.TLS
 	retryOptionsVal localconfig.Retry
 	kafkaVersionVal sarama.KafkaVersion
+	healthChecker   healthChecker
 }
 
 // HandleChain creates/returns a reference to a consensus.Chain object for the
@@ -44,8 +56,32 @@ type consenterImpl struct {
 // multichannel.NewManagerImpl() when ranging over the ledgerFactory's
 // existingChains.
 func (consenter *consenterImpl) HandleChain(support consensus.ConsenterSupport, metadata *cb.Metadata) (consensus.Chain, error) {
+	lastCutBlockNumber := getLastCutBlockNumber(support.Height())
+	if _, ok := support.BlockCutter().(blockcutter.BacklogReceiver); ok {
+		lastCutBlockNumber, metadata = getResumeBlock(support, metadata)
+	}
 	lastOffsetPersisted, lastOriginalOffsetProcessed, lastResubmittedConfigOffset := getOffsets(metadata.Value, support.ChainID())
-	return newChain(consenter, support, lastOffsetPersisted, lastOriginalOffsetProcessed, lastResubmittedConfigOffset)
+	chain, err := newChain(consenter, support, lastOffsetPersisted, lastOriginalOffsetProcessed, lastResubmittedConfigOffset)
+	if err != nil {
+		return nil, err
+	}
+	if lastCutBlockNumber != chain.lastCutBlockNumber {
+		logger.Infof("[channel: %s] Resuming from block %d, the blocks after it are cut again from the messages after offset %d",
+			support.ChainID(), lastCutBlockNumber, lastOffsetPersisted)
+		chain.lastCutBlockNumber = lastCutBlockNumber
+	}
+	if consenter.healthChecker != nil {
+		if err := consenter.healthChecker.RegisterChecker(healthCheckComponent(support.ChainID()), chain); err != nil {
+			logger.Warningf("[channel: %s] Failed registering health check: %s", support.ChainID(), err)
+		}
+	}
+	return chain, nil
+}
+
+// healthCheckComponent returns the name the health check
+// of the chain of the given channel is registered with
+func healthCheckComponent(chainID string) string {
+	return "kafka/" + chainID
 }
 
 // commonConsenter allows us to retrieve the configuration options set on the
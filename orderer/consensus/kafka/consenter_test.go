@@ -15,6 +15,7 @@ import (
 	"github.com/Shopify/sarama"
 	"github.com/golang/protobuf/proto"
 	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/healthz"
 	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
 	localconfig "github.com/hyperledger/fabric/orderer/common/localconfig"
 	"github.com/hyperledger/fabric/orderer/consensus"
@@ -56,11 +57,11 @@ func init() {
 }
 
 func TestNew(t *testing.T) {
-	_ = consensus.Consenter(New(mockLocalConfig.Kafka))
+	_ = consensus.Consenter(New(mockLocalConfig.Kafka, nil))
 }
 
 func TestHandleChain(t *testing.T) {
-	consenter := consensus.Consenter(New(mockLocalConfig.Kafka))
+	consenter := consensus.Consenter(New(mockLocalConfig.Kafka, nil))
 
 	oldestOffset := int64(0)
 	newestOffset := int64(5)
@@ -93,6 +94,31 @@ func TestHandleChain(t *testing.T) {
 
 	_, err := consenter.HandleChain(mockSupport, mockMetadata)
 	assert.NoError(t, err, "Expected the HandleChain call to return without errors")
+
+	// The health check of the chain is registered with the health checker
+	checker := &mockHealthChecker{checkers: make(map[string]healthz.HealthChecker)}
+	consenter = consensus.Consenter(New(mockLocalConfig.Kafka, checker))
+	chain, err := consenter.HandleChain(mockSupport, mockMetadata)
+	assert.NoError(t, err, "Expected the HandleChain call to return without errors")
+	assert.Equal(t, chain, checker.checkers["kafka/"+mockChannel.topic()])
+
+	// A failure to register the health check doesn't fail the chain
+	checker.err = fmt.Errorf("already registered")
+	_, err = consenter.HandleChain(mockSupport, mockMetadata)
+	assert.NoError(t, err, "Expected the HandleChain call to return without errors")
+}
+
+type mockHealthChecker struct {
+	checkers map[string]healthz.HealthChecker
+	err      error
+}
+
+func (c *mockHealthChecker) RegisterChecker(component string, checker healthz.HealthChecker) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.checkers[component] = checker
+	return nil
 }
 
 // Test helper functions and mock objects defined here
@@ -0,0 +1,784 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package raft
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/coreos/etcd/raft"
+	"github.com/coreos/etcd/raft/raftpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/common/util"
+	"github.com/hyperledger/fabric/orderer/consensus"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/op/go-logging"
+	"github.com/pkg/errors"
+)
+
+const pkgLogID = "orderer/consensus/raft"
+
+var logger *logging.Logger
+
+func init() {
+	logger = flogging.MustGetLogger(pkgLogID)
+}
+
+// RPC is used by a chain to communicate with the other consenters of its channel.
+type RPC interface {
+	// SendConsensus sends a consensus message to the given consenter,
+	// without waiting for it to be processed.
+	SendConsensus(dest uint64, msg *ab.ConsensusRequest) error
+
+	// SendSubmit forwards a transaction to the given consenter.
+	SendSubmit(dest uint64, req *ab.SubmitRequest) (*ab.SubmitResponse, error)
+
+	// Pull retrieves a block from the given consenter.
+	Pull(dest uint64, req *ab.PullRequest) (*ab.PullResponse, error)
+
+	// Configure sets the consenters this RPC communicates with.
+	Configure(consenters map[uint64]*ab.RaftConsenter)
+}
+
+// Options contains the configuration of a chain.
+type Options struct {
+	RaftID        uint64
+	WALDir        string
+	SnapDir       string
+	TickInterval  time.Duration
+	ElectionTick  int
+	HeartbeatTick int
+	// MaxEntriesPerMsg bounds the size of the append messages to as many
+	// blocks of the maximum size allowed by the batch size of the channel.
+	MaxEntriesPerMsg uint64
+	SnapshotInterval uint64
+
+	// RaftMetadata is the Raft metadata of the last block of the chain.
+	RaftMetadata *ab.RaftBlockMetadata
+	// Join is set when the last block of the chain was written by Raft, in
+	// which case a node without any persisted state joins the existing Raft
+	// cluster rather than bootstrapping a new one.
+	Join bool
+	// Consenters maps the IDs of the consenters to their definitions.
+	Consenters map[uint64]*ab.RaftConsenter
+}
+
+type submission struct {
+	req     *ab.SubmitRequest
+	resultC chan submitResult
+}
+
+// submitResult holds either the error of a submission, or the ID of the
+// leader to forward it to. Both are empty when the submission was ordered.
+type submitResult struct {
+	leader uint64
+	err    error
+}
+
+// Chain implements consensus.Chain on top of the etcd Raft library. The
+// leader cuts the blocks and replicates them to the followers through the
+// Raft log, and every consenter writes the blocks to its ledger once they
+// are committed.
+type Chain struct {
+	raftID    uint64
+	channelID string
+	support   consensus.ConsenterSupport
+	opts      Options
+	rpc       RPC
+	storage   *storage
+	node      *node
+
+	submitC  chan *submission
+	haltC    chan struct{}
+	doneC    chan struct{}
+	haltOnce sync.Once
+
+	// ctx is cancelled when the chain is halted, to abort the pending calls
+	// to the raft.Node.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	consentersLock sync.RWMutex
+	consenters     map[uint64]*ab.RaftConsenter
+
+	// leaderID is the ID of the current leader, accessed atomically.
+	leaderID uint64
+
+	// The fields below are only accessed by the serving goroutine.
+	raftMetadata      *ab.RaftBlockMetadata
+	confState         raftpb.ConfState
+	appliedIndex      uint64
+	lastBlock         *cb.Block
+	lastSnapshotBlock uint64
+	pendingSnapshot   *raftpb.Snapshot
+	lead              uint64
+	readyIndex        uint64
+	creator           *blockCreator
+	configInflight    bool
+	batchTimer        <-chan time.Time
+	removed           bool
+}
+
+// NewChain creates a Raft chain for the given channel, recovering its state
+// from the WAL and snapshots of a previous run if there are any.
+func NewChain(support consensus.ConsenterSupport, opts Options, rpc RPC) (*Chain, error) {
+	st, err := openStorage(opts.WALDir, opts.SnapDir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open Raft storage")
+	}
+
+	lastBlock := support.Block(support.Height() - 1)
+	if lastBlock == nil {
+		st.close()
+		return nil, errors.Errorf("failed to retrieve block %d", support.Height()-1)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &Chain{
+		raftID:            opts.RaftID,
+		channelID:         support.ChainID(),
+		support:           support,
+		opts:              opts,
+		rpc:               rpc,
+		storage:           st,
+		submitC:           make(chan *submission),
+		haltC:             make(chan struct{}),
+		doneC:             make(chan struct{}),
+		ctx:               ctx,
+		cancel:            cancel,
+		consenters:        opts.Consenters,
+		raftMetadata:      proto.Clone(opts.RaftMetadata).(*ab.RaftBlockMetadata),
+		lastBlock:         lastBlock,
+		lastSnapshotBlock: lastBlock.Header.Number,
+	}
+
+	if snap, _ := st.ram.Snapshot(); !raft.IsEmptySnap(snap) {
+		snapBlock := &cb.Block{}
+		if err := proto.Unmarshal(snap.Data, snapBlock); err != nil {
+			st.close()
+			return nil, errors.Wrap(err, "failed to unmarshal snapshot block")
+		}
+		if snapBlock.Header.Number > lastBlock.Header.Number {
+			// The snapshot was received from the leader, but the blocks
+			// it covers were not pulled before the chain was stopped.
+			c.pendingSnapshot = &snap
+		} else {
+			c.lastSnapshotBlock = snapBlock.Header.Number
+		}
+		c.confState = snap.Metadata.ConfState
+		c.appliedIndex = snap.Metadata.Index
+	}
+
+	maxSizePerMsg := uint64(math.MaxUint64)
+	if maxBytes := uint64(support.SharedConfig().BatchSize().GetAbsoluteMaxBytes()); maxBytes != 0 && opts.MaxEntriesPerMsg != 0 {
+		maxSizePerMsg = opts.MaxEntriesPerMsg * maxBytes
+	}
+	config := &raft.Config{
+		ID:              opts.RaftID,
+		ElectionTick:    opts.ElectionTick,
+		HeartbeatTick:   opts.HeartbeatTick,
+		Storage:         st.ram,
+		MaxSizePerMsg:   maxSizePerMsg,
+		MaxInflightMsgs: maxInflightMsgs,
+		CheckQuorum:     true,
+		PreVote:         true,
+		// Only the leader creates blocks, so the proposals of a node which
+		// lost the leadership are dropped rather than forwarded.
+		DisableProposalForwarding: true,
+		Logger:                    logger,
+	}
+	// The committed entries are replayed from the last snapshot on restart,
+	// the blocks already in the ledger are skipped by writeBlock.
+	c.node = startNode(c.channelID, config, st, rpc, opts.RaftMetadata.ConsenterIds, opts.Join)
+	c.rpc.Configure(c.remoteConsenters())
+
+	return c, nil
+}
+
+// Start starts the chain.
+func (c *Chain) Start() {
+	go c.serve()
+}
+
+// Halt stops the chain.
+func (c *Chain) Halt() {
+	c.halt()
+}
+
+func (c *Chain) halt() {
+	c.haltOnce.Do(func() {
+		close(c.haltC)
+		c.cancel()
+	})
+}
+
+// WaitReady returns an error if the chain is stopped.
+func (c *Chain) WaitReady() error {
+	select {
+	case <-c.haltC:
+		return errors.New("chain is stopped")
+	default:
+		return nil
+	}
+}
+
+// Errored returns a channel which is closed when the chain is stopped.
+func (c *Chain) Errored() <-chan struct{} {
+	return c.haltC
+}
+
+// Order submits a normal transaction for ordering.
+func (c *Chain) Order(env *cb.Envelope, configSeq uint64) error {
+	return c.submit(&ab.SubmitRequest{Channel: c.channelID, LastValidationSeq: configSeq, Content: env}, true)
+}
+
+// Configure submits a config transaction for ordering.
+func (c *Chain) Configure(env *cb.Envelope, configSeq uint64) error {
+	return c.submit(&ab.SubmitRequest{Channel: c.channelID, LastValidationSeq: configSeq, Content: env}, true)
+}
+
+// submit orders the request if this node is the leader, and otherwise
+// forwards it to the leader if forward is true.
+func (c *Chain) submit(req *ab.SubmitRequest, forward bool) error {
+	s := &submission{req: req, resultC: make(chan submitResult, 1)}
+	select {
+	case c.submitC <- s:
+	case <-c.haltC:
+		return errors.New("chain is stopped")
+	}
+
+	res := <-s.resultC
+	if res.err != nil || res.leader == 0 {
+		return res.err
+	}
+	if !forward {
+		return errors.Errorf("not the leader, the current leader is %d", res.leader)
+	}
+
+	resp, err := c.rpc.SendSubmit(res.leader, req)
+	if err != nil {
+		return errors.Wrapf(err, "failed to forward request to leader %d", res.leader)
+	}
+	if resp.Status != cb.Status_SUCCESS {
+		return errors.Errorf("request rejected by leader %d: %s", res.leader, resp.Info)
+	}
+	return nil
+}
+
+func (c *Chain) onConsensus(sender uint64, req *ab.ConsensusRequest) error {
+	msg := raftpb.Message{}
+	if err := msg.Unmarshal(req.Payload); err != nil {
+		return errors.Wrap(err, "failed to unmarshal Raft message")
+	}
+	if msg.From != sender {
+		return errors.Errorf("message from %d claims to be sent by %d", sender, msg.From)
+	}
+	if err := c.node.Step(c.ctx, msg); err != nil {
+		select {
+		case <-c.haltC:
+			return errors.New("chain is stopped")
+		default:
+			return errors.Wrap(err, "failed to process Raft message")
+		}
+	}
+	return nil
+}
+
+func (c *Chain) onSubmit(sender uint64, req *ab.SubmitRequest) (*ab.SubmitResponse, error) {
+	if err := c.submit(req, false); err != nil {
+		return &ab.SubmitResponse{Channel: c.channelID, Status: cb.Status_SERVICE_UNAVAILABLE, Info: err.Error()}, nil
+	}
+	return &ab.SubmitResponse{Channel: c.channelID, Status: cb.Status_SUCCESS}, nil
+}
+
+func (c *Chain) onPull(sender uint64, req *ab.PullRequest) (*ab.PullResponse, error) {
+	block := c.support.Block(req.BlockNumber)
+	if block == nil {
+		return nil, errors.Errorf("block %d not found", req.BlockNumber)
+	}
+	return &ab.PullResponse{Block: block}, nil
+}
+
+// consenterID returns the ID of the consenter with the given client
+// certificate hash.
+func (c *Chain) consenterID(certHash []byte) (uint64, bool) {
+	c.consentersLock.RLock()
+	defer c.consentersLock.RUnlock()
+	for id, consenter := range c.consenters {
+		if bytes.Equal(util.ComputeSHA256(certDER(consenter.ClientTlsCert)), certHash) {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+func (c *Chain) remoteConsenters() map[uint64]*ab.RaftConsenter {
+	c.consentersLock.RLock()
+	defer c.consentersLock.RUnlock()
+	remotes := make(map[uint64]*ab.RaftConsenter, len(c.consenters))
+	for id, consenter := range c.consenters {
+		if id != c.raftID {
+			remotes[id] = consenter
+		}
+	}
+	return remotes
+}
+
+func (c *Chain) serve() {
+	defer func() {
+		c.node.Stop()
+		c.storage.close()
+		close(c.doneC)
+	}()
+
+	if c.pendingSnapshot != nil {
+		if err := c.catchUp(*c.pendingSnapshot); err != nil {
+			logger.Errorf("[channel: %s] Failed to catch up with snapshot: %s", c.channelID, err)
+			c.halt()
+			return
+		}
+		c.pendingSnapshot = nil
+	}
+
+	ticker := time.NewTicker(c.opts.TickInterval)
+	defer ticker.Stop()
+
+	for {
+		submitC := c.submitC
+		if c.lead == c.raftID && (c.creator == nil || c.configInflight) {
+			// Hold the submissions until the leader has applied the entries
+			// of the previous terms, or until the pending config is applied.
+			submitC = nil
+		}
+
+		select {
+		case s := <-submitC:
+			s.resultC <- c.handleSubmission(s.req)
+		case rd := <-c.node.Ready():
+			if err := c.processReady(rd); err != nil {
+				logger.Errorf("[channel: %s] Halting Raft node %d: %s", c.channelID, c.raftID, err)
+				c.halt()
+				return
+			}
+		case <-ticker.C:
+			c.node.Tick()
+		case <-c.batchTimer:
+			c.batchTimer = nil
+			batch := c.support.BlockCutter().Cut()
+			if len(batch) == 0 {
+				logger.Warningf("[channel: %s] Batch timer expired with no pending requests, this might indicate a bug", c.channelID)
+				break
+			}
+			logger.Debugf("[channel: %s] Batch timer expired, creating block", c.channelID)
+			c.propose(c.creator.createNextBlock(batch))
+		case <-c.haltC:
+			logger.Infof("[channel: %s] Halting Raft node %d", c.channelID, c.raftID)
+			return
+		}
+
+		if c.removed {
+			logger.Warningf("[channel: %s] Raft node %d was removed from the channel, halting", c.channelID, c.raftID)
+			c.halt()
+			return
+		}
+		c.checkLeadership()
+	}
+}
+
+func (c *Chain) handleSubmission(req *ab.SubmitRequest) submitResult {
+	if c.lead != c.raftID {
+		if c.lead == 0 {
+			return submitResult{err: errors.New("no Raft leader")}
+		}
+		return submitResult{leader: c.lead}
+	}
+	return submitResult{err: c.ordered(req)}
+}
+
+// ordered cuts the submitted transaction into blocks and proposes them. It
+// must only be invoked on the leader.
+func (c *Chain) ordered(req *ab.SubmitRequest) error {
+	env := req.Content
+	seq := c.support.Sequence()
+
+	isConfig, err := isConfigEnvelope(env)
+	if err != nil {
+		return errors.Wrap(err, "bad message")
+	}
+
+	if isConfig {
+		if req.LastValidationSeq < seq {
+			env, _, err = c.support.ProcessConfigMsg(env)
+			if err != nil {
+				return errors.Errorf("bad config message: %s", err)
+			}
+		}
+		if err := c.checkConfigUpdate(env); err != nil {
+			return err
+		}
+
+		batch := c.support.BlockCutter().Cut()
+		if batch != nil {
+			c.propose(c.creator.createNextBlock(batch))
+		}
+		c.propose(c.creator.createNextBlock([]*cb.Envelope{env}))
+		c.batchTimer = nil
+		c.configInflight = true
+		return nil
+	}
+
+	if req.LastValidationSeq < seq {
+		if _, err := c.support.ProcessNormalMsg(env); err != nil {
+			return errors.Errorf("bad normal message: %s", err)
+		}
+	}
+	batches, pending := c.support.BlockCutter().Ordered(env)
+	for _, batch := range batches {
+		c.propose(c.creator.createNextBlock(batch))
+	}
+	if len(batches) > 0 {
+		c.batchTimer = nil
+	}
+	if pending && c.batchTimer == nil {
+		c.batchTimer = time.After(c.support.SharedConfig().BatchTimeout())
+	}
+	return nil
+}
+
+// checkConfigUpdate rejects config updates which add or remove more than one
+// consenter at a time.
+func (c *Chain) checkConfigUpdate(env *cb.Envelope) error {
+	consenters, err := configEnvelopeConsenters(env)
+	if err != nil {
+		return errors.Wrap(err, "invalid config update")
+	}
+	if consenters == nil {
+		return nil
+	}
+	c.consentersLock.RLock()
+	_, _, changes := membershipChange(c.raftMetadata, c.consenters, consenters)
+	c.consentersLock.RUnlock()
+	if changes > 1 {
+		return errors.Errorf("update of more than one consenter at a time is not supported, requested changes add or remove %d consenters", changes)
+	}
+	return nil
+}
+
+// propose proposes the block to the other consenters. When the proposal
+// fails, the leader stops creating blocks until the entries proposed so far
+// are applied, so that the next blocks are chained to the last written one.
+func (c *Chain) propose(block *cb.Block) {
+	logger.Debugf("[channel: %s] Proposing block %d", c.channelID, block.Header.Number)
+	ctx, cancel := context.WithTimeout(c.ctx, c.proposalTimeout())
+	defer cancel()
+	if err := c.node.Propose(ctx, utils.MarshalOrPanic(block)); err != nil {
+		logger.Warningf("[channel: %s] Failed to propose block %d: %s", c.channelID, block.Header.Number, err)
+		c.resetCreator()
+	}
+}
+
+// proposeConfChange proposes to add or remove the consenter by which the
+// Raft cluster differs from the consenters of the channel. It returns false
+// if there is no such consenter, or if the proposal failed.
+func (c *Chain) proposeConfChange() bool {
+	cc, ok := confChange(c.confState, c.raftMetadata.ConsenterIds)
+	if !ok {
+		return false
+	}
+	logger.Infof("[channel: %s] Proposing %s of Raft node %d", c.channelID, cc.Type, cc.NodeID)
+	ctx, cancel := context.WithTimeout(c.ctx, c.proposalTimeout())
+	defer cancel()
+	if err := c.node.ProposeConfChange(ctx, cc); err != nil {
+		logger.Warningf("[channel: %s] Failed to propose %s of Raft node %d: %s", c.channelID, cc.Type, cc.NodeID, err)
+		c.resetCreator()
+		return false
+	}
+	return true
+}
+
+// proposalTimeout bounds the time spent proposing, which only blocks if
+// the raft.Node is busy or this node lost the leadership in the meantime.
+func (c *Chain) proposalTimeout() time.Duration {
+	return c.opts.TickInterval * time.Duration(c.opts.ElectionTick)
+}
+
+func (c *Chain) resetCreator() {
+	c.creator = nil
+	c.readyIndex = c.node.lastIndex()
+	c.configInflight = false
+}
+
+// checkLeadership starts creating blocks once this node, elected leader, has
+// applied all the entries of the previous terms. It then completes the
+// change of the Raft cluster a previous leader may have left pending.
+func (c *Chain) checkLeadership() {
+	if c.lead != c.raftID || c.creator != nil || c.appliedIndex < c.readyIndex {
+		return
+	}
+	c.creator = newBlockCreator(c.lastBlock)
+	c.configInflight = c.proposeConfChange()
+}
+
+// changeLeader tracks the changes of leader reported by the raft.Node.
+func (c *Chain) changeLeader(lead uint64) {
+	logger.Infof("[channel: %s] Raft leader changed: %d -> %d", c.channelID, c.lead, lead)
+	if c.lead == c.raftID {
+		if batch := c.support.BlockCutter().Cut(); len(batch) > 0 {
+			logger.Warningf("[channel: %s] Dropping %d pending requests after losing leadership", c.channelID, len(batch))
+		}
+		c.batchTimer = nil
+		c.configInflight = false
+	}
+	c.lead = lead
+	atomic.StoreUint64(&c.leaderID, lead)
+	c.creator = nil
+	if lead == c.raftID {
+		c.readyIndex = c.node.lastIndex()
+	}
+}
+
+// processReady persists, sends and applies what the raft.Node produced.
+func (c *Chain) processReady(rd raft.Ready) error {
+	if rd.SoftState != nil && rd.SoftState.Lead != c.lead {
+		c.changeLeader(rd.SoftState.Lead)
+	}
+	if err := c.storage.store(rd.Entries, rd.HardState, rd.Snapshot); err != nil {
+		return err
+	}
+	if !raft.IsEmptySnap(rd.Snapshot) {
+		if err := c.catchUp(rd.Snapshot); err != nil {
+			return err
+		}
+	}
+	c.node.send(rd.Messages)
+	if err := c.apply(rd.CommittedEntries); err != nil {
+		return err
+	}
+	c.node.Advance()
+	return c.maybeSnapshot()
+}
+
+// apply writes the blocks of the committed entries to the ledger, and
+// applies the committed changes of the Raft cluster.
+func (c *Chain) apply(entries []raftpb.Entry) error {
+	for _, entry := range entries {
+		switch entry.Type {
+		case raftpb.EntryNormal:
+			if len(entry.Data) == 0 {
+				break
+			}
+			block := &cb.Block{}
+			if err := proto.Unmarshal(entry.Data, block); err != nil {
+				return errors.Wrapf(err, "failed to unmarshal block of entry %d", entry.Index)
+			}
+			c.writeBlock(block, entry.Index)
+		case raftpb.EntryConfChange:
+			var cc raftpb.ConfChange
+			if err := cc.Unmarshal(entry.Data); err != nil {
+				return errors.Wrapf(err, "failed to unmarshal Raft config change of entry %d", entry.Index)
+			}
+			c.confState = *c.node.ApplyConfChange(cc)
+			logger.Infof("[channel: %s] Applied %s of Raft node %d, the Raft nodes are %v", c.channelID, cc.Type, cc.NodeID, c.confState.Nodes)
+			c.configInflight = false
+		}
+		c.appliedIndex = entry.Index
+	}
+	return nil
+}
+
+// writeBlock writes a committed block to the ledger. Blocks which are already
+// in the ledger are skipped, as entries are replayed after a restart, and so
+// are blocks which don't follow the last block, as a leader chained them to
+// a proposal which was never committed.
+func (c *Chain) writeBlock(block *cb.Block, index uint64) {
+	last := c.lastBlock.Header.Number
+	if block.Header.Number <= last {
+		logger.Debugf("[channel: %s] Skipping block %d, already in the ledger", c.channelID, block.Header.Number)
+		return
+	}
+	if block.Header.Number != last+1 || !bytes.Equal(block.Header.PreviousHash, c.lastBlock.Header.Hash()) {
+		logger.Warningf("[channel: %s] Discarding block %d of entry %d, it doesn't follow block %d", c.channelID, block.Header.Number, index, last)
+		return
+	}
+
+	c.raftMetadata.RaftIndex = index
+	if !isConfigBlock(block) {
+		c.support.WriteBlock(block, utils.MarshalOrPanic(c.raftMetadata))
+		c.lastBlock = block
+		return
+	}
+
+	consenters, err := blockConsenters(block)
+	if err != nil {
+		logger.Panicf("[channel: %s] Failed to read the consenters of config block %d: %s", c.channelID, block.Header.Number, err)
+	}
+	changes := 0
+	if consenters == nil {
+		c.support.WriteConfigBlock(block, utils.MarshalOrPanic(c.raftMetadata))
+	} else {
+		metadata, byID, n := membershipChange(c.raftMetadata, c.consenters, consenters)
+		c.support.WriteConfigBlock(block, utils.MarshalOrPanic(metadata))
+		c.raftMetadata = metadata
+		if changes = n; changes > 0 {
+			c.setConsenters(byID)
+		}
+	}
+	c.lastBlock = block
+
+	// A config changing the consenters stays in flight until the Raft
+	// cluster has been changed accordingly.
+	c.configInflight = changes > 0 && c.lead == c.raftID && c.creator != nil && c.proposeConfChange()
+}
+
+func (c *Chain) setConsenters(consenters map[uint64]*ab.RaftConsenter) {
+	c.consentersLock.Lock()
+	c.consenters = consenters
+	c.consentersLock.Unlock()
+
+	logger.Infof("[channel: %s] Consenters changed to %v", c.channelID, c.raftMetadata.ConsenterIds)
+	c.rpc.Configure(c.remoteConsenters())
+	if _, exists := consenters[c.raftID]; !exists {
+		c.removed = true
+	}
+}
+
+// maxPullAttempts bounds the consecutive attempts to pull a block during a
+// catch-up, an attempt being made every tick.
+var maxPullAttempts = 100
+
+// catchUp pulls the blocks covered by a snapshot received from the leader.
+// It fails if no consenter serves the next block after maxPullAttempts
+// attempts, which halts the chain. The catch-up resumes when the chain is
+// restarted, as the snapshot has been persisted.
+func (c *Chain) catchUp(snap raftpb.Snapshot) error {
+	target := &cb.Block{}
+	if err := proto.Unmarshal(snap.Data, target); err != nil {
+		return errors.Wrap(err, "failed to unmarshal snapshot block")
+	}
+	logger.Infof("[channel: %s] Catching up from block %d to block %d", c.channelID, c.lastBlock.Header.Number, target.Header.Number)
+
+	for attempts := 0; c.lastBlock.Header.Number < target.Header.Number; {
+		block := c.pull(c.lastBlock.Header.Number + 1)
+		if block == nil {
+			if attempts++; attempts >= maxPullAttempts {
+				return errors.Errorf("failed to pull block %d from the other consenters after %d attempts", c.lastBlock.Header.Number+1, attempts)
+			}
+			select {
+			case <-c.haltC:
+				return errors.New("chain is stopped")
+			case <-time.After(c.opts.TickInterval):
+			}
+			continue
+		}
+		attempts = 0
+		c.writeBlock(block, snap.Metadata.Index)
+	}
+	if !bytes.Equal(c.lastBlock.Header.Hash(), target.Header.Hash()) {
+		return errors.Errorf("block %d pulled from the other consenters doesn't match the snapshot", target.Header.Number)
+	}
+	c.raftMetadata.RaftIndex = snap.Metadata.Index
+	c.confState = snap.Metadata.ConfState
+	c.appliedIndex = snap.Metadata.Index
+	c.lastSnapshotBlock = target.Header.Number
+	return nil
+}
+
+// pull retrieves the block with the given number from the leader, or from the
+// other consenters if the leader doesn't have it.
+func (c *Chain) pull(number uint64) *cb.Block {
+	ids := []uint64{c.lead}
+	for id := range c.remoteConsenters() {
+		if id != c.lead {
+			ids = append(ids, id)
+		}
+	}
+	for _, id := range ids {
+		if id == 0 || id == c.raftID {
+			continue
+		}
+		resp, err := c.rpc.Pull(id, &ab.PullRequest{Channel: c.channelID, BlockNumber: number})
+		if err != nil {
+			logger.Debugf("[channel: %s] Failed to pull block %d from %d: %s", c.channelID, number, id, err)
+			continue
+		}
+		block := resp.Block
+		if block == nil || block.Header == nil || block.Header.Number != number ||
+			!bytes.Equal(block.Header.PreviousHash, c.lastBlock.Header.Hash()) {
+			logger.Warningf("[channel: %s] Consenter %d returned an invalid block %d", c.channelID, id, number)
+			continue
+		}
+		return block
+	}
+	return nil
+}
+
+// maybeSnapshot takes a snapshot and compacts the log once enough blocks were
+// written since the last snapshot.
+func (c *Chain) maybeSnapshot() error {
+	if c.opts.SnapshotInterval == 0 || c.lastBlock.Header.Number < c.lastSnapshotBlock+c.opts.SnapshotInterval {
+		return nil
+	}
+	snap, err := c.storage.takeSnapshot(c.appliedIndex, c.confState, utils.MarshalOrPanic(c.lastBlock))
+	if err != nil {
+		return errors.Wrap(err, "failed to take snapshot")
+	}
+	logger.Debugf("[channel: %s] Took snapshot at index %d, block %d", c.channelID, snap.Metadata.Index, c.lastBlock.Header.Number)
+	c.lastSnapshotBlock = c.lastBlock.Header.Number
+	return nil
+}
+
+func isConfigEnvelope(env *cb.Envelope) (bool, error) {
+	chdr, err := utils.ChannelHeader(env)
+	if err != nil {
+		return false, err
+	}
+	return chdr.Type == int32(cb.HeaderType_CONFIG) || chdr.Type == int32(cb.HeaderType_ORDERER_TRANSACTION), nil
+}
+
+func isConfigBlock(block *cb.Block) bool {
+	if block.Data == nil || len(block.Data.Data) != 1 {
+		return false
+	}
+	env, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return false
+	}
+	isConfig, _ := isConfigEnvelope(env)
+	return isConfig
+}
+
+// blockCreator creates the blocks proposed by the leader, which are chained
+// to the preceding proposed blocks rather than to the last written block.
+type blockCreator struct {
+	hash   []byte
+	number uint64
+}
+
+func newBlockCreator(lastBlock *cb.Block) *blockCreator {
+	return &blockCreator{hash: lastBlock.Header.Hash(), number: lastBlock.Header.Number}
+}
+
+func (bc *blockCreator) createNextBlock(envs []*cb.Envelope) *cb.Block {
+	data := &cb.BlockData{Data: make([][]byte, len(envs))}
+	for i, env := range envs {
+		data.Data[i] = utils.MarshalOrPanic(env)
+	}
+	block := cb.NewBlock(bc.number+1, bc.hash)
+	block.Header.DataHash = data.Hash()
+	block.Data = data
+
+	bc.number++
+	bc.hash = block.Header.Hash()
+	return block
+}
@@ -0,0 +1,515 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package raft
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/coreos/etcd/raft/raftpb"
+	"github.com/golang/protobuf/proto"
+	mockconfig "github.com/hyperledger/fabric/common/mocks/config"
+	mockblockcutter "github.com/hyperledger/fabric/orderer/mocks/common/blockcutter"
+	mockmultichannel "github.com/hyperledger/fabric/orderer/mocks/common/multichannel"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testChannel = "testchannel"
+
+// testSupport keeps the written blocks in memory.
+type testSupport struct {
+	*mockmultichannel.ConsenterSupport
+	lock    sync.Mutex
+	blocks  []*cb.Block
+	written chan *cb.Block
+}
+
+func newTestSupport(blocks ...*cb.Block) *testSupport {
+	cutter := mockblockcutter.NewReceiver()
+	cutter.CutNext = true
+	close(cutter.Block)
+	return &testSupport{
+		ConsenterSupport: &mockmultichannel.ConsenterSupport{
+			ChainIDVal:      testChannel,
+			SharedConfigVal: &mockconfig.Orderer{BatchTimeoutVal: time.Hour},
+			BlockCutterVal:  cutter,
+		},
+		blocks:  blocks,
+		written: make(chan *cb.Block, 100),
+	}
+}
+
+func (ts *testSupport) WriteBlock(block *cb.Block, encodedMetadataValue []byte) {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	block.Metadata.Metadata[cb.BlockMetadataIndex_ORDERER] = utils.MarshalOrPanic(&cb.Metadata{Value: encodedMetadataValue})
+	ts.blocks = append(ts.blocks, block)
+	ts.written <- block
+}
+
+func (ts *testSupport) WriteConfigBlock(block *cb.Block, encodedMetadataValue []byte) {
+	ts.WriteBlock(block, encodedMetadataValue)
+}
+
+func (ts *testSupport) Height() uint64 {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	return uint64(len(ts.blocks))
+}
+
+func (ts *testSupport) Block(number uint64) *cb.Block {
+	ts.lock.Lock()
+	defer ts.lock.Unlock()
+	if number >= uint64(len(ts.blocks)) {
+		return nil
+	}
+	return ts.blocks[number]
+}
+
+func (ts *testSupport) expectBlock(t *testing.T) *cb.Block {
+	select {
+	case block := <-ts.written:
+		return block
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out waiting for a block")
+		return nil
+	}
+}
+
+// testCluster routes the messages between in-memory chains.
+type testCluster struct {
+	lock         sync.RWMutex
+	chains       map[uint64]*Chain
+	disconnected map[uint64]bool
+}
+
+func (tc *testCluster) chain(from, to uint64) (*Chain, error) {
+	tc.lock.RLock()
+	defer tc.lock.RUnlock()
+	if tc.disconnected[from] || tc.disconnected[to] || tc.chains[to] == nil {
+		return nil, errors.Errorf("consenter %d is unreachable", to)
+	}
+	return tc.chains[to], nil
+}
+
+func (tc *testCluster) setDisconnected(id uint64, disconnected bool) {
+	tc.lock.Lock()
+	defer tc.lock.Unlock()
+	tc.disconnected[id] = disconnected
+}
+
+type testRPC struct {
+	id      uint64
+	cluster *testCluster
+}
+
+func (r *testRPC) SendConsensus(dest uint64, msg *ab.ConsensusRequest) error {
+	c, err := r.cluster.chain(r.id, dest)
+	if err != nil {
+		return err
+	}
+	go c.onConsensus(r.id, msg)
+	return nil
+}
+
+func (r *testRPC) SendSubmit(dest uint64, req *ab.SubmitRequest) (*ab.SubmitResponse, error) {
+	c, err := r.cluster.chain(r.id, dest)
+	if err != nil {
+		return nil, err
+	}
+	return c.onSubmit(r.id, req)
+}
+
+func (r *testRPC) Pull(dest uint64, req *ab.PullRequest) (*ab.PullResponse, error) {
+	c, err := r.cluster.chain(r.id, dest)
+	if err != nil {
+		return nil, err
+	}
+	return c.onPull(r.id, req)
+}
+
+func (r *testRPC) Configure(consenters map[uint64]*ab.RaftConsenter) {}
+
+func testConsenters(ids ...uint64) []*ab.RaftConsenter {
+	var consenters []*ab.RaftConsenter
+	for _, id := range ids {
+		consenters = append(consenters, &ab.RaftConsenter{
+			Host:          fmt.Sprintf("orderer%d", id),
+			Port:          7050,
+			ClientTlsCert: []byte(fmt.Sprintf("client%d", id)),
+			ServerTlsCert: []byte(fmt.Sprintf("server%d", id)),
+		})
+	}
+	return consenters
+}
+
+type testNode struct {
+	id      uint64
+	dir     string
+	support *testSupport
+	chain   *Chain
+}
+
+func (tn *testNode) start(t *testing.T, cluster *testCluster, opts Options) {
+	opts.RaftID = tn.id
+	opts.WALDir = filepath.Join(tn.dir, "wal")
+	opts.SnapDir = filepath.Join(tn.dir, "snap")
+	if opts.TickInterval == 0 {
+		opts.TickInterval = 10 * time.Millisecond
+	}
+	opts.ElectionTick = 10
+	opts.HeartbeatTick = 1
+	opts.MaxEntriesPerMsg = 10
+
+	var err error
+	tn.chain, err = NewChain(tn.support, opts, &testRPC{id: tn.id, cluster: cluster})
+	require.NoError(t, err)
+	cluster.lock.Lock()
+	cluster.chains[tn.id] = tn.chain
+	cluster.lock.Unlock()
+	tn.chain.Start()
+}
+
+func (tn *testNode) stop() {
+	tn.chain.Halt()
+	<-tn.chain.doneC
+}
+
+func newTestNodes(t *testing.T, ids ...uint64) ([]*testNode, *testCluster, Options, func()) {
+	dir, err := ioutil.TempDir("", "raft-chain")
+	require.NoError(t, err)
+
+	blockMetadata, err := readBlockMetadata(nil, &ab.RaftConfigMetadata{Consenters: testConsenters(ids...)})
+	require.NoError(t, err)
+	opts := Options{
+		RaftMetadata: blockMetadata,
+		Consenters:   consentersByID(blockMetadata, testConsenters(ids...)),
+	}
+
+	cluster := &testCluster{chains: make(map[uint64]*Chain), disconnected: make(map[uint64]bool)}
+	genesis := cb.NewBlock(0, nil)
+	var nodes []*testNode
+	for _, id := range ids {
+		nodes = append(nodes, &testNode{
+			id:      id,
+			dir:     filepath.Join(dir, fmt.Sprintf("node%d", id)),
+			support: newTestSupport(proto.Clone(genesis).(*cb.Block)),
+		})
+	}
+	cleanup := func() {
+		for _, n := range nodes {
+			if n.chain != nil {
+				n.chain.Halt()
+			}
+		}
+		os.RemoveAll(dir)
+	}
+	return nodes, cluster, opts, cleanup
+}
+
+func testEnvelope(t *testing.T, headerType cb.HeaderType, data []byte) *cb.Envelope {
+	return &cb.Envelope{
+		Payload: utils.MarshalOrPanic(&cb.Payload{
+			Header: &cb.Header{ChannelHeader: utils.MarshalOrPanic(&cb.ChannelHeader{Type: int32(headerType), ChannelId: testChannel})},
+			Data:   data,
+		}),
+	}
+}
+
+func configEnvelope(t *testing.T, consenters []*ab.RaftConsenter) *cb.Envelope {
+	metadata := utils.MarshalOrPanic(&ab.RaftConfigMetadata{Consenters: consenters})
+	configEnv := &cb.ConfigEnvelope{
+		Config: &cb.Config{
+			ChannelGroup: &cb.ConfigGroup{
+				Groups: map[string]*cb.ConfigGroup{
+					"Orderer": {
+						Values: map[string]*cb.ConfigValue{
+							"ConsensusType": {Value: utils.MarshalOrPanic(&ab.ConsensusType{Type: ConsensusType, Metadata: metadata})},
+						},
+					},
+				},
+			},
+		},
+	}
+	return testEnvelope(t, cb.HeaderType_CONFIG, utils.MarshalOrPanic(configEnv))
+}
+
+func blockRaftMetadata(t *testing.T, block *cb.Block) *ab.RaftBlockMetadata {
+	metadata, err := utils.GetMetadataFromBlock(block, cb.BlockMetadataIndex_ORDERER)
+	require.NoError(t, err)
+	m := &ab.RaftBlockMetadata{}
+	require.NoError(t, proto.Unmarshal(metadata.Value, m))
+	return m
+}
+
+// orderWithRetry orders the envelope through the given node, retrying
+// while the cluster has no leader.
+func orderWithRetry(t *testing.T, n *testNode, env *cb.Envelope) {
+	var err error
+	for i := 0; i < 500; i++ {
+		if err = n.chain.Order(env, 0); err == nil {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("failed to order: %s", err)
+}
+
+func TestSingleNodeChain(t *testing.T) {
+	nodes, cluster, opts, cleanup := newTestNodes(t, 1)
+	defer cleanup()
+	n := nodes[0]
+	n.start(t, cluster, opts)
+
+	orderWithRetry(t, n, testEnvelope(t, cb.HeaderType_MESSAGE, []byte("foo")))
+	block := n.support.expectBlock(t)
+	assert.Equal(t, uint64(1), block.Header.Number)
+	assert.Equal(t, n.support.Block(0).Header.Hash(), block.Header.PreviousHash)
+	assert.Equal(t, block.Data.Hash(), block.Header.DataHash)
+	metadata := blockRaftMetadata(t, block)
+	assert.Equal(t, []uint64{1}, metadata.ConsenterIds)
+	assert.Equal(t, uint64(2), metadata.NextConsenterId)
+	assert.NotZero(t, metadata.RaftIndex)
+
+	// a bad message is rejected when the config sequence advanced
+	n.support.SequenceVal = 1
+	n.support.ProcessNormalMsgErr = errors.New("bad")
+	assert.EqualError(t, n.chain.Order(testEnvelope(t, cb.HeaderType_MESSAGE, []byte("bar")), 0), "bad normal message: bad")
+	n.support.ProcessNormalMsgErr = nil
+
+	n.stop()
+	select {
+	case <-n.chain.Errored():
+	default:
+		t.Fatal("Errored should be closed after halting")
+	}
+	assert.Error(t, n.chain.WaitReady())
+
+	// the restarted chain picks up where it left off
+	opts.RaftMetadata = metadata
+	n.start(t, cluster, opts)
+	orderWithRetry(t, n, testEnvelope(t, cb.HeaderType_MESSAGE, []byte("baz")))
+	block = n.support.expectBlock(t)
+	assert.Equal(t, uint64(2), block.Header.Number)
+	assert.True(t, blockRaftMetadata(t, block).RaftIndex > metadata.RaftIndex)
+}
+
+func TestMultiNodeChain(t *testing.T) {
+	nodes, cluster, opts, cleanup := newTestNodes(t, 1, 2, 3)
+	defer cleanup()
+	for _, n := range nodes {
+		n.start(t, cluster, opts)
+	}
+
+	// requests are forwarded to the leader by the followers
+	for i, n := range nodes {
+		orderWithRetry(t, n, testEnvelope(t, cb.HeaderType_MESSAGE, []byte{byte(i)}))
+		var hash []byte
+		for _, n := range nodes {
+			block := n.support.expectBlock(t)
+			assert.Equal(t, uint64(i+1), block.Header.Number)
+			if hash == nil {
+				hash = block.Header.Hash()
+			}
+			assert.Equal(t, hash, block.Header.Hash())
+		}
+	}
+
+	// the cluster tolerates the failure of the leader
+	var leaderNode *testNode
+	for _, n := range nodes {
+		if atomic.LoadUint64(&n.chain.leaderID) == n.id {
+			leaderNode = n
+		}
+	}
+	require.NotNil(t, leaderNode)
+	leaderNode.stop()
+	cluster.setDisconnected(leaderNode.id, true)
+
+	for _, n := range nodes {
+		if n == leaderNode {
+			continue
+		}
+		orderWithRetry(t, n, testEnvelope(t, cb.HeaderType_MESSAGE, []byte("after failover")))
+		break
+	}
+	for _, n := range nodes {
+		if n != leaderNode {
+			assert.Equal(t, uint64(4), n.support.expectBlock(t).Header.Number)
+		}
+	}
+}
+
+func TestFollowerCatchesUpWithSnapshot(t *testing.T) {
+	nodes, cluster, opts, cleanup := newTestNodes(t, 1, 2, 3)
+	defer cleanup()
+	opts.SnapshotInterval = 2
+	for _, n := range nodes {
+		n.start(t, cluster, opts)
+	}
+
+	lagging := nodes[2]
+	orderWithRetry(t, nodes[0], testEnvelope(t, cb.HeaderType_MESSAGE, []byte("first")))
+	for _, n := range nodes {
+		n.support.expectBlock(t)
+	}
+	if atomic.LoadUint64(&lagging.chain.leaderID) == lagging.id {
+		// make sure the lagging node isn't the leader
+		lagging = nodes[0]
+	}
+
+	cluster.setDisconnected(lagging.id, true)
+	var active *testNode
+	for _, n := range nodes {
+		if n != lagging {
+			active = n
+		}
+	}
+	for i := 0; i < 5; i++ {
+		orderWithRetry(t, active, testEnvelope(t, cb.HeaderType_MESSAGE, []byte{byte(i)}))
+	}
+	for _, n := range nodes {
+		if n == lagging {
+			continue
+		}
+		for i := 0; i < 5; i++ {
+			n.support.expectBlock(t)
+		}
+	}
+
+	cluster.setDisconnected(lagging.id, false)
+	for i := 0; i < 5; i++ {
+		block := lagging.support.expectBlock(t)
+		assert.Equal(t, uint64(i+2), block.Header.Number)
+		assert.Equal(t, active.support.Block(block.Header.Number).Header.Hash(), block.Header.Hash())
+	}
+}
+
+// A channel migrated from Kafka has blocks whose orderer metadata wasn't
+// written by Raft, and no Raft state, as it was removed by the migration.
+func TestChainAfterMigration(t *testing.T) {
+	nodes, cluster, _, cleanup := newTestNodes(t, 1, 2, 3)
+	defer cleanup()
+
+	blocks := []*cb.Block{cb.NewBlock(0, nil)}
+	for i := uint64(1); i <= 3; i++ {
+		block := cb.NewBlock(i, blocks[i-1].Header.Hash())
+		block.Metadata.Metadata[cb.BlockMetadataIndex_ORDERER] = utils.MarshalOrPanic(&cb.Metadata{
+			Value: utils.MarshalOrPanic(&ab.KafkaMetadata{LastOffsetPersisted: int64(i)}),
+		})
+		blocks = append(blocks, block)
+	}
+	configMetadata := utils.MarshalOrPanic(&ab.RaftConfigMetadata{Consenters: testConsenters(1, 2, 3)})
+
+	for _, n := range nodes {
+		var migrated []*cb.Block
+		for _, block := range blocks {
+			migrated = append(migrated, proto.Clone(block).(*cb.Block))
+		}
+		n.support = newTestSupport(migrated...)
+		n.support.SharedConfigVal.ConsensusMetadataVal = configMetadata
+
+		// the chain starts afresh, without the metadata of the last block
+		c := &consenter{serverCert: []byte(fmt.Sprintf("server%d", n.id))}
+		opts, err := c.options(n.support, nil)
+		require.NoError(t, err)
+		assert.Equal(t, n.id, opts.RaftID)
+		assert.False(t, opts.Join, "Should bootstrap the Raft cluster")
+		opts.TickInterval = 0
+		n.start(t, cluster, opts)
+	}
+
+	orderWithRetry(t, nodes[0], testEnvelope(t, cb.HeaderType_MESSAGE, []byte("after migration")))
+	for _, n := range nodes {
+		block := n.support.expectBlock(t)
+		assert.Equal(t, uint64(4), block.Header.Number)
+		assert.Equal(t, blocks[3].Header.Hash(), block.Header.PreviousHash)
+		assert.Equal(t, []uint64{1, 2, 3}, blockRaftMetadata(t, block).ConsenterIds)
+	}
+
+	// once Raft wrote a block, a node without Raft state joins the cluster
+	c := &consenter{serverCert: []byte("server1")}
+	metadata, err := utils.GetMetadataFromBlock(nodes[0].support.Block(4), cb.BlockMetadataIndex_ORDERER)
+	require.NoError(t, err)
+	opts, err := c.options(nodes[0].support, metadata)
+	require.NoError(t, err)
+	assert.True(t, opts.Join, "Should join the existing Raft cluster")
+}
+
+func TestCatchUpGivesUp(t *testing.T) {
+	defer func(attempts int) { maxPullAttempts = attempts }(maxPullAttempts)
+	maxPullAttempts = 3
+
+	nodes, cluster, opts, cleanup := newTestNodes(t, 1, 2)
+	defer cleanup()
+	n := nodes[0]
+	opts.RaftID = n.id
+	opts.WALDir = filepath.Join(n.dir, "wal")
+	opts.SnapDir = filepath.Join(n.dir, "snap")
+	opts.TickInterval = time.Millisecond
+	opts.ElectionTick = 10
+	opts.HeartbeatTick = 1
+	opts.MaxEntriesPerMsg = 10
+	chain, err := NewChain(n.support, opts, &testRPC{id: n.id, cluster: cluster})
+	require.NoError(t, err)
+	defer func() {
+		chain.node.Stop()
+		chain.storage.close()
+	}()
+
+	// the other consenter is unreachable
+	snap := raftpb.Snapshot{Data: utils.MarshalOrPanic(cb.NewBlock(3, nil))}
+	err = chain.catchUp(snap)
+	assert.EqualError(t, err, "failed to pull block 1 from the other consenters after 3 attempts")
+}
+
+func TestConsenterRemoval(t *testing.T) {
+	nodes, cluster, opts, cleanup := newTestNodes(t, 1, 2, 3)
+	defer cleanup()
+	for _, n := range nodes {
+		n.start(t, cluster, opts)
+	}
+
+	// at most one consenter can be added or removed at a time
+	err := nodes[0].chain.Configure(configEnvelope(t, testConsenters(1, 4, 5)), 0)
+	for i := 0; err != nil && i < 500 && err.Error() == "no Raft leader"; i++ {
+		time.Sleep(10 * time.Millisecond)
+		err = nodes[0].chain.Configure(configEnvelope(t, testConsenters(1, 4, 5)), 0)
+	}
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "update of more than one consenter at a time is not supported")
+
+	// remove the third consenter
+	require.NoError(t, nodes[0].chain.Configure(configEnvelope(t, testConsenters(1, 2)), 0))
+	for _, n := range nodes {
+		block := n.support.expectBlock(t)
+		assert.Equal(t, []uint64{1, 2}, blockRaftMetadata(t, block).ConsenterIds)
+		assert.Equal(t, uint64(4), blockRaftMetadata(t, block).NextConsenterId)
+	}
+
+	select {
+	case <-nodes[2].chain.Errored():
+	case <-time.After(10 * time.Second):
+		t.Fatal("removed consenter should halt")
+	}
+
+	orderWithRetry(t, nodes[0], testEnvelope(t, cb.HeaderType_MESSAGE, []byte("foo")))
+	for _, n := range nodes[:2] {
+		assert.Equal(t, uint64(2), n.support.expectBlock(t).Header.Number)
+	}
+}
@@ -0,0 +1,304 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package raft
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hyperledger/fabric/core/comm"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/pkg/errors"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+const sendBufferSize = 100
+
+// handler processes the cluster messages of a channel.
+type handler interface {
+	consenterID(certHash []byte) (uint64, bool)
+	onConsensus(sender uint64, req *ab.ConsensusRequest) error
+	onSubmit(sender uint64, req *ab.SubmitRequest) (*ab.SubmitResponse, error)
+	onPull(sender uint64, req *ab.PullRequest) (*ab.PullResponse, error)
+}
+
+// Comm implements the Cluster service, which dispatches the messages sent by
+// the other consenters to the chains, and connects to the other consenters.
+type Comm struct {
+	client     comm.GRPCClient
+	rpcTimeout time.Duration
+
+	lock     sync.RWMutex
+	handlers map[string]handler
+
+	// certHash extracts the hash of the TLS certificate of the caller.
+	certHash func(ctx context.Context) []byte
+}
+
+// NewComm creates a Comm which uses the given client to connect to the other consenters.
+func NewComm(client comm.GRPCClient, rpcTimeout time.Duration) *Comm {
+	return &Comm{
+		client:     client,
+		rpcTimeout: rpcTimeout,
+		handlers:   make(map[string]handler),
+		certHash:   comm.ExtractCertificateHashFromContext,
+	}
+}
+
+func (c *Comm) register(channel string, h handler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.handlers[channel] = h
+}
+
+func (c *Comm) unregister(channel string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.handlers, channel)
+}
+
+// authenticate returns the handler of the channel and the ID of the
+// consenter which invoked the service.
+func (c *Comm) authenticate(ctx context.Context, channel string) (handler, uint64, error) {
+	c.lock.RLock()
+	h, exists := c.handlers[channel]
+	c.lock.RUnlock()
+	if !exists {
+		return nil, 0, errors.Errorf("channel %s doesn't exist", channel)
+	}
+
+	certHash := c.certHash(ctx)
+	if len(certHash) == 0 {
+		return nil, 0, errors.New("no TLS certificate sent")
+	}
+	id, exists := h.consenterID(certHash)
+	if !exists {
+		return nil, 0, errors.Errorf("certificate isn't authorized for channel %s", channel)
+	}
+	return h, id, nil
+}
+
+// Step passes a consensus message or a transaction to the chain of the channel.
+func (c *Comm) Step(ctx context.Context, req *ab.StepRequest) (*ab.StepResponse, error) {
+	switch payload := req.Payload.(type) {
+	case *ab.StepRequest_ConsensusRequest:
+		h, sender, err := c.authenticate(ctx, payload.ConsensusRequest.Channel)
+		if err != nil {
+			return nil, err
+		}
+		return &ab.StepResponse{}, h.onConsensus(sender, payload.ConsensusRequest)
+	case *ab.StepRequest_SubmitRequest:
+		h, sender, err := c.authenticate(ctx, payload.SubmitRequest.Channel)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := h.onSubmit(sender, payload.SubmitRequest)
+		if err != nil {
+			return nil, err
+		}
+		return &ab.StepResponse{SubmitRes: resp}, nil
+	default:
+		return nil, errors.Errorf("unknown step request type %T", req.Payload)
+	}
+}
+
+// Pull returns a block of the channel.
+func (c *Comm) Pull(ctx context.Context, req *ab.PullRequest) (*ab.PullResponse, error) {
+	h, sender, err := c.authenticate(ctx, req.Channel)
+	if err != nil {
+		return nil, err
+	}
+	return h.onPull(sender, req)
+}
+
+// rpc returns the RPC a chain of the given channel uses to reach the other consenters.
+func (c *Comm) rpc(channel string) *clusterRPC {
+	return &clusterRPC{comm: c, channel: channel, remotes: make(map[uint64]*remote)}
+}
+
+// clusterRPC implements RPC over gRPC connections to the other consenters.
+type clusterRPC struct {
+	comm    *Comm
+	channel string
+
+	lock    sync.Mutex
+	remotes map[uint64]*remote
+}
+
+// Configure connects to the given consenters, and disconnects from the ones
+// which are no longer part of the channel.
+func (r *clusterRPC) Configure(consenters map[uint64]*ab.RaftConsenter) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for id, rem := range r.remotes {
+		if c, exists := consenters[id]; !exists || !sameEndpoint(c, rem.consenter) {
+			rem.stop()
+			delete(r.remotes, id)
+		}
+	}
+	for id, c := range consenters {
+		if _, exists := r.remotes[id]; !exists {
+			r.remotes[id] = newRemote(r.comm, id, c)
+		}
+	}
+}
+
+func sameEndpoint(a, b *ab.RaftConsenter) bool {
+	return a.Host == b.Host && a.Port == b.Port
+}
+
+func (r *clusterRPC) remote(id uint64) (*remote, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	rem, exists := r.remotes[id]
+	if !exists {
+		return nil, errors.Errorf("unknown consenter %d", id)
+	}
+	return rem, nil
+}
+
+// SendConsensus queues the message to be sent to the given consenter.
+func (r *clusterRPC) SendConsensus(dest uint64, msg *ab.ConsensusRequest) error {
+	rem, err := r.remote(dest)
+	if err != nil {
+		return err
+	}
+	select {
+	case rem.sendC <- msg:
+		return nil
+	default:
+		return errors.Errorf("send buffer of consenter %d is full", dest)
+	}
+}
+
+// SendSubmit forwards a transaction to the given consenter.
+func (r *clusterRPC) SendSubmit(dest uint64, req *ab.SubmitRequest) (*ab.SubmitResponse, error) {
+	rem, err := r.remote(dest)
+	if err != nil {
+		return nil, err
+	}
+	client, err := rem.client()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), r.comm.rpcTimeout)
+	defer cancel()
+	resp, err := client.Step(ctx, &ab.StepRequest{Payload: &ab.StepRequest_SubmitRequest{SubmitRequest: req}})
+	if err != nil {
+		rem.reset()
+		return nil, err
+	}
+	if resp.SubmitRes == nil {
+		return nil, errors.New("empty submit response")
+	}
+	return resp.SubmitRes, nil
+}
+
+// Pull retrieves a block from the given consenter.
+func (r *clusterRPC) Pull(dest uint64, req *ab.PullRequest) (*ab.PullResponse, error) {
+	rem, err := r.remote(dest)
+	if err != nil {
+		return nil, err
+	}
+	client, err := rem.client()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), r.comm.rpcTimeout)
+	defer cancel()
+	resp, err := client.Pull(ctx, req)
+	if err != nil {
+		rem.reset()
+	}
+	return resp, err
+}
+
+// remote is a connection to another consenter. Consensus messages are sent
+// asynchronously, in the order they were queued.
+type remote struct {
+	id        uint64
+	consenter *ab.RaftConsenter
+	comm      *Comm
+	sendC     chan *ab.ConsensusRequest
+	stopC     chan struct{}
+
+	lock sync.Mutex
+	conn *grpc.ClientConn
+}
+
+func newRemote(c *Comm, id uint64, consenter *ab.RaftConsenter) *remote {
+	rem := &remote{
+		id:        id,
+		consenter: consenter,
+		comm:      c,
+		sendC:     make(chan *ab.ConsensusRequest, sendBufferSize),
+		stopC:     make(chan struct{}),
+	}
+	go rem.run()
+	return rem
+}
+
+func (rem *remote) endpoint() string {
+	return fmt.Sprintf("%s:%d", rem.consenter.Host, rem.consenter.Port)
+}
+
+func (rem *remote) client() (ab.ClusterClient, error) {
+	rem.lock.Lock()
+	defer rem.lock.Unlock()
+	if rem.conn == nil {
+		conn, err := rem.comm.client.NewConnection(rem.endpoint(), "")
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to connect to consenter %d at %s", rem.id, rem.endpoint())
+		}
+		rem.conn = conn
+	}
+	return ab.NewClusterClient(rem.conn), nil
+}
+
+// reset closes the connection so that it is established again on next use.
+func (rem *remote) reset() {
+	rem.lock.Lock()
+	defer rem.lock.Unlock()
+	if rem.conn != nil {
+		rem.conn.Close()
+		rem.conn = nil
+	}
+}
+
+func (rem *remote) stop() {
+	close(rem.stopC)
+}
+
+func (rem *remote) run() {
+	defer rem.reset()
+	for {
+		select {
+		case msg := <-rem.sendC:
+			rem.send(msg)
+		case <-rem.stopC:
+			return
+		}
+	}
+}
+
+func (rem *remote) send(msg *ab.ConsensusRequest) {
+	client, err := rem.client()
+	if err != nil {
+		logger.Debugf("[channel: %s] %s", msg.Channel, err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), rem.comm.rpcTimeout)
+	defer cancel()
+	_, err = client.Step(ctx, &ab.StepRequest{Payload: &ab.StepRequest_ConsensusRequest{ConsensusRequest: msg}})
+	if err != nil {
+		logger.Debugf("[channel: %s] Failed to send message to consenter %d: %s", msg.Channel, rem.id, err)
+		rem.reset()
+	}
+}
@@ -0,0 +1,81 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package raft
+
+import (
+	"testing"
+
+	"github.com/hyperledger/fabric/common/util"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/net/context"
+)
+
+type mockHandler struct {
+	ids       map[string]uint64
+	sender    uint64
+	consensus *ab.ConsensusRequest
+}
+
+func (h *mockHandler) consenterID(certHash []byte) (uint64, bool) {
+	id, exists := h.ids[string(certHash)]
+	return id, exists
+}
+
+func (h *mockHandler) onConsensus(sender uint64, req *ab.ConsensusRequest) error {
+	h.sender = sender
+	h.consensus = req
+	return nil
+}
+
+func (h *mockHandler) onSubmit(sender uint64, req *ab.SubmitRequest) (*ab.SubmitResponse, error) {
+	h.sender = sender
+	return &ab.SubmitResponse{Channel: req.Channel, Info: "submitted"}, nil
+}
+
+func (h *mockHandler) onPull(sender uint64, req *ab.PullRequest) (*ab.PullResponse, error) {
+	h.sender = sender
+	return nil, errors.Errorf("block %d not found", req.BlockNumber)
+}
+
+func TestCommDispatch(t *testing.T) {
+	c := NewComm(nil, 0)
+	var certHash []byte
+	c.certHash = func(ctx context.Context) []byte { return certHash }
+	h := &mockHandler{ids: map[string]uint64{string(util.ComputeSHA256([]byte("cert2"))): 2}}
+	c.register("foo", h)
+
+	consensus := &ab.StepRequest{Payload: &ab.StepRequest_ConsensusRequest{ConsensusRequest: &ab.ConsensusRequest{Channel: "foo", Payload: []byte{1}}}}
+	submit := &ab.StepRequest{Payload: &ab.StepRequest_SubmitRequest{SubmitRequest: &ab.SubmitRequest{Channel: "foo"}}}
+
+	_, err := c.Step(context.Background(), consensus)
+	assert.EqualError(t, err, "no TLS certificate sent")
+
+	certHash = util.ComputeSHA256([]byte("cert3"))
+	_, err = c.Step(context.Background(), consensus)
+	assert.EqualError(t, err, "certificate isn't authorized for channel foo")
+
+	certHash = util.ComputeSHA256([]byte("cert2"))
+	_, err = c.Step(context.Background(), consensus)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), h.sender)
+	assert.Equal(t, []byte{1}, h.consensus.Payload)
+
+	resp, err := c.Step(context.Background(), submit)
+	assert.NoError(t, err)
+	assert.Equal(t, "submitted", resp.SubmitRes.Info)
+
+	_, err = c.Pull(context.Background(), &ab.PullRequest{Channel: "foo", BlockNumber: 5})
+	assert.EqualError(t, err, "block 5 not found")
+
+	_, err = c.Pull(context.Background(), &ab.PullRequest{Channel: "bar"})
+	assert.EqualError(t, err, "channel bar doesn't exist")
+
+	_, err = c.Step(context.Background(), &ab.StepRequest{})
+	assert.EqualError(t, err, "unknown step request type <nil>")
+}
@@ -0,0 +1,229 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package raft
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hyperledger/fabric/core/comm"
+	localconfig "github.com/hyperledger/fabric/orderer/common/localconfig"
+	"github.com/hyperledger/fabric/orderer/consensus"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/pkg/errors"
+)
+
+// consenter creates the Raft chains of the channels. All the chains share the
+// same Cluster service and the same client TLS credentials.
+type consenter struct {
+	comm       *Comm
+	walDir     string
+	snapDir    string
+	serverCert []byte
+}
+
+// New creates a Raft-based consenter and registers the Cluster service on the
+// given server. The WAL and the snapshots are stored next to the ledger unless
+// specified otherwise. Called by orderer's main.go.
+func New(conf *localconfig.TopLevel, ledgerDir string, srv comm.GRPCServer) (consensus.Consenter, error) {
+	c := &consenter{
+		walDir:  conf.Raft.WALDir,
+		snapDir: conf.Raft.SnapDir,
+	}
+	if c.walDir == "" {
+		c.walDir = filepath.Join(ledgerDir, "raft", "wal")
+	}
+	if c.snapDir == "" {
+		c.snapDir = filepath.Join(ledgerDir, "raft", "snapshot")
+	}
+
+	if !conf.General.TLS.Enabled {
+		// Consenters authenticate each other with their TLS certificates,
+		// the chains will fail to be created.
+		return c, nil
+	}
+
+	var err error
+	c.serverCert, err = ioutil.ReadFile(conf.General.TLS.Certificate)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load server certificate")
+	}
+
+	client, err := newClusterClient(conf)
+	if err != nil {
+		return nil, err
+	}
+	c.comm = NewComm(client, conf.General.Cluster.RPCTimeout)
+	ab.RegisterClusterServer(srv.Server(), c.comm)
+	return c, nil
+}
+
+func newClusterClient(conf *localconfig.TopLevel) (comm.GRPCClient, error) {
+	certFile, keyFile, rootCAFiles := conf.General.Cluster.ClientCertificate, conf.General.Cluster.ClientPrivateKey, conf.General.Cluster.RootCAs
+	if certFile == "" {
+		certFile, keyFile = conf.General.TLS.Certificate, conf.General.TLS.PrivateKey
+	}
+	if len(rootCAFiles) == 0 {
+		rootCAFiles = conf.General.TLS.RootCAs
+	}
+
+	cert, err := ioutil.ReadFile(certFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load cluster client certificate")
+	}
+	key, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load cluster client private key")
+	}
+	var rootCAs [][]byte
+	for _, file := range rootCAFiles {
+		rootCA, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load cluster root CA")
+		}
+		rootCAs = append(rootCAs, rootCA)
+	}
+
+	client, err := comm.NewGRPCClient(comm.ClientConfig{
+		SecOpts: &comm.SecureOptions{
+			UseTLS:            true,
+			RequireClientCert: true,
+			Certificate:       cert,
+			Key:               key,
+			ServerRootCAs:     rootCAs,
+		},
+		Timeout: conf.General.Cluster.DialTimeout,
+	})
+	return client, errors.Wrap(err, "failed to create cluster client")
+}
+
+// HandleChain creates a Raft chain for the given channel. The consenters are
+// read from the channel config, and their IDs from the metadata of the last block.
+func (c *consenter) HandleChain(support consensus.ConsenterSupport, metadata *cb.Metadata) (consensus.Chain, error) {
+	if c.comm == nil {
+		return nil, errors.New("TLS must be enabled to use the raft consenter")
+	}
+
+	opts, err := c.options(support, metadata)
+	if err != nil {
+		return nil, err
+	}
+	chain, err := NewChain(support, opts, c.comm.rpc(support.ChainID()))
+	if err != nil {
+		return nil, err
+	}
+	c.comm.register(support.ChainID(), chain)
+	return chain, nil
+}
+
+// options returns the options of the chain of the given channel, whose last
+// block has the given metadata.
+func (c *consenter) options(support consensus.ConsenterSupport, metadata *cb.Metadata) (Options, error) {
+	configMetadata, err := readConfigMetadata(support.SharedConfig().ConsensusMetadata())
+	if err != nil {
+		return Options{}, err
+	}
+	blockMetadata, err := readBlockMetadata(metadata, configMetadata)
+	if err != nil {
+		return Options{}, err
+	}
+	consenters := consentersByID(blockMetadata, configMetadata.Consenters)
+	id, err := detectSelfID(consenters, c.serverCert)
+	if err != nil {
+		return Options{}, err
+	}
+
+	opts, err := chainOptions(configMetadata.Options)
+	if err != nil {
+		return Options{}, err
+	}
+	opts.RaftID = id
+	opts.WALDir = filepath.Join(c.walDir, support.ChainID())
+	opts.SnapDir = filepath.Join(c.snapDir, support.ChainID())
+	opts.RaftMetadata = blockMetadata
+	opts.Consenters = consenters
+	// Without Raft metadata, the chain is new or was migrated to Raft, and
+	// the consenters of the config bootstrap the Raft cluster.
+	opts.Join = metadata != nil && len(metadata.Value) > 0
+	return opts, nil
+}
+
+// RemoveChain stops dispatching the cluster messages of the channel to its
+// chain, which must have been halted, and deletes the WAL and the snapshots
+// of the chain.
+func (c *consenter) RemoveChain(chainID string) error {
+	if c.comm != nil {
+		c.comm.unregister(chainID)
+	}
+	if err := os.RemoveAll(filepath.Join(c.walDir, chainID)); err != nil {
+		return errors.Wrap(err, "failed to remove the WAL")
+	}
+	if err := os.RemoveAll(filepath.Join(c.snapDir, chainID)); err != nil {
+		return errors.Wrap(err, "failed to remove the snapshots")
+	}
+	return nil
+}
+
+// ReadsHistory returns true, as a lagging consenter pulls the blocks it
+// misses from the ledgers of the other consenters.
+func (c *consenter) ReadsHistory() bool {
+	return true
+}
+
+// ValidateConsensusMetadata checks that the given metadata defines the
+// consenters of a Raft chain, with valid options.
+func (c *consenter) ValidateConsensusMetadata(metadata []byte) error {
+	configMetadata, err := readConfigMetadata(metadata)
+	if err != nil {
+		return err
+	}
+	_, err = chainOptions(configMetadata.Options)
+	return err
+}
+
+// chainOptions converts the Raft options of the channel config, applying
+// the defaults for the ones which are not set.
+func chainOptions(options *ab.RaftOptions) (Options, error) {
+	opts := Options{
+		TickInterval:     defaultTickInterval,
+		ElectionTick:     defaultElectionTick,
+		HeartbeatTick:    defaultHeartbeatTick,
+		MaxEntriesPerMsg: defaultMaxEntriesPerMsg,
+		SnapshotInterval: defaultSnapshotIntervalBlocks,
+	}
+	if options == nil {
+		return opts, nil
+	}
+
+	if options.TickInterval != "" {
+		tickInterval, err := time.ParseDuration(options.TickInterval)
+		if err != nil {
+			return opts, errors.Wrap(err, "invalid tick interval")
+		}
+		opts.TickInterval = tickInterval
+	}
+	if options.ElectionTick != 0 {
+		opts.ElectionTick = int(options.ElectionTick)
+	}
+	if options.HeartbeatTick != 0 {
+		opts.HeartbeatTick = int(options.HeartbeatTick)
+	}
+	if options.MaxEntriesPerMsg != 0 {
+		opts.MaxEntriesPerMsg = uint64(options.MaxEntriesPerMsg)
+	}
+	if options.SnapshotIntervalBlocks != 0 {
+		opts.SnapshotInterval = uint64(options.SnapshotIntervalBlocks)
+	}
+
+	if opts.ElectionTick <= opts.HeartbeatTick {
+		return opts, errors.Errorf("election tick (%d) must be greater than heartbeat tick (%d)", opts.ElectionTick, opts.HeartbeatTick)
+	}
+	return opts, nil
+}
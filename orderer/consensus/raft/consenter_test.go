@@ -0,0 +1,74 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package raft
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/context"
+)
+
+func TestRemoveChain(t *testing.T) {
+	dir, err := ioutil.TempDir("", "raft-consenter")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	c := &consenter{
+		comm:    NewComm(nil, 0),
+		walDir:  filepath.Join(dir, "wal"),
+		snapDir: filepath.Join(dir, "snapshot"),
+	}
+	c.comm.register("foo", &mockHandler{})
+	c.comm.register("bar", &mockHandler{})
+	for _, channel := range []string{"foo", "bar"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(c.walDir, channel), 0755))
+		require.NoError(t, os.MkdirAll(filepath.Join(c.snapDir, channel), 0755))
+	}
+
+	assert.NoError(t, c.RemoveChain("foo"))
+
+	_, err = c.comm.Pull(context.Background(), &ab.PullRequest{Channel: "foo"})
+	assert.EqualError(t, err, "channel foo doesn't exist")
+	for _, path := range []string{filepath.Join(c.walDir, "foo"), filepath.Join(c.snapDir, "foo")} {
+		_, err = os.Stat(path)
+		assert.True(t, os.IsNotExist(err), "expected %s to be removed", path)
+	}
+	for _, path := range []string{filepath.Join(c.walDir, "bar"), filepath.Join(c.snapDir, "bar")} {
+		_, err = os.Stat(path)
+		assert.NoError(t, err)
+	}
+
+	// removing a chain which does not exist is not an error
+	assert.NoError(t, c.RemoveChain("baz"))
+}
+
+func TestValidateConsensusMetadata(t *testing.T) {
+	c := &consenter{}
+
+	metadata := utils.MarshalOrPanic(&ab.RaftConfigMetadata{Consenters: testConsenters(1, 2, 3)})
+	assert.NoError(t, c.ValidateConsensusMetadata(metadata))
+
+	err := c.ValidateConsensusMetadata(nil)
+	assert.EqualError(t, err, "no consenters are defined in the consensus metadata")
+
+	err = c.ValidateConsensusMetadata([]byte("garbage"))
+	assert.Error(t, err)
+
+	metadata = utils.MarshalOrPanic(&ab.RaftConfigMetadata{
+		Consenters: testConsenters(1, 2, 3),
+		Options:    &ab.RaftOptions{ElectionTick: 1, HeartbeatTick: 1},
+	})
+	err = c.ValidateConsensusMetadata(metadata)
+	assert.EqualError(t, err, "election tick (1) must be greater than heartbeat tick (1)")
+}
@@ -0,0 +1,86 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package raft
+
+import (
+	"github.com/coreos/etcd/raft"
+	"github.com/coreos/etcd/raft/raftpb"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+)
+
+// maxInflightMsgs is the number of append messages the leader sends to a
+// follower without waiting for their acknowledgement.
+const maxInflightMsgs = 256
+
+// node wraps the etcd raft.Node of a chain, and sends the messages it
+// produces to the other consenters of the channel.
+type node struct {
+	raft.Node
+	channelID string
+	rpc       RPC
+	storage   *storage
+}
+
+// startNode starts the raft.Node of a chain. A node without any persisted
+// state bootstraps the cluster with the given voters, unless it joins an
+// existing cluster, in which case it learns about the cluster from the
+// leader.
+func startNode(channelID string, config *raft.Config, st *storage, rpc RPC, voters []uint64, join bool) *node {
+	n := &node{channelID: channelID, rpc: rpc, storage: st}
+	if !st.fresh {
+		logger.Infof("[channel: %s] Restarting Raft node %d", channelID, config.ID)
+		n.Node = raft.RestartNode(config)
+		return n
+	}
+	if join {
+		logger.Infof("[channel: %s] Starting Raft node %d to join an existing cluster", channelID, config.ID)
+		n.Node = raft.RestartNode(config)
+		return n
+	}
+
+	logger.Infof("[channel: %s] Starting Raft node %d with voters %v", channelID, config.ID, voters)
+	peers := make([]raft.Peer, len(voters))
+	for i, id := range voters {
+		peers[i] = raft.Peer{ID: id}
+	}
+	n.Node = raft.StartNode(config, peers)
+	return n
+}
+
+// send sends the messages to the other consenters. The consenters which
+// can't be reached, and the snapshots which can't be sent, are reported to
+// the raft.Node.
+func (n *node) send(msgs []raftpb.Message) {
+	for _, msg := range msgs {
+		if msg.To == 0 {
+			continue
+		}
+		payload, err := msg.Marshal()
+		if err != nil {
+			logger.Panicf("[channel: %s] Failed to marshal Raft message: %s", n.channelID, err)
+		}
+
+		err = n.rpc.SendConsensus(msg.To, &ab.ConsensusRequest{Channel: n.channelID, Payload: payload})
+		if err != nil {
+			logger.Debugf("[channel: %s] Failed to send %s to %d: %s", n.channelID, msg.Type, msg.To, err)
+			n.ReportUnreachable(msg.To)
+		}
+		if msg.Type == raftpb.MsgSnap {
+			status := raft.SnapshotFinish
+			if err != nil {
+				status = raft.SnapshotFailure
+			}
+			n.ReportSnapshot(msg.To, status)
+		}
+	}
+}
+
+// lastIndex returns the index of the last persisted entry of the log.
+func (n *node) lastIndex() uint64 {
+	i, _ := n.storage.ram.LastIndex()
+	return i
+}
@@ -0,0 +1,137 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package raft
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/coreos/etcd/raft"
+	"github.com/coreos/etcd/raft/raftpb"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingRPC records the consensus messages sent by a node.
+type recordingRPC struct {
+	lock sync.Mutex
+	sent []*ab.ConsensusRequest
+	err  error
+}
+
+func (r *recordingRPC) SendConsensus(dest uint64, msg *ab.ConsensusRequest) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.sent = append(r.sent, msg)
+	return r.err
+}
+
+func (r *recordingRPC) SendSubmit(dest uint64, req *ab.SubmitRequest) (*ab.SubmitResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *recordingRPC) Pull(dest uint64, req *ab.PullRequest) (*ab.PullResponse, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *recordingRPC) Configure(consenters map[uint64]*ab.RaftConsenter) {}
+
+func newTestNode(t *testing.T, id uint64, voters []uint64, join bool, rpc RPC) (*node, func()) {
+	walDir, snapDir, cleanup := newTestStorageDirs(t)
+	st, err := openStorage(walDir, snapDir)
+	require.NoError(t, err)
+	config := &raft.Config{
+		ID:              id,
+		ElectionTick:    10,
+		HeartbeatTick:   1,
+		Storage:         st.ram,
+		MaxSizePerMsg:   1024 * 1024,
+		MaxInflightMsgs: maxInflightMsgs,
+		PreVote:         true,
+		Logger:          logger,
+	}
+	n := startNode(testChannel, config, st, rpc, voters, join)
+	return n, func() {
+		n.Stop()
+		st.close()
+		cleanup()
+	}
+}
+
+// runNode ticks the node and processes its ready until the condition holds.
+func runNode(t *testing.T, n *node, done func(raft.Ready) bool) {
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case rd := <-n.Ready():
+			require.NoError(t, n.storage.store(rd.Entries, rd.HardState, rd.Snapshot))
+			n.send(rd.Messages)
+			for _, e := range rd.CommittedEntries {
+				if e.Type == raftpb.EntryConfChange {
+					var cc raftpb.ConfChange
+					require.NoError(t, cc.Unmarshal(e.Data))
+					n.ApplyConfChange(cc)
+				}
+			}
+			n.Advance()
+			if done(rd) {
+				return
+			}
+		case <-time.After(time.Millisecond):
+			n.Tick()
+		case <-timeout:
+			t.Fatal("timed out")
+		}
+	}
+}
+
+func TestStartNodeElectsSingleVoter(t *testing.T) {
+	n, cleanup := newTestNode(t, 1, []uint64{1}, false, &recordingRPC{})
+	defer cleanup()
+
+	runNode(t, n, func(rd raft.Ready) bool {
+		return rd.SoftState != nil && rd.SoftState.Lead == 1
+	})
+	assert.Equal(t, raft.StateLeader, n.Status().RaftState)
+	// the bootstrapping config changes and the empty entry of the leader
+	assert.Equal(t, uint64(2), n.lastIndex())
+}
+
+func TestStartNodeJoining(t *testing.T) {
+	n, cleanup := newTestNode(t, 2, []uint64{1, 2}, true, &recordingRPC{})
+	defer cleanup()
+
+	// a joining node waits for the leader to replicate the cluster to it
+	for i := 0; i < 50; i++ {
+		n.Tick()
+	}
+	assert.Equal(t, raft.StateFollower, n.Status().RaftState)
+	assert.Zero(t, n.Status().Lead)
+	assert.Zero(t, n.lastIndex())
+}
+
+func TestNodeSend(t *testing.T) {
+	rpc := &recordingRPC{err: errors.New("unreachable")}
+	n, cleanup := newTestNode(t, 1, []uint64{1, 2}, false, rpc)
+	defer cleanup()
+
+	runNode(t, n, func(rd raft.Ready) bool {
+		return len(rd.Messages) > 0
+	})
+	rpc.lock.Lock()
+	defer rpc.lock.Unlock()
+	require.NotEmpty(t, rpc.sent)
+	assert.Equal(t, testChannel, rpc.sent[0].Channel)
+	msg := raftpb.Message{}
+	require.NoError(t, msg.Unmarshal(rpc.sent[0].Payload))
+	assert.Equal(t, raftpb.MsgPreVote, msg.Type)
+	assert.Equal(t, uint64(1), msg.From)
+	assert.Equal(t, uint64(2), msg.To)
+}
@@ -0,0 +1,179 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package raft
+
+import (
+	"io"
+	"os"
+	"time"
+
+	"github.com/coreos/etcd/pkg/fileutil"
+	"github.com/coreos/etcd/raft"
+	"github.com/coreos/etcd/raft/raftpb"
+	"github.com/coreos/etcd/snap"
+	"github.com/coreos/etcd/wal"
+	"github.com/coreos/etcd/wal/walpb"
+	"github.com/pkg/errors"
+)
+
+const (
+	// snapshotCatchUpEntries is the number of entries kept in memory after a
+	// snapshot, so that slightly lagging followers can catch up from the log
+	// rather than from the snapshot.
+	snapshotCatchUpEntries = 4
+
+	// maxSnapFiles and maxWALFiles are the number of snapshot and WAL files
+	// kept on disk, the older ones are purged every purgeInterval.
+	maxSnapFiles  = 5
+	maxWALFiles   = 5
+	purgeInterval = 30 * time.Second
+)
+
+// storage persists the state of the Raft node of a channel. Hard states and
+// log entries are appended to an etcd WAL and snapshots are written by an
+// etcd Snapshotter. Everything persisted is also applied to the
+// MemoryStorage the raft.Node reads its log from.
+type storage struct {
+	ram   *raft.MemoryStorage
+	wal   *wal.WAL
+	snap  *snap.Snapshotter
+	stopC chan struct{}
+
+	// fresh is true if there was no WAL, i.e. the node starts for the
+	// first time.
+	fresh bool
+}
+
+// openStorage opens, or creates, the storage in the given directories and
+// loads the state recovered from it into the MemoryStorage.
+func openStorage(walDir, snapDir string) (*storage, error) {
+	if err := os.MkdirAll(snapDir, 0755); err != nil {
+		return nil, errors.Wrapf(err, "failed to create directory %s", snapDir)
+	}
+	ss := snap.New(snapDir)
+	snapshot, err := ss.Load()
+	if err != nil && err != snap.ErrNoSnapshot {
+		return nil, errors.Wrap(err, "failed to load snapshot")
+	}
+
+	fresh := !wal.Exist(walDir)
+	if fresh {
+		w, err := wal.Create(walDir, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create WAL in %s", walDir)
+		}
+		w.Close()
+	}
+
+	walSnap := walpb.Snapshot{}
+	if snapshot != nil {
+		walSnap.Index, walSnap.Term = snapshot.Metadata.Index, snapshot.Metadata.Term
+	}
+	w, hs, entries, err := readWAL(walDir, walSnap)
+	if err != nil {
+		return nil, err
+	}
+
+	ram := raft.NewMemoryStorage()
+	if snapshot != nil {
+		if err := ram.ApplySnapshot(*snapshot); err != nil {
+			w.Close()
+			return nil, errors.Wrap(err, "failed to apply snapshot")
+		}
+	}
+	if err := ram.SetHardState(hs); err != nil {
+		w.Close()
+		return nil, errors.Wrap(err, "failed to set hard state")
+	}
+	if err := ram.Append(entries); err != nil {
+		w.Close()
+		return nil, errors.Wrap(err, "failed to append entries")
+	}
+
+	s := &storage{ram: ram, wal: w, snap: ss, stopC: make(chan struct{}), fresh: fresh}
+	fileutil.PurgeFile(snapDir, ".snap", maxSnapFiles, purgeInterval, s.stopC)
+	fileutil.PurgeFile(walDir, ".wal", maxWALFiles, purgeInterval, s.stopC)
+	return s, nil
+}
+
+// readWAL opens the WAL and reads the hard state and the entries following
+// the given snapshot. A partially written record at the end of the WAL is
+// discarded.
+func readWAL(dir string, walSnap walpb.Snapshot) (*wal.WAL, raftpb.HardState, []raftpb.Entry, error) {
+	repaired := false
+	for {
+		w, err := wal.Open(dir, walSnap)
+		if err != nil {
+			return nil, raftpb.HardState{}, nil, errors.Wrapf(err, "failed to open WAL in %s", dir)
+		}
+		_, hs, entries, err := w.ReadAll()
+		if err == nil {
+			return w, hs, entries, nil
+		}
+		w.Close()
+		if err != io.ErrUnexpectedEOF || repaired {
+			return nil, hs, nil, errors.Wrapf(err, "failed to read WAL in %s", dir)
+		}
+		logger.Warningf("Repairing WAL in %s: %s", dir, err)
+		if !wal.Repair(dir) {
+			return nil, hs, nil, errors.Errorf("failed to repair WAL in %s", dir)
+		}
+		repaired = true
+	}
+}
+
+// store persists the given entries, hard state and snapshot, any of which
+// may be empty. It returns once the data has been synced to disk.
+func (s *storage) store(entries []raftpb.Entry, hs raftpb.HardState, snapshot raftpb.Snapshot) error {
+	if !raft.IsEmptySnap(snapshot) {
+		if err := s.saveSnapshot(snapshot); err != nil {
+			return err
+		}
+		if err := s.ram.ApplySnapshot(snapshot); err != nil && err != raft.ErrSnapOutOfDate {
+			return errors.Wrap(err, "failed to apply snapshot")
+		}
+	}
+	if err := s.wal.Save(hs, entries); err != nil {
+		return errors.Wrap(err, "failed to write WAL")
+	}
+	return errors.Wrap(s.ram.Append(entries), "failed to append entries")
+}
+
+// takeSnapshot takes a snapshot of the log up to the given applied index,
+// and compacts the log kept in memory.
+func (s *storage) takeSnapshot(index uint64, cs raftpb.ConfState, data []byte) (raftpb.Snapshot, error) {
+	snapshot, err := s.ram.CreateSnapshot(index, &cs, data)
+	if err != nil {
+		return snapshot, errors.Wrap(err, "failed to create snapshot")
+	}
+	if err := s.saveSnapshot(snapshot); err != nil {
+		return snapshot, err
+	}
+	if index <= snapshotCatchUpEntries {
+		return snapshot, nil
+	}
+	if err := s.ram.Compact(index - snapshotCatchUpEntries); err != nil && err != raft.ErrCompacted {
+		return snapshot, errors.Wrap(err, "failed to compact log")
+	}
+	return snapshot, nil
+}
+
+func (s *storage) saveSnapshot(snapshot raftpb.Snapshot) error {
+	if err := s.snap.SaveSnap(snapshot); err != nil {
+		return errors.Wrap(err, "failed to save snapshot")
+	}
+	walSnap := walpb.Snapshot{Index: snapshot.Metadata.Index, Term: snapshot.Metadata.Term}
+	if err := s.wal.SaveSnapshot(walSnap); err != nil {
+		return errors.Wrap(err, "failed to write snapshot to WAL")
+	}
+	return errors.Wrap(s.wal.ReleaseLockTo(snapshot.Metadata.Index), "failed to release WAL")
+}
+
+func (s *storage) close() error {
+	close(s.stopC)
+	return s.wal.Close()
+}
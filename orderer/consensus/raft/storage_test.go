@@ -0,0 +1,123 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package raft
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/coreos/etcd/raft/raftpb"
+	"github.com/coreos/etcd/wal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	// Don't preallocate 64MB for every WAL created by the tests.
+	wal.SegmentSizeBytes = 1 << 20
+}
+
+func entries(term uint64, from, to uint64) []raftpb.Entry {
+	var es []raftpb.Entry
+	for i := from; i <= to; i++ {
+		es = append(es, raftpb.Entry{Term: term, Index: i, Data: []byte{byte(i)}})
+	}
+	return es
+}
+
+func newTestStorageDirs(t *testing.T) (string, string, func()) {
+	dir, err := ioutil.TempDir("", "raft-storage")
+	require.NoError(t, err)
+	return filepath.Join(dir, "wal"), filepath.Join(dir, "snap"), func() { os.RemoveAll(dir) }
+}
+
+func TestStorageRecovery(t *testing.T) {
+	walDir, snapDir, cleanup := newTestStorageDirs(t)
+	defer cleanup()
+
+	st, err := openStorage(walDir, snapDir)
+	require.NoError(t, err)
+	assert.True(t, st.fresh)
+	last, _ := st.ram.LastIndex()
+	assert.Zero(t, last)
+
+	require.NoError(t, st.store(entries(1, 1, 5), raftpb.HardState{Term: 1, Vote: 1}, raftpb.Snapshot{}))
+	// entries 4 and 5 are overwritten by a new leader
+	hs := raftpb.HardState{Term: 2, Vote: 2, Commit: 3}
+	require.NoError(t, st.store(entries(2, 4, 4), hs, raftpb.Snapshot{}))
+	require.NoError(t, st.close())
+
+	st, err = openStorage(walDir, snapDir)
+	require.NoError(t, err)
+	defer st.close()
+	assert.False(t, st.fresh)
+	recovered, _, err := st.ram.InitialState()
+	require.NoError(t, err)
+	assert.Equal(t, hs, recovered)
+	last, _ = st.ram.LastIndex()
+	assert.Equal(t, uint64(4), last)
+	term, err := st.ram.Term(3)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1), term)
+	term, err = st.ram.Term(4)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(2), term)
+}
+
+func TestStorageSnapshot(t *testing.T) {
+	walDir, snapDir, cleanup := newTestStorageDirs(t)
+	defer cleanup()
+
+	st, err := openStorage(walDir, snapDir)
+	require.NoError(t, err)
+	require.NoError(t, st.store(entries(1, 1, 10), raftpb.HardState{Term: 1, Commit: 10}, raftpb.Snapshot{}))
+
+	cs := raftpb.ConfState{Nodes: []uint64{1}}
+	snap, err := st.takeSnapshot(8, cs, []byte("block"))
+	require.NoError(t, err)
+	assert.Equal(t, uint64(8), snap.Metadata.Index)
+	first, _ := st.ram.FirstIndex()
+	assert.Equal(t, uint64(8-snapshotCatchUpEntries+1), first)
+
+	// a snapshot older than the last one is rejected
+	_, err = st.takeSnapshot(6, cs, []byte("older"))
+	assert.Error(t, err)
+	require.NoError(t, st.store(entries(1, 11, 11), raftpb.HardState{Term: 1, Commit: 11}, raftpb.Snapshot{}))
+	require.NoError(t, st.close())
+
+	st, err = openStorage(walDir, snapDir)
+	require.NoError(t, err)
+	recovered, err := st.ram.Snapshot()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(8), recovered.Metadata.Index)
+	assert.Equal(t, cs, recovered.Metadata.ConfState)
+	assert.Equal(t, []byte("block"), recovered.Data)
+	first, _ = st.ram.FirstIndex()
+	assert.Equal(t, uint64(9), first)
+	last, _ := st.ram.LastIndex()
+	assert.Equal(t, uint64(11), last)
+
+	// a snapshot received from the leader replaces the whole log
+	received := raftpb.Snapshot{
+		Data:     []byte("received"),
+		Metadata: raftpb.SnapshotMetadata{Index: 20, Term: 2, ConfState: cs},
+	}
+	require.NoError(t, st.store(nil, raftpb.HardState{Term: 2, Commit: 20}, received))
+	require.NoError(t, st.close())
+
+	st, err = openStorage(walDir, snapDir)
+	require.NoError(t, err)
+	defer st.close()
+	recovered, err = st.ram.Snapshot()
+	require.NoError(t, err)
+	assert.Equal(t, uint64(20), recovered.Metadata.Index)
+	assert.Equal(t, []byte("received"), recovered.Data)
+	last, _ = st.ram.LastIndex()
+	assert.Equal(t, uint64(20), last)
+}
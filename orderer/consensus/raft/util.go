@@ -0,0 +1,220 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package raft
+
+import (
+	"bytes"
+	"encoding/pem"
+	"time"
+
+	"github.com/coreos/etcd/raft/raftpb"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/channelconfig"
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+)
+
+// ConsensusType is the name of the Raft consensus type in the channel config.
+const ConsensusType = "raft"
+
+const (
+	defaultTickInterval           = 500 * time.Millisecond
+	defaultElectionTick           = 10
+	defaultHeartbeatTick          = 1
+	defaultMaxEntriesPerMsg       = 10
+	defaultSnapshotIntervalBlocks = 100
+)
+
+// readConfigMetadata unmarshals the consensus metadata of the channel config.
+func readConfigMetadata(metadata []byte) (*ab.RaftConfigMetadata, error) {
+	m := &ab.RaftConfigMetadata{}
+	if err := proto.Unmarshal(metadata, m); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal consensus metadata")
+	}
+	if len(m.Consenters) == 0 {
+		return nil, errors.New("no consenters are defined in the consensus metadata")
+	}
+	return m, nil
+}
+
+// readBlockMetadata returns the Raft metadata stored in the orderer metadata
+// of the last block. For a new chain there is no such metadata, in which case
+// the IDs of the consenters are assigned in the order of the config.
+func readBlockMetadata(metadata *cb.Metadata, configMetadata *ab.RaftConfigMetadata) (*ab.RaftBlockMetadata, error) {
+	if metadata == nil || len(metadata.Value) == 0 {
+		m := &ab.RaftBlockMetadata{NextConsenterId: 1}
+		for range configMetadata.Consenters {
+			m.ConsenterIds = append(m.ConsenterIds, m.NextConsenterId)
+			m.NextConsenterId++
+		}
+		return m, nil
+	}
+
+	m := &ab.RaftBlockMetadata{}
+	if err := proto.Unmarshal(metadata.Value, m); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal block metadata")
+	}
+	if len(m.ConsenterIds) != len(configMetadata.Consenters) {
+		return nil, errors.Errorf("block metadata has %d consenter IDs but the config has %d consenters",
+			len(m.ConsenterIds), len(configMetadata.Consenters))
+	}
+	return m, nil
+}
+
+// consentersByID maps the consenter IDs of the block metadata to the
+// consenters of the config.
+func consentersByID(blockMetadata *ab.RaftBlockMetadata, consenters []*ab.RaftConsenter) map[uint64]*ab.RaftConsenter {
+	m := make(map[uint64]*ab.RaftConsenter, len(consenters))
+	for i, id := range blockMetadata.ConsenterIds {
+		m[id] = consenters[i]
+	}
+	return m
+}
+
+// membershipChange computes the block metadata resulting from replacing the
+// current consenters with the given ones. Consenters that remain keep their
+// IDs, and new consenters are assigned fresh IDs. It also returns the number
+// of consenters added and removed.
+func membershipChange(blockMetadata *ab.RaftBlockMetadata, current map[uint64]*ab.RaftConsenter, consenters []*ab.RaftConsenter) (*ab.RaftBlockMetadata, map[uint64]*ab.RaftConsenter, int) {
+	m := &ab.RaftBlockMetadata{NextConsenterId: blockMetadata.NextConsenterId, RaftIndex: blockMetadata.RaftIndex}
+	byID := make(map[uint64]*ab.RaftConsenter, len(consenters))
+	changes := 0
+	for _, c := range consenters {
+		id, exists := consenterID(current, c)
+		if !exists {
+			id = m.NextConsenterId
+			m.NextConsenterId++
+			changes++
+		}
+		m.ConsenterIds = append(m.ConsenterIds, id)
+		byID[id] = c
+	}
+	for id := range current {
+		if _, exists := byID[id]; !exists {
+			changes++
+		}
+	}
+	return m, byID, changes
+}
+
+// confChange returns the change of the Raft cluster needed to converge
+// towards the given consenter IDs. As the consenters are added or removed
+// one at a time, there is at most one such change pending.
+func confChange(confState raftpb.ConfState, consenterIDs []uint64) (raftpb.ConfChange, bool) {
+	nodes := make(map[uint64]bool, len(confState.Nodes))
+	for _, id := range confState.Nodes {
+		nodes[id] = true
+	}
+	for _, id := range consenterIDs {
+		if !nodes[id] {
+			return raftpb.ConfChange{Type: raftpb.ConfChangeAddNode, NodeID: id}, true
+		}
+		delete(nodes, id)
+	}
+	for _, id := range confState.Nodes {
+		if nodes[id] {
+			return raftpb.ConfChange{Type: raftpb.ConfChangeRemoveNode, NodeID: id}, true
+		}
+	}
+	return raftpb.ConfChange{}, false
+}
+
+func consenterID(consenters map[uint64]*ab.RaftConsenter, c *ab.RaftConsenter) (uint64, bool) {
+	for id, existing := range consenters {
+		if proto.Equal(existing, c) {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// configEnvelopeConsenters returns the consenters defined by the config
+// contained in the given envelope, or nil if the envelope isn't a config
+// transaction or migrates the channel to another consensus type.
+func configEnvelopeConsenters(env *cb.Envelope) ([]*ab.RaftConsenter, error) {
+	payload, err := utils.UnmarshalPayload(env.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if payload.Header == nil {
+		return nil, errors.New("missing header")
+	}
+	chdr, err := utils.UnmarshalChannelHeader(payload.Header.ChannelHeader)
+	if err != nil {
+		return nil, err
+	}
+	if chdr.Type != int32(cb.HeaderType_CONFIG) {
+		return nil, nil
+	}
+
+	configEnv := &cb.ConfigEnvelope{}
+	if err := proto.Unmarshal(payload.Data, configEnv); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal config envelope")
+	}
+	if configEnv.Config == nil || configEnv.Config.ChannelGroup == nil {
+		return nil, errors.New("config envelope has no channel group")
+	}
+	ordererGroup, exists := configEnv.Config.ChannelGroup.Groups[channelconfig.OrdererGroupKey]
+	if !exists {
+		return nil, errors.New("config has no orderer group")
+	}
+	value, exists := ordererGroup.Values[channelconfig.ConsensusTypeKey]
+	if !exists {
+		return nil, errors.New("orderer config has no consensus type")
+	}
+	consensusType := &ab.ConsensusType{}
+	if err := proto.Unmarshal(value.Value, consensusType); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal consensus type")
+	}
+	if consensusType.Type != ConsensusType {
+		// the channel is migrated to another consensus type, the chain is
+		// halted once the config block is written
+		return nil, nil
+	}
+	configMetadata, err := readConfigMetadata(consensusType.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	return configMetadata.Consenters, nil
+}
+
+// blockConsenters returns the consenters defined by the given block if it is
+// a config block, or nil otherwise.
+func blockConsenters(block *cb.Block) ([]*ab.RaftConsenter, error) {
+	if !utils.IsConfigBlock(block) {
+		return nil, nil
+	}
+	env, err := utils.ExtractEnvelope(block, 0)
+	if err != nil {
+		return nil, err
+	}
+	return configEnvelopeConsenters(env)
+}
+
+// detectSelfID returns the ID of the consenter whose server certificate is
+// the given one.
+func detectSelfID(consenters map[uint64]*ab.RaftConsenter, serverCert []byte) (uint64, error) {
+	der := certDER(serverCert)
+	for id, c := range consenters {
+		if bytes.Equal(certDER(c.ServerTlsCert), der) {
+			return id, nil
+		}
+	}
+	return 0, errors.New("failed to detect own Raft ID because no matching certificate found")
+}
+
+// certDER returns the DER encoding of a PEM encoded certificate, or the given
+// bytes if they are not PEM encoded.
+func certDER(cert []byte) []byte {
+	block, _ := pem.Decode(cert)
+	if block == nil {
+		return cert
+	}
+	return block.Bytes
+}
@@ -0,0 +1,138 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package raft
+
+import (
+	"testing"
+	"time"
+
+	cb "github.com/hyperledger/fabric/protos/common"
+	ab "github.com/hyperledger/fabric/protos/orderer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadBlockMetadata(t *testing.T) {
+	configMetadata := &ab.RaftConfigMetadata{Consenters: testConsenters(1, 2, 3)}
+
+	m, err := readBlockMetadata(&cb.Metadata{}, configMetadata)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{1, 2, 3}, m.ConsenterIds)
+	assert.Equal(t, uint64(4), m.NextConsenterId)
+
+	stored := &ab.RaftBlockMetadata{ConsenterIds: []uint64{2, 5, 7}, NextConsenterId: 8, RaftIndex: 42}
+	m, err = readBlockMetadata(&cb.Metadata{Value: utils.MarshalOrPanic(stored)}, configMetadata)
+	require.NoError(t, err)
+	assert.Equal(t, stored.ConsenterIds, m.ConsenterIds)
+	assert.Equal(t, uint64(42), m.RaftIndex)
+
+	byID := consentersByID(m, configMetadata.Consenters)
+	assert.Equal(t, "orderer2", byID[5].Host)
+
+	_, err = readBlockMetadata(&cb.Metadata{Value: utils.MarshalOrPanic(&ab.RaftBlockMetadata{ConsenterIds: []uint64{1}})}, configMetadata)
+	assert.EqualError(t, err, "block metadata has 1 consenter IDs but the config has 3 consenters")
+
+	_, err = readBlockMetadata(&cb.Metadata{Value: []byte("garbage")}, configMetadata)
+	assert.Error(t, err)
+}
+
+func TestMembershipChange(t *testing.T) {
+	current := &ab.RaftBlockMetadata{ConsenterIds: []uint64{1, 2, 3}, NextConsenterId: 4, RaftIndex: 10}
+	consenters := consentersByID(current, testConsenters(1, 2, 3))
+
+	m, byID, changes := membershipChange(current, consenters, testConsenters(1, 2, 3))
+	assert.Equal(t, 0, changes)
+	assert.Equal(t, []uint64{1, 2, 3}, m.ConsenterIds)
+	assert.Len(t, byID, 3)
+
+	m, byID, changes = membershipChange(current, consenters, testConsenters(3, 1, 4))
+	assert.Equal(t, 2, changes)
+	assert.Equal(t, []uint64{3, 1, 4}, m.ConsenterIds)
+	assert.Equal(t, uint64(5), m.NextConsenterId)
+	assert.Equal(t, uint64(10), m.RaftIndex)
+	assert.Equal(t, "orderer4", byID[4].Host)
+
+	// a consenter with a new certificate is a new consenter
+	updated := testConsenters(1, 2, 3)
+	updated[0].ServerTlsCert = []byte("renewed")
+	m, _, changes = membershipChange(current, consenters, updated)
+	assert.Equal(t, 2, changes)
+	assert.Equal(t, []uint64{4, 2, 3}, m.ConsenterIds)
+}
+
+func TestConfigEnvelopeConsenters(t *testing.T) {
+	consenters, err := configEnvelopeConsenters(configEnvelope(t, testConsenters(1, 2)))
+	require.NoError(t, err)
+	assert.Len(t, consenters, 2)
+
+	consenters, err = configEnvelopeConsenters(testEnvelope(t, cb.HeaderType_ORDERER_TRANSACTION, nil))
+	assert.NoError(t, err)
+	assert.Nil(t, consenters)
+
+	_, err = configEnvelopeConsenters(configEnvelope(t, nil))
+	assert.EqualError(t, err, "no consenters are defined in the consensus metadata")
+
+	// a config migrating the channel to another consensus type
+	migration := &cb.ConfigEnvelope{
+		Config: &cb.Config{
+			ChannelGroup: &cb.ConfigGroup{
+				Groups: map[string]*cb.ConfigGroup{
+					"Orderer": {
+						Values: map[string]*cb.ConfigValue{
+							"ConsensusType": {Value: utils.MarshalOrPanic(&ab.ConsensusType{Type: "kafka", State: ab.ConsensusType_STATE_MAINTENANCE})},
+						},
+					},
+				},
+			},
+		},
+	}
+	consenters, err = configEnvelopeConsenters(testEnvelope(t, cb.HeaderType_CONFIG, utils.MarshalOrPanic(migration)))
+	assert.NoError(t, err)
+	assert.Nil(t, consenters)
+
+	_, err = configEnvelopeConsenters(testEnvelope(t, cb.HeaderType_CONFIG, []byte("garbage")))
+	assert.Error(t, err)
+}
+
+func TestDetectSelfID(t *testing.T) {
+	consenters := map[uint64]*ab.RaftConsenter{
+		1: {ServerTlsCert: []byte("-----BEGIN CERTIFICATE-----\nYWJj\n-----END CERTIFICATE-----\n")},
+		2: {ServerTlsCert: []byte("-----BEGIN CERTIFICATE-----\nZGVm\n-----END CERTIFICATE-----\n")},
+	}
+	id, err := detectSelfID(consenters, []byte("-----BEGIN CERTIFICATE-----\nZGVm\n-----END CERTIFICATE-----\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(2), id)
+
+	_, err = detectSelfID(consenters, []byte("-----BEGIN CERTIFICATE-----\nZ2hp\n-----END CERTIFICATE-----\n"))
+	assert.EqualError(t, err, "failed to detect own Raft ID because no matching certificate found")
+}
+
+func TestChainOptions(t *testing.T) {
+	opts, err := chainOptions(nil)
+	require.NoError(t, err)
+	assert.Equal(t, defaultTickInterval, opts.TickInterval)
+	assert.Equal(t, uint64(defaultSnapshotIntervalBlocks), opts.SnapshotInterval)
+
+	opts, err = chainOptions(&ab.RaftOptions{TickInterval: "100ms", ElectionTick: 20, HeartbeatTick: 2, MaxEntriesPerMsg: 5})
+	require.NoError(t, err)
+	assert.Equal(t, 100*time.Millisecond, opts.TickInterval)
+	assert.Equal(t, 20, opts.ElectionTick)
+	assert.Equal(t, 2, opts.HeartbeatTick)
+	assert.Equal(t, uint64(5), opts.MaxEntriesPerMsg)
+	assert.Equal(t, uint64(defaultSnapshotIntervalBlocks), opts.SnapshotInterval)
+
+	opts, err = chainOptions(&ab.RaftOptions{SnapshotIntervalBlocks: 10})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(10), opts.SnapshotInterval)
+
+	_, err = chainOptions(&ab.RaftOptions{TickInterval: "soon"})
+	assert.Error(t, err)
+
+	_, err = chainOptions(&ab.RaftOptions{ElectionTick: 1, HeartbeatTick: 1})
+	assert.EqualError(t, err, "election tick (1) must be greater than heartbeat tick (1)")
+}
@@ -60,6 +60,9 @@ type ConsenterSupport struct {
 
 	// SequenceVal is returned by Sequence
 	SequenceVal uint64
+
+	// BlockByIndex maps block numbers to the blocks returned by Block
+	BlockByIndex map[uint64]*cb.Block
 }
 
 // BlockCutter returns BlockCutterVal
@@ -108,6 +111,11 @@ func (mcs *ConsenterSupport) Height() uint64 {
 	return mcs.HeightVal
 }
 
+// Block returns the block with the given number from BlockByIndex
+func (mcs *ConsenterSupport) Block(number uint64) *cb.Block {
+	return mcs.BlockByIndex[number]
+}
+
 // Sign returns the bytes passed in
 func (mcs *ConsenterSupport) Sign(message []byte) ([]byte, error) {
 	return message, nil
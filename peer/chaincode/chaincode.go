@@ -64,6 +64,7 @@ var (
 	transient             string
 	collectionsConfigFile string
 	collectionConfigBytes []byte
+	connectionFile        string
 )
 
 var chaincodeCmd = &cobra.Command{
@@ -111,6 +112,8 @@ func resetFlags() {
 		"Get the instantiated chaincodes on a channel")
 	flags.StringVar(&collectionsConfigFile, "collections-config", common.UndefinedParamValue,
 		fmt.Sprint("The file containing the configuration for the chaincode's collection"))
+	flags.StringVar(&connectionFile, "connection", common.UndefinedParamValue,
+		fmt.Sprint("The file containing the connection information of a chaincode that runs as an external service"))
 }
 
 func attachFlags(cmd *cobra.Command, names []string) {
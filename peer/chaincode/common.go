@@ -19,6 +19,7 @@ import (
 	"github.com/hyperledger/fabric/core/chaincode/platforms"
 	"github.com/hyperledger/fabric/core/chaincode/shim"
 	"github.com/hyperledger/fabric/core/container"
+	"github.com/hyperledger/fabric/core/container/externalcontroller"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/peer/common"
 	pcommon "github.com/hyperledger/fabric/protos/common"
@@ -48,7 +49,15 @@ func checkSpec(spec *pb.ChaincodeSpec) error {
 // getChaincodeDeploymentSpec get chaincode deployment spec given the chaincode spec
 func getChaincodeDeploymentSpec(spec *pb.ChaincodeSpec, crtPkg bool) (*pb.ChaincodeDeploymentSpec, error) {
 	var codePackageBytes []byte
-	if chaincode.IsDevMode() == false && crtPkg {
+	execEnv := pb.ChaincodeDeploymentSpec_DOCKER
+	if chaincode.IsDevMode() == false && crtPkg && connectionFile != common.UndefinedParamValue {
+		var err error
+		codePackageBytes, err = getExternalCodePackageFromFile(connectionFile)
+		if err != nil {
+			return nil, err
+		}
+		execEnv = pb.ChaincodeDeploymentSpec_EXTERNAL
+	} else if chaincode.IsDevMode() == false && crtPkg {
 		var err error
 		if err = checkSpec(spec); err != nil {
 			return nil, err
@@ -60,10 +69,27 @@ func getChaincodeDeploymentSpec(spec *pb.ChaincodeSpec, crtPkg bool) (*pb.Chainc
 			return nil, err
 		}
 	}
-	chaincodeDeploymentSpec := &pb.ChaincodeDeploymentSpec{ChaincodeSpec: spec, CodePackage: codePackageBytes}
+	chaincodeDeploymentSpec := &pb.ChaincodeDeploymentSpec{ChaincodeSpec: spec, CodePackage: codePackageBytes, ExecEnv: execEnv}
 	return chaincodeDeploymentSpec, nil
 }
 
+// getExternalCodePackageFromFile creates the code package of a chaincode
+// that runs as an external service; the supplied file must contain the
+// json-formatted connection information of the chaincode server
+func getExternalCodePackageFromFile(connFile string) ([]byte, error) {
+	fileBytes, err := ioutil.ReadFile(connFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "could not read file '%s'", connFile)
+	}
+
+	conn := &externalcontroller.Connection{}
+	if err := json.Unmarshal(fileBytes, conn); err != nil {
+		return nil, errors.Wrap(err, "could not parse the connection information")
+	}
+
+	return externalcontroller.CreateCodePackage(conn)
+}
+
 // getChaincodeSpec get chaincode spec from the cli cmd pramameters
 func getChaincodeSpec(cmd *cobra.Command) (*pb.ChaincodeSpec, error) {
 	spec := &pb.ChaincodeSpec{}
@@ -163,12 +189,13 @@ type collectionConfigJson struct {
 	Policy        string `json:"policy"`
 	RequiredCount int32  `json:"requiredPeerCount"`
 	MaxPeerCount  int32  `json:"maxPeerCount"`
+	BlockToLive   uint64 `json:"blockToLive"`
 }
 
-// getCollectionConfig retrieves the collection configuration
+// GetCollectionConfigFromFile retrieves the collection configuration
 // from the supplied file; the supplied file must contain a
 // json-formatted array of collectionConfigJson elements
-func getCollectionConfigFromFile(ccFile string) ([]byte, error) {
+func GetCollectionConfigFromFile(ccFile string) ([]byte, error) {
 	fileBytes, err := ioutil.ReadFile(ccFile)
 	if err != nil {
 		return nil, errors.Wrapf(err, "could not read file '%s'", ccFile)
@@ -207,6 +234,7 @@ func getCollectionConfigFromBytes(cconfBytes []byte) ([]byte, error) {
 					MemberOrgsPolicy:  cpc,
 					RequiredPeerCount: cconfitem.RequiredCount,
 					MaximumPeerCount:  cconfitem.MaxPeerCount,
+					BlockToLive:       cconfitem.BlockToLive,
 				},
 			},
 		}
@@ -255,7 +283,7 @@ func checkChaincodeCmdParams(cmd *cobra.Command) error {
 
 	if collectionsConfigFile != common.UndefinedParamValue {
 		var err error
-		collectionConfigBytes, err = getCollectionConfigFromFile(collectionsConfigFile)
+		collectionConfigBytes, err = GetCollectionConfigFromFile(collectionsConfigFile)
 		if err != nil {
 			return errors.WithMessage(err, fmt.Sprintf("invalid collection configuration in file %s", collectionsConfigFile))
 		}
@@ -182,7 +182,8 @@ const sampleCollectionConfigGood = `[
 		"name": "foo",
 		"policy": "OR('A.member', 'B.member')",
 		"requiredPeerCount": 3,
-		"maxPeerCount": 483279847
+		"maxPeerCount": 483279847,
+		"blockToLive": 10
 	}
 ]`
 
@@ -205,6 +206,7 @@ func TestCollectionParsing(t *testing.T) {
 	pol, _ := cauthdsl.FromString("OR('A.member', 'B.member')")
 	assert.Equal(t, 3, int(conf.RequiredPeerCount))
 	assert.Equal(t, 483279847, int(conf.MaximumPeerCount))
+	assert.Equal(t, uint64(10), conf.BlockToLive)
 	assert.Equal(t, "foo", conf.Name)
 	assert.Equal(t, pol, conf.MemberOrgsPolicy.GetSignaturePolicy())
 
@@ -50,6 +50,7 @@ func installCmd(cf *ChaincodeCmdFactory) *cobra.Command {
 		"path",
 		"name",
 		"version",
+		"connection",
 	}
 	attachFlags(chaincodeInstallCmd, flagList)
 
@@ -158,7 +159,10 @@ func chaincodeInstall(cmd *cobra.Command, ccpackfile string, cf *ChaincodeCmdFac
 
 	var ccpackmsg proto.Message
 	if ccpackfile == "" {
-		if chaincodePath == common.UndefinedParamValue || chaincodeVersion == common.UndefinedParamValue || chaincodeName == common.UndefinedParamValue {
+		if chaincodePath == common.UndefinedParamValue && connectionFile == common.UndefinedParamValue {
+			return fmt.Errorf("Must supply value for %s path or connection parameter.", chainFuncName)
+		}
+		if chaincodeVersion == common.UndefinedParamValue || chaincodeName == common.UndefinedParamValue {
 			return fmt.Errorf("Must supply value for %s name, path and version parameters.", chainFuncName)
 		}
 		//generate a raw ChaincodeDeploymentSpec
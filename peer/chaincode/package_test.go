@@ -17,13 +17,16 @@
 package chaincode
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"testing"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
 
+	"github.com/hyperledger/fabric/core/container/externalcontroller"
 	"github.com/hyperledger/fabric/msp"
 	"github.com/hyperledger/fabric/peer/common"
 	pcommon "github.com/hyperledger/fabric/protos/common"
@@ -202,3 +205,45 @@ func TestInvalidPolicy(t *testing.T) {
 		t.Fatalf("Expected error with nil signer but succeeded")
 	}
 }
+
+// TestExternalCDSPackage tests packaging a chaincode that runs as an
+// external service from its connection information
+func TestExternalCDSPackage(t *testing.T) {
+	pdir := newTempDir()
+	defer os.RemoveAll(pdir)
+	defer resetFlags()
+
+	connfile := pdir + "/connection.json"
+	err := ioutil.WriteFile(connfile, []byte(`{"address": "extcc:9999", "dial_timeout": "10s"}`), 0644)
+	assert.NoError(t, err)
+
+	InitMSP()
+	resetFlags()
+	cmd := packageCmd(&ChaincodeCmdFactory{}, nil)
+	addFlags(cmd)
+
+	ccpackfile := pdir + "/ccpack.file"
+	cmd.SetArgs([]string{"-n", "extcc", "-v", "0", "--connection", connfile, ccpackfile})
+	assert.NoError(t, cmd.Execute())
+
+	b, err := ioutil.ReadFile(ccpackfile)
+	assert.NoError(t, err)
+	cds := &pb.ChaincodeDeploymentSpec{}
+	assert.NoError(t, proto.Unmarshal(b, cds))
+	assert.Equal(t, pb.ChaincodeDeploymentSpec_EXTERNAL, cds.ExecEnv)
+
+	conn, err := externalcontroller.ParseConnection(bytes.NewReader(cds.CodePackage))
+	assert.NoError(t, err)
+	assert.Equal(t, "extcc:9999", conn.Address)
+	assert.Equal(t, "10s", conn.DialTimeout)
+
+	err = ioutil.WriteFile(connfile, []byte(`{"dial_timeout": "10s"}`), 0644)
+	assert.NoError(t, err)
+	resetFlags()
+	cmd = packageCmd(&ChaincodeCmdFactory{}, nil)
+	addFlags(cmd)
+	cmd.SetArgs([]string{"-n", "extcc", "-v", "0", "--connection", connfile, ccpackfile})
+	err = cmd.Execute()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "chaincode address must be specified")
+}
@@ -30,8 +30,8 @@ import (
 
 const (
 	channelFuncName = "channel"
-	shortDes        = "Operate a channel: create|fetch|join|list|update|signconfigtx|getinfo."
-	longDes         = "Operate a channel: create|fetch|join|list|update|signconfigtx|getinfo."
+	shortDes        = "Operate a channel: create|fetch|join|joinbysnapshot|exportsnapshot|list|update|signconfigtx|getinfo|getblocks|getheaders|gettxinfo|gettxs."
+	longDes         = "Operate a channel: create|fetch|join|joinbysnapshot|exportsnapshot|list|update|signconfigtx|getinfo|getblocks|getheaders|gettxinfo|gettxs."
 )
 
 var logger = flogging.MustGetLogger("channelCmd")
@@ -49,11 +49,19 @@ const (
 var (
 	// join related variables.
 	genesisBlockPath string
+	snapshotPath     string
 
 	// create related variables
 	channelID     string
 	channelTxFile string
 	timeout       int
+
+	// ledger query related variables
+	startBlock    uint64
+	endBlock      uint64
+	blockNumber   uint64
+	chaincodeName string
+	creatorMspID  string
 )
 
 // Cmd returns the cobra command for Node
@@ -63,10 +71,16 @@ func Cmd(cf *ChannelCmdFactory) *cobra.Command {
 	channelCmd.AddCommand(createCmd(cf))
 	channelCmd.AddCommand(fetchCmd(cf))
 	channelCmd.AddCommand(joinCmd(cf))
+	channelCmd.AddCommand(joinBySnapshotCmd(cf))
+	channelCmd.AddCommand(exportSnapshotCmd(cf))
 	channelCmd.AddCommand(listCmd(cf))
 	channelCmd.AddCommand(updateCmd(cf))
 	channelCmd.AddCommand(signconfigtxCmd(cf))
 	channelCmd.AddCommand(getinfoCmd(cf))
+	channelCmd.AddCommand(getblocksCmd(cf))
+	channelCmd.AddCommand(getheadersCmd(cf))
+	channelCmd.AddCommand(gettxinfoCmd(cf))
+	channelCmd.AddCommand(gettxsCmd(cf))
 
 	return channelCmd
 }
@@ -87,9 +101,15 @@ func resetFlags() {
 	flags = &pflag.FlagSet{}
 
 	flags.StringVarP(&genesisBlockPath, "blockpath", "b", common.UndefinedParamValue, "Path to file containing genesis block")
+	flags.StringVarP(&snapshotPath, "snapshotpath", "", common.UndefinedParamValue, "Path to the directory of a ledger snapshot on the peer")
 	flags.StringVarP(&channelID, "channelID", "c", common.UndefinedParamValue, "In case of a newChain command, the channel ID to create.")
 	flags.StringVarP(&channelTxFile, "file", "f", "", "Configuration transaction file generated by a tool such as configtxgen for submitting to orderer")
 	flags.IntVarP(&timeout, "timeout", "t", 5, "Channel creation timeout")
+	flags.Uint64VarP(&startBlock, "start", "", 0, "Number of the first block of the range to query")
+	flags.Uint64VarP(&endBlock, "end", "", 0, "Number of the last block of the range to query")
+	flags.Uint64VarP(&blockNumber, "blocknum", "", 0, "Number of the block to query")
+	flags.StringVarP(&chaincodeName, "chaincode", "", "", "Name of the chaincode whose transactions are queried")
+	flags.StringVarP(&creatorMspID, "creator", "", "", "ID of the MSP whose members created the queried transactions")
 }
 
 func attachFlags(cmd *cobra.Command, names []string) {
@@ -0,0 +1,273 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/core/scc/qscc"
+	"github.com/hyperledger/fabric/peer/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+func getblocksCmd(cf *ChannelCmdFactory) *cobra.Command {
+	getblocksCmd := &cobra.Command{
+		Use:   "getblocks [outputdir]",
+		Short: "Gets a range of blocks from the ledger of the peer.",
+		Long: "Gets a range of blocks from the ledger of the peer, writing each block to a file named " +
+			"<channelID>_<number>.block in the output directory. Requires '-c', '--start' and '--end'.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getblocks(cf, args)
+		},
+	}
+	flagList := []string{
+		"channelID",
+		"start",
+		"end",
+	}
+	attachFlags(getblocksCmd, flagList)
+
+	return getblocksCmd
+}
+
+func getheadersCmd(cf *ChannelCmdFactory) *cobra.Command {
+	getheadersCmd := &cobra.Command{
+		Use:   "getheaders",
+		Short: "Gets the headers of a range of blocks from the ledger of the peer.",
+		Long:  "Gets the headers of a range of blocks from the ledger of the peer. Requires '-c', '--start' and '--end'.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return getheaders(cf)
+		},
+	}
+	flagList := []string{
+		"channelID",
+		"start",
+		"end",
+	}
+	attachFlags(getheadersCmd, flagList)
+
+	return getheadersCmd
+}
+
+func gettxinfoCmd(cf *ChannelCmdFactory) *cobra.Command {
+	gettxinfoCmd := &cobra.Command{
+		Use:   "gettxinfo",
+		Short: "Gets the transactions of a block with their validation codes.",
+		Long:  "Gets the IDs and validation codes of the transactions of a block. Requires '-c' and '--blocknum'.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return gettxinfo(cf)
+		},
+	}
+	flagList := []string{
+		"channelID",
+		"blocknum",
+	}
+	attachFlags(gettxinfoCmd, flagList)
+
+	return gettxinfoCmd
+}
+
+func gettxsCmd(cf *ChannelCmdFactory) *cobra.Command {
+	gettxsCmd := &cobra.Command{
+		Use:   "gettxs",
+		Short: "Gets the transactions of a chaincode or creator MSP over a range of blocks.",
+		Long: "Gets the transactions invoking a chaincode or created by a member of an MSP over a range of blocks. " +
+			"Requires '-c', '--start', '--end' and either '--chaincode' or '--creator'.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return gettxs(cf)
+		},
+	}
+	flagList := []string{
+		"channelID",
+		"start",
+		"end",
+		"chaincode",
+		"creator",
+	}
+	attachFlags(gettxsCmd, flagList)
+
+	return gettxsCmd
+}
+
+func getblocks(cf *ChannelCmdFactory, args []string) error {
+	if channelID == common.UndefinedParamValue {
+		return errors.New("Must supply channel ID")
+	}
+	if len(args) > 1 {
+		return errors.New("Too many arguments")
+	}
+	outputDir := "."
+	if len(args) == 1 {
+		outputDir = args[0]
+	}
+
+	var err error
+	if cf == nil {
+		cf, err = InitCmdFactory(EndorserRequired, OrdererNotRequired)
+		if err != nil {
+			return err
+		}
+	}
+
+	blocks := &cb.BlockList{}
+	if err = queryQSCC(cf, blocks, []byte(qscc.GetBlocksByRange), []byte(channelID), rangeArg(startBlock), rangeArg(endBlock)); err != nil {
+		return err
+	}
+
+	for _, block := range blocks.Blocks {
+		b, err := proto.Marshal(block)
+		if err != nil {
+			return err
+		}
+		file := filepath.Join(outputDir, fmt.Sprintf("%s_%d.block", channelID, block.Header.Number))
+		if err = ioutil.WriteFile(file, b, 0644); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("Received %d blocks\n", len(blocks.Blocks))
+	return nil
+}
+
+func getheaders(cf *ChannelCmdFactory) error {
+	if channelID == common.UndefinedParamValue {
+		return errors.New("Must supply channel ID")
+	}
+
+	var err error
+	if cf == nil {
+		cf, err = InitCmdFactory(EndorserRequired, OrdererNotRequired)
+		if err != nil {
+			return err
+		}
+	}
+
+	headers := &cb.BlockHeaderList{}
+	if err = queryQSCC(cf, headers, []byte(qscc.GetBlockHeadersByRange), []byte(channelID), rangeArg(startBlock), rangeArg(endBlock)); err != nil {
+		return err
+	}
+	jsonBytes, err := json.Marshal(headers.Headers)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Block headers: %s\n", string(jsonBytes))
+	return nil
+}
+
+func gettxinfo(cf *ChannelCmdFactory) error {
+	if channelID == common.UndefinedParamValue {
+		return errors.New("Must supply channel ID")
+	}
+
+	var err error
+	if cf == nil {
+		cf, err = InitCmdFactory(EndorserRequired, OrdererNotRequired)
+		if err != nil {
+			return err
+		}
+	}
+
+	txs := &cb.TransactionInfoList{}
+	if err = queryQSCC(cf, txs, []byte(qscc.GetTransactionsInfoByBlockNumber), []byte(channelID), rangeArg(blockNumber)); err != nil {
+		return err
+	}
+	printTransactionsInfo(txs)
+	return nil
+}
+
+func gettxs(cf *ChannelCmdFactory) error {
+	if channelID == common.UndefinedParamValue {
+		return errors.New("Must supply channel ID")
+	}
+	if (chaincodeName == "") == (creatorMspID == "") {
+		return errors.New("Must supply either a chaincode name or a creator MSP ID")
+	}
+
+	var err error
+	if cf == nil {
+		cf, err = InitCmdFactory(EndorserRequired, OrdererNotRequired)
+		if err != nil {
+			return err
+		}
+	}
+
+	function, filter := qscc.GetTransactionsByChaincode, chaincodeName
+	if creatorMspID != "" {
+		function, filter = qscc.GetTransactionsByCreator, creatorMspID
+	}
+
+	txs := &cb.TransactionInfoList{}
+	if err = queryQSCC(cf, txs, []byte(function), []byte(channelID), rangeArg(startBlock), rangeArg(endBlock), []byte(filter)); err != nil {
+		return err
+	}
+	printTransactionsInfo(txs)
+	return nil
+}
+
+func printTransactionsInfo(txs *cb.TransactionInfoList) {
+	for _, tx := range txs.Transactions {
+		fmt.Printf("Block %d, transaction %d: ID %s, type %s, chaincode %q, creator %q, validation code %s\n",
+			tx.BlockNum, tx.TxNum, tx.TxId, tx.Type, tx.Chaincode, tx.CreatorMspId,
+			pb.TxValidationCode(tx.ValidationCode))
+	}
+}
+
+func rangeArg(n uint64) []byte {
+	return []byte(strconv.FormatUint(n, 10))
+}
+
+// queryQSCC sends a proposal for invoking qscc with the given args to the peer and
+// unmarshals the payload of the response into msg
+func queryQSCC(cf *ChannelCmdFactory, msg proto.Message, args ...[]byte) error {
+	invocation := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_Type(pb.ChaincodeSpec_Type_value["GOLANG"]),
+			ChaincodeId: &pb.ChaincodeID{Name: "qscc"},
+			Input:       &pb.ChaincodeInput{Args: args},
+		},
+	}
+
+	creator, err := cf.Signer.Serialize()
+	if err != nil {
+		return errors.WithMessage(err, "cannot serialize identity")
+	}
+
+	prop, _, err := utils.CreateProposalFromCIS(cb.HeaderType_ENDORSER_TRANSACTION, "", invocation, creator)
+	if err != nil {
+		return errors.WithMessage(err, "cannot create proposal")
+	}
+
+	signedProp, err := utils.GetSignedProposal(prop, cf.Signer)
+	if err != nil {
+		return errors.WithMessage(err, "cannot create signed proposal")
+	}
+
+	proposalResp, err := cf.EndorserClient.ProcessProposal(context.Background(), signedProp)
+	if err != nil {
+		return errors.WithMessage(err, "failed sending proposal")
+	}
+
+	if proposalResp == nil || proposalResp.Response == nil {
+		return errors.New("received nil proposal response")
+	}
+
+	if proposalResp.Response.Status != 200 {
+		return errors.Errorf("received bad response, status %d: %s", proposalResp.Response.Status, proposalResp.Response.Message)
+	}
+
+	return errors.Wrap(proto.Unmarshal(proposalResp.Response.Payload, msg), "cannot read qscc response")
+}
@@ -0,0 +1,131 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/peer/common"
+	cb "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mockQSCCCmdFactory(t *testing.T, status int32, msg proto.Message) *ChannelCmdFactory {
+	InitMSP()
+	resetFlags()
+
+	mockPayload, err := proto.Marshal(msg)
+	require.NoError(t, err)
+
+	mockResponse := &pb.ProposalResponse{
+		Response: &pb.Response{
+			Status:  status,
+			Payload: mockPayload,
+		},
+		Endorsement: &pb.Endorsement{},
+	}
+
+	signer, err := common.GetDefaultSigner()
+	require.NoError(t, err)
+
+	return &ChannelCmdFactory{
+		EndorserClient:   common.GetMockEndorserClient(mockResponse, nil),
+		BroadcastFactory: mockBroadcastClientFactory,
+		Signer:           signer,
+	}
+}
+
+func TestGetBlocks(t *testing.T) {
+	blocks := &cb.BlockList{Blocks: []*cb.Block{cb.NewBlock(0, nil), cb.NewBlock(1, []byte("hash"))}}
+	mockCF := mockQSCCCmdFactory(t, 200, blocks)
+
+	dir, err := ioutil.TempDir("", "getblocks")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	cmd := getblocksCmd(mockCF)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"-c", mockChannel, "--start", "0", "--end", "1", dir})
+	assert.NoError(t, cmd.Execute())
+
+	for _, block := range blocks.Blocks {
+		b, err := ioutil.ReadFile(filepath.Join(dir, fmt.Sprintf("%s_%d.block", mockChannel, block.Header.Number)))
+		assert.NoError(t, err)
+		readBlock := &cb.Block{}
+		assert.NoError(t, proto.Unmarshal(b, readBlock))
+		assert.True(t, proto.Equal(block, readBlock))
+	}
+
+	mockCF = mockQSCCCmdFactory(t, 500, blocks)
+	cmd = getblocksCmd(mockCF)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"-c", mockChannel, "--start", "0", "--end", "1", dir})
+	assert.EqualError(t, cmd.Execute(), "received bad response, status 500: ")
+}
+
+func TestGetHeaders(t *testing.T) {
+	headers := &cb.BlockHeaderList{Headers: []*cb.BlockHeader{{Number: 3, DataHash: []byte("hash")}}}
+	mockCF := mockQSCCCmdFactory(t, 200, headers)
+
+	cmd := getheadersCmd(mockCF)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"-c", mockChannel, "--start", "3", "--end", "3"})
+	assert.NoError(t, cmd.Execute())
+}
+
+func TestGetTxInfo(t *testing.T) {
+	txs := &cb.TransactionInfoList{
+		Transactions: []*cb.TransactionInfo{
+			{BlockNum: 3, TxNum: 0, TxId: "tx1", Type: cb.HeaderType_ENDORSER_TRANSACTION, Chaincode: "mycc", CreatorMspId: "Org1MSP"},
+			{BlockNum: 3, TxNum: 1, TxId: "tx2", Type: cb.HeaderType_ENDORSER_TRANSACTION, Chaincode: "mycc", CreatorMspId: "Org2MSP",
+				ValidationCode: int32(pb.TxValidationCode_MVCC_READ_CONFLICT)},
+		},
+	}
+	mockCF := mockQSCCCmdFactory(t, 200, txs)
+
+	cmd := gettxinfoCmd(mockCF)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"-c", mockChannel, "--blocknum", "3"})
+	assert.NoError(t, cmd.Execute())
+
+	mockCF = mockQSCCCmdFactory(t, 200, txs)
+	cmd = gettxinfoCmd(mockCF)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"--blocknum", "3"})
+	assert.EqualError(t, cmd.Execute(), "Must supply channel ID")
+}
+
+func TestGetTxs(t *testing.T) {
+	txs := &cb.TransactionInfoList{
+		Transactions: []*cb.TransactionInfo{
+			{BlockNum: 3, TxNum: 0, TxId: "tx1", Type: cb.HeaderType_ENDORSER_TRANSACTION, Chaincode: "mycc", CreatorMspId: "Org1MSP"},
+		},
+	}
+
+	for _, filter := range [][]string{{"--chaincode", "mycc"}, {"--creator", "Org1MSP"}} {
+		mockCF := mockQSCCCmdFactory(t, 200, txs)
+		cmd := gettxsCmd(mockCF)
+		AddFlags(cmd)
+		cmd.SetArgs(append([]string{"-c", mockChannel, "--start", "0", "--end", "5"}, filter...))
+		assert.NoError(t, cmd.Execute())
+	}
+
+	for _, filter := range [][]string{{}, {"--chaincode", "mycc", "--creator", "Org1MSP"}} {
+		mockCF := mockQSCCCmdFactory(t, 200, txs)
+		cmd := gettxsCmd(mockCF)
+		AddFlags(cmd)
+		cmd.SetArgs(append([]string{"-c", mockChannel, "--start", "0", "--end", "5"}, filter...))
+		assert.EqualError(t, cmd.Execute(), "Must supply either a chaincode name or a creator MSP ID")
+	}
+}
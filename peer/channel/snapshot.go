@@ -0,0 +1,145 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/scc/cscc"
+	"github.com/hyperledger/fabric/peer/common"
+	pcommon "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/context"
+)
+
+func joinBySnapshotCmd(cf *ChannelCmdFactory) *cobra.Command {
+	joinBySnapshotCmd := &cobra.Command{
+		Use:   "joinbysnapshot",
+		Short: "Joins the peer to a channel using a ledger snapshot.",
+		Long: "Joins the peer to a channel using a ledger snapshot present on the file system of the peer. " +
+			"The peer receives only the blocks after the height of the snapshot. Requires '--snapshotpath'.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return joinBySnapshot(cf)
+		},
+	}
+	flagList := []string{
+		"snapshotpath",
+	}
+	attachFlags(joinBySnapshotCmd, flagList)
+
+	return joinBySnapshotCmd
+}
+
+func exportSnapshotCmd(cf *ChannelCmdFactory) *cobra.Command {
+	exportSnapshotCmd := &cobra.Command{
+		Use:   "exportsnapshot",
+		Short: "Exports a snapshot of the ledger of a channel.",
+		Long: "Exports a snapshot of the ledger of a channel at its current height to a directory on the file " +
+			"system of the peer. Requires '-c' and '--snapshotpath'.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return exportSnapshot(cf)
+		},
+	}
+	flagList := []string{
+		"channelID",
+		"snapshotpath",
+	}
+	attachFlags(exportSnapshotCmd, flagList)
+
+	return exportSnapshotCmd
+}
+
+func joinBySnapshot(cf *ChannelCmdFactory) error {
+	if snapshotPath == common.UndefinedParamValue {
+		return errors.New("Must supply snapshot path")
+	}
+
+	var err error
+	if cf == nil {
+		cf, err = InitCmdFactory(EndorserRequired, OrdererNotRequired)
+		if err != nil {
+			return err
+		}
+	}
+
+	resp, err := invokeCSCC(cf, [][]byte{[]byte(cscc.JoinChainBySnapshot), []byte(snapshotPath)})
+	if err != nil {
+		return err
+	}
+	logger.Infof("Successfully submitted proposal to join channel [%s] by snapshot", string(resp.Payload))
+	return nil
+}
+
+func exportSnapshot(cf *ChannelCmdFactory) error {
+	if channelID == common.UndefinedParamValue {
+		return errors.New("Must supply channel ID")
+	}
+	if snapshotPath == common.UndefinedParamValue {
+		return errors.New("Must supply snapshot path")
+	}
+
+	var err error
+	if cf == nil {
+		cf, err = InitCmdFactory(EndorserRequired, OrdererNotRequired)
+		if err != nil {
+			return err
+		}
+	}
+
+	resp, err := invokeCSCC(cf, [][]byte{[]byte(cscc.ExportSnapshot), []byte(channelID), []byte(snapshotPath)})
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Snapshot hash: %s\n", hex.EncodeToString(resp.Payload))
+	return nil
+}
+
+// invokeCSCC sends a proposal for invoking cscc with the given args to the peer and
+// returns the response if the proposal succeeded
+func invokeCSCC(cf *ChannelCmdFactory, args [][]byte) (*pb.Response, error) {
+	invocation := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			Type:        pb.ChaincodeSpec_Type(pb.ChaincodeSpec_Type_value["GOLANG"]),
+			ChaincodeId: &pb.ChaincodeID{Name: "cscc"},
+			Input:       &pb.ChaincodeInput{Args: args},
+		},
+	}
+
+	creator, err := cf.Signer.Serialize()
+	if err != nil {
+		return nil, fmt.Errorf("Error serializing identity for %s: %s", cf.Signer.GetIdentifier(), err)
+	}
+
+	prop, _, err := putils.CreateProposalFromCIS(pcommon.HeaderType_CONFIG, "", invocation, creator)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating proposal %s", err)
+	}
+
+	signedProp, err := putils.GetSignedProposal(prop, cf.Signer)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating signed proposal %s", err)
+	}
+
+	proposalResp, err := cf.EndorserClient.ProcessProposal(context.Background(), signedProp)
+	if err != nil {
+		return nil, ProposalFailedErr(err.Error())
+	}
+
+	if proposalResp == nil || proposalResp.Response == nil {
+		return nil, ProposalFailedErr("nil proposal response")
+	}
+
+	if proposalResp.Response.Status != 0 && proposalResp.Response.Status != 200 {
+		return nil, ProposalFailedErr(fmt.Sprintf("bad proposal response %d: %s",
+			proposalResp.Response.Status, proposalResp.Response.Message))
+	}
+	return proposalResp.Response, nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package channel
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hyperledger/fabric/peer/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	"github.com/stretchr/testify/assert"
+)
+
+func mockSnapshotCmdFactory(t *testing.T, response *pb.ProposalResponse, err error) *ChannelCmdFactory {
+	signer, serr := common.GetDefaultSigner()
+	assert.NoError(t, serr, "Get default signer error: %v", serr)
+	return &ChannelCmdFactory{
+		EndorserClient:   common.GetMockEndorserClient(response, err),
+		BroadcastFactory: mockBroadcastClientFactory,
+		Signer:           signer,
+	}
+}
+
+func TestJoinBySnapshot(t *testing.T) {
+	InitMSP()
+	resetFlags()
+
+	mockResponse := &pb.ProposalResponse{
+		Response:    &pb.Response{Status: 200, Payload: []byte("mychannel")},
+		Endorsement: &pb.Endorsement{},
+	}
+	cmd := joinBySnapshotCmd(mockSnapshotCmdFactory(t, mockResponse, nil))
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"--snapshotpath", "/var/hyperledger/snapshots/mychannel"})
+	assert.NoError(t, cmd.Execute(), "expected joinbysnapshot command to succeed")
+}
+
+func TestJoinBySnapshotMissingPath(t *testing.T) {
+	resetFlags()
+
+	cmd := joinBySnapshotCmd(nil)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{})
+	assert.EqualError(t, cmd.Execute(), "Must supply snapshot path")
+}
+
+func TestJoinBySnapshotBadProposalResponse(t *testing.T) {
+	InitMSP()
+	resetFlags()
+
+	mockResponse := &pb.ProposalResponse{
+		Response:    &pb.Response{Status: 500, Message: "Cannot create ledger from snapshot"},
+		Endorsement: &pb.Endorsement{},
+	}
+	cmd := joinBySnapshotCmd(mockSnapshotCmdFactory(t, mockResponse, nil))
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"--snapshotpath", "/var/hyperledger/snapshots/mychannel"})
+	err := cmd.Execute()
+	assert.Error(t, err, "expected joinbysnapshot command to fail")
+	assert.IsType(t, ProposalFailedErr(err.Error()), err, "expected error type of ProposalFailedErr")
+	assert.Contains(t, err.Error(), "Cannot create ledger from snapshot")
+
+	resetFlags()
+	cmd = joinBySnapshotCmd(mockSnapshotCmdFactory(t, nil, errors.New("connection refused")))
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"--snapshotpath", "/var/hyperledger/snapshots/mychannel"})
+	err = cmd.Execute()
+	assert.Error(t, err, "expected joinbysnapshot command to fail")
+	assert.IsType(t, ProposalFailedErr(err.Error()), err, "expected error type of ProposalFailedErr")
+}
+
+func TestExportSnapshot(t *testing.T) {
+	InitMSP()
+	resetFlags()
+
+	mockResponse := &pb.ProposalResponse{
+		Response:    &pb.Response{Status: 200, Payload: []byte{0xde, 0xad, 0xbe, 0xef}},
+		Endorsement: &pb.Endorsement{},
+	}
+	cmd := exportSnapshotCmd(mockSnapshotCmdFactory(t, mockResponse, nil))
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"-c", "mychannel", "--snapshotpath", "/var/hyperledger/snapshots/mychannel"})
+	assert.NoError(t, cmd.Execute(), "expected exportsnapshot command to succeed")
+}
+
+func TestExportSnapshotMissingFlags(t *testing.T) {
+	resetFlags()
+	cmd := exportSnapshotCmd(nil)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"--snapshotpath", "/var/hyperledger/snapshots/mychannel"})
+	assert.EqualError(t, cmd.Execute(), "Must supply channel ID")
+
+	resetFlags()
+	cmd = exportSnapshotCmd(nil)
+	AddFlags(cmd)
+	cmd.SetArgs([]string{"-c", "mychannel"})
+	assert.EqualError(t, cmd.Execute(), "Must supply snapshot path")
+}
@@ -7,6 +7,7 @@ package common
 
 import (
 	"fmt"
+	"io/ioutil"
 	"time"
 
 	"github.com/hyperledger/fabric/core/comm"
@@ -42,6 +43,40 @@ func NewPeerClientFromEnv() (*PeerClient, error) {
 	return pClient, nil
 }
 
+// NewPeerClientForAddress creates an instance of a PeerClient for the peer
+// at the given address. The TLS settings are taken from the global Viper
+// instance, but the supplied root certificate file, if any, is used to
+// verify the peer
+func NewPeerClientForAddress(address, tlsRootCertFile string) (*PeerClient, error) {
+	_, override, clientConfig, err := configFromEnv("peer")
+	if err != nil {
+		return nil, errors.WithMessage(err,
+			"failed to load config for PeerClient")
+	}
+	if clientConfig.SecOpts.UseTLS && tlsRootCertFile != "" {
+		caPEM, err := ioutil.ReadFile(tlsRootCertFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to load TLS root cert file %s", tlsRootCertFile)
+		}
+		clientConfig.SecOpts.ServerRootCAs = [][]byte{caPEM}
+		// the server name override only applies to the configured peer
+		override = ""
+	}
+	// set timeout
+	clientConfig.Timeout = time.Second * 3
+	gClient, err := comm.NewGRPCClient(clientConfig)
+	if err != nil {
+		return nil, errors.WithMessage(err,
+			"failed to create PeerClient from config")
+	}
+	pClient := &PeerClient{
+		commonClient: commonClient{
+			GRPCClient: gClient,
+			address:    address,
+			sn:         override}}
+	return pClient, nil
+}
+
 // Endorser returns a client for the Endorser service
 func (pc *PeerClient) Endorser() (pb.EndorserClient, error) {
 	conn, err := pc.commonClient.NewConnection(pc.address, pc.sn)
@@ -72,6 +107,16 @@ func GetEndorserClient() (pb.EndorserClient, error) {
 	return peerClient.Endorser()
 }
 
+// GetEndorserClientForAddress returns a new endorser client for the peer
+// at the given address
+func GetEndorserClientForAddress(address, tlsRootCertFile string) (pb.EndorserClient, error) {
+	peerClient, err := NewPeerClientForAddress(address, tlsRootCertFile)
+	if err != nil {
+		return nil, err
+	}
+	return peerClient.Endorser()
+}
+
 // GetAdminClient returns a new admin client.  The target address for
 // the client is taken from the configuration setting "peer.address"
 func GetAdminClient() (pb.AdminClient, error) {
@@ -79,6 +79,27 @@ func TestNewPeerClientFromEnv(t *testing.T) {
 
 }
 
+func TestNewPeerClientForAddress(t *testing.T) {
+	initPeerTestEnv(t)
+	defer func() {
+		viper.Reset()
+		os.Unsetenv("FABRIC_CFG_PATH")
+	}()
+
+	pClient, err := common.NewPeerClientForAddress("peer1:7051", "")
+	assert.NoError(t, err)
+	assert.NotNil(t, pClient)
+
+	viper.Set("peer.tls.enabled", true)
+	pClient, err = common.NewPeerClientForAddress("peer1:7051", filepath.Join("testdata", "certs", "ca.crt"))
+	assert.NoError(t, err)
+	assert.NotNil(t, pClient)
+
+	pClient, err = common.NewPeerClientForAddress("peer1:7051", "noroot.crt")
+	assert.Contains(t, err.Error(), "unable to load TLS root cert file noroot.crt")
+	assert.Nil(t, pClient)
+}
+
 func TestPeerClient(t *testing.T) {
 	initPeerTestEnv(t)
 	lis, err := net.Listen("tcp", "localhost:0")
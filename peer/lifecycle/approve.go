@@ -0,0 +1,68 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle
+
+import (
+	"github.com/hyperledger/fabric/core/scc/lifecycle"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/spf13/cobra"
+)
+
+const approveCmdName = "approve"
+
+// approveCmd returns the cobra command for approving a chaincode
+// definition on behalf of the org of the peer
+func approveCmd(cf *CmdFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   approveCmdName,
+		Short: "Approve a chaincode definition for your org.",
+		Long:  "Approve a chaincode definition on a channel on behalf of the org of the peer. The approval is recorded once the transaction is committed.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return approve(cf)
+		},
+	}
+
+	flagList := []string{
+		"channelID",
+		"name",
+		"version",
+		"sequence",
+		"endorsement-plugin",
+		"validation-plugin",
+		"signature-policy",
+		"collections-config",
+		"init-required",
+	}
+	attachFlags(cmd, flagList)
+
+	return cmd
+}
+
+func approve(cf *CmdFactory) error {
+	definition, err := getDefinition()
+	if err != nil {
+		return err
+	}
+
+	if cf == nil {
+		cf, err = InitCmdFactory(true)
+		if err != nil {
+			return err
+		}
+	}
+
+	args := &lb.ApproveChaincodeDefinitionForMyOrgArgs{
+		Name:       chaincodeName,
+		Definition: definition,
+	}
+	if err = cf.invoke(lifecycle.ApproveFuncName, args); err != nil {
+		return err
+	}
+
+	logger.Infof("Approval of chaincode definition %s, version %s, sequence %d submitted on channel %s", chaincodeName, chaincodeVersion, sequence, channelID)
+	return nil
+}
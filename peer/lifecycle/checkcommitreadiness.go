@@ -0,0 +1,80 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/hyperledger/fabric/core/scc/lifecycle"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/spf13/cobra"
+)
+
+const checkCommitReadinessCmdName = "checkcommitreadiness"
+
+// checkCommitReadinessCmd returns the cobra command for checking which
+// orgs of a channel have approved a chaincode definition
+func checkCommitReadinessCmd(cf *CmdFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   checkCommitReadinessCmdName,
+		Short: "Check whether a chaincode definition is ready to be committed on a channel.",
+		Long:  "Check which orgs of a channel have approved a chaincode definition.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return checkCommitReadiness(cf)
+		},
+	}
+
+	flagList := []string{
+		"channelID",
+		"name",
+		"version",
+		"sequence",
+		"endorsement-plugin",
+		"validation-plugin",
+		"signature-policy",
+		"collections-config",
+		"init-required",
+	}
+	attachFlags(cmd, flagList)
+
+	return cmd
+}
+
+func checkCommitReadiness(cf *CmdFactory) error {
+	definition, err := getDefinition()
+	if err != nil {
+		return err
+	}
+
+	if cf == nil {
+		cf, err = InitCmdFactory(false)
+		if err != nil {
+			return err
+		}
+	}
+
+	args := &lb.CheckCommitReadinessArgs{
+		Name:       chaincodeName,
+		Definition: definition,
+	}
+	result := &lb.CheckCommitReadinessResult{}
+	if err = cf.query(lifecycle.CheckCommitReadinessFuncName, args, result); err != nil {
+		return err
+	}
+
+	fmt.Printf("Chaincode definition for chaincode '%s', version '%s', sequence '%d' on channel '%s' approval status by org:\n", chaincodeName, chaincodeVersion, sequence, channelID)
+	var orgs []string
+	for org := range result.Approvals {
+		orgs = append(orgs, org)
+	}
+	sort.Strings(orgs)
+	for _, org := range orgs {
+		fmt.Printf("%s: %t\n", org, result.Approvals[org])
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle
+
+import (
+	"github.com/hyperledger/fabric/core/scc/lifecycle"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/spf13/cobra"
+)
+
+const commitCmdName = "commit"
+
+// commitCmd returns the cobra command for committing a chaincode
+// definition on a channel
+func commitCmd(cf *CmdFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   commitCmdName,
+		Short: "Commit a chaincode definition on a channel.",
+		Long:  "Commit a chaincode definition on a channel. The transaction must be endorsed by peers of enough of the orgs that approved the definition to satisfy the lifecycle endorsement policy of the channel.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return commit(cf)
+		},
+	}
+
+	flagList := []string{
+		"channelID",
+		"name",
+		"version",
+		"sequence",
+		"endorsement-plugin",
+		"validation-plugin",
+		"signature-policy",
+		"collections-config",
+		"init-required",
+		"peerAddresses",
+		"tlsRootCertFiles",
+	}
+	attachFlags(cmd, flagList)
+
+	return cmd
+}
+
+func commit(cf *CmdFactory) error {
+	definition, err := getDefinition()
+	if err != nil {
+		return err
+	}
+
+	if cf == nil {
+		cf, err = InitCmdFactory(true)
+		if err != nil {
+			return err
+		}
+	}
+
+	args := &lb.CommitChaincodeDefinitionArgs{
+		Name:       chaincodeName,
+		Definition: definition,
+	}
+	if err = cf.invoke(lifecycle.CommitFuncName, args); err != nil {
+		return err
+	}
+
+	logger.Infof("Commit of chaincode definition %s, version %s, sequence %d submitted on channel %s", chaincodeName, chaincodeVersion, sequence, channelID)
+	return nil
+}
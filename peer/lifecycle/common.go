@@ -0,0 +1,246 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle
+
+import (
+	"fmt"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric/common/cauthdsl"
+	cclifecycle "github.com/hyperledger/fabric/core/common/lifecycle"
+	"github.com/hyperledger/fabric/msp"
+	"github.com/hyperledger/fabric/peer/chaincode"
+	"github.com/hyperledger/fabric/peer/common"
+	pcommon "github.com/hyperledger/fabric/protos/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+	"golang.org/x/net/context"
+)
+
+// CmdFactory holds the clients used by the lifecycle commands
+type CmdFactory struct {
+	EndorserClients []pb.EndorserClient
+	Signer          msp.SigningIdentity
+	BroadcastClient common.BroadcastClient
+}
+
+// InitCmdFactory init the CmdFactory with default clients. If peer
+// addresses were supplied, one endorser client is created per peer;
+// otherwise the configured peer is used
+func InitCmdFactory(isOrdererRequired bool) (*CmdFactory, error) {
+	if len(tlsRootCertFiles) > 0 && len(tlsRootCertFiles) != len(peerAddresses) {
+		return nil, errors.Errorf("number of TLS root cert files (%d) does not match the number of peer addresses (%d)", len(tlsRootCertFiles), len(peerAddresses))
+	}
+
+	var endorserClients []pb.EndorserClient
+	if len(peerAddresses) == 0 {
+		endorserClient, err := common.GetEndorserClientFnc()
+		if err != nil {
+			return nil, errors.WithMessage(err, "error getting endorser client")
+		}
+		endorserClients = append(endorserClients, endorserClient)
+	}
+	for i, address := range peerAddresses {
+		var tlsRootCertFile string
+		if len(tlsRootCertFiles) > 0 {
+			tlsRootCertFile = tlsRootCertFiles[i]
+		}
+		endorserClient, err := common.GetEndorserClientForAddress(address, tlsRootCertFile)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("error getting endorser client for %s", address))
+		}
+		endorserClients = append(endorserClients, endorserClient)
+	}
+
+	signer, err := common.GetDefaultSignerFnc()
+	if err != nil {
+		return nil, errors.WithMessage(err, "error getting default signer")
+	}
+
+	var broadcastClient common.BroadcastClient
+	if isOrdererRequired {
+		if len(common.OrderingEndpoint) == 0 {
+			orderingEndpoints, err := common.GetOrdererEndpointOfChainFnc(channelID, signer, endorserClients[0])
+			if err != nil {
+				return nil, errors.WithMessage(err, fmt.Sprintf("error getting (%s) orderer endpoint", channelID))
+			}
+			if len(orderingEndpoints) == 0 {
+				return nil, errors.Errorf("no orderer endpoint got for %s", channelID)
+			}
+			logger.Infof("Get chain(%s) orderer endpoint: %s", channelID, orderingEndpoints[0])
+			// override viper env
+			viper.Set("orderer.address", orderingEndpoints[0])
+		}
+
+		broadcastClient, err = common.GetBroadcastClientFnc()
+		if err != nil {
+			return nil, errors.WithMessage(err, "error getting broadcast client")
+		}
+	}
+
+	return &CmdFactory{
+		EndorserClients: endorserClients,
+		Signer:          signer,
+		BroadcastClient: broadcastClient,
+	}, nil
+}
+
+// checkDefinitionParams checks that the parameters identifying a chaincode
+// definition were supplied
+func checkDefinitionParams() error {
+	if channelID == "" {
+		return errors.New("the required parameter 'channelID' is empty. Rerun the command with -C flag")
+	}
+	if chaincodeName == common.UndefinedParamValue {
+		return errors.New("the required parameter 'name' is empty. Rerun the command with -n flag")
+	}
+	if chaincodeVersion == common.UndefinedParamValue {
+		return errors.New("the required parameter 'version' is empty. Rerun the command with -v flag")
+	}
+	if sequence <= 0 {
+		return errors.New("the required parameter 'sequence' must be a positive number. Rerun the command with --sequence flag")
+	}
+	return nil
+}
+
+// getDefinition builds the chaincode definition described by the flags
+func getDefinition() (*lb.ChaincodeDefinition, error) {
+	if err := checkDefinitionParams(); err != nil {
+		return nil, err
+	}
+
+	definition := &lb.ChaincodeDefinition{
+		Sequence:          sequence,
+		Version:           chaincodeVersion,
+		EndorsementPlugin: endorsementPlugin,
+		ValidationPlugin:  validationPlugin,
+		InitRequired:      initRequired,
+	}
+
+	if signaturePolicy != "" {
+		p, err := cauthdsl.FromString(signaturePolicy)
+		if err != nil {
+			return nil, errors.Errorf("invalid signature policy %s", signaturePolicy)
+		}
+		definition.ValidationParameter = putils.MarshalOrPanic(p)
+	}
+
+	if collectionsConfigFile != "" {
+		ccpBytes, err := chaincode.GetCollectionConfigFromFile(collectionsConfigFile)
+		if err != nil {
+			return nil, errors.WithMessage(err, fmt.Sprintf("invalid collection configuration in file %s", collectionsConfigFile))
+		}
+		ccp := &pcommon.CollectionConfigPackage{}
+		if err := proto.Unmarshal(ccpBytes, ccp); err != nil {
+			return nil, errors.Wrap(err, "could not unmarshal collection configuration")
+		}
+		definition.Collections = ccp
+	}
+
+	return definition, nil
+}
+
+// createProposal creates a signed proposal invoking the given function
+// of the lifecycle system chaincode with the given arguments
+func createProposal(signer msp.SigningIdentity, function string, args proto.Message) (*pb.Proposal, *pb.SignedProposal, error) {
+	argsBytes, err := proto.Marshal(args)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not marshal args")
+	}
+
+	cis := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			ChaincodeId: &pb.ChaincodeID{Name: cclifecycle.Namespace},
+			Input:       &pb.ChaincodeInput{Args: [][]byte{[]byte(function), argsBytes}},
+		},
+	}
+
+	creator, err := signer.Serialize()
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "error serializing identity")
+	}
+
+	prop, _, err := putils.CreateProposalFromCIS(pcommon.HeaderType_ENDORSER_TRANSACTION, channelID, cis, creator)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "error creating proposal")
+	}
+
+	signedProp, err := putils.GetSignedProposal(prop, signer)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "error creating signed proposal")
+	}
+	return prop, signedProp, nil
+}
+
+// endorse sends the signed proposal to the given endorsers and returns
+// their responses; it fails if any of the endorsers did not endorse it
+func endorse(endorsers []pb.EndorserClient, signedProp *pb.SignedProposal) ([]*pb.ProposalResponse, error) {
+	var responses []*pb.ProposalResponse
+	for _, endorser := range endorsers {
+		proposalResponse, err := endorser.ProcessProposal(context.Background(), signedProp)
+		if err != nil {
+			return nil, errors.WithMessage(err, "error endorsing proposal")
+		}
+		if proposalResponse == nil {
+			return nil, errors.New("received nil proposal response")
+		}
+		if proposalResponse.Response == nil {
+			return nil, errors.New("received proposal response with nil response")
+		}
+		if proposalResponse.Response.Status != int32(pcommon.Status_SUCCESS) {
+			return nil, errors.Errorf("proposal failed with status: %d - %s", proposalResponse.Response.Status, proposalResponse.Response.Message)
+		}
+		responses = append(responses, proposalResponse)
+	}
+	return responses, nil
+}
+
+// invoke endorses an invocation of the lifecycle system chaincode and
+// submits the resulting transaction to the ordering service
+func (cf *CmdFactory) invoke(function string, args proto.Message) error {
+	prop, signedProp, err := createProposal(cf.Signer, function, args)
+	if err != nil {
+		return err
+	}
+
+	responses, err := endorse(cf.EndorserClients, signedProp)
+	if err != nil {
+		return err
+	}
+
+	env, err := putils.CreateSignedTx(prop, cf.Signer, responses...)
+	if err != nil {
+		return errors.WithMessage(err, "could not assemble transaction")
+	}
+
+	if err = cf.BroadcastClient.Send(env); err != nil {
+		return errors.WithMessage(err, "error sending transaction")
+	}
+	return nil
+}
+
+// query sends a query of the lifecycle system chaincode to the first
+// endorser and unmarshals its result
+func (cf *CmdFactory) query(function string, args, result proto.Message) error {
+	_, signedProp, err := createProposal(cf.Signer, function, args)
+	if err != nil {
+		return err
+	}
+
+	responses, err := endorse(cf.EndorserClients[:1], signedProp)
+	if err != nil {
+		return err
+	}
+
+	if err = proto.Unmarshal(responses[0].Response.Payload, result); err != nil {
+		return errors.Wrap(err, "failed to unmarshal proposal response's response payload")
+	}
+	return nil
+}
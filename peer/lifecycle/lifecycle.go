@@ -0,0 +1,103 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/common/flogging"
+	"github.com/hyperledger/fabric/peer/common"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const (
+	lifecycleFuncName = "lifecycle"
+	shortDes          = "Operate the lifecycle of a chaincode definition: approve|checkcommitreadiness|commit|querycommitted."
+	longDes           = "Agree on the definition of a chaincode with the other organizations of a channel and commit it: approve|checkcommitreadiness|commit|querycommitted."
+)
+
+var logger = flogging.MustGetLogger("lifecycleCmd")
+
+// Cmd returns the cobra command for the chaincode lifecycle
+func Cmd(cf *CmdFactory) *cobra.Command {
+	common.AddOrdererFlags(lifecycleCmd)
+
+	lifecycleCmd.AddCommand(approveCmd(cf))
+	lifecycleCmd.AddCommand(checkCommitReadinessCmd(cf))
+	lifecycleCmd.AddCommand(commitCmd(cf))
+	lifecycleCmd.AddCommand(queryCommittedCmd(cf))
+
+	return lifecycleCmd
+}
+
+// Lifecycle-related variables.
+var (
+	channelID             string
+	chaincodeName         string
+	chaincodeVersion      string
+	sequence              int64
+	endorsementPlugin     string
+	validationPlugin      string
+	signaturePolicy       string
+	collectionsConfigFile string
+	initRequired          bool
+	peerAddresses         []string
+	tlsRootCertFiles      []string
+)
+
+var lifecycleCmd = &cobra.Command{
+	Use:              lifecycleFuncName,
+	Short:            fmt.Sprint(shortDes),
+	Long:             fmt.Sprint(longDes),
+	PersistentPreRun: common.SetOrdererEnv,
+}
+
+var flags *pflag.FlagSet
+
+func init() {
+	resetFlags()
+}
+
+// Explicitly define a method to facilitate tests
+func resetFlags() {
+	flags = &pflag.FlagSet{}
+
+	flags.StringVarP(&channelID, "channelID", "C", "",
+		fmt.Sprint("The channel on which this command should be executed"))
+	flags.StringVarP(&chaincodeName, "name", "n", common.UndefinedParamValue,
+		fmt.Sprint("Name of the chaincode"))
+	flags.StringVarP(&chaincodeVersion, "version", "v", common.UndefinedParamValue,
+		fmt.Sprint("Version of the chaincode"))
+	flags.Int64VarP(&sequence, "sequence", "", 0,
+		fmt.Sprint("The sequence number of the chaincode definition for the channel"))
+	flags.StringVarP(&endorsementPlugin, "endorsement-plugin", "E", "",
+		fmt.Sprint("The name of the endorsement plugin to be used for this chaincode"))
+	flags.StringVarP(&validationPlugin, "validation-plugin", "V", "",
+		fmt.Sprint("The name of the validation plugin to be used for this chaincode"))
+	flags.StringVarP(&signaturePolicy, "signature-policy", "P", "",
+		fmt.Sprint("The endorsement policy associated to this chaincode"))
+	flags.StringVar(&collectionsConfigFile, "collections-config", "",
+		fmt.Sprint("The file containing the configuration for the chaincode's collection"))
+	flags.BoolVar(&initRequired, "init-required", false,
+		fmt.Sprint("Whether the chaincode requires invoking 'init'"))
+	flags.StringSliceVar(&peerAddresses, "peerAddresses", nil,
+		fmt.Sprint("The addresses of the peers to connect to; defaults to the configured peer"))
+	flags.StringSliceVar(&tlsRootCertFiles, "tlsRootCertFiles", nil,
+		fmt.Sprint("If TLS is enabled, the paths to the TLS root cert files of the peers to connect to. The order and number of certs specified should match the --peerAddresses flag"))
+}
+
+func attachFlags(cmd *cobra.Command, names []string) {
+	cmdFlags := cmd.Flags()
+	for _, name := range names {
+		if flag := flags.Lookup(name); flag != nil {
+			cmdFlags.AddFlag(flag)
+		} else {
+			logger.Fatalf("Could not find flag '%s' to attach to commond '%s'", name, cmd.Name())
+		}
+	}
+}
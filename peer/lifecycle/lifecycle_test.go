@@ -0,0 +1,219 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/hyperledger/fabric/msp/mgmt/testtools"
+	"github.com/hyperledger/fabric/peer/common"
+	pb "github.com/hyperledger/fabric/protos/peer"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	putils "github.com/hyperledger/fabric/protos/utils"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+)
+
+var once sync.Once
+
+// InitMSP init MSP
+func InitMSP() {
+	once.Do(func() {
+		err := msptesttools.LoadMSPSetupForTesting()
+		if err != nil {
+			panic(fmt.Errorf("Fatal error when reading MSP config: %s\n", err))
+		}
+	})
+}
+
+func getMockCmdFactory(t *testing.T, payload []byte, endorsers int, broadcastErr error) *CmdFactory {
+	InitMSP()
+	signer, err := common.GetDefaultSigner()
+	assert.NoError(t, err)
+
+	mockResponse := &pb.ProposalResponse{
+		Response:    &pb.Response{Status: 200, Payload: payload},
+		Endorsement: &pb.Endorsement{},
+	}
+	cf := &CmdFactory{
+		Signer:          signer,
+		BroadcastClient: common.GetMockBroadcastClient(broadcastErr),
+	}
+	for i := 0; i < endorsers; i++ {
+		cf.EndorserClients = append(cf.EndorserClients, common.GetMockEndorserClient(mockResponse, nil))
+	}
+	return cf
+}
+
+func runCmd(newCmd func(*CmdFactory) *cobra.Command, cf *CmdFactory, args []string) error {
+	resetFlags()
+	cmd := newCmd(cf)
+	cmd.SetArgs(args)
+	return cmd.Execute()
+}
+
+func TestApproveCmd(t *testing.T) {
+	cf := getMockCmdFactory(t, nil, 1, nil)
+
+	var tests = []struct {
+		name   string
+		args   []string
+		errMsg string
+	}{
+		{
+			name: "successful",
+			args: []string{"-C", "mychannel", "-n", "mycc", "-v", "1.0", "--sequence", "1", "--init-required"},
+		},
+		{
+			name: "successful with policy",
+			args: []string{"-C", "mychannel", "-n", "mycc", "-v", "1.0", "--sequence", "1", "-P", "OR('Org1MSP.member', 'Org2MSP.member')"},
+		},
+		{
+			name:   "missing channelID",
+			args:   []string{"-n", "mycc", "-v", "1.0", "--sequence", "1"},
+			errMsg: "the required parameter 'channelID' is empty",
+		},
+		{
+			name:   "missing name",
+			args:   []string{"-C", "mychannel", "-v", "1.0", "--sequence", "1"},
+			errMsg: "the required parameter 'name' is empty",
+		},
+		{
+			name:   "missing version",
+			args:   []string{"-C", "mychannel", "-n", "mycc", "--sequence", "1"},
+			errMsg: "the required parameter 'version' is empty",
+		},
+		{
+			name:   "missing sequence",
+			args:   []string{"-C", "mychannel", "-n", "mycc", "-v", "1.0"},
+			errMsg: "the required parameter 'sequence' must be a positive number",
+		},
+		{
+			name:   "bad policy",
+			args:   []string{"-C", "mychannel", "-n", "mycc", "-v", "1.0", "--sequence", "1", "-P", "OR("},
+			errMsg: "invalid signature policy OR(",
+		},
+		{
+			name:   "bad collections config",
+			args:   []string{"-C", "mychannel", "-n", "mycc", "-v", "1.0", "--sequence", "1", "--collections-config", "/does/not/exist"},
+			errMsg: "invalid collection configuration in file /does/not/exist",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := runCmd(approveCmd, cf, test.args)
+			if test.errMsg == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), test.errMsg)
+			}
+		})
+	}
+
+	// endorsement failure
+	failCF := getMockCmdFactory(t, nil, 1, nil)
+	failCF.EndorserClients[0] = common.GetMockEndorserClient(&pb.ProposalResponse{
+		Response: &pb.Response{Status: 500, Message: "chaincode definition not agreed to by this org"},
+	}, nil)
+	err := runCmd(approveCmd, failCF, []string{"-C", "mychannel", "-n", "mycc", "-v", "1.0", "--sequence", "1"})
+	assert.EqualError(t, err, "proposal failed with status: 500 - chaincode definition not agreed to by this org")
+
+	failCF.EndorserClients[0] = common.GetMockEndorserClient(nil, errors.New("connection refused"))
+	err = runCmd(approveCmd, failCF, []string{"-C", "mychannel", "-n", "mycc", "-v", "1.0", "--sequence", "1"})
+	assert.EqualError(t, err, "error endorsing proposal: connection refused")
+
+	// broadcast failure
+	err = runCmd(approveCmd, getMockCmdFactory(t, nil, 1, errors.New("orderer down")), []string{"-C", "mychannel", "-n", "mycc", "-v", "1.0", "--sequence", "1"})
+	assert.EqualError(t, err, "error sending transaction: orderer down")
+}
+
+func TestCommitCmd(t *testing.T) {
+	err := runCmd(commitCmd, getMockCmdFactory(t, nil, 2, nil), []string{"-C", "mychannel", "-n", "mycc", "-v", "1.0", "--sequence", "1"})
+	assert.NoError(t, err)
+
+	err = runCmd(commitCmd, getMockCmdFactory(t, nil, 2, nil), []string{"-n", "mycc", "-v", "1.0", "--sequence", "1"})
+	assert.EqualError(t, err, "the required parameter 'channelID' is empty. Rerun the command with -C flag")
+
+	// the endorsements of the peers must match
+	cf := getMockCmdFactory(t, nil, 2, nil)
+	cf.EndorserClients[1] = common.GetMockEndorserClient(&pb.ProposalResponse{
+		Response:    &pb.Response{Status: 200},
+		Payload:     []byte("different"),
+		Endorsement: &pb.Endorsement{},
+	}, nil)
+	err = runCmd(commitCmd, cf, []string{"-C", "mychannel", "-n", "mycc", "-v", "1.0", "--sequence", "1"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "could not assemble transaction")
+}
+
+func TestCheckCommitReadinessCmd(t *testing.T) {
+	result := &lb.CheckCommitReadinessResult{Approvals: map[string]bool{"Org1MSP": true, "Org2MSP": false}}
+	cf := getMockCmdFactory(t, putils.MarshalOrPanic(result), 1, nil)
+	err := runCmd(checkCommitReadinessCmd, cf, []string{"-C", "mychannel", "-n", "mycc", "-v", "1.0", "--sequence", "1"})
+	assert.NoError(t, err)
+
+	cf = getMockCmdFactory(t, []byte("garbage"), 1, nil)
+	err = runCmd(checkCommitReadinessCmd, cf, []string{"-C", "mychannel", "-n", "mycc", "-v", "1.0", "--sequence", "1"})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to unmarshal proposal response's response payload")
+}
+
+func TestQueryCommittedCmd(t *testing.T) {
+	definition := &lb.ChaincodeDefinition{Sequence: 1, Version: "1.0", EndorsementPlugin: "DefaultEndorsement", ValidationPlugin: "DefaultValidation"}
+
+	result := &lb.QueryChaincodeDefinitionResult{Definition: definition}
+	err := runCmd(queryCommittedCmd, getMockCmdFactory(t, putils.MarshalOrPanic(result), 1, nil), []string{"-C", "mychannel", "-n", "mycc"})
+	assert.NoError(t, err)
+
+	results := &lb.QueryChaincodeDefinitionsResult{
+		ChaincodeDefinitions: []*lb.NamedChaincodeDefinition{{Name: "mycc", Definition: definition}},
+	}
+	err = runCmd(queryCommittedCmd, getMockCmdFactory(t, putils.MarshalOrPanic(results), 1, nil), []string{"-C", "mychannel"})
+	assert.NoError(t, err)
+
+	err = runCmd(queryCommittedCmd, getMockCmdFactory(t, nil, 1, nil), []string{"-n", "mycc"})
+	assert.EqualError(t, err, "the required parameter 'channelID' is empty. Rerun the command with -C flag")
+}
+
+func TestGetDefinition(t *testing.T) {
+	dir, err := ioutil.TempDir("", "lifecycle")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	collectionsFile := filepath.Join(dir, "collections.json")
+	collections := `[{"name": "foo", "policy": "OR('A.member', 'B.member')", "requiredPeerCount": 1, "maxPeerCount": 3, "blockToLive": 10}]`
+	assert.NoError(t, ioutil.WriteFile(collectionsFile, []byte(collections), 0644))
+
+	resetFlags()
+	assert.NoError(t, flags.Parse([]string{"-C", "mychannel", "-n", "mycc", "-v", "1.0", "--sequence", "2",
+		"-E", "myescc", "-V", "myvscc", "-P", "AND('A.member', 'B.member')", "--collections-config", collectionsFile, "--init-required"}))
+
+	definition, err := getDefinition()
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), definition.Sequence)
+	assert.Equal(t, "1.0", definition.Version)
+	assert.Equal(t, "myescc", definition.EndorsementPlugin)
+	assert.Equal(t, "myvscc", definition.ValidationPlugin)
+	assert.NotEmpty(t, definition.ValidationParameter)
+	assert.True(t, definition.InitRequired)
+	assert.Len(t, definition.Collections.Config, 1)
+	assert.Equal(t, "foo", definition.Collections.Config[0].GetStaticCollectionConfig().Name)
+}
+
+func TestInitCmdFactory(t *testing.T) {
+	resetFlags()
+	assert.NoError(t, flags.Parse([]string{"--peerAddresses", "peer0:7051,peer1:7051", "--tlsRootCertFiles", "ca.crt"}))
+	_, err := InitCmdFactory(false)
+	assert.EqualError(t, err, "number of TLS root cert files (1) does not match the number of peer addresses (2)")
+}
@@ -0,0 +1,80 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package lifecycle
+
+import (
+	"fmt"
+
+	"github.com/hyperledger/fabric/core/scc/lifecycle"
+	"github.com/hyperledger/fabric/peer/common"
+	lb "github.com/hyperledger/fabric/protos/peer/lifecycle"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+const queryCommittedCmdName = "querycommitted"
+
+// queryCommittedCmd returns the cobra command for querying the chaincode
+// definitions committed on a channel
+func queryCommittedCmd(cf *CmdFactory) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   queryCommittedCmdName,
+		Short: "Query the committed chaincode definitions on a channel.",
+		Long:  "Query the committed definition of a chaincode on a channel, or all committed chaincode definitions if no name is given.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return queryCommitted(cf)
+		},
+	}
+
+	flagList := []string{
+		"channelID",
+		"name",
+	}
+	attachFlags(cmd, flagList)
+
+	return cmd
+}
+
+func queryCommitted(cf *CmdFactory) error {
+	if channelID == "" {
+		return errors.New("the required parameter 'channelID' is empty. Rerun the command with -C flag")
+	}
+
+	var err error
+	if cf == nil {
+		cf, err = InitCmdFactory(false)
+		if err != nil {
+			return err
+		}
+	}
+
+	if chaincodeName == common.UndefinedParamValue {
+		result := &lb.QueryChaincodeDefinitionsResult{}
+		if err = cf.query(lifecycle.QueryChaincodeDefinitionsFuncName, &lb.QueryChaincodeDefinitionsArgs{}, result); err != nil {
+			return err
+		}
+		fmt.Printf("Committed chaincode definitions on channel '%s':\n", channelID)
+		for _, cd := range result.ChaincodeDefinitions {
+			printDefinition(cd.Name, cd.Definition)
+		}
+		return nil
+	}
+
+	result := &lb.QueryChaincodeDefinitionResult{}
+	args := &lb.QueryChaincodeDefinitionArgs{Name: chaincodeName}
+	if err = cf.query(lifecycle.QueryChaincodeDefinitionFuncName, args, result); err != nil {
+		return err
+	}
+	fmt.Printf("Committed chaincode definition for chaincode '%s' on channel '%s':\n", chaincodeName, channelID)
+	printDefinition(chaincodeName, result.Definition)
+	return nil
+}
+
+func printDefinition(name string, definition *lb.ChaincodeDefinition) {
+	fmt.Printf("Name: %s, Version: %s, Sequence: %d, Endorsement Plugin: %s, Validation Plugin: %s, Init Required: %t\n",
+		name, definition.GetVersion(), definition.GetSequence(), definition.GetEndorsementPlugin(), definition.GetValidationPlugin(), definition.GetInitRequired())
+}
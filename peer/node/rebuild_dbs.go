@@ -0,0 +1,27 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/spf13/cobra"
+)
+
+func rebuildDBsCmd() *cobra.Command {
+	return nodeRebuildDBsCmd
+}
+
+var nodeRebuildDBsCmd = &cobra.Command{
+	Use:   "rebuild-dbs",
+	Short: "Rebuilds databases.",
+	Long: "Drops the state database, the history database and the internal bookkeeping data of all the channels. " +
+		"These are rebuilt from the blocks when the peer is started next time. The peer must be stopped before " +
+		"running this command.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return kvledger.RebuildDBs()
+	},
+}
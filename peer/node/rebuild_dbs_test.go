@@ -0,0 +1,45 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRebuildDBsCmd(t *testing.T) {
+	testPath, err := ioutil.TempDir("", "rebuilddbscmd")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testPath)
+	defer viper.Set("peer.fileSystemPath", viper.GetString("peer.fileSystemPath"))
+	viper.Set("peer.fileSystemPath", testPath)
+
+	// the dbs are dropped
+	stateDBPath := ledgerconfig.GetStateLevelDBPath()
+	assert.NoError(t, os.MkdirAll(stateDBPath, 0755))
+	cmd := rebuildDBsCmd()
+	cmd.SetArgs([]string{})
+	assert.NoError(t, cmd.Execute())
+	_, err = os.Stat(stateDBPath)
+	assert.True(t, os.IsNotExist(err))
+
+	// the command fails while the ledger is in use
+	fileLock := leveldbhelper.NewFileLock(ledgerconfig.GetFileLockPath())
+	assert.NoError(t, fileLock.Lock())
+	defer fileLock.Unlock()
+	cmd = rebuildDBsCmd()
+	cmd.SetArgs([]string{})
+	err = cmd.Execute()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the ledger is in use")
+}
@@ -0,0 +1,42 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"errors"
+
+	"github.com/hyperledger/fabric/core/ledger/kvledger"
+	"github.com/hyperledger/fabric/peer/common"
+	"github.com/spf13/cobra"
+)
+
+var (
+	channelID   string
+	blockNumber uint64
+)
+
+func rollbackCmd() *cobra.Command {
+	nodeRollbackCmd.ResetFlags()
+	flags := nodeRollbackCmd.Flags()
+	flags.StringVarP(&channelID, "channelID", "c", common.UndefinedParamValue, "Channel to rollback.")
+	flags.Uint64VarP(&blockNumber, "blockNumber", "b", 0, "Block number to which the channel needs to be rolled back to.")
+	return nodeRollbackCmd
+}
+
+var nodeRollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Rolls back a channel.",
+	Long: "Rolls back the ledger of a channel to the specified block number. The state database and the history " +
+		"database of all the channels are rebuilt when the peer is started next time. The peer must be stopped " +
+		"before running this command.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if channelID == common.UndefinedParamValue {
+			return errors.New("Must supply channel ID")
+		}
+		return kvledger.RollbackKVLedger(channelID, blockNumber)
+	},
+}
@@ -0,0 +1,44 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package node
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/hyperledger/fabric/common/ledger/util/leveldbhelper"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRollbackCmd(t *testing.T) {
+	testPath, err := ioutil.TempDir("", "rollbackcmd")
+	assert.NoError(t, err)
+	defer os.RemoveAll(testPath)
+	defer viper.Set("peer.fileSystemPath", viper.GetString("peer.fileSystemPath"))
+	viper.Set("peer.fileSystemPath", testPath)
+
+	cmd := rollbackCmd()
+	cmd.SetArgs([]string{"-b", "10"})
+	assert.EqualError(t, cmd.Execute(), "Must supply channel ID")
+
+	cmd = rollbackCmd()
+	cmd.SetArgs([]string{"-c", "mychannel", "-b", "10"})
+	assert.EqualError(t, cmd.Execute(), "ledger [mychannel] does not exist")
+
+	// the command fails while the ledger is in use
+	fileLock := leveldbhelper.NewFileLock(ledgerconfig.GetFileLockPath())
+	assert.NoError(t, fileLock.Lock())
+	defer fileLock.Unlock()
+	cmd = rollbackCmd()
+	cmd.SetArgs([]string{"-c", "mychannel", "-b", "10"})
+	err = cmd.Execute()
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "the ledger is in use")
+}
@@ -22,20 +22,31 @@ import (
 	"github.com/hyperledger/fabric/common/deliver"
 	"github.com/hyperledger/fabric/common/flogging"
 	"github.com/hyperledger/fabric/common/localmsp"
+	"github.com/hyperledger/fabric/common/metrics"
 	"github.com/hyperledger/fabric/common/viperutil"
 	"github.com/hyperledger/fabric/core"
 	"github.com/hyperledger/fabric/core/aclmgmt"
 	"github.com/hyperledger/fabric/core/chaincode"
 	"github.com/hyperledger/fabric/core/chaincode/accesscontrol"
 	"github.com/hyperledger/fabric/core/comm"
+	"github.com/hyperledger/fabric/core/committer/txvalidator"
 	"github.com/hyperledger/fabric/core/common/ccprovider"
 	"github.com/hyperledger/fabric/core/config"
+	"github.com/hyperledger/fabric/core/container/dockercontroller"
 	"github.com/hyperledger/fabric/core/endorser"
 	authHandler "github.com/hyperledger/fabric/core/handlers/auth"
+	endorsementapi "github.com/hyperledger/fabric/core/handlers/endorsement/api"
 	"github.com/hyperledger/fabric/core/handlers/library"
+	validationapi "github.com/hyperledger/fabric/core/handlers/validation/api"
+	"github.com/hyperledger/fabric/core/ledger/ledgerconfig"
 	"github.com/hyperledger/fabric/core/ledger/ledgermgmt"
+	"github.com/hyperledger/fabric/core/ledger/util/couchdb"
+	"github.com/hyperledger/fabric/core/operations"
 	"github.com/hyperledger/fabric/core/peer"
 	"github.com/hyperledger/fabric/core/scc"
+	"github.com/hyperledger/fabric/discovery"
+	"github.com/hyperledger/fabric/discovery/endorsement"
+	discsupport "github.com/hyperledger/fabric/discovery/support"
 	"github.com/hyperledger/fabric/events/producer"
 	common2 "github.com/hyperledger/fabric/gossip/common"
 	"github.com/hyperledger/fabric/gossip/service"
@@ -45,6 +56,7 @@ import (
 	peergossip "github.com/hyperledger/fabric/peer/gossip"
 	"github.com/hyperledger/fabric/peer/version"
 	cb "github.com/hyperledger/fabric/protos/common"
+	discprotos "github.com/hyperledger/fabric/protos/discovery"
 	"github.com/hyperledger/fabric/protos/ledger/rwset"
 	pb "github.com/hyperledger/fabric/protos/peer"
 	"github.com/pkg/errors"
@@ -113,6 +125,15 @@ func serve(args []string) error {
 	//Users can pass in their own ACLProvider to RegisterACLProvider (currently unit tests do this)
 	aclmgmt.RegisterACLProvider(nil)
 
+	if err := metrics.Init(metrics.NewOpts()); err != nil {
+		return errors.WithMessage(err, "failed to initialize metrics")
+	}
+	go func() {
+		if err := metrics.Start(); err != nil {
+			logger.Errorf("Failed to start metrics reporting: %s", err)
+		}
+	}()
+
 	//initialize resource management exit
 	ledgermgmt.Initialize(peer.ConfigTxProcessors)
 
@@ -179,7 +200,7 @@ func serve(args []string) error {
 		}
 	}
 
-	abServer := peer.NewDeliverEventsServer(mutualTLS, policyCheckerProvider, &peer.DeliverSupportManager{})
+	abServer := peer.NewDeliverEventsServer(mutualTLS, policyCheckerProvider, &peer.DeliverSupportManager{}, &peer.DeliverPvtDataProvider{})
 	pb.RegisterDeliverServer(peerServer.Server(), abServer)
 
 	// enable the cache of chaincode info
@@ -206,12 +227,21 @@ func serve(args []string) error {
 		return service.GetGossipService().DistributePrivateData(channel, txID, privateData)
 	}
 
-	serverEndorser := endorser.NewEndorserServer(privDataDist, &endorser.SupportImpl{})
 	libConf := library.Config{}
 	if err = viperutil.EnhancedExactUnmarshalKey("peer.handlers", &libConf); err != nil {
 		return errors.WithMessage(err, "could not load YAML config")
 	}
-	authFilters := library.InitRegistry(libConf).Lookup(library.Auth).([]authHandler.Filter)
+	reg := library.InitRegistry(libConf)
+
+	endorserSupport := &endorser.SupportImpl{}
+	endorsementPlugins := reg.Lookup(library.Endorsement).(map[string]endorsementapi.PluginFactory)
+	endorserSupport.PluginEndorser = endorser.NewPluginEndorser(&endorser.PluginSupport{
+		ChannelStateRetriever:  endorserSupport,
+		SigningIdentityFetcher: endorserSupport,
+		PluginMapper:           endorser.MapBasedPluginMapper(endorsementPlugins),
+	})
+	serverEndorser := endorser.NewEndorserServer(privDataDist, endorserSupport)
+	authFilters := reg.Lookup(library.Auth).([]authHandler.Filter)
 	auth := authHandler.ChainFilters(serverEndorser, authFilters...)
 	// Register the Endorser server
 	pb.RegisterEndorserServer(peerServer.Server(), auth)
@@ -276,11 +306,17 @@ func serve(args []string) error {
 	//initialize system chaincodes
 	initSysCCs()
 
+	validationPlugins := reg.Lookup(library.Validation).(map[string]validationapi.PluginFactory)
+
 	//this brings up all the chains (including testchainid)
 	peer.Initialize(func(cid string) {
 		logger.Debugf("Deploying system CC, for chain <%s>", cid)
 		scc.DeploySysCCs(cid)
-	})
+	}, txvalidator.MapBasedPluginMapper(validationPlugins))
+
+	if viper.GetBool("peer.discovery.enabled") {
+		registerDiscoveryService(peerServer)
+	}
 
 	logger.Infof("Starting peer with ID=[%s], network ID=[%s], address=[%s]",
 		peerEndpoint.Id, viper.GetString("peer.networkId"), peerEndpoint.Address)
@@ -316,6 +352,12 @@ func serve(args []string) error {
 		go ehubGrpcServer.Start()
 	}
 
+	opsSystem, err := startOperationsSystem()
+	if err != nil {
+		return err
+	}
+	defer opsSystem.Stop()
+
 	// Start profiling http endpoint if enabled
 	if viper.GetBool("peer.profile.enabled") {
 		go func() {
@@ -346,6 +388,47 @@ func serve(args []string) error {
 	return <-serve
 }
 
+// startOperationsSystem starts the operations endpoint of the peer and
+// registers the health checks of the services the peer depends on
+func startOperationsSystem() (*operations.System, error) {
+	var clientRootCAs []string
+	for _, file := range viper.GetStringSlice("operations.tls.clientRootCAs.files") {
+		clientRootCAs = append(clientRootCAs, config.TranslatePath(filepath.Dir(viper.ConfigFileUsed()), file))
+	}
+	opsSystem := operations.NewSystem(operations.Options{
+		ListenAddress: viper.GetString("operations.listenAddress"),
+		TLS: operations.TLS{
+			Enabled:            viper.GetBool("operations.tls.enabled"),
+			CertFile:           config.GetPath("operations.tls.cert.file"),
+			KeyFile:            config.GetPath("operations.tls.key.file"),
+			ClientCertRequired: viper.GetBool("operations.tls.clientAuthRequired"),
+			ClientRootCAs:      clientRootCAs,
+		},
+	})
+
+	if !chaincode.IsDevMode() {
+		if err := opsSystem.RegisterChecker("docker", dockercontroller.NewDockerVM()); err != nil {
+			return nil, errors.WithMessage(err, "failed to register the docker health checker")
+		}
+	}
+	if ledgerconfig.IsCouchDBEnabled() {
+		couchDBDef := couchdb.GetCouchDBDefinition()
+		couchInstance, err := couchdb.CreateCouchInstance(couchDBDef.URL, couchDBDef.Username, couchDBDef.Password,
+			couchDBDef.MaxRetries, couchDBDef.MaxRetriesOnStartup, couchDBDef.RequestTimeout)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to create the CouchDB instance for health checking")
+		}
+		if err := opsSystem.RegisterChecker("couchdb", couchInstance); err != nil {
+			return nil, errors.WithMessage(err, "failed to register the CouchDB health checker")
+		}
+	}
+
+	if err := opsSystem.Start(); err != nil {
+		return nil, errors.WithMessage(err, "failed to start the operations system")
+	}
+	return opsSystem, nil
+}
+
 //create a CC listener using peer.chaincodeListenAddress (and if that's not set use peer.peerAddress)
 func createChaincodeServer(ca accesscontrol.CA, peerHostname string) (srv comm.GRPCServer, ccEndpoint string, err error) {
 	// before potentially setting chaincodeListenAddress, compute chaincode endpoint at first
@@ -513,6 +596,21 @@ func registerChaincodeSupport(grpcServer comm.GRPCServer, ccEndpoint string, ca
 	pb.RegisterChaincodeSupportServer(grpcServer.Server(), ccSrv)
 }
 
+func registerDiscoveryService(peerServer comm.GRPCServer) {
+	gSup := service.GetGossipService()
+	pe := discsupport.NewPrincipalEvaluator(mgmt.GetIdentityDeserializer)
+	pf := discsupport.NewChaincodePolicySupport(peer.GetLedger)
+	sup := discsupport.NewDiscoverySupport(
+		discsupport.NewACLSupport(peer.GetPolicyManager),
+		gSup,
+		endorsement.NewEndorsementAnalyzer(gSup, pf, pe),
+		discsupport.NewConfigSupport(peer.GetChannelConfig),
+	)
+	svc := discovery.NewService(discovery.Config{TLS: peerServer.TLSEnabled()}, sup)
+	logger.Info("Discovery service activated")
+	discprotos.RegisterDiscoveryServer(peerServer.Server(), svc)
+}
+
 func createEventHubServer(serverConfig comm.ServerConfig) (comm.GRPCServer, error) {
 	var lis net.Listener
 	var err error
@@ -243,6 +243,10 @@ type ChannelHeader struct {
 	// If mutual TLS is employed, this represents
 	// the hash of the client's TLS certificate
 	TlsCertHash []byte `protobuf:"bytes,8,opt,name=tls_cert_hash,json=tlsCertHash,proto3" json:"tls_cert_hash,omitempty"`
+	// The priority class requested by the creator of the message. The orderer
+	// assigns the message to the class if the creator satisfies the policy of
+	// the class, see orderer.BlockScheduling
+	PriorityClass string `protobuf:"bytes,9,opt,name=priority_class,json=priorityClass" json:"priority_class,omitempty"`
 }
 
 func (m *ChannelHeader) Reset()                    { *m = ChannelHeader{} }
@@ -306,6 +310,13 @@ func (m *ChannelHeader) GetTlsCertHash() []byte {
 	return nil
 }
 
+func (m *ChannelHeader) GetPriorityClass() string {
+	if m != nil {
+		return m.PriorityClass
+	}
+	return ""
+}
+
 type SignatureHeader struct {
 	// Creator of the message, a marshaled msp.SerializedIdentity
 	Creator []byte `protobuf:"bytes,1,opt,name=creator,proto3" json:"creator,omitempty"`
@@ -510,65 +521,66 @@ func init() {
 func init() { proto.RegisterFile("common/common.proto", fileDescriptor1) }
 
 var fileDescriptor1 = []byte{
-	// 950 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x55, 0xcf, 0x6f, 0xe3, 0x44,
-	0x18, 0x5d, 0xc7, 0xf9, 0xd1, 0x7c, 0x6e, 0x5a, 0x77, 0xd2, 0xb2, 0xa6, 0xb0, 0xda, 0xca, 0xb0,
-	0xa8, 0xb4, 0x52, 0x2a, 0xca, 0x05, 0x8e, 0x8e, 0x3d, 0x69, 0xad, 0xa6, 0x76, 0x18, 0x3b, 0x8b,
-	0xd8, 0x45, 0xb2, 0x9c, 0x64, 0x9a, 0x44, 0x24, 0x76, 0x64, 0x4f, 0xaa, 0xf6, 0xcc, 0x1d, 0x21,
-	0xc1, 0x95, 0xbf, 0x82, 0x7f, 0x80, 0x23, 0x7f, 0x10, 0x88, 0x2b, 0x1a, 0x8f, 0xed, 0x4d, 0xca,
-	0x4a, 0x9c, 0x32, 0xef, 0xcd, 0xcb, 0xf7, 0xbd, 0xf9, 0xde, 0xd8, 0x86, 0xf6, 0x38, 0x5e, 0x2e,
-	0xe3, 0xe8, 0x42, 0xfc, 0x74, 0x56, 0x49, 0xcc, 0x62, 0x54, 0x17, 0xe8, 0xf8, 0xe5, 0x34, 0x8e,
-	0xa7, 0x0b, 0x7a, 0x91, 0xb1, 0xa3, 0xf5, 0xdd, 0x05, 0x9b, 0x2f, 0x69, 0xca, 0xc2, 0xe5, 0x4a,
-	0x08, 0x75, 0x1d, 0xa0, 0x1f, 0xa6, 0xcc, 0x8c, 0xa3, 0xbb, 0xf9, 0x14, 0x1d, 0x42, 0x6d, 0x1e,
-	0x4d, 0xe8, 0x83, 0x26, 0x9d, 0x48, 0xa7, 0x55, 0x22, 0x80, 0xfe, 0x16, 0x76, 0x6e, 0x29, 0x0b,
-	0x27, 0x21, 0x0b, 0xb9, 0xe2, 0x3e, 0x5c, 0xac, 0x69, 0xa6, 0xd8, 0x25, 0x02, 0xa0, 0xaf, 0x01,
-	0xd2, 0xf9, 0x34, 0x0a, 0xd9, 0x3a, 0xa1, 0xa9, 0x56, 0x39, 0x91, 0x4f, 0x95, 0xcb, 0x0f, 0x3b,
-	0xb9, 0xa3, 0xe2, 0xbf, 0x5e, 0xa1, 0x20, 0x1b, 0x62, 0xfd, 0x7b, 0x38, 0xf8, 0x8f, 0x00, 0x7d,
-	0x0e, 0x6a, 0x29, 0x09, 0x66, 0x34, 0x9c, 0xd0, 0x24, 0x6f, 0xb8, 0x5f, 0xf2, 0xd7, 0x19, 0x8d,
-	0x3e, 0x86, 0x66, 0x49, 0x69, 0x95, 0x4c, 0xf3, 0x8e, 0xd0, 0xdf, 0x40, 0x3d, 0xd7, 0xbd, 0x82,
-	0xbd, 0xf1, 0x2c, 0x8c, 0x22, 0xba, 0xd8, 0x2e, 0xd8, 0xca, 0xd9, 0x5c, 0xf6, 0xbe, 0xce, 0x95,
-	0xf7, 0x76, 0xd6, 0x7f, 0xac, 0x40, 0xcb, 0xdc, 0xfa, 0x33, 0x82, 0x2a, 0x7b, 0x5c, 0x89, 0xd9,
-	0xd4, 0x48, 0xb6, 0x46, 0x1a, 0x34, 0xee, 0x69, 0x92, 0xce, 0xe3, 0x28, 0xab, 0x53, 0x23, 0x05,
-	0x44, 0x5f, 0x41, 0xb3, 0x4c, 0x43, 0x93, 0x4f, 0xa4, 0x53, 0xe5, 0xf2, 0xb8, 0x23, 0xf2, 0xea,
-	0x14, 0x79, 0x75, 0xfc, 0x42, 0x41, 0xde, 0x89, 0xd1, 0x0b, 0x80, 0xe2, 0x2c, 0xf3, 0x89, 0x56,
-	0x3d, 0x91, 0x4e, 0x9b, 0xa4, 0x99, 0x33, 0xf6, 0x04, 0xb5, 0xa1, 0xc6, 0x1e, 0xf8, 0x4e, 0x2d,
-	0xdb, 0xa9, 0xb2, 0x07, 0x7b, 0xc2, 0x83, 0xa3, 0xab, 0x78, 0x3c, 0xd3, 0xea, 0x22, 0xda, 0x0c,
-	0xf0, 0xe9, 0xd1, 0x07, 0x46, 0xa3, 0xcc, 0x5f, 0x43, 0x4c, 0xaf, 0x24, 0x90, 0x0e, 0x2d, 0xb6,
-	0x48, 0x83, 0x31, 0x4d, 0x58, 0x30, 0x0b, 0xd3, 0x99, 0xb6, 0x93, 0x29, 0x14, 0xb6, 0x48, 0x4d,
-	0x9a, 0xb0, 0xeb, 0x30, 0x9d, 0xe9, 0x06, 0xec, 0x7b, 0x4f, 0x22, 0xd1, 0xa0, 0x31, 0x4e, 0x68,
-	0xc8, 0xe2, 0x62, 0xc6, 0x05, 0xe4, 0x26, 0xa2, 0x38, 0x1a, 0x17, 0x41, 0x09, 0xa0, 0x63, 0x68,
-	0x0c, 0xc2, 0xc7, 0x45, 0x1c, 0x4e, 0xd0, 0x67, 0x50, 0xdf, 0x48, 0x47, 0xb9, 0xdc, 0x2b, 0x2e,
-	0x91, 0x28, 0x4d, 0xf2, 0x5d, 0x3e, 0x69, 0x7e, 0x63, 0xf2, 0x3a, 0xd9, 0x5a, 0xef, 0xc2, 0x0e,
-	0x8e, 0xee, 0xe9, 0x22, 0x16, 0x53, 0x5f, 0x89, 0x92, 0x85, 0x85, 0x1c, 0xfe, 0xcf, 0x7d, 0xf9,
-	0x49, 0x82, 0x5a, 0x77, 0x11, 0x8f, 0x7f, 0x40, 0xe7, 0x4f, 0x9c, 0xb4, 0x0b, 0x27, 0xd9, 0xf6,
-	0x13, 0x3b, 0xaf, 0x36, 0xec, 0x28, 0x97, 0x07, 0x5b, 0x52, 0x2b, 0x64, 0xa1, 0x70, 0x88, 0xbe,
-	0x80, 0x9d, 0x65, 0x7e, 0xd7, 0xf3, 0xc0, 0x8f, 0xb6, 0xa4, 0xc5, 0x83, 0x40, 0x4a, 0x99, 0x3e,
-	0x05, 0x65, 0xa3, 0x21, 0xfa, 0x00, 0xea, 0xd1, 0x7a, 0x39, 0xca, 0x5d, 0x55, 0x49, 0x8e, 0xd0,
-	0x27, 0xd0, 0x5a, 0x25, 0xf4, 0x7e, 0x1e, 0xaf, 0x53, 0x91, 0x94, 0x38, 0xd9, 0x6e, 0x41, 0xf2,
-	0xa8, 0xd0, 0x47, 0xd0, 0xe4, 0x35, 0x85, 0x40, 0xce, 0x04, 0x3b, 0x9c, 0xc8, 0x72, 0x7c, 0x09,
-	0xcd, 0xd2, 0x6e, 0x39, 0x5e, 0xe9, 0x44, 0x2e, 0xc7, 0x7b, 0x0e, 0xad, 0x2d, 0x93, 0xe8, 0x78,
-	0xe3, 0x34, 0x42, 0x58, 0xe2, 0xb3, 0x3f, 0x24, 0xa8, 0x7b, 0x2c, 0x64, 0xeb, 0x14, 0x29, 0xd0,
-	0x18, 0x3a, 0x37, 0x8e, 0xfb, 0xad, 0xa3, 0x3e, 0x43, 0xbb, 0xd0, 0xf0, 0x86, 0xa6, 0x89, 0x3d,
-	0x4f, 0xfd, 0x53, 0x42, 0x2a, 0x28, 0x5d, 0xc3, 0x0a, 0x08, 0xfe, 0x66, 0x88, 0x3d, 0x5f, 0xfd,
-	0x59, 0x46, 0x7b, 0xd0, 0xec, 0xb9, 0xa4, 0x6b, 0x5b, 0x16, 0x76, 0xd4, 0x5f, 0x32, 0xec, 0xb8,
-	0x7e, 0xd0, 0x73, 0x87, 0x8e, 0xa5, 0xfe, 0x2a, 0xa3, 0x17, 0xa0, 0xe5, 0xea, 0x00, 0x3b, 0xbe,
-	0xed, 0x7f, 0x17, 0xf8, 0xae, 0x1b, 0xf4, 0x0d, 0x72, 0x85, 0xd5, 0xdf, 0x64, 0x74, 0x0c, 0x47,
-	0xb6, 0xe3, 0x63, 0xe2, 0x18, 0xfd, 0xc0, 0xc3, 0xe4, 0x35, 0x26, 0x01, 0x26, 0xc4, 0x25, 0xea,
-	0x5f, 0x32, 0x3a, 0x84, 0x7d, 0x5e, 0xca, 0xbe, 0x1d, 0xf4, 0xf1, 0x2d, 0x76, 0x7c, 0x6c, 0xa9,
-	0x7f, 0xcb, 0x48, 0x83, 0x36, 0x17, 0xda, 0x26, 0x0e, 0x86, 0x8e, 0xf1, 0xda, 0xb0, 0xfb, 0x46,
-	0xb7, 0x8f, 0xd5, 0x7f, 0xe4, 0xb3, 0xdf, 0x25, 0x00, 0x31, 0x75, 0x9f, 0x3f, 0xc7, 0x0a, 0x34,
-	0x6e, 0xb1, 0xe7, 0x19, 0x57, 0x58, 0x7d, 0x86, 0x00, 0xea, 0xa6, 0xeb, 0xf4, 0xec, 0x2b, 0x55,
-	0x42, 0x07, 0xd0, 0x12, 0xeb, 0x60, 0x38, 0xb0, 0x0c, 0x1f, 0xab, 0x15, 0xa4, 0xc1, 0x21, 0x76,
-	0x2c, 0x97, 0x78, 0x98, 0x04, 0x3e, 0x31, 0x1c, 0xcf, 0x30, 0x7d, 0xdb, 0x75, 0x54, 0x19, 0x3d,
-	0x87, 0xb6, 0x4b, 0x2c, 0x4c, 0x9e, 0x6c, 0x54, 0xd1, 0x11, 0x1c, 0x58, 0xb8, 0x6f, 0x73, 0xc7,
-	0x1e, 0xc6, 0x37, 0x81, 0xed, 0xf4, 0x5c, 0xb5, 0xc6, 0x69, 0xf3, 0xda, 0xb0, 0x1d, 0xd3, 0xb5,
-	0x70, 0x30, 0x30, 0xcc, 0x1b, 0xde, 0xbf, 0xce, 0x1b, 0x0c, 0x30, 0x26, 0x01, 0xc1, 0x9e, 0x3b,
-	0x24, 0xdc, 0xbb, 0x68, 0xdd, 0x38, 0x7b, 0x0b, 0x68, 0x2b, 0x25, 0x9b, 0xbf, 0xc1, 0xd1, 0x1e,
-	0x80, 0x67, 0x5f, 0x39, 0x86, 0x3f, 0x24, 0xd8, 0x53, 0x9f, 0xa1, 0x7d, 0x50, 0xfa, 0x86, 0xe7,
-	0x07, 0xe5, 0x21, 0x9e, 0x43, 0x7b, 0xc3, 0x8f, 0x17, 0xf4, 0xec, 0xbe, 0x8f, 0x89, 0x5a, 0xe1,
-	0xc7, 0xce, 0x0d, 0xab, 0x72, 0xd7, 0x83, 0x4f, 0xe3, 0x64, 0xda, 0x99, 0x3d, 0xae, 0x68, 0xb2,
-	0xa0, 0x93, 0x29, 0x4d, 0x3a, 0x77, 0xe1, 0x28, 0x99, 0x8f, 0xc5, 0xfb, 0x2a, 0xcd, 0x2f, 0xf3,
-	0x9b, 0xf3, 0xe9, 0x9c, 0xcd, 0xd6, 0x23, 0x0e, 0x2f, 0x36, 0xc4, 0x17, 0x42, 0x2c, 0x3e, 0x46,
-	0x69, 0xfe, 0xc1, 0x1a, 0xd5, 0x33, 0xf8, 0xe5, 0xbf, 0x01, 0x00, 0x00, 0xff, 0xff, 0xe2, 0x37,
-	0xfd, 0x02, 0xc8, 0x06, 0x00, 0x00,
+	// 968 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x55, 0xdd, 0x6e, 0xe3, 0x44,
+	0x18, 0xdd, 0xc4, 0xf9, 0x69, 0x3e, 0x37, 0xad, 0x3b, 0x69, 0x59, 0x53, 0x58, 0x6d, 0x65, 0x58,
+	0x54, 0x5a, 0x29, 0x15, 0xe5, 0x06, 0x2e, 0x1d, 0x7b, 0xda, 0x5a, 0x4d, 0xed, 0x32, 0x76, 0x16,
+	0xb1, 0x8b, 0x64, 0x39, 0xc9, 0x34, 0xb1, 0x70, 0xec, 0xc8, 0x9e, 0x54, 0xed, 0x4b, 0x20, 0x24,
+	0xb8, 0xe5, 0x05, 0xb8, 0xe5, 0x05, 0xb8, 0xe4, 0x81, 0x40, 0xdc, 0xa2, 0xf1, 0xd8, 0xde, 0xa4,
+	0xac, 0xc4, 0x55, 0x7c, 0xce, 0x9c, 0x7c, 0xdf, 0x99, 0xef, 0xcc, 0xd8, 0xd0, 0x9b, 0x24, 0x8b,
+	0x45, 0x12, 0x9f, 0x89, 0x9f, 0xfe, 0x32, 0x4d, 0x58, 0x82, 0x5a, 0x02, 0x1d, 0xbe, 0x9c, 0x25,
+	0xc9, 0x2c, 0xa2, 0x67, 0x39, 0x3b, 0x5e, 0xdd, 0x9d, 0xb1, 0x70, 0x41, 0x33, 0x16, 0x2c, 0x96,
+	0x42, 0xa8, 0x69, 0x00, 0xc3, 0x20, 0x63, 0x46, 0x12, 0xdf, 0x85, 0x33, 0xb4, 0x0f, 0xcd, 0x30,
+	0x9e, 0xd2, 0x07, 0xb5, 0x76, 0x54, 0x3b, 0x6e, 0x10, 0x01, 0xb4, 0xb7, 0xb0, 0x75, 0x43, 0x59,
+	0x30, 0x0d, 0x58, 0xc0, 0x15, 0xf7, 0x41, 0xb4, 0xa2, 0xb9, 0x62, 0x9b, 0x08, 0x80, 0xbe, 0x06,
+	0xc8, 0xc2, 0x59, 0x1c, 0xb0, 0x55, 0x4a, 0x33, 0xb5, 0x7e, 0x24, 0x1d, 0xcb, 0xe7, 0x1f, 0xf6,
+	0x0b, 0x47, 0xe5, 0x7f, 0xdd, 0x52, 0x41, 0xd6, 0xc4, 0xda, 0xf7, 0xb0, 0xf7, 0x1f, 0x01, 0xfa,
+	0x1c, 0x94, 0x4a, 0xe2, 0xcf, 0x69, 0x30, 0xa5, 0x69, 0xd1, 0x70, 0xb7, 0xe2, 0xaf, 0x72, 0x1a,
+	0x7d, 0x0c, 0x9d, 0x8a, 0x52, 0xeb, 0xb9, 0xe6, 0x1d, 0xa1, 0xbd, 0x81, 0x56, 0xa1, 0x7b, 0x05,
+	0x3b, 0x93, 0x79, 0x10, 0xc7, 0x34, 0xda, 0x2c, 0xd8, 0x2d, 0xd8, 0x42, 0xf6, 0xbe, 0xce, 0xf5,
+	0xf7, 0x76, 0xd6, 0x7e, 0xab, 0x43, 0xd7, 0xd8, 0xf8, 0x33, 0x82, 0x06, 0x7b, 0x5c, 0x8a, 0xd9,
+	0x34, 0x49, 0xfe, 0x8c, 0x54, 0x68, 0xdf, 0xd3, 0x34, 0x0b, 0x93, 0x38, 0xaf, 0xd3, 0x24, 0x25,
+	0x44, 0x5f, 0x41, 0xa7, 0x4a, 0x43, 0x95, 0x8e, 0x6a, 0xc7, 0xf2, 0xf9, 0x61, 0x5f, 0xe4, 0xd5,
+	0x2f, 0xf3, 0xea, 0x7b, 0xa5, 0x82, 0xbc, 0x13, 0xa3, 0x17, 0x00, 0xe5, 0x5e, 0xc2, 0xa9, 0xda,
+	0x38, 0xaa, 0x1d, 0x77, 0x48, 0xa7, 0x60, 0xac, 0x29, 0xea, 0x41, 0x93, 0x3d, 0xf0, 0x95, 0x66,
+	0xbe, 0xd2, 0x60, 0x0f, 0xd6, 0x94, 0x07, 0x47, 0x97, 0xc9, 0x64, 0xae, 0xb6, 0x44, 0xb4, 0x39,
+	0xe0, 0xd3, 0xa3, 0x0f, 0x8c, 0xc6, 0xb9, 0xbf, 0xb6, 0x98, 0x5e, 0x45, 0x20, 0x0d, 0xba, 0x2c,
+	0xca, 0xfc, 0x09, 0x4d, 0x99, 0x3f, 0x0f, 0xb2, 0xb9, 0xba, 0x95, 0x2b, 0x64, 0x16, 0x65, 0x06,
+	0x4d, 0xd9, 0x55, 0x90, 0xcd, 0xf9, 0x5c, 0x97, 0x69, 0x98, 0xa4, 0x21, 0x7b, 0xf4, 0x27, 0x51,
+	0x90, 0x65, 0x6a, 0x27, 0xef, 0xda, 0x2d, 0x59, 0x83, 0x93, 0x9a, 0x0e, 0xbb, 0xee, 0x93, 0xe4,
+	0x54, 0x68, 0x4f, 0x52, 0x1a, 0xb0, 0xa4, 0x8c, 0xa2, 0x84, 0xdc, 0x6b, 0x9c, 0xc4, 0x93, 0x32,
+	0x4f, 0x01, 0x34, 0x0c, 0xed, 0xdb, 0xe0, 0x31, 0x4a, 0x82, 0x29, 0xfa, 0x0c, 0x5a, 0x6b, 0x21,
+	0xca, 0xe7, 0x3b, 0xe5, 0x59, 0x13, 0xa5, 0x49, 0x6b, 0x5e, 0x05, 0xc2, 0x0f, 0x56, 0x51, 0x27,
+	0x7f, 0xd6, 0x06, 0xb0, 0x85, 0xe3, 0x7b, 0x1a, 0x25, 0x22, 0x9c, 0xa5, 0x28, 0x59, 0x5a, 0x28,
+	0xe0, 0xff, 0x1c, 0xab, 0x1f, 0x6b, 0xd0, 0x1c, 0x44, 0xc9, 0xe4, 0x07, 0x74, 0xfa, 0xc4, 0x49,
+	0xaf, 0x74, 0x92, 0x2f, 0x3f, 0xb1, 0xf3, 0x6a, 0xcd, 0x8e, 0x7c, 0xbe, 0xb7, 0x21, 0x35, 0x03,
+	0x16, 0x08, 0x87, 0xe8, 0x0b, 0xd8, 0x5a, 0x14, 0x57, 0xa2, 0x38, 0x17, 0x07, 0x1b, 0xd2, 0xf2,
+	0xbe, 0x90, 0x4a, 0xa6, 0xcd, 0x40, 0x5e, 0x6b, 0x88, 0x3e, 0x80, 0x56, 0xbc, 0x5a, 0x8c, 0x0b,
+	0x57, 0x0d, 0x52, 0x20, 0xf4, 0x09, 0x74, 0x97, 0x29, 0xbd, 0x0f, 0x93, 0x55, 0x26, 0x02, 0x15,
+	0x3b, 0xdb, 0x2e, 0xc9, 0x3c, 0xd1, 0x8f, 0xa0, 0xc3, 0x6b, 0x0a, 0x81, 0x94, 0x0b, 0xb6, 0x38,
+	0xc1, 0x17, 0xb5, 0x97, 0xd0, 0xa9, 0xec, 0x56, 0xe3, 0xad, 0x1d, 0x49, 0xd5, 0x78, 0x4f, 0xa1,
+	0xbb, 0x61, 0x12, 0x1d, 0xae, 0xed, 0x46, 0x08, 0x2b, 0x7c, 0xf2, 0x47, 0x0d, 0x5a, 0x2e, 0x0b,
+	0xd8, 0x2a, 0x43, 0x32, 0xb4, 0x47, 0xf6, 0xb5, 0xed, 0x7c, 0x6b, 0x2b, 0xcf, 0xd0, 0x36, 0xb4,
+	0xdd, 0x91, 0x61, 0x60, 0xd7, 0x55, 0xfe, 0xac, 0x21, 0x05, 0xe4, 0x81, 0x6e, 0xfa, 0x04, 0x7f,
+	0x33, 0xc2, 0xae, 0xa7, 0xfc, 0x24, 0xa1, 0x1d, 0xe8, 0x5c, 0x38, 0x64, 0x60, 0x99, 0x26, 0xb6,
+	0x95, 0x9f, 0x73, 0x6c, 0x3b, 0x9e, 0x7f, 0xe1, 0x8c, 0x6c, 0x53, 0xf9, 0x45, 0x42, 0x2f, 0x40,
+	0x2d, 0xd4, 0x3e, 0xb6, 0x3d, 0xcb, 0xfb, 0xce, 0xf7, 0x1c, 0xc7, 0x1f, 0xea, 0xe4, 0x12, 0x2b,
+	0xbf, 0x4a, 0xe8, 0x10, 0x0e, 0x2c, 0xdb, 0xc3, 0xc4, 0xd6, 0x87, 0xbe, 0x8b, 0xc9, 0x6b, 0x4c,
+	0x7c, 0x4c, 0x88, 0x43, 0x94, 0xbf, 0x24, 0xb4, 0x0f, 0xbb, 0xbc, 0x94, 0x75, 0x73, 0x3b, 0xc4,
+	0x37, 0xd8, 0xf6, 0xb0, 0xa9, 0xfc, 0x2d, 0x21, 0x15, 0x7a, 0x5c, 0x68, 0x19, 0xd8, 0x1f, 0xd9,
+	0xfa, 0x6b, 0xdd, 0x1a, 0xea, 0x83, 0x21, 0x56, 0xfe, 0x91, 0x4e, 0x7e, 0xaf, 0x01, 0x88, 0xa9,
+	0x7b, 0xfc, 0xba, 0xcb, 0xd0, 0xbe, 0xc1, 0xae, 0xab, 0x5f, 0x62, 0xe5, 0x19, 0x02, 0x68, 0x19,
+	0x8e, 0x7d, 0x61, 0x5d, 0x2a, 0x35, 0xb4, 0x07, 0x5d, 0xf1, 0xec, 0x8f, 0x6e, 0x4d, 0xdd, 0xc3,
+	0x4a, 0x1d, 0xa9, 0xb0, 0x8f, 0x6d, 0xd3, 0x21, 0x2e, 0x26, 0xbe, 0x47, 0x74, 0xdb, 0xd5, 0x0d,
+	0xcf, 0x72, 0x6c, 0x45, 0x42, 0xcf, 0xa1, 0xe7, 0x10, 0x13, 0x93, 0x27, 0x0b, 0x0d, 0x74, 0x00,
+	0x7b, 0x26, 0x1e, 0x5a, 0xdc, 0xb1, 0x8b, 0xf1, 0xb5, 0x6f, 0xd9, 0x17, 0x8e, 0xd2, 0xe4, 0xb4,
+	0x71, 0xa5, 0x5b, 0xb6, 0xe1, 0x98, 0xd8, 0xbf, 0xd5, 0x8d, 0x6b, 0xde, 0xbf, 0xc5, 0x1b, 0xdc,
+	0x62, 0x4c, 0x7c, 0x82, 0x5d, 0x67, 0x44, 0x0c, 0x5c, 0xb6, 0x6e, 0x9f, 0xbc, 0x05, 0xb4, 0x91,
+	0x92, 0xc5, 0x5f, 0xf4, 0x68, 0x07, 0xc0, 0xb5, 0x2e, 0x6d, 0xdd, 0x1b, 0x11, 0xec, 0x2a, 0xcf,
+	0xd0, 0x2e, 0xc8, 0x43, 0xdd, 0xf5, 0xfc, 0x6a, 0x13, 0xcf, 0xa1, 0xb7, 0xe6, 0xc7, 0xf5, 0x2f,
+	0xac, 0xa1, 0x87, 0x89, 0x52, 0xe7, 0xdb, 0x2e, 0x0c, 0x2b, 0xd2, 0xc0, 0x85, 0x4f, 0x93, 0x74,
+	0xd6, 0x9f, 0x3f, 0x2e, 0x69, 0x1a, 0xd1, 0xe9, 0x8c, 0xa6, 0xfd, 0xbb, 0x60, 0x9c, 0x86, 0x13,
+	0xf1, 0x5a, 0xcb, 0x8a, 0xc3, 0xfc, 0xe6, 0x74, 0x16, 0xb2, 0xf9, 0x6a, 0xcc, 0xe1, 0xd9, 0x9a,
+	0xf8, 0x4c, 0x88, 0xc5, 0x37, 0x2b, 0x2b, 0xbe, 0x6b, 0xe3, 0x56, 0x0e, 0xbf, 0xfc, 0x77, 0x00,
+	0x82, 0xe2, 0x4a, 0x35, 0xef, 0x06, 0x00, 0x00,
 }
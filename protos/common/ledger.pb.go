@@ -46,24 +46,238 @@ func (m *BlockchainInfo) GetPreviousBlockHash() []byte {
 	return nil
 }
 
+// Contains the information about the private data of the committed blocks
+// that is missing at a peer. The entries are ordered by the block number,
+// most recent blocks first.
+type MissingPvtDataInfo struct {
+	Entries []*MissingPvtDataEntry `protobuf:"bytes,1,rep,name=entries" json:"entries,omitempty"`
+}
+
+func (m *MissingPvtDataInfo) Reset()                    { *m = MissingPvtDataInfo{} }
+func (m *MissingPvtDataInfo) String() string            { return proto.CompactTextString(m) }
+func (*MissingPvtDataInfo) ProtoMessage()               {}
+func (*MissingPvtDataInfo) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{1} }
+
+func (m *MissingPvtDataInfo) GetEntries() []*MissingPvtDataEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+// Identifies the private data of a collection, written by a transaction
+// of a committed block, that is missing at a peer.
+type MissingPvtDataEntry struct {
+	BlockNum   uint64 `protobuf:"varint,1,opt,name=block_num,json=blockNum" json:"block_num,omitempty"`
+	TxNum      uint64 `protobuf:"varint,2,opt,name=tx_num,json=txNum" json:"tx_num,omitempty"`
+	TxId       string `protobuf:"bytes,3,opt,name=tx_id,json=txId" json:"tx_id,omitempty"`
+	Namespace  string `protobuf:"bytes,4,opt,name=namespace" json:"namespace,omitempty"`
+	Collection string `protobuf:"bytes,5,opt,name=collection" json:"collection,omitempty"`
+}
+
+func (m *MissingPvtDataEntry) Reset()                    { *m = MissingPvtDataEntry{} }
+func (m *MissingPvtDataEntry) String() string            { return proto.CompactTextString(m) }
+func (*MissingPvtDataEntry) ProtoMessage()               {}
+func (*MissingPvtDataEntry) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{2} }
+
+func (m *MissingPvtDataEntry) GetBlockNum() uint64 {
+	if m != nil {
+		return m.BlockNum
+	}
+	return 0
+}
+
+func (m *MissingPvtDataEntry) GetTxNum() uint64 {
+	if m != nil {
+		return m.TxNum
+	}
+	return 0
+}
+
+func (m *MissingPvtDataEntry) GetTxId() string {
+	if m != nil {
+		return m.TxId
+	}
+	return ""
+}
+
+func (m *MissingPvtDataEntry) GetNamespace() string {
+	if m != nil {
+		return m.Namespace
+	}
+	return ""
+}
+
+func (m *MissingPvtDataEntry) GetCollection() string {
+	if m != nil {
+		return m.Collection
+	}
+	return ""
+}
+
+// Contains a range of blocks of the ledger, ordered by the block number.
+type BlockList struct {
+	Blocks []*Block `protobuf:"bytes,1,rep,name=blocks" json:"blocks,omitempty"`
+}
+
+func (m *BlockList) Reset()                    { *m = BlockList{} }
+func (m *BlockList) String() string            { return proto.CompactTextString(m) }
+func (*BlockList) ProtoMessage()               {}
+func (*BlockList) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{3} }
+
+func (m *BlockList) GetBlocks() []*Block {
+	if m != nil {
+		return m.Blocks
+	}
+	return nil
+}
+
+// Contains the headers of a range of blocks of the ledger, ordered by the
+// block number.
+type BlockHeaderList struct {
+	Headers []*BlockHeader `protobuf:"bytes,1,rep,name=headers" json:"headers,omitempty"`
+}
+
+func (m *BlockHeaderList) Reset()                    { *m = BlockHeaderList{} }
+func (m *BlockHeaderList) String() string            { return proto.CompactTextString(m) }
+func (*BlockHeaderList) ProtoMessage()               {}
+func (*BlockHeaderList) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{4} }
+
+func (m *BlockHeaderList) GetHeaders() []*BlockHeader {
+	if m != nil {
+		return m.Headers
+	}
+	return nil
+}
+
+// Summarizes a transaction of a committed block: its position in the
+// ledger, its type, the chaincode it invoked (for endorser transactions),
+// the MSP of its creator and its validation code (a protos.TxValidationCode).
+type TransactionInfo struct {
+	BlockNum       uint64     `protobuf:"varint,1,opt,name=block_num,json=blockNum" json:"block_num,omitempty"`
+	TxNum          uint64     `protobuf:"varint,2,opt,name=tx_num,json=txNum" json:"tx_num,omitempty"`
+	TxId           string     `protobuf:"bytes,3,opt,name=tx_id,json=txId" json:"tx_id,omitempty"`
+	Type           HeaderType `protobuf:"varint,4,opt,name=type,enum=common.HeaderType" json:"type,omitempty"`
+	Chaincode      string     `protobuf:"bytes,5,opt,name=chaincode" json:"chaincode,omitempty"`
+	CreatorMspId   string     `protobuf:"bytes,6,opt,name=creator_msp_id,json=creatorMspId" json:"creator_msp_id,omitempty"`
+	ValidationCode int32      `protobuf:"varint,7,opt,name=validation_code,json=validationCode" json:"validation_code,omitempty"`
+}
+
+func (m *TransactionInfo) Reset()                    { *m = TransactionInfo{} }
+func (m *TransactionInfo) String() string            { return proto.CompactTextString(m) }
+func (*TransactionInfo) ProtoMessage()               {}
+func (*TransactionInfo) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{5} }
+
+func (m *TransactionInfo) GetBlockNum() uint64 {
+	if m != nil {
+		return m.BlockNum
+	}
+	return 0
+}
+
+func (m *TransactionInfo) GetTxNum() uint64 {
+	if m != nil {
+		return m.TxNum
+	}
+	return 0
+}
+
+func (m *TransactionInfo) GetTxId() string {
+	if m != nil {
+		return m.TxId
+	}
+	return ""
+}
+
+func (m *TransactionInfo) GetType() HeaderType {
+	if m != nil {
+		return m.Type
+	}
+	return HeaderType_MESSAGE
+}
+
+func (m *TransactionInfo) GetChaincode() string {
+	if m != nil {
+		return m.Chaincode
+	}
+	return ""
+}
+
+func (m *TransactionInfo) GetCreatorMspId() string {
+	if m != nil {
+		return m.CreatorMspId
+	}
+	return ""
+}
+
+func (m *TransactionInfo) GetValidationCode() int32 {
+	if m != nil {
+		return m.ValidationCode
+	}
+	return 0
+}
+
+// Contains the summaries of transactions, ordered by the block number and
+// the position of the transactions in their block.
+type TransactionInfoList struct {
+	Transactions []*TransactionInfo `protobuf:"bytes,1,rep,name=transactions" json:"transactions,omitempty"`
+}
+
+func (m *TransactionInfoList) Reset()                    { *m = TransactionInfoList{} }
+func (m *TransactionInfoList) String() string            { return proto.CompactTextString(m) }
+func (*TransactionInfoList) ProtoMessage()               {}
+func (*TransactionInfoList) Descriptor() ([]byte, []int) { return fileDescriptor4, []int{6} }
+
+func (m *TransactionInfoList) GetTransactions() []*TransactionInfo {
+	if m != nil {
+		return m.Transactions
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*BlockchainInfo)(nil), "common.BlockchainInfo")
+	proto.RegisterType((*MissingPvtDataInfo)(nil), "common.MissingPvtDataInfo")
+	proto.RegisterType((*MissingPvtDataEntry)(nil), "common.MissingPvtDataEntry")
+	proto.RegisterType((*BlockList)(nil), "common.BlockList")
+	proto.RegisterType((*BlockHeaderList)(nil), "common.BlockHeaderList")
+	proto.RegisterType((*TransactionInfo)(nil), "common.TransactionInfo")
+	proto.RegisterType((*TransactionInfoList)(nil), "common.TransactionInfoList")
 }
 
 func init() { proto.RegisterFile("common/ledger.proto", fileDescriptor4) }
 
 var fileDescriptor4 = []byte{
-	// 186 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0x12, 0x4e, 0xce, 0xcf, 0xcd,
-	0xcd, 0xcf, 0xd3, 0xcf, 0x49, 0x4d, 0x49, 0x4f, 0x2d, 0xd2, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17,
-	0x62, 0x83, 0x08, 0x2a, 0x35, 0x31, 0x72, 0xf1, 0x39, 0xe5, 0xe4, 0x27, 0x67, 0x27, 0x67, 0x24,
-	0x66, 0xe6, 0x79, 0xe6, 0xa5, 0xe5, 0x0b, 0x89, 0x71, 0xb1, 0x65, 0xa4, 0x66, 0xa6, 0x67, 0x94,
-	0x48, 0x30, 0x2a, 0x30, 0x6a, 0xb0, 0x04, 0x41, 0x79, 0x42, 0x5a, 0x5c, 0x02, 0xc9, 0xa5, 0x45,
-	0x45, 0xa9, 0x79, 0x25, 0x60, 0x0d, 0x1e, 0x89, 0xc5, 0x19, 0x12, 0x4c, 0x0a, 0x8c, 0x1a, 0x3c,
-	0x41, 0x18, 0xe2, 0x42, 0x3a, 0x5c, 0x82, 0x05, 0x45, 0xa9, 0x65, 0x99, 0xf9, 0xa5, 0xc5, 0x08,
-	0xc5, 0xcc, 0x60, 0xc5, 0x98, 0x12, 0x4e, 0xc1, 0x5c, 0x2a, 0xf9, 0x45, 0xe9, 0x7a, 0x19, 0x95,
-	0x05, 0xa9, 0x45, 0x50, 0x57, 0xa6, 0x25, 0x26, 0x15, 0x65, 0x26, 0x43, 0x1c, 0x5b, 0xac, 0x07,
-	0x71, 0x6c, 0x94, 0x76, 0x7a, 0x66, 0x49, 0x46, 0x69, 0x12, 0x88, 0xab, 0x8f, 0xa4, 0x58, 0x1f,
-	0xa2, 0x58, 0x1f, 0xa2, 0x58, 0x1f, 0xa2, 0x38, 0x89, 0x0d, 0xcc, 0x35, 0x06, 0x04, 0x00, 0x00,
-	0xff, 0xff, 0x9f, 0xcc, 0x05, 0xd1, 0xff, 0x00, 0x00, 0x00,
+	// 486 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xac, 0x53, 0x4f, 0x8f, 0xd3, 0x3e,
+	0x14, 0x54, 0x76, 0xdb, 0xf4, 0xd7, 0xf7, 0x2b, 0x2d, 0xb8, 0x02, 0x2a, 0x16, 0xa1, 0x2a, 0x5a,
+	0xa0, 0xe2, 0x4f, 0x2b, 0x15, 0x71, 0xe2, 0x82, 0x16, 0x90, 0xa8, 0x60, 0x11, 0x32, 0x7b, 0xe2,
+	0x52, 0xb9, 0x8e, 0x37, 0xb1, 0x48, 0xec, 0xc8, 0x76, 0xaa, 0xf6, 0xca, 0xe7, 0xe0, 0x3b, 0xf2,
+	0x15, 0x50, 0x9e, 0x13, 0x65, 0xbb, 0xcb, 0x91, 0x53, 0xf4, 0x66, 0xe6, 0xd9, 0x33, 0xa3, 0x18,
+	0xc6, 0x5c, 0xe7, 0xb9, 0x56, 0x8b, 0x4c, 0xc4, 0x89, 0x30, 0xf3, 0xc2, 0x68, 0xa7, 0x49, 0xe8,
+	0xc1, 0x07, 0x0d, 0xe9, 0x3f, 0x9e, 0x8c, 0x7e, 0x06, 0x30, 0x3c, 0xcb, 0x34, 0xff, 0xc1, 0x53,
+	0x26, 0xd5, 0x4a, 0x5d, 0x6a, 0x72, 0x0f, 0xc2, 0x54, 0xc8, 0x24, 0x75, 0x93, 0x60, 0x1a, 0xcc,
+	0x3a, 0xb4, 0x9e, 0xc8, 0x33, 0xb8, 0xcd, 0x4b, 0x63, 0x84, 0x72, 0xb8, 0xf0, 0x91, 0xd9, 0x74,
+	0x72, 0x34, 0x0d, 0x66, 0x03, 0x7a, 0x03, 0x27, 0x2f, 0xe0, 0x4e, 0x61, 0xc4, 0x56, 0xea, 0xd2,
+	0xb6, 0xe2, 0x63, 0x14, 0xdf, 0x24, 0xa2, 0x4f, 0x40, 0xce, 0xa5, 0xb5, 0x52, 0x25, 0x5f, 0xb7,
+	0xee, 0x3d, 0x73, 0x0c, 0x7d, 0xbc, 0x86, 0x9e, 0x50, 0xce, 0x48, 0x61, 0x27, 0xc1, 0xf4, 0x78,
+	0xf6, 0xff, 0xf2, 0x64, 0x5e, 0x5b, 0x3f, 0x14, 0x7f, 0x50, 0xce, 0xec, 0x69, 0xa3, 0x8d, 0x7e,
+	0x05, 0x30, 0xfe, 0x8b, 0x80, 0x9c, 0x40, 0x7f, 0x53, 0xdd, 0xb8, 0x56, 0x65, 0x5e, 0x27, 0xfb,
+	0x0f, 0x81, 0x2f, 0x65, 0x4e, 0xee, 0x42, 0xe8, 0x76, 0xc8, 0x1c, 0x21, 0xd3, 0x75, 0xbb, 0x0a,
+	0x1e, 0x43, 0xd7, 0xed, 0xd6, 0x32, 0x46, 0xeb, 0x7d, 0xda, 0x71, 0xbb, 0x55, 0x4c, 0x1e, 0x42,
+	0x5f, 0xb1, 0x5c, 0xd8, 0x82, 0x71, 0x31, 0xe9, 0x20, 0xd1, 0x02, 0xe4, 0x11, 0x00, 0xd7, 0x59,
+	0x26, 0xb8, 0x93, 0x5a, 0x4d, 0xba, 0x48, 0x5f, 0x41, 0xa2, 0x25, 0xf4, 0x31, 0xf8, 0x67, 0x69,
+	0x1d, 0x79, 0x0c, 0x21, 0x5a, 0x68, 0x12, 0xde, 0x6a, 0x12, 0xa2, 0x84, 0xd6, 0x64, 0xf4, 0x16,
+	0x46, 0xbe, 0x2c, 0xc1, 0x62, 0x61, 0x70, 0xf3, 0x25, 0xf4, 0x52, 0x9c, 0x9a, 0xd5, 0xf1, 0xc1,
+	0xaa, 0x57, 0xd2, 0x46, 0x13, 0xfd, 0x0e, 0x60, 0x74, 0x61, 0x98, 0xb2, 0x0c, 0x5d, 0x60, 0xbf,
+	0xff, 0xac, 0x90, 0x27, 0xd0, 0x71, 0xfb, 0xc2, 0x77, 0x31, 0x5c, 0x92, 0xc6, 0x88, 0xf7, 0x70,
+	0xb1, 0x2f, 0x04, 0x45, 0xbe, 0x2a, 0x0e, 0xff, 0x32, 0xae, 0x63, 0x51, 0x37, 0xd3, 0x02, 0xe4,
+	0x14, 0x86, 0xdc, 0x08, 0xe6, 0xb4, 0x59, 0xe7, 0xb6, 0xa8, 0xee, 0x08, 0x51, 0x32, 0xa8, 0xd1,
+	0x73, 0x5b, 0xac, 0x62, 0xf2, 0x14, 0x46, 0x5b, 0x96, 0xc9, 0x98, 0x55, 0x31, 0xd6, 0x78, 0x52,
+	0x6f, 0x1a, 0xcc, 0xba, 0x74, 0xd8, 0xc2, 0xef, 0x74, 0x2c, 0x22, 0x0a, 0xe3, 0x6b, 0x81, 0xb1,
+	0xb7, 0x37, 0x30, 0x70, 0x2d, 0xdc, 0x94, 0x77, 0xbf, 0xf1, 0x7c, 0x6d, 0x85, 0x1e, 0x88, 0xcf,
+	0xbe, 0xc1, 0xa9, 0x36, 0xc9, 0x3c, 0xdd, 0x17, 0xc2, 0xd4, 0x4f, 0xec, 0x92, 0x6d, 0x8c, 0xe4,
+	0xfe, 0x31, 0xd9, 0xfa, 0x94, 0xef, 0xcf, 0x13, 0xe9, 0xd2, 0x72, 0x53, 0x8d, 0x8b, 0x2b, 0xe2,
+	0x85, 0x17, 0x2f, 0xbc, 0xb8, 0x7e, 0x87, 0x9b, 0x10, 0xc7, 0x57, 0x7f, 0x06, 0x00, 0x24, 0x1d,
+	0x3d, 0x5e, 0xbc, 0x03, 0x00, 0x00,
 }
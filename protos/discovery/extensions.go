@@ -0,0 +1,82 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// QueryType defines the types of service discovery requests
+type QueryType uint8
+
+// QueryType values
+const (
+	InvalidQueryType QueryType = iota
+	ConfigQueryType
+	PeerMembershipQueryType
+	ChaincodeQueryType
+)
+
+// GetType returns the type of the query
+func (q *Query) GetType() QueryType {
+	if q.GetCcQuery() != nil {
+		return ChaincodeQueryType
+	}
+	if q.GetConfigQuery() != nil {
+		return ConfigQueryType
+	}
+	if q.GetPeerQuery() != nil {
+		return PeerMembershipQueryType
+	}
+	return InvalidQueryType
+}
+
+// ToRequest deserializes this SignedRequest's payload
+// and returns the serialized Request in its object form.
+// Returns an error in case the operation fails.
+func (sr *SignedRequest) ToRequest() (*Request, error) {
+	req := &Request{}
+	return req, proto.Unmarshal(sr.Payload, req)
+}
+
+// ConfigAt returns the ConfigResult at a given index in the Response,
+// or an Error if present.
+func (m *Response) ConfigAt(i int) (*ConfigResult, *Error) {
+	r := m.Results[i]
+	return r.GetConfigResult(), r.GetError()
+}
+
+// MembershipAt returns the PeerMembershipResult at a given index in the Response,
+// or an Error if present.
+func (m *Response) MembershipAt(i int) (*PeerMembershipResult, *Error) {
+	r := m.Results[i]
+	return r.GetMembers(), r.GetError()
+}
+
+// EndorsersAt returns the ChaincodeQueryResult at a given index in the Response,
+// or an Error if present.
+func (m *Response) EndorsersAt(i int) (*ChaincodeQueryResult, *Error) {
+	r := m.Results[i]
+	return r.GetCcQueryRes(), r.GetError()
+}
+
+// NewChaincodeInterest returns a ChaincodeInterest for an invocation of the
+// first given chaincode that calls the rest of the given chaincodes
+func NewChaincodeInterest(chaincodes ...string) (*ChaincodeInterest, error) {
+	if len(chaincodes) == 0 {
+		return nil, errors.New("no chaincode given")
+	}
+	interest := &ChaincodeInterest{}
+	for _, cc := range chaincodes {
+		if cc == "" {
+			return nil, errors.New("chaincode name is empty")
+		}
+		interest.Chaincodes = append(interest.Chaincodes, &ChaincodeCall{Name: cc})
+	}
+	return interest, nil
+}
@@ -0,0 +1,70 @@
+/*
+Copyright IBM Corp. All Rights Reserved.
+
+SPDX-License-Identifier: Apache-2.0
+*/
+
+package discovery
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryType(t *testing.T) {
+	q := &Query{Query: &Query_ConfigQuery{ConfigQuery: &ConfigQuery{}}}
+	assert.Equal(t, ConfigQueryType, q.GetType())
+	q = &Query{Query: &Query_PeerQuery{PeerQuery: &PeerMembershipQuery{}}}
+	assert.Equal(t, PeerMembershipQueryType, q.GetType())
+	q = &Query{Query: &Query_CcQuery{CcQuery: &ChaincodeQuery{}}}
+	assert.Equal(t, ChaincodeQueryType, q.GetType())
+	q = &Query{}
+	assert.Equal(t, InvalidQueryType, q.GetType())
+}
+
+func TestToRequest(t *testing.T) {
+	sr := &SignedRequest{Payload: []byte{0}}
+	_, err := sr.ToRequest()
+	assert.Error(t, err)
+
+	req := &Request{Authentication: &AuthInfo{ClientIdentity: []byte("identity")}}
+	sr.Payload, _ = proto.Marshal(req)
+	req2, err := sr.ToRequest()
+	assert.NoError(t, err)
+	assert.True(t, proto.Equal(req, req2))
+}
+
+func TestResultAt(t *testing.T) {
+	res := &Response{
+		Results: []*QueryResult{
+			{Result: &QueryResult_ConfigResult{ConfigResult: &ConfigResult{}}},
+			{Result: &QueryResult_Members{Members: &PeerMembershipResult{}}},
+			{Result: &QueryResult_CcQueryRes{CcQueryRes: &ChaincodeQueryResult{}}},
+			{Result: &QueryResult_Error{Error: &Error{Content: "bad request"}}},
+		},
+	}
+	config, errRes := res.ConfigAt(0)
+	assert.NotNil(t, config)
+	assert.Nil(t, errRes)
+	members, errRes := res.MembershipAt(1)
+	assert.NotNil(t, members)
+	assert.Nil(t, errRes)
+	endorsers, errRes := res.EndorsersAt(2)
+	assert.NotNil(t, endorsers)
+	assert.Nil(t, errRes)
+	endorsers, errRes = res.EndorsersAt(3)
+	assert.Nil(t, endorsers)
+	assert.Equal(t, "bad request", errRes.Content)
+}
+
+func TestNewChaincodeInterest(t *testing.T) {
+	_, err := NewChaincodeInterest()
+	assert.Error(t, err)
+	_, err = NewChaincodeInterest("cc1", "")
+	assert.Error(t, err)
+	interest, err := NewChaincodeInterest("cc1", "cc2")
+	assert.NoError(t, err)
+	assert.Equal(t, []*ChaincodeCall{{Name: "cc1"}, {Name: "cc2"}}, interest.Chaincodes)
+}
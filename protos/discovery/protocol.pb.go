@@ -0,0 +1,979 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: discovery/protocol.proto
+
+/*
+Package discovery is a generated protocol buffer package.
+
+It is generated from these files:
+	discovery/protocol.proto
+
+It has these top-level messages:
+	SignedRequest
+	Request
+	AuthInfo
+	Query
+	Response
+	QueryResult
+	ConfigQuery
+	ConfigResult
+	PeerMembershipQuery
+	PeerMembershipResult
+	ChaincodeQuery
+	ChaincodeInterest
+	ChaincodeCall
+	ChaincodeQueryResult
+	EndorsementDescriptor
+	Layout
+	Peers
+	Peer
+	Error
+	Endpoint
+*/
+package discovery
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import msp "github.com/hyperledger/fabric/protos/msp"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
+
+// SignedRequest contains a serialized Request in the payload field
+// and a signature.
+// The identity that is used to verify the signature
+// can be extracted from the authentication field of type AuthInfo
+// in the Request itself after deserializing it.
+type SignedRequest struct {
+	Payload   []byte `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`
+	Signature []byte `protobuf:"bytes,2,opt,name=signature,proto3" json:"signature,omitempty"`
+}
+
+func (m *SignedRequest) Reset()                    { *m = SignedRequest{} }
+func (m *SignedRequest) String() string            { return proto.CompactTextString(m) }
+func (*SignedRequest) ProtoMessage()               {}
+func (*SignedRequest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+
+func (m *SignedRequest) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *SignedRequest) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+// Request contains authentication info about the client that sent the request
+// and the queries it wishes to query the service
+type Request struct {
+	// authentication contains information that the service uses to check
+	// the client's eligibility for the queries.
+	Authentication *AuthInfo `protobuf:"bytes,1,opt,name=authentication" json:"authentication,omitempty"`
+	// queries
+	Queries []*Query `protobuf:"bytes,2,rep,name=queries" json:"queries,omitempty"`
+}
+
+func (m *Request) Reset()                    { *m = Request{} }
+func (m *Request) String() string            { return proto.CompactTextString(m) }
+func (*Request) ProtoMessage()               {}
+func (*Request) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{1} }
+
+func (m *Request) GetAuthentication() *AuthInfo {
+	if m != nil {
+		return m.Authentication
+	}
+	return nil
+}
+
+func (m *Request) GetQueries() []*Query {
+	if m != nil {
+		return m.Queries
+	}
+	return nil
+}
+
+// AuthInfo aggregates authentication information that the server uses
+// to authenticate the client
+type AuthInfo struct {
+	// This is the identity of the client that is used to verify the signature
+	// on the SignedRequest's payload.
+	// It is a msp.SerializedIdentity in bytes form
+	ClientIdentity []byte `protobuf:"bytes,1,opt,name=client_identity,json=clientIdentity,proto3" json:"client_identity,omitempty"`
+	// This is the hash of the client's TLS cert.
+	// When the network is running with TLS, clients that don't include a certificate
+	// will be denied access to the service.
+	// Since the Request is encapsulated with a SignedRequest (which is signed),
+	// this binds the TLS session to the enrollment identity of the client and
+	// therefore both authenticates the client to the server,
+	// and also prevents the server from relaying the request message to another server.
+	ClientTlsCertHash []byte `protobuf:"bytes,2,opt,name=client_tls_cert_hash,json=clientTlsCertHash,proto3" json:"client_tls_cert_hash,omitempty"`
+}
+
+func (m *AuthInfo) Reset()                    { *m = AuthInfo{} }
+func (m *AuthInfo) String() string            { return proto.CompactTextString(m) }
+func (*AuthInfo) ProtoMessage()               {}
+func (*AuthInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{2} }
+
+func (m *AuthInfo) GetClientIdentity() []byte {
+	if m != nil {
+		return m.ClientIdentity
+	}
+	return nil
+}
+
+func (m *AuthInfo) GetClientTlsCertHash() []byte {
+	if m != nil {
+		return m.ClientTlsCertHash
+	}
+	return nil
+}
+
+// Query asks for information in the context of a specific channel
+type Query struct {
+	Channel string `protobuf:"bytes,1,opt,name=channel" json:"channel,omitempty"`
+	// Types that are valid to be assigned to Query:
+	//	*Query_ConfigQuery
+	//	*Query_PeerQuery
+	//	*Query_CcQuery
+	Query isQuery_Query `protobuf_oneof:"query"`
+}
+
+func (m *Query) Reset()                    { *m = Query{} }
+func (m *Query) String() string            { return proto.CompactTextString(m) }
+func (*Query) ProtoMessage()               {}
+func (*Query) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{3} }
+
+type isQuery_Query interface {
+	isQuery_Query()
+}
+
+type Query_ConfigQuery struct {
+	ConfigQuery *ConfigQuery `protobuf:"bytes,2,opt,name=config_query,json=configQuery,oneof"`
+}
+type Query_PeerQuery struct {
+	PeerQuery *PeerMembershipQuery `protobuf:"bytes,3,opt,name=peer_query,json=peerQuery,oneof"`
+}
+type Query_CcQuery struct {
+	CcQuery *ChaincodeQuery `protobuf:"bytes,4,opt,name=cc_query,json=ccQuery,oneof"`
+}
+
+func (*Query_ConfigQuery) isQuery_Query() {}
+func (*Query_PeerQuery) isQuery_Query()   {}
+func (*Query_CcQuery) isQuery_Query()     {}
+
+func (m *Query) GetQuery() isQuery_Query {
+	if m != nil {
+		return m.Query
+	}
+	return nil
+}
+
+func (m *Query) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+func (m *Query) GetConfigQuery() *ConfigQuery {
+	if x, ok := m.GetQuery().(*Query_ConfigQuery); ok {
+		return x.ConfigQuery
+	}
+	return nil
+}
+
+func (m *Query) GetPeerQuery() *PeerMembershipQuery {
+	if x, ok := m.GetQuery().(*Query_PeerQuery); ok {
+		return x.PeerQuery
+	}
+	return nil
+}
+
+func (m *Query) GetCcQuery() *ChaincodeQuery {
+	if x, ok := m.GetQuery().(*Query_CcQuery); ok {
+		return x.CcQuery
+	}
+	return nil
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*Query) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _Query_OneofMarshaler, _Query_OneofUnmarshaler, _Query_OneofSizer, []interface{}{
+		(*Query_ConfigQuery)(nil),
+		(*Query_PeerQuery)(nil),
+		(*Query_CcQuery)(nil),
+	}
+}
+
+func _Query_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*Query)
+	// query
+	switch x := m.Query.(type) {
+	case *Query_ConfigQuery:
+		b.EncodeVarint(2<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.ConfigQuery); err != nil {
+			return err
+		}
+	case *Query_PeerQuery:
+		b.EncodeVarint(3<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.PeerQuery); err != nil {
+			return err
+		}
+	case *Query_CcQuery:
+		b.EncodeVarint(4<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.CcQuery); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		return fmt.Errorf("Query.Query has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _Query_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*Query)
+	switch tag {
+	case 2: // query.config_query
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(ConfigQuery)
+		err := b.DecodeMessage(msg)
+		m.Query = &Query_ConfigQuery{msg}
+		return true, err
+	case 3: // query.peer_query
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(PeerMembershipQuery)
+		err := b.DecodeMessage(msg)
+		m.Query = &Query_PeerQuery{msg}
+		return true, err
+	case 4: // query.cc_query
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(ChaincodeQuery)
+		err := b.DecodeMessage(msg)
+		m.Query = &Query_CcQuery{msg}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _Query_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*Query)
+	// query
+	switch x := m.Query.(type) {
+	case *Query_ConfigQuery:
+		s := proto.Size(x.ConfigQuery)
+		n += proto.SizeVarint(2<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Query_PeerQuery:
+		s := proto.Size(x.PeerQuery)
+		n += proto.SizeVarint(3<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *Query_CcQuery:
+		s := proto.Size(x.CcQuery)
+		n += proto.SizeVarint(4<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+// Response contains a list of QueryResults.
+// Each QueryResult is at the same index as the Query in the Request
+type Response struct {
+	// The results are returned in the same order of the queries
+	Results []*QueryResult `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
+}
+
+func (m *Response) Reset()                    { *m = Response{} }
+func (m *Response) String() string            { return proto.CompactTextString(m) }
+func (*Response) ProtoMessage()               {}
+func (*Response) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
+
+func (m *Response) GetResults() []*QueryResult {
+	if m != nil {
+		return m.Results
+	}
+	return nil
+}
+
+// QueryResult contains a result for a given Query.
+// The corresponding Query can be inferred by the index of the QueryResult from
+// its enclosing Response message.
+// QueryResults are ordered in the same order as the Queries are ordered in their enclosing Request.
+type QueryResult struct {
+	// Types that are valid to be assigned to Result:
+	//	*QueryResult_Error
+	//	*QueryResult_ConfigResult
+	//	*QueryResult_CcQueryRes
+	//	*QueryResult_Members
+	Result isQueryResult_Result `protobuf_oneof:"result"`
+}
+
+func (m *QueryResult) Reset()                    { *m = QueryResult{} }
+func (m *QueryResult) String() string            { return proto.CompactTextString(m) }
+func (*QueryResult) ProtoMessage()               {}
+func (*QueryResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{5} }
+
+type isQueryResult_Result interface {
+	isQueryResult_Result()
+}
+
+type QueryResult_Error struct {
+	Error *Error `protobuf:"bytes,1,opt,name=error,oneof"`
+}
+type QueryResult_ConfigResult struct {
+	ConfigResult *ConfigResult `protobuf:"bytes,2,opt,name=config_result,json=configResult,oneof"`
+}
+type QueryResult_CcQueryRes struct {
+	CcQueryRes *ChaincodeQueryResult `protobuf:"bytes,3,opt,name=cc_query_res,json=ccQueryRes,oneof"`
+}
+type QueryResult_Members struct {
+	Members *PeerMembershipResult `protobuf:"bytes,4,opt,name=members,oneof"`
+}
+
+func (*QueryResult_Error) isQueryResult_Result()        {}
+func (*QueryResult_ConfigResult) isQueryResult_Result() {}
+func (*QueryResult_CcQueryRes) isQueryResult_Result()   {}
+func (*QueryResult_Members) isQueryResult_Result()      {}
+
+func (m *QueryResult) GetResult() isQueryResult_Result {
+	if m != nil {
+		return m.Result
+	}
+	return nil
+}
+
+func (m *QueryResult) GetError() *Error {
+	if x, ok := m.GetResult().(*QueryResult_Error); ok {
+		return x.Error
+	}
+	return nil
+}
+
+func (m *QueryResult) GetConfigResult() *ConfigResult {
+	if x, ok := m.GetResult().(*QueryResult_ConfigResult); ok {
+		return x.ConfigResult
+	}
+	return nil
+}
+
+func (m *QueryResult) GetCcQueryRes() *ChaincodeQueryResult {
+	if x, ok := m.GetResult().(*QueryResult_CcQueryRes); ok {
+		return x.CcQueryRes
+	}
+	return nil
+}
+
+func (m *QueryResult) GetMembers() *PeerMembershipResult {
+	if x, ok := m.GetResult().(*QueryResult_Members); ok {
+		return x.Members
+	}
+	return nil
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*QueryResult) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _QueryResult_OneofMarshaler, _QueryResult_OneofUnmarshaler, _QueryResult_OneofSizer, []interface{}{
+		(*QueryResult_Error)(nil),
+		(*QueryResult_ConfigResult)(nil),
+		(*QueryResult_CcQueryRes)(nil),
+		(*QueryResult_Members)(nil),
+	}
+}
+
+func _QueryResult_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*QueryResult)
+	// result
+	switch x := m.Result.(type) {
+	case *QueryResult_Error:
+		b.EncodeVarint(1<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Error); err != nil {
+			return err
+		}
+	case *QueryResult_ConfigResult:
+		b.EncodeVarint(2<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.ConfigResult); err != nil {
+			return err
+		}
+	case *QueryResult_CcQueryRes:
+		b.EncodeVarint(3<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.CcQueryRes); err != nil {
+			return err
+		}
+	case *QueryResult_Members:
+		b.EncodeVarint(4<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.Members); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		return fmt.Errorf("QueryResult.Result has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _QueryResult_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*QueryResult)
+	switch tag {
+	case 1: // result.error
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(Error)
+		err := b.DecodeMessage(msg)
+		m.Result = &QueryResult_Error{msg}
+		return true, err
+	case 2: // result.config_result
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(ConfigResult)
+		err := b.DecodeMessage(msg)
+		m.Result = &QueryResult_ConfigResult{msg}
+		return true, err
+	case 3: // result.cc_query_res
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(ChaincodeQueryResult)
+		err := b.DecodeMessage(msg)
+		m.Result = &QueryResult_CcQueryRes{msg}
+		return true, err
+	case 4: // result.members
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(PeerMembershipResult)
+		err := b.DecodeMessage(msg)
+		m.Result = &QueryResult_Members{msg}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _QueryResult_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*QueryResult)
+	// result
+	switch x := m.Result.(type) {
+	case *QueryResult_Error:
+		s := proto.Size(x.Error)
+		n += proto.SizeVarint(1<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *QueryResult_ConfigResult:
+		s := proto.Size(x.ConfigResult)
+		n += proto.SizeVarint(2<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *QueryResult_CcQueryRes:
+		s := proto.Size(x.CcQueryRes)
+		n += proto.SizeVarint(3<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *QueryResult_Members:
+		s := proto.Size(x.Members)
+		n += proto.SizeVarint(4<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+// ConfigQuery requests a ConfigResult
+type ConfigQuery struct {
+}
+
+func (m *ConfigQuery) Reset()                    { *m = ConfigQuery{} }
+func (m *ConfigQuery) String() string            { return proto.CompactTextString(m) }
+func (*ConfigQuery) ProtoMessage()               {}
+func (*ConfigQuery) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+type ConfigResult struct {
+	// msps is a map from MSP_ID to FabricMSPConfig
+	Msps map[string]*msp.FabricMSPConfig `protobuf:"bytes,1,rep,name=msps" json:"msps,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// orderers is the list of the ordering service endpoints
+	Orderers []*Endpoint `protobuf:"bytes,2,rep,name=orderers" json:"orderers,omitempty"`
+}
+
+func (m *ConfigResult) Reset()                    { *m = ConfigResult{} }
+func (m *ConfigResult) String() string            { return proto.CompactTextString(m) }
+func (*ConfigResult) ProtoMessage()               {}
+func (*ConfigResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *ConfigResult) GetMsps() map[string]*msp.FabricMSPConfig {
+	if m != nil {
+		return m.Msps
+	}
+	return nil
+}
+
+func (m *ConfigResult) GetOrderers() []*Endpoint {
+	if m != nil {
+		return m.Orderers
+	}
+	return nil
+}
+
+// PeerMembershipQuery requests PeerMembershipResult
+type PeerMembershipQuery struct {
+}
+
+func (m *PeerMembershipQuery) Reset()                    { *m = PeerMembershipQuery{} }
+func (m *PeerMembershipQuery) String() string            { return proto.CompactTextString(m) }
+func (*PeerMembershipQuery) ProtoMessage()               {}
+func (*PeerMembershipQuery) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+// PeerMembershipResult contains peers mapped by their organizations (MSP_ID)
+type PeerMembershipResult struct {
+	PeersByOrg map[string]*Peers `protobuf:"bytes,1,rep,name=peers_by_org,json=peersByOrg" json:"peers_by_org,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (m *PeerMembershipResult) Reset()                    { *m = PeerMembershipResult{} }
+func (m *PeerMembershipResult) String() string            { return proto.CompactTextString(m) }
+func (*PeerMembershipResult) ProtoMessage()               {}
+func (*PeerMembershipResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+
+func (m *PeerMembershipResult) GetPeersByOrg() map[string]*Peers {
+	if m != nil {
+		return m.PeersByOrg
+	}
+	return nil
+}
+
+// ChaincodeQuery requests ChaincodeQueryResults for a given
+// list of chaincode invocations.
+// Each invocation is a separate one, and the endorsement policy
+// is evaluated independently for each given interest.
+type ChaincodeQuery struct {
+	Interests []*ChaincodeInterest `protobuf:"bytes,1,rep,name=interests" json:"interests,omitempty"`
+}
+
+func (m *ChaincodeQuery) Reset()                    { *m = ChaincodeQuery{} }
+func (m *ChaincodeQuery) String() string            { return proto.CompactTextString(m) }
+func (*ChaincodeQuery) ProtoMessage()               {}
+func (*ChaincodeQuery) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *ChaincodeQuery) GetInterests() []*ChaincodeInterest {
+	if m != nil {
+		return m.Interests
+	}
+	return nil
+}
+
+// ChaincodeInterest defines an interest about an endorsement
+// for a specific single chaincode invocation.
+// Multiple chaincodes indicate chaincode to chaincode invocations.
+type ChaincodeInterest struct {
+	Chaincodes []*ChaincodeCall `protobuf:"bytes,1,rep,name=chaincodes" json:"chaincodes,omitempty"`
+}
+
+func (m *ChaincodeInterest) Reset()                    { *m = ChaincodeInterest{} }
+func (m *ChaincodeInterest) String() string            { return proto.CompactTextString(m) }
+func (*ChaincodeInterest) ProtoMessage()               {}
+func (*ChaincodeInterest) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *ChaincodeInterest) GetChaincodes() []*ChaincodeCall {
+	if m != nil {
+		return m.Chaincodes
+	}
+	return nil
+}
+
+// ChaincodeCall defines a call to a chaincode.
+type ChaincodeCall struct {
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *ChaincodeCall) Reset()                    { *m = ChaincodeCall{} }
+func (m *ChaincodeCall) String() string            { return proto.CompactTextString(m) }
+func (*ChaincodeCall) ProtoMessage()               {}
+func (*ChaincodeCall) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *ChaincodeCall) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+// ChaincodeQueryResult contains EndorsementDescriptors for
+// chaincodes
+type ChaincodeQueryResult struct {
+	Content []*EndorsementDescriptor `protobuf:"bytes,1,rep,name=content" json:"content,omitempty"`
+}
+
+func (m *ChaincodeQueryResult) Reset()                    { *m = ChaincodeQueryResult{} }
+func (m *ChaincodeQueryResult) String() string            { return proto.CompactTextString(m) }
+func (*ChaincodeQueryResult) ProtoMessage()               {}
+func (*ChaincodeQueryResult) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
+
+func (m *ChaincodeQueryResult) GetContent() []*EndorsementDescriptor {
+	if m != nil {
+		return m.Content
+	}
+	return nil
+}
+
+// EndorsementDescriptor contains information about which peers can be used
+// to request endorsement from, such that the endorsement policy would be fulfilled.
+// Here is how to compute a set of peers to ask an endorsement from, given an EndorsementDescriptor:
+// Let e: G --> P be the endorsers_by_groups field that maps a group to a set of peers.
+// Note that applying e on a group g yields a set of peers.
+//  1. Select a layout l: G --> N out of the layouts given.
+//     l is the quantities_by_group field of a Layout, and it maps a group to an integer.
+//  2. R = {}  (an empty set of peers)
+//  3. For each group g in the layout l, compute n = l(g)
+//     3.1) Select a subset of n peers from e(g) and add them to R
+//  4. The set of peers R is the set of peers the client needs to request endorsements from
+type EndorsementDescriptor struct {
+	Chaincode string `protobuf:"bytes,1,opt,name=chaincode" json:"chaincode,omitempty"`
+	// Specifies the endorsers, separated to groups.
+	EndorsersByGroups map[string]*Peers `protobuf:"bytes,2,rep,name=endorsers_by_groups,json=endorsersByGroups" json:"endorsers_by_groups,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Specifies options of fulfilling the endorsement policy.
+	// Each option lists the group names, and the amount of signatures needed
+	// from each group.
+	Layouts []*Layout `protobuf:"bytes,3,rep,name=layouts" json:"layouts,omitempty"`
+}
+
+func (m *EndorsementDescriptor) Reset()                    { *m = EndorsementDescriptor{} }
+func (m *EndorsementDescriptor) String() string            { return proto.CompactTextString(m) }
+func (*EndorsementDescriptor) ProtoMessage()               {}
+func (*EndorsementDescriptor) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *EndorsementDescriptor) GetChaincode() string {
+	if m != nil {
+		return m.Chaincode
+	}
+	return ""
+}
+
+func (m *EndorsementDescriptor) GetEndorsersByGroups() map[string]*Peers {
+	if m != nil {
+		return m.EndorsersByGroups
+	}
+	return nil
+}
+
+func (m *EndorsementDescriptor) GetLayouts() []*Layout {
+	if m != nil {
+		return m.Layouts
+	}
+	return nil
+}
+
+// Layout contains a mapping from a group name to number of peers
+// that are needed for fulfilling an endorsement policy
+type Layout struct {
+	// Specifies how many non repeated signatures of each group
+	// are needed for endorsement
+	QuantitiesByGroup map[string]uint32 `protobuf:"bytes,1,rep,name=quantities_by_group,json=quantitiesByGroup" json:"quantities_by_group,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+}
+
+func (m *Layout) Reset()                    { *m = Layout{} }
+func (m *Layout) String() string            { return proto.CompactTextString(m) }
+func (*Layout) ProtoMessage()               {}
+func (*Layout) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *Layout) GetQuantitiesByGroup() map[string]uint32 {
+	if m != nil {
+		return m.QuantitiesByGroup
+	}
+	return nil
+}
+
+// Peers contains a list of Peer(s)
+type Peers struct {
+	Peers []*Peer `protobuf:"bytes,1,rep,name=peers" json:"peers,omitempty"`
+}
+
+func (m *Peers) Reset()                    { *m = Peers{} }
+func (m *Peers) String() string            { return proto.CompactTextString(m) }
+func (*Peers) ProtoMessage()               {}
+func (*Peers) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *Peers) GetPeers() []*Peer {
+	if m != nil {
+		return m.Peers
+	}
+	return nil
+}
+
+// Peer contains information about the peer such as its channel specific
+// state, and membership information.
+type Peer struct {
+	// This is the endpoint the peer advertises to the other peers of the channel
+	Endpoint string `protobuf:"bytes,1,opt,name=endpoint" json:"endpoint,omitempty"`
+	// This is the msp.SerializedIdentity of the peer, represented in bytes.
+	Identity []byte `protobuf:"bytes,2,opt,name=identity,proto3" json:"identity,omitempty"`
+	// This is the height of the ledger of the channel as advertised by the peer
+	LedgerHeight uint64 `protobuf:"varint,3,opt,name=ledger_height,json=ledgerHeight" json:"ledger_height,omitempty"`
+}
+
+func (m *Peer) Reset()                    { *m = Peer{} }
+func (m *Peer) String() string            { return proto.CompactTextString(m) }
+func (*Peer) ProtoMessage()               {}
+func (*Peer) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+func (m *Peer) GetEndpoint() string {
+	if m != nil {
+		return m.Endpoint
+	}
+	return ""
+}
+
+func (m *Peer) GetIdentity() []byte {
+	if m != nil {
+		return m.Identity
+	}
+	return nil
+}
+
+func (m *Peer) GetLedgerHeight() uint64 {
+	if m != nil {
+		return m.LedgerHeight
+	}
+	return 0
+}
+
+// Error denotes that something went wrong and contains the error message
+type Error struct {
+	Content string `protobuf:"bytes,1,opt,name=content" json:"content,omitempty"`
+}
+
+func (m *Error) Reset()                    { *m = Error{} }
+func (m *Error) String() string            { return proto.CompactTextString(m) }
+func (*Error) ProtoMessage()               {}
+func (*Error) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+func (m *Error) GetContent() string {
+	if m != nil {
+		return m.Content
+	}
+	return ""
+}
+
+// Endpoint is a combination of a host and a port
+type Endpoint struct {
+	Host string `protobuf:"bytes,1,opt,name=host" json:"host,omitempty"`
+	Port uint32 `protobuf:"varint,2,opt,name=port" json:"port,omitempty"`
+}
+
+func (m *Endpoint) Reset()                    { *m = Endpoint{} }
+func (m *Endpoint) String() string            { return proto.CompactTextString(m) }
+func (*Endpoint) ProtoMessage()               {}
+func (*Endpoint) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+func (m *Endpoint) GetHost() string {
+	if m != nil {
+		return m.Host
+	}
+	return ""
+}
+
+func (m *Endpoint) GetPort() uint32 {
+	if m != nil {
+		return m.Port
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*SignedRequest)(nil), "discovery.SignedRequest")
+	proto.RegisterType((*Request)(nil), "discovery.Request")
+	proto.RegisterType((*AuthInfo)(nil), "discovery.AuthInfo")
+	proto.RegisterType((*Query)(nil), "discovery.Query")
+	proto.RegisterType((*Response)(nil), "discovery.Response")
+	proto.RegisterType((*QueryResult)(nil), "discovery.QueryResult")
+	proto.RegisterType((*ConfigQuery)(nil), "discovery.ConfigQuery")
+	proto.RegisterType((*ConfigResult)(nil), "discovery.ConfigResult")
+	proto.RegisterType((*PeerMembershipQuery)(nil), "discovery.PeerMembershipQuery")
+	proto.RegisterType((*PeerMembershipResult)(nil), "discovery.PeerMembershipResult")
+	proto.RegisterType((*ChaincodeQuery)(nil), "discovery.ChaincodeQuery")
+	proto.RegisterType((*ChaincodeInterest)(nil), "discovery.ChaincodeInterest")
+	proto.RegisterType((*ChaincodeCall)(nil), "discovery.ChaincodeCall")
+	proto.RegisterType((*ChaincodeQueryResult)(nil), "discovery.ChaincodeQueryResult")
+	proto.RegisterType((*EndorsementDescriptor)(nil), "discovery.EndorsementDescriptor")
+	proto.RegisterType((*Layout)(nil), "discovery.Layout")
+	proto.RegisterType((*Peers)(nil), "discovery.Peers")
+	proto.RegisterType((*Peer)(nil), "discovery.Peer")
+	proto.RegisterType((*Error)(nil), "discovery.Error")
+	proto.RegisterType((*Endpoint)(nil), "discovery.Endpoint")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for Discovery service
+
+type DiscoveryClient interface {
+	// Discover receives a signed request, and returns a response.
+	Discover(ctx context.Context, in *SignedRequest, opts ...grpc.CallOption) (*Response, error)
+}
+
+type discoveryClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewDiscoveryClient(cc *grpc.ClientConn) DiscoveryClient {
+	return &discoveryClient{cc}
+}
+
+func (c *discoveryClient) Discover(ctx context.Context, in *SignedRequest, opts ...grpc.CallOption) (*Response, error) {
+	out := new(Response)
+	err := grpc.Invoke(ctx, "/discovery.Discovery/Discover", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Discovery service
+
+type DiscoveryServer interface {
+	// Discover receives a signed request, and returns a response.
+	Discover(context.Context, *SignedRequest) (*Response, error)
+}
+
+func RegisterDiscoveryServer(s *grpc.Server, srv DiscoveryServer) {
+	s.RegisterService(&_Discovery_serviceDesc, srv)
+}
+
+func _Discovery_Discover_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SignedRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DiscoveryServer).Discover(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/discovery.Discovery/Discover",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DiscoveryServer).Discover(ctx, req.(*SignedRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Discovery_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "discovery.Discovery",
+	HandlerType: (*DiscoveryServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Discover",
+			Handler:    _Discovery_Discover_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "discovery/protocol.proto",
+}
+
+func init() { proto.RegisterFile("discovery/protocol.proto", fileDescriptor0) }
+
+var fileDescriptor0 = []byte{
+	// 1020 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x56, 0xcd, 0x6e, 0xdb, 0x46,
+	0x10, 0xb6, 0x6c, 0xc9, 0x92, 0x46, 0x92, 0x7f, 0xd6, 0x8a, 0xab, 0x0a, 0x41, 0xeb, 0x30, 0x68,
+	0x63, 0xa4, 0x00, 0x55, 0xb8, 0x68, 0x9b, 0xda, 0x45, 0x8b, 0xfa, 0x27, 0xb1, 0x81, 0x18, 0x89,
+	0x99, 0xa2, 0x68, 0x7b, 0x11, 0xa8, 0xd5, 0x98, 0x24, 0x4a, 0x71, 0xe9, 0xdd, 0x65, 0x00, 0x9e,
+	0xfb, 0x28, 0xbd, 0xf4, 0x19, 0x7a, 0xef, 0x7b, 0xf4, 0x51, 0x0a, 0xee, 0x0f, 0x45, 0xc9, 0x0a,
+	0x72, 0xc8, 0x6d, 0x67, 0xe6, 0x9b, 0x6f, 0xbf, 0x99, 0x1d, 0xee, 0x12, 0x06, 0xd3, 0x48, 0x50,
+	0xf6, 0x16, 0x79, 0x3e, 0x4a, 0x39, 0x93, 0x8c, 0xb2, 0xd8, 0x55, 0x0b, 0xd2, 0x2e, 0x23, 0xc3,
+	0xfe, 0x4c, 0xa4, 0xa3, 0x99, 0x48, 0xc7, 0x94, 0x25, 0xb7, 0x51, 0xa0, 0x01, 0xce, 0x0b, 0xe8,
+	0xbd, 0x89, 0x82, 0x04, 0xa7, 0x1e, 0xde, 0x65, 0x28, 0x24, 0x19, 0x40, 0x33, 0xf5, 0xf3, 0x98,
+	0xf9, 0xd3, 0x41, 0xed, 0xa0, 0x76, 0xd8, 0xf5, 0xac, 0x49, 0x1e, 0x42, 0x5b, 0x44, 0x41, 0xe2,
+	0xcb, 0x8c, 0xe3, 0x60, 0x5d, 0xc5, 0xe6, 0x0e, 0x87, 0x43, 0xd3, 0x52, 0x9c, 0xc0, 0x96, 0x9f,
+	0xc9, 0x10, 0x13, 0x19, 0x51, 0x5f, 0x46, 0x2c, 0x51, 0x4c, 0x9d, 0xa3, 0x3d, 0xb7, 0x54, 0xe3,
+	0xfe, 0x94, 0xc9, 0xf0, 0x2a, 0xb9, 0x65, 0xde, 0x12, 0x94, 0x3c, 0x85, 0xe6, 0x5d, 0x86, 0x3c,
+	0x42, 0x31, 0x58, 0x3f, 0xd8, 0x38, 0xec, 0x1c, 0xed, 0x54, 0xb2, 0x6e, 0x32, 0xe4, 0xb9, 0x67,
+	0x01, 0xce, 0x14, 0x5a, 0x96, 0x87, 0x3c, 0x81, 0x6d, 0x1a, 0x47, 0x98, 0xc8, 0x71, 0x34, 0x2d,
+	0xe8, 0x64, 0x6e, 0xf4, 0x6f, 0x69, 0xf7, 0x95, 0xf1, 0x92, 0x11, 0xf4, 0x0d, 0x50, 0xc6, 0x62,
+	0x4c, 0x91, 0xcb, 0x71, 0xe8, 0x8b, 0xd0, 0x54, 0xb4, 0xab, 0x63, 0x3f, 0xc7, 0xe2, 0x0c, 0xb9,
+	0xbc, 0xf4, 0x45, 0xe8, 0xfc, 0x57, 0x83, 0x86, 0xda, 0xb8, 0xe8, 0x0d, 0x0d, 0xfd, 0x24, 0xc1,
+	0x58, 0x71, 0xb7, 0x3d, 0x6b, 0x92, 0x13, 0xe8, 0xea, 0xb6, 0x8e, 0x0b, 0x6d, 0xb9, 0x22, 0xeb,
+	0x1c, 0xed, 0x57, 0xa4, 0x9f, 0xa9, 0xb0, 0xe2, 0xb9, 0x5c, 0xf3, 0x3a, 0x74, 0x6e, 0x92, 0x1f,
+	0x01, 0x52, 0x44, 0x6e, 0x52, 0x37, 0x54, 0xea, 0x27, 0x95, 0xd4, 0xd7, 0x88, 0xfc, 0x1a, 0x67,
+	0x13, 0xe4, 0x22, 0x8c, 0x52, 0x4b, 0xd1, 0x2e, 0x72, 0x34, 0xc1, 0x37, 0xd0, 0xa2, 0xd4, 0xa4,
+	0xd7, 0x55, 0xfa, 0xc7, 0xd5, 0x9d, 0x43, 0x3f, 0x4a, 0x28, 0x9b, 0xa2, 0xcd, 0x6c, 0x52, 0xaa,
+	0x96, 0xa7, 0x4d, 0x68, 0xa8, 0x24, 0xe7, 0x7b, 0x68, 0x79, 0x28, 0x52, 0x96, 0x08, 0x24, 0x5f,
+	0x42, 0x93, 0xa3, 0xc8, 0x62, 0x29, 0x06, 0xb5, 0x83, 0x8d, 0xa5, 0x2a, 0xf4, 0x01, 0xa8, 0xb0,
+	0x67, 0x61, 0xce, 0x9f, 0xeb, 0xd0, 0xa9, 0x04, 0xc8, 0x21, 0x34, 0x90, 0x73, 0xc6, 0xcd, 0xb1,
+	0x57, 0x0f, 0xf0, 0xa2, 0xf0, 0x5f, 0xae, 0x79, 0x1a, 0x40, 0x7e, 0x80, 0x9e, 0x69, 0x9b, 0xe6,
+	0x32, 0x7d, 0xfb, 0xe8, 0x5e, 0xdf, 0x34, 0xf3, 0xe5, 0x9a, 0xd7, 0xa5, 0x15, 0x9b, 0x9c, 0x41,
+	0xd7, 0x16, 0x5e, 0x30, 0x98, 0xde, 0x7d, 0xfa, 0xce, 0xe2, 0x4b, 0x1a, 0x30, 0x2d, 0xf0, 0x50,
+	0x90, 0x13, 0x68, 0xce, 0x74, 0x77, 0x07, 0xf5, 0x7b, 0xf9, 0x8b, 0xbd, 0x2f, 0xf3, 0x6d, 0xc6,
+	0x69, 0x0b, 0x36, 0xb5, 0x74, 0xa7, 0x07, 0x9d, 0xca, 0x19, 0x3b, 0xff, 0xd6, 0xa0, 0x5b, 0xd5,
+	0x4e, 0xbe, 0x86, 0xfa, 0x4c, 0xa4, 0xb6, 0xa9, 0x8f, 0xde, 0x51, 0xa2, 0x7b, 0x2d, 0x52, 0x71,
+	0x91, 0x48, 0x9e, 0x7b, 0x0a, 0x4e, 0x46, 0xd0, 0x62, 0x7c, 0x8a, 0x1c, 0xb9, 0xfd, 0x20, 0xaa,
+	0x9f, 0xd1, 0x45, 0x32, 0x4d, 0x59, 0x94, 0x48, 0xaf, 0x04, 0x0d, 0xaf, 0xa1, 0x5d, 0x72, 0x90,
+	0x1d, 0xd8, 0xf8, 0x03, 0x73, 0x33, 0xad, 0xc5, 0x92, 0x3c, 0x85, 0xc6, 0x5b, 0x3f, 0xce, 0xd0,
+	0xb4, 0xba, 0xef, 0xce, 0x44, 0xea, 0x3e, 0xf7, 0x27, 0x3c, 0xa2, 0xd7, 0x6f, 0x5e, 0x1b, 0x29,
+	0x1a, 0x72, 0xbc, 0xfe, 0xac, 0xe6, 0x3c, 0x80, 0xbd, 0x15, 0xf3, 0xe7, 0xfc, 0x53, 0x83, 0xfe,
+	0xaa, 0xde, 0x90, 0x1b, 0xe8, 0x16, 0x83, 0x29, 0xc6, 0x93, 0x7c, 0xcc, 0x78, 0x60, 0xca, 0x1d,
+	0xbd, 0xa7, 0xa5, 0xca, 0x29, 0x4e, 0xf3, 0x57, 0x3c, 0xd0, 0xc5, 0x43, 0x5a, 0x3a, 0x86, 0xaf,
+	0x60, 0x7b, 0x29, 0xbc, 0xa2, 0xae, 0xcf, 0x17, 0xeb, 0xda, 0x59, 0xda, 0x50, 0x54, 0x6b, 0x7a,
+	0x09, 0x5b, 0x8b, 0x73, 0x41, 0x8e, 0xa1, 0x1d, 0x25, 0x12, 0x39, 0x8a, 0x72, 0xec, 0x1f, 0xae,
+	0x9a, 0xa2, 0x2b, 0x03, 0xf2, 0xe6, 0x70, 0xe7, 0x1a, 0x76, 0xef, 0xc5, 0xc9, 0x33, 0x00, 0x6a,
+	0x9d, 0x96, 0x71, 0xb0, 0x8a, 0xf1, 0xcc, 0x8f, 0x63, 0xaf, 0x82, 0x75, 0x1e, 0x43, 0x6f, 0x21,
+	0x48, 0x08, 0xd4, 0x13, 0x7f, 0x86, 0xa6, 0x58, 0xb5, 0x76, 0x3c, 0xe8, 0xaf, 0x9a, 0x6c, 0x72,
+	0x0c, 0x4d, 0xca, 0x12, 0x89, 0x89, 0x34, 0x7b, 0x1e, 0x2c, 0x0e, 0x0b, 0xe3, 0x02, 0x67, 0x98,
+	0xc8, 0x73, 0x14, 0x94, 0x47, 0xa9, 0x64, 0xdc, 0xb3, 0x09, 0xce, 0x5f, 0xeb, 0xf0, 0x60, 0x25,
+	0xa4, 0xb8, 0xf9, 0x4b, 0x81, 0x46, 0xc6, 0xdc, 0x41, 0x02, 0xd8, 0x43, 0x9d, 0xa6, 0x4f, 0x3d,
+	0xe0, 0x2c, 0x4b, 0xed, 0xb0, 0x7e, 0xfb, 0xbe, 0xfd, 0xad, 0xb7, 0x38, 0xde, 0x17, 0x2a, 0x53,
+	0x0f, 0xc0, 0x2e, 0x2e, 0xfb, 0xc9, 0x17, 0xd0, 0x8c, 0xfd, 0x9c, 0x65, 0xb2, 0xf8, 0xd0, 0x0b,
+	0xf2, 0xdd, 0x0a, 0xf9, 0x4b, 0x15, 0xf1, 0x2c, 0x62, 0xf8, 0x0b, 0xec, 0xaf, 0x66, 0xfe, 0xc0,
+	0xd9, 0xf9, 0xbb, 0x06, 0x9b, 0x7a, 0x2f, 0xf2, 0x2b, 0xec, 0xdd, 0x65, 0x7e, 0xf1, 0xaa, 0x44,
+	0x38, 0xaf, 0xdc, 0x34, 0xfe, 0xf0, 0x9e, 0x36, 0xf7, 0xa6, 0x04, 0x1b, 0x41, 0xa6, 0xd2, 0xbb,
+	0x65, 0xff, 0xf0, 0x1c, 0xf6, 0x57, 0x83, 0x57, 0x88, 0xef, 0x57, 0xc5, 0xf7, 0xaa, 0x52, 0x5d,
+	0x68, 0x28, 0xf9, 0xe4, 0x33, 0x68, 0xa8, 0xcf, 0xc9, 0x48, 0xdb, 0x5e, 0xaa, 0xcf, 0xd3, 0x51,
+	0x87, 0x42, 0xbd, 0x30, 0xc9, 0x10, 0x5a, 0x68, 0xee, 0x15, 0xb3, 0x51, 0x69, 0x17, 0xb1, 0xf2,
+	0x7d, 0xd5, 0x2f, 0x66, 0x69, 0x93, 0xc7, 0xd0, 0x8b, 0x71, 0x1a, 0x20, 0x1f, 0x87, 0x18, 0x05,
+	0xa1, 0x54, 0xd7, 0x71, 0xdd, 0xeb, 0x6a, 0xe7, 0xa5, 0xf2, 0x39, 0x8f, 0xa0, 0xa1, 0x1e, 0x01,
+	0xf5, 0x98, 0x96, 0xa3, 0xaa, 0x1f, 0x53, 0x33, 0x88, 0x47, 0xd0, 0xb2, 0xf7, 0x5a, 0x31, 0xfc,
+	0x21, 0x13, 0x16, 0xa2, 0xd6, 0x85, 0x2f, 0x65, 0x5c, 0x9a, 0x82, 0xd5, 0xfa, 0xe8, 0x39, 0xb4,
+	0xcf, 0x6d, 0x51, 0xe4, 0x3b, 0x68, 0x59, 0x83, 0x54, 0x3f, 0xba, 0x85, 0x3f, 0x9d, 0x61, 0xf5,
+	0x1e, 0xb5, 0xaf, 0xdf, 0xe9, 0x6f, 0xf0, 0x84, 0xf1, 0xc0, 0x0d, 0xf3, 0x14, 0xb9, 0xd6, 0xed,
+	0xde, 0xaa, 0xcb, 0x51, 0xff, 0x2f, 0x89, 0x79, 0xce, 0xef, 0x6e, 0x10, 0xc9, 0x30, 0x9b, 0xb8,
+	0x94, 0xcd, 0x46, 0x15, 0xfc, 0x48, 0xe3, 0xf5, 0x9f, 0x98, 0x18, 0x95, 0xf8, 0xc9, 0xa6, 0xf2,
+	0x7c, 0xf5, 0xff, 0x00, 0x16, 0x5a, 0xf1, 0x52, 0xae, 0x09, 0x00, 0x00,
+}
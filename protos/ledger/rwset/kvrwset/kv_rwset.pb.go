@@ -14,6 +14,9 @@ It has these top-level messages:
 	KVWrite
 	KVReadHash
 	KVWriteHash
+	KVMetadataWrite
+	KVMetadataWriteHash
+	KVMetadataEntry
 	Version
 	RangeQueryInfo
 	QueryReads
@@ -39,9 +42,10 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 // KVRWSet encapsulates the read-write set for a chaincode that operates upon a KV or Document data model
 // This structure is used for both the public data and the private data
 type KVRWSet struct {
-	Reads            []*KVRead         `protobuf:"bytes,1,rep,name=reads" json:"reads,omitempty"`
-	RangeQueriesInfo []*RangeQueryInfo `protobuf:"bytes,2,rep,name=range_queries_info,json=rangeQueriesInfo" json:"range_queries_info,omitempty"`
-	Writes           []*KVWrite        `protobuf:"bytes,3,rep,name=writes" json:"writes,omitempty"`
+	Reads            []*KVRead          `protobuf:"bytes,1,rep,name=reads" json:"reads,omitempty"`
+	RangeQueriesInfo []*RangeQueryInfo  `protobuf:"bytes,2,rep,name=range_queries_info,json=rangeQueriesInfo" json:"range_queries_info,omitempty"`
+	Writes           []*KVWrite         `protobuf:"bytes,3,rep,name=writes" json:"writes,omitempty"`
+	MetadataWrites   []*KVMetadataWrite `protobuf:"bytes,4,rep,name=metadata_writes,json=metadataWrites" json:"metadata_writes,omitempty"`
 }
 
 func (m *KVRWSet) Reset()                    { *m = KVRWSet{} }
@@ -70,10 +74,18 @@ func (m *KVRWSet) GetWrites() []*KVWrite {
 	return nil
 }
 
+func (m *KVRWSet) GetMetadataWrites() []*KVMetadataWrite {
+	if m != nil {
+		return m.MetadataWrites
+	}
+	return nil
+}
+
 // HashedRWSet encapsulates hashed representation of a private read-write set for KV or Document data model
 type HashedRWSet struct {
-	HashedReads  []*KVReadHash  `protobuf:"bytes,1,rep,name=hashed_reads,json=hashedReads" json:"hashed_reads,omitempty"`
-	HashedWrites []*KVWriteHash `protobuf:"bytes,2,rep,name=hashed_writes,json=hashedWrites" json:"hashed_writes,omitempty"`
+	HashedReads    []*KVReadHash          `protobuf:"bytes,1,rep,name=hashed_reads,json=hashedReads" json:"hashed_reads,omitempty"`
+	HashedWrites   []*KVWriteHash         `protobuf:"bytes,2,rep,name=hashed_writes,json=hashedWrites" json:"hashed_writes,omitempty"`
+	MetadataWrites []*KVMetadataWriteHash `protobuf:"bytes,3,rep,name=metadata_writes,json=metadataWrites" json:"metadata_writes,omitempty"`
 }
 
 func (m *HashedRWSet) Reset()                    { *m = HashedRWSet{} }
@@ -95,6 +107,13 @@ func (m *HashedRWSet) GetHashedWrites() []*KVWriteHash {
 	return nil
 }
 
+func (m *HashedRWSet) GetMetadataWrites() []*KVMetadataWriteHash {
+	if m != nil {
+		return m.MetadataWrites
+	}
+	return nil
+}
+
 // KVRead captures a read operation performed during transaction simulation
 // A 'nil' version indicates a non-existing key read by the transaction
 type KVRead struct {
@@ -214,6 +233,81 @@ func (m *KVWriteHash) GetValueHash() []byte {
 	return nil
 }
 
+// KVMetadataWrite captures all the entries in the metadata associated with a key
+type KVMetadataWrite struct {
+	Key     string             `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Entries []*KVMetadataEntry `protobuf:"bytes,2,rep,name=entries" json:"entries,omitempty"`
+}
+
+func (m *KVMetadataWrite) Reset()                    { *m = KVMetadataWrite{} }
+func (m *KVMetadataWrite) String() string            { return proto.CompactTextString(m) }
+func (*KVMetadataWrite) ProtoMessage()               {}
+func (*KVMetadataWrite) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+
+func (m *KVMetadataWrite) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *KVMetadataWrite) GetEntries() []*KVMetadataEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+// KVMetadataWriteHash captures all the upserts to the metadata associated with a key hash
+type KVMetadataWriteHash struct {
+	KeyHash []byte             `protobuf:"bytes,1,opt,name=key_hash,json=keyHash,proto3" json:"key_hash,omitempty"`
+	Entries []*KVMetadataEntry `protobuf:"bytes,2,rep,name=entries" json:"entries,omitempty"`
+}
+
+func (m *KVMetadataWriteHash) Reset()                    { *m = KVMetadataWriteHash{} }
+func (m *KVMetadataWriteHash) String() string            { return proto.CompactTextString(m) }
+func (*KVMetadataWriteHash) ProtoMessage()               {}
+func (*KVMetadataWriteHash) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+
+func (m *KVMetadataWriteHash) GetKeyHash() []byte {
+	if m != nil {
+		return m.KeyHash
+	}
+	return nil
+}
+
+func (m *KVMetadataWriteHash) GetEntries() []*KVMetadataEntry {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
+// KVMetadataEntry captures a 'name'ed entry in the metadata of a key/key-hash.
+type KVMetadataEntry struct {
+	Name  string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Value []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *KVMetadataEntry) Reset()                    { *m = KVMetadataEntry{} }
+func (m *KVMetadataEntry) String() string            { return proto.CompactTextString(m) }
+func (*KVMetadataEntry) ProtoMessage()               {}
+func (*KVMetadataEntry) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+
+func (m *KVMetadataEntry) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *KVMetadataEntry) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
 // Version encapsulates the version of a Key
 // A version of a committed key is maintained as the height of the transaction that committed the key.
 // The height is represenetd as a tuple <blockNum, txNum> where the txNum is the position of the transaction
@@ -226,7 +320,7 @@ type Version struct {
 func (m *Version) Reset()                    { *m = Version{} }
 func (m *Version) String() string            { return proto.CompactTextString(m) }
 func (*Version) ProtoMessage()               {}
-func (*Version) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{6} }
+func (*Version) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
 
 func (m *Version) GetBlockNum() uint64 {
 	if m != nil {
@@ -261,7 +355,7 @@ type RangeQueryInfo struct {
 func (m *RangeQueryInfo) Reset()                    { *m = RangeQueryInfo{} }
 func (m *RangeQueryInfo) String() string            { return proto.CompactTextString(m) }
 func (*RangeQueryInfo) ProtoMessage()               {}
-func (*RangeQueryInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{7} }
+func (*RangeQueryInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
 
 type isRangeQueryInfo_ReadsInfo interface {
 	isRangeQueryInfo_ReadsInfo()
@@ -401,7 +495,7 @@ type QueryReads struct {
 func (m *QueryReads) Reset()                    { *m = QueryReads{} }
 func (m *QueryReads) String() string            { return proto.CompactTextString(m) }
 func (*QueryReads) ProtoMessage()               {}
-func (*QueryReads) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+func (*QueryReads) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
 
 func (m *QueryReads) GetKvReads() []*KVRead {
 	if m != nil {
@@ -424,7 +518,7 @@ type QueryReadsMerkleSummary struct {
 func (m *QueryReadsMerkleSummary) Reset()                    { *m = QueryReadsMerkleSummary{} }
 func (m *QueryReadsMerkleSummary) String() string            { return proto.CompactTextString(m) }
 func (*QueryReadsMerkleSummary) ProtoMessage()               {}
-func (*QueryReadsMerkleSummary) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+func (*QueryReadsMerkleSummary) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
 
 func (m *QueryReadsMerkleSummary) GetMaxDegree() uint32 {
 	if m != nil {
@@ -454,6 +548,9 @@ func init() {
 	proto.RegisterType((*KVWrite)(nil), "kvrwset.KVWrite")
 	proto.RegisterType((*KVReadHash)(nil), "kvrwset.KVReadHash")
 	proto.RegisterType((*KVWriteHash)(nil), "kvrwset.KVWriteHash")
+	proto.RegisterType((*KVMetadataWrite)(nil), "kvrwset.KVMetadataWrite")
+	proto.RegisterType((*KVMetadataWriteHash)(nil), "kvrwset.KVMetadataWriteHash")
+	proto.RegisterType((*KVMetadataEntry)(nil), "kvrwset.KVMetadataEntry")
 	proto.RegisterType((*Version)(nil), "kvrwset.Version")
 	proto.RegisterType((*RangeQueryInfo)(nil), "kvrwset.RangeQueryInfo")
 	proto.RegisterType((*QueryReads)(nil), "kvrwset.QueryReads")
@@ -463,46 +560,52 @@ func init() {
 func init() { proto.RegisterFile("ledger/rwset/kvrwset/kv_rwset.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 641 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x54, 0x5d, 0x6b, 0xdb, 0x4a,
-	0x10, 0x8d, 0xec, 0xd8, 0x92, 0xc7, 0x76, 0xae, 0xef, 0x26, 0x97, 0xe8, 0x52, 0x0a, 0x46, 0xa1,
-	0x60, 0xf2, 0x20, 0x43, 0x0a, 0xa5, 0x79, 0xe8, 0x4b, 0x49, 0x4a, 0x4a, 0xda, 0x40, 0x37, 0x90,
-	0x40, 0x5f, 0xc4, 0x3a, 0x9a, 0xd8, 0x42, 0x5f, 0xe9, 0xae, 0x64, 0x5b, 0x4f, 0x6d, 0x7f, 0x4a,
-	0xff, 0x69, 0xd9, 0x59, 0x39, 0x76, 0x42, 0x1a, 0xe8, 0x93, 0xb5, 0xe7, 0xcc, 0x99, 0x39, 0x33,
-	0x9e, 0x5d, 0x38, 0x48, 0x30, 0x9c, 0xa2, 0x1c, 0xcb, 0x85, 0xc2, 0x62, 0x1c, 0xcf, 0x57, 0xbf,
-	0x01, 0x7d, 0xf8, 0x77, 0x32, 0x2f, 0x72, 0x66, 0xd7, 0xb8, 0xf7, 0xcb, 0x02, 0xfb, 0xfc, 0x8a,
-	0x5f, 0x5f, 0x62, 0xc1, 0x5e, 0x41, 0x4b, 0xa2, 0x08, 0x95, 0x6b, 0x0d, 0x9b, 0xa3, 0xee, 0xd1,
-	0x3f, 0x7e, 0x1d, 0xe4, 0x9f, 0x5f, 0x71, 0x14, 0x21, 0x37, 0x2c, 0x3b, 0x05, 0x26, 0x45, 0x36,
-	0xc5, 0xe0, 0x5b, 0x89, 0x32, 0x42, 0x15, 0x44, 0xd9, 0x6d, 0xee, 0x36, 0x48, 0xb3, 0x7f, 0xaf,
-	0xe1, 0x3a, 0xe4, 0x4b, 0x89, 0xb2, 0xfa, 0x98, 0xdd, 0xe6, 0x7c, 0x20, 0x57, 0xe7, 0x08, 0x95,
-	0x46, 0xd8, 0x08, 0xda, 0x0b, 0x19, 0x15, 0xa8, 0xdc, 0x26, 0x49, 0x07, 0x1b, 0xe5, 0xae, 0x35,
-	0xc1, 0x6b, 0xde, 0xfb, 0x61, 0x41, 0xf7, 0x4c, 0xa8, 0x19, 0x86, 0xc6, 0xe7, 0x1b, 0xe8, 0xcd,
-	0xe8, 0x18, 0x6c, 0xda, 0xdd, 0x7d, 0x64, 0x57, 0x2b, 0x78, 0xd7, 0x04, 0x72, 0x32, 0x7e, 0x0c,
-	0xfd, 0x5a, 0x57, 0x17, 0x36, 0x9e, 0xf7, 0x1e, 0x17, 0x26, 0x65, 0x5d, 0xe2, 0xda, 0x58, 0xf8,
-	0x00, 0x6d, 0x93, 0x95, 0x0d, 0xa0, 0x19, 0x63, 0xe5, 0x5a, 0x43, 0x6b, 0xd4, 0xe1, 0xfa, 0x93,
-	0x1d, 0x82, 0x3d, 0x47, 0xa9, 0xa2, 0x3c, 0x73, 0x1b, 0x43, 0xeb, 0x41, 0x27, 0x57, 0x06, 0xe7,
-	0xab, 0x00, 0xef, 0x42, 0x4f, 0x9b, 0x72, 0x3e, 0x91, 0xe8, 0x05, 0x74, 0x22, 0x15, 0x84, 0x98,
-	0x60, 0x81, 0x94, 0xca, 0xe1, 0x4e, 0xa4, 0x4e, 0xe8, 0xcc, 0xf6, 0xa0, 0x35, 0x17, 0x49, 0x89,
-	0x6e, 0x73, 0x68, 0x8d, 0x7a, 0xdc, 0x1c, 0xbc, 0x4b, 0x80, 0x75, 0xb7, 0xec, 0x7f, 0x70, 0x62,
-	0xac, 0x02, 0xed, 0x9c, 0xf2, 0xf6, 0xb8, 0x1d, 0x63, 0x45, 0xd4, 0xdf, 0x98, 0x0c, 0xa1, 0xbb,
-	0x31, 0x89, 0xe7, 0xb2, 0x3e, 0xeb, 0xf8, 0x25, 0x00, 0x99, 0x34, 0x4a, 0x63, 0xbb, 0x43, 0x88,
-	0xd6, 0x7a, 0xef, 0xc0, 0xae, 0x2b, 0xeb, 0x34, 0x93, 0x24, 0xbf, 0x89, 0x83, 0xac, 0x4c, 0xa9,
-	0xc4, 0x36, 0x77, 0x08, 0xb8, 0x28, 0x53, 0xf6, 0x1f, 0xb4, 0x8b, 0x25, 0x31, 0x0d, 0x62, 0x5a,
-	0xc5, 0xf2, 0xa2, 0x4c, 0xbd, 0x9f, 0x0d, 0xd8, 0x79, 0xb8, 0x63, 0x3a, 0x8d, 0x2a, 0x84, 0x2c,
-	0x82, 0xf5, 0x5c, 0x1d, 0x02, 0xce, 0xb1, 0x62, 0xfb, 0x60, 0x63, 0x16, 0x12, 0xd5, 0x20, 0xaa,
-	0x8d, 0x59, 0xa8, 0x89, 0x03, 0xe8, 0x47, 0x85, 0x0c, 0x70, 0x39, 0x13, 0xa5, 0x2a, 0x30, 0x24,
-	0xa7, 0x0e, 0xef, 0x45, 0x85, 0x3c, 0x5d, 0x61, 0xec, 0x08, 0x3a, 0x52, 0x2c, 0xea, 0x7d, 0xdb,
-	0xa6, 0x01, 0xae, 0xf7, 0x8d, 0x1c, 0xd0, 0x8a, 0x9d, 0x6d, 0x71, 0x47, 0x8a, 0x85, 0x59, 0x37,
-	0x0e, 0xbb, 0x14, 0x1f, 0xa4, 0x28, 0xe3, 0xc4, 0x8c, 0x01, 0x95, 0xdb, 0x22, 0xf5, 0xf0, 0x09,
-	0xf5, 0x67, 0x8a, 0xbb, 0x2c, 0xd3, 0x54, 0xc8, 0xea, 0x6c, 0x8b, 0xff, 0x2b, 0xd7, 0x28, 0xed,
-	0xbf, 0x7a, 0xdf, 0x03, 0x30, 0x39, 0xf5, 0x9d, 0xf3, 0xde, 0x02, 0xac, 0xd5, 0xec, 0x10, 0x1c,
-	0x7d, 0xcb, 0x9f, 0xbb, 0xc1, 0x76, 0x3c, 0xa7, 0x58, 0xef, 0x3b, 0xec, 0xff, 0xa1, 0xae, 0xfe,
-	0xdb, 0x52, 0xb1, 0x0c, 0x42, 0x9c, 0x4a, 0x44, 0x1a, 0x63, 0x9f, 0x77, 0x52, 0xb1, 0x3c, 0x21,
-	0x40, 0x0f, 0x59, 0xd3, 0x09, 0xce, 0x31, 0xa1, 0x49, 0xf6, 0xb9, 0x93, 0x8a, 0xe5, 0x27, 0x7d,
-	0x66, 0x23, 0x18, 0xdc, 0x93, 0xab, 0x7e, 0xf5, 0xed, 0xee, 0xf1, 0x9d, 0x55, 0x4c, 0xdd, 0x48,
-	0x0e, 0x47, 0xb9, 0x9c, 0xfa, 0xb3, 0xea, 0x0e, 0xa5, 0x79, 0xb0, 0xfc, 0x5b, 0x31, 0x91, 0xd1,
-	0x8d, 0x79, 0xa0, 0x94, 0x5f, 0x83, 0xc6, 0x7e, 0xdd, 0xc6, 0xd7, 0xe3, 0x69, 0x54, 0xcc, 0xca,
-	0x89, 0x7f, 0x93, 0xa7, 0xe3, 0x0d, 0xe9, 0xd8, 0x48, 0xc7, 0x46, 0x3a, 0x7e, 0xea, 0x01, 0x9c,
-	0xb4, 0x89, 0x7c, 0xfd, 0x3b, 0x00, 0x00, 0xff, 0xff, 0x29, 0xbc, 0x0b, 0xfc, 0x1f, 0x05, 0x00,
+	// 737 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x55, 0x5f, 0x6b, 0xe3, 0x46,
+	0x10, 0x8f, 0xfc, 0x57, 0x1e, 0xdb, 0xb1, 0xbb, 0x49, 0x89, 0x4a, 0x5b, 0x30, 0x0a, 0x05, 0x93,
+	0x07, 0x1b, 0x5c, 0x28, 0x0d, 0xa5, 0x0f, 0x2d, 0x71, 0x49, 0xc9, 0x25, 0x70, 0x1b, 0x48, 0xe0,
+	0x5e, 0xc4, 0x3a, 0x9a, 0xd8, 0xc2, 0x96, 0x94, 0x5b, 0xad, 0x6c, 0xeb, 0xe9, 0xb8, 0x4f, 0x77,
+	0x5f, 0xe4, 0x3e, 0xc8, 0xb1, 0xb3, 0x72, 0xec, 0xf8, 0x1c, 0xc3, 0xdd, 0x93, 0x76, 0xe6, 0x37,
+	0xbf, 0xd9, 0xf9, 0xcd, 0x68, 0x77, 0xe1, 0x74, 0x86, 0xfe, 0x18, 0x65, 0x5f, 0x2e, 0x12, 0x54,
+	0xfd, 0xe9, 0x7c, 0xf5, 0xf5, 0x68, 0xd1, 0x7b, 0x92, 0xb1, 0x8a, 0x59, 0x35, 0xf7, 0xbb, 0x9f,
+	0x2d, 0xa8, 0x5e, 0xdd, 0xf1, 0xfb, 0x5b, 0x54, 0xec, 0x37, 0x28, 0x4b, 0x14, 0x7e, 0xe2, 0x58,
+	0x9d, 0x62, 0xb7, 0x3e, 0x68, 0xf5, 0xf2, 0xa0, 0xde, 0xd5, 0x1d, 0x47, 0xe1, 0x73, 0x83, 0xb2,
+	0x21, 0x30, 0x29, 0xa2, 0x31, 0x7a, 0xef, 0x53, 0x94, 0x01, 0x26, 0x5e, 0x10, 0x3d, 0xc6, 0x4e,
+	0x81, 0x38, 0x27, 0xcf, 0x1c, 0xae, 0x43, 0xde, 0xa6, 0x28, 0xb3, 0xff, 0xa3, 0xc7, 0x98, 0xb7,
+	0xe5, 0xca, 0x0e, 0x30, 0xd1, 0x1e, 0xd6, 0x85, 0xca, 0x42, 0x06, 0x0a, 0x13, 0xa7, 0x48, 0xd4,
+	0xf6, 0xc6, 0x76, 0xf7, 0x1a, 0xe0, 0x39, 0xce, 0xfe, 0x81, 0x56, 0x88, 0x4a, 0xf8, 0x42, 0x09,
+	0x2f, 0xa7, 0x94, 0x88, 0xe2, 0x6c, 0x50, 0xae, 0xf3, 0x08, 0x43, 0x3d, 0x0c, 0x37, 0xcd, 0xc4,
+	0xfd, 0x64, 0x41, 0xfd, 0x52, 0x24, 0x13, 0xf4, 0x8d, 0xd4, 0x3f, 0xa0, 0x31, 0x21, 0xd3, 0xdb,
+	0x54, 0x7c, 0xb4, 0xa5, 0x58, 0x33, 0x78, 0xdd, 0x04, 0x72, 0xd2, 0x7e, 0x0e, 0xcd, 0x9c, 0x97,
+	0x17, 0x62, 0x64, 0x1f, 0x6f, 0xd7, 0x4e, 0xcc, 0x7c, 0x0b, 0x53, 0x02, 0x1b, 0x7e, 0xad, 0xc2,
+	0x08, 0xff, 0xe5, 0x35, 0x15, 0x94, 0x64, 0x5b, 0xc9, 0x7f, 0x50, 0x31, 0xc5, 0xb1, 0x36, 0x14,
+	0xa7, 0x98, 0x39, 0x56, 0xc7, 0xea, 0xd6, 0xb8, 0x5e, 0xb2, 0x33, 0xa8, 0xce, 0x51, 0x26, 0x41,
+	0x1c, 0x39, 0x85, 0x8e, 0xf5, 0xa2, 0xa7, 0x77, 0xc6, 0xcf, 0x57, 0x01, 0xee, 0x8d, 0x9e, 0x3b,
+	0xe5, 0xdc, 0x91, 0xe8, 0x67, 0xa8, 0x05, 0x89, 0xe7, 0xe3, 0x0c, 0x15, 0x52, 0x2a, 0x9b, 0xdb,
+	0x41, 0x72, 0x41, 0x36, 0x3b, 0x86, 0xf2, 0x5c, 0xcc, 0x52, 0x74, 0x8a, 0x1d, 0xab, 0xdb, 0xe0,
+	0xc6, 0x70, 0x6f, 0x01, 0xd6, 0x4d, 0x63, 0x3f, 0x81, 0x3d, 0xc5, 0xcc, 0xd3, 0x0d, 0xa0, 0xbc,
+	0x0d, 0x5e, 0x9d, 0x62, 0x46, 0xd0, 0xb7, 0x14, 0xe9, 0x43, 0x7d, 0xa3, 0xa1, 0xfb, 0xb2, 0xee,
+	0xad, 0xf8, 0x57, 0x00, 0x2a, 0xd2, 0x30, 0x4d, 0xd9, 0x35, 0xf2, 0x68, 0xae, 0x7b, 0x0f, 0xad,
+	0xad, 0xce, 0xef, 0x68, 0xc9, 0x00, 0xaa, 0x18, 0x29, 0x19, 0x3c, 0xcf, 0x7c, 0xd7, 0xcf, 0x37,
+	0x8c, 0x94, 0xcc, 0xf8, 0x2a, 0xd0, 0xf5, 0xe1, 0x68, 0xc7, 0x48, 0xf7, 0xc9, 0xf8, 0x9e, 0x5d,
+	0xfe, 0x82, 0xd6, 0x16, 0xc6, 0x18, 0x94, 0x22, 0x11, 0x62, 0x5e, 0x3f, 0xad, 0xd7, 0x63, 0x2b,
+	0x6c, 0x8e, 0xed, 0x6f, 0xa8, 0xe6, 0x5d, 0xd7, 0x2d, 0x1c, 0xcd, 0xe2, 0x87, 0xa9, 0x17, 0xa5,
+	0x21, 0x31, 0x4b, 0xdc, 0x26, 0xc7, 0x4d, 0x1a, 0xb2, 0x1f, 0xa1, 0xa2, 0x96, 0x84, 0x14, 0x08,
+	0x29, 0xab, 0xe5, 0x4d, 0x1a, 0xba, 0x1f, 0x0b, 0x70, 0xf8, 0xf2, 0xa4, 0xeb, 0x34, 0x89, 0x12,
+	0x52, 0x79, 0xeb, 0x06, 0xda, 0xe4, 0xb8, 0xc2, 0x8c, 0x9d, 0x68, 0x7d, 0x3e, 0x41, 0x05, 0x82,
+	0x2a, 0x18, 0xf9, 0x1a, 0x38, 0x85, 0x66, 0xa0, 0xa4, 0x87, 0xcb, 0x89, 0x48, 0x13, 0x85, 0x3e,
+	0x4d, 0xc9, 0xe6, 0x8d, 0x40, 0xc9, 0xe1, 0xca, 0xc7, 0x06, 0x50, 0x93, 0x62, 0x91, 0x1f, 0xd9,
+	0x52, 0xc7, 0x7a, 0x71, 0x64, 0xa9, 0x02, 0x3a, 0xa5, 0x97, 0x07, 0xdc, 0x96, 0x62, 0x41, 0x6b,
+	0xc6, 0xe1, 0x88, 0xe2, 0xbd, 0x10, 0xe5, 0x74, 0x66, 0x7e, 0x01, 0x4c, 0x9c, 0x32, 0xb1, 0x3b,
+	0x3b, 0xd8, 0xd7, 0x14, 0x77, 0x9b, 0x86, 0xa1, 0x90, 0xd9, 0xe5, 0x01, 0xff, 0x41, 0xae, 0xbd,
+	0x74, 0x85, 0x24, 0xff, 0x36, 0x00, 0x4c, 0x4e, 0x7d, 0xf3, 0xb9, 0x7f, 0x02, 0xac, 0xd9, 0xec,
+	0x0c, 0x6c, 0x7d, 0xd7, 0xee, 0xbb, 0x47, 0xab, 0xd3, 0x39, 0xc5, 0xba, 0x1f, 0xe0, 0xe4, 0x95,
+	0x7d, 0xf5, 0x2f, 0x1b, 0x8a, 0xa5, 0xe7, 0xe3, 0x58, 0xa2, 0x99, 0x63, 0x93, 0xd7, 0x42, 0xb1,
+	0xbc, 0x20, 0x87, 0x6e, 0xb2, 0x86, 0x67, 0x38, 0xc7, 0x19, 0x75, 0xb2, 0xc9, 0xed, 0x50, 0x2c,
+	0xdf, 0x68, 0x9b, 0x75, 0xa1, 0xfd, 0x0c, 0xae, 0xf4, 0xea, 0xab, 0xa6, 0xc1, 0x0f, 0x57, 0x31,
+	0xb9, 0x90, 0x18, 0x06, 0xb1, 0x1c, 0xf7, 0x26, 0xd9, 0x13, 0x4a, 0xf3, 0x6c, 0xf4, 0x1e, 0xc5,
+	0x48, 0x06, 0x0f, 0xe6, 0x99, 0x48, 0x7a, 0xb9, 0xd3, 0x94, 0x9f, 0xcb, 0x78, 0x77, 0x3e, 0x0e,
+	0xd4, 0x24, 0x1d, 0xf5, 0x1e, 0xe2, 0xb0, 0xbf, 0x41, 0xed, 0x1b, 0x6a, 0xdf, 0x50, 0xfb, 0xbb,
+	0x9e, 0xa1, 0x51, 0x85, 0xc0, 0xdf, 0xbf, 0x0c, 0x00, 0xf0, 0x47, 0xb0, 0x1d, 0xa5, 0x06, 0x00,
 	0x00,
 }
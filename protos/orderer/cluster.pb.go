@@ -0,0 +1,453 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: orderer/cluster.proto
+
+package orderer
+
+import proto "github.com/golang/protobuf/proto"
+import fmt "fmt"
+import math "math"
+import common "github.com/hyperledger/fabric/protos/common"
+
+import (
+	context "golang.org/x/net/context"
+	grpc "google.golang.org/grpc"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// StepRequest wraps a message that is sent to a cluster member.
+type StepRequest struct {
+	// Types that are valid to be assigned to Payload:
+	//	*StepRequest_ConsensusRequest
+	//	*StepRequest_SubmitRequest
+	Payload isStepRequest_Payload `protobuf_oneof:"payload"`
+}
+
+func (m *StepRequest) Reset()                    { *m = StepRequest{} }
+func (m *StepRequest) String() string            { return proto.CompactTextString(m) }
+func (*StepRequest) ProtoMessage()               {}
+func (*StepRequest) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{0} }
+
+type isStepRequest_Payload interface {
+	isStepRequest_Payload()
+}
+
+type StepRequest_ConsensusRequest struct {
+	ConsensusRequest *ConsensusRequest `protobuf:"bytes,1,opt,name=consensus_request,json=consensusRequest,oneof"`
+}
+type StepRequest_SubmitRequest struct {
+	SubmitRequest *SubmitRequest `protobuf:"bytes,2,opt,name=submit_request,json=submitRequest,oneof"`
+}
+
+func (*StepRequest_ConsensusRequest) isStepRequest_Payload() {}
+func (*StepRequest_SubmitRequest) isStepRequest_Payload()    {}
+
+func (m *StepRequest) GetPayload() isStepRequest_Payload {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+func (m *StepRequest) GetConsensusRequest() *ConsensusRequest {
+	if x, ok := m.GetPayload().(*StepRequest_ConsensusRequest); ok {
+		return x.ConsensusRequest
+	}
+	return nil
+}
+
+func (m *StepRequest) GetSubmitRequest() *SubmitRequest {
+	if x, ok := m.GetPayload().(*StepRequest_SubmitRequest); ok {
+		return x.SubmitRequest
+	}
+	return nil
+}
+
+// XXX_OneofFuncs is for the internal use of the proto package.
+func (*StepRequest) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
+	return _StepRequest_OneofMarshaler, _StepRequest_OneofUnmarshaler, _StepRequest_OneofSizer, []interface{}{
+		(*StepRequest_ConsensusRequest)(nil),
+		(*StepRequest_SubmitRequest)(nil),
+	}
+}
+
+func _StepRequest_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
+	m := msg.(*StepRequest)
+	// payload
+	switch x := m.Payload.(type) {
+	case *StepRequest_ConsensusRequest:
+		b.EncodeVarint(1<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.ConsensusRequest); err != nil {
+			return err
+		}
+	case *StepRequest_SubmitRequest:
+		b.EncodeVarint(2<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.SubmitRequest); err != nil {
+			return err
+		}
+	case nil:
+	default:
+		return fmt.Errorf("StepRequest.Payload has unexpected type %T", x)
+	}
+	return nil
+}
+
+func _StepRequest_OneofUnmarshaler(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error) {
+	m := msg.(*StepRequest)
+	switch tag {
+	case 1: // payload.consensus_request
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(ConsensusRequest)
+		err := b.DecodeMessage(msg)
+		m.Payload = &StepRequest_ConsensusRequest{msg}
+		return true, err
+	case 2: // payload.submit_request
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(SubmitRequest)
+		err := b.DecodeMessage(msg)
+		m.Payload = &StepRequest_SubmitRequest{msg}
+		return true, err
+	default:
+		return false, nil
+	}
+}
+
+func _StepRequest_OneofSizer(msg proto.Message) (n int) {
+	m := msg.(*StepRequest)
+	// payload
+	switch x := m.Payload.(type) {
+	case *StepRequest_ConsensusRequest:
+		s := proto.Size(x.ConsensusRequest)
+		n += proto.SizeVarint(1<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case *StepRequest_SubmitRequest:
+		s := proto.Size(x.SubmitRequest)
+		n += proto.SizeVarint(2<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
+	case nil:
+	default:
+		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
+	}
+	return n
+}
+
+// StepResponse is a message received from a cluster member.
+type StepResponse struct {
+	SubmitRes *SubmitResponse `protobuf:"bytes,1,opt,name=submit_res,json=submitRes" json:"submit_res,omitempty"`
+}
+
+func (m *StepResponse) Reset()                    { *m = StepResponse{} }
+func (m *StepResponse) String() string            { return proto.CompactTextString(m) }
+func (*StepResponse) ProtoMessage()               {}
+func (*StepResponse) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{1} }
+
+func (m *StepResponse) GetSubmitRes() *SubmitResponse {
+	if m != nil {
+		return m.SubmitRes
+	}
+	return nil
+}
+
+// ConsensusRequest is a consensus specific message sent to a cluster member.
+type ConsensusRequest struct {
+	Channel string `protobuf:"bytes,1,opt,name=channel" json:"channel,omitempty"`
+	Payload []byte `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`
+}
+
+func (m *ConsensusRequest) Reset()                    { *m = ConsensusRequest{} }
+func (m *ConsensusRequest) String() string            { return proto.CompactTextString(m) }
+func (*ConsensusRequest) ProtoMessage()               {}
+func (*ConsensusRequest) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{2} }
+
+func (m *ConsensusRequest) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+func (m *ConsensusRequest) GetPayload() []byte {
+	if m != nil {
+		return m.Payload
+	}
+	return nil
+}
+
+// SubmitRequest wraps a transaction to be sent for ordering.
+type SubmitRequest struct {
+	Channel string `protobuf:"bytes,1,opt,name=channel" json:"channel,omitempty"`
+	// last_validation_seq denotes the last
+	// configuration sequence at which the
+	// sender validated this message.
+	LastValidationSeq uint64 `protobuf:"varint,2,opt,name=last_validation_seq,json=lastValidationSeq" json:"last_validation_seq,omitempty"`
+	// content is the fabric transaction
+	// that is forwarded to the cluster member.
+	Content *common.Envelope `protobuf:"bytes,3,opt,name=content" json:"content,omitempty"`
+}
+
+func (m *SubmitRequest) Reset()                    { *m = SubmitRequest{} }
+func (m *SubmitRequest) String() string            { return proto.CompactTextString(m) }
+func (*SubmitRequest) ProtoMessage()               {}
+func (*SubmitRequest) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{3} }
+
+func (m *SubmitRequest) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+func (m *SubmitRequest) GetLastValidationSeq() uint64 {
+	if m != nil {
+		return m.LastValidationSeq
+	}
+	return 0
+}
+
+func (m *SubmitRequest) GetContent() *common.Envelope {
+	if m != nil {
+		return m.Content
+	}
+	return nil
+}
+
+// SubmitResponse returns a success
+// or failure status to the sender.
+type SubmitResponse struct {
+	Channel string `protobuf:"bytes,1,opt,name=channel" json:"channel,omitempty"`
+	// Status code, which may be used to programatically respond to success/failure.
+	Status common.Status `protobuf:"varint,2,opt,name=status,enum=common.Status" json:"status,omitempty"`
+	// Info string which may contain additional information about the returned status.
+	Info string `protobuf:"bytes,3,opt,name=info" json:"info,omitempty"`
+}
+
+func (m *SubmitResponse) Reset()                    { *m = SubmitResponse{} }
+func (m *SubmitResponse) String() string            { return proto.CompactTextString(m) }
+func (*SubmitResponse) ProtoMessage()               {}
+func (*SubmitResponse) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{4} }
+
+func (m *SubmitResponse) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+func (m *SubmitResponse) GetStatus() common.Status {
+	if m != nil {
+		return m.Status
+	}
+	return common.Status_UNKNOWN
+}
+
+func (m *SubmitResponse) GetInfo() string {
+	if m != nil {
+		return m.Info
+	}
+	return ""
+}
+
+// PullRequest asks a cluster member for a committed block.
+type PullRequest struct {
+	Channel     string `protobuf:"bytes,1,opt,name=channel" json:"channel,omitempty"`
+	BlockNumber uint64 `protobuf:"varint,2,opt,name=block_number,json=blockNumber" json:"block_number,omitempty"`
+}
+
+func (m *PullRequest) Reset()                    { *m = PullRequest{} }
+func (m *PullRequest) String() string            { return proto.CompactTextString(m) }
+func (*PullRequest) ProtoMessage()               {}
+func (*PullRequest) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{5} }
+
+func (m *PullRequest) GetChannel() string {
+	if m != nil {
+		return m.Channel
+	}
+	return ""
+}
+
+func (m *PullRequest) GetBlockNumber() uint64 {
+	if m != nil {
+		return m.BlockNumber
+	}
+	return 0
+}
+
+// PullResponse carries the requested block.
+type PullResponse struct {
+	Block *common.Block `protobuf:"bytes,1,opt,name=block" json:"block,omitempty"`
+}
+
+func (m *PullResponse) Reset()                    { *m = PullResponse{} }
+func (m *PullResponse) String() string            { return proto.CompactTextString(m) }
+func (*PullResponse) ProtoMessage()               {}
+func (*PullResponse) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{6} }
+
+func (m *PullResponse) GetBlock() *common.Block {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func init() {
+	proto.RegisterType((*StepRequest)(nil), "orderer.StepRequest")
+	proto.RegisterType((*StepResponse)(nil), "orderer.StepResponse")
+	proto.RegisterType((*ConsensusRequest)(nil), "orderer.ConsensusRequest")
+	proto.RegisterType((*SubmitRequest)(nil), "orderer.SubmitRequest")
+	proto.RegisterType((*SubmitResponse)(nil), "orderer.SubmitResponse")
+	proto.RegisterType((*PullRequest)(nil), "orderer.PullRequest")
+	proto.RegisterType((*PullResponse)(nil), "orderer.PullResponse")
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// Client API for Cluster service
+
+type ClusterClient interface {
+	// Step passes an implementation-specific message to another cluster member.
+	Step(ctx context.Context, in *StepRequest, opts ...grpc.CallOption) (*StepResponse, error)
+	// Pull retrieves a committed block of a channel from another cluster member.
+	Pull(ctx context.Context, in *PullRequest, opts ...grpc.CallOption) (*PullResponse, error)
+}
+
+type clusterClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewClusterClient(cc *grpc.ClientConn) ClusterClient {
+	return &clusterClient{cc}
+}
+
+func (c *clusterClient) Step(ctx context.Context, in *StepRequest, opts ...grpc.CallOption) (*StepResponse, error) {
+	out := new(StepResponse)
+	err := grpc.Invoke(ctx, "/orderer.Cluster/Step", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *clusterClient) Pull(ctx context.Context, in *PullRequest, opts ...grpc.CallOption) (*PullResponse, error) {
+	out := new(PullResponse)
+	err := grpc.Invoke(ctx, "/orderer.Cluster/Pull", in, out, c.cc, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// Server API for Cluster service
+
+type ClusterServer interface {
+	// Step passes an implementation-specific message to another cluster member.
+	Step(context.Context, *StepRequest) (*StepResponse, error)
+	// Pull retrieves a committed block of a channel from another cluster member.
+	Pull(context.Context, *PullRequest) (*PullResponse, error)
+}
+
+func RegisterClusterServer(s *grpc.Server, srv ClusterServer) {
+	s.RegisterService(&_Cluster_serviceDesc, srv)
+}
+
+func _Cluster_Step_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StepRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClusterServer).Step(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/orderer.Cluster/Step",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClusterServer).Step(ctx, req.(*StepRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Cluster_Pull_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PullRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ClusterServer).Pull(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/orderer.Cluster/Pull",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ClusterServer).Pull(ctx, req.(*PullRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Cluster_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "orderer.Cluster",
+	HandlerType: (*ClusterServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Step",
+			Handler:    _Cluster_Step_Handler,
+		},
+		{
+			MethodName: "Pull",
+			Handler:    _Cluster_Pull_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "orderer/cluster.proto",
+}
+
+func init() { proto.RegisterFile("orderer/cluster.proto", fileDescriptor1) }
+
+var fileDescriptor1 = []byte{
+	// 461 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x84, 0x93, 0xdf, 0x8a, 0xd3, 0x40,
+	0x14, 0xc6, 0xad, 0xd6, 0x0d, 0x3d, 0xfd, 0x43, 0x77, 0xd6, 0x6a, 0xed, 0x95, 0x46, 0x14, 0x11,
+	0x49, 0xa0, 0x05, 0x6f, 0x85, 0x2e, 0x2e, 0x8b, 0x17, 0x22, 0x53, 0xf4, 0xc2, 0x9b, 0x92, 0xa4,
+	0xa7, 0x6d, 0x70, 0x3a, 0x93, 0xce, 0x99, 0x2c, 0xec, 0x03, 0xf8, 0x24, 0xbe, 0xa8, 0x64, 0x66,
+	0x92, 0x76, 0x2b, 0xec, 0x5e, 0xb5, 0xf3, 0x9d, 0xef, 0xfc, 0xce, 0x77, 0x98, 0x09, 0x8c, 0x94,
+	0x5e, 0xa1, 0x46, 0x1d, 0x67, 0xa2, 0x24, 0x83, 0x3a, 0x2a, 0xb4, 0x32, 0x8a, 0x05, 0x5e, 0x9e,
+	0x5c, 0x64, 0x6a, 0xb7, 0x53, 0x32, 0x76, 0x3f, 0xae, 0x1a, 0xfe, 0x6d, 0x41, 0x77, 0x61, 0xb0,
+	0xe0, 0xb8, 0x2f, 0x91, 0x0c, 0xbb, 0x86, 0xf3, 0x4c, 0x49, 0x42, 0x49, 0x25, 0x2d, 0xb5, 0x13,
+	0xc7, 0xad, 0x57, 0xad, 0xf7, 0xdd, 0xe9, 0xcb, 0xc8, 0x93, 0xa2, 0xcb, 0xda, 0xe1, 0xbb, 0xae,
+	0x1f, 0xf1, 0x61, 0x76, 0xa2, 0xb1, 0xcf, 0x30, 0xa0, 0x32, 0xdd, 0xe5, 0xa6, 0xc1, 0x3c, 0xb6,
+	0x98, 0xe7, 0x0d, 0x66, 0x61, 0xcb, 0x07, 0x46, 0x9f, 0x8e, 0x85, 0x79, 0x07, 0x82, 0x22, 0xb9,
+	0x15, 0x2a, 0x59, 0x85, 0x57, 0xd0, 0x73, 0x21, 0xa9, 0xa8, 0xc6, 0xb0, 0x4f, 0x00, 0x0d, 0x9b,
+	0x7c, 0xbc, 0x17, 0xff, 0x71, 0x9d, 0x99, 0x77, 0x6a, 0x2c, 0x85, 0x57, 0x30, 0x3c, 0xcd, 0xce,
+	0xc6, 0x10, 0x64, 0xdb, 0x44, 0x4a, 0x14, 0x16, 0xd4, 0xe1, 0xf5, 0x91, 0x8d, 0x9b, 0x00, 0x36,
+	0x7a, 0x8f, 0x37, 0x79, 0xfe, 0xb4, 0xa0, 0x7f, 0x27, 0xfd, 0x3d, 0x94, 0x08, 0x2e, 0x44, 0x42,
+	0x66, 0x79, 0x93, 0x88, 0x7c, 0x95, 0x98, 0x5c, 0xc9, 0x25, 0xe1, 0xde, 0x12, 0xdb, 0xfc, 0xbc,
+	0x2a, 0xfd, 0x6c, 0x2a, 0x0b, 0xdc, 0xb3, 0x0f, 0x10, 0x64, 0x4a, 0x1a, 0x94, 0x66, 0xfc, 0xc4,
+	0x2e, 0x36, 0x8c, 0xfc, 0x8d, 0x7d, 0x91, 0x37, 0x28, 0x54, 0x81, 0xbc, 0x36, 0x84, 0x6b, 0x18,
+	0xdc, 0x5d, 0xf6, 0x9e, 0x1c, 0xef, 0xe0, 0x8c, 0x4c, 0x62, 0x4a, 0xb2, 0xa3, 0x07, 0xd3, 0x41,
+	0x8d, 0x5d, 0x58, 0x95, 0xfb, 0x2a, 0x63, 0xd0, 0xce, 0xe5, 0x5a, 0xd9, 0xe1, 0x1d, 0x6e, 0xff,
+	0x87, 0x5f, 0xa1, 0xfb, 0xbd, 0x14, 0xe2, 0xe1, 0x65, 0x5f, 0x43, 0x2f, 0x15, 0x2a, 0xfb, 0xbd,
+	0x94, 0xe5, 0x2e, 0x45, 0xed, 0xb7, 0xec, 0x5a, 0xed, 0x9b, 0x95, 0xc2, 0x19, 0xf4, 0x1c, 0xcb,
+	0x27, 0x7e, 0x03, 0x4f, 0x6d, 0xd9, 0x5f, 0x63, 0xbf, 0x8e, 0x35, 0xaf, 0x44, 0xee, 0x6a, 0x53,
+	0x82, 0xe0, 0xd2, 0xbd, 0x6a, 0x36, 0x83, 0x76, 0xf5, 0x16, 0xd8, 0xb3, 0xc3, 0x7d, 0x1f, 0xde,
+	0xef, 0x64, 0x74, 0xa2, 0xfa, 0x21, 0x33, 0x68, 0x57, 0x43, 0x8f, 0x9a, 0x8e, 0xf6, 0x99, 0x8c,
+	0x4e, 0x54, 0xd7, 0x34, 0xff, 0x01, 0x6f, 0x95, 0xde, 0x44, 0xdb, 0xdb, 0x02, 0xb5, 0xc0, 0xd5,
+	0x06, 0x75, 0xb4, 0x4e, 0x52, 0x9d, 0x67, 0xee, 0xdb, 0xa1, 0xba, 0xeb, 0xd7, 0xc7, 0x4d, 0x6e,
+	0xb6, 0x65, 0x5a, 0x25, 0x8f, 0x8f, 0xdc, 0xb1, 0x73, 0xc7, 0xce, 0x1d, 0x7b, 0x77, 0x7a, 0x66,
+	0xcf, 0xb3, 0x7f, 0x03, 0x00, 0xa3, 0x7c, 0xbf, 0x01, 0xb0, 0x03, 0x00, 0x00,
+}
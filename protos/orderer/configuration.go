@@ -23,6 +23,7 @@ import (
 	"github.com/hyperledger/fabric/protos/msp"
 
 	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes/empty"
 )
 
 func init() {
@@ -109,6 +110,8 @@ func (docv *DynamicOrdererConfigValue) VariablyOpaqueFieldProto(name string) (pr
 		return &KafkaBrokers{}, nil
 	case "ChannelRestrictions":
 		return &ChannelRestrictions{}, nil
+	case "BlockScheduling":
+		return &BlockScheduling{}, nil
 	case "Capabilities":
 		return &common.Capabilities{}, nil
 	default:
@@ -132,3 +135,19 @@ func (doocv *DynamicOrdererOrgConfigValue) VariablyOpaqueFieldProto(name string)
 		return nil, fmt.Errorf("unknown Orderer Org ConfigValue name: %s", doocv.name)
 	}
 }
+
+func (ct *ConsensusType) VariablyOpaqueFields() []string {
+	return []string{"metadata"}
+}
+
+func (ct *ConsensusType) VariablyOpaqueFieldProto(name string) (proto.Message, error) {
+	if name != ct.VariablyOpaqueFields()[0] {
+		return nil, fmt.Errorf("not a marshaled field: %s", name)
+	}
+	switch ct.Type {
+	case "raft":
+		return &RaftConfigMetadata{}, nil
+	default:
+		return &empty.Empty{}, nil
+	}
+}
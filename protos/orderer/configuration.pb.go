@@ -12,14 +12,42 @@ var _ = proto.Marshal
 var _ = fmt.Errorf
 var _ = math.Inf
 
+type ConsensusType_State int32
+
+const (
+	ConsensusType_STATE_NORMAL      ConsensusType_State = 0
+	ConsensusType_STATE_MAINTENANCE ConsensusType_State = 1
+)
+
+var ConsensusType_State_name = map[int32]string{
+	0: "STATE_NORMAL",
+	1: "STATE_MAINTENANCE",
+}
+var ConsensusType_State_value = map[string]int32{
+	"STATE_NORMAL":      0,
+	"STATE_MAINTENANCE": 1,
+}
+
+func (x ConsensusType_State) String() string {
+	return proto.EnumName(ConsensusType_State_name, int32(x))
+}
+func (ConsensusType_State) EnumDescriptor() ([]byte, []int) { return fileDescriptor2, []int{0, 0} }
+
 type ConsensusType struct {
+	// The consensus type: "solo", "kafka" or "raft".
 	Type string `protobuf:"bytes,1,opt,name=type" json:"type,omitempty"`
+	// Opaque metadata, dependent on the consensus type.
+	Metadata []byte `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	// The state of the channel. In maintenance, normal transactions are
+	// rejected, and the consensus type may be changed to migrate the channel
+	// to another consenter.
+	State ConsensusType_State `protobuf:"varint,3,opt,name=state,enum=orderer.ConsensusType_State" json:"state,omitempty"`
 }
 
 func (m *ConsensusType) Reset()                    { *m = ConsensusType{} }
 func (m *ConsensusType) String() string            { return proto.CompactTextString(m) }
 func (*ConsensusType) ProtoMessage()               {}
-func (*ConsensusType) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{0} }
+func (*ConsensusType) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{0} }
 
 func (m *ConsensusType) GetType() string {
 	if m != nil {
@@ -28,6 +56,20 @@ func (m *ConsensusType) GetType() string {
 	return ""
 }
 
+func (m *ConsensusType) GetMetadata() []byte {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+func (m *ConsensusType) GetState() ConsensusType_State {
+	if m != nil {
+		return m.State
+	}
+	return ConsensusType_STATE_NORMAL
+}
+
 type BatchSize struct {
 	// Simply specified as number of messages for now, in the future
 	// we may want to allow this to be specified by size in bytes
@@ -43,7 +85,7 @@ type BatchSize struct {
 func (m *BatchSize) Reset()                    { *m = BatchSize{} }
 func (m *BatchSize) String() string            { return proto.CompactTextString(m) }
 func (*BatchSize) ProtoMessage()               {}
-func (*BatchSize) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{1} }
+func (*BatchSize) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{1} }
 
 func (m *BatchSize) GetMaxMessageCount() uint32 {
 	if m != nil {
@@ -75,7 +117,7 @@ type BatchTimeout struct {
 func (m *BatchTimeout) Reset()                    { *m = BatchTimeout{} }
 func (m *BatchTimeout) String() string            { return proto.CompactTextString(m) }
 func (*BatchTimeout) ProtoMessage()               {}
-func (*BatchTimeout) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{2} }
+func (*BatchTimeout) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{2} }
 
 func (m *BatchTimeout) GetTimeout() string {
 	if m != nil {
@@ -95,7 +137,7 @@ type KafkaBrokers struct {
 func (m *KafkaBrokers) Reset()                    { *m = KafkaBrokers{} }
 func (m *KafkaBrokers) String() string            { return proto.CompactTextString(m) }
 func (*KafkaBrokers) ProtoMessage()               {}
-func (*KafkaBrokers) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{3} }
+func (*KafkaBrokers) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{3} }
 
 func (m *KafkaBrokers) GetBrokers() []string {
 	if m != nil {
@@ -112,7 +154,7 @@ type ChannelRestrictions struct {
 func (m *ChannelRestrictions) Reset()                    { *m = ChannelRestrictions{} }
 func (m *ChannelRestrictions) String() string            { return proto.CompactTextString(m) }
 func (*ChannelRestrictions) ProtoMessage()               {}
-func (*ChannelRestrictions) Descriptor() ([]byte, []int) { return fileDescriptor1, []int{4} }
+func (*ChannelRestrictions) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{4} }
 
 func (m *ChannelRestrictions) GetMaxCount() uint64 {
 	if m != nil {
@@ -121,36 +163,139 @@ func (m *ChannelRestrictions) GetMaxCount() uint64 {
 	return 0
 }
 
+// BlockScheduling configures the order in which the messages of the channel
+// are included in blocks. The messages are assigned to priority classes, and
+// the messages of a class are included in a block before those of the classes
+// listed after it, then those assigned to no class. Within a class, the
+// messages of the different creators are interleaved in proportion to the
+// weights of their organizations. An empty BlockScheduling keeps the messages
+// in the order in which they are ordered. Whether the messages are scheduled
+// is decided when the chain of the channel starts, so a config update setting
+// the first class or weight, or removing the last ones, takes effect once the
+// orderer restarts.
+type BlockScheduling struct {
+	Classes []*BlockScheduling_Class `protobuf:"bytes,1,rep,name=classes" json:"classes,omitempty"`
+	// The weight of the creators of each organization, by MSP ID. The creators
+	// of the other organizations have a weight of 1.
+	OrganizationWeights map[string]uint32 `protobuf:"bytes,2,rep,name=organization_weights,json=organizationWeights" json:"organization_weights,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"varint,2,opt,name=value"`
+}
+
+func (m *BlockScheduling) Reset()                    { *m = BlockScheduling{} }
+func (m *BlockScheduling) String() string            { return proto.CompactTextString(m) }
+func (*BlockScheduling) ProtoMessage()               {}
+func (*BlockScheduling) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{5} }
+
+func (m *BlockScheduling) GetClasses() []*BlockScheduling_Class {
+	if m != nil {
+		return m.Classes
+	}
+	return nil
+}
+
+func (m *BlockScheduling) GetOrganizationWeights() map[string]uint32 {
+	if m != nil {
+		return m.OrganizationWeights
+	}
+	return nil
+}
+
+type BlockScheduling_Class struct {
+	// The name of the class, which may be requested in the channel header
+	// of a message.
+	Name string `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	// The channel header types of the messages assigned to the class.
+	HeaderTypes []int32 `protobuf:"varint,2,rep,packed,name=header_types,json=headerTypes" json:"header_types,omitempty"`
+	// The policy the creator of a message must satisfy for the class to be
+	// requested in its channel header. If empty, the class cannot be
+	// requested.
+	Policy string `protobuf:"bytes,3,opt,name=policy" json:"policy,omitempty"`
+	// Whether the pending batch is cut as soon as a message of the class
+	// is ordered, for latency-sensitive messages.
+	Expedite bool `protobuf:"varint,4,opt,name=expedite" json:"expedite,omitempty"`
+}
+
+func (m *BlockScheduling_Class) Reset()                    { *m = BlockScheduling_Class{} }
+func (m *BlockScheduling_Class) String() string            { return proto.CompactTextString(m) }
+func (*BlockScheduling_Class) ProtoMessage()               {}
+func (*BlockScheduling_Class) Descriptor() ([]byte, []int) { return fileDescriptor2, []int{5, 0} }
+
+func (m *BlockScheduling_Class) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *BlockScheduling_Class) GetHeaderTypes() []int32 {
+	if m != nil {
+		return m.HeaderTypes
+	}
+	return nil
+}
+
+func (m *BlockScheduling_Class) GetPolicy() string {
+	if m != nil {
+		return m.Policy
+	}
+	return ""
+}
+
+func (m *BlockScheduling_Class) GetExpedite() bool {
+	if m != nil {
+		return m.Expedite
+	}
+	return false
+}
+
 func init() {
 	proto.RegisterType((*ConsensusType)(nil), "orderer.ConsensusType")
 	proto.RegisterType((*BatchSize)(nil), "orderer.BatchSize")
 	proto.RegisterType((*BatchTimeout)(nil), "orderer.BatchTimeout")
 	proto.RegisterType((*KafkaBrokers)(nil), "orderer.KafkaBrokers")
 	proto.RegisterType((*ChannelRestrictions)(nil), "orderer.ChannelRestrictions")
+	proto.RegisterType((*BlockScheduling)(nil), "orderer.BlockScheduling")
+	proto.RegisterType((*BlockScheduling_Class)(nil), "orderer.BlockScheduling.Class")
+	proto.RegisterEnum("orderer.ConsensusType_State", ConsensusType_State_name, ConsensusType_State_value)
 }
 
-func init() { proto.RegisterFile("orderer/configuration.proto", fileDescriptor1) }
-
-var fileDescriptor1 = []byte{
-	// 313 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x4c, 0xd0, 0xcd, 0x4a, 0xc3, 0x40,
-	0x10, 0x07, 0x70, 0x62, 0x8b, 0xb5, 0x8b, 0x45, 0xbb, 0xbd, 0x04, 0x7a, 0x29, 0x11, 0xa1, 0x48,
-	0x49, 0x40, 0xdf, 0x20, 0x3d, 0x4a, 0x2f, 0xb1, 0x5e, 0xbc, 0x94, 0x4d, 0x3a, 0x49, 0x96, 0x36,
-	0x3b, 0x61, 0x76, 0x03, 0x89, 0xef, 0xe1, 0xfb, 0xca, 0x6e, 0x52, 0xed, 0x6d, 0x3e, 0x7e, 0x0b,
-	0xb3, 0x7f, 0xb6, 0x44, 0x3a, 0x02, 0x01, 0x45, 0x19, 0xaa, 0x5c, 0x16, 0x0d, 0x09, 0x23, 0x51,
-	0x85, 0x35, 0xa1, 0x41, 0x3e, 0x19, 0x96, 0xc1, 0x13, 0x9b, 0x6d, 0x51, 0x69, 0x50, 0xba, 0xd1,
-	0xfb, 0xae, 0x06, 0xce, 0xd9, 0xd8, 0x74, 0x35, 0xf8, 0xde, 0xca, 0x5b, 0x4f, 0x13, 0x57, 0x07,
-	0x3f, 0x1e, 0x9b, 0xc6, 0xc2, 0x64, 0xe5, 0x87, 0xfc, 0x06, 0xfe, 0xc2, 0xe6, 0x95, 0x68, 0x0f,
-	0x15, 0x68, 0x2d, 0x0a, 0x38, 0x64, 0xd8, 0x28, 0xe3, 0xf8, 0x2c, 0x79, 0xa8, 0x44, 0xbb, 0xeb,
-	0xe7, 0x5b, 0x3b, 0xe6, 0x1b, 0xc6, 0x45, 0xaa, 0xf1, 0xdc, 0x18, 0x38, 0xd8, 0x47, 0x69, 0x67,
-	0x40, 0xfb, 0x37, 0x0e, 0x3f, 0x5e, 0x36, 0x3b, 0xd1, 0xc6, 0x76, 0xce, 0x43, 0xb6, 0xa8, 0x09,
-	0x72, 0x20, 0x82, 0xe3, 0x15, 0x1f, 0x39, 0x3e, 0xff, 0x5b, 0x5d, 0x7c, 0xb0, 0x66, 0xf7, 0xee,
-	0xac, 0xbd, 0xac, 0x00, 0x1b, 0xc3, 0x7d, 0x36, 0x31, 0x7d, 0x39, 0x9c, 0x7f, 0x69, 0xad, 0x7c,
-	0x17, 0xf9, 0x49, 0xc4, 0x84, 0x27, 0x20, 0x6d, 0x65, 0xda, 0x97, 0xbe, 0xb7, 0x1a, 0x59, 0x39,
-	0xb4, 0xc1, 0x2b, 0x5b, 0x6c, 0x4b, 0xa1, 0x14, 0x9c, 0x13, 0xd0, 0x86, 0x64, 0x66, 0x53, 0xd3,
-	0x7c, 0xc9, 0xa6, 0xf6, 0xa0, 0xff, 0xcf, 0x8e, 0x93, 0xbb, 0x4a, 0xb4, 0xee, 0x97, 0xf1, 0x27,
-	0x7b, 0x46, 0x2a, 0xc2, 0xb2, 0xab, 0x81, 0xce, 0x70, 0x2c, 0x80, 0xc2, 0x5c, 0xa4, 0x24, 0xb3,
-	0x3e, 0x6d, 0x1d, 0x0e, 0x69, 0x7f, 0x6d, 0x0a, 0x69, 0xca, 0x26, 0x0d, 0x33, 0xac, 0xa2, 0x2b,
-	0x1d, 0xf5, 0x3a, 0xea, 0x75, 0x34, 0xe8, 0xf4, 0xd6, 0xf5, 0x6f, 0xbf, 0x01, 0x00, 0x00, 0xff,
-	0xff, 0x0c, 0x47, 0xa2, 0x55, 0xca, 0x01, 0x00, 0x00,
+func init() { proto.RegisterFile("orderer/configuration.proto", fileDescriptor2) }
+
+var fileDescriptor2 = []byte{
+	// 571 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x93, 0xe1, 0x6e, 0xd3, 0x30,
+	0x10, 0xc7, 0xc9, 0xba, 0x6e, 0xeb, 0xad, 0x63, 0x9d, 0x37, 0x50, 0xb4, 0x21, 0x54, 0x2a, 0x21,
+	0x55, 0x68, 0x4a, 0xa1, 0x7c, 0x99, 0xf8, 0xd6, 0x56, 0x45, 0x42, 0xd0, 0x4e, 0x72, 0x8b, 0x90,
+	0xf8, 0x52, 0xb9, 0xc9, 0x35, 0xb1, 0x9a, 0xc4, 0x91, 0xed, 0x40, 0xb3, 0xf7, 0xe0, 0x11, 0x78,
+	0x3c, 0xde, 0x01, 0x39, 0x4e, 0xcb, 0x00, 0xed, 0xdb, 0xfd, 0xef, 0x7e, 0x3e, 0x9f, 0xed, 0xbf,
+	0xe1, 0x4a, 0xc8, 0x00, 0x25, 0xca, 0x9e, 0x2f, 0xd2, 0x15, 0x0f, 0x73, 0xc9, 0x34, 0x17, 0xa9,
+	0x97, 0x49, 0xa1, 0x05, 0x39, 0xac, 0x8a, 0x9d, 0x9f, 0x0e, 0x9c, 0x8c, 0x44, 0xaa, 0x30, 0x55,
+	0xb9, 0x9a, 0x17, 0x19, 0x12, 0x02, 0xfb, 0xba, 0xc8, 0xd0, 0x75, 0xda, 0x4e, 0xb7, 0x41, 0xcb,
+	0x98, 0x5c, 0xc2, 0x51, 0x82, 0x9a, 0x05, 0x4c, 0x33, 0x77, 0xaf, 0xed, 0x74, 0x9b, 0x74, 0xa7,
+	0x49, 0x1f, 0xea, 0x4a, 0x33, 0x8d, 0x6e, 0xad, 0xed, 0x74, 0x1f, 0xf7, 0x9f, 0x79, 0x55, 0x6b,
+	0xef, 0xaf, 0xb6, 0xde, 0xcc, 0x30, 0xd4, 0xa2, 0x9d, 0xd7, 0x50, 0x2f, 0x35, 0x69, 0x41, 0x73,
+	0x36, 0x1f, 0xcc, 0xc7, 0x8b, 0xe9, 0x2d, 0x9d, 0x0c, 0x3e, 0xb5, 0x1e, 0x91, 0x27, 0x70, 0x66,
+	0x33, 0x93, 0xc1, 0x87, 0xe9, 0x7c, 0x3c, 0x1d, 0x4c, 0x47, 0xe3, 0x96, 0xd3, 0xf9, 0xe1, 0x40,
+	0x63, 0xc8, 0xb4, 0x1f, 0xcd, 0xf8, 0x1d, 0x92, 0x57, 0x70, 0x96, 0xb0, 0xcd, 0x22, 0x41, 0xa5,
+	0x58, 0x88, 0x0b, 0x5f, 0xe4, 0xa9, 0x2e, 0x07, 0x3e, 0xa1, 0xa7, 0x09, 0xdb, 0x4c, 0x6c, 0x7e,
+	0x64, 0xd2, 0xe4, 0x1a, 0x08, 0x5b, 0x2a, 0x11, 0xe7, 0x1a, 0x17, 0x66, 0xd1, 0xb2, 0xd0, 0xa8,
+	0xca, 0x53, 0x9c, 0xd0, 0xd6, 0xb6, 0x32, 0x61, 0x9b, 0xa1, 0xc9, 0x13, 0x0f, 0xce, 0x33, 0x89,
+	0x2b, 0x94, 0x12, 0x83, 0x7b, 0x78, 0xad, 0xc4, 0xcf, 0x76, 0xa5, 0x2d, 0xdf, 0xe9, 0x42, 0xb3,
+	0x1c, 0x6b, 0xce, 0x13, 0x14, 0xb9, 0x26, 0x2e, 0x1c, 0x6a, 0x1b, 0x56, 0x17, 0xb8, 0x95, 0x86,
+	0xfc, 0xc8, 0x56, 0x6b, 0x36, 0x94, 0x62, 0x8d, 0x52, 0x19, 0x72, 0x69, 0x43, 0xd7, 0x69, 0xd7,
+	0x0c, 0x59, 0xc9, 0x4e, 0x1f, 0xce, 0x47, 0x11, 0x4b, 0x53, 0x8c, 0x29, 0x2a, 0x2d, 0xb9, 0x6f,
+	0x1e, 0x4e, 0x91, 0x2b, 0x68, 0x98, 0x81, 0xfe, 0x1c, 0x76, 0x9f, 0x1e, 0x25, 0x6c, 0x53, 0x9e,
+	0xb2, 0xf3, 0x6b, 0x0f, 0x4e, 0x87, 0xb1, 0xf0, 0xd7, 0x33, 0x3f, 0xc2, 0x20, 0x8f, 0x79, 0x1a,
+	0x92, 0x1b, 0x38, 0xf4, 0x63, 0xa6, 0x14, 0xda, 0x1d, 0x8e, 0xfb, 0xcf, 0x77, 0x6f, 0xf3, 0x0f,
+	0xea, 0x8d, 0x0c, 0x47, 0xb7, 0x38, 0x09, 0xe0, 0x42, 0xc8, 0x90, 0xa5, 0xfc, 0xae, 0x34, 0xcd,
+	0xe2, 0x3b, 0xf2, 0x30, 0xd2, 0xe6, 0xd6, 0x4c, 0x9b, 0x37, 0x0f, 0xb6, 0xb9, 0xbd, 0xb7, 0xe8,
+	0x8b, 0x5d, 0x33, 0x4e, 0xb5, 0x2c, 0xe8, 0xb9, 0xf8, 0xbf, 0x72, 0x29, 0xa1, 0x5e, 0xee, 0x6b,
+	0x2c, 0x97, 0xb2, 0x64, 0x67, 0x39, 0x13, 0x93, 0x17, 0xd0, 0x8c, 0x90, 0x05, 0x28, 0x17, 0xc6,
+	0x81, 0x76, 0xeb, 0x3a, 0x3d, 0xb6, 0x39, 0xe3, 0x28, 0x45, 0x9e, 0xc2, 0x41, 0x26, 0x62, 0xee,
+	0x17, 0xe5, 0xf3, 0x34, 0x68, 0xa5, 0x8c, 0x5b, 0x71, 0x93, 0x61, 0xc0, 0x35, 0xba, 0xfb, 0x6d,
+	0xa7, 0x7b, 0x44, 0x77, 0xfa, 0xf2, 0x3d, 0xb8, 0x0f, 0x0d, 0x49, 0x5a, 0x50, 0x5b, 0x63, 0x51,
+	0x4d, 0x61, 0x42, 0x72, 0x01, 0xf5, 0x6f, 0x2c, 0xce, 0xb1, 0xb2, 0x8b, 0x15, 0xef, 0xf6, 0x6e,
+	0x9c, 0xe1, 0x67, 0x78, 0x29, 0x64, 0xe8, 0x45, 0x45, 0x86, 0x32, 0xc6, 0x20, 0x44, 0xe9, 0xad,
+	0xd8, 0x52, 0x72, 0xdf, 0x7e, 0x30, 0xb5, 0xbd, 0xa2, 0xaf, 0xd7, 0x21, 0xd7, 0x51, 0xbe, 0xf4,
+	0x7c, 0x91, 0xf4, 0xee, 0xd1, 0x3d, 0x4b, 0xf7, 0x2c, 0xdd, 0xab, 0xe8, 0xe5, 0x41, 0xa9, 0xdf,
+	0xfe, 0x1e, 0x00, 0xda, 0x17, 0x21, 0xb7, 0xbd, 0x03, 0x00, 0x00,
 }
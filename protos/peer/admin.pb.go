@@ -33,13 +33,19 @@ It has these top-level messages:
 	GetState
 	PutState
 	DelState
+	GetStateMetadata
+	PutStateMetadata
+	StateMetadata
+	StateMetadataResult
 	GetStateByRange
 	GetQueryResult
+	QueryMetadata
 	GetHistoryForKey
 	QueryStateNext
 	QueryStateClose
 	QueryResultBytes
 	QueryResponse
+	QueryResponseMetadata
 	AnchorPeers
 	AnchorPeer
 	ChaincodeReg
@@ -40,6 +40,8 @@ const (
 	ChaincodeMessage_QUERY_STATE_CLOSE   ChaincodeMessage_Type = 17
 	ChaincodeMessage_KEEPALIVE           ChaincodeMessage_Type = 18
 	ChaincodeMessage_GET_HISTORY_FOR_KEY ChaincodeMessage_Type = 19
+	ChaincodeMessage_GET_STATE_METADATA  ChaincodeMessage_Type = 20
+	ChaincodeMessage_PUT_STATE_METADATA  ChaincodeMessage_Type = 21
 )
 
 var ChaincodeMessage_Type_name = map[int32]string{
@@ -62,6 +64,8 @@ var ChaincodeMessage_Type_name = map[int32]string{
 	17: "QUERY_STATE_CLOSE",
 	18: "KEEPALIVE",
 	19: "GET_HISTORY_FOR_KEY",
+	20: "GET_STATE_METADATA",
+	21: "PUT_STATE_METADATA",
 }
 var ChaincodeMessage_Type_value = map[string]int32{
 	"UNDEFINED":           0,
@@ -83,6 +87,8 @@ var ChaincodeMessage_Type_value = map[string]int32{
 	"QUERY_STATE_CLOSE":   17,
 	"KEEPALIVE":           18,
 	"GET_HISTORY_FOR_KEY": 19,
+	"GET_STATE_METADATA":  20,
+	"PUT_STATE_METADATA":  21,
 }
 
 func (x ChaincodeMessage_Type) String() string {
@@ -186,6 +192,7 @@ type PutState struct {
 	Key        string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
 	Value      []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
 	Collection string `protobuf:"bytes,3,opt,name=collection" json:"collection,omitempty"`
+	Metadata   []byte `protobuf:"bytes,4,opt,name=metadata,proto3" json:"metadata,omitempty"`
 }
 
 func (m *PutState) Reset()                    { *m = PutState{} }
@@ -238,16 +245,113 @@ func (m *DelState) GetCollection() string {
 	return ""
 }
 
+type GetStateMetadata struct {
+	Key        string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Collection string `protobuf:"bytes,2,opt,name=collection" json:"collection,omitempty"`
+}
+
+func (m *GetStateMetadata) Reset()                    { *m = GetStateMetadata{} }
+func (m *GetStateMetadata) String() string            { return proto.CompactTextString(m) }
+func (*GetStateMetadata) ProtoMessage()               {}
+func (*GetStateMetadata) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{4} }
+
+func (m *GetStateMetadata) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *GetStateMetadata) GetCollection() string {
+	if m != nil {
+		return m.Collection
+	}
+	return ""
+}
+
+type PutStateMetadata struct {
+	Key        string         `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
+	Collection string         `protobuf:"bytes,3,opt,name=collection" json:"collection,omitempty"`
+	Metadata   *StateMetadata `protobuf:"bytes,4,opt,name=metadata" json:"metadata,omitempty"`
+}
+
+func (m *PutStateMetadata) Reset()                    { *m = PutStateMetadata{} }
+func (m *PutStateMetadata) String() string            { return proto.CompactTextString(m) }
+func (*PutStateMetadata) ProtoMessage()               {}
+func (*PutStateMetadata) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{5} }
+
+func (m *PutStateMetadata) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *PutStateMetadata) GetCollection() string {
+	if m != nil {
+		return m.Collection
+	}
+	return ""
+}
+
+func (m *PutStateMetadata) GetMetadata() *StateMetadata {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+type StateMetadata struct {
+	Metakey string `protobuf:"bytes,1,opt,name=metakey" json:"metakey,omitempty"`
+	Value   []byte `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+}
+
+func (m *StateMetadata) Reset()                    { *m = StateMetadata{} }
+func (m *StateMetadata) String() string            { return proto.CompactTextString(m) }
+func (*StateMetadata) ProtoMessage()               {}
+func (*StateMetadata) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{6} }
+
+func (m *StateMetadata) GetMetakey() string {
+	if m != nil {
+		return m.Metakey
+	}
+	return ""
+}
+
+func (m *StateMetadata) GetValue() []byte {
+	if m != nil {
+		return m.Value
+	}
+	return nil
+}
+
+type StateMetadataResult struct {
+	Entries []*StateMetadata `protobuf:"bytes,1,rep,name=entries" json:"entries,omitempty"`
+}
+
+func (m *StateMetadataResult) Reset()                    { *m = StateMetadataResult{} }
+func (m *StateMetadataResult) String() string            { return proto.CompactTextString(m) }
+func (*StateMetadataResult) ProtoMessage()               {}
+func (*StateMetadataResult) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{7} }
+
+func (m *StateMetadataResult) GetEntries() []*StateMetadata {
+	if m != nil {
+		return m.Entries
+	}
+	return nil
+}
+
 type GetStateByRange struct {
 	StartKey   string `protobuf:"bytes,1,opt,name=startKey" json:"startKey,omitempty"`
 	EndKey     string `protobuf:"bytes,2,opt,name=endKey" json:"endKey,omitempty"`
 	Collection string `protobuf:"bytes,3,opt,name=collection" json:"collection,omitempty"`
+	Metadata   []byte `protobuf:"bytes,4,opt,name=metadata,proto3" json:"metadata,omitempty"`
 }
 
 func (m *GetStateByRange) Reset()                    { *m = GetStateByRange{} }
 func (m *GetStateByRange) String() string            { return proto.CompactTextString(m) }
 func (*GetStateByRange) ProtoMessage()               {}
-func (*GetStateByRange) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{4} }
+func (*GetStateByRange) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{8} }
 
 func (m *GetStateByRange) GetStartKey() string {
 	if m != nil {
@@ -270,15 +374,23 @@ func (m *GetStateByRange) GetCollection() string {
 	return ""
 }
 
+func (m *GetStateByRange) GetMetadata() []byte {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
 type GetQueryResult struct {
 	Query      string `protobuf:"bytes,1,opt,name=query" json:"query,omitempty"`
 	Collection string `protobuf:"bytes,2,opt,name=collection" json:"collection,omitempty"`
+	Metadata   []byte `protobuf:"bytes,3,opt,name=metadata,proto3" json:"metadata,omitempty"`
 }
 
 func (m *GetQueryResult) Reset()                    { *m = GetQueryResult{} }
 func (m *GetQueryResult) String() string            { return proto.CompactTextString(m) }
 func (*GetQueryResult) ProtoMessage()               {}
-func (*GetQueryResult) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{5} }
+func (*GetQueryResult) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{9} }
 
 func (m *GetQueryResult) GetQuery() string {
 	if m != nil {
@@ -294,6 +406,41 @@ func (m *GetQueryResult) GetCollection() string {
 	return ""
 }
 
+func (m *GetQueryResult) GetMetadata() []byte {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// QueryMetadata is the metadata of a GetStateByRange or GetQueryResult.
+// It is used to build a paginated query, where pageSize is the number of
+// records to be fetched and bookmark is the bookmark returned by the
+// previous page of the same query.
+type QueryMetadata struct {
+	PageSize int32  `protobuf:"varint,1,opt,name=pageSize" json:"pageSize,omitempty"`
+	Bookmark string `protobuf:"bytes,2,opt,name=bookmark" json:"bookmark,omitempty"`
+}
+
+func (m *QueryMetadata) Reset()                    { *m = QueryMetadata{} }
+func (m *QueryMetadata) String() string            { return proto.CompactTextString(m) }
+func (*QueryMetadata) ProtoMessage()               {}
+func (*QueryMetadata) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{10} }
+
+func (m *QueryMetadata) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *QueryMetadata) GetBookmark() string {
+	if m != nil {
+		return m.Bookmark
+	}
+	return ""
+}
+
 type GetHistoryForKey struct {
 	Key string `protobuf:"bytes,1,opt,name=key" json:"key,omitempty"`
 }
@@ -301,7 +448,7 @@ type GetHistoryForKey struct {
 func (m *GetHistoryForKey) Reset()                    { *m = GetHistoryForKey{} }
 func (m *GetHistoryForKey) String() string            { return proto.CompactTextString(m) }
 func (*GetHistoryForKey) ProtoMessage()               {}
-func (*GetHistoryForKey) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{6} }
+func (*GetHistoryForKey) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{11} }
 
 func (m *GetHistoryForKey) GetKey() string {
 	if m != nil {
@@ -317,7 +464,7 @@ type QueryStateNext struct {
 func (m *QueryStateNext) Reset()                    { *m = QueryStateNext{} }
 func (m *QueryStateNext) String() string            { return proto.CompactTextString(m) }
 func (*QueryStateNext) ProtoMessage()               {}
-func (*QueryStateNext) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{7} }
+func (*QueryStateNext) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{12} }
 
 func (m *QueryStateNext) GetId() string {
 	if m != nil {
@@ -333,7 +480,7 @@ type QueryStateClose struct {
 func (m *QueryStateClose) Reset()                    { *m = QueryStateClose{} }
 func (m *QueryStateClose) String() string            { return proto.CompactTextString(m) }
 func (*QueryStateClose) ProtoMessage()               {}
-func (*QueryStateClose) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{8} }
+func (*QueryStateClose) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{13} }
 
 func (m *QueryStateClose) GetId() string {
 	if m != nil {
@@ -349,7 +496,7 @@ type QueryResultBytes struct {
 func (m *QueryResultBytes) Reset()                    { *m = QueryResultBytes{} }
 func (m *QueryResultBytes) String() string            { return proto.CompactTextString(m) }
 func (*QueryResultBytes) ProtoMessage()               {}
-func (*QueryResultBytes) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{9} }
+func (*QueryResultBytes) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{14} }
 
 func (m *QueryResultBytes) GetResultBytes() []byte {
 	if m != nil {
@@ -359,15 +506,16 @@ func (m *QueryResultBytes) GetResultBytes() []byte {
 }
 
 type QueryResponse struct {
-	Results []*QueryResultBytes `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
-	HasMore bool                `protobuf:"varint,2,opt,name=has_more,json=hasMore" json:"has_more,omitempty"`
-	Id      string              `protobuf:"bytes,3,opt,name=id" json:"id,omitempty"`
+	Results  []*QueryResultBytes `protobuf:"bytes,1,rep,name=results" json:"results,omitempty"`
+	HasMore  bool                `protobuf:"varint,2,opt,name=has_more,json=hasMore" json:"has_more,omitempty"`
+	Id       string              `protobuf:"bytes,3,opt,name=id" json:"id,omitempty"`
+	Metadata []byte              `protobuf:"bytes,4,opt,name=metadata,proto3" json:"metadata,omitempty"`
 }
 
 func (m *QueryResponse) Reset()                    { *m = QueryResponse{} }
 func (m *QueryResponse) String() string            { return proto.CompactTextString(m) }
 func (*QueryResponse) ProtoMessage()               {}
-func (*QueryResponse) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{10} }
+func (*QueryResponse) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{15} }
 
 func (m *QueryResponse) GetResults() []*QueryResultBytes {
 	if m != nil {
@@ -390,18 +538,58 @@ func (m *QueryResponse) GetId() string {
 	return ""
 }
 
+func (m *QueryResponse) GetMetadata() []byte {
+	if m != nil {
+		return m.Metadata
+	}
+	return nil
+}
+
+// QueryResponseMetadata is the metadata of a QueryResponse. It contains the
+// count of records fetched from the ledger and the bookmark to be used for
+// fetching the next page of a paginated query.
+type QueryResponseMetadata struct {
+	FetchedRecordsCount int32  `protobuf:"varint,1,opt,name=fetched_records_count,json=fetchedRecordsCount" json:"fetched_records_count,omitempty"`
+	Bookmark            string `protobuf:"bytes,2,opt,name=bookmark" json:"bookmark,omitempty"`
+}
+
+func (m *QueryResponseMetadata) Reset()                    { *m = QueryResponseMetadata{} }
+func (m *QueryResponseMetadata) String() string            { return proto.CompactTextString(m) }
+func (*QueryResponseMetadata) ProtoMessage()               {}
+func (*QueryResponseMetadata) Descriptor() ([]byte, []int) { return fileDescriptor3, []int{16} }
+
+func (m *QueryResponseMetadata) GetFetchedRecordsCount() int32 {
+	if m != nil {
+		return m.FetchedRecordsCount
+	}
+	return 0
+}
+
+func (m *QueryResponseMetadata) GetBookmark() string {
+	if m != nil {
+		return m.Bookmark
+	}
+	return ""
+}
+
 func init() {
 	proto.RegisterType((*ChaincodeMessage)(nil), "protos.ChaincodeMessage")
 	proto.RegisterType((*GetState)(nil), "protos.GetState")
 	proto.RegisterType((*PutState)(nil), "protos.PutState")
 	proto.RegisterType((*DelState)(nil), "protos.DelState")
+	proto.RegisterType((*GetStateMetadata)(nil), "protos.GetStateMetadata")
+	proto.RegisterType((*PutStateMetadata)(nil), "protos.PutStateMetadata")
+	proto.RegisterType((*StateMetadata)(nil), "protos.StateMetadata")
+	proto.RegisterType((*StateMetadataResult)(nil), "protos.StateMetadataResult")
 	proto.RegisterType((*GetStateByRange)(nil), "protos.GetStateByRange")
 	proto.RegisterType((*GetQueryResult)(nil), "protos.GetQueryResult")
+	proto.RegisterType((*QueryMetadata)(nil), "protos.QueryMetadata")
 	proto.RegisterType((*GetHistoryForKey)(nil), "protos.GetHistoryForKey")
 	proto.RegisterType((*QueryStateNext)(nil), "protos.QueryStateNext")
 	proto.RegisterType((*QueryStateClose)(nil), "protos.QueryStateClose")
 	proto.RegisterType((*QueryResultBytes)(nil), "protos.QueryResultBytes")
 	proto.RegisterType((*QueryResponse)(nil), "protos.QueryResponse")
+	proto.RegisterType((*QueryResponseMetadata)(nil), "protos.QueryResponseMetadata")
 	proto.RegisterEnum("protos.ChaincodeMessage_Type", ChaincodeMessage_Type_name, ChaincodeMessage_Type_value)
 }
 
@@ -509,60 +697,168 @@ var _ChaincodeSupport_serviceDesc = grpc.ServiceDesc{
 	Metadata: "peer/chaincode_shim.proto",
 }
 
+// Client API for Chaincode service
+
+type ChaincodeClient interface {
+	Connect(ctx context.Context, opts ...grpc.CallOption) (Chaincode_ConnectClient, error)
+}
+
+type chaincodeClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewChaincodeClient(cc *grpc.ClientConn) ChaincodeClient {
+	return &chaincodeClient{cc}
+}
+
+func (c *chaincodeClient) Connect(ctx context.Context, opts ...grpc.CallOption) (Chaincode_ConnectClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Chaincode_serviceDesc.Streams[0], c.cc, "/protos.Chaincode/Connect", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &chaincodeConnectClient{stream}
+	return x, nil
+}
+
+type Chaincode_ConnectClient interface {
+	Send(*ChaincodeMessage) error
+	Recv() (*ChaincodeMessage, error)
+	grpc.ClientStream
+}
+
+type chaincodeConnectClient struct {
+	grpc.ClientStream
+}
+
+func (x *chaincodeConnectClient) Send(m *ChaincodeMessage) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *chaincodeConnectClient) Recv() (*ChaincodeMessage, error) {
+	m := new(ChaincodeMessage)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Server API for Chaincode service
+
+type ChaincodeServer interface {
+	Connect(Chaincode_ConnectServer) error
+}
+
+func RegisterChaincodeServer(s *grpc.Server, srv ChaincodeServer) {
+	s.RegisterService(&_Chaincode_serviceDesc, srv)
+}
+
+func _Chaincode_Connect_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(ChaincodeServer).Connect(&chaincodeConnectServer{stream})
+}
+
+type Chaincode_ConnectServer interface {
+	Send(*ChaincodeMessage) error
+	Recv() (*ChaincodeMessage, error)
+	grpc.ServerStream
+}
+
+type chaincodeConnectServer struct {
+	grpc.ServerStream
+}
+
+func (x *chaincodeConnectServer) Send(m *ChaincodeMessage) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *chaincodeConnectServer) Recv() (*ChaincodeMessage, error) {
+	m := new(ChaincodeMessage)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+var _Chaincode_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "protos.Chaincode",
+	HandlerType: (*ChaincodeServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Connect",
+			Handler:       _Chaincode_Connect_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "peer/chaincode_shim.proto",
+}
+
 func init() { proto.RegisterFile("peer/chaincode_shim.proto", fileDescriptor3) }
 
 var fileDescriptor3 = []byte{
-	// 825 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x95, 0x51, 0x6f, 0xe2, 0x46,
-	0x10, 0xc7, 0x8f, 0x00, 0xc1, 0x0c, 0x09, 0xec, 0x6d, 0xae, 0xa9, 0x0f, 0xe9, 0x5a, 0x8a, 0xfa,
-	0x40, 0x5f, 0xa0, 0xa5, 0x7d, 0xe8, 0xc3, 0x49, 0x15, 0xc1, 0x1b, 0x62, 0x85, 0xd8, 0xdc, 0xda,
-	0x39, 0x1d, 0x7d, 0xb1, 0x1c, 0xbc, 0x67, 0xac, 0x1a, 0xaf, 0x6b, 0x2f, 0xa7, 0xf3, 0x67, 0xe8,
-	0x07, 0xeb, 0xd7, 0xaa, 0xd6, 0xc6, 0x84, 0x23, 0x8a, 0x4e, 0xea, 0x13, 0xfe, 0xcf, 0xfc, 0x66,
-	0xe6, 0x3f, 0xd6, 0xb2, 0x86, 0xd7, 0x31, 0x63, 0xc9, 0x68, 0xb5, 0x76, 0x83, 0x68, 0xc5, 0x3d,
-	0xe6, 0xa4, 0xeb, 0x60, 0x33, 0x8c, 0x13, 0x2e, 0x38, 0x3e, 0xcd, 0x7f, 0xd2, 0x6e, 0xf7, 0x08,
-	0x61, 0x9f, 0x58, 0x24, 0x0a, 0xa6, 0x7b, 0x91, 0xe7, 0xe2, 0x84, 0xc7, 0x3c, 0x75, 0xc3, 0x5d,
-	0xf0, 0x7b, 0x9f, 0x73, 0x3f, 0x64, 0xa3, 0x5c, 0x3d, 0x6c, 0x3f, 0x8e, 0x44, 0xb0, 0x61, 0xa9,
-	0x70, 0x37, 0x71, 0x01, 0xf4, 0xff, 0xa9, 0x03, 0x9a, 0x96, 0xfd, 0xee, 0x58, 0x9a, 0xba, 0x3e,
-	0xc3, 0xbf, 0x40, 0x4d, 0x64, 0x31, 0x53, 0x2b, 0xbd, 0xca, 0xa0, 0x3d, 0x7e, 0x53, 0xa0, 0xe9,
-	0xf0, 0x98, 0x1b, 0xda, 0x59, 0xcc, 0x68, 0x8e, 0xe2, 0xdf, 0xa1, 0xb9, 0x6f, 0xad, 0x9e, 0xf4,
-	0x2a, 0x83, 0xd6, 0xb8, 0x3b, 0x2c, 0x86, 0x0f, 0xcb, 0xe1, 0x43, 0xbb, 0x24, 0xe8, 0x23, 0x8c,
-	0x55, 0x68, 0xc4, 0x6e, 0x16, 0x72, 0xd7, 0x53, 0xab, 0xbd, 0xca, 0xe0, 0x8c, 0x96, 0x12, 0x63,
-	0xa8, 0x89, 0xcf, 0x81, 0xa7, 0xd6, 0x7a, 0x95, 0x41, 0x93, 0xe6, 0xcf, 0x78, 0x0c, 0x4a, 0xb9,
-	0xa2, 0x5a, 0xcf, 0xc7, 0x5c, 0x96, 0xf6, 0xac, 0xc0, 0x8f, 0x98, 0xb7, 0xd8, 0x65, 0xe9, 0x9e,
-	0xc3, 0x7f, 0x40, 0xe7, 0xe8, 0x95, 0xa9, 0xa7, 0x5f, 0x96, 0xee, 0x37, 0x23, 0x32, 0x4b, 0xdb,
-	0xab, 0x2f, 0x34, 0x7e, 0x03, 0xb0, 0x5a, 0xbb, 0x51, 0xc4, 0x42, 0x27, 0xf0, 0xd4, 0x46, 0x6e,
-	0xa7, 0xb9, 0x8b, 0xe8, 0x5e, 0xff, 0xdf, 0x13, 0xa8, 0xc9, 0x57, 0x81, 0xcf, 0xa1, 0x79, 0x6f,
-	0x68, 0xe4, 0x5a, 0x37, 0x88, 0x86, 0x5e, 0xe0, 0x33, 0x50, 0x28, 0x99, 0xe9, 0x96, 0x4d, 0x28,
-	0xaa, 0xe0, 0x36, 0x40, 0xa9, 0x88, 0x86, 0x4e, 0xb0, 0x02, 0x35, 0xdd, 0xd0, 0x6d, 0x54, 0xc5,
-	0x4d, 0xa8, 0x53, 0x32, 0xd1, 0x96, 0xa8, 0x86, 0x3b, 0xd0, 0xb2, 0xe9, 0xc4, 0xb0, 0x26, 0x53,
-	0x5b, 0x37, 0x0d, 0x54, 0x97, 0x2d, 0xa7, 0xe6, 0xdd, 0x62, 0x4e, 0x6c, 0xa2, 0xa1, 0x53, 0x89,
-	0x12, 0x4a, 0x4d, 0x8a, 0x1a, 0x32, 0x33, 0x23, 0xb6, 0x63, 0xd9, 0x13, 0x9b, 0x20, 0x45, 0xca,
-	0xc5, 0x7d, 0x29, 0x9b, 0x52, 0x6a, 0x64, 0xbe, 0x93, 0x80, 0x5f, 0x01, 0xd2, 0x8d, 0xf7, 0xe6,
-	0x2d, 0x71, 0xa6, 0x37, 0x13, 0xdd, 0x98, 0x9a, 0x1a, 0x41, 0xad, 0xc2, 0xa0, 0xb5, 0x30, 0x0d,
-	0x8b, 0xa0, 0x73, 0x7c, 0x09, 0x78, 0xdf, 0xd0, 0xb9, 0x5a, 0x3a, 0x74, 0x62, 0xcc, 0x08, 0x6a,
-	0xcb, 0x5a, 0x19, 0x7f, 0x77, 0x4f, 0xe8, 0xd2, 0xa1, 0xc4, 0xba, 0x9f, 0xdb, 0xa8, 0x23, 0xa3,
-	0x45, 0xa4, 0xe0, 0x0d, 0xf2, 0xc1, 0x46, 0x08, 0x7f, 0x03, 0x2f, 0x0f, 0xa3, 0xd3, 0xb9, 0x69,
-	0x11, 0xf4, 0x52, 0xba, 0xb9, 0x25, 0x64, 0x31, 0x99, 0xeb, 0xef, 0x09, 0xc2, 0xf8, 0x5b, 0xb8,
-	0x90, 0x1d, 0x6f, 0x74, 0xcb, 0x36, 0xe9, 0xd2, 0xb9, 0x36, 0xa9, 0x73, 0x4b, 0x96, 0xe8, 0xa2,
-	0xff, 0x16, 0x94, 0x19, 0x13, 0x96, 0x70, 0x05, 0xc3, 0x08, 0xaa, 0x7f, 0xb1, 0x2c, 0x3f, 0x83,
-	0x4d, 0x2a, 0x1f, 0xf1, 0x77, 0x00, 0x2b, 0x1e, 0x86, 0x6c, 0x25, 0x02, 0x1e, 0xe5, 0x87, 0xac,
-	0x49, 0x0f, 0x22, 0x7d, 0x0a, 0xca, 0x62, 0xfb, 0x6c, 0xf5, 0x2b, 0xa8, 0x7f, 0x72, 0xc3, 0x2d,
-	0xcb, 0x0b, 0xcf, 0x68, 0x21, 0x8e, 0x7a, 0x56, 0x9f, 0xf4, 0x7c, 0x0b, 0x8a, 0xc6, 0xc2, 0xff,
-	0xeb, 0x88, 0x41, 0xa7, 0xdc, 0xe7, 0x2a, 0xa3, 0x6e, 0xe4, 0x33, 0xdc, 0x05, 0x25, 0x15, 0x6e,
-	0x22, 0x6e, 0xf7, 0x9d, 0xf6, 0x1a, 0x5f, 0xc2, 0x29, 0x8b, 0x3c, 0x99, 0x29, 0x5a, 0xed, 0xd4,
-	0x57, 0x4d, 0x5e, 0x43, 0x7b, 0xc6, 0xc4, 0xbb, 0x2d, 0x4b, 0x32, 0xca, 0xd2, 0x6d, 0x28, 0xe4,
-	0xb2, 0x7f, 0x4b, 0xb9, 0x1b, 0x51, 0x88, 0xaf, 0xda, 0xfd, 0x11, 0xd0, 0x8c, 0x89, 0x9b, 0x20,
-	0x15, 0x3c, 0xc9, 0xae, 0x79, 0x22, 0x67, 0x3f, 0x59, 0xba, 0xdf, 0x83, 0x76, 0x3e, 0x2a, 0x5f,
-	0xcb, 0x60, 0x9f, 0x05, 0x6e, 0xc3, 0x49, 0xe0, 0xed, 0x90, 0x93, 0xc0, 0xeb, 0xff, 0x00, 0x9d,
-	0x47, 0x62, 0x1a, 0xf2, 0x94, 0x3d, 0x41, 0x7e, 0x03, 0x74, 0xe0, 0xf7, 0x2a, 0x13, 0x2c, 0xc5,
-	0x3d, 0x68, 0x25, 0x8f, 0x32, 0x87, 0xcf, 0xe8, 0x61, 0xa8, 0x1f, 0xc1, 0x79, 0x59, 0x15, 0xf3,
-	0x28, 0x65, 0x78, 0x0c, 0x8d, 0x22, 0x2f, 0xf1, 0xea, 0xa0, 0x35, 0x56, 0xcb, 0xbf, 0xf4, 0x71,
-	0x77, 0x5a, 0x82, 0xf8, 0x35, 0x28, 0x6b, 0x37, 0x75, 0x36, 0x3c, 0x29, 0xce, 0x82, 0x42, 0x1b,
-	0x6b, 0x37, 0xbd, 0xe3, 0x49, 0xe9, 0xb2, 0x5a, 0xba, 0x1c, 0x7f, 0x38, 0xb8, 0x1c, 0xad, 0x6d,
-	0x1c, 0xf3, 0x44, 0x60, 0x0d, 0x14, 0xca, 0xfc, 0x20, 0x15, 0x2c, 0xc1, 0xea, 0x73, 0x57, 0x63,
-	0xf7, 0xd9, 0x4c, 0xff, 0xc5, 0xa0, 0xf2, 0x73, 0xe5, 0xca, 0x84, 0x3e, 0x4f, 0xfc, 0xe1, 0x3a,
-	0x8b, 0x59, 0x12, 0x32, 0xcf, 0x67, 0xc9, 0xf0, 0xa3, 0xfb, 0x90, 0x04, 0xab, 0xb2, 0x4e, 0xde,
-	0xe6, 0x7f, 0xfe, 0xe4, 0x07, 0x62, 0xbd, 0x7d, 0x18, 0xae, 0xf8, 0x66, 0x74, 0x80, 0x8e, 0x0a,
-	0xb4, 0xb8, 0xd5, 0xd3, 0x91, 0x44, 0x1f, 0x8a, 0x4f, 0xc4, 0xaf, 0xff, 0x05, 0x00, 0x00, 0xff,
-	0xff, 0x18, 0x6a, 0x79, 0xe7, 0x46, 0x06, 0x00, 0x00,
+	// 1024 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x56, 0xcf, 0x73, 0xda, 0xc6,
+	0x17, 0x0f, 0x06, 0x8c, 0x78, 0xb6, 0xf1, 0x66, 0x6d, 0xe7, 0xab, 0x30, 0x93, 0x6f, 0xa9, 0xa6,
+	0x07, 0xf7, 0x02, 0x0d, 0xed, 0xa1, 0x87, 0xcc, 0x64, 0x30, 0xac, 0x09, 0x63, 0x1b, 0xc8, 0x4a,
+	0xce, 0xc4, 0xbd, 0x68, 0x84, 0xb4, 0x16, 0x1a, 0x0b, 0xad, 0x2a, 0x2d, 0x69, 0xe8, 0xad, 0xd7,
+	0xfe, 0x4b, 0xfd, 0xc3, 0x7a, 0xed, 0xac, 0x7e, 0x19, 0x70, 0x9d, 0x4c, 0x7d, 0x42, 0x9f, 0xf7,
+	0x3e, 0xef, 0xf3, 0x7e, 0xec, 0x13, 0x5a, 0x78, 0x19, 0x32, 0x16, 0x75, 0xec, 0xb9, 0xe5, 0x05,
+	0x36, 0x77, 0x98, 0x19, 0xcf, 0xbd, 0x45, 0x3b, 0x8c, 0xb8, 0xe0, 0x78, 0x37, 0xf9, 0x89, 0x9b,
+	0xcd, 0x2d, 0x0a, 0xfb, 0xc4, 0x02, 0x91, 0x72, 0x9a, 0x47, 0x89, 0x2f, 0x8c, 0x78, 0xc8, 0x63,
+	0xcb, 0xcf, 0x8c, 0xdf, 0xb8, 0x9c, 0xbb, 0x3e, 0xeb, 0x24, 0x68, 0xb6, 0xbc, 0xed, 0x08, 0x6f,
+	0xc1, 0x62, 0x61, 0x2d, 0xc2, 0x94, 0xa0, 0xfd, 0x55, 0x05, 0xd4, 0xcf, 0xf5, 0xae, 0x58, 0x1c,
+	0x5b, 0x2e, 0xc3, 0xaf, 0xa1, 0x22, 0x56, 0x21, 0x53, 0x4b, 0xad, 0xd2, 0x69, 0xa3, 0xfb, 0x2a,
+	0xa5, 0xc6, 0xed, 0x6d, 0x5e, 0xdb, 0x58, 0x85, 0x8c, 0x26, 0x54, 0xfc, 0x33, 0xd4, 0x0b, 0x69,
+	0x75, 0xa7, 0x55, 0x3a, 0xdd, 0xeb, 0x36, 0xdb, 0x69, 0xf2, 0x76, 0x9e, 0xbc, 0x6d, 0xe4, 0x0c,
+	0x7a, 0x4f, 0xc6, 0x2a, 0xd4, 0x42, 0x6b, 0xe5, 0x73, 0xcb, 0x51, 0xcb, 0xad, 0xd2, 0xe9, 0x3e,
+	0xcd, 0x21, 0xc6, 0x50, 0x11, 0x9f, 0x3d, 0x47, 0xad, 0xb4, 0x4a, 0xa7, 0x75, 0x9a, 0x3c, 0xe3,
+	0x2e, 0x28, 0x79, 0x8b, 0x6a, 0x35, 0x49, 0xf3, 0x22, 0x2f, 0x4f, 0xf7, 0xdc, 0x80, 0x39, 0xd3,
+	0xcc, 0x4b, 0x0b, 0x1e, 0x7e, 0x0b, 0x87, 0x5b, 0x23, 0x53, 0x77, 0x37, 0x43, 0x8b, 0xce, 0x88,
+	0xf4, 0xd2, 0x86, 0xbd, 0x81, 0xf1, 0x2b, 0x00, 0x7b, 0x6e, 0x05, 0x01, 0xf3, 0x4d, 0xcf, 0x51,
+	0x6b, 0x49, 0x39, 0xf5, 0xcc, 0x32, 0x72, 0xb4, 0xbf, 0x77, 0xa0, 0x22, 0x47, 0x81, 0x0f, 0xa0,
+	0x7e, 0x3d, 0x1e, 0x90, 0xf3, 0xd1, 0x98, 0x0c, 0xd0, 0x33, 0xbc, 0x0f, 0x0a, 0x25, 0xc3, 0x91,
+	0x6e, 0x10, 0x8a, 0x4a, 0xb8, 0x01, 0x90, 0x23, 0x32, 0x40, 0x3b, 0x58, 0x81, 0xca, 0x68, 0x3c,
+	0x32, 0x50, 0x19, 0xd7, 0xa1, 0x4a, 0x49, 0x6f, 0x70, 0x83, 0x2a, 0xf8, 0x10, 0xf6, 0x0c, 0xda,
+	0x1b, 0xeb, 0xbd, 0xbe, 0x31, 0x9a, 0x8c, 0x51, 0x55, 0x4a, 0xf6, 0x27, 0x57, 0xd3, 0x4b, 0x62,
+	0x90, 0x01, 0xda, 0x95, 0x54, 0x42, 0xe9, 0x84, 0xa2, 0x9a, 0xf4, 0x0c, 0x89, 0x61, 0xea, 0x46,
+	0xcf, 0x20, 0x48, 0x91, 0x70, 0x7a, 0x9d, 0xc3, 0xba, 0x84, 0x03, 0x72, 0x99, 0x41, 0xc0, 0xc7,
+	0x80, 0x46, 0xe3, 0x0f, 0x93, 0x0b, 0x62, 0xf6, 0xdf, 0xf5, 0x46, 0xe3, 0xfe, 0x64, 0x40, 0xd0,
+	0x5e, 0x5a, 0xa0, 0x3e, 0x9d, 0x8c, 0x75, 0x82, 0x0e, 0xf0, 0x0b, 0xc0, 0x85, 0xa0, 0x79, 0x76,
+	0x63, 0xd2, 0xde, 0x78, 0x48, 0x50, 0x43, 0xc6, 0x4a, 0xfb, 0xfb, 0x6b, 0x42, 0x6f, 0x4c, 0x4a,
+	0xf4, 0xeb, 0x4b, 0x03, 0x1d, 0x4a, 0x6b, 0x6a, 0x49, 0xf9, 0x63, 0xf2, 0xd1, 0x40, 0x08, 0x9f,
+	0xc0, 0xf3, 0x75, 0x6b, 0xff, 0x72, 0xa2, 0x13, 0xf4, 0x5c, 0x56, 0x73, 0x41, 0xc8, 0xb4, 0x77,
+	0x39, 0xfa, 0x40, 0x10, 0xc6, 0xff, 0x83, 0x23, 0xa9, 0xf8, 0x6e, 0xa4, 0x1b, 0x13, 0x7a, 0x63,
+	0x9e, 0x4f, 0xa8, 0x79, 0x41, 0x6e, 0xd0, 0xd1, 0x66, 0x09, 0x57, 0xc4, 0xe8, 0x0d, 0x7a, 0x46,
+	0x0f, 0x1d, 0x4b, 0xfb, 0xf4, 0xfa, 0x81, 0xfd, 0x44, 0x7b, 0x03, 0xca, 0x90, 0x09, 0x5d, 0x58,
+	0x82, 0x61, 0x04, 0xe5, 0x3b, 0xb6, 0x4a, 0x76, 0xb6, 0x4e, 0xe5, 0x23, 0xfe, 0x3f, 0x80, 0xcd,
+	0x7d, 0x9f, 0xd9, 0xc2, 0xe3, 0x41, 0xb2, 0x94, 0x75, 0xba, 0x66, 0xd1, 0x28, 0x28, 0xd3, 0xe5,
+	0xa3, 0xd1, 0xc7, 0x50, 0xfd, 0x64, 0xf9, 0x4b, 0x96, 0x04, 0xee, 0xd3, 0x14, 0x6c, 0x69, 0x96,
+	0x1f, 0x68, 0xbe, 0x01, 0x65, 0xc0, 0xfc, 0xa7, 0x56, 0x34, 0x00, 0x94, 0xf7, 0x73, 0xc5, 0x84,
+	0xe5, 0x58, 0xc2, 0x7a, 0x82, 0xca, 0x6f, 0x80, 0xa6, 0xcb, 0xff, 0xa8, 0xf2, 0xa0, 0x13, 0xfc,
+	0x1a, 0x94, 0x45, 0x16, 0x9d, 0xbc, 0x81, 0x7b, 0xdd, 0x93, 0xe2, 0x4d, 0x5b, 0x97, 0xa6, 0x05,
+	0x4d, 0x7b, 0x0b, 0x07, 0x9b, 0x59, 0x55, 0xa8, 0x49, 0xe7, 0x7d, 0xe6, 0x1c, 0xfe, 0xfb, 0x74,
+	0xb5, 0x73, 0x38, 0xda, 0xd4, 0x66, 0xf1, 0xd2, 0x17, 0xb8, 0x03, 0x35, 0x16, 0x88, 0xc8, 0x63,
+	0xb1, 0x5a, 0x6a, 0x95, 0x1f, 0xaf, 0x24, 0x67, 0x69, 0x7f, 0x94, 0xe0, 0x30, 0x1f, 0xe4, 0xd9,
+	0x8a, 0x5a, 0x81, 0xcb, 0x70, 0x13, 0x94, 0x58, 0x58, 0x91, 0xb8, 0x28, 0x8a, 0x29, 0x30, 0x7e,
+	0x01, 0xbb, 0x2c, 0x70, 0xa4, 0x27, 0x9d, 0x66, 0x86, 0xbe, 0x3a, 0xa3, 0xe6, 0xd6, 0x8c, 0xf6,
+	0xd7, 0x86, 0x31, 0x83, 0xc6, 0x90, 0x89, 0xf7, 0x4b, 0x16, 0xad, 0xb2, 0x36, 0x8e, 0xa1, 0xfa,
+	0xab, 0x84, 0x59, 0xfa, 0x14, 0x7c, 0xed, 0x34, 0x37, 0x72, 0x94, 0xb7, 0x72, 0x0c, 0xe1, 0x20,
+	0x49, 0x50, 0x0c, 0xbc, 0x09, 0x4a, 0x68, 0xb9, 0x4c, 0xf7, 0x7e, 0x4f, 0xff, 0xbd, 0xab, 0xb4,
+	0xc0, 0xd2, 0x37, 0xe3, 0xfc, 0x6e, 0x61, 0x45, 0x77, 0x59, 0x9a, 0x02, 0x6b, 0xdf, 0x25, 0x8b,
+	0xf7, 0xce, 0x8b, 0x05, 0x8f, 0x56, 0xe7, 0x3c, 0x92, 0xcd, 0x3f, 0x58, 0x19, 0xad, 0x05, 0x8d,
+	0x24, 0x5d, 0x32, 0xd7, 0x31, 0xfb, 0x2c, 0x70, 0x03, 0x76, 0x3c, 0x27, 0xa3, 0xec, 0x78, 0x8e,
+	0xf6, 0x2d, 0x1c, 0xde, 0x33, 0xfa, 0x3e, 0x8f, 0xd9, 0x03, 0xca, 0x4f, 0x80, 0xd6, 0x86, 0x72,
+	0xb6, 0x12, 0x2c, 0xc6, 0x2d, 0xd8, 0x8b, 0xee, 0x61, 0x42, 0xde, 0xa7, 0xeb, 0x26, 0xed, 0xcf,
+	0x52, 0xd6, 0x2a, 0x65, 0x71, 0xc8, 0x83, 0x98, 0xe1, 0x2e, 0xd4, 0x52, 0x42, 0xbe, 0x14, 0x6a,
+	0xbe, 0x14, 0xdb, 0xf2, 0x34, 0x27, 0xe2, 0x97, 0xa0, 0xcc, 0xad, 0xd8, 0x5c, 0xf0, 0x28, 0x5d,
+	0x3c, 0x85, 0xd6, 0xe6, 0x56, 0x7c, 0xc5, 0xa3, 0xbc, 0xcc, 0x72, 0x5e, 0xe6, 0x17, 0x8f, 0xd6,
+	0x85, 0x93, 0x8d, 0x5a, 0x8a, 0xf1, 0x77, 0xe1, 0xe4, 0x96, 0x09, 0x7b, 0xce, 0x1c, 0x33, 0x62,
+	0x36, 0x8f, 0x9c, 0xd8, 0xb4, 0xf9, 0x32, 0x10, 0xd9, 0x59, 0x1c, 0x65, 0x4e, 0x9a, 0xfa, 0xfa,
+	0xd2, 0xf5, 0xa5, 0x63, 0xe9, 0x7e, 0x5c, 0xfb, 0x38, 0xeb, 0xcb, 0x30, 0xe4, 0x91, 0xc0, 0x03,
+	0x50, 0x28, 0x73, 0xbd, 0x58, 0xb0, 0x08, 0xab, 0x8f, 0x7d, 0x9a, 0x9b, 0x8f, 0x7a, 0xb4, 0x67,
+	0xa7, 0xa5, 0x1f, 0x4a, 0xdd, 0x29, 0xd4, 0x0b, 0x0f, 0xee, 0x43, 0xad, 0xcf, 0x83, 0x80, 0xd9,
+	0xe2, 0xe9, 0x8a, 0x67, 0x13, 0xd0, 0x78, 0xe4, 0xb6, 0xe7, 0xab, 0x90, 0x45, 0x3e, 0x73, 0x5c,
+	0x16, 0xb5, 0x6f, 0xad, 0x59, 0xe4, 0xd9, 0x79, 0x9c, 0xbc, 0x9f, 0xfc, 0xf2, 0xbd, 0xeb, 0x89,
+	0xf9, 0x72, 0xd6, 0xb6, 0xf9, 0xa2, 0xb3, 0x46, 0xed, 0xa4, 0xd4, 0xf4, 0x9e, 0x12, 0x77, 0x24,
+	0x75, 0x96, 0x5e, 0x7a, 0x7e, 0xfc, 0x67, 0x00, 0x69, 0x9e, 0x3a, 0xcc, 0x18, 0x09, 0x00, 0x00,
 }
@@ -8,6 +8,7 @@ import fmt "fmt"
 import math "math"
 import common "github.com/hyperledger/fabric/protos/common"
 import google_protobuf1 "github.com/golang/protobuf/ptypes/timestamp"
+import rwset "github.com/hyperledger/fabric/protos/ledger/rwset"
 
 import (
 	context "golang.org/x/net/context"
@@ -743,6 +744,7 @@ type DeliverResponse struct {
 	//	*DeliverResponse_Status
 	//	*DeliverResponse_Block
 	//	*DeliverResponse_FilteredBlock
+	//	*DeliverResponse_BlockAndPrivateData
 	Type isDeliverResponse_Type `protobuf_oneof:"Type"`
 }
 
@@ -764,10 +766,14 @@ type DeliverResponse_Block struct {
 type DeliverResponse_FilteredBlock struct {
 	FilteredBlock *FilteredBlock `protobuf:"bytes,3,opt,name=filtered_block,json=filteredBlock,oneof"`
 }
+type DeliverResponse_BlockAndPrivateData struct {
+	BlockAndPrivateData *BlockAndPrivateData `protobuf:"bytes,4,opt,name=block_and_private_data,json=blockAndPrivateData,oneof"`
+}
 
-func (*DeliverResponse_Status) isDeliverResponse_Type()        {}
-func (*DeliverResponse_Block) isDeliverResponse_Type()         {}
-func (*DeliverResponse_FilteredBlock) isDeliverResponse_Type() {}
+func (*DeliverResponse_Status) isDeliverResponse_Type()              {}
+func (*DeliverResponse_Block) isDeliverResponse_Type()               {}
+func (*DeliverResponse_FilteredBlock) isDeliverResponse_Type()       {}
+func (*DeliverResponse_BlockAndPrivateData) isDeliverResponse_Type() {}
 
 func (m *DeliverResponse) GetType() isDeliverResponse_Type {
 	if m != nil {
@@ -797,12 +803,20 @@ func (m *DeliverResponse) GetFilteredBlock() *FilteredBlock {
 	return nil
 }
 
+func (m *DeliverResponse) GetBlockAndPrivateData() *BlockAndPrivateData {
+	if x, ok := m.GetType().(*DeliverResponse_BlockAndPrivateData); ok {
+		return x.BlockAndPrivateData
+	}
+	return nil
+}
+
 // XXX_OneofFuncs is for the internal use of the proto package.
 func (*DeliverResponse) XXX_OneofFuncs() (func(msg proto.Message, b *proto.Buffer) error, func(msg proto.Message, tag, wire int, b *proto.Buffer) (bool, error), func(msg proto.Message) (n int), []interface{}) {
 	return _DeliverResponse_OneofMarshaler, _DeliverResponse_OneofUnmarshaler, _DeliverResponse_OneofSizer, []interface{}{
 		(*DeliverResponse_Status)(nil),
 		(*DeliverResponse_Block)(nil),
 		(*DeliverResponse_FilteredBlock)(nil),
+		(*DeliverResponse_BlockAndPrivateData)(nil),
 	}
 }
 
@@ -823,6 +837,11 @@ func _DeliverResponse_OneofMarshaler(msg proto.Message, b *proto.Buffer) error {
 		if err := b.EncodeMessage(x.FilteredBlock); err != nil {
 			return err
 		}
+	case *DeliverResponse_BlockAndPrivateData:
+		b.EncodeVarint(4<<3 | proto.WireBytes)
+		if err := b.EncodeMessage(x.BlockAndPrivateData); err != nil {
+			return err
+		}
 	case nil:
 	default:
 		return fmt.Errorf("DeliverResponse.Type has unexpected type %T", x)
@@ -856,6 +875,14 @@ func _DeliverResponse_OneofUnmarshaler(msg proto.Message, tag, wire int, b *prot
 		err := b.DecodeMessage(msg)
 		m.Type = &DeliverResponse_FilteredBlock{msg}
 		return true, err
+	case 4: // Type.block_and_private_data
+		if wire != proto.WireBytes {
+			return true, proto.ErrInternalBadWireType
+		}
+		msg := new(BlockAndPrivateData)
+		err := b.DecodeMessage(msg)
+		m.Type = &DeliverResponse_BlockAndPrivateData{msg}
+		return true, err
 	default:
 		return false, nil
 	}
@@ -878,6 +905,11 @@ func _DeliverResponse_OneofSizer(msg proto.Message) (n int) {
 		n += proto.SizeVarint(3<<3 | proto.WireBytes)
 		n += proto.SizeVarint(uint64(s))
 		n += s
+	case *DeliverResponse_BlockAndPrivateData:
+		s := proto.Size(x.BlockAndPrivateData)
+		n += proto.SizeVarint(4<<3 | proto.WireBytes)
+		n += proto.SizeVarint(uint64(s))
+		n += s
 	case nil:
 	default:
 		panic(fmt.Sprintf("proto: unexpected type %T in oneof", x))
@@ -885,6 +917,33 @@ func _DeliverResponse_OneofSizer(msg proto.Message) (n int) {
 	return n
 }
 
+// BlockAndPrivateData contains a block and the private data of its
+// transactions which the requesting client is eligible to receive
+type BlockAndPrivateData struct {
+	Block *common.Block `protobuf:"bytes,1,opt,name=block" json:"block,omitempty"`
+	// map from the index of a transaction in the block to its private data
+	PrivateDataMap map[uint64]*rwset.TxPvtReadWriteSet `protobuf:"bytes,2,rep,name=private_data_map,json=privateDataMap" json:"private_data_map,omitempty" protobuf_key:"varint,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (m *BlockAndPrivateData) Reset()                    { *m = BlockAndPrivateData{} }
+func (m *BlockAndPrivateData) String() string            { return proto.CompactTextString(m) }
+func (*BlockAndPrivateData) ProtoMessage()               {}
+func (*BlockAndPrivateData) Descriptor() ([]byte, []int) { return fileDescriptor5, []int{12} }
+
+func (m *BlockAndPrivateData) GetBlock() *common.Block {
+	if m != nil {
+		return m.Block
+	}
+	return nil
+}
+
+func (m *BlockAndPrivateData) GetPrivateDataMap() map[uint64]*rwset.TxPvtReadWriteSet {
+	if m != nil {
+		return m.PrivateDataMap
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*ChaincodeReg)(nil), "protos.ChaincodeReg")
 	proto.RegisterType((*Interest)(nil), "protos.Interest")
@@ -898,6 +957,7 @@ func init() {
 	proto.RegisterType((*SignedEvent)(nil), "protos.SignedEvent")
 	proto.RegisterType((*Event)(nil), "protos.Event")
 	proto.RegisterType((*DeliverResponse)(nil), "protos.DeliverResponse")
+	proto.RegisterType((*BlockAndPrivateData)(nil), "protos.BlockAndPrivateData")
 	proto.RegisterEnum("protos.EventType", EventType_name, EventType_value)
 }
 
@@ -1016,6 +1076,9 @@ type DeliverClient interface {
 	// deliver first requires an Envelope of type ab.DELIVER_SEEK_INFO with Payload data as a marshaled orderer.SeekInfo message,
 	// then a stream of **filtered** block replies is received.
 	DeliverFiltered(ctx context.Context, opts ...grpc.CallOption) (Deliver_DeliverFilteredClient, error)
+	// deliver first requires an Envelope of type ab.DELIVER_SEEK_INFO with Payload data as a marshaled orderer.SeekInfo message,
+	// then a stream of block and private data replies is received.
+	DeliverWithPrivateData(ctx context.Context, opts ...grpc.CallOption) (Deliver_DeliverWithPrivateDataClient, error)
 }
 
 type deliverClient struct {
@@ -1088,6 +1151,37 @@ func (x *deliverDeliverFilteredClient) Recv() (*DeliverResponse, error) {
 	return m, nil
 }
 
+func (c *deliverClient) DeliverWithPrivateData(ctx context.Context, opts ...grpc.CallOption) (Deliver_DeliverWithPrivateDataClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_Deliver_serviceDesc.Streams[2], c.cc, "/protos.Deliver/DeliverWithPrivateData", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &deliverDeliverWithPrivateDataClient{stream}
+	return x, nil
+}
+
+type Deliver_DeliverWithPrivateDataClient interface {
+	Send(*common.Envelope) error
+	Recv() (*DeliverResponse, error)
+	grpc.ClientStream
+}
+
+type deliverDeliverWithPrivateDataClient struct {
+	grpc.ClientStream
+}
+
+func (x *deliverDeliverWithPrivateDataClient) Send(m *common.Envelope) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *deliverDeliverWithPrivateDataClient) Recv() (*DeliverResponse, error) {
+	m := new(DeliverResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for Deliver service
 
 type DeliverServer interface {
@@ -1097,6 +1191,9 @@ type DeliverServer interface {
 	// deliver first requires an Envelope of type ab.DELIVER_SEEK_INFO with Payload data as a marshaled orderer.SeekInfo message,
 	// then a stream of **filtered** block replies is received.
 	DeliverFiltered(Deliver_DeliverFilteredServer) error
+	// deliver first requires an Envelope of type ab.DELIVER_SEEK_INFO with Payload data as a marshaled orderer.SeekInfo message,
+	// then a stream of block and private data replies is received.
+	DeliverWithPrivateData(Deliver_DeliverWithPrivateDataServer) error
 }
 
 func RegisterDeliverServer(s *grpc.Server, srv DeliverServer) {
@@ -1155,6 +1252,32 @@ func (x *deliverDeliverFilteredServer) Recv() (*common.Envelope, error) {
 	return m, nil
 }
 
+func _Deliver_DeliverWithPrivateData_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(DeliverServer).DeliverWithPrivateData(&deliverDeliverWithPrivateDataServer{stream})
+}
+
+type Deliver_DeliverWithPrivateDataServer interface {
+	Send(*DeliverResponse) error
+	Recv() (*common.Envelope, error)
+	grpc.ServerStream
+}
+
+type deliverDeliverWithPrivateDataServer struct {
+	grpc.ServerStream
+}
+
+func (x *deliverDeliverWithPrivateDataServer) Send(m *DeliverResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *deliverDeliverWithPrivateDataServer) Recv() (*common.Envelope, error) {
+	m := new(common.Envelope)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 var _Deliver_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "protos.Deliver",
 	HandlerType: (*DeliverServer)(nil),
@@ -1172,6 +1295,12 @@ var _Deliver_serviceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "DeliverWithPrivateData",
+			Handler:       _Deliver_DeliverWithPrivateData_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
 	},
 	Metadata: "peer/events.proto",
 }
@@ -1179,69 +1308,78 @@ var _Deliver_serviceDesc = grpc.ServiceDesc{
 func init() { proto.RegisterFile("peer/events.proto", fileDescriptor5) }
 
 var fileDescriptor5 = []byte{
-	// 1009 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x56, 0xdd, 0x72, 0xdb, 0x44,
-	0x14, 0xb6, 0x62, 0xc7, 0xb1, 0x8e, 0xe3, 0xd4, 0xd9, 0xb4, 0xa9, 0xc6, 0x05, 0x5a, 0xc4, 0xc0,
-	0x04, 0x2e, 0xec, 0x60, 0x3a, 0x0c, 0xd3, 0x0b, 0x98, 0xf8, 0x27, 0xc8, 0x34, 0x4d, 0x32, 0x1b,
-	0x87, 0x8b, 0x5e, 0xa0, 0x59, 0xcb, 0x6b, 0x59, 0xad, 0x2c, 0x79, 0x76, 0xd7, 0x99, 0xe4, 0x11,
-	0x78, 0x03, 0xde, 0x80, 0x19, 0x5e, 0x85, 0x17, 0xe2, 0x92, 0xd1, 0x6a, 0x57, 0x52, 0x6c, 0xda,
-	0x21, 0x57, 0xf6, 0xf9, 0xfb, 0xf6, 0xfc, 0x7c, 0x67, 0x57, 0xb0, 0xbf, 0xa4, 0x94, 0x75, 0xe8,
-	0x0d, 0x8d, 0x04, 0x6f, 0x2f, 0x59, 0x2c, 0x62, 0x54, 0x95, 0x3f, 0xbc, 0x75, 0xe0, 0xc5, 0x8b,
-	0x45, 0x1c, 0x75, 0xd2, 0x9f, 0xd4, 0xd8, 0x7a, 0xee, 0xc7, 0xb1, 0x1f, 0xd2, 0x8e, 0x94, 0x26,
-	0xab, 0x59, 0x47, 0x04, 0x0b, 0xca, 0x05, 0x59, 0x2c, 0x95, 0x43, 0x4b, 0x02, 0x7a, 0x73, 0x12,
-	0x44, 0x5e, 0x3c, 0xa5, 0xae, 0x84, 0x56, 0xb6, 0x43, 0x69, 0x13, 0x8c, 0x44, 0x9c, 0x78, 0x22,
-	0xd0, 0xa0, 0xf6, 0x25, 0xec, 0xf6, 0x75, 0x00, 0xa6, 0x3e, 0xfa, 0x1c, 0x76, 0x73, 0x80, 0x60,
-	0x6a, 0x19, 0x2f, 0x8c, 0x23, 0x13, 0xd7, 0x33, 0xdd, 0x68, 0x8a, 0x3e, 0x05, 0x90, 0xc8, 0x6e,
-	0x44, 0x16, 0xd4, 0xda, 0x92, 0x0e, 0xa6, 0xd4, 0x9c, 0x93, 0x05, 0xb5, 0xff, 0x34, 0xa0, 0x36,
-	0x8a, 0x04, 0x65, 0x94, 0x0b, 0x74, 0xac, 0x7d, 0xc5, 0xdd, 0x92, 0x4a, 0xb0, 0xbd, 0xee, 0x7e,
-	0x7a, 0x34, 0x6f, 0x0f, 0x13, 0xcb, 0xf8, 0x6e, 0x49, 0x55, 0x78, 0xf2, 0x17, 0x0d, 0x00, 0xe5,
-	0x09, 0x30, 0xea, 0xbb, 0x41, 0x34, 0x8b, 0xe5, 0x29, 0xf5, 0xee, 0x63, 0x1d, 0x59, 0x4c, 0xd9,
-	0x29, 0xe1, 0xa6, 0x57, 0x90, 0x47, 0xd1, 0x2c, 0x46, 0x16, 0xec, 0x48, 0xdd, 0x68, 0x60, 0x95,
-	0x65, 0x82, 0x5a, 0xec, 0x99, 0xb0, 0xa3, 0x9c, 0xec, 0x97, 0x50, 0xc3, 0xd4, 0x0f, 0xb8, 0xa0,
-	0x0c, 0x1d, 0x41, 0x35, 0x9d, 0x84, 0x65, 0xbc, 0x28, 0x1f, 0xd5, 0xbb, 0x4d, 0x7d, 0x94, 0x2e,
-	0x05, 0x2b, 0xbb, 0xfd, 0x06, 0x4c, 0x4c, 0xdf, 0x51, 0xd9, 0x44, 0xf4, 0x05, 0x6c, 0x89, 0x5b,
-	0x59, 0x57, 0xbd, 0x7b, 0xa0, 0x43, 0xc6, 0x79, 0x97, 0xf1, 0x96, 0xb8, 0x45, 0xcf, 0xc0, 0xa4,
-	0x8c, 0xc5, 0xcc, 0x5d, 0x70, 0x5f, 0xf5, 0xab, 0x26, 0x15, 0x6f, 0xb8, 0x6f, 0x7f, 0x0f, 0x70,
-	0x1d, 0xb1, 0x87, 0xa7, 0xf1, 0x87, 0x01, 0x8d, 0xd3, 0x20, 0x4c, 0xb4, 0xd3, 0x5e, 0x18, 0x7b,
-	0xef, 0x93, 0xb9, 0x78, 0x73, 0x12, 0x45, 0x34, 0xcc, 0x07, 0x67, 0x2a, 0xcd, 0x68, 0x8a, 0x0e,
-	0xa1, 0x1a, 0xad, 0x16, 0x13, 0xca, 0x64, 0x0a, 0x15, 0xac, 0x24, 0x74, 0x09, 0x4f, 0x66, 0x0a,
-	0xc7, 0x2d, 0xf0, 0x83, 0x5b, 0x15, 0x99, 0xc1, 0x33, 0x9d, 0x81, 0x3e, 0xac, 0x58, 0xdd, 0xe3,
-	0xd9, 0xa6, 0x92, 0xdb, 0xff, 0x18, 0x70, 0xf0, 0x1f, 0xde, 0x08, 0x41, 0x45, 0xdc, 0x66, 0xa9,
-	0xc9, 0xff, 0xe8, 0x2b, 0xa8, 0x48, 0x6a, 0x6c, 0x49, 0x6a, 0xa0, 0xb6, 0x62, 0xbc, 0x43, 0xc9,
-	0x94, 0x32, 0xc9, 0x0d, 0x69, 0x47, 0xa7, 0x80, 0xc4, 0xad, 0x7b, 0x43, 0xc2, 0x60, 0x4a, 0x12,
-	0x30, 0x37, 0x99, 0xb6, 0x9c, 0xed, 0x5e, 0xd7, 0xca, 0x1a, 0x7f, 0xfb, 0x6b, 0xe6, 0xd0, 0x4f,
-	0xd8, 0xd0, 0x14, 0x6b, 0x1a, 0x74, 0x0d, 0x07, 0x85, 0x22, 0xdd, 0xbc, 0xd6, 0x64, 0x82, 0xf6,
-	0x47, 0x6a, 0x3d, 0x49, 0x3d, 0x9d, 0x12, 0x46, 0x62, 0x43, 0xdb, 0xab, 0x42, 0x65, 0x40, 0x04,
-	0xb1, 0xdf, 0x41, 0xeb, 0xc3, 0xb1, 0xe8, 0x0c, 0xf6, 0x73, 0x6e, 0xeb, 0xa3, 0xd3, 0x41, 0x3f,
-	0x5f, 0x3f, 0x3a, 0xa3, 0x78, 0x1a, 0x5c, 0xe0, 0xb8, 0x42, 0xb3, 0xdf, 0xc2, 0xd3, 0x0f, 0x38,
-	0xa3, 0x9f, 0xe0, 0xd1, 0xda, 0x35, 0xa0, 0x38, 0x7a, 0xb8, 0xb1, 0x41, 0x72, 0x09, 0xf1, 0x9e,
-	0x77, 0x4f, 0xb6, 0x5f, 0x43, 0xfd, 0x2a, 0xf0, 0x23, 0x3a, 0x95, 0x22, 0xfa, 0x04, 0x4c, 0x1e,
-	0xf8, 0x11, 0x11, 0x2b, 0x96, 0x6e, 0xf1, 0x2e, 0xce, 0x15, 0xe8, 0x33, 0xb5, 0xe4, 0xbd, 0x3b,
-	0x41, 0xb9, 0x9c, 0xe4, 0x2e, 0x2e, 0x68, 0xec, 0xbf, 0xcb, 0xb0, 0x9d, 0xe2, 0xb4, 0xa1, 0xa6,
-	0xa9, 0xae, 0x12, 0xca, 0x08, 0xae, 0x37, 0xd1, 0x29, 0xe1, 0xcc, 0x07, 0x7d, 0x09, 0xdb, 0x93,
-	0x84, 0xdb, 0x6a, 0xff, 0x1b, 0x9a, 0x1e, 0x92, 0xf0, 0x4e, 0x09, 0xa7, 0x56, 0x74, 0xb2, 0x59,
-	0x6e, 0xf9, 0x63, 0xe5, 0x3a, 0xa5, 0xf5, 0x82, 0xd1, 0xb7, 0x60, 0x32, 0xbd, 0xd5, 0x8a, 0x0d,
-	0xfb, 0x79, 0x6a, 0xca, 0xe0, 0x94, 0x70, 0xee, 0x85, 0x5e, 0x02, 0xac, 0xb2, 0xcd, 0xb5, 0xb6,
-	0x65, 0x0c, 0xd2, 0x31, 0xf9, 0x4e, 0x3b, 0x25, 0x5c, 0xf0, 0x43, 0x3f, 0xc2, 0x5e, 0xb6, 0x6e,
-	0x69, 0x6d, 0x3b, 0x32, 0xf2, 0xc9, 0x3a, 0x01, 0x74, 0x8d, 0x8d, 0xd9, 0xbd, 0x2d, 0x4f, 0x6e,
-	0x36, 0x46, 0x89, 0x88, 0x99, 0x55, 0x95, 0x9d, 0xd6, 0x22, 0xfa, 0x01, 0xcc, 0xec, 0x45, 0xb0,
-	0x6a, 0x12, 0xb4, 0xd5, 0x4e, 0xdf, 0x8c, 0xb6, 0x7e, 0x33, 0xda, 0x63, 0xed, 0x81, 0x73, 0x67,
-	0x64, 0x43, 0x43, 0x84, 0xdc, 0xf5, 0x28, 0x13, 0xee, 0x9c, 0xf0, 0xb9, 0x65, 0x4a, 0xe4, 0xba,
-	0x08, 0x79, 0x9f, 0x32, 0xe1, 0x10, 0x3e, 0xef, 0xed, 0xa8, 0x19, 0xda, 0x7f, 0x19, 0xf0, 0x68,
-	0x40, 0xc3, 0xe0, 0x86, 0x32, 0x4c, 0xf9, 0x32, 0x8e, 0x38, 0x4d, 0xae, 0x2d, 0x2e, 0x88, 0x58,
-	0x71, 0x75, 0xc5, 0xef, 0xe9, 0x41, 0x5d, 0x49, 0xad, 0x53, 0xc2, 0xca, 0xfe, 0x7f, 0x27, 0xba,
-	0xd9, 0xa5, 0xf2, 0x43, 0xba, 0x94, 0xec, 0x63, 0x72, 0x79, 0x7c, 0x73, 0x0d, 0x66, 0xf6, 0xca,
-	0xa0, 0x5d, 0xa8, 0xe1, 0xe1, 0xcf, 0xa3, 0xab, 0xf1, 0x10, 0x37, 0x4b, 0xc8, 0x84, 0xed, 0xde,
-	0xd9, 0x45, 0xff, 0x75, 0xd3, 0x40, 0x0d, 0x30, 0xfb, 0xce, 0xc9, 0xe8, 0xbc, 0x7f, 0x31, 0x18,
-	0x36, 0xb7, 0x12, 0x11, 0x0f, 0x7f, 0x19, 0xf6, 0xc7, 0xa3, 0x8b, 0xf3, 0x66, 0x19, 0xed, 0x43,
-	0xe3, 0x74, 0x74, 0x36, 0x1e, 0xe2, 0xe1, 0x20, 0x0d, 0xa8, 0x74, 0x5f, 0x41, 0x55, 0xc2, 0x72,
-	0x74, 0x0c, 0x95, 0xfe, 0x9c, 0x08, 0x94, 0x5d, 0xfe, 0x85, 0xb5, 0x69, 0x35, 0xee, 0xbd, 0x74,
-	0x76, 0xe9, 0xc8, 0x38, 0x36, 0xba, 0xbf, 0x1b, 0xb0, 0xa3, 0xfa, 0x87, 0x5e, 0xe5, 0x7f, 0x9b,
-	0xba, 0x13, 0xc3, 0xe8, 0x86, 0x86, 0xf1, 0x92, 0xb6, 0x9e, 0xea, 0xe8, 0xb5, 0x6e, 0xa7, 0x38,
-	0xa8, 0x97, 0x8d, 0x41, 0xf7, 0xe2, 0xc1, 0x18, 0xbd, 0xdf, 0xc0, 0x8e, 0x99, 0xdf, 0x9e, 0xdf,
-	0x2d, 0x29, 0x0b, 0xe9, 0xd4, 0xa7, 0xac, 0x3d, 0x23, 0x13, 0x16, 0x78, 0x3a, 0x2c, 0xf9, 0x6a,
-	0xe8, 0x35, 0xd2, 0x5a, 0x2f, 0x89, 0xf7, 0x9e, 0xf8, 0xf4, 0xed, 0xd7, 0x7e, 0x20, 0xe6, 0xab,
-	0x49, 0x72, 0x56, 0xa7, 0x10, 0xd9, 0x49, 0x23, 0xd3, 0xcf, 0x13, 0xde, 0x49, 0x22, 0x27, 0xe9,
-	0xf7, 0xcc, 0x77, 0xff, 0x06, 0x00, 0x00, 0xff, 0xff, 0x7f, 0xfc, 0x9e, 0x06, 0xeb, 0x08, 0x00,
-	0x00,
+	// 1164 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x94, 0x56, 0xdb, 0x6e, 0xdb, 0x46,
+	0x13, 0x16, 0x25, 0xf9, 0xc0, 0xb1, 0xe5, 0xc8, 0xab, 0xc4, 0x21, 0x94, 0xff, 0x6f, 0x52, 0x06,
+	0x2d, 0xdc, 0x5e, 0x48, 0xa9, 0x1a, 0x14, 0x41, 0x2e, 0x5a, 0x58, 0xb2, 0x52, 0xa9, 0x39, 0x19,
+	0x6b, 0xa5, 0x41, 0x53, 0xa0, 0xc4, 0x4a, 0x1c, 0x51, 0x4c, 0x24, 0x92, 0x58, 0xae, 0x54, 0xfb,
+	0x4d, 0xfa, 0x06, 0x7d, 0x98, 0xde, 0xf6, 0x49, 0x7a, 0xd5, 0xab, 0xa2, 0xe0, 0x72, 0x97, 0xa4,
+	0x2d, 0xc7, 0xa8, 0x6f, 0x24, 0xee, 0xcc, 0x7c, 0x73, 0xd8, 0xf9, 0x66, 0x77, 0x61, 0x3f, 0x42,
+	0xe4, 0x6d, 0x5c, 0x61, 0x20, 0xe2, 0x56, 0xc4, 0x43, 0x11, 0x92, 0x4d, 0xf9, 0x17, 0x37, 0x1b,
+	0x93, 0x70, 0xb1, 0x08, 0x83, 0x76, 0xfa, 0x97, 0x2a, 0x9b, 0xf7, 0xbd, 0x30, 0xf4, 0xe6, 0xd8,
+	0x96, 0xab, 0xf1, 0x72, 0xda, 0x16, 0xfe, 0x02, 0x63, 0xc1, 0x16, 0x91, 0x32, 0xb0, 0xe6, 0xe8,
+	0x7a, 0xc8, 0xdb, 0xfc, 0xd7, 0x18, 0x45, 0xfa, 0xab, 0x34, 0x4d, 0x19, 0x6a, 0x32, 0x63, 0x7e,
+	0x30, 0x09, 0x5d, 0x74, 0x64, 0x50, 0xa5, 0x3b, 0x90, 0x3a, 0xc1, 0x59, 0x10, 0xb3, 0x89, 0xf0,
+	0x75, 0x38, 0xfb, 0x04, 0x76, 0x7b, 0x1a, 0x40, 0xd1, 0x23, 0x9f, 0xc2, 0x6e, 0xee, 0xc0, 0x77,
+	0x2d, 0xe3, 0x81, 0x71, 0x68, 0xd2, 0x9d, 0x4c, 0x36, 0x74, 0xc9, 0xff, 0x01, 0xa4, 0x67, 0x27,
+	0x60, 0x0b, 0xb4, 0xca, 0xd2, 0xc0, 0x94, 0x92, 0x57, 0x6c, 0x81, 0xf6, 0xef, 0x06, 0x6c, 0x0f,
+	0x03, 0x81, 0x1c, 0x63, 0x41, 0x1e, 0x69, 0x5b, 0x71, 0x1e, 0xa1, 0x74, 0xb6, 0xd7, 0xd9, 0x4f,
+	0x43, 0xc7, 0xad, 0x7e, 0xa2, 0x19, 0x9d, 0x47, 0xa8, 0xe0, 0xc9, 0x27, 0x39, 0x06, 0x92, 0x27,
+	0xc0, 0xd1, 0x73, 0xfc, 0x60, 0x1a, 0xca, 0x28, 0x3b, 0x9d, 0xdb, 0x1a, 0x59, 0x4c, 0x79, 0x50,
+	0xa2, 0xf5, 0x49, 0x61, 0x3d, 0x0c, 0xa6, 0x21, 0xb1, 0x60, 0x4b, 0xca, 0x86, 0xc7, 0x56, 0x45,
+	0x26, 0xa8, 0x97, 0x5d, 0x13, 0xb6, 0x94, 0x91, 0xfd, 0x18, 0xb6, 0x29, 0x7a, 0x7e, 0x2c, 0x90,
+	0x93, 0x43, 0xd8, 0x4c, 0x7b, 0x64, 0x19, 0x0f, 0x2a, 0x87, 0x3b, 0x9d, 0xba, 0x0e, 0xa5, 0x4b,
+	0xa1, 0x4a, 0x6f, 0xbf, 0x04, 0x93, 0xe2, 0x7b, 0x94, 0x9b, 0x48, 0x1e, 0x42, 0x59, 0x9c, 0xc9,
+	0xba, 0x76, 0x3a, 0x0d, 0x0d, 0x19, 0xe5, 0xbb, 0x4c, 0xcb, 0xe2, 0x8c, 0xdc, 0x03, 0x13, 0x39,
+	0x0f, 0xb9, 0xb3, 0x88, 0x3d, 0xb5, 0x5f, 0xdb, 0x52, 0xf0, 0x32, 0xf6, 0xec, 0x6f, 0x00, 0xde,
+	0x04, 0xfc, 0xe6, 0x69, 0xfc, 0x66, 0x40, 0xed, 0x99, 0x3f, 0x4f, 0xa4, 0x6e, 0x77, 0x1e, 0x4e,
+	0x3e, 0x24, 0x7d, 0x99, 0xcc, 0x58, 0x10, 0xe0, 0x3c, 0x6f, 0x9c, 0xa9, 0x24, 0x43, 0x97, 0x1c,
+	0xc0, 0x66, 0xb0, 0x5c, 0x8c, 0x91, 0xcb, 0x14, 0xaa, 0x54, 0xad, 0xc8, 0x09, 0xdc, 0x99, 0x2a,
+	0x3f, 0x4e, 0x81, 0x1f, 0xb1, 0x55, 0x95, 0x19, 0xdc, 0xd3, 0x19, 0xe8, 0x60, 0xc5, 0xea, 0x6e,
+	0x4f, 0xd7, 0x85, 0xb1, 0xfd, 0xb7, 0x01, 0x8d, 0x2b, 0xac, 0x09, 0x81, 0xaa, 0x38, 0xcb, 0x52,
+	0x93, 0xdf, 0xe4, 0x73, 0xa8, 0x4a, 0x6a, 0x94, 0x25, 0x35, 0x48, 0x4b, 0xcd, 0xc2, 0x00, 0x99,
+	0x8b, 0x5c, 0x72, 0x43, 0xea, 0xc9, 0x33, 0x20, 0xe2, 0xcc, 0x59, 0xb1, 0xb9, 0xef, 0xb2, 0xc4,
+	0x99, 0x93, 0x74, 0x5b, 0xf6, 0x76, 0xaf, 0x63, 0x65, 0x1b, 0x7f, 0xf6, 0x63, 0x66, 0xd0, 0x4b,
+	0xd8, 0x50, 0x17, 0x97, 0x24, 0xe4, 0x0d, 0x34, 0x0a, 0x45, 0x3a, 0x79, 0xad, 0x49, 0x07, 0xed,
+	0x6b, 0x6a, 0x3d, 0x4a, 0x2d, 0x07, 0x25, 0x4a, 0xc4, 0x9a, 0xb4, 0xbb, 0x09, 0xd5, 0x63, 0x26,
+	0x98, 0xfd, 0x1e, 0x9a, 0x1f, 0xc7, 0x92, 0x17, 0xb0, 0x9f, 0x73, 0x5b, 0x87, 0x4e, 0x1b, 0x7d,
+	0xff, 0x72, 0xe8, 0x8c, 0xe2, 0x29, 0xb8, 0xc0, 0x71, 0xe5, 0xcd, 0x7e, 0x07, 0x77, 0x3f, 0x62,
+	0x4c, 0xbe, 0x83, 0x5b, 0x97, 0x8e, 0x01, 0xc5, 0xd1, 0x83, 0xb5, 0x09, 0x92, 0x43, 0x48, 0xf7,
+	0x26, 0x17, 0xd6, 0xf6, 0x73, 0xd8, 0x39, 0xf5, 0xbd, 0x00, 0x5d, 0xb9, 0x24, 0xff, 0x03, 0x33,
+	0xf6, 0xbd, 0x80, 0x89, 0x25, 0x4f, 0xa7, 0x78, 0x97, 0xe6, 0x02, 0xf2, 0x89, 0x1a, 0xf2, 0xee,
+	0xb9, 0xc0, 0x58, 0x76, 0x72, 0x97, 0x16, 0x24, 0xf6, 0x1f, 0x15, 0xd8, 0x48, 0xfd, 0xb4, 0x60,
+	0x5b, 0x53, 0x5d, 0x25, 0x94, 0x11, 0x5c, 0x4f, 0xe2, 0xa0, 0x44, 0x33, 0x1b, 0xf2, 0x19, 0x6c,
+	0x8c, 0x13, 0x6e, 0xab, 0xf9, 0xaf, 0x69, 0x7a, 0x48, 0xc2, 0x0f, 0x4a, 0x34, 0xd5, 0x92, 0xa3,
+	0xf5, 0x72, 0x2b, 0xd7, 0x95, 0x3b, 0x28, 0x5d, 0x2e, 0x98, 0x7c, 0x05, 0x26, 0xd7, 0x53, 0xad,
+	0xd8, 0xb0, 0x9f, 0xa7, 0xa6, 0x14, 0x83, 0x12, 0xcd, 0xad, 0xc8, 0x63, 0x80, 0x65, 0x36, 0xb9,
+	0xd6, 0x86, 0xc4, 0x10, 0x8d, 0xc9, 0x67, 0x7a, 0x50, 0xa2, 0x05, 0x3b, 0xf2, 0x2d, 0xec, 0x65,
+	0xe3, 0x96, 0xd6, 0xb6, 0x25, 0x91, 0x77, 0x2e, 0x13, 0x40, 0xd7, 0x58, 0x9b, 0x5e, 0x98, 0xf2,
+	0xe4, 0x64, 0xe3, 0xc8, 0x44, 0xc8, 0xad, 0x4d, 0xb9, 0xd3, 0x7a, 0x49, 0x9e, 0x80, 0x99, 0xdd,
+	0x15, 0xd6, 0xb6, 0x74, 0xda, 0x6c, 0xa5, 0xb7, 0x49, 0x4b, 0xdf, 0x26, 0xad, 0x91, 0xb6, 0xa0,
+	0xb9, 0x31, 0xb1, 0xa1, 0x26, 0xe6, 0xb1, 0x33, 0x41, 0x2e, 0x9c, 0x19, 0x8b, 0x67, 0x96, 0x29,
+	0x3d, 0xef, 0x88, 0x79, 0xdc, 0x43, 0x2e, 0x06, 0x2c, 0x9e, 0x75, 0xb7, 0x54, 0x0f, 0xed, 0x7f,
+	0x0c, 0xb8, 0x75, 0x8c, 0x73, 0x7f, 0x85, 0x9c, 0x62, 0x1c, 0x85, 0x41, 0x8c, 0xc9, 0xb1, 0x15,
+	0x0b, 0x26, 0x96, 0xb1, 0x3a, 0xe2, 0xf7, 0x74, 0xa3, 0x4e, 0xa5, 0x74, 0x50, 0xa2, 0x4a, 0xff,
+	0x5f, 0x3b, 0xba, 0xbe, 0x4b, 0x95, 0x1b, 0xed, 0x12, 0x85, 0x03, 0x09, 0x73, 0x58, 0xe0, 0x3a,
+	0x11, 0xf7, 0x57, 0x4c, 0xa0, 0xe3, 0x32, 0xc1, 0x54, 0x6f, 0xb3, 0x53, 0x4d, 0x9a, 0x1f, 0x05,
+	0xee, 0x49, 0x6a, 0x93, 0x0c, 0xf1, 0xa0, 0x44, 0x1b, 0xe3, 0x75, 0x71, 0x32, 0xe3, 0xc9, 0x81,
+	0x64, 0xff, 0x65, 0x40, 0xe3, 0x0a, 0x18, 0x79, 0xa8, 0x4b, 0x33, 0xae, 0x28, 0x4d, 0x17, 0xf6,
+	0x13, 0xd4, 0x8b, 0xe9, 0x38, 0x0b, 0x16, 0x59, 0x65, 0x79, 0x02, 0xb4, 0xaf, 0x49, 0xa9, 0x55,
+	0xf8, 0x7e, 0xc9, 0xa2, 0x7e, 0x20, 0xf8, 0x39, 0xdd, 0x8b, 0x2e, 0x08, 0x9b, 0x3f, 0x43, 0xe3,
+	0x0a, 0x33, 0x52, 0x87, 0xca, 0x07, 0x3c, 0x97, 0x49, 0x55, 0x69, 0xf2, 0x49, 0x5a, 0xb0, 0xb1,
+	0x62, 0xf3, 0x25, 0xaa, 0x1e, 0x58, 0xad, 0xf4, 0x11, 0x31, 0x3a, 0x3b, 0x59, 0x09, 0x8a, 0xcc,
+	0x7d, 0xcb, 0x7d, 0x81, 0xa7, 0x28, 0x68, 0x6a, 0xf6, 0xb4, 0xfc, 0xc4, 0xf8, 0xf2, 0x0d, 0x98,
+	0xd9, 0x75, 0x4d, 0x76, 0x61, 0x9b, 0xf6, 0xbf, 0x1f, 0x9e, 0x8e, 0xfa, 0xb4, 0x5e, 0x22, 0x26,
+	0x6c, 0x74, 0x5f, 0xbc, 0xee, 0x3d, 0xaf, 0x1b, 0xa4, 0x06, 0x66, 0x6f, 0x70, 0x34, 0x7c, 0xd5,
+	0x7b, 0x7d, 0xdc, 0xaf, 0x97, 0x93, 0x25, 0xed, 0xff, 0xd0, 0xef, 0x8d, 0x86, 0xaf, 0x5f, 0xd5,
+	0x2b, 0x64, 0x1f, 0x6a, 0xcf, 0x86, 0x2f, 0x46, 0x7d, 0xda, 0x3f, 0x4e, 0x01, 0xd5, 0xce, 0x53,
+	0xd8, 0x94, 0x6e, 0x63, 0xf2, 0x08, 0xaa, 0xbd, 0x19, 0x13, 0x24, 0xbb, 0x45, 0x0b, 0xe7, 0x4f,
+	0xb3, 0x76, 0xe1, 0xc9, 0x60, 0x97, 0x0e, 0x8d, 0x47, 0x46, 0xe7, 0x4f, 0x03, 0xb6, 0x14, 0x11,
+	0xc9, 0xd3, 0xfc, 0xb3, 0xae, 0xf7, 0xbd, 0x1f, 0xac, 0x70, 0x1e, 0x46, 0xd8, 0xbc, 0xab, 0xd1,
+	0x97, 0x68, 0x9b, 0xfa, 0x21, 0xdd, 0x8c, 0xcf, 0x9a, 0x54, 0x37, 0xf7, 0x31, 0x84, 0x03, 0xa5,
+	0x78, 0xeb, 0x8b, 0x59, 0x91, 0x15, 0x37, 0x75, 0xd5, 0xfd, 0x05, 0xec, 0x90, 0x7b, 0xad, 0xd9,
+	0x79, 0x84, 0x3c, 0x7d, 0xea, 0xb5, 0xa6, 0x6c, 0xcc, 0xfd, 0x89, 0x86, 0x45, 0x88, 0xbc, 0x5b,
+	0x4b, 0xb7, 0xed, 0x84, 0x4d, 0x3e, 0x30, 0x0f, 0xdf, 0x7d, 0xe1, 0xf9, 0x62, 0xb6, 0x1c, 0x27,
+	0xb1, 0xda, 0x05, 0x64, 0x3b, 0x45, 0xa6, 0x8f, 0xc9, 0xb8, 0x9d, 0x20, 0xc7, 0xe9, 0xeb, 0xf3,
+	0xeb, 0x7f, 0x07, 0x00, 0x9e, 0x48, 0x9b, 0x89, 0x99, 0x0a, 0x00, 0x00,
 }
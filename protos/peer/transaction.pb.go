@@ -104,6 +104,25 @@ func (x TxValidationCode) String() string {
 }
 func (TxValidationCode) EnumDescriptor() ([]byte, []int) { return fileDescriptor12, []int{0} }
 
+// Reserved entries in the key-level metadata namespace.
+type MetaDataKeys int32
+
+const (
+	MetaDataKeys_VALIDATION_PARAMETER MetaDataKeys = 0
+)
+
+var MetaDataKeys_name = map[int32]string{
+	0: "VALIDATION_PARAMETER",
+}
+var MetaDataKeys_value = map[string]int32{
+	"VALIDATION_PARAMETER": 0,
+}
+
+func (x MetaDataKeys) String() string {
+	return proto.EnumName(MetaDataKeys_name, int32(x))
+}
+func (MetaDataKeys) EnumDescriptor() ([]byte, []int) { return fileDescriptor12, []int{1} }
+
 // This message is necessary to facilitate the verification of the signature
 // (in the signature field) over the bytes of the transaction (in the
 // transactionBytes field).
@@ -303,63 +322,65 @@ func init() {
 	proto.RegisterType((*ChaincodeActionPayload)(nil), "protos.ChaincodeActionPayload")
 	proto.RegisterType((*ChaincodeEndorsedAction)(nil), "protos.ChaincodeEndorsedAction")
 	proto.RegisterEnum("protos.TxValidationCode", TxValidationCode_name, TxValidationCode_value)
+	proto.RegisterEnum("protos.MetaDataKeys", MetaDataKeys_name, MetaDataKeys_value)
 }
 
 func init() { proto.RegisterFile("peer/transaction.proto", fileDescriptor12) }
 
 var fileDescriptor12 = []byte{
-	// 833 bytes of a gzipped FileDescriptorProto
+	// 857 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x54, 0xd1, 0x6e, 0xe2, 0x46,
-	0x14, 0x2d, 0xd9, 0x4d, 0xd2, 0x0c, 0xd9, 0x64, 0x32, 0x10, 0x42, 0x50, 0xd4, 0x5d, 0xf1, 0x50,
-	0x6d, 0x5b, 0x09, 0xa4, 0xec, 0x43, 0xa5, 0xaa, 0x2f, 0x83, 0x3d, 0x09, 0x56, 0xcd, 0x8c, 0x35,
-	0x1e, 0x08, 0xe9, 0x43, 0x47, 0x06, 0xcf, 0x12, 0x54, 0xb0, 0x2d, 0xdb, 0x59, 0x35, 0xaf, 0xfd,
-	0x80, 0xf6, 0x3f, 0xfb, 0x13, 0xad, 0xc6, 0x63, 0x03, 0xc9, 0x76, 0x5f, 0x30, 0x73, 0xcf, 0xb9,
-	0xf7, 0x9c, 0x7b, 0xaf, 0x3d, 0xa0, 0x95, 0x28, 0x95, 0xf6, 0xf3, 0x34, 0x88, 0xb2, 0x60, 0x9e,
-	0x2f, 0xe3, 0xa8, 0x97, 0xa4, 0x71, 0x1e, 0xa3, 0x83, 0xe2, 0x91, 0x75, 0xde, 0x2e, 0xe2, 0x78,
-	0xb1, 0x52, 0xfd, 0xe2, 0x38, 0x7b, 0xfc, 0xd8, 0xcf, 0x97, 0x6b, 0x95, 0xe5, 0xc1, 0x3a, 0x31,
-	0xc4, 0xce, 0x55, 0x51, 0x20, 0x49, 0xe3, 0x24, 0xce, 0x82, 0x95, 0x4c, 0x55, 0x96, 0xc4, 0x51,
-	0xa6, 0x4a, 0xb4, 0x31, 0x8f, 0xd7, 0xeb, 0x38, 0xea, 0x9b, 0x87, 0x09, 0x76, 0x7f, 0x03, 0x67,
-	0xfe, 0x72, 0x11, 0xa9, 0x50, 0x6c, 0x65, 0xd1, 0x0f, 0xe0, 0x6c, 0xc7, 0x85, 0x9c, 0x3d, 0xe5,
-	0x2a, 0x6b, 0xd7, 0xde, 0xd5, 0xde, 0x1f, 0x73, 0xb8, 0x03, 0x0c, 0x74, 0x1c, 0x5d, 0x81, 0xa3,
-	0x6c, 0xb9, 0x88, 0x82, 0xfc, 0x31, 0x55, 0xed, 0xbd, 0x82, 0xb4, 0x0d, 0x74, 0xff, 0xac, 0x81,
-	0xa6, 0x97, 0xc6, 0x73, 0x95, 0x65, 0xcf, 0x35, 0x06, 0xa0, 0xb1, 0x53, 0x8a, 0x44, 0x9f, 0xd4,
-	0x2a, 0x4e, 0x54, 0xa1, 0x52, 0xbf, 0x86, 0xbd, 0xd2, 0x64, 0x15, 0xe7, 0xff, 0x47, 0x46, 0xdf,
-	0x82, 0x93, 0x4f, 0xc1, 0x6a, 0x19, 0x06, 0x3a, 0x6a, 0xc5, 0xa1, 0xd1, 0xdf, 0xe7, 0x2f, 0xa2,
-	0xdd, 0x01, 0xa8, 0xef, 0x4a, 0x7f, 0x00, 0x87, 0xe6, 0x9f, 0x6e, 0xea, 0xd5, 0xfb, 0xfa, 0xf5,
-	0xa5, 0x19, 0x46, 0xd6, 0xdb, 0x61, 0xe1, 0xe2, 0x97, 0x57, 0xcc, 0x2e, 0x01, 0x67, 0x9f, 0xa1,
-	0xa8, 0x05, 0x0e, 0x1e, 0x54, 0x10, 0xaa, 0xb4, 0x9c, 0x4e, 0x79, 0x42, 0x6d, 0x70, 0x98, 0x04,
-	0x4f, 0xab, 0x38, 0x08, 0xcb, 0x89, 0x54, 0xc7, 0xee, 0xdf, 0x35, 0xd0, 0xb2, 0x1e, 0x82, 0x65,
-	0x34, 0x8f, 0x43, 0x65, 0xaa, 0x78, 0x06, 0x42, 0x3f, 0x83, 0xce, 0xbc, 0x42, 0xe4, 0x66, 0x89,
-	0x55, 0x1d, 0x23, 0xd0, 0xde, 0x30, 0xbc, 0x92, 0x50, 0x65, 0xff, 0x08, 0x0e, 0x8c, 0xb5, 0x42,
-	0xb1, 0x7e, 0xfd, 0xb6, 0xea, 0x69, 0xa3, 0x46, 0xa2, 0x30, 0x4e, 0x33, 0x15, 0x96, 0x9d, 0x95,
-	0xf4, 0xee, 0x5f, 0x35, 0x70, 0xf1, 0x05, 0x0e, 0xfa, 0x09, 0x5c, 0x7e, 0xf6, 0x36, 0xbd, 0x70,
-	0x74, 0x51, 0x11, 0x78, 0x89, 0x6f, 0x0d, 0x1d, 0x2b, 0x53, 0x6d, 0xad, 0xa2, 0x3c, 0x6b, 0xef,
-	0x15, 0xa3, 0x6e, 0x54, 0xb6, 0xc8, 0x16, 0xe3, 0xcf, 0x88, 0xdf, 0xff, 0xf3, 0x1a, 0x40, 0xf1,
-	0xc7, 0xe4, 0xd9, 0x0a, 0xd1, 0x11, 0xd8, 0x9f, 0x60, 0xd7, 0xb1, 0xe1, 0x57, 0x08, 0x82, 0x63,
-	0xea, 0xb8, 0x92, 0xd0, 0x09, 0x71, 0x99, 0x47, 0x60, 0x0d, 0x9d, 0x82, 0xfa, 0x00, 0xdb, 0xd2,
-	0xc3, 0xf7, 0x2e, 0xc3, 0x36, 0xdc, 0x43, 0xe7, 0xe0, 0x4c, 0x07, 0x2c, 0x36, 0x1a, 0x31, 0x2a,
-	0x87, 0x04, 0xdb, 0x84, 0xc3, 0x57, 0xe8, 0x12, 0x9c, 0x17, 0x61, 0x4e, 0xb0, 0x60, 0x5c, 0xfa,
-	0xce, 0x2d, 0xc5, 0x62, 0xcc, 0x09, 0x7c, 0x8d, 0xde, 0x81, 0x2b, 0x87, 0x16, 0x0a, 0x92, 0x50,
-	0x9b, 0x71, 0x9f, 0x70, 0x29, 0x38, 0xa6, 0x3e, 0xb6, 0x84, 0xc3, 0x28, 0xdc, 0x47, 0xdf, 0x80,
-	0x4e, 0xc5, 0xb0, 0x18, 0xbd, 0x71, 0x6e, 0x9f, 0xe1, 0x07, 0xa8, 0x03, 0x5a, 0x63, 0xea, 0x8f,
-	0x3d, 0x8f, 0x71, 0x41, 0x6c, 0x29, 0xa6, 0x1b, 0x3f, 0x87, 0x95, 0x1f, 0x8f, 0x33, 0x8f, 0xf9,
-	0xd8, 0x95, 0x62, 0xea, 0xd8, 0xf0, 0x6b, 0x84, 0xc0, 0x89, 0x3d, 0xf6, 0x5c, 0xc7, 0xc2, 0x82,
-	0x98, 0xd8, 0x91, 0x96, 0x29, 0x0d, 0x8c, 0x08, 0x15, 0xd2, 0x63, 0xae, 0x63, 0xdd, 0xcb, 0x1b,
-	0xec, 0xb8, 0xda, 0x28, 0x40, 0x2d, 0x80, 0x46, 0x13, 0xcb, 0x92, 0x9c, 0x60, 0x63, 0xc4, 0x75,
-	0x2c, 0x01, 0xeb, 0xba, 0x37, 0x6f, 0x88, 0xa9, 0x60, 0xa3, 0x17, 0xd0, 0x31, 0x6a, 0x80, 0xd3,
-	0x31, 0xfd, 0x85, 0xb2, 0x3b, 0xaa, 0x5d, 0x89, 0x7b, 0x8f, 0xc0, 0x37, 0xda, 0xae, 0xc0, 0xfc,
-	0x96, 0x08, 0x69, 0x0d, 0xb1, 0x43, 0x25, 0x65, 0x42, 0xde, 0xb0, 0x31, 0xb5, 0xe1, 0x09, 0x6a,
-	0x02, 0x38, 0xc2, 0xdc, 0x1f, 0x16, 0x4e, 0x25, 0xe1, 0x9c, 0x71, 0x78, 0x5a, 0xcd, 0x5d, 0x4c,
-	0xcb, 0x96, 0xa1, 0x6e, 0x8b, 0x4c, 0x3d, 0x87, 0x13, 0xdb, 0x14, 0xb1, 0x98, 0x4d, 0xe0, 0x99,
-	0x6e, 0x61, 0x73, 0x94, 0x13, 0xc2, 0x7d, 0x87, 0xd1, 0xad, 0x1f, 0x84, 0xda, 0xa0, 0xa9, 0xa7,
-	0x61, 0xd6, 0x22, 0xc9, 0x54, 0x10, 0xaa, 0x29, 0xb0, 0xa1, 0x9b, 0x2b, 0x16, 0x34, 0xc4, 0x94,
-	0x12, 0xb7, 0x5a, 0x5c, 0xb3, 0xca, 0xe0, 0xc4, 0xf7, 0x18, 0xf5, 0xc9, 0x66, 0xb2, 0xe7, 0xe8,
-	0x0d, 0x38, 0x2a, 0x90, 0x3b, 0x9f, 0x08, 0xd8, 0xd2, 0xce, 0x1d, 0xd7, 0x25, 0xb7, 0xd8, 0x95,
-	0x77, 0xdc, 0x11, 0x44, 0x47, 0x2f, 0xd0, 0x25, 0x68, 0x56, 0xab, 0x63, 0x62, 0x48, 0xb8, 0x9e,
-	0x90, 0xcf, 0x28, 0xfc, 0xb7, 0x36, 0x98, 0x83, 0x6e, 0x9c, 0x2e, 0x7a, 0x0f, 0x4f, 0x89, 0x4a,
-	0x57, 0x2a, 0x5c, 0xa8, 0xb4, 0xf7, 0x31, 0x98, 0xa5, 0xcb, 0x79, 0xf5, 0x9e, 0xea, 0x2b, 0x75,
-	0x80, 0x76, 0x3e, 0x7d, 0x2f, 0x98, 0xff, 0x1e, 0x2c, 0xd4, 0xaf, 0xdf, 0x2d, 0x96, 0xf9, 0xc3,
-	0xe3, 0x4c, 0xdf, 0x54, 0xfd, 0x9d, 0xf4, 0xbe, 0x49, 0x37, 0x97, 0x74, 0xd6, 0xd7, 0xe9, 0x33,
-	0x73, 0x81, 0x7f, 0xf8, 0x2f, 0x00, 0x00, 0xff, 0xff, 0x19, 0x1c, 0xb2, 0xe4, 0xe1, 0x05, 0x00,
-	0x00,
+	0x14, 0x5d, 0xb2, 0x9b, 0xa4, 0x19, 0xb2, 0xc9, 0x30, 0x10, 0x42, 0x50, 0xd4, 0x5d, 0xf1, 0x50,
+	0xa5, 0x5b, 0x09, 0xa4, 0xec, 0x43, 0xa5, 0xaa, 0x2f, 0x83, 0x3d, 0x09, 0xd6, 0x9a, 0x19, 0x6b,
+	0x3c, 0x10, 0xd2, 0x87, 0x8e, 0x0c, 0xcc, 0x12, 0x54, 0xb0, 0x2d, 0xdb, 0x59, 0x35, 0xaf, 0xfd,
+	0x80, 0xf6, 0x3f, 0xfb, 0x13, 0xad, 0xc6, 0x63, 0x03, 0xc9, 0xb6, 0x2f, 0x98, 0xb9, 0xe7, 0xcc,
+	0x3d, 0xe7, 0xde, 0x3b, 0xba, 0xa0, 0x19, 0x2b, 0x95, 0xf4, 0xb2, 0x24, 0x08, 0xd3, 0x60, 0x96,
+	0x2d, 0xa3, 0xb0, 0x1b, 0x27, 0x51, 0x16, 0xa1, 0x83, 0xfc, 0x93, 0xb6, 0xdf, 0x2d, 0xa2, 0x68,
+	0xb1, 0x52, 0xbd, 0xfc, 0x38, 0x7d, 0xfc, 0xdc, 0xcb, 0x96, 0x6b, 0x95, 0x66, 0xc1, 0x3a, 0x36,
+	0xc4, 0xf6, 0x65, 0x9e, 0x20, 0x4e, 0xa2, 0x38, 0x4a, 0x83, 0x95, 0x4c, 0x54, 0x1a, 0x47, 0x61,
+	0xaa, 0x0a, 0xb4, 0x3e, 0x8b, 0xd6, 0xeb, 0x28, 0xec, 0x99, 0x8f, 0x09, 0x76, 0x7e, 0x05, 0x35,
+	0x7f, 0xb9, 0x08, 0xd5, 0x5c, 0x6c, 0x65, 0xd1, 0x0f, 0xa0, 0xb6, 0xe3, 0x42, 0x4e, 0x9f, 0x32,
+	0x95, 0xb6, 0x2a, 0xef, 0x2b, 0x57, 0xc7, 0x1c, 0xee, 0x00, 0x7d, 0x1d, 0x47, 0x97, 0xe0, 0x28,
+	0x5d, 0x2e, 0xc2, 0x20, 0x7b, 0x4c, 0x54, 0x6b, 0x2f, 0x27, 0x6d, 0x03, 0x9d, 0x3f, 0x2a, 0xa0,
+	0xe1, 0x25, 0xd1, 0x4c, 0xa5, 0xe9, 0x73, 0x8d, 0x3e, 0xa8, 0xef, 0xa4, 0x22, 0xe1, 0x17, 0xb5,
+	0x8a, 0x62, 0x95, 0xab, 0x54, 0xaf, 0x61, 0xb7, 0x30, 0x59, 0xc6, 0xf9, 0x7f, 0x91, 0xd1, 0x77,
+	0xe0, 0xe4, 0x4b, 0xb0, 0x5a, 0xce, 0x03, 0x1d, 0xb5, 0xa2, 0xb9, 0xd1, 0xdf, 0xe7, 0x2f, 0xa2,
+	0x9d, 0x3e, 0xa8, 0xee, 0x4a, 0x7f, 0x04, 0x87, 0xe6, 0x9f, 0x2e, 0xea, 0xf5, 0x55, 0xf5, 0xfa,
+	0xc2, 0x34, 0x23, 0xed, 0xee, 0xb0, 0x70, 0xfe, 0xcb, 0x4b, 0x66, 0x87, 0x80, 0xda, 0x57, 0x28,
+	0x6a, 0x82, 0x83, 0x07, 0x15, 0xcc, 0x55, 0x52, 0x74, 0xa7, 0x38, 0xa1, 0x16, 0x38, 0x8c, 0x83,
+	0xa7, 0x55, 0x14, 0xcc, 0x8b, 0x8e, 0x94, 0xc7, 0xce, 0x5f, 0x15, 0xd0, 0xb4, 0x1e, 0x82, 0x65,
+	0x38, 0x8b, 0xe6, 0xca, 0x64, 0xf1, 0x0c, 0x84, 0x7e, 0x06, 0xed, 0x59, 0x89, 0xc8, 0xcd, 0x10,
+	0xcb, 0x3c, 0x46, 0xa0, 0xb5, 0x61, 0x78, 0x05, 0xa1, 0xbc, 0xfd, 0x23, 0x38, 0x30, 0xd6, 0x72,
+	0xc5, 0xea, 0xf5, 0xbb, 0xb2, 0xa6, 0x8d, 0x1a, 0x09, 0xe7, 0x51, 0x92, 0xaa, 0x79, 0x51, 0x59,
+	0x41, 0xef, 0xfc, 0x59, 0x01, 0xe7, 0xff, 0xc3, 0x41, 0x3f, 0x81, 0x8b, 0xaf, 0x5e, 0xd3, 0x0b,
+	0x47, 0xe7, 0x25, 0x81, 0x17, 0xf8, 0xd6, 0xd0, 0xb1, 0x32, 0xd9, 0xd6, 0x2a, 0xcc, 0xd2, 0xd6,
+	0x5e, 0xde, 0xea, 0x7a, 0x69, 0x8b, 0x6c, 0x31, 0xfe, 0x8c, 0xf8, 0xe1, 0xef, 0x37, 0x00, 0x8a,
+	0xdf, 0xc7, 0xcf, 0x46, 0x88, 0x8e, 0xc0, 0xfe, 0x18, 0xbb, 0x8e, 0x0d, 0x5f, 0x21, 0x08, 0x8e,
+	0xa9, 0xe3, 0x4a, 0x42, 0xc7, 0xc4, 0x65, 0x1e, 0x81, 0x15, 0x74, 0x0a, 0xaa, 0x7d, 0x6c, 0x4b,
+	0x0f, 0xdf, 0xbb, 0x0c, 0xdb, 0x70, 0x0f, 0x9d, 0x81, 0x9a, 0x0e, 0x58, 0x6c, 0x38, 0x64, 0x54,
+	0x0e, 0x08, 0xb6, 0x09, 0x87, 0xaf, 0xd1, 0x05, 0x38, 0xcb, 0xc3, 0x9c, 0x60, 0xc1, 0xb8, 0xf4,
+	0x9d, 0x5b, 0x8a, 0xc5, 0x88, 0x13, 0xf8, 0x06, 0xbd, 0x07, 0x97, 0x0e, 0xcd, 0x15, 0x24, 0xa1,
+	0x36, 0xe3, 0x3e, 0xe1, 0x52, 0x70, 0x4c, 0x7d, 0x6c, 0x09, 0x87, 0x51, 0xb8, 0x8f, 0xbe, 0x05,
+	0xed, 0x92, 0x61, 0x31, 0x7a, 0xe3, 0xdc, 0x3e, 0xc3, 0x0f, 0x50, 0x1b, 0x34, 0x47, 0xd4, 0x1f,
+	0x79, 0x1e, 0xe3, 0x82, 0xd8, 0x52, 0x4c, 0x36, 0x7e, 0x0e, 0x4b, 0x3f, 0x1e, 0x67, 0x1e, 0xf3,
+	0xb1, 0x2b, 0xc5, 0xc4, 0xb1, 0xe1, 0x37, 0x08, 0x81, 0x13, 0x7b, 0xe4, 0xb9, 0x8e, 0x85, 0x05,
+	0x31, 0xb1, 0x23, 0x2d, 0x53, 0x18, 0x18, 0x12, 0x2a, 0xa4, 0xc7, 0x5c, 0xc7, 0xba, 0x97, 0x37,
+	0xd8, 0x71, 0xb5, 0x51, 0x80, 0x9a, 0x00, 0x0d, 0xc7, 0x96, 0x25, 0x39, 0xc1, 0xc6, 0x88, 0xeb,
+	0x58, 0x02, 0x56, 0x75, 0x6d, 0xde, 0x00, 0x53, 0xc1, 0x86, 0x2f, 0xa0, 0x63, 0x54, 0x07, 0xa7,
+	0x23, 0xfa, 0x89, 0xb2, 0x3b, 0xaa, 0x5d, 0x89, 0x7b, 0x8f, 0xc0, 0xb7, 0xda, 0xae, 0xc0, 0xfc,
+	0x96, 0x08, 0x69, 0x0d, 0xb0, 0x43, 0x25, 0x65, 0x42, 0xde, 0xb0, 0x11, 0xb5, 0xe1, 0x09, 0x6a,
+	0x00, 0x38, 0xc4, 0xdc, 0x1f, 0xe4, 0x4e, 0x25, 0xe1, 0x9c, 0x71, 0x78, 0x5a, 0xf6, 0x5d, 0x4c,
+	0x8a, 0x92, 0xa1, 0x2e, 0x8b, 0x4c, 0x3c, 0x87, 0x13, 0xdb, 0x24, 0xb1, 0x98, 0x4d, 0x60, 0x4d,
+	0x97, 0xb0, 0x39, 0xca, 0x31, 0xe1, 0xbe, 0xc3, 0xe8, 0xd6, 0x0f, 0x42, 0x2d, 0xd0, 0xd0, 0xdd,
+	0x30, 0x63, 0x91, 0x64, 0x22, 0x08, 0xd5, 0x14, 0x58, 0xd7, 0xc5, 0xe5, 0x03, 0x1a, 0x60, 0x4a,
+	0x89, 0x5b, 0x0e, 0xae, 0x51, 0xde, 0xe0, 0xc4, 0xf7, 0x18, 0xf5, 0xc9, 0xa6, 0xb3, 0x67, 0xe8,
+	0x2d, 0x38, 0xca, 0x91, 0x3b, 0x9f, 0x08, 0xd8, 0xd4, 0xce, 0x1d, 0xd7, 0x25, 0xb7, 0xd8, 0x95,
+	0x77, 0xdc, 0x11, 0x44, 0x47, 0xcf, 0xd1, 0x05, 0x68, 0x94, 0xa3, 0x63, 0x62, 0x40, 0xb8, 0xee,
+	0x90, 0xcf, 0x28, 0xfc, 0xa7, 0xf2, 0xe1, 0x0a, 0x1c, 0x0f, 0x55, 0x16, 0xd8, 0x41, 0x16, 0x7c,
+	0x52, 0x4f, 0xa9, 0x56, 0xca, 0x89, 0x58, 0x97, 0x28, 0x3d, 0xcc, 0xf1, 0x90, 0x08, 0xc2, 0xe1,
+	0xab, 0xfe, 0x0c, 0x74, 0xa2, 0x64, 0xd1, 0x7d, 0x78, 0x8a, 0x55, 0xb2, 0x52, 0xf3, 0x85, 0x4a,
+	0xba, 0x9f, 0x83, 0x69, 0xb2, 0x9c, 0x95, 0x2f, 0x5a, 0x2f, 0xdf, 0x3e, 0xda, 0x59, 0x12, 0x5e,
+	0x30, 0xfb, 0x2d, 0x58, 0xa8, 0x5f, 0xbe, 0x5f, 0x2c, 0xb3, 0x87, 0xc7, 0xa9, 0xde, 0x69, 0xbd,
+	0x9d, 0xeb, 0x3d, 0x73, 0xdd, 0xac, 0xf3, 0xb4, 0xa7, 0xaf, 0x4f, 0xcd, 0xaa, 0xff, 0xf8, 0xef,
+	0x00, 0x9b, 0x57, 0x8b, 0x71, 0x0b, 0x06, 0x00, 0x00,
 }